@@ -0,0 +1,209 @@
+// Command gencities builds the offline city database of the geocoding
+// package from the GeoNames dumps.
+//
+// It reads three files of https://download.geonames.org/export/dump/:
+//   - cities1000.zip (or cities500/5000/15000, zipped or unpacked to
+//     citiesNNNN.txt)
+//   - admin1CodesASCII.txt (region names)
+//   - countryInfo.txt (country names)
+//
+// A file that isn't found locally is downloaded from -url (and not kept),
+// so from a clean checkout
+//
+//	go generate ./internal/service/geocoding
+//
+// rebuilds the database. To build offline, or from a mirror's copy, put
+// the files in internal/service/geocoding, or run directly:
+//
+//	go run ./cmd/gencities -cities cities1000.txt -admin1 admin1CodesASCII.txt \
+//	    -countries countryInfo.txt -o internal/service/geocoding/cities.tsv.gz
+//
+// The largest -max cities (by population) are kept. The output format is
+// described in internal/service/geocoding/offline.go.
+package main
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// downloadTimeout bounds the download of one dump file (cities1000.zip is
+// about 10 MB).
+const downloadTimeout = 5 * time.Minute
+
+// city is one output line.
+type city struct {
+	name, asciiName string
+	lat, lon        string
+	region, country string
+	population      int
+}
+
+func main() {
+	citiesPath := flag.String("cities", "cities1000.zip", "GeoNames cities dump (.txt or .zip)")
+	admin1Path := flag.String("admin1", "admin1CodesASCII.txt", "GeoNames region names")
+	countriesPath := flag.String("countries", "countryInfo.txt", "GeoNames country names")
+	outPath := flag.String("o", "cities.tsv.gz", "output file")
+	maxCities := flag.Int("max", 100000, "number of cities to keep, largest first")
+	dumpURL := flag.String("url", "https://download.geonames.org/export/dump/", "where to download missing files from")
+	flag.Parse()
+
+	regions, err := readNames(load(*admin1Path, *dumpURL), 0, 1)
+	if err != nil {
+		log.Fatalf("reading regions: %v", err)
+	}
+	countries, err := readNames(load(*countriesPath, *dumpURL), 0, 4)
+	if err != nil {
+		log.Fatalf("reading countries: %v", err)
+	}
+	cities, err := readCities(load(*citiesPath, *dumpURL), regions, countries)
+	if err != nil {
+		log.Fatalf("reading cities: %v", err)
+	}
+
+	slices.SortStableFunc(cities, func(a, b city) int { return cmp.Compare(b.population, a.population) })
+	cities = cities[:min(len(cities), *maxCities)]
+
+	if err := write(*outPath, cities); err != nil {
+		log.Fatalf("writing %s: %v", *outPath, err)
+	}
+	fmt.Printf("wrote %d cities to %s\n", len(cities), *outPath)
+}
+
+// load returns the contents of a dump file: the local file at path, or else
+// the file of the same name downloaded from dumpURL. A .zip is unpacked to
+// the .txt of the same name inside it.
+//
+// Exits if the file can be neither read nor downloaded.
+func load(path, dumpURL string) []byte {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		url := strings.TrimSuffix(dumpURL, "/") + "/" + filepath.Base(path)
+		fmt.Printf("%s not found, downloading %s\n", path, url)
+		data, err = download(url)
+	}
+	if err != nil {
+		log.Fatalf("reading %s: %v", path, err)
+	}
+	if strings.HasSuffix(path, ".zip") {
+		if data, err = unzip(data, strings.TrimSuffix(filepath.Base(path), ".zip")+".txt"); err != nil {
+			log.Fatalf("unpacking %s: %v", path, err)
+		}
+	}
+	return data
+}
+
+// download fetches url.
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: downloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// unzip returns the file called name in the zip archive data.
+func unzip(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// readNames reads a tab-separated GeoNames table into a map from the key
+// column to the name column, skipping "#" comment lines.
+func readNames(data []byte, keyColumn, nameColumn int) (map[string]string, error) {
+	names := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) > max(keyColumn, nameColumn) {
+			names[fields[keyColumn]] = fields[nameColumn]
+		}
+	}
+	return names, scanner.Err()
+}
+
+// readCities reads a GeoNames cities dump, naming each city's region and
+// country.
+//
+// Columns used: 1 name, 2 ASCII name, 4 latitude, 5 longitude, 8 country
+// code, 10 admin1 code, 14 population.
+func readCities(data []byte, regions, countries map[string]string) ([]city, error) {
+	var cities []city
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, 1<<20) // alternate names make long lines
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) < 15 {
+			continue
+		}
+		population, _ := strconv.Atoi(fields[14])
+		cities = append(cities, city{
+			name:       fields[1],
+			asciiName:  fields[2],
+			lat:        fields[4],
+			lon:        fields[5],
+			region:     regions[fields[8]+"."+fields[10]],
+			country:    countries[fields[8]],
+			population: population,
+		})
+	}
+	return cities, scanner.Err()
+}
+
+// write writes the cities as gzip-compressed tab-separated lines.
+func write(path string, cities []city) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw, err := gzip.NewWriterLevel(f, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(zw)
+	for _, c := range cities {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n",
+			c.name, c.asciiName, c.lat, c.lon, c.region, c.country, c.population)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
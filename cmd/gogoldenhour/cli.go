@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+)
+
+// =============================================================================
+// Command Line Mode
+// =============================================================================
+
+// cliFlag runs the calculation without the window and prints the times, for
+// scripts and cron jobs:
+//
+//	gogoldenhour --cli --lat 48.85 --lon 2.35 --date 2025-06-21 --format json
+//
+// Qt is never started in this mode, so it also works without a display
+// (e.g., over SSH).
+const cliFlag = "--cli"
+
+// Output formats of the command line mode (--format).
+const (
+	// cliFormatText is the Markdown summary of Edit → Copy Times, one per
+	// day (see export.DefaultCopyTemplate).
+	cliFormatText = "text"
+
+	// cliFormatJSON is an array with one object per day (see
+	// export.DaysJSON).
+	cliFormatJSON = "json"
+
+	// cliFormatCSV is a table with one row per day (see export.DaysCSV).
+	cliFormatCSV = "csv"
+)
+
+// usageError is an invalid option of the command line mode.
+type usageError struct {
+	error
+}
+
+// usageErrorf returns a usageError with a formatted message.
+func usageErrorf(format string, args ...any) error {
+	return usageError{fmt.Errorf(format, args...)}
+}
+
+// isCLI reports whether the command line asks for the command line mode.
+//
+// Parameters:
+//   - args: The command line, including the program name (os.Args)
+func isCLI(args []string) bool {
+	return len(args) > 1 && slices.Contains(args[1:], cliFlag)
+}
+
+// runCLI calculates the sun times given on the command line and prints them.
+//
+// Options (also with a single "-", or as "--name=value"):
+//   - --lat, --lon: The place's coordinates in degrees (required)
+//   - --date: The first day, YYYY-MM-DD; default today
+//   - --until: The last day of a date range (at most
+//     domain.MaxDateRangeDays days); default the first day
+//   - --tz: The IANA timezone of the times; default the place's, looked up
+//     from the coordinates
+//   - --elevation: The place's height above sea level in meters
+//   - --name: The place name shown by the text format
+//   - --golden, --blue-start, --blue-end: The elevation angles (see
+//     domain.Settings); default the app's defaults, not the user's settings,
+//     so scripts give the same times on every machine
+//   - --format: text, json or csv
+//   - --12h: 12-hour times ("9:58 PM") instead of 24-hour ones
+//
+// Parameters:
+//   - args: The command line without the program name (os.Args[1:])
+//   - stdout: Where the times go
+//   - stderr: Where errors and the usage go
+//
+// Returns the exit code: 0 on success, 1 if the calculation failed, 2 for
+// invalid options (as the flag package does).
+func runCLI(args []string, stdout, stderr io.Writer) int {
+	output, err := cliOutput(args, stderr)
+	var usage usageError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.As(err, &usage):
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", err)
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", err)
+		return 1
+	}
+	if !bytes.HasSuffix(output, []byte("\n")) {
+		output = append(output, '\n')
+	}
+	if _, err := stdout.Write(output); err != nil {
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// cliOutput parses the options of runCLI and returns what it prints.
+//
+// Invalid options are returned as a usageError (the flag package has
+// printed the usage for unknown ones already); flag.ErrHelp is returned
+// for -h.
+func cliOutput(args []string, stderr io.Writer) ([]byte, error) {
+	defaults := domain.DefaultSettings()
+
+	flags := flag.NewFlagSet("gogoldenhour --cli", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Bool("cli", true, "print the times without opening the window")
+	lat := flags.String("lat", "", "latitude in degrees, -90 to 90 (required)")
+	lon := flags.String("lon", "", "longitude in degrees, -180 to 180 (required)")
+	date := flags.String("date", "", "first day, YYYY-MM-DD (default today)")
+	until := flags.String("until", "", "last day of a date range, YYYY-MM-DD")
+	zone := flags.String("tz", "", "IANA timezone (default: the place's)")
+	elevation := flags.Float64("elevation", 0, "height above sea level in meters")
+	name := flags.String("name", "", "place name for the text format")
+	golden := flags.Float64("golden", defaults.GoldenHourElevation, "sun elevation where golden hour ends, degrees")
+	blueStart := flags.Float64("blue-start", defaults.BlueHourStart, "sun elevation where blue hour starts, degrees")
+	blueEnd := flags.Float64("blue-end", defaults.BlueHourEnd, "sun elevation where blue hour ends, degrees")
+	format := flags.String("format", cliFormatText, "output format: text, json or csv")
+	use12Hour := flags.Bool("12h", false, "12-hour times")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, usageError{err}
+	}
+	if flags.NArg() > 0 {
+		return nil, usageErrorf("unexpected argument %q", flags.Arg(0))
+	}
+	if *lat == "" || *lon == "" {
+		return nil, usageErrorf("--lat and --lon are required")
+	}
+	if !slices.Contains([]string{cliFormatText, cliFormatJSON, cliFormatCSV}, *format) {
+		return nil, usageErrorf("unknown format %q (use text, json or csv)", *format)
+	}
+
+	// The coordinates, date and name are checked like a share link's
+	query := url.Values{"lat": {*lat}, "lon": {*lon}, "name": {*name}}
+	if *date != "" {
+		query.Set("date", *date)
+	}
+	link, err := domain.ParseLinkQuery(query)
+	if err != nil {
+		return nil, usageError{err}
+	}
+	first := link.Date
+	if first.IsZero() {
+		first = time.Now()
+	}
+	last := first
+	if *until != "" {
+		if last, err = time.ParseInLocation("2006-01-02", *until, time.Local); err != nil {
+			return nil, usageErrorf("invalid date %q (use YYYY-MM-DD)", *until)
+		}
+	}
+	dates := domain.NewDateRange(first, last)
+	if dates.Days() > domain.MaxDateRangeDays {
+		return nil, usageErrorf("at most %d days can be calculated at once", domain.MaxDateRangeDays)
+	}
+
+	loc := domain.Location{
+		Name:      link.Name,
+		Latitude:  link.Latitude,
+		Longitude: link.Longitude,
+		Elevation: *elevation,
+		Timezone:  *zone,
+	}
+	if loc.Timezone == "" {
+		loc.Timezone = timezone.FromCoordinates(loc.Latitude, loc.Longitude)
+	} else if !domain.ValidTimezone(loc.Timezone) {
+		return nil, usageErrorf("unknown timezone %q (e.g., Europe/Paris)", loc.Timezone)
+	}
+
+	// Out-of-range angles are clamped like saved settings
+	settings := defaults
+	settings.GoldenHourElevation = *golden
+	settings.BlueHourStart = *blueStart
+	settings.BlueHourEnd = *blueEnd
+	settings.Validate()
+
+	days, err := solar.New(settings).CalculateRange(loc, dates)
+	if err != nil {
+		return nil, fmt.Errorf("calculation failed: %w", err)
+	}
+
+	options := export.DaysOptions{Columns: export.AllDayColumns(), Use24Hour: !*use12Hour}
+	switch *format {
+	case cliFormatJSON:
+		return export.DaysJSON(days, options)
+	case cliFormatCSV:
+		return export.DaysCSV(days, options)
+	default:
+		summaries := make([]string, 0, len(days))
+		for _, day := range days {
+			data := export.NewCopyData(loc, day, options.Use24Hour, domain.CoordinateFormatDecimal,
+				domain.PlaceNameFull)
+			text, err := export.CopyText("", data)
+			if err != nil {
+				return nil, err
+			}
+			summaries = append(summaries, text)
+		}
+		return []byte(strings.Join(summaries, "\n")), nil
+	}
+}
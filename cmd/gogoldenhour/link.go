@@ -0,0 +1,69 @@
+package main
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// linkOptions are the command-line options that open a place and date like
+// a share link: "--lat 48.85 --lon 2.35 --date 2025-06-21" (or
+// "--lat=48.85"). The link parameters have the same names.
+var linkOptions = []string{"lat", "lon", "date", "name"}
+
+// parseLinkArgs extracts a share link from the command line: either a
+// gogoldenhour:// argument, as passed by the desktop when a link is
+// clicked, or the equivalent link options.
+//
+// The link and its options are removed from the returned arguments, which
+// are passed on to Qt.
+//
+// Parameters:
+//   - args: The command line, including the program name (os.Args)
+//
+// Returns:
+//   - link: The link to open, or nil if none was given
+//   - rest: args without the link
+//   - err: Non-nil if a link was given but is invalid (rest is still
+//     returned, so the app can start without it)
+func parseLinkArgs(args []string) (link *domain.Link, rest []string, err error) {
+	var (
+		text  string
+		query = url.Values{}
+	)
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if i > 0 && domain.IsLink(arg) {
+			text = arg
+			continue
+		}
+		name, value, found := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if i == 0 || !strings.HasPrefix(arg, "--") || !slices.Contains(linkOptions, name) {
+			rest = append(rest, arg)
+			continue
+		}
+		if !found && i+1 < len(args) {
+			// Negative coordinates start with "-" too, so take the next
+			// argument whatever it is
+			value = args[i+1]
+			i++
+		}
+		query.Set(name, value)
+	}
+
+	var parsed domain.Link
+	switch {
+	case text != "":
+		parsed, err = domain.ParseLink(text)
+	case len(query) > 0:
+		parsed, err = domain.ParseLinkQuery(query)
+	default:
+		return nil, rest, nil
+	}
+	if err != nil {
+		return nil, rest, err
+	}
+	return &parsed, rest, nil
+}
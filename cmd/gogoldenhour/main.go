@@ -10,7 +10,7 @@
 //
 //	main.go (entry point)
 //	    └── app.App (controller/orchestrator)
-//	            ├── ui.MainWindow (view)
+//	            ├── ui.MainWindow or tui.Terminal (frontend.View)
 //	            │       └── widgets/* (UI components)
 //	            ├── solar.Calculator (calculations)
 //	            ├── geolocation.IPAPIService (IP detection)
@@ -32,14 +32,71 @@
 // Chromium's GPU acceleration. The application disables GPU acceleration
 // before Qt initialization to ensure reliable rendering on all platforms.
 //
+// # Profiles
+//
+// Started with --profile NAME, the application uses a separate set of
+// settings and favorites (e.g., work and personal), stored per profile by
+// storage.PreferencesStore. --profile without a name shows a chooser with
+// the existing profiles, and --default-profile picks the default one.
+// Without a flag, the profile of the last session is used (see Session).
+//
+// # Session
+//
+// On exit, the app saves the session (profile, place, date, map zoom and
+// the open planning windows) to session.json in the config folder, and the
+// next launch comes back to it (see domain.Session). A share link on the
+// command line still wins over the session's place and date.
+//
+// # Share Links
+//
+// A gogoldenhour:// link on the command line (as passed by the desktop when
+// a link is clicked, see linkscheme), or the equivalent --lat, --lon,
+// --date and --name options, opens that place and date instead of the
+// saved or detected location:
+//
+//	gogoldenhour "gogoldenhour://?lat=48.85&lon=2.35&date=2025-06-21"
+//	gogoldenhour --lat 48.85 --lon 2.35 --date 2025-06-21
+//
+// # Single Instance
+//
+// Only one window runs per profile. Starting the app again (e.g., by
+// clicking a share link) brings the running window to the front and opens
+// the new start's link there, instead of a second window overwriting the
+// first one's settings (see ui.ForwardToRunningInstance).
+//
+// # Command Line Mode
+//
+// With --cli, the times are printed as text, JSON or CSV and the program
+// exits without starting Qt (see runCLI):
+//
+//	gogoldenhour --cli --lat 48.85 --lon 2.35 --date 2025-06-21 --format json
+//
+// # HTTP API Mode
+//
+// With --serve, the sun times and the place search are served as a REST
+// API for home automation and other tools, without starting Qt either
+// (see runServe and the api package):
+//
+//	gogoldenhour --serve --addr 127.0.0.1:8765
+//
+// # Terminal UI Mode
+//
+// With --tui, the application runs in the terminal instead of a window, for
+// headless servers and SSH sessions (see runTUI and the tui package). It
+// shares the App controller with the window through the frontend package:
+//
+//	gogoldenhour --tui --profile work
+//
 // # Startup Flow
 //
 //  1. Disable GPU acceleration (environment variable)
 //  2. Initialize Qt application (locks OS thread)
-//  3. Create App controller (loads settings, creates services)
-//  4. Run the application (shows window, optionally auto-detects location)
-//  5. Enter Qt event loop (handles user interactions)
-//  6. Exit when user closes the window
+//  3. Choose the profile, and hand the link to the profile's window if it
+//     runs already (see Single Instance)
+//  4. Create the App controller (loads settings, creates services)
+//  5. Run the application (shows window, optionally auto-detects location)
+//  6. Enter Qt event loop (handles user interactions)
+//  7. Exit when user closes the window
 package main
 
 import (
@@ -48,6 +105,13 @@ import (
 
 	qt "github.com/mappu/miqt/qt6"
 	"github.com/megatih/GoGoldenHour/internal/app"
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/frontend"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+	"github.com/megatih/GoGoldenHour/internal/storage"
+	"github.com/megatih/GoGoldenHour/internal/ui"
+	"github.com/megatih/GoGoldenHour/internal/ui/widgets"
 )
 
 // main is the entry point of the GoGoldenHour application.
@@ -55,12 +119,27 @@ import (
 // This function performs the following initialization steps:
 //  1. Sets environment variable to disable GPU acceleration
 //  2. Initializes the Qt application framework
-//  3. Creates the application controller
-//  4. Starts the application and Qt event loop
+//  3. Picks the settings profile, handing over to its running window if
+//     there is one
+//  4. Creates the application controller
+//  5. Starts the application and Qt event loop
 //
 // The function exits the process with the Qt application's exit code,
 // which is typically 0 for normal exit or non-zero for errors.
 func main() {
+	// The command line mode prints the times and exits before Qt starts
+	if isCLI(os.Args) {
+		os.Exit(runCLI(os.Args[1:], os.Stdout, os.Stderr))
+	}
+	// So does the HTTP API mode, which runs until interrupted
+	if isServe(os.Args) {
+		os.Exit(runServe(os.Args[1:], os.Stderr))
+	}
+	// The terminal UI runs the whole app without Qt
+	if isTUI(os.Args) {
+		os.Exit(runTUI(os.Args, os.Stderr))
+	}
+
 	// =========================================================================
 	// Step 1: GPU Compatibility Fix
 	// =========================================================================
@@ -86,10 +165,56 @@ func main() {
 	//
 	// After this call, the current goroutine is permanently bound to the
 	// main thread. All Qt widget operations must happen on this thread.
-	qt.NewQApplication(os.Args)
+	profile, choose, givenProfile, args := parseProfileFlag(os.Args)
+	// Without a profile flag, the last session's profile runs again
+	if !givenProfile {
+		profile = sessionProfile()
+	}
+	link, args, linkErr := parseLinkArgs(args)
+	qt.NewQApplication(args)
+
+	// =========================================================================
+	// Step 3: Profile and Single Instance
+	// =========================================================================
+	// With --profile but no name, the user picks the profile first; canceling
+	// the chooser quits.
+	if choose {
+		profiles, err := storage.ListProfiles()
+		if err != nil {
+			log.Printf("Warning: %v", err)
+		}
+		var ok bool
+		if profile, ok = widgets.ChooseProfile(profiles, storage.ValidateProfileName); !ok {
+			return
+		}
+	}
+
+	// A broken link shouldn't keep the app from starting
+	if linkErr != nil {
+		log.Printf("Warning: ignoring link: %v", linkErr)
+	}
+
+	// One window per profile: if the profile's window is running, it opens
+	// the link (or just comes to the front) and this start ends before
+	// anything touches the settings
+	if ui.ForwardToRunningInstance(profile, link) {
+		return
+	}
+
+	// =========================================================================
+	// Step 4: Crash Reporting
+	// =========================================================================
+	// Crashes that end the process are written to the crash folder by the
+	// runtime. The last run's is taken first, as enabling the reports
+	// empties the file; it is shown once the window is up.
+	crashDir := crash.DefaultDir()
+	lastCrash, lastCrashPath, crashed := crash.TakeFatalReport(crashDir)
+	if err := crash.EnableFatalReports(crashDir); err != nil {
+		log.Printf("Warning: crashes won't be reported: %v", err)
+	}
 
 	// =========================================================================
-	// Step 3: Application Controller Creation
+	// Step 5: Application Controller Creation
 	// =========================================================================
 	// Create the main application controller. This performs:
 	//   - Loading user preferences from disk
@@ -97,22 +222,45 @@ func main() {
 	//   - Setting up the main window with all UI components
 	//
 	// Errors at this stage are fatal (e.g., cannot create preferences store).
-	application, err := app.New()
+	var window *ui.MainWindow
+	application, err := app.New(profile, func(cfg config.AppConfig, controller frontend.Controller) frontend.View {
+		window = ui.NewMainWindow(cfg, controller)
+		return window
+	})
 	if err != nil {
 		log.Fatalf("Failed to create application: %v", err)
 	}
 
+	// Panics recovered in the App's goroutines and the window's callbacks
+	// show the crash dialog
+	crash.SetHandler(crashDir, func(report crash.Report, path string) {
+		window.ShowCrash(report, path, false)
+	})
+	if crashed {
+		window.ShowCrash(lastCrash, lastCrashPath, true)
+	}
+
+	// Later starts of the profile forward their links to this window
+	if err := window.ListenForInstances(profile); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	// Report the time zone database in use, to help diagnose wrong times
+	// on systems without zoneinfo files (e.g., "system (/usr/share/zoneinfo/)")
+	log.Printf("Time zone database: %s", timezone.Database())
+
 	// =========================================================================
-	// Step 4: Application Startup
+	// Step 6: Application Startup
 	// =========================================================================
 	// Start the application. This:
 	//   - Shows the main window
-	//   - Optionally auto-detects the user's location (if enabled in settings)
+	//   - Opens the share link given on the command line, or optionally
+	//     auto-detects the user's location (if enabled in settings)
 	//   - Performs initial solar calculations
-	application.Run()
+	application.Run(link)
 
 	// =========================================================================
-	// Step 5: Qt Event Loop
+	// Step 7: Qt Event Loop
 	// =========================================================================
 	// Enter the Qt event loop. This function blocks until the application
 	// exits (user closes the window or calls QApplication::quit()).
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"log"
+	"slices"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/storage"
+)
+
+// =============================================================================
+// Command Line
+// =============================================================================
+
+// profileFlag selects the settings profile: "--profile NAME" or
+// "--profile=NAME". Without a name, a chooser lists the existing profiles.
+const profileFlag = "--profile"
+
+// defaultProfileFlag starts the default profile, also when the last
+// session was another profile's (see sessionProfile). Tools → Open
+// Profile passes it for the default profile.
+const defaultProfileFlag = "--default-profile"
+
+// parseProfileFlag extracts the profile flag from the command line.
+//
+// The flag is removed from the returned arguments, which are passed on to
+// Qt (Qt keeps unknown arguments but has no use for them).
+//
+// Parameters:
+//   - args: The command line, including the program name (os.Args)
+//
+// Returns:
+//   - profile: The profile name, or "" if none was given
+//   - choose: True if the flag was given without a name
+//   - given: True if a profile was asked for (a name, the chooser or
+//     --default-profile); without one, the last session's profile runs
+//   - rest: args without the profile flag
+func parseProfileFlag(args []string) (profile string, choose, given bool, rest []string) {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case i > 0 && arg == defaultProfileFlag:
+			profile, choose, given = storage.DefaultProfile, false, true
+			continue
+		case i > 0 && arg == profileFlag:
+			// The name is the next argument, unless that is another option
+			if i+1 < len(args) && !strings.HasPrefix(args[i+1], "-") {
+				profile = args[i+1]
+				i++
+			}
+		case i > 0 && strings.HasPrefix(arg, profileFlag+"="):
+			profile = strings.TrimPrefix(arg, profileFlag+"=")
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+		choose = profile == ""
+		given = true
+	}
+	return profile, choose, given, rest
+}
+
+// sessionProfile returns the profile of the last session (see
+// storage.SessionStore), for a start without a profile flag: the app comes
+// back with the profile it was closed with. Returns the default profile if
+// there was no session, or its profile was deleted since.
+func sessionProfile() string {
+	sessions, err := storage.NewSessionStore()
+	if err != nil {
+		return storage.DefaultProfile
+	}
+	session, err := sessions.Load()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return storage.DefaultProfile
+	}
+	if session.Profile == storage.DefaultProfile {
+		return storage.DefaultProfile
+	}
+	profiles, err := storage.ListProfiles()
+	if err != nil || !slices.Contains(profiles, session.Profile) {
+		return storage.DefaultProfile
+	}
+	return session.Profile
+}
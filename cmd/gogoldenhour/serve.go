@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/api"
+	"github.com/megatih/GoGoldenHour/internal/service/geocoding"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+)
+
+// =============================================================================
+// HTTP API Mode
+// =============================================================================
+
+// serveFlag serves the sun times and the place search as a REST API (see
+// the api package) instead of opening the window, for home automation
+// systems and other tools:
+//
+//	gogoldenhour --serve --addr 127.0.0.1:8765
+//	curl "http://127.0.0.1:8765/v1/suntimes?lat=48.85&lon=2.35&date=2025-06-21"
+//
+// Like the command line mode, Qt isn't started, and the app's default
+// elevation angles are used rather than the saved settings.
+const serveFlag = "--serve"
+
+// isServe reports whether the command line asks for the HTTP API mode.
+//
+// Parameters:
+//   - args: The command line, including the program name (os.Args)
+func isServe(args []string) bool {
+	return len(args) > 1 && slices.Contains(args[1:], serveFlag)
+}
+
+// runServe serves the API until interrupted (Ctrl+C).
+//
+// Options:
+//   - --addr: The address to listen on; default api.DefaultAddress (this
+//     machine only). Use e.g. "0.0.0.0:8765" to serve the local network.
+//   - --rate: Each client's requests per minute; default
+//     api.DefaultRequestsPerMinute
+//   - --offline: Search the offline city database only, without Nominatim
+//
+// Parameters:
+//   - args: The command line without the program name (os.Args[1:])
+//   - stderr: Where errors, the usage and the log go
+//
+// Returns the exit code: 0 after an interrupt, 1 if the address can't be
+// served, 2 for invalid options.
+func runServe(args []string, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gogoldenhour --serve", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Bool("serve", true, "serve the HTTP API without opening the window")
+	addr := flags.String("addr", api.DefaultAddress, "address to listen on")
+	rate := flags.Int("rate", api.DefaultRequestsPerMinute, "requests per minute per client")
+	offline := flags.Bool("offline", false, "search the offline city database only")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if flags.NArg() > 0 || *rate < 1 {
+		fmt.Fprintln(stderr, "gogoldenhour: --serve takes --addr, --rate (at least 1) and --offline")
+		return 2
+	}
+
+	logger := log.New(stderr, "", log.LstdFlags)
+	cfg := config.DefaultConfig()
+	geocoder := geocoding.NewNominatimService(geocoding.UserAgent(cfg.AppVersion, ""), geocoding.DefaultCachePath())
+	geocoder.SetOffline(*offline)
+	// Nominatim's results with the offline cities, as the app's search
+	search := func(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+		results, err := geocoder.Search(ctx, query, limit, geocoding.SearchOptions{})
+		offline := geocoding.SearchOffline(query, limit)
+		switch {
+		case err != nil && len(offline) > 0:
+			return offline, nil
+		case errors.Is(err, domain.ErrPrivacyMode):
+			return nil, nil
+		case err != nil:
+			return nil, err
+		}
+		return geocoding.MergeResults(results, offline, limit), nil
+	}
+	handler := api.New(solar.New(cfg.Settings), search, *rate)
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", err)
+		return 1
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: config.DefaultHTTPTimeout, ErrorLog: logger}
+
+	// Ctrl+C lets the requests in flight finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	stopped := make(chan struct{})
+	crash.Go(func() {
+		defer close(stopped)
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), config.DefaultHTTPTimeout)
+		defer cancel()
+		server.Shutdown(shutdown)
+	})
+
+	logger.Printf("Serving the API on http://%s/v1/ (%d requests per minute per client)", listener.Addr(), *rate)
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", err)
+		return 1
+	}
+	// Serve returns as soon as the shutdown starts
+	<-stopped
+	return 0
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/megatih/GoGoldenHour/internal/app"
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/frontend"
+	"github.com/megatih/GoGoldenHour/internal/tui"
+)
+
+// =============================================================================
+// Terminal UI Mode
+// =============================================================================
+
+// tuiFlag runs the application in the terminal instead of a window (see the
+// tui package), for headless servers and SSH sessions:
+//
+//	gogoldenhour --tui
+//	gogoldenhour --tui --profile work --lat 48.85 --lon 2.35
+//
+// Unlike the command line mode, this is the whole app (searches,
+// favorites, presets, automation) with the saved settings; only Qt isn't
+// started.
+const tuiFlag = "--tui"
+
+// isTUI reports whether the command line asks for the terminal UI.
+//
+// Parameters:
+//   - args: The command line, including the program name (os.Args)
+func isTUI(args []string) bool {
+	return len(args) > 1 && slices.Contains(args[1:], tuiFlag)
+}
+
+// runTUI runs the application in the terminal until the user quits.
+//
+// --profile NAME and share links work as in the window; --profile needs a
+// name, as the profile chooser is a Qt dialog.
+//
+// Parameters:
+//   - args: The command line, including the program name (os.Args)
+//   - stderr: Where errors go (before and after the terminal is taken over)
+//
+// Returns the exit code: 0 after quitting, 1 if the app or the terminal
+// can't be started, 2 for invalid options.
+func runTUI(args []string, stderr io.Writer) int {
+	profile, choose, given, rest := parseProfileFlag(args)
+	if !given {
+		profile = sessionProfile()
+	}
+	if choose {
+		fmt.Fprintln(stderr, "gogoldenhour: --profile needs a name with --tui")
+		return 2
+	}
+	link, rest, linkErr := parseLinkArgs(rest)
+	if linkErr != nil {
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", linkErr)
+		return 2
+	}
+	for _, arg := range rest[1:] {
+		if arg != tuiFlag {
+			fmt.Fprintf(stderr, "gogoldenhour: unexpected argument %q with --tui\n", arg)
+			return 2
+		}
+	}
+
+	var terminal *tui.Terminal
+	application, err := app.New(profile, func(cfg config.AppConfig, controller frontend.Controller) frontend.View {
+		terminal = tui.New(cfg, controller)
+		return terminal
+	})
+	if err != nil {
+		fmt.Fprintf(stderr, "gogoldenhour: failed to create application: %v\n", err)
+		return 1
+	}
+	// Recovered panics go to the status line; the report stays in the
+	// crash folder, as the terminal has no dialog to open it from.
+	crash.SetHandler(crash.DefaultDir(), func(r crash.Report, path string) {
+		terminal.QueueOnMainThread(func() {
+			message := "Something went wrong: " + r.Panic
+			if path != "" {
+				message += " (report saved to " + path + ")"
+			}
+			terminal.ShowError(message)
+		})
+	})
+	application.Run(link)
+	if err := terminal.Run(); err != nil {
+		fmt.Fprintf(stderr, "gogoldenhour: %v\n", err)
+		return 1
+	}
+	return 0
+}
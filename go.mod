@@ -1,22 +1,32 @@
 module github.com/megatih/GoGoldenHour
 
-go 1.24
+go 1.24.0
 
 require (
+	github.com/gdamore/tcell/v2 v2.13.10
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/hablullah/go-sampa v1.0.0
 	github.com/mappu/miqt v0.12.0
 	github.com/ringsaturn/tzf v1.0.2
+	github.com/rivo/tview v0.42.0
+	github.com/rwcarlsen/goexif v0.0.0-20190401172101-9e8deecbddbd
 )
 
 require (
+	github.com/gdamore/encoding v1.0.1 // indirect
 	github.com/hablullah/go-juliandays v1.0.1-0.20220316153050-f56193695a5b // indirect
+	github.com/lucasb-eyer/go-colorful v1.3.0 // indirect
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
 	github.com/paulmach/orb v0.12.0 // indirect
 	github.com/ringsaturn/tzf-rel-lite v0.0.2025-b2 // indirect
+	github.com/rivo/uniseg v0.4.7 // indirect
 	github.com/tidwall/geoindex v1.7.0 // indirect
 	github.com/tidwall/geojson v1.4.5 // indirect
 	github.com/tidwall/rtree v1.10.0 // indirect
 	github.com/twpayne/go-polyline v1.1.1 // indirect
 	go.mongodb.org/mongo-driver v1.11.4 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/term v0.37.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
 	google.golang.org/protobuf v1.36.9 // indirect
 )
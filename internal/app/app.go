@@ -3,7 +3,8 @@
 // This package implements the central orchestrator that coordinates all
 // components of the application. Following the controller pattern, it:
 //
-//   - Owns and manages all services (solar calculator, geocoding, geolocation)
+//   - Owns and manages all services (solar calculator, geocoding, geolocation,
+//     automation)
 //   - Maintains application state (current location, date, settings)
 //   - Handles user actions via callbacks from the UI
 //   - Coordinates data flow between services and UI components
@@ -15,6 +16,7 @@
 //
 //	                    ┌─────────────────┐
 //	                    │   MainWindow    │
+//	                    │  or Terminal    │
 //	                    │   (UI Layer)    │
 //	                    └────────┬────────┘
 //	                             │ callbacks
@@ -26,10 +28,25 @@
 //
 // # Thread Safety
 //
-// The App controller is designed to be used from the main Qt thread.
-// Asynchronous operations (network requests) are performed in goroutines,
-// but all UI updates and state modifications happen on the main thread
-// using mainthread.Wait() from the miqt library.
+// The App controller is designed to be used from the frontend's main thread
+// (the Qt thread, or the terminal UI's event loop). Asynchronous operations
+// (network requests, and sun times over many days on the calculation
+// worker, see worker.go) are performed in goroutines (started with
+// crash.Go, so a panic is reported rather than ending the app), but all UI
+// updates and state modifications happen on the main thread: goroutines
+// hand their results over with onMainThread (frontend.View.RunOnMainThread)
+// and never call App methods directly.
+//
+// The location, date and settings live in a state.State, which goroutines
+// may read at any time (see the state package). Everything else in the App
+// is only accessed on the main thread.
+//
+// # Events
+//
+// Changes of the location, date and sun times are published once on the
+// event bus (see the events package, Events), also on the main thread; the
+// frontend's widgets subscribe to what they show. The rest of the display
+// is updated through the View.
 //
 // This pattern ensures:
 //   - UI remains responsive during network operations
@@ -41,29 +58,56 @@
 // The App constructor performs initialization in a specific order:
 //  1. Load preferences (or use defaults if first run)
 //  2. Create configuration with loaded preferences
-//  3. Create all services (solar, geocoding, geolocation)
+//  3. Create all services (solar, geocoding, geolocation), unless given
+//     to NewWithServices (see services.go)
 //  4. Restore last location (or use default)
-//  5. Create main window (which creates all widgets)
+//  5. Create the frontend's view (e.g., the main window with all widgets)
 //
 // This order is important because:
 //   - Services need settings for proper configuration
-//   - MainWindow needs the App reference for callbacks
+//   - The view needs the App reference for callbacks
 //   - Initial recalculation needs both location and services
 package app
 
 import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
 	"time"
 
-	"github.com/mappu/miqt/qt6/mainthread"
+	"github.com/megatih/GoGoldenHour/internal/changelog"
 	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/coordinates"
+	"github.com/megatih/GoGoldenHour/internal/crash"
 	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/events"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/frontend"
+	"github.com/megatih/GoGoldenHour/internal/geodata"
+	"github.com/megatih/GoGoldenHour/internal/service/aurora"
+	"github.com/megatih/GoGoldenHour/internal/service/automation"
+	"github.com/megatih/GoGoldenHour/internal/service/caldav"
+	"github.com/megatih/GoGoldenHour/internal/service/elevation"
 	"github.com/megatih/GoGoldenHour/internal/service/geocoding"
 	"github.com/megatih/GoGoldenHour/internal/service/geolocation"
+	"github.com/megatih/GoGoldenHour/internal/service/linkscheme"
+	"github.com/megatih/GoGoldenHour/internal/service/photo"
 	"github.com/megatih/GoGoldenHour/internal/service/solar"
+	"github.com/megatih/GoGoldenHour/internal/service/tilecache"
 	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+	"github.com/megatih/GoGoldenHour/internal/service/updates"
+	"github.com/megatih/GoGoldenHour/internal/service/weather"
+	"github.com/megatih/GoGoldenHour/internal/state"
 	"github.com/megatih/GoGoldenHour/internal/storage"
-	"github.com/megatih/GoGoldenHour/internal/ui"
 )
 
 // =============================================================================
@@ -72,50 +116,166 @@ import (
 
 // App is the main application controller that orchestrates all components.
 //
-// The App implements the ui.AppController interface, which defines the callbacks
-// that the MainWindow uses to communicate user actions back to the controller.
+// The App implements the frontend.Controller interface, which defines the
+// callbacks that the frontend uses to communicate user actions back to the
+// controller, and updates the display through a frontend.View.
 //
-// State Management:
-//   - location: The currently selected geographic location
-//   - currentDate: The date for solar calculations (defaults to today)
-//   - config.Settings: User preferences (elevation angles, display format, etc.)
+// State Management (in state, readable from any goroutine):
+//   - Location: The currently selected geographic location
+//   - Date: The date for solar calculations (defaults to today)
+//   - Settings: User preferences (elevation angles, display format, etc.)
 //
 // All state modifications go through public methods that also:
 //  1. Update the relevant UI components
 //  2. Trigger recalculation if needed
 //  3. Persist changes to disk when appropriate
 type App struct {
-	// config holds the complete application configuration including settings.
-	// This is the authoritative source for current settings values.
-	config config.AppConfig
+	// state holds the location, date and settings. It is the authoritative
+	// source for current settings values. Written on the main thread only.
+	state *state.State
 
 	// prefs handles persistence of user settings to disk.
 	// Settings are saved automatically when they change.
-	prefs *storage.PreferencesStore
+	prefs PreferencesStore
+
+	// sessions keeps the session the app quits with (see EndSession); nil
+	// if the config directory isn't available.
+	sessions SessionStore
 
 	// solarCalc performs all solar position and time calculations.
 	// It maintains the current elevation angle settings for golden/blue hour.
-	solarCalc *solar.Calculator
+	solarCalc SolarCalculator
 
-	// geoService provides IP-based location detection.
-	// Used for auto-detect on startup if enabled in settings.
-	geoService *geolocation.IPAPIService
+	// locationProviders are the location detection backends by ID
+	// (domain.LocationProviderIPAPI, ...). Detection chains them in the
+	// order of Settings.LocationProviders (see detectionChain).
+	locationProviders map[string]Geolocator
 
 	// geocoding provides address search and reverse geocoding.
 	// Used for the location search feature and map click handling.
-	geocoding *geocoding.NominatimService
+	geocoding Geocoder
+
+	// timezones looks up the time zone of new locations.
+	timezones TimezoneResolver
+
+	// elevation provides terrain heights for elevation profiles.
+	// Used when the user measures a line on the map.
+	elevation *elevation.OpenMeteoService
+
+	// weather provides the frames of the map's cloud layer.
+	// Used when the user turns the layer on.
+	weather *weather.RainViewerService
+
+	// forecast provides the weather forecast of the selected day.
+	// Used after every recalculation, for the time panel.
+	forecast *weather.OpenMeteoService
+
+	// aurora provides the Kp index forecast.
+	// Used after every recalculation while the aurora outlook is on
+	// (Settings.AuroraForecast), for the Astro panel.
+	aurora *aurora.SWPCService
+
+	// updates looks up the latest release.
+	// Used when the user checks for updates (Help menu).
+	updates *updates.GitHubService
+
+	// tiles serves the map's base layer tiles from disk, loading them from
+	// the tile server when needed. tilesErr is why it couldn't be
+	// started (the map then loads tiles directly), reported by Run.
+	tiles    *tilecache.Cache
+	tilesErr error
+
+	// scheduler runs the user's automation hooks at phase transitions.
+	// Re-armed whenever the location, settings, or hooks change.
+	scheduler *automation.Scheduler
+
+	// summaryScheduler sends the daily summary of the favorites.
+	// Re-armed whenever the settings or favorites change.
+	summaryScheduler *automation.SummaryScheduler
+
+	// feedWriter rewrites the widget feed of the home location.
+	// Re-armed whenever the settings or the home location change.
+	feedWriter *automation.FeedWriter
+
+	// notificationsPaused is true while the tray icon's "Pause
+	// Notifications" is checked: phone reminders and webhook calls aren't
+	// scheduled. Not saved, so a restart resumes them. Only accessed on the
+	// main thread.
+	notificationsPaused bool
+
+	// mapLocatePending is true while DetectLocation waits for the map's
+	// browser geolocation (LocationSource "browser"), so a failure can fall
+	// back to IP detection. Only accessed on the main thread.
+	mapLocatePending bool
 
-	// mainWindow is the main UI controller.
-	// The App calls its methods to update the display.
-	mainWindow *ui.MainWindow
+	// mapLocateCancelled is true after CancelDetection stopped a detection
+	// that waited for the map, so the map's late answer is ignored. Only
+	// accessed on the main thread.
+	mapLocateCancelled bool
 
-	// location is the currently selected geographic location.
-	// Solar calculations are performed for this location.
-	location domain.Location
+	// cancelDetect stops the running location detection, and cancelSearch
+	// the running search; nil while none runs, so a second click doesn't
+	// start another (see CancelDetection, CancelSearch). Only accessed on
+	// the main thread.
+	cancelDetect context.CancelFunc
+	cancelSearch context.CancelFunc
 
-	// currentDate is the date for which solar times are calculated.
-	// Defaults to today, can be changed via the date picker.
-	currentDate time.Time
+	// forecastRequest numbers forecast requests, and cancelForecast cancels
+	// the latest, so a slow forecast for an earlier place or date can't
+	// replace the current one. Only accessed on the main thread.
+	forecastRequest int
+	cancelForecast  context.CancelFunc
+
+	// auroraRequest numbers aurora outlook requests, so a slow Kp forecast
+	// for an earlier place or date can't replace the current outlook. Only
+	// accessed on the main thread.
+	auroraRequest int
+
+	// profileRequest numbers elevation profile requests, so a slow reply
+	// for an earlier measurement can't replace the current one. Only
+	// accessed on the main thread.
+	profileRequest int
+
+	// mapClickRequest numbers map clicks, and cancelMapClick cancels the
+	// name lookup of the latest one, so only the last of several quick
+	// clicks updates the location. Only accessed on the main thread.
+	mapClickRequest int
+	cancelMapClick  context.CancelFunc
+
+	// favoriteTimes caches tonight's golden hour of each favorite by ID,
+	// for the favorites panel. favoriteTimesKey names the day and golden
+	// hour angle they were calculated for; the cache is emptied when it
+	// changes. Only accessed on the main thread.
+	favoriteTimes    map[string]domain.TimeRange
+	favoriteTimesKey string
+
+	// worker calculates the sun times of many days off the main thread
+	// (the date range, the week outlook; see worker.go).
+	worker *calcWorker
+
+	// view is the frontend's display (the Qt main window or the terminal
+	// UI). The App calls its methods to update the display.
+	view frontend.View
+
+	// events publishes the changes of location, date and sun times to the
+	// frontend's subscribers (see Events).
+	events *events.Bus
+
+	// version is the application version, sent in geocoding requests and
+	// compared with Settings.LastSeenVersion for the release notes.
+	version string
+
+	// hadSettings is true if a settings file existed on startup, i.e. the
+	// app ran before on this machine (see showWhatsNew).
+	hadSettings bool
+
+	// sessionStart is when the app started; scratch locations used before
+	// it expire with domain.ScratchKeepSession.
+	sessionStart time.Time
+
+	// profile is the settings profile the app runs (storage.DefaultProfile
+	// unless started with --profile NAME).
+	profile string
 }
 
 // =============================================================================
@@ -124,36 +284,74 @@ type App struct {
 
 // New creates a new application instance with all components initialized.
 //
+// New uses the real services (see Services); NewWithServices takes others,
+// e.g., fakes in tests.
+//
 // Initialization steps:
-//  1. Create and load preferences store
+//  1. Create and load the preferences store of the profile
 //  2. Load settings from disk (or use defaults)
 //  3. Create configuration with loaded settings
 //  4. Create all services with current settings
 //  5. Restore last location or use default
-//  6. Create main window with callback bindings
+//  6. Create the frontend's view with callback bindings
+//
+// Parameters:
+//   - profile: The settings profile to use (storage.DefaultProfile unless
+//     started with --profile NAME)
+//   - newView: Creates the frontend's view (e.g., ui.NewMainWindow)
 //
 // Returns:
 //   - *App: The fully initialized application controller
 //   - error: Non-nil if initialization fails (rare, indicates system issues)
 //
 // The only failure case is if the preferences store cannot be created,
-// which indicates a problem with the user's config directory.
-func New() (*App, error) {
+// which indicates an invalid profile name or a problem with the user's
+// config directory.
+func New(profile string, newView frontend.Factory) (*App, error) {
 	// =========================================================================
 	// Step 1: Initialize Preferences Storage
 	// =========================================================================
 	// Create the preferences store first, as we need it to load settings.
 	// This also creates the config directory if it doesn't exist.
-	prefs, err := storage.NewPreferencesStore()
+	prefs, err := storage.NewPreferencesStore(profile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create preferences store: %w", err)
 	}
+	return NewWithServices(profile, Services{Prefs: prefs}, newView)
+}
+
+// NewWithServices creates an application instance with the given services
+// (constructor injection); those left nil in services are created as New
+// does. The other steps are New's.
+//
+// Parameters:
+//   - profile: The settings profile's name, shown in the title and passed
+//     on to other windows (storage.DefaultProfile for the default)
+//   - services: The services; Prefs is required
+//   - newView: Creates the frontend's view
+//
+// Returns an error if services.Prefs is nil.
+//
+// Example (a controller test without network or Qt):
+//
+//	a, err := app.NewWithServices(storage.DefaultProfile, app.Services{
+//	    Prefs:     &fakePrefs{},
+//	    Geocoder:  &fakeGeocoder{},
+//	    Timezones: fakeTimezones{},
+//	}, newFakeView)
+func NewWithServices(profile string, services Services, newView frontend.Factory) (*App, error) {
+	prefs := services.Prefs
+	if prefs == nil {
+		return nil, errors.New("no preferences store")
+	}
 
 	// =========================================================================
 	// Step 2: Load User Settings
 	// =========================================================================
 	// Load settings from disk. If the file doesn't exist (first run) or is
-	// corrupted, Load() returns default settings.
+	// corrupted, Load() returns default settings (Run then offers the
+	// backup of a corrupted file).
+	hadSettings := prefs.Exists()
 	settings, err := prefs.Load()
 	if err != nil {
 		// This is a fallback that should rarely be needed, as Load() handles
@@ -161,56 +359,157 @@ func New() (*App, error) {
 		settings = domain.DefaultSettings()
 	}
 
+	// Scratch locations saved before their use was recorded count as used
+	// now; those of earlier sessions past the retention are forgotten
+	sessionStart := time.Now()
+	for i := range settings.RecentLocations {
+		if settings.RecentLocations[i].Used.IsZero() {
+			settings.RecentLocations[i].Used = sessionStart
+		}
+	}
+	settings.RecentLocations = domain.ExpireRecentLocations(settings.RecentLocations,
+		domain.ScratchCutoff(settings.ScratchRetentionDays, sessionStart, sessionStart))
+
+	// The session the profile last quit with comes back: its map zoom here,
+	// its planning windows with the view, its location and date in Step 5
+	// (see EndSession)
+	sessions := services.Sessions
+	if sessions == nil {
+		if store, err := storage.NewSessionStore(); err != nil {
+			log.Printf("Warning: the session won't be restored: %v", err)
+		} else {
+			sessions = store
+		}
+	}
+	session := loadSession(sessions, profile)
+	if session.MapZoom > 0 {
+		settings.MapZoom = session.MapZoom
+	}
+
 	// =========================================================================
 	// Step 3: Create Configuration
 	// =========================================================================
 	// Start with default config (window size, app name) and merge in loaded settings.
 	cfg := config.DefaultConfig()
+	cfg.Profile = profile
 	cfg.Settings = settings
+	cfg.OpenPanels = session.Panels
 
 	// =========================================================================
 	// Step 4: Create Services
 	// =========================================================================
 	// Create all services that the application needs. Each service is
 	// independent and can be used immediately after creation.
-	solarCalc := solar.New(settings)
-	geoService := geolocation.NewIPAPIService()
-	geocodingService := geocoding.NewNominatimService()
+	solarCalc := services.Solar
+	if solarCalc == nil {
+		solarCalc = solar.New(settings)
+	}
+	locationProviders := services.LocationProviders
+	if locationProviders == nil {
+		locationProviders = map[string]Geolocator{
+			domain.LocationProviderIPAPI:  geolocation.NewIPAPIService(),
+			domain.LocationProviderIPInfo: geolocation.NewIPInfoService(),
+			domain.LocationProviderSystem: geolocation.NewSystemService(),
+		}
+	}
+	userAgent := geocoding.UserAgent(cfg.AppVersion, settings.ContactEmail)
+	geocoder := services.Geocoder
+	if geocoder == nil {
+		geocoder = geocoding.NewNominatimService(userAgent, geocoding.DefaultCachePath())
+	}
+	geocoder.SetOffline(settings.PrivacyMode)
+	timezones := services.Timezones
+	if timezones == nil {
+		timezones = timezoneLookup{}
+	}
+	elevationService := elevation.NewOpenMeteoService()
+
+	// The tile cache must run before the map is created, which loads its
+	// tiles from the cache's address
+	tiles := tilecache.New(tilecache.DefaultDir(), userAgent)
+	tiles.SetServer(settings.TileServer)
+	tiles.SetOffline(settings.PrivacyMode)
+	var tilesErr error
+	cfg.TileCacheURL, tilesErr = tiles.Start()
+	cfg.ConfigDir = filepath.Dir(prefs.GetConfigPath())
 
 	// =========================================================================
 	// Step 5: Restore or Default Location
 	// =========================================================================
-	// Try to restore the user's last location from settings.
-	// Fall back to default location (London) if no saved location.
-	location := domain.DefaultLocation()
+	// Try to restore the session's location, then the user's last location
+	// from settings. Fall back to the home location (London unless set) if
+	// no saved location. The session's date is restored unless it passed.
+	location := settings.Home()
 	if settings.LastLocation != nil {
 		location = *settings.LastLocation
 	}
+	if session.Location != nil {
+		location = *session.Location
+	}
+	date := sessionStart
+	if day, ok := session.SelectedDate(sessionStart); ok {
+		date = day
+	}
+	// Validate clears a timezone that can't be loaded; look it up again
+	if location.Timezone == "" {
+		location.Timezone = timezones.FromCoordinates(location.Latitude, location.Longitude)
+	}
 
 	// =========================================================================
 	// Step 6: Assemble Application
 	// =========================================================================
 	app := &App{
-		config:      cfg,
-		prefs:       prefs,
-		solarCalc:   solarCalc,
-		geoService:  geoService,
-		geocoding:   geocodingService,
-		location:    location,
-		currentDate: time.Now(),
+		state:             state.New(location, date, settings),
+		prefs:             prefs,
+		sessions:          sessions,
+		solarCalc:         solarCalc,
+		locationProviders: locationProviders,
+		geocoding:         geocoder,
+		timezones:         timezones,
+		elevation:         elevationService,
+		weather:           weather.NewRainViewerService(),
+		forecast:          weather.NewOpenMeteoService(),
+		aurora:            aurora.NewSWPCService(),
+		updates:           updates.NewGitHubService(geocoding.UserAgent(cfg.AppVersion, "")),
+		tiles:             tiles,
+		tilesErr:          tilesErr,
+		version:           cfg.AppVersion,
+		events:            events.New(),
+
+		hadSettings:  hadSettings,
+		sessionStart: sessionStart,
+		profile:      profile,
 	}
 
 	// =========================================================================
-	// Step 7: Create Main Window
+	// Step 7: Create the View
+	// =========================================================================
+	// Create the view last, after the App is fully constructed.
+	// The view receives a reference to the App for callbacks, and
+	// subscribes its widgets to the event bus (Events).
+	app.view = newView(cfg, app)
+	app.worker = newCalcWorker(app.onMainThread)
+
+	// =========================================================================
+	// Step 8: Create Automation Scheduler
 	// =========================================================================
-	// Create the main window last, after the App is fully constructed.
-	// The window receives a reference to the App for callbacks.
-	//
-	// IMPORTANT: The SettingsPanel may trigger callbacks during construction
-	// (when applySettings is called). The recalculate() method checks for
-	// mainWindow == nil to handle this case safely.
-	mainWindow := ui.NewMainWindow(cfg, app)
-	app.mainWindow = mainWindow
+	// Created after the view so hook results always have somewhere to
+	// go. Results arrive on timer goroutines and are shown on the main thread.
+	app.scheduler = automation.NewScheduler(func(result automation.Result) {
+		app.onMainThread(func() {
+			app.view.ShowHookResult(result)
+		})
+	})
+	app.summaryScheduler = automation.NewSummaryScheduler(func(result automation.SummaryResult) {
+		app.onMainThread(func() {
+			app.view.ShowSummaryResult(result)
+		})
+	})
+	app.feedWriter = automation.NewFeedWriter(func(result automation.FeedResult) {
+		app.onMainThread(func() {
+			app.view.ShowFeedResult(result)
+		})
+	})
 
 	return app, nil
 }
@@ -222,18 +521,49 @@ func New() (*App, error) {
 // Run starts the application and makes it visible.
 //
 // This method should be called after New() returns successfully. It:
-//  1. Shows the main window
-//  2. Either auto-detects location or uses saved/default location
+//  1. Shows the view (the main window), and the welcome wizard on a first start
+//  2. Opens the shared link, or auto-detects location, or uses the
+//     saved/default location
 //  3. Performs initial solar calculations
+//  4. Arms automation hooks and the daily summary, and starts the day/night
+//     overlay updates
+//  5. Queues the release notes after an update (shown once the event
+//     loop runs, so startup isn't blocked by the dialog)
 //
-// After Run() returns, the application is ready and the Qt event loop
-// should be started with qt.QApplication_Exec().
-func (a *App) Run() {
-	// Show the main window to the user
-	a.mainWindow.Show()
+// Parameters:
+//   - link: A share link from the command line (see domain.Link), or nil
+//
+// After Run() returns, the application is ready and the frontend's event
+// loop should be started (qt.QApplication_Exec() for the main window).
+func (a *App) Run(link *domain.Link) {
+	// Show the main window (or terminal UI) to the user
+	a.view.Show()
+
+	// Without a time zone database, every location's times would silently
+	// be shown in the system's local time (only possible in builds tagged
+	// system_tzdata, see timezone.Database)
+	if db := timezone.Database(); !db.Available {
+		a.view.ShowError("No time zone database found: times are shown in local time")
+	}
+	if a.tilesErr != nil {
+		a.view.ShowError(fmt.Sprintf("Map tile cache unavailable: %v", a.tilesErr))
+	}
+	if a.prefs.Corrupt() {
+		a.view.ShowSettingsRecovery(a.prefs.HasBackup())
+	}
+
+	// A first start asks for the home location and formats (see
+	// CompleteWelcome) before the initial location is chosen below
+	if !a.hadSettings {
+		a.view.ShowWelcome()
+	}
 
-	// Determine initial location based on user preference
-	if a.config.Settings.AutoDetectLocation {
+	// Determine initial location: a shared link wins over the user's
+	// preference
+	if link != nil {
+		a.recalculate()
+		a.OpenLink(*link)
+	} else if a.state.Settings().AutoDetectLocation {
 		// Start async location detection
 		// This will update the UI when complete
 		a.DetectLocation()
@@ -241,41 +571,312 @@ func (a *App) Run() {
 		// Use saved or default location and calculate sun times immediately
 		a.recalculate()
 	}
+
+	// Arm automation hooks for the initial location
+	a.rescheduleHooks()
+	a.reportUnconfirmedHooks()
+	a.summaryScheduler.Schedule(a.state.Settings())
+	a.feedWriter.Schedule(a.state.Settings())
+
+	// Draw the day/night terminator and keep it moving
+	crash.Go(a.runTerminatorUpdates)
+
+	// Queued, so it runs once the event loop is started
+	a.view.QueueOnMainThread(a.showWhatsNew)
+}
+
+// showWhatsNew shows the release notes of the versions released since the
+// app last ran, and records the running version.
+//
+// Nothing is shown on a fresh install (nothing is new to a new user), or if
+// the user turned the notes off. Settings files from before the version was
+// tracked get the notes of the running version only, rather than the whole
+// history.
+func (a *App) showWhatsNew() {
+	settings := a.state.Settings()
+	if settings.LastSeenVersion == a.version {
+		return
+	}
+
+	if settings.ShowWhatsNew && a.hadSettings {
+		releases := changelog.Between(settings.LastSeenVersion, a.version)
+		if settings.LastSeenVersion == "" && len(releases) > 1 {
+			releases = releases[:1]
+		}
+		if len(releases) > 0 {
+			a.view.ShowWhatsNew(releases)
+		}
+	}
+
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.LastSeenVersion = a.version
+	})
+	a.saveSettings()
+}
+
+// CompleteWelcome applies the answers of the welcome wizard shown on a
+// first start (see Run), and writes the first settings file.
+//
+// Parameters:
+//   - home: The chosen home location, or nil for the default (London)
+//   - autoDetect: Whether the location is detected on startup
+//   - timeFormat24Hour: Whether times are shown in 24-hour format
+//   - coordinateFormat: domain.CoordinateFormatDecimal or
+//     domain.CoordinateFormatDMS
+//
+// Without detection, the home location is shown right away; otherwise it
+// is the fallback should detection fail. Canceling the wizard keeps the
+// defaults; it isn't shown again either way, as the release notes state is
+// saved on every start.
+func (a *App) CompleteWelcome(home *domain.Location, autoDetect, timeFormat24Hour bool, coordinateFormat string) {
+	a.UpdateHomeLocation(home)
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		s.AutoDetectLocation = autoDetect
+		s.TimeFormat24Hour = timeFormat24Hour
+		s.CoordinateFormat = coordinateFormat
+	})
+	a.view.ReloadSettings(settings)
+	a.saveSettings()
+
+	if !autoDetect {
+		a.state.SetLocation(settings.Home())
+		events.Publish(a.events, events.LocationChanged{Location: settings.Home()})
+	}
+}
+
+// UpdateShowWhatsNew records whether release notes are shown after updates.
+//
+// Called with the state of the checkbox whenever the "What's new" dialog
+// is closed.
+func (a *App) UpdateShowWhatsNew(show bool) {
+	if show == a.state.Settings().ShowWhatsNew {
+		return
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.ShowWhatsNew = show
+	})
+	a.saveSettings()
 }
 
 // =============================================================================
 // Location Management
 // =============================================================================
 
-// DetectLocation attempts to detect the user's location using IP geolocation.
+// DetectLocation attempts to detect the user's location.
+//
+// The backend follows the LocationSource setting: IP geolocation, the OS
+// location services (GeoClue2/Windows Location), or the map's browser
+// geolocation. Providers are tried in the user's fallback order, each for
+// at most its timeout (see domain.Settings.DetectionOrder), so a blocked or
+// denied backend falls back to the next.
+//
+// In privacy mode only the OS location services may be used: the map's
+// geolocation (which asks a network location service) is skipped, and if
+// the OS location services are turned off the location is left as is.
 //
 // This method runs asynchronously to avoid blocking the UI. The detection
 // process:
-//  1. Queries the IP-API service in a background goroutine
+//  1. Queries the selected backend in a background goroutine
 //  2. Waits for the main thread before updating UI
 //  3. Either updates to detected location or falls back to default
 //
-// Thread Safety: Uses mainthread.Wait() to ensure UI updates happen on
-// the Qt main thread.
+// While a detection runs, further calls are ignored; CancelDetection stops
+// it.
+//
+// Thread Safety: Uses onMainThread() to ensure UI updates happen on
+// the main thread.
 func (a *App) DetectLocation() {
-	// Run geolocation in background to keep UI responsive
-	go func() {
-		// Make network request to IP-API
-		location, err := a.geoService.DetectLocation()
+	if a.cancelDetect != nil {
+		return // e.g., a double click
+	}
+	settings := a.state.Settings()
+	if settings.LocationSource == domain.LocationSourceBrowser && !settings.PrivacyMode {
+		// The map reports back through OnMapLocate; a cancelled request
+		// can't be withdrawn from the page, so its answer is ignored
+		a.mapLocatePending = true
+		a.cancelDetect = func() {
+			a.mapLocatePending = false
+			a.mapLocateCancelled = true
+		}
+		a.view.SetBusy(frontend.TaskDetect, true)
+		a.view.LocateWithMap()
+		return
+	}
+
+	chain := a.detectionChain()
+	if len(chain) == 0 && settings.PrivacyMode {
+		a.view.ShowError("Location not detected: privacy mode only allows the system " +
+			"location services (Edit → Preferences → Network)")
+		// On startup nothing has been calculated yet
+		a.recalculate()
+		return
+	}
+
+	a.detectInBackground(chain, nil)
+}
+
+// detectInBackground runs location detection in a background goroutine
+// (keeping the UI responsive) and applies the result on the main thread,
+// unless CancelDetection stopped it first.
+//
+// Parameters:
+//   - chain: The providers to try (see detectionChain)
+//   - before: Why an earlier backend (the map) failed, or nil
+func (a *App) detectInBackground(chain geolocation.Chain, before error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelDetect = cancel
+	a.view.SetBusy(frontend.TaskDetect, true)
+	crash.Go(func() {
+		location, err, fallbackReason := a.detectWithChain(ctx, chain, before)
 
 		// Switch back to main thread for UI updates
-		mainthread.Wait(func() {
-			if err != nil {
-				// Show error to user but don't fail completely
-				a.mainWindow.ShowError(fmt.Sprintf("Failed to detect location: %v", err))
-				// Fall back to default location (London)
-				a.UpdateLocation(domain.DefaultLocation())
-				return
+		a.onMainThread(func() {
+			if ctx.Err() != nil {
+				return // stopped by CancelDetection
 			}
-			// Success - update to detected location
-			a.UpdateLocation(location)
+			cancel()
+			a.applyDetectedLocation(location, err, fallbackReason)
 		})
-	}()
+	})
+}
+
+// CancelDetection stops the running location detection (the Detect
+// button while detecting). The location stays as it was; its sun times are
+// shown, as on a start without detection (the detection on startup may be
+// the one stopped).
+func (a *App) CancelDetection() {
+	if a.cancelDetect == nil {
+		return
+	}
+	a.cancelDetect()
+	a.finishDetection()
+	a.recalculate()
+}
+
+// finishDetection marks the detection as no longer running.
+func (a *App) finishDetection() {
+	a.cancelDetect = nil
+	a.view.SetBusy(frontend.TaskDetect, false)
+}
+
+// detectionChain returns the location providers to try, in the order and
+// with the timeouts of the settings. Called on the main thread.
+func (a *App) detectionChain() geolocation.Chain {
+	var chain geolocation.Chain
+	for _, p := range a.state.Settings().DetectionOrder() {
+		if provider, ok := a.locationProviders[p.ID]; ok {
+			chain = append(chain, geolocation.Step{Provider: provider, Timeout: p.Timeout()})
+		}
+	}
+	return chain
+}
+
+// detectWithChain finds the location with the first working provider.
+//
+// Providers that only report coordinates (the system location services)
+// get their name by reverse geocoding (falling back to the coordinates),
+// and the timezone comes from the offline timezone database if the
+// provider didn't report one, as for a map click.
+//
+// This blocks for up to the providers' timeouts and must be called from a
+// background goroutine.
+//
+// Parameters:
+//   - ctx: Cancels the detection and the name lookup (see CancelDetection)
+//   - chain: The providers to try (see detectionChain)
+//   - before: Why an earlier backend (the map) failed, or nil
+//
+// Returns the location, the error if every provider failed, and the
+// errors of the backends that failed before one succeeded (nil if none).
+func (a *App) detectWithChain(ctx context.Context, chain geolocation.Chain, before error) (loc domain.Location, err, fallbackReason error) {
+	detection, err := chain.Detect(ctx)
+	if err != nil {
+		return domain.Location{}, err, nil
+	}
+
+	loc = detection.Location
+	if loc.Name == "" {
+		// Error is intentionally ignored - we fall back to coordinate display
+		place, _ := a.geocoding.ReverseGeocode(ctx, loc.Latitude, loc.Longitude)
+		loc.Name, loc.City, loc.Region, loc.Country = place.Name, place.City, place.Region, place.Country
+		if loc.Name == "" {
+			loc.Name = fmt.Sprintf("%.4f, %.4f", loc.Latitude, loc.Longitude)
+		}
+	}
+	if loc.Timezone == "" {
+		loc.Timezone = a.timezones.FromCoordinates(loc.Latitude, loc.Longitude)
+	}
+	failures := detection.Failures
+	if before != nil {
+		failures = append([]error{before}, failures...)
+	}
+	if len(failures) > 0 {
+		fallbackReason = fmt.Errorf("%w; using %s", errors.Join(failures...),
+			domain.LocationProvider{ID: detection.Provider}.Label())
+	}
+	return loc, nil, fallbackReason
+}
+
+// applyDetectedLocation finishes location detection on the main thread.
+//
+// Parameters:
+//   - location: The detected location (ignored if err is non-nil)
+//   - err: Detection error; the home location is used instead
+//   - fallbackReason: Why the preferred backends were replaced by a later
+//     one (naming it), or nil if they weren't
+func (a *App) applyDetectedLocation(location domain.Location, err, fallbackReason error) {
+	a.finishDetection()
+	if err != nil {
+		// Show error to user but don't fail completely; detection can be
+		// retried from the error, e.g., once the network is back
+		a.view.ShowRetryableError(fmt.Sprintf("Failed to detect location: %v", err), a.DetectLocation)
+		// Fall back to the home location (London unless set)
+		home := a.state.Settings().Home()
+		if home.Timezone == "" {
+			home.Timezone = a.timezones.FromCoordinates(home.Latitude, home.Longitude)
+		}
+		a.UpdateLocation(home)
+		return
+	}
+	// Success - update to detected location
+	a.UpdateLocation(location)
+
+	// Tell the user why a fallback (possibly less accurate) was used
+	if fallbackReason != nil {
+		a.view.ShowError(fallbackReason.Error())
+	}
+}
+
+// OnMapLocate handles the result of a browser geolocation request.
+//
+// Requests come from the map's locate button or from DetectLocation when
+// the map is the selected location source. A found position is selected
+// like a map click (reverse geocoded in the background). A failure is
+// shown to the user; if DetectLocation started the request, the location
+// providers are used instead so detection still produces a location.
+func (a *App) OnMapLocate(lat, lon float64, err error) {
+	pending := a.mapLocatePending
+	a.mapLocatePending = false
+	if a.mapLocateCancelled {
+		a.mapLocateCancelled = false
+		return // the detection was stopped by CancelDetection
+	}
+	if pending {
+		a.finishDetection()
+	}
+
+	if err == nil {
+		a.OnMapClick(lat, lon)
+		return
+	}
+
+	locateErr := fmt.Errorf("map location unavailable: %w", err)
+	if !pending {
+		a.view.ShowError(locateErr.Error())
+		return
+	}
+
+	a.detectInBackground(a.detectionChain(), locateErr)
 }
 
 // UpdateLocation updates the current location and triggers recalculation.
@@ -284,27 +885,102 @@ func (a *App) DetectLocation() {
 //   - DetectLocation (after IP geolocation)
 //   - SearchLocation (after geocoding search results)
 //   - OnMapClick (after map click with reverse geocoding)
+//   - The location panel's "Recent" menu
 //
 // The method performs these actions:
-//  1. Updates the internal location state
+//  1. Updates the internal location state; a favorite's timezone and
+//     elevation overrides replace those of the location (see
+//     domain.FavoriteOverrides)
 //  2. Updates the UI to show the new location
-//  3. Recalculates sun times for the new location
-//  4. Saves the location as "last location" for future sessions
+//  3. Recalculates sun times for the new location (with the favorite's
+//     golden hour angle, if it has one)
+//  4. Saves the location as "last location" for future sessions and, unless
+//     it is a favorite, moves it to the front of the scratch locations
+//     (expiring those past Settings.ScratchRetentionDays)
 func (a *App) UpdateLocation(loc domain.Location) {
+	// A favorite is used with its own settings, however it was selected
+	if f, ok := domain.FavoriteAt(a.state.Settings().Favorites, loc); ok && !f.Overrides.IsZero() {
+		loc = f.CurrentLocation()
+	}
+
 	// Update internal state
-	a.location = loc
+	a.state.SetLocation(loc)
 
 	// Update UI components (location panel, map)
-	a.mainWindow.UpdateLocation(loc)
+	events.Publish(a.events, events.LocationChanged{Location: loc})
 
 	// Recalculate sun times for new location
 	a.recalculate()
 
-	// Persist as last used location for next app launch
-	a.config.Settings.LastLocation = &loc
+	// Hooks follow the selected location
+	a.rescheduleHooks()
+
+	// Persist as last used location for next app launch, and remember it
+	// in the history for the "Recent" menu
+	now := time.Now()
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		s.LastLocation = &loc
+		s.RecentLocations = domain.ExpireRecentLocations(s.RecentLocations,
+			domain.ScratchCutoff(s.ScratchRetentionDays, now, a.sessionStart))
+		if _, ok := domain.FavoriteAt(s.Favorites, loc); !ok {
+			s.RecentLocations = domain.AddRecentLocation(s.RecentLocations, loc, now)
+		}
+	})
+	a.view.UpdateRecentLocations(settings.RecentLocations)
 	a.saveSettings()
 }
 
+// =============================================================================
+// Session
+// =============================================================================
+
+// loadSession returns the last session if the profile ran it, so a profile
+// doesn't start with another one's place and date. Any failure to read it
+// is an empty session.
+//
+// Parameters:
+//   - sessions: The session store, or nil
+//   - profile: The profile starting
+func loadSession(sessions SessionStore, profile string) domain.Session {
+	if sessions == nil {
+		return domain.Session{}
+	}
+	session, err := sessions.Load()
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return domain.Session{}
+	}
+	if session.Profile != profile {
+		return domain.Session{}
+	}
+	return session
+}
+
+// EndSession saves the session when the app quits: the profile, selected
+// place and date, map zoom and the planning windows left open, restored
+// by the next launch (see domain.Session).
+//
+// Parameters:
+//   - panels: The planning windows open (domain.SessionPanel*)
+func (a *App) EndSession(panels []string) {
+	if a.sessions == nil {
+		return
+	}
+	snap := a.state.Snapshot()
+	err := a.sessions.Save(domain.Session{
+		Profile:  a.profile,
+		Location: &snap.Location,
+		Date:     snap.Date.Format(time.DateOnly),
+		MapZoom:  snap.Settings.MapZoom,
+		Panels:   panels,
+		SavedAt:  time.Now(),
+	})
+	if err != nil {
+		// The window is closing, so there is no one to show it to
+		log.Printf("Warning: %v", err)
+	}
+}
+
 // =============================================================================
 // Date Management
 // =============================================================================
@@ -319,15 +995,51 @@ func (a *App) UpdateLocation(loc domain.Location) {
 // The method updates the date state, UI display, and recalculates sun times.
 func (a *App) UpdateDate(date time.Time) {
 	// Update internal state
-	a.currentDate = date
+	a.state.SetDate(date)
 
 	// Update UI date display
-	a.mainWindow.UpdateDate(date)
+	events.Publish(a.events, events.DateChanged{Date: date})
 
 	// Recalculate sun times for new date
 	a.recalculate()
 }
 
+// UpdateDateRange plans several days: the date range from the selected
+// date to end, whose days are listed in the day table.
+//
+// Parameters:
+//   - end: The last day of the range; zero plans the selected date only
+func (a *App) UpdateDateRange(end time.Time) {
+	a.state.SetRangeEnd(end)
+	a.recalculate()
+}
+
+// RefreshCountdown calculates the days the countdown to the next golden or
+// blue hour runs through: yesterday to tomorrow at the current location,
+// whatever date is selected. Yesterday is included for a blue hour that
+// runs past midnight.
+//
+// This is called after every recalculation, and by the countdown itself
+// when the clock passes midnight.
+func (a *App) RefreshCountdown() {
+	if a.view == nil {
+		return
+	}
+	loc := a.state.Location()
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		// Like the calculator, fall back to the system timezone
+		tz = time.Local
+	}
+	today := time.Now().In(tz)
+	days, err := a.solarCalc.CalculateRange(loc, domain.NewDateRange(today.AddDate(0, 0, -1), today.AddDate(0, 0, 1)))
+	if err != nil {
+		a.view.ShowError(fmt.Sprintf("Countdown not updated: %v", err))
+		days = nil
+	}
+	a.view.UpdateCountdown(days)
+}
+
 // =============================================================================
 // Settings Management
 // =============================================================================
@@ -338,13 +1050,59 @@ func (a *App) UpdateDate(date time.Time) {
 // such as elevation angles or time format preferences.
 //
 // The method:
-//  1. Updates the configuration with new settings
+//  1. Updates the configuration with new settings (last, home and scratch
+//     locations, map zoom, automation hooks, favorites, daily summary, desktop notifications, calendar sync,
+//     webhook, widget feed, contact email, tile server, search bias, home timezone, copy template, minimize to tray, location
+//     providers, privacy mode, custom events, presets and release notes state are kept, since the panel doesn't
+//     manage them), and stores the new elevation angles in the active preset
 //  2. Updates the solar calculator with new elevation angles
 //  3. Saves settings to disk for persistence
 //  4. Recalculates sun times with new parameters
+//  5. Re-arms automation hooks (elevation angles move the event times), the
+//     daily summary and the widget feed (which use the angles and time
+//     format)
 func (a *App) UpdateSettings(settings domain.Settings) {
-	// Update configuration
-	a.config.Settings = settings
+	// Keep state the App tracks itself rather than taking the (possibly
+	// stale) copy held by the settings panel
+	settings = a.state.UpdateSettings(func(current *domain.Settings) {
+		settings.LastLocation = current.LastLocation
+		settings.HomeLocation = current.HomeLocation
+		settings.RecentLocations = current.RecentLocations
+		settings.MapZoom = current.MapZoom
+		settings.PanelLayout = current.PanelLayout
+		settings.WindowLayout = current.WindowLayout
+		settings.AutomationEnabled = current.AutomationEnabled
+		settings.Hooks = current.Hooks
+		settings.Favorites = current.Favorites
+		settings.DailySummary = current.DailySummary
+		settings.Notifications = current.Notifications
+		settings.Push = current.Push
+		settings.CalDAV = current.CalDAV
+		settings.Webhook = current.Webhook
+		settings.WidgetFeed = current.WidgetFeed
+		settings.ContactEmail = current.ContactEmail
+		settings.TileServer = current.TileServer
+		settings.SearchBias = current.SearchBias
+		settings.HomeTimezone = current.HomeTimezone
+		settings.CopyTemplate = current.CopyTemplate
+		settings.MinimizeToTray = current.MinimizeToTray
+		settings.AuroraForecast = current.AuroraForecast
+		settings.LocationSource = current.LocationSource
+		settings.LocationProviders = current.LocationProviders
+		settings.SecureLocationOnly = current.SecureLocationOnly
+		settings.CoordinateFormat = current.CoordinateFormat
+		settings.PlaceNameStyle = current.PlaceNameStyle
+		settings.TimePanelLayout = current.TimePanelLayout
+		settings.ScratchRetentionDays = current.ScratchRetentionDays
+		settings.PrivacyMode = current.PrivacyMode
+		settings.LastSeenVersion = current.LastSeenVersion
+		settings.ShowWhatsNew = current.ShowWhatsNew
+		settings.CustomEvents = current.CustomEvents
+		settings.Presets = current.Presets
+		settings.ActivePreset = current.ActivePreset
+		*current = settings
+		current.SyncActivePreset()
+	})
 
 	// Update solar calculator with new elevation angles
 	// This is necessary because the calculator caches the settings
@@ -355,116 +1113,2022 @@ func (a *App) UpdateSettings(settings domain.Settings) {
 
 	// Recalculate with new settings (may change golden/blue hour times)
 	a.recalculate()
-}
 
-// =============================================================================
-// Location Search
-// =============================================================================
+	// Golden/blue hour boundaries moved, so hook timers must follow
+	a.rescheduleHooks()
+	a.rescheduleSummary()
+	a.rescheduleFeed()
+}
 
-// SearchLocation performs a geocoding search and updates to the first result.
-//
-// This is called when the user types a location query and presses Enter or
-// clicks the Search button. The search runs asynchronously to keep the UI
-// responsive.
-//
-// Search flow:
-//  1. Query the Nominatim geocoding service (background)
-//  2. Wait for main thread
-//  3. If successful, update to first result
-//  4. If failed or no results, show error message
+// UpdateMapZoom records the map's zoom level after the user zooms.
 //
-// Thread Safety: Uses mainthread.Wait() for UI updates.
-func (a *App) SearchLocation(query string) {
-	// Run geocoding in background
-	go func() {
-		// Search for up to 5 matching locations
-		locations, err := a.geocoding.Search(query, 5)
+// The zoom level is persisted so that later location changes and the next
+// app launch keep the user's preferred level of detail. Recalculation is not
+// needed since zoom doesn't affect sun times.
+func (a *App) UpdateMapZoom(zoom int) {
+	if zoom == a.state.Settings().MapZoom {
+		return
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.MapZoom = zoom
+	})
+	a.saveSettings()
+}
 
-		// Switch back to main thread for UI updates
-		mainthread.Wait(func() {
-			if err != nil {
-				a.mainWindow.ShowError(fmt.Sprintf("Search failed: %v", err))
-				return
-			}
-			if len(locations) == 0 {
-				a.mainWindow.ShowError("No locations found")
-				return
-			}
-			// Use the first (most relevant) result
-			a.UpdateLocation(locations[0])
-		})
-	}()
+// UpdatePanelLayout records the arrangement of the side panels when the
+// app quits, so the next launch shows them where the user left them.
+func (a *App) UpdatePanelLayout(layout []byte) {
+	if bytes.Equal(layout, a.state.Settings().PanelLayout) {
+		return
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.PanelLayout = layout
+	})
+	a.saveSettings()
 }
 
-// =============================================================================
-// Map Interaction
-// =============================================================================
+// UpdateWindowLayout records the layout chosen in View → Layout
+// (domain.WindowLayoutAutomatic, WindowLayoutWide or WindowLayoutCompact).
+func (a *App) UpdateWindowLayout(layout string) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.WindowLayout = layout
+	})
+	a.saveSettings()
+}
 
-// OnMapClick handles map click events by reverse geocoding the clicked location.
-//
-// When the user clicks on the map, this method:
-//  1. Attempts to reverse geocode the coordinates to get a place name
-//  2. Creates a location with the coordinates and name
-//  3. Falls back to coordinate display if reverse geocoding fails
-//  4. Updates to the new location
+// RestoreSettings replaces all settings after the settings file was found
+// damaged at startup: with those of the backup (the file as it was before
+// its last save), or with the defaults.
 //
-// The reverse geocoding is optional - the app works fine with just coordinates.
-// This is why errors from ReverseGeocode are intentionally ignored.
+// The current location stays on screen. Everything else is applied as at
+// startup: the services (solar calculator, geocoding, tile cache) and the
+// main window are updated, the favorites, sun times and schedules follow,
+// and the settings are saved, after which backups rotate again (see
+// storage.PreferencesStore.Recover).
 //
-// Thread Safety: Uses mainthread.Wait() for UI updates.
-func (a *App) OnMapClick(lat, lon float64) {
-	// Reverse geocode in background
-	go func() {
-		// Try to get a human-readable name for the coordinates.
-		// Error is intentionally ignored - we fall back to coordinate display.
-		name, _ := a.geocoding.ReverseGeocode(lat, lon)
-
-		// Switch back to main thread for UI updates
-		mainthread.Wait(func() {
-			// Build location with timezone from coordinates
-			loc := domain.Location{
-				Latitude:  lat,
-				Longitude: lon,
-				Name:      name,
-				Timezone:  timezone.FromCoordinates(lat, lon),
-			}
+// Returns an error if the backup is missing or damaged; settings are left
+// unchanged.
+func (a *App) RestoreSettings(fromBackup bool) error {
+	settings, err := a.prefs.Recover(fromBackup)
+	if err != nil {
+		return err
+	}
+	a.replaceSettings(settings)
+	return nil
+}
 
-			// Fall back to coordinate display if no name was found
-			if name == "" {
-				loc.Name = fmt.Sprintf("%.4f, %.4f", lat, lon)
-			}
+// replaceSettings replaces all settings at once (see RestoreSettings and
+// ImportConfiguration), keeping the current location on screen, and
+// applies them as at startup.
+func (a *App) replaceSettings(settings domain.Settings) {
+	settings = a.state.UpdateSettings(func(current *domain.Settings) {
+		settings.LastLocation = current.LastLocation
+		*current = settings
+	})
+	a.solarCalc.UpdateSettings(settings)
+	userAgent := geocoding.UserAgent(a.version, settings.ContactEmail)
+	a.geocoding.SetUserAgent(userAgent)
+	a.geocoding.SetOffline(settings.PrivacyMode)
+	a.tiles.SetUserAgent(userAgent)
+	a.tiles.SetServer(settings.TileServer)
+	a.tiles.SetOffline(settings.PrivacyMode)
 
-			a.UpdateLocation(loc)
-		})
-	}()
+	a.view.ReloadSettings(settings)
+	a.updateFavorites(settings)
+	a.saveSettings()
+	a.recalculate()
+	a.rescheduleHooks()
+	a.rescheduleSummary()
+	a.rescheduleFeed()
 }
 
 // =============================================================================
-// State Getters (implements ui.AppController interface)
+// Presets
 // =============================================================================
 
-// GetSettings returns the current settings.
+// SelectPreset switches to the named preset from the toolbar (see
+// domain.Preset).
 //
-// This is part of the ui.AppController interface, allowing the UI to query
-// current settings values (e.g., for initializing the settings panel).
-func (a *App) GetSettings() domain.Settings {
-	return a.config.Settings
+// The preset's elevation angles and custom events become the current ones:
+// the settings panel is refreshed to show them, and the new settings are
+// applied, persisted and used for recalculation as if the user had entered
+// them. Unknown names are ignored.
+func (a *App) SelectPreset(name string) {
+	var found bool
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		found = s.ApplyPreset(name)
+	})
+	if !found {
+		return
+	}
+
+	// Refresh the settings controls so they reflect the preset's values
+	// (the controls don't report them back), then apply them
+	a.view.ApplySettings(settings)
+	a.UpdateSettings(settings)
+	a.view.UpdatePresets(a.state.Settings())
 }
 
-// GetLocation returns the current location.
+// SavePresetAs saves the current elevation angles and custom events as a
+// preset with the given name, which becomes the active one. A preset of the
+// same name is replaced.
 //
-// This is part of the ui.AppController interface, allowing the UI to query
-// the current location (e.g., for displaying in the location panel).
-func (a *App) GetLocation() domain.Location {
-	return a.location
+// Returns an error if the name is invalid or too many presets are saved
+// (see domain.Settings.SavePreset); settings are left unchanged.
+func (a *App) SavePresetAs(name string) error {
+	var err error
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		err = s.SavePreset(name)
+	})
+	if err != nil {
+		return err
+	}
+	a.saveSettings()
+	a.view.UpdatePresets(settings)
+	return nil
 }
 
-// GetDate returns the current date for calculations.
-//
-// This is part of the ui.AppController interface, allowing the UI to query
+// DeletePreset removes the named preset. The current elevation angles and
+// custom events stay, so nothing is recalculated; if the preset was the
+// active one, no preset is active until the user picks one.
+func (a *App) DeletePreset(name string) {
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		s.DeletePreset(name)
+	})
+	a.saveSettings()
+	a.view.UpdatePresets(settings)
+}
+
+// UpdateCustomEvents applies the custom events from the preferences dialog.
+//
+// The events are repaired like loaded ones (names trimmed, elevations
+// clamped), stored in the active preset and saved, and the sun times are
+// recalculated to show them.
+func (a *App) UpdateCustomEvents(events []domain.CustomEvent) {
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		s.CustomEvents = events
+		s.Validate()
+		s.SyncActivePreset()
+	})
+	a.solarCalc.UpdateSettings(settings)
+	a.saveSettings()
+	a.recalculate()
+	a.view.UpdatePresets(settings)
+}
+
+// =============================================================================
+// Automation
+// =============================================================================
+
+// UpdateAutomation applies the automation preferences from the preferences dialog.
+//
+// The master switch and hooks are saved and the scheduler is re-armed, so
+// the new configuration takes effect immediately. The dialog has already
+// asked the user to confirm any new commands.
+func (a *App) UpdateAutomation(enabled bool, hooks []domain.Hook) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.AutomationEnabled = enabled
+		s.Hooks = hooks
+	})
+	a.saveSettings()
+	a.rescheduleHooks()
+}
+
+// UpdateContactEmail applies the contact email from the preferences dialog.
+//
+// The address is saved and used in the User-Agent of later geocoding
+// requests (see geocoding.UserAgent). The dialog has already checked it;
+// an invalid address is dropped, as Settings.Validate would on the next
+// start.
+func (a *App) UpdateContactEmail(email string) {
+	email = strings.TrimSpace(email)
+	if !domain.IsValidContactEmail(email) {
+		email = ""
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.ContactEmail = email
+	})
+	a.geocoding.SetUserAgent(geocoding.UserAgent(a.version, email))
+	a.tiles.SetUserAgent(geocoding.UserAgent(a.version, email))
+	a.saveSettings()
+}
+
+// UpdateSearchBias applies the search preferences from the preferences
+// dialog.
+//
+// The bias is saved and used by later searches (see SearchLocation). The
+// dialog has already checked it; malformed languages and countries are
+// dropped, as Settings.Validate would on the next start.
+func (a *App) UpdateSearchBias(bias domain.SearchBias) {
+	if bias.Validate() != nil {
+		bias = domain.SearchBias{NearMapView: bias.NearMapView}
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.SearchBias = bias
+	})
+	a.saveSettings()
+}
+
+// UpdateLocationProviders applies the location source
+// (Settings.LocationSource), the detection fallback order and the
+// HTTPS-only switch (Settings.SecureLocationOnly) from the preferences
+// dialog. The list is repaired like a loaded one (see
+// domain.RepairLocationProviders) and saved; the next detection uses it.
+func (a *App) UpdateLocationProviders(source string, providers []domain.LocationProvider, secureOnly bool) {
+	providers = domain.RepairLocationProviders(providers)
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.LocationSource = source
+		s.LocationProviders = providers
+		s.SecureLocationOnly = secureOnly
+	})
+	a.saveSettings()
+}
+
+// UpdatePrivacyMode turns privacy mode on or off (see
+// Settings.PrivacyMode).
+//
+// The setting is saved, geocoding and the tile cache switch to their
+// cached answers (or back), and push reminders are re-armed, as they
+// aren't sent in privacy mode. The MainWindow updates the map and the
+// status bar itself.
+func (a *App) UpdatePrivacyMode(on bool) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.PrivacyMode = on
+	})
+	a.geocoding.SetOffline(on)
+	a.tiles.SetOffline(on)
+	a.saveSettings()
+	a.rescheduleHooks()
+}
+
+// UpdateDisplayFormats applies the display formats from the preferences
+// dialog: the coordinate format, the place name style, the time panel
+// layout and how long scratch locations stay in the Recent menu.
+//
+// The settings are saved and recent locations past the new retention are
+// dropped from the menu. The MainWindow shows the new formats itself; sun
+// times don't change, so nothing is recalculated.
+func (a *App) UpdateDisplayFormats(coordinateFormat, placeNameStyle, timePanelLayout string, scratchRetentionDays int) {
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		s.CoordinateFormat = coordinateFormat
+		s.PlaceNameStyle = placeNameStyle
+		s.TimePanelLayout = timePanelLayout
+		s.ScratchRetentionDays = scratchRetentionDays
+		s.RecentLocations = domain.ExpireRecentLocations(s.RecentLocations,
+			domain.ScratchCutoff(s.ScratchRetentionDays, time.Now(), a.sessionStart))
+	})
+	a.view.UpdateRecentLocations(settings.RecentLocations)
+	a.saveSettings()
+}
+
+// UpdateHomeLocation applies the home location from the preferences dialog
+// (see domain.Settings.Home).
+//
+// The location is saved and shown the next time detection fails or on a
+// first run, not now; the widget feed follows it right away. nil goes back
+// to the default location.
+func (a *App) UpdateHomeLocation(home *domain.Location) {
+	if home != nil {
+		loc := *home
+		if !loc.Sanitize() {
+			return
+		}
+		if loc.Timezone == "" {
+			loc.Timezone = a.timezones.FromCoordinates(loc.Latitude, loc.Longitude)
+		}
+		home = &loc
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.HomeLocation = home
+	})
+	a.saveSettings()
+	a.rescheduleFeed()
+}
+
+// UpdateHomeTimezone applies the home clock's timezone from the preferences
+// dialog.
+//
+// The name is saved (cleared if it isn't a timezone); the MainWindow redraws
+// the time panel with it. Sun times don't change, so nothing is
+// recalculated.
+func (a *App) UpdateHomeTimezone(name string) {
+	if name != "" && !domain.ValidTimezone(name) {
+		name = ""
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.HomeTimezone = name
+	})
+	a.saveSettings()
+}
+
+// UpdateCopyTemplate applies the Copy Times template from the preferences
+// dialog.
+//
+// A template that doesn't parse (see export.CheckCopyTemplate) is replaced
+// by the built-in summary. Nothing is recalculated.
+func (a *App) UpdateCopyTemplate(text string) {
+	if strings.TrimSpace(text) == "" || export.CheckCopyTemplate(text) != nil {
+		text = ""
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.CopyTemplate = text
+	})
+	a.saveSettings()
+}
+
+// UpdateMinimizeToTray applies the "Minimize to the system tray" option
+// from the preferences dialog and hands it to the main window.
+func (a *App) UpdateMinimizeToTray(on bool) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.MinimizeToTray = on
+	})
+	a.saveSettings()
+	a.view.SetMinimizeToTray(on)
+}
+
+// UpdateAuroraForecast applies the "Aurora forecast" option from the
+// preferences dialog and rates the current night if it was turned on.
+func (a *App) UpdateAuroraForecast(on bool) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.AuroraForecast = on
+	})
+	a.saveSettings()
+	a.fetchAurora(a.state.Snapshot())
+}
+
+// PauseNotifications pauses or resumes the desktop notifications, phone
+// reminders and webhook calls, from the tray icon's menu. Automation hooks
+// keep running, and the settings aren't changed.
+func (a *App) PauseNotifications(paused bool) {
+	a.notificationsPaused = paused
+	a.rescheduleHooks()
+}
+
+// searchOptions returns the options of a search from the user's search
+// bias: its languages (or the system language), its countries and, if
+// enabled, the area the map shows.
+func (a *App) searchOptions() geocoding.SearchOptions {
+	bias := a.state.Settings().SearchBias
+	options := geocoding.SearchOptions{
+		Language:  cmp.Or(bias.Language, geocoding.SystemLanguage()),
+		Countries: bias.Countries,
+	}
+	if bounds, ok := a.view.MapBounds(); ok && bias.NearMapView {
+		options.Viewbox = &bounds
+	}
+	return options
+}
+
+// UpdateTileServer applies the map tile server from the preferences dialog.
+//
+// The server is saved for the next start; the MainWindow switches the map
+// itself. The dialog has already checked it; an invalid server falls back
+// to OpenStreetMap, as Settings.Validate would on the next start.
+func (a *App) UpdateTileServer(server domain.TileServer) {
+	if server.Validate() != nil {
+		server = domain.TileServer{}
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.TileServer = server
+	})
+	a.tiles.SetServer(server)
+	a.saveSettings()
+}
+
+// UpdateDailySummary applies the daily summary from the preferences dialog.
+//
+// The configuration is saved and the summary re-armed. The dialog has
+// already checked it; favorites that were removed in the meantime are
+// dropped, and a summary that can't be delivered is turned off, as
+// Settings.Validate would on the next start.
+func (a *App) UpdateDailySummary(summary domain.DailySummary) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.DailySummary = summary
+		s.Validate()
+	})
+	a.saveSettings()
+	a.rescheduleSummary()
+}
+
+// SendSummaryNow sends a daily summary once, with the given configuration.
+//
+// This backs the "Send Now" button in the preferences dialog, so the
+// configuration may not be saved yet. Delivery runs in a background
+// goroutine and the result is shown in the status bar when it finishes.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) SendSummaryNow(summary domain.DailySummary) {
+	settings := a.state.Settings()
+	settings.DailySummary = summary
+
+	crash.Go(func() {
+		result := automation.SendSummary(settings, time.Now())
+
+		a.onMainThread(func() {
+			a.view.ShowSummaryResult(result)
+		})
+	})
+}
+
+// UpdateWidgetFeed applies the widget feed from the preferences dialog.
+//
+// The configuration is saved and the feed rewritten right away. The dialog
+// has already checked it; a feed that can't be written is turned off, as
+// Settings.Validate would on the next start.
+func (a *App) UpdateWidgetFeed(feed domain.WidgetFeed) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.WidgetFeed = feed
+		s.Validate()
+	})
+	a.saveSettings()
+	a.rescheduleFeed()
+}
+
+// WriteFeedNow writes the widget feed once, with the given configuration.
+//
+// This backs the "Write Now" button in the preferences dialog, so the
+// configuration may not be saved yet. Writing runs in a background
+// goroutine and the result is shown in the status bar when it finishes.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) WriteFeedNow(feed domain.WidgetFeed) {
+	settings := a.state.Settings()
+	settings.WidgetFeed = feed
+
+	crash.Go(func() {
+		err := automation.WriteFeed(settings, time.Now())
+
+		a.onMainThread(func() {
+			a.view.ShowFeedResult(automation.FeedResult{Path: feed.Path, Err: err})
+		})
+	})
+}
+
+// UpdateNotifications applies the desktop notifications from the
+// preferences dialog.
+//
+// The configuration is saved and the scheduler re-armed, so notifications
+// follow the new events, lead times and places immediately. Favorites that
+// were removed in the meantime are dropped, as Settings.Validate would on
+// the next start.
+func (a *App) UpdateNotifications(notifications domain.DesktopNotifications) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.Notifications = notifications
+		s.Validate()
+	})
+	a.saveSettings()
+	a.rescheduleHooks()
+}
+
+// TestNotification shows a desktop notification once, as if the first
+// event (or sunset) at the current location were as far off as the first
+// lead time.
+//
+// This backs the "Show Test" button in the preferences dialog, so the
+// configuration may not be saved yet. Nothing goes online, so it works in
+// privacy mode too.
+func (a *App) TestNotification(notifications domain.DesktopNotifications) {
+	snap := a.state.Snapshot()
+	lead := 10
+	if len(notifications.LeadMinutes) > 0 {
+		lead = notifications.LeadMinutes[0]
+	}
+	event := domain.SunEvent{Kind: domain.EventSunset, Time: time.Now().Add(time.Duration(lead) * time.Minute)}
+	if len(notifications.Events) > 0 {
+		event.Kind = notifications.Events[0]
+	}
+	title, message := automation.NotificationText(snap.Settings, event, snap.Location, lead)
+	a.view.ShowHookResult(automation.Result{Event: event, Notification: true, Output: title, Message: message})
+}
+
+// UpdatePush applies the push notifications from the preferences dialog.
+//
+// The configuration is saved and the scheduler re-armed, so reminders
+// follow the new events and lead time immediately. The dialog has already
+// checked it; reminders that can't be pushed are turned off, as
+// Settings.Validate would on the next start.
+func (a *App) UpdatePush(push domain.PushNotifications) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.Push = push
+		s.Validate()
+	})
+	a.saveSettings()
+	a.rescheduleHooks()
+}
+
+// SendPushSchedule pushes the schedule of the next two weeks at the current
+// location once, with the given configuration.
+//
+// This backs the "Send 14-Day Schedule" button in the preferences dialog,
+// so the configuration may not be saved yet. Calculating and sending run
+// in a background goroutine and the result is shown in the status bar when
+// it finishes.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) SendPushSchedule(push domain.PushNotifications) {
+	snap := a.state.Snapshot()
+	snap.Settings.Push = push
+	if snap.Settings.PrivacyMode {
+		a.view.ShowPushResult(domain.ErrPrivacyMode)
+		return
+	}
+
+	crash.Go(func() {
+		err := automation.SendSchedule(snap.Settings, snap.Location, time.Now())
+
+		a.onMainThread(func() {
+			a.view.ShowPushResult(err)
+		})
+	})
+}
+
+// UpdateWebhook applies the webhook from the preferences dialog.
+//
+// The configuration is saved and the scheduler re-armed, so webhook calls
+// follow the new events, places and lead time immediately. A webhook
+// without a valid URL is turned off, as Settings.Validate would on the
+// next start.
+func (a *App) UpdateWebhook(webhook domain.Webhook) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.Webhook = webhook
+		s.Validate()
+	})
+	a.saveSettings()
+	a.rescheduleHooks()
+}
+
+// TestWebhook calls the webhook once, as if its first event (or sunset)
+// at the current location were coming up.
+//
+// This backs the "Send Test" button in the preferences dialog, so the
+// configuration may not be saved yet. The request runs in a background
+// goroutine and the result is shown in the status bar when it finishes.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) TestWebhook(webhook domain.Webhook) {
+	snap := a.state.Snapshot()
+	snap.Settings.Webhook = webhook
+	event := domain.SunEvent{Kind: domain.EventSunset,
+		Time: time.Now().Add(time.Duration(webhook.LeadMinutes) * time.Minute)}
+	if len(webhook.Events) > 0 {
+		event.Kind = webhook.Events[0]
+	}
+	if snap.Settings.PrivacyMode {
+		a.view.ShowHookResult(automation.Result{Event: event, Webhook: true, Err: domain.ErrPrivacyMode})
+		return
+	}
+
+	crash.Go(func() {
+		payload := automation.NewWebhookPayload(snap.Settings, event, snap.Location)
+		result := automation.Result{Event: event, Webhook: true, Output: payload.Value3,
+			Err: automation.CallWebhook(webhook, payload)}
+
+		a.onMainThread(func() {
+			a.view.ShowHookResult(result)
+		})
+	})
+}
+
+// UpdateCalDAV applies the calendar sync configuration from the
+// preferences dialog.
+//
+// The ETags of the events sent so far are kept while the calendar stays
+// the same; a different calendar starts afresh. The dialog has already
+// checked the configuration; one that fails domain.CalDAVSync.Check turns
+// sync off, as Settings.Validate would on the next start.
+func (a *App) UpdateCalDAV(sync domain.CalDAVSync) {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		sync.ETags = nil
+		if sync.CalendarURL == s.CalDAV.CalendarURL {
+			sync.ETags = s.CalDAV.ETags
+		}
+		s.CalDAV = sync
+		s.Validate()
+	})
+	a.saveSettings()
+}
+
+// SyncCalendar sends the golden and blue hours of the watch calendar (the
+// planned date range, or a week from the selected date) to the user's
+// calendar server, creating or updating one event each (see caldav.Sync).
+//
+// Calculating runs on the main thread; sending runs in a background
+// goroutine, and the outcome is shown in the status bar when it finishes.
+// The ETags the server returned are saved for the next sync.
+//
+// Thread Safety: Uses onMainThread() for UI and settings updates.
+func (a *App) SyncCalendar() {
+	snap := a.state.Snapshot()
+	if snap.Settings.PrivacyMode {
+		a.view.ShowCalendarSyncResult(caldav.Result{}, domain.ErrPrivacyMode)
+		return
+	}
+	days, err := a.watchCalendarDays(snap)
+	if err != nil {
+		a.view.ShowCalendarSyncResult(caldav.Result{}, err)
+		return
+	}
+	events := export.CalendarEvents(days, snap.Settings.TimeFormat24Hour, time.Now())
+
+	// The goroutine gets its own copy of the ETags, which the main thread
+	// replaces when results come in
+	sync := snap.Settings.CalDAV
+	sync.ETags = maps.Clone(sync.ETags)
+
+	crash.Go(func() {
+		result, err := caldav.Sync(context.Background(), sync, events)
+
+		a.onMainThread(func() {
+			a.saveCalendarETags(sync.CalendarURL, result.ETags)
+			a.view.ShowCalendarSyncResult(result, err)
+		})
+	})
+}
+
+// AddToCalendar sends one golden or blue hour of the selected date at the
+// current location to the user's calendar server, as an event of its own.
+//
+// The event is the same as SyncCalendar sends for the day (same UID), so a
+// later sync updates it instead of adding it twice, and adding it again
+// after it was changed in the calendar is reported as a conflict.
+//
+// Parameters:
+//   - period: export.PeriodBlueAM, PeriodGoldenAM, PeriodGoldenPM or
+//     PeriodBluePM
+//
+// Thread Safety: Uses onMainThread() for UI and settings updates.
+func (a *App) AddToCalendar(period string) {
+	snap := a.state.Snapshot()
+	if snap.Settings.PrivacyMode {
+		a.view.ShowCalendarSyncResult(caldav.Result{}, domain.ErrPrivacyMode)
+		return
+	}
+	event, err := a.periodEvent(snap, period)
+	if err != nil {
+		a.view.ShowCalendarSyncResult(caldav.Result{}, err)
+		return
+	}
+	sync := snap.Settings.CalDAV
+	sync.ETags = maps.Clone(sync.ETags)
+
+	crash.Go(func() {
+		result, err := caldav.Sync(context.Background(), sync, []export.CalendarEvent{event})
+
+		a.onMainThread(func() {
+			a.saveCalendarETags(sync.CalendarURL, result.ETags)
+			a.view.ShowCalendarSyncResult(result, err)
+		})
+	})
+}
+
+// GoogleCalendarURL returns the address of Google Calendar's event form,
+// prefilled with one golden or blue hour of the selected date at the
+// current location (see export.GoogleCalendarURL). The UI opens it in the
+// browser.
+//
+// Parameters:
+//   - period: As for AddToCalendar
+//
+// Returns domain.ErrPrivacyMode in privacy mode, since opening the form
+// hands the place to Google, or an error if the period doesn't occur on
+// the date.
+func (a *App) GoogleCalendarURL(period string) (string, error) {
+	snap := a.state.Snapshot()
+	if snap.Settings.PrivacyMode {
+		return "", domain.ErrPrivacyMode
+	}
+	day, err := a.solarCalc.Calculate(snap.Location, snap.Date)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	link, ok := export.GoogleCalendarURL(day, period, snap.Settings.TimeFormat24Hour)
+	if !ok {
+		return "", errors.New("this period doesn't occur on the selected date")
+	}
+	return link, nil
+}
+
+// periodEvent builds the calendar event of one period of the selected date
+// at the current location.
+func (a *App) periodEvent(snap state.Snapshot, period string) (export.CalendarEvent, error) {
+	day, err := a.solarCalc.Calculate(snap.Location, snap.Date)
+	if err != nil {
+		return export.CalendarEvent{}, fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	event, ok := export.PeriodEvent(day, period, snap.Settings.TimeFormat24Hour, time.Now())
+	if !ok {
+		return export.CalendarEvent{}, errors.New("this period doesn't occur on the selected date")
+	}
+	return event, nil
+}
+
+// saveCalendarETags merges the ETags a calendar server returned into the
+// settings, for the next sync, and forgets those of past days.
+//
+// Parameters:
+//   - calendarURL: The calendar the events were sent to; results for a
+//     calendar the user has since replaced don't belong to the new one
+//   - etags: The ETags by UID (see caldav.Result); nothing is saved if
+//     empty
+func (a *App) saveCalendarETags(calendarURL string, etags map[string]string) {
+	if len(etags) == 0 {
+		return
+	}
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		if s.CalDAV.CalendarURL != calendarURL {
+			return
+		}
+		merged := maps.Clone(s.CalDAV.ETags)
+		if merged == nil {
+			merged = make(map[string]string)
+		}
+		maps.Copy(merged, etags)
+		s.CalDAV.ETags = merged
+		s.CalDAV.PruneETags(time.Now().AddDate(0, 0, -1))
+	})
+	a.saveSettings()
+}
+
+// rescheduleSummary re-arms the daily summary for the current settings.
+//
+// The summary scheduler is nil while the App is being constructed (see
+// rescheduleHooks).
+func (a *App) rescheduleSummary() {
+	if a.summaryScheduler == nil {
+		return
+	}
+	a.summaryScheduler.Schedule(a.state.Settings())
+}
+
+// rescheduleFeed re-arms the widget feed for the current settings, which
+// rewrites it.
+//
+// The feed writer is nil while the App is being constructed (see
+// rescheduleHooks).
+func (a *App) rescheduleFeed() {
+	if a.feedWriter == nil {
+		return
+	}
+	a.feedWriter.Schedule(a.state.Settings())
+}
+
+// TestHook runs a hook once immediately, as if its event happened now.
+//
+// This backs the "Test" button in the preferences dialog. The command runs
+// in a background goroutine (it may take a while) and the result is shown
+// in the status bar when it finishes.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) TestHook(hook domain.Hook) {
+	event := domain.SunEvent{Kind: hook.Event, Time: time.Now()}
+	loc := a.state.Location()
+
+	crash.Go(func() {
+		result := automation.Run(hook, event, loc)
+
+		a.onMainThread(func() {
+			a.view.ShowHookResult(result)
+		})
+	})
+}
+
+// reportUnconfirmedHooks warns about enabled hooks that won't run because
+// their command was never approved (e.g., settings edited by hand or saved
+// by a version without confirmation fingerprints).
+func (a *App) reportUnconfirmedHooks() {
+	settings := a.state.Settings()
+	if !settings.AutomationEnabled {
+		return
+	}
+	count := 0
+	for _, h := range settings.Hooks {
+		if h.Enabled && !h.IsConfirmed() {
+			count++
+		}
+	}
+	if count > 0 {
+		a.view.ShowError(fmt.Sprintf(
+			"%d automation hook(s) not confirmed: review them in Edit → Preferences", count))
+	}
+}
+
+// rescheduleHooks re-arms the automation scheduler for the current state.
+//
+// Hooks and push reminders are scheduled against the real current date at
+// the selected location; push reminders and webhook calls are left out in
+// privacy mode and while notifications are paused, desktop notifications
+// only while they are paused (they don't go online).
+// When both are disabled this simply cancels all timers.
+// Does nothing while the App is being constructed, before the scheduler
+// exists.
+func (a *App) rescheduleHooks() {
+	if a.scheduler == nil {
+		return
+	}
+	snap := a.state.Snapshot()
+	if snap.Settings.PrivacyMode || a.notificationsPaused {
+		snap.Settings.Push.Enabled = false
+		snap.Settings.Webhook.Enabled = false
+	}
+	if a.notificationsPaused {
+		snap.Settings.Notifications.Enabled = false
+	}
+	if err := a.scheduler.Schedule(snap.Location, snap.Settings); err != nil {
+		a.view.ShowError(fmt.Sprintf("Automation hooks not scheduled: %v", err))
+	}
+}
+
+// =============================================================================
+// Day/Night Terminator
+// =============================================================================
+
+// terminatorInterval is how often the map's day/night overlay is refreshed.
+//
+// The terminator moves 15° of longitude per hour, so in 5 minutes it moves
+// about 1.25°, less than the overlay's 2° sampling step.
+const terminatorInterval = 5 * time.Minute
+
+// runTerminatorUpdates keeps the map's day/night overlay in sync with the clock.
+//
+// Runs for the lifetime of the application in its own goroutine. The bands
+// are computed in the background with the stateless solar.Terminator (the
+// App's calculator is not needed), then drawn on the main thread.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) runTerminatorUpdates() {
+	ticker := time.NewTicker(terminatorInterval)
+	defer ticker.Stop()
+
+	for {
+		bands, err := solar.Terminator(time.Now())
+		a.onMainThread(func() {
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Day/night overlay failed: %v", err))
+				return
+			}
+			a.view.UpdateTerminator(bands)
+		})
+		<-ticker.C
+	}
+}
+
+// =============================================================================
+// Import / Export
+// =============================================================================
+
+// watchCalendarDays is the number of days included in a watch calendar export.
+//
+// A week covers a typical trip while keeping the watch's agenda short.
+const watchCalendarDays = 7
+
+// ImportMapData reads points and tracks from a file for display on the map.
+//
+// See geodata.ParseFile for the supported formats. Files are small enough
+// to parse on the main thread.
+//
+// Parameters:
+//   - path: The GPX, KML, KMZ or GeoJSON file to read
+//
+// Returns the file's points and tracks, or an error if it can't be read or
+// contains nothing to show.
+func (a *App) ImportMapData(path string) (geodata.Data, error) {
+	return geodata.ParseFile(path)
+}
+
+// ExportWatchCalendar writes a smartwatch-friendly ICS file to path.
+//
+// The calendar covers the planned date range, or watchCalendarDays days
+// from the currently selected date if a single day is planned, at the
+// current location, using the current golden/blue hour settings. See
+// export.WatchCalendar for the format.
+//
+// Parameters:
+//   - path: Destination file (overwritten if it exists)
+//
+// Returns an error if a day can't be calculated or the file can't be written.
+func (a *App) ExportWatchCalendar(path string) error {
+	snap := a.state.Snapshot()
+	days, err := a.watchCalendarDays(snap)
+	if err != nil {
+		return err
+	}
+
+	ics := export.WatchCalendar(days, snap.Settings.TimeFormat24Hour, time.Now())
+	if err := os.WriteFile(path, []byte(ics), 0o644); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// watchCalendarDays calculates the days of the watch calendar and calendar
+// sync: the planned date range, or watchCalendarDays days from the
+// selected date, at the current location.
+func (a *App) watchCalendarDays(snap state.Snapshot) ([]domain.SunTimes, error) {
+	dates, ok := snap.DateRange()
+	if !ok {
+		dates = domain.NewDateRange(snap.Date, snap.Date.AddDate(0, 0, watchCalendarDays-1))
+	}
+	days, err := a.solarCalc.CalculateMany(snap.Location, dates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	return days, nil
+}
+
+// ExportDateRange writes the sun times of each day of the planned date
+// range at the current location to path.
+//
+// The format follows the file extension: JSON for ".json", CSV otherwise
+// (see export.DaysJSON and export.DaysCSV).
+//
+// Parameters:
+//   - path: Destination file (overwritten if it exists)
+//   - opts: Columns and time format
+//
+// Returns an error if no date range is planned, a day can't be calculated
+// or the file can't be written.
+func (a *App) ExportDateRange(path string, opts export.DaysOptions) error {
+	days, err := a.dateRangeDays()
+	if err != nil {
+		return err
+	}
+
+	write := export.DaysCSV
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		write = export.DaysJSON
+	}
+	content, err := write(days, opts)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write days: %w", err)
+	}
+	return nil
+}
+
+// DateRangeText returns the sun times of each day of the planned date range
+// as tab-separated text, which pastes into a spreadsheet as a table.
+//
+// Parameters:
+//   - opts: Columns and time format (the separator is always a tab)
+//
+// Returns an error if no date range is planned or a day can't be
+// calculated.
+func (a *App) DateRangeText(opts export.DaysOptions) (string, error) {
+	days, err := a.dateRangeDays()
+	if err != nil {
+		return "", err
+	}
+	opts.Separator = '\t'
+	content, err := export.DaysCSV(days, opts)
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// dateRangeDays calculates each day of the planned date range at the
+// current location, for the day table exports.
+func (a *App) dateRangeDays() ([]domain.SunTimes, error) {
+	snap := a.state.Snapshot()
+	dates, ok := snap.DateRange()
+	if !ok {
+		return nil, errors.New("no date range is selected; check \"Until\" in the date panel first")
+	}
+	days, err := a.solarCalc.CalculateMany(snap.Location, dates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	return days, nil
+}
+
+// CopyTimesText fills in the user's copy template (or the built-in
+// summary) with the sun times of the selected date at the current location,
+// for Edit → Copy Times.
+//
+// Returns an error if the day can't be calculated or the template is
+// malformed.
+func (a *App) CopyTimesText() (string, error) {
+	snap := a.state.Snapshot()
+	day, err := a.solarCalc.Calculate(snap.Location, snap.Date)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	data := export.NewCopyData(snap.Location, day, snap.Settings.TimeFormat24Hour,
+		snap.Settings.CoordinateFormat, snap.Settings.PlaceNameStyle)
+	return export.CopyText(snap.Settings.CopyTemplate, data)
+}
+
+// ShootPlanHTML builds the text of a shoot plan for the current location:
+// the days of the planned date range, or the selected date alone (see
+// export.ShootPlanHTML). The UI prints it as a PDF with a snapshot of the
+// map.
+//
+// Returns an error if a day can't be calculated.
+func (a *App) ShootPlanHTML() (string, error) {
+	snap := a.state.Snapshot()
+	dates, ok := snap.DateRange()
+	if !ok {
+		dates = domain.NewDateRange(snap.Date, snap.Date)
+	}
+	days, err := a.solarCalc.CalculateMany(snap.Location, dates)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	return export.ShootPlanHTML(snap.Location, days, snap.Settings.TimeFormat24Hour,
+		snap.Settings.CoordinateFormat, snap.Settings.PlaceNameStyle, time.Now()), nil
+}
+
+// ExportWaypoints writes the shoot's places as GPX waypoints to path, for
+// GPS units and phone apps: the current location followed by the
+// favorites, each with its sun times on the days of the planned date range
+// or the selected date (see export.WaypointsGPX).
+//
+// Each favorite's times use its own golden hour angle, timezone and
+// elevation (see domain.Favorite.Overrides). A favorite at the current
+// location isn't repeated. Like the shoot plan, at most
+// export.ShootPlanMaxDays days are listed.
+//
+// Parameters:
+//   - path: Destination file (overwritten if it exists)
+//
+// Returns an error if a day can't be calculated or the file can't be
+// written.
+func (a *App) ExportWaypoints(path string) error {
+	snap := a.state.Snapshot()
+	dates, ok := snap.DateRange()
+	if !ok {
+		dates = domain.NewDateRange(snap.Date, snap.Date)
+	}
+	if dates.Days() > export.ShootPlanMaxDays {
+		dates = domain.NewDateRange(dates.Start, dates.Start.AddDate(0, 0, export.ShootPlanMaxDays-1))
+	}
+
+	places := []domain.Location{snap.Location}
+	here, isFavorite := domain.FavoriteAt(snap.Settings.Favorites, snap.Location)
+	for _, f := range snap.Settings.Favorites {
+		if !isFavorite || f.ID != here.ID {
+			places = append(places, f.CurrentLocation())
+		}
+	}
+	waypoints := make([]export.ShootWaypoint, len(places))
+	for i, loc := range places {
+		days, err := solar.New(snap.Settings.ForLocation(loc)).CalculateMany(loc, dates)
+		if err != nil {
+			return fmt.Errorf("failed to calculate sun times at %s: %w", loc.DisplayName(snap.Settings.PlaceNameStyle), err)
+		}
+		waypoints[i] = export.ShootWaypoint{Location: loc, Days: days}
+	}
+
+	content, err := export.WaypointsGPX(waypoints, snap.Settings.TimeFormat24Hour,
+		snap.Settings.PlaceNameStyle, time.Now())
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write waypoints: %w", err)
+	}
+	return nil
+}
+
+// AnalyzePhoto reads a photo's GPS position and capture time from its EXIF
+// data and works out the light it was taken in: the sun's elevation and
+// azimuth, and the golden or blue hour by the current settings (see
+// photo.Analyze). A photo taken at a favorite uses the favorite's name and
+// overrides.
+//
+// Parameters:
+//   - path: The photo file
+//
+// Returns an error if the photo can't be read or has no GPS position or
+// capture time.
+func (a *App) AnalyzePhoto(path string) (domain.PhotoLight, error) {
+	return photo.Analyze(path, a.state.Settings())
+}
+
+// ExportFavorites writes the favorites ("My Places") to path.
+//
+// The format follows the file extension: CSV for ".csv", GeoJSON otherwise
+// (see export.PlacesCSV and export.PlacesGeoJSON).
+//
+// Parameters:
+//   - path: Destination file (overwritten if it exists)
+//
+// Returns an error if there are no favorites or the file can't be written.
+func (a *App) ExportFavorites(path string) error {
+	favorites := a.state.Settings().Favorites
+	if len(favorites) == 0 {
+		return errors.New("there are no favorites to export")
+	}
+
+	write := export.PlacesGeoJSON
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		write = export.PlacesCSV
+	}
+	content, err := write(favorites)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		return fmt.Errorf("failed to write places: %w", err)
+	}
+	return nil
+}
+
+// ImportFavorites adds the points of a file to the favorites ("My Places").
+//
+// Any format of geodata.ParseFile is accepted, including the GeoJSON and
+// CSV files of ExportFavorites; tracks are ignored. Timezones are looked up
+// from the coordinates rather than trusted from the file, and places that
+// are favorites already are skipped (see domain.ImportFavorites). The
+// favorites panel, map layer and daily summary are updated.
+//
+// Parameters:
+//   - path: The file to read
+//
+// Returns:
+//   - added: The number of new favorites
+//   - found: The number of points in the file
+//   - err: Non-nil if the file can't be read or contains no points
+func (a *App) ImportFavorites(path string) (added, found int, err error) {
+	data, err := geodata.ParseFile(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(data.Points) == 0 {
+		return 0, 0, errors.New("no points found in file")
+	}
+
+	places := make([]domain.Location, len(data.Points))
+	for i, p := range data.Points {
+		places[i] = p.Location
+		places[i].Timezone = a.timezones.FromCoordinates(p.Location.Latitude, p.Location.Longitude)
+	}
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		s.Favorites, added = domain.ImportFavorites(s.Favorites, places)
+		for _, f := range s.Favorites[len(s.Favorites)-added:] {
+			s.RecentLocations = domain.RemoveRecentLocation(s.RecentLocations, f.Location)
+		}
+	})
+	if added > 0 {
+		a.saveSettings()
+		a.updateFavorites(settings)
+		a.view.UpdateRecentLocations(settings.RecentLocations)
+		a.rescheduleSummary()
+	}
+	return added, len(data.Points), nil
+}
+
+// =============================================================================
+// Config Codes
+// =============================================================================
+
+// ExportConfigCode returns a shareable config code for the current settings.
+//
+// The code contains elevation presets and display options (see
+// storage.EncodeConfigCode) and is intended to be pasted by another user
+// into ImportConfigCode to replicate this setup.
+func (a *App) ExportConfigCode() string {
+	return storage.EncodeConfigCode(a.state.Settings())
+}
+
+// ImportConfigCode applies a config code pasted by the user.
+//
+// The code is decoded on top of the current settings, so personal values
+// such as the last location are kept. On success the settings panel is
+// refreshed to show the imported values and the new settings are applied,
+// persisted, and used for recalculation.
+//
+// Returns an error if the code is malformed; settings are left unchanged.
+func (a *App) ImportConfigCode(code string) error {
+	settings, err := storage.DecodeConfigCode(code, a.state.Settings())
+	if err != nil {
+		return err
+	}
+
+	// Refresh the settings controls so they reflect the imported values
+	// (the controls don't report them back), then apply them
+	a.view.ApplySettings(settings)
+	a.UpdateSettings(settings)
+	return nil
+}
+
+// =============================================================================
+// Configuration Backup
+// =============================================================================
+
+// ExportConfiguration writes the settings of every profile, with their
+// favorites, presets and automation, to one file (see
+// storage.ConfigBundle), for a backup or for moving to another machine.
+//
+// The running profile's settings are saved on every change, so the file
+// has them as shown.
+//
+// Returns the number of profiles written, or an error if a settings file
+// is damaged or the file can't be written.
+func (a *App) ExportConfiguration(path string) (int, error) {
+	return storage.ExportConfigBundle(path, time.Now())
+}
+
+// ReadConfiguration reads and validates a file written by
+// ExportConfiguration, without applying it, so the user can confirm what it
+// replaces (see ImportConfiguration).
+//
+// Returns an error describing the first problem if the file isn't a valid
+// configuration.
+func (a *App) ReadConfiguration(path string) (storage.ConfigBundle, error) {
+	return storage.ReadConfigBundle(path)
+}
+
+// ImportConfiguration replaces the settings of the profiles in a bundle read
+// by ReadConfiguration. Profiles on this machine that aren't in the bundle
+// are left alone.
+//
+// The running profile's settings are applied at once, like restored ones
+// (see RestoreSettings; the current location stays on screen); other
+// profiles are written to their settings files, each keeping its previous
+// file as the backup, and are used the next time they are started.
+// Automation hooks come in unconfirmed, so none runs before the user has
+// reviewed its command.
+//
+// Returns an error if a profile's settings can't be written. The profiles
+// before it are imported already.
+func (a *App) ImportConfiguration(bundle storage.ConfigBundle) error {
+	for _, p := range bundle.Profiles {
+		if p.Name == a.profile {
+			// The imported settings end the recovery of a damaged settings
+			// file, as restoring the defaults would
+			if a.prefs.Corrupt() {
+				a.prefs.Recover(false)
+			}
+			a.replaceSettings(p.Settings)
+			// Imported commands need approving again (see
+			// storage.ReadConfigBundle)
+			a.reportUnconfirmedHooks()
+			continue
+		}
+		if err := storage.SaveProfile(p.Name, p.Settings); err != nil {
+			return fmt.Errorf("failed to import %s: %w", profileName(p.Name), err)
+		}
+	}
+	return nil
+}
+
+// =============================================================================
+// Profiles and Updates
+// =============================================================================
+
+// OpenProfile starts another window of the app with a settings profile
+// (Tools → Open Profile), e.g., to compare the work and the travel setup
+// side by side.
+//
+// The window is a separate process, started like "gogoldenhour --profile
+// NAME"; it doesn't share any state with this one.
+//
+// Parameters:
+//   - profile: The profile (storage.DefaultProfile for the default one); a
+//     new name creates the profile
+//
+// Returns an error if the profile is the one of this window, the name is
+// invalid, or the process can't be started.
+func (a *App) OpenProfile(profile string) error {
+	if profile == a.profile {
+		return fmt.Errorf("%s is open in this window", profileName(profile))
+	}
+	if err := storage.ValidateProfileName(profile); err != nil {
+		return err
+	}
+	program, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to find the program: %w", err)
+	}
+	// The default profile is asked for, as a start without a profile
+	// runs the last session's (this one's)
+	args := []string{"--default-profile"}
+	if profile != storage.DefaultProfile {
+		args = []string{"--profile=" + profile}
+	}
+	cmd := exec.Command(program, args...)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", profileName(profile), err)
+	}
+	// Collect the exit status, so the ended process doesn't linger
+	go cmd.Wait()
+	return nil
+}
+
+// profileName names a profile in messages: "the default profile" or
+// "the work profile".
+func profileName(profile string) string {
+	return fmt.Sprintf("the %s profile", cmp.Or(profile, "default"))
+}
+
+// CheckForUpdates looks up the latest release and shows whether it is
+// newer than the running version (Help → Check for Updates).
+//
+// The check is only made when the user asks for it, and not in privacy
+// mode. The lookup runs in a background goroutine; the result is shown
+// on the main thread.
+func (a *App) CheckForUpdates() {
+	if a.state.Settings().PrivacyMode {
+		a.view.ShowError(fmt.Sprintf("Update check unavailable: %v", domain.ErrPrivacyMode))
+		return
+	}
+	crash.Go(func() {
+		latest, err := a.updates.Latest()
+		a.onMainThread(func() {
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Couldn't check for updates: %v", err))
+				return
+			}
+			a.view.ShowUpdateCheck(latest, changelog.CompareVersions(latest.Version, a.version) > 0)
+		})
+	})
+}
+
+// =============================================================================
+// Share Links
+// =============================================================================
+
+// ShareLink returns a gogoldenhour:// link to the current location and
+// date, for someone else to open the app on the same spot and day.
+func (a *App) ShareLink() string {
+	snap := a.state.Snapshot()
+	return domain.Link{
+		Latitude:  snap.Location.Latitude,
+		Longitude: snap.Location.Longitude,
+		Date:      snap.Date,
+		Name:      snap.Location.Name,
+	}.URL()
+}
+
+// OpenLink shows the place and date of a share link.
+//
+// The date (if the link has one) is selected first. A link with a place
+// name becomes the location right away, with the timezone looked up from
+// its coordinates; a link without one is handled like a click on the map,
+// so the place is named by reverse geocoding.
+func (a *App) OpenLink(link domain.Link) {
+	if !link.Date.IsZero() {
+		a.UpdateDate(link.Date)
+	}
+	if link.Name == "" {
+		a.OnMapClick(link.Latitude, link.Longitude)
+		return
+	}
+	loc := link.Location()
+	loc.Timezone = a.timezones.FromCoordinates(loc.Latitude, loc.Longitude)
+	a.UpdateLocation(loc)
+}
+
+// OpenLinkText parses and opens a pasted share link (see OpenLink).
+//
+// Returns an error if the text isn't a valid link; nothing changes then.
+func (a *App) OpenLinkText(text string) error {
+	link, err := domain.ParseLink(text)
+	if err != nil {
+		return err
+	}
+	a.OpenLink(link)
+	return nil
+}
+
+// RegisterLinks makes this copy of the app the handler of gogoldenhour://
+// links for the current user (see linkscheme.Register).
+//
+// Returns an error if links can't be registered on this platform or the
+// registration can't be written.
+func (a *App) RegisterLinks() error {
+	return linkscheme.Register()
+}
+
+// =============================================================================
+// Sun Alignments
+// =============================================================================
+
+// alignmentSearchDays is how far ahead FindSunAlignments looks.
+// One year covers both seasonal passes of the sunrise/sunset azimuth.
+const alignmentSearchDays = 365
+
+// FindSunAlignments searches for dates when the sun lines up with a landmark.
+//
+// Starting from the currently selected date, the next year is searched for
+// moments when the sun, seen from camera, rises or sets along the line to
+// subject (see solar.Calculator.FindAlignments). The results are shown in
+// the main window once the search completes.
+//
+// The search evaluates hundreds of sun positions, so it runs in a background
+// goroutine. Because the shared calculator is not thread-safe, the goroutine
+// uses its own calculator created from a snapshot of the current settings.
+// The snapshot is taken before the goroutine starts, so the search matches
+// the state at the time of the request.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) FindSunAlignments(camera, subject domain.Location) {
+	// Results are shown in the camera's local time
+	camera.Timezone = a.timezones.FromCoordinates(camera.Latitude, camera.Longitude)
+
+	snap := a.state.Snapshot()
+	calc := solar.New(snap.Settings)
+	start := snap.Date
+
+	crash.Go(func() {
+		alignments, err := calc.FindAlignments(camera, subject, start, alignmentSearchDays)
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Alignment search failed: %v", err))
+				return
+			}
+			a.view.ShowSunAlignments(camera, subject, alignments)
+		})
+	})
+}
+
+// profileSamples is the number of terrain samples in an elevation profile.
+//
+// 100 is the most the elevation API accepts in one request; over a typical
+// 5-10 km measurement that is one sample every 50-100 m, about the
+// resolution of the underlying elevation data.
+const profileSamples = 100
+
+// minProfileLength is the shortest line an elevation profile is fetched
+// for, in meters. Shorter lines (e.g., a double click) would sample the same
+// terrain cell profileSamples times and have no horizon to show.
+const minProfileLength = 10
+
+// FetchElevationProfile samples terrain heights along a measured line.
+//
+// The profile is fetched in a background goroutine and shown on the map
+// when it arrives, so the user can see whether terrain between the camera
+// (from) and the horizon hides the low sun in that direction. Failures are
+// reported in the status bar; the measurement itself is unaffected.
+//
+// Only the reply for the latest measurement is used: each request is
+// numbered, and replies for earlier ones are dropped when they arrive.
+// Lines shorter than minProfileLength are not sampled (but still cancel an
+// earlier request), and nothing is fetched in privacy mode.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) FetchElevationProfile(from, to domain.Location) {
+	a.profileRequest++
+	request := a.profileRequest
+	if from.DistanceTo(to) < minProfileLength {
+		return
+	}
+	if a.state.Settings().PrivacyMode {
+		a.view.ShowError(fmt.Sprintf("Elevation profile unavailable: %v", domain.ErrPrivacyMode))
+		return
+	}
+
+	crash.Go(func() {
+		profile, err := a.elevation.Profile(from, to, profileSamples)
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if request != a.profileRequest {
+				return // superseded by a newer measurement
+			}
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Elevation profile unavailable: %v", err))
+				return
+			}
+			a.view.ShowElevationProfile(from, to, profile)
+		})
+	})
+}
+
+// cloudMargin is how long before and after the evening golden hour cloud
+// frames are played (when there are frames that close to it).
+const cloudMargin = 90 * time.Minute
+
+// FetchCloudFrames loads the frames of the map's cloud layer.
+//
+// The frames are fetched in a background goroutine. Weather map services
+// only keep about two hours of frames, so the animation covers the time
+// from cloudMargin before to cloudMargin after today's evening golden hour
+// at the current location when that is available (around sunset), and
+// all frames otherwise. Failures are reported in the status bar. The map
+// doesn't offer the layer in privacy mode.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) FetchCloudFrames() {
+	if a.state.Settings().PrivacyMode {
+		a.view.ShowError(fmt.Sprintf("Cloud layer unavailable: %v", domain.ErrPrivacyMode))
+		return
+	}
+	loc := a.state.Location()
+
+	crash.Go(func() {
+		frames, err := a.weather.Frames()
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Cloud layer unavailable: %v", err))
+				return
+			}
+			var golden domain.TimeRange
+			if sunTimes, err := a.solarCalc.Calculate(loc, time.Now()); err == nil {
+				golden = sunTimes.GoldenEvening
+			}
+			around := domain.CloudFramesAround(frames, golden, cloudMargin)
+			if len(around) > 0 {
+				frames = around
+			}
+			a.view.ShowCloudFrames(frames, golden, len(around) > 0)
+		})
+	})
+}
+
+// HorizonEvents returns the selected date's sunrise, sunset, moonrise and
+// moonset as seen from a camera position.
+//
+// Used by the camera view to check which events fall inside the field of
+// view. The calculation takes a few milliseconds, so unlike the alignment
+// search it runs directly on the calling (main) thread. Times are in the
+// camera position's timezone.
+//
+// Parameters:
+//   - camera: Camera position (only coordinates are used)
+//
+// Returns the events in chronological order, or an error if the positions
+// can't be calculated.
+func (a *App) HorizonEvents(camera domain.Location) ([]domain.HorizonEvent, error) {
+	camera.Timezone = a.timezones.FromCoordinates(camera.Latitude, camera.Longitude)
+	return solar.HorizonEvents(camera, a.state.Date())
+}
+
+// MonthSunTimes returns the sun times of each day of a month at the current
+// location, for the month calendar.
+//
+// A month takes a few milliseconds to calculate, so like HorizonEvents this
+// runs directly on the calling (main) thread.
+//
+// Parameters:
+//   - month: Any day of the month (only year and month are used)
+//
+// Returns the days in order; if some can't be calculated they are listed
+// without times and an error is returned as well.
+func (a *App) MonthSunTimes(month time.Time) ([]domain.SunTimes, error) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	days, err := a.solarCalc.CalculateRange(a.state.Location(), domain.NewDateRange(first, first.AddDate(0, 1, -1)))
+	if err != nil {
+		return days, fmt.Errorf("failed to calculate sun times: %w", err)
+	}
+	return days, nil
+}
+
+// MonthMeteorNights rates the peak nights of the meteor showers in a month
+// at the current location, for the month calendar (see
+// solar.MeteorNights).
+//
+// Like MonthSunTimes, this runs directly on the calling (main) thread.
+//
+// Parameters:
+//   - month: Any day of the month (only year and month are used)
+//
+// Returns the peak nights in date order, usually one or none.
+func (a *App) MonthMeteorNights(month time.Time) ([]domain.MeteorNight, error) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.Local)
+	nights, err := solar.MeteorNights(a.state.Location(), domain.NewDateRange(first, first.AddDate(0, 1, -1)))
+	if err != nil {
+		return nights, fmt.Errorf("failed to rate meteor showers: %w", err)
+	}
+	return nights, nil
+}
+
+// weekOutlookDays is how many days the week outlook rates, today included.
+const weekOutlookDays = 7
+
+// FetchWeekOutlook rates the golden and blue hours of the coming week at
+// the current location by the weather forecast, for the week outlook.
+//
+// The days are calculated on the worker (see calcWorker), then the
+// forecast is fetched (see fetchWeekForecast); the view gets both
+// together, with the error if there is no forecast (none is fetched in
+// privacy mode), so the times are shown either way. Each period's chance of usable light is left to the
+// view (see domain.PeriodWeather.ShootProbability).
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) FetchWeekOutlook() {
+	snap := a.state.Snapshot()
+	tz, err := time.LoadLocation(snap.Location.Timezone)
+	if err != nil {
+		// Like the calculator, fall back to the system timezone
+		tz = time.Local
+	}
+	today := time.Now().In(tz)
+	dates := domain.NewDateRange(today, today.AddDate(0, 0, weekOutlookDays-1))
+	settings := snap.Settings.ForLocation(snap.Location)
+	a.worker.submit(calcWeekOutlook, func(ctx context.Context) func() {
+		days, err := calculateRange(ctx, settings, snap.Location, dates)
+		return func() {
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Some days couldn't be calculated: %v", err))
+			}
+			a.fetchWeekForecast(snap, days)
+		}
+	})
+}
+
+// fetchWeekForecast fetches the forecast for the week outlook's days in a
+// background goroutine (not on the worker, which would wait for the
+// network) and shows both.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) fetchWeekForecast(snap state.Snapshot, days []domain.SunTimes) {
+	if snap.Settings.PrivacyMode {
+		a.view.ShowWeekOutlook(days, domain.WeatherForecast{}, domain.ErrPrivacyMode)
+		return
+	}
+
+	crash.Go(func() {
+		forecast, err := a.forecast.Outlook(context.Background(), snap.Location, weekOutlookDays)
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			a.view.ShowWeekOutlook(days, forecast, err)
+		})
+	})
+}
+
+// =============================================================================
+// Location Search
+// =============================================================================
+
+// searchResultLimit is the most search results offered to choose from.
+// Nominatim orders results by relevance, so more than a screenful of
+// places rarely helps (10 is also Nominatim's own maximum for a search).
+const searchResultLimit = 10
+
+// SearchLocation performs a geocoding search and lets the user pick a result.
+//
+// This is called when the user types a location query and presses Enter or
+// clicks the Search button. The search runs asynchronously to keep the UI
+// responsive.
+//
+// Coordinates (decimal, degrees/minutes/seconds, MGRS or plus codes, see
+// the coordinates package) are decoded locally and selected right away,
+// without a request. Otherwise:
+//
+// Search flow:
+//  1. Query the Nominatim geocoding service (background) with the user's
+//     search bias (see searchOptions) and add matches from the offline
+//     city database it didn't find, unless the search is restricted to
+//     some countries; if Nominatim can't be reached (or privacy mode
+//     keeps it from being asked), use the offline matches alone
+//  2. Wait for main thread
+//  3. If there is a single result, update to it; if there are several,
+//     show them in the location panel's dropdown, which calls
+//     UpdateLocation for the one the user selects
+//  4. If failed or no results, show error message (a failed search can be
+//     retried from it)
+//
+// While a search runs, further searches are ignored; CancelSearch stops
+// it.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) SearchLocation(query string) {
+	if a.cancelSearch != nil {
+		return // e.g., Enter pressed twice
+	}
+
+	// Typed coordinates don't need the geocoding service; short plus codes
+	// are completed near the current location
+	lat, lon, err := coordinates.Parse(query, a.state.Location())
+	switch {
+	case err == nil:
+		a.UpdateLocation(domain.Location{
+			Latitude:  lat,
+			Longitude: lon,
+			Name:      domain.FormatCoordinatesIn(lat, lon, a.state.Settings().CoordinateFormat),
+			Timezone:  a.timezones.FromCoordinates(lat, lon),
+		})
+		return
+	case !errors.Is(err, coordinates.ErrNotCoordinates):
+		a.view.ShowError(fmt.Sprintf("Invalid coordinates: %v", err))
+		return
+	}
+
+	// Run geocoding in background
+	options := a.searchOptions()
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelSearch = cancel
+	a.view.SetBusy(frontend.TaskSearch, true)
+	crash.Go(func() {
+		results, err := a.geocoding.Search(ctx, query, searchResultLimit, options)
+		offline := geocoding.SearchOffline(query, searchResultLimit)
+		switch {
+		case err != nil && len(offline) > 0:
+			results, err = offline, nil
+		case errors.Is(err, domain.ErrPrivacyMode):
+			// Nothing cached or offline: "No locations found"
+			results, err = nil, nil
+		case err == nil && len(options.Countries) == 0:
+			results = geocoding.MergeResults(results, offline, searchResultLimit)
+		}
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if ctx.Err() != nil {
+				return // stopped by CancelSearch
+			}
+			cancel()
+			a.finishSearch()
+			if err != nil {
+				a.view.ShowRetryableError(fmt.Sprintf("Search failed: %v", err), func() {
+					a.SearchLocation(query)
+				})
+				return
+			}
+			switch len(results) {
+			case 0:
+				a.view.ShowError("No locations found")
+			case 1:
+				// Nothing to choose from
+				a.UpdateLocation(results[0].Location)
+			default:
+				a.view.ShowSearchResults(results)
+			}
+		})
+	})
+}
+
+// CancelSearch stops the running location search (the Go button while
+// searching). No results are shown.
+func (a *App) CancelSearch() {
+	if a.cancelSearch == nil {
+		return
+	}
+	a.cancelSearch()
+	a.finishSearch()
+}
+
+// finishSearch marks the search as no longer running.
+func (a *App) finishSearch() {
+	a.cancelSearch = nil
+	a.view.SetBusy(frontend.TaskSearch, false)
+}
+
+// =============================================================================
+// Map Interaction
+// =============================================================================
+
+// mapClickDelay is how long after a map click its name is looked up. A
+// click that follows within this time replaces it without a request, which
+// also keeps rapid clicking well within Nominatim's rate limit.
+const mapClickDelay = 250 * time.Millisecond
+
+// OnMapClick handles map click events by reverse geocoding the clicked location.
+//
+// When the user clicks on the map, this method:
+//  1. Attempts to reverse geocode the coordinates to get a place name
+//  2. Creates a location with the coordinates and name
+//  3. Falls back to coordinate display if reverse geocoding fails
+//  4. Updates to the new location
+//
+// The reverse geocoding is optional - the app works fine with just coordinates.
+// This is why errors from ReverseGeocode are intentionally ignored.
+//
+// Rapid clicks are debounced: the lookup starts mapClickDelay after the
+// click, and a newer click cancels the lookup of the previous one (whether
+// it is still waiting or already sent), so names can't arrive out of order
+// and only the latest click moves the location.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) OnMapClick(lat, lon float64) {
+	if a.cancelMapClick != nil {
+		a.cancelMapClick()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelMapClick = cancel
+	a.mapClickRequest++
+	request := a.mapClickRequest
+	// A newer click takes over the indicator; the latest one ends it
+	a.view.SetBusy(frontend.TaskLookup, true)
+
+	// Reverse geocode in background
+	crash.Go(func() {
+		defer cancel()
+		select {
+		case <-time.After(mapClickDelay):
+		case <-ctx.Done():
+			return // superseded by a newer click
+		}
+
+		// Try to get a human-readable name for the coordinates.
+		// Error is intentionally ignored - we fall back to coordinate display.
+		place, _ := a.geocoding.ReverseGeocode(ctx, lat, lon)
+		if ctx.Err() != nil {
+			return // superseded by a newer click
+		}
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if request != a.mapClickRequest {
+				return // a newer click came in while switching threads
+			}
+			a.view.SetBusy(frontend.TaskLookup, false)
+			// Build location with timezone from coordinates
+			loc := place
+			loc.Latitude, loc.Longitude = lat, lon
+			loc.Timezone = a.timezones.FromCoordinates(lat, lon)
+
+			// Fall back to coordinate display if no name was found
+			if loc.Name == "" {
+				loc.Name = fmt.Sprintf("%.4f, %.4f", lat, lon)
+			}
+
+			a.UpdateLocation(loc)
+		})
+	})
+}
+
+// ToggleFavorite adds the current location to the favorites, or removes
+// it if it is one already.
+//
+// A removed favorite is also taken out of the daily summary, the desktop
+// notifications and the webhook; an added one is taken out of the scratch
+// locations. The map's favorites layer, the location panel's star and
+// "Recent" menu are updated, and the daily summary, the notifications and
+// the webhook calls are re-armed with the new list.
+func (a *App) ToggleFavorite() {
+	loc := a.state.Location()
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		if f, ok := domain.FavoriteAt(s.Favorites, loc); ok {
+			s.Favorites = slices.DeleteFunc(s.Favorites, func(other domain.Favorite) bool {
+				return other.ID == f.ID
+			})
+			s.DailySummary.Favorites = slices.DeleteFunc(s.DailySummary.Favorites, func(id string) bool {
+				return id == f.ID
+			})
+			s.Notifications.Favorites = slices.DeleteFunc(s.Notifications.Favorites, func(id string) bool {
+				return id == f.ID
+			})
+			s.Webhook.Favorites = slices.DeleteFunc(s.Webhook.Favorites, func(id string) bool {
+				return id == f.ID
+			})
+			return
+		}
+		s.Favorites = append(s.Favorites, domain.Favorite{ID: domain.NewFavoriteID(), Location: loc})
+		s.RecentLocations = domain.RemoveRecentLocation(s.RecentLocations, loc)
+	})
+	a.saveSettings()
+	a.updateFavorites(settings)
+	a.view.UpdateRecentLocations(settings.RecentLocations)
+	a.rescheduleSummary()
+	a.rescheduleHooks()
+}
+
+// UpdateFavoriteOverrides saves a favorite's own settings (see
+// domain.FavoriteOverrides), edited from the favorites list.
+//
+// The overrides are validated first. If the favorite is the current
+// location, they apply at once: the location gets the timezone and
+// elevation, and the sun times are recalculated.
+//
+// Parameters:
+//   - id: The favorite's ID (unknown IDs are ignored)
+//   - overrides: The new overrides; zero overrides remove them
+func (a *App) UpdateFavoriteOverrides(id string, overrides domain.FavoriteOverrides) {
+	overrides = overrides.Validate()
+	var favorite domain.Favorite
+	settings := a.state.UpdateSettings(func(s *domain.Settings) {
+		i := slices.IndexFunc(s.Favorites, func(f domain.Favorite) bool { return f.ID == id })
+		if i < 0 {
+			return
+		}
+		s.Favorites[i].Overrides = overrides
+		favorite = s.Favorites[i]
+	})
+	if favorite.ID == "" {
+		return
+	}
+	a.saveSettings()
+
+	// The favorite's cached time was calculated with the old overrides
+	delete(a.favoriteTimes, id)
+	current, isCurrent := domain.FavoriteAt(settings.Favorites, a.state.Location())
+	if !isCurrent || current.ID != id {
+		a.updateFavorites(settings)
+		return
+	}
+	loc := favorite.CurrentLocation()
+	a.state.SetLocation(loc)
+	events.Publish(a.events, events.LocationChanged{Location: loc})
+	a.recalculate()
+	a.rescheduleHooks()
+}
+
+// ClearRecentLocations forgets all scratch locations, emptying the location
+// panel's "Recent" menu. Favorites are not affected.
+func (a *App) ClearRecentLocations() {
+	a.state.UpdateSettings(func(s *domain.Settings) {
+		s.RecentLocations = nil
+	})
+	a.view.UpdateRecentLocations(nil)
+	a.saveSettings()
+}
+
+// updateFavorites shows the favorites with tonight's golden hour start.
+//
+// Times come from favoriteTimes; only favorites not cached yet are
+// calculated, in one batch, so this is cheap enough to run on every
+// recalculation (which keeps the times current when the day or the golden
+// hour angle changes). Favorites with overrides are calculated one by one
+// with their own settings and location.
+func (a *App) updateFavorites(settings domain.Settings) {
+	now := time.Now()
+	key := fmt.Sprintf("%s/%g", now.Format(time.DateOnly), settings.GoldenHourElevation)
+	if key != a.favoriteTimesKey || a.favoriteTimes == nil {
+		a.favoriteTimes = make(map[string]domain.TimeRange)
+		a.favoriteTimesKey = key
+	}
+
+	var missing []domain.Favorite
+	var locations []domain.Location
+	for _, f := range settings.Favorites {
+		if _, ok := a.favoriteTimes[f.ID]; ok {
+			continue
+		}
+		if !f.Overrides.IsZero() {
+			// Failed favorites are cached with an invalid range and show "N/A"
+			day, _ := solar.New(settings.ForLocation(f.Location)).Calculate(f.CurrentLocation(), now)
+			a.favoriteTimes[f.ID] = day.GoldenEvening
+			continue
+		}
+		missing = append(missing, f)
+		locations = append(locations, f.Location)
+	}
+	if len(missing) > 0 {
+		// The shared calculator may hold the current favorite's angle
+		results, _ := solar.New(settings).CalculateBatch(locations, now)
+		for i, f := range missing {
+			a.favoriteTimes[f.ID] = results[i].GoldenEvening
+		}
+	}
+
+	tonight := make([]domain.TimeRange, len(settings.Favorites))
+	for i, f := range settings.Favorites {
+		tonight[i] = a.favoriteTimes[f.ID]
+	}
+	a.view.UpdateFavorites(settings.Favorites, tonight)
+}
+
+// SelectMapPoint selects a point from one of the map's point layers.
+//
+// Points from imported files usually carry their own name (e.g., a waypoint
+// called "Sunset viewpoint"), which is kept instead of reverse geocoding.
+// Unnamed points are handled like a map click. The timezone is always
+// looked up from the coordinates, since files don't provide one.
+func (a *App) SelectMapPoint(loc domain.Location) {
+	if loc.Name == "" {
+		a.OnMapClick(loc.Latitude, loc.Longitude)
+		return
+	}
+	loc.Timezone = a.timezones.FromCoordinates(loc.Latitude, loc.Longitude)
+	a.UpdateLocation(loc)
+}
+
+// =============================================================================
+// State Getters (implements frontend.Controller interface)
+// =============================================================================
+
+// GetSettings returns the current settings.
+//
+// This is part of the frontend.Controller interface, allowing the UI to query
+// current settings values (e.g., for initializing the settings panel).
+func (a *App) GetSettings() domain.Settings {
+	return a.state.Settings()
+}
+
+// GetLocation returns the current location.
+//
+// This is part of the frontend.Controller interface, allowing the UI to query
+// the current location (e.g., for displaying in the location panel).
+func (a *App) GetLocation() domain.Location {
+	return a.state.Location()
+}
+
+// GetDate returns the current date for calculations.
+//
+// This is part of the frontend.Controller interface, allowing the UI to query
 // the current date (e.g., for initializing the date picker).
 func (a *App) GetDate() time.Time {
-	return a.currentDate
+	return a.state.Date()
+}
+
+// Events returns the event bus the App publishes the changes of location,
+// date and sun times on.
+//
+// This is part of the frontend.Controller interface; a frontend subscribes
+// its widgets when it is created (in the frontend.Factory), before the
+// first event.
+func (a *App) Events() *events.Bus {
+	return a.events
 }
 
 // =============================================================================
@@ -474,30 +3138,198 @@ func (a *App) GetDate() time.Time {
 // recalculate performs solar calculations and updates the UI with results.
 //
 // This is called whenever the location, date, or settings change. It:
-//  1. Calculates sun times using the solar calculator
+//  1. Calculates sun times using the solar calculator, with the golden
+//     hour angle of the favorite at the location if it has its own (see
+//     domain.Settings.ForLocation)
 //  2. Updates the UI to display the new times
 //  3. Shows an error if calculation fails (rare)
 //
-// IMPORTANT: This method checks if mainWindow is nil because it may be called
-// during initialization when the SettingsPanel triggers OnValueChanged callbacks.
-// At that point, the mainWindow hasn't been assigned to the App yet.
+// Does nothing before the view is created.
 func (a *App) recalculate() {
-	// Guard against calls during initialization
-	// (SettingsPanel triggers callbacks before mainWindow is set)
-	if a.mainWindow == nil {
+	if a.view == nil {
 		return
 	}
 
-	// Calculate sun times for current location and date
-	sunTimes, err := a.solarCalc.Calculate(a.location, a.currentDate)
+	// Calculate sun times for current location and date. The calculator
+	// keeps these settings, so exports and the month calendar match
+	snap := a.state.Snapshot()
+	a.solarCalc.UpdateSettings(snap.Settings.ForLocation(snap.Location))
+	sunTimes, err := a.solarCalc.Calculate(snap.Location, snap.Date)
 	if err != nil {
 		// Calculation errors are rare with valid input, but handle them
-		a.mainWindow.ShowError(fmt.Sprintf("Calculation error: %v", err))
+		a.view.ShowError(fmt.Sprintf("Calculation error: %v", err))
 		return
 	}
 
 	// Update the time display panel with calculated values
-	a.mainWindow.UpdateSunTimes(sunTimes)
+	events.Publish(a.events, events.SunTimesUpdated{SunTimes: sunTimes})
+	a.fetchForecast(snap)
+	a.fetchAurora(snap)
+	a.updateMeteors(snap)
+
+	// The timeline runs on into the next morning
+	days := []domain.SunTimes{sunTimes}
+	if next, err := a.solarCalc.Calculate(snap.Location, snap.Date.AddDate(0, 0, 1)); err == nil {
+		days = append(days, next)
+	}
+	a.view.UpdateTimeline(days)
+
+	// Show on the map which part of the horizon the evening light sweeps
+	path, err := solar.SunPath(snap.Location, sunTimes.GoldenEvening, solar.SunPathSamples)
+	if err != nil {
+		a.view.ShowError(fmt.Sprintf("Sun path not shown: %v", err))
+	}
+	a.view.UpdateSunPath(snap.Location, path)
+
+	// The time slider moves the sun through the whole day
+	positions, err := solar.PositionSeries(snap.Location, snap.Date)
+	if err != nil {
+		a.view.ShowError(fmt.Sprintf("Sun positions not shown: %v", err))
+	}
+	a.view.UpdateSunPositions(snap.Location, positions)
+
+	// The favorites' times follow the angles and the day
+	a.updateFavorites(snap.Settings)
+
+	// So do the days of a planned date range
+	a.updateDateRange(snap)
+
+	// The countdown follows the location and the angles
+	a.RefreshCountdown()
+}
+
+// fetchForecast loads the weather forecast of the selected date at the
+// current location, for the weather icons of the time panel.
+//
+// The forecast is fetched in a background goroutine. The icons are cleared
+// first, so a new place or date doesn't show the last one's weather; the
+// request for the previous recalculation is cancelled, and a late reply
+// for it is dropped. Dates beyond the forecast show no weather, and
+// nothing is fetched in privacy mode. Failures are reported in the status
+// bar.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) fetchForecast(snap state.Snapshot) {
+	a.forecastRequest++
+	request := a.forecastRequest
+	if a.cancelForecast != nil {
+		a.cancelForecast()
+		a.cancelForecast = nil
+	}
+	a.view.UpdateWeather(domain.WeatherForecast{})
+	if snap.Settings.PrivacyMode {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	a.cancelForecast = cancel
+	crash.Go(func() {
+		forecast, err := a.forecast.Forecast(ctx, snap.Location, snap.Date)
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if request != a.forecastRequest {
+				return // superseded by a newer recalculation
+			}
+			a.cancelForecast = nil
+			cancel()
+			switch {
+			case errors.Is(err, weather.ErrNoForecast):
+			case err != nil:
+				a.view.ShowError(fmt.Sprintf("Weather forecast unavailable: %v", err))
+			default:
+				a.view.UpdateWeather(forecast)
+			}
+		})
+	})
+}
+
+// fetchAurora rates the night after the selected date at the current
+// location for the aurora, for the Astro panel.
+//
+// The darkness of the night is shown right away. With the aurora outlook
+// on (Settings.AuroraForecast), the Kp forecast is then fetched in a
+// background goroutine and the night is rated by it; a late reply for an
+// earlier recalculation is dropped. Nothing is fetched in privacy mode.
+// Failures are reported in the status bar.
+//
+// Thread Safety: Uses onMainThread() for UI updates.
+func (a *App) fetchAurora(snap state.Snapshot) {
+	a.auroraRequest++
+	request := a.auroraRequest
+	dark, err := solar.Darkness(snap.Location, snap.Date, domain.AuroraDarkElevation)
+	if err != nil {
+		a.view.ShowError(fmt.Sprintf("Darkness not calculated: %v", err))
+	}
+	a.view.UpdateAurora(domain.NewAuroraOutlook(snap.Location, dark, domain.KpForecast{}))
+	if !snap.Settings.AuroraForecast || snap.Settings.PrivacyMode || !dark.IsValid() {
+		return
+	}
+
+	crash.Go(func() {
+		forecast, err := a.aurora.Forecast()
+
+		// Switch back to main thread for UI updates
+		a.onMainThread(func() {
+			if request != a.auroraRequest {
+				return // superseded by a newer recalculation
+			}
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Aurora forecast unavailable: %v", err))
+				return
+			}
+			a.view.UpdateAurora(domain.NewAuroraOutlook(snap.Location, dark, forecast))
+		})
+	})
+}
+
+// updateMeteors rates the meteor shower peaking on the night after the
+// selected date at the current location, if any, for the Astro panel.
+//
+// Most nights no shower peaks and nothing is calculated; a peak night
+// takes a few milliseconds (see solar.MeteorNights), so this runs on the
+// main thread.
+func (a *App) updateMeteors(snap state.Snapshot) {
+	nights, err := solar.MeteorNights(snap.Location, domain.NewDateRange(snap.Date, snap.Date))
+	if err != nil {
+		a.view.ShowError(fmt.Sprintf("Meteor shower not rated: %v", err))
+	}
+	a.view.UpdateMeteors(nights)
+}
+
+// updateDateRange calculates the days of the planned date range for the
+// day table, or hides the table if a single day is planned.
+//
+// A range may run for a year, so its days are calculated on the worker
+// (see calcWorker); a newer recalculation cancels the previous one, and
+// hiding the table drops a calculation still running.
+func (a *App) updateDateRange(snap state.Snapshot) {
+	dates, ok := snap.DateRange()
+	if !ok {
+		a.worker.cancel(calcDateRange)
+		a.view.UpdateDateRange(nil)
+		return
+	}
+	settings := snap.Settings.ForLocation(snap.Location)
+	a.worker.submit(calcDateRange, func(ctx context.Context) func() {
+		days, err := calculateRange(ctx, settings, snap.Location, dates)
+		return func() {
+			if err != nil {
+				a.view.ShowError(fmt.Sprintf("Some days couldn't be calculated: %v", err))
+			}
+			a.view.UpdateDateRange(days)
+		}
+	})
+}
+
+// onMainThread runs fn on the frontend's main thread and waits until it
+// has run.
+//
+// This is the only way background goroutines hand results back to the App:
+// App methods, state writes and widget updates must all happen on the main
+// thread (see Thread Safety in the package docs).
+func (a *App) onMainThread(fn func()) {
+	a.view.RunOnMainThread(fn)
 }
 
 // saveSettings persists the current settings to disk.
@@ -510,8 +3342,8 @@ func (a *App) recalculate() {
 // The app can continue working even if settings can't be saved; they just
 // won't persist to the next session.
 func (a *App) saveSettings() {
-	if err := a.prefs.Save(a.config.Settings); err != nil && a.mainWindow != nil {
-		// Only show error if mainWindow exists (avoid error during init)
-		a.mainWindow.ShowError(fmt.Sprintf("Failed to save settings: %v", err))
+	if err := a.prefs.Save(a.state.Settings()); err != nil && a.view != nil {
+		// Only show error if the view exists (avoid error during init)
+		a.view.ShowError(fmt.Sprintf("Failed to save settings: %v", err))
 	}
 }
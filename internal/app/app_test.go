@@ -0,0 +1,276 @@
+package app
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestNewWithServicesRequiresPrefs(t *testing.T) {
+	if _, err := NewWithServices("", Services{}, nil); err == nil {
+		t.Error("NewWithServices() without preferences: want an error")
+	}
+}
+
+func TestNewWithServicesAppliesPrivacyMode(t *testing.T) {
+	ta := newTestApp(t, nil, nil)
+	if !ta.geocoder.offline {
+		t.Error("geocoder not offline in privacy mode")
+	}
+}
+
+func TestUpdateLocation(t *testing.T) {
+	ta := newTestApp(t, nil, nil)
+	loc := domain.Location{Name: "Lisbon", Latitude: 38.7223, Longitude: -9.1393, Timezone: "Europe/Lisbon"}
+
+	ta.UpdateLocation(loc)
+
+	if got := ta.calc.lastLocation(); got.Name != loc.Name {
+		t.Errorf("calculated for %q, want %q", got.Name, loc.Name)
+	}
+	if len(ta.view.sunTimes) != 1 || ta.view.sunTimes[0].Location.Name != loc.Name {
+		t.Errorf("sun times shown = %v, want those of %q", ta.view.sunTimes, loc.Name)
+	}
+	saved, ok := ta.prefs.lastSaved()
+	if !ok {
+		t.Fatal("settings not saved")
+	}
+	if saved.LastLocation == nil || saved.LastLocation.Name != loc.Name {
+		t.Errorf("saved last location = %v, want %q", saved.LastLocation, loc.Name)
+	}
+	if len(saved.RecentLocations) == 0 || saved.RecentLocations[0].Location.Name != loc.Name {
+		t.Errorf("recent locations = %v, want %q first", saved.RecentLocations, loc.Name)
+	}
+}
+
+func TestUpdateSettingsRecalculates(t *testing.T) {
+	ta := newTestApp(t, nil, nil)
+	settings := ta.state.Settings()
+	settings.GoldenHourElevation = 8
+
+	ta.UpdateSettings(settings)
+
+	if got := ta.calc.settings.GoldenHourElevation; got != 8 {
+		t.Errorf("calculator golden hour elevation = %v, want 8", got)
+	}
+	saved, ok := ta.prefs.lastSaved()
+	if !ok || saved.GoldenHourElevation != 8 {
+		t.Errorf("saved golden hour elevation = %v (saved %v), want 8", saved.GoldenHourElevation, ok)
+	}
+	if len(ta.view.sunTimes) == 0 {
+		t.Error("sun times not shown again")
+	}
+}
+
+func TestSearchLocation(t *testing.T) {
+	berlin := domain.SearchResult{Location: domain.Location{Name: "Berlin", Latitude: 52.52, Longitude: 13.405}}
+	berlinNH := domain.SearchResult{Location: domain.Location{Name: "Berlin, NH", Latitude: 44.4687, Longitude: -71.185}}
+
+	t.Run("coordinates need no geocoder", func(t *testing.T) {
+		ta := newTestApp(t, nil, nil)
+		ta.SearchLocation("48.8566, 2.3522")
+		loc := ta.state.Location()
+		if loc.Latitude != 48.8566 || loc.Longitude != 2.3522 {
+			t.Errorf("location = %v, %v; want 48.8566, 2.3522", loc.Latitude, loc.Longitude)
+		}
+		if loc.Timezone != testTimezone {
+			t.Errorf("timezone = %q, want %q", loc.Timezone, testTimezone)
+		}
+	})
+
+	t.Run("several results are offered", func(t *testing.T) {
+		ta := newTestApp(t, nil, nil)
+		ta.geocoder.results = []domain.SearchResult{berlin, berlinNH}
+		ta.SearchLocation("Zzyzx Berlin")
+		ta.view.drain(t)
+		if len(ta.view.searchResults) != 1 || len(ta.view.searchResults[0]) != 2 {
+			t.Errorf("results shown = %v, want both", ta.view.searchResults)
+		}
+		if ta.cancelSearch != nil {
+			t.Error("search still running")
+		}
+	})
+
+	t.Run("a single result is selected", func(t *testing.T) {
+		ta := newTestApp(t, nil, nil)
+		ta.geocoder.results = []domain.SearchResult{berlin}
+		ta.SearchLocation("Zzyzx Berlin")
+		ta.view.drain(t)
+		if got := ta.state.Location().Name; got != berlin.Location.Name {
+			t.Errorf("location = %q, want %q", got, berlin.Location.Name)
+		}
+	})
+
+	t.Run("failures can be retried", func(t *testing.T) {
+		ta := newTestApp(t, nil, nil)
+		ta.geocoder.err = errOffline
+		ta.SearchLocation("Zzyzx Road")
+		ta.view.drain(t)
+		if len(ta.view.retryableError) != 1 || !strings.Contains(ta.view.retryableError[0], errOffline.Error()) {
+			t.Errorf("errors shown = %v, want %q", ta.view.retryableError, errOffline)
+		}
+	})
+}
+
+func TestDetectLocation(t *testing.T) {
+	useSystem := func(s *domain.Settings) {
+		s.LocationSource = domain.LocationSourceSystem
+	}
+
+	t.Run("names and places the detected coordinates", func(t *testing.T) {
+		ta := newTestApp(t, useSystem, map[string]Geolocator{
+			domain.LocationProviderSystem: fakeGeolocator{
+				id:  domain.LocationProviderSystem,
+				loc: domain.Location{Latitude: 59.3293, Longitude: 18.0686},
+			},
+		})
+		ta.geocoder.place = domain.Location{Name: "Stockholm", Country: "Sweden"}
+
+		ta.DetectLocation()
+		ta.view.drain(t)
+
+		loc := ta.state.Location()
+		if loc.Name != "Stockholm" || loc.Country != "Sweden" {
+			t.Errorf("location = %q, %q; want Stockholm, Sweden", loc.Name, loc.Country)
+		}
+		if loc.Timezone != testTimezone {
+			t.Errorf("timezone = %q, want %q", loc.Timezone, testTimezone)
+		}
+	})
+
+	t.Run("falls back to home", func(t *testing.T) {
+		home := domain.Location{Name: "Home", Latitude: 47.3769, Longitude: 8.5417, Timezone: "Europe/Zurich"}
+		ta := newTestApp(t, func(s *domain.Settings) {
+			useSystem(s)
+			s.HomeLocation = &home
+		}, map[string]Geolocator{
+			domain.LocationProviderSystem: fakeGeolocator{id: domain.LocationProviderSystem, err: errOffline},
+		})
+
+		ta.DetectLocation()
+		ta.view.drain(t)
+
+		if got := ta.state.Location().Name; got != home.Name {
+			t.Errorf("location = %q, want %q", got, home.Name)
+		}
+		if len(ta.view.retryableError) != 1 {
+			t.Errorf("errors shown = %v, want the detection failure", ta.view.retryableError)
+		}
+	})
+
+	t.Run("privacy mode skips online providers", func(t *testing.T) {
+		ta := newTestApp(t, nil, map[string]Geolocator{
+			domain.LocationProviderIPAPI: fakeGeolocator{
+				id:  domain.LocationProviderIPAPI,
+				loc: domain.Location{Name: "Somewhere", Latitude: 1, Longitude: 1},
+			},
+		})
+		before := ta.state.Location()
+
+		ta.DetectLocation()
+
+		if ta.cancelDetect != nil {
+			t.Error("detection started in privacy mode")
+		}
+		if got := ta.state.Location(); got.Name != before.Name {
+			t.Errorf("location = %q, want %q unchanged", got.Name, before.Name)
+		}
+		if len(ta.view.errors) == 0 {
+			t.Error("no error shown")
+		}
+	})
+}
+
+func TestUpdateDateRangeOnWorker(t *testing.T) {
+	ta := newTestApp(t, nil, nil)
+	ta.UpdateDate(time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	ta.UpdateDateRange(time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC))
+	if ta.view.dateRange != nil {
+		t.Fatal("days shown before the worker finished")
+	}
+	ta.view.drain(t)
+	if len(ta.view.dateRange) != 14 {
+		t.Errorf("days shown = %d, want 14", len(ta.view.dateRange))
+	}
+}
+
+// The countdown covers yesterday to tomorrow, whatever date is selected.
+func TestRefreshCountdown(t *testing.T) {
+	ta := newTestApp(t, nil, nil)
+	ta.UpdateDate(time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	tz, err := time.LoadLocation(ta.state.Location().Timezone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	today := time.Now().In(tz)
+	if len(ta.view.countdown) != 3 {
+		t.Fatalf("countdown days = %d, want 3", len(ta.view.countdown))
+	}
+	for i, day := range ta.view.countdown {
+		want := today.AddDate(0, 0, i-1)
+		if day.Date.Year() != want.Year() || day.Date.YearDay() != want.YearDay() {
+			t.Errorf("countdown day %d = %s, want %s", i, day.Date.Format(time.DateOnly), want.Format(time.DateOnly))
+		}
+	}
+}
+
+func TestSessionRestored(t *testing.T) {
+	paris := domain.Location{Name: "Paris", Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris"}
+	future := time.Now().AddDate(0, 0, 3)
+	session := domain.Session{
+		Location: &paris,
+		Date:     future.Format(time.DateOnly),
+		MapZoom:  9,
+		Panels:   []string{domain.SessionPanelWeekOutlook},
+	}
+
+	ta := newTestAppWithSession(t, nil, nil, session)
+
+	if got := ta.GetLocation(); got.Name != "Paris" {
+		t.Errorf("location = %q, want the session's Paris", got.Name)
+	}
+	if got := ta.GetDate().Format(time.DateOnly); got != session.Date {
+		t.Errorf("date = %s, want the session's %s", got, session.Date)
+	}
+	if got := ta.GetSettings().MapZoom; got != 9 {
+		t.Errorf("map zoom = %d, want the session's 9", got)
+	}
+	if len(ta.cfg.OpenPanels) != 1 || ta.cfg.OpenPanels[0] != domain.SessionPanelWeekOutlook {
+		t.Errorf("view opens %v, want the week outlook", ta.cfg.OpenPanels)
+	}
+}
+
+func TestSessionOfOtherProfileIgnored(t *testing.T) {
+	paris := domain.Location{Name: "Paris", Latitude: 48.8566, Longitude: 2.3522}
+	ta := newTestAppWithSession(t, nil, nil, domain.Session{Profile: "work", Location: &paris, MapZoom: 9})
+
+	if got := ta.GetLocation(); got.Name == "Paris" {
+		t.Error("restored the location of another profile's session")
+	}
+	if got := ta.GetSettings().MapZoom; got == 9 {
+		t.Error("restored the map zoom of another profile's session")
+	}
+}
+
+func TestEndSession(t *testing.T) {
+	ta := newTestApp(t, nil, nil)
+	loc := domain.Location{Name: "Lisbon", Latitude: 38.7223, Longitude: -9.1393, Timezone: "Europe/Lisbon"}
+	ta.UpdateLocation(loc)
+	ta.UpdateDate(time.Date(2030, 6, 21, 0, 0, 0, 0, time.Local))
+	ta.UpdateMapZoom(11)
+
+	ta.EndSession([]string{domain.SessionPanelMonthCalendar})
+
+	if len(ta.sessions.saved) != 1 {
+		t.Fatalf("%d sessions saved, want 1", len(ta.sessions.saved))
+	}
+	saved := ta.sessions.saved[0]
+	if saved.Location == nil || saved.Location.Name != "Lisbon" || saved.Date != "2030-06-21" ||
+		saved.MapZoom != 11 || !saved.HasPanel(domain.SessionPanelMonthCalendar) || saved.SavedAt.IsZero() {
+		t.Errorf("saved session = %+v, want Lisbon on 2030-06-21 at zoom 11 with the month calendar", saved)
+	}
+}
@@ -0,0 +1,319 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/changelog"
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/events"
+	"github.com/megatih/GoGoldenHour/internal/frontend"
+	"github.com/megatih/GoGoldenHour/internal/service/automation"
+	"github.com/megatih/GoGoldenHour/internal/service/caldav"
+	"github.com/megatih/GoGoldenHour/internal/service/geocoding"
+	"github.com/megatih/GoGoldenHour/internal/service/updates"
+	"github.com/megatih/GoGoldenHour/internal/storage"
+)
+
+// =============================================================================
+// Fake Services
+// =============================================================================
+//
+// Fakes of the service interfaces (services.go) and of frontend.View, so
+// the controller runs without network, disk or Qt. Each records what the
+// App asked of it.
+
+// fakePrefs keeps the settings in memory.
+type fakePrefs struct {
+	settings domain.Settings
+	saved    []domain.Settings
+}
+
+func (p *fakePrefs) Load() (domain.Settings, error)        { return p.settings, nil }
+func (p *fakePrefs) Exists() bool                          { return true }
+func (p *fakePrefs) Corrupt() bool                         { return false }
+func (p *fakePrefs) HasBackup() bool                       { return false }
+func (p *fakePrefs) Recover(bool) (domain.Settings, error) { return p.settings, nil }
+func (p *fakePrefs) GetConfigPath() string                 { return "/nonexistent/settings.json" }
+func (p *fakePrefs) Save(settings domain.Settings) error {
+	p.saved = append(p.saved, settings)
+	return nil
+}
+func (p *fakePrefs) lastSaved() (settings domain.Settings, ok bool) {
+	if len(p.saved) == 0 {
+		return domain.Settings{}, false
+	}
+	return p.saved[len(p.saved)-1], true
+}
+
+// fakeCalculator returns made-up sun times: a golden hour from 18:00 to
+// 19:00 on every date.
+type fakeCalculator struct {
+	mu         sync.Mutex
+	calculated []domain.Location
+	settings   domain.Settings
+}
+
+func (c *fakeCalculator) Calculate(loc domain.Location, date time.Time) (domain.SunTimes, error) {
+	c.mu.Lock()
+	c.calculated = append(c.calculated, loc)
+	c.mu.Unlock()
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	return domain.SunTimes{
+		Date:          day,
+		Location:      loc,
+		Sunrise:       day.Add(6 * time.Hour),
+		Sunset:        day.Add(19 * time.Hour),
+		SolarNoon:     day.Add(12 * time.Hour),
+		GoldenEvening: domain.TimeRange{Start: day.Add(18 * time.Hour), End: day.Add(19 * time.Hour)},
+	}, nil
+}
+
+func (c *fakeCalculator) CalculateRange(loc domain.Location, dates domain.DateRange) ([]domain.SunTimes, error) {
+	var days []domain.SunTimes
+	for _, date := range dates.Dates() {
+		day, _ := c.Calculate(loc, date)
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+func (c *fakeCalculator) CalculateMany(loc domain.Location, dates domain.DateRange) ([]domain.SunTimes, error) {
+	return c.CalculateRange(loc, dates)
+}
+
+func (c *fakeCalculator) UpdateSettings(settings domain.Settings) {
+	c.mu.Lock()
+	c.settings = settings
+	c.mu.Unlock()
+}
+
+// lastLocation returns the place of the latest calculation.
+func (c *fakeCalculator) lastLocation() domain.Location {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.calculated) == 0 {
+		return domain.Location{}
+	}
+	return c.calculated[len(c.calculated)-1]
+}
+
+// fakeGeocoder answers searches with results and err, and names every
+// place place.
+type fakeGeocoder struct {
+	results []domain.SearchResult
+	err     error
+	place   domain.Location
+	offline bool
+}
+
+func (g *fakeGeocoder) Search(context.Context, string, int, geocoding.SearchOptions) ([]domain.SearchResult, error) {
+	return g.results, g.err
+}
+
+func (g *fakeGeocoder) ReverseGeocode(context.Context, float64, float64) (domain.Location, error) {
+	return g.place, nil
+}
+
+func (g *fakeGeocoder) SetUserAgent(string)     {}
+func (g *fakeGeocoder) SetOffline(offline bool) { g.offline = offline }
+
+// fakeGeolocator detects loc, or fails with err.
+type fakeGeolocator struct {
+	id  string
+	loc domain.Location
+	err error
+}
+
+func (l fakeGeolocator) ID() string { return l.id }
+
+func (l fakeGeolocator) DetectLocation(context.Context) (domain.Location, error) {
+	return l.loc, l.err
+}
+
+// fakeSessions keeps the session in memory.
+type fakeSessions struct {
+	session domain.Session
+	saved   []domain.Session
+}
+
+func (s *fakeSessions) Load() (domain.Session, error) { return s.session, nil }
+func (s *fakeSessions) Save(session domain.Session) error {
+	s.saved = append(s.saved, session)
+	return nil
+}
+
+// fakeTimezones puts every place in one time zone.
+type fakeTimezones string
+
+func (tz fakeTimezones) FromCoordinates(float64, float64) string { return string(tz) }
+
+// =============================================================================
+// Fake View
+// =============================================================================
+
+// fakeView records what the App shows, through the View and the event bus
+// (see subscribe). Work the App hands to the main thread is queued and runs
+// on the test's goroutine in drain, as the Qt event loop would run it on
+// the GUI thread.
+type fakeView struct {
+	queue chan func()
+
+	sunTimes       []domain.SunTimes
+	locations      []domain.Location
+	searchResults  [][]domain.SearchResult
+	errors         []string
+	retryableError []string
+	countdown      []domain.SunTimes
+	dateRange      []domain.SunTimes
+}
+
+func newFakeView() *fakeView {
+	return &fakeView{queue: make(chan func(), 16)}
+}
+
+// drain runs the next function the App queued for the main thread,
+// failing the test if none arrives in time.
+func (v *fakeView) drain(t *testing.T) {
+	t.Helper()
+	select {
+	case fn := <-v.queue:
+		fn()
+	case <-time.After(5 * time.Second):
+		t.Fatal("nothing was run on the main thread")
+	}
+}
+
+func (v *fakeView) RunOnMainThread(fn func())   { v.queue <- fn }
+func (v *fakeView) QueueOnMainThread(fn func()) { v.queue <- fn }
+
+// subscribe records the locations and sun times the App publishes, as a
+// frontend's widgets would show them.
+func (v *fakeView) subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(e events.LocationChanged) { v.locations = append(v.locations, e.Location) })
+	events.Subscribe(bus, func(e events.SunTimesUpdated) { v.sunTimes = append(v.sunTimes, e.SunTimes) })
+}
+
+func (v *fakeView) ShowSearchResults(results []domain.SearchResult) {
+	v.searchResults = append(v.searchResults, results)
+}
+func (v *fakeView) UpdateCountdown(days []domain.SunTimes) { v.countdown = days }
+func (v *fakeView) UpdateDateRange(days []domain.SunTimes) { v.dateRange = days }
+func (v *fakeView) ShowError(message string)               { v.errors = append(v.errors, message) }
+func (v *fakeView) ShowRetryableError(message string, _ func()) {
+	v.retryableError = append(v.retryableError, message)
+}
+
+func (v *fakeView) Show()                                                                     {}
+func (v *fakeView) ShowWelcome()                                                              {}
+func (v *fakeView) ShowWhatsNew([]changelog.Release)                                          {}
+func (v *fakeView) ShowSettingsRecovery(bool)                                                 {}
+func (v *fakeView) UpdateWeather(domain.WeatherForecast)                                      {}
+func (v *fakeView) UpdateAurora(domain.AuroraOutlook)                                         {}
+func (v *fakeView) UpdateMeteors([]domain.MeteorNight)                                        {}
+func (v *fakeView) UpdateTimeline([]domain.SunTimes)                                          {}
+func (v *fakeView) UpdateSunPath(domain.Location, []domain.SunPathPoint)                      {}
+func (v *fakeView) UpdateSunPositions(domain.Location, []domain.SunPathPoint)                 {}
+func (v *fakeView) UpdateTerminator([]domain.TwilightBand)                                    {}
+func (v *fakeView) UpdateFavorites([]domain.Favorite, []domain.TimeRange)                     {}
+func (v *fakeView) UpdateRecentLocations([]domain.RecentLocation)                             {}
+func (v *fakeView) UpdatePresets(domain.Settings)                                             {}
+func (v *fakeView) ApplySettings(domain.Settings)                                             {}
+func (v *fakeView) ReloadSettings(domain.Settings)                                            {}
+func (v *fakeView) SetMinimizeToTray(bool)                                                    {}
+func (v *fakeView) ShowSunAlignments(domain.Location, domain.Location, []domain.SunAlignment) {}
+func (v *fakeView) ShowElevationProfile(domain.Location, domain.Location, domain.ElevationProfile) {
+}
+func (v *fakeView) ShowCloudFrames([]domain.CloudFrame, domain.TimeRange, bool)      {}
+func (v *fakeView) ShowWeekOutlook([]domain.SunTimes, domain.WeatherForecast, error) {}
+func (v *fakeView) ShowUpdateCheck(updates.Release, bool)                            {}
+func (v *fakeView) ShowHookResult(automation.Result)                                 {}
+func (v *fakeView) ShowSummaryResult(automation.SummaryResult)                       {}
+func (v *fakeView) ShowFeedResult(automation.FeedResult)                             {}
+func (v *fakeView) ShowPushResult(error)                                             {}
+func (v *fakeView) ShowCalendarSyncResult(caldav.Result, error)                      {}
+func (v *fakeView) SetBusy(frontend.Task, bool)                                      {}
+func (v *fakeView) MapBounds() (domain.Bounds, bool)                                 { return domain.Bounds{}, false }
+func (v *fakeView) LocateWithMap()                                                   {}
+
+// =============================================================================
+// Test App
+// =============================================================================
+
+// testApp is an App built from fakes.
+type testApp struct {
+	*App
+	view     *fakeView
+	prefs    *fakePrefs
+	calc     *fakeCalculator
+	geocoder *fakeGeocoder
+	sessions *fakeSessions
+
+	// cfg is the configuration the view was created with.
+	cfg config.AppConfig
+}
+
+// testTimezone is the time zone of every place in a testApp.
+const testTimezone = "Europe/Berlin"
+
+// newTestApp builds an App with fake services and the given settings. The
+// settings are in privacy mode, so the concrete services (weather, aurora,
+// updates) stay offline; the tile cache keeps to a temporary directory.
+//
+// Parameters:
+//   - t: The test
+//   - edit: Changes the default settings, or nil
+//   - providers: The location providers by ID, or nil for none
+func newTestApp(t *testing.T, edit func(*domain.Settings), providers map[string]Geolocator) *testApp {
+	t.Helper()
+	return newTestAppWithSession(t, edit, providers, domain.Session{})
+}
+
+// newTestAppWithSession builds a test App (see newTestApp) that starts
+// with the given last session.
+func newTestAppWithSession(t *testing.T, edit func(*domain.Settings), providers map[string]Geolocator, session domain.Session) *testApp {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	settings := domain.DefaultSettings()
+	settings.PrivacyMode = true
+	if edit != nil {
+		edit(&settings)
+	}
+	if providers == nil {
+		providers = map[string]Geolocator{}
+	}
+
+	ta := &testApp{
+		view:     newFakeView(),
+		prefs:    &fakePrefs{settings: settings},
+		calc:     &fakeCalculator{},
+		geocoder: &fakeGeocoder{},
+		sessions: &fakeSessions{session: session},
+	}
+	a, err := NewWithServices(storage.DefaultProfile, Services{
+		Prefs:             ta.prefs,
+		Solar:             ta.calc,
+		Geocoder:          ta.geocoder,
+		LocationProviders: providers,
+		Timezones:         fakeTimezones(testTimezone),
+		Sessions:          ta.sessions,
+	}, func(cfg config.AppConfig, controller frontend.Controller) frontend.View {
+		ta.cfg = cfg
+		ta.view.subscribe(controller.Events())
+		return ta.view
+	})
+	if err != nil {
+		t.Fatalf("NewWithServices() error = %v", err)
+	}
+	ta.App = a
+	return ta
+}
+
+// errOffline is the error of a fake service without network.
+var errOffline = errors.New("network unreachable")
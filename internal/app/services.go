@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/geocoding"
+	"github.com/megatih/GoGoldenHour/internal/service/geolocation"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+)
+
+// =============================================================================
+// Service Interfaces
+// =============================================================================
+//
+// The App reaches the services it needs on every user action through the
+// interfaces below rather than the concrete types, so they can be replaced
+// (see Services and NewWithServices):
+//
+//	┌───────────────────┐     ┌──────────────────────────────┐
+//	│                   │────►│ SolarCalculator  solar       │
+//	│                   │────►│ Geocoder         geocoding   │
+//	│        App        │────►│ Geolocator       geolocation │
+//	│                   │────►│ TimezoneResolver timezone    │
+//	│                   │────►│ PreferencesStore storage     │
+//	└───────────────────┘     └──────────────────────────────┘
+//
+// Each interface holds only the methods the App calls. Services used for
+// a single feature (weather, aurora, elevation, updates, the tile cache)
+// stay concrete.
+
+// SolarCalculator calculates the sun times of a place (solar.Calculator).
+type SolarCalculator interface {
+	// Calculate returns the sun times of a date at a place.
+	Calculate(loc domain.Location, date time.Time) (domain.SunTimes, error)
+
+	// CalculateRange returns the sun times of each day of a range, in order.
+	CalculateRange(loc domain.Location, dates domain.DateRange) ([]domain.SunTimes, error)
+
+	// CalculateMany returns the same as CalculateRange, calculating the
+	// days in parallel (for long ranges, e.g., exports).
+	CalculateMany(loc domain.Location, dates domain.DateRange) ([]domain.SunTimes, error)
+
+	// UpdateSettings applies new elevation angles and custom events.
+	UpdateSettings(settings domain.Settings)
+}
+
+// Geocoder searches places by name and names coordinates
+// (geocoding.NominatimService).
+type Geocoder interface {
+	// Search returns up to limit places matching a query.
+	Search(ctx context.Context, query string, limit int, options geocoding.SearchOptions) ([]domain.SearchResult, error)
+
+	// ReverseGeocode returns the named place at coordinates.
+	ReverseGeocode(ctx context.Context, lat, lon float64) (domain.Location, error)
+
+	// SetUserAgent changes the User-Agent sent with requests.
+	SetUserAgent(userAgent string)
+
+	// SetOffline makes the geocoder answer from its caches only.
+	SetOffline(offline bool)
+}
+
+// Geolocator detects the current location (geolocation.Provider); the App
+// holds one per domain.LocationProvider ID.
+type Geolocator = geolocation.Provider
+
+// TimezoneResolver finds the time zone of coordinates (the timezone
+// package).
+type TimezoneResolver interface {
+	// FromCoordinates returns the IANA time zone name at coordinates.
+	FromCoordinates(lat, lon float64) string
+}
+
+// PreferencesStore loads and saves the settings of a profile
+// (storage.PreferencesStore).
+type PreferencesStore interface {
+	// Load reads the settings, or returns the defaults on a first start or
+	// if the file is corrupt.
+	Load() (domain.Settings, error)
+
+	// Save writes the settings.
+	Save(settings domain.Settings) error
+
+	// Exists reports whether a settings file was found.
+	Exists() bool
+
+	// Corrupt reports whether Load found a settings file it couldn't read.
+	Corrupt() bool
+
+	// HasBackup reports whether a backup of the previous settings exists.
+	HasBackup() bool
+
+	// Recover returns the backup's settings or the defaults after Load
+	// found the file corrupt.
+	Recover(fromBackup bool) (domain.Settings, error)
+
+	// GetConfigPath returns the settings file's path.
+	GetConfigPath() string
+}
+
+// SessionStore loads and saves the session the app last quit with
+// (storage.SessionStore).
+type SessionStore interface {
+	// Load reads the last session; an empty one if there is none.
+	Load() (domain.Session, error)
+
+	// Save replaces the last session.
+	Save(session domain.Session) error
+}
+
+// Services are the services an App is built with (see NewWithServices).
+// Services left nil are created as New would.
+type Services struct {
+	// Prefs stores the settings; required.
+	Prefs PreferencesStore
+
+	// Solar calculates sun times (default: solar.New with the loaded
+	// settings).
+	Solar SolarCalculator
+
+	// Geocoder searches places (default: Nominatim with the on-disk cache).
+	Geocoder Geocoder
+
+	// LocationProviders detect the location, by domain.LocationProvider ID
+	// (default: ip-api.com, ipinfo.io and the system's location service).
+	LocationProviders map[string]Geolocator
+
+	// Timezones resolves time zones (default: timezone.FromCoordinates,
+	// offline with embedded data).
+	Timezones TimezoneResolver
+
+	// Sessions keeps the last session (default: session.json in the config
+	// directory).
+	Sessions SessionStore
+}
+
+// timezoneLookup resolves time zones with the timezone package.
+type timezoneLookup struct{}
+
+// FromCoordinates implements TimezoneResolver with timezone.FromCoordinates.
+func (timezoneLookup) FromCoordinates(lat, lon float64) string {
+	return timezone.FromCoordinates(lat, lon)
+}
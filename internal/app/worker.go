@@ -0,0 +1,204 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+)
+
+// =============================================================================
+// Calculation Worker
+// =============================================================================
+//
+// A day's sun times take well under a millisecond and are calculated on the
+// main thread, so the time panel follows each click at once. Calculations
+// over many days (the date range's day table, up to a year, and the week
+// outlook) run on the calculation worker instead, so planning a long range
+// doesn't freeze the window:
+//
+//	Main thread                      Worker goroutine
+//	───────────                      ────────────────
+//	submit(kind, work) ──► queue ──► work(ctx): calculate the days
+//	  (cancels the kind's                │
+//	   previous job)                     ▼
+//	apply() ◄──────── onMainThread ◄── apply, unless cancelled meanwhile
+//
+// Jobs run one at a time, in the order submitted. A newer job of the same
+// kind cancels the older one: a queued job is skipped, a running one stops
+// between chunks of days (see calculateRange), and a finished one's result
+// is dropped. Only the result crosses back to the main thread.
+
+// calcKind names a kind of calculation job; each kind has at most one job
+// that counts, the newest.
+type calcKind int
+
+const (
+	// calcDateRange calculates the days of the planned date range.
+	calcDateRange calcKind = iota
+
+	// calcWeekOutlook calculates the days of the week outlook.
+	calcWeekOutlook
+)
+
+// rangeChunkDays is how many days calculateRange calculates between checks
+// for cancellation: a few milliseconds' work each.
+const rangeChunkDays = 31
+
+// calcJob is a queued calculation.
+type calcJob struct {
+	kind calcKind
+	ctx  context.Context
+
+	// work runs on the worker goroutine and returns the function that
+	// applies its result on the main thread (nil for nothing to apply).
+	work func(ctx context.Context) (apply func())
+}
+
+// calcWorker runs calculation jobs on a background goroutine.
+//
+// submit and cancel are called on the main thread only; the queue is
+// shared with the worker goroutine under mu.
+type calcWorker struct {
+	onMainThread func(func())
+
+	mu    sync.Mutex
+	queue []calcJob
+	ready chan struct{} // signalled when a job is queued
+
+	// cancels cancels each kind's newest job. Only accessed on the main
+	// thread.
+	cancels map[calcKind]context.CancelFunc
+}
+
+// newCalcWorker creates a worker and starts its goroutine, which runs for
+// the life of the process.
+//
+// Parameters:
+//   - onMainThread: Runs a function on the main thread and waits for it
+//     (App.onMainThread)
+func newCalcWorker(onMainThread func(func())) *calcWorker {
+	w := &calcWorker{
+		onMainThread: onMainThread,
+		ready:        make(chan struct{}, 1),
+		cancels:      make(map[calcKind]context.CancelFunc),
+	}
+	go w.run()
+	return w
+}
+
+// submit queues a job, cancelling the kind's previous one. It never blocks:
+// the worker may be waiting for the main thread with a result.
+//
+// Parameters:
+//   - kind: The job's kind
+//   - work: The calculation; it should return early once ctx is done
+func (w *calcWorker) submit(kind calcKind, work func(ctx context.Context) (apply func())) {
+	w.cancel(kind)
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancels[kind] = cancel
+
+	w.mu.Lock()
+	w.queue = append(w.queue, calcJob{kind: kind, ctx: ctx, work: work})
+	w.mu.Unlock()
+	select {
+	case w.ready <- struct{}{}:
+	default: // already signalled
+	}
+}
+
+// cancel drops the kind's newest job, queued, running or waiting to be
+// applied.
+func (w *calcWorker) cancel(kind calcKind) {
+	if cancel, ok := w.cancels[kind]; ok {
+		cancel()
+		delete(w.cancels, kind)
+	}
+}
+
+// run is the worker goroutine: it takes jobs off the queue in order.
+func (w *calcWorker) run() {
+	for range w.ready {
+		for {
+			job, ok := w.next()
+			if !ok {
+				break
+			}
+			w.runJob(job)
+		}
+	}
+}
+
+// next takes the oldest job off the queue.
+func (w *calcWorker) next() (calcJob, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.queue) == 0 {
+		return calcJob{}, false
+	}
+	job := w.queue[0]
+	w.queue = w.queue[1:]
+	return job, true
+}
+
+// runJob runs a job and applies its result on the main thread, unless it
+// was cancelled. Cancellation happens on the main thread, so checking the
+// context there again can't miss a newer job. A panicking job is reported
+// (see crash.Recover) and the worker goes on with the next.
+func (w *calcWorker) runJob(job calcJob) {
+	defer crash.Recover()
+	if job.ctx.Err() != nil {
+		return // superseded while queued
+	}
+	apply := job.work(job.ctx)
+	if apply == nil || job.ctx.Err() != nil {
+		return
+	}
+	w.onMainThread(func() {
+		if job.ctx.Err() != nil {
+			return // superseded while calculating
+		}
+		w.cancels[job.kind]()
+		delete(w.cancels, job.kind)
+		apply()
+	})
+}
+
+// calculateRange calculates the sun times of each day of a range on the
+// worker goroutine.
+//
+// The App's calculator is used on the main thread only (it isn't
+// thread-safe), so the job gets its own, with the settings of the
+// snapshot it was submitted with. The days are calculated rangeChunkDays
+// at a time (in parallel, see solar.Calculator.CalculateMany), stopping
+// early once ctx is done.
+//
+// Parameters:
+//   - ctx: The job's context
+//   - settings: The settings for the location (Settings.ForLocation)
+//   - loc: The place
+//   - dates: The days
+//
+// Returns the days calculated (as solar.Calculator.CalculateRange) and the
+// failures joined, or ctx's error if cancelled.
+func calculateRange(ctx context.Context, settings domain.Settings, loc domain.Location, dates domain.DateRange) ([]domain.SunTimes, error) {
+	calc := solar.New(settings)
+	all := dates.Dates()
+	days := make([]domain.SunTimes, 0, len(all))
+	var errs []error
+	for start := 0; start < len(all); start += rangeChunkDays {
+		if err := ctx.Err(); err != nil {
+			return days, err
+		}
+		end := min(start+rangeChunkDays, len(all))
+		chunk, err := calc.CalculateMany(loc, domain.NewDateRange(all[start], all[end-1]))
+		if err != nil {
+			errs = append(errs, err)
+		}
+		days = append(days, chunk...)
+	}
+	return days, errors.Join(errs...)
+}
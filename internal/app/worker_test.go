@@ -0,0 +1,82 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCalcWorkerDropsSupersededJobs(t *testing.T) {
+	view := newFakeView()
+	w := newCalcWorker(view.RunOnMainThread)
+
+	release := make(chan struct{})
+	var applied []string
+	w.submit(calcDateRange, func(ctx context.Context) func() {
+		<-release // still calculating when the next job comes
+		return func() { applied = append(applied, "first") }
+	})
+	w.submit(calcDateRange, func(ctx context.Context) func() {
+		return func() { applied = append(applied, "second") }
+	})
+	w.submit(calcWeekOutlook, func(ctx context.Context) func() {
+		return func() { applied = append(applied, "other kind") }
+	})
+	close(release)
+
+	view.drain(t)
+	view.drain(t)
+	want := []string{"second", "other kind"}
+	if len(applied) != len(want) || applied[0] != want[0] || applied[1] != want[1] {
+		t.Errorf("applied = %v, want %v", applied, want)
+	}
+}
+
+func TestCalcWorkerCancel(t *testing.T) {
+	view := newFakeView()
+	w := newCalcWorker(view.RunOnMainThread)
+
+	started, release := make(chan struct{}), make(chan struct{})
+	w.submit(calcDateRange, func(ctx context.Context) func() {
+		close(started)
+		<-release
+		return func() { t.Error("cancelled job applied") }
+	})
+	<-started
+	w.cancel(calcDateRange)
+	close(release)
+	select {
+	case <-view.queue:
+		t.Error("cancelled job handed to the main thread")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestCalculateRange(t *testing.T) {
+	settings := domain.DefaultSettings()
+	loc := domain.Location{Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	year := domain.NewDateRange(start, start.AddDate(0, 0, 364))
+
+	days, err := calculateRange(context.Background(), settings, loc, year)
+	if err != nil {
+		t.Fatalf("calculateRange() error = %v", err)
+	}
+	if len(days) != 365 {
+		t.Fatalf("calculateRange() = %d days, want 365", len(days))
+	}
+	for i, day := range days {
+		if want := start.AddDate(0, 0, i); day.Date.YearDay() != want.YearDay() {
+			t.Fatalf("day %d is %s, want %s", i, day.Date.Format(time.DateOnly), want.Format(time.DateOnly))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := calculateRange(ctx, settings, loc, year); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled calculateRange() error = %v, want context.Canceled", err)
+	}
+}
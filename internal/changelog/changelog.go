@@ -0,0 +1,173 @@
+// Package changelog provides the release notes shown in the "What's new" dialog.
+//
+// After an update, the app shows what changed since the version the user
+// last ran (see domain.Settings.LastSeenVersion). The notes are written for
+// users rather than developers, so they are kept separately from
+// CHANGELOG.md, which also records internal changes.
+//
+// # Data File
+//
+// The notes live in changelog.json, which is embedded into the binary at
+// build time. Add an entry at the top for every release; the newest entry
+// must match config.AppConfig.AppVersion (checked by the package tests):
+//
+//	{
+//	  "version": 1,
+//	  "releases": [
+//	    {"version": "0.1.3", "date": "2026-01-02", "changes": ["..."]}
+//	  ]
+//	}
+package changelog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Release
+// =============================================================================
+
+// Release holds the notes for one version of the app.
+type Release struct {
+	// Version is the release's version number (e.g., "0.1.3").
+	Version string `json:"version"`
+
+	// Date is the release date in YYYY-MM-DD form.
+	Date string `json:"date"`
+
+	// Changes lists the user-visible changes, one sentence each.
+	Changes []string `json:"changes"`
+}
+
+// Title returns a heading for the release, e.g., "Version 0.1.3 (January 2, 2026)".
+//
+// The date is left out if it can't be parsed.
+func (r Release) Title() string {
+	date, err := time.Parse("2006-01-02", r.Date)
+	if err != nil {
+		return "Version " + r.Version
+	}
+	return fmt.Sprintf("Version %s (%s)", r.Version, date.Format("January 2, 2006"))
+}
+
+// changelogFile is the structure of the embedded changelog.json.
+type changelogFile struct {
+	Version  int       `json:"version"`
+	Releases []Release `json:"releases"`
+}
+
+// changelogJSON holds the raw embedded release notes.
+//
+//go:embed changelog.json
+var changelogJSON []byte
+
+var (
+	// loadOnce guards parsing of the embedded data.
+	loadOnce sync.Once
+
+	// releases holds the parsed releases, newest first.
+	releases []Release
+
+	// loadErr is the error from parsing, if any.
+	loadErr error
+)
+
+// load parses the embedded release notes on first use.
+func load() ([]Release, error) {
+	loadOnce.Do(func() {
+		var file changelogFile
+		if err := json.Unmarshal(changelogJSON, &file); err != nil {
+			loadErr = fmt.Errorf("failed to parse changelog: %w", err)
+			return
+		}
+		releases = file.Releases
+	})
+	return releases, loadErr
+}
+
+// Releases returns all releases, newest first.
+func Releases() ([]Release, error) {
+	return load()
+}
+
+// Between returns the releases newer than after, up to and including upTo,
+// newest first.
+//
+// Parameters:
+//   - after: The version the user last saw; empty for all releases
+//   - upTo: The version now running (releases in the data file that are
+//     newer, e.g., on a development build, are left out)
+//
+// Returns nil if nothing changed or the embedded data is invalid, so a
+// broken data file never keeps the app from starting.
+//
+// Example:
+//
+//	notes := changelog.Between("0.1.1", "0.1.3")
+//	// notes holds 0.1.3 and 0.1.2
+func Between(after, upTo string) []Release {
+	all, err := load()
+	if err != nil {
+		return nil
+	}
+
+	var found []Release
+	for _, r := range all {
+		if CompareVersions(r.Version, upTo) > 0 {
+			continue
+		}
+		if after != "" && CompareVersions(r.Version, after) <= 0 {
+			continue
+		}
+		found = append(found, r)
+	}
+	return found
+}
+
+// CompareVersions compares two dotted version numbers such as "0.1.3".
+//
+// Components are compared as numbers, so "0.10.0" is newer than "0.9.0".
+// A missing component counts as zero ("1.2" equals "1.2.0"). A leading "v"
+// and anything after a "-" (pre-release suffix) are ignored, as are
+// components that aren't numbers.
+//
+// Returns -1 if a is older than b, 0 if they are the same version, and +1
+// if a is newer.
+func CompareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionParts splits a version number into its numeric components.
+func versionParts(version string) []int {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	version, _, _ = strings.Cut(version, "-")
+
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		n, _ := strconv.Atoi(field)
+		parts = append(parts, n)
+	}
+	return parts
+}
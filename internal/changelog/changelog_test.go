@@ -0,0 +1,94 @@
+package changelog
+
+import (
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+)
+
+func TestReleasesMatchAppVersion(t *testing.T) {
+	all, err := Releases()
+	if err != nil {
+		t.Fatalf("Releases() error: %v", err)
+	}
+	if len(all) == 0 {
+		t.Fatal("changelog.json has no releases")
+	}
+	if want := config.DefaultConfig().AppVersion; all[0].Version != want {
+		t.Errorf("newest release is %s, want an entry for the app version %s", all[0].Version, want)
+	}
+
+	for i, r := range all {
+		if len(r.Changes) == 0 {
+			t.Errorf("release %s has no changes", r.Version)
+		}
+		if i > 0 && CompareVersions(r.Version, all[i-1].Version) >= 0 {
+			t.Errorf("release %s is listed after %s; releases must be newest first", r.Version, all[i-1].Version)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"0.1.3", "0.1.3", 0},
+		{"0.1.2", "0.1.3", -1},
+		{"0.2.0", "0.1.9", 1},
+		{"0.10.0", "0.9.0", 1},
+		{"1.2", "1.2.0", 0},
+		{"v1.0.0", "1.0.0", 0},
+		{"1.0.0-beta", "1.0.0", 0},
+		{"", "0.0.1", -1},
+	}
+
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBetween(t *testing.T) {
+	versions := func(releases []Release) []string {
+		var v []string
+		for _, r := range releases {
+			v = append(v, r.Version)
+		}
+		return v
+	}
+
+	tests := []struct {
+		after, upTo string
+		want        []string
+	}{
+		{"0.1.1", "0.1.3", []string{"0.1.3", "0.1.2"}},
+		{"0.1.3", "0.1.3", nil},
+		{"", "0.1.1", []string{"0.1.1", "0.1.0"}},
+		{"0.1.0", "0.1.2", []string{"0.1.2", "0.1.1"}},
+	}
+
+	for _, tt := range tests {
+		got := versions(Between(tt.after, tt.upTo))
+		if len(got) != len(tt.want) {
+			t.Errorf("Between(%q, %q) = %v, want %v", tt.after, tt.upTo, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("Between(%q, %q) = %v, want %v", tt.after, tt.upTo, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestReleaseTitle(t *testing.T) {
+	if got, want := (Release{Version: "0.1.3", Date: "2026-01-02"}).Title(), "Version 0.1.3 (January 2, 2026)"; got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+	if got, want := (Release{Version: "0.2.0"}).Title(), "Version 0.2.0"; got != want {
+		t.Errorf("Title() = %q, want %q", got, want)
+	}
+}
@@ -100,9 +100,28 @@ type AppConfig struct {
 	// AppVersion is the current version string for the application.
 	// This may be displayed in the UI or used for update checking in the future.
 	//
-	// Default: "1.0.0"
+	// Default: the current release (see CHANGELOG.md)
 	AppVersion string
 
+	// Profile is the settings profile in use (--profile NAME), shown in the
+	// window title. Empty for the default profile.
+	Profile string
+
+	// TileCacheURL is the base URL of the local tile cache the map loads its
+	// tiles through (see the tilecache package), set by the App once the
+	// cache is started. Empty if it couldn't be started; the map then
+	// loads tiles from the tile server directly.
+	TileCacheURL string
+
+	// ConfigDir is the folder of the profile's settings file, set by the
+	// App at startup (Help → About opens it). Empty if unknown.
+	ConfigDir string
+
+	// OpenPanels are the planning windows the last session of the profile
+	// left open (domain.SessionPanel*), set by the App at startup; the
+	// frontend opens them again when it is shown.
+	OpenPanels []string
+
 	// Settings holds user-configurable preferences.
 	// These are loaded from disk on startup and saved when the user changes them.
 	// See domain.Settings for detailed documentation of each setting.
@@ -115,7 +134,7 @@ type AppConfig struct {
 // preferences have been saved yet. The returned configuration includes:
 //
 //   - Window size: 800x600 pixels (comfortable for desktop use)
-//   - App name/version: "GoGoldenHour" v1.0.0
+//   - App name/version: "GoGoldenHour" v0.1.3
 //   - Settings: domain.DefaultSettings() (see that function for details)
 //
 // The defaults are designed to work well on most systems and provide a good
@@ -125,7 +144,7 @@ func DefaultConfig() AppConfig {
 		WindowWidth:  800,
 		WindowHeight: 600,
 		AppName:      "GoGoldenHour",
-		AppVersion:   "1.0.0",
+		AppVersion:   "0.1.3",
 		Settings:     domain.DefaultSettings(),
 	}
 }
@@ -0,0 +1,370 @@
+// Package coordinates parses positions typed into the location search box.
+//
+// Photographers often copy coordinates from other tools (a GPS, a topo map,
+// a photo's metadata, a shared link) instead of a place name. Such input is
+// recognized and decoded locally, so it neither goes to the geocoding
+// service nor depends on the network. Supported formats:
+//
+//   - Decimal degrees: "48.8566, 2.3522", "-33.86 151.21",
+//     "48.8566° N, 2.3522° E", "N 48.8566 E 2.3522", "geo:48.8566,2.3522"
+//   - Degrees, minutes and seconds: 48°51'24"N 2°21'03"E,
+//     "48 51 24 N 2 21 3 E", or degrees and decimal minutes: 48°51.4'N 2°21.05'E
+//   - MGRS (military grid): "31U DQ 48251 11932", "31UDQ4825111932"
+//   - Plus codes (Open Location Code): "8FW4V75V+8Q", or the short form
+//     "V75V+8Q" near a reference location
+//
+// Without hemisphere letters, the first value is the latitude. With them,
+// either order works ("2.35E 48.85N").
+//
+// Usage:
+//
+//	lat, lon, err := coordinates.Parse(query, currentLocation)
+//	if errors.Is(err, coordinates.ErrNotCoordinates) {
+//	    // search for the text as a place name
+//	}
+package coordinates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// ErrNotCoordinates is returned by Parse for text that isn't written in any
+// of the supported coordinate formats, such as a place name. Other errors
+// mean the text looks like coordinates but can't be used (e.g., a latitude
+// of 95°).
+var ErrNotCoordinates = errors.New("not coordinates")
+
+// =============================================================================
+// Parse
+// =============================================================================
+
+// Parse decodes a position from text in any of the supported formats (see
+// the package docs).
+//
+// Parameters:
+//   - text: The user's input, e.g., `48°51'24"N 2°21'03"E`
+//   - reference: A location near the wanted position, used to complete
+//     short plus codes (normally the current location)
+//
+// Returns the latitude and longitude in decimal degrees, or an error that
+// wraps ErrNotCoordinates if the text isn't coordinates at all.
+func Parse(text string, reference domain.Location) (lat, lon float64, err error) {
+	text = strings.TrimSpace(text)
+	if rest, ok := cutPrefixFold(text, "geo:"); ok {
+		// geo: URIs may carry parameters such as ";u=35"
+		text, _, _ = strings.Cut(rest, ";")
+	}
+	if text == "" {
+		return 0, 0, ErrNotCoordinates
+	}
+
+	switch {
+	case isPlusCode(text):
+		lat, lon, err = parsePlusCode(text, reference)
+	case isMGRS(text):
+		lat, lon, err = parseMGRS(text)
+	default:
+		lat, lon, err = parseDegrees(text)
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	return lat, lon, nil
+}
+
+// cutPrefixFold is strings.CutPrefix ignoring ASCII case.
+func cutPrefixFold(s, prefix string) (string, bool) {
+	if len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+		return s[len(prefix):], true
+	}
+	return s, false
+}
+
+// =============================================================================
+// Degrees
+// =============================================================================
+
+// tokenKind classifies the pieces of a degrees text.
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenHemisphere
+	tokenComma
+)
+
+// Unit markers that may follow a number.
+const (
+	unitNone = iota
+	unitDegrees
+	unitMinutes
+	unitSeconds
+)
+
+// token is one piece of a degrees text.
+type token struct {
+	kind tokenKind
+
+	// value and unit are set for numbers; the value includes the sign.
+	value float64
+	unit  int
+
+	// hemisphere is 'N', 'S', 'E' or 'W' for hemisphere letters.
+	hemisphere rune
+}
+
+// component is one coordinate being assembled from tokens: up to three
+// numbers (degrees, minutes, seconds) and an optional hemisphere letter.
+type component struct {
+	numbers    []float64
+	units      []int
+	hemisphere rune
+}
+
+// parseDegrees parses decimal degrees or degrees/minutes/seconds.
+func parseDegrees(text string) (lat, lon float64, err error) {
+	tokens, ok := tokenize(text)
+	if !ok {
+		return 0, 0, ErrNotCoordinates
+	}
+	numbers := 0
+	for _, t := range tokens {
+		if t.kind == tokenNumber {
+			numbers++
+		}
+	}
+	if numbers < 2 {
+		return 0, 0, ErrNotCoordinates
+	}
+
+	// From here on the text is clearly meant as coordinates, so problems
+	// are reported rather than passed on to place name search
+	components, err := group(tokens)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(components) != 2 {
+		return 0, 0, errors.New("expected a latitude and a longitude")
+	}
+
+	first, second := components[0], components[1]
+	if isLongitudeHemisphere(first.hemisphere) || isLatitudeHemisphere(second.hemisphere) {
+		first, second = second, first
+	}
+	if isLongitudeHemisphere(first.hemisphere) || isLatitudeHemisphere(second.hemisphere) {
+		return 0, 0, errors.New("both values are in the same direction (N/S or E/W)")
+	}
+
+	if lat, err = first.degrees(); err != nil {
+		return 0, 0, fmt.Errorf("latitude: %w", err)
+	}
+	if lon, err = second.degrees(); err != nil {
+		return 0, 0, fmt.Errorf("longitude: %w", err)
+	}
+	if math.Abs(lat) > 90 {
+		return 0, 0, fmt.Errorf("latitude %g is out of range (-90 to 90)", lat)
+	}
+	if math.Abs(lon) > 180 {
+		return 0, 0, fmt.Errorf("longitude %g is out of range (-180 to 180)", lon)
+	}
+	return lat, lon, nil
+}
+
+// tokenize splits a degrees text into numbers, hemisphere letters and
+// commas. Returns ok=false if the text contains anything else, such as
+// words of a place name.
+func tokenize(text string) (tokens []token, ok bool) {
+	runes := []rune(strings.ToUpper(text))
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r) || r == ';':
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokenComma})
+			i++
+		case r == 'N' || r == 'S' || r == 'E' || r == 'W':
+			// A lone letter, not the start of a word such as "NEW"
+			if i+1 < len(runes) && unicode.IsLetter(runes[i+1]) {
+				return nil, false
+			}
+			tokens = append(tokens, token{kind: tokenHemisphere, hemisphere: r})
+			i++
+		case r == '-' || r == '+' || r == '−' || r == '.' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			number := strings.Replace(string(runes[start:i]), "−", "-", 1)
+			value, err := strconv.ParseFloat(number, 64)
+			if err != nil {
+				return nil, false
+			}
+			t := token{kind: tokenNumber, value: value}
+
+			// Unit marker, possibly after a space
+			j := i
+			for j < len(runes) && runes[j] == ' ' {
+				j++
+			}
+			if j < len(runes) {
+				switch runes[j] {
+				case '°', 'º', '˚':
+					t.unit, i = unitDegrees, j+1
+				case '\'', '′', '’', '‘':
+					t.unit, i = unitMinutes, j+1
+					// Two single quotes are often typed for seconds
+					if i < len(runes) && (runes[i] == '\'' || runes[i] == '′') {
+						t.unit, i = unitSeconds, i+1
+					}
+				case '"', '″', '”', '“':
+					t.unit, i = unitSeconds, j+1
+				}
+			}
+			tokens = append(tokens, t)
+		default:
+			return nil, false
+		}
+	}
+	return tokens, true
+}
+
+// group assembles tokens into coordinate components.
+//
+// A component ends at a comma, after a trailing hemisphere letter, before
+// a leading one, or when a number can't continue it (a second degrees
+// value, or a number after seconds). Unmarked numbers that end up in a
+// single component ("48.85 2.35", "48 51 24 2 21 3") are split in half.
+func group(tokens []token) ([]component, error) {
+	var components []component
+	var current component
+	leading := false // current.hemisphere came before its numbers
+	flush := func() {
+		if len(current.numbers) > 0 || current.hemisphere != 0 {
+			components = append(components, current)
+		}
+		current, leading = component{}, false
+	}
+
+	for _, t := range tokens {
+		switch t.kind {
+		case tokenComma:
+			flush()
+		case tokenHemisphere:
+			switch {
+			case len(current.numbers) == 0 && current.hemisphere == 0:
+				current.hemisphere, leading = t.hemisphere, true
+			case len(current.numbers) > 0 && current.hemisphere == 0:
+				current.hemisphere = t.hemisphere
+				flush()
+			case len(current.numbers) > 0 && leading:
+				flush()
+				current.hemisphere, leading = t.hemisphere, true
+			default:
+				return nil, errors.New("misplaced N/S/E/W")
+			}
+		case tokenNumber:
+			// A degrees value, or a number after marked seconds, starts the
+			// next component. Unmarked numbers stay together until they
+			// are split at the end.
+			if len(current.numbers) > 0 && (t.unit == unitDegrees || current.last() == unitSeconds) {
+				flush()
+			}
+			current.numbers = append(current.numbers, t.value)
+			current.units = append(current.units, t.unit)
+		}
+	}
+	flush()
+
+	if len(components) == 1 {
+		return splitUnmarked(components[0]), nil
+	}
+	return components, nil
+}
+
+// last returns the unit of the component's last number.
+func (c component) last() int {
+	if len(c.units) == 0 {
+		return unitNone
+	}
+	return c.units[len(c.units)-1]
+}
+
+// splitUnmarked splits a component of only unmarked numbers in two halves.
+func splitUnmarked(c component) []component {
+	n := len(c.numbers)
+	if c.hemisphere != 0 || n%2 != 0 {
+		return []component{c}
+	}
+	for _, u := range c.units {
+		if u != unitNone {
+			return []component{c}
+		}
+	}
+	half := n / 2
+	return []component{
+		{numbers: c.numbers[:half], units: c.units[:half]},
+		{numbers: c.numbers[half:], units: c.units[half:]},
+	}
+}
+
+// degrees returns the component's value in signed decimal degrees.
+func (c component) degrees() (float64, error) {
+	if len(c.numbers) == 0 {
+		return 0, errors.New("no value")
+	}
+	if len(c.numbers) > 3 {
+		return 0, errors.New("too many numbers")
+	}
+
+	// Units must follow the degrees/minutes/seconds order where given
+	for i, u := range c.units {
+		if u != unitNone && u != i+1 {
+			return 0, errors.New("degrees, minutes and seconds are out of order")
+		}
+	}
+
+	for _, n := range c.numbers[:len(c.numbers)-1] {
+		if n != math.Trunc(n) {
+			return 0, errors.New("only the last value may have decimals")
+		}
+	}
+
+	negative := math.Signbit(c.numbers[0])
+	value := math.Abs(c.numbers[0])
+	for i, part := range c.numbers[1:] {
+		if math.Signbit(part) || part >= 60 {
+			return 0, fmt.Errorf("%g is not a valid number of minutes or seconds", part)
+		}
+		value += part / math.Pow(60, float64(i+1))
+	}
+
+	if c.hemisphere == 'S' || c.hemisphere == 'W' {
+		if negative {
+			return 0, errors.New("both a minus sign and S/W given")
+		}
+		negative = true
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// isLatitudeHemisphere reports whether h is N or S.
+func isLatitudeHemisphere(h rune) bool {
+	return h == 'N' || h == 'S'
+}
+
+// isLongitudeHemisphere reports whether h is E or W.
+func isLongitudeHemisphere(h rune) bool {
+	return h == 'E' || h == 'W'
+}
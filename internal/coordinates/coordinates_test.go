@@ -0,0 +1,124 @@
+package coordinates
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestParse(t *testing.T) {
+	paris := domain.Location{Latitude: 48.85, Longitude: 2.35}
+
+	tests := []struct {
+		text     string
+		lat, lon float64
+		// tolerance in degrees (0: 1e-6)
+		tolerance float64
+	}{
+		// Decimal degrees
+		{text: "48.8566, 2.3522", lat: 48.8566, lon: 2.3522},
+		{text: "48.8566 2.3522", lat: 48.8566, lon: 2.3522},
+		{text: "-33.8568,151.2153", lat: -33.8568, lon: 151.2153},
+		{text: "48.8566° N, 2.3522° W", lat: 48.8566, lon: -2.3522},
+		{text: "N 48.8566 E 2.3522", lat: 48.8566, lon: 2.3522},
+		{text: "2.3522E 48.8566N", lat: 48.8566, lon: 2.3522},
+		{text: "33.8568s 151.2153e", lat: -33.8568, lon: 151.2153},
+		{text: "geo:48.8566,2.3522;u=35", lat: 48.8566, lon: 2.3522},
+		{text: "−33.8568 −70.5", lat: -33.8568, lon: -70.5},
+
+		// Degrees, minutes and seconds
+		{text: `48°51'24"N 2°21'03"E`, lat: 48.856667, lon: 2.350833, tolerance: 1e-5},
+		{text: `48°51′24″N, 2°21′03″E`, lat: 48.856667, lon: 2.350833, tolerance: 1e-5},
+		{text: `48° 51' 24'' S 2° 21' 3'' W`, lat: -48.856667, lon: -2.350833, tolerance: 1e-5},
+		{text: "48 51 24 N 2 21 3 E", lat: 48.856667, lon: 2.350833, tolerance: 1e-5},
+		{text: "48 51 24 2 21 3", lat: 48.856667, lon: 2.350833, tolerance: 1e-5},
+		{text: "48 51.4 2 21.05", lat: 48.856667, lon: 2.350833, tolerance: 1e-5},
+		{text: `48°51.4'N 2°21.05'E`, lat: 48.856667, lon: 2.350833, tolerance: 1e-5},
+
+		// MGRS (Eiffel Tower, Sydney Opera House); 1 m is about 1e-5°
+		{text: "31U DQ 48251 11932", lat: 48.85820, lon: 2.29450, tolerance: 2e-5},
+		{text: "31udq4825111932", lat: 48.85820, lon: 2.29450, tolerance: 2e-5},
+		{text: "56H LH 34873 52303", lat: -33.8568, lon: 151.2153, tolerance: 2e-3},
+		{text: "31U DQ 4 1", lat: 48.86, lon: 2.30, tolerance: 0.1},
+
+		// MGRS by the antimeridian: the central meridians of zones 1 and
+		// 60, and polar squares reaching past 180° (wrapped around)
+		{text: "1N EA 00000 00000", lat: 0, lon: -177, tolerance: 2e-5},
+		{text: "60N WF 00000 00000", lat: 0, lon: 177, tolerance: 2e-5},
+		{text: "1CAA00", lat: -71.7202, lon: 171.6572, tolerance: 1e-3},
+		{text: "1X AA 0 0", lat: 71.8081, lon: 171.6035, tolerance: 1e-3},
+		{text: "60C ZA 0 0", lat: -76.2667, lon: -171.4208, tolerance: 1e-3},
+		{text: "60X ZF 0 0", lat: 71.9429, lon: -174.1556, tolerance: 1e-3},
+
+		// Plus codes
+		{text: "8FW4V75V+8Q", lat: 48.8583125, lon: 2.2944375},
+		{text: "8fw4v75v+8q", lat: 48.8583125, lon: 2.2944375},
+		{text: "8FW40000+", lat: 48.5, lon: 2.5},
+		{text: "V75V+8Q", lat: 48.8583125, lon: 2.2944375},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			lat, lon, err := Parse(tt.text, paris)
+			if err != nil {
+				t.Fatalf("Parse() error: %v", err)
+			}
+			tolerance := tt.tolerance
+			if tolerance == 0 {
+				tolerance = 1e-6
+			}
+			if math.Abs(lat-tt.lat) > tolerance || math.Abs(lon-tt.lon) > tolerance {
+				t.Errorf("Parse() = %.6f, %.6f, want %.6f, %.6f", lat, lon, tt.lat, tt.lon)
+			}
+		})
+	}
+}
+
+func TestParseShortCodeNearCellEdge(t *testing.T) {
+	// The reference's own 1° cell (49° N) is farther from the code's area
+	// than the cell below it
+	reference := domain.Location{Latitude: 49.2, Longitude: 2.5}
+	lat, lon, err := Parse("V75V+8Q", reference)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if math.Abs(lat-48.8583125) > 1e-6 || math.Abs(lon-2.2944375) > 1e-6 {
+		t.Errorf("Parse() = %.6f, %.6f, want the area nearest the reference", lat, lon)
+	}
+}
+
+func TestParseNotCoordinates(t *testing.T) {
+	for _, text := range []string{
+		"", "Paris", "New York", "10 Downing Street", "E1 6AN",
+		"Route 66", "N1", "St. Ives", "A-7", "31U DI 48251 11932",
+	} {
+		if _, _, err := Parse(text, domain.Location{}); !errors.Is(err, ErrNotCoordinates) {
+			t.Errorf("Parse(%q) error = %v, want ErrNotCoordinates", text, err)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, text := range []string{
+		"95.0, 10.0",         // latitude out of range
+		"45.0, 190.0",        // longitude out of range
+		"48.85N 2.35S",       // two latitudes
+		"-48.85S 2.35E",      // sign and hemisphere
+		`48°75'N 2°21'E`,     // 75 minutes
+		"48.5 30 2 21",       // decimals before minutes
+		"1.0, 2.0, 3.0",      // three values
+		"31U DQ 4825 119",    // uneven digits
+		"31U DK 48251 11932", // square not in the band
+		"61U DQ 48251 11932", // no zone 61
+		"8FW4V75V+8",         // single digit after +
+		"XXW4V75V+8Q",        // beyond the pole
+		"8FW400V5+",          // digits after padding
+	} {
+		_, _, err := Parse(text, domain.Location{Latitude: 48.85, Longitude: 2.35})
+		if err == nil || errors.Is(err, ErrNotCoordinates) {
+			t.Errorf("Parse(%q) error = %v, want a coordinates error", text, err)
+		}
+	}
+}
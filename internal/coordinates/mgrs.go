@@ -0,0 +1,181 @@
+package coordinates
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// MGRS
+// =============================================================================
+
+// MGRS (Military Grid Reference System) references, as printed on many
+// topographic maps and shown by hiking GPS units, name a square of the UTM
+// grid:
+//
+//	31U DQ 48251 11932
+//	│ │ │  │     └ northing within the square (same number of digits)
+//	│ │ │  └ easting within the 100 km square (1 to 5 digits)
+//	│ │ └ 100 km square: column and row letters
+//	│ └ latitude band (8° bands from C at 80°S to X at 72°N-84°N)
+//	└ UTM zone (6° of longitude)
+//
+// The polar regions (UPS, bands A, B, Y and Z) are not supported; nobody
+// photographs a sunset there from a grid reference.
+
+// mgrsPattern matches an MGRS reference with the spaces removed.
+var mgrsPattern = regexp.MustCompile(`^(\d{1,2})([C-HJ-NP-X])([A-HJ-NP-Z])([A-HJ-NP-V])(\d*)$`)
+
+// Letter sequences of the MGRS grid (I and O are never used).
+const (
+	mgrsBands = "CDEFGHJKLMNPQRSTUVWX"
+
+	// mgrsRows are the 100 km row letters, repeating every 2000 km.
+	mgrsRows = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// mgrsColumns are the 100 km column letters, by zone (zone-1)%3.
+var mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// WGS84 ellipsoid and UTM projection constants.
+const (
+	wgs84A = 6378137.0
+	wgs84F = 1 / 298.257223563
+	utmK0  = 0.9996
+
+	// utmFalseEasting and utmFalseNorthingSouth keep grid values positive.
+	utmFalseEasting       = 500000.0
+	utmFalseNorthingSouth = 10000000.0
+)
+
+// isMGRS reports whether text has the shape of an MGRS reference.
+func isMGRS(text string) bool {
+	return mgrsPattern.MatchString(compactUpper(text))
+}
+
+// compactUpper removes white space and upper-cases text.
+func compactUpper(text string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(text), ""))
+}
+
+// parseMGRS converts an MGRS reference to the center of its square.
+func parseMGRS(text string) (lat, lon float64, err error) {
+	m := mgrsPattern.FindStringSubmatch(compactUpper(text))
+	zone, _ := strconv.Atoi(m[1])
+	band, column, row, digits := m[2][0], m[3][0], m[4][0], m[5]
+	if zone < 1 || zone > 60 {
+		return 0, 0, fmt.Errorf("MGRS zone %d is out of range (1 to 60)", zone)
+	}
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return 0, 0, fmt.Errorf("MGRS reference needs 0 to 10 digits, evenly split, not %d", len(digits))
+	}
+
+	// 100 km square: the column letters cycle through three sets by zone,
+	// the row letters start 5 letters later in even zones
+	columnIndex := strings.IndexByte(mgrsColumns[(zone-1)%3], column)
+	if columnIndex < 0 {
+		return 0, 0, fmt.Errorf("MGRS column letter %c isn't used in zone %d", column, zone)
+	}
+	rowIndex := strings.IndexByte(mgrsRows, row)
+	if zone%2 == 0 {
+		rowIndex = (rowIndex + len(mgrsRows) - 5) % len(mgrsRows)
+	}
+	easting := float64(columnIndex+1) * 100000
+	northing := float64(rowIndex) * 100000
+
+	// Position within the square, to the center of the given precision
+	precision := len(digits) / 2
+	size := math.Pow(10, float64(5-precision))
+	if precision > 0 {
+		e, _ := strconv.Atoi(digits[:precision])
+		n, _ := strconv.Atoi(digits[precision:])
+		easting += float64(e) * size
+		northing += float64(n) * size
+	}
+	easting += size / 2
+	northing += size / 2
+
+	// The row letters repeat every 2000 km; the latitude band tells which
+	// repetition is meant. Northing at a latitude is lowest on the zone's
+	// central meridian, so the band's southern edge there is a lower bound
+	// (less one square, for references rounded down to a coarse square).
+	bandIndex := strings.IndexByte(mgrsBands, band)
+	bandSouth := -80 + 8*float64(bandIndex)
+	bandNorth := bandSouth + 8
+	if band == 'X' {
+		bandNorth = 84
+	}
+	southern := bandSouth < 0
+	minNorthing := utmK0 * meridianArc(bandSouth*math.Pi/180)
+	if southern {
+		minNorthing += utmFalseNorthingSouth
+	}
+	for northing < minNorthing-100000 {
+		northing += 2000000
+	}
+
+	lat, lon = utmToLatLon(zone, southern, easting, northing)
+	// Half a degree of slack for squares straddling the band's edges
+	if lat < bandSouth-0.5 || lat > bandNorth+0.5 {
+		return 0, 0, fmt.Errorf("MGRS square %c%c is not in latitude band %c", column, row, band)
+	}
+	return lat, lon, nil
+}
+
+// meridianArc returns the distance along the WGS84 meridian from the
+// equator to latitude phi (in radians), in meters.
+func meridianArc(phi float64) float64 {
+	e2 := wgs84F * (2 - wgs84F)
+	e4, e6 := e2*e2, e2*e2*e2
+	return wgs84A * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+}
+
+// utmToLatLon converts UTM grid coordinates to latitude and longitude in
+// degrees (inverse transverse Mercator, Snyder's series; accurate to well
+// under a meter within a zone).
+//
+// The longitude is normalized to [-180, 180): near the poles the zone's
+// outer squares reach past the antimeridian (zone 1's west edge, zone 60's
+// east edge), which would otherwise give e.g. -188°.
+func utmToLatLon(zone int, southern bool, easting, northing float64) (lat, lon float64) {
+	e2 := wgs84F * (2 - wgs84F)
+	e4, e6 := e2*e2, e2*e2*e2
+	ep2 := e2 / (1 - e2)
+
+	x := easting - utmFalseEasting
+	y := northing
+	if southern {
+		y -= utmFalseNorthingSouth
+	}
+
+	// Footpoint latitude
+	mu := y / utmK0 / (wgs84A * (1 - e2/4 - 3*e4/64 - 5*e6/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+	phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+	sin1, cos1, tan1 := math.Sin(phi1), math.Cos(phi1), math.Tan(phi1)
+	n1 := wgs84A / math.Sqrt(1-e2*sin1*sin1)
+	t1 := tan1 * tan1
+	c1 := ep2 * cos1 * cos1
+	r1 := wgs84A * (1 - e2) / math.Pow(1-e2*sin1*sin1, 1.5)
+	d := x / (n1 * utmK0)
+
+	phi := phi1 - (n1*tan1/r1)*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+	lambda := (d - (1+2*t1+c1)*math.Pow(d, 3)/6 +
+		(5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120) / cos1
+
+	centralMeridian := float64(zone-1)*6 - 180 + 3
+	lon = centralMeridian + lambda*180/math.Pi
+	return phi * 180 / math.Pi, math.Mod(lon+540, 360) - 180
+}
@@ -0,0 +1,187 @@
+package coordinates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Plus Codes
+// =============================================================================
+
+// Plus codes (Open Location Code, as shown by Google Maps) name a small
+// rectangle with digits from a 20-character alphabet:
+//
+//	8FW4V75V+8Q
+//	└──────┘ └┘
+//	 pairs    after "+": a fifth pair, then optional grid refinement
+//
+// Each pair of digits narrows the latitude and longitude 20-fold (20°, 1°,
+// 0.05°, ...). Short codes leave out the first pairs ("V75V+8Q"), which are
+// taken from a nearby reference location.
+
+// Plus code alphabet and layout.
+const (
+	plusCodeAlphabet = "23456789CFGHJMPQRVWX"
+
+	// plusCodeSeparator follows the first eight digits of a full code.
+	plusCodeSeparator         = '+'
+	plusCodeSeparatorPosition = 8
+
+	// plusCodePadding fills the omitted digits of low-precision codes
+	// ("8FW40000+").
+	plusCodePadding = '0'
+
+	// plusCodePairDigits is the number of digits encoded as pairs.
+	plusCodePairDigits = 10
+
+	// Grid refinement after the pairs: 5 rows by 4 columns per digit.
+	plusCodeGridRows    = 5
+	plusCodeGridColumns = 4
+
+	// plusCodeMaxDigits is the longest code decoded (about 1 cm).
+	plusCodeMaxDigits = 15
+)
+
+// isPlusCode reports whether text has the shape of a full or short plus
+// code: alphabet digits (or padding) with one separator.
+func isPlusCode(text string) bool {
+	code := strings.ToUpper(strings.TrimSpace(text))
+	sep := strings.IndexByte(code, plusCodeSeparator)
+	if sep < 0 || strings.Count(code, string(plusCodeSeparator)) != 1 || sep > plusCodeSeparatorPosition {
+		return false
+	}
+	for _, r := range code {
+		if r != plusCodeSeparator && r != plusCodePadding && !strings.ContainsRune(plusCodeAlphabet, r) {
+			return false
+		}
+	}
+	return sep%2 == 0 && sep >= 2
+}
+
+// parsePlusCode decodes a plus code to the center of its area.
+//
+// Short codes are completed with the leading digits of the reference
+// location, picking the nearest of the candidate areas.
+func parsePlusCode(text string, reference domain.Location) (lat, lon float64, err error) {
+	code := strings.ToUpper(strings.TrimSpace(text))
+	sep := strings.IndexByte(code, plusCodeSeparator)
+
+	if sep < plusCodeSeparatorPosition {
+		if strings.ContainsRune(code, plusCodePadding) {
+			return 0, 0, errors.New("short plus codes can't contain padding")
+		}
+		return recoverShortCode(code, sep, reference)
+	}
+	return decodePlusCode(code)
+}
+
+// decodePlusCode decodes a full plus code to the center of its area.
+func decodePlusCode(code string) (lat, lon float64, err error) {
+	digits := strings.Replace(code, string(plusCodeSeparator), "", 1)
+
+	// Padding must run up to the separator, and only in whole pairs
+	if pad := strings.IndexByte(digits, plusCodePadding); pad >= 0 {
+		if strings.Trim(digits[pad:], string(plusCodePadding)) != "" || pad%2 != 0 || pad < 2 ||
+			len(code) != plusCodeSeparatorPosition+1 {
+			return 0, 0, fmt.Errorf("invalid plus code %q", code)
+		}
+		digits = digits[:pad]
+	}
+	if len(digits) == plusCodeSeparatorPosition+1 {
+		return 0, 0, fmt.Errorf("plus code %q needs at least two digits after +", code)
+	}
+	digits = digits[:min(len(digits), plusCodeMaxDigits)]
+
+	// South-west corner and size, starting from (-90, -180) in 20° pairs
+	south, west := -90.0, -180.0
+	latSize, lonSize := 400.0, 400.0
+	for i := 0; i < len(digits); i++ {
+		value := float64(strings.IndexByte(plusCodeAlphabet, digits[i]))
+		switch {
+		case i < plusCodePairDigits && i%2 == 0:
+			latSize /= 20
+			lonSize /= 20
+			south += value * latSize
+		case i < plusCodePairDigits:
+			west += value * lonSize
+		default:
+			latSize /= plusCodeGridRows
+			lonSize /= plusCodeGridColumns
+			row := math.Floor(value / plusCodeGridColumns)
+			col := math.Mod(value, plusCodeGridColumns)
+			south += row * latSize
+			west += col * lonSize
+		}
+	}
+
+	// The first digits allow latitudes beyond the poles; those areas are
+	// not valid
+	first := strings.IndexByte(plusCodeAlphabet, digits[0])
+	second := strings.IndexByte(plusCodeAlphabet, digits[1])
+	if first > 8 || second > 17 {
+		return 0, 0, fmt.Errorf("plus code %q is outside the world", code)
+	}
+
+	lat = min(south+latSize/2, 90)
+	lon = west + lonSize/2
+	return lat, lon, nil
+}
+
+// recoverShortCode completes a short plus code (sep digits before the
+// separator) near the reference location.
+func recoverShortCode(code string, sep int, reference domain.Location) (lat, lon float64, err error) {
+	// The omitted pairs are taken from the reference's own code
+	missing := plusCodeSeparatorPosition - sep
+	prefix := encodePairs(reference.Latitude, reference.Longitude, missing)
+	lat, lon, err = decodePlusCode(prefix + code)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// The completed area may be in the neighboring cell of the prefix'
+	// resolution if the reference lies near the cell edge: move by one
+	// cell towards the reference if that is closer
+	resolution := math.Pow(20, 2-float64(missing/2))
+	half := resolution / 2
+	switch {
+	case lat > reference.Latitude+half && lat-resolution >= -90:
+		lat -= resolution
+	case lat < reference.Latitude-half && lat+resolution <= 90:
+		lat += resolution
+	}
+	switch {
+	case lon > reference.Longitude+half:
+		lon -= resolution
+	case lon < reference.Longitude-half:
+		lon += resolution
+	}
+	lon = math.Mod(lon+540, 360) - 180
+	return lat, lon, nil
+}
+
+// encodePairs returns the first digits of the plus code of a location.
+//
+// Only the pair digits needed to complete short codes are produced.
+func encodePairs(lat, lon float64, digits int) string {
+	// Work in positive ranges; the north pole belongs to the cell below it
+	lat = min(max(lat, -90), 90-1e-10) + 90
+	lon = math.Mod(math.Mod(lon+180, 360)+360, 360)
+
+	var b strings.Builder
+	size := 20.0
+	for i := 0; i < digits; i += 2 {
+		latDigit := int(math.Floor(lat / size))
+		lonDigit := int(math.Floor(lon / size))
+		b.WriteByte(plusCodeAlphabet[latDigit])
+		b.WriteByte(plusCodeAlphabet[lonDigit])
+		lat -= float64(latDigit) * size
+		lon -= float64(lonDigit) * size
+		size /= 20
+	}
+	return b.String()
+}
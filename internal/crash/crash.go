@@ -0,0 +1,294 @@
+// Package crash turns panics into crash reports instead of silent exits.
+//
+// A panic in a goroutine or a Qt callback would end the whole process with
+// a stack trace on a terminal the user never sees. The wrappers in this
+// package recover the panic instead, write a crash report to the crash
+// folder and hand it to the frontend, which offers to open the report or
+// to file it as a GitHub issue:
+//
+//	crash.Go(func() { ... })              // a goroutine
+//	action.OnTriggered(crash.Guard(fn))   // a Qt callback
+//
+//	panic ──► Recover ──► Write ──► crash-20261014-153000.txt
+//	                        │
+//	                        ▼
+//	                   handler (SetHandler): the crash dialog
+//
+// The app keeps running after a recovered panic: the failed action is
+// lost, the rest still works. Panics the wrappers don't reach (e.g., in a
+// callback without Guard) still end the process; with EnableFatalReports
+// the runtime writes them to the crash folder, and the next start shows
+// them (see TakeFatalReport).
+//
+// Nothing is sent anywhere: the user decides whether to submit a report.
+package crash
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+)
+
+// =============================================================================
+// Reports
+// =============================================================================
+
+const (
+	// crashDirName is the crash folder, next to the settings (see
+	// DefaultDir).
+	crashDirName = "crashes"
+
+	// fatalFileName is the file the runtime writes fatal crashes to (see
+	// EnableFatalReports).
+	fatalFileName = "fatal.txt"
+
+	// issuesURL opens a new GitHub issue.
+	issuesURL = "https://github.com/megatih/GoGoldenHour/issues/new"
+
+	// issueStackLines is how much of the stack is put in a new issue; the
+	// URL must stay short enough for browsers, so the rest is left to the
+	// attached report.
+	issueStackLines = 40
+)
+
+// Report describes a crash.
+type Report struct {
+	// Time is when the crash happened.
+	Time time.Time
+
+	// Version is the application version; OS and Arch the platform, and
+	// GoVersion the Go release it was built with.
+	Version   string
+	OS        string
+	Arch      string
+	GoVersion string
+
+	// Panic is the panic's value, e.g., "runtime error: index out of range".
+	Panic string
+
+	// Stack is the stack trace of the goroutine that panicked.
+	Stack string
+}
+
+// NewReport describes a panic of this process.
+//
+// Parameters:
+//   - value: The value recovered from the panic
+//   - stack: The stack trace (debug.Stack, taken in the deferred function)
+func NewReport(value any, stack []byte) Report {
+	return Report{
+		Time:      time.Now(),
+		Version:   config.DefaultConfig().AppVersion,
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		GoVersion: runtime.Version(),
+		Panic:     fmt.Sprint(value),
+		Stack:     string(stack),
+	}
+}
+
+// Header returns the lines naming the version and platform, e.g.:
+//
+//	GoGoldenHour 0.1.3 (linux/amd64, go1.24.2)
+//	Crashed at 2026-10-14 15:30:00 +0200
+func (r Report) Header() string {
+	return fmt.Sprintf("GoGoldenHour %s (%s/%s, %s)\nCrashed at %s\n",
+		r.Version, r.OS, r.Arch, r.GoVersion, r.Time.Format("2006-01-02 15:04:05 -0700"))
+}
+
+// Text returns the report as written to its file: the header, the panic
+// and the stack trace.
+func (r Report) Text() string {
+	return r.Header() + "\npanic: " + r.Panic + "\n\n" + r.Stack
+}
+
+// IssueURL returns the address of a new GitHub issue filled in with the
+// report: the panic as the title, the header and the top of the stack as
+// the body. Opening it only shows the form; the user submits it.
+func (r Report) IssueURL() string {
+	stack := strings.Split(strings.TrimSpace(r.Stack), "\n")
+	if len(stack) > issueStackLines {
+		stack = append(stack[:issueStackLines], "...")
+	}
+	body := "**What I was doing:**\n\n\n" +
+		"**Crash report** (the full report is in the crash folder):\n\n```\n" +
+		r.Header() + "\npanic: " + r.Panic + "\n\n" + strings.Join(stack, "\n") + "\n```\n"
+	title := []rune("Crash: " + r.Panic)
+	if len(title) > 120 {
+		title = append(title[:120], '…')
+	}
+	return issuesURL + "?" + url.Values{"title": {string(title)}, "body": {body}}.Encode()
+}
+
+// DefaultDir returns the crash folder: crashes in the application's config
+// folder (e.g., ~/.config/GoGoldenHour/crashes on Linux), shared by all
+// profiles. Returns an empty string if the platform has no config folder.
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "GoGoldenHour", crashDirName)
+}
+
+// Write saves a report to dir as crash-<time>.txt, creating dir if needed.
+//
+// Returns the report's path, or an error if it couldn't be written.
+func Write(dir string, r Report) (string, error) {
+	if dir == "" {
+		return "", errors.New("no crash folder")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create crash folder: %w", err)
+	}
+	path := filepath.Join(dir, "crash-"+r.Time.Format("20060102-150405.000")+".txt")
+	if err := os.WriteFile(path, []byte(r.Text()), 0644); err != nil {
+		return "", fmt.Errorf("failed to write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// =============================================================================
+// Recovery
+// =============================================================================
+
+// handler is where recovered panics go (see SetHandler).
+var handler struct {
+	mu  sync.Mutex
+	dir string
+	fn  func(report Report, path string)
+}
+
+// SetHandler sets the folder reports are written to and the function
+// called with each recovered panic, e.g., to show the crash dialog.
+//
+// fn is called on the goroutine that panicked, after the report is
+// written (path is empty if it couldn't be), so a frontend must hand it to
+// its main thread. Without a handler, the report's path is logged.
+//
+// Parameters:
+//   - dir: The crash folder (DefaultDir)
+//   - fn: Called with each report, or nil
+func SetHandler(dir string, fn func(report Report, path string)) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	handler.dir, handler.fn = dir, fn
+}
+
+// Recover recovers a panic, writes its report and calls the handler. It
+// must be deferred directly:
+//
+//	defer crash.Recover()
+//
+// The function it is deferred in returns normally after the report.
+func Recover() {
+	value := recover()
+	if value == nil {
+		return
+	}
+	report := NewReport(value, debug.Stack())
+
+	handler.mu.Lock()
+	dir, fn := handler.dir, handler.fn
+	handler.mu.Unlock()
+
+	path, err := Write(dir, report)
+	if err != nil {
+		log.Printf("Crash report not saved: %v\n%s", err, report.Text())
+	} else {
+		log.Printf("Recovered from a crash (%s), report saved to %s", report.Panic, path)
+	}
+	if fn != nil {
+		fn(report, path)
+	}
+}
+
+// Go runs fn in a new goroutine, with panics reported rather than ending
+// the process.
+func Go(fn func()) {
+	go func() {
+		defer Recover()
+		fn()
+	}()
+}
+
+// Guard wraps fn, e.g., a Qt callback, so its panics are reported rather
+// than ending the process.
+func Guard(fn func()) func() {
+	return func() {
+		defer Recover()
+		fn()
+	}
+}
+
+// =============================================================================
+// Fatal Crashes
+// =============================================================================
+
+// EnableFatalReports makes the runtime write crashes that end the process
+// (panics no wrapper recovered, fatal runtime errors) to fatal.txt in dir,
+// for TakeFatalReport on the next start.
+//
+// Call it early in main, after TakeFatalReport (the file is emptied).
+// Returns an error if the file can't be created; crashes then go to the
+// standard error only.
+func EnableFatalReports(dir string) error {
+	if dir == "" {
+		return errors.New("no crash folder")
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create crash folder: %w", err)
+	}
+	f, err := os.Create(filepath.Join(dir, fatalFileName))
+	if err != nil {
+		return fmt.Errorf("failed to create crash file: %w", err)
+	}
+	defer f.Close() // the runtime keeps its own copy
+	if err := debug.SetCrashOutput(f, debug.CrashOptions{}); err != nil {
+		return fmt.Errorf("failed to set crash output: %w", err)
+	}
+	return nil
+}
+
+// TakeFatalReport returns the crash that ended the last run, if the
+// runtime wrote one (see EnableFatalReports), and keeps it in dir as a
+// crash-<time>.txt report.
+//
+// The runtime's output is the panic and the stacks of all goroutines;
+// the report gets this process's version and platform, which are the
+// crashed run's unless the app was updated in between.
+//
+// Returns the report and its path, or ok false if the last run didn't
+// crash.
+func TakeFatalReport(dir string) (report Report, path string, ok bool) {
+	fatal := filepath.Join(dir, fatalFileName)
+	info, err := os.Stat(fatal)
+	if dir == "" || err != nil || info.Size() == 0 {
+		return Report{}, "", false
+	}
+	data, err := os.ReadFile(fatal)
+	if err != nil {
+		return Report{}, "", false
+	}
+	os.Remove(fatal)
+
+	output := strings.TrimSpace(string(data))
+	first, rest, _ := strings.Cut(output, "\n")
+	report = NewReport(strings.TrimPrefix(first, "panic: "), []byte(strings.TrimSpace(rest)))
+	report.Time = info.ModTime()
+	path, err = Write(dir, report)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return report, path, true
+}
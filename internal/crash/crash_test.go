@@ -0,0 +1,97 @@
+package crash
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRecover(t *testing.T) {
+	dir := t.TempDir()
+	reports := make(chan Report, 1)
+	paths := make(chan string, 1)
+	SetHandler(dir, func(report Report, path string) {
+		reports <- report
+		paths <- path
+	})
+	defer SetHandler("", nil)
+
+	Go(func() {
+		var favorites []string
+		_ = favorites[3]
+	})
+	report, path := <-reports, <-paths
+
+	if !strings.Contains(report.Panic, "index out of range") {
+		t.Errorf("Panic = %q, want the index error", report.Panic)
+	}
+	if !strings.Contains(report.Stack, "TestRecover") {
+		t.Errorf("Stack doesn't name the panicking function:\n%s", report.Stack)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("report not written: %v", err)
+	}
+	for _, want := range []string{"GoGoldenHour " + report.Version, report.OS + "/" + report.Arch, "panic: " + report.Panic} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("report lacks %q:\n%s", want, data)
+		}
+	}
+}
+
+func TestGuard(t *testing.T) {
+	SetHandler(t.TempDir(), nil)
+	defer SetHandler("", nil)
+
+	ran := false
+	Guard(func() {
+		ran = true
+		panic("menu action failed")
+	})()
+	if !ran {
+		t.Error("guarded function didn't run")
+	}
+}
+
+func TestIssueURL(t *testing.T) {
+	report := NewReport("boom", []byte(strings.Repeat("frame\n", 100)))
+	u, err := url.Parse(report.IssueURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if got := query.Get("title"); got != "Crash: boom" {
+		t.Errorf("title = %q, want %q", got, "Crash: boom")
+	}
+	body := query.Get("body")
+	if !strings.Contains(body, "panic: boom") || strings.Count(body, "frame") != issueStackLines {
+		t.Errorf("body = %q, want the panic and %d frames", body, issueStackLines)
+	}
+}
+
+func TestTakeFatalReport(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, ok := TakeFatalReport(dir); ok {
+		t.Error("report without a crash")
+	}
+
+	output := "panic: assignment to entry in nil map\n\ngoroutine 1 [running]:\nmain.main()\n"
+	if err := os.WriteFile(filepath.Join(dir, fatalFileName), []byte(output), 0644); err != nil {
+		t.Fatal(err)
+	}
+	report, path, ok := TakeFatalReport(dir)
+	if !ok {
+		t.Fatal("crash not found")
+	}
+	if report.Panic != "assignment to entry in nil map" || !strings.HasPrefix(report.Stack, "goroutine 1") {
+		t.Errorf("report = %q / %q", report.Panic, report.Stack)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("report not kept: %v", err)
+	}
+	if _, _, ok := TakeFatalReport(dir); ok {
+		t.Error("the same crash reported twice")
+	}
+}
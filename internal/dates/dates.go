@@ -0,0 +1,276 @@
+// Package dates reads dates typed in everyday words and describes dates
+// relative to today.
+//
+// Planning a shoot usually starts from "this Saturday" or "in two weeks"
+// rather than from a calendar date, so the date panel accepts such input
+// and shows how far away the selected date is. Supported input (case is
+// ignored):
+//
+//   - Days: "today", "tomorrow", "yesterday"
+//   - Weekdays: "saturday" or "sat" (the coming one, today included),
+//     "next saturday" (after today), "last saturday" (before today)
+//   - Offsets: "in 3 days", "in a week", "2 months ago", "+5", "-2" (days),
+//     "next week", "last month"
+//   - Dates: "2026-07-04", "july 4", "4 jul 2026"; without a year, the
+//     next such day (today included)
+//
+// Usage:
+//
+//	date, err := dates.Parse("next saturday", time.Now())
+//	label := dates.Describe(date, time.Now()) // e.g., "in 5 days"
+package dates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotDate is returned by Parse for text that isn't written in any of
+// the supported forms.
+var ErrNotDate = errors.New("not a recognized date")
+
+// maxOffset limits offsets such as "in 9999 years", which time.AddDate
+// would accept but no photographer plans for.
+const maxOffset = 10000
+
+// weekdays maps weekday names and their three-letter abbreviations.
+var weekdays = map[string]time.Weekday{}
+
+// months maps month names and their three-letter abbreviations ("sept"
+// is common enough to accept as well).
+var months = map[string]time.Month{"sept": time.September}
+
+func init() {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		weekdays[name] = d
+		weekdays[name[:3]] = d
+	}
+	for m := time.January; m <= time.December; m++ {
+		name := strings.ToLower(m.String())
+		months[name] = m
+		months[name[:3]] = m
+	}
+}
+
+// Parse reads a date typed in words or numbers (see the package docs).
+//
+// Parameters:
+//   - text: The typed text
+//   - today: The current date; its time zone is used for the result
+//
+// Returns the date at midnight, or an error wrapping ErrNotDate if the text
+// isn't a date. A date that reads right but doesn't exist (February 30) is
+// an error too.
+func Parse(text string, today time.Time) (time.Time, error) {
+	today = midnight(today)
+	words := strings.Fields(strings.ToLower(strings.ReplaceAll(text, ",", " ")))
+	if len(words) == 0 {
+		return time.Time{}, fmt.Errorf("%q: %w", text, ErrNotDate)
+	}
+
+	switch strings.Join(words, " ") {
+	case "today", "now":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if date, ok := parseWeekday(words, today); ok {
+		return date, nil
+	}
+	if date, ok := parseOffset(words, today); ok {
+		return date, nil
+	}
+	if len(words) == 1 {
+		if date, err := time.ParseInLocation("2006-01-02", words[0], today.Location()); err == nil {
+			return date, nil
+		}
+	}
+	return parseMonthDay(text, words, today)
+}
+
+// parseWeekday reads "saturday", "this saturday", "next saturday" and
+// "last saturday".
+func parseWeekday(words []string, today time.Time) (time.Time, bool) {
+	modifier := ""
+	if len(words) == 2 {
+		modifier, words = words[0], words[1:]
+	}
+	day, ok := weekdays[words[0]]
+	if !ok || len(words) != 1 {
+		return time.Time{}, false
+	}
+	ahead := (int(day) - int(today.Weekday()) + 7) % 7
+	switch modifier {
+	case "", "this":
+		return today.AddDate(0, 0, ahead), true
+	case "next":
+		if ahead == 0 {
+			ahead = 7
+		}
+		return today.AddDate(0, 0, ahead), true
+	case "last":
+		behind := (int(today.Weekday()) - int(day) + 7) % 7
+		if behind == 0 {
+			behind = 7
+		}
+		return today.AddDate(0, 0, -behind), true
+	}
+	return time.Time{}, false
+}
+
+// parseOffset reads "+5", "-2", "in 3 days", "2 weeks ago", "next month"
+// and "last year".
+func parseOffset(words []string, today time.Time) (time.Time, bool) {
+	// Plain day offsets
+	if len(words) == 1 && (words[0][0] == '+' || words[0][0] == '-') {
+		n, err := strconv.Atoi(words[0])
+		if err != nil || n > maxOffset || n < -maxOffset {
+			return time.Time{}, false
+		}
+		return today.AddDate(0, 0, n), true
+	}
+
+	var count, unit string
+	sign := 1
+	switch {
+	case len(words) == 2 && (words[0] == "next" || words[0] == "last"):
+		count, unit = "1", words[1]
+		if words[0] == "last" {
+			sign = -1
+		}
+	case len(words) == 3 && words[0] == "in":
+		count, unit = words[1], words[2]
+	case len(words) == 3 && words[2] == "ago":
+		count, unit, sign = words[0], words[1], -1
+	default:
+		return time.Time{}, false
+	}
+
+	n := 1
+	if count != "a" && count != "an" && count != "one" {
+		var err error
+		if n, err = strconv.Atoi(count); err != nil || n < 0 || n > maxOffset {
+			return time.Time{}, false
+		}
+	}
+	n *= sign
+	switch strings.TrimSuffix(unit, "s") {
+	case "day":
+		return today.AddDate(0, 0, n), true
+	case "week":
+		return today.AddDate(0, 0, 7*n), true
+	case "month":
+		return today.AddDate(0, n, 0), true
+	case "year":
+		return today.AddDate(n, 0, 0), true
+	}
+	return time.Time{}, false
+}
+
+// parseMonthDay reads "july 4", "4 july", "jul 4 2026" and "4 jul 2026".
+func parseMonthDay(text string, words []string, today time.Time) (time.Time, error) {
+	notDate := fmt.Errorf("%q: %w", text, ErrNotDate)
+	if len(words) < 2 || len(words) > 3 {
+		return time.Time{}, notDate
+	}
+
+	month, ok := months[strings.TrimSuffix(words[0], ".")]
+	dayWord := words[1]
+	if !ok {
+		month, ok = months[strings.TrimSuffix(words[1], ".")]
+		dayWord = words[0]
+	}
+	day, err := strconv.Atoi(strings.TrimRight(dayWord, "stndrh."))
+	if !ok || err != nil {
+		return time.Time{}, notDate
+	}
+
+	year := today.Year()
+	if len(words) == 3 {
+		if year, err = strconv.Atoi(words[2]); err != nil || year < 1 || year > 9999 {
+			return time.Time{}, notDate
+		}
+	}
+	date := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+	if date.Month() != month || day < 1 {
+		return time.Time{}, fmt.Errorf("%s has no day %d", month, day)
+	}
+	if len(words) == 2 && date.Before(today) {
+		date = time.Date(year+1, month, day, 0, 0, 0, 0, today.Location())
+	}
+	return date, nil
+}
+
+// Describe says how far date is from today, for display next to the date:
+// "today", "tomorrow", "yesterday", "in 3 days", "5 days ago", then weeks,
+// months and years ("in 2 weeks", "about 3 months ago").
+//
+// Spans up to eight weeks are given in weeks, marked "about" unless the
+// days make whole weeks; longer spans in months and years, always "about".
+func Describe(date, today time.Time) string {
+	days := daysBetween(midnight(today), midnight(date))
+	switch days {
+	case 0:
+		return "today"
+	case 1:
+		return "tomorrow"
+	case -1:
+		return "yesterday"
+	}
+
+	n := abs(days)
+	var amount string
+	switch {
+	case n < 14:
+		amount = plural(n, "day")
+	case n < 8*7 && n%7 == 0:
+		amount = plural(n/7, "week")
+	case n < 8*7:
+		amount = "about " + plural(int(math.Round(float64(n)/7)), "week")
+	case n < 2*365:
+		amount = "about " + plural(int(math.Round(float64(n)/30.44)), "month")
+	default:
+		amount = "about " + plural(int(math.Round(float64(n)/365.25)), "year")
+	}
+	if days > 0 {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
+
+// midnight returns the start of t's calendar day in t's time zone.
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// daysBetween counts the calendar days from a to b, ignoring daylight
+// saving changes between them.
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+// plural formats a count with its unit ("1 day", "3 days").
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// abs returns the absolute value of n.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
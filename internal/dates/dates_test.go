@@ -0,0 +1,81 @@
+package dates
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// today is a Wednesday.
+var today = time.Date(2026, time.June, 17, 15, 4, 0, 0, time.UTC)
+
+func TestParse(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{"Today", day(6, 17)},
+		{" tomorrow ", day(6, 18)},
+		{"yesterday", day(6, 16)},
+		{"saturday", day(6, 20)},
+		{"Sat", day(6, 20)},
+		{"wednesday", day(6, 17)},
+		{"next wednesday", day(6, 24)},
+		{"next sat", day(6, 20)},
+		{"last wednesday", day(6, 10)},
+		{"last friday", day(6, 12)},
+		{"+5", day(6, 22)},
+		{"-2", day(6, 15)},
+		{"in 3 days", day(6, 20)},
+		{"in a week", day(6, 24)},
+		{"2 weeks ago", day(6, 3)},
+		{"next month", day(7, 17)},
+		{"last year", time.Date(2025, 6, 17, 0, 0, 0, 0, time.UTC)},
+		{"2026-07-04", day(7, 4)},
+		{"July 4", day(7, 4)},
+		{"4th jul", day(7, 4)},
+		{"Jun 17", day(6, 17)},
+		{"jun 1", time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{"dec 24, 2025", time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.text, today)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("Parse(%q) = %v, %v; want %v", tt.text, got, err, tt.want)
+		}
+	}
+
+	for _, text := range []string{"", "someday", "next fortnight", "in many days", "+5000000", "paris"} {
+		if _, err := Parse(text, today); !errors.Is(err, ErrNotDate) {
+			t.Errorf("Parse(%q) error = %v, want ErrNotDate", text, err)
+		}
+	}
+	if _, err := Parse("feb 30", today); err == nil || errors.Is(err, ErrNotDate) {
+		t.Errorf("Parse(feb 30) error = %v, want a date that doesn't exist", err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		days int
+		want string
+	}{
+		{0, "today"},
+		{1, "tomorrow"},
+		{-1, "yesterday"},
+		{3, "in 3 days"},
+		{-13, "13 days ago"},
+		{14, "in 2 weeks"},
+		{17, "in about 2 weeks"},
+		{-21, "3 weeks ago"},
+		{90, "in about 3 months"},
+		{800, "in about 2 years"},
+	}
+	for _, tt := range tests {
+		if got := Describe(today.AddDate(0, 0, tt.days), today); got != tt.want {
+			t.Errorf("Describe(%+d days) = %q, want %q", tt.days, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+// =============================================================================
+// SunAlignment
+// =============================================================================
+
+// SunAlignment describes a moment when the sun lines up with a chosen bearing.
+//
+// Alignments are found by the solar calculator's alignment search: the user
+// picks a camera point and a subject point on the map, and the sun is
+// "aligned" when its azimuth, as seen from the camera, equals the bearing
+// from the camera to the subject while the sun is low on the horizon. This
+// is the effect behind "Manhattanhenge", where the setting sun lines up
+// with a city's street grid.
+//
+// Only alignments during golden hour (between the horizon and the
+// configured golden hour elevation) are reported, since that is when the
+// sun appears next to, or just above, a subject on the horizon.
+type SunAlignment struct {
+	// Time is the moment of alignment in the camera location's timezone.
+	Time time.Time
+
+	// Azimuth is the sun's compass direction at Time, in degrees clockwise
+	// from true north. It matches the target bearing to within a fraction
+	// of a degree.
+	Azimuth float64
+
+	// Elevation is the sun's angle above the horizon at Time, in degrees.
+	// Always between 0° and the golden hour elevation.
+	Elevation float64
+
+	// Rising is true for morning alignments (sun rising behind the subject)
+	// and false for evening alignments (sun setting behind the subject).
+	Rising bool
+}
@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Aurora Forecast
+// =============================================================================
+
+// KpReading is the planetary K index of one three-hour interval, from an
+// aurora forecast service (see the aurora package).
+//
+// The Kp index (0-9) measures the disturbance of the Earth's magnetic
+// field: the higher it is, the brighter the aurora and the farther from the
+// poles it can be seen.
+type KpReading struct {
+	// Time is the start of the interval.
+	Time time.Time
+
+	// Kp is the index, in thirds (e.g., 4.33 for "4+").
+	Kp float64
+
+	// Predicted is true for forecast intervals, false for observed or
+	// estimated ones.
+	Predicted bool
+}
+
+// KpForecast is the Kp index of the past few days and the next three, in
+// three-hour intervals.
+type KpForecast struct {
+	// Readings are the intervals in chronological order.
+	Readings []KpReading
+}
+
+// kpInterval is the length of a Kp interval.
+const kpInterval = 3 * time.Hour
+
+// MaxDuring returns the highest Kp of the intervals overlapping a time
+// range.
+//
+// Returns false if r is invalid or no interval overlaps it (e.g., a night
+// beyond the three-day forecast).
+func (f KpForecast) MaxDuring(r TimeRange) (KpReading, bool) {
+	if !r.IsValid() {
+		return KpReading{}, false
+	}
+	var best KpReading
+	found := false
+	for _, k := range f.Readings {
+		if !k.Time.Before(r.End) || !k.Time.Add(kpInterval).After(r.Start) {
+			continue
+		}
+		if !found || k.Kp > best.Kp {
+			best, found = k, true
+		}
+	}
+	return best, found
+}
+
+// AuroraVisibility is how well the aurora can be seen from a place on a
+// night (see AuroraChance).
+type AuroraVisibility int
+
+const (
+	// AuroraUnknown: no Kp forecast covers the night.
+	AuroraUnknown AuroraVisibility = iota
+
+	// AuroraTooBright: the sun doesn't get low enough for a dark sky
+	// (AuroraDarkElevation), e.g., in the white nights of a high-latitude
+	// summer.
+	AuroraTooBright
+
+	// AuroraUnlikely: the auroral oval stays too far poleward to be seen.
+	AuroraUnlikely
+
+	// AuroraHorizon: the aurora may show low over the poleward horizon.
+	AuroraHorizon
+
+	// AuroraOverhead: the place is under the auroral oval.
+	AuroraOverhead
+)
+
+// AuroraDarkElevation is the sun elevation below which the sky is dark
+// enough for the aurora: the end of nautical twilight. Bright aurora shows
+// earlier, but faint displays need this darkness.
+const AuroraDarkElevation = NauticalTwilightElevation
+
+// Geomagnetic north pole of the dipole field model (IGRF, 2025), in degrees.
+const (
+	geomagneticPoleLatitude  = 80.8
+	geomagneticPoleLongitude = -72.8
+)
+
+// Auroral oval heuristic, in degrees of geomagnetic latitude (see
+// AuroraChance).
+const (
+	// ovalQuietLatitude is the oval's equatorward edge at Kp 0.
+	ovalQuietLatitude = 67.0
+
+	// ovalShiftPerKp is how far the edge moves toward the equator per Kp.
+	ovalShiftPerKp = 2.0
+
+	// horizonReach is how far beyond the edge the aurora is still seen
+	// low on the horizon (it glows 100-300 km up).
+	horizonReach = 4.0
+)
+
+// GeomagneticLatitude returns a place's latitude relative to the
+// geomagnetic poles rather than the geographic ones, in degrees (negative
+// in the south).
+//
+// The auroral ovals are centered on the geomagnetic poles, which lie toward
+// North America and the opposite side of Antarctica: at the same latitude,
+// Canada sees far more aurora than Siberia.
+func GeomagneticLatitude(loc Location) float64 {
+	lat := loc.Latitude * math.Pi / 180
+	poleLat := geomagneticPoleLatitude * math.Pi / 180
+	dLon := (loc.Longitude - geomagneticPoleLongitude) * math.Pi / 180
+	sin := math.Sin(lat)*math.Sin(poleLat) + math.Cos(lat)*math.Cos(poleLat)*math.Cos(dLon)
+	return math.Asin(math.Max(-1, math.Min(1, sin))) * 180 / math.Pi
+}
+
+// AuroraChance estimates how well the aurora can be seen from a place at a
+// Kp index, assuming a dark, clear sky.
+//
+// The equatorward edge of the auroral oval moves from about 67° geomagnetic
+// latitude at Kp 0 toward the equator by 2° per Kp (49° at Kp 9). Places
+// poleward of the edge have the aurora overhead; up to 4° beyond it, it
+// shows low over the poleward horizon:
+//
+//	geomagnetic
+//	latitude
+//	 67° ┤━━╮         overhead poleward of the edge,
+//	     │   ╲━━╮      on the horizon up to 4° beyond
+//	     │       ╲━━╮
+//	 49° ┤           ╲━━
+//	     └┬─────────────┬─
+//	      0             9   Kp
+//
+// This is a rule of thumb: the oval's shape and substorms make the local
+// activity vary a lot within a three-hour Kp interval.
+func AuroraChance(loc Location, kp float64) AuroraVisibility {
+	edge := ovalQuietLatitude - ovalShiftPerKp*kp
+	switch lat := math.Abs(GeomagneticLatitude(loc)); {
+	case lat >= edge:
+		return AuroraOverhead
+	case lat >= edge-horizonReach:
+		return AuroraHorizon
+	}
+	return AuroraUnlikely
+}
+
+// AuroraKpNeeded returns the lowest Kp index, in thirds, at which
+// AuroraChance gives a place at least a visibility (AuroraHorizon or
+// AuroraOverhead): 0 under the quiet oval, more than 9 where even the
+// strongest storms don't reach.
+func AuroraKpNeeded(loc Location, v AuroraVisibility) float64 {
+	edge := math.Abs(GeomagneticLatitude(loc))
+	if v == AuroraHorizon {
+		edge += horizonReach
+	}
+	kp := math.Ceil((ovalQuietLatitude-edge)/ovalShiftPerKp*3) / 3
+	return math.Max(0, kp)
+}
+
+// AuroraOutlook is the aurora forecast for a night at a place.
+type AuroraOutlook struct {
+	// Location is the place.
+	Location Location
+
+	// Dark is when the sun is below AuroraDarkElevation, from the evening
+	// of the date to the next morning; invalid if it doesn't get that low.
+	Dark TimeRange
+
+	// Kp is the highest Kp index while dark; its Time is zero if the Kp
+	// forecast doesn't cover the night.
+	Kp KpReading
+
+	// Visibility rates the night.
+	Visibility AuroraVisibility
+}
+
+// NewAuroraOutlook rates a night by the Kp forecast.
+//
+// Parameters:
+//   - loc: The place
+//   - dark: The darkness of the night (solar.Darkness with
+//     AuroraDarkElevation)
+//   - forecast: The Kp forecast
+func NewAuroraOutlook(loc Location, dark TimeRange, forecast KpForecast) AuroraOutlook {
+	o := AuroraOutlook{Location: loc, Dark: dark}
+	if !dark.IsValid() {
+		o.Visibility = AuroraTooBright
+		return o
+	}
+	if kp, ok := forecast.MaxDuring(dark); ok {
+		o.Kp = kp
+		o.Visibility = AuroraChance(loc, kp.Kp)
+	}
+	return o
+}
+
+// Summary describes the visibility in a few words, e.g., "Possible low
+// on the northern horizon".
+func (o AuroraOutlook) Summary() string {
+	switch o.Visibility {
+	case AuroraTooBright:
+		return "Not visible: the sky doesn't get dark"
+	case AuroraUnlikely:
+		return "Unlikely at this latitude"
+	case AuroraHorizon:
+		side := "northern"
+		if GeomagneticLatitude(o.Location) < 0 {
+			side = "southern"
+		}
+		return fmt.Sprintf("Possible low on the %s horizon", side)
+	case AuroraOverhead:
+		return "Likely, overhead"
+	}
+	return "No Kp forecast for this night"
+}
+
+// FormatKp formats a Kp index the way space weather services write it:
+// the whole number with "+" or "-" for the thirds, e.g., "4+" for 4.33
+// and "5-" for 4.67.
+func FormatKp(kp float64) string {
+	thirds := int(math.Round(kp * 3))
+	switch whole := thirds / 3; thirds % 3 {
+	case 1:
+		return fmt.Sprintf("%d+", whole)
+	case 2:
+		return fmt.Sprintf("%d-", whole+1)
+	default:
+		return fmt.Sprint(whole)
+	}
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAuroraChance(t *testing.T) {
+	tromso := Location{Latitude: 69.65, Longitude: 18.96}
+	edinburgh := Location{Latitude: 55.95, Longitude: -3.19}
+	christchurch := Location{Latitude: -43.53, Longitude: 172.64}
+	for _, tc := range []struct {
+		loc  Location
+		kp   float64
+		want AuroraVisibility
+	}{
+		{tromso, 1, AuroraOverhead},
+		{edinburgh, 2, AuroraUnlikely},
+		{edinburgh, 4, AuroraHorizon},
+		{edinburgh, 6, AuroraOverhead},
+		{christchurch, 5, AuroraUnlikely},
+		{christchurch, 9, AuroraHorizon},
+	} {
+		if got := AuroraChance(tc.loc, tc.kp); got != tc.want {
+			t.Errorf("%+v at Kp %.0f: %d, want %d (geomagnetic latitude %.1f°)",
+				tc.loc, tc.kp, got, tc.want, GeomagneticLatitude(tc.loc))
+		}
+	}
+
+	// The Kp needed is the lowest at which the chance is reached
+	for _, loc := range []Location{tromso, edinburgh, christchurch} {
+		for _, v := range []AuroraVisibility{AuroraHorizon, AuroraOverhead} {
+			kp := AuroraKpNeeded(loc, v)
+			if kp <= 9 && AuroraChance(loc, kp) < v || kp > 0 && AuroraChance(loc, kp-1.0/3) >= v {
+				t.Errorf("%+v needs Kp %.2f for %d", loc, kp, v)
+			}
+		}
+	}
+	if kp := AuroraKpNeeded(tromso, AuroraOverhead); kp != 0 {
+		t.Errorf("Tromsø needs Kp %.2f for the aurora overhead, want 0", kp)
+	}
+}
+
+func TestNewAuroraOutlook(t *testing.T) {
+	base := time.Date(2025, 9, 21, 18, 0, 0, 0, time.UTC)
+	forecast := KpForecast{Readings: []KpReading{
+		{Time: base, Kp: 2},
+		{Time: base.Add(3 * time.Hour), Kp: 4.33, Predicted: true},
+		{Time: base.Add(6 * time.Hour), Kp: 3, Predicted: true},
+	}}
+	edinburgh := Location{Latitude: 55.95, Longitude: -3.19}
+
+	// 20:00 to 04:00 overlaps all three intervals
+	dark := TimeRange{Start: base.Add(2 * time.Hour), End: base.Add(10 * time.Hour)}
+	outlook := NewAuroraOutlook(edinburgh, dark, forecast)
+	if outlook.Kp.Kp != 4.33 || outlook.Visibility != AuroraHorizon {
+		t.Errorf("outlook %+v, want Kp 4.33 on the horizon", outlook)
+	}
+	if want := "Possible low on the northern horizon"; outlook.Summary() != want {
+		t.Errorf("summary %q, want %q", outlook.Summary(), want)
+	}
+
+	if outlook := NewAuroraOutlook(edinburgh, TimeRange{}, forecast); outlook.Visibility != AuroraTooBright {
+		t.Errorf("outlook without darkness %+v, want too bright", outlook)
+	}
+	later := TimeRange{Start: base.AddDate(0, 0, 5), End: base.AddDate(0, 0, 5).Add(8 * time.Hour)}
+	if outlook := NewAuroraOutlook(edinburgh, later, forecast); outlook.Visibility != AuroraUnknown {
+		t.Errorf("outlook beyond the forecast %+v, want unknown", outlook)
+	}
+}
+
+func TestFormatKp(t *testing.T) {
+	for kp, want := range map[float64]string{0: "0", 4.33: "4+", 4.67: "5-", 5: "5", 8.67: "9-", 9: "9"} {
+		if got := FormatKp(kp); got != want {
+			t.Errorf("FormatKp(%.2f) = %q, want %q", kp, got, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Calendar Sync
+// =============================================================================
+
+// CalDAVSync configures pushing the planned golden and blue hours straight
+// to the user's calendar server (Nextcloud, Radicale, iCloud, Fastmail, ...)
+// over CalDAV, instead of importing an exported ICS file by hand.
+//
+// The same events as the watch calendar export are sent, each as its own
+// calendar object. Syncing again updates them in place. Updates are
+// conflict-safe: the server's ETag of every event sent is kept, and an
+// event that was changed in the calendar since (e.g., the user moved or
+// renamed it) is left alone rather than overwritten.
+type CalDAVSync struct {
+	// CalendarURL is the URL of the calendar collection the events are put
+	// in, e.g., "https://cloud.example.com/remote.php/dav/calendars/me/shoots/".
+	// Empty means calendar sync isn't set up.
+	CalendarURL string `json:"calendar_url,omitempty"`
+
+	// Username and Password are the account's login (HTTP basic
+	// authentication). For servers with two-factor logins this is an app
+	// password. The password is stored in the settings file like the other
+	// service keys, so a dedicated app password is recommended.
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+
+	// ETags maps the UID of each event sent to the server's ETag of it,
+	// for conflict-safe updates. Events of days past are dropped (see
+	// PruneETags). An empty ETag means the server didn't report one.
+	ETags map[string]string `json:"etags,omitempty"`
+}
+
+// maxCalDAVETags bounds the number of remembered events, so a settings file
+// can't grow without limit (a year of four daily events fits).
+const maxCalDAVETags = 1500
+
+// Configured reports whether a calendar URL is set.
+func (c CalDAVSync) Configured() bool {
+	return c.CalendarURL != ""
+}
+
+// Check reports the first problem that would keep events from being sent,
+// suitable for showing to the user. An empty configuration passes (sync is
+// simply off).
+func (c CalDAVSync) Check() error {
+	if c.CalendarURL == "" {
+		return nil
+	}
+	u, err := url.Parse(c.CalendarURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("calendar URL %q must be an http:// or https:// URL", c.CalendarURL)
+	}
+	if u.User != nil {
+		return errors.New("put the login into the username and password fields, not the calendar URL")
+	}
+	if c.Password != "" && c.Username == "" {
+		return errors.New("the calendar password needs a username")
+	}
+	return nil
+}
+
+// PruneETags drops the ETags of events before the given date, whose UIDs
+// start with their date as "YYYYMMDD" (see export.CalendarEvents). Those
+// events won't be sent again, so their ETags aren't needed.
+func (c *CalDAVSync) PruneETags(before time.Time) {
+	cutoff := before.Format("20060102")
+	for uid := range c.ETags {
+		if len(uid) < 8 || uid[:8] < cutoff {
+			delete(c.ETags, uid)
+		}
+	}
+}
+
+// validate repairs a loaded configuration: a URL that fails Check turns
+// sync off (the login is kept), and oversized ETag maps are dropped.
+func (c *CalDAVSync) validate() {
+	c.CalendarURL = strings.TrimSpace(c.CalendarURL)
+	c.Username = strings.TrimSpace(c.Username)
+	if c.Check() != nil {
+		c.CalendarURL = ""
+		c.ETags = nil
+	}
+	if len(c.ETags) > maxCalDAVETags {
+		c.ETags = nil
+	}
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Camera Field of View
+// =============================================================================
+
+// Full-frame (35 mm) sensor dimensions in millimeters.
+//
+// Focal lengths are interpreted as full-frame equivalents, which is how
+// most photographers think about them regardless of their actual sensor.
+const (
+	FullFrameWidth  = 36.0
+	FullFrameHeight = 24.0
+)
+
+// Camera describes where a camera stands and what it sees.
+//
+// The camera is placed on the map in camera mode: the first click sets the
+// position, the second the direction it faces. The field of view depends on
+// the focal length and on whether the camera is held in landscape or
+// portrait orientation, and is drawn on the map as a cone.
+type Camera struct {
+	// Location is where the camera stands.
+	Location Location
+
+	// Heading is the direction the lens points, in degrees clockwise from
+	// true north (0-360).
+	Heading float64
+
+	// FocalLength is the lens focal length in millimeters (full-frame
+	// equivalent, e.g., 24 for a wide angle or 200 for a telephoto).
+	FocalLength float64
+
+	// Portrait is true when the camera is turned on its side, so the short
+	// side of the sensor is horizontal and the view is narrower.
+	Portrait bool
+}
+
+// HorizontalFOV returns the horizontal angle of view in degrees.
+//
+// Uses the rectilinear lens formula 2·atan(w / 2f), where w is the
+// horizontal sensor dimension (36 mm in landscape, 24 mm in portrait).
+// Examples in landscape: 24 mm → 73.7°, 50 mm → 39.6°, 200 mm → 10.3°.
+func (c Camera) HorizontalFOV() float64 {
+	width := FullFrameWidth
+	if c.Portrait {
+		width = FullFrameHeight
+	}
+	return toDegrees(2 * math.Atan(width/(2*c.FocalLength)))
+}
+
+// InView reports whether a compass direction is inside the horizontal field of view.
+//
+// Parameters:
+//   - azimuth: Direction in degrees clockwise from true north
+//
+// Returns true if the direction is within half the angle of view of Heading.
+func (c Camera) InView(azimuth float64) bool {
+	return math.Abs(AngleDifference(azimuth, c.Heading)) <= c.HorizontalFOV()/2
+}
+
+// HorizonEvent is a moment when the sun or moon crosses the horizon.
+//
+// Used by the camera view to show whether sunrise, sunset, moonrise, or
+// moonset on the selected date appears inside the camera's field of view.
+type HorizonEvent struct {
+	// Name identifies the event: "Sunrise", "Sunset", "Moonrise" or "Moonset".
+	Name string
+
+	// Moon is true for moonrise/moonset and false for sunrise/sunset.
+	Moon bool
+
+	// Time is when the event happens, in the camera location's timezone.
+	Time time.Time
+
+	// Azimuth is where on the horizon the event happens, in degrees
+	// clockwise from true north.
+	Azimuth float64
+}
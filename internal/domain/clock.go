@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Home Clock
+// =============================================================================
+
+// ValidTimezone reports whether name is an IANA timezone identifier that
+// time.LoadLocation accepts (e.g., "America/New_York"), as used for
+// Settings.HomeTimezone. "Local" and the empty name are rejected: the home
+// clock is meant to stay put when the computer travels.
+func ValidTimezone(name string) bool {
+	if name == "" || strings.EqualFold(name, "Local") {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// ShowsHomeClock reports whether t reads differently on the home clock,
+// i.e. whether the home timezone's UTC offset at t differs from t's own.
+//
+// A home clock in the location's timezone (or one with the same offset)
+// would only repeat the local time, so it isn't shown.
+func ShowsHomeClock(t time.Time, home *time.Location) bool {
+	if home == nil || t.IsZero() {
+		return false
+	}
+	_, local := t.Zone()
+	_, other := t.In(home).Zone()
+	return local != other
+}
+
+// FormatHomeTime formats t on the home clock, with the home timezone's
+// abbreviation (e.g., "9:15 AM EDT").
+//
+// When the home date differs from the local date, the difference in days
+// follows the time ("01:15+1 EST" is 1:15 on the next day at home).
+//
+// Returns "" if the home clock isn't shown (see ShowsHomeClock).
+func FormatHomeTime(t time.Time, home *time.Location, use24Hour bool) string {
+	if !ShowsHomeClock(t, home) {
+		return ""
+	}
+	return homeClock(t, home, use24Hour) + " " + t.In(home).Format("MST")
+}
+
+// FormatHomeRange formats a period on the home clock, with the home
+// timezone's abbreviation once (e.g., "01:15 - 02:15 EST").
+//
+// Returns "" if the home clock isn't shown at the start (see
+// ShowsHomeClock).
+func FormatHomeRange(start, end time.Time, home *time.Location, use24Hour bool) string {
+	if !ShowsHomeClock(start, home) {
+		return ""
+	}
+	return homeClock(start, home, use24Hour) + " - " + homeClock(end, home, use24Hour) +
+		" " + end.In(home).Format("MST")
+}
+
+// homeClock formats t in home with the day marker of FormatHomeTime.
+func homeClock(t time.Time, home *time.Location, use24Hour bool) string {
+	at := t.In(home)
+	text := FormatTime(at, use24Hour)
+	localDay := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	homeDay := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, time.UTC)
+	switch days := int(homeDay.Sub(localDay).Hours() / 24); {
+	case days > 0:
+		text += "+" + strconv.Itoa(days)
+	case days < 0:
+		text += strconv.Itoa(days)
+	}
+	return text
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidTimezone(t *testing.T) {
+	for name, want := range map[string]bool{
+		"America/New_York": true,
+		"UTC":              true,
+		"":                 false,
+		"Local":            false,
+		"Mars/Olympus":     false,
+	} {
+		if got := ValidTimezone(name); got != want {
+			t.Errorf("ValidTimezone(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestFormatHomeTime(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	newYork, _ := time.LoadLocation("America/New_York")
+	tokyo, _ := time.LoadLocation("Asia/Tokyo")
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	sunset := time.Date(2026, time.January, 15, 17, 20, 0, 0, paris)
+
+	tests := []struct {
+		name      string
+		home      *time.Location
+		use24Hour bool
+		want      string
+	}{
+		{name: "earlier offset", home: newYork, use24Hour: true, want: "11:20 EST"},
+		{name: "next day at home", home: tokyo, use24Hour: true, want: "01:20+1 JST"},
+		{name: "12-hour", home: newYork, want: "11:20 AM EST"},
+		{name: "same offset", home: berlin, want: ""},
+		{name: "no home clock", home: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatHomeTime(sunset, tt.home, tt.use24Hour); got != tt.want {
+				t.Errorf("FormatHomeTime = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if got := FormatHomeTime(time.Time{}, newYork, true); got != "" {
+		t.Errorf("FormatHomeTime(zero) = %q, want empty", got)
+	}
+	end := sunset.Add(-time.Hour)
+	if got, want := FormatHomeRange(end, sunset, tokyo, true), "00:20+1 - 01:20+1 JST"; got != want {
+		t.Errorf("FormatHomeRange = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// =============================================================================
+// Cloud Frames
+// =============================================================================
+
+// CloudFrame is one image of an animated cloud / precipitation map layer.
+//
+// Frames come from a weather map service (see the weather package), a few
+// minutes apart. Played in order on the map, they show whether a cloud
+// bank is moving in or clearing before sunset.
+type CloudFrame struct {
+	// Time is the moment the frame shows.
+	Time time.Time
+
+	// TileURL is the frame's tile URL template with {z}, {x} and {y}
+	// placeholders, as used by Leaflet.
+	TileURL string
+
+	// Forecast is true for frames extrapolated into the future (nowcast);
+	// false for observed ones.
+	Forecast bool
+}
+
+// CloudFramesAround returns the frames from margin before r starts to
+// margin after it ends, e.g., the frames around a golden hour.
+//
+// Weather map services only keep a short window of frames around the
+// present, so this is usually empty unless r is close to now.
+//
+// Parameters:
+//   - frames: All available frames in chronological order
+//   - r: The time range of interest; invalid ranges match no frames
+//   - margin: How far before and after r frames are kept
+//
+// Returns the matching frames in chronological order.
+func CloudFramesAround(frames []CloudFrame, r TimeRange, margin time.Duration) []CloudFrame {
+	if !r.IsValid() {
+		return nil
+	}
+	from, to := r.Start.Add(-margin), r.End.Add(margin)
+	var kept []CloudFrame
+	for _, f := range frames {
+		if !f.Time.Before(from) && !f.Time.After(to) {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCloudFramesAround(t *testing.T) {
+	base := time.Date(2025, 6, 21, 19, 0, 0, 0, time.UTC)
+	var frames []CloudFrame
+	for i := range 12 {
+		frames = append(frames, CloudFrame{Time: base.Add(time.Duration(i) * 10 * time.Minute)})
+	}
+	golden := TimeRange{Start: base.Add(60 * time.Minute), End: base.Add(80 * time.Minute)}
+
+	around := CloudFramesAround(frames, golden, 20*time.Minute)
+	if len(around) != 7 {
+		t.Fatalf("got %d frames, want 7 (19:40 to 20:40)", len(around))
+	}
+	if !around[0].Time.Equal(base.Add(40*time.Minute)) || !around[6].Time.Equal(base.Add(100*time.Minute)) {
+		t.Errorf("frames from %v to %v, want 19:40 to 20:40", around[0].Time, around[6].Time)
+	}
+
+	if around := CloudFramesAround(frames, TimeRange{}, time.Hour); len(around) != 0 {
+		t.Errorf("invalid range matched %d frames", len(around))
+	}
+}
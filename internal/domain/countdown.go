@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// Countdown
+// =============================================================================
+
+// LightPeriod is one golden or blue hour of a day, with its display name.
+type LightPeriod struct {
+	// Name is the period's display name, e.g., "Evening golden hour".
+	Name string
+
+	// Period is when the light lasts.
+	Period TimeRange
+}
+
+// LightPeriods returns the day's golden and blue hours in chronological
+// order, leaving out those that don't occur (invalid ranges).
+func (st SunTimes) LightPeriods() []LightPeriod {
+	var periods []LightPeriod
+	add := func(name string, tr TimeRange) {
+		if tr.IsValid() {
+			periods = append(periods, LightPeriod{Name: name, Period: tr})
+		}
+	}
+	add("Morning blue hour", st.BlueMorning)
+	add("Morning golden hour", st.GoldenMorning)
+	add("Evening golden hour", st.GoldenEvening)
+	add("Evening blue hour", st.BlueEvening)
+	return periods
+}
+
+// NextLightPeriod finds the golden or blue hour to count down to: the one
+// in progress at now, or else the next to start.
+//
+// Parameters:
+//   - days: Sun times of consecutive days around now, in order (e.g.,
+//     yesterday to tomorrow, so that a blue hour running past midnight is
+//     found too)
+//   - now: The current time
+//
+// Returns the period and true, or false if none of the days has a golden or
+// blue hour after now.
+func NextLightPeriod(days []SunTimes, now time.Time) (LightPeriod, bool) {
+	for _, day := range days {
+		for _, p := range day.LightPeriods() {
+			if now.Before(p.Period.End) {
+				return p, true
+			}
+		}
+	}
+	return LightPeriod{}, false
+}
+
+// InProgress reports whether the period has started and not yet ended at now.
+func (p LightPeriod) InProgress(now time.Time) bool {
+	return !now.Before(p.Period.Start) && now.Before(p.Period.End)
+}
+
+// Progress returns how much of the period has passed at now, from 0 (not
+// started) to 1 (over).
+func (p LightPeriod) Progress(now time.Time) float64 {
+	total := p.Period.Duration()
+	if total <= 0 {
+		return 0
+	}
+	return min(max(float64(now.Sub(p.Period.Start))/float64(total), 0), 1)
+}
+
+// FormatCountdown formats the time left until an event as "HH:MM:SS"
+// (e.g., "02:05:09"), rounded down to the second. Hours run past 24 for
+// distant events; negative durations show as "00:00:00".
+func FormatCountdown(d time.Duration) string {
+	seconds := max(int(d/time.Second), 0)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+// LightStatus summarizes the next golden or blue hour in one line, for
+// places without room for the live countdown, such as the system tray
+// tooltip:
+//
+//	Evening golden hour at 20:15, in 2h 5m
+//	Evening golden hour until 21:00, 35 min left
+//	Morning blue hour at 05:10 tomorrow, in 8h 40m
+//
+// Parameters:
+//   - days: Sun times of consecutive days around now (see NextLightPeriod)
+//   - now: The current time, in the location's timezone
+//   - use24Hour: Time format
+func LightStatus(days []SunTimes, now time.Time, use24Hour bool) string {
+	period, ok := NextLightPeriod(days, now)
+	if !ok {
+		return "No golden or blue hour until tomorrow night"
+	}
+	if period.InProgress(now) {
+		return fmt.Sprintf("%s until %s, %s left", period.Name,
+			FormatTime(period.Period.End, use24Hour), FormatDuration(period.Period.End.Sub(now)))
+	}
+	start := FormatTime(period.Period.Start, use24Hour)
+	if period.Period.Start.YearDay() != now.YearDay() || period.Period.Start.Year() != now.Year() {
+		start += " tomorrow"
+	}
+	return fmt.Sprintf("%s at %s, in %s", period.Name, start, FormatDuration(period.Period.Start.Sub(now)))
+}
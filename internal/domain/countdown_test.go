@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextLightPeriod(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+	day := func(d int) SunTimes {
+		return SunTimes{
+			BlueMorning:   TimeRange{Start: at(d, 4, 30), End: at(d, 4, 50)},
+			GoldenMorning: TimeRange{Start: at(d, 5, 0), End: at(d, 5, 45)},
+			GoldenEvening: TimeRange{Start: at(d, 20, 15), End: at(d, 21, 0)},
+			BlueEvening:   TimeRange{Start: at(d, 21, 10), End: at(d, 21, 30)},
+		}
+	}
+	days := []SunTimes{day(1), day(2)}
+
+	tests := []struct {
+		name         string
+		now          time.Time
+		wantName     string
+		wantStart    time.Time
+		wantProgress bool
+	}{
+		{name: "before dawn", now: at(1, 3, 0), wantName: "Morning blue hour", wantStart: at(1, 4, 30)},
+		{name: "during golden hour", now: at(1, 20, 30), wantName: "Evening golden hour",
+			wantStart: at(1, 20, 15), wantProgress: true},
+		{name: "at the end of a period", now: at(1, 21, 0), wantName: "Evening blue hour", wantStart: at(1, 21, 10)},
+		{name: "after dusk", now: at(1, 23, 0), wantName: "Morning blue hour", wantStart: at(2, 4, 30)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := NextLightPeriod(days, tt.now)
+			if !ok {
+				t.Fatal("no period found")
+			}
+			if p.Name != tt.wantName || !p.Period.Start.Equal(tt.wantStart) {
+				t.Errorf("got %s at %v, want %s at %v", p.Name, p.Period.Start, tt.wantName, tt.wantStart)
+			}
+			if p.InProgress(tt.now) != tt.wantProgress {
+				t.Errorf("InProgress = %v, want %v", p.InProgress(tt.now), tt.wantProgress)
+			}
+		})
+	}
+
+	if _, ok := NextLightPeriod(days, at(2, 22, 0)); ok {
+		t.Error("found a period after the last day's blue hour")
+	}
+	if got := (SunTimes{}).LightPeriods(); len(got) != 0 {
+		t.Errorf("LightPeriods of a day without light = %v", got)
+	}
+}
+
+func TestLightPeriodProgress(t *testing.T) {
+	start := time.Date(2026, 6, 1, 20, 0, 0, 0, time.UTC)
+	p := LightPeriod{Period: TimeRange{Start: start, End: start.Add(time.Hour)}}
+	for _, tt := range []struct {
+		now  time.Time
+		want float64
+	}{
+		{start.Add(-time.Minute), 0},
+		{start.Add(15 * time.Minute), 0.25},
+		{start.Add(2 * time.Hour), 1},
+	} {
+		if got := p.Progress(tt.now); got != tt.want {
+			t.Errorf("Progress(%v) = %v, want %v", tt.now.Format("15:04"), got, tt.want)
+		}
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "00:00:00"},
+		{2*time.Hour + 5*time.Minute + 9*time.Second + 900*time.Millisecond, "02:05:09"},
+		{30 * time.Hour, "30:00:00"},
+		{-time.Minute, "00:00:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatCountdown(tt.d); got != tt.want {
+			t.Errorf("FormatCountdown(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestLightStatus(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+	days := []SunTimes{
+		{GoldenEvening: TimeRange{Start: at(1, 20, 15), End: at(1, 21, 0)}},
+		{BlueMorning: TimeRange{Start: at(2, 4, 30), End: at(2, 4, 50)}},
+	}
+
+	tests := []struct {
+		now  time.Time
+		want string
+	}{
+		{at(1, 18, 10), "Evening golden hour at 20:15, in 2h 5m"},
+		{at(1, 20, 25), "Evening golden hour until 21:00, 35 min left"},
+		{at(1, 22, 0), "Morning blue hour at 04:30 tomorrow, in 6h 30m"},
+		{at(2, 5, 0), "No golden or blue hour until tomorrow night"},
+	}
+	for _, tt := range tests {
+		if got := LightStatus(days, tt.now, true); got != tt.want {
+			t.Errorf("LightStatus(%v) = %q, want %q", tt.now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Custom Events
+// =============================================================================
+
+// Limits for custom events.
+const (
+	// MaxCustomEvents limits how many custom events a preset can have, so
+	// the time panel keeps its size.
+	MaxCustomEvents = 6
+
+	// MinCustomElevation and MaxCustomElevation bound a custom event's sun
+	// elevation: from the end of astronomical twilight (darker is the same
+	// night sky) to a sun high enough for hard midday light at most
+	// latitudes.
+	MinCustomElevation = -18.0
+	MaxCustomElevation = 45.0
+
+	// maxCustomEventName limits custom event names to what fits in the time
+	// panel.
+	maxCustomEventName = 30
+)
+
+// CustomEvent is a sun elevation of the user's choosing, beyond the golden
+// and blue hour boundaries, e.g., "Astronomical dusk" at -18° for night sky
+// photography or "Sun at 15°" for the end of soft portrait light.
+//
+// The sun passes the elevation twice a day: rising in the morning and
+// setting in the evening (see CustomTime). Custom events belong to a preset
+// (see Preset) and are shown in the time panel.
+type CustomEvent struct {
+	// Name is the label shown in the time panel.
+	Name string `json:"name"`
+
+	// Elevation is the sun's elevation in degrees (MinCustomElevation to
+	// MaxCustomElevation); negative is below the horizon.
+	Elevation float64 `json:"elevation"`
+}
+
+// Label returns the event's name with its elevation, e.g.,
+// "Astronomical dusk (-18°)".
+func (e CustomEvent) Label() string {
+	return fmt.Sprintf("%s (%g°)", e.Name, e.Elevation)
+}
+
+// CustomTime is when the sun passes a custom event's elevation on a day.
+type CustomTime struct {
+	// Event is the custom event.
+	Event CustomEvent `json:"event"`
+
+	// Morning is when the rising sun passes the elevation, Evening when
+	// the setting sun does. Either is zero if the sun doesn't reach (or
+	// doesn't leave) the elevation that day, e.g., at high latitudes.
+	Morning time.Time `json:"morning"`
+	Evening time.Time `json:"evening"`
+}
+
+// validateCustomEvents repairs loaded custom events: names are trimmed and
+// shortened, unnamed events dropped, elevations clamped, and the list cut
+// to MaxCustomEvents.
+func validateCustomEvents(events []CustomEvent) []CustomEvent {
+	var valid []CustomEvent
+	for _, e := range events {
+		e.Name = strings.TrimSpace(e.Name)
+		if e.Name == "" {
+			continue
+		}
+		if runes := []rune(e.Name); len(runes) > maxCustomEventName {
+			e.Name = string(runes[:maxCustomEventName])
+		}
+		e.Elevation = min(max(e.Elevation, MinCustomElevation), MaxCustomElevation)
+		valid = append(valid, e)
+		if len(valid) == MaxCustomEvents {
+			break
+		}
+	}
+	return valid
+}
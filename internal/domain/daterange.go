@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+// =============================================================================
+// Date Ranges (Multi-Day Planning)
+// =============================================================================
+
+// MaxDateRangeDays is the longest date range that can be planned.
+//
+// A year covers every season at a place; longer ranges would only repeat
+// it and make the day table slow to fill.
+const MaxDateRangeDays = 366
+
+// DateRange is a span of whole days, both ends included, e.g., the days of
+// a photo trip.
+//
+// Start and End are midnights in the start date's time zone; only their
+// calendar dates matter (see NewDateRange).
+type DateRange struct {
+	// Start is the first day.
+	Start time.Time
+
+	// End is the last day, not before Start.
+	End time.Time
+}
+
+// NewDateRange returns the days from start to end.
+//
+// The times of day are dropped, the ends are swapped if end comes first,
+// and the range is cut to MaxDateRangeDays. Both dates are taken in
+// start's time zone.
+//
+// Parameters:
+//   - start: The first day
+//   - end: The last day (the same as start for a single day)
+func NewDateRange(start, end time.Time) DateRange {
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+	if last.Before(first) {
+		first, last = last, first
+	}
+	r := DateRange{Start: first, End: last}
+	if r.Days() > MaxDateRangeDays {
+		r.End = first.AddDate(0, 0, MaxDateRangeDays-1)
+	}
+	return r
+}
+
+// Days returns the number of days in the range, both ends included.
+//
+// Days are counted on the calendar, so a range across a daylight saving
+// change still counts the 23- or 25-hour day once.
+func (r DateRange) Days() int {
+	start := time.Date(r.Start.Year(), r.Start.Month(), r.Start.Day(), 0, 0, 0, 0, time.UTC)
+	end := time.Date(r.End.Year(), r.End.Month(), r.End.Day(), 0, 0, 0, 0, time.UTC)
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// Dates returns the midnight of each day in the range, in order.
+func (r DateRange) Dates() []time.Time {
+	dates := make([]time.Time, 0, r.Days())
+	for i := range r.Days() {
+		dates = append(dates, r.Start.AddDate(0, 0, i))
+	}
+	return dates
+}
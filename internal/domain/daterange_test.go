@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDateRange(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, paris) }
+
+	// Times of day are dropped, ends in the wrong order swapped
+	r := NewDateRange(time.Date(2025, 3, 31, 18, 30, 0, 0, paris), day(3, 28))
+	if !r.Start.Equal(day(3, 28)) || !r.End.Equal(day(3, 31)) {
+		t.Errorf("range = %v - %v, want Mar 28 - Mar 31", r.Start, r.End)
+	}
+
+	// The daylight saving change on March 30 is counted as one day
+	if got := r.Days(); got != 4 {
+		t.Errorf("Days() = %d, want 4", got)
+	}
+	dates := r.Dates()
+	if len(dates) != 4 || !dates[3].Equal(day(3, 31)) {
+		t.Errorf("Dates() = %v, want Mar 28 to Mar 31", dates)
+	}
+
+	if got := NewDateRange(day(6, 1), day(6, 1)).Days(); got != 1 {
+		t.Errorf("single day Days() = %d, want 1", got)
+	}
+
+	long := NewDateRange(day(1, 1), day(1, 1).AddDate(3, 0, 0))
+	if got := long.Days(); got != MaxDateRangeDays {
+		t.Errorf("three years Days() = %d, want %d", got, MaxDateRangeDays)
+	}
+}
@@ -0,0 +1,76 @@
+package domain
+
+import "math"
+
+// =============================================================================
+// Elevation Profile
+// =============================================================================
+
+// ObserverEyeHeight is the height of the camera above the ground, in meters.
+// Used as the viewpoint height when checking what the terrain hides.
+const ObserverEyeHeight = 1.7
+
+// refractionCoefficient is the standard atmospheric refraction coefficient.
+//
+// Refraction bends lines of sight downward, following the Earth's curve a
+// little, so distant terrain appears slightly higher than geometry alone
+// predicts. 0.13 is the value commonly used by surveyors.
+const refractionCoefficient = 0.13
+
+// ProfileSample is the terrain height at one point of an elevation profile.
+type ProfileSample struct {
+	// Distance is the distance from the start of the profile in meters.
+	Distance float64
+
+	// Elevation is the terrain height above sea level in meters.
+	Elevation float64
+}
+
+// ElevationProfile is terrain height sampled along a line.
+//
+// The line starts at the observer (camera) and runs in one direction, so
+// the profile shows what lies between the camera and the horizon in that
+// direction: a ridge that rises above the line of sight hides the sun
+// until it climbs above the ridge.
+type ElevationProfile struct {
+	// Samples are evenly spaced along the line, starting at the observer.
+	Samples []ProfileSample
+}
+
+// HorizonAngle returns the apparent elevation angle of the terrain horizon
+// as seen from the start of the profile.
+//
+// The angle to each sample is measured from an eye ObserverEyeHeight above
+// the first sample, after lowering the sample by the Earth's curvature
+// (reduced by atmospheric refraction):
+//
+//	drop  = d² / 2R × (1 - k)
+//	angle = atan((h - drop - h₀ - eye) / d)
+//
+// The highest angle is the horizon: the sun is hidden behind terrain in
+// this direction while its elevation is below it. Negative values mean the
+// view is open down to below the astronomical horizon (e.g., from a summit).
+//
+// Returns:
+//   - angle: Horizon elevation angle in degrees
+//   - index: Index of the sample forming the horizon
+//   - ok: false if the profile has fewer than two samples
+func (p ElevationProfile) HorizonAngle() (angle float64, index int, ok bool) {
+	if len(p.Samples) < 2 {
+		return 0, 0, false
+	}
+	eye := p.Samples[0].Elevation + ObserverEyeHeight
+
+	angle = math.Inf(-1)
+	for i, s := range p.Samples[1:] {
+		if s.Distance <= 0 {
+			continue
+		}
+		drop := s.Distance * s.Distance / (2 * EarthRadiusMeters) * (1 - refractionCoefficient)
+		a := toDegrees(math.Atan((s.Elevation - drop - eye) / s.Distance))
+		if a > angle {
+			angle, index = a, i+1
+		}
+	}
+	return angle, index, !math.IsInf(angle, -1)
+}
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestHorizonAngle(t *testing.T) {
+	tests := []struct {
+		name      string
+		samples   []ProfileSample
+		wantAngle float64
+		wantIndex int
+		wantOK    bool
+	}{
+		{name: "no samples", wantOK: false},
+		{name: "observer only", samples: []ProfileSample{{0, 100}}, wantOK: false},
+		{
+			name:    "only zero distances",
+			samples: []ProfileSample{{0, 100}, {0, 200}},
+			wantOK:  false,
+		},
+		{
+			// Looking down at flat ground, the farthest sample is the horizon
+			name:      "flat ground",
+			samples:   []ProfileSample{{0, 0}, {100, 0}, {1000, 0}},
+			wantAngle: -0.1013, wantIndex: 2, wantOK: true,
+		},
+		{
+			name:      "distant ridge",
+			samples:   []ProfileSample{{0, 10}, {500, 20}, {1000, 110}, {1500, 50}},
+			wantAngle: 5.6103, wantIndex: 2, wantOK: true,
+		},
+		{
+			// Curvature hides a far peak behind a lower, nearer one
+			name:      "curvature lowers far terrain",
+			samples:   []ProfileSample{{0, 0}, {10000, 500}, {100000, 5000}},
+			wantAngle: 2.8137, wantIndex: 1, wantOK: true,
+		},
+		{
+			name:      "view from a summit",
+			samples:   []ProfileSample{{0, 2000}, {5000, 1500}, {20000, 1000}},
+			wantAngle: -2.9453, wantIndex: 2, wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			angle, index, ok := ElevationProfile{Samples: tt.samples}.HorizonAngle()
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(angle-tt.wantAngle) > 0.001 || index != tt.wantIndex {
+				t.Errorf("HorizonAngle = %.4f at %d, want %.4f at %d",
+					angle, index, tt.wantAngle, tt.wantIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Sun Events
+// =============================================================================
+
+// EventKind identifies a phase transition during the day.
+//
+// Event kinds are stable string identifiers so they can be stored in the
+// settings file (e.g., to attach automation hooks to an event) and survive
+// changes to their display labels.
+type EventKind string
+
+// Phase transition kinds, listed in the order they occur on a normal day.
+const (
+	EventBlueMorningStart   EventKind = "blue_morning_start"
+	EventBlueMorningEnd     EventKind = "blue_morning_end"
+	EventSunrise            EventKind = "sunrise"
+	EventGoldenMorningStart EventKind = "golden_morning_start"
+	EventGoldenMorningEnd   EventKind = "golden_morning_end"
+	EventSolarNoon          EventKind = "solar_noon"
+	EventGoldenEveningStart EventKind = "golden_evening_start"
+	EventGoldenEveningEnd   EventKind = "golden_evening_end"
+	EventSunset             EventKind = "sunset"
+	EventBlueEveningStart   EventKind = "blue_evening_start"
+	EventBlueEveningEnd     EventKind = "blue_evening_end"
+)
+
+// eventLabels maps event kinds to human-readable labels.
+var eventLabels = map[EventKind]string{
+	EventBlueMorningStart:   "Morning blue hour start",
+	EventBlueMorningEnd:     "Morning blue hour end",
+	EventSunrise:            "Sunrise",
+	EventGoldenMorningStart: "Morning golden hour start",
+	EventGoldenMorningEnd:   "Morning golden hour end",
+	EventSolarNoon:          "Solar noon",
+	EventGoldenEveningStart: "Evening golden hour start",
+	EventGoldenEveningEnd:   "Evening golden hour end",
+	EventSunset:             "Sunset",
+	EventBlueEveningStart:   "Evening blue hour start",
+	EventBlueEveningEnd:     "Evening blue hour end",
+}
+
+// AllEventKinds returns every event kind in daily order.
+//
+// This is used by the UI to populate event pickers.
+func AllEventKinds() []EventKind {
+	return []EventKind{
+		EventBlueMorningStart, EventBlueMorningEnd,
+		EventSunrise, EventGoldenMorningStart, EventGoldenMorningEnd,
+		EventSolarNoon,
+		EventGoldenEveningStart, EventGoldenEveningEnd, EventSunset,
+		EventBlueEveningStart, EventBlueEveningEnd,
+	}
+}
+
+// Label returns the human-readable name of the event kind.
+//
+// Unknown kinds (e.g., from a settings file written by a newer version)
+// return the raw identifier.
+func (k EventKind) Label() string {
+	if label, ok := eventLabels[k]; ok {
+		return label
+	}
+	return string(k)
+}
+
+// SunEvent is a single phase transition at a specific moment.
+type SunEvent struct {
+	// Kind identifies which transition this is.
+	Kind EventKind
+
+	// Time is when the transition happens, in the location's timezone.
+	Time time.Time
+}
+
+// Events returns the day's phase transitions in chronological order.
+//
+// Transitions that don't occur on this date (zero times, e.g., no blue hour
+// during polar summer) are omitted. Golden and blue hour boundaries are
+// only included when their range is valid.
+//
+// This flattens SunTimes into a timeline that schedulers can walk through,
+// for example to fire automation hooks at each transition.
+func (st SunTimes) Events() []SunEvent {
+	var events []SunEvent
+	add := func(kind EventKind, t time.Time) {
+		if !t.IsZero() {
+			events = append(events, SunEvent{Kind: kind, Time: t})
+		}
+	}
+	addRange := func(startKind, endKind EventKind, tr TimeRange) {
+		if tr.IsValid() {
+			add(startKind, tr.Start)
+			add(endKind, tr.End)
+		}
+	}
+
+	addRange(EventBlueMorningStart, EventBlueMorningEnd, st.BlueMorning)
+	add(EventSunrise, st.Sunrise)
+	addRange(EventGoldenMorningStart, EventGoldenMorningEnd, st.GoldenMorning)
+	add(EventSolarNoon, st.SolarNoon)
+	addRange(EventGoldenEveningStart, EventGoldenEveningEnd, st.GoldenEvening)
+	add(EventSunset, st.Sunset)
+	addRange(EventBlueEveningStart, EventBlueEveningEnd, st.BlueEvening)
+
+	// Sort by time; stable so simultaneous events keep their daily order
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
+
+// =============================================================================
+// Timeline
+// =============================================================================
+
+// Timeline spans: the selected day alone, or with the next morning too.
+const (
+	// DayTimelineHours covers the selected day from midnight to midnight.
+	DayTimelineHours = 24
+
+	// ExtendedTimelineHours runs on to noon of the next day, so the evening
+	// blue hour and the next morning's events read as one night.
+	ExtendedTimelineHours = 36
+)
+
+// Timeline returns the phase transitions of consecutive days that fall in
+// the first hours hours after the first day's midnight, in chronological
+// order.
+//
+// Parameters:
+//   - days: Sun times of consecutive days, in order (for a 36-hour timeline,
+//     the selected day and the next)
+//   - hours: Length of the timeline, e.g., DayTimelineHours or
+//     ExtendedTimelineHours; hours are counted on the wall clock, so a
+//     36-hour timeline always ends at noon, even across a daylight saving
+//     change
+//
+// Returns nil if days is empty.
+func Timeline(days []SunTimes, hours int) []SunEvent {
+	if len(days) == 0 {
+		return nil
+	}
+	first := days[0].Date
+	start := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, first.Location())
+	end := time.Date(first.Year(), first.Month(), first.Day(), hours, 0, 0, 0, first.Location())
+
+	var events []SunEvent
+	for _, day := range days {
+		for _, event := range day.Events() {
+			if !event.Time.Before(start) && event.Time.Before(end) {
+				events = append(events, event)
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+	day := func(d int) SunTimes {
+		return SunTimes{
+			Date:          at(d, 0, 0),
+			Sunrise:       at(d, 5, 0),
+			SolarNoon:     at(d, 13, 0),
+			Sunset:        at(d, 21, 0),
+			BlueMorning:   TimeRange{Start: at(d, 4, 30), End: at(d, 4, 50)},
+			GoldenEvening: TimeRange{Start: at(d, 20, 15), End: at(d, 21, 0)},
+			// The evening blue hour runs past midnight
+			BlueEvening: TimeRange{Start: at(d, 23, 30), End: at(d+1, 0, 20)},
+		}
+	}
+	days := []SunTimes{day(1), day(2)}
+
+	kinds := func(events []SunEvent) []EventKind {
+		var k []EventKind
+		for _, e := range events {
+			k = append(k, e.Kind)
+		}
+		return k
+	}
+	oneDay := []EventKind{
+		EventBlueMorningStart, EventBlueMorningEnd, EventSunrise, EventSolarNoon,
+		EventGoldenEveningStart, EventGoldenEveningEnd, EventSunset, EventBlueEveningStart,
+	}
+	if got := kinds(Timeline(days, DayTimelineHours)); !slices.Equal(got, oneDay) {
+		t.Errorf("24-hour timeline = %v, want %v", got, oneDay)
+	}
+
+	// The 36-hour timeline adds the end of the blue hour and the next
+	// morning, up to noon
+	extended := append(oneDay, EventBlueEveningEnd, EventBlueMorningStart, EventBlueMorningEnd, EventSunrise)
+	got := Timeline(days, ExtendedTimelineHours)
+	if !slices.Equal(kinds(got), extended) {
+		t.Errorf("36-hour timeline = %v, want %v", kinds(got), extended)
+	}
+	if last := got[len(got)-1].Time; !last.Equal(at(2, 5, 0)) {
+		t.Errorf("36-hour timeline ends with %v, want the next sunrise", last)
+	}
+
+	if Timeline(nil, ExtendedTimelineHours) != nil {
+		t.Error("Timeline(nil) is not nil")
+	}
+}
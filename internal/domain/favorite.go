@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	"slices"
+)
+
+// =============================================================================
+// Favorites
+// =============================================================================
+
+// Favorite is a location the user saved to come back to, such as a regular
+// shooting spot.
+//
+// Favorites are shown as a point layer on the map and can be included in
+// the daily summary (see DailySummary).
+type Favorite struct {
+	// ID identifies the favorite across edits and restarts (e.g., in
+	// DailySummary.Favorites and as the map point ID). See NewFavoriteID.
+	ID string `json:"id"`
+
+	// Location is the saved place, with its name and timezone.
+	Location Location `json:"location"`
+
+	// Overrides are settings of this spot that replace the general ones
+	// while it is the current location (see FavoriteOverrides). Omitted
+	// from the file when there are none.
+	Overrides FavoriteOverrides `json:"overrides,omitzero"`
+}
+
+// FavoriteOverrides are a favorite's own settings, for recurring spots that
+// need special handling: e.g., a valley where the sun disappears behind a
+// ridge early (a higher golden hour angle), or a spot near a timezone
+// border that the lookup gets wrong.
+//
+// They are applied whenever the favorite becomes the current location,
+// however it is selected (the favorites list, the map or a search at the
+// same coordinates, see FavoriteAt):
+//   - GoldenHourElevation: replaces Settings.GoldenHourElevation for the
+//     calculation (see Settings.ForLocation)
+//   - Timezone and Elevation: replace those of the location (see
+//     Favorite.CurrentLocation)
+type FavoriteOverrides struct {
+	// GoldenHourElevation is the golden hour angle at this spot, in
+	// degrees; nil uses the general setting. Clamped like the setting.
+	GoldenHourElevation *float64 `json:"golden_hour_elevation,omitempty"`
+
+	// Timezone is the IANA timezone used at this spot; empty uses the one
+	// looked up from the coordinates. Cleared if it can't be loaded.
+	Timezone string `json:"timezone,omitempty"`
+
+	// Elevation is the height used at this spot, in meters; nil uses the
+	// location's. Clamped to the range of land on Earth.
+	Elevation *float64 `json:"elevation,omitempty"`
+}
+
+// IsZero reports whether there are no overrides (so none are saved).
+func (o FavoriteOverrides) IsZero() bool {
+	return o.GoldenHourElevation == nil && o.Timezone == "" && o.Elevation == nil
+}
+
+// Validate repairs overrides from a file or the editor: the golden hour
+// angle is clamped to [0, 15]° like the setting, an unknown timezone is
+// cleared, and an elevation outside the range of land on Earth (see
+// Location.Sanitize) is dropped.
+func (o FavoriteOverrides) Validate() FavoriteOverrides {
+	if o.GoldenHourElevation != nil {
+		golden := *o.GoldenHourElevation
+		if math.IsNaN(golden) {
+			o.GoldenHourElevation = nil
+		} else {
+			golden = min(max(golden, 0), 15)
+			o.GoldenHourElevation = &golden
+		}
+	}
+	if o.Timezone != "" && !ValidTimezone(o.Timezone) {
+		o.Timezone = ""
+	}
+	if o.Elevation != nil {
+		if e := *o.Elevation; math.IsNaN(e) || e < minElevation || e > maxElevation {
+			o.Elevation = nil
+		} else {
+			o.Elevation = &e
+		}
+	}
+	return o
+}
+
+// CurrentLocation returns the favorite's location with its timezone and
+// elevation overrides applied, as it is used when selected.
+func (f Favorite) CurrentLocation() Location {
+	loc := f.Location
+	if f.Overrides.Timezone != "" {
+		loc.Timezone = f.Overrides.Timezone
+	}
+	if f.Overrides.Elevation != nil {
+		loc.Elevation = *f.Overrides.Elevation
+	}
+	return loc
+}
+
+// NewFavoriteID returns a random identifier for a new favorite.
+//
+// IDs are 16 hex digits, random rather than derived from the coordinates,
+// so two favorites at the same spot (e.g., "Bridge, sunrise side" and
+// "Bridge, sunset side") stay distinct.
+func NewFavoriteID() string {
+	var b [8]byte
+	// crypto/rand.Read never returns an error
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// FavoriteAt returns the favorite at the location's coordinates, if any.
+//
+// Coordinates are compared to about 1 m (5 decimal places), which tells
+// apart spots a few steps apart but matches a favorite selected again from
+// the map or the saved last location.
+func FavoriteAt(favorites []Favorite, loc Location) (Favorite, bool) {
+	for _, f := range favorites {
+		if sameCoordinate(f.Location.Latitude, loc.Latitude) &&
+			sameCoordinate(f.Location.Longitude, loc.Longitude) {
+			return f, true
+		}
+	}
+	return Favorite{}, false
+}
+
+// ImportFavorites adds places read from a file to the favorites.
+//
+// Places at the coordinates of an existing favorite (see FavoriteAt) or of
+// an earlier place in the list are skipped, so importing the same file
+// twice doesn't duplicate anything. Places with invalid coordinates are
+// skipped too; the rest are cleaned up with Location.Sanitize and get a new
+// ID.
+//
+// Parameters:
+//   - favorites: The current favorites (not modified)
+//   - places: The imported places, with their names
+//
+// Returns:
+//   - []Favorite: The favorites followed by the added places
+//   - int: The number of places added
+func ImportFavorites(favorites []Favorite, places []Location) ([]Favorite, int) {
+	updated := slices.Clone(favorites)
+	for _, loc := range places {
+		if !loc.Sanitize() {
+			continue
+		}
+		if _, ok := FavoriteAt(updated, loc); ok {
+			continue
+		}
+		updated = append(updated, Favorite{ID: NewFavoriteID(), Location: loc})
+	}
+	return updated, len(updated) - len(favorites)
+}
+
+// sameCoordinate reports whether two coordinates are within about 1 m.
+func sameCoordinate(a, b float64) bool {
+	const epsilon = 1e-5
+	return a-b < epsilon && b-a < epsilon
+}
+
+// validateFavorites repairs loaded favorites: entries with invalid
+// coordinates or a missing or duplicate ID are dropped, and the rest are
+// cleaned up with Location.Sanitize and FavoriteOverrides.Validate. Returns
+// a new slice.
+func validateFavorites(favorites []Favorite) []Favorite {
+	var valid []Favorite
+	seen := make(map[string]bool, len(favorites))
+	for _, f := range favorites {
+		if f.ID == "" || seen[f.ID] || !f.Location.Sanitize() {
+			continue
+		}
+		seen[f.ID] = true
+		f.Overrides = f.Overrides.Validate()
+		valid = append(valid, f)
+	}
+	return valid
+}
@@ -0,0 +1,66 @@
+package domain
+
+import "testing"
+
+func TestImportFavorites(t *testing.T) {
+	existing := []Favorite{{ID: "a", Location: Location{Latitude: 48.8584, Longitude: 2.2945, Name: "Eiffel Tower"}}}
+	places := []Location{
+		{Latitude: 48.858401, Longitude: 2.294501, Name: "Eiffel Tower again"}, // Existing favorite
+		{Latitude: 48.8606, Longitude: 2.3376, Name: "  Louvre  "},
+		{Latitude: 48.8606, Longitude: 2.3376, Name: "Louvre duplicate"}, // Earlier in the file
+		{Latitude: 91, Longitude: 0, Name: "Invalid"},
+	}
+
+	got, added := ImportFavorites(existing, places)
+	if added != 1 || len(got) != 2 {
+		t.Fatalf("ImportFavorites added %d, got %+v", added, got)
+	}
+	if got[0].ID != "a" {
+		t.Errorf("existing favorite changed: %+v", got[0])
+	}
+	if got[1].ID == "" || got[1].ID == "a" || got[1].Location.Name != "Louvre" {
+		t.Errorf("added favorite = %+v, want a new ID and the trimmed name", got[1])
+	}
+	if len(existing) != 1 {
+		t.Error("ImportFavorites modified its input")
+	}
+}
+
+func TestFavoriteOverrides(t *testing.T) {
+	golden, elevation := 11.0, 1800.0
+	valley := Favorite{
+		ID:       "v",
+		Location: Location{Latitude: 46.5, Longitude: 8.1, Elevation: 600, Name: "Valley", Timezone: "Europe/Zurich"},
+		Overrides: FavoriteOverrides{
+			GoldenHourElevation: &golden,
+			Timezone:            "Europe/Rome",
+			Elevation:           &elevation,
+		},
+	}
+	s := DefaultSettings()
+	s.Favorites = []Favorite{valley}
+
+	loc := valley.CurrentLocation()
+	if loc.Timezone != "Europe/Rome" || loc.Elevation != 1800 {
+		t.Errorf("CurrentLocation() = %+v, want the overridden timezone and elevation", loc)
+	}
+	if got := s.ForLocation(loc).GoldenHourElevation; got != 11 {
+		t.Errorf("ForLocation(favorite) golden hour elevation = %v, want 11", got)
+	}
+	if got := s.ForLocation(Location{Latitude: 47, Longitude: 8}).GoldenHourElevation; got != 6 {
+		t.Errorf("ForLocation(elsewhere) golden hour elevation = %v, want the setting", got)
+	}
+
+	// Loaded overrides are repaired
+	tooHigh, underground := 40.0, -900.0
+	s.Favorites[0].Overrides = FavoriteOverrides{GoldenHourElevation: &tooHigh, Timezone: "Mars/Olympus_Mons", Elevation: &underground}
+	s.Validate()
+	o := s.Favorites[0].Overrides
+	if o.GoldenHourElevation == nil || *o.GoldenHourElevation != 15 || o.Timezone != "" || o.Elevation != nil {
+		t.Errorf("validated overrides = %+v, want 15° and nothing else", o)
+	}
+	if !(FavoriteOverrides{}).IsZero() || o.IsZero() {
+		t.Errorf("IsZero() of no overrides and of %+v = %v, %v; want true, false",
+			o, (FavoriteOverrides{}).IsZero(), o.IsZero())
+	}
+}
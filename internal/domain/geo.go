@@ -0,0 +1,189 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// =============================================================================
+// Geodesy Helpers
+// =============================================================================
+
+// EarthRadiusMeters is the mean Earth radius used for great-circle calculations.
+//
+// Using a spherical Earth model introduces an error of up to ~0.5% compared
+// to an ellipsoidal model, which is far below what matters for judging
+// whether the sun will rise behind a distant landmark.
+const EarthRadiusMeters = 6371008.8
+
+// compassPoints lists the 16 compass directions in clockwise order from north.
+var compassPoints = [...]string{
+	"N", "NNE", "NE", "ENE", "E", "ESE", "SE", "SSE",
+	"S", "SSW", "SW", "WSW", "W", "WNW", "NW", "NNW",
+}
+
+// toRadians converts degrees to radians.
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// toDegrees converts radians to degrees.
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
+
+// DistanceTo returns the great-circle distance to another location in meters.
+//
+// This uses the haversine formula, which is numerically stable for both
+// short and long distances. Elevation is ignored.
+//
+// Example:
+//
+//	paris := domain.Location{Latitude: 48.8566, Longitude: 2.3522}
+//	london := domain.DefaultLocation()
+//	meters := paris.DistanceTo(london) // ≈ 343,500 m
+func (l Location) DistanceTo(other Location) float64 {
+	lat1 := toRadians(l.Latitude)
+	lat2 := toRadians(other.Latitude)
+	dLat := lat2 - lat1
+	dLon := toRadians(other.Longitude - l.Longitude)
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusMeters * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// BearingTo returns the initial great-circle bearing to another location.
+//
+// The bearing is measured in degrees clockwise from true north (0° = North,
+// 90° = East) and normalized to [0, 360). This matches the convention of the
+// sun azimuth returned by the solar calculator, so the two can be compared
+// directly to tell whether the sun will rise or set behind a landmark.
+func (l Location) BearingTo(other Location) float64 {
+	lat1 := toRadians(l.Latitude)
+	lat2 := toRadians(other.Latitude)
+	dLon := toRadians(other.Longitude - l.Longitude)
+
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return NormalizeDegrees(toDegrees(math.Atan2(y, x)))
+}
+
+// Destination returns the location reached by travelling a distance along a bearing.
+//
+// Parameters:
+//   - bearing: Initial direction in degrees clockwise from north
+//   - meters: Distance to travel along the great circle
+//
+// The returned location has only coordinates set; name and timezone are empty.
+func (l Location) Destination(bearing, meters float64) Location {
+	lat1 := toRadians(l.Latitude)
+	lon1 := toRadians(l.Longitude)
+	brng := toRadians(bearing)
+	angular := meters / EarthRadiusMeters
+
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(angular) +
+		math.Cos(lat1)*math.Sin(angular)*math.Cos(brng))
+	lon2 := lon1 + math.Atan2(math.Sin(brng)*math.Sin(angular)*math.Cos(lat1),
+		math.Cos(angular)-math.Sin(lat1)*math.Sin(lat2))
+
+	return Location{
+		Latitude:  toDegrees(lat2),
+		Longitude: math.Mod(toDegrees(lon2)+540, 360) - 180, // Normalize to [-180, 180)
+	}
+}
+
+// NormalizeDegrees maps an angle in degrees to the range [0, 360).
+func NormalizeDegrees(deg float64) float64 {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return deg
+}
+
+// AngleDifference returns the signed difference a - b between two bearings.
+//
+// The result is normalized to (-180, 180], so that bearings on either side
+// of north compare correctly (e.g., AngleDifference(2, 358) is 4, not -356).
+func AngleDifference(a, b float64) float64 {
+	diff := NormalizeDegrees(a - b)
+	if diff > 180 {
+		diff -= 360
+	}
+	return diff
+}
+
+// CompassPoint returns the 16-point compass direction for a bearing.
+//
+// Example: CompassPoint(247.5) returns "WSW".
+func CompassPoint(bearing float64) string {
+	index := int(math.Round(NormalizeDegrees(bearing)/22.5)) % len(compassPoints)
+	return compassPoints[index]
+}
+
+// FormatDistance returns a distance in meters as a human-readable string.
+//
+// Format rules:
+//   - Under 1 km: whole meters (e.g., "850 m")
+//   - Under 100 km: one decimal (e.g., "12.3 km")
+//   - Otherwise: whole kilometers (e.g., "343 km")
+func FormatDistance(meters float64) string {
+	switch {
+	case meters < 1000:
+		return fmt.Sprintf("%.0f m", meters)
+	case meters < 100000:
+		return fmt.Sprintf("%.1f km", meters/1000)
+	default:
+		return fmt.Sprintf("%.0f km", meters/1000)
+	}
+}
+
+// FormatCoordinates returns a position as decimal degrees with hemisphere
+// letters.
+//
+// Four decimals resolve about 11 m, matching the location panel.
+//
+// Example: FormatCoordinates(51.5074, -0.1278) returns "51.5074° N, 0.1278° W".
+func FormatCoordinates(lat, lon float64) string {
+	return FormatCoordinatesIn(lat, lon, CoordinateFormatDecimal)
+}
+
+// FormatCoordinatesIn returns a position in the given display format
+// (Settings.CoordinateFormat), see FormatLatitude.
+//
+// Example: FormatCoordinatesIn(51.5074, -0.1278, CoordinateFormatDMS)
+// returns `51°30'26.6" N, 0°07'40.1" W`.
+func FormatCoordinatesIn(lat, lon float64, format string) string {
+	return FormatLatitude(lat, format) + ", " + FormatLongitude(lon, format)
+}
+
+// FormatLatitude returns a latitude with its hemisphere letter, as decimal
+// degrees ("51.5074° N") or, for CoordinateFormatDMS, as degrees, minutes
+// and seconds (`51°30'26.6" N`). Tenths of a second resolve about 3 m, a
+// little finer than the four decimals.
+func FormatLatitude(lat float64, format string) string {
+	if lat < 0 {
+		return formatDegrees(-lat, format) + " S"
+	}
+	return formatDegrees(lat, format) + " N"
+}
+
+// FormatLongitude returns a longitude like FormatLatitude: "0.1278° W" or
+// `0°07'40.1" W`.
+func FormatLongitude(lon float64, format string) string {
+	if lon < 0 {
+		return formatDegrees(-lon, format) + " W"
+	}
+	return formatDegrees(lon, format) + " E"
+}
+
+// formatDegrees formats a non-negative angle without hemisphere.
+func formatDegrees(deg float64, format string) string {
+	if format != CoordinateFormatDMS {
+		return fmt.Sprintf("%.4f°", deg)
+	}
+	// Round once, in tenths of a second, so 59.96" carries into the minutes
+	tenths := int64(math.Round(deg * 36000))
+	return fmt.Sprintf("%d°%02d'%02d.%d\"", tenths/36000, tenths/600%60, tenths/10%60, tenths%10)
+}
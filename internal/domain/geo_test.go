@@ -0,0 +1,188 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+var (
+	london = Location{Latitude: 51.5074, Longitude: -0.1278}
+	paris  = Location{Latitude: 48.8566, Longitude: 2.3522}
+)
+
+func TestDistanceTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to Location
+		want     float64 // meters
+		tol      float64
+	}{
+		{"same point", london, london, 0, 1e-6},
+		{"London to Paris", london, paris, 343_900, 500},
+		{"one degree of latitude", Location{}, Location{Latitude: 1}, 111_195, 5},
+		{"one degree of longitude at 60°N", Location{Latitude: 60}, Location{Latitude: 60, Longitude: 1}, 55_597, 5},
+		{"across the antimeridian", Location{Longitude: 179.5}, Location{Longitude: -179.5}, 111_195, 5},
+		{"antipodes", Location{}, Location{Longitude: 180}, math.Pi * EarthRadiusMeters, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.from.DistanceTo(tt.to)
+			if math.Abs(got-tt.want) > tt.tol {
+				t.Errorf("DistanceTo = %.1f m, want %.1f ± %.1f", got, tt.want, tt.tol)
+			}
+			if back := tt.to.DistanceTo(tt.from); math.Abs(back-got) > 1e-6 {
+				t.Errorf("distance is not symmetric: %.3f vs %.3f", got, back)
+			}
+		})
+	}
+}
+
+func TestBearingTo(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to Location
+		want     float64 // degrees
+	}{
+		{"due north", Location{}, Location{Latitude: 1}, 0},
+		{"due east", Location{}, Location{Longitude: 1}, 90},
+		{"due south", Location{}, Location{Latitude: -1}, 180},
+		{"due west", Location{}, Location{Longitude: -1}, 270},
+		{"east across the antimeridian", Location{Longitude: 179.5}, Location{Longitude: -179.5}, 90},
+		{"London to Paris", london, paris, 148.1},
+		{"Paris to London", paris, london, 330.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.from.BearingTo(tt.to)
+			if got < 0 || got >= 360 {
+				t.Fatalf("BearingTo = %.2f, outside [0, 360)", got)
+			}
+			if math.Abs(AngleDifference(got, tt.want)) > 0.1 {
+				t.Errorf("BearingTo = %.2f°, want %.1f°", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDestination(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    Location
+		bearing float64
+		meters  float64
+	}{
+		{"north from the equator", Location{}, 0, 100_000},
+		{"southwest from London", london, 225, 25_000},
+		{"east across the antimeridian", Location{Latitude: 10, Longitude: 179.9}, 90, 50_000},
+		{"zero distance", paris, 123, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dest := tt.from.Destination(tt.bearing, tt.meters)
+			if dest.Longitude < -180 || dest.Longitude > 180 {
+				t.Errorf("longitude %.4f not normalized", dest.Longitude)
+			}
+
+			// Destination must invert DistanceTo/BearingTo
+			if d := tt.from.DistanceTo(dest); math.Abs(d-tt.meters) > 0.01 {
+				t.Errorf("distance to destination = %.3f m, want %.0f", d, tt.meters)
+			}
+			if tt.meters > 0 {
+				if b := tt.from.BearingTo(dest); math.Abs(AngleDifference(b, tt.bearing)) > 1e-6 {
+					t.Errorf("bearing to destination = %.6f°, want %.1f°", b, tt.bearing)
+				}
+			}
+		})
+	}
+}
+
+func TestAngleHelpers(t *testing.T) {
+	tests := []struct {
+		a, b        float64
+		wantDiff    float64
+		wantCompass string // compass point of a
+		wantNormal  float64
+	}{
+		{2, 358, 4, "N", 2},
+		{358, 2, -4, "N", 358},
+		{-90, 0, -90, "W", 270},
+		{540, 0, 180, "S", 180},
+		{247.5, 0, -112.5, "WSW", 247.5},
+		{11.24, 0, 11.24, "N", 11.24},
+		{11.26, 0, 11.26, "NNE", 11.26},
+	}
+
+	for _, tt := range tests {
+		if got := AngleDifference(tt.a, tt.b); math.Abs(got-tt.wantDiff) > 1e-9 {
+			t.Errorf("AngleDifference(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.wantDiff)
+		}
+		if got := CompassPoint(tt.a); got != tt.wantCompass {
+			t.Errorf("CompassPoint(%v) = %q, want %q", tt.a, got, tt.wantCompass)
+		}
+		if got := NormalizeDegrees(tt.a); math.Abs(got-tt.wantNormal) > 1e-9 {
+			t.Errorf("NormalizeDegrees(%v) = %v, want %v", tt.a, got, tt.wantNormal)
+		}
+	}
+}
+
+func TestFormatDistance(t *testing.T) {
+	tests := []struct {
+		meters float64
+		want   string
+	}{
+		{0, "0 m"},
+		{850.4, "850 m"},
+		{1000, "1.0 km"},
+		{12_345, "12.3 km"},
+		{343_456, "343 km"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDistance(tt.meters); got != tt.want {
+			t.Errorf("FormatDistance(%v) = %q, want %q", tt.meters, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCoordinates(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		want     string
+	}{
+		{51.5074, -0.1278, "51.5074° N, 0.1278° W"},
+		{-33.85678, 151.21530, "33.8568° S, 151.2153° E"},
+		{0, 0, "0.0000° N, 0.0000° E"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatCoordinates(tt.lat, tt.lon); got != tt.want {
+			t.Errorf("FormatCoordinates(%v, %v) = %q, want %q", tt.lat, tt.lon, got, tt.want)
+		}
+	}
+}
+
+func TestFormatCoordinatesDMS(t *testing.T) {
+	tests := []struct {
+		lat, lon float64
+		want     string
+	}{
+		{51.5074, -0.1278, `51°30'26.6" N, 0°07'40.1" W`},
+		{-33.85678, 151.21530, `33°51'24.4" S, 151°12'55.1" E`},
+		{0, 0, `0°00'00.0" N, 0°00'00.0" E`},
+		// 59.99" rounds up into the next minute and degree
+		{10.99999722, -20.5, `11°00'00.0" N, 20°30'00.0" W`},
+	}
+
+	for _, tt := range tests {
+		if got := FormatCoordinatesIn(tt.lat, tt.lon, CoordinateFormatDMS); got != tt.want {
+			t.Errorf("FormatCoordinatesIn(%v, %v, dms) = %q, want %q", tt.lat, tt.lon, got, tt.want)
+		}
+	}
+
+	if got, want := FormatCoordinatesIn(51.5074, -0.1278, "unknown"), "51.5074° N, 0.1278° W"; got != want {
+		t.Errorf("FormatCoordinatesIn with unknown format = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// =============================================================================
+// Automation Hooks
+// =============================================================================
+
+// Hook is a user-configured command that runs at a phase transition.
+//
+// Hooks let photographers automate their setup, for example triggering a
+// tethered camera with gphoto2 when golden hour starts, or starting a
+// timelapse script at sunset. They are configured in the Automation tab of
+// the preferences dialog and executed by the automation service.
+//
+// The command is split into arguments like a shell would (quotes and
+// backslash escapes are honored), but it is NOT run through a shell. Each
+// argument may contain template placeholders such as {{.Time}}; see the
+// automation package for the full list.
+//
+// A hook only runs once the user has approved its exact command: Confirmed
+// holds the fingerprint of the approved command, so editing the command
+// (or a settings file written by hand or by an older version) requires a
+// new confirmation in the preferences dialog.
+//
+// Example:
+//
+//	Hook{
+//	    Event:   EventGoldenEveningStart,
+//	    Command: `gphoto2 --capture-image --filename "{{.Date}}-%n.jpg"`,
+//	    Enabled: true,
+//	}
+type Hook struct {
+	// Event is the phase transition that triggers the hook.
+	Event EventKind `json:"event"`
+
+	// Command is the program and arguments to run (template placeholders allowed).
+	Command string `json:"command"`
+
+	// Enabled allows a hook to be kept in the list without running it.
+	Enabled bool `json:"enabled"`
+
+	// Confirmed is the CommandFingerprint of the command the user approved
+	// (empty if never approved).
+	Confirmed string `json:"confirmed,omitempty"`
+}
+
+// CommandFingerprint returns the hex SHA-256 digest of a hook command.
+//
+// The digest is stored instead of a flag so that an approval only covers the
+// command text that was shown to the user.
+func CommandFingerprint(command string) string {
+	sum := sha256.Sum256([]byte(command))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsConfirmed reports whether the user approved the hook's current command.
+func (h Hook) IsConfirmed() bool {
+	return h.Command != "" && h.Confirmed == CommandFingerprint(h.Command)
+}
+
+// IsRunnable reports whether the hook may run: it is enabled and its
+// command has been approved.
+func (h Hook) IsRunnable() bool {
+	return h.Enabled && h.IsConfirmed()
+}
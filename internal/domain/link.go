@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Share Links
+// =============================================================================
+
+// LinkScheme is the URL scheme of share links, registered with the desktop
+// so clicking a link opens the app (see Link).
+const LinkScheme = "gogoldenhour"
+
+// Link is a shareable location and date, so someone else can open the app
+// on exactly the same spot and day:
+//
+//	gogoldenhour://?lat=48.8566&lon=2.3522&date=2025-06-21&name=Pont+Neuf
+//
+// The date and name are optional; without a date the app stays on the
+// date it shows, and without a name the place is looked up.
+type Link struct {
+	// Latitude and Longitude are the place's coordinates in decimal degrees.
+	Latitude  float64
+	Longitude float64
+
+	// Date is the day, at midnight local time; zero if the link has none.
+	Date time.Time
+
+	// Name is the place name; empty if the link has none.
+	Name string
+}
+
+// URL returns the link as a gogoldenhour:// URL. Coordinates are rounded to
+// five decimals (about a meter).
+func (l Link) URL() string {
+	// Written by hand rather than with url.Values.Encode, which sorts the
+	// keys; coordinates first read better in a chat
+	link := LinkScheme + "://?lat=" + strconv.FormatFloat(l.Latitude, 'f', 5, 64) +
+		"&lon=" + strconv.FormatFloat(l.Longitude, 'f', 5, 64)
+	if !l.Date.IsZero() {
+		link += "&date=" + l.Date.Format("2006-01-02")
+	}
+	if l.Name != "" {
+		link += "&name=" + url.QueryEscape(l.Name)
+	}
+	return link
+}
+
+// Location returns the place of the link, with the name if it has one.
+// The timezone is left for the caller to look up.
+func (l Link) Location() Location {
+	return Location{Latitude: l.Latitude, Longitude: l.Longitude, Name: l.Name}
+}
+
+// IsLink reports whether text looks like a share link (starts with
+// "gogoldenhour:"), as opposed to an option or another argument.
+func IsLink(text string) bool {
+	return strings.HasPrefix(strings.ToLower(strings.TrimSpace(text)), LinkScheme+":")
+}
+
+// ParseLink parses a share link.
+//
+// Besides the canonical form returned by Link.URL, "gogoldenhour:?lat=..."
+// and "gogoldenhour://open?lat=..." are accepted, since desktops and chat
+// apps rewrite links differently. Unknown parameters are ignored, so later
+// versions can add more.
+//
+// Returns an error if the text isn't a gogoldenhour link, the coordinates
+// are missing or out of range, or the date isn't YYYY-MM-DD.
+func ParseLink(text string) (Link, error) {
+	u, err := url.Parse(strings.TrimSpace(text))
+	if err != nil {
+		return Link{}, fmt.Errorf("invalid link: %w", err)
+	}
+	if !strings.EqualFold(u.Scheme, LinkScheme) {
+		return Link{}, fmt.Errorf("not a %s:// link", LinkScheme)
+	}
+	return ParseLinkQuery(u.Query())
+}
+
+// ParseLinkQuery reads a link's parameters (lat, lon, and optionally date
+// and name). The command line's --lat/--lon/--date/--name options are read
+// with it too, so they behave exactly like a link.
+func ParseLinkQuery(query url.Values) (Link, error) {
+	if !query.Has("lat") || !query.Has("lon") {
+		return Link{}, errors.New("the link has no lat and lon")
+	}
+	// NaN fails every comparison, so IsValid rejects it too
+	lat, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil || !(Location{Latitude: lat}).IsValid() {
+		return Link{}, fmt.Errorf("invalid latitude %q (use -90 to 90)", query.Get("lat"))
+	}
+	lon, err := strconv.ParseFloat(query.Get("lon"), 64)
+	if err != nil || !(Location{Longitude: lon}).IsValid() {
+		return Link{}, fmt.Errorf("invalid longitude %q (use -180 to 180)", query.Get("lon"))
+	}
+
+	// The name comes from whoever wrote the link
+	link := Link{Latitude: lat, Longitude: lon, Name: sanitizeName(query.Get("name"))}
+	if text := query.Get("date"); text != "" {
+		link.Date, err = time.ParseInLocation("2006-01-02", text, time.Local)
+		if err != nil {
+			return Link{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD)", text)
+		}
+	}
+	return link, nil
+}
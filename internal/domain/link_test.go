@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestLinkRoundTrip(t *testing.T) {
+	link := Link{
+		Latitude:  48.85661,
+		Longitude: 2.35222,
+		Date:      time.Date(2025, time.June, 21, 0, 0, 0, 0, time.Local),
+		Name:      "Pont Neuf & Seine",
+	}
+	text := link.URL()
+	if want := "gogoldenhour://?lat=48.85661&lon=2.35222&date=2025-06-21&name=Pont+Neuf+%26+Seine"; text != want {
+		t.Errorf("URL() = %q, want %q", text, want)
+	}
+
+	parsed, err := ParseLink(text)
+	if err != nil {
+		t.Fatalf("ParseLink(%q): %v", text, err)
+	}
+	if parsed.Latitude != link.Latitude || parsed.Longitude != link.Longitude ||
+		!parsed.Date.Equal(link.Date) || parsed.Name != link.Name {
+		t.Errorf("ParseLink(%q) = %+v, want %+v", text, parsed, link)
+	}
+}
+
+func TestParseLink(t *testing.T) {
+	for _, text := range []string{
+		"gogoldenhour://?lat=48.85&lon=2.35",
+		"GoGoldenHour:?lat=48.85&lon=2.35&zoom=12",
+		"gogoldenhour://open?lat=48.85&lon=2.35&date=",
+	} {
+		link, err := ParseLink(text)
+		if err != nil {
+			t.Errorf("ParseLink(%q): %v", text, err)
+			continue
+		}
+		if link.Latitude != 48.85 || link.Longitude != 2.35 || !link.Date.IsZero() {
+			t.Errorf("ParseLink(%q) = %+v", text, link)
+		}
+	}
+
+	for _, text := range []string{
+		"https://example.com/?lat=48.85&lon=2.35",
+		"gogoldenhour://?lat=48.85",
+		"gogoldenhour://?lat=91&lon=2.35",
+		"gogoldenhour://?lat=NaN&lon=2.35",
+		"gogoldenhour://?lat=48.85&lon=2.35&date=21.06.2025",
+	} {
+		if _, err := ParseLink(text); err == nil {
+			t.Errorf("ParseLink(%q) = nil error, want one", text)
+		}
+	}
+
+	link, err := ParseLinkQuery(url.Values{"lat": {"-33.9"}, "lon": {"18.4"}, "name": {" Cape\nPoint "}})
+	if err != nil || link.Name != "Cape Point" {
+		t.Errorf("ParseLinkQuery name = %q, %v; want %q", link.Name, err, "Cape Point")
+	}
+}
+
+func TestIsLink(t *testing.T) {
+	for text, want := range map[string]bool{
+		"gogoldenhour://?lat=1&lon=2": true,
+		" GOGOLDENHOUR:?lat=1":        true,
+		"--profile":                   false,
+		"https://example.com":         false,
+	} {
+		if got := IsLink(text); got != want {
+			t.Errorf("IsLink(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
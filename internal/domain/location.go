@@ -15,6 +15,25 @@
 //   - Settings: User-configurable preferences for calculations and display
 package domain
 
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLocationNameLength is the longest location name kept, in characters.
+// Geocoded names are far shorter; longer ones come from damaged files.
+const MaxLocationNameLength = 200
+
+// Elevation limits for stored locations, in meters: the shore of the Dead
+// Sea lies about 430 m below sea level, the summit of Everest 8849 m above.
+const (
+	minElevation = -500
+	maxElevation = 9000
+)
+
 // Location represents a geographic point on Earth with associated metadata.
 //
 // Locations are used as input to the solar calculator and are obtained from:
@@ -52,6 +71,42 @@ type Location struct {
 	// Required for converting UTC sun times to local time for display.
 	// Automatically determined from coordinates using the tzf library.
 	Timezone string `json:"timezone"`
+
+	// City, Region and Country are the parts of the address the geocoding
+	// service provided (e.g., "Paris", "Île-de-France", "France"); empty
+	// when unknown. They make up the shorter names of DisplayName.
+	City    string `json:"city,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// DisplayName returns the location's name in a place name style
+// (Settings.PlaceNameStyle).
+//
+// PlaceNameFull is the complete Name. PlaceNameShort names the city (or
+// the region, outside cities) and country, e.g., "Paris, France";
+// PlaceNameMedium adds the region: "Paris, Île-de-France, France". Parts
+// repeating the previous one are left out ("Singapore", not "Singapore,
+// Singapore"). Locations without address parts, such as those saved by
+// earlier versions, keep their Name.
+func (l Location) DisplayName(style string) string {
+	if style == PlaceNameFull {
+		return l.Name
+	}
+	parts := []string{l.City, l.Region, l.Country}
+	if style != PlaceNameMedium && l.City != "" {
+		parts = []string{l.City, l.Country}
+	}
+	var kept []string
+	for _, part := range parts {
+		if part != "" && (len(kept) == 0 || kept[len(kept)-1] != part) {
+			kept = append(kept, part)
+		}
+	}
+	if len(kept) == 0 {
+		return l.Name
+	}
+	return strings.Join(kept, ", ")
 }
 
 // IsValid checks if the location has valid geographic coordinates.
@@ -69,9 +124,78 @@ func (l Location) IsValid() bool {
 		l.Longitude >= -180 && l.Longitude <= 180
 }
 
+// Sanitize repairs the metadata of a location read from an untrusted source
+// (a settings file) and reports whether the location is usable.
+//
+// Coordinates can't be repaired: if they are out of range or not numbers,
+// Sanitize returns false and the location should be dropped. Otherwise:
+//   - Name, City, Region, Country: invalid UTF-8 and control characters
+//     (e.g., newlines) are replaced, and the text is trimmed and cut to
+//     MaxLocationNameLength
+//   - Elevation: values that aren't finite or lie outside the range of land
+//     on Earth are reset to 0
+//   - Timezone: identifiers that time.LoadLocation rejects are cleared, so
+//     the caller can look the zone up from the coordinates again
+//
+// The method modifies the Location in place (receiver is a pointer).
+func (l *Location) Sanitize() bool {
+	// NaN fails every comparison, so IsValid rejects it too
+	if !l.IsValid() {
+		return false
+	}
+
+	l.Name = sanitizeName(l.Name)
+	l.City = sanitizeName(l.City)
+	l.Region = sanitizeName(l.Region)
+	l.Country = sanitizeName(l.Country)
+
+	if math.IsNaN(l.Elevation) || l.Elevation < minElevation || l.Elevation > maxElevation {
+		l.Elevation = 0
+	}
+
+	if l.Timezone != "" {
+		if _, err := time.LoadLocation(l.Timezone); err != nil {
+			l.Timezone = ""
+		}
+	}
+	return true
+}
+
+// sanitizeName cleans up a name for Sanitize: invalid UTF-8 and control
+// characters are replaced, and the name is trimmed and cut to
+// MaxLocationNameLength.
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, strings.ToValidUTF8(name, "\uFFFD"))
+	name = strings.TrimSpace(name)
+	if utf8.RuneCountInString(name) > MaxLocationNameLength {
+		name = strings.TrimSpace(string([]rune(name)[:MaxLocationNameLength]))
+	}
+	return name
+}
+
+// SearchResult is a location found by a place search.
+//
+// Place names are often ambiguous (there are dozens of Springfields), so
+// the kind of place is kept alongside the location to help the user pick
+// the right one.
+type SearchResult struct {
+	// Location is the place found, with its name and timezone.
+	Location Location
+
+	// Type is the kind of place in plain words (e.g., "city", "peak",
+	// "viewpoint"); empty if unknown.
+	Type string
+}
+
 // DefaultLocation returns London, UK as the fallback location.
 //
-// This is used when:
+// This is used, unless the user set a home location (see Settings.Home),
+// when:
 //   - The application starts without a saved location
 //   - IP-based geolocation fails
 //   - The user's saved location cannot be loaded
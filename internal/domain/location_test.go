@@ -0,0 +1,41 @@
+package domain
+
+import "testing"
+
+func TestLocationDisplayName(t *testing.T) {
+	paris := Location{
+		Name: "Eiffel Tower, Avenue Gustave Eiffel, Paris, Île-de-France, France",
+		City: "Paris", Region: "Île-de-France", Country: "France",
+	}
+	singapore := Location{Name: "Marina Bay, Singapore", City: "Singapore", Country: "Singapore"}
+	ridge := Location{Name: "Ben Nevis, Highland, Scotland, United Kingdom", Region: "Scotland", Country: "United Kingdom"}
+	old := Location{Name: "Paris, Île-de-France, France"}
+
+	tests := []struct {
+		loc   Location
+		style string
+		want  string
+	}{
+		{paris, PlaceNameShort, "Paris, France"},
+		{paris, PlaceNameMedium, "Paris, Île-de-France, France"},
+		{paris, PlaceNameFull, paris.Name},
+		{singapore, PlaceNameShort, "Singapore"},
+		{ridge, PlaceNameShort, "Scotland, United Kingdom"},
+		{old, PlaceNameShort, old.Name},
+	}
+	for _, tt := range tests {
+		if got := tt.loc.DisplayName(tt.style); got != tt.want {
+			t.Errorf("DisplayName(%q) of %q = %q, want %q", tt.style, tt.loc.Name, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeAddress(t *testing.T) {
+	loc := Location{Latitude: 48.85, Longitude: 2.35, City: " Paris\n", Country: "Fr\x00ance"}
+	if !loc.Sanitize() {
+		t.Fatal("Sanitize() = false")
+	}
+	if loc.City != "Paris" || loc.Country != "Fr ance" {
+		t.Errorf("City, Country = %q, %q; want them cleaned", loc.City, loc.Country)
+	}
+}
@@ -0,0 +1,149 @@
+package domain
+
+import "time"
+
+// =============================================================================
+// Location Providers
+// =============================================================================
+
+// Location detection providers, stored in LocationProvider.ID.
+const (
+	// LocationProviderIPAPI detects the location from the public IP address
+	// with ip-api.com.
+	LocationProviderIPAPI = "ip-api"
+
+	// LocationProviderIPInfo detects the location from the public IP
+	// address with ipinfo.io, over HTTPS. A second IP service keeps
+	// detection working on networks that block the first.
+	LocationProviderIPInfo = "ipinfo"
+
+	// LocationProviderSystem uses the operating system's location services
+	// (GeoClue2 on Linux, Windows Location API on Windows).
+	LocationProviderSystem = "system"
+)
+
+// Timeout limits of a location provider, in seconds.
+const (
+	minProviderTimeout = 1
+	maxProviderTimeout = 120
+)
+
+// LocationProvider is one backend in the location detection fallback order
+// (Settings.LocationProviders).
+//
+// Detection tries the enabled providers in order, each for at most its
+// timeout, until one finds the location. The map's browser geolocation is
+// not a provider: it runs in the map page and is chosen with
+// Settings.LocationSource, falling back to the providers.
+type LocationProvider struct {
+	// ID identifies the backend: LocationProviderIPAPI,
+	// LocationProviderIPInfo or LocationProviderSystem.
+	ID string `json:"id"`
+
+	// Enabled includes the provider in detection.
+	Enabled bool `json:"enabled"`
+
+	// TimeoutSeconds is how long to wait for the provider before trying
+	// the next one (1 to 120).
+	TimeoutSeconds int `json:"timeout_seconds"`
+}
+
+// Timeout returns TimeoutSeconds as a duration.
+func (p LocationProvider) Timeout() time.Duration {
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+// Label returns the provider's display name (e.g., "ipinfo.io").
+func (p LocationProvider) Label() string {
+	switch p.ID {
+	case LocationProviderIPAPI:
+		return "ip-api.com (IP address)"
+	case LocationProviderIPInfo:
+		return "ipinfo.io (IP address, HTTPS)"
+	case LocationProviderSystem:
+		return "System location services"
+	default:
+		return p.ID
+	}
+}
+
+// Online reports whether the provider asks a web service, which
+// Settings.PrivacyMode rules out. The system location services are the
+// operating system's, and are used in privacy mode too.
+func (p LocationProvider) Online() bool {
+	return p.ID != LocationProviderSystem
+}
+
+// Encrypted reports whether the provider's answer can't be read on the
+// network: the request goes over HTTPS (ipinfo.io) or doesn't leave the
+// machine (system location services). See Settings.SecureLocationOnly.
+func (p LocationProvider) Encrypted() bool {
+	return p.ID != LocationProviderIPAPI
+}
+
+// DefaultLocationProviders returns the default fallback order: the two IP
+// services, then the (off by default) system location services.
+//
+// The IP services answer within a second or two, so 10 seconds leaves room
+// for a slow network; a first Wi-Fi fix or a permission prompt of the
+// system location services can take much longer.
+func DefaultLocationProviders() []LocationProvider {
+	return []LocationProvider{
+		{ID: LocationProviderIPAPI, Enabled: true, TimeoutSeconds: 10},
+		{ID: LocationProviderIPInfo, Enabled: true, TimeoutSeconds: 10},
+		{ID: LocationProviderSystem, Enabled: false, TimeoutSeconds: 30},
+	}
+}
+
+// DetectionOrder returns the providers to try, in order, for the selected
+// LocationSource.
+//
+// With LocationSourceSystem the system location services go first (even if
+// disabled in the list), followed by the other enabled providers; otherwise
+// the enabled providers are used as listed. Online providers are left out
+// in privacy mode, and unencrypted ones with SecureLocationOnly. An empty
+// result means no provider may be used.
+func (s Settings) DetectionOrder() []LocationProvider {
+	var order []LocationProvider
+	for _, p := range s.LocationProviders {
+		switch {
+		case s.PrivacyMode && p.Online(), s.SecureLocationOnly && !p.Encrypted():
+			continue
+		case s.LocationSource == LocationSourceSystem && p.ID == LocationProviderSystem:
+			p.Enabled = true
+			order = append([]LocationProvider{p}, order...)
+		case p.Enabled:
+			order = append(order, p)
+		}
+	}
+	return order
+}
+
+// RepairLocationProviders repairs a loaded or edited fallback order: unknown and
+// repeated providers are dropped, timeouts are clamped, and providers
+// missing from the list (e.g., added in a newer version) are appended with
+// their defaults. Returns a new slice.
+func RepairLocationProviders(providers []LocationProvider) []LocationProvider {
+	defaults := DefaultLocationProviders()
+	known := make(map[string]bool, len(defaults))
+	for _, p := range defaults {
+		known[p.ID] = true
+	}
+
+	valid := make([]LocationProvider, 0, len(defaults))
+	seen := make(map[string]bool, len(defaults))
+	for _, p := range providers {
+		if !known[p.ID] || seen[p.ID] {
+			continue
+		}
+		seen[p.ID] = true
+		p.TimeoutSeconds = max(minProviderTimeout, min(p.TimeoutSeconds, maxProviderTimeout))
+		valid = append(valid, p)
+	}
+	for _, p := range defaults {
+		if !seen[p.ID] {
+			valid = append(valid, p)
+		}
+	}
+	return valid
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateLocationProviders(t *testing.T) {
+	got := RepairLocationProviders([]LocationProvider{
+		{ID: LocationProviderSystem, Enabled: true, TimeoutSeconds: 500},
+		{ID: "carrier-pigeon", Enabled: true, TimeoutSeconds: 10},
+		{ID: LocationProviderSystem, Enabled: false, TimeoutSeconds: 5},
+		{ID: LocationProviderIPInfo, Enabled: false, TimeoutSeconds: 0},
+	})
+	want := []LocationProvider{
+		{ID: LocationProviderSystem, Enabled: true, TimeoutSeconds: maxProviderTimeout},
+		{ID: LocationProviderIPInfo, Enabled: false, TimeoutSeconds: minProviderTimeout},
+		{ID: LocationProviderIPAPI, Enabled: true, TimeoutSeconds: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RepairLocationProviders = %+v, want %+v", got, want)
+	}
+}
+
+func TestDetectionOrder(t *testing.T) {
+	ids := func(providers []LocationProvider) []string {
+		var list []string
+		for _, p := range providers {
+			list = append(list, p.ID)
+		}
+		return list
+	}
+
+	s := DefaultSettings()
+	if got, want := ids(s.DetectionOrder()), []string{LocationProviderIPAPI, LocationProviderIPInfo}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IP source order = %v, want %v", got, want)
+	}
+
+	s.LocationSource = LocationSourceSystem
+	order := s.DetectionOrder()
+	if got, want := ids(order), []string{LocationProviderSystem, LocationProviderIPAPI, LocationProviderIPInfo}; !reflect.DeepEqual(got, want) {
+		t.Errorf("system source order = %v, want %v", got, want)
+	}
+	if !order[0].Enabled || s.LocationProviders[2].Enabled {
+		t.Error("DetectionOrder should enable the system provider in its result only")
+	}
+
+	s.SecureLocationOnly = true
+	if got, want := ids(s.DetectionOrder()), []string{LocationProviderSystem, LocationProviderIPInfo}; !reflect.DeepEqual(got, want) {
+		t.Errorf("secure only order = %v, want %v", got, want)
+	}
+	s.PrivacyMode = true
+	if got, want := ids(s.DetectionOrder()), []string{LocationProviderSystem}; !reflect.DeepEqual(got, want) {
+		t.Errorf("privacy mode order = %v, want %v", got, want)
+	}
+	s.SecureLocationOnly, s.PrivacyMode = false, false
+
+	for i := range s.LocationProviders {
+		s.LocationProviders[i].Enabled = false
+	}
+	s.LocationSource = LocationSourceIP
+	if order := s.DetectionOrder(); len(order) != 0 {
+		t.Errorf("all disabled: order = %v, want none", ids(order))
+	}
+}
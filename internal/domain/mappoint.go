@@ -0,0 +1,33 @@
+package domain
+
+// =============================================================================
+// Map Points
+// =============================================================================
+
+// MapPoint is a location shown as a pin in one of the map's point layers.
+//
+// Point layers hold collections such as saved favorites, photo spots, or
+// imported waypoints. They can contain hundreds of points, so the map
+// clusters nearby pins and only draws those inside the visible area.
+//
+// ID identifies the point within its layer. When a layer is updated, points
+// are matched by ID so only added, removed, or changed points are sent to
+// the map page; it must therefore be stable across updates (e.g., a database
+// key or a file position) and unique within the layer.
+type MapPoint struct {
+	// ID is the point's stable identifier within its layer.
+	ID string
+
+	// Location is the point's position; Name is shown as the pin's tooltip.
+	Location Location
+}
+
+// Track is a line drawn in one of the map's point layers, such as a hiking
+// route or a recorded GPS trace from an imported file.
+type Track struct {
+	// Name is the track's display name (may be empty).
+	Name string
+
+	// Points is the line in drawing order (only coordinates are used).
+	Points []Location
+}
@@ -0,0 +1,157 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Meteor Showers
+// =============================================================================
+
+// MeteorShower is one of the major annual meteor showers.
+//
+// The Earth crosses the shower's stream of comet dust around the same date
+// every year, so the peak is given as a calendar date. Rates are highest
+// after midnight, when the observer faces the way the Earth moves, and
+// when the radiant (the point the meteors appear to come from) stands high.
+type MeteorShower struct {
+	// Name is the shower's name, e.g., "Perseids".
+	Name string
+
+	// PeakMonth and PeakDay give the evening that starts the peak night,
+	// e.g., August 12 for the Perseids' night of August 12-13.
+	PeakMonth time.Month
+	PeakDay   int
+
+	// ZHR is the zenithal hourly rate: the meteors per hour an observer
+	// would see at the peak under a perfectly dark sky with the radiant
+	// overhead. Real counts are lower.
+	ZHR int
+
+	// RadiantDeclination is the declination of the radiant at the peak, in
+	// degrees. Showers with a far northern radiant can't be seen from far
+	// south, and the other way around.
+	RadiantDeclination float64
+}
+
+// majorMeteorShowers are the showers with the highest rates, in calendar
+// order (International Meteor Organization working list).
+var majorMeteorShowers = []MeteorShower{
+	{Name: "Quadrantids", PeakMonth: time.January, PeakDay: 3, ZHR: 110, RadiantDeclination: 49},
+	{Name: "Lyrids", PeakMonth: time.April, PeakDay: 21, ZHR: 18, RadiantDeclination: 34},
+	{Name: "Eta Aquariids", PeakMonth: time.May, PeakDay: 5, ZHR: 50, RadiantDeclination: -1},
+	{Name: "Southern Delta Aquariids", PeakMonth: time.July, PeakDay: 29, ZHR: 25, RadiantDeclination: -16},
+	{Name: "Perseids", PeakMonth: time.August, PeakDay: 12, ZHR: 100, RadiantDeclination: 58},
+	{Name: "Orionids", PeakMonth: time.October, PeakDay: 20, ZHR: 20, RadiantDeclination: 16},
+	{Name: "Leonids", PeakMonth: time.November, PeakDay: 16, ZHR: 15, RadiantDeclination: 22},
+	{Name: "Geminids", PeakMonth: time.December, PeakDay: 13, ZHR: 150, RadiantDeclination: 33},
+	{Name: "Ursids", PeakMonth: time.December, PeakDay: 21, ZHR: 10, RadiantDeclination: 75},
+}
+
+// MeteorShowers returns the major annual meteor showers in calendar order.
+func MeteorShowers() []MeteorShower {
+	showers := make([]MeteorShower, len(majorMeteorShowers))
+	copy(showers, majorMeteorShowers)
+	return showers
+}
+
+// MeteorShowersOn returns the showers whose peak night starts on the
+// evening of date (only its calendar date is used).
+func MeteorShowersOn(date time.Time) []MeteorShower {
+	var showers []MeteorShower
+	for _, s := range majorMeteorShowers {
+		if s.PeakMonth == date.Month() && s.PeakDay == date.Day() {
+			showers = append(showers, s)
+		}
+	}
+	return showers
+}
+
+// RadiantAltitude returns the highest the radiant climbs in the sky at a
+// latitude, in degrees: 90° minus its distance from the zenith when it
+// crosses the meridian. Negative if it never rises.
+func (s MeteorShower) RadiantAltitude(latitude float64) float64 {
+	return 90 - math.Abs(latitude-s.RadiantDeclination)
+}
+
+// Thresholds of a good meteor night (see MeteorNight.Good).
+const (
+	// MeteorDarkElevation is the sun elevation below which the sky is
+	// fully dark: the end of astronomical twilight. Faint meteors are lost
+	// in any brighter sky.
+	MeteorDarkElevation = AstronomicalTwilightElevation
+
+	// MeteorMoonBright is the moon's illuminated fraction, in percent, from
+	// which it washes out faint meteors while above the horizon. A thinner
+	// crescent doesn't matter.
+	MeteorMoonBright = 25.0
+
+	// MinMeteorDarkness is the shortest stretch of moonless dark worth
+	// going out for.
+	MinMeteorDarkness = 2 * time.Hour
+
+	// minRadiantAltitude is how high the radiant must climb for a fair
+	// share of the meteors to reach the sky above the horizon.
+	minRadiantAltitude = 20.0
+)
+
+// MeteorNight rates a shower's peak night at a place.
+type MeteorNight struct {
+	// Shower is the shower peaking this night.
+	Shower MeteorShower
+
+	// Date is the evening that starts the night.
+	Date time.Time
+
+	// Dark is when the sun is below MeteorDarkElevation, from the evening
+	// of Date to the next morning; invalid if it doesn't get that low.
+	Dark TimeRange
+
+	// Moonless is the longest part of Dark with the moon below the horizon
+	// or too thin to matter (under MeteorMoonBright); invalid if there is
+	// none.
+	Moonless TimeRange
+
+	// MoonIllumination is the moon's illuminated fraction during the night,
+	// in percent.
+	MoonIllumination float64
+
+	// RadiantAltitude is the highest the radiant climbs at the place (see
+	// MeteorShower.RadiantAltitude).
+	RadiantAltitude float64
+}
+
+// Good reports whether the night is worth going out for: at least
+// MinMeteorDarkness of moonless dark, with the radiant high enough.
+func (n MeteorNight) Good() bool {
+	return n.Moonless.IsValid() && n.Moonless.Duration() >= MinMeteorDarkness &&
+		n.RadiantAltitude >= minRadiantAltitude
+}
+
+// Summary describes the night in one line, e.g.:
+//
+//	Perseids peak: moonless dark 22:41 - 03:52 (5h 11m), up to 100/h
+//	Geminids peak: only 1h 10m moonless (moon 87% lit)
+//	Quadrantids peak: radiant too low here
+//
+// Parameters:
+//   - use24Hour: Time format
+func (n MeteorNight) Summary(use24Hour bool) string {
+	prefix := n.Shower.Name + " peak: "
+	switch {
+	case n.RadiantAltitude < minRadiantAltitude:
+		return prefix + "radiant too low here"
+	case !n.Dark.IsValid():
+		return prefix + "no astronomical darkness"
+	case !n.Moonless.IsValid():
+		return prefix + fmt.Sprintf("moonlit all night (%.0f%% lit)", n.MoonIllumination)
+	case !n.Good():
+		return prefix + fmt.Sprintf("only %s moonless (moon %.0f%% lit)",
+			n.Moonless.FormatDuration(), n.MoonIllumination)
+	}
+	return prefix + fmt.Sprintf("moonless dark %s - %s (%s), up to %d/h",
+		FormatTime(n.Moonless.Start, use24Hour), FormatTime(n.Moonless.End, use24Hour),
+		n.Moonless.FormatDuration(), n.Shower.ZHR)
+}
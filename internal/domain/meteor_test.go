@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMeteorShowersOn(t *testing.T) {
+	showers := MeteorShowersOn(time.Date(2026, time.December, 13, 21, 0, 0, 0, time.UTC))
+	if len(showers) != 1 || showers[0].Name != "Geminids" {
+		t.Errorf("December 13 = %+v, want the Geminids", showers)
+	}
+	if showers := MeteorShowersOn(time.Date(2026, time.December, 14, 0, 0, 0, 0, time.UTC)); len(showers) != 0 {
+		t.Errorf("December 14 = %+v, want none", showers)
+	}
+
+	// In calendar order
+	all := MeteorShowers()
+	for i := 1; i < len(all); i++ {
+		if all[i].PeakMonth < all[i-1].PeakMonth {
+			t.Errorf("%s comes before %s", all[i-1].Name, all[i].Name)
+		}
+	}
+}
+
+func TestRadiantAltitude(t *testing.T) {
+	perseids := MeteorShower{Name: "Perseids", RadiantDeclination: 58}
+	if alt := perseids.RadiantAltitude(48); alt != 80 {
+		t.Errorf("from Paris: %v°, want 80°", alt)
+	}
+	// From Sydney the Perseids' radiant stays below the horizon
+	if alt := perseids.RadiantAltitude(-34); alt >= 0 {
+		t.Errorf("from Sydney: %v°, want below the horizon", alt)
+	}
+}
+
+func TestMeteorNightSummary(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.August, day, hour, min, 0, 0, time.UTC)
+	}
+	dark := TimeRange{Start: at(12, 22, 41), End: at(13, 3, 52)}
+	night := MeteorNight{
+		Shower:          MeteorShower{Name: "Perseids", ZHR: 100},
+		Dark:            dark,
+		Moonless:        dark,
+		RadiantAltitude: 80,
+	}
+	if !night.Good() {
+		t.Error("moonless night isn't good")
+	}
+	if got := night.Summary(true); got != "Perseids peak: moonless dark 22:41 - 03:52 (5h 11m), up to 100/h" {
+		t.Errorf("got %q", got)
+	}
+
+	night.Moonless = TimeRange{Start: at(12, 22, 41), End: at(12, 23, 51)}
+	night.MoonIllumination = 87
+	if night.Good() || night.Summary(true) != "Perseids peak: only 1h 10m moonless (moon 87% lit)" {
+		t.Errorf("short moonless night: %v, %q", night.Good(), night.Summary(true))
+	}
+
+	night.Moonless = TimeRange{}
+	if got := night.Summary(true); !strings.Contains(got, "moonlit all night") {
+		t.Errorf("moonlit night: %q", got)
+	}
+
+	night.RadiantAltitude = 5
+	if got := night.Summary(true); got != "Perseids peak: radiant too low here" {
+		t.Errorf("low radiant: %q", got)
+	}
+}
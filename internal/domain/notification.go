@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"slices"
+)
+
+// =============================================================================
+// Desktop Notifications
+// =============================================================================
+
+// Limits for desktop notifications.
+const (
+	// MaxNotificationLeads limits how many lead times can be set, so a
+	// single event can't flood the desktop with notifications.
+	MaxNotificationLeads = 4
+)
+
+// DesktopNotifications configures notifications shown on the desktop some
+// minutes before selected events, so a photographer working in another
+// program (or with the window minimized to the tray) doesn't miss the light.
+//
+// Each event can be announced more than once, e.g., 30 minutes ahead to
+// pack the bag and 10 minutes ahead to head out. Like the webhook, the
+// notifications can follow the current location or selected favorites.
+// They are shown through the system tray icon (see widgets.TrayIcon) and
+// queued by the automation scheduler while the app is running. No online
+// service is involved, so they keep working in privacy mode.
+type DesktopNotifications struct {
+	// Enabled turns the notifications on.
+	//
+	// Default: false
+	Enabled bool `json:"enabled"`
+
+	// Events lists the events notifications are shown for, in daily order.
+	//
+	// Default: morning blue hour start and evening golden hour start
+	Events []EventKind `json:"events,omitempty"`
+
+	// LeadMinutes lists how many minutes before each event a notification
+	// is shown, longest first (1 to MaxPushLeadMinutes each, at most
+	// MaxNotificationLeads of them).
+	//
+	// Default: 30 and 10
+	LeadMinutes []int `json:"lead_minutes,omitempty"`
+
+	// Favorites lists the IDs of the favorites notifications are shown for
+	// (see Favorite.ID). Empty means the current location.
+	Favorites []string `json:"favorites,omitempty"`
+}
+
+// DefaultDesktopNotifications returns the notification configuration for
+// new users (turned off).
+func DefaultDesktopNotifications() DesktopNotifications {
+	return DesktopNotifications{
+		Events:      []EventKind{EventBlueMorningStart, EventGoldenEveningStart},
+		LeadMinutes: []int{30, 10},
+	}
+}
+
+// validate repairs a loaded configuration: unknown events and favorite IDs
+// that no longer exist are dropped, lead times out of range or repeated are
+// dropped and the rest sorted longest first, and the notifications are
+// turned off if no lead time is left.
+func (n *DesktopNotifications) validate(favorites []Favorite) {
+	n.Events = slices.DeleteFunc(slices.Clone(n.Events), func(k EventKind) bool {
+		return !slices.Contains(AllEventKinds(), k)
+	})
+	n.LeadMinutes = slices.DeleteFunc(slices.Clone(n.LeadMinutes), func(m int) bool {
+		return m < 1 || m > MaxPushLeadMinutes
+	})
+	slices.Sort(n.LeadMinutes)
+	n.LeadMinutes = slices.Compact(n.LeadMinutes)
+	slices.Reverse(n.LeadMinutes)
+	if len(n.LeadMinutes) > MaxNotificationLeads {
+		n.LeadMinutes = n.LeadMinutes[:MaxNotificationLeads]
+	}
+	n.Favorites = slices.DeleteFunc(slices.Clone(n.Favorites), func(id string) bool {
+		return !slices.ContainsFunc(favorites, func(f Favorite) bool { return f.ID == id })
+	})
+
+	if len(n.LeadMinutes) == 0 {
+		n.Enabled = false
+	}
+}
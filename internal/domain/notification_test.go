@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidateDesktopNotifications(t *testing.T) {
+	s := DefaultSettings()
+	s.Favorites = []Favorite{{ID: "a", Location: Location{Latitude: 1, Longitude: 2, Name: "A"}}}
+	s.Notifications = DesktopNotifications{
+		Enabled:     true,
+		Events:      []EventKind{EventSunset, "moonrise"},
+		LeadMinutes: []int{10, 0, 60, 10, 500, 5, 30, 15},
+		Favorites:   []string{"gone", "a"},
+	}
+	s.Validate()
+
+	n := s.Notifications
+	if !n.Enabled {
+		t.Error("Validate() turned off notifications with valid lead times")
+	}
+	if want := []int{60, 30, 15, 10}; !slices.Equal(n.LeadMinutes, want) {
+		t.Errorf("LeadMinutes = %v, want %v", n.LeadMinutes, want)
+	}
+	if want := []EventKind{EventSunset}; !slices.Equal(n.Events, want) {
+		t.Errorf("Events = %v, want %v", n.Events, want)
+	}
+	if want := []string{"a"}; !slices.Equal(n.Favorites, want) {
+		t.Errorf("Favorites = %v, want %v", n.Favorites, want)
+	}
+
+	s.Notifications.LeadMinutes = []int{0, -10}
+	s.Validate()
+	if s.Notifications.Enabled {
+		t.Error("Validate() kept notifications without lead times enabled")
+	}
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// =============================================================================
+// Photo Light Analysis
+// =============================================================================
+
+// Light phases that aren't a golden or blue hour, for LightPhaseAt.
+const (
+	// PhaseDaylight is the sun above the horizon outside the golden hours.
+	PhaseDaylight = "Daylight"
+
+	// PhaseTwilight is the sun below the horizon outside the blue hours,
+	// but above the end of astronomical twilight.
+	PhaseTwilight = "Twilight"
+
+	// PhaseNight is the sun below AstronomicalTwilightElevation.
+	PhaseNight = "Night"
+)
+
+// PhotoLight is the light a photo was taken in, worked out from the
+// place and time in its EXIF data (Tools → Analyze Photo).
+type PhotoLight struct {
+	// File is the photo's file name, without its directory.
+	File string
+
+	// Camera is the camera's make and model, empty if the photo doesn't
+	// say.
+	Camera string
+
+	// Location is where the photo was taken, with its timezone.
+	Location Location
+
+	// Taken is when the photo was taken, in the location's timezone.
+	Taken time.Time
+
+	// Elevation and Azimuth are the sun's position at Taken, in degrees.
+	Elevation float64
+	Azimuth   float64
+
+	// Phase is the light phase at Taken (see LightPhaseAt), e.g.,
+	// "Evening golden hour" or PhaseDaylight.
+	Phase string
+
+	// Period is the golden or blue hour the photo was taken in; not valid
+	// for the other phases.
+	Period TimeRange
+}
+
+// LightPhaseAt names the light at a moment: the golden or blue hour in
+// progress, or else daylight, twilight or night by the sun's elevation.
+//
+//	in a golden or blue hour    →  its name, e.g., "Evening golden hour"
+//	else sun above the horizon  →  Daylight
+//	else sun above -18°         →  Twilight
+//	else                        →  Night
+//
+// Parameters:
+//   - days: Sun times of the day of t and the day before, in order (so a
+//     blue hour running past midnight is found too)
+//   - t: The moment
+//   - elevation: The sun's elevation at t, in degrees
+//
+// Returns the phase's name and the golden or blue hour in progress (not
+// valid if there is none).
+func LightPhaseAt(days []SunTimes, t time.Time, elevation float64) (string, TimeRange) {
+	for _, day := range days {
+		for _, p := range day.LightPeriods() {
+			if p.InProgress(t) {
+				return p.Name, p.Period
+			}
+		}
+	}
+	switch {
+	case elevation > 0:
+		return PhaseDaylight, TimeRange{}
+	case elevation > AstronomicalTwilightElevation:
+		return PhaseTwilight, TimeRange{}
+	default:
+		return PhaseNight, TimeRange{}
+	}
+}
+
+// Report describes the light of the photo in a few lines, for the
+// Analyze Photo dialog:
+//
+//	Taken Sat, Jun 21 2025 at 21:24 (CEST) with Canon EOS R6
+//	at 48.8584° N, 2.2945° E
+//
+//	Evening golden hour (21:00 - 21:58), 34 min before its end
+//	Sun at 3.2° elevation, azimuth 302° (WNW)
+//
+// Parameters:
+//   - use24Hour: Time format
+//   - coordinateFormat: How to show the coordinates
+//     (Settings.CoordinateFormat)
+func (p PhotoLight) Report(use24Hour bool, coordinateFormat string) string {
+	taken := fmt.Sprintf("Taken %s at %s (%s)", p.Taken.Format("Mon, Jan 2 2006"),
+		FormatTime(p.Taken, use24Hour), p.Taken.Format("MST"))
+	if p.Camera != "" {
+		taken += " with " + p.Camera
+	}
+	phase := p.Phase
+	if p.Period.IsValid() {
+		phase += fmt.Sprintf(" (%s - %s), %s before its end", FormatTime(p.Period.Start, use24Hour),
+			FormatTime(p.Period.End, use24Hour), FormatDuration(p.Period.End.Sub(p.Taken)))
+	}
+	return fmt.Sprintf("%s\nat %s\n\n%s\nSun at %.1f° elevation, azimuth %.0f° (%s)", taken,
+		FormatCoordinatesIn(p.Location.Latitude, p.Location.Longitude, coordinateFormat),
+		phase, p.Elevation, p.Azimuth, CompassPoint(p.Azimuth))
+}
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLightPhaseAt(t *testing.T) {
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 6, day, hour, minute, 0, 0, time.UTC)
+	}
+	days := []SunTimes{
+		{BlueEvening: TimeRange{Start: at(1, 23, 40), End: at(2, 0, 20)}},
+		{GoldenEvening: TimeRange{Start: at(2, 20, 15), End: at(2, 21, 0)}},
+	}
+
+	tests := []struct {
+		name       string
+		t          time.Time
+		elevation  float64
+		wantPhase  string
+		wantPeriod bool
+	}{
+		{name: "golden hour", t: at(2, 20, 30), elevation: 3, wantPhase: "Evening golden hour", wantPeriod: true},
+		{name: "blue hour past midnight", t: at(2, 0, 10), elevation: -5, wantPhase: "Evening blue hour", wantPeriod: true},
+		{name: "daylight", t: at(2, 14, 0), elevation: 55, wantPhase: PhaseDaylight},
+		{name: "twilight", t: at(2, 22, 0), elevation: -14, wantPhase: PhaseTwilight},
+		{name: "night", t: at(2, 2, 0), elevation: -25, wantPhase: PhaseNight},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			phase, period := LightPhaseAt(days, tt.t, tt.elevation)
+			if phase != tt.wantPhase || period.IsValid() != tt.wantPeriod {
+				t.Errorf("got %q (period %v), want %q (period %v)", phase, period.IsValid(), tt.wantPhase, tt.wantPeriod)
+			}
+		})
+	}
+}
+
+func TestPhotoLightReport(t *testing.T) {
+	paris := time.FixedZone("CEST", 2*3600)
+	taken := time.Date(2025, 6, 21, 21, 24, 0, 0, paris)
+	light := PhotoLight{
+		Camera:    "Canon EOS R6",
+		Location:  Location{Latitude: 48.8584, Longitude: 2.2945},
+		Taken:     taken,
+		Elevation: 3.24,
+		Azimuth:   302,
+		Phase:     "Evening golden hour",
+		Period: TimeRange{Start: time.Date(2025, 6, 21, 21, 0, 0, 0, paris),
+			End: time.Date(2025, 6, 21, 21, 58, 0, 0, paris)},
+	}
+	report := light.Report(true, "")
+	for _, want := range []string{
+		"Taken Sat, Jun 21 2025 at 21:24 (CEST) with Canon EOS R6",
+		"Evening golden hour (21:00 - 21:58), 34 min before its end",
+		"Sun at 3.2° elevation, azimuth 302° (WNW)",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("report lacks %q:\n%s", want, report)
+		}
+	}
+
+	light.Camera, light.Phase, light.Period = "", PhaseDaylight, TimeRange{}
+	if report := light.Report(true, ""); strings.Contains(report, " with ") || !strings.Contains(report, "\nDaylight\n") {
+		t.Errorf("report without camera or golden hour:\n%s", report)
+	}
+}
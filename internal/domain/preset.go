@@ -0,0 +1,197 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// Presets
+// =============================================================================
+
+// Limits for presets.
+const (
+	// MaxPresets limits how many presets can be saved, so the toolbar's
+	// list stays short.
+	MaxPresets = 12
+
+	// maxPresetName limits preset names to what fits in the toolbar.
+	maxPresetName = 30
+)
+
+// Preset is a named set of the calculation settings for one kind of
+// photography, e.g., "Landscape", "Portraits" or "Astro": the golden and
+// blue hour elevation angles and the custom events (see CustomEvent).
+//
+// Presets are switched from the toolbar. The active preset's values are
+// the ones in Settings (GoldenHourElevation, BlueHourStart, BlueHourEnd,
+// CustomEvents); changing them in the settings panel or the preferences
+// dialog changes the active preset too (see Settings.SyncActivePreset).
+//
+// Presets are not the same as profiles (started with --profile NAME),
+// which keep separate settings files with their own locations, favorites
+// and automation.
+type Preset struct {
+	// Name identifies the preset (see ValidatePresetName); names are
+	// unique, ignoring case.
+	Name string `json:"name"`
+
+	// GoldenHourElevation, BlueHourStart and BlueHourEnd are the elevation
+	// angles, with the same ranges as the Settings fields of the same names.
+	GoldenHourElevation float64 `json:"golden_hour_elevation"`
+	BlueHourStart       float64 `json:"blue_hour_start"`
+	BlueHourEnd         float64 `json:"blue_hour_end"`
+
+	// CustomEvents are the preset's custom events.
+	CustomEvents []CustomEvent `json:"custom_events,omitempty"`
+}
+
+// DefaultPresets returns the presets for new users:
+//
+//   - Landscape: the default angles (golden hour up to 6°, blue hour from
+//     -4° to -8°)
+//   - Portraits: golden hour up to 10°, when faces are still lit softly,
+//     and a shorter blue hour down to -6° (civil dusk), while there is
+//     light enough for a face; plus the sun at 15°, where hard light begins
+//   - Astro: the default angles, plus nautical (-12°) and astronomical
+//     (-18°) dusk and dawn, when the stars and then the Milky Way show
+func DefaultPresets() []Preset {
+	return []Preset{
+		{Name: "Landscape", GoldenHourElevation: 6, BlueHourStart: -4, BlueHourEnd: -8},
+		{Name: "Portraits", GoldenHourElevation: 10, BlueHourStart: -4, BlueHourEnd: -6,
+			CustomEvents: []CustomEvent{{Name: "Hard light", Elevation: 15}}},
+		{Name: "Astro", GoldenHourElevation: 6, BlueHourStart: -4, BlueHourEnd: -8,
+			CustomEvents: []CustomEvent{
+				{Name: "Nautical twilight", Elevation: -12},
+				{Name: "Astronomical dark", Elevation: -18},
+			}},
+	}
+}
+
+// ValidatePresetName checks a name for a new preset.
+//
+// Names must not be empty and are at most 30 characters long; surrounding
+// spaces are ignored. Returns a descriptive error, suitable for display.
+func ValidatePresetName(name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return errors.New("the preset needs a name")
+	}
+	if len([]rune(name)) > maxPresetName {
+		return fmt.Errorf("preset name %q is longer than %d characters", name, maxPresetName)
+	}
+	return nil
+}
+
+// FindPreset returns the index of the preset with the given name, ignoring
+// case, or -1 if there is none.
+func FindPreset(presets []Preset, name string) int {
+	for i, p := range presets {
+		if strings.EqualFold(p.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// ApplyPreset makes the named preset the active one, copying its angles and
+// custom events into the settings.
+//
+// Returns false (and changes nothing) if there is no such preset.
+func (s *Settings) ApplyPreset(name string) bool {
+	i := FindPreset(s.Presets, name)
+	if i < 0 {
+		return false
+	}
+	p := s.Presets[i]
+	s.ActivePreset = p.Name
+	s.GoldenHourElevation = p.GoldenHourElevation
+	s.BlueHourStart = p.BlueHourStart
+	s.BlueHourEnd = p.BlueHourEnd
+	s.CustomEvents = append([]CustomEvent(nil), p.CustomEvents...)
+	return true
+}
+
+// SyncActivePreset stores the current angles and custom events in the
+// active preset, so changes made while it is active are kept with it.
+// Does nothing without an active preset.
+func (s *Settings) SyncActivePreset() {
+	i := FindPreset(s.Presets, s.ActivePreset)
+	if i < 0 {
+		return
+	}
+	// Copies of the settings may share the list, so it is replaced
+	s.Presets = slices.Clone(s.Presets)
+	s.Presets[i] = s.currentPreset(s.Presets[i].Name)
+}
+
+// SavePreset saves the current angles and custom events as a preset and
+// makes it the active one. A preset with the same name (ignoring case) is
+// replaced.
+//
+// Returns an error if the name is invalid (see ValidatePresetName) or
+// MaxPresets are saved already.
+func (s *Settings) SavePreset(name string) error {
+	if err := ValidatePresetName(name); err != nil {
+		return err
+	}
+	name = strings.TrimSpace(name)
+	preset := s.currentPreset(name)
+	if i := FindPreset(s.Presets, name); i >= 0 {
+		s.Presets = slices.Clone(s.Presets)
+		s.Presets[i] = preset
+	} else if len(s.Presets) >= MaxPresets {
+		return fmt.Errorf("at most %d presets can be saved; delete one first", MaxPresets)
+	} else {
+		s.Presets = append(slices.Clip(s.Presets), preset)
+	}
+	s.ActivePreset = name
+	return nil
+}
+
+// DeletePreset removes the named preset. If it was the active one, the
+// current values stay but no preset is active any more.
+func (s *Settings) DeletePreset(name string) {
+	i := FindPreset(s.Presets, name)
+	if i < 0 {
+		return
+	}
+	if strings.EqualFold(s.ActivePreset, name) {
+		s.ActivePreset = ""
+	}
+	s.Presets = append(s.Presets[:i:i], s.Presets[i+1:]...)
+}
+
+// currentPreset returns the current angles and custom events as a preset.
+func (s *Settings) currentPreset(name string) Preset {
+	return Preset{
+		Name:                name,
+		GoldenHourElevation: s.GoldenHourElevation,
+		BlueHourStart:       s.BlueHourStart,
+		BlueHourEnd:         s.BlueHourEnd,
+		CustomEvents:        append([]CustomEvent(nil), s.CustomEvents...),
+	}
+}
+
+// validatePresets repairs loaded presets: names are trimmed, unnamed,
+// overlong and duplicate ones dropped, angles clamped like the settings'
+// own, custom events repaired, and the list cut to MaxPresets.
+func validatePresets(presets []Preset) []Preset {
+	var valid []Preset
+	for _, p := range presets {
+		p.Name = strings.TrimSpace(p.Name)
+		if ValidatePresetName(p.Name) != nil || FindPreset(valid, p.Name) >= 0 {
+			continue
+		}
+		p.GoldenHourElevation, p.BlueHourStart, p.BlueHourEnd =
+			clampElevations(p.GoldenHourElevation, p.BlueHourStart, p.BlueHourEnd)
+		p.CustomEvents = validateCustomEvents(p.CustomEvents)
+		valid = append(valid, p)
+		if len(valid) == MaxPresets {
+			break
+		}
+	}
+	return valid
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	s := DefaultSettings()
+	if !s.ApplyPreset("astro") {
+		t.Fatal("ApplyPreset(astro) = false, want true")
+	}
+	if s.ActivePreset != "Astro" || len(s.CustomEvents) != 2 {
+		t.Fatalf("after ApplyPreset: active %q, %d custom events; want Astro, 2", s.ActivePreset, len(s.CustomEvents))
+	}
+
+	// Syncing must not change copies that share the list
+	before := s
+	s.GoldenHourElevation = 3
+	s.SyncActivePreset()
+	if got := s.Presets[FindPreset(s.Presets, "Astro")].GoldenHourElevation; got != 3 {
+		t.Errorf("synced golden hour elevation = %v, want 3", got)
+	}
+	if got := before.Presets[FindPreset(before.Presets, "Astro")].GoldenHourElevation; got != 6 {
+		t.Errorf("copy's golden hour elevation = %v, want 6", got)
+	}
+
+	if err := s.SavePreset("  Night  "); err != nil {
+		t.Fatalf("SavePreset(Night) = %v", err)
+	}
+	if s.ActivePreset != "Night" || len(s.Presets) != 4 || len(before.Presets) != 3 {
+		t.Errorf("after SavePreset: active %q, %d presets (copy %d); want Night, 4 (3)",
+			s.ActivePreset, len(s.Presets), len(before.Presets))
+	}
+	if err := s.SavePreset(" "); err == nil {
+		t.Error("SavePreset with an empty name succeeded")
+	}
+
+	s.DeletePreset("night")
+	if s.ActivePreset != "" || FindPreset(s.Presets, "Night") >= 0 {
+		t.Errorf("after DeletePreset: active %q, presets %v", s.ActivePreset, s.Presets)
+	}
+	if s.ApplyPreset("Night") {
+		t.Error("ApplyPreset of a deleted preset = true, want false")
+	}
+}
+
+func TestValidateCustomEvents(t *testing.T) {
+	s := DefaultSettings()
+	s.CustomEvents = []CustomEvent{
+		{Name: "  Dark  ", Elevation: -30},
+		{Name: " ", Elevation: 1},
+		{Name: "High", Elevation: 90},
+	}
+	s.ActivePreset = "LANDSCAPE"
+	s.Validate()
+
+	want := []CustomEvent{{Name: "Dark", Elevation: MinCustomElevation}, {Name: "High", Elevation: MaxCustomElevation}}
+	if len(s.CustomEvents) != len(want) || s.CustomEvents[0] != want[0] || s.CustomEvents[1] != want[1] {
+		t.Errorf("CustomEvents = %v, want %v", s.CustomEvents, want)
+	}
+	if s.ActivePreset != "Landscape" {
+		t.Errorf("ActivePreset = %q, want Landscape", s.ActivePreset)
+	}
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+// =============================================================================
+// Privacy Mode
+// =============================================================================
+
+// ErrPrivacyMode is returned by features that need an online service while
+// Settings.PrivacyMode is on, such as elevation profiles or the cloud layer.
+var ErrPrivacyMode = errors.New("not available in privacy mode")
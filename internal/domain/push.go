@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// Push Notifications
+// =============================================================================
+
+// Push services, stored in PushNotifications.Service.
+const (
+	// PushServiceNtfy publishes to a topic on an ntfy server (ntfy.sh or a
+	// self-hosted one); the ntfy app on the phone subscribes to the topic.
+	PushServiceNtfy = "ntfy"
+
+	// PushServicePushover sends through the Pushover API with an
+	// application token and the user's key.
+	PushServicePushover = "pushover"
+)
+
+// Defaults and limits for push notifications.
+const (
+	// DefaultNtfyServer is the public ntfy server.
+	DefaultNtfyServer = "https://ntfy.sh"
+
+	// DefaultPushLeadMinutes is how long before an event the reminder is
+	// pushed: enough to get to a nearby spot.
+	DefaultPushLeadMinutes = 30
+
+	// MaxPushLeadMinutes limits the lead time. Reminders are queued with the
+	// automation hooks, which look at most a day ahead.
+	MaxPushLeadMinutes = 180
+
+	// PushScheduleDays is how many days the schedule sent to the phone
+	// covers.
+	PushScheduleDays = 14
+)
+
+// PushNotifications configures reminders pushed to the user's phone.
+//
+// The desktop notification of an event doesn't help once the photographer
+// has left the house, so reminders can be pushed through ntfy or Pushover
+// some minutes before selected events. A schedule of the next
+// PushScheduleDays days can be sent on demand as well, so the times are at
+// hand on the phone even when the computer is off. Reminders are queued by
+// the automation scheduler at the current location while the app is
+// running.
+type PushNotifications struct {
+	// Enabled turns the event reminders on.
+	//
+	// Default: false
+	Enabled bool `json:"enabled"`
+
+	// Service is PushServiceNtfy or PushServicePushover.
+	//
+	// Default: PushServiceNtfy
+	Service string `json:"service"`
+
+	// NtfyServer is the base URL of the ntfy server (ntfy).
+	//
+	// Default: DefaultNtfyServer
+	NtfyServer string `json:"ntfy_server,omitempty"`
+
+	// NtfyTopic is the topic the phone subscribes to (ntfy). Anyone who
+	// knows the topic can read it on a public server, so it should be hard
+	// to guess.
+	NtfyTopic string `json:"ntfy_topic,omitempty"`
+
+	// PushoverToken is the API token of the user's Pushover application
+	// (Pushover).
+	PushoverToken string `json:"pushover_token,omitempty"`
+
+	// PushoverUser is the user (or group) key to notify (Pushover).
+	PushoverUser string `json:"pushover_user,omitempty"`
+
+	// Events lists the events a reminder is pushed for, in daily order.
+	//
+	// Default: morning blue hour start and evening golden hour start
+	Events []EventKind `json:"events,omitempty"`
+
+	// LeadMinutes is how many minutes before each event the reminder is
+	// pushed (0 to MaxPushLeadMinutes).
+	//
+	// Default: DefaultPushLeadMinutes
+	LeadMinutes int `json:"lead_minutes"`
+}
+
+// DefaultPushNotifications returns the push configuration for new users
+// (turned off, ntfy.sh).
+func DefaultPushNotifications() PushNotifications {
+	return PushNotifications{
+		Service:     PushServiceNtfy,
+		NtfyServer:  DefaultNtfyServer,
+		Events:      []EventKind{EventBlueMorningStart, EventGoldenEveningStart},
+		LeadMinutes: DefaultPushLeadMinutes,
+	}
+}
+
+// Check reports the first problem that would keep notifications from being
+// pushed, suitable for showing to the user.
+//
+// Only the fields used by the selected service are checked. The event list
+// may be empty, e.g. for a user who only sends the schedule.
+func (p PushNotifications) Check() error {
+	switch p.Service {
+	case PushServiceNtfy:
+		u, err := url.Parse(p.NtfyServer)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("ntfy server %q must be an http:// or https:// URL", p.NtfyServer)
+		}
+		if p.NtfyTopic == "" {
+			return errors.New("no ntfy topic set")
+		}
+		if !isPushKey(p.NtfyTopic, 64, "-_") {
+			return fmt.Errorf("ntfy topic %q may only contain letters, digits, '-' and '_' (at most 64)", p.NtfyTopic)
+		}
+	case PushServicePushover:
+		if p.PushoverToken == "" || p.PushoverUser == "" {
+			return errors.New("Pushover needs an API token and a user key")
+		}
+		if !isPushKey(p.PushoverToken, 64, "") || !isPushKey(p.PushoverUser, 64, "") {
+			return errors.New("Pushover token and user key may only contain letters and digits")
+		}
+	default:
+		return fmt.Errorf("unknown push service %q", p.Service)
+	}
+	return nil
+}
+
+// isPushKey reports whether s is 1 to maxLen ASCII letters, digits or
+// characters from extra.
+func isPushKey(s string, maxLen int, extra string) bool {
+	if s == "" || len(s) > maxLen {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune(extra, r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// validate repairs a loaded configuration: missing values get their
+// defaults, unknown events are dropped, the lead time is clamped, and the
+// reminders are turned off if they still couldn't be pushed (see Check).
+func (p *PushNotifications) validate() {
+	if p.Service != PushServicePushover {
+		p.Service = PushServiceNtfy
+	}
+	if p.NtfyServer = strings.TrimRight(strings.TrimSpace(p.NtfyServer), "/"); p.NtfyServer == "" {
+		p.NtfyServer = DefaultNtfyServer
+	}
+	p.NtfyTopic = strings.TrimSpace(p.NtfyTopic)
+	p.PushoverToken = strings.TrimSpace(p.PushoverToken)
+	p.PushoverUser = strings.TrimSpace(p.PushoverUser)
+
+	p.Events = slices.DeleteFunc(slices.Clone(p.Events), func(k EventKind) bool {
+		return !slices.Contains(AllEventKinds(), k)
+	})
+	p.LeadMinutes = min(max(p.LeadMinutes, 0), MaxPushLeadMinutes)
+
+	if p.Enabled && p.Check() != nil {
+		p.Enabled = false
+	}
+}
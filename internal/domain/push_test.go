@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPushNotificationsCheck(t *testing.T) {
+	ntfy := DefaultPushNotifications()
+	ntfy.NtfyTopic = "golden-hour_x7Qp"
+	pushover := DefaultPushNotifications()
+	pushover.Service = PushServicePushover
+	pushover.PushoverToken = "azGDORePK8gMaC0QOYAMyEEuzJnyUi"
+	pushover.PushoverUser = "uQiRzpo4DXghDmr9QzzfQu27cmVRsG"
+
+	tests := []struct {
+		name   string
+		change func(p *PushNotifications)
+		base   PushNotifications
+		ok     bool
+	}{
+		{"ntfy", func(p *PushNotifications) {}, ntfy, true},
+		{"self-hosted ntfy", func(p *PushNotifications) { p.NtfyServer = "http://ntfy.lan:8080" }, ntfy, true},
+		{"no topic", func(p *PushNotifications) { p.NtfyTopic = "" }, ntfy, false},
+		{"topic with slash", func(p *PushNotifications) { p.NtfyTopic = "a/b" }, ntfy, false},
+		{"server without scheme", func(p *PushNotifications) { p.NtfyServer = "ntfy.sh" }, ntfy, false},
+		{"pushover", func(p *PushNotifications) {}, pushover, true},
+		{"pushover without user", func(p *PushNotifications) { p.PushoverUser = "" }, pushover, false},
+		{"pushover token with spaces", func(p *PushNotifications) { p.PushoverToken = "a b" }, pushover, false},
+		{"unknown service", func(p *PushNotifications) { p.Service = "sms" }, ntfy, false},
+	}
+	for _, tt := range tests {
+		p := tt.base
+		tt.change(&p)
+		if err := p.Check(); (err == nil) != tt.ok {
+			t.Errorf("%s: Check() = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+	}
+}
+
+func TestValidatePushNotifications(t *testing.T) {
+	s := DefaultSettings()
+	s.Push = PushNotifications{
+		Enabled:     true,
+		Service:     "",
+		NtfyServer:  " https://ntfy.example.com/ ",
+		NtfyTopic:   " shoots ",
+		Events:      []EventKind{EventSunset, "moonrise", EventSunrise},
+		LeadMinutes: 1000,
+	}
+	s.Validate()
+
+	p := s.Push
+	if p.Service != PushServiceNtfy || p.NtfyServer != "https://ntfy.example.com" || p.NtfyTopic != "shoots" {
+		t.Errorf("Validate() = %+v, want ntfy at https://ntfy.example.com, topic shoots", p)
+	}
+	if want := []EventKind{EventSunset, EventSunrise}; !slices.Equal(p.Events, want) {
+		t.Errorf("Events = %v, want %v", p.Events, want)
+	}
+	if p.LeadMinutes != MaxPushLeadMinutes {
+		t.Errorf("LeadMinutes = %d, want %d", p.LeadMinutes, MaxPushLeadMinutes)
+	}
+	if !p.Enabled {
+		t.Error("a complete configuration was turned off")
+	}
+
+	// Reminders that can't be pushed are turned off
+	s.Push.NtfyTopic = ""
+	s.Validate()
+	if s.Push.Enabled {
+		t.Error("Enabled kept without a topic")
+	}
+}
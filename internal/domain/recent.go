@@ -0,0 +1,148 @@
+package domain
+
+import "time"
+
+// =============================================================================
+// Recent (Scratch) Locations
+// =============================================================================
+
+// MaxRecentLocations is how many recently selected locations are kept in
+// Settings.RecentLocations.
+//
+// Ten fits in the location panel's "Recent" menu without scrolling, and is
+// enough to find the way back after exploring the map for a while.
+const MaxRecentLocations = 10
+
+// Scratch retention values of Settings.ScratchRetentionDays besides a
+// number of days.
+const (
+	// ScratchKeepSession keeps scratch locations until the app closes.
+	ScratchKeepSession = 0
+
+	// ScratchKeepForever never expires scratch locations (the list is
+	// still limited to MaxRecentLocations).
+	ScratchKeepForever = -1
+
+	// DefaultScratchRetentionDays is how long scratch locations are kept
+	// by default.
+	DefaultScratchRetentionDays = 30
+
+	// maxScratchRetentionDays is the longest retention accepted.
+	maxScratchRetentionDays = 3650
+)
+
+// RecentLocation is a "scratch" location: a place selected by a map click,
+// a one-off search or detection, as opposed to a saved Favorite.
+//
+// Scratch locations are kept for the "Recent" menu only, and expire after
+// Settings.ScratchRetentionDays. Favorites are never listed among them.
+type RecentLocation struct {
+	// Location is the selected place. It is embedded, so settings files
+	// from before scratch locations had a time still load.
+	Location
+
+	// Used is when the location was last selected; zero in settings files
+	// from before it was recorded.
+	Used time.Time `json:"used,omitempty"`
+}
+
+// AddRecentLocation records loc as the most recently selected location.
+//
+// The location is moved to the front of the list; an entry at the same
+// coordinates (see FavoriteAt) is replaced, so selecting a place again
+// doesn't list it twice. The oldest entries beyond MaxRecentLocations are
+// dropped.
+//
+// Parameters:
+//   - recent: The current list, most recent first (not modified)
+//   - loc: The location just selected (not a favorite; see
+//     RemoveRecentLocation)
+//   - now: The time of the selection
+//
+// Returns the new list, most recent first.
+func AddRecentLocation(recent []RecentLocation, loc Location, now time.Time) []RecentLocation {
+	updated := make([]RecentLocation, 0, min(len(recent)+1, MaxRecentLocations))
+	updated = append(updated, RecentLocation{Location: loc, Used: now})
+	for _, r := range recent {
+		if len(updated) == MaxRecentLocations {
+			break
+		}
+		if sameCoordinate(r.Latitude, loc.Latitude) && sameCoordinate(r.Longitude, loc.Longitude) {
+			continue
+		}
+		updated = append(updated, r)
+	}
+	return updated
+}
+
+// RemoveRecentLocation drops the entry at loc's coordinates, e.g., when
+// the place is saved as a favorite and stops being a scratch location.
+//
+// Returns a new list (recent is not modified).
+func RemoveRecentLocation(recent []RecentLocation, loc Location) []RecentLocation {
+	var kept []RecentLocation
+	for _, r := range recent {
+		if !sameCoordinate(r.Latitude, loc.Latitude) || !sameCoordinate(r.Longitude, loc.Longitude) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// ExpireRecentLocations drops the scratch locations last used before
+// cutoff (see ScratchCutoff).
+//
+// Returns a new list (recent is not modified).
+func ExpireRecentLocations(recent []RecentLocation, cutoff time.Time) []RecentLocation {
+	var kept []RecentLocation
+	for _, r := range recent {
+		if !r.Used.Before(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
+
+// ScratchCutoff returns the time before which scratch locations expire.
+//
+// Parameters:
+//   - days: Settings.ScratchRetentionDays (ScratchKeepSession,
+//     ScratchKeepForever or a number of days)
+//   - now: The current time
+//   - sessionStart: When the app started; locations of earlier sessions
+//     expire with ScratchKeepSession
+//
+// Returns the zero time (nothing expires) for ScratchKeepForever.
+func ScratchCutoff(days int, now, sessionStart time.Time) time.Time {
+	switch {
+	case days == ScratchKeepForever:
+		return time.Time{}
+	case days == ScratchKeepSession:
+		return sessionStart
+	default:
+		return now.AddDate(0, 0, -days)
+	}
+}
+
+// validScratchRetention reports whether days is an accepted value of
+// Settings.ScratchRetentionDays.
+func validScratchRetention(days int) bool {
+	return days == ScratchKeepForever || (days >= ScratchKeepSession && days <= maxScratchRetentionDays)
+}
+
+// validateRecentLocations repairs loaded recent locations: entries with
+// invalid coordinates are dropped, the rest are cleaned up with
+// Location.Sanitize, and the list is cut to MaxRecentLocations. Returns a
+// new slice.
+func validateRecentLocations(recent []RecentLocation) []RecentLocation {
+	var valid []RecentLocation
+	for _, r := range recent {
+		if len(valid) == MaxRecentLocations {
+			break
+		}
+		if r.Sanitize() {
+			valid = append(valid, r)
+		}
+	}
+	return valid
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestAddRecentLocation(t *testing.T) {
+	at := func(lat float64) Location {
+		return Location{Latitude: lat, Longitude: 2.35, Name: fmt.Sprintf("%.0f", lat)}
+	}
+	names := func(recent []RecentLocation) string {
+		s := ""
+		for _, loc := range recent {
+			s += loc.Name + " "
+		}
+		return s
+	}
+
+	now := time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC)
+	var recent []RecentLocation
+	for lat := 1.0; lat <= 3; lat++ {
+		recent = AddRecentLocation(recent, at(lat), now)
+	}
+	if got := names(recent); got != "3 2 1 " {
+		t.Fatalf("recent = %s, want 3 2 1", got)
+	}
+
+	// Selecting a place again moves it to the front instead of adding it
+	again := at(1)
+	again.Latitude += 1e-6
+	again.Name = "1 again"
+	updated := AddRecentLocation(recent, again, now.Add(time.Hour))
+	if got := names(updated); got != "1 again 3 2 " {
+		t.Errorf("after reselecting = %s, want 1 again 3 2", got)
+	}
+	if !updated[0].Used.Equal(now.Add(time.Hour)) {
+		t.Errorf("reselected entry used at %v, want the new time", updated[0].Used)
+	}
+
+	if got := names(RemoveRecentLocation(updated, at(3))); got != "1 again 2 " {
+		t.Errorf("after removing 3 = %s, want 1 again 2", got)
+	}
+	if got := names(recent); got != "3 2 1 " {
+		t.Errorf("input list modified: %s", got)
+	}
+
+	// The oldest entries are dropped
+	for lat := 10.0; lat < 10+MaxRecentLocations; lat++ {
+		recent = AddRecentLocation(recent, at(lat), now)
+	}
+	if len(recent) != MaxRecentLocations || recent[len(recent)-1].Name != "10" {
+		t.Errorf("full list = %s, want %d entries ending with 10", names(recent), MaxRecentLocations)
+	}
+}
+
+func TestValidateRecentLocations(t *testing.T) {
+	s := DefaultSettings()
+	for i := range MaxRecentLocations + 2 {
+		s.RecentLocations = append(s.RecentLocations,
+			RecentLocation{Location: Location{Latitude: float64(i), Longitude: 1, Name: " spot\n"}})
+	}
+	s.RecentLocations[0].Latitude = 100
+	s.Validate()
+
+	if len(s.RecentLocations) != MaxRecentLocations {
+		t.Fatalf("kept %d recent locations, want %d", len(s.RecentLocations), MaxRecentLocations)
+	}
+	if first := s.RecentLocations[0]; first.Latitude != 1 || first.Name != "spot" {
+		t.Errorf("first recent location = %+v, want the sanitized second entry", first)
+	}
+}
+
+func TestExpireRecentLocations(t *testing.T) {
+	now := time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC)
+	sessionStart := now.Add(-time.Hour)
+	recent := []RecentLocation{
+		{Location: Location{Name: "this session"}, Used: now.Add(-time.Minute)},
+		{Location: Location{Name: "last week"}, Used: now.AddDate(0, 0, -6)},
+		{Location: Location{Name: "last year"}, Used: now.AddDate(-1, 0, 0)},
+	}
+
+	tests := []struct {
+		days int
+		want int
+	}{
+		{ScratchKeepForever, 3},
+		{ScratchKeepSession, 1},
+		{7, 2},
+		{1, 1},
+	}
+	for _, tt := range tests {
+		kept := ExpireRecentLocations(recent, ScratchCutoff(tt.days, now, sessionStart))
+		if len(kept) != tt.want {
+			t.Errorf("retention %d days kept %d locations, want %d", tt.days, len(kept), tt.want)
+		}
+	}
+}
+
+func TestValidateScratchRetention(t *testing.T) {
+	for days, want := range map[int]int{
+		ScratchKeepForever: ScratchKeepForever,
+		ScratchKeepSession: ScratchKeepSession,
+		7:                  7,
+		-5:                 DefaultScratchRetentionDays,
+		100000:             DefaultScratchRetentionDays,
+	} {
+		s := DefaultSettings()
+		s.ScratchRetentionDays = days
+		s.Validate()
+		if s.ScratchRetentionDays != want {
+			t.Errorf("Validate(%d) kept %d, want %d", days, s.ScratchRetentionDays, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// Search Bias
+// =============================================================================
+
+// SearchBias steers location searches toward locally relevant results.
+//
+// The zero value searches worldwide in the system language, as before
+// these options existed. For example, a photographer in Austria who types
+// "St. Wolfgang" and wants the place by the Wolfgangsee rather than one in
+// Switzerland, named in German:
+//
+//	Language:    de
+//	Countries:   [at de]
+//	NearMapView: true
+type SearchBias struct {
+	// Language lists the preferred languages of place names as
+	// comma-separated language tags (e.g., "de" or "fr-CA,en"); empty for
+	// the system language.
+	Language string `json:"language,omitempty"`
+
+	// Countries restricts results to these countries, as lower-case ISO
+	// 3166-1 alpha-2 codes (e.g., "at"); empty for worldwide.
+	Countries []string `json:"countries,omitempty"`
+
+	// NearMapView prefers (but doesn't require) results in the area the
+	// map shows.
+	NearMapView bool `json:"near_map_view,omitempty"`
+}
+
+// languageTag matches a simple BCP 47 language tag such as "de", "pt-BR"
+// or "zh-Hant".
+var languageTag = regexp.MustCompile(`^[a-zA-Z]{2,3}(-[a-zA-Z0-9]{2,8})*$`)
+
+// countryCode matches an ISO 3166-1 alpha-2 country code.
+var countryCode = regexp.MustCompile(`^[a-z]{2}$`)
+
+// Validate checks that the languages and countries are well-formed.
+//
+// Returns an error naming the first malformed entry; nil if the bias can be
+// sent to the geocoding service as is.
+func (b SearchBias) Validate() error {
+	if b.Language != "" {
+		for _, tag := range strings.Split(b.Language, ",") {
+			if !languageTag.MatchString(tag) {
+				return fmt.Errorf("%q is not a language code (e.g., de or pt-BR)", tag)
+			}
+		}
+	}
+	for _, code := range b.Countries {
+		if !countryCode.MatchString(code) {
+			return fmt.Errorf("%q is not a two-letter country code (e.g., at)", code)
+		}
+	}
+	return nil
+}
+
+// ParseLanguages normalizes a list of language tags typed by the user:
+// separated by commas or spaces, trimmed, without duplicates.
+func ParseLanguages(text string) string {
+	return strings.Join(splitList(text, false), ",")
+}
+
+// ParseCountryCodes normalizes a list of country codes typed by the user:
+// separated by commas or spaces, lower case, without duplicates.
+func ParseCountryCodes(text string) []string {
+	return splitList(text, true)
+}
+
+// splitList splits text at commas and spaces, dropping empty and repeated
+// entries.
+func splitList(text string, lower bool) []string {
+	if lower {
+		text = strings.ToLower(text)
+	}
+	var list []string
+	for _, entry := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' }) {
+		if !slices.Contains(list, entry) {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+// Bounds is a latitude/longitude box, such as the area shown by the map.
+type Bounds struct {
+	// South and North are the latitudes of the box's edges, in degrees.
+	South, North float64
+
+	// West and East are the longitudes of the box's edges, in degrees.
+	West, East float64
+}
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseSearchBias(t *testing.T) {
+	if got := ParseLanguages(" de, en  en,fr-CA "); got != "de,en,fr-CA" {
+		t.Errorf("ParseLanguages = %q, want de,en,fr-CA", got)
+	}
+	if got := ParseCountryCodes("AT, de,,at ch"); !slices.Equal(got, []string{"at", "de", "ch"}) {
+		t.Errorf("ParseCountryCodes = %v, want [at de ch]", got)
+	}
+	if got := ParseCountryCodes("  "); got != nil {
+		t.Errorf("ParseCountryCodes(blank) = %v, want none", got)
+	}
+
+	for _, bias := range []SearchBias{
+		{Language: "de_DE"},
+		{Language: "de,"},
+		{Countries: []string{"AT"}},
+		{Countries: []string{"aut"}},
+	} {
+		if bias.Validate() == nil {
+			t.Errorf("Validate(%+v) accepted a malformed bias", bias)
+		}
+	}
+}
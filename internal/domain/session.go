@@ -0,0 +1,93 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// =============================================================================
+// Session
+// =============================================================================
+
+// Planning windows a session can reopen (Session.Panels). The docks are
+// not among them: their arrangement and visibility are part of
+// Settings.PanelLayout.
+const (
+	// SessionPanelMonthCalendar is the month calendar.
+	SessionPanelMonthCalendar = "month_calendar"
+
+	// SessionPanelWeekOutlook is the week outlook.
+	SessionPanelWeekOutlook = "week_outlook"
+)
+
+// Session is what the user was looking at when the app last quit: saved
+// on exit to session.json in the config folder and restored on the next
+// launch (see storage.SessionStore).
+//
+// Unlike Settings.LastLocation, which follows every change of the location
+// and is kept per profile, a session belongs to one quit and holds the
+// whole view: the profile, place, date, map zoom and the planning windows
+// left open. There is one session for all profiles, the last one to quit.
+type Session struct {
+	// Profile is the settings profile that ran (empty for the default).
+	// A launch without --profile runs it again.
+	Profile string `json:"profile,omitempty"`
+
+	// Location is the selected place, nil if unknown.
+	Location *Location `json:"location,omitempty"`
+
+	// Date is the selected day, "2006-01-02" (see SelectedDate).
+	Date string `json:"date,omitempty"`
+
+	// MapZoom is the map's zoom level, 0 if unknown.
+	MapZoom int `json:"map_zoom,omitempty"`
+
+	// Panels are the planning windows that were open (SessionPanel*).
+	Panels []string `json:"panels,omitempty"`
+
+	// SavedAt is when the app quit.
+	SavedAt time.Time `json:"saved_at"`
+}
+
+// SelectedDate returns the session's date as a local day, unless it has
+// passed: a day before today is rarely still the one being planned, so the
+// app starts on today then.
+//
+// Parameters:
+//   - now: The current time
+//
+// Returns false if the session has no date, an invalid one or one before
+// now's day.
+func (s Session) SelectedDate(now time.Time) (time.Time, bool) {
+	date, err := time.ParseInLocation(time.DateOnly, s.Date, time.Local)
+	if err != nil {
+		return time.Time{}, false
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	if date.Before(today) {
+		return time.Time{}, false
+	}
+	return date, true
+}
+
+// Validate repairs a session read from disk, like Settings.Validate: a
+// location with invalid coordinates is dropped (the rest of it repaired,
+// see Location.Sanitize), and a map zoom outside [1, 19] is forgotten.
+func (s *Session) Validate() {
+	if s.Location != nil {
+		loc := *s.Location
+		if loc.Sanitize() {
+			s.Location = &loc
+		} else {
+			s.Location = nil
+		}
+	}
+	if s.MapZoom < 1 || s.MapZoom > 19 {
+		s.MapZoom = 0
+	}
+}
+
+// HasPanel reports whether a planning window was open.
+func (s Session) HasPanel(panel string) bool {
+	return slices.Contains(s.Panels, panel)
+}
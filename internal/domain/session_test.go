@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSessionSelectedDate(t *testing.T) {
+	now := time.Date(2026, 6, 10, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		date   string
+		want   time.Time
+		wantOK bool
+	}{
+		{"2026-06-21", time.Date(2026, 6, 21, 0, 0, 0, 0, time.Local), true},
+		{"2026-06-10", time.Date(2026, 6, 10, 0, 0, 0, 0, time.Local), true}, // today
+		{"2026-06-09", time.Time{}, false},                                   // passed
+		{"", time.Time{}, false},
+		{"21/06/2026", time.Time{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := Session{Date: tt.date}.SelectedDate(now)
+		if ok != tt.wantOK || !got.Equal(tt.want) {
+			t.Errorf("SelectedDate(%q) = %v, %v; want %v, %v", tt.date, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSessionHasPanel(t *testing.T) {
+	s := Session{Panels: []string{SessionPanelWeekOutlook}}
+	if !s.HasPanel(SessionPanelWeekOutlook) {
+		t.Error("HasPanel(week outlook) = false, want true")
+	}
+	if s.HasPanel(SessionPanelMonthCalendar) {
+		t.Error("HasPanel(month calendar) = true, want false")
+	}
+}
+
+func TestSessionValidate(t *testing.T) {
+	s := Session{
+		Location: &Location{Latitude: 120, Longitude: 2.35},
+		MapZoom:  42,
+	}
+	s.Validate()
+	if s.Location != nil || s.MapZoom != 0 {
+		t.Errorf("Validate() kept location %v and zoom %d, want nil and 0", s.Location, s.MapZoom)
+	}
+
+	s = Session{
+		Location: &Location{Latitude: 48.85, Longitude: 2.35, Name: "Paris\n", Timezone: "Nowhere/City"},
+		MapZoom:  13,
+	}
+	s.Validate()
+	if s.Location == nil || s.Location.Name != "Paris" || s.Location.Timezone != "" || s.MapZoom != 13 {
+		t.Errorf("Validate() = %+v, want Paris repaired and zoom 13", s)
+	}
+}
@@ -1,5 +1,85 @@
 package domain
 
+import (
+	"net/mail"
+	"strings"
+)
+
+// =============================================================================
+// Location Sources
+// =============================================================================
+
+// Location detection backends, stored in Settings.LocationSource.
+const (
+	// LocationSourceIP detects the location from the public IP address
+	// (ip-api.com or ipinfo.io, see Settings.LocationProviders). Works
+	// everywhere but is only accurate to the city level.
+	LocationSourceIP = "ip"
+
+	// LocationSourceSystem uses the operating system's location services
+	// (GeoClue2 on Linux, Windows Location API on Windows). Much more
+	// accurate on laptops with Wi-Fi positioning; falls back to the other
+	// providers where unavailable.
+	LocationSourceSystem = "system"
+
+	// LocationSourceBrowser asks the map's web engine (the browser
+	// Geolocation API). Works where Qt WebEngine has a position provider
+	// and the page is allowed to use it; falls back to the providers
+	// otherwise.
+	LocationSourceBrowser = "browser"
+)
+
+// Coordinate display formats, stored in Settings.CoordinateFormat (see
+// FormatCoordinatesIn).
+const (
+	// CoordinateFormatDecimal shows decimal degrees: 48.8566° N.
+	CoordinateFormatDecimal = "decimal"
+
+	// CoordinateFormatDMS shows degrees, minutes and seconds: 48°51'23.8" N,
+	// as printed on paper maps and by many GPS units.
+	CoordinateFormatDMS = "dms"
+)
+
+// Place name styles, stored in Settings.PlaceNameStyle (see
+// Location.DisplayName).
+const (
+	// PlaceNameShort shows the city and country: "Paris, France".
+	PlaceNameShort = "short"
+
+	// PlaceNameMedium adds the region: "Paris, Île-de-France, France".
+	PlaceNameMedium = "medium"
+
+	// PlaceNameFull shows the complete name from the geocoding service,
+	// e.g., "Eiffel Tower, Champ de Mars, 7th Arrondissement, Paris, ...".
+	PlaceNameFull = "full"
+)
+
+// Time panel layouts, stored in Settings.TimePanelLayout.
+const (
+	// TimePanelCompact shows sunrise and sunset above a grid of the golden
+	// and blue hours.
+	TimePanelCompact = "compact"
+
+	// TimePanelDetailed lists every event of the day in one table, in
+	// chronological order.
+	TimePanelDetailed = "detailed"
+)
+
+// Main window layouts, stored in Settings.WindowLayout.
+const (
+	// WindowLayoutAutomatic uses the compact layout while the window is too
+	// small for the panels beside the map (e.g., on a 1366x768 laptop or a
+	// small single-board computer display), and the wide one otherwise.
+	WindowLayoutAutomatic = "automatic"
+
+	// WindowLayoutWide shows the info panels beside the map.
+	WindowLayoutWide = "wide"
+
+	// WindowLayoutCompact stacks the map above the info panels, which
+	// share the space as tabs.
+	WindowLayoutCompact = "compact"
+)
+
 // =============================================================================
 // Settings
 // =============================================================================
@@ -11,11 +91,58 @@ package domain
 // 1. Solar Calculation Parameters:
 //   - GoldenHourElevation: defines when golden hour ends (sun elevation angle)
 //   - BlueHourStart/BlueHourEnd: define the blue hour period boundaries
+//   - CustomEvents: further sun elevations shown in the time panel
+//   - Presets/ActivePreset: named sets of the above, switched from the
+//     toolbar
 //
 // 2. Display Preferences:
 //   - TimeFormat24Hour: controls time display format
-//   - AutoDetectLocation: enables IP-based location detection on startup
+//   - CoordinateFormat: decimal degrees or degrees/minutes/seconds
+//   - PlaceNameStyle: short, medium or full place names
+//   - TimePanelLayout: golden/blue hour grid or a table of all events
+//   - CopyTemplate: the summary copied by Edit → Copy Times
+//   - MinimizeToTray: hides the minimized window in the system tray
+//   - AuroraForecast: rates the night for the aurora in the Astro panel
+//   - HomeTimezone: a second clock next to the location's local times
+//   - AutoDetectLocation: enables location detection on startup
+//   - LocationSource: selects IP-based or OS location detection
+//   - LocationProviders: fallback order and timeouts of the detection backends
+//   - SecureLocationOnly: skips detection backends that don't use HTTPS
+//   - PrivacyMode: keeps the app from contacting online services
 //   - LastLocation: persists the user's last selected location
+//   - HomeLocation: the location used on first run and when detection fails
+//   - RecentLocations: the last few selected scratch locations, for recall
+//   - ScratchRetentionDays: how long scratch locations are kept
+//   - MapZoom: persists the user's last map zoom level
+//   - PanelLayout: persists the arrangement of the side panels
+//   - WindowLayout: panels beside the map, below it as tabs, or by window size
+//   - TeachingMode: shows explanations next to the calculated times
+//
+// 3. Automation:
+//   - AutomationEnabled: master switch for running hooks
+//   - Hooks: commands to run at phase transitions
+//
+// 4. Advanced:
+//   - ContactEmail: contact address sent to OpenStreetMap's Nominatim
+//   - TileServer: custom map tile server (OpenStreetMap if unset)
+//   - SearchBias: language, countries and map area preferred by searches
+//
+// 5. Favorites and daily summary:
+//   - Favorites: saved locations, shown as a map layer
+//   - DailySummary: tomorrow's times for selected favorites, sent every day
+//
+// 6. Notifications, phone and calendar:
+//   - Notifications: desktop notifications before selected events
+//   - Push: event reminders and the 14-day schedule via ntfy or Pushover
+//   - CalDAV: the planned golden and blue hours sent to a calendar server
+//   - Webhook: a web request before selected events, for home automation
+//   - WidgetFeed: the home location's upcoming light, written to a file
+//     for desktop widgets
+//
+// 7. Updates:
+//   - LastSeenVersion: the app version that last ran with these settings
+//   - ShowWhatsNew: shows the release notes after an update
+//   - SchemaVersion: the settings file layout, for migrating older files
 //
 // Settings are persisted to disk via PreferencesStore and loaded on application
 // startup. The Validate method ensures all values are within acceptable ranges
@@ -69,6 +196,27 @@ type Settings struct {
 	// blue hour into deeper twilight with darker, more saturated blues.
 	BlueHourEnd float64 `json:"blue_hour_end"`
 
+	// CustomEvents are sun elevations beyond the golden and blue hour
+	// boundaries whose times are shown in the time panel (see CustomEvent),
+	// at most MaxCustomEvents. Managed from the Calculation tab of the
+	// preferences dialog.
+	//
+	// Default: none
+	CustomEvents []CustomEvent `json:"custom_events,omitempty"`
+
+	// Presets are the saved sets of elevation angles and custom events,
+	// switched from the toolbar (see Preset), at most MaxPresets.
+	//
+	// Default: DefaultPresets() (Landscape, Portraits and Astro)
+	Presets []Preset `json:"presets,omitempty"`
+
+	// ActivePreset names the preset the current angles and custom events
+	// belong to; changing them changes that preset. Empty if none is
+	// active (e.g., after it was deleted).
+	//
+	// Default: "Landscape"
+	ActivePreset string `json:"active_preset,omitempty"`
+
 	// TimeFormat24Hour determines whether times are displayed in 24-hour format.
 	// true  = 24-hour format (e.g., "14:30", "06:45")
 	// false = 12-hour format with AM/PM (e.g., "2:30 PM", "6:45 AM")
@@ -76,17 +224,112 @@ type Settings struct {
 	// Default: true (24-hour format)
 	TimeFormat24Hour bool `json:"time_format_24_hour"`
 
-	// AutoDetectLocation enables automatic IP-based location detection on startup.
-	// When enabled, the app queries ip-api.com to determine the user's approximate
-	// location based on their IP address. This is convenient but may not be accurate
-	// for users behind VPNs or in regions with poor IP geolocation data.
+	// CoordinateFormat selects how latitudes and longitudes are displayed in
+	// the location panel and exports: CoordinateFormatDecimal or
+	// CoordinateFormatDMS. Unknown values are reset to decimal.
+	//
+	// Default: CoordinateFormatDecimal
+	CoordinateFormat string `json:"coordinate_format"`
+
+	// PlaceNameStyle selects how much of a place's address is shown in the
+	// location panel, favorites, status bar and exports: PlaceNameShort,
+	// PlaceNameMedium or PlaceNameFull. Unknown values are reset to short.
+	//
+	// Default: PlaceNameShort
+	PlaceNameStyle string `json:"place_name_style"`
+
+	// TimePanelLayout selects how the time panel shows the day:
+	// TimePanelCompact (the golden and blue hour grid) or TimePanelDetailed
+	// (all events in one chronological table). Unknown values are reset to
+	// compact.
+	//
+	// Default: TimePanelCompact
+	TimePanelLayout string `json:"time_panel_layout,omitempty"`
+
+	// CopyTemplate is the text Edit → Copy Times puts on the clipboard, with
+	// placeholders for the day's times in Go template syntax (e.g.,
+	// "Golden hour {{.GoldenEvening}}"; see export.CopyData). Empty means
+	// the built-in Markdown summary (export.DefaultCopyTemplate).
+	//
+	// Default: "" (built-in summary)
+	CopyTemplate string `json:"copy_template,omitempty"`
+
+	// HomeTimezone is the IANA timezone of the user's home clock (e.g.,
+	// "America/New_York"). When set, event times are also shown on the
+	// home clock, for planning a remote shoot around calls at home (see
+	// FormatHomeTime). Empty turns the second clock off, as do names that
+	// can't be loaded. Managed from the Display tab of the preferences
+	// dialog.
+	//
+	// Default: empty (local times only)
+	HomeTimezone string `json:"home_timezone,omitempty"`
+
+	// MinimizeToTray hides the window when it is minimized, leaving only
+	// the system tray icon (which shows the next golden or blue hour in
+	// its tooltip). Clicking the icon brings the window back. Has no
+	// effect on desktops without a system tray. Managed from the Display
+	// tab of the preferences dialog.
+	//
+	// Default: false
+	MinimizeToTray bool `json:"minimize_to_tray,omitempty"`
+
+	// AuroraForecast rates the night after the selected date for the
+	// aurora in the Astro panel, by the Kp index forecast of NOAA's Space
+	// Weather Prediction Center (see AuroraOutlook). Off by default as it
+	// only matters at high latitudes and asks one more service after each
+	// recalculation. Managed from the Display tab of the preferences
+	// dialog.
+	//
+	// Default: false
+	AuroraForecast bool `json:"aurora_forecast,omitempty"`
+
+	// AutoDetectLocation enables automatic location detection on startup.
+	// When enabled, the app detects the user's location with the backend
+	// selected by LocationSource. With IP detection this is convenient but may
+	// not be accurate for users behind VPNs or in regions with poor IP
+	// geolocation data.
 	//
 	// When disabled, the app uses the LastLocation if available, or falls back
-	// to the default location (London, UK).
+	// to the home location (see Home).
 	//
 	// Default: true (auto-detect enabled)
 	AutoDetectLocation bool `json:"auto_detect_location"`
 
+	// LocationSource selects how the location is detected, both on startup
+	// and when the user clicks "Detect": LocationSourceIP,
+	// LocationSourceSystem, or LocationSourceBrowser. Unknown values are
+	// reset to LocationSourceIP.
+	//
+	// Default: LocationSourceIP (works without OS permissions)
+	LocationSource string `json:"location_source"`
+
+	// LocationProviders is the fallback order of the detection backends,
+	// with a timeout each (see LocationProvider and DetectionOrder).
+	// Managed from the Location tab of the preferences dialog.
+	//
+	// Default: DefaultLocationProviders (ip-api.com, then ipinfo.io)
+	LocationProviders []LocationProvider `json:"location_providers"`
+
+	// SecureLocationOnly skips the location providers that send the
+	// request unencrypted (see LocationProvider.Encrypted): ip-api.com's
+	// free service only speaks plain HTTP, so anyone on the network path
+	// can read the detected location. Managed from the Location tab of the
+	// preferences dialog.
+	//
+	// Default: false
+	SecureLocationOnly bool `json:"secure_location_only,omitempty"`
+
+	// PrivacyMode keeps the app from contacting online services: location
+	// detection only uses the system location services, searches and place
+	// names come from the offline city database and the geocoding cache,
+	// the map only shows cached (or local server) tiles, and elevation
+	// profiles, the cloud layer and push notifications are unavailable
+	// (ErrPrivacyMode). Toggled from the Edit menu; the status bar shows
+	// while it is on.
+	//
+	// Default: false
+	PrivacyMode bool `json:"privacy_mode,omitempty"`
+
 	// LastLocation stores the user's last selected location for persistence.
 	// This is used to restore the user's location when they restart the app
 	// (if AutoDetectLocation is disabled) and is updated whenever the user
@@ -95,6 +338,201 @@ type Settings struct {
 	// This field is a pointer so it can be nil (omitted from JSON) when no
 	// location has been saved yet.
 	LastLocation *Location `json:"last_location,omitempty"`
+
+	// HomeLocation is the user's home location: shown on first run (before
+	// there is a LastLocation) and whenever location detection fails. Set
+	// from the Location tab of the preferences dialog ("Set Current as
+	// Home"); nil uses DefaultLocation (see Home).
+	//
+	// Personal like the last location, so never included in config codes.
+	//
+	// Default: nil (London, UK)
+	HomeLocation *Location `json:"home_location,omitempty"`
+
+	// RecentLocations lists the last selected scratch locations, most
+	// recent first (at most MaxRecentLocations), whether they came from a
+	// search, a map click or detection; favorites are not included (see
+	// RecentLocation). Shown in the location panel's "Recent" menu so the
+	// user can jump back after exploring the map.
+	//
+	// Personal like the last location, so never included in config codes.
+	//
+	// Default: none
+	RecentLocations []RecentLocation `json:"recent_locations,omitempty"`
+
+	// ScratchRetentionDays is how many days scratch locations stay in
+	// RecentLocations after they were last used: ScratchKeepSession drops
+	// them at the next start, ScratchKeepForever keeps them until newer
+	// ones push them out.
+	//
+	// Default: DefaultScratchRetentionDays (30)
+	ScratchRetentionDays int `json:"scratch_retention_days"`
+
+	// MapZoom stores the map's last zoom level so it survives location
+	// changes and app restarts. Updated whenever the user zooms the map.
+	//
+	// Range: 1 (whole world) to 19 (street level) (validated by Validate method)
+	// Default: 13 (city level)
+	MapZoom int `json:"map_zoom"`
+
+	// PanelLayout stores the arrangement of the main window's side panels
+	// (docked left or right of the map, floating or hidden, and their
+	// sizes) so it survives app restarts. Saved when the app quits, in the
+	// format of Qt's QMainWindow.SaveState (base64 in the JSON file).
+	//
+	// Default: none (the panels stacked right of the map)
+	PanelLayout []byte `json:"panel_layout,omitempty"`
+
+	// WindowLayout selects where the info panels go (View → Layout):
+	// beside the map (WindowLayoutWide), below it as tabs
+	// (WindowLayoutCompact), or depending on the window size
+	// (WindowLayoutAutomatic). Unknown values are reset to automatic.
+	//
+	// Default: WindowLayoutAutomatic
+	WindowLayout string `json:"window_layout,omitempty"`
+
+	// TeachingMode annotates the sun times display with short explanations
+	// of the elevation angles and the order of golden and blue hour. The
+	// explanations come from the help package's data file.
+	//
+	// Intended for workshops and first-time users; experienced users will
+	// usually leave it off to keep the panel compact.
+	//
+	// Default: false
+	TeachingMode bool `json:"teaching_mode"`
+
+	// AutomationEnabled is the master switch for automation hooks. When
+	// false, no hook runs even if individual hooks are enabled, which makes
+	// it easy to pause automation without losing the configured commands.
+	//
+	// Default: false (automation must be explicitly turned on)
+	AutomationEnabled bool `json:"automation_enabled"`
+
+	// Hooks lists the commands to run at phase transitions (see Hook).
+	// Managed from the Automation tab of the preferences dialog.
+	//
+	// Hooks are never included in config codes, so importing a code shared
+	// by someone else can't add commands to this machine.
+	Hooks []Hook `json:"hooks,omitempty"`
+
+	// ContactEmail is an optional email address included in the User-Agent
+	// of geocoding requests, as OpenStreetMap's Nominatim usage policy
+	// recommends, so its operators can reach the user about a problem with
+	// their requests instead of blocking them. Managed from the Network tab
+	// of the preferences dialog.
+	//
+	// Like the last location it is personal, so it is never included in
+	// config codes.
+	//
+	// Default: "" (requests name only the project page)
+	ContactEmail string `json:"contact_email,omitempty"`
+
+	// TileServer is the server the map's base layer tiles are loaded from
+	// (see TileServer). Managed from the Map tab of the preferences
+	// dialog. Not included in config codes: it may hold an API key, and a
+	// local server address is meaningless on another machine.
+	//
+	// Default: zero value (public OpenStreetMap tiles)
+	TileServer TileServer `json:"tile_server"`
+
+	// SearchBias steers location searches toward a language, countries and
+	// the map's area (see SearchBias). Managed from the Network tab of the
+	// preferences dialog. Not included in config codes, as it depends on
+	// where the user lives.
+	//
+	// Default: zero value (worldwide, in the system language)
+	SearchBias SearchBias `json:"search_bias"`
+
+	// Favorites are the locations the user saved (see Favorite), in the
+	// order they were added. Personal, so never included in config codes.
+	//
+	// Default: none
+	Favorites []Favorite `json:"favorites,omitempty"`
+
+	// DailySummary configures the daily summary automation (see
+	// DailySummary). Managed from the Daily Summary tab of the preferences
+	// dialog.
+	//
+	// Default: DefaultDailySummary() (turned off)
+	DailySummary DailySummary `json:"daily_summary"`
+
+	// Notifications configures the desktop notifications shown before
+	// selected events (see DesktopNotifications). Managed from the
+	// Notifications tab of the preferences dialog.
+	//
+	// Default: DefaultDesktopNotifications() (turned off)
+	Notifications DesktopNotifications `json:"desktop_notifications"`
+
+	// Push configures reminders pushed to the user's phone (see
+	// PushNotifications). Managed from the Phone tab of the preferences
+	// dialog.
+	//
+	// Default: DefaultPushNotifications() (turned off)
+	Push PushNotifications `json:"push"`
+
+	// CalDAV configures sending the planned golden and blue hours to the
+	// user's calendar server (see CalDAVSync). Managed from the Calendar tab
+	// of the preferences dialog.
+	//
+	// Default: no calendar (sync off)
+	CalDAV CalDAVSync `json:"caldav"`
+
+	// Webhook configures the web request sent before selected events (see
+	// Webhook). Managed from the Webhook tab of the preferences dialog.
+	//
+	// Default: DefaultWebhook() (turned off)
+	Webhook Webhook `json:"webhook"`
+
+	// WidgetFeed configures the JSON or RSS file of upcoming golden and
+	// blue hours for desktop widgets (see WidgetFeed). Managed from the
+	// Widget Feed tab of the preferences dialog.
+	//
+	// Default: DefaultWidgetFeed() (turned off)
+	WidgetFeed WidgetFeed `json:"widget_feed"`
+
+	// LastSeenVersion is the app version that last ran with these settings,
+	// used to show the release notes of newer versions once after an update.
+	// Settings files from before it was tracked don't have it.
+	//
+	// Default: "" (set on the first start)
+	LastSeenVersion string `json:"last_seen_version,omitempty"`
+
+	// ShowWhatsNew shows the "What's new" dialog after an update. The
+	// dialog itself has a checkbox to turn it off; the notes stay available
+	// from Help → What's New.
+	//
+	// Default: true
+	ShowWhatsNew bool `json:"show_whats_new"`
+
+	// SchemaVersion is the layout of the settings file, set by
+	// PreferencesStore when saving. Files of older layouts are migrated when
+	// loaded (see storage.PreferencesStore.Load); files from before it was
+	// tracked don't have it.
+	//
+	// Default: SettingsSchemaVersion
+	SchemaVersion int `json:"schema_version"`
+}
+
+// SettingsSchemaVersion is the current layout of the settings file:
+//
+//   - 1 (or none): a single set of elevation angles
+//   - 2: presets (see Preset) and custom events
+const SettingsSchemaVersion = 2
+
+// maxEmailLength is the longest email address accepted (RFC 5321 limit).
+const maxEmailLength = 254
+
+// IsValidContactEmail reports whether email is a plain address such as
+// "me@example.com", without a display name or angle brackets.
+//
+// The address is sent in an HTTP header, so anything that isn't a bare
+// address (spaces, line breaks, several addresses) is rejected.
+func IsValidContactEmail(email string) bool {
+	if email == "" || len(email) > maxEmailLength {
+		return false
+	}
+	addr, err := mail.ParseAddress(email)
+	return err == nil && addr.Name == "" && addr.Address == email
 }
 
 // DefaultSettings returns the default application settings.
@@ -106,17 +544,57 @@ type Settings struct {
 // Default values:
 //   - Golden hour elevation: 6° (sun 0-6° above horizon)
 //   - Blue hour: -4° to -8° (sun 4-8° below horizon)
+//   - Presets: Landscape (active), Portraits and Astro; no custom events
 //   - Time format: 24-hour
+//   - Coordinate format: decimal degrees
+//   - Place names: short (city and country)
 //   - Auto-detect location: enabled
+//   - Location source: IP address
 //   - Last location: none (will use London, UK as fallback)
+//   - Recent locations: none, kept 30 days
+//   - Map zoom: 13 (city level)
+//   - Teaching mode: disabled
+//   - Automation: disabled, no hooks
+//   - Contact email: none
+//   - Tile server: OpenStreetMap
+//   - Favorites: none; daily summary: disabled
+//   - Phone reminders: disabled; calendar sync: not set up
+//   - Webhook: disabled; widget feed: disabled
+//   - What's new after updates: shown
 func DefaultSettings() Settings {
 	return Settings{
-		GoldenHourElevation: 6.0,
-		BlueHourStart:       -4.0,
-		BlueHourEnd:         -8.0,
-		TimeFormat24Hour:    true,
-		AutoDetectLocation:  true,
-		LastLocation:        nil,
+		GoldenHourElevation:  6.0,
+		BlueHourStart:        -4.0,
+		BlueHourEnd:          -8.0,
+		Presets:              DefaultPresets(),
+		ActivePreset:         "Landscape",
+		TimeFormat24Hour:     true,
+		CoordinateFormat:     CoordinateFormatDecimal,
+		PlaceNameStyle:       PlaceNameShort,
+		TimePanelLayout:      TimePanelCompact,
+		WindowLayout:         WindowLayoutAutomatic,
+		AutoDetectLocation:   true,
+		LocationSource:       LocationSourceIP,
+		LocationProviders:    DefaultLocationProviders(),
+		LastLocation:         nil,
+		HomeLocation:         nil,
+		RecentLocations:      nil,
+		ScratchRetentionDays: DefaultScratchRetentionDays,
+		MapZoom:              13,
+		TeachingMode:         false,
+		AutomationEnabled:    false,
+		Hooks:                nil,
+		ContactEmail:         "",
+		TileServer:           TileServer{},
+		Favorites:            nil,
+		DailySummary:         DefaultDailySummary(),
+		Notifications:        DefaultDesktopNotifications(),
+		Push:                 DefaultPushNotifications(),
+		Webhook:              DefaultWebhook(),
+		WidgetFeed:           DefaultWidgetFeed(),
+		LastSeenVersion:      "",
+		ShowWhatsNew:         true,
+		SchemaVersion:        SettingsSchemaVersion,
 	}
 }
 
@@ -131,36 +609,198 @@ func DefaultSettings() Settings {
 //   - BlueHourStart: clamped to [-6, 0] degrees
 //   - BlueHourEnd: clamped to [-18, -6] degrees
 //   - BlueHourEnd must be below BlueHourStart (if not, adjusted to Start - 4)
+//   - CustomEvents: unnamed events dropped, elevations clamped to
+//     [MinCustomElevation, MaxCustomElevation], at most MaxCustomEvents
+//   - Presets: unnamed and duplicate presets dropped, angles and custom
+//     events repaired like the above; ActivePreset cleared if it doesn't
+//     name one
+//   - MapZoom: clamped to [1, 19]
+//   - LocationSource: unknown values reset to LocationSourceIP
+//   - LocationProviders: unknown and repeated providers dropped, timeouts
+//     clamped to [1, 120] seconds, missing providers appended
+//   - CoordinateFormat: unknown values reset to CoordinateFormatDecimal
+//   - PlaceNameStyle: unknown values reset to PlaceNameShort
+//   - TimePanelLayout: unknown values reset to TimePanelCompact
+//   - WindowLayout: unknown values reset to WindowLayoutAutomatic
+//   - HomeTimezone: cleared if it isn't a valid timezone (see
+//     ValidTimezone)
+//   - LastLocation: dropped if its coordinates are invalid, otherwise its
+//     name, elevation and timezone are repaired (see Location.Sanitize)
+//   - HomeLocation: dropped or repaired like LastLocation
+//   - RecentLocations: repaired like LastLocation, at most
+//     MaxRecentLocations kept
+//   - ScratchRetentionDays: reset to DefaultScratchRetentionDays if out
+//     of range
+//   - ContactEmail: trimmed; cleared if it isn't a plain email address
+//   - TileServer: trimmed; reset to OpenStreetMap if invalid (see
+//     TileServer.Validate)
+//   - SearchBias: languages and countries cleared if malformed (see
+//     SearchBias.Validate)
+//   - Favorites: invalid entries dropped, the rest repaired like LastLocation
+//   - DailySummary: defaults filled in, unknown favorites dropped, turned
+//     off if it couldn't be delivered (see DailySummary.Check)
+//   - Notifications: unknown events and favorites dropped, lead times out
+//     of range dropped, turned off without a lead time
+//   - Push: defaults filled in, unknown events dropped, lead time clamped,
+//     turned off if it couldn't be pushed (see PushNotifications.Check)
+//   - CalDAV: a calendar URL that fails CalDAVSync.Check turns sync off
+//   - Webhook: unknown events and favorites dropped, lead time clamped,
+//     turned off without a valid URL (see Webhook.Check)
+//   - WidgetFeed: unknown format reset to JSON, interval clamped, turned
+//     off without a full file path (see WidgetFeed.Check)
 //
 // The method modifies the Settings in place (receiver is a pointer).
 func (s *Settings) Validate() {
+	s.GoldenHourElevation, s.BlueHourStart, s.BlueHourEnd =
+		clampElevations(s.GoldenHourElevation, s.BlueHourStart, s.BlueHourEnd)
+
+	// Custom events and presets feed the calculator like the angles above;
+	// a preset that no longer exists can't be active
+	s.CustomEvents = validateCustomEvents(s.CustomEvents)
+	s.Presets = validatePresets(s.Presets)
+	if i := FindPreset(s.Presets, s.ActivePreset); i >= 0 {
+		s.ActivePreset = s.Presets[i].Name
+	} else {
+		s.ActivePreset = ""
+	}
+
+	// Map zoom must be a level the OpenStreetMap tiles are available for
+	if s.MapZoom < 1 {
+		s.MapZoom = 1
+	} else if s.MapZoom > 19 {
+		s.MapZoom = 19
+	}
+
+	// Location source must name a known backend
+	if s.LocationSource != LocationSourceSystem && s.LocationSource != LocationSourceBrowser {
+		s.LocationSource = LocationSourceIP
+	}
+	s.LocationProviders = RepairLocationProviders(s.LocationProviders)
+
+	// Coordinate format must be a known one
+	if s.CoordinateFormat != CoordinateFormatDMS {
+		s.CoordinateFormat = CoordinateFormatDecimal
+	}
+
+	// Place name style must be a known one
+	if s.PlaceNameStyle != PlaceNameMedium && s.PlaceNameStyle != PlaceNameFull {
+		s.PlaceNameStyle = PlaceNameShort
+	}
+
+	// Time panel layout must be a known one
+	if s.TimePanelLayout != TimePanelDetailed {
+		s.TimePanelLayout = TimePanelCompact
+	}
+
+	// Window layout must be a known one
+	if s.WindowLayout != WindowLayoutWide && s.WindowLayout != WindowLayoutCompact {
+		s.WindowLayout = WindowLayoutAutomatic
+	}
+	if s.HomeTimezone != "" && !ValidTimezone(s.HomeTimezone) {
+		s.HomeTimezone = ""
+	}
+
+	// Last location is fed straight into the calculator on startup, so it
+	// must be usable. A copy is repaired so the caller's Location is untouched.
+	if s.LastLocation != nil {
+		loc := *s.LastLocation
+		if loc.Sanitize() {
+			s.LastLocation = &loc
+		} else {
+			s.LastLocation = nil
+		}
+	}
+	// Home location replaces it when detection fails, so the same applies
+	if s.HomeLocation != nil {
+		loc := *s.HomeLocation
+		if loc.Sanitize() {
+			s.HomeLocation = &loc
+		} else {
+			s.HomeLocation = nil
+		}
+	}
+	s.RecentLocations = validateRecentLocations(s.RecentLocations)
+	if !validScratchRetention(s.ScratchRetentionDays) {
+		s.ScratchRetentionDays = DefaultScratchRetentionDays
+	}
+
+	// Contact email is sent in an HTTP header, so it must be a bare address
+	s.ContactEmail = strings.TrimSpace(s.ContactEmail)
+	if s.ContactEmail != "" && !IsValidContactEmail(s.ContactEmail) {
+		s.ContactEmail = ""
+	}
+
+	// A broken tile URL would leave the map blank, so fall back to OSM
+	s.TileServer.URL = strings.TrimSpace(s.TileServer.URL)
+	s.TileServer.Subdomains = strings.TrimSpace(s.TileServer.Subdomains)
+	s.TileServer.APIKey = strings.TrimSpace(s.TileServer.APIKey)
+	if s.TileServer.Validate() != nil {
+		s.TileServer = TileServer{}
+	}
+
+	// Malformed codes would be sent to Nominatim, which rejects them
+	if s.SearchBias.Validate() != nil {
+		s.SearchBias = SearchBias{NearMapView: s.SearchBias.NearMapView}
+	}
+
+	// Favorites are fed into the calculator like the last location
+	s.Favorites = validateFavorites(s.Favorites)
+	s.DailySummary.validate(s.Favorites)
+	s.Notifications.validate(s.Favorites)
+	s.Push.validate()
+	s.CalDAV.validate()
+	s.Webhook.validate(s.Favorites)
+	s.WidgetFeed.validate()
+}
+
+// Home returns the home location: HomeLocation if the user set one,
+// otherwise DefaultLocation.
+//
+// Used on first run, before there is a LastLocation, and when location
+// detection fails.
+func (s Settings) Home() Location {
+	if s.HomeLocation != nil {
+		return *s.HomeLocation
+	}
+	return DefaultLocation()
+}
+
+// ForLocation returns the settings to calculate with at a location: the
+// settings with the golden hour angle of the favorite at its coordinates
+// (see FavoriteOverrides), or unchanged if there is no such favorite or it
+// keeps the general angle.
+//
+// The blue hour angles aren't overridden, so the golden hour angle is
+// clamped with them (see Validate).
+func (s Settings) ForLocation(loc Location) Settings {
+	f, ok := FavoriteAt(s.Favorites, loc)
+	if !ok || f.Overrides.GoldenHourElevation == nil {
+		return s
+	}
+	s.GoldenHourElevation, s.BlueHourStart, s.BlueHourEnd =
+		clampElevations(*f.Overrides.GoldenHourElevation, s.BlueHourStart, s.BlueHourEnd)
+	return s
+}
+
+// clampElevations clamps the golden and blue hour elevation angles to their
+// ranges (see Validate), for the settings and for every preset.
+func clampElevations(golden, blueStart, blueEnd float64) (float64, float64, float64) {
 	// Golden hour elevation must be between 0° (horizon) and 15° (very high sun)
 	// Values outside this range would produce unrealistic golden hour periods
-	if s.GoldenHourElevation < 0 {
-		s.GoldenHourElevation = 0
-	} else if s.GoldenHourElevation > 15 {
-		s.GoldenHourElevation = 15
-	}
+	golden = min(max(golden, 0), 15)
 
 	// Blue hour start must be between 0° and -6° (civil twilight boundary)
 	// Values outside this range would overlap with golden hour or deep twilight
-	if s.BlueHourStart > 0 {
-		s.BlueHourStart = 0
-	} else if s.BlueHourStart < -6 {
-		s.BlueHourStart = -6
-	}
+	blueStart = min(max(blueStart, -6), 0)
 
 	// Blue hour end must be between -6° and -18° (astronomical twilight)
 	// Below -18° is full darkness, above -6° is civil twilight
-	if s.BlueHourEnd > -6 {
-		s.BlueHourEnd = -6
-	} else if s.BlueHourEnd < -18 {
-		s.BlueHourEnd = -18
-	}
+	blueEnd = min(max(blueEnd, -18), -6)
 
 	// Blue hour end must be below (more negative than) blue hour start
 	// Otherwise the blue hour period would have negative duration
-	if s.BlueHourEnd > s.BlueHourStart {
-		s.BlueHourEnd = s.BlueHourStart - 4
+	if blueEnd > blueStart {
+		blueEnd = blueStart - 4
 	}
+	return golden, blueStart, blueEnd
 }
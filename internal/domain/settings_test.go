@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestValidateLastLocation(t *testing.T) {
+	valid := Location{Latitude: 48.8566, Longitude: 2.3522, Elevation: 35, Name: "Paris, France", Timezone: "Europe/Paris"}
+	with := func(change func(*Location)) *Location {
+		loc := valid
+		change(&loc)
+		return &loc
+	}
+
+	tests := []struct {
+		name string
+		last *Location
+		want *Location // nil: dropped
+	}{
+		{name: "none saved", last: nil, want: nil},
+		{name: "valid location is kept", last: with(func(*Location) {}), want: &valid},
+		{name: "latitude out of range", last: with(func(l *Location) { l.Latitude = 91 }), want: nil},
+		{name: "longitude out of range", last: with(func(l *Location) { l.Longitude = -180.5 }), want: nil},
+		{name: "NaN latitude", last: with(func(l *Location) { l.Latitude = math.NaN() }), want: nil},
+		{
+			name: "unknown timezone is cleared",
+			last: with(func(l *Location) { l.Timezone = "Mars/Olympus_Mons" }),
+			want: with(func(l *Location) { l.Timezone = "" }),
+		},
+		{
+			name: "impossible elevation is reset",
+			last: with(func(l *Location) { l.Elevation = math.Inf(1) }),
+			want: with(func(l *Location) { l.Elevation = 0 }),
+		},
+		{
+			name: "control characters and spaces are cleaned",
+			last: with(func(l *Location) { l.Name = "  Paris,\nFrance\t" }),
+			want: with(func(l *Location) { l.Name = "Paris, France" }),
+		},
+		{
+			name: "invalid UTF-8 is replaced",
+			last: with(func(l *Location) { l.Name = "Caf\xe9" }),
+			want: with(func(l *Location) { l.Name = "Caf\uFFFD" }),
+		},
+		{
+			name: "long name is cut",
+			last: with(func(l *Location) { l.Name = strings.Repeat("é", MaxLocationNameLength+50) }),
+			want: with(func(l *Location) { l.Name = strings.Repeat("é", MaxLocationNameLength) }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := DefaultSettings()
+			s.LastLocation = tt.last
+			s.Validate()
+			switch {
+			case tt.want == nil && s.LastLocation != nil:
+				t.Errorf("LastLocation = %+v, want dropped", *s.LastLocation)
+			case tt.want != nil && s.LastLocation == nil:
+				t.Errorf("LastLocation dropped, want %+v", *tt.want)
+			case tt.want != nil && *s.LastLocation != *tt.want:
+				t.Errorf("LastLocation = %+v, want %+v", *s.LastLocation, *tt.want)
+			}
+		})
+	}
+}
+
+func TestHomeLocation(t *testing.T) {
+	s := DefaultSettings()
+	if s.Home() != DefaultLocation() {
+		t.Errorf("Home() without a home location = %+v, want DefaultLocation", s.Home())
+	}
+
+	home := Location{Latitude: 47.3769, Longitude: 8.5417, Name: "Zürich", Timezone: "Europe/Zurich"}
+	s.HomeLocation = &home
+	s.Validate()
+	if s.Home() != home {
+		t.Errorf("Home() = %+v, want %+v", s.Home(), home)
+	}
+
+	// A home location that can't be calculated for falls back to London
+	s.HomeLocation = &Location{Latitude: 120, Longitude: 8.5417, Name: "Nowhere"}
+	s.Validate()
+	if s.HomeLocation != nil || s.Home() != DefaultLocation() {
+		t.Errorf("invalid home location kept as %+v", s.HomeLocation)
+	}
+}
+
+func TestValidateContactEmail(t *testing.T) {
+	tests := []struct {
+		email string
+		want  string
+	}{
+		{"", ""},
+		{"me@example.com", "me@example.com"},
+		{"  me@example.com \n", "me@example.com"},
+		{"Me <me@example.com>", ""},
+		{"me@example.com, you@example.com", ""},
+		{"not an address", ""},
+		{"me@example.com\r\nX-Injected: 1", ""},
+		{strings.Repeat("a", 250) + "@example.com", ""},
+	}
+
+	for _, tt := range tests {
+		s := DefaultSettings()
+		s.ContactEmail = tt.email
+		s.Validate()
+		if s.ContactEmail != tt.want {
+			t.Errorf("Validate(%q) kept %q, want %q", tt.email, s.ContactEmail, tt.want)
+		}
+	}
+}
+
+func TestValidateCoordinateFormat(t *testing.T) {
+	for format, want := range map[string]string{
+		CoordinateFormatDecimal: CoordinateFormatDecimal,
+		CoordinateFormatDMS:     CoordinateFormatDMS,
+		"":                      CoordinateFormatDecimal,
+		"utm":                   CoordinateFormatDecimal,
+	} {
+		s := DefaultSettings()
+		s.CoordinateFormat = format
+		s.Validate()
+		if s.CoordinateFormat != want {
+			t.Errorf("Validate(%q) kept %q, want %q", format, s.CoordinateFormat, want)
+		}
+	}
+}
+
+func TestValidatePlaceNameStyle(t *testing.T) {
+	for style, want := range map[string]string{
+		PlaceNameShort:  PlaceNameShort,
+		PlaceNameMedium: PlaceNameMedium,
+		PlaceNameFull:   PlaceNameFull,
+		"":              PlaceNameShort,
+		"tiny":          PlaceNameShort,
+	} {
+		s := DefaultSettings()
+		s.PlaceNameStyle = style
+		s.Validate()
+		if s.PlaceNameStyle != want {
+			t.Errorf("Validate(%q) kept %q, want %q", style, s.PlaceNameStyle, want)
+		}
+	}
+}
+
+func TestValidateTimePanelLayout(t *testing.T) {
+	for layout, want := range map[string]string{
+		TimePanelCompact:  TimePanelCompact,
+		TimePanelDetailed: TimePanelDetailed,
+		"":                TimePanelCompact,
+		"grid":            TimePanelCompact,
+	} {
+		s := DefaultSettings()
+		s.TimePanelLayout = layout
+		s.Validate()
+		if s.TimePanelLayout != want {
+			t.Errorf("Validate(%q) kept %q, want %q", layout, s.TimePanelLayout, want)
+		}
+	}
+}
+
+func TestValidateWindowLayout(t *testing.T) {
+	for layout, want := range map[string]string{
+		WindowLayoutAutomatic: WindowLayoutAutomatic,
+		WindowLayoutWide:      WindowLayoutWide,
+		WindowLayoutCompact:   WindowLayoutCompact,
+		"":                    WindowLayoutAutomatic,
+		"narrow":              WindowLayoutAutomatic,
+	} {
+		s := DefaultSettings()
+		s.WindowLayout = layout
+		s.Validate()
+		if s.WindowLayout != want {
+			t.Errorf("Validate(%q) kept %q, want %q", layout, s.WindowLayout, want)
+		}
+	}
+}
+
+func TestValidateHomeTimezone(t *testing.T) {
+	for zone, want := range map[string]string{
+		"America/New_York": "America/New_York",
+		"":                 "",
+		"Local":            "",
+		"Nowhere/Special":  "",
+	} {
+		s := DefaultSettings()
+		s.HomeTimezone = zone
+		s.Validate()
+		if s.HomeTimezone != want {
+			t.Errorf("Validate(%q) kept %q, want %q", zone, s.HomeTimezone, want)
+		}
+	}
+}
+
+func TestValidateSearchBias(t *testing.T) {
+	s := DefaultSettings()
+	s.SearchBias = SearchBias{Language: "de,pt-BR", Countries: []string{"at", "de"}, NearMapView: true}
+	s.Validate()
+	if s.SearchBias.Language != "de,pt-BR" || len(s.SearchBias.Countries) != 2 {
+		t.Errorf("Validate changed a valid bias to %+v", s.SearchBias)
+	}
+
+	s.SearchBias = SearchBias{Language: "de", Countries: []string{"austria"}, NearMapView: true}
+	s.Validate()
+	if b := s.SearchBias; b.Language != "" || b.Countries != nil || !b.NearMapView {
+		t.Errorf("Validate kept a malformed bias: %+v", s.SearchBias)
+	}
+}
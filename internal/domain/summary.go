@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// =============================================================================
+// Daily Summary
+// =============================================================================
+
+// Delivery methods for the daily summary, stored in DailySummary.Delivery.
+const (
+	// SummaryDeliveryFile writes the summary to DailySummary.FilePath,
+	// replacing the previous day's summary (e.g., in a synced folder).
+	SummaryDeliveryFile = "file"
+
+	// SummaryDeliverySendmail pipes the summary as an email to the local
+	// sendmail program (DailySummary.SendmailPath), as provided by Postfix,
+	// msmtp or ssmtp.
+	SummaryDeliverySendmail = "sendmail"
+
+	// SummaryDeliverySMTP sends the summary as an email through an SMTP
+	// server (DailySummary.SMTPServer), normally a relay on the local
+	// machine or network that doesn't require a login.
+	SummaryDeliverySMTP = "smtp"
+)
+
+// Defaults for the daily summary.
+const (
+	// DefaultSummaryTime is when the summary is sent: early enough in the
+	// evening to plan tomorrow's shoot.
+	DefaultSummaryTime = "19:00"
+
+	// DefaultSendmailPath is where sendmail lives on most Unix systems.
+	DefaultSendmailPath = "/usr/sbin/sendmail"
+
+	// DefaultSMTPServer is a mail relay on the local machine.
+	DefaultSMTPServer = "localhost:25"
+)
+
+// DailySummary configures the daily summary automation.
+//
+// Photographers who plan each evening can have tomorrow's golden and blue
+// hour times for their favorite spots delivered at a fixed time every day,
+// as a file or an email. The summary goes out whether or not the app's
+// window is in use, as long as the app is running.
+type DailySummary struct {
+	// Enabled turns the daily summary on.
+	//
+	// Default: false
+	Enabled bool `json:"enabled"`
+
+	// Time is the local time of day the summary is sent, as "HH:MM".
+	//
+	// Default: DefaultSummaryTime
+	Time string `json:"time"`
+
+	// Favorites lists the IDs of the favorites included (see Favorite.ID),
+	// in the order they appear in the summary.
+	Favorites []string `json:"favorites,omitempty"`
+
+	// Delivery is how the summary is sent: SummaryDeliveryFile,
+	// SummaryDeliverySendmail or SummaryDeliverySMTP.
+	//
+	// Default: SummaryDeliveryFile
+	Delivery string `json:"delivery"`
+
+	// FilePath is the file the summary is written to (file delivery).
+	FilePath string `json:"file_path,omitempty"`
+
+	// EmailTo is the recipient address (sendmail and SMTP delivery). The
+	// same address is used as the sender.
+	EmailTo string `json:"email_to,omitempty"`
+
+	// SendmailPath is the sendmail program (sendmail delivery).
+	//
+	// Default: DefaultSendmailPath
+	SendmailPath string `json:"sendmail_path,omitempty"`
+
+	// SMTPServer is the mail server as "host:port" (SMTP delivery). No login
+	// is used, so no password is ever stored in the settings file.
+	//
+	// Default: DefaultSMTPServer
+	SMTPServer string `json:"smtp_server,omitempty"`
+}
+
+// DefaultDailySummary returns the daily summary configuration for new users
+// (turned off, file delivery).
+func DefaultDailySummary() DailySummary {
+	return DailySummary{
+		Time:         DefaultSummaryTime,
+		Delivery:     SummaryDeliveryFile,
+		SendmailPath: DefaultSendmailPath,
+		SMTPServer:   DefaultSMTPServer,
+	}
+}
+
+// ParseSummaryTime parses a time of day in "HH:MM" form.
+//
+// Returns the hour and minute, or an error if the text isn't a valid time.
+func ParseSummaryTime(text string) (hour, minute int, err error) {
+	t, err := time.Parse("15:04", text)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid time %q (use HH:MM)", text)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Check reports the first problem that would keep the summary from being
+// delivered, suitable for showing to the user.
+//
+// Only the fields used by the selected delivery method are checked. The
+// favorites list may be empty (the summary then says so), since favorites
+// can be removed after the summary was set up.
+func (d DailySummary) Check() error {
+	if _, _, err := ParseSummaryTime(d.Time); err != nil {
+		return err
+	}
+
+	switch d.Delivery {
+	case SummaryDeliveryFile:
+		if strings.TrimSpace(d.FilePath) == "" {
+			return errors.New("no file selected for the summary")
+		}
+	case SummaryDeliverySendmail, SummaryDeliverySMTP:
+		if !IsValidContactEmail(d.EmailTo) {
+			return fmt.Errorf("%q is not an email address", d.EmailTo)
+		}
+		if d.Delivery == SummaryDeliverySendmail && d.SendmailPath == "" {
+			return errors.New("no sendmail program set")
+		}
+		if d.Delivery == SummaryDeliverySMTP {
+			host, port, err := net.SplitHostPort(d.SMTPServer)
+			if err != nil || host == "" {
+				return fmt.Errorf("SMTP server %q must be host:port", d.SMTPServer)
+			}
+			if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+				return fmt.Errorf("invalid SMTP port %q", port)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown delivery method %q", d.Delivery)
+	}
+	return nil
+}
+
+// validate repairs a loaded configuration: missing or unparseable values
+// get their defaults, favorite IDs that no longer exist are dropped, and
+// the summary is turned off if it still couldn't be delivered (see Check).
+func (d *DailySummary) validate(favorites []Favorite) {
+	defaults := DefaultDailySummary()
+	d.Time = strings.TrimSpace(d.Time)
+	if _, _, err := ParseSummaryTime(d.Time); err != nil {
+		d.Time = defaults.Time
+	}
+	if d.Delivery != SummaryDeliverySendmail && d.Delivery != SummaryDeliverySMTP {
+		d.Delivery = SummaryDeliveryFile
+	}
+	d.FilePath = strings.TrimSpace(d.FilePath)
+	d.EmailTo = strings.TrimSpace(d.EmailTo)
+	if d.SendmailPath = strings.TrimSpace(d.SendmailPath); d.SendmailPath == "" {
+		d.SendmailPath = defaults.SendmailPath
+	}
+	if d.SMTPServer = strings.TrimSpace(d.SMTPServer); d.SMTPServer == "" {
+		d.SMTPServer = defaults.SMTPServer
+	}
+
+	d.Favorites = slices.DeleteFunc(slices.Clone(d.Favorites), func(id string) bool {
+		return !slices.ContainsFunc(favorites, func(f Favorite) bool { return f.ID == id })
+	})
+
+	if d.Enabled && d.Check() != nil {
+		d.Enabled = false
+	}
+}
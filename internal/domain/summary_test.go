@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDailySummaryCheck(t *testing.T) {
+	valid := DefaultDailySummary()
+	valid.FilePath = "/tmp/golden-hour.txt"
+	with := func(change func(*DailySummary)) DailySummary {
+		d := valid
+		change(&d)
+		return d
+	}
+
+	tests := []struct {
+		name    string
+		summary DailySummary
+		wantErr bool
+	}{
+		{name: "file delivery", summary: valid},
+		{name: "no file", summary: with(func(d *DailySummary) { d.FilePath = " " }), wantErr: true},
+		{name: "bad time", summary: with(func(d *DailySummary) { d.Time = "25:00" }), wantErr: true},
+		{name: "unknown delivery", summary: with(func(d *DailySummary) { d.Delivery = "pigeon" }), wantErr: true},
+		{
+			name: "sendmail delivery",
+			summary: with(func(d *DailySummary) {
+				d.Delivery, d.EmailTo = SummaryDeliverySendmail, "me@example.com"
+			}),
+		},
+		{
+			name: "sendmail without address",
+			summary: with(func(d *DailySummary) {
+				d.Delivery = SummaryDeliverySendmail
+			}),
+			wantErr: true,
+		},
+		{
+			name: "SMTP delivery",
+			summary: with(func(d *DailySummary) {
+				d.Delivery, d.EmailTo, d.SMTPServer = SummaryDeliverySMTP, "me@example.com", "mail.lan:587"
+			}),
+		},
+		{
+			name: "SMTP server without port",
+			summary: with(func(d *DailySummary) {
+				d.Delivery, d.EmailTo, d.SMTPServer = SummaryDeliverySMTP, "me@example.com", "mail.lan"
+			}),
+			wantErr: true,
+		},
+		{
+			name: "SMTP port out of range",
+			summary: with(func(d *DailySummary) {
+				d.Delivery, d.EmailTo, d.SMTPServer = SummaryDeliverySMTP, "me@example.com", "mail.lan:70000"
+			}),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.summary.Check(); (err != nil) != tt.wantErr {
+				t.Errorf("Check() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateDailySummary(t *testing.T) {
+	favorites := []Favorite{{ID: "a"}, {ID: "b"}}
+	d := DailySummary{
+		Enabled:   true,
+		Time:      " 7:5 ",
+		Favorites: []string{"b", "gone", "a"},
+		Delivery:  "",
+	}
+	d.validate(favorites)
+
+	if d.Time != DefaultSummaryTime {
+		t.Errorf("Time = %q, want default", d.Time)
+	}
+	if d.Delivery != SummaryDeliveryFile || d.SendmailPath != DefaultSendmailPath || d.SMTPServer != DefaultSMTPServer {
+		t.Errorf("defaults not filled in: %+v", d)
+	}
+	if want := []string{"b", "a"}; !slices.Equal(d.Favorites, want) {
+		t.Errorf("Favorites = %v, want %v", d.Favorites, want)
+	}
+	if d.Enabled {
+		t.Error("summary without a file stayed enabled")
+	}
+}
+
+func TestFavorites(t *testing.T) {
+	paris := Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris"}
+	favorites := validateFavorites([]Favorite{
+		{ID: "paris", Location: paris},
+		{ID: "paris", Location: Location{Latitude: 1, Longitude: 1}},
+		{ID: "", Location: paris},
+		{ID: "nowhere", Location: Location{Latitude: 95}},
+	})
+	if len(favorites) != 1 || favorites[0].ID != "paris" {
+		t.Fatalf("validateFavorites() = %+v, want only paris", favorites)
+	}
+
+	near := Location{Latitude: paris.Latitude + 4e-6, Longitude: paris.Longitude - 4e-6}
+	if f, ok := FavoriteAt(favorites, near); !ok || f.ID != "paris" {
+		t.Errorf("FavoriteAt(near) = %+v, %v", f, ok)
+	}
+	far := Location{Latitude: paris.Latitude + 1e-4, Longitude: paris.Longitude}
+	if _, ok := FavoriteAt(favorites, far); ok {
+		t.Error("FavoriteAt(far) matched")
+	}
+
+	if a, b := NewFavoriteID(), NewFavoriteID(); len(a) != 16 || a == b {
+		t.Errorf("NewFavoriteID() = %q, %q", a, b)
+	}
+}
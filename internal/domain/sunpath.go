@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// =============================================================================
+// Sun Path
+// =============================================================================
+
+// SunPathPoint is the sun's position at one moment of its apparent path
+// across the sky.
+//
+// A series of points sampled through the evening golden hour is drawn on
+// the map as a sector from the observer, showing which segment of the
+// horizon the low sun sweeps before it sets. A series through the whole
+// day backs the time slider (see InterpolateSunPosition).
+type SunPathPoint struct {
+	// Time is the moment of the sample in the location's timezone.
+	Time time.Time
+
+	// Azimuth is the sun's compass direction, in degrees clockwise from
+	// true north.
+	Azimuth float64
+
+	// Elevation is the sun's angle above the horizon, in degrees.
+	Elevation float64
+}
+
+// InterpolateSunPosition estimates the sun's position at t from a series
+// of positions (see solar.PositionSeries), without calculating it anew.
+//
+// The position is interpolated linearly between the samples before and
+// after t; the azimuth takes the shorter way around north. With samples a
+// few minutes apart the error is a small fraction of a degree, which
+// keeps dragging a time slider smooth.
+//
+// Parameters:
+//   - series: Positions in chronological order
+//   - t: The moment to estimate; times outside the series take its first
+//     or last position
+//
+// Returns the position with Time set to t (clamped to the series), and
+// false if the series is empty.
+func InterpolateSunPosition(series []SunPathPoint, t time.Time) (SunPathPoint, bool) {
+	if len(series) == 0 {
+		return SunPathPoint{}, false
+	}
+	// Index of the first sample after t
+	next := sort.Search(len(series), func(i int) bool { return series[i].Time.After(t) })
+	switch {
+	case next == 0:
+		return series[0], true
+	case next == len(series):
+		return series[len(series)-1], true
+	}
+
+	before, after := series[next-1], series[next]
+	span := after.Time.Sub(before.Time)
+	f := 0.0
+	if span > 0 {
+		f = float64(t.Sub(before.Time)) / float64(span)
+	}
+	azimuth := before.Azimuth + f*AngleDifference(after.Azimuth, before.Azimuth)
+	return SunPathPoint{
+		Time:      t,
+		Azimuth:   NormalizeDegrees(azimuth),
+		Elevation: before.Elevation + f*(after.Elevation-before.Elevation),
+	}, true
+}
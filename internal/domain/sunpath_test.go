@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestInterpolateSunPosition(t *testing.T) {
+	base := time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC)
+	series := []SunPathPoint{
+		{Time: base, Azimuth: 350, Elevation: 10},
+		{Time: base.Add(10 * time.Minute), Azimuth: 10, Elevation: 20},
+	}
+
+	tests := []struct {
+		name    string
+		t       time.Time
+		azimuth float64
+		elev    float64
+	}{
+		{"halfway across north", base.Add(5 * time.Minute), 0, 15},
+		{"quarter", base.Add(150 * time.Second), 355, 12.5},
+		{"before the series", base.Add(-time.Hour), 350, 10},
+		{"after the series", base.Add(time.Hour), 10, 20},
+	}
+	for _, tt := range tests {
+		got, ok := InterpolateSunPosition(series, tt.t)
+		if !ok || math.Abs(AngleDifference(got.Azimuth, tt.azimuth)) > 1e-9 || math.Abs(got.Elevation-tt.elev) > 1e-9 {
+			t.Errorf("%s: got %+v, %v; want azimuth %v, elevation %v", tt.name, got, ok, tt.azimuth, tt.elev)
+		}
+	}
+
+	if _, ok := InterpolateSunPosition(nil, base); ok {
+		t.Error("empty series reported a position")
+	}
+}
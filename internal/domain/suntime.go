@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"sort"
 	"time"
 )
 
@@ -75,7 +76,12 @@ func (tr TimeRange) IsValid() bool {
 // This is used in the UI to show photographers how long each golden/blue
 // hour period lasts, helping them plan their shoots.
 func (tr TimeRange) FormatDuration() string {
-	d := tr.Duration()
+	return FormatDuration(tr.Duration())
+}
+
+// FormatDuration formats a duration like TimeRange.FormatDuration: "45 min",
+// "2h" or "1h 30m".
+func FormatDuration(d time.Duration) string {
 	minutes := int(d.Minutes())
 
 	// Short durations: show only minutes
@@ -160,6 +166,10 @@ type SunTimes struct {
 	// Starts at blue start angle (default -4°) and ends at blue end angle
 	// (default -8°). Sky transitions from orange to deep blue.
 	BlueEvening TimeRange `json:"blue_evening"`
+
+	// Custom holds the times of the user's custom events (see CustomEvent),
+	// in the order of Settings.CustomEvents. Empty without custom events.
+	Custom []CustomTime `json:"custom,omitempty"`
 }
 
 // HasValidGoldenHour returns true if at least one golden hour period is available.
@@ -184,6 +194,78 @@ func (st SunTimes) HasValidBlueHour() bool {
 	return st.BlueMorning.IsValid() || st.BlueEvening.IsValid()
 }
 
+// =============================================================================
+// Shooting Window
+// =============================================================================
+
+// ShootingWindow returns the total time of photographically useful light on
+// the day: morning blue and golden hour plus evening golden and blue hour.
+//
+// Overlapping periods are counted once. They occur at high latitudes, where
+// e.g. a sun that never climbs above the golden hour elevation makes the
+// morning and evening golden hours the same stretch of the day.
+func (st SunTimes) ShootingWindow() time.Duration {
+	return totalDuration(st.BlueMorning, st.GoldenMorning, st.GoldenEvening, st.BlueEvening)
+}
+
+// GoldenHourTotal returns the total golden hour of the day, morning plus
+// evening, counting an overlap (see ShootingWindow) once. Zero if the day
+// has no golden hour.
+func (st SunTimes) GoldenHourTotal() time.Duration {
+	return totalDuration(st.GoldenMorning, st.GoldenEvening)
+}
+
+// totalDuration adds up the valid ranges, counting overlapping stretches
+// once.
+func totalDuration(ranges ...TimeRange) time.Duration {
+	var periods []TimeRange
+	for _, tr := range ranges {
+		if tr.IsValid() {
+			periods = append(periods, tr)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].Start.Before(periods[j].Start)
+	})
+
+	// Merge overlapping periods while adding them up
+	var total time.Duration
+	var current TimeRange
+	for _, tr := range periods {
+		if current.IsValid() && !tr.Start.After(current.End) {
+			if tr.End.After(current.End) {
+				current.End = tr.End
+			}
+			continue
+		}
+		total += current.Duration()
+		current = tr
+	}
+	return total + current.Duration()
+}
+
+// ShootingFraction returns ShootingWindow as a fraction (0 to 1) of the
+// calendar day, which is 23 or 25 hours long on daylight saving changes.
+func (st SunTimes) ShootingFraction() float64 {
+	day := 24 * time.Hour
+	if !st.Date.IsZero() {
+		y, m, d := st.Date.Date()
+		midnight := time.Date(y, m, d, 0, 0, 0, 0, st.Date.Location())
+		day = midnight.AddDate(0, 0, 1).Sub(midnight)
+	}
+	return min(float64(st.ShootingWindow())/float64(day), 1)
+}
+
+// FormatShootingWindow returns the shooting window for display, e.g.
+// "2h 14m (9% of the day)", or "none" on days without golden or blue hour.
+func (st SunTimes) FormatShootingWindow() string {
+	window := st.ShootingWindow()
+	if window <= 0 {
+		return "none"
+	}
+	return fmt.Sprintf("%s (%.0f%% of the day)", FormatDuration(window), st.ShootingFraction()*100)
+}
+
 // =============================================================================
 // Time Formatting
 // =============================================================================
@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestShootingWindow(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip("no time zone data")
+	}
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.March, day, hour, min, 0, 0, paris)
+	}
+	st := SunTimes{
+		Date:          at(28, 0, 0),
+		BlueMorning:   TimeRange{Start: at(28, 6, 30), End: at(28, 6, 50)},
+		GoldenMorning: TimeRange{Start: at(28, 7, 0), End: at(28, 7, 40)},
+		GoldenEvening: TimeRange{Start: at(28, 19, 30), End: at(28, 20, 10)},
+		BlueEvening:   TimeRange{Start: at(28, 20, 20), End: at(28, 20, 40)},
+	}
+	if got, want := st.ShootingWindow(), 2*time.Hour; got != want {
+		t.Errorf("ShootingWindow() = %v, want %v", got, want)
+	}
+	if got, want := st.ShootingFraction(), 2.0/24; math.Abs(got-want) > 1e-9 {
+		t.Errorf("ShootingFraction() = %v, want %v", got, want)
+	}
+	if got, want := st.FormatShootingWindow(), "2h (8% of the day)"; got != want {
+		t.Errorf("FormatShootingWindow() = %q, want %q", got, want)
+	}
+
+	// March 29 has 23 hours in Paris
+	dst := st
+	dst.Date = at(29, 0, 0)
+	if got, want := dst.ShootingFraction(), 2.0/23; math.Abs(got-want) > 1e-9 {
+		t.Errorf("ShootingFraction() on DST day = %v, want %v", got, want)
+	}
+
+	// Overlapping periods (sun below the golden hour elevation all day)
+	// count once; missing periods count nothing
+	polar := SunTimes{
+		Date:          at(28, 0, 0),
+		GoldenMorning: TimeRange{Start: at(28, 10, 0), End: at(28, 14, 0)},
+		GoldenEvening: TimeRange{Start: at(28, 10, 0), End: at(28, 14, 0)},
+		BlueEvening:   TimeRange{Start: at(28, 13, 30), End: at(28, 15, 0)},
+	}
+	if got, want := polar.ShootingWindow(), 5*time.Hour; got != want {
+		t.Errorf("ShootingWindow() with overlaps = %v, want %v", got, want)
+	}
+	if got, want := st.GoldenHourTotal(), 80*time.Minute; got != want {
+		t.Errorf("GoldenHourTotal() = %v, want %v", got, want)
+	}
+	if got, want := polar.GoldenHourTotal(), 4*time.Hour; got != want {
+		t.Errorf("GoldenHourTotal() with overlaps = %v, want %v", got, want)
+	}
+
+	if got := (SunTimes{}).FormatShootingWindow(); got != "none" {
+		t.Errorf("FormatShootingWindow() without periods = %q, want \"none\"", got)
+	}
+}
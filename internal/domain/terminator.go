@@ -0,0 +1,48 @@
+package domain
+
+// =============================================================================
+// Day/Night Terminator
+// =============================================================================
+
+// Sun elevation thresholds of the terminator bands, in degrees.
+//
+// These are the standard astronomical definitions and are deliberately
+// independent of the user's golden/blue hour settings: the terminator
+// overlay shows global lighting conditions, not personal shooting windows.
+const (
+	// HorizonElevation is the apparent sunrise/sunset elevation (includes
+	// atmospheric refraction and the sun's radius).
+	HorizonElevation = -0.833
+
+	// CivilTwilightElevation is the end of civil twilight.
+	CivilTwilightElevation = -6.0
+
+	// NauticalTwilightElevation is the end of nautical twilight.
+	NauticalTwilightElevation = -12.0
+
+	// AstronomicalTwilightElevation is the end of astronomical twilight
+	// (full night).
+	AstronomicalTwilightElevation = -18.0
+)
+
+// TwilightBand is the area of the Earth where the sun is below an elevation.
+//
+// The terminator overlay stacks several bands, one per threshold: the band
+// for HorizonElevation covers the whole night side, the band for
+// AstronomicalTwilightElevation only the region in full darkness. Drawing
+// them with low opacity on top of each other gives progressively darker
+// shading from twilight to night.
+//
+// Boundary is a closed polygon in drawing order (the first point is not
+// repeated at the end). Longitudes are continuous rather than wrapped to
+// [-180, 180), so a region crossing the antimeridian may extend past ±180°;
+// map code should draw copies shifted by ±360° to cover every world copy.
+// Regions containing a pole are closed along the pole's latitude (±90°).
+type TwilightBand struct {
+	// Elevation is the threshold; the band covers points where the sun is
+	// below this elevation.
+	Elevation float64
+
+	// Boundary is the outline of the band (only coordinates are set).
+	Boundary []Location
+}
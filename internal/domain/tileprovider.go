@@ -0,0 +1,186 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+)
+
+// =============================================================================
+// Tile Providers
+// =============================================================================
+
+// ErrPrefetchForbidden is returned by TileProvider.CheckPrefetch for
+// providers whose usage policy doesn't allow downloading tiles in bulk
+// (e.g., to keep an area for offline use).
+var ErrPrefetchForbidden = errors.New("bulk downloading is not allowed by the tile provider")
+
+// TileProvider describes a map tile service: what the map must credit and
+// how its tiles may be used.
+//
+// Tile servers are matched to a provider by their host (see
+// TileServer.Provider). Servers of unknown providers, such as a local
+// tileserver-gl, get a generic entry naming their host.
+type TileProvider struct {
+	// ID identifies the provider, e.g., "osm"; empty for unknown servers.
+	ID string
+
+	// Name is the provider's name for display, e.g., "OpenStreetMap".
+	Name string
+
+	// Hosts are the domains the provider's tiles are served from;
+	// subdomains match too ("tile.example.com" matches "a.tile.example.com").
+	Hosts []string
+
+	// Attribution is the credit shown in the map's corner, as HTML.
+	Attribution string
+
+	// MaxZoom is the highest zoom level the provider serves tiles for.
+	MaxZoom int
+
+	// AllowsPrefetch is true if the usage policy permits downloading many
+	// tiles ahead of viewing them (see CheckPrefetch).
+	AllowsPrefetch bool
+
+	// PolicyURL links the provider's tile usage policy or terms; empty for
+	// unknown servers.
+	PolicyURL string
+}
+
+// defaultTileMaxZoom is the highest zoom level of unknown tile servers,
+// which is also the map's own limit.
+const defaultTileMaxZoom = 19
+
+// osmAttribution credits OpenStreetMap, whose data almost all providers'
+// tiles are drawn from.
+const osmAttribution = `© <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a> contributors`
+
+// TileProviders are the known tile providers; the first entry is the
+// default OpenStreetMap server.
+//
+// None of the public services allows bulk downloading: their usage
+// policies ask apps to load only the tiles a user actually looks at.
+var TileProviders = []TileProvider{
+	{
+		ID:          "osm",
+		Name:        "OpenStreetMap",
+		Hosts:       []string{"tile.openstreetmap.org"},
+		Attribution: osmAttribution,
+		MaxZoom:     19,
+		PolicyURL:   "https://operations.osmfoundation.org/policies/tiles/",
+	},
+	{
+		ID:    "opentopomap",
+		Name:  "OpenTopoMap",
+		Hosts: []string{"tile.opentopomap.org"},
+		Attribution: osmAttribution + `, SRTM | Map style: © <a href="https://opentopomap.org">OpenTopoMap</a> ` +
+			`(<a href="https://creativecommons.org/licenses/by-sa/3.0/">CC-BY-SA</a>)`,
+		MaxZoom:   17,
+		PolicyURL: "https://opentopomap.org/about",
+	},
+	{
+		ID:          "carto",
+		Name:        "CARTO",
+		Hosts:       []string{"basemaps.cartocdn.com"},
+		Attribution: osmAttribution + ` © <a href="https://carto.com/attributions">CARTO</a>`,
+		MaxZoom:     20,
+		PolicyURL:   "https://github.com/CartoDB/basemap-styles#1-web-raster-basemaps",
+	},
+	{
+		ID:    "esri",
+		Name:  "Esri World Imagery",
+		Hosts: []string{"server.arcgisonline.com", "services.arcgisonline.com"},
+		Attribution: `Tiles © <a href="https://www.esri.com">Esri</a> — Source: Esri, Maxar, Earthstar Geographics, ` +
+			`and the GIS User Community`,
+		MaxZoom:   19,
+		PolicyURL: "https://www.esri.com/en-us/legal/terms/full-master-agreement",
+	},
+	{
+		ID:          "thunderforest",
+		Name:        "Thunderforest",
+		Hosts:       []string{"tile.thunderforest.com"},
+		Attribution: `Maps © <a href="https://www.thunderforest.com">Thunderforest</a>, Data ` + osmAttribution,
+		MaxZoom:     22,
+		PolicyURL:   "https://www.thunderforest.com/terms/",
+	},
+	{
+		ID:    "stadia",
+		Name:  "Stadia Maps",
+		Hosts: []string{"tiles.stadiamaps.com"},
+		Attribution: `© <a href="https://stadiamaps.com/">Stadia Maps</a> © <a href="https://openmaptiles.org/">OpenMapTiles</a> ` +
+			osmAttribution,
+		MaxZoom:   20,
+		PolicyURL: "https://stadiamaps.com/terms-of-service/",
+	},
+	{
+		ID:          "maptiler",
+		Name:        "MapTiler",
+		Hosts:       []string{"api.maptiler.com"},
+		Attribution: `© <a href="https://www.maptiler.com/copyright/">MapTiler</a> ` + osmAttribution,
+		MaxZoom:     20,
+		PolicyURL:   "https://www.maptiler.com/terms/",
+	},
+}
+
+// DefaultTileProvider returns the provider of the default tiles
+// (OpenStreetMap).
+func DefaultTileProvider() TileProvider {
+	return TileProviders[0]
+}
+
+// Provider returns the provider serving the tile server's tiles.
+//
+// The default server is OpenStreetMap. Other servers are matched by the
+// host of their URL; an unknown host gets a provider without ID whose
+// attribution names the host, since the app can't know whose data the
+// tiles show. Such servers (usually the user's own) allow prefetching.
+func (t TileServer) Provider() TileProvider {
+	if t.IsDefault() {
+		return DefaultTileProvider()
+	}
+	host := tileHost(t.URL)
+	for _, p := range TileProviders {
+		for _, h := range p.Hosts {
+			if host == h || strings.HasSuffix(host, "."+h) {
+				return p
+			}
+		}
+	}
+	return TileProvider{
+		Name:           host,
+		Attribution:    "Map tiles: " + html.EscapeString(host),
+		MaxZoom:        defaultTileMaxZoom,
+		AllowsPrefetch: true,
+	}
+}
+
+// tileHost returns the lower-case host name of a tile URL template; the
+// template itself if it can't be parsed.
+func tileHost(template string) string {
+	sample := tilePlaceholder.ReplaceAllStringFunc(template, func(match string) string {
+		return tilePlaceholderSamples[match[1:len(match)-1]]
+	})
+	u, err := url.Parse(sample)
+	if err != nil || u.Hostname() == "" {
+		return template
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// CheckPrefetch checks whether tiles may be downloaded in bulk, ahead of
+// viewing them.
+//
+// Returns an error wrapping ErrPrefetchForbidden (with the provider's name
+// and policy, suitable for showing to the user) if the provider doesn't
+// allow it; nil otherwise.
+func (p TileProvider) CheckPrefetch() error {
+	if p.AllowsPrefetch {
+		return nil
+	}
+	if p.PolicyURL != "" {
+		return fmt.Errorf("%s: %w (see %s)", p.Name, ErrPrefetchForbidden, p.PolicyURL)
+	}
+	return fmt.Errorf("%s: %w", p.Name, ErrPrefetchForbidden)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTileServerProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		server   TileServer
+		wantID   string
+		prefetch bool
+	}{
+		{name: "default", server: TileServer{}, wantID: "osm"},
+		{name: "OpenStreetMap URL", server: TileServer{URL: "https://tile.openstreetmap.org/{z}/{x}/{y}.png"}, wantID: "osm"},
+		{name: "subdomain", server: TileServer{URL: "https://{s}.tile.opentopomap.org/{z}/{x}/{y}.png"}, wantID: "opentopomap"},
+		{name: "upper case host", server: TileServer{URL: "https://API.MapTiler.com/maps/streets/{z}/{x}/{y}.png?key={apikey}", APIKey: "k"}, wantID: "maptiler"},
+		{name: "local server", server: TileServer{URL: "http://localhost:8080/styles/basic/{z}/{x}/{y}.png"}, prefetch: true},
+		{name: "look-alike host", server: TileServer{URL: "https://nottile.openstreetmap.org.example.com/{z}/{x}/{y}.png"}, prefetch: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.server.Provider()
+			if p.ID != tt.wantID {
+				t.Errorf("Provider().ID = %q, want %q", p.ID, tt.wantID)
+			}
+			if err := p.CheckPrefetch(); (err == nil) != tt.prefetch {
+				t.Errorf("CheckPrefetch() = %v, want allowed: %v", err, tt.prefetch)
+			} else if err != nil && !errors.Is(err, ErrPrefetchForbidden) {
+				t.Errorf("CheckPrefetch() = %v, want ErrPrefetchForbidden", err)
+			}
+			if p.Attribution == "" || p.MaxZoom == 0 {
+				t.Errorf("Provider() = %+v, want an attribution and max zoom", p)
+			}
+		})
+	}
+}
+
+func TestUnknownProviderAttributionEscaped(t *testing.T) {
+	p := TileServer{URL: "http://<b>/{z}/{x}/{y}.png"}.Provider()
+	if strings.Contains(p.Attribution, "<b>") {
+		t.Errorf("Attribution = %q, want the host escaped", p.Attribution)
+	}
+}
@@ -0,0 +1,213 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// =============================================================================
+// Tile Server
+// =============================================================================
+
+// DefaultTileSubdomains are the subdomains used for the {s} placeholder when
+// a tile server doesn't list its own (the common a/b/c convention).
+const DefaultTileSubdomains = "abc"
+
+// DefaultTileURL is the tile URL template of the public OpenStreetMap server,
+// used when TileServer.URL is empty.
+const DefaultTileURL = "https://tile.openstreetmap.org/{z}/{x}/{y}.png"
+
+// tilePlaceholder matches a {name} placeholder in a tile URL template.
+var tilePlaceholder = regexp.MustCompile(`\{([^{}]*)\}`)
+
+// tilePlaceholderSamples maps the placeholders a tile URL template may use to
+// sample values, which turn a template into a URL that can be parsed:
+//
+//	{z} {x} {y}  Zoom level and tile column/row (required)
+//	{-y}         Tile row counted from the bottom (TMS servers), instead of {y}
+//	{s}          Subdomain, cycled through TileServer.Subdomains
+//	{r}          "@2x" on high-DPI screens, for servers with retina tiles
+//	{apikey}     TileServer.APIKey
+var tilePlaceholderSamples = map[string]string{
+	"z":      "0",
+	"x":      "0",
+	"y":      "0",
+	"-y":     "0",
+	"s":      "a",
+	"r":      "",
+	"apikey": "key",
+}
+
+// TileServer configures where the map's base layer tiles come from.
+//
+// The zero value means the public OpenStreetMap tile server. Photographers
+// working offline or in a company network can point the map at their own
+// server instead, such as tileserver-gl on localhost or a commercial tile
+// provider that needs an API key, e.g.:
+//
+//	URL:        https://{s}.tiles.example.com/{z}/{x}/{y}.png?key={apikey}
+//	Subdomains: abc
+//	APIKey:     0123456789abcdef
+//
+// The URL template uses Leaflet's placeholder syntax (see
+// tilePlaceholderSamples for the accepted placeholders).
+type TileServer struct {
+	// URL is the tile URL template; empty for OpenStreetMap.
+	URL string `json:"url,omitempty"`
+
+	// Subdomains lists the values for {s}, one character each (e.g.,
+	// "abc"); empty for DefaultTileSubdomains.
+	Subdomains string `json:"subdomains,omitempty"`
+
+	// APIKey is the value for {apikey}; empty if the server needs none.
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// IsDefault reports whether the public OpenStreetMap tiles are used.
+func (t TileServer) IsDefault() bool {
+	return t.URL == ""
+}
+
+// SubdomainList returns the subdomains to use for {s}, with the default
+// applied.
+func (t TileServer) SubdomainList() string {
+	if t.Subdomains == "" {
+		return DefaultTileSubdomains
+	}
+	return t.Subdomains
+}
+
+// Validate checks that the tile server can be used by the map.
+//
+// The zero value (OpenStreetMap) is always valid. Otherwise:
+//   - URL must be an http or https URL once placeholders are filled in
+//   - URL must contain {z}, {x} and {y} (or {-y}), and no other
+//     placeholders than those listed in tilePlaceholderSamples
+//   - Subdomains may only contain letters and digits
+//   - APIKey must be set if URL uses {apikey}, and may not contain spaces
+//     or characters that would break the URL
+//
+// Returns an error describing the first problem found, suitable for
+// showing to the user.
+func (t TileServer) Validate() error {
+	if t.IsDefault() {
+		return nil
+	}
+
+	// Check placeholders and fill them with sample values
+	used := make(map[string]bool)
+	var unknown []string
+	sample := tilePlaceholder.ReplaceAllStringFunc(t.URL, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := tilePlaceholderSamples[name]
+		if !ok {
+			unknown = append(unknown, match)
+		}
+		used[name] = true
+		return value
+	})
+	if len(unknown) > 0 {
+		return fmt.Errorf("unknown placeholder %s in tile URL", unknown[0])
+	}
+	if strings.ContainsAny(sample, "{}") {
+		return errors.New("unmatched brace in tile URL")
+	}
+	if !used["z"] || !used["x"] || !(used["y"] || used["-y"]) {
+		return errors.New("tile URL must contain {z}, {x} and {y}")
+	}
+
+	u, err := url.Parse(sample)
+	if err != nil {
+		return fmt.Errorf("invalid tile URL: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return errors.New("tile URL must start with http:// or https://")
+	}
+
+	for _, c := range t.Subdomains {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return errors.New("subdomains may only contain letters and digits")
+		}
+	}
+
+	if used["apikey"] && t.APIKey == "" {
+		return errors.New("tile URL uses {apikey} but no API key is set")
+	}
+	if t.APIKey != "" && url.QueryEscape(t.APIKey) != t.APIKey {
+		return errors.New("API key may only contain letters, digits and - _ . ~")
+	}
+	return nil
+}
+
+// Template returns the tile URL template, with DefaultTileURL for the
+// default server.
+func (t TileServer) Template() string {
+	if t.IsDefault() {
+		return DefaultTileURL
+	}
+	return t.URL
+}
+
+// TileURL fills in the URL template for one tile, as the map would.
+//
+// {s} is cycled through the subdomains by the tile's position, like
+// Leaflet does, so neighboring tiles are spread over the servers.
+//
+// Parameters:
+//   - z, x, y: Zoom level, column and row (rows counted from the top)
+//   - retina: Request the server's "@2x" tiles (where the URL has {r})
+func (t TileServer) TileURL(z, x, y int, retina bool) string {
+	return tilePlaceholder.ReplaceAllStringFunc(t.Template(), func(match string) string {
+		switch match[1 : len(match)-1] {
+		case "z":
+			return strconv.Itoa(z)
+		case "x":
+			return strconv.Itoa(x)
+		case "y":
+			return strconv.Itoa(y)
+		case "-y":
+			return strconv.Itoa(1<<z - 1 - y)
+		case "s":
+			subdomains := t.SubdomainList()
+			return string(subdomains[(x+y)%len(subdomains)])
+		case "r":
+			if retina {
+				return "@2x"
+			}
+			return ""
+		case "apikey":
+			return t.APIKey
+		}
+		return match
+	})
+}
+
+// CacheKey identifies the server's tiles in the tile cache: servers with
+// the same URL template share the key, other servers never do.
+func (t TileServer) CacheKey() string {
+	sum := sha256.Sum256([]byte(t.Template()))
+	return hex.EncodeToString(sum[:8])
+}
+
+// IsLocal reports whether the server runs on this machine or the local
+// network (localhost, a loopback, private or link-local address), such as
+// a tileserver-gl for offline use. Host names other than localhost are not
+// looked up, and count as remote.
+func (t TileServer) IsLocal() bool {
+	host := tileHost(t.Template())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	return addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast()
+}
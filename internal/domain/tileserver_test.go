@@ -0,0 +1,108 @@
+package domain
+
+import "testing"
+
+func TestTileServerValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  TileServer
+		wantErr bool
+	}{
+		{name: "default", server: TileServer{}},
+		{name: "local tileserver-gl", server: TileServer{URL: "http://localhost:8080/styles/basic/{z}/{x}/{y}.png"}},
+		{name: "subdomains and retina", server: TileServer{URL: "https://{s}.tiles.example.com/{z}/{x}/{y}{r}.png", Subdomains: "abcd"}},
+		{name: "TMS row order", server: TileServer{URL: "https://tiles.example.com/{z}/{x}/{-y}.png"}},
+		{name: "API key", server: TileServer{URL: "https://tiles.example.com/{z}/{x}/{y}.png?key={apikey}", APIKey: "0123-abcd_EF"}},
+		{name: "missing coordinates", server: TileServer{URL: "https://tiles.example.com/{z}/{x}.png"}, wantErr: true},
+		{name: "unknown placeholder", server: TileServer{URL: "https://tiles.example.com/{z}/{x}/{y}.png?{token}"}, wantErr: true},
+		{name: "unmatched brace", server: TileServer{URL: "https://tiles.example.com/{z}/{x}/{y.png"}, wantErr: true},
+		{name: "not http", server: TileServer{URL: "file:///tiles/{z}/{x}/{y}.png"}, wantErr: true},
+		{name: "no scheme", server: TileServer{URL: "tiles.example.com/{z}/{x}/{y}.png"}, wantErr: true},
+		{name: "bad subdomains", server: TileServer{URL: "https://{s}.example.com/{z}/{x}/{y}.png", Subdomains: "a,b"}, wantErr: true},
+		{name: "API key missing", server: TileServer{URL: "https://tiles.example.com/{z}/{x}/{y}.png?key={apikey}"}, wantErr: true},
+		{name: "API key with space", server: TileServer{URL: "https://tiles.example.com/{z}/{x}/{y}.png?key={apikey}", APIKey: "ab cd"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateTileServer(t *testing.T) {
+	s := DefaultSettings()
+	s.TileServer = TileServer{URL: " https://{s}.example.com/{z}/{x}/{y}.png ", Subdomains: " ab "}
+	s.Validate()
+	want := TileServer{URL: "https://{s}.example.com/{z}/{x}/{y}.png", Subdomains: "ab"}
+	if s.TileServer != want {
+		t.Errorf("TileServer = %+v, want %+v", s.TileServer, want)
+	}
+	if got := s.TileServer.SubdomainList(); got != "ab" {
+		t.Errorf("SubdomainList() = %q, want %q", got, "ab")
+	}
+
+	s.TileServer = TileServer{URL: "https://example.com/tiles.png"}
+	s.Validate()
+	if !s.TileServer.IsDefault() {
+		t.Errorf("invalid TileServer kept: %+v", s.TileServer)
+	}
+	if got := s.TileServer.SubdomainList(); got != DefaultTileSubdomains {
+		t.Errorf("SubdomainList() = %q, want %q", got, DefaultTileSubdomains)
+	}
+}
+
+func TestTileServerTileURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		server TileServer
+		retina bool
+		want   string
+	}{
+		{name: "default", server: TileServer{}, want: "https://tile.openstreetmap.org/5/17/11.png"},
+		{
+			name:   "subdomains, retina and key",
+			server: TileServer{URL: "https://{s}.example.com/{z}/{x}/{y}{r}.png?key={apikey}", Subdomains: "ab", APIKey: "k"},
+			retina: true,
+			want:   "https://a.example.com/5/17/11@2x.png?key=k",
+		},
+		{name: "TMS row order", server: TileServer{URL: "http://localhost/{z}/{x}/{-y}.png"}, want: "http://localhost/5/17/20.png"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.server.TileURL(5, 17, 11, tt.retina); got != tt.want {
+				t.Errorf("TileURL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTileServerCacheKey(t *testing.T) {
+	osm := TileServer{}.CacheKey()
+	if got := (TileServer{URL: DefaultTileURL}).CacheKey(); got != osm {
+		t.Errorf("explicit OpenStreetMap URL key = %q, want the default's %q", got, osm)
+	}
+	if got := (TileServer{URL: "https://tiles.example.com/{z}/{x}/{y}.png"}).CacheKey(); got == osm {
+		t.Error("another server shares the OpenStreetMap cache key")
+	}
+}
+
+func TestTileServerIsLocal(t *testing.T) {
+	tests := map[string]bool{
+		"":                                          false,
+		"http://localhost:8080/{z}/{x}/{y}.png":     true,
+		"http://127.0.0.1/{z}/{x}/{y}.png":          true,
+		"http://192.168.1.20/{z}/{x}/{y}.png":       true,
+		"http://[::1]:8080/{z}/{x}/{y}.png":         true,
+		"https://tiles.example.com/{z}/{x}/{y}.png": false,
+		"http://203.0.113.5/{z}/{x}/{y}.png":        false,
+	}
+	for url, want := range tests {
+		if got := (TileServer{URL: url}).IsLocal(); got != want {
+			t.Errorf("IsLocal(%q) = %v, want %v", url, got, want)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// =============================================================================
+// Weather Forecast
+// =============================================================================
+
+// WeatherHour is the forecast weather of one hour at a place.
+type WeatherHour struct {
+	// Time is the start of the hour.
+	Time time.Time
+
+	// CloudCover is the part of the sky covered by clouds, in percent
+	// (0-100).
+	CloudCover int
+
+	// Precipitation is the rain, showers and snow expected in the hour,
+	// in millimeters.
+	Precipitation float64
+
+	// Visibility is how far one can see, in meters; 0 if the forecast
+	// doesn't include it.
+	Visibility float64
+}
+
+// WeatherForecast is an hourly weather forecast for a place, from a
+// weather service (see the weather package).
+//
+// It covers the selected date and the following night, or the days of the
+// week outlook, so the golden and blue hours can be rated by the weather
+// they're likely to have (see During and ShootProbability): cloud cover is
+// what most often spoils a golden hour plan.
+type WeatherForecast struct {
+	// Hours are the forecast hours in chronological order.
+	Hours []WeatherHour
+}
+
+// PeriodWeather is the forecast weather during a period, e.g., the evening
+// golden hour.
+type PeriodWeather struct {
+	// CloudCover is the mean cloud cover of the hours overlapping the
+	// period, in percent.
+	CloudCover int
+
+	// Precipitation is the heaviest hourly precipitation during the
+	// period, in millimeters.
+	Precipitation float64
+
+	// Visibility is the lowest visibility during the period, in meters;
+	// 0 if unknown.
+	Visibility float64
+}
+
+const (
+	// rainThreshold is the hourly precipitation (mm) from which a period
+	// counts as rainy; less is a trace that doesn't change the light.
+	rainThreshold = 0.1
+
+	// fogVisibility is the visibility (m) below which a period counts as
+	// foggy (the meteorological definition of fog).
+	fogVisibility = 1000
+)
+
+// Shoot probability levels, for color coding (see ShootProbability): a
+// chance of at least ShootLikely is shown green, at least ShootPossible
+// amber, and less red.
+const (
+	ShootLikely   = 70
+	ShootPossible = 40
+)
+
+// During returns the forecast weather during a time range.
+//
+// Parameters:
+//   - r: The period, e.g., SunTimes.GoldenEvening
+//
+// Returns the weather of the forecast hours overlapping r, and false if r
+// is invalid or the forecast has no hour overlapping it (e.g., a date
+// beyond the forecast).
+func (f WeatherForecast) During(r TimeRange) (PeriodWeather, bool) {
+	if !r.IsValid() {
+		return PeriodWeather{}, false
+	}
+	var w PeriodWeather
+	clouds, n := 0, 0
+	for _, h := range f.Hours {
+		if !h.Time.Before(r.End) || !h.Time.Add(time.Hour).After(r.Start) {
+			continue
+		}
+		clouds += h.CloudCover
+		n++
+		w.Precipitation = math.Max(w.Precipitation, h.Precipitation)
+		if h.Visibility > 0 && (w.Visibility == 0 || h.Visibility < w.Visibility) {
+			w.Visibility = h.Visibility
+		}
+	}
+	if n == 0 {
+		return PeriodWeather{}, false
+	}
+	w.CloudCover = int(math.Round(float64(clouds) / float64(n)))
+	return w, true
+}
+
+// Condition returns a one or two word description of the weather:
+// "Rain", "Fog", "Clear", "Partly cloudy", "Mostly cloudy" or "Overcast".
+//
+// Rain and fog come first, as they matter more to the light than the
+// clouds.
+func (w PeriodWeather) Condition() string {
+	switch {
+	case w.Precipitation >= rainThreshold:
+		return "Rain"
+	case w.Visibility > 0 && w.Visibility < fogVisibility:
+		return "Fog"
+	case w.CloudCover < 20:
+		return "Clear"
+	case w.CloudCover < 50:
+		return "Partly cloudy"
+	case w.CloudCover < 80:
+		return "Mostly cloudy"
+	}
+	return "Overcast"
+}
+
+// Icon returns an emoji for the weather, matching Condition (e.g., "🌤"
+// for partly cloudy).
+func (w PeriodWeather) Icon() string {
+	switch w.Condition() {
+	case "Rain":
+		return "🌧"
+	case "Fog":
+		return "🌫"
+	case "Clear":
+		return "☀"
+	case "Partly cloudy":
+		return "🌤"
+	case "Mostly cloudy":
+		return "⛅"
+	}
+	return "☁"
+}
+
+// ShootProbability estimates the chance, in percent, that a golden or blue
+// hour with this weather gives usable light, e.g., 70 for "70% chance of
+// a usable golden hour". The estimate is rounded to 5%.
+//
+// Clouds count against the light only when they cover most of the sky: a
+// partly cloudy sky is as good as a clear one (often better, as lit clouds
+// add color), while overcast hides the low sun:
+//
+//	chance
+//	 95% ┤━━━━━━━━━━━━━━━━━━━━━╮
+//	     │                      ╲
+//	     │                       ╲
+//	 10% ┤                        ╲━
+//	     └──────────────────────┬───┬─
+//	     0%                    60% 100%  cloud cover
+//
+// Rain cuts the chance to a third (a tenth from 1 mm/h), fog to half, and
+// haze (visibility under 5 km) by a fifth.
+func (w PeriodWeather) ShootProbability() int {
+	chance := 95.0
+	if w.CloudCover > 60 {
+		chance -= float64(w.CloudCover-60) * (95 - 10) / 40
+	}
+	switch {
+	case w.Precipitation >= 1:
+		chance *= 0.1
+	case w.Precipitation >= rainThreshold:
+		chance /= 3
+	}
+	switch {
+	case w.Visibility <= 0:
+	case w.Visibility < fogVisibility:
+		chance *= 0.5
+	case w.Visibility < 5000:
+		chance *= 0.8
+	}
+	return int(math.Round(math.Max(0, math.Min(100, chance))/5) * 5)
+}
+
+// Summary describes the weather in detail, for tooltips, e.g.,
+// "Partly cloudy: 35% clouds, no rain, visibility 24 km".
+func (w PeriodWeather) Summary() string {
+	rain := "no rain"
+	if w.Precipitation >= rainThreshold {
+		rain = fmt.Sprintf("%.1f mm/h rain", w.Precipitation)
+	}
+	text := fmt.Sprintf("%s: %d%% clouds, %s", w.Condition(), w.CloudCover, rain)
+	if w.Visibility > 0 {
+		if w.Visibility < 10000 {
+			text += fmt.Sprintf(", visibility %.1f km", w.Visibility/1000)
+		} else {
+			text += fmt.Sprintf(", visibility %.0f km", w.Visibility/1000)
+		}
+	}
+	return text
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeatherDuring(t *testing.T) {
+	base := time.Date(2025, 6, 21, 18, 0, 0, 0, time.UTC)
+	forecast := WeatherForecast{Hours: []WeatherHour{
+		{Time: base, CloudCover: 90, Precipitation: 1.2, Visibility: 8000},
+		{Time: base.Add(time.Hour), CloudCover: 40, Precipitation: 0, Visibility: 24000},
+		{Time: base.Add(2 * time.Hour), CloudCover: 10, Precipitation: 0},
+		{Time: base.Add(3 * time.Hour), CloudCover: 0, Precipitation: 0, Visibility: 600},
+	}}
+
+	// 19:20 to 20:10 overlaps the hours from 19:00 and 20:00
+	w, ok := forecast.During(TimeRange{Start: base.Add(80 * time.Minute), End: base.Add(130 * time.Minute)})
+	if !ok {
+		t.Fatal("no weather for a forecast period")
+	}
+	if w.CloudCover != 25 || w.Precipitation != 0 || w.Visibility != 24000 {
+		t.Errorf("weather %+v, want 25%% clouds, no rain, 24 km", w)
+	}
+	if w.Condition() != "Partly cloudy" || w.Icon() != "🌤" {
+		t.Errorf("condition %q %s, want partly cloudy", w.Condition(), w.Icon())
+	}
+	if want := "Partly cloudy: 25% clouds, no rain, visibility 24 km"; w.Summary() != want {
+		t.Errorf("summary %q, want %q", w.Summary(), want)
+	}
+
+	// Rain and fog outweigh the clouds
+	if w, _ := forecast.During(TimeRange{Start: base, End: base.Add(30 * time.Minute)}); w.Condition() != "Rain" {
+		t.Errorf("condition %q during rain, want Rain", w.Condition())
+	}
+	if w, _ := forecast.During(TimeRange{Start: base.Add(3 * time.Hour), End: base.Add(200 * time.Minute)}); w.Condition() != "Fog" {
+		t.Errorf("condition %q at 600 m visibility, want Fog", w.Condition())
+	}
+
+	if _, ok := forecast.During(TimeRange{Start: base.Add(5 * time.Hour), End: base.Add(6 * time.Hour)}); ok {
+		t.Error("weather for a period beyond the forecast")
+	}
+	if _, ok := forecast.During(TimeRange{}); ok {
+		t.Error("weather for an invalid period")
+	}
+}
+
+func TestShootProbability(t *testing.T) {
+	for _, tc := range []struct {
+		weather PeriodWeather
+		want    int
+	}{
+		{PeriodWeather{CloudCover: 0}, 95},
+		{PeriodWeather{CloudCover: 60, Visibility: 30000}, 95},
+		{PeriodWeather{CloudCover: 80}, 55},
+		{PeriodWeather{CloudCover: 100}, 10},
+		{PeriodWeather{CloudCover: 30, Precipitation: 0.4}, 30},
+		{PeriodWeather{CloudCover: 30, Precipitation: 2}, 10},
+		{PeriodWeather{CloudCover: 10, Visibility: 500}, 50},
+		{PeriodWeather{CloudCover: 10, Visibility: 3000}, 75},
+	} {
+		if got := tc.weather.ShootProbability(); got != tc.want {
+			t.Errorf("%+v: %d%%, want %d%%", tc.weather, got, tc.want)
+		}
+	}
+}
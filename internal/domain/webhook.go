@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// =============================================================================
+// Webhook
+// =============================================================================
+
+// DefaultWebhookLeadMinutes is how long before an event the webhook is
+// called by default: enough to turn on a light or start a camera's
+// warm-up routine.
+const DefaultWebhookLeadMinutes = 15
+
+// Webhook configures a web request sent some minutes before selected
+// events, for home automation (Home Assistant, Node-RED), IFTTT's Webhooks
+// service, or any other service that reacts to an HTTP POST.
+//
+// The request carries the event, its time and the place as JSON (see
+// automation.WebhookPayload). Unlike the phone reminders, which follow the
+// current location, the webhook can be called for selected favorites as
+// well, so a porch light at home reacts to the sunset there while the map
+// is showing a trip. Requests are queued by the automation scheduler while
+// the app is running.
+type Webhook struct {
+	// Enabled turns the webhook on.
+	//
+	// Default: false
+	Enabled bool `json:"enabled"`
+
+	// URL receives the POST requests, e.g.,
+	// "https://maker.ifttt.com/trigger/golden_hour/json/with/key/<key>" or
+	// "http://homeassistant.local:8123/api/webhook/<id>". It often holds
+	// a secret key, so it is stored like the other service keys.
+	URL string `json:"url,omitempty"`
+
+	// Events lists the events the webhook is called for, in daily order.
+	//
+	// Default: evening golden hour start
+	Events []EventKind `json:"events,omitempty"`
+
+	// LeadMinutes is how many minutes before each event the webhook is
+	// called (0 to MaxPushLeadMinutes, like the phone reminders).
+	//
+	// Default: DefaultWebhookLeadMinutes
+	LeadMinutes int `json:"lead_minutes"`
+
+	// Favorites lists the IDs of the favorites the webhook is called for
+	// (see Favorite.ID). Empty means the current location.
+	Favorites []string `json:"favorites,omitempty"`
+}
+
+// DefaultWebhook returns the webhook configuration for new users (turned
+// off, no URL).
+func DefaultWebhook() Webhook {
+	return Webhook{
+		Events:      []EventKind{EventGoldenEveningStart},
+		LeadMinutes: DefaultWebhookLeadMinutes,
+	}
+}
+
+// Check reports the first problem that would keep the webhook from being
+// called, suitable for showing to the user.
+func (w Webhook) Check() error {
+	if w.URL == "" {
+		return errors.New("no webhook URL set")
+	}
+	u, err := url.Parse(w.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("webhook URL %q must be an http:// or https:// URL", w.URL)
+	}
+	return nil
+}
+
+// validate repairs a loaded configuration: unknown events and favorite IDs
+// that no longer exist are dropped, the lead time is clamped, and the
+// webhook is turned off if it still couldn't be called (see Check).
+func (w *Webhook) validate(favorites []Favorite) {
+	w.URL = strings.TrimSpace(w.URL)
+	w.Events = slices.DeleteFunc(slices.Clone(w.Events), func(k EventKind) bool {
+		return !slices.Contains(AllEventKinds(), k)
+	})
+	w.LeadMinutes = min(max(w.LeadMinutes, 0), MaxPushLeadMinutes)
+	w.Favorites = slices.DeleteFunc(slices.Clone(w.Favorites), func(id string) bool {
+		return !slices.ContainsFunc(favorites, func(f Favorite) bool { return f.ID == id })
+	})
+
+	if w.Enabled && w.Check() != nil {
+		w.Enabled = false
+	}
+}
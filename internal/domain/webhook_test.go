@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWebhookCheck(t *testing.T) {
+	tests := []struct {
+		url string
+		ok  bool
+	}{
+		{"https://maker.ifttt.com/trigger/golden_hour/json/with/key/abc", true},
+		{"http://homeassistant.local:8123/api/webhook/sunset", true},
+		{"", false},
+		{"maker.ifttt.com/trigger", false},
+		{"ftp://example.com/hook", false},
+	}
+	for _, tt := range tests {
+		w := DefaultWebhook()
+		w.URL = tt.url
+		if err := w.Check(); (err == nil) != tt.ok {
+			t.Errorf("Check(%q) = %v, want ok=%v", tt.url, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	s := DefaultSettings()
+	s.Favorites = []Favorite{{ID: "a", Location: Location{Latitude: 1, Longitude: 2, Name: "A"}}}
+	s.Webhook = Webhook{
+		Enabled:     true,
+		URL:         " https://example.com/hook ",
+		Events:      []EventKind{EventSunset, "moonrise"},
+		LeadMinutes: -5,
+		Favorites:   []string{"a", "gone"},
+	}
+	s.Validate()
+
+	w := s.Webhook
+	if !w.Enabled || w.URL != "https://example.com/hook" || w.LeadMinutes != 0 {
+		t.Errorf("Validate() = %+v, want enabled at https://example.com/hook, no lead time", w)
+	}
+	if want := []EventKind{EventSunset}; !slices.Equal(w.Events, want) {
+		t.Errorf("Events = %v, want %v", w.Events, want)
+	}
+	if want := []string{"a"}; !slices.Equal(w.Favorites, want) {
+		t.Errorf("Favorites = %v, want %v", w.Favorites, want)
+	}
+
+	s.Webhook.URL = "not a url"
+	s.Validate()
+	if s.Webhook.Enabled {
+		t.Error("Validate() kept a webhook without a valid URL enabled")
+	}
+}
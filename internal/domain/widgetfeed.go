@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// =============================================================================
+// Widget Feed
+// =============================================================================
+
+// Formats of the widget feed, stored in WidgetFeed.Format.
+const (
+	// FeedFormatJSON writes a JSON document, for scripts and widgets that
+	// read JSON (conky with Lua, Rainmeter's WebParser, Übersicht).
+	FeedFormatJSON = "json"
+
+	// FeedFormatRSS writes an RSS 2.0 feed with one item per upcoming
+	// golden or blue hour, for feed reader widgets.
+	FeedFormatRSS = "rss"
+)
+
+// Limits of the widget feed's update interval, in minutes.
+const (
+	// DefaultFeedIntervalMinutes keeps the countdown in the feed close
+	// enough for a desktop widget without touching the disk often.
+	DefaultFeedIntervalMinutes = 15
+
+	// MinFeedIntervalMinutes and MaxFeedIntervalMinutes bound
+	// WidgetFeed.IntervalMinutes.
+	MinFeedIntervalMinutes = 1
+	MaxFeedIntervalMinutes = 360
+)
+
+// FeedHours is how far ahead the widget feed lists events: tonight and
+// the whole of tomorrow.
+const FeedHours = 48
+
+// WidgetFeed configures a small file of the home location's upcoming
+// golden and blue hours, rewritten every few minutes, for desktop widgets
+// such as conky, Rainmeter or GeekTool to display (see
+// export.FeedJSON and export.FeedRSS).
+//
+// The feed follows the home location (Settings.Home), not the place on
+// the map, so a widget keeps showing home while planning a trip. It is
+// written while the app is running, also in privacy mode: nothing goes
+// online.
+type WidgetFeed struct {
+	// Enabled turns the feed on.
+	//
+	// Default: false
+	Enabled bool `json:"enabled"`
+
+	// Path is the file the feed is written to, replaced on every update.
+	Path string `json:"path,omitempty"`
+
+	// Format is FeedFormatJSON or FeedFormatRSS.
+	//
+	// Default: FeedFormatJSON
+	Format string `json:"format"`
+
+	// IntervalMinutes is how often the feed is rewritten
+	// (MinFeedIntervalMinutes to MaxFeedIntervalMinutes).
+	//
+	// Default: DefaultFeedIntervalMinutes
+	IntervalMinutes int `json:"interval_minutes"`
+}
+
+// DefaultWidgetFeed returns the widget feed configuration for new users
+// (turned off, JSON every 15 minutes).
+func DefaultWidgetFeed() WidgetFeed {
+	return WidgetFeed{
+		Format:          FeedFormatJSON,
+		IntervalMinutes: DefaultFeedIntervalMinutes,
+	}
+}
+
+// Check reports the first problem that would keep the feed from being
+// written, suitable for showing to the user. Whether the file's folder
+// exists is left to the first write.
+func (f WidgetFeed) Check() error {
+	if f.Path == "" {
+		return errors.New("no feed file set")
+	}
+	if !filepath.IsAbs(f.Path) {
+		return fmt.Errorf("feed file %q must be a full path", f.Path)
+	}
+	if f.Format != FeedFormatJSON && f.Format != FeedFormatRSS {
+		return fmt.Errorf("unknown feed format %q", f.Format)
+	}
+	return nil
+}
+
+// validate repairs a loaded configuration: an unknown format becomes JSON,
+// the interval is clamped, and the feed is turned off if it still couldn't
+// be written (see Check).
+func (f *WidgetFeed) validate() {
+	f.Path = strings.TrimSpace(f.Path)
+	if f.Format != FeedFormatRSS {
+		f.Format = FeedFormatJSON
+	}
+	if f.IntervalMinutes == 0 {
+		f.IntervalMinutes = DefaultFeedIntervalMinutes
+	}
+	f.IntervalMinutes = min(max(f.IntervalMinutes, MinFeedIntervalMinutes), MaxFeedIntervalMinutes)
+
+	if f.Enabled && f.Check() != nil {
+		f.Enabled = false
+	}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "testing"
+
+func TestWidgetFeedCheck(t *testing.T) {
+	tests := []struct {
+		path, format string
+		ok           bool
+	}{
+		{"/home/me/.cache/golden-hour.json", FeedFormatJSON, true},
+		{"/home/me/golden-hour.xml", FeedFormatRSS, true},
+		{"", FeedFormatJSON, false},
+		{"golden-hour.json", FeedFormatJSON, false},
+		{"/home/me/golden-hour.txt", "text", false},
+	}
+	for _, tt := range tests {
+		feed := WidgetFeed{Path: tt.path, Format: tt.format}
+		if err := feed.Check(); (err == nil) != tt.ok {
+			t.Errorf("Check(%q, %q) = %v, want ok=%v", tt.path, tt.format, err, tt.ok)
+		}
+	}
+}
+
+func TestValidateWidgetFeed(t *testing.T) {
+	s := DefaultSettings()
+	s.WidgetFeed = WidgetFeed{Enabled: true, Path: " /tmp/feed.xml ", Format: "atom", IntervalMinutes: 5000}
+	s.Validate()
+	if want := (WidgetFeed{Enabled: true, Path: "/tmp/feed.xml", Format: FeedFormatJSON,
+		IntervalMinutes: MaxFeedIntervalMinutes}); s.WidgetFeed != want {
+		t.Errorf("Validate() = %+v, want %+v", s.WidgetFeed, want)
+	}
+
+	// Settings files from before the feed get the default interval
+	s.WidgetFeed = WidgetFeed{Enabled: true, Format: FeedFormatRSS}
+	s.Validate()
+	if s.WidgetFeed.Enabled || s.WidgetFeed.IntervalMinutes != DefaultFeedIntervalMinutes {
+		t.Errorf("feed without a path: %+v, want turned off at the default interval", s.WidgetFeed)
+	}
+}
@@ -0,0 +1,141 @@
+// Package events is the App's event bus: the controller publishes each
+// change of state once, and any number of frontend widgets subscribe to
+// the changes they show.
+//
+// # Architecture
+//
+// Before the bus, every change went through a hand-wired chain: the App
+// called View.UpdateLocation, which called each widget that shows the
+// location. Now a widget subscribes by itself, so adding a panel doesn't
+// touch the App or the View interface:
+//
+//	App.UpdateLocation ──► Publish(LocationChanged) ──┬──► location panel
+//	                                                  ├──► map marker
+//	                                                  └──► status bar
+//
+// Subscriptions are typed by the event's Go type:
+//
+//	unsubscribe := events.Subscribe(bus, func(e events.LocationChanged) {
+//	    panel.SetLocation(e.Location)
+//	})
+//
+// # Thread Safety
+//
+// The App publishes on the frontend's main thread, and handlers run there,
+// synchronously and in the order they subscribed, so they may update
+// widgets directly. Subscribing and unsubscribing are safe from any
+// goroutine, including from a handler.
+package events
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Events
+// =============================================================================
+
+// LocationChanged is published when the current location changes: after a
+// detection, search, map click, favorite or link, and when a favorite's
+// overrides change the place it stands for.
+type LocationChanged struct {
+	// Location is the new current location.
+	Location domain.Location
+}
+
+// DateChanged is published when the selected date changes.
+type DateChanged struct {
+	// Date is the new selected date.
+	Date time.Time
+}
+
+// SunTimesUpdated is published after every recalculation of the selected
+// day (a new location, date or settings).
+type SunTimesUpdated struct {
+	// SunTimes are the selected day's sun times.
+	SunTimes domain.SunTimes
+}
+
+// =============================================================================
+// Bus
+// =============================================================================
+
+// Bus delivers published events to their subscribers.
+//
+// The zero value is not usable; create a bus with New.
+type Bus struct {
+	// mu guards handlers and nextID.
+	mu sync.Mutex
+
+	// handlers are the subscriptions of each event type, in the order
+	// they were made.
+	handlers map[reflect.Type][]subscription
+
+	// nextID numbers the subscriptions, so unsubscribe finds its own.
+	nextID int
+}
+
+// subscription is a handler of one event type.
+type subscription struct {
+	id int
+	fn func(any)
+}
+
+// New creates a bus without subscribers.
+func New() *Bus {
+	return &Bus{handlers: make(map[reflect.Type][]subscription)}
+}
+
+// eventType returns the type subscriptions of E are kept under.
+func eventType[E any]() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
+// Subscribe calls fn with every event of type E published on the bus,
+// until the returned function is called.
+//
+// Parameters:
+//   - bus: The bus
+//   - fn: Called with each event, on the publisher's goroutine
+//
+// Returns the function that ends the subscription; calling it again does
+// nothing.
+func Subscribe[E any](bus *Bus, fn func(E)) (unsubscribe func()) {
+	t := eventType[E]()
+	bus.mu.Lock()
+	bus.nextID++
+	id := bus.nextID
+	bus.handlers[t] = append(bus.handlers[t], subscription{
+		id: id,
+		fn: func(event any) { fn(event.(E)) },
+	})
+	bus.mu.Unlock()
+
+	return func() {
+		bus.mu.Lock()
+		defer bus.mu.Unlock()
+		bus.handlers[t] = slices.DeleteFunc(slices.Clone(bus.handlers[t]), func(s subscription) bool {
+			return s.id == id
+		})
+	}
+}
+
+// Publish calls the subscribers of E with the event, in the order they
+// subscribed, and returns when all have run.
+//
+// The subscribers are those at the time of the call: a handler that
+// subscribes or unsubscribes takes effect from the next event.
+func Publish[E any](bus *Bus, event E) {
+	bus.mu.Lock()
+	handlers := bus.handlers[eventType[E]()]
+	bus.mu.Unlock()
+
+	for _, s := range handlers {
+		s.fn(event)
+	}
+}
@@ -0,0 +1,91 @@
+package events
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestPublishInSubscriptionOrder(t *testing.T) {
+	bus := New()
+	var got []string
+	Subscribe(bus, func(e LocationChanged) { got = append(got, "first "+e.Location.Name) })
+	Subscribe(bus, func(e LocationChanged) { got = append(got, "second "+e.Location.Name) })
+
+	Publish(bus, LocationChanged{Location: domain.Location{Name: "Paris"}})
+
+	want := []string{"first Paris", "second Paris"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("handlers ran as %q, want %q", got, want)
+	}
+}
+
+func TestPublishByType(t *testing.T) {
+	bus := New()
+	var dates, sunTimes int
+	Subscribe(bus, func(DateChanged) { dates++ })
+	Subscribe(bus, func(SunTimesUpdated) { sunTimes++ })
+
+	Publish(bus, DateChanged{Date: time.Now()})
+	Publish(bus, DateChanged{Date: time.Now()})
+	Publish(bus, LocationChanged{}) // no subscribers
+
+	if dates != 2 || sunTimes != 0 {
+		t.Errorf("DateChanged handled %d times, SunTimesUpdated %d; want 2 and 0", dates, sunTimes)
+	}
+}
+
+func TestUnsubscribe(t *testing.T) {
+	bus := New()
+	var first, second int
+	unsubscribe := Subscribe(bus, func(DateChanged) { first++ })
+	Subscribe(bus, func(DateChanged) { second++ })
+
+	Publish(bus, DateChanged{})
+	unsubscribe()
+	unsubscribe() // does nothing the second time
+	Publish(bus, DateChanged{})
+
+	if first != 1 || second != 2 {
+		t.Errorf("handlers ran %d and %d times, want 1 and 2", first, second)
+	}
+}
+
+func TestSubscribeDuringPublish(t *testing.T) {
+	bus := New()
+	var late int
+	var unsubscribe func()
+	unsubscribe = Subscribe(bus, func(DateChanged) {
+		unsubscribe()
+		Subscribe(bus, func(DateChanged) { late++ })
+	})
+
+	Publish(bus, DateChanged{}) // the new handler waits for the next event
+	if late != 0 {
+		t.Errorf("handler subscribed during Publish ran %d times, want 0", late)
+	}
+	Publish(bus, DateChanged{})
+	if late != 1 {
+		t.Errorf("handler subscribed during Publish ran %d times after the next event, want 1", late)
+	}
+}
+
+func TestConcurrentSubscribe(t *testing.T) {
+	bus := New()
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unsubscribe := Subscribe(bus, func(SunTimesUpdated) {})
+			Publish(bus, SunTimesUpdated{})
+			unsubscribe()
+		}()
+	}
+	wg.Wait()
+	if n := len(bus.handlers[eventType[SunTimesUpdated]()]); n != 0 {
+		t.Errorf("%d subscriptions left after unsubscribing all", n)
+	}
+}
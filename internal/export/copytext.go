@@ -0,0 +1,170 @@
+package export
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Copy Times
+// =============================================================================
+
+// DefaultCopyTemplate is the summary copied by Edit → Copy Times unless the
+// user sets their own (domain.Settings.CopyTemplate). It is Markdown, which
+// chat apps and note takers render and which still reads well as plain
+// text:
+//
+//	**Riverside Bridge** – Monday, June 22, 2026
+//	- Blue hour: 05:10 - 05:35 · 21:58 - 22:30
+//	- Golden hour: 05:47 - 06:30 · 21:00 - 21:58
+//	- Sunrise 05:47 · Sunset 21:58
+//	- Shooting light: 2h 38m
+const DefaultCopyTemplate = `**{{.Location}}** – {{.Date}}
+- Blue hour: {{.BlueMorning}} · {{.BlueEvening}}
+- Golden hour: {{.GoldenMorning}} · {{.GoldenEvening}}
+- Sunrise {{.Sunrise}} · Sunset {{.Sunset}}
+- Shooting light: {{.ShootingLight}}
+`
+
+// CopyData holds the values available to copy template placeholders.
+//
+// Placeholders use Go template syntax, e.g. {{.Sunrise}}, like hook
+// commands (see automation.TemplateData). Times are formatted in the user's
+// time format and local to the place; periods and events that don't occur
+// are "none".
+type CopyData struct {
+	// Location is the place name, or its coordinates if it has none.
+	Location string
+
+	// Coordinates are the place's coordinates in the user's format.
+	Coordinates string
+
+	// Timezone is the place's IANA timezone (e.g., "Europe/Paris").
+	Timezone string
+
+	// Date is the day, spelled out (e.g., "Monday, June 22, 2026").
+	Date string
+
+	// Sunrise, Sunset and SolarNoon are times of day (e.g., "05:47").
+	Sunrise   string
+	Sunset    string
+	SolarNoon string
+
+	// GoldenMorning, GoldenEvening, BlueMorning and BlueEvening are periods
+	// as "start - end".
+	GoldenMorning string
+	GoldenEvening string
+	BlueMorning   string
+	BlueEvening   string
+
+	// ShootingLight is the day's golden and blue hours together (e.g.,
+	// "2h 38m").
+	ShootingLight string
+
+	// Events are all of the day's events in chronological order, for
+	// templates that list them with {{range .Events}}.
+	Events []CopyEvent
+}
+
+// CopyEvent is one event of CopyData.Events.
+type CopyEvent struct {
+	// Time is the time of day (e.g., "21:00").
+	Time string
+
+	// Label is the event name (e.g., "Evening golden hour start").
+	Label string
+}
+
+// NewCopyData builds the placeholder values for a day at a place.
+//
+// Parameters:
+//   - loc: The place
+//   - day: Its sun times
+//   - use24Hour: Time format
+//   - coordinateFormat: Format of the coordinates
+//     (domain.Settings.CoordinateFormat)
+//   - nameStyle: How much of the place name to show
+//     (domain.Settings.PlaceNameStyle)
+func NewCopyData(loc domain.Location, day domain.SunTimes, use24Hour bool, coordinateFormat, nameStyle string) CopyData {
+	clock := func(t time.Time) string {
+		if t.IsZero() {
+			return "none"
+		}
+		return domain.FormatTime(t, use24Hour)
+	}
+
+	coordinates := domain.FormatCoordinatesIn(loc.Latitude, loc.Longitude, coordinateFormat)
+	data := CopyData{
+		Location:      cmp.Or(loc.DisplayName(nameStyle), coordinates),
+		Coordinates:   coordinates,
+		Timezone:      loc.Timezone,
+		Date:          day.Date.Format("Monday, January 2, 2006"),
+		Sunrise:       clock(day.Sunrise),
+		Sunset:        clock(day.Sunset),
+		SolarNoon:     clock(day.SolarNoon),
+		GoldenMorning: planRange(day.GoldenMorning, use24Hour),
+		GoldenEvening: planRange(day.GoldenEvening, use24Hour),
+		BlueMorning:   planRange(day.BlueMorning, use24Hour),
+		BlueEvening:   planRange(day.BlueEvening, use24Hour),
+		ShootingLight: planLight(day),
+	}
+	for _, event := range day.Events() {
+		data.Events = append(data.Events, CopyEvent{
+			Time:  domain.FormatTime(event.Time, use24Hour),
+			Label: event.Kind.Label(),
+		})
+	}
+	return data
+}
+
+// CopyText fills in a copy template.
+//
+// Parameters:
+//   - text: The template; empty means DefaultCopyTemplate
+//   - data: Placeholder values (see NewCopyData)
+//
+// Returns the summary, or an error if the template is malformed or uses a
+// placeholder that doesn't exist (so typos are reported rather than
+// silently copied as empty text).
+//
+// Example:
+//
+//	text, err := export.CopyText(settings.CopyTemplate, export.NewCopyData(loc, day,
+//	    settings.TimeFormat24Hour, settings.CoordinateFormat, settings.PlaceNameStyle))
+func CopyText(text string, data CopyData) (string, error) {
+	tmpl, err := parseCopyTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("invalid copy template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// CheckCopyTemplate reports the first problem with a copy template, by
+// filling it in with sample values. Used by the preferences dialog before
+// the template is saved.
+func CheckCopyTemplate(text string) error {
+	_, err := CopyText(text, CopyData{Events: []CopyEvent{{}}})
+	return err
+}
+
+// parseCopyTemplate parses a copy template, or the default one if text is
+// blank.
+func parseCopyTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		text = DefaultCopyTemplate
+	}
+	tmpl, err := template.New("copy").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid copy template: %w", err)
+	}
+	return tmpl, nil
+}
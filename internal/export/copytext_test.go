@@ -0,0 +1,54 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCopyText(t *testing.T) {
+	at := func(hour, min int) time.Time {
+		return time.Date(2026, time.June, 22, hour, min, 0, 0, time.UTC)
+	}
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Riverside Bridge", Timezone: "Europe/Paris"}
+	day := domain.SunTimes{
+		Date:          at(0, 0),
+		Sunrise:       at(5, 47),
+		Sunset:        at(21, 58),
+		GoldenEvening: domain.TimeRange{Start: at(21, 0), End: at(21, 58)},
+	}
+	data := NewCopyData(loc, day, true, domain.CoordinateFormatDecimal, domain.PlaceNameFull)
+
+	text, err := CopyText("", data)
+	if err != nil {
+		t.Fatalf("default template: %v", err)
+	}
+	for _, want := range []string{
+		"**Riverside Bridge** – Monday, June 22, 2026",
+		"- Golden hour: none · 21:00 - 21:58",
+		"- Sunrise 05:47 · Sunset 21:58",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary lacks %q:\n%s", want, text)
+		}
+	}
+
+	text, err = CopyText("{{range .Events}}{{.Time}} {{.Label}}\n{{end}}", data)
+	if err != nil {
+		t.Fatalf("events template: %v", err)
+	}
+	if want := "05:47 Sunrise\n21:00 Evening golden hour start\n"; !strings.HasPrefix(text, want) {
+		t.Errorf("events = %q, want prefix %q", text, want)
+	}
+
+	for _, bad := range []string{"{{.Sunrise", "{{.Sunrize}}"} {
+		if err := CheckCopyTemplate(bad); err == nil {
+			t.Errorf("CheckCopyTemplate(%q) = nil, want an error", bad)
+		}
+	}
+	if err := CheckCopyTemplate(DefaultCopyTemplate); err != nil {
+		t.Errorf("default template: %v", err)
+	}
+}
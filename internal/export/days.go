@@ -0,0 +1,262 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Day Table
+// =============================================================================
+
+// DayColumn identifies a column of the day table. The identifiers are the
+// CSV headers and JSON keys.
+type DayColumn string
+
+// Day table columns, in table order. The date always comes first and isn't
+// a selectable column.
+const (
+	ColumnSunrise            DayColumn = "sunrise"
+	ColumnSunset             DayColumn = "sunset"
+	ColumnSolarNoon          DayColumn = "solar_noon"
+	ColumnGoldenMorningStart DayColumn = "golden_morning_start"
+	ColumnGoldenMorningEnd   DayColumn = "golden_morning_end"
+	ColumnGoldenEveningStart DayColumn = "golden_evening_start"
+	ColumnGoldenEveningEnd   DayColumn = "golden_evening_end"
+	ColumnBlueMorningStart   DayColumn = "blue_morning_start"
+	ColumnBlueMorningEnd     DayColumn = "blue_morning_end"
+	ColumnBlueEveningStart   DayColumn = "blue_evening_start"
+	ColumnBlueEveningEnd     DayColumn = "blue_evening_end"
+	ColumnShootingMinutes    DayColumn = "shooting_minutes"
+)
+
+// dayColumnLabels maps columns to the names shown in the export dialog.
+var dayColumnLabels = map[DayColumn]string{
+	ColumnSunrise:            "Sunrise",
+	ColumnSunset:             "Sunset",
+	ColumnSolarNoon:          "Solar noon",
+	ColumnGoldenMorningStart: "Morning golden hour start",
+	ColumnGoldenMorningEnd:   "Morning golden hour end",
+	ColumnGoldenEveningStart: "Evening golden hour start",
+	ColumnGoldenEveningEnd:   "Evening golden hour end",
+	ColumnBlueMorningStart:   "Morning blue hour start",
+	ColumnBlueMorningEnd:     "Morning blue hour end",
+	ColumnBlueEveningStart:   "Evening blue hour start",
+	ColumnBlueEveningEnd:     "Evening blue hour end",
+	ColumnShootingMinutes:    "Shooting light (minutes)",
+}
+
+// AllDayColumns returns every column in table order.
+func AllDayColumns() []DayColumn {
+	return []DayColumn{
+		ColumnSunrise, ColumnSunset, ColumnSolarNoon,
+		ColumnGoldenMorningStart, ColumnGoldenMorningEnd, ColumnGoldenEveningStart, ColumnGoldenEveningEnd,
+		ColumnBlueMorningStart, ColumnBlueMorningEnd, ColumnBlueEveningStart, ColumnBlueEveningEnd,
+		ColumnShootingMinutes,
+	}
+}
+
+// DefaultDayColumns returns the columns exported unless others are chosen:
+// all but solar noon.
+func DefaultDayColumns() []DayColumn {
+	var columns []DayColumn
+	for _, c := range AllDayColumns() {
+		if c != ColumnSolarNoon {
+			columns = append(columns, c)
+		}
+	}
+	return columns
+}
+
+// Label returns the column's display name; unknown columns return the raw
+// identifier.
+func (c DayColumn) Label() string {
+	if label, ok := dayColumnLabels[c]; ok {
+		return label
+	}
+	return string(c)
+}
+
+// value returns a day's value of the column: a time of day, the shooting
+// minutes, or "" for an event that doesn't occur.
+func (c DayColumn) value(day domain.SunTimes, use24Hour bool) string {
+	clock := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return domain.FormatTime(t, use24Hour)
+	}
+	start := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return ""
+		}
+		return clock(tr.Start)
+	}
+	end := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return ""
+		}
+		return clock(tr.End)
+	}
+
+	switch c {
+	case ColumnSunrise:
+		return clock(day.Sunrise)
+	case ColumnSunset:
+		return clock(day.Sunset)
+	case ColumnSolarNoon:
+		return clock(day.SolarNoon)
+	case ColumnGoldenMorningStart:
+		return start(day.GoldenMorning)
+	case ColumnGoldenMorningEnd:
+		return end(day.GoldenMorning)
+	case ColumnGoldenEveningStart:
+		return start(day.GoldenEvening)
+	case ColumnGoldenEveningEnd:
+		return end(day.GoldenEvening)
+	case ColumnBlueMorningStart:
+		return start(day.BlueMorning)
+	case ColumnBlueMorningEnd:
+		return end(day.BlueMorning)
+	case ColumnBlueEveningStart:
+		return start(day.BlueEvening)
+	case ColumnBlueEveningEnd:
+		return end(day.BlueEvening)
+	case ColumnShootingMinutes:
+		return strconv.Itoa(int(day.ShootingWindow().Minutes()))
+	}
+	return ""
+}
+
+// DaysOptions selects what the day table contains.
+type DaysOptions struct {
+	// Columns are the columns after the date, in the order given; empty
+	// means DefaultDayColumns.
+	Columns []DayColumn
+
+	// Use24Hour writes times as "21:58" rather than "9:58 PM".
+	Use24Hour bool
+
+	// Separator is the CSV field separator; zero means a comma. A tab
+	// pastes into spreadsheets as separate cells.
+	Separator rune
+}
+
+// columns returns the chosen columns, or the default ones.
+func (o DaysOptions) columns() []DayColumn {
+	if len(o.Columns) == 0 {
+		return DefaultDayColumns()
+	}
+	return o.Columns
+}
+
+// DaysCSV writes the sun times of a date range as CSV, one row per day, for
+// planning a trip in a spreadsheet.
+//
+// The first column is the date (YYYY-MM-DD), followed by the chosen
+// columns; the header row holds their identifiers:
+//
+//	date,sunrise,sunset,golden_morning_start,...,blue_evening_end,shooting_minutes
+//	2026-06-22,05:47,21:58,05:47,06:30,21:00,21:58,05:10,05:35,21:58,22:30,158
+//
+// Times are local to the place; events that don't occur are empty.
+// 24-hour times are what spreadsheets read as times of day most reliably.
+//
+// Parameters:
+//   - days: Sun times for each day, in order (see solar.CalculateRange)
+//   - opts: Columns, time format and separator
+//
+// Returns the file content, or an error if it can't be encoded.
+func DaysCSV(days []domain.SunTimes, opts DaysOptions) ([]byte, error) {
+	columns := opts.columns()
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if opts.Separator != 0 {
+		w.Comma = opts.Separator
+	}
+	header := []string{"date"}
+	for _, c := range columns {
+		header = append(header, string(c))
+	}
+	_ = w.Write(header)
+
+	for _, day := range days {
+		row := []string{day.Date.Format("2006-01-02")}
+		for _, c := range columns {
+			row = append(row, c.value(day, opts.Use24Hour))
+		}
+		_ = w.Write(row)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode days: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// DaysJSON writes the sun times of a date range as a JSON array, one object
+// per day with the date and the chosen columns, in that order:
+//
+//	[
+//	  {"date": "2026-06-22", "sunrise": "05:47", ..., "shooting_minutes": 158},
+//	  {"date": "2026-06-23", "sunrise": null, ..., "shooting_minutes": 0}
+//	]
+//
+// Times are strings local to the place, in the chosen format; events that
+// don't occur are null. Shooting minutes are numbers. The separator option
+// is ignored.
+//
+// Parameters:
+//   - days: Sun times for each day, in order (see solar.CalculateRange)
+//   - opts: Columns and time format
+//
+// Returns the file content, or an error if it can't be encoded.
+func DaysJSON(days []domain.SunTimes, opts DaysOptions) ([]byte, error) {
+	columns := opts.columns()
+
+	// Written by hand so the keys keep the column order (a map would be
+	// sorted)
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, day := range days {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {\"date\": ")
+		date, _ := json.Marshal(day.Date.Format("2006-01-02"))
+		buf.Write(date)
+		for _, c := range columns {
+			key, err := json.Marshal(string(c))
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode days: %w", err)
+			}
+			buf.WriteString(", ")
+			buf.Write(key)
+			buf.WriteString(": ")
+
+			value := c.value(day, opts.Use24Hour)
+			switch {
+			case c == ColumnShootingMinutes:
+				buf.WriteString(value)
+			case value == "":
+				buf.WriteString("null")
+			default:
+				encoded, _ := json.Marshal(value)
+				buf.Write(encoded)
+			}
+		}
+		buf.WriteString("}")
+	}
+	if len(days) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// testDays are a day with a golden hour and a polar night day.
+func testDays() []domain.SunTimes {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.June, day, hour, min, 0, 0, time.UTC)
+	}
+	return []domain.SunTimes{
+		{
+			Date:          at(22, 0, 0),
+			Sunrise:       at(22, 5, 47),
+			Sunset:        at(22, 21, 58),
+			GoldenMorning: domain.TimeRange{Start: at(22, 5, 47), End: at(22, 6, 30)},
+			GoldenEvening: domain.TimeRange{Start: at(22, 21, 0), End: at(22, 21, 58)},
+		},
+		// Polar night: no events at all
+		{Date: at(23, 0, 0)},
+	}
+}
+
+func TestDaysCSV(t *testing.T) {
+	content, err := DaysCSV(testDays(), DaysOptions{Use24Hour: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "date,sunrise,sunset,") {
+		t.Fatalf("DaysCSV =\n%s", content)
+	}
+	if want := "2026-06-22,05:47,21:58,05:47,06:30,21:00,21:58,,,,,101"; lines[1] != want {
+		t.Errorf("day row = %q, want %q", lines[1], want)
+	}
+	if want := "2026-06-23,,,,,,,,,,,0"; lines[2] != want {
+		t.Errorf("empty day row = %q, want %q", lines[2], want)
+	}
+}
+
+func TestDaysCSVOptions(t *testing.T) {
+	content, err := DaysCSV(testDays(), DaysOptions{
+		Columns:   []DayColumn{ColumnSunset, ColumnGoldenEveningStart},
+		Separator: '\t',
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "date\tsunset\tgolden_evening_start\n" +
+		"2026-06-22\t9:58 PM\t9:00 PM\n" +
+		"2026-06-23\t\t\n"
+	if string(content) != want {
+		t.Errorf("DaysCSV =\n%q\nwant\n%q", content, want)
+	}
+}
+
+func TestDaysJSON(t *testing.T) {
+	content, err := DaysJSON(testDays(), DaysOptions{
+		Columns:   []DayColumn{ColumnSunrise, ColumnBlueMorningStart, ColumnShootingMinutes},
+		Use24Hour: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(content, &got); err != nil {
+		t.Fatalf("DaysJSON is not valid JSON: %v\n%s", err, content)
+	}
+	if len(got) != 2 {
+		t.Fatalf("DaysJSON has %d days, want 2", len(got))
+	}
+	if got[0]["date"] != "2026-06-22" || got[0]["sunrise"] != "05:47" || got[0]["shooting_minutes"] != 101.0 {
+		t.Errorf("first day = %v", got[0])
+	}
+	if v, ok := got[0]["blue_morning_start"]; !ok || v != nil {
+		t.Errorf("missing blue hour = %v, want null", v)
+	}
+	// Keys keep the column order
+	if !strings.HasPrefix(string(content), "[\n  {\"date\": \"2026-06-22\", \"sunrise\": ") {
+		t.Errorf("DaysJSON =\n%s", content)
+	}
+
+	if empty, _ := DaysJSON(nil, DaysOptions{}); string(empty) != "[]\n" {
+		t.Errorf("DaysJSON(nil) = %q", empty)
+	}
+}
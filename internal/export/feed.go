@@ -0,0 +1,231 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Widget Feed
+// =============================================================================
+
+// feedPeriod is a golden or blue hour in the JSON feed.
+type feedPeriod struct {
+	Name       string    `json:"name"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	StartText  string    `json:"start_text"`
+	EndText    string    `json:"end_text"`
+	Duration   string    `json:"duration"`
+	InProgress bool      `json:"in_progress"`
+}
+
+// feedEvent is a phase transition in the JSON feed.
+type feedEvent struct {
+	Kind  domain.EventKind `json:"kind"`
+	Label string           `json:"label"`
+	Time  time.Time        `json:"time"`
+	Text  string           `json:"text"`
+}
+
+// feedLocation is the place of the JSON feed.
+type feedLocation struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+// feedDocument is the JSON feed.
+type feedDocument struct {
+	Generated time.Time    `json:"generated"`
+	Location  feedLocation `json:"location"`
+	Status    string       `json:"status"`
+	Periods   []feedPeriod `json:"periods"`
+	Events    []feedEvent  `json:"events"`
+}
+
+// FeedJSON writes the widget feed as JSON: a one-line status, then the
+// golden and blue hours and the phase transitions of the next
+// domain.FeedHours hours.
+//
+//	{
+//	  "generated": "2025-06-21T19:00:00+02:00",
+//	  "location": {"name": "Paris, France", "latitude": 48.8566, ...},
+//	  "status": "Evening golden hour at 21:00, in 2h",
+//	  "periods": [
+//	    {"name": "Evening golden hour", "start": "2025-06-21T21:00:00+02:00",
+//	     "end": "...", "start_text": "21:00", "end_text": "21:58",
+//	     "duration": "58 min", "in_progress": false}, ...],
+//	  "events": [
+//	    {"kind": "golden_evening_start", "label": "Evening golden hour start",
+//	     "time": "2025-06-21T21:00:00+02:00", "text": "21:00"}, ...]
+//	}
+//
+// Times are RFC 3339 in the location's timezone; the "_text" fields are
+// formatted for display, so a conky or Rainmeter widget doesn't have to.
+//
+// Parameters:
+//   - days: Sun times at the feed's location from yesterday on, in order
+//     (yesterday for a blue hour running past midnight, then enough days
+//     to cover domain.FeedHours)
+//   - now: The current time
+//   - use24Hour: Time format
+//   - nameStyle: How much of the place name to show
+//     (domain.Settings.PlaceNameStyle)
+func FeedJSON(days []domain.SunTimes, now time.Time, use24Hour bool, nameStyle string) ([]byte, error) {
+	doc := feedDocument{
+		Generated: now.Truncate(time.Second),
+		Status:    domain.LightStatus(days, now, use24Hour),
+		Periods:   []feedPeriod{},
+		Events:    []feedEvent{},
+	}
+	if len(days) > 0 {
+		loc := days[0].Location
+		doc.Location = feedLocation{Name: feedPlace(loc, nameStyle), Latitude: loc.Latitude,
+			Longitude: loc.Longitude, Timezone: loc.Timezone}
+		now = now.In(days[0].Date.Location())
+		doc.Generated = doc.Generated.In(now.Location())
+	}
+	for _, p := range upcomingPeriods(days, now) {
+		doc.Periods = append(doc.Periods, feedPeriod{
+			Name:       p.Name,
+			Start:      p.Period.Start,
+			End:        p.Period.End,
+			StartText:  domain.FormatTime(p.Period.Start, use24Hour),
+			EndText:    domain.FormatTime(p.Period.End, use24Hour),
+			Duration:   p.Period.FormatDuration(),
+			InProgress: p.InProgress(now),
+		})
+	}
+	end := now.Add(domain.FeedHours * time.Hour)
+	for _, day := range days {
+		for _, e := range day.Events() {
+			if e.Time.After(now) && e.Time.Before(end) {
+				doc.Events = append(doc.Events, feedEvent{Kind: e.Kind, Label: e.Kind.Label(), Time: e.Time,
+					Text: domain.FormatTime(e.Time, use24Hour)})
+			}
+		}
+	}
+
+	content, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// rssFeed is the root element of an RSS 2.0 feed.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// rssChannel is the feed's channel.
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           int       `xml:"ttl"`
+	Items         []rssItem `xml:"item"`
+}
+
+// rssItem is one golden or blue hour.
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Description string  `xml:"description"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+}
+
+// rssGUID identifies an item; it isn't a web address.
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+// FeedRSS writes the widget feed as RSS 2.0, one item per golden or blue
+// hour of the next domain.FeedHours hours:
+//
+//	<item>
+//	  <title>Evening golden hour 21:00 - 21:58</title>
+//	  <description>Sat, Jun 21: 58 min, in 2h</description>
+//	  <guid isPermaLink="false">48.85660,2.35220/2025-06-21T21:00:00+02:00</guid>
+//	  <pubDate>Sat, 21 Jun 2025 19:00:00 +0200</pubDate>
+//	</item>
+//
+// The channel's description is the one-line status and its link a
+// gogoldenhour:// share link to the place. Items keep their GUID across
+// updates, so readers don't show a period as new every time.
+//
+// Parameters:
+//   - days, now, use24Hour, nameStyle: As for FeedJSON
+//   - interval: How often the feed is rewritten, the channel's TTL
+func FeedRSS(days []domain.SunTimes, now time.Time, use24Hour bool, nameStyle string,
+	interval time.Duration) ([]byte, error) {
+	channel := rssChannel{
+		Title:       "Golden hour",
+		Description: domain.LightStatus(days, now, use24Hour),
+		TTL:         max(int(interval.Minutes()), 1),
+	}
+	var loc domain.Location
+	if len(days) > 0 {
+		loc = days[0].Location
+		channel.Title += " at " + feedPlace(loc, nameStyle)
+		channel.Link = domain.Link{Latitude: loc.Latitude, Longitude: loc.Longitude, Name: loc.Name}.URL()
+		now = now.In(days[0].Date.Location())
+	}
+	channel.LastBuildDate = now.Format(time.RFC1123Z)
+
+	for _, p := range upcomingPeriods(days, now) {
+		when := "now, " + domain.FormatDuration(p.Period.End.Sub(now)) + " left"
+		if !p.InProgress(now) {
+			when = "in " + domain.FormatDuration(p.Period.Start.Sub(now))
+		}
+		channel.Items = append(channel.Items, rssItem{
+			Title: fmt.Sprintf("%s %s - %s", p.Name, domain.FormatTime(p.Period.Start, use24Hour),
+				domain.FormatTime(p.Period.End, use24Hour)),
+			Description: fmt.Sprintf("%s: %s, %s", p.Period.Start.Format("Mon, Jan 2"),
+				p.Period.FormatDuration(), when),
+			GUID: rssGUID{Value: fmt.Sprintf("%.5f,%.5f/%s", loc.Latitude, loc.Longitude,
+				p.Period.Start.Format(time.RFC3339))},
+			PubDate: channel.LastBuildDate,
+		})
+	}
+
+	content, err := xml.MarshalIndent(rssFeed{Version: "2.0", Channel: channel}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
+
+// upcomingPeriods returns the golden and blue hours that haven't ended at
+// now and start within domain.FeedHours, in order.
+func upcomingPeriods(days []domain.SunTimes, now time.Time) []domain.LightPeriod {
+	end := now.Add(domain.FeedHours * time.Hour)
+	var periods []domain.LightPeriod
+	for _, day := range days {
+		for _, p := range day.LightPeriods() {
+			if p.Period.End.After(now) && p.Period.Start.Before(end) {
+				periods = append(periods, p)
+			}
+		}
+	}
+	return periods
+}
+
+// feedPlace names the feed's location, by its coordinates if it has no
+// name.
+func feedPlace(loc domain.Location, nameStyle string) string {
+	if name := loc.DisplayName(nameStyle); name != "" {
+		return name
+	}
+	return domain.FormatCoordinates(loc.Latitude, loc.Longitude)
+}
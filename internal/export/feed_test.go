@@ -0,0 +1,114 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// feedDays are three days at the bridge with an evening golden hour and
+// a blue hour running past midnight.
+func feedDays() []domain.SunTimes {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.June, day, hour, min, 0, 0, time.UTC)
+	}
+	var days []domain.SunTimes
+	for d := 20; d <= 22; d++ {
+		days = append(days, domain.SunTimes{
+			Date:          at(d, 0, 0),
+			Location:      testPlaces[0].Location,
+			Sunset:        at(d, 21, 58),
+			GoldenEvening: domain.TimeRange{Start: at(d, 21, 0), End: at(d, 21, 58)},
+			BlueEvening:   domain.TimeRange{Start: at(d, 23, 40), End: at(d+1, 0, 20)},
+		})
+	}
+	return days
+}
+
+func TestFeedJSON(t *testing.T) {
+	// Just after midnight, in the blue hour that started yesterday
+	now := time.Date(2026, time.June, 21, 0, 5, 30, 123, time.UTC)
+	content, err := FeedJSON(feedDays(), now, true, domain.PlaceNameShort)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc struct {
+		Generated time.Time `json:"generated"`
+		Location  struct {
+			Name string `json:"name"`
+		} `json:"location"`
+		Status  string `json:"status"`
+		Periods []struct {
+			Name       string `json:"name"`
+			StartText  string `json:"start_text"`
+			InProgress bool   `json:"in_progress"`
+		} `json:"periods"`
+		Events []struct {
+			Kind domain.EventKind `json:"kind"`
+			Text string           `json:"text"`
+		} `json:"events"`
+	}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, content)
+	}
+	if !doc.Generated.Equal(now.Truncate(time.Second)) || doc.Location.Name != "Paris" {
+		t.Errorf("generated %v at %q", doc.Generated, doc.Location.Name)
+	}
+	if doc.Status != "Evening blue hour until 00:20, 14 min left" {
+		t.Errorf("status %q", doc.Status)
+	}
+
+	// Yesterday's blue hour, then two evenings within 48 hours
+	if len(doc.Periods) != 5 || !doc.Periods[0].InProgress || doc.Periods[1].InProgress ||
+		doc.Periods[1].Name != "Evening golden hour" || doc.Periods[1].StartText != "21:00" {
+		t.Errorf("periods %+v", doc.Periods)
+	}
+	// Events still to come only: yesterday's blue hour start is left out
+	if len(doc.Events) == 0 || doc.Events[0].Kind != domain.EventBlueEveningEnd || doc.Events[0].Text != "00:20" {
+		t.Errorf("events %+v", doc.Events)
+	}
+
+	// Without days the lists are empty, not null
+	if content, err := FeedJSON(nil, now, true, ""); err != nil {
+		t.Fatal(err)
+	} else if !strings.Contains(string(content), `"periods": []`) {
+		t.Errorf("feed without days:\n%s", content)
+	}
+}
+
+func TestFeedRSS(t *testing.T) {
+	now := time.Date(2026, time.June, 21, 19, 0, 0, 0, time.UTC)
+	content, err := FeedRSS(feedDays(), now, true, domain.PlaceNameShort, 15*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<rss version="2.0">`,
+		"<title>Golden hour at Paris</title>",
+		"<link>gogoldenhour://?lat=48.85840&amp;lon=2.29450",
+		"<ttl>15</ttl>",
+		"<title>Evening golden hour 21:00 - 21:58</title>",
+		"<description>Sun, Jun 21: 58 min, in 2h</description>",
+		`<guid isPermaLink="false">48.85840,2.29450/2026-06-21T21:00:00Z</guid>`,
+		"<pubDate>Sun, 21 Jun 2026 19:00:00 +0000</pubDate>",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("RSS lacks %s:\n%s", want, content)
+		}
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(content, &feed); err != nil {
+		t.Fatalf("invalid XML: %v", err)
+	}
+	// Tonight's golden and blue hours, tomorrow's, and the golden hour of
+	// the day after, which starts 50 hours on, is left out
+	if len(feed.Channel.Items) != 4 {
+		t.Errorf("got %d items, want 4", len(feed.Channel.Items))
+	}
+}
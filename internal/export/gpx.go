@@ -0,0 +1,138 @@
+package export
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Shoot Waypoints (GPX)
+// =============================================================================
+
+// ShootWaypoint is a place of a planned shoot with its sun times, for
+// WaypointsGPX.
+type ShootWaypoint struct {
+	// Location is the place; its name becomes the waypoint's name.
+	Location domain.Location
+
+	// Days are the sun times at the place for each planned day, in order.
+	Days []domain.SunTimes
+}
+
+// gpxFile is the root element of a GPX 1.1 file.
+type gpxFile struct {
+	XMLName   xml.Name      `xml:"gpx"`
+	Version   string        `xml:"version,attr"`
+	Creator   string        `xml:"creator,attr"`
+	Namespace string        `xml:"xmlns,attr"`
+	Metadata  gpxMetadata   `xml:"metadata"`
+	Waypoints []gpxWaypoint `xml:"wpt"`
+}
+
+// gpxMetadata names the file and when it was written.
+type gpxMetadata struct {
+	Name string `xml:"name"`
+	Time string `xml:"time"`
+}
+
+// gpxWaypoint is a waypoint. GPS units show the name and the comment in
+// lists; the description holds the details.
+type gpxWaypoint struct {
+	Latitude    float64  `xml:"lat,attr"`
+	Longitude   float64  `xml:"lon,attr"`
+	Elevation   *float64 `xml:"ele,omitempty"`
+	Name        string   `xml:"name"`
+	Comment     string   `xml:"cmt,omitempty"`
+	Description string   `xml:"desc,omitempty"`
+	Symbol      string   `xml:"sym"`
+}
+
+// gpxSymbol is the waypoint symbol, a name Garmin units and most apps
+// know.
+const gpxSymbol = "Scenic Area"
+
+// WaypointsGPX writes the places of a shoot as GPX 1.1 waypoints, to load
+// into a handheld GPS unit or a phone app for finding the spots in the
+// field.
+//
+// Each waypoint's comment gives the first day's evening golden hour (short
+// enough for a GPS screen), and its description lists each day's sun
+// times:
+//
+//	<wpt lat="48.8584" lon="2.2945">
+//	  <ele>35</ele>
+//	  <name>Riverside Bridge</name>
+//	  <cmt>Golden hour 21:00 - 21:58</cmt>
+//	  <desc>Sat, Jun 21: blue 04:52 - 05:30, sunrise 05:47, golden 05:47 - 06:30,
+//	    golden 21:00 - 21:58, sunset 21:58, blue 21:58 - 22:40 ...</desc>
+//	  <sym>Scenic Area</sym>
+//	</wpt>
+//
+// Times are local to each place. The file can be imported again with
+// geodata.ParseFile.
+//
+// Parameters:
+//   - waypoints: The places, in order; places without a name are named by
+//     their coordinates
+//   - use24Hour: Time format
+//   - nameStyle: How much of the place names to show
+//     (domain.Settings.PlaceNameStyle)
+//   - now: When the file is written, for its metadata
+func WaypointsGPX(waypoints []ShootWaypoint, use24Hour bool, nameStyle string, now time.Time) ([]byte, error) {
+	file := gpxFile{
+		Version:   "1.1",
+		Creator:   "GoGoldenHour",
+		Namespace: "http://www.topografix.com/GPX/1/1",
+		Metadata: gpxMetadata{
+			Name: "GoGoldenHour shoot waypoints",
+			Time: now.UTC().Format(time.RFC3339),
+		},
+	}
+	for _, w := range waypoints {
+		loc := w.Location
+		name := loc.DisplayName(nameStyle)
+		if name == "" {
+			name = domain.FormatCoordinates(loc.Latitude, loc.Longitude)
+		}
+		wpt := gpxWaypoint{
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Name:      name,
+			Symbol:    gpxSymbol,
+		}
+		if loc.Elevation != 0 {
+			elevation := loc.Elevation
+			wpt.Elevation = &elevation
+		}
+		if len(w.Days) > 0 {
+			wpt.Comment = "Golden hour " + planRange(w.Days[0].GoldenEvening, use24Hour)
+		}
+		lines := make([]string, len(w.Days))
+		for i, day := range w.Days {
+			lines[i] = waypointDay(day, use24Hour)
+		}
+		wpt.Description = strings.Join(lines, "\n")
+		file.Waypoints = append(file.Waypoints, wpt)
+	}
+
+	content, err := xml.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode waypoints: %w", err)
+	}
+	return append([]byte(xml.Header), append(content, '\n')...), nil
+}
+
+// waypointDay formats a day's sun times in order for a waypoint's
+// description, e.g., "Sat, Jun 21: blue 04:52 - 05:30, sunrise 05:47,
+// ...". Periods that don't occur are "none".
+func waypointDay(day domain.SunTimes, use24Hour bool) string {
+	return fmt.Sprintf("%s: blue %s, sunrise %s, golden %s, golden %s, sunset %s, blue %s",
+		day.Date.Format("Mon, Jan 2"),
+		planRange(day.BlueMorning, use24Hour), domain.FormatTime(day.Sunrise, use24Hour),
+		planRange(day.GoldenMorning, use24Hour), planRange(day.GoldenEvening, use24Hour),
+		domain.FormatTime(day.Sunset, use24Hour), planRange(day.BlueEvening, use24Hour))
+}
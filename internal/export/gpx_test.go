@@ -0,0 +1,62 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/geodata"
+)
+
+func TestWaypointsGPX(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.June, day, hour, min, 0, 0, time.UTC)
+	}
+	day := domain.SunTimes{
+		Date:          at(22, 0, 0),
+		Sunrise:       at(22, 5, 47),
+		Sunset:        at(22, 21, 58),
+		GoldenEvening: domain.TimeRange{Start: at(22, 21, 0), End: at(22, 21, 58)},
+	}
+	waypoints := []ShootWaypoint{
+		{Location: testPlaces[0].Location, Days: []domain.SunTimes{day, day}},
+		{Location: domain.Location{Latitude: -33.8568, Longitude: 151.2153}},
+	}
+
+	content, err := WaypointsGPX(waypoints, true, domain.PlaceNameFull, at(20, 14, 5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<gpx version="1.1" creator="GoGoldenHour" xmlns="http://www.topografix.com/GPX/1/1">`,
+		"<time>2026-06-20T14:05:00Z</time>",
+		`<wpt lat="48.8584" lon="2.2945">`,
+		"<ele>35</ele>",
+		"<cmt>Golden hour 21:00 - 21:58</cmt>",
+		"Mon, Jun 22: blue none, sunrise 05:47, golden none, golden 21:00 - 21:58, sunset 21:58, blue none",
+		"<name>33.8568° S, 151.2153° E</name>",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("GPX lacks %s:\n%s", want, content)
+		}
+	}
+
+	// The waypoints import again with their names and positions
+	path := filepath.Join(t.TempDir(), "waypoints.gpx")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := geodata.ParseFile(path)
+	if err != nil {
+		t.Fatalf("ParseFile: %v", err)
+	}
+	if len(data.Points) != 2 {
+		t.Fatalf("got %d points, want 2", len(data.Points))
+	}
+	if p := data.Points[0].Location; p.Name != "Bridge, sunset side" || p.Latitude != 48.8584 || p.Elevation != 35 {
+		t.Errorf("first point %+v, want the bridge", p)
+	}
+}
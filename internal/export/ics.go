@@ -0,0 +1,380 @@
+// Package export writes sun times to file formats understood by other apps.
+//
+// # Watch Calendar (ICS)
+//
+// WatchCalendar produces an iCalendar (RFC 5545) file tuned for smartwatch
+// calendar apps such as Garmin Connect and the Apple Watch calendar: import
+// it into a phone calendar and each golden/blue hour appears as a short
+// event whose reminders buzz on the wrist during a shoot.
+//
+// Watch faces have little room, so the file is deliberately compact:
+//   - Titles are short ("Golden AM", "Blue PM") and fit a watch complication
+//   - Event details go into LOCATION/DESCRIPTION, which watches show on tap,
+//     including the day's total shooting light
+//   - Every event carries two alerts (see alarmLeadTime), preconfigured so
+//     nothing has to be set up on the watch
+//   - Times are written in UTC, which every calendar app handles without
+//     needing VTIMEZONE definitions
+//
+// CalendarEvents builds the same events one calendar object at a time, for
+// sending them straight to a calendar server over CalDAV (see the caldav
+// package).
+//
+// # Add to Calendar
+//
+// PeriodEvent and GoogleCalendarURL build a single golden or blue hour
+// (one of the Period constants), for adding just the period the user
+// picked in the Sun Times panel to their own calendar or Google Calendar.
+//
+// # Daily Summary
+//
+// DailySummary produces a short plain-text overview of a day's golden and
+// blue hours at several places, meant to be read in an email or a text
+// file when planning the next day's shoot (see automation.SummaryScheduler).
+//
+// # Phone Schedule
+//
+// PhoneSchedule produces a one-line-per-day schedule of the coming two
+// weeks at one place, sent as a push notification so the times are at hand
+// on the phone in the field (see automation.SendSchedule).
+//
+// # Day Table
+//
+// DaysCSV and DaysJSON write the sun times of a date range, one row (or
+// object) per day with a choice of columns and time format, for planning a
+// trip's shoots in a spreadsheet or another tool.
+//
+// # Shoot Plan
+//
+// ShootPlanHTML builds the text of a one-page shoot plan, printed as a PDF
+// by the UI below a snapshot of the map.
+//
+// # My Places
+//
+// PlacesGeoJSON and PlacesCSV write the favorites to files that can be
+// imported on another machine (see geodata.ParseFile) or shared with other
+// photographers and their mapping tools.
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// alarmLeadTime is how long before an event the first alert fires.
+	// Enough time to walk to the spot and set up the tripod.
+	alarmLeadTime = 15 * time.Minute
+
+	// icsTimeFormat is the iCalendar UTC date-time format.
+	icsTimeFormat = "20060102T150405Z"
+
+	// icsLineLength is the maximum line length in octets before folding.
+	icsLineLength = 75
+
+	// productID identifies the generating application (PRODID property).
+	productID = "-//GoGoldenHour//Watch Calendar//EN"
+)
+
+// Periods of the calendar events, as used in their UIDs.
+const (
+	PeriodBlueAM   = "blue-am"
+	PeriodGoldenAM = "golden-am"
+	PeriodGoldenPM = "golden-pm"
+	PeriodBluePM   = "blue-pm"
+)
+
+// googleCalendarURL is the address of Google Calendar's event form.
+const googleCalendarURL = "https://calendar.google.com/calendar/render"
+
+// =============================================================================
+// Watch Calendar
+// =============================================================================
+
+// watchEvent is one calendar event derived from a time range.
+type watchEvent struct {
+	// id is a stable identifier used to build the UID (e.g., "golden-am").
+	id string
+
+	// title is the short summary shown on the watch.
+	title string
+
+	// window is the event's time range.
+	window domain.TimeRange
+}
+
+// WatchCalendar builds an ICS calendar with golden and blue hour events.
+//
+// For every day, up to four events are written in chronological order:
+// morning blue hour, morning golden hour, evening golden hour and evening
+// blue hour. Ranges that don't occur on a day (e.g., no blue hour during
+// polar summer) are skipped.
+//
+// UIDs are derived from the date, event and coordinates, so importing an
+// updated export for the same place replaces events instead of duplicating
+// them in calendar apps that honor UIDs.
+//
+// Parameters:
+//   - days: Sun times for each day to include (typically a week)
+//   - use24Hour: Time format for the human-readable event descriptions
+//   - now: Creation timestamp (DTSTAMP), usually time.Now()
+//
+// Returns the calendar as text with CRLF line endings, ready to be written
+// to a .ics file.
+//
+// Example:
+//
+//	ics := export.WatchCalendar(week, settings.TimeFormat24Hour, time.Now())
+//	err := os.WriteFile("golden-hour.ics", []byte(ics), 0o644)
+func WatchCalendar(days []domain.SunTimes, use24Hour bool, now time.Time) string {
+	var sb strings.Builder
+	writeLine(&sb, "BEGIN:VCALENDAR")
+	writeLine(&sb, "VERSION:2.0")
+	writeLine(&sb, "PRODID:"+productID)
+	writeLine(&sb, "CALSCALE:GREGORIAN")
+	writeLine(&sb, "METHOD:PUBLISH")
+	writeLine(&sb, "X-WR-CALNAME:Golden Hour")
+
+	stamp := now.UTC().Format(icsTimeFormat)
+	for _, day := range days {
+		for _, event := range watchEvents(day) {
+			writeEvent(&sb, day, event, use24Hour, stamp)
+		}
+	}
+
+	writeLine(&sb, "END:VCALENDAR")
+	return sb.String()
+}
+
+// CalendarEvent is one watch calendar event as a calendar object of its own,
+// for a CalDAV server (RFC 4791), which stores every event as a separate
+// resource.
+type CalendarEvent struct {
+	// UID is the event's UID, the same as in WatchCalendar. It starts with
+	// the date as "YYYYMMDD" (see domain.CalDAVSync.PruneETags).
+	UID string
+
+	// Title is the short event title (e.g., "Golden PM"), for messages.
+	Title string
+
+	// ICS is a VCALENDAR holding only this event, with CRLF line endings.
+	ICS string
+}
+
+// CalendarEvents builds the events of WatchCalendar one by one, for
+// sending to a calendar server.
+//
+// Each object is a complete calendar with the one event and its alerts.
+// There is no METHOD property, which RFC 4791 forbids in stored calendar
+// objects.
+//
+// Parameters:
+//   - days: Sun times for each day to include
+//   - use24Hour: Time format for the human-readable event descriptions
+//   - now: Creation timestamp (DTSTAMP), usually time.Now()
+func CalendarEvents(days []domain.SunTimes, use24Hour bool, now time.Time) []CalendarEvent {
+	stamp := now.UTC().Format(icsTimeFormat)
+	var events []CalendarEvent
+	for _, day := range days {
+		for _, event := range watchEvents(day) {
+			events = append(events, calendarObject(day, event, use24Hour, stamp))
+		}
+	}
+	return events
+}
+
+// calendarObject builds a calendar holding only the one event.
+func calendarObject(day domain.SunTimes, event watchEvent, use24Hour bool, stamp string) CalendarEvent {
+	var sb strings.Builder
+	writeLine(&sb, "BEGIN:VCALENDAR")
+	writeLine(&sb, "VERSION:2.0")
+	writeLine(&sb, "PRODID:"+productID)
+	writeLine(&sb, "CALSCALE:GREGORIAN")
+	writeEvent(&sb, day, event, use24Hour, stamp)
+	writeLine(&sb, "END:VCALENDAR")
+	return CalendarEvent{UID: eventUID(day, event), Title: event.title, ICS: sb.String()}
+}
+
+// watchEvents returns the day's events in chronological order: morning
+// blue hour, morning golden hour, evening golden hour and evening blue
+// hour. Ranges that don't occur on the day are left out.
+func watchEvents(day domain.SunTimes) []watchEvent {
+	var events []watchEvent
+	for _, event := range []watchEvent{
+		{id: PeriodBlueAM, title: "Blue AM", window: day.BlueMorning},
+		{id: PeriodGoldenAM, title: "Golden AM", window: day.GoldenMorning},
+		{id: PeriodGoldenPM, title: "Golden PM", window: day.GoldenEvening},
+		{id: PeriodBluePM, title: "Blue PM", window: day.BlueEvening},
+	} {
+		if event.window.IsValid() {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// eventUID returns the UID of an event, made of its date, kind and place.
+func eventUID(day domain.SunTimes, event watchEvent) string {
+	loc := day.Location
+	return fmt.Sprintf("%s-%s-%.4f-%.4f@gogoldenhour",
+		day.Date.Format("20060102"), event.id, loc.Latitude, loc.Longitude)
+}
+
+// writeEvent writes one VEVENT with its two alerts.
+func writeEvent(sb *strings.Builder, day domain.SunTimes, event watchEvent, use24Hour bool, stamp string) {
+	loc := day.Location
+	uid := eventUID(day, event)
+	description := eventDescription(day, event, use24Hour)
+
+	writeLine(sb, "BEGIN:VEVENT")
+	writeLine(sb, "UID:"+uid)
+	writeLine(sb, "DTSTAMP:"+stamp)
+	writeLine(sb, "DTSTART:"+event.window.Start.UTC().Format(icsTimeFormat))
+	writeLine(sb, "DTEND:"+event.window.End.UTC().Format(icsTimeFormat))
+	writeLine(sb, "SUMMARY:"+escapeText(event.title))
+	writeLine(sb, "DESCRIPTION:"+escapeText(description))
+	if loc.Name != "" {
+		writeLine(sb, "LOCATION:"+escapeText(loc.Name))
+	}
+	writeLine(sb, fmt.Sprintf("GEO:%.6f;%.6f", loc.Latitude, loc.Longitude))
+	writeLine(sb, "TRANSP:TRANSPARENT")
+
+	// Heads-up before the event, then a second buzz when it starts
+	writeAlarm(sb, event.title, fmt.Sprintf("-PT%dM", int(alarmLeadTime.Minutes())))
+	writeAlarm(sb, event.title, "PT0M")
+
+	writeLine(sb, "END:VEVENT")
+}
+
+// eventDescription returns the details of an event, e.g.,
+// "Golden PM 21:00-21:58 (58 min)\nShooting light today: 2h 6m ...".
+func eventDescription(day domain.SunTimes, event watchEvent, use24Hour bool) string {
+	return fmt.Sprintf("%s %s-%s (%s)\nShooting light today: %s", event.title,
+		domain.FormatTime(event.window.Start, use24Hour),
+		domain.FormatTime(event.window.End, use24Hour),
+		event.window.FormatDuration(), day.FormatShootingWindow())
+}
+
+// writeAlarm writes a display VALARM relative to the event start.
+func writeAlarm(sb *strings.Builder, title, trigger string) {
+	writeLine(sb, "BEGIN:VALARM")
+	writeLine(sb, "ACTION:DISPLAY")
+	writeLine(sb, "DESCRIPTION:"+escapeText(title))
+	writeLine(sb, "TRIGGER:"+trigger)
+	writeLine(sb, "END:VALARM")
+}
+
+// =============================================================================
+// Add to Calendar
+// =============================================================================
+
+// periodEvent returns the day's event of a period, and false if the period
+// is unknown or doesn't occur on the day.
+func periodEvent(day domain.SunTimes, period string) (watchEvent, bool) {
+	for _, event := range watchEvents(day) {
+		if event.id == period {
+			return event, true
+		}
+	}
+	return watchEvent{}, false
+}
+
+// PeriodEvent builds one golden or blue hour of a day as a calendar object,
+// the same as CalendarEvents does for the day, for sending it to a calendar
+// server on its own.
+//
+// Parameters:
+//   - day: The sun times of the day
+//   - period: PeriodBlueAM, PeriodGoldenAM, PeriodGoldenPM or PeriodBluePM
+//   - use24Hour: Time format for the human-readable event description
+//   - now: Creation timestamp (DTSTAMP), usually time.Now()
+//
+// Returns false if the period doesn't occur on the day (e.g., no blue hour
+// during polar summer).
+func PeriodEvent(day domain.SunTimes, period string, use24Hour bool, now time.Time) (CalendarEvent, bool) {
+	event, ok := periodEvent(day, period)
+	if !ok {
+		return CalendarEvent{}, false
+	}
+	return calendarObject(day, event, use24Hour, now.UTC().Format(icsTimeFormat)), true
+}
+
+// GoogleCalendarURL returns the address of Google Calendar's event form,
+// prefilled with one golden or blue hour of a day:
+//
+//	https://calendar.google.com/calendar/render?action=TEMPLATE
+//	    &text=Golden+PM&dates=20260621T190000Z/20260621T195800Z
+//	    &details=Golden+PM+21:00-21:58+(58+min)...&location=Paris,+France
+//
+// Opened in the browser, the form lets the user check the event and pick a
+// calendar before saving it. The
+// location is the place's name, or its coordinates if it has none, which
+// Google Maps understands too.
+//
+// Parameters:
+//   - day, period, use24Hour: As for PeriodEvent
+//
+// Returns false if the period doesn't occur on the day.
+func GoogleCalendarURL(day domain.SunTimes, period string, use24Hour bool) (string, bool) {
+	event, ok := periodEvent(day, period)
+	if !ok {
+		return "", false
+	}
+	loc := day.Location
+	place := loc.Name
+	if place == "" {
+		place = fmt.Sprintf("%.6f,%.6f", loc.Latitude, loc.Longitude)
+	}
+	query := url.Values{
+		"action":   {"TEMPLATE"},
+		"text":     {event.title},
+		"dates":    {event.window.Start.UTC().Format(icsTimeFormat) + "/" + event.window.End.UTC().Format(icsTimeFormat)},
+		"details":  {eventDescription(day, event, use24Hour)},
+		"location": {place},
+	}
+	return googleCalendarURL + "?" + query.Encode(), true
+}
+
+// =============================================================================
+// iCalendar Formatting Helpers
+// =============================================================================
+
+// escapeText escapes a TEXT property value (RFC 5545 §3.3.11).
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// writeLine writes a content line, folding it at icsLineLength octets.
+//
+// Continuation lines start with a single space. Folding never splits a
+// multi-byte UTF-8 character, as required by RFC 5545 §3.1.
+func writeLine(sb *strings.Builder, line string) {
+	limit := icsLineLength
+	for len(line) > limit {
+		cut := limit
+		// Back up to the start of a UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		sb.WriteString(line[:cut])
+		sb.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines lose one octet to the leading space
+		limit = icsLineLength - 1
+	}
+	sb.WriteString(line)
+	sb.WriteString("\r\n")
+}
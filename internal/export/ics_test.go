@@ -0,0 +1,220 @@
+package export
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"Golden AM", "Golden AM"},
+		{"Paris, France", `Paris\, France`},
+		{"a;b", `a\;b`},
+		{`C:\photos`, `C:\\photos`},
+		{"line 1\nline 2", `line 1\nline 2`},
+		{`\,;`, `\\\,\;`},
+		{"Zürich · 06:45", "Zürich · 06:45"},
+	}
+
+	for _, tt := range tests {
+		if got := escapeText(tt.input); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// unfold reverses RFC 5545 line folding.
+func unfold(s string) string {
+	return strings.ReplaceAll(s, "\r\n ", "")
+}
+
+func TestWriteLine(t *testing.T) {
+	tests := []struct {
+		name      string
+		line      string
+		wantLines int
+	}{
+		{"short", "SUMMARY:Golden AM", 1},
+		{"exactly the limit", strings.Repeat("a", icsLineLength), 1},
+		{"one octet over", strings.Repeat("a", icsLineLength+1), 2},
+		{"several folds", strings.Repeat("b", 3*icsLineLength), 4},
+		{"multi-byte at the fold", strings.Repeat("a", icsLineLength-1) + "äöü", 2},
+		{"all multi-byte", "LOCATION:" + strings.Repeat("日本", 40), 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var sb strings.Builder
+			writeLine(&sb, tt.line)
+			out := sb.String()
+
+			if !strings.HasSuffix(out, "\r\n") {
+				t.Fatalf("output %q lacks CRLF", out)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+			if len(lines) != tt.wantLines {
+				t.Errorf("got %d physical lines, want %d", len(lines), tt.wantLines)
+			}
+			for i, l := range lines {
+				if len(l) > icsLineLength {
+					t.Errorf("line %d has %d octets", i, len(l))
+				}
+				if i > 0 && !strings.HasPrefix(l, " ") {
+					t.Errorf("continuation line %d doesn't start with a space", i)
+				}
+				if !utf8.ValidString(l) {
+					t.Errorf("line %d splits a UTF-8 character: %q", i, l)
+				}
+			}
+			if got := strings.TrimSuffix(unfold(out), "\r\n"); got != tt.line {
+				t.Errorf("unfolded line = %q, want %q", got, tt.line)
+			}
+		})
+	}
+}
+
+func TestWatchCalendar(t *testing.T) {
+	tz := time.FixedZone("CEST", 2*3600)
+	at := func(h, m int) time.Time { return time.Date(2026, 6, 21, h, m, 0, 0, tz) }
+	day := domain.SunTimes{
+		Date:          time.Date(2026, 6, 21, 0, 0, 0, 0, tz),
+		Location:      domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris, France"},
+		BlueMorning:   domain.TimeRange{Start: at(5, 10), End: at(5, 35)},
+		GoldenMorning: domain.TimeRange{Start: at(5, 47), End: at(6, 30)},
+		GoldenEvening: domain.TimeRange{Start: at(21, 0), End: at(21, 58)},
+		// No evening blue hour on this day
+	}
+	now := time.Date(2026, 6, 20, 12, 0, 0, 0, time.UTC)
+
+	ics := WatchCalendar([]domain.SunTimes{day}, true, now)
+
+	if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+		t.Fatalf("calendar is not wrapped in VCALENDAR:\n%s", ics)
+	}
+	if strings.Contains(strings.ReplaceAll(ics, "\r\n", ""), "\n") {
+		t.Error("calendar contains bare LF line endings")
+	}
+
+	unfolded := unfold(ics)
+	for _, want := range []string{
+		"UID:20260621-blue-am-48.8566-2.3522@gogoldenhour\r\n",
+		"SUMMARY:Golden PM\r\n",
+		"DTSTART:20260621T190000Z\r\n", // 21:00 CEST in UTC
+		"DTEND:20260621T195800Z\r\n",
+		"DTSTAMP:20260620T120000Z\r\n",
+		`LOCATION:Paris\, France` + "\r\n",
+		"GEO:48.856600;2.352200\r\n",
+		"TRIGGER:-PT15M\r\n",
+		"TRIGGER:PT0M\r\n",
+		`DESCRIPTION:Golden AM 05:47-06:30 (43 min)\nShooting light today: 2h 6m (9% of the day)` + "\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("calendar lacks %q", strings.TrimSpace(want))
+		}
+	}
+
+	if n := strings.Count(ics, "BEGIN:VEVENT"); n != 3 {
+		t.Errorf("got %d events, want 3 (invalid ranges are skipped)", n)
+	}
+	if n := strings.Count(ics, "BEGIN:VALARM"); n != 6 {
+		t.Errorf("got %d alarms, want 2 per event", n)
+	}
+	if strings.Contains(ics, "Blue PM") {
+		t.Error("calendar contains the missing evening blue hour")
+	}
+
+	// Events appear in chronological order
+	order := []string{"Blue AM", "Golden AM", "Golden PM"}
+	last := -1
+	for _, title := range order {
+		i := strings.Index(ics, "SUMMARY:"+title)
+		if i < last {
+			t.Errorf("%s is out of order", title)
+		}
+		last = i
+	}
+}
+
+func TestCalendarEvents(t *testing.T) {
+	tz := time.FixedZone("CEST", 2*3600)
+	at := func(h, m int) time.Time { return time.Date(2026, 6, 21, h, m, 0, 0, tz) }
+	day := domain.SunTimes{
+		Date:          time.Date(2026, 6, 21, 0, 0, 0, 0, tz),
+		Location:      domain.Location{Latitude: 48.8566, Longitude: 2.3522},
+		GoldenMorning: domain.TimeRange{Start: at(5, 47), End: at(6, 30)},
+		GoldenEvening: domain.TimeRange{Start: at(21, 0), End: at(21, 58)},
+	}
+	now := time.Date(2026, 6, 20, 12, 0, 0, 0, time.UTC)
+
+	events := CalendarEvents([]domain.SunTimes{day}, true, now)
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2", len(events))
+	}
+	event := events[1]
+	if event.UID != "20260621-golden-pm-48.8566-2.3522@gogoldenhour" || event.Title != "Golden PM" {
+		t.Errorf("event = %q (%s)", event.UID, event.Title)
+	}
+	if !strings.HasPrefix(event.ICS, "BEGIN:VCALENDAR\r\n") || strings.Count(event.ICS, "BEGIN:VEVENT") != 1 {
+		t.Errorf("event isn't a calendar with one event:\n%s", event.ICS)
+	}
+	if strings.Contains(event.ICS, "METHOD:") {
+		t.Error("calendar object has a METHOD, which CalDAV servers reject")
+	}
+	if !strings.Contains(event.ICS, "UID:"+event.UID+"\r\n") {
+		t.Errorf("calendar object lacks its UID:\n%s", event.ICS)
+	}
+}
+
+func TestAddToCalendar(t *testing.T) {
+	tz := time.FixedZone("CEST", 2*3600)
+	at := func(h, m int) time.Time { return time.Date(2026, 6, 21, h, m, 0, 0, tz) }
+	day := domain.SunTimes{
+		Date:          time.Date(2026, 6, 21, 0, 0, 0, 0, tz),
+		Location:      domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris, France"},
+		GoldenEvening: domain.TimeRange{Start: at(21, 0), End: at(21, 58)},
+	}
+	now := time.Date(2026, 6, 20, 12, 0, 0, 0, time.UTC)
+
+	event, ok := PeriodEvent(day, PeriodGoldenPM, true, now)
+	if !ok || event.UID != "20260621-golden-pm-48.8566-2.3522@gogoldenhour" ||
+		strings.Count(event.ICS, "BEGIN:VEVENT") != 1 {
+		t.Errorf("PeriodEvent = %q, %v", event.UID, ok)
+	}
+	if _, ok := PeriodEvent(day, PeriodBluePM, true, now); ok {
+		t.Error("PeriodEvent built the missing evening blue hour")
+	}
+
+	link, ok := GoogleCalendarURL(day, PeriodGoldenPM, true)
+	if !ok {
+		t.Fatal("no Google Calendar link")
+	}
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if parsed.Host != "calendar.google.com" || query.Get("action") != "TEMPLATE" ||
+		query.Get("text") != "Golden PM" || query.Get("location") != "Paris, France" {
+		t.Errorf("link = %s", link)
+	}
+	if got := query.Get("dates"); got != "20260621T190000Z/20260621T195800Z" {
+		t.Errorf("dates = %q", got)
+	}
+	if got := query.Get("details"); !strings.HasPrefix(got, "Golden PM 21:00-21:58 (58 min)\n") {
+		t.Errorf("details = %q", got)
+	}
+
+	// Without a name, the place is given by its coordinates
+	day.Location.Name = ""
+	link, _ = GoogleCalendarURL(day, PeriodGoldenPM, true)
+	if !strings.Contains(link, "location=48.856600%2C2.352200") {
+		t.Errorf("link without a place name = %s", link)
+	}
+}
@@ -0,0 +1,110 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// My Places
+// =============================================================================
+
+// placeFeature is a GeoJSON Feature of an exported favorite.
+type placeFeature struct {
+	Type     string `json:"type"`
+	Geometry struct {
+		Type        string    `json:"type"`
+		Coordinates []float64 `json:"coordinates"`
+	} `json:"geometry"`
+	Properties placeProperties `json:"properties"`
+}
+
+// placeProperties are the properties of an exported favorite. "name" is
+// what geodata and most GIS tools read; the rest is kept for reference.
+type placeProperties struct {
+	Name     string `json:"name"`
+	Timezone string `json:"timezone,omitempty"`
+	City     string `json:"city,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Country  string `json:"country,omitempty"`
+}
+
+// PlacesGeoJSON writes favorites as a GeoJSON (RFC 7946) FeatureCollection
+// of points, one Feature per favorite.
+//
+// Positions are [longitude, latitude], with the elevation as a third value
+// when it is known. The file can be imported again with geodata.ParseFile,
+// and opened in GIS tools, Google Earth or hiking apps.
+//
+// Example feature:
+//
+//	{"type": "Feature",
+//	 "geometry": {"type": "Point", "coordinates": [2.2945, 48.8584, 35]},
+//	 "properties": {"name": "Eiffel Tower", "timezone": "Europe/Paris"}}
+func PlacesGeoJSON(favorites []domain.Favorite) ([]byte, error) {
+	collection := struct {
+		Type     string         `json:"type"`
+		Features []placeFeature `json:"features"`
+	}{Type: "FeatureCollection", Features: make([]placeFeature, 0, len(favorites))}
+
+	for _, f := range favorites {
+		loc := f.Location
+		feature := placeFeature{Type: "Feature"}
+		feature.Geometry.Type = "Point"
+		feature.Geometry.Coordinates = []float64{loc.Longitude, loc.Latitude}
+		if loc.Elevation != 0 {
+			feature.Geometry.Coordinates = append(feature.Geometry.Coordinates, loc.Elevation)
+		}
+		feature.Properties = placeProperties{
+			Name:     loc.Name,
+			Timezone: loc.Timezone,
+			City:     loc.City,
+			Region:   loc.Region,
+			Country:  loc.Country,
+		}
+		collection.Features = append(collection.Features, feature)
+	}
+
+	content, err := json.MarshalIndent(collection, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode places: %w", err)
+	}
+	return append(content, '\n'), nil
+}
+
+// PlacesCSV writes favorites as a CSV table with a header row:
+//
+//	name,latitude,longitude,elevation,timezone
+//	Eiffel Tower,48.8584,2.2945,35,Europe/Paris
+//
+// Spreadsheets open it directly, and geodata.ParseFile imports it again.
+// The elevation cell is empty when unknown.
+func PlacesCSV(favorites []domain.Favorite) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"name", "latitude", "longitude", "elevation", "timezone"})
+	for _, f := range favorites {
+		loc := f.Location
+		elevation := ""
+		if loc.Elevation != 0 {
+			elevation = strconv.FormatFloat(loc.Elevation, 'f', -1, 64)
+		}
+		_ = w.Write([]string{
+			loc.Name,
+			strconv.FormatFloat(loc.Latitude, 'f', -1, 64),
+			strconv.FormatFloat(loc.Longitude, 'f', -1, 64),
+			elevation,
+			loc.Timezone,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("failed to encode places: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/geodata"
+)
+
+// testPlaces are favorites with and without an elevation, and a name that
+// needs quoting in CSV.
+var testPlaces = []domain.Favorite{
+	{ID: "a", Location: domain.Location{Latitude: 48.8584, Longitude: 2.2945, Elevation: 35,
+		Name: "Bridge, sunset side", Timezone: "Europe/Paris", City: "Paris"}},
+	{ID: "b", Location: domain.Location{Latitude: -33.8568, Longitude: 151.2153,
+		Name: "Opera House", Timezone: "Australia/Sydney"}},
+}
+
+func TestPlacesCSV(t *testing.T) {
+	content, err := PlacesCSV(testPlaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name,latitude,longitude,elevation,timezone\n" +
+		"\"Bridge, sunset side\",48.8584,2.2945,35,Europe/Paris\n" +
+		"Opera House,-33.8568,151.2153,,Australia/Sydney\n"
+	if string(content) != want {
+		t.Errorf("PlacesCSV =\n%s\nwant\n%s", content, want)
+	}
+}
+
+func TestPlacesGeoJSON(t *testing.T) {
+	content, err := PlacesGeoJSON(testPlaces)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"type": "FeatureCollection"`, `"timezone": "Europe/Paris"`, `"city": "Paris"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("PlacesGeoJSON lacks %s:\n%s", want, content)
+		}
+	}
+}
+
+// TestPlacesRoundTrip checks that exported places import again with their
+// names, coordinates and elevations.
+func TestPlacesRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	for name, write := range map[string]func([]domain.Favorite) ([]byte, error){
+		"places.geojson": PlacesGeoJSON,
+		"places.csv":     PlacesCSV,
+	} {
+		t.Run(name, func(t *testing.T) {
+			content, err := write(testPlaces)
+			if err != nil {
+				t.Fatal(err)
+			}
+			path := filepath.Join(dir, name)
+			if err := os.WriteFile(path, content, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			data, err := geodata.ParseFile(path)
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			if len(data.Points) != len(testPlaces) {
+				t.Fatalf("got %d points, want %d", len(data.Points), len(testPlaces))
+			}
+			for i, p := range data.Points {
+				want := testPlaces[i].Location
+				got := p.Location
+				if got.Name != want.Name || got.Latitude != want.Latitude ||
+					got.Longitude != want.Longitude || got.Elevation != want.Elevation {
+					t.Errorf("point %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
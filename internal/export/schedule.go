@@ -0,0 +1,60 @@
+package export
+
+import (
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Phone Schedule
+// =============================================================================
+
+// PhoneSchedule builds a compact schedule of the coming days' light at one
+// place, short enough for a push notification.
+//
+// Each day takes one line with the start times of the golden and blue
+// hours, morning and evening:
+//
+//	Paris, France
+//	Mon Jun 22  golden 05:47 21:00  blue 05:10 21:58
+//	Tue Jun 23  golden 05:47 21:00  blue 05:11 21:58
+//
+// Mornings come first within each pair. Periods that don't occur are shown
+// as "--:--". Fourteen days stay well within Pushover's 1024 characters.
+//
+// Parameters:
+//   - days: Sun times for each day, in order (normally
+//     domain.PushScheduleDays)
+//   - use24Hour: Time format
+//   - nameStyle: How much of the place name to show
+//     (domain.Settings.PlaceNameStyle)
+//
+// Returns the notification title and the body text.
+func PhoneSchedule(days []domain.SunTimes, use24Hour bool, nameStyle string) (title, body string) {
+	title = "Golden hour schedule"
+	if len(days) == 0 {
+		return title, "No days to show."
+	}
+
+	var sb strings.Builder
+	loc := days[0].Location
+	name := loc.DisplayName(nameStyle)
+	if name == "" {
+		name = domain.FormatCoordinates(loc.Latitude, loc.Longitude)
+	}
+	sb.WriteString(name)
+
+	start := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return "--:--"
+		}
+		return domain.FormatTime(tr.Start, use24Hour)
+	}
+	for _, day := range days {
+		sb.WriteString("\n" + day.Date.Format("Mon Jan 2") +
+			"  golden " + start(day.GoldenMorning) + " " + start(day.GoldenEvening) +
+			"  blue " + start(day.BlueMorning) + " " + start(day.BlueEvening))
+	}
+	return title, sb.String()
+}
@@ -0,0 +1,108 @@
+package export
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Shoot Plan
+// =============================================================================
+
+// ShootPlanMaxDays is the most days a shoot plan lists. The plan is one
+// printed page; longer ranges are better exported as a day table (see
+// DaysCSV).
+const ShootPlanMaxDays = 14
+
+// ShootPlanHTML builds the text of a printable shoot plan: the location's
+// details and, for each day, its sunrise, sunset, golden and blue hours.
+//
+// The result is simple HTML for a QTextDocument (no CSS beyond what Qt's
+// rich text supports), which the UI prints below a snapshot of the map:
+//
+//	Shoot Plan: Riverside Bridge
+//	48.8566° N, 2.3522° E · Europe/Paris · 35 m
+//	┌────────────┬─────────┬────────┬──────────────┬──────────────┬─────
+//	│ Date       │ Sunrise │ Sunset │ Golden AM    │ Golden PM    │ ...
+//	├────────────┼─────────┼────────┼──────────────┼──────────────┼─────
+//	│ Mon, Jun 22│ 05:47   │ 21:58  │ 05:47 - 06:30│ 21:00 - 21:58│ ...
+//	└────────────┴─────────┴────────┴──────────────┴──────────────┴─────
+//	Generated by GoGoldenHour on June 20, 2026 at 14:05. Times are local
+//	to the location.
+//
+// Periods that don't occur are shown as "none". Days after
+// ShootPlanMaxDays are left out, with a note saying how many.
+//
+// Parameters:
+//   - loc: The location of the shoot
+//   - days: Sun times for each day, in order (see solar.CalculateRange)
+//   - use24Hour: Time format
+//   - coordinateFormat: Format of the coordinates
+//     (domain.Settings.CoordinateFormat)
+//   - nameStyle: How much of the place name to show
+//     (domain.Settings.PlaceNameStyle)
+//   - now: When the plan is generated, for the footer
+func ShootPlanHTML(loc domain.Location, days []domain.SunTimes, use24Hour bool, coordinateFormat, nameStyle string, now time.Time) string {
+	coordinates := domain.FormatCoordinatesIn(loc.Latitude, loc.Longitude, coordinateFormat)
+	name := loc.DisplayName(nameStyle)
+	if name == "" {
+		name = coordinates
+	}
+	details := []string{coordinates}
+	if loc.Timezone != "" {
+		details = append(details, loc.Timezone)
+	}
+	if loc.Elevation != 0 {
+		details = append(details, fmt.Sprintf("%.0f m", loc.Elevation))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "<h2>Shoot Plan: %s</h2>\n", html.EscapeString(name))
+	fmt.Fprintf(&sb, "<p>%s</p>\n", html.EscapeString(strings.Join(details, " · ")))
+
+	listed := days[:min(len(days), ShootPlanMaxDays)]
+	if len(listed) == 0 {
+		sb.WriteString("<p>No days to plan.</p>\n")
+	} else {
+		sb.WriteString("<table border=\"1\" cellspacing=\"0\" cellpadding=\"4\" width=\"100%\">\n")
+		sb.WriteString("<tr><th align=\"left\">Date</th><th>Sunrise</th><th>Sunset</th>" +
+			"<th>Blue AM</th><th>Golden AM</th><th>Golden PM</th><th>Blue PM</th><th>Light</th></tr>\n")
+		for _, day := range listed {
+			fmt.Fprintf(&sb, "<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>\n",
+				day.Date.Format("Mon, Jan 2"),
+				domain.FormatTime(day.Sunrise, use24Hour), domain.FormatTime(day.Sunset, use24Hour),
+				planRange(day.BlueMorning, use24Hour), planRange(day.GoldenMorning, use24Hour),
+				planRange(day.GoldenEvening, use24Hour), planRange(day.BlueEvening, use24Hour),
+				html.EscapeString(planLight(day)))
+		}
+		sb.WriteString("</table>\n")
+	}
+	if more := len(days) - len(listed); more > 0 {
+		fmt.Fprintf(&sb, "<p><i>%d more days are not listed; export the date range for all of them.</i></p>\n", more)
+	}
+
+	fmt.Fprintf(&sb, "<p><small>Generated by GoGoldenHour on %s at %s. Times are local to the location.</small></p>\n",
+		now.Format("January 2, 2006"), domain.FormatTime(now, use24Hour))
+	return sb.String()
+}
+
+// planRange formats a period as "start - end", or "none" if it doesn't
+// occur.
+func planRange(tr domain.TimeRange, use24Hour bool) string {
+	if !tr.IsValid() {
+		return "none"
+	}
+	return domain.FormatTime(tr.Start, use24Hour) + " - " + domain.FormatTime(tr.End, use24Hour)
+}
+
+// planLight formats the day's shooting light, or "none".
+func planLight(day domain.SunTimes) string {
+	if window := day.ShootingWindow(); window > 0 {
+		return domain.FormatDuration(window)
+	}
+	return "none"
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestShootPlanHTML(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.June, day, hour, min, 0, 0, time.UTC)
+	}
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Bridge <North>", Timezone: "Europe/Paris", Elevation: 35}
+	day := domain.SunTimes{
+		Date:          at(22, 0, 0),
+		Sunrise:       at(22, 5, 47),
+		Sunset:        at(22, 21, 58),
+		GoldenEvening: domain.TimeRange{Start: at(22, 21, 0), End: at(22, 21, 58)},
+	}
+
+	plan := ShootPlanHTML(loc, []domain.SunTimes{day}, true, domain.CoordinateFormatDecimal, domain.PlaceNameFull, at(20, 14, 5))
+	for _, want := range []string{
+		"Shoot Plan: Bridge &lt;North&gt;",
+		"Europe/Paris · 35 m",
+		"<td>Mon, Jun 22</td><td>05:47</td><td>21:58</td><td>none</td><td>none</td><td>21:00 - 21:58</td>",
+		"on June 20, 2026 at 14:05",
+	} {
+		if !strings.Contains(plan, want) {
+			t.Errorf("plan lacks %q:\n%s", want, plan)
+		}
+	}
+	if strings.Contains(plan, "more days") {
+		t.Error("a one-day plan notes more days")
+	}
+
+	// Long ranges are cut to one page
+	days := make([]domain.SunTimes, ShootPlanMaxDays+3)
+	for i := range days {
+		days[i] = domain.SunTimes{Date: at(1+i, 0, 0)}
+	}
+	plan = ShootPlanHTML(loc, days, true, domain.CoordinateFormatDecimal, domain.PlaceNameFull, at(1, 9, 0))
+	if rows := strings.Count(plan, "<tr>") - 1; rows != ShootPlanMaxDays {
+		t.Errorf("plan lists %d days, want %d", rows, ShootPlanMaxDays)
+	}
+	if !strings.Contains(plan, "3 more days") {
+		t.Errorf("plan doesn't note the days left out:\n%s", plan)
+	}
+}
@@ -0,0 +1,81 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Daily Summary
+// =============================================================================
+
+// DailySummary builds the plain-text summary of a day's light at several places.
+//
+// Each place gets a short block with its golden and blue hours, morning and
+// evening, in the place's own time zone:
+//
+//	Riverside Bridge (Europe/Paris)
+//	  Morning  blue 05:12-05:38  golden 05:52-06:41
+//	  Evening  golden 21:02-21:51  blue 22:05-22:31
+//	  Sunrise 05:52, sunset 21:51
+//	  Shooting light 2h 16m (9% of the day)
+//
+// Periods that don't occur (e.g., no blue hour during polar summer) are
+// shown as "none".
+//
+// Parameters:
+//   - date: The day summarized, used in the subject and heading
+//   - days: Sun times for that day at each place, in display order
+//   - use24Hour: Time format
+//   - coordinateFormat: Format of the coordinates shown for places without
+//     a name (domain.Settings.CoordinateFormat)
+//   - nameStyle: How much of the place names to show
+//     (domain.Settings.PlaceNameStyle)
+//
+// Returns the subject line (used as the email subject) and the body text.
+//
+// Example:
+//
+//	subject, body := export.DailySummary(tomorrow, days,
+//	    settings.TimeFormat24Hour, settings.CoordinateFormat, settings.PlaceNameStyle)
+func DailySummary(date time.Time, days []domain.SunTimes, use24Hour bool, coordinateFormat, nameStyle string) (subject, body string) {
+	heading := date.Format("Monday, January 2, 2006")
+	subject = "Golden hour for " + heading
+
+	var sb strings.Builder
+	sb.WriteString("Golden and blue hours for " + heading + "\n")
+	if len(days) == 0 {
+		sb.WriteString("\nNo favorites are selected for the daily summary.\n")
+		return subject, sb.String()
+	}
+
+	for _, day := range days {
+		name := day.Location.DisplayName(nameStyle)
+		if name == "" {
+			name = domain.FormatCoordinatesIn(day.Location.Latitude, day.Location.Longitude, coordinateFormat)
+		}
+		if day.Location.Timezone != "" {
+			name += " (" + day.Location.Timezone + ")"
+		}
+		fmt.Fprintf(&sb, "\n%s\n", name)
+		fmt.Fprintf(&sb, "  Morning  blue %s  golden %s\n",
+			summaryRange(day.BlueMorning, use24Hour), summaryRange(day.GoldenMorning, use24Hour))
+		fmt.Fprintf(&sb, "  Evening  golden %s  blue %s\n",
+			summaryRange(day.GoldenEvening, use24Hour), summaryRange(day.BlueEvening, use24Hour))
+		fmt.Fprintf(&sb, "  Sunrise %s, sunset %s\n",
+			domain.FormatTime(day.Sunrise, use24Hour), domain.FormatTime(day.Sunset, use24Hour))
+		fmt.Fprintf(&sb, "  Shooting light %s\n", day.FormatShootingWindow())
+	}
+	return subject, sb.String()
+}
+
+// summaryRange formats a period as "start-end", or "none" if it doesn't occur.
+func summaryRange(tr domain.TimeRange, use24Hour bool) string {
+	if !tr.IsValid() {
+		return "none"
+	}
+	return domain.FormatTime(tr.Start, use24Hour) + "-" + domain.FormatTime(tr.End, use24Hour)
+}
@@ -0,0 +1,89 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestDailySummary(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	at := func(hour, min int) time.Time {
+		return time.Date(2026, time.May, 28, hour, min, 0, 0, paris)
+	}
+	day := domain.SunTimes{
+		Date:          at(0, 0),
+		Location:      domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Riverside Bridge", Timezone: "Europe/Paris"},
+		Sunrise:       at(5, 52),
+		Sunset:        at(21, 51),
+		BlueMorning:   domain.TimeRange{Start: at(5, 12), End: at(5, 38)},
+		GoldenMorning: domain.TimeRange{Start: at(5, 52), End: at(6, 41)},
+		GoldenEvening: domain.TimeRange{Start: at(21, 2), End: at(21, 51)},
+		// No evening blue hour, as in polar summer
+	}
+
+	subject, body := DailySummary(day.Date, []domain.SunTimes{day}, true, domain.CoordinateFormatDecimal, domain.PlaceNameFull)
+	if want := "Golden hour for Thursday, May 28, 2026"; subject != want {
+		t.Errorf("subject = %q, want %q", subject, want)
+	}
+	for _, want := range []string{
+		"Golden and blue hours for Thursday, May 28, 2026\n",
+		"\nRiverside Bridge (Europe/Paris)\n",
+		"  Morning  blue 05:12-05:38  golden 05:52-06:41\n",
+		"  Evening  golden 21:02-21:51  blue none\n",
+		"  Sunrise 05:52, sunset 21:51\n",
+		"  Shooting light 2h 4m (9% of the day)\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body lacks %q:\n%s", want, body)
+		}
+	}
+
+	// Unnamed places are identified by their coordinates
+	day.Location.Name = ""
+	if _, body := DailySummary(day.Date, []domain.SunTimes{day}, false, domain.CoordinateFormatDecimal, domain.PlaceNameFull); !strings.Contains(body, "48.8566° N, 2.3522° E") {
+		t.Errorf("unnamed place not shown by coordinates:\n%s", body)
+	}
+	if _, body := DailySummary(day.Date, []domain.SunTimes{day}, false, domain.CoordinateFormatDMS, domain.PlaceNameFull); !strings.Contains(body, `48°51'23.8" N, 2°21'07.9" E`) {
+		t.Errorf("unnamed place not shown in DMS:\n%s", body)
+	}
+
+	if _, body := DailySummary(day.Date, nil, true, domain.CoordinateFormatDecimal, domain.PlaceNameFull); !strings.Contains(body, "No favorites") {
+		t.Errorf("empty summary doesn't say so:\n%s", body)
+	}
+}
+
+func TestPhoneSchedule(t *testing.T) {
+	paris, _ := time.LoadLocation("Europe/Paris")
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, time.June, day, hour, min, 0, 0, paris)
+	}
+	var days []domain.SunTimes
+	for d := 22; d < 22+domain.PushScheduleDays; d++ {
+		days = append(days, domain.SunTimes{
+			Date:          at(d, 0, 0),
+			Location:      domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris, France"},
+			BlueMorning:   domain.TimeRange{Start: at(d, 5, 10), End: at(d, 5, 35)},
+			GoldenMorning: domain.TimeRange{Start: at(d, 5, 47), End: at(d, 6, 30)},
+			GoldenEvening: domain.TimeRange{Start: at(d, 21, 0), End: at(d, 21, 58)},
+		})
+	}
+
+	title, body := PhoneSchedule(days, true, domain.PlaceNameFull)
+	if title == "" {
+		t.Error("empty title")
+	}
+	lines := strings.Split(body, "\n")
+	if len(lines) != 1+domain.PushScheduleDays || lines[0] != "Paris, France" {
+		t.Fatalf("schedule has %d lines, first %q:\n%s", len(lines), lines[0], body)
+	}
+	if want := "Mon Jun 22  golden 05:47 21:00  blue 05:10 --:--"; lines[1] != want {
+		t.Errorf("first day = %q, want %q", lines[1], want)
+	}
+	// Pushover limits messages to 1024 characters
+	if _, body := PhoneSchedule(days, false, domain.PlaceNameFull); len([]rune(body)) > 1024 {
+		t.Errorf("12-hour schedule is %d characters long", len([]rune(body)))
+	}
+}
@@ -0,0 +1,667 @@
+// Package frontend defines how the application controller (app.App) and
+// its user interfaces talk to each other, so the same controller and
+// services can drive more than one frontend.
+//
+// # Architecture
+//
+// A frontend reports user actions through the Controller interface, and the
+// App updates the display through the View interface and, for the changes
+// of location, date and sun times, through the event bus its widgets
+// subscribe to (Controller.Events, see the events package):
+//
+//	┌──────────────────┐   ┌──────────────────┐
+//	│ ui.MainWindow    │   │ tui.Terminal     │
+//	│ (Qt, graphical)  │   │ (SSH, headless)  │
+//	└───┬──────────▲───┘   └───┬──────────▲───┘
+//	    │ Controller│ View     │ Controller│ View
+//	    ▼          │           ▼          │
+//	┌─────────────────────────────────────────┐
+//	│                  App                    │
+//	│              (Controller)               │
+//	└─────────────────────────────────────────┘
+//
+// One frontend runs at a time; main picks it (--tui for the terminal UI)
+// and passes its Factory to app.New.
+//
+// # Thread Safety
+//
+// Each frontend has a main thread (the Qt event loop, the terminal UI's
+// event loop) on which all Controller and View calls happen. The App's
+// goroutines hand their results over with View.RunOnMainThread, so this
+// package and the App don't depend on a particular toolkit.
+package frontend
+
+import (
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/changelog"
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/events"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/geodata"
+	"github.com/megatih/GoGoldenHour/internal/service/automation"
+	"github.com/megatih/GoGoldenHour/internal/service/caldav"
+	"github.com/megatih/GoGoldenHour/internal/service/updates"
+	"github.com/megatih/GoGoldenHour/internal/storage"
+)
+
+// =============================================================================
+// Controller Interface
+// =============================================================================
+
+// Controller defines the interface for application control.
+//
+// This interface allows a frontend (the Qt MainWindow or the terminal UI)
+// to communicate user actions to the application controller without
+// knowing the concrete implementation. It's implemented by app.App.
+//
+// The interface includes:
+//   - Action methods: DetectLocation, CancelDetection, SearchLocation,
+//     CancelSearch, OnMapClick, OnMapLocate, SelectMapPoint
+//   - Update methods: UpdateLocation, UpdateDate, UpdateDateRange, UpdateSettings,
+//     UpdateMapZoom, UpdatePanelLayout, UpdateWindowLayout, UpdatePrivacyMode,
+//     UpdateMinimizeToTray, UpdateAuroraForecast, RefreshCountdown,
+//     RestoreSettings
+//   - Query methods: GetSettings, GetLocation, GetDate, Events
+//   - Sharing methods: ExportConfigCode, ImportConfigCode, ShareLink,
+//     OpenLinkText, RegisterLinks, ExportConfiguration, ReadConfiguration,
+//     ImportConfiguration
+//   - Application methods: OpenProfile, CheckForUpdates, EndSession
+//   - Planning methods: FindSunAlignments, HorizonEvents, MonthSunTimes, MonthMeteorNights,
+//     FetchWeekOutlook, FetchElevationProfile, FetchCloudFrames, ImportMapData, ExportWatchCalendar, SyncCalendar,
+//     AddToCalendar, GoogleCalendarURL,
+//     ExportDateRange, DateRangeText, ShootPlanHTML, ExportWaypoints, CopyTimesText,
+//     ImportFavorites, ExportFavorites, AnalyzePhoto
+//   - Automation methods: UpdateAutomation, TestHook, UpdateDailySummary,
+//     SendSummaryNow, UpdateWidgetFeed, WriteFeedNow, UpdatePush, SendPushSchedule, UpdateWebhook, TestWebhook,
+//     UpdateNotifications, TestNotification, UpdateCalDAV, PauseNotifications
+//   - Favorites methods: ToggleFavorite, UpdateFavoriteOverrides,
+//     ClearRecentLocations
+//   - Preset methods: SelectPreset, SavePresetAs, DeletePreset,
+//     UpdateCustomEvents
+//
+// This interface enables:
+//   - Loose coupling between UI and application logic
+//   - Easier testing (can mock the controller)
+//   - Clear contract for UI-to-app communication
+type Controller interface {
+	// DetectLocation initiates IP-based location detection.
+	// Called when user clicks "Detect My Location" button.
+	DetectLocation()
+
+	// CancelDetection stops the running location detection.
+	// Called when user clicks the Detect button while it is detecting.
+	CancelDetection()
+
+	// UpdateLocation changes the current location.
+	// Called after search results or map clicks.
+	UpdateLocation(loc domain.Location)
+
+	// UpdateDate changes the date for calculations.
+	// Called when user navigates dates or uses calendar.
+	UpdateDate(date time.Time)
+
+	// UpdateDateRange sets the last day of a date range starting at the
+	// date, or turns the range off (zero time).
+	// Called when user changes "Until" in the date panel.
+	UpdateDateRange(end time.Time)
+
+	// RefreshCountdown recalculates the days the countdown runs through.
+	// Called when the countdown passes midnight.
+	RefreshCountdown()
+
+	// UpdateSettings applies new user preferences.
+	// Called when settings panel values change.
+	UpdateSettings(settings domain.Settings)
+
+	// UpdateMapZoom records the map's zoom level for persistence.
+	// Called when the user zooms the map.
+	UpdateMapZoom(zoom int)
+
+	// UpdatePanelLayout records the arrangement of the side panels for
+	// persistence (QMainWindow.SaveState).
+	// Called when the app quits.
+	UpdatePanelLayout(layout []byte)
+
+	// UpdateWindowLayout records the window layout (domain.WindowLayout*).
+	// Called when the user picks one from View → Layout.
+	UpdateWindowLayout(layout string)
+
+	// RestoreSettings replaces the settings with the backup or the defaults
+	// after the settings file was found damaged.
+	// Called when user clicks "Restore Backup" or "Restore Defaults".
+	RestoreSettings(fromBackup bool) error
+
+	// SearchLocation performs geocoding search.
+	// Called when user submits a location query.
+	SearchLocation(query string)
+
+	// CancelSearch stops the running location search.
+	// Called when user clicks the Go button while it is searching.
+	CancelSearch()
+
+	// OnMapClick handles map click events.
+	// Called when user clicks on the map.
+	OnMapClick(lat, lon float64)
+
+	// SelectMapPoint selects a point of a map point layer as the location.
+	// Called when user clicks an imported (or otherwise listed) point.
+	SelectMapPoint(loc domain.Location)
+
+	// OnMapLocate handles the result of a browser geolocation request.
+	// Called when the map's locate button or LocateWithMap finishes.
+	OnMapLocate(lat, lon float64, err error)
+
+	// GetSettings returns current settings.
+	// Used for initializing UI components.
+	GetSettings() domain.Settings
+
+	// GetLocation returns current location.
+	// Used for initializing UI components.
+	GetLocation() domain.Location
+
+	// GetDate returns current calculation date.
+	// Used for initializing UI components.
+	GetDate() time.Time
+
+	// Events returns the bus the changes of location, date and sun times
+	// are published on (events.LocationChanged, events.DateChanged,
+	// events.SunTimesUpdated), on the main thread.
+	// Subscribed to by the widgets that show them, when the frontend is
+	// created.
+	Events() *events.Bus
+
+	// ExportConfigCode returns a shareable code for the current settings.
+	// Called when user clicks "Copy Code" in the settings panel.
+	ExportConfigCode() string
+
+	// ShareLink returns a gogoldenhour:// link to the location and date.
+	// Called when user picks Edit → Copy Link.
+	ShareLink() string
+
+	// ExportConfiguration writes the settings of all profiles to one file,
+	// returning how many profiles were written.
+	// Called when user clicks "Export Settings...".
+	ExportConfiguration(path string) (int, error)
+
+	// ReadConfiguration reads and validates an exported configuration
+	// without applying it.
+	// Called when user clicks "Import Settings...", before confirming.
+	ReadConfiguration(path string) (storage.ConfigBundle, error)
+
+	// ImportConfiguration replaces the settings of the bundle's profiles.
+	// Called when user confirms "Import Settings...".
+	ImportConfiguration(bundle storage.ConfigBundle) error
+
+	// OpenProfile starts another window of the app with a settings profile.
+	// Called when user picks Tools → Open Profile.
+	OpenProfile(profile string) error
+
+	// CheckForUpdates looks up the latest release.
+	// Called when user picks Help → Check for Updates.
+	CheckForUpdates()
+
+	// EndSession saves the session (place, date, map zoom, the open
+	// planning windows, domain.SessionPanel*) for the next launch.
+	// Called when the app quits.
+	EndSession(panels []string)
+
+	// OpenLinkText opens the place and date of a pasted share link.
+	// Called when user picks File → Open Link.
+	OpenLinkText(text string) error
+
+	// RegisterLinks makes the app the handler of gogoldenhour:// links.
+	// Called when user picks File → Register Link Handler.
+	RegisterLinks() error
+
+	// ImportConfigCode applies a pasted config code.
+	// Called when user submits a code via "Paste Code".
+	ImportConfigCode(code string) error
+
+	// FindSunAlignments searches for dates when the sun lines up with a landmark.
+	// Called when user picks camera and subject points in the map's alignment mode.
+	FindSunAlignments(camera, subject domain.Location)
+
+	// FetchElevationProfile samples terrain along a line (asynchronous).
+	// Called when the user completes a measurement in the map's measure mode.
+	FetchElevationProfile(from, to domain.Location)
+
+	// FetchCloudFrames loads the map's cloud animation (asynchronous).
+	// Called when the user turns on the map's cloud layer.
+	FetchCloudFrames()
+
+	// HorizonEvents returns the selected date's sun/moon rise and set events.
+	// Called when the user places or adjusts a camera in the map's camera mode.
+	HorizonEvents(camera domain.Location) ([]domain.HorizonEvent, error)
+
+	// MonthSunTimes returns each day's sun times of a month at the current
+	// location.
+	// Called when the month calendar opens, changes month or is refreshed.
+	MonthSunTimes(month time.Time) ([]domain.SunTimes, error)
+
+	// MonthMeteorNights rates the month's meteor shower peak nights at the
+	// current location.
+	// Called along with MonthSunTimes for the month calendar.
+	MonthMeteorNights(month time.Time) ([]domain.MeteorNight, error)
+
+	// FetchWeekOutlook rates the coming week's golden and blue hours by the
+	// weather forecast (asynchronous).
+	// Called when the week outlook opens or is refreshed.
+	FetchWeekOutlook()
+
+	// ImportMapData reads points and tracks from a GPX, KML or GeoJSON file.
+	// Called when user chooses File → Import Map Data.
+	ImportMapData(path string) (geodata.Data, error)
+
+	// ExportWatchCalendar writes the date range's (or the coming week's)
+	// events as an ICS file.
+	// Called when user picks File → Export Watch Calendar.
+	ExportWatchCalendar(path string) error
+
+	// SyncCalendar sends the same events to the CalDAV calendar set up in
+	// the preferences (asynchronous).
+	// Called when user picks File → Sync to Calendar.
+	SyncCalendar()
+
+	// AddToCalendar sends one golden or blue hour of the selected date
+	// (export.PeriodGoldenPM etc.) to the CalDAV calendar (asynchronous).
+	// Called when user picks "Add to My Calendar" on a period.
+	AddToCalendar(period string)
+
+	// GoogleCalendarURL returns Google Calendar's event form prefilled with
+	// one golden or blue hour of the selected date.
+	// Called when user picks "Add to Google Calendar" on a period.
+	GoogleCalendarURL(period string) (string, error)
+
+	// ExportDateRange writes each day of the date range as a CSV or JSON
+	// file, with the chosen columns and time format.
+	// Called when user picks File → Export Date Range and saves a file.
+	ExportDateRange(path string, opts export.DaysOptions) error
+
+	// CopyTimesText returns the selected day's times filled into the copy
+	// template.
+	// Called when user picks Edit → Copy Times.
+	CopyTimesText() (string, error)
+
+	// ShootPlanHTML returns the text of a shoot plan for the selected date(s).
+	// Called when user picks File → Shoot Plan (PDF).
+	ShootPlanHTML() (string, error)
+
+	// ExportWaypoints writes the current location and the favorites as
+	// GPX waypoints with their sun times on the selected date(s).
+	// Called when user picks File → Export Shoot Waypoints (GPX).
+	ExportWaypoints(path string) error
+
+	// DateRangeText returns each day of the date range as tab-separated text.
+	// Called when user picks File → Export Date Range and copies the table.
+	DateRangeText(opts export.DaysOptions) (string, error)
+
+	// ImportFavorites adds the points of a file to the favorites, returning
+	// how many were added and how many points the file had.
+	// Called when user clicks "Import My Places...".
+	ImportFavorites(path string) (added, found int, err error)
+
+	// ExportFavorites writes the favorites as GeoJSON or CSV (by extension).
+	// Called when user clicks "Export My Places...".
+	ExportFavorites(path string) error
+
+	// AnalyzePhoto works out the light a photo was taken in from its EXIF
+	// GPS position and capture time.
+	// Called when user picks Tools → Analyze Photo and opens a photo.
+	AnalyzePhoto(path string) (domain.PhotoLight, error)
+
+	// UpdateAutomation applies the automation switch and hooks.
+	// Called when user confirms the preferences dialog.
+	UpdateAutomation(enabled bool, hooks []domain.Hook)
+
+	// UpdateContactEmail applies the contact email for geocoding requests.
+	// Called when user confirms the preferences dialog.
+	UpdateContactEmail(email string)
+
+	// UpdateLocationProviders applies the location source, the fallback order
+	// and timeouts of location detection, and whether providers without HTTPS
+	// are skipped.
+	// Called when user confirms the preferences dialog.
+	UpdateLocationProviders(source string, providers []domain.LocationProvider, secureOnly bool)
+
+	// UpdateDisplayFormats applies the coordinate format, the place name style,
+	// the time panel layout and how long scratch locations are kept.
+	// Called when user confirms the preferences dialog.
+	UpdateDisplayFormats(coordinateFormat, placeNameStyle, timePanelLayout string, scratchRetentionDays int)
+
+	// UpdatePrivacyMode turns privacy mode (no requests to online services) on or off.
+	// Called when user toggles Edit → Privacy Mode.
+	UpdatePrivacyMode(on bool)
+
+	// UpdateHomeLocation applies the location used on first run and when
+	// detection fails (nil for the default, London).
+	// Called when user confirms the preferences dialog.
+	UpdateHomeLocation(home *domain.Location)
+
+	// UpdateHomeTimezone applies the timezone of the home clock (empty for none).
+	// Called when user confirms the preferences dialog.
+	UpdateHomeTimezone(name string)
+
+	// UpdateCopyTemplate applies the template of Edit → Copy Times (empty for
+	// the built-in summary).
+	// Called when user confirms the preferences dialog.
+	UpdateCopyTemplate(text string)
+
+	// UpdateMinimizeToTray applies whether the minimized window hides in
+	// the system tray.
+	// Called when user confirms the preferences dialog.
+	UpdateMinimizeToTray(on bool)
+
+	// UpdateAuroraForecast applies whether the Astro panel rates the night
+	// by the Kp index forecast.
+	// Called when user confirms the preferences dialog.
+	UpdateAuroraForecast(on bool)
+
+	// UpdateSearchBias applies the language, countries and map area searches prefer.
+	// Called when user confirms the preferences dialog.
+	UpdateSearchBias(bias domain.SearchBias)
+
+	// UpdateTileServer applies the map tile server.
+	// Called when user confirms the preferences dialog.
+	UpdateTileServer(server domain.TileServer)
+
+	// UpdateShowWhatsNew turns the release notes after updates on or off.
+	// Called when user closes the "What's new" dialog.
+	UpdateShowWhatsNew(show bool)
+
+	// CompleteWelcome applies the answers of the first start's welcome
+	// wizard (nil home for the default).
+	// Called when user finishes the welcome wizard.
+	CompleteWelcome(home *domain.Location, autoDetect, timeFormat24Hour bool, coordinateFormat string)
+
+	// TestHook runs a hook once immediately.
+	// Called when user clicks "Test" in the preferences dialog.
+	TestHook(hook domain.Hook)
+
+	// UpdateDailySummary applies the daily summary configuration.
+	// Called when user confirms the preferences dialog.
+	UpdateDailySummary(summary domain.DailySummary)
+
+	// SendSummaryNow sends a daily summary once immediately (asynchronous).
+	// Called when user clicks "Send Now" in the preferences dialog.
+	SendSummaryNow(summary domain.DailySummary)
+
+	// UpdateWidgetFeed applies the widget feed configuration.
+	// Called when user confirms the preferences dialog.
+	UpdateWidgetFeed(feed domain.WidgetFeed)
+
+	// WriteFeedNow writes the widget feed once immediately (asynchronous).
+	// Called when user clicks "Write Now" in the preferences dialog.
+	WriteFeedNow(feed domain.WidgetFeed)
+
+	// UpdatePush applies the push notification configuration.
+	// Called when user confirms the preferences dialog.
+	UpdatePush(push domain.PushNotifications)
+
+	// SendPushSchedule pushes the next two weeks' times once (asynchronous).
+	// Called when user clicks "Send 14-Day Schedule" in the preferences dialog.
+	SendPushSchedule(push domain.PushNotifications)
+
+	// UpdateWebhook applies the webhook configuration.
+	// Called when user confirms the preferences dialog.
+	UpdateWebhook(webhook domain.Webhook)
+
+	// TestWebhook calls the webhook once (asynchronous).
+	// Called when user clicks "Send Test" in the preferences dialog.
+	TestWebhook(webhook domain.Webhook)
+
+	// UpdateNotifications applies the desktop notification configuration.
+	// Called when user confirms the preferences dialog.
+	UpdateNotifications(notifications domain.DesktopNotifications)
+
+	// TestNotification shows a desktop notification once.
+	// Called when user clicks "Show Test" in the preferences dialog.
+	TestNotification(notifications domain.DesktopNotifications)
+
+	// UpdateCalDAV applies the calendar sync login.
+	// Called when user confirms the preferences dialog.
+	UpdateCalDAV(sync domain.CalDAVSync)
+
+	// PauseNotifications pauses or resumes desktop notifications, phone
+	// reminders and webhook calls for the session.
+	// Called when user toggles "Pause Notifications" in the tray icon's menu.
+	PauseNotifications(paused bool)
+
+	// ToggleFavorite adds the current location to the favorites or removes it.
+	// Called when user clicks the star in the location panel.
+	ToggleFavorite()
+
+	// UpdateFavoriteOverrides saves a favorite's own settings.
+	// Called when user confirms a favorite's settings dialog.
+	UpdateFavoriteOverrides(id string, overrides domain.FavoriteOverrides)
+
+	// ClearRecentLocations forgets the scratch locations of the "Recent" menu.
+	// Called when user clicks "Clear Scratch Locations".
+	ClearRecentLocations()
+
+	// SelectPreset switches to a preset's elevation angles and custom events.
+	// Called when user picks a preset in the toolbar.
+	SelectPreset(name string)
+
+	// SavePresetAs saves the current angles and custom events as a preset.
+	// Called when user clicks "Save As..." in the toolbar and enters a name.
+	SavePresetAs(name string) error
+
+	// DeletePreset removes a preset.
+	// Called when user clicks "Delete" in the toolbar and confirms.
+	DeletePreset(name string)
+
+	// UpdateCustomEvents applies the custom events of the active preset.
+	// Called when user confirms the preferences dialog.
+	UpdateCustomEvents(events []domain.CustomEvent)
+}
+
+// =============================================================================
+// View Interface
+// =============================================================================
+
+// View defines the display the App controller updates.
+//
+// It's implemented by ui.MainWindow and tui.Terminal. A frontend that has
+// no place for an update (e.g., the terminal UI has no map for the sun
+// path) ignores it. The location, date and sun times aren't View methods:
+// they are published on the event bus (Controller.Events), so any number
+// of widgets can follow them.
+//
+// The interface includes:
+//   - Window methods: Show, ShowWelcome, ShowWhatsNew, ShowSettingsRecovery
+//   - Update methods: UpdateWeather, UpdateAurora, UpdateMeteors,
+//     UpdateTimeline, UpdateSunPath, UpdateSunPositions, UpdateTerminator,
+//     UpdateCountdown, UpdateDateRange, UpdateFavorites,
+//     UpdateRecentLocations, UpdatePresets, ApplySettings, ReloadSettings,
+//     SetMinimizeToTray
+//   - Result methods: ShowSearchResults, ShowSunAlignments,
+//     ShowElevationProfile, ShowCloudFrames, ShowWeekOutlook, ShowUpdateCheck,
+//     ShowHookResult, ShowSummaryResult, ShowFeedResult, ShowPushResult,
+//     ShowCalendarSyncResult
+//   - Status methods: ShowError, ShowRetryableError, SetBusy
+//   - Map methods: MapBounds, LocateWithMap
+//   - Thread methods: RunOnMainThread, QueueOnMainThread
+type View interface {
+	// Show makes the frontend visible.
+	// Called once from App.Run.
+	Show()
+
+	// ShowWelcome asks a first-time user for the home location and formats,
+	// passed to Controller.CompleteWelcome.
+	// Called from App.Run when no settings file existed.
+	ShowWelcome()
+
+	// ShowWhatsNew displays the release notes of the given versions.
+	// Called on the first start after an update.
+	ShowWhatsNew(releases []changelog.Release)
+
+	// ShowSettingsRecovery tells the user that the settings file was
+	// damaged, offering its backup if there is one.
+	// Called from App.Run.
+	ShowSettingsRecovery(hasBackup bool)
+
+	// UpdateWeather shows the forecast weather of the selected day's
+	// periods; an empty forecast hides it.
+	// Called after every recalculation (empty first, so no stale weather
+	// is shown) and again when the forecast arrives.
+	UpdateWeather(forecast domain.WeatherForecast)
+
+	// UpdateAurora shows the darkness of the selected date's night and,
+	// while Settings.AuroraForecast is on, its aurora outlook.
+	// Called after every recalculation (without a Kp forecast first) and
+	// again when the Kp forecast arrives.
+	UpdateAurora(outlook domain.AuroraOutlook)
+
+	// UpdateMeteors shows the meteor showers peaking on the selected
+	// date's night; empty on most nights.
+	// Called after every recalculation.
+	UpdateMeteors(nights []domain.MeteorNight)
+
+	// UpdateTimeline shows the selected day's events and the next day's.
+	// Called after every recalculation.
+	UpdateTimeline(days []domain.SunTimes)
+
+	// UpdateSunPath shows the sun's positions through the evening golden
+	// hour at loc (nil if there is none).
+	// Called after every recalculation.
+	UpdateSunPath(loc domain.Location, path []domain.SunPathPoint)
+
+	// UpdateSunPositions gives the sun's positions through the selected
+	// day at loc (nil if they couldn't be calculated).
+	// Called after every recalculation.
+	UpdateSunPositions(loc domain.Location, positions []domain.SunPathPoint)
+
+	// UpdateTerminator shows the day/night bands at the current time.
+	// Called periodically.
+	UpdateTerminator(bands []domain.TwilightBand)
+
+	// UpdateCountdown restarts the countdown to the next golden or blue
+	// hour from the days around today (nil stops it).
+	// Called after the location or the angles changed.
+	UpdateCountdown(days []domain.SunTimes)
+
+	// UpdateDateRange shows each day of the planned date range (nil: a
+	// single day is planned).
+	// Called after every recalculation.
+	UpdateDateRange(days []domain.SunTimes)
+
+	// UpdateFavorites shows the favorites with each one's evening golden
+	// hour today, in the same order.
+	// Called after the favorites or their times changed.
+	UpdateFavorites(favorites []domain.Favorite, tonight []domain.TimeRange)
+
+	// UpdateRecentLocations shows the location history, most recent first.
+	// Called after every location change.
+	UpdateRecentLocations(recent []domain.RecentLocation)
+
+	// UpdatePresets shows the presets and the active one.
+	// Called after a preset was selected, saved or deleted.
+	UpdatePresets(settings domain.Settings)
+
+	// ApplySettings shows settings changed from outside the settings
+	// controls (e.g., an imported config code).
+	ApplySettings(settings domain.Settings)
+
+	// ReloadSettings shows settings that replaced the current ones as a
+	// whole (see Controller.RestoreSettings).
+	ReloadSettings(settings domain.Settings)
+
+	// SetMinimizeToTray turns hiding the minimized window in the tray on
+	// or off.
+	// Called when the preference changes.
+	SetMinimizeToTray(on bool)
+
+	// ShowSearchResults offers the results of a location search to choose
+	// from; the chosen one goes to Controller.UpdateLocation.
+	ShowSearchResults(results []domain.SearchResult)
+
+	// ShowSunAlignments shows the results of Controller.FindSunAlignments.
+	ShowSunAlignments(camera, subject domain.Location, alignments []domain.SunAlignment)
+
+	// ShowElevationProfile shows the result of
+	// Controller.FetchElevationProfile.
+	ShowElevationProfile(from, to domain.Location, profile domain.ElevationProfile)
+
+	// ShowCloudFrames shows the result of Controller.FetchCloudFrames.
+	ShowCloudFrames(frames []domain.CloudFrame, golden domain.TimeRange, aroundGolden bool)
+
+	// ShowWeekOutlook shows the result of Controller.FetchWeekOutlook: the
+	// days' sun times, and the forecast or why there is none.
+	ShowWeekOutlook(days []domain.SunTimes, forecast domain.WeatherForecast, err error)
+
+	// ShowUpdateCheck shows the result of Controller.CheckForUpdates.
+	ShowUpdateCheck(latest updates.Release, newer bool)
+
+	// ShowHookResult reports an automation hook, push reminder, webhook
+	// call or due desktop notification.
+	ShowHookResult(result automation.Result)
+
+	// ShowSummaryResult reports a daily summary that was sent or skipped.
+	ShowSummaryResult(result automation.SummaryResult)
+
+	// ShowFeedResult reports a widget feed write that failed, or succeeded
+	// after failing or with "Write Now".
+	ShowFeedResult(result automation.FeedResult)
+
+	// ShowPushResult reports the result of Controller.SendPushSchedule.
+	ShowPushResult(err error)
+
+	// ShowCalendarSyncResult reports the result of Controller.SyncCalendar.
+	ShowCalendarSyncResult(result caldav.Result, err error)
+
+	// ShowError displays an error message without blocking the user.
+	ShowError(message string)
+
+	// ShowRetryableError displays an error with a way to run the failed
+	// operation again (retry; nil shows it like ShowError).
+	ShowRetryableError(message string, retry func())
+
+	// SetBusy shows whether a network operation runs.
+	// Called when it starts (busy=true) and when it ends (busy=false).
+	SetBusy(task Task, busy bool)
+
+	// MapBounds returns the area the map shows, for searches near the map
+	// view; ok is false without a (loaded) map.
+	MapBounds() (bounds domain.Bounds, ok bool)
+
+	// LocateWithMap starts a browser geolocation request in the map, whose
+	// result goes to Controller.OnMapLocate (an error without a map).
+	LocateWithMap()
+
+	// RunOnMainThread runs fn on the frontend's main thread and waits until
+	// it has run.
+	// Called from the App's goroutines only.
+	RunOnMainThread(fn func())
+
+	// QueueOnMainThread runs fn on the main thread once the frontend's
+	// event loop runs, and returns immediately.
+	QueueOnMainThread(fn func())
+}
+
+// Factory creates a frontend's view for the App controller (e.g.,
+// ui.NewMainWindow). Called by app.New once the App exists, with the
+// configuration and the App itself.
+type Factory func(cfg config.AppConfig, controller Controller) View
+
+// =============================================================================
+// Tasks
+// =============================================================================
+
+// Task names a network operation shown by a frontend's progress indicator
+// (see View.SetBusy).
+type Task string
+
+// Network operations reported by the App controller.
+const (
+	// TaskSearch is a location search (Controller.SearchLocation).
+	TaskSearch Task = "Searching"
+
+	// TaskDetect is location detection (Controller.DetectLocation).
+	TaskDetect Task = "Detecting location"
+
+	// TaskLookup is the reverse geocoding of a map click or a dragged
+	// marker (Controller.OnMapClick).
+	TaskLookup Task = "Looking up the place name"
+)
@@ -0,0 +1,102 @@
+package geodata
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// CSV
+// =============================================================================
+
+// csvColumns lists the accepted header names of each CSV column, in lower
+// case. The first names are those written by export.PlacesCSV; the others
+// are common in spreadsheets and exports of other apps.
+var csvColumns = map[string][]string{
+	"name":      {"name", "title", "label"},
+	"latitude":  {"latitude", "lat"},
+	"longitude": {"longitude", "lon", "lng", "long"},
+	"elevation": {"elevation", "ele", "altitude", "alt"},
+}
+
+// parseCSV reads points from a CSV table with a header row.
+//
+// The latitude and longitude columns are required; name and elevation are
+// optional, and other columns are ignored. Columns are found by header name
+// (see csvColumns) in any order and case. Rows with missing or malformed
+// coordinates are skipped.
+//
+// Example:
+//
+//	name,latitude,longitude
+//	Eiffel Tower,48.8584,2.2945
+func parseCSV(content []byte) (Data, error) {
+	// Spreadsheet apps often start the file with a byte order mark
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(content, []byte("\uFEFF"))))
+	reader.FieldsPerRecord = -1 // Rows may be short; missing cells are empty
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return Data{}, fmt.Errorf("invalid CSV file: %w", err)
+	}
+	columns := csvColumnIndexes(header)
+	if columns["latitude"] < 0 || columns["longitude"] < 0 {
+		return Data{}, errors.New("invalid CSV file: no latitude and longitude columns")
+	}
+
+	var data Data
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Data{}, fmt.Errorf("invalid CSV file: %w", err)
+		}
+		cell := func(column string) string {
+			if i := columns[column]; i >= 0 && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		lat, errLat := strconv.ParseFloat(cell("latitude"), 64)
+		lon, errLon := strconv.ParseFloat(cell("longitude"), 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+		loc := domain.Location{Latitude: lat, Longitude: lon}
+		if ele, err := strconv.ParseFloat(cell("elevation"), 64); err == nil {
+			loc.Elevation = ele
+		}
+		data.addPoint(cell("name"), loc)
+	}
+	return data, nil
+}
+
+// csvColumnIndexes finds the columns of csvColumns in a header row.
+//
+// Returns the index of each column, -1 for those not present.
+func csvColumnIndexes(header []string) map[string]int {
+	indexes := make(map[string]int, len(csvColumns))
+	for column, names := range csvColumns {
+		indexes[column] = -1
+		for i, h := range header {
+			h = strings.ToLower(strings.TrimSpace(h))
+			for _, name := range names {
+				if h == name && indexes[column] < 0 {
+					indexes[column] = i
+				}
+			}
+		}
+	}
+	return indexes
+}
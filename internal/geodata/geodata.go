@@ -0,0 +1,156 @@
+// Package geodata reads points and tracks from common geographic file formats.
+//
+// Photographers often plan shoots in other tools (hiking apps, Google Earth,
+// GIS software) and want to see those plans next to the sun times. This
+// package turns such files into map points and tracks that the map can show
+// in a point layer:
+//
+//   - GPX (.gpx): Waypoints become points; tracks and routes become tracks
+//   - KML (.kml) and KMZ (.kmz): Placemarks with Point geometries become
+//     points; LineString, LinearRing (polygon outlines) and gx:Track
+//     geometries become tracks, including inside MultiGeometry and folders
+//   - GeoJSON (.geojson, .json): Point/MultiPoint geometries become points;
+//     LineString, MultiLineString, Polygon and MultiPolygon become tracks
+//   - CSV (.csv): Rows with latitude and longitude columns become points
+//     (see parseCSV)
+//
+// Only geometry and names are read; styles, timestamps and other metadata
+// are ignored. Elevations are kept when the file has them.
+//
+// Usage:
+//
+//	data, err := geodata.ParseFile("hike.gpx")
+//	if err != nil {
+//	    return err
+//	}
+//	mapView.SetPoints("hike.gpx", data.Points)
+package geodata
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Data
+// =============================================================================
+
+// Data holds the points and tracks read from a file.
+type Data struct {
+	// Points are individual locations (waypoints, placemarks). IDs are
+	// assigned in file order ("p0", "p1", ...), so re-importing the same
+	// file yields the same IDs.
+	Points []domain.MapPoint
+
+	// Tracks are lines (recorded traces, routes, outlines).
+	Tracks []domain.Track
+}
+
+// IsEmpty returns true if no points or tracks were found.
+func (d Data) IsEmpty() bool {
+	return len(d.Points) == 0 && len(d.Tracks) == 0
+}
+
+// Bounds returns the bounding box of all points and tracks, in degrees.
+//
+// Returns ok=false if the data is empty. Data crossing the antimeridian is
+// not handled specially (the box then spans the whole longitude range).
+func (d Data) Bounds() (south, west, north, east float64, ok bool) {
+	extend := func(l domain.Location) {
+		if !ok {
+			south, west, north, east, ok = l.Latitude, l.Longitude, l.Latitude, l.Longitude, true
+			return
+		}
+		south, west = min(south, l.Latitude), min(west, l.Longitude)
+		north, east = max(north, l.Latitude), max(east, l.Longitude)
+	}
+
+	for _, p := range d.Points {
+		extend(p.Location)
+	}
+	for _, t := range d.Tracks {
+		for _, l := range t.Points {
+			extend(l)
+		}
+	}
+	return south, west, north, east, ok
+}
+
+// addPoint appends a point with the next ID, skipping invalid coordinates.
+func (d *Data) addPoint(name string, loc domain.Location) {
+	if !loc.IsValid() {
+		return
+	}
+	loc.Name = strings.TrimSpace(name)
+	d.Points = append(d.Points, domain.MapPoint{
+		ID:       fmt.Sprintf("p%d", len(d.Points)),
+		Location: loc,
+	})
+}
+
+// addTrack appends a track, skipping invalid coordinates and tracks with
+// fewer than two points (nothing to draw).
+func (d *Data) addTrack(name string, points []domain.Location) {
+	valid := make([]domain.Location, 0, len(points))
+	for _, p := range points {
+		if p.IsValid() {
+			valid = append(valid, p)
+		}
+	}
+	if len(valid) < 2 {
+		return
+	}
+	d.Tracks = append(d.Tracks, domain.Track{Name: strings.TrimSpace(name), Points: valid})
+}
+
+// =============================================================================
+// File Parsing
+// =============================================================================
+
+// ParseFile reads points and tracks from a file.
+//
+// The format is chosen by file extension (see the package docs for the
+// supported formats).
+//
+// Parameters:
+//   - path: The file to read
+//
+// Returns:
+//   - Data: The points and tracks found (never empty on success)
+//   - error: Non-nil if the file can't be read, its type isn't supported,
+//     it is malformed, or it contains no points or tracks
+func ParseFile(path string) (Data, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Data{}, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	var data Data
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".gpx":
+		data, err = parseGPX(content)
+	case ".kml":
+		data, err = parseKML(content)
+	case ".kmz":
+		data, err = parseKMZ(content)
+	case ".geojson", ".json":
+		data, err = parseGeoJSON(content)
+	case ".csv":
+		data, err = parseCSV(content)
+	default:
+		return Data{}, fmt.Errorf("unsupported file type %q (expected GPX, KML, KMZ, GeoJSON or CSV)", ext)
+	}
+	if err != nil {
+		return Data{}, err
+	}
+
+	if data.IsEmpty() {
+		return Data{}, errors.New("no points or tracks found in file")
+	}
+	return data, nil
+}
@@ -0,0 +1,364 @@
+package geodata
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// summary describes parsed data compactly for comparisons.
+type summary struct {
+	Points []string // "name@lat,lon"
+	Tracks []string // "name:points"
+}
+
+func summarize(d Data) summary {
+	var s summary
+	for _, p := range d.Points {
+		s.Points = append(s.Points, p.Location.Name+"@"+ftoa(p.Location.Latitude)+","+ftoa(p.Location.Longitude))
+	}
+	for _, t := range d.Tracks {
+		s.Tracks = append(s.Tracks, t.Name+":"+strconv.Itoa(len(t.Points)))
+	}
+	return s
+}
+
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func TestParseGPX(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    summary
+		wantErr bool
+	}{
+		{
+			name: "waypoints, route and track segments",
+			content: `<?xml version="1.0"?>
+<gpx version="1.1" xmlns="http://www.topografix.com/GPX/1/1">
+  <wpt lat="48.8584" lon="2.2945"><ele>35</ele><name> Eiffel Tower </name></wpt>
+  <wpt lat="48.8606" lon="2.3376"><name>Louvre</name></wpt>
+  <rte><name>Walk</name>
+    <rtept lat="48.85" lon="2.29"/><rtept lat="48.86" lon="2.33"/>
+  </rte>
+  <trk><name>Run</name>
+    <trkseg><trkpt lat="1" lon="1"/><trkpt lat="1.1" lon="1.1"/><trkpt lat="1.2" lon="1.2"/></trkseg>
+    <trkseg><trkpt lat="2" lon="2"/><trkpt lat="2.1" lon="2.1"/></trkseg>
+  </trk>
+</gpx>`,
+			want: summary{
+				Points: []string{"Eiffel Tower@48.8584,2.2945", "Louvre@48.8606,2.3376"},
+				Tracks: []string{"Walk:2", "Run:3", "Run:2"},
+			},
+		},
+		{
+			name: "invalid coordinates and one-point tracks are dropped",
+			content: `<gpx>
+  <wpt lat="95" lon="0"><name>Bad</name></wpt>
+  <trk><trkseg><trkpt lat="1" lon="1"/><trkpt lat="1" lon="200"/></trkseg></trk>
+</gpx>`,
+			want: summary{},
+		},
+		{name: "malformed XML", content: `<gpx><wpt lat="1"`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseGPX([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGPX error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := summarize(data); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGPX = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// sampleKML has a point, a line, a multi-geometry and a gx:Track.
+const sampleKML = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+<Document><name>Trip</name>
+  <Folder><name>Spots</name>
+    <Placemark><name>Sunset spot</name>
+      <Point><coordinates>-3.1883,55.9486,251</coordinates></Point>
+    </Placemark>
+  </Folder>
+  <Placemark><name>Ridge</name>
+    <LineString><coordinates>
+      -3.16,55.94,0 -3.15,55.95,0
+      -3.14,55.96
+    </coordinates></LineString>
+  </Placemark>
+  <Placemark><name>Both</name>
+    <MultiGeometry>
+      <Point><coordinates>1,2</coordinates></Point>
+      <Polygon><outerBoundaryIs><LinearRing>
+        <coordinates>0,0 1,0 1,1 0,0</coordinates>
+      </LinearRing></outerBoundaryIs></Polygon>
+    </MultiGeometry>
+  </Placemark>
+  <Placemark><name>Recorded</name>
+    <gx:Track>
+      <when>2026-01-01T10:00:00Z</when><when>2026-01-01T10:01:00Z</when>
+      <gx:coord>10 20 5</gx:coord><gx:coord>10.1 20.1 6</gx:coord>
+    </gx:Track>
+  </Placemark>
+</Document>
+</kml>`
+
+var sampleKMLSummary = summary{
+	Points: []string{"Sunset spot@55.9486,-3.1883", "Both@2,1"},
+	Tracks: []string{"Ridge:3", "Both:4", "Recorded:2"},
+}
+
+func TestParseKML(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    summary
+		wantErr bool
+	}{
+		{name: "placemarks of all kinds", content: sampleKML, want: sampleKMLSummary},
+		{
+			name:    "folder and document names are not placemark names",
+			content: `<kml><Document><name>Doc</name><Placemark><Point><coordinates>5,6</coordinates></Point></Placemark></Document></kml>`,
+			want:    summary{Points: []string{"@6,5"}},
+		},
+		{
+			name:    "malformed tuples are skipped",
+			content: `<kml><Placemark><name>X</name><LineString><coordinates>a,b 1,2 3 4,5</coordinates></LineString></Placemark></kml>`,
+			want:    summary{Tracks: []string{"X:2"}},
+		},
+		{name: "malformed XML", content: `<kml><Placemark>`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseKML([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKML error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := summarize(data); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKML = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// zipFiles builds a zip archive holding the given name/content pairs.
+func zipFiles(t *testing.T, files ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for i := 0; i < len(files); i += 2 {
+		f, err := w.Create(files[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(files[i+1])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseKMZ(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    summary
+		wantErr bool
+	}{
+		{
+			name:    "first KML document is used",
+			content: zipFiles(t, "images/icon.png", "PNG", "files/DOC.KML", sampleKML, "other.kml", "<kml/>"),
+			want:    sampleKMLSummary,
+		},
+		{name: "no KML in archive", content: zipFiles(t, "readme.txt", "hi"), wantErr: true},
+		{name: "not a zip file", content: []byte(sampleKML), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseKMZ(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKMZ error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := summarize(data); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKMZ = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGeoJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    summary
+		wantErr bool
+	}{
+		{
+			name: "feature collection",
+			content: `{"type": "FeatureCollection", "features": [
+				{"type": "Feature", "properties": {"name": "Viewpoint"},
+				 "geometry": {"type": "Point", "coordinates": [7.6586, 45.9763, 3100]}},
+				{"type": "Feature", "properties": {"title": "Trail"},
+				 "geometry": {"type": "LineString", "coordinates": [[7.6, 45.9], [7.7, 46.0], [7.8, 46.1]]}},
+				{"type": "Feature", "properties": null, "geometry": null},
+				{"type": "Feature", "properties": {"name": "Lake"},
+				 "geometry": {"type": "Polygon", "coordinates": [[[0,0],[1,0],[1,1],[0,0]], [[0.2,0.2],[0.3,0.2],[0.2,0.2]]]}}
+			]}`,
+			want: summary{
+				Points: []string{"Viewpoint@45.9763,7.6586"},
+				Tracks: []string{"Trail:3", "Lake:4", "Lake:3"},
+			},
+		},
+		{
+			name: "bare geometries and collections",
+			content: `{"type": "GeometryCollection", "geometries": [
+				{"type": "MultiPoint", "coordinates": [[1, 2], [3, 4], [5]]},
+				{"type": "MultiLineString", "coordinates": [[[0, 0], [1, 1]]]},
+				{"type": "MultiPolygon", "coordinates": [[[[0, 0], [2, 0], [2, 2], [0, 0]]]]}
+			]}`,
+			want: summary{
+				Points: []string{"@2,1", "@4,3"},
+				Tracks: []string{":2", ":4"},
+			},
+		},
+		{name: "unsupported type", content: `{"type": "Topology"}`, wantErr: true},
+		{name: "wrong coordinate nesting", content: `{"type": "Point", "coordinates": [[1, 2]]}`, wantErr: true},
+		{name: "malformed JSON", content: `{"type": "Point",`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseGeoJSON([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseGeoJSON error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := summarize(data); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseGeoJSON = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    summary
+		wantErr bool
+	}{
+		{
+			name: "exported places",
+			content: "name,latitude,longitude,elevation,timezone\n" +
+				"\"Bridge, sunset side\",48.8584,2.2945,35,Europe/Paris\n" +
+				"Louvre,48.8606,2.3376,,Europe/Paris\n",
+			want: summary{Points: []string{"Bridge, sunset side@48.8584,2.2945", "Louvre@48.8606,2.3376"}},
+		},
+		{
+			name:    "semicolon separated",
+			content: "Lat;Lng\n45;2.5\n",
+			wantErr: true,
+		},
+		{
+			name:    "spreadsheet columns",
+			content: "\uFEFFID, Lng, LAT, Title\n1, 2.5, 45, Peak\n2, x, 45, Bad row\n3, 3\n",
+			want:    summary{Points: []string{"Peak@45,2.5"}},
+		},
+		{name: "no coordinate columns", content: "name,city\nA,B\n", wantErr: true},
+		{name: "empty file", content: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := parseCSV([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCSV error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := summarize(data); !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCSV = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		wantPoints int
+		wantErr    string
+	}{
+		{name: "GPX by extension", path: write("a.GPX", `<gpx><wpt lat="1" lon="2"/></gpx>`), wantPoints: 1},
+		{name: "KML by extension", path: write("b.kml", sampleKML), wantPoints: 2},
+		{name: "JSON is GeoJSON", path: write("c.json", `{"type": "Point", "coordinates": [2, 1]}`), wantPoints: 1},
+		{name: "CSV by extension", path: write("d.csv", "lat,lon\n1,2\n"), wantPoints: 1},
+		{name: "unsupported extension", path: write("d.txt", "lat,lon"), wantErr: "unsupported file type"},
+		{name: "empty file contents", path: write("e.gpx", `<gpx></gpx>`), wantErr: "no points or tracks"},
+		{name: "missing file", path: filepath.Join(dir, "missing.gpx"), wantErr: "failed to read file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ParseFile(tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseFile error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseFile: %v", err)
+			}
+			if len(data.Points) != tt.wantPoints {
+				t.Errorf("got %d points, want %d", len(data.Points), tt.wantPoints)
+			}
+			for i, p := range data.Points {
+				if want := "p" + strconv.Itoa(i); p.ID != want {
+					t.Errorf("point %d ID = %q, want %q", i, p.ID, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBounds(t *testing.T) {
+	data, err := parseKML([]byte(sampleKML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	south, west, north, east, ok := data.Bounds()
+	if !ok {
+		t.Fatal("Bounds reported no data")
+	}
+	if south != 0 || west != -3.1883 || north != 55.96 || east != 10.1 {
+		t.Errorf("Bounds = %v, %v, %v, %v", south, west, north, east)
+	}
+
+	if _, _, _, _, ok := (Data{}).Bounds(); ok {
+		t.Error("Bounds of empty data reported ok")
+	}
+}
@@ -0,0 +1,156 @@
+package geodata
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// GeoJSON
+// =============================================================================
+
+// geoJSONObject is any GeoJSON object (RFC 7946).
+//
+// One struct covers feature collections, features, geometries and geometry
+// collections; only the fields of the actual Type are set. Coordinates are
+// kept raw because their nesting depth depends on the geometry type.
+type geoJSONObject struct {
+	Type        string          `json:"type"`
+	Features    []geoJSONObject `json:"features"`
+	Geometry    *geoJSONObject  `json:"geometry"`
+	Geometries  []geoJSONObject `json:"geometries"`
+	Coordinates json.RawMessage `json:"coordinates"`
+	Properties  map[string]any  `json:"properties"`
+}
+
+// parseGeoJSON reads points and lines from a GeoJSON document.
+//
+// The document may be a FeatureCollection, a single Feature or a bare
+// geometry. Feature names are taken from the "name" property (or "title",
+// used by some exporters).
+func parseGeoJSON(content []byte) (Data, error) {
+	var root geoJSONObject
+	if err := json.Unmarshal(content, &root); err != nil {
+		return Data{}, fmt.Errorf("invalid GeoJSON file: %w", err)
+	}
+
+	var data Data
+	if err := data.addGeoJSON(root, ""); err != nil {
+		return Data{}, fmt.Errorf("invalid GeoJSON file: %w", err)
+	}
+	return data, nil
+}
+
+// addGeoJSON adds the points and tracks of a GeoJSON object.
+//
+// Polygons are added as their rings (outer boundary and holes), since only
+// outlines are meaningful on a planning map.
+func (d *Data) addGeoJSON(obj geoJSONObject, name string) error {
+	switch obj.Type {
+	case "FeatureCollection":
+		for _, f := range obj.Features {
+			if err := d.addGeoJSON(f, ""); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "Feature":
+		if obj.Geometry == nil {
+			return nil // Features may have null geometry
+		}
+		return d.addGeoJSON(*obj.Geometry, geoJSONName(obj.Properties))
+
+	case "GeometryCollection":
+		for _, g := range obj.Geometries {
+			if err := d.addGeoJSON(g, name); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "Point":
+		var c []float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return err
+		}
+		if loc, ok := geoJSONPosition(c); ok {
+			d.addPoint(name, loc)
+		}
+
+	case "MultiPoint":
+		var c [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return err
+		}
+		for _, loc := range geoJSONPositions(c) {
+			d.addPoint(name, loc)
+		}
+
+	case "LineString":
+		var c [][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return err
+		}
+		d.addTrack(name, geoJSONPositions(c))
+
+	case "MultiLineString", "Polygon":
+		var c [][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return err
+		}
+		for _, line := range c {
+			d.addTrack(name, geoJSONPositions(line))
+		}
+
+	case "MultiPolygon":
+		var c [][][][]float64
+		if err := json.Unmarshal(obj.Coordinates, &c); err != nil {
+			return err
+		}
+		for _, polygon := range c {
+			for _, ring := range polygon {
+				d.addTrack(name, geoJSONPositions(ring))
+			}
+		}
+
+	default:
+		return fmt.Errorf("unsupported GeoJSON type %q", obj.Type)
+	}
+	return nil
+}
+
+// geoJSONName returns a feature's display name from its properties.
+func geoJSONName(properties map[string]any) string {
+	for _, key := range []string{"name", "title"} {
+		if s, ok := properties[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// geoJSONPosition converts a [lon, lat(, alt)] position.
+func geoJSONPosition(c []float64) (domain.Location, bool) {
+	if len(c) < 2 {
+		return domain.Location{}, false
+	}
+	loc := domain.Location{Latitude: c[1], Longitude: c[0]}
+	if len(c) >= 3 {
+		loc.Elevation = c[2]
+	}
+	return loc, true
+}
+
+// geoJSONPositions converts a list of positions, skipping malformed ones.
+func geoJSONPositions(c [][]float64) []domain.Location {
+	locations := make([]domain.Location, 0, len(c))
+	for _, p := range c {
+		if loc, ok := geoJSONPosition(p); ok {
+			locations = append(locations, loc)
+		}
+	}
+	return locations
+}
@@ -0,0 +1,78 @@
+package geodata
+
+import (
+	"encoding/xml"
+	"fmt"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// GPX
+// =============================================================================
+
+// gpxFile is the subset of a GPX 1.0/1.1 document that is read.
+//
+// Element names are matched without namespace, so both GPX versions (and
+// files with vendor extensions) decode the same way.
+type gpxFile struct {
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []struct {
+		Name   string     `xml:"name"`
+		Points []gpxPoint `xml:"rtept"`
+	} `xml:"rte"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Segments []struct {
+			Points []gpxPoint `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+// gpxPoint is a GPX waypoint, route point or track point.
+type gpxPoint struct {
+	Lat  float64 `xml:"lat,attr"`
+	Lon  float64 `xml:"lon,attr"`
+	Ele  float64 `xml:"ele"`
+	Name string  `xml:"name"`
+}
+
+// location converts the point to a domain location (without name).
+func (p gpxPoint) location() domain.Location {
+	return domain.Location{Latitude: p.Lat, Longitude: p.Lon, Elevation: p.Ele}
+}
+
+// parseGPX reads waypoints as points and routes/track segments as tracks.
+//
+// Each track segment becomes its own track, since segments are separate
+// recordings (e.g., the GPS lost its fix in between) and shouldn't be joined
+// by a straight line.
+func parseGPX(content []byte) (Data, error) {
+	var file gpxFile
+	if err := xml.Unmarshal(content, &file); err != nil {
+		return Data{}, fmt.Errorf("invalid GPX file: %w", err)
+	}
+
+	var data Data
+	for _, w := range file.Waypoints {
+		data.addPoint(w.Name, w.location())
+	}
+	for _, r := range file.Routes {
+		data.addTrack(r.Name, gpxLocations(r.Points))
+	}
+	for _, t := range file.Tracks {
+		for _, s := range t.Segments {
+			data.addTrack(t.Name, gpxLocations(s.Points))
+		}
+	}
+	return data, nil
+}
+
+// gpxLocations converts GPX points to domain locations.
+func gpxLocations(points []gpxPoint) []domain.Location {
+	locations := make([]domain.Location, len(points))
+	for i, p := range points {
+		locations[i] = p.location()
+	}
+	return locations
+}
@@ -0,0 +1,180 @@
+package geodata
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// KML / KMZ
+// =============================================================================
+
+// parseKML reads the geometries of all placemarks in a KML document.
+//
+// KML nests placemarks in any number of Document and Folder elements, and
+// geometries in MultiGeometry and Polygon elements, so instead of mapping
+// the schema to structs the document is scanned as a token stream. The
+// coordinates of each geometry are interpreted by their enclosing element:
+//
+//	<Point><coordinates>                      → point
+//	<LineString><coordinates>                 → track
+//	<LinearRing><coordinates>                 → track (polygon outline)
+//	<gx:Track><gx:coord> ... </gx:Track>      → track
+//
+// Every geometry takes the name of its placemark.
+func parseKML(content []byte) (Data, error) {
+	var data Data
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+
+	var (
+		stack      []string        // Local names of the open elements
+		text       strings.Builder // Character data of the innermost element
+		name       string          // Name of the current placemark
+		gxTrack    []domain.Location
+		geometries []kmlGeometry // Geometries of the current placemark
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Data{}, fmt.Errorf("invalid KML file: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name.Local)
+			text.Reset()
+			if t.Name.Local == "Placemark" {
+				name, geometries = "", nil
+			}
+
+		case xml.CharData:
+			text.Write(t)
+
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return Data{}, errors.New("invalid KML file: unbalanced elements")
+			}
+			parent := ""
+			if len(stack) >= 2 {
+				parent = stack[len(stack)-2]
+			}
+
+			switch t.Name.Local {
+			case "name":
+				if parent == "Placemark" {
+					name = text.String()
+				}
+			case "coordinates":
+				geometries = append(geometries, kmlGeometry{
+					point:  parent == "Point",
+					points: parseKMLCoordinates(text.String()),
+				})
+			case "coord":
+				// gx:coord uses spaces: "lon lat alt"
+				if loc, ok := parseKMLTuple(strings.Fields(text.String())); ok {
+					gxTrack = append(gxTrack, loc)
+				}
+			case "Track":
+				geometries = append(geometries, kmlGeometry{points: gxTrack})
+				gxTrack = nil
+			case "Placemark":
+				for _, g := range geometries {
+					if g.point {
+						for _, p := range g.points {
+							data.addPoint(name, p)
+						}
+					} else {
+						data.addTrack(name, g.points)
+					}
+				}
+				geometries = nil
+			}
+
+			stack = stack[:len(stack)-1]
+			text.Reset()
+		}
+	}
+	return data, nil
+}
+
+// kmlGeometry is a geometry found inside a placemark.
+type kmlGeometry struct {
+	// point is true for Point geometries; all others are drawn as tracks.
+	point bool
+
+	// points are the geometry's coordinates.
+	points []domain.Location
+}
+
+// parseKMLCoordinates parses a KML coordinates string.
+//
+// Tuples are "lon,lat[,alt]" separated by whitespace. Invalid tuples are
+// skipped.
+func parseKMLCoordinates(s string) []domain.Location {
+	var locations []domain.Location
+	for _, tuple := range strings.Fields(s) {
+		if loc, ok := parseKMLTuple(strings.Split(tuple, ",")); ok {
+			locations = append(locations, loc)
+		}
+	}
+	return locations
+}
+
+// parseKMLTuple parses the parts of a "lon,lat[,alt]" tuple.
+func parseKMLTuple(parts []string) (domain.Location, bool) {
+	if len(parts) < 2 {
+		return domain.Location{}, false
+	}
+	lon, err1 := strconv.ParseFloat(parts[0], 64)
+	lat, err2 := strconv.ParseFloat(parts[1], 64)
+	if err1 != nil || err2 != nil {
+		return domain.Location{}, false
+	}
+
+	loc := domain.Location{Latitude: lat, Longitude: lon}
+	if len(parts) >= 3 {
+		loc.Elevation, _ = strconv.ParseFloat(parts[2], 64)
+	}
+	return loc, true
+}
+
+// parseKMZ reads the main KML document from a KMZ (zipped KML) archive.
+//
+// By convention the main document is the first .kml file in the archive
+// (usually doc.kml); other files are images and overlays.
+func parseKMZ(content []byte) (Data, error) {
+	archive, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return Data{}, fmt.Errorf("invalid KMZ file: %w", err)
+	}
+
+	for _, f := range archive.File {
+		if !strings.EqualFold(path.Ext(f.Name), ".kml") {
+			continue
+		}
+		r, err := f.Open()
+		if err != nil {
+			return Data{}, fmt.Errorf("invalid KMZ file: %w", err)
+		}
+		kml, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			return Data{}, fmt.Errorf("invalid KMZ file: %w", err)
+		}
+		return parseKML(kml)
+	}
+	return Data{}, errors.New("invalid KMZ file: no KML document in archive")
+}
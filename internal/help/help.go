@@ -0,0 +1,160 @@
+// Package help provides the explanatory content shown in teaching mode.
+//
+// Teaching mode annotates the sun times display with short explanations of
+// what the elevation angles mean and why the golden and blue hours happen
+// in the order they do. It is aimed at photography workshops, where an
+// instructor wants participants to see the reasoning next to the numbers.
+//
+// # Data File
+//
+// The content lives in topics.json, which is embedded into the binary at
+// build time. Keeping the text in a structured data file (instead of string
+// literals scattered through the widgets) lets it be reviewed, corrected,
+// or translated without touching UI code:
+//
+//	{
+//	  "version": 1,
+//	  "topics": [
+//	    {"id": "golden_hour", "title": "Golden hour", "text": "... {golden} ..."}
+//	  ]
+//	}
+//
+// # Placeholders
+//
+// Topic text may contain placeholders that are replaced with the user's
+// current settings, so the explanation always matches the displayed times:
+//
+//	{golden}      Golden hour elevation (e.g., "6°")
+//	{blue_start}  Blue hour start elevation (e.g., "-4°")
+//	{blue_end}    Blue hour end elevation (e.g., "-8°")
+package help
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Topic IDs
+// =============================================================================
+
+// Topic IDs used by the UI. Each must have a matching entry in topics.json.
+const (
+	// TopicSunElevation explains what the elevation angles mean.
+	TopicSunElevation = "sun_elevation"
+
+	// TopicGoldenHour explains why golden hour light is warm.
+	TopicGoldenHour = "golden_hour"
+
+	// TopicBlueHour explains where blue hour light comes from.
+	TopicBlueHour = "blue_hour"
+
+	// TopicBlueBeforeSunrise explains the order of morning and evening periods.
+	TopicBlueBeforeSunrise = "blue_before_sunrise"
+)
+
+// =============================================================================
+// Topic
+// =============================================================================
+
+// Topic is a single explanation shown in teaching mode.
+type Topic struct {
+	// ID is the stable identifier the UI uses to look up the topic.
+	ID string `json:"id"`
+
+	// Title is a short heading for the explanation.
+	Title string `json:"title"`
+
+	// Text is the explanation itself, possibly containing placeholders.
+	Text string `json:"text"`
+}
+
+// topicsFile is the structure of the embedded topics.json.
+type topicsFile struct {
+	Version int     `json:"version"`
+	Topics  []Topic `json:"topics"`
+}
+
+// topicsJSON holds the raw embedded help data.
+//
+//go:embed topics.json
+var topicsJSON []byte
+
+var (
+	// loadOnce guards parsing of the embedded data.
+	loadOnce sync.Once
+
+	// topics maps topic IDs to topics after parsing.
+	topics map[string]Topic
+
+	// loadErr is the error from parsing, if any.
+	loadErr error
+)
+
+// load parses the embedded help data on first use.
+func load() (map[string]Topic, error) {
+	loadOnce.Do(func() {
+		var file topicsFile
+		if err := json.Unmarshal(topicsJSON, &file); err != nil {
+			loadErr = fmt.Errorf("failed to parse help topics: %w", err)
+			return
+		}
+
+		topics = make(map[string]Topic, len(file.Topics))
+		for _, t := range file.Topics {
+			topics[t.ID] = t
+		}
+	})
+	return topics, loadErr
+}
+
+// Lookup returns the topic with the given ID.
+//
+// Returns false if the topic doesn't exist or the embedded data is invalid.
+// Placeholders in the returned topic's text are not yet substituted; use
+// Render for display.
+func Lookup(id string) (Topic, bool) {
+	all, err := load()
+	if err != nil {
+		return Topic{}, false
+	}
+	t, ok := all[id]
+	return t, ok
+}
+
+// Render returns a topic's text with placeholders filled from settings.
+//
+// Parameters:
+//   - id: Topic ID (one of the Topic* constants)
+//   - settings: Current settings used for placeholder values
+//
+// Returns an empty string if the topic doesn't exist, so a missing entry in
+// the data file hides the annotation instead of showing a broken label.
+//
+// Example:
+//
+//	text := help.Render(help.TopicGoldenHour, settings)
+//	// "Golden hour lasts while the sun is between the horizon (0°) and 6° above it. ..."
+func Render(id string, settings domain.Settings) string {
+	t, ok := Lookup(id)
+	if !ok {
+		return ""
+	}
+
+	replacer := strings.NewReplacer(
+		"{golden}", formatDegrees(settings.GoldenHourElevation),
+		"{blue_start}", formatDegrees(settings.BlueHourStart),
+		"{blue_end}", formatDegrees(settings.BlueHourEnd),
+	)
+	return replacer.Replace(t.Text)
+}
+
+// formatDegrees formats an angle without a trailing ".0" (e.g., "6°", "-4.5°").
+func formatDegrees(deg float64) string {
+	return strings.TrimSuffix(fmt.Sprintf("%.1f", deg), ".0") + "°"
+}
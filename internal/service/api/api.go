@@ -0,0 +1,382 @@
+// Package api serves the sun time calculation and the place search over
+// HTTP (gogoldenhour --serve), so home automation systems and other tools
+// can query the same engine as the app.
+//
+// # Endpoints
+//
+// The /v1 endpoints answer JSON, and errors as {"error": "..."} with a
+// 4xx or 5xx status:
+//
+//   - GET /v1/suntimes?lat=48.85&lon=2.35[&date=2025-06-21][&tz=Europe/Paris][&elevation=35]
+//     The day's sun times at the place (see sunTimesResponse); the date
+//     defaults to today, the timezone to the place's
+//   - GET /v1/search?q=Eiffel+Tower[&limit=5]
+//     Places matching the query, best first (see searchResult)
+//   - GET /metrics
+//     The request counts, calculation and upstream request latencies and
+//     errors, and cache hit rates in the Prometheus text format, for
+//     monitoring (see handleMetrics)
+//
+// # Rate Limiting
+//
+// Each client (by IP address) may send a number of requests per minute,
+// as a burst or spread out. Beyond that, requests are answered 429 Too
+// Many Requests with a Retry-After header, so a misconfigured poller
+// can't flood the services behind the search (Nominatim allows one
+// request per second from the whole app; see geocoding). /metrics isn't
+// limited, so a scraper doesn't use up its host's allowance.
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// DefaultAddress is the address served unless another is given: the
+	// loopback interface only, so the API isn't open to the network by
+	// accident.
+	DefaultAddress = "127.0.0.1:8765"
+
+	// DefaultRequestsPerMinute is each client's request allowance unless
+	// another is given.
+	DefaultRequestsPerMinute = 60
+
+	// defaultSearchLimit and maxSearchLimit are the number of search
+	// results without and with the limit parameter.
+	defaultSearchLimit = 5
+	maxSearchLimit     = 20
+
+	// maxClients is how many clients the rate limiter tracks before it
+	// forgets those with a full allowance.
+	maxClients = 1000
+)
+
+// =============================================================================
+// Responses
+// =============================================================================
+
+// period is a golden or blue hour in a response.
+type period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// sunTimesResponse is the answer of /v1/suntimes. Times are RFC 3339 in
+// the place's timezone (e.g., "2025-06-21T21:57:00+02:00"); events and
+// periods that don't occur that day (e.g., in polar summer) are null.
+//
+// Example (abridged):
+//
+//	{"latitude": 48.85, "longitude": 2.35, "timezone": "Europe/Paris",
+//	 "date": "2025-06-21", "sunrise": "2025-06-21T05:47:00+02:00", ...,
+//	 "golden_evening": {"start": "...T21:07:00+02:00", "end": "...T21:51:00+02:00"},
+//	 "shooting_minutes": 158}
+type sunTimesResponse struct {
+	Latitude        float64    `json:"latitude"`
+	Longitude       float64    `json:"longitude"`
+	Timezone        string     `json:"timezone"`
+	Date            string     `json:"date"`
+	Sunrise         *time.Time `json:"sunrise"`
+	Sunset          *time.Time `json:"sunset"`
+	SolarNoon       *time.Time `json:"solar_noon"`
+	GoldenMorning   *period    `json:"golden_morning"`
+	GoldenEvening   *period    `json:"golden_evening"`
+	BlueMorning     *period    `json:"blue_morning"`
+	BlueEvening     *period    `json:"blue_evening"`
+	ShootingMinutes int        `json:"shooting_minutes"`
+}
+
+// searchResult is one place of the answer of /v1/search: the location's
+// fields (name, coordinates, timezone, ...) and the kind of place.
+type searchResult struct {
+	domain.Location
+	Type string `json:"type,omitempty"`
+}
+
+// errorResponse is the answer to a failed request.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// =============================================================================
+// Server
+// =============================================================================
+
+// Server answers the API's requests. It is an http.Handler.
+//
+// Usage:
+//
+//	server := api.New(calculator, search, api.DefaultRequestsPerMinute)
+//	http.ListenAndServe(api.DefaultAddress, server)
+type Server struct {
+	// calculator computes the sun times.
+	calculator *solar.Calculator
+
+	// search finds places (usually the geocoding service's Search).
+	search func(ctx context.Context, query string, limit int) ([]domain.SearchResult, error)
+
+	// limiter enforces each client's request allowance.
+	limiter *clientLimiter
+
+	// mux routes the requests to the endpoints.
+	mux *http.ServeMux
+
+	// requests counts the answers for /metrics.
+	requests *requestCounter
+}
+
+// New creates an API server.
+//
+// Parameters:
+//   - calculator: Computes the sun times, with the elevation angles to use
+//   - search: Finds places matching a query, at most limit, best first
+//   - requestsPerMinute: Each client's allowance (see the package docs)
+func New(calculator *solar.Calculator,
+	search func(ctx context.Context, query string, limit int) ([]domain.SearchResult, error),
+	requestsPerMinute int) *Server {
+	s := &Server{
+		calculator: calculator,
+		search:     search,
+		limiter:    newClientLimiter(max(requestsPerMinute, 1), time.Now),
+		mux:        http.NewServeMux(),
+		requests:   &requestCounter{},
+	}
+	s.mux.HandleFunc("GET /v1/suntimes", s.handleSunTimes)
+	s.mux.HandleFunc("GET /v1/search", s.handleSearch)
+	s.mux.HandleFunc("GET "+metricsPath, s.handleMetrics)
+	return s
+}
+
+// ServeHTTP answers a request, unless the client is over its allowance,
+// and counts the answer for /metrics.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: w, code: http.StatusOK}
+	defer func() { s.requests.add(endpointLabel(r.URL.Path), recorder.code) }()
+	w = recorder
+
+	if r.URL.Path == metricsPath {
+		s.mux.ServeHTTP(w, r)
+		return
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if wait := s.limiter.take(host); wait > 0 {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		writeError(w, http.StatusTooManyRequests, "too many requests")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handleSunTimes answers /v1/suntimes.
+func (s *Server) handleSunTimes(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if !query.Has("lat") || !query.Has("lon") {
+		writeError(w, http.StatusBadRequest, "lat and lon are required")
+		return
+	}
+	// The coordinates and date are checked like a share link's
+	link, err := domain.ParseLinkQuery(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	loc := domain.Location{Latitude: link.Latitude, Longitude: link.Longitude, Timezone: query.Get("tz")}
+	if loc.Timezone == "" {
+		loc.Timezone = timezone.FromCoordinates(loc.Latitude, loc.Longitude)
+	} else if !domain.ValidTimezone(loc.Timezone) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unknown timezone %q", loc.Timezone))
+		return
+	}
+	if text := query.Get("elevation"); text != "" {
+		if loc.Elevation, err = strconv.ParseFloat(text, 64); err != nil || math.IsNaN(loc.Elevation) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid elevation %q (meters)", text))
+			return
+		}
+	}
+	date := link.Date
+	if date.IsZero() {
+		// Today at the place, which may be another day than here
+		zone, err := time.LoadLocation(loc.Timezone)
+		if err != nil {
+			zone = time.UTC
+		}
+		date = time.Now().In(zone)
+	}
+
+	day, err := s.calculator.Calculate(loc, date)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("calculation failed: %v", err))
+		return
+	}
+	writeJSON(w, http.StatusOK, newSunTimesResponse(loc, day))
+}
+
+// handleSearch answers /v1/search.
+func (s *Server) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		writeError(w, http.StatusBadRequest, "q is required")
+		return
+	}
+	limit := defaultSearchLimit
+	if text := r.URL.Query().Get("limit"); text != "" {
+		n, err := strconv.Atoi(text)
+		if err != nil || n < 1 || n > maxSearchLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid limit %q (use 1 to %d)", text, maxSearchLimit))
+			return
+		}
+		limit = n
+	}
+
+	found, err := s.search(r.Context(), q, limit)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, fmt.Sprintf("search failed: %v", err))
+		return
+	}
+	results := make([]searchResult, 0, len(found))
+	for _, f := range found {
+		results = append(results, searchResult{Location: f.Location, Type: f.Type})
+	}
+	writeJSON(w, http.StatusOK, struct {
+		Results []searchResult `json:"results"`
+	}{results})
+}
+
+// newSunTimesResponse converts a day's sun times for the response.
+func newSunTimesResponse(loc domain.Location, day domain.SunTimes) sunTimesResponse {
+	clock := func(t time.Time) *time.Time {
+		if t.IsZero() {
+			return nil
+		}
+		t = t.Truncate(time.Second)
+		return &t
+	}
+	hour := func(tr domain.TimeRange) *period {
+		if !tr.IsValid() {
+			return nil
+		}
+		return &period{Start: tr.Start.Truncate(time.Second), End: tr.End.Truncate(time.Second)}
+	}
+	return sunTimesResponse{
+		Latitude:        loc.Latitude,
+		Longitude:       loc.Longitude,
+		Timezone:        loc.Timezone,
+		Date:            day.Date.Format("2006-01-02"),
+		Sunrise:         clock(day.Sunrise),
+		Sunset:          clock(day.Sunset),
+		SolarNoon:       clock(day.SolarNoon),
+		GoldenMorning:   hour(day.GoldenMorning),
+		GoldenEvening:   hour(day.GoldenEvening),
+		BlueMorning:     hour(day.BlueMorning),
+		BlueEvening:     hour(day.BlueEvening),
+		ShootingMinutes: int(day.ShootingWindow().Minutes()),
+	}
+}
+
+// writeJSON writes a JSON response.
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error response.
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
+
+// =============================================================================
+// Rate Limiting
+// =============================================================================
+
+// clientLimiter gives each client a bucket of perMinute requests that
+// refills steadily over a minute (a token bucket).
+type clientLimiter struct {
+	mu sync.Mutex
+
+	// perMinute is the bucket size and the refill per minute.
+	perMinute int
+
+	// now returns the current time (time.Now; a fake clock in tests).
+	now func() time.Time
+
+	// clients are the buckets by client address.
+	clients map[string]*bucket
+}
+
+// bucket is one client's remaining allowance.
+type bucket struct {
+	// tokens are the requests left, refilled since updated.
+	tokens float64
+
+	// updated is when tokens was last computed.
+	updated time.Time
+}
+
+// newClientLimiter creates a limiter allowing perMinute requests per
+// client.
+func newClientLimiter(perMinute int, now func() time.Time) *clientLimiter {
+	return &clientLimiter{perMinute: perMinute, now: now, clients: make(map[string]*bucket)}
+}
+
+// take uses one request of a client's allowance.
+//
+// Returns 0 if the request may be answered, otherwise how long until the
+// client may send the next one.
+func (l *clientLimiter) take(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	size := float64(l.perMinute)
+	perSecond := size / 60
+
+	b, ok := l.clients[client]
+	if !ok {
+		if len(l.clients) >= maxClients {
+			l.forgetIdle(now)
+		}
+		b = &bucket{tokens: size, updated: now}
+		l.clients[client] = b
+	}
+	b.tokens = min(size, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+
+	if b.tokens < 1 {
+		return time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+	}
+	b.tokens--
+	return 0
+}
+
+// forgetIdle drops the buckets that have refilled completely, which are the
+// same as new ones.
+func (l *clientLimiter) forgetIdle(now time.Time) {
+	size := float64(l.perMinute)
+	for client, b := range l.clients {
+		if b.tokens+now.Sub(b.updated).Seconds()*size/60 >= size {
+			delete(l.clients, client)
+		}
+	}
+}
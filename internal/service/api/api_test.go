@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// get sends a GET request to the server and decodes the JSON answer.
+func get(t *testing.T, s *Server, target string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	if body != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), body); err != nil {
+			t.Fatalf("GET %s: %v in %q", target, err, rec.Body.String())
+		}
+	}
+	return rec
+}
+
+func TestSunTimes(t *testing.T) {
+	if _, err := time.LoadLocation("Europe/Paris"); err != nil {
+		t.Skip("no tzdata:", err)
+	}
+	s := New(solar.New(domain.DefaultSettings()), nil, 100)
+
+	var day sunTimesResponse
+	rec := get(t, s, "/v1/suntimes?lat=48.85&lon=2.35&date=2025-06-21", &day)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200: %s", rec.Code, rec.Body.String())
+	}
+	if day.Timezone != "Europe/Paris" || day.Date != "2025-06-21" {
+		t.Errorf("timezone %q, date %q; want Europe/Paris on 2025-06-21", day.Timezone, day.Date)
+	}
+	if day.Sunrise == nil || day.Sunrise.Format("15:04") != "05:47" {
+		t.Errorf("sunrise %v, want 05:47", day.Sunrise)
+	}
+	if day.GoldenEvening == nil || !day.GoldenEvening.Start.Before(day.GoldenEvening.End) {
+		t.Errorf("golden evening %+v, want a period", day.GoldenEvening)
+	}
+	if _, offset := day.Sunrise.Zone(); offset != 2*60*60 {
+		t.Errorf("sunrise offset %d s, want Paris summer time", offset)
+	}
+
+	// In polar summer the sun doesn't set
+	var polar sunTimesResponse
+	get(t, s, "/v1/suntimes?lat=78.22&lon=15.65&date=2025-06-21&tz=Arctic/Longyearbyen", &polar)
+	if polar.Sunset != nil || polar.BlueEvening != nil {
+		t.Errorf("polar summer: sunset %v, blue evening %v; want null", polar.Sunset, polar.BlueEvening)
+	}
+
+	for _, target := range []string{
+		"/v1/suntimes?lat=48.85",
+		"/v1/suntimes?lat=91&lon=2.35",
+		"/v1/suntimes?lat=48.85&lon=2.35&date=21.06.2025",
+		"/v1/suntimes?lat=48.85&lon=2.35&tz=Mars/Olympus",
+		"/v1/suntimes?lat=48.85&lon=2.35&elevation=high",
+	} {
+		var answer errorResponse
+		if rec := get(t, s, target, &answer); rec.Code != http.StatusBadRequest || answer.Error == "" {
+			t.Errorf("GET %s: status %d, error %q; want 400 with a message", target, rec.Code, answer.Error)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	var gotQuery string
+	var gotLimit int
+	search := func(ctx context.Context, query string, limit int) ([]domain.SearchResult, error) {
+		gotQuery, gotLimit = query, limit
+		if query == "fail" {
+			return nil, errors.New("offline")
+		}
+		return []domain.SearchResult{{
+			Location: domain.Location{Name: "Eiffel Tower", Latitude: 48.858, Longitude: 2.294, Timezone: "Europe/Paris"},
+			Type:     "attraction",
+		}}, nil
+	}
+	s := New(solar.New(domain.DefaultSettings()), search, 100)
+
+	var answer struct {
+		Results []map[string]any `json:"results"`
+	}
+	if rec := get(t, s, "/v1/search?q=eiffel+tower&limit=3", &answer); rec.Code != http.StatusOK {
+		t.Fatalf("status %d, want 200", rec.Code)
+	}
+	if gotQuery != "eiffel tower" || gotLimit != 3 {
+		t.Errorf("searched %q with limit %d, want \"eiffel tower\" and 3", gotQuery, gotLimit)
+	}
+	if len(answer.Results) != 1 || answer.Results[0]["name"] != "Eiffel Tower" ||
+		answer.Results[0]["latitude"] != 48.858 || answer.Results[0]["type"] != "attraction" {
+		t.Errorf("results %v, want the Eiffel Tower with its type", answer.Results)
+	}
+
+	get(t, s, "/v1/search?q=paris", nil)
+	if gotLimit != defaultSearchLimit {
+		t.Errorf("limit %d without the parameter, want %d", gotLimit, defaultSearchLimit)
+	}
+
+	for target, want := range map[string]int{
+		"/v1/search":                 http.StatusBadRequest,
+		"/v1/search?q=paris&limit=0": http.StatusBadRequest,
+		"/v1/search?q=fail":          http.StatusBadGateway,
+		"/v1/unknown":                http.StatusNotFound,
+	} {
+		if rec := get(t, s, target, nil); rec.Code != want {
+			t.Errorf("GET %s: status %d, want %d", target, rec.Code, want)
+		}
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/search?q=paris", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("POST: status %d, want 405", rec.Code)
+	}
+}
+
+func TestClientLimiter(t *testing.T) {
+	now := time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC)
+	l := newClientLimiter(2, func() time.Time { return now })
+
+	if l.take("a") != 0 || l.take("a") != 0 {
+		t.Fatal("burst of 2 refused")
+	}
+	if wait := l.take("a"); wait <= 0 || wait > 30*time.Second {
+		t.Errorf("third request: wait %v, want up to 30s", wait)
+	}
+	if l.take("b") != 0 {
+		t.Error("other client refused")
+	}
+
+	// Two requests per minute: one is back after 30 seconds
+	now = now.Add(30 * time.Second)
+	if l.take("a") != 0 {
+		t.Error("request after the refill refused")
+	}
+	if l.take("a") == 0 {
+		t.Error("second request after one refill allowed")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := New(solar.New(domain.DefaultSettings()), nil, 1)
+	if rec := get(t, s, "/v1/search", nil); rec.Code != http.StatusBadRequest {
+		t.Fatalf("first request: status %d, want 400", rec.Code)
+	}
+	rec := get(t, s, "/v1/search", nil)
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Errorf("second request: status %d, Retry-After %q; want 429 with a wait",
+			rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	s := New(solar.New(domain.DefaultSettings()), nil, 1)
+	get(t, s, "/v1/suntimes?lat=48.85&lon=2.35&date=2025-06-21", nil)
+	get(t, s, "/v1/suntimes", nil)
+	get(t, s, "/v1/unknown", nil)
+
+	// Scraping isn't rate limited, and counts itself
+	get(t, s, "/metrics", nil)
+	rec := get(t, s, "/metrics", nil)
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain") {
+		t.Fatalf("status %d, type %q; want 200 with text", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE gogoldenhour_api_requests_total counter\n",
+		`gogoldenhour_api_requests_total{endpoint="/v1/suntimes",code="429"} 1` + "\n",
+		`gogoldenhour_api_requests_total{endpoint="other",code="429"} 1` + "\n",
+		`gogoldenhour_api_requests_total{endpoint="/metrics",code="200"} 1` + "\n",
+		`gogoldenhour_operations_total{operation="sun_times"} `,
+		`gogoldenhour_operation_duration_seconds_count{operation="sun_times"} `,
+		"gogoldenhour_start_time_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics lack %q:\n%s", want, body)
+		}
+	}
+}
+
+func TestWriteMetrics(t *testing.T) {
+	report := stats.Report{
+		Since: time.Unix(1750000000, 0),
+		Operations: []stats.Operation{{
+			Name: stats.OpNominatim, Count: 4, Errors: 1, Total: 1500 * time.Millisecond, Slowest: 800 * time.Millisecond,
+		}},
+		Caches: []stats.Cache{{Name: "Elevation (Open-Meteo)", Hits: 3, Misses: 1}},
+	}
+	var b strings.Builder
+	writeMetrics(&b, &requestCounter{}, report)
+	for _, want := range []string{
+		`gogoldenhour_operations_total{operation="nominatim"} 4`,
+		`gogoldenhour_operation_errors_total{operation="nominatim"} 1`,
+		`gogoldenhour_operation_duration_seconds_sum{operation="nominatim"} 1.5`,
+		`gogoldenhour_operation_duration_seconds_max{operation="nominatim"} 0.8`,
+		`gogoldenhour_cache_lookups_total{cache="elevation_open_meteo",result="hit"} 3`,
+		`gogoldenhour_cache_lookups_total{cache="elevation_open_meteo",result="miss"} 1`,
+		"gogoldenhour_start_time_seconds 1750000000",
+	} {
+		if !strings.Contains(b.String(), want+"\n") {
+			t.Errorf("metrics lack %q:\n%s", want, b.String())
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Metrics
+// =============================================================================
+
+// metricsPath is the path of the metrics endpoint, which isn't rate limited
+// (see the package docs).
+const metricsPath = "/metrics"
+
+// metricIDs are the label values of the stats package's operations and
+// caches, which are named for the statistics dialog ("Nominatim request
+// (place search)"). Names not listed are converted with metricID.
+var metricIDs = map[string]string{
+	stats.OpSunTimes:    "sun_times",
+	stats.OpNominatim:   "nominatim",
+	stats.OpOpenMeteo:   "open_meteo",
+	stats.OpRainViewer:  "rainviewer",
+	stats.OpForecast:    "open_meteo_forecast",
+	stats.OpSWPC:        "noaa_swpc",
+	stats.OpIPAPI:       "ip_api",
+	stats.OpIPInfo:      "ipinfo",
+	stats.OpPushMessage: "push",
+	stats.OpMapTile:     "map_tile",
+	stats.OpCalDAV:      "caldav",
+	stats.OpWebhook:     "webhook",
+	stats.OpGitHub:      "github",
+
+	stats.CacheGeocodingSearch:  "geocoding_search",
+	stats.CacheGeocodingReverse: "geocoding_reverse",
+	stats.CacheMapTiles:         "map_tiles",
+	stats.CacheForecasts:        "weather_forecasts",
+	stats.CacheKpForecast:       "kp_forecast",
+	stats.CacheSunTimes:         "sun_times",
+}
+
+// nonMetricChars are the characters metricID replaces.
+var nonMetricChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// metricID returns the label value of a stats operation or cache, e.g.,
+// "nominatim".
+func metricID(name string) string {
+	if id, ok := metricIDs[name]; ok {
+		return id
+	}
+	return strings.Trim(nonMetricChars.ReplaceAllString(strings.ToLower(name), "_"), "_")
+}
+
+// requestKey identifies a count of requestCounter.
+type requestKey struct {
+	// endpoint is the path of one of the endpoints, or "other".
+	endpoint string
+
+	// code is the status code of the answer.
+	code int
+}
+
+// requestCounter counts the answered requests by endpoint and status.
+type requestCounter struct {
+	mu     sync.Mutex
+	counts map[requestKey]int
+}
+
+// add counts one answer.
+func (c *requestCounter) add(endpoint string, code int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[requestKey]int)
+	}
+	c.counts[requestKey{endpoint, code}]++
+}
+
+// snapshot returns the counts, sorted by endpoint and code.
+func (c *requestCounter) snapshot() ([]requestKey, map[requestKey]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	keys := make([]requestKey, 0, len(c.counts))
+	counts := make(map[requestKey]int, len(c.counts))
+	for key, n := range c.counts {
+		keys = append(keys, key)
+		counts[key] = n
+	}
+	slices.SortFunc(keys, func(a, b requestKey) int {
+		if a.endpoint != b.endpoint {
+			return strings.Compare(a.endpoint, b.endpoint)
+		}
+		return a.code - b.code
+	})
+	return keys, counts
+}
+
+// statusRecorder remembers the status code a handler answers with.
+type statusRecorder struct {
+	http.ResponseWriter
+	code int
+}
+
+// WriteHeader records the code and writes it.
+func (r *statusRecorder) WriteHeader(code int) {
+	r.code = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// endpointLabel returns the endpoint a request path counts for: one of the
+// API's paths, or "other" (so unknown paths can't add label values).
+func endpointLabel(path string) string {
+	switch path {
+	case "/v1/suntimes", "/v1/search", metricsPath:
+		return path
+	}
+	return "other"
+}
+
+// handleMetrics answers /metrics with the counts in the Prometheus text
+// format, for self-hosters monitoring the server:
+//
+//	gogoldenhour_api_requests_total{endpoint="/v1/suntimes",code="200"} 42
+//	gogoldenhour_operations_total{operation="sun_times"} 42
+//	gogoldenhour_operation_errors_total{operation="nominatim"} 1
+//	gogoldenhour_operation_duration_seconds_sum{operation="sun_times"} 0.0194
+//	gogoldenhour_operation_duration_seconds_count{operation="sun_times"} 42
+//	gogoldenhour_operation_duration_seconds_max{operation="nominatim"} 0.812
+//	gogoldenhour_cache_lookups_total{cache="geocoding_search",result="hit"} 7
+//
+// Operations and caches are those of the stats package (sun time
+// calculations, upstream web service requests and their errors), counted
+// since the server started.
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	writeMetrics(w, s.requests, stats.Snapshot())
+}
+
+// writeMetrics writes the request counts and the stats report in the
+// Prometheus text format (see handleMetrics).
+func writeMetrics(w io.Writer, requests *requestCounter, report stats.Report) {
+	family := func(name, kind, help string) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	}
+	seconds := func(d time.Duration) string {
+		return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+	}
+
+	family("gogoldenhour_api_requests_total", "counter", "API requests answered, by endpoint and status code.")
+	keys, counts := requests.snapshot()
+	for _, key := range keys {
+		fmt.Fprintf(w, "gogoldenhour_api_requests_total{endpoint=%q,code=\"%d\"} %d\n",
+			key.endpoint, key.code, counts[key])
+	}
+
+	family("gogoldenhour_operations_total", "counter",
+		"Sun time calculations and upstream web service requests.")
+	for _, op := range report.Operations {
+		fmt.Fprintf(w, "gogoldenhour_operations_total{operation=%q} %d\n", metricID(op.Name), op.Count)
+	}
+	family("gogoldenhour_operation_errors_total", "counter",
+		"Failed calculations and upstream requests (errors and non-200 answers).")
+	for _, op := range report.Operations {
+		fmt.Fprintf(w, "gogoldenhour_operation_errors_total{operation=%q} %d\n", metricID(op.Name), op.Errors)
+	}
+	family("gogoldenhour_operation_duration_seconds", "summary", "Time taken by the operations.")
+	for _, op := range report.Operations {
+		id := metricID(op.Name)
+		fmt.Fprintf(w, "gogoldenhour_operation_duration_seconds_sum{operation=%q} %s\n", id, seconds(op.Total))
+		fmt.Fprintf(w, "gogoldenhour_operation_duration_seconds_count{operation=%q} %d\n", id, op.Count)
+	}
+	family("gogoldenhour_operation_duration_seconds_max", "gauge", "Slowest run of each operation.")
+	for _, op := range report.Operations {
+		fmt.Fprintf(w, "gogoldenhour_operation_duration_seconds_max{operation=%q} %s\n",
+			metricID(op.Name), seconds(op.Slowest))
+	}
+
+	family("gogoldenhour_cache_lookups_total", "counter", "Cache lookups, by cache and result (hit or miss).")
+	for _, c := range report.Caches {
+		id := metricID(c.Name)
+		fmt.Fprintf(w, "gogoldenhour_cache_lookups_total{cache=%q,result=\"hit\"} %d\n", id, c.Hits)
+		fmt.Fprintf(w, "gogoldenhour_cache_lookups_total{cache=%q,result=\"miss\"} %d\n", id, c.Misses)
+	}
+
+	family("gogoldenhour_start_time_seconds", "gauge", "When counting started, in seconds since the Unix epoch.")
+	fmt.Fprintf(w, "gogoldenhour_start_time_seconds %d\n", report.Since.Unix())
+}
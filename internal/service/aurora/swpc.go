@@ -0,0 +1,210 @@
+// Package aurora provides the planetary K index forecast from NOAA's Space
+// Weather Prediction Center, for the aurora outlook of the Astro panel.
+//
+// The Kp index tells how disturbed the Earth's magnetic field is, and so
+// how far from the poles the aurora reaches (see domain.AuroraChance).
+// Together with the darkness of the night (solar.Darkness) it rates the
+// chance of seeing the aurora from the selected location.
+//
+// # SWPC Kp Index Forecast
+//
+// SWPC publishes its products as JSON files, free and without an API key:
+//
+//   - Observed and estimated Kp for the past week, predicted Kp for the
+//     next three days, in three-hour intervals (UTC)
+//   - Updated a few times a day
+//
+// Documentation: https://www.swpc.noaa.gov/products/planetary-k-index
+package aurora
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// kpForecastEndpoint is the URL of the Kp forecast product.
+	kpForecastEndpoint = "https://services.swpc.noaa.gov/products/noaa-planetary-k-index-forecast.json"
+
+	// kpForecastTTL is how long a forecast is reused before it's fetched
+	// again. The product is updated a few times a day, and the same
+	// forecast serves every place and date.
+	kpForecastTTL = time.Hour
+
+	// kpTimeLayout is the format of the product's times (UTC).
+	kpTimeLayout = "2006-01-02 15:04:05"
+)
+
+// =============================================================================
+// API Response Types
+// =============================================================================
+
+// kpForecastResponse is the Kp forecast product: a table whose first row
+// names the columns.
+//
+// Example response (abridged):
+//
+//	[["time_tag", "kp", "observed", "noaa_scale"],
+//	 ["2025-06-21 00:00:00", "2.33", "observed", null],
+//	 ["2025-06-24 21:00:00", "5.00", "predicted", "G1"]]
+//
+// The "observed" column is "observed", "estimated" or "predicted".
+type kpForecastResponse [][]*string
+
+// readings returns the table as Kp readings, leaving out rows that don't
+// parse.
+func (r kpForecastResponse) readings() ([]domain.KpReading, error) {
+	if len(r) == 0 {
+		return nil, fmt.Errorf("forecast table is empty")
+	}
+	columns := make(map[string]int)
+	for i, name := range r[0] {
+		if name != nil {
+			columns[*name] = i
+		}
+	}
+	timeCol, ok1 := columns["time_tag"]
+	kpCol, ok2 := columns["kp"]
+	if !ok1 || !ok2 {
+		return nil, fmt.Errorf("forecast table has no time_tag and kp columns")
+	}
+	stateCol, hasState := columns["observed"]
+
+	cell := func(row []*string, i int) string {
+		if i < len(row) && row[i] != nil {
+			return *row[i]
+		}
+		return ""
+	}
+	var readings []domain.KpReading
+	for _, row := range r[1:] {
+		t, err := time.Parse(kpTimeLayout, cell(row, timeCol))
+		if err != nil {
+			continue
+		}
+		kp, err := strconv.ParseFloat(cell(row, kpCol), 64)
+		if err != nil {
+			continue
+		}
+		readings = append(readings, domain.KpReading{
+			Time:      t,
+			Kp:        kp,
+			Predicted: hasState && cell(row, stateCol) == "predicted",
+		})
+	}
+	return readings, nil
+}
+
+// =============================================================================
+// Service
+// =============================================================================
+
+// SWPCService looks up the Kp index forecast from NOAA SWPC.
+//
+// The forecast is global, so one copy is kept for kpForecastTTL whatever
+// place or date it's asked for.
+//
+// Usage:
+//
+//	service := aurora.NewSWPCService()
+//	forecast, err := service.Forecast()
+//	if err != nil {
+//	    // Show no aurora outlook
+//	}
+//	dark, _ := solar.Darkness(location, date, domain.AuroraDarkElevation)
+//	outlook := domain.NewAuroraOutlook(location, dark, forecast)
+type SWPCService struct {
+	// client is the HTTP client used for API requests.
+	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	client *http.Client
+
+	// endpoint is the URL of the forecast product (kpForecastEndpoint; a
+	// test server in tests).
+	endpoint string
+
+	// now returns the current time (time.Now; a fake clock in tests).
+	now func() time.Time
+
+	// mu guards forecast and fetched.
+	mu sync.Mutex
+
+	// forecast is the last forecast, fetched at fetched (zero before the
+	// first).
+	forecast domain.KpForecast
+	fetched  time.Time
+}
+
+// NewSWPCService creates a new Kp forecast service.
+//
+// Returns a ready-to-use SWPCService instance.
+func NewSWPCService() *SWPCService {
+	return &SWPCService{
+		client: &http.Client{
+			Timeout: config.DefaultHTTPTimeout,
+		},
+		endpoint: kpForecastEndpoint,
+		now:      time.Now,
+	}
+}
+
+// Forecast fetches the Kp index of the past week and the next three days.
+//
+// This makes a network request (unless the forecast is cached) and must be
+// run in a background goroutine.
+//
+// Returns:
+//   - domain.KpForecast: The three-hour intervals
+//   - error: Non-nil if the request fails or returns no intervals
+func (s *SWPCService) Forecast() (domain.KpForecast, error) {
+	now := s.now()
+	s.mu.Lock()
+	hit := !s.fetched.IsZero() && now.Sub(s.fetched) < kpForecastTTL
+	forecast := s.forecast
+	s.mu.Unlock()
+	stats.CacheLookup(stats.CacheKpForecast, hit)
+	if hit {
+		return forecast, nil
+	}
+
+	start := time.Now()
+	resp, err := s.client.Get(s.endpoint)
+	stats.RecordRequest(stats.OpSWPC, time.Since(start), resp, err)
+	if err != nil {
+		return domain.KpForecast{}, fmt.Errorf("Kp forecast request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.KpForecast{}, fmt.Errorf("Kp forecast request returned status %d", resp.StatusCode)
+	}
+
+	var result kpForecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return domain.KpForecast{}, fmt.Errorf("failed to decode Kp forecast response: %w", err)
+	}
+	readings, err := result.readings()
+	if err != nil {
+		return domain.KpForecast{}, fmt.Errorf("unexpected Kp forecast response: %w", err)
+	}
+	if len(readings) == 0 {
+		return domain.KpForecast{}, fmt.Errorf("Kp forecast response has no intervals")
+	}
+
+	forecast = domain.KpForecast{Readings: readings}
+	s.mu.Lock()
+	s.forecast, s.fetched = forecast, now
+	s.mu.Unlock()
+	return forecast, nil
+}
@@ -0,0 +1,49 @@
+package aurora
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestForecast(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[["time_tag", "kp", "observed", "noaa_scale"],
+			["2025-06-21 00:00:00", "2.33", "observed", null],
+			["2025-06-21 03:00:00", null, "estimated", null],
+			["2025-06-24 21:00:00", "5.00", "predicted", "G1"]]`))
+	}))
+	defer server.Close()
+	now := time.Date(2025, 6, 22, 12, 0, 0, 0, time.UTC)
+	s := NewSWPCService()
+	s.endpoint = server.URL
+	s.now = func() time.Time { return now }
+
+	forecast, err := s.Forecast()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The interval without a Kp is left out
+	if len(forecast.Readings) != 2 {
+		t.Fatalf("readings %+v, want 2", forecast.Readings)
+	}
+	first, last := forecast.Readings[0], forecast.Readings[1]
+	if !first.Time.Equal(time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)) || first.Kp != 2.33 || first.Predicted {
+		t.Errorf("first reading %+v, want the observed Kp 2.33 at midnight", first)
+	}
+	if last.Kp != 5 || !last.Predicted {
+		t.Errorf("last reading %+v, want the predicted Kp 5", last)
+	}
+
+	// Asked again, the forecast comes from the cache until it's old
+	if _, err := s.Forecast(); err != nil || requests != 1 {
+		t.Errorf("second forecast: %v after %d requests, want the cached one", err, requests)
+	}
+	now = now.Add(kpForecastTTL)
+	if _, err := s.Forecast(); err != nil || requests != 2 {
+		t.Errorf("expired forecast: %v after %d requests, want a new request", err, requests)
+	}
+}
@@ -0,0 +1,188 @@
+package automation
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Command Templates
+// =============================================================================
+
+// TemplateData holds the values available to hook command placeholders.
+//
+// Placeholders use Go template syntax, e.g. {{.Time}}. Referencing a field
+// that doesn't exist is an error, so typos are reported instead of silently
+// producing an empty argument.
+type TemplateData struct {
+	// Event is the event kind identifier (e.g., "golden_evening_start").
+	Event string
+
+	// EventLabel is the human-readable event name (e.g., "Evening golden hour start").
+	EventLabel string
+
+	// Time is the event time in RFC 3339 format (e.g., "2026-06-21T20:31:00+02:00").
+	Time string
+
+	// Date is the event date (e.g., "2026-06-21").
+	Date string
+
+	// Clock is the event time of day in 24-hour format (e.g., "20:31").
+	Clock string
+
+	// Unix is the event time as seconds since the Unix epoch.
+	Unix int64
+
+	// Location is the location name (e.g., "Paris, France").
+	Location string
+
+	// Latitude and Longitude are the location's coordinates in decimal degrees.
+	Latitude  string
+	Longitude string
+}
+
+// NewTemplateData builds the placeholder values for an event at a location.
+func NewTemplateData(event domain.SunEvent, loc domain.Location) TemplateData {
+	return TemplateData{
+		Event:      string(event.Kind),
+		EventLabel: event.Kind.Label(),
+		Time:       event.Time.Format(time.RFC3339),
+		Date:       event.Time.Format("2006-01-02"),
+		Clock:      event.Time.Format("15:04"),
+		Unix:       event.Time.Unix(),
+		Location:   loc.Name,
+		Latitude:   fmt.Sprintf("%.6f", loc.Latitude),
+		Longitude:  fmt.Sprintf("%.6f", loc.Longitude),
+	}
+}
+
+// ExpandCommand splits a hook command into arguments and fills in placeholders.
+//
+// The command is split first and each argument is expanded separately, so
+// a placeholder value containing spaces or quotes (such as a location name)
+// always stays a single argument and can never inject extra arguments.
+//
+// Parameters:
+//   - command: The hook's command line (e.g., `notify-send "{{.EventLabel}}"`)
+//   - data: Placeholder values
+//
+// Returns:
+//   - []string: Program name followed by its arguments
+//   - error: Non-nil if the command is empty, has unbalanced quotes, or uses
+//     an unknown placeholder
+func ExpandCommand(command string, data TemplateData) ([]string, error) {
+	args, err := SplitCommandLine(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	for i, arg := range args {
+		// Skip template parsing for plain arguments
+		if !strings.Contains(arg, "{{") {
+			continue
+		}
+
+		tmpl, err := template.New("arg").Option("missingkey=error").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder in %q: %w", arg, err)
+		}
+
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, fmt.Errorf("invalid placeholder in %q: %w", arg, err)
+		}
+		args[i] = sb.String()
+	}
+
+	return args, nil
+}
+
+// SplitCommandLine splits a command line into arguments using shell-like rules.
+//
+// Supported syntax:
+//   - Whitespace separates arguments
+//   - 'single quotes' keep everything literally
+//   - "double quotes" keep whitespace; \" and \\ are escapes inside them
+//   - A backslash outside quotes escapes the next character
+//
+// No other shell features (variables, globbing, pipes, redirection) are
+// interpreted. Users who need them can run a shell explicitly, e.g.
+// `sh -c 'gphoto2 --capture-image && notify-send done'`.
+//
+// Returns an error if a quote is left unterminated.
+func SplitCommandLine(s string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		inArg   bool
+		quote   rune
+		escaped bool
+	)
+
+	for _, r := range s {
+		switch {
+		case escaped:
+			// Inside double quotes, only \" and \\ are escapes
+			if quote == '"' && r != '"' && r != '\\' {
+				current.WriteRune('\\')
+			}
+			current.WriteRune(r)
+			escaped = false
+
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+
+		case quote == '"':
+			switch r {
+			case '"':
+				quote = 0
+			case '\\':
+				escaped = true
+			default:
+				current.WriteRune(r)
+			}
+
+		case r == '\\':
+			escaped = true
+			inArg = true
+
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		current.WriteRune('\\')
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
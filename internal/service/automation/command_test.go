@@ -0,0 +1,115 @@
+package automation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSplitCommandLine(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: nil},
+		{name: "only whitespace", input: " \t\n ", want: nil},
+		{name: "plain words", input: "gphoto2 --capture-image", want: []string{"gphoto2", "--capture-image"}},
+		{name: "repeated whitespace", input: "  a \t b\n c  ", want: []string{"a", "b", "c"}},
+		{name: "double quotes keep spaces", input: `notify-send "Golden hour"`, want: []string{"notify-send", "Golden hour"}},
+		{name: "single quotes are literal", input: `sh -c 'echo "$HOME" \n'`, want: []string{"sh", "-c", `echo "$HOME" \n`}},
+		{name: "escapes in double quotes", input: `echo "a \"b\" c\\d \n"`, want: []string{"echo", `a "b" c\d \n`}},
+		{name: "backslash outside quotes", input: `echo a\ b \'c`, want: []string{"echo", "a b", "'c"}},
+		{name: "adjacent quoted parts join", input: `--name="a b"'c'd`, want: []string{"--name=a bcd"}},
+		{name: "empty quoted argument", input: `cmd "" ''`, want: []string{"cmd", "", ""}},
+		{name: "trailing backslash is kept", input: `echo a\`, want: []string{"echo", `a\`}},
+		{name: "unterminated double quote", input: `echo "abc`, wantErr: true},
+		{name: "unterminated single quote", input: `echo 'abc`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitCommandLine(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("SplitCommandLine(%q) = %q, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitCommandLine(%q): %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitCommandLine(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandCommand(t *testing.T) {
+	paris := time.FixedZone("CEST", 2*60*60)
+	event := domain.SunEvent{
+		Kind: domain.EventGoldenEveningStart,
+		Time: time.Date(2026, 6, 21, 20, 31, 0, 0, paris),
+	}
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: `Paris "Centre"; rm -rf ~`}
+	data := NewTemplateData(event, loc)
+
+	tests := []struct {
+		name    string
+		command string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "no placeholders",
+			command: "gphoto2 --capture-image",
+			want:    []string{"gphoto2", "--capture-image"},
+		},
+		{
+			name:    "time placeholders",
+			command: `echo {{.Date}} {{.Clock}} {{.Time}} {{.Unix}}`,
+			want:    []string{"echo", "2026-06-21", "20:31", "2026-06-21T20:31:00+02:00", "1782066660"},
+		},
+		{
+			name:    "event and coordinates",
+			command: `log {{.Event}} "{{.EventLabel}}" {{.Latitude}},{{.Longitude}}`,
+			want:    []string{"log", string(domain.EventGoldenEveningStart), domain.EventGoldenEveningStart.Label(), "48.856600,2.352200"},
+		},
+		{
+			name:    "values never split into more arguments",
+			command: `notify-send {{.Location}}`,
+			want:    []string{"notify-send", loc.Name},
+		},
+		{
+			name:    "placeholder inside a larger argument",
+			command: `gphoto2 --filename={{.Date}}-%n.jpg`,
+			want:    []string{"gphoto2", "--filename=2026-06-21-%n.jpg"},
+		},
+		{name: "empty command", command: "   ", wantErr: true},
+		{name: "unknown placeholder", command: "echo {{.Sunset}}", wantErr: true},
+		{name: "malformed placeholder", command: "echo {{.Time", wantErr: true},
+		{name: "unbalanced quotes", command: `echo "{{.Time}}`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandCommand(tt.command, data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ExpandCommand(%q) = %q, want error", tt.command, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandCommand(%q): %v", tt.command, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandCommand(%q) =\n  %q\nwant\n  %q", tt.command, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package automation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+)
+
+// =============================================================================
+// Widget Feed
+// =============================================================================
+
+// FeedResult describes one write of the widget feed.
+type FeedResult struct {
+	// Path is the feed file.
+	Path string
+
+	// Err is non-nil if the feed could not be built or written.
+	Err error
+}
+
+// FeedWriter rewrites the widget feed every few minutes (see
+// domain.WidgetFeed).
+//
+// Like SummaryScheduler, it never sleeps longer than checkInterval before
+// looking at the wall clock again, so the feed is fresh soon after the
+// computer wakes up (see the package docs). The feed is written as soon
+// as it's scheduled, then every WidgetFeed.IntervalMinutes.
+//
+// Only failures are reported, and only the first of a run of them, so a
+// missing folder doesn't raise an error every interval; the next write
+// that succeeds is reported again.
+//
+// Usage:
+//
+//	writer := automation.NewFeedWriter(func(r automation.FeedResult) {
+//	    mainthread.Wait(func() { /* show r in the UI */ })
+//	})
+//	writer.Schedule(settings) // again on every settings change
+type FeedWriter struct {
+	// mu guards all fields below except onResult and wake.
+	mu sync.Mutex
+
+	// settings is the configuration of the last Schedule call.
+	settings domain.Settings
+
+	// next is when the feed is written next.
+	next time.Time
+
+	// failing is true after a failed write, until one succeeds.
+	failing bool
+
+	// wake interrupts the check loop's wait (buffered, capacity 1).
+	wake chan struct{}
+
+	// running is true while the check loop goroutine is alive.
+	running bool
+
+	// generation increases on every Schedule/Stop; the check loop exits
+	// once it differs from the one it was started with.
+	generation int
+
+	// onResult is invoked after a write fails, or succeeds after a
+	// failure (may be nil).
+	onResult func(FeedResult)
+}
+
+// NewFeedWriter creates a writer that reports failed writes to onResult.
+//
+// The writer starts idle; call Schedule to arm it.
+func NewFeedWriter(onResult func(FeedResult)) *FeedWriter {
+	return &FeedWriter{onResult: onResult, wake: make(chan struct{}, 1)}
+}
+
+// Schedule arms the writer for the given settings, replacing the previous
+// configuration, and writes the feed right away. If the feed is disabled
+// or can't be written (see domain.WidgetFeed.Check), the writer stops.
+func (w *FeedWriter) Schedule(settings domain.Settings) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+
+	if !settings.WidgetFeed.Enabled || settings.WidgetFeed.Check() != nil {
+		return
+	}
+	w.settings = settings
+	w.next = time.Now()
+	w.failing = false
+	w.running = true
+	crash.Go(func() { w.run(w.generation) })
+}
+
+// Stop ends the feed updates. The file is left as it is.
+func (w *FeedWriter) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopLocked()
+}
+
+// stopLocked ends the check loop. Caller must hold w.mu.
+func (w *FeedWriter) stopLocked() {
+	w.generation++
+	if w.running {
+		w.running = false
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run is the check loop: it waits until the feed is due (at most
+// checkInterval at a time) and writes it.
+func (w *FeedWriter) run(generation int) {
+	for {
+		w.mu.Lock()
+		if w.generation != generation {
+			w.mu.Unlock()
+			return
+		}
+		wait := min(max(time.Until(w.next), 0), checkInterval)
+		w.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-w.wake:
+			timer.Stop()
+		}
+		w.check(generation, time.Now())
+	}
+}
+
+// check writes the feed if it is due at now, and arms the next write.
+//
+// Writing takes a few milliseconds of calculation, so unlike the summary it
+// runs on the check loop's goroutine.
+func (w *FeedWriter) check(generation int, now time.Time) {
+	w.mu.Lock()
+	// Compare wall clock times only (see package docs)
+	now = now.Round(0)
+	if w.generation != generation || now.Before(w.next) {
+		w.mu.Unlock()
+		return
+	}
+	settings := w.settings
+	w.next = now.Add(time.Duration(settings.WidgetFeed.IntervalMinutes) * time.Minute)
+	w.mu.Unlock()
+
+	err := WriteFeed(settings, now)
+
+	w.mu.Lock()
+	report := w.generation == generation && (err != nil) != w.failing
+	if report {
+		w.failing = err != nil
+	}
+	w.mu.Unlock()
+	if report && w.onResult != nil {
+		w.onResult(FeedResult{Path: settings.WidgetFeed.Path, Err: err})
+	}
+}
+
+// WriteFeed writes the widget feed of the home location once.
+//
+// This is used by the writer every interval, and by the "Write Now" button
+// in the preferences dialog. The file is replaced atomically (written to a
+// temporary file next to it, then renamed), so a widget never reads half a
+// feed.
+//
+// Parameters:
+//   - settings: Elevation angles, time format, home location and the feed
+//     configuration (Enabled is not checked)
+//   - now: The current time
+//
+// Returns an error if the configuration is incomplete (see
+// domain.WidgetFeed.Check), or the feed can't be calculated or written.
+func WriteFeed(settings domain.Settings, now time.Time) error {
+	config := settings.WidgetFeed
+	if err := config.Check(); err != nil {
+		return err
+	}
+
+	// From yesterday (a blue hour past midnight) until FeedHours ahead,
+	// with a private calculator like SendSummary
+	home := settings.Home()
+	tz, err := time.LoadLocation(home.Timezone)
+	if err != nil {
+		tz = time.Local
+	}
+	local := now.In(tz)
+	dates := domain.NewDateRange(local.AddDate(0, 0, -1), local.AddDate(0, 0, domain.FeedHours/24+1))
+	days, err := solar.New(settings.ForLocation(home)).CalculateRange(home, dates)
+	if err != nil {
+		return fmt.Errorf("failed to calculate the feed: %w", err)
+	}
+
+	var content []byte
+	if config.Format == domain.FeedFormatRSS {
+		content, err = export.FeedRSS(days, now, settings.TimeFormat24Hour, settings.PlaceNameStyle,
+			time.Duration(config.IntervalMinutes)*time.Minute)
+	} else {
+		content, err = export.FeedJSON(days, now, settings.TimeFormat24Hour, settings.PlaceNameStyle)
+	}
+	if err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(config.Path), ".feed-*")
+	if err != nil {
+		return fmt.Errorf("failed to write feed: %w", err)
+	}
+	_, err = temp.Write(content)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		// CreateTemp makes the file private; widgets may run as another user
+		err = os.Chmod(temp.Name(), 0o644)
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), config.Path)
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return fmt.Errorf("failed to write feed: %w", err)
+	}
+	return nil
+}
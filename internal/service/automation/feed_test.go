@@ -0,0 +1,105 @@
+package automation
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// feedSettings returns settings for a JSON feed of a home in Paris written
+// to path.
+func feedSettings(path string) domain.Settings {
+	settings := domain.DefaultSettings()
+	settings.HomeLocation = &domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris",
+		Timezone: "Europe/Paris"}
+	settings.WidgetFeed = domain.WidgetFeed{Enabled: true, Path: path, Format: domain.FeedFormatJSON,
+		IntervalMinutes: domain.DefaultFeedIntervalMinutes}
+	return settings
+}
+
+func TestWriteFeed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feed.json")
+	now := time.Date(2026, 6, 20, 17, 0, 0, 0, time.UTC)
+
+	if err := WriteFeed(feedSettings(path), now); err != nil {
+		t.Fatalf("WriteFeed() error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("feed not written: %v", err)
+	}
+	var doc struct {
+		Location struct{ Name string } `json:"location"`
+		Periods  []struct {
+			Name  string    `json:"name"`
+			Start time.Time `json:"start"`
+		} `json:"periods"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid feed: %v\n%s", err, data)
+	}
+	// Tonight's golden and blue hours come first, tomorrow's follow
+	if doc.Location.Name != "Paris" || len(doc.Periods) < 4 || doc.Periods[0].Name != "Evening golden hour" ||
+		doc.Periods[0].Start.Format(time.DateOnly) != "2026-06-20" {
+		t.Errorf("feed:\n%s", data)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+		t.Errorf("temporary files left: %v", entries)
+	}
+
+	settings := feedSettings(filepath.Join(t.TempDir(), "feed.xml"))
+	settings.WidgetFeed.Format = domain.FeedFormatRSS
+	if err := WriteFeed(settings, now); err != nil {
+		t.Fatalf("WriteFeed() RSS error: %v", err)
+	}
+	if data, _ := os.ReadFile(settings.WidgetFeed.Path); !strings.Contains(string(data), `<rss version="2.0">`) {
+		t.Errorf("RSS feed:\n%s", data)
+	}
+
+	if err := WriteFeed(feedSettings(filepath.Join(t.TempDir(), "missing", "feed.json")), now); err == nil {
+		t.Error("WriteFeed() into a missing folder succeeded")
+	}
+}
+
+func TestFeedWriterCheck(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 6, 20, 17, 0, 0, 0, time.Local)
+
+	var results []FeedResult
+	w := NewFeedWriter(func(r FeedResult) { results = append(results, r) })
+	w.settings = feedSettings(filepath.Join(dir, "missing", "feed.json"))
+
+	// A failure is reported once, and the next write is armed
+	w.next = now
+	w.check(w.generation, now)
+	if want := now.Add(domain.DefaultFeedIntervalMinutes * time.Minute); !w.next.Equal(want) {
+		t.Errorf("next = %v, want %v", w.next, want)
+	}
+	w.check(w.generation, w.next)
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("results after two failures = %+v, want one failure", results)
+	}
+
+	// Once the folder is there, the recovery is reported
+	if err := os.Mkdir(filepath.Join(dir, "missing"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w.check(w.generation, w.next)
+	w.check(w.generation, w.next)
+	if len(results) != 2 || results[1].Err != nil {
+		t.Errorf("results after the folder was made = %+v, want one success", results)
+	}
+
+	// Not yet due, or a stale generation: nothing happens
+	next := w.next
+	w.check(w.generation, next.Add(-time.Minute))
+	w.check(w.generation-1, next)
+	if !w.next.Equal(next) {
+		t.Errorf("next changed to %v", w.next)
+	}
+}
@@ -0,0 +1,42 @@
+package automation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Desktop Notifications
+// =============================================================================
+
+// NotificationText returns the title and text of the desktop notification
+// for an upcoming event (see domain.DesktopNotifications):
+//
+//	Evening golden hour start in 10 min
+//	20:31 at Pont Neuf
+//
+// Parameters:
+//   - settings: Time format and place name style
+//   - event: The upcoming event
+//   - loc: The place of the event; unnamed places are named by their
+//     coordinates
+//   - leadMinutes: How long before the event the notification is shown
+func NotificationText(settings domain.Settings, event domain.SunEvent, loc domain.Location, leadMinutes int) (title, message string) {
+	name := loc.DisplayName(settings.PlaceNameStyle)
+	if name == "" {
+		name = domain.FormatCoordinatesIn(loc.Latitude, loc.Longitude, settings.CoordinateFormat)
+	}
+	title = fmt.Sprintf("%s in %s", event.Kind.Label(), domain.FormatDuration(time.Duration(leadMinutes)*time.Minute))
+	message = domain.FormatTime(event.Time, settings.TimeFormat24Hour) + " at " + name
+	return title, message
+}
+
+// notify builds the result that asks the UI to show a desktop notification.
+// Nothing is shown here: notifications go through the tray icon, which
+// only the main thread may touch.
+func notify(settings domain.Settings, event domain.SunEvent, loc domain.Location, leadMinutes int) Result {
+	title, message := NotificationText(settings, event, loc, leadMinutes)
+	return Result{Event: event, Notification: true, Output: title, Message: message}
+}
@@ -0,0 +1,28 @@
+package automation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestNotificationText(t *testing.T) {
+	settings := domain.DefaultSettings()
+	paris, _ := time.LoadLocation("Europe/Paris")
+	event := domain.SunEvent{Kind: domain.EventGoldenEveningStart, Time: time.Date(2026, 6, 21, 20, 31, 0, 0, paris)}
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris", Name: "Pont Neuf"}
+
+	title, message := NotificationText(settings, event, loc, 10)
+	if want := "Evening golden hour start in 10 min"; title != want {
+		t.Errorf("title = %q, want %q", title, want)
+	}
+	if want := "20:31 at Pont Neuf"; message != want {
+		t.Errorf("message = %q, want %q", message, want)
+	}
+
+	result := notify(settings, event, loc, 90)
+	if !result.Notification || result.Output != "Evening golden hour start in 1h 30m" {
+		t.Errorf("notify() = %+v, want a notification 1h 30m ahead", result)
+	}
+}
@@ -0,0 +1,151 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Push Notifications
+// =============================================================================
+
+// pushoverURL is the Pushover message API (a variable for tests).
+var pushoverURL = "https://api.pushover.net/1/messages.json"
+
+// Pushover's message limits, in characters.
+const (
+	pushoverTitleLimit   = 250
+	pushoverMessageLimit = 1024
+)
+
+// pushClient sends push notifications and webhook requests. Like the other
+// web services, a request may take at most config.DefaultHTTPTimeout.
+var pushClient = &http.Client{Timeout: config.DefaultHTTPTimeout}
+
+// Push sends one notification through the configured service.
+//
+// The call blocks until the service answered, so callers on the UI thread
+// should run it in a goroutine.
+//
+// Parameters:
+//   - push: The service configuration (Enabled is not checked)
+//   - title: The notification title
+//   - message: The notification text (may span several lines)
+//
+// Returns an error if the configuration is incomplete (see
+// domain.PushNotifications.Check) or the service rejected the message.
+func Push(push domain.PushNotifications, title, message string) error {
+	if err := push.Check(); err != nil {
+		return err
+	}
+
+	var req *http.Request
+	var err error
+	switch push.Service {
+	case domain.PushServicePushover:
+		form := url.Values{
+			"token":   {push.PushoverToken},
+			"user":    {push.PushoverUser},
+			"title":   {truncate(title, pushoverTitleLimit)},
+			"message": {truncate(message, pushoverMessageLimit)},
+		}
+		req, err = http.NewRequest(http.MethodPost, pushoverURL, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	default:
+		// JSON publishing at the server root keeps the UTF-8 title intact,
+		// which HTTP headers (ntfy's other way to set a title) don't
+		body, _ := json.Marshal(map[string]string{
+			"topic":   push.NtfyTopic,
+			"title":   title,
+			"message": message,
+		})
+		req, err = http.NewRequest(http.MethodPost, strings.TrimRight(push.NtfyServer, "/")+"/", bytes.NewReader(body))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid push request: %w", err)
+	}
+
+	start := time.Now()
+	resp, err := pushClient.Do(req)
+	stats.RecordRequest(stats.OpPushMessage, time.Since(start), resp, err)
+	if err != nil {
+		return fmt.Errorf("failed to reach %s: %w", push.Service, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		// Both services explain the problem in the response body
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutputLength))
+		return fmt.Errorf("%s: %s: %s", push.Service, resp.Status, strings.TrimSpace(string(text)))
+	}
+	return nil
+}
+
+// SendSchedule pushes the schedule of the next domain.PushScheduleDays days
+// at a location, starting today.
+//
+// This backs the "Send 14-Day Schedule" button in the preferences dialog.
+// The call blocks while calculating and sending, so callers on the UI
+// thread should run it in a goroutine.
+//
+// Parameters:
+//   - settings: Elevation angles, time format and the push configuration
+//     (Push.Enabled is not checked)
+//   - loc: The location of the schedule
+//   - now: The current time; the schedule starts on its date
+func SendSchedule(settings domain.Settings, loc domain.Location, now time.Time) error {
+	calc := solar.New(settings)
+	var days []domain.SunTimes
+	for day := 0; day < domain.PushScheduleDays; day++ {
+		sunTimes, err := calc.Calculate(loc, now.AddDate(0, 0, day))
+		if err != nil {
+			return fmt.Errorf("failed to calculate the schedule: %w", err)
+		}
+		days = append(days, sunTimes)
+	}
+	title, body := export.PhoneSchedule(days, settings.TimeFormat24Hour, settings.PlaceNameStyle)
+	return Push(settings.Push, title, body)
+}
+
+// pushReminder pushes the reminder for an upcoming event.
+//
+// The title names the event and its time ("Evening golden hour start at
+// 20:31"), the message the place and how soon it is.
+func pushReminder(settings domain.Settings, event domain.SunEvent, loc domain.Location) Result {
+	title := fmt.Sprintf("%s at %s", event.Kind.Label(),
+		domain.FormatTime(event.Time, settings.TimeFormat24Hour))
+	message := loc.DisplayName(settings.PlaceNameStyle)
+	if message == "" {
+		message = domain.FormatCoordinatesIn(loc.Latitude, loc.Longitude, settings.CoordinateFormat)
+	}
+	if lead := settings.Push.LeadMinutes; lead > 0 {
+		message += fmt.Sprintf(", in %d minutes", lead)
+	}
+	return Result{Event: event, Reminder: true, Output: title,
+		Err: Push(settings.Push, title, message)}
+}
+
+// truncate shortens s to at most n characters, marking the cut with "…".
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
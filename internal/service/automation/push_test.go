@@ -0,0 +1,69 @@
+package automation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestPushNtfy(t *testing.T) {
+	var got map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/" {
+			t.Errorf("request %s %s, want POST /", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid JSON: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	push := domain.DefaultPushNotifications()
+	push.NtfyServer = server.URL
+	push.NtfyTopic = "golden-test"
+	if err := Push(push, "Évening golden hour", "Riverside"); err != nil {
+		t.Fatal(err)
+	}
+	if got["topic"] != "golden-test" || got["title"] != "Évening golden hour" || got["message"] != "Riverside" {
+		t.Errorf("published %v", got)
+	}
+}
+
+func TestPushPushover(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("token") != "apptoken" || r.FormValue("user") != "userkey" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":0,"errors":["user key is invalid"]}`))
+			return
+		}
+		if n := len([]rune(r.FormValue("message"))); n > pushoverMessageLimit {
+			t.Errorf("message of %d characters, want at most %d", n, pushoverMessageLimit)
+		}
+	}))
+	defer server.Close()
+	saved := pushoverURL
+	pushoverURL = server.URL
+	defer func() { pushoverURL = saved }()
+
+	push := domain.DefaultPushNotifications()
+	push.Service = domain.PushServicePushover
+	push.PushoverToken, push.PushoverUser = "apptoken", "userkey"
+	if err := Push(push, "Schedule", strings.Repeat("é", 2000)); err != nil {
+		t.Fatal(err)
+	}
+
+	push.PushoverUser = "wrongkey"
+	err := Push(push, "Schedule", "body")
+	if err == nil || !strings.Contains(err.Error(), "user key is invalid") {
+		t.Errorf("Push with a wrong key: err = %v, want the service's message", err)
+	}
+
+	push.PushoverToken = ""
+	if err := Push(push, "Schedule", "body"); err == nil {
+		t.Error("Push without a token succeeded")
+	}
+}
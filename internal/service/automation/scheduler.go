@@ -0,0 +1,573 @@
+// Package automation runs user-configured commands at sun phase transitions.
+//
+// Photographers can attach hooks (see domain.Hook) to events such as
+// "Evening golden hour start" to trigger cameras, start timelapse scripts, or
+// send notifications. This package turns the day's sun events into timers
+// and executes the matching commands when they fire.
+//
+// # Scheduling
+//
+// The Scheduler computes events for today and tomorrow at the current
+// location and queues one run per matching hook. It re-arms itself shortly
+// after local midnight, so hooks keep firing day after day without the
+// user touching the app. Any change to the location, settings, or hooks
+// should call Schedule again, which replaces all pending runs.
+//
+// Runs are due at wall-clock times. Go timers measure the monotonic clock,
+// which stops while the computer sleeps, so a plain timer for "sunset in
+// 3 hours" would fire late by however long a laptop was suspended. The
+// scheduler therefore never sleeps longer than checkInterval before
+// comparing the wall clock with the queue again. A run found more than
+// lateGrace past its time (the computer was asleep or busy) is skipped and
+// reported instead of starting a shoot after the light is gone.
+//
+// Hooks always follow the real current date, not the date selected in the
+// date panel: browsing next week's times must not trigger today's camera.
+//
+// # Daily Summary
+//
+// SummaryScheduler sends a plain-text overview of tomorrow's golden and
+// blue hours at the user's selected favorites once a day, at a configured
+// local time (see domain.DailySummary). The summary is written to a file,
+// piped to the local sendmail program, or sent through an SMTP relay.
+//
+// # Widget Feed
+//
+// FeedWriter rewrites a small JSON or RSS file of the home location's
+// upcoming golden and blue hours every few minutes (see
+// domain.WidgetFeed), for desktop widgets such as conky or Rainmeter.
+//
+// # Phone Reminders
+//
+// When push notifications are enabled (see domain.PushNotifications), the
+// Scheduler also queues a reminder some minutes before each selected event
+// and pushes it through ntfy or Pushover (see Push), independent of the
+// hooks and their master switch. SendSchedule pushes the times of the next
+// two weeks on demand.
+//
+// # Webhook
+//
+// When the webhook is enabled (see domain.Webhook), the Scheduler queues a
+// POST request some minutes before each selected event, at the current
+// location or at the selected favorites (see CallWebhook). Like the phone
+// reminders it doesn't depend on the hooks' master switch.
+//
+// # Desktop Notifications
+//
+// When desktop notifications are enabled (see domain.DesktopNotifications),
+// the Scheduler queues one notification per lead time before each selected
+// event, at the current location or at the selected favorites. It doesn't
+// show them itself: the result callback receives a Result with
+// Notification set, whose title and text the UI shows (see
+// NotificationText).
+//
+// # Safety
+//
+// Commands are executed directly (no shell), with placeholders expanded per
+// argument (see ExpandCommand). Each run is limited to commandTimeout. The
+// UI is responsible for asking the user to confirm commands before they are
+// saved; this package only runs hooks that are enabled and confirmed (see
+// domain.Hook.IsRunnable) while the master switch
+// (domain.Settings.AutomationEnabled) is on.
+//
+// # Thread Safety
+//
+// Schedule and Stop may be called from any goroutine. Hooks run in their
+// own goroutines, and the result callback is invoked from those goroutines;
+// UI code must switch to the main thread (mainthread.Wait) before touching
+// widgets.
+package automation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// commandTimeout is the longest a hook command may run before it is killed.
+	// Long enough for a burst of captures, short enough to catch a hung process.
+	commandTimeout = 10 * time.Minute
+
+	// maxOutputLength limits how much command output is kept in a Result.
+	// Only the tail is kept, since errors are usually printed last.
+	maxOutputLength = 500
+
+	// rescheduleDelay is how long after local midnight the scheduler re-arms.
+	// The small delay avoids scheduling right on a date boundary.
+	rescheduleDelay = time.Minute
+
+	// checkInterval is the longest the scheduler waits before re-reading the
+	// wall clock, which bounds how late a run fires after the system resumes.
+	checkInterval = 15 * time.Second
+
+	// lateGrace is how late a run may still start. Later runs are skipped:
+	// golden hour lasts tens of minutes, so a few minutes late is still
+	// useful, but a sunset hook an hour late is not.
+	lateGrace = 2 * time.Minute
+)
+
+// =============================================================================
+// Result
+// =============================================================================
+
+// Result describes one execution of a hook, one pushed reminder, one
+// webhook call, or one desktop notification due.
+type Result struct {
+	// Hook is the hook that was run (zero for reminders, webhook calls and
+	// notifications).
+	Hook domain.Hook
+
+	// Reminder is true if this was a push reminder instead of a hook run.
+	Reminder bool
+
+	// Webhook is true if this was a webhook call instead of a hook run.
+	Webhook bool
+
+	// Notification is true if a desktop notification is due instead of a
+	// hook run; the UI shows it.
+	Notification bool
+
+	// Event is the phase transition that triggered the run.
+	Event domain.SunEvent
+
+	// Output is the tail of the command's combined stdout/stderr, the
+	// title of a reminder or notification, or the place of a webhook call.
+	Output string
+
+	// Message is the text of a desktop notification.
+	Message string
+
+	// Err is non-nil if the command could not be started, exited with a
+	// non-zero status, or timed out, or if the reminder was not pushed or
+	// the webhook not called.
+	Err error
+}
+
+// =============================================================================
+// Scheduler
+// =============================================================================
+
+// Scheduler arms timers for hooks, reminders, webhook calls and desktop
+// notifications and runs them at the matching events.
+//
+// Usage:
+//
+//	scheduler := automation.NewScheduler(func(r automation.Result) {
+//	    mainthread.Wait(func() { /* show r in the UI */ })
+//	})
+//	scheduler.Schedule(location, settings)
+//	// ... later, on any change:
+//	scheduler.Schedule(newLocation, newSettings)
+type Scheduler struct {
+	// mu guards all fields below except onResult.
+	mu sync.Mutex
+
+	// pending holds the queued runs in chronological order.
+	pending []pendingRun
+
+	// rescheduleAt is when the queue is rebuilt for the next day.
+	rescheduleAt time.Time
+
+	// loc and settings are the configuration of the last Schedule call,
+	// reused when the scheduler re-arms itself after midnight.
+	loc      domain.Location
+	settings domain.Settings
+
+	// wake interrupts the check loop's wait (buffered, capacity 1).
+	wake chan struct{}
+
+	// running is true while the check loop goroutine is alive.
+	running bool
+
+	// generation increases on every Schedule/Stop. The check loop remembers
+	// the generation it was started in and exits once it has changed.
+	generation int
+
+	// onResult is invoked after every hook run, reminder and webhook call,
+	// and when a desktop notification is due (may be nil).
+	onResult func(Result)
+}
+
+// pendingRun is a hook queued to run at an event, or a reminder, webhook
+// call or desktop notification queued ahead of it.
+type pendingRun struct {
+	hook  domain.Hook
+	event domain.SunEvent
+
+	// at is when the run is due: the event time for hooks, LeadMinutes
+	// earlier for reminders, webhook calls and notifications.
+	at time.Time
+
+	// reminder is true for push reminders.
+	reminder bool
+
+	// webhook is true for webhook calls, which are made for place.
+	webhook bool
+	place   domain.Location
+
+	// notification is true for desktop notifications, which are shown for
+	// place lead minutes before the event.
+	notification bool
+	lead         int
+}
+
+// NewScheduler creates a scheduler that reports hook runs to onResult.
+//
+// The scheduler starts idle; call Schedule to arm it.
+func NewScheduler(onResult func(Result)) *Scheduler {
+	return &Scheduler{onResult: onResult, wake: make(chan struct{}, 1)}
+}
+
+// Schedule replaces all pending runs with ones for the given configuration.
+//
+// Events from now until the end of tomorrow are considered, so a hook for
+// an event later today fires today and one for an event that already passed
+// fires tomorrow. Hooks are only queued while automation is enabled,
+// reminders while push notifications are, webhook calls while the webhook
+// is, and desktop notifications while they are; without any of them, all
+// runs are simply cancelled.
+//
+// Parameters:
+//   - loc: The location to compute events for (its timezone is used)
+//   - settings: Elevation angles, the automation switch, the hooks, the
+//     push notifications, the webhook, the desktop notifications and the
+//     favorites they are for
+//
+// Returns an error if the sun events can't be calculated; in that case no
+// runs are queued.
+func (s *Scheduler) Schedule(loc domain.Location, settings domain.Settings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.scheduleLocked(loc, settings, time.Now())
+}
+
+// scheduleLocked rebuilds the queue for events after now. Caller must hold s.mu.
+func (s *Scheduler) scheduleLocked(loc domain.Location, settings domain.Settings, now time.Time) error {
+	s.stopLocked()
+	hooks := settings.AutomationEnabled && hasRunnableHooks(settings.Hooks)
+	reminders := settings.Push.Enabled && len(settings.Push.Events) > 0
+	webhooks := settings.Webhook.Enabled && len(settings.Webhook.Events) > 0
+	notifications := settings.Notifications.Enabled && len(settings.Notifications.Events) > 0
+	if !hooks && !reminders && !webhooks && !notifications {
+		return nil
+	}
+
+	// Events for today and tomorrow, computed with a private calculator
+	// because the App's calculator is not thread-safe
+	calc := solar.New(settings)
+	days, err := upcomingDays(calc, loc, now, 2)
+	if err != nil {
+		return err
+	}
+	var events []domain.SunEvent
+	for _, sunTimes := range days {
+		events = append(events, sunTimes.Events()...)
+	}
+	lastDay := days[len(days)-1]
+
+	lead := time.Duration(settings.Push.LeadMinutes) * time.Minute
+	for _, event := range events {
+		if reminders && slices.Contains(settings.Push.Events, event.Kind) && event.Time.Add(-lead).After(now) {
+			s.pending = append(s.pending, pendingRun{event: event, at: event.Time.Add(-lead), reminder: true})
+		}
+		if !hooks || !event.Time.After(now) {
+			continue
+		}
+		for _, hook := range settings.Hooks {
+			if hook.IsRunnable() && hook.Event == event.Kind {
+				s.pending = append(s.pending, pendingRun{hook: hook, event: event, at: event.Time})
+			}
+		}
+	}
+
+	if webhooks {
+		lead := time.Duration(settings.Webhook.LeadMinutes) * time.Minute
+		queueAtPlaces(calc, loc, events, settings.Webhook.Favorites, settings, now,
+			func(event domain.SunEvent, place domain.Location) {
+				if slices.Contains(settings.Webhook.Events, event.Kind) && event.Time.Add(-lead).After(now) {
+					s.pending = append(s.pending, pendingRun{event: event, at: event.Time.Add(-lead),
+						webhook: true, place: place})
+				}
+			})
+	}
+	if notifications {
+		queueAtPlaces(calc, loc, events, settings.Notifications.Favorites, settings, now,
+			func(event domain.SunEvent, place domain.Location) {
+				if !slices.Contains(settings.Notifications.Events, event.Kind) {
+					return
+				}
+				for _, minutes := range settings.Notifications.LeadMinutes {
+					at := event.Time.Add(-time.Duration(minutes) * time.Minute)
+					if at.After(now) {
+						s.pending = append(s.pending, pendingRun{event: event, at: at,
+							notification: true, place: place, lead: minutes})
+					}
+				}
+			})
+	}
+
+	// Events are in chronological order, but reminders are due before
+	// their event, so the queue is sorted by due time (stable, keeping
+	// hooks of one event in order)
+	slices.SortStableFunc(s.pending, func(a, b pendingRun) int {
+		return a.at.Compare(b.at)
+	})
+
+	// Re-arm after tomorrow's date begins, so there are always two days ahead
+	s.rescheduleAt = lastDay.Date.Add(rescheduleDelay)
+	s.loc, s.settings = loc, settings
+
+	s.running = true
+	crash.Go(func() { s.run(s.generation) })
+	return nil
+}
+
+// queueAtPlaces hands queue the upcoming events at the favorites with the
+// given IDs, or at loc (whose events are given) if there are none. Used for
+// the webhook calls and desktop notifications, which can follow favorites.
+//
+// Favorites may be in other timezones, where tomorrow can end hours before
+// the queue is rebuilt after loc's midnight, so three days are computed
+// for them. A favorite whose events can't be calculated is skipped rather
+// than keeping the hooks from being scheduled.
+func queueAtPlaces(calc *solar.Calculator, loc domain.Location, events []domain.SunEvent, favoriteIDs []string,
+	settings domain.Settings, now time.Time, queue func(event domain.SunEvent, place domain.Location)) {
+	places := []domain.Location{loc}
+	if len(favoriteIDs) > 0 {
+		places = nil
+		for _, id := range favoriteIDs {
+			for _, f := range settings.Favorites {
+				if f.ID == id {
+					places = append(places, f.Location)
+				}
+			}
+		}
+	}
+
+	for _, place := range places {
+		placeEvents := events
+		if len(favoriteIDs) > 0 {
+			days, err := upcomingDays(calc, place, now, 3)
+			if err != nil {
+				continue
+			}
+			placeEvents = nil
+			for _, sunTimes := range days {
+				placeEvents = append(placeEvents, sunTimes.Events()...)
+			}
+		}
+		for _, event := range placeEvents {
+			queue(event, place)
+		}
+	}
+}
+
+// upcomingDays calculates the sun times of n days at loc, starting with
+// the date of now.
+func upcomingDays(calc *solar.Calculator, loc domain.Location, now time.Time, n int) ([]domain.SunTimes, error) {
+	days := make([]domain.SunTimes, 0, n)
+	for day := 0; day < n; day++ {
+		sunTimes, err := calc.Calculate(loc, now.AddDate(0, 0, day))
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate events: %w", err)
+		}
+		days = append(days, sunTimes)
+	}
+	return days, nil
+}
+
+// run is the check loop: it waits until the next run is due (at most
+// checkInterval at a time), then starts due runs. It exits when the
+// generation changes.
+func (s *Scheduler) run(generation int) {
+	for {
+		s.mu.Lock()
+		if s.generation != generation {
+			s.mu.Unlock()
+			return
+		}
+		wait := s.nextWaitLocked(time.Now())
+		s.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		}
+		s.check(generation, time.Now())
+	}
+}
+
+// nextWaitLocked returns how long the check loop may sleep. Caller must hold s.mu.
+func (s *Scheduler) nextWaitLocked(now time.Time) time.Duration {
+	next := s.rescheduleAt
+	if len(s.pending) > 0 && s.pending[0].at.Before(next) {
+		next = s.pending[0].at
+	}
+	return min(max(next.Sub(now), 0), checkInterval)
+}
+
+// check starts the runs that are due at now, reports those that are too late,
+// and re-arms for the next day once rescheduleAt has passed.
+func (s *Scheduler) check(generation int, now time.Time) {
+	s.mu.Lock()
+	if s.generation != generation {
+		s.mu.Unlock()
+		return
+	}
+
+	// Compare wall clock times only (see package docs)
+	now = now.Round(0)
+	var due, missed []pendingRun
+	for len(s.pending) > 0 && !s.pending[0].at.After(now) {
+		run := s.pending[0]
+		s.pending = s.pending[1:]
+		if now.Sub(run.at) > lateGrace {
+			missed = append(missed, run)
+		} else {
+			due = append(due, run)
+		}
+	}
+	if !now.Before(s.rescheduleAt) {
+		// A failure here means the same calculation failed earlier; the next
+		// explicit Schedule call (location or settings change) will retry.
+		_ = s.scheduleLocked(s.loc, s.settings, now)
+	}
+	loc, settings := s.loc, s.settings
+	s.mu.Unlock()
+
+	for _, run := range missed {
+		s.report(Result{
+			Hook:         run.hook,
+			Reminder:     run.reminder,
+			Webhook:      run.webhook,
+			Notification: run.notification,
+			Event:        run.event,
+			Err: fmt.Errorf("missed by %v (was the computer asleep?)",
+				now.Sub(run.at).Round(time.Minute)),
+		})
+	}
+	for _, run := range due {
+		if run.notification {
+			s.report(notify(settings, run.event, run.place, run.lead))
+			continue
+		}
+		crash.Go(func() {
+			switch {
+			case run.reminder:
+				s.report(pushReminder(settings, run.event, loc))
+			case run.webhook:
+				s.report(callWebhook(settings, run.event, run.place))
+			default:
+				s.report(Run(run.hook, run.event, loc))
+			}
+		})
+	}
+}
+
+// Stop cancels all pending runs.
+//
+// Commands that are already running are not interrupted.
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+// stopLocked cancels all runs and ends the check loop. Caller must hold s.mu.
+func (s *Scheduler) stopLocked() {
+	s.pending = nil
+	s.generation++
+	if s.running {
+		s.running = false
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// report forwards a result to the callback, if any.
+func (s *Scheduler) report(result Result) {
+	if s.onResult != nil {
+		s.onResult(result)
+	}
+}
+
+// hasRunnableHooks reports whether at least one hook is enabled and confirmed.
+func hasRunnableHooks(hooks []domain.Hook) bool {
+	for _, h := range hooks {
+		if h.IsRunnable() {
+			return true
+		}
+	}
+	return false
+}
+
+// =============================================================================
+// Execution
+// =============================================================================
+
+// Run executes a hook's command for an event and waits for it to finish.
+//
+// This is used by the scheduler when a timer fires, and by the preferences
+// dialog's "Test" button (with a synthetic event at the current time).
+// The call blocks for as long as the command runs (up to commandTimeout),
+// so callers on the UI thread should run it in a goroutine.
+//
+// Parameters:
+//   - hook: The hook to run (Enabled is not checked)
+//   - event: The triggering event, used for placeholder values
+//   - loc: The location, used for placeholder values
+//
+// Returns a Result with the output tail and any error.
+func Run(hook domain.Hook, event domain.SunEvent, loc domain.Location) Result {
+	result := Result{Hook: hook, Event: event}
+
+	args, err := ExpandCommand(hook.Command, NewTemplateData(event, loc))
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("timed out after %v", commandTimeout)
+		}
+		result.Err = fmt.Errorf("%s: %w", args[0], err)
+	}
+
+	result.Output = tail(strings.TrimSpace(output.String()), maxOutputLength)
+	return result
+}
+
+// tail returns at most the last n bytes of s.
+func tail(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return "…" + s[len(s)-n:]
+}
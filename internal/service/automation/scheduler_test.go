@@ -0,0 +1,205 @@
+package automation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSchedulerCheck(t *testing.T) {
+	now := time.Date(2026, 6, 21, 21, 0, 0, 0, time.UTC)
+	hook := domain.Hook{Event: domain.EventGoldenEveningStart, Command: "true", Enabled: true}
+	hook.Confirmed = domain.CommandFingerprint(hook.Command)
+	at := func(offset time.Duration) pendingRun {
+		return pendingRun{hook: hook, event: domain.SunEvent{Kind: hook.Event, Time: now.Add(offset)}, at: now.Add(offset)}
+	}
+
+	results := make(chan Result, 10)
+	s := NewScheduler(func(r Result) { results <- r })
+	s.pending = []pendingRun{at(-time.Hour), at(-lateGrace / 2), at(0), at(time.Minute)}
+	s.rescheduleAt = now.Add(time.Hour)
+
+	s.check(s.generation, now)
+
+	if len(s.pending) != 1 || !s.pending[0].event.Time.Equal(now.Add(time.Minute)) {
+		t.Fatalf("pending after check = %+v, want only the future run", s.pending)
+	}
+
+	missed, ran := 0, 0
+	for i := 0; i < 3; i++ {
+		select {
+		case r := <-results:
+			if r.Err != nil && strings.Contains(r.Err.Error(), "missed") {
+				missed++
+				if !r.Event.Time.Equal(now.Add(-time.Hour)) {
+					t.Errorf("run at %v reported as missed", r.Event.Time)
+				}
+			} else {
+				ran++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for hook results")
+		}
+	}
+	if missed != 1 || ran != 2 {
+		t.Errorf("missed %d, ran %d; want 1 missed and 2 run", missed, ran)
+	}
+
+	// A stale generation must not touch the queue
+	s.check(s.generation-1, now.Add(2*time.Minute))
+	if len(s.pending) != 1 {
+		t.Errorf("check with a stale generation changed the queue")
+	}
+}
+
+func TestNextWait(t *testing.T) {
+	now := time.Date(2026, 6, 21, 12, 0, 0, 0, time.UTC)
+	hook := domain.Hook{Event: domain.EventGoldenEveningStart}
+
+	tests := []struct {
+		name       string
+		next       time.Duration // offset of the first pending run (0 = none)
+		reschedule time.Duration
+		want       time.Duration
+	}{
+		{"due soon", 5 * time.Second, time.Hour, 5 * time.Second},
+		{"far away is capped", 3 * time.Hour, 4 * time.Hour, checkInterval},
+		{"overdue", -time.Minute, time.Hour, 0},
+		{"reschedule first", 0, 2 * time.Second, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewScheduler(nil)
+			if tt.next != 0 {
+				s.pending = []pendingRun{{hook: hook, event: domain.SunEvent{Time: now.Add(tt.next)}, at: now.Add(tt.next)}}
+			}
+			s.rescheduleAt = now.Add(tt.reschedule)
+			if got := s.nextWaitLocked(now); got != tt.want {
+				t.Errorf("nextWaitLocked = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleReminders(t *testing.T) {
+	now := time.Date(2026, 6, 21, 4, 0, 0, 0, time.UTC)
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris"}
+	settings := domain.DefaultSettings()
+	settings.Push.Enabled = true
+	settings.Push.NtfyTopic = "golden-test"
+	settings.Push.Events = []domain.EventKind{domain.EventGoldenEveningStart}
+	settings.Push.LeadMinutes = 30
+
+	s := NewScheduler(nil)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.scheduleLocked(loc, settings, now); err != nil {
+		t.Fatal(err)
+	}
+	defer s.stopLocked()
+
+	// Hooks are off, so the queue holds today's and tomorrow's reminder
+	if len(s.pending) != 2 {
+		t.Fatalf("queued %d runs, want 2 reminders", len(s.pending))
+	}
+	for _, run := range s.pending {
+		if !run.reminder || run.event.Kind != domain.EventGoldenEveningStart {
+			t.Errorf("queued %+v, want a golden hour reminder", run)
+		}
+		if lead := run.event.Time.Sub(run.at); lead != 30*time.Minute {
+			t.Errorf("reminder lead = %v, want 30m", lead)
+		}
+	}
+
+	// A hook on the same event is due after its reminder
+	hook := domain.Hook{Event: domain.EventGoldenEveningStart, Command: "true", Enabled: true}
+	hook.Confirmed = domain.CommandFingerprint(hook.Command)
+	settings.AutomationEnabled = true
+	settings.Hooks = []domain.Hook{hook}
+	if err := s.scheduleLocked(loc, settings, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.pending) != 4 || !s.pending[0].reminder || s.pending[1].reminder {
+		t.Fatalf("queue = %+v, want reminder then hook for each day", s.pending)
+	}
+
+	settings.Push.Enabled = false
+	settings.AutomationEnabled = false
+	if err := s.scheduleLocked(loc, settings, now); err != nil || len(s.pending) != 0 {
+		t.Errorf("with both off: queued %d runs, err %v; want none", len(s.pending), err)
+	}
+}
+
+func TestScheduleWebhooks(t *testing.T) {
+	now := time.Date(2026, 6, 21, 4, 0, 0, 0, time.UTC)
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris"}
+	settings := domain.DefaultSettings()
+	settings.Webhook.Enabled = true
+	settings.Webhook.URL = "https://example.com/hook"
+	settings.Webhook.Events = []domain.EventKind{domain.EventSunset}
+
+	s := NewScheduler(nil)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.scheduleLocked(loc, settings, now); err != nil {
+		t.Fatal(err)
+	}
+	defer s.stopLocked()
+
+	// Without favorites, today's and tomorrow's sunset at the location
+	if len(s.pending) != 2 {
+		t.Fatalf("queued %d runs, want 2 webhook calls", len(s.pending))
+	}
+	for _, run := range s.pending {
+		if !run.webhook || run.place != loc || run.event.Kind != domain.EventSunset {
+			t.Errorf("queued %+v, want a sunset webhook call in Paris", run)
+		}
+	}
+
+	// With a favorite, three days there instead
+	tokyo := domain.Location{Latitude: 35.6762, Longitude: 139.6503, Timezone: "Asia/Tokyo", Name: "Tokyo"}
+	settings.Favorites = []domain.Favorite{{ID: "tokyo", Location: tokyo}}
+	settings.Webhook.Favorites = []string{"tokyo"}
+	if err := s.scheduleLocked(loc, settings, now); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.pending) != 3 {
+		t.Fatalf("queued %d runs, want 3 webhook calls", len(s.pending))
+	}
+	for _, run := range s.pending {
+		if run.place != tokyo {
+			t.Errorf("queued a webhook call at %+v, want Tokyo", run.place)
+		}
+	}
+}
+
+func TestScheduleNotifications(t *testing.T) {
+	now := time.Date(2026, 6, 21, 4, 0, 0, 0, time.UTC)
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris"}
+	settings := domain.DefaultSettings()
+	settings.Notifications.Enabled = true
+	settings.Notifications.Events = []domain.EventKind{domain.EventSunset}
+	settings.Notifications.LeadMinutes = []int{30, 10}
+
+	s := NewScheduler(nil)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.scheduleLocked(loc, settings, now); err != nil {
+		t.Fatal(err)
+	}
+	defer s.stopLocked()
+
+	// Two notifications before each of today's and tomorrow's sunsets
+	if len(s.pending) != 4 {
+		t.Fatalf("queued %d runs, want 4 notifications", len(s.pending))
+	}
+	for i, run := range s.pending {
+		want := []int{30, 10}[i%2]
+		if !run.notification || run.lead != want || !run.at.Equal(run.event.Time.Add(-time.Duration(want)*time.Minute)) {
+			t.Errorf("queued %+v, want a notification %d minutes before sunset", run, want)
+		}
+	}
+}
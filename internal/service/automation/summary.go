@@ -0,0 +1,355 @@
+package automation
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+)
+
+// =============================================================================
+// Daily Summary
+// =============================================================================
+
+const (
+	// summaryLateGrace is how late the daily summary may still be sent, e.g.
+	// after the computer wakes up. Unlike a hook, a plan for tomorrow is
+	// still useful a while after its usual time.
+	summaryLateGrace = 2 * time.Hour
+
+	// deliveryTimeout is the longest sending one summary may take.
+	deliveryTimeout = time.Minute
+)
+
+// SummaryResult describes one delivery of the daily summary.
+type SummaryResult struct {
+	// Target is where the summary was sent: the file path or the email
+	// address.
+	Target string
+
+	// Err is non-nil if the summary could not be built or delivered, or
+	// was skipped because its time passed while the computer was asleep.
+	Err error
+}
+
+// SummaryScheduler sends the daily summary at its configured time.
+//
+// Like Scheduler, it never sleeps longer than checkInterval before looking
+// at the wall clock again, so it isn't thrown off by system sleep (see the
+// package docs). A summary found more than summaryLateGrace past its time
+// is skipped and reported.
+//
+// Usage:
+//
+//	scheduler := automation.NewSummaryScheduler(func(r automation.SummaryResult) {
+//	    mainthread.Wait(func() { /* show r in the UI */ })
+//	})
+//	scheduler.Schedule(settings) // again on every settings change
+type SummaryScheduler struct {
+	// mu guards all fields below except onResult and wake.
+	mu sync.Mutex
+
+	// settings is the configuration of the last Schedule call.
+	settings domain.Settings
+
+	// next is when the summary is due next.
+	next time.Time
+
+	// wake interrupts the check loop's wait (buffered, capacity 1).
+	wake chan struct{}
+
+	// running is true while the check loop goroutine is alive.
+	running bool
+
+	// generation increases on every Schedule/Stop; the check loop exits
+	// once it differs from the one it was started with.
+	generation int
+
+	// onResult is invoked after every delivery attempt (may be nil).
+	onResult func(SummaryResult)
+}
+
+// NewSummaryScheduler creates a scheduler that reports deliveries to onResult.
+//
+// The scheduler starts idle; call Schedule to arm it.
+func NewSummaryScheduler(onResult func(SummaryResult)) *SummaryScheduler {
+	return &SummaryScheduler{onResult: onResult, wake: make(chan struct{}, 1)}
+}
+
+// Schedule arms the scheduler for the given settings, replacing the
+// previous configuration. If the daily summary is disabled or its time is
+// invalid, the scheduler stops.
+func (s *SummaryScheduler) Schedule(settings domain.Settings) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+
+	hour, minute, err := domain.ParseSummaryTime(settings.DailySummary.Time)
+	if !settings.DailySummary.Enabled || err != nil {
+		return
+	}
+	s.settings = settings
+	s.next = nextDailyTime(time.Now(), hour, minute)
+	s.running = true
+	crash.Go(func() { s.run(s.generation) })
+}
+
+// Stop cancels the daily summary. A delivery in progress is not interrupted.
+func (s *SummaryScheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopLocked()
+}
+
+// stopLocked ends the check loop. Caller must hold s.mu.
+func (s *SummaryScheduler) stopLocked() {
+	s.generation++
+	if s.running {
+		s.running = false
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run is the check loop: it waits until the summary is due (at most
+// checkInterval at a time), sends it and arms the next day.
+func (s *SummaryScheduler) run(generation int) {
+	for {
+		s.mu.Lock()
+		if s.generation != generation {
+			s.mu.Unlock()
+			return
+		}
+		wait := min(max(time.Until(s.next), 0), checkInterval)
+		s.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-s.wake:
+			timer.Stop()
+		}
+		s.check(generation, time.Now())
+	}
+}
+
+// check sends the summary if it is due at now, or reports it as missed.
+func (s *SummaryScheduler) check(generation int, now time.Time) {
+	s.mu.Lock()
+	// Compare wall clock times only (see package docs)
+	now = now.Round(0)
+	if s.generation != generation || now.Before(s.next) {
+		s.mu.Unlock()
+		return
+	}
+	due, settings := s.next, s.settings
+	hour, minute, _ := domain.ParseSummaryTime(settings.DailySummary.Time)
+	s.next = nextDailyTime(now, hour, minute)
+	s.mu.Unlock()
+
+	if late := now.Sub(due); late > summaryLateGrace {
+		s.report(SummaryResult{
+			Target: summaryTarget(settings.DailySummary),
+			Err: fmt.Errorf("missed by %v (was the computer asleep?)",
+				late.Round(time.Minute)),
+		})
+		return
+	}
+	crash.Go(func() {
+		s.report(SendSummary(settings, now))
+	})
+}
+
+// report forwards a result to the callback, if any.
+func (s *SummaryScheduler) report(result SummaryResult) {
+	if s.onResult != nil {
+		s.onResult(result)
+	}
+}
+
+// nextDailyTime returns the next time after now that the local clock shows
+// hour:minute.
+func nextDailyTime(now time.Time, hour, minute int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, hour, minute, 0, 0, now.Location())
+	}
+	return next
+}
+
+// SendSummary builds the summary for the day after now and delivers it.
+//
+// This is used by the scheduler at the configured time, and by the "Send
+// Now" button in the preferences dialog. The call blocks until the summary
+// is delivered (up to deliveryTimeout), so callers on the UI thread should
+// run it in a goroutine.
+//
+// Parameters:
+//   - settings: Elevation angles, time format, favorites and the summary
+//     configuration (Enabled is not checked)
+//   - now: The current time; the summary covers the following day
+//
+// Returns a SummaryResult with the target and any error.
+func SendSummary(settings domain.Settings, now time.Time) SummaryResult {
+	config := settings.DailySummary
+	result := SummaryResult{Target: summaryTarget(config)}
+	if err := config.Check(); err != nil {
+		result.Err = err
+		return result
+	}
+
+	// Tomorrow's times at each selected favorite, with a private
+	// calculator because the App's calculator is not thread-safe
+	calc := solar.New(settings)
+	tomorrow := now.AddDate(0, 0, 1)
+	var days []domain.SunTimes
+	for _, id := range config.Favorites {
+		for _, f := range settings.Favorites {
+			if f.ID != id {
+				continue
+			}
+			day, err := calc.Calculate(f.Location, tomorrow)
+			if err != nil {
+				result.Err = fmt.Errorf("%s: %w", f.Location.Name, err)
+				return result
+			}
+			days = append(days, day)
+		}
+	}
+	subject, body := export.DailySummary(tomorrow, days, settings.TimeFormat24Hour, settings.CoordinateFormat,
+		settings.PlaceNameStyle)
+
+	switch config.Delivery {
+	case domain.SummaryDeliverySendmail:
+		result.Err = sendmail(config.SendmailPath, config.EmailTo,
+			buildEmail(config.EmailTo, subject, body, now))
+	case domain.SummaryDeliverySMTP:
+		result.Err = sendSMTP(config.SMTPServer, config.EmailTo,
+			buildEmail(config.EmailTo, subject, body, now))
+	default:
+		if err := os.WriteFile(config.FilePath, []byte(body), 0o644); err != nil {
+			result.Err = fmt.Errorf("failed to write summary: %w", err)
+		}
+	}
+	return result
+}
+
+// summaryTarget returns where a summary configuration delivers to.
+func summaryTarget(config domain.DailySummary) string {
+	if config.Delivery == domain.SummaryDeliveryFile {
+		return config.FilePath
+	}
+	return config.EmailTo
+}
+
+// buildEmail formats a plain-text email from and to the same address.
+//
+// The subject is MIME-encoded if it isn't plain ASCII; the body is sent as
+// 8-bit UTF-8 with CRLF line endings.
+func buildEmail(address, subject, body string, date time.Time) []byte {
+	var msg bytes.Buffer
+	header := func(name, value string) {
+		msg.WriteString(name + ": " + value + "\r\n")
+	}
+	header("From", address)
+	header("To", address)
+	header("Subject", mime.QEncoding.Encode("utf-8", subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("MIME-Version", "1.0")
+	header("Content-Type", "text/plain; charset=utf-8")
+	header("Content-Transfer-Encoding", "8bit")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.TrimRight(body, "\n"), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes()
+}
+
+// sendmail pipes a message to a sendmail-compatible program.
+//
+// -i keeps a line with a single dot from ending the message early; the
+// recipient is passed as an argument rather than read from the headers.
+func sendmail(program, to string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancel()
+
+	var output bytes.Buffer
+	cmd := exec.CommandContext(ctx, program, "-i", "--", to)
+	cmd.Stdin = bytes.NewReader(msg)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Run(); err != nil {
+		if text := tail(strings.TrimSpace(output.String()), maxOutputLength); text != "" {
+			return fmt.Errorf("%s: %w: %s", program, err, text)
+		}
+		return fmt.Errorf("%s: %w", program, err)
+	}
+	return nil
+}
+
+// sendSMTP sends a message through an SMTP server without logging in.
+//
+// This does what smtp.SendMail does (including STARTTLS when the server
+// offers it), but within deliveryTimeout, so an unresponsive server can't
+// hold the delivery goroutine forever.
+func sendSMTP(server, to string, msg []byte) error {
+	host, _, err := net.SplitHostPort(server)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP server: %w", err)
+	}
+
+	conn, err := net.DialTimeout("tcp", server, deliveryTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(deliveryTimeout)); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return fmt.Errorf("SMTP server: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS: %w", err)
+		}
+	}
+	if err := client.Mail(to); err != nil {
+		return fmt.Errorf("SMTP MAIL: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("SMTP RCPT: %w", err)
+	}
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP DATA: %w", err)
+	}
+	if err := client.Quit(); err != nil {
+		return fmt.Errorf("SMTP QUIT: %w", err)
+	}
+	return nil
+}
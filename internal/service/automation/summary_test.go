@@ -0,0 +1,158 @@
+package automation
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// summarySettings returns settings with one favorite selected for a file
+// summary written to path.
+func summarySettings(path string) domain.Settings {
+	settings := domain.DefaultSettings()
+	settings.Favorites = []domain.Favorite{{
+		ID:       "bridge",
+		Location: domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Riverside Bridge", Timezone: "Europe/Paris"},
+	}}
+	settings.DailySummary.Enabled = true
+	settings.DailySummary.Favorites = []string{"bridge"}
+	settings.DailySummary.FilePath = path
+	return settings
+}
+
+func TestNextDailyTime(t *testing.T) {
+	now := time.Date(2026, 6, 21, 18, 30, 0, 0, time.UTC)
+	tests := []struct {
+		hour, minute int
+		want         time.Time
+	}{
+		{19, 0, time.Date(2026, 6, 21, 19, 0, 0, 0, time.UTC)},
+		{18, 30, time.Date(2026, 6, 22, 18, 30, 0, 0, time.UTC)},
+		{7, 15, time.Date(2026, 6, 22, 7, 15, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		if got := nextDailyTime(now, tt.hour, tt.minute); !got.Equal(tt.want) {
+			t.Errorf("nextDailyTime(%02d:%02d) = %v, want %v", tt.hour, tt.minute, got, tt.want)
+		}
+	}
+}
+
+func TestSendSummaryToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tomorrow.txt")
+	now := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+
+	result := SendSummary(summarySettings(path), now)
+	if result.Err != nil {
+		t.Fatalf("SendSummary() error: %v", result.Err)
+	}
+	if result.Target != path {
+		t.Errorf("Target = %q, want %q", result.Target, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("summary not written: %v", err)
+	}
+	for _, want := range []string{"Sunday, June 21, 2026", "Riverside Bridge (Europe/Paris)", "Morning  blue"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("summary lacks %q:\n%s", want, data)
+		}
+	}
+
+	// An unusable configuration is reported, not delivered
+	settings := summarySettings("")
+	if result := SendSummary(settings, now); result.Err == nil {
+		t.Error("SendSummary() without a file succeeded")
+	}
+}
+
+func TestSendSummaryWithSendmail(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a shell script as the sendmail program")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "message")
+	program := filepath.Join(dir, "sendmail")
+	script := "#!/bin/sh\necho \"$@\" > " + out + ".args\ncat > " + out + "\n"
+	if err := os.WriteFile(program, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	settings := summarySettings("")
+	settings.DailySummary.Delivery = domain.SummaryDeliverySendmail
+	settings.DailySummary.SendmailPath = program
+	settings.DailySummary.EmailTo = "me@example.com"
+
+	now := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+	if result := SendSummary(settings, now); result.Err != nil {
+		t.Fatalf("SendSummary() error: %v", result.Err)
+	}
+	args, _ := os.ReadFile(out + ".args")
+	if got := strings.TrimSpace(string(args)); got != "-i -- me@example.com" {
+		t.Errorf("sendmail arguments = %q", got)
+	}
+	msg, _ := os.ReadFile(out)
+	for _, want := range []string{"To: me@example.com\r\n", "Subject: Golden hour for Sunday, June 21, 2026\r\n", "\r\n\r\nGolden and blue hours"} {
+		if !strings.Contains(string(msg), want) {
+			t.Errorf("message lacks %q:\n%s", want, msg)
+		}
+	}
+}
+
+func TestBuildEmail(t *testing.T) {
+	date := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+	msg := string(buildEmail("me@example.com", "Golden hour · Zürich", "line 1\nline 2\n", date))
+
+	if !strings.Contains(msg, "Subject: =?utf-8?q?") {
+		t.Errorf("non-ASCII subject not encoded:\n%s", msg)
+	}
+	if !strings.Contains(msg, "Date: Sat, 20 Jun 2026 19:00:00 +0000\r\n") {
+		t.Errorf("missing or malformed Date header:\n%s", msg)
+	}
+	if !strings.HasSuffix(msg, "\r\n\r\nline 1\r\nline 2\r\n") {
+		t.Errorf("body not in CRLF form:\n%q", msg)
+	}
+}
+
+func TestSummarySchedulerCheck(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tomorrow.txt")
+	now := time.Date(2026, 6, 20, 19, 0, 0, 0, time.Local)
+
+	results := make(chan SummaryResult, 2)
+	s := NewSummaryScheduler(func(r SummaryResult) { results <- r })
+	s.settings = summarySettings(path)
+
+	// Slept through the summary time: skipped and re-armed for tomorrow
+	s.next = now.Add(-summaryLateGrace - time.Minute)
+	s.check(s.generation, now)
+	if r := <-results; r.Err == nil || !strings.Contains(r.Err.Error(), "missed") {
+		t.Errorf("late summary result = %+v, want missed", r)
+	}
+	if want := time.Date(2026, 6, 21, 19, 0, 0, 0, time.Local); !s.next.Equal(want) {
+		t.Errorf("next = %v, want %v", s.next, want)
+	}
+
+	// Due: delivered
+	s.next = now
+	s.check(s.generation, now)
+	select {
+	case r := <-results:
+		if r.Err != nil {
+			t.Errorf("due summary failed: %v", r.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the summary")
+	}
+
+	// Not yet due, or a stale generation: nothing happens
+	next := s.next
+	s.check(s.generation, now)
+	s.check(s.generation-1, next)
+	if !s.next.Equal(next) {
+		t.Errorf("next changed to %v", s.next)
+	}
+}
@@ -0,0 +1,140 @@
+package automation
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Webhook
+// =============================================================================
+
+// WebhookPayload is the JSON body POSTed to the webhook (see
+// domain.Webhook):
+//
+//	{
+//	  "event": "golden_evening_start",
+//	  "label": "Evening golden hour start",
+//	  "time": "2026-06-21T20:31:00+02:00",
+//	  "minutes_before": 15,
+//	  "location": {"name": "Pont Neuf", "latitude": 48.8566, "longitude": 2.3522, "timezone": "Europe/Paris"},
+//	  "value1": "Evening golden hour start",
+//	  "value2": "20:31",
+//	  "value3": "Pont Neuf"
+//	}
+//
+// The value fields are what IFTTT's "Receive a web request" trigger passes
+// on to its actions; other services can use the structured fields.
+type WebhookPayload struct {
+	// Event is the event's stable identifier (see domain.EventKind).
+	Event domain.EventKind `json:"event"`
+
+	// Label is the event's display name.
+	Label string `json:"label"`
+
+	// Time is when the event happens, in RFC 3339 with the place's offset.
+	Time string `json:"time"`
+
+	// MinutesBefore is how long before the event the request was sent.
+	MinutesBefore int `json:"minutes_before"`
+
+	// Location is the place the event was calculated for.
+	Location WebhookLocation `json:"location"`
+
+	// Value1, Value2 and Value3 are the label, the time of day in the
+	// user's format and the place name, for IFTTT.
+	Value1 string `json:"value1"`
+	Value2 string `json:"value2"`
+	Value3 string `json:"value3"`
+}
+
+// WebhookLocation is the place of a WebhookPayload.
+type WebhookLocation struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Timezone  string  `json:"timezone"`
+}
+
+// NewWebhookPayload builds the request body for an upcoming event.
+//
+// Parameters:
+//   - settings: Time format, place name style and the webhook's lead time
+//   - event: The upcoming event
+//   - loc: The place of the event; unnamed places are named by their
+//     coordinates
+func NewWebhookPayload(settings domain.Settings, event domain.SunEvent, loc domain.Location) WebhookPayload {
+	name := loc.DisplayName(settings.PlaceNameStyle)
+	if name == "" {
+		name = domain.FormatCoordinatesIn(loc.Latitude, loc.Longitude, settings.CoordinateFormat)
+	}
+	return WebhookPayload{
+		Event:         event.Kind,
+		Label:         event.Kind.Label(),
+		Time:          event.Time.Format(time.RFC3339),
+		MinutesBefore: settings.Webhook.LeadMinutes,
+		Location: WebhookLocation{
+			Name:      name,
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Timezone:  loc.Timezone,
+		},
+		Value1: event.Kind.Label(),
+		Value2: domain.FormatTime(event.Time, settings.TimeFormat24Hour),
+		Value3: name,
+	}
+}
+
+// CallWebhook POSTs a payload to the webhook.
+//
+// The call blocks until the server answered, so callers on the UI thread
+// should run it in a goroutine.
+//
+// Parameters:
+//   - webhook: The webhook configuration (Enabled is not checked)
+//   - payload: The request body (see NewWebhookPayload)
+//
+// Returns an error if the URL is invalid (see domain.Webhook.Check) or the
+// server doesn't answer with a 2xx status.
+func CallWebhook(webhook domain.Webhook, payload WebhookPayload) error {
+	if err := webhook.Check(); err != nil {
+		return err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook request: %w", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	start := time.Now()
+	resp, err := pushClient.Do(req)
+	stats.RecordRequest(stats.OpWebhook, time.Since(start), resp, err)
+	if err != nil {
+		return fmt.Errorf("failed to reach the webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		text, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutputLength))
+		return fmt.Errorf("webhook: %s: %s", resp.Status, strings.TrimSpace(string(text)))
+	}
+	return nil
+}
+
+// callWebhook calls the webhook for an upcoming event at a place.
+func callWebhook(settings domain.Settings, event domain.SunEvent, loc domain.Location) Result {
+	payload := NewWebhookPayload(settings, event, loc)
+	return Result{Event: event, Webhook: true, Output: payload.Value3,
+		Err: CallWebhook(settings.Webhook, payload)}
+}
@@ -0,0 +1,63 @@
+package automation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCallWebhook(t *testing.T) {
+	var got WebhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("request %s with %q, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("invalid JSON: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	settings := domain.DefaultSettings()
+	settings.Webhook.URL = server.URL + "/trigger"
+	settings.Webhook.LeadMinutes = 15
+	paris, _ := time.LoadLocation("Europe/Paris")
+	event := domain.SunEvent{Kind: domain.EventGoldenEveningStart, Time: time.Date(2026, 6, 21, 20, 31, 0, 0, paris)}
+	loc := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris", Name: "Pont Neuf"}
+
+	if result := callWebhook(settings, event, loc); result.Err != nil || !result.Webhook {
+		t.Fatalf("callWebhook() = %+v, want a successful webhook call", result)
+	}
+	if got.Event != domain.EventGoldenEveningStart || got.Time != "2026-06-21T20:31:00+02:00" || got.MinutesBefore != 15 {
+		t.Errorf("payload = %+v", got)
+	}
+	if got.Location.Name != "Pont Neuf" || got.Location.Timezone != "Europe/Paris" {
+		t.Errorf("payload location = %+v", got.Location)
+	}
+	if got.Value1 != "Evening golden hour start" || got.Value2 != "20:31" || got.Value3 != "Pont Neuf" {
+		t.Errorf("IFTTT values = %q, %q, %q", got.Value1, got.Value2, got.Value3)
+	}
+}
+
+func TestCallWebhookFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no such trigger"))
+	}))
+	defer server.Close()
+
+	webhook := domain.DefaultWebhook()
+	webhook.URL = server.URL
+	err := CallWebhook(webhook, WebhookPayload{})
+	if err == nil || !strings.Contains(err.Error(), "no such trigger") {
+		t.Errorf("CallWebhook() = %v, want the server's explanation", err)
+	}
+	if err := CallWebhook(domain.DefaultWebhook(), WebhookPayload{}); err == nil {
+		t.Error("CallWebhook() without a URL succeeded")
+	}
+}
@@ -0,0 +1,205 @@
+// Package caldav sends the planned golden and blue hours to the user's
+// calendar server over CalDAV (RFC 4791).
+//
+// # Protocol
+//
+// Only the small part of CalDAV needed to keep a set of events up to date
+// is used, with plain HTTP requests:
+//
+//   - Every event is a calendar object resource of its own, named after its
+//     UID (e.g., ".../shoots/20260621-golden-pm-48.8566-2.3522@gogoldenhour.ics"),
+//     written with PUT
+//   - New events are sent with "If-None-Match: *", so an event that some
+//     other client already created isn't replaced
+//   - Known events are sent with "If-Match: <ETag>", the ETag the server
+//     returned last time, so an event changed in the calendar since (moved,
+//     renamed, given a note) isn't overwritten
+//
+// The server answers 412 Precondition Failed in both cases; such events are
+// reported as conflicts and left as they are. Servers that don't return an
+// ETag on PUT are asked with a HEAD request; if they still don't report
+// one, later updates use "If-Match: *" (replace if it exists).
+//
+// Authentication is HTTP basic, which Nextcloud, Radicale, Baïkal, iCloud
+// and Fastmail accept with an app password.
+package caldav
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Sync
+// =============================================================================
+
+// maxErrorText is how much of an error response body is shown to the user.
+const maxErrorText = 200
+
+// client sends the requests. Like the other web services, a request may
+// take at most config.DefaultHTTPTimeout.
+var client = &http.Client{Timeout: config.DefaultHTTPTimeout}
+
+// Result reports what a Sync did.
+type Result struct {
+	// Created and Updated count the events written.
+	Created int
+	Updated int
+
+	// Conflicts lists the events left alone because they were changed in
+	// the calendar or created by another client, as "Golden PM on Jun 21".
+	Conflicts []string
+
+	// ETags are the server's ETags of the events written, by UID, to be
+	// merged into domain.CalDAVSync.ETags. Filled in even when Sync fails
+	// part way, so the events already sent aren't treated as new next time.
+	ETags map[string]string
+}
+
+// Sync sends events to the calendar.
+//
+// The call blocks until every event was sent, so callers on the UI thread
+// should run it in a goroutine.
+//
+// Parameters:
+//   - ctx: Cancels the sync between (and during) requests
+//   - sync: The calendar and login; its ETags decide between creating and
+//     updating each event
+//   - events: The events to send (see export.CalendarEvents)
+//
+// Returns what was done, and an error if the calendar isn't set up, the
+// login is refused, or the server fails a request other than with a
+// conflict. Sending stops at the first such error.
+func Sync(ctx context.Context, sync domain.CalDAVSync, events []export.CalendarEvent) (Result, error) {
+	result := Result{ETags: make(map[string]string)}
+	if !sync.Configured() {
+		return result, errors.New("no calendar is set up (Preferences → Notifications → Calendar)")
+	}
+	if err := sync.Check(); err != nil {
+		return result, err
+	}
+	collection := strings.TrimRight(sync.CalendarURL, "/") + "/"
+
+	for _, event := range events {
+		resource := collection + url.PathEscape(event.UID) + ".ics"
+		etag, known := sync.ETags[event.UID]
+
+		req, err := newRequest(ctx, http.MethodPut, resource, sync, strings.NewReader(event.ICS))
+		if err != nil {
+			return result, err
+		}
+		req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+		switch {
+		case !known:
+			req.Header.Set("If-None-Match", "*")
+		case etag != "":
+			req.Header.Set("If-Match", etag)
+		default:
+			req.Header.Set("If-Match", "*")
+		}
+
+		resp, err := do(req)
+		if err != nil {
+			return result, err
+		}
+		failure := responseText(resp)
+		resp.Body.Close()
+
+		switch {
+		case resp.StatusCode == http.StatusPreconditionFailed:
+			result.Conflicts = append(result.Conflicts, conflictName(event))
+			continue
+		case resp.StatusCode == http.StatusCreated:
+			result.Created++
+		case resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent:
+			result.Updated++
+		default:
+			return result, fmt.Errorf("calendar server: %s", failure)
+		}
+
+		etag = resp.Header.Get("ETag")
+		if etag == "" {
+			etag = headETag(ctx, resource, sync)
+		}
+		result.ETags[event.UID] = etag
+	}
+	return result, nil
+}
+
+// headETag asks for the ETag of a resource, for servers that don't return
+// it on PUT. Returns "" if the server doesn't report one either.
+func headETag(ctx context.Context, resource string, sync domain.CalDAVSync) string {
+	req, err := newRequest(ctx, http.MethodHead, resource, sync, nil)
+	if err != nil {
+		return ""
+	}
+	resp, err := do(req)
+	if err != nil {
+		return ""
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+	return resp.Header.Get("ETag")
+}
+
+// newRequest builds a request to the calendar server with the login.
+func newRequest(ctx context.Context, method, resource string, sync domain.CalDAVSync, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, resource, body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid calendar request: %w", err)
+	}
+	if sync.Username != "" {
+		req.SetBasicAuth(sync.Username, sync.Password)
+	}
+	return req, nil
+}
+
+// do sends a request and records it in the statistics. A refused login is
+// returned as an error.
+func do(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := client.Do(req)
+	stats.RecordRequest(stats.OpCalDAV, time.Since(start), resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach the calendar server: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		resp.Body.Close()
+		return nil, fmt.Errorf("the calendar server refused the login (%s)", resp.Status)
+	}
+	return resp, nil
+}
+
+// responseText returns the status of a response with the start of its
+// body, which servers use to explain a failure.
+func responseText(resp *http.Response) string {
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorText))
+	if body := strings.TrimSpace(string(text)); body != "" {
+		return resp.Status + ": " + body
+	}
+	return resp.Status
+}
+
+// conflictName names a conflicting event for the user, e.g., "Golden PM on
+// Jun 21", from its title and the date at the start of its UID.
+func conflictName(event export.CalendarEvent) string {
+	if len(event.UID) >= 8 {
+		if date, err := time.Parse("20060102", event.UID[:8]); err == nil {
+			return event.Title + " on " + date.Format("Jan 2")
+		}
+	}
+	return event.Title
+}
@@ -0,0 +1,112 @@
+package caldav
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+)
+
+// calendarServer is a minimal CalDAV collection that honors If-Match and
+// If-None-Match on PUT.
+type calendarServer struct {
+	mu      sync.Mutex
+	objects map[string]string // path → ETag
+	version int
+}
+
+func (s *calendarServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if user, pass, ok := r.BasicAuth(); !ok || user != "me" || pass != "app-password" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.Method != http.MethodPut || r.Header.Get("Content-Type") != "text/calendar; charset=utf-8" {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	if !strings.HasPrefix(string(body), "BEGIN:VCALENDAR") {
+		http.Error(w, "not a calendar", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	etag, exists := s.objects[r.URL.Path]
+	if match := r.Header.Get("If-Match"); match != "" && (!exists || (match != "*" && match != etag)) {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	if r.Header.Get("If-None-Match") == "*" && exists {
+		w.WriteHeader(http.StatusPreconditionFailed)
+		return
+	}
+	s.version++
+	s.objects[r.URL.Path] = fmt.Sprintf(`"v%d"`, s.version)
+	w.Header().Set("ETag", s.objects[r.URL.Path])
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+func TestSync(t *testing.T) {
+	server := &calendarServer{objects: make(map[string]string)}
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	events := []export.CalendarEvent{
+		{UID: "20260621-golden-am-48.8566-2.3522@gogoldenhour", Title: "Golden AM", ICS: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"},
+		{UID: "20260621-golden-pm-48.8566-2.3522@gogoldenhour", Title: "Golden PM", ICS: "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"},
+	}
+	config := domain.CalDAVSync{CalendarURL: ts.URL + "/cal/me/shoots", Username: "me", Password: "app-password"}
+
+	// First sync creates both events
+	result, err := Sync(context.Background(), config, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 2 || len(result.ETags) != 2 || len(result.Conflicts) != 0 {
+		t.Fatalf("first sync = %+v, want 2 created", result)
+	}
+	config.ETags = result.ETags
+
+	// The user edits the evening event in their calendar app
+	path := "/cal/me/shoots/" + events[1].UID + ".ics"
+	server.objects[path] = `"edited"`
+
+	result, err = Sync(context.Background(), config, events)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Updated != 1 || len(result.Conflicts) != 1 || result.Conflicts[0] != "Golden PM on Jun 21" {
+		t.Errorf("second sync = %+v, want 1 updated and the edited event as a conflict", result)
+	}
+	if server.objects[path] != `"edited"` {
+		t.Error("the edited event was overwritten")
+	}
+
+	// Events another client created aren't replaced either
+	config.ETags = nil
+	result, err = Sync(context.Background(), config, events[:1])
+	if err != nil || len(result.Conflicts) != 1 {
+		t.Errorf("sync without ETags = %+v, %v; want a conflict", result, err)
+	}
+
+	config.Password = "wrong"
+	if _, err := Sync(context.Background(), config, events); err == nil || !strings.Contains(err.Error(), "refused the login") {
+		t.Errorf("sync with a wrong password: err = %v", err)
+	}
+
+	if _, err := Sync(context.Background(), domain.CalDAVSync{}, events); err == nil {
+		t.Error("sync without a calendar succeeded")
+	}
+}
@@ -0,0 +1,152 @@
+// Package elevation provides terrain heights from the Open-Meteo elevation API.
+//
+// The elevation profile feature samples terrain along a line drawn with the
+// map's measure tool, so photographers can check whether a ridge or hill
+// will hide the low sun in that direction.
+//
+// # Open-Meteo Elevation API
+//
+// Open-Meteo serves heights from the Copernicus DEM GLO-90 dataset (90 m
+// resolution) for free, without an API key, for non-commercial use:
+//
+//   - Up to 100 coordinates per request (one profile fits in one request)
+//   - Fair use limit of 10,000 requests per day
+//
+// Documentation: https://open-meteo.com/en/docs/elevation-api
+package elevation
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// openMeteoEndpoint is the URL of the elevation API.
+	// Accepts query parameters: latitude and longitude (comma-separated lists)
+	openMeteoEndpoint = "https://api.open-meteo.com/v1/elevation"
+
+	// maxCoordinates is the maximum number of coordinates per request.
+	maxCoordinates = 100
+)
+
+// =============================================================================
+// API Response Types
+// =============================================================================
+
+// openMeteoResponse is the elevation API response.
+//
+// Example response:
+//
+//	{"elevation": [38.0, 35.0]}
+type openMeteoResponse struct {
+	// Elevation holds one height in meters per requested coordinate, in
+	// request order.
+	Elevation []float64 `json:"elevation"`
+}
+
+// =============================================================================
+// Service
+// =============================================================================
+
+// OpenMeteoService looks up terrain heights using the Open-Meteo elevation API.
+//
+// Usage:
+//
+//	service := elevation.NewOpenMeteoService()
+//	profile, err := service.Profile(camera, subject, 100)
+//	if err != nil {
+//	    // Show error
+//	}
+//	angle, _, _ := profile.HorizonAngle()
+type OpenMeteoService struct {
+	// client is the HTTP client used for API requests.
+	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	client *http.Client
+}
+
+// NewOpenMeteoService creates a new elevation service.
+//
+// Returns a ready-to-use OpenMeteoService instance.
+func NewOpenMeteoService() *OpenMeteoService {
+	return &OpenMeteoService{
+		client: &http.Client{
+			Timeout: config.DefaultHTTPTimeout,
+		},
+	}
+}
+
+// Profile samples terrain heights along the great circle from one point to another.
+//
+// The samples are evenly spaced, the first at from and the last at to.
+// This makes a network request and must be run in a background goroutine.
+//
+// Parameters:
+//   - from: Start of the line (the observer)
+//   - to: End of the line
+//   - samples: Number of samples, between 2 and 100
+//
+// Returns:
+//   - domain.ElevationProfile: Heights from from to to
+//   - error: Non-nil if the request fails or returns an unexpected result
+func (s *OpenMeteoService) Profile(from, to domain.Location, samples int) (domain.ElevationProfile, error) {
+	if samples < 2 || samples > maxCoordinates {
+		return domain.ElevationProfile{}, fmt.Errorf("sample count %d out of range (2-%d)", samples, maxCoordinates)
+	}
+
+	bearing := from.BearingTo(to)
+	total := from.DistanceTo(to)
+
+	distances := make([]float64, samples)
+	lats := make([]string, samples)
+	lons := make([]string, samples)
+	for i := range distances {
+		distances[i] = total * float64(i) / float64(samples-1)
+		p := from.Destination(bearing, distances[i])
+		lats[i] = strconv.FormatFloat(p.Latitude, 'f', 5, 64)
+		lons[i] = strconv.FormatFloat(p.Longitude, 'f', 5, 64)
+	}
+
+	params := url.Values{}
+	params.Set("latitude", strings.Join(lats, ","))
+	params.Set("longitude", strings.Join(lons, ","))
+
+	start := time.Now()
+	resp, err := s.client.Get(openMeteoEndpoint + "?" + params.Encode())
+	stats.RecordRequest(stats.OpOpenMeteo, time.Since(start), resp, err)
+	if err != nil {
+		return domain.ElevationProfile{}, fmt.Errorf("elevation request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.ElevationProfile{}, fmt.Errorf("elevation request returned status %d", resp.StatusCode)
+	}
+
+	var result openMeteoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return domain.ElevationProfile{}, fmt.Errorf("failed to decode elevation response: %w", err)
+	}
+	if len(result.Elevation) != samples {
+		return domain.ElevationProfile{}, fmt.Errorf("elevation response has %d values, expected %d",
+			len(result.Elevation), samples)
+	}
+
+	profile := domain.ElevationProfile{Samples: make([]domain.ProfileSample, samples)}
+	for i, h := range result.Elevation {
+		profile.Samples[i] = domain.ProfileSample{Distance: distances[i], Elevation: h}
+	}
+	return profile, nil
+}
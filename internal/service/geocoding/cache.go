@@ -0,0 +1,314 @@
+package geocoding
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Cache
+// =============================================================================
+
+const (
+	// cacheMaxEntries is how many searches and place names the cache keeps.
+	// The least recently used ones are dropped first; 500 entries make a
+	// file of a few hundred kilobytes at most.
+	cacheMaxEntries = 500
+
+	// cacheTTL is how long a cached answer is used without asking Nominatim
+	// again. OpenStreetMap data changes slowly, and a stale answer is still
+	// used while the service can't be reached.
+	cacheTTL = 30 * 24 * time.Hour
+
+	// reverseCacheRadius is how close (in meters) a map click must be to a
+	// cached one to reuse its place name. Clicks this close almost always
+	// name the same street or building.
+	reverseCacheRadius = 30.0
+
+	// cacheDirName and cacheFileName name the on-disk cache (see
+	// DefaultCachePath).
+	cacheDirName  = "GoGoldenHour"
+	cacheFileName = "geocoding.json"
+)
+
+// DefaultCachePath returns the on-disk location of the geocoding cache, in
+// the platform's cache directory (e.g., ~/.cache/GoGoldenHour/geocoding.json
+// on Linux).
+//
+// The cache holds public place names only, so all profiles share it.
+// Returns an empty string if the platform has no cache directory, in which
+// case the cache is kept in memory only.
+func DefaultCachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, cacheDirName, cacheFileName)
+}
+
+// cacheEntry is one cached answer: the results of a search, or the place
+// name at a point.
+type cacheEntry struct {
+	// Key identifies the request (see searchKey and reverseKey).
+	Key string `json:"key"`
+
+	// Results are the search results (searches only).
+	Results []cachedResult `json:"results,omitempty"`
+
+	// Name is the place name, City/Region/Country its address parts, and
+	// Latitude/Longitude the point it was asked for (reverse geocoding
+	// only). Entries saved by earlier versions have no address parts.
+	Name      string  `json:"name,omitempty"`
+	City      string  `json:"city,omitempty"`
+	Region    string  `json:"region,omitempty"`
+	Country   string  `json:"country,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`
+	Longitude float64 `json:"longitude,omitempty"`
+
+	// Stored is when the answer came from Nominatim.
+	Stored time.Time `json:"stored"`
+}
+
+// cachedResult is a domain.SearchResult in the cache file.
+type cachedResult struct {
+	Location domain.Location `json:"location"`
+	Type     string          `json:"type,omitempty"`
+}
+
+// fresh reports whether the entry may be used without asking Nominatim.
+func (e cacheEntry) fresh(now time.Time) bool {
+	return now.Sub(e.Stored) < cacheTTL
+}
+
+// isReverse reports whether the entry holds a place name.
+func (e cacheEntry) isReverse() bool {
+	return strings.HasPrefix(e.Key, reverseKeyPrefix)
+}
+
+// place returns the cached place name as a location at the point asked
+// for (which may be a few meters from the cached one).
+func (e cacheEntry) place(lat, lon float64) domain.Location {
+	return domain.Location{
+		Latitude:  lat,
+		Longitude: lon,
+		Name:      e.Name,
+		City:      e.City,
+		Region:    e.Region,
+		Country:   e.Country,
+	}
+}
+
+// searchResults converts the cached results back.
+func (e cacheEntry) searchResults() []domain.SearchResult {
+	results := make([]domain.SearchResult, len(e.Results))
+	for i, r := range e.Results {
+		results[i] = domain.SearchResult{Location: r.Location, Type: r.Type}
+	}
+	return results
+}
+
+// Cache keys: searches by their normalized query and limit, place names by
+// their point.
+const (
+	searchKeyPrefix  = "search:"
+	reverseKeyPrefix = "reverse:"
+)
+
+// searchKey returns the cache key of a search. Case and extra spaces don't
+// change Nominatim's answer, so "paris,  France" shares the entry of
+// "Paris, France"; other options do (see SearchOptions.key).
+func searchKey(query string, limit int, options SearchOptions) string {
+	normalized := strings.ToLower(strings.Join(strings.Fields(query), " "))
+	return searchKeyPrefix + strconv.Itoa(limit) + options.key() + ":" + normalized
+}
+
+// reverseKey returns the cache key of the place name at a point.
+func reverseKey(lat, lon float64) string {
+	return reverseKeyPrefix + strconv.FormatFloat(lat, 'f', 6, 64) + "," + strconv.FormatFloat(lon, 'f', 6, 64)
+}
+
+// cache is a least recently used cache of Nominatim answers, saved to a
+// JSON file after every change so answers survive restarts and can be used
+// offline.
+//
+// The cache is best-effort: a missing or broken file starts an empty
+// cache, and failures to save are ignored (the answers are still cached in
+// memory).
+type cache struct {
+	// mu guards all fields below; NominatimService is used from several
+	// goroutines.
+	mu sync.Mutex
+
+	// path is the cache file; empty to keep the cache in memory only.
+	path string
+
+	// maxEntries is the capacity; the least recently used entry is dropped
+	// when it is exceeded.
+	maxEntries int
+
+	// order holds the entries (*cacheEntry), most recently used first.
+	order *list.List
+
+	// index finds the element of a key in order.
+	index map[string]*list.Element
+}
+
+// newCache creates a cache and loads the entries saved at path.
+func newCache(path string, maxEntries int) *cache {
+	c := &cache{
+		path:       path,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		index:      make(map[string]*list.Element),
+	}
+	if path == "" {
+		return c
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var entries []cacheEntry
+	if json.Unmarshal(data, &entries) != nil {
+		return c
+	}
+	// Saved most recently used first
+	for _, e := range entries {
+		if _, ok := c.index[e.Key]; ok || c.order.Len() >= maxEntries {
+			continue
+		}
+		c.index[e.Key] = c.order.PushBack(&e)
+	}
+	return c
+}
+
+// get returns the entry of a key and marks it as recently used.
+func (c *cache) get(key string) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.index[key]
+	if !ok {
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(elem)
+	return *elem.Value.(*cacheEntry), true
+}
+
+// nearest returns the place name entry closest to a point within radius
+// meters, and marks it as recently used.
+func (c *cache) nearest(lat, lon, radius float64) (cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	point := domain.Location{Latitude: lat, Longitude: lon}
+	var best *list.Element
+	bestDistance := radius
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		e := elem.Value.(*cacheEntry)
+		if !e.isReverse() {
+			continue
+		}
+		d := point.DistanceTo(domain.Location{Latitude: e.Latitude, Longitude: e.Longitude})
+		if d <= bestDistance {
+			best, bestDistance = elem, d
+		}
+	}
+	if best == nil {
+		return cacheEntry{}, false
+	}
+	c.order.MoveToFront(best)
+	return *best.Value.(*cacheEntry), true
+}
+
+// put adds or replaces an entry, drops the least recently used entries
+// beyond the capacity, and saves the cache.
+func (c *cache) put(entry cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.index[entry.Key]; ok {
+		c.order.Remove(elem)
+	}
+	c.index[entry.Key] = c.order.PushFront(&entry)
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.index, oldest.Value.(*cacheEntry).Key)
+	}
+	c.saveLocked()
+}
+
+// saveLocked writes the entries to the cache file, most recently used
+// first. The file is replaced atomically, so a crash can't leave half a
+// cache behind. Caller must hold c.mu.
+func (c *cache) saveLocked() {
+	if c.path == "" {
+		return
+	}
+	entries := make([]cacheEntry, 0, c.order.Len())
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		entries = append(entries, *elem.Value.(*cacheEntry))
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return
+	}
+	if os.MkdirAll(filepath.Dir(c.path), 0o755) != nil {
+		return
+	}
+	tmp := c.path + ".tmp"
+	if os.WriteFile(tmp, data, 0o644) != nil {
+		return
+	}
+	if os.Rename(tmp, c.path) != nil {
+		os.Remove(tmp)
+	}
+}
+
+// =============================================================================
+// Rate Limiting
+// =============================================================================
+
+// requestInterval is the shortest time between two Nominatim requests, as
+// its usage policy allows at most one request per second.
+const requestInterval = time.Second
+
+// rateLimiter spaces requests at least interval apart.
+//
+// Waiting callers hold the lock while they sleep, so concurrent requests
+// (e.g., a search while a map click is being named) queue up and leave one
+// after another.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+
+	// next is the earliest time the next request may be sent.
+	next time.Time
+}
+
+// wait blocks until the next request may be sent and reserves its slot.
+//
+// Returns the context's error if it is cancelled first; the slot then
+// stays free for the next caller.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if d := time.Until(l.next); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	l.next = time.Now().Add(l.interval)
+	return nil
+}
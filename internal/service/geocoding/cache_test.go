@@ -0,0 +1,154 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCacheEviction(t *testing.T) {
+	c := newCache("", 2)
+	now := time.Now()
+	c.put(cacheEntry{Key: "a", Stored: now})
+	c.put(cacheEntry{Key: "b", Stored: now})
+	c.get("a") // a is now more recently used than b
+	c.put(cacheEntry{Key: "c", Stored: now})
+
+	if _, ok := c.get("b"); ok {
+		t.Error("least recently used entry was kept")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := c.get(key); !ok {
+			t.Errorf("entry %q was dropped", key)
+		}
+	}
+}
+
+func TestCachePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "geocoding.json")
+	c := newCache(path, 10)
+	c.put(cacheEntry{Key: reverseKey(48.8588, 2.3200), Name: "Eiffel Tower",
+		Latitude: 48.8588, Longitude: 2.3200, Stored: time.Now()})
+	c.put(cacheEntry{Key: searchKey("Paris", 5, SearchOptions{}), Results: []cachedResult{{Type: "city"}}, Stored: time.Now()})
+
+	loaded := newCache(path, 10)
+	if e, ok := loaded.get(searchKey("  PARIS ", 5, SearchOptions{})); !ok || len(e.Results) != 1 || e.Results[0].Type != "city" {
+		t.Errorf("search entry after reload = %+v, %v", e, ok)
+	}
+
+	// About 15 m away: same place; about 150 m away: not
+	if e, ok := loaded.nearest(48.85893, 2.32018, reverseCacheRadius); !ok || e.Name != "Eiffel Tower" {
+		t.Errorf("nearest within radius = %+v, %v", e, ok)
+	}
+	if _, ok := loaded.nearest(48.8602, 2.3200, reverseCacheRadius); ok {
+		t.Error("nearest found a place 150 m away")
+	}
+}
+
+func TestRateLimiter(t *testing.T) {
+	l := &rateLimiter{interval: 50 * time.Millisecond}
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := l.wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("three requests took %v, want at least 100ms", elapsed)
+	}
+
+	// A cancelled wait returns at once and leaves the slot free
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	l.next = time.Now().Add(time.Hour)
+	if err := l.wait(ctx); err != context.Canceled {
+		t.Errorf("cancelled wait = %v, want context.Canceled", err)
+	}
+}
+
+func TestServiceCache(t *testing.T) {
+	requests := 0
+	up := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if !up {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path == "/search" {
+			fmt.Fprint(w, `[{"lat":"48.8566","lon":"2.3522","display_name":"Paris","type":"city",`+
+				`"address":{"city":"Paris","state":"Île-de-France","country":"France"}}]`)
+		} else {
+			fmt.Fprint(w, `{"display_name":"Eiffel Tower","address":{"town":"Paris","country":"France"}}`)
+		}
+	}))
+	defer server.Close()
+
+	s := NewNominatimService("test", "")
+	s.searchEndpoint, s.reverseEndpoint = server.URL+"/search", server.URL+"/reverse"
+	s.limiter.interval = 0
+
+	for i := 0; i < 2; i++ {
+		results, err := s.Search(context.Background(), "Paris", 5, SearchOptions{})
+		if err != nil || len(results) != 1 || results[0].Location.Name != "Paris" ||
+			results[0].Location.Region != "Île-de-France" {
+			t.Fatalf("Search = %+v, %v", results, err)
+		}
+		place, err := s.ReverseGeocode(context.Background(), 48.8588, 2.3200+float64(i)*0.0001)
+		if err != nil || place.Name != "Eiffel Tower" || place.DisplayName(domain.PlaceNameShort) != "Paris, France" {
+			t.Fatalf("ReverseGeocode = %+v, %v", place, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("sent %d requests, want 2 (the repeats come from the cache)", requests)
+	}
+
+	// Stale answers are used while the service is down
+	up = false
+	s.cache.mu.Lock()
+	for elem := s.cache.order.Front(); elem != nil; elem = elem.Next() {
+		elem.Value.(*cacheEntry).Stored = time.Now().Add(-2 * cacheTTL)
+	}
+	s.cache.mu.Unlock()
+	if results, err := s.Search(context.Background(), "paris", 5, SearchOptions{}); err != nil || len(results) != 1 {
+		t.Errorf("Search while down = %+v, %v; want the stale answer", results, err)
+	}
+	if _, err := s.Search(context.Background(), "Lyon", 5, SearchOptions{}); err == nil {
+		t.Error("Search for an uncached place succeeded while down")
+	}
+
+	// A stopped search doesn't fall back to the stale answer
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.Search(ctx, "paris", 5, SearchOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled Search error = %v, want context.Canceled", err)
+	}
+}
+
+func TestReverseGeocodeCancel(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		fmt.Fprint(w, `{"display_name":"Eiffel Tower"}`)
+	}))
+	defer server.Close()
+
+	s := NewNominatimService("test", "")
+	s.reverseEndpoint = server.URL + "/reverse"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := s.ReverseGeocode(ctx, 48.8588, 2.3200); !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled ReverseGeocode: err = %v, want context.Canceled", err)
+	}
+	if requests != 0 {
+		t.Errorf("cancelled lookup sent %d requests", requests)
+	}
+}
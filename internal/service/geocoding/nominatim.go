@@ -13,12 +13,27 @@
 // The package uses Nominatim, the geocoding service provided by OpenStreetMap.
 // Nominatim is free to use with the following requirements:
 //
-//   - Maximum 1 request per second (we're well within this with user interactions)
-//   - Required User-Agent header identifying the application
+//   - Maximum 1 request per second (enforced by the service, see below)
+//   - Required User-Agent header identifying the application (see UserAgent)
 //   - No bulk/automated queries (interactive use only)
 //
 // Documentation: https://nominatim.org/release-docs/latest/api/Overview/
 //
+// # Caching and Rate Limiting
+//
+// Answers are kept in a least recently used cache that is saved to disk
+// (see DefaultCachePath), so repeating a search, or clicking the map close
+// to an earlier click (within reverseCacheRadius), doesn't reach Nominatim
+// at all. Cached answers are reused for cacheTTL; older ones are only used
+// if Nominatim can't be reached, so places seen before still work offline.
+//
+// Requests that do go out are spaced at least requestInterval apart, as the
+// policy asks, however fast the user types or clicks.
+//
+// In privacy mode (SetOffline) no request goes out at all: cached answers
+// are used however old they are, and anything else fails with an error
+// wrapping domain.ErrPrivacyMode.
+//
 // # Timezone Integration
 //
 // When converting search results to domain.Location, the package automatically
@@ -37,15 +52,22 @@
 package geocoding
 
 import (
+	"cmp"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/megatih/GoGoldenHour/internal/config"
 	"github.com/megatih/GoGoldenHour/internal/domain"
 	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+	"github.com/megatih/GoGoldenHour/internal/stats"
 )
 
 // =============================================================================
@@ -61,13 +83,36 @@ const (
 	// Accepts query parameters: lat, lon, format (json/xml)
 	nominatimReverseEndpoint = "https://nominatim.openstreetmap.org/reverse"
 
-	// userAgent is the required User-Agent header for Nominatim requests.
-	// Nominatim's usage policy requires a valid User-Agent that identifies
-	// the application and provides contact information.
-	// See: https://operations.osmfoundation.org/policies/nominatim/
-	userAgent = "GoGoldenHour/1.0 (https://github.com/megatih/GoGoldenHour)"
+	// projectURL identifies the application in the User-Agent header.
+	projectURL = "https://github.com/megatih/GoGoldenHour"
 )
 
+// UserAgent builds the User-Agent header for Nominatim requests.
+//
+// Nominatim's usage policy requires a valid User-Agent that identifies
+// the application and provides contact information. The application is
+// named with its version, so OSM operators can tell releases apart, and
+// the project page serves as the contact unless the user supplies an email
+// address (recommended by the policy, so operators can reach the user
+// rather than the project about a problem with their requests).
+// See: https://operations.osmfoundation.org/policies/nominatim/
+//
+// Parameters:
+//   - version: The application version (e.g., "0.1.3")
+//   - contact: Optional contact email address (empty for none)
+//
+// Example:
+//
+//	UserAgent("0.1.3", "me@example.com")
+//	// "GoGoldenHour/0.1.3 (https://github.com/megatih/GoGoldenHour; me@example.com)"
+func UserAgent(version, contact string) string {
+	ua := "GoGoldenHour/" + version + " (" + projectURL
+	if contact != "" {
+		ua += "; " + contact
+	}
+	return ua + ")"
+}
+
 // =============================================================================
 // API Response Types
 // =============================================================================
@@ -86,7 +131,8 @@ const (
 //	  "lon": "2.3200410",
 //	  "display_name": "Paris, Île-de-France, France métropolitaine, France",
 //	  "type": "city",
-//	  "importance": 0.9411
+//	  "importance": 0.9411,
+//	  "address": {"city": "Paris", "state": "Île-de-France", "country": "France"}
 //	}
 type nominatimResult struct {
 	// PlaceID is Nominatim's internal identifier for this place.
@@ -107,13 +153,46 @@ type nominatimResult struct {
 	DisplayName string `json:"display_name"`
 
 	// Type indicates the OSM object type (city, street, building, etc.).
-	// Not currently used but useful for filtering or categorizing results.
+	// Shown next to search results to tell places with similar names apart.
 	Type string `json:"type"`
 
+	// AddressType is the address level the place stands for (city, state,
+	// country, etc.), used when Type is too generic (see placeType).
+	AddressType string `json:"addresstype"`
+
 	// Importance is a score indicating result relevance (0.0 to 1.0).
 	// Higher values = more relevant/important places.
 	// Results are sorted by this value in descending order.
 	Importance float64 `json:"importance"`
+
+	// Address holds the address parts (requested with addressdetails=1).
+	Address nominatimAddress `json:"address"`
+}
+
+// nominatimAddress is the part of a Nominatim address used for short place
+// names. Which keys are set depends on the country and the kind of place:
+// a village has "village" instead of "city", a Japanese prefecture is a
+// "province", and so on.
+type nominatimAddress struct {
+	City         string `json:"city"`
+	Town         string `json:"town"`
+	Village      string `json:"village"`
+	Hamlet       string `json:"hamlet"`
+	Municipality string `json:"municipality"`
+	State        string `json:"state"`
+	Province     string `json:"province"`
+	Region       string `json:"region"`
+	County       string `json:"county"`
+	Country      string `json:"country"`
+}
+
+// apply sets the location's City, Region and Country from the address,
+// using the first key that is set of each group (largest settlement kind,
+// largest subdivision).
+func (a nominatimAddress) apply(loc *domain.Location) {
+	loc.City = cmp.Or(a.City, a.Town, a.Village, a.Hamlet, a.Municipality)
+	loc.Region = cmp.Or(a.State, a.Province, a.Region, a.County)
+	loc.Country = a.Country
 }
 
 // =============================================================================
@@ -127,21 +206,42 @@ type nominatimResult struct {
 //   - ReverseGeocode: Convert coordinates to place names
 //
 // The service maintains an HTTP client with configured timeout and automatically
-// includes the required User-Agent header for all requests.
+// includes the required User-Agent header for all requests. Answers are
+// cached and requests rate limited (see the package docs).
 //
 // Usage:
 //
-//	service := geocoding.NewNominatimService()
+//	service := geocoding.NewNominatimService(geocoding.UserAgent(version, ""),
+//	    geocoding.DefaultCachePath())
 //
 //	// Forward geocoding (search)
-//	locations, err := service.Search("Eiffel Tower", 5)
+//	results, err := service.Search(ctx, "Eiffel Tower", 5, geocoding.SearchOptions{})
 //
-//	// Reverse geocoding (map click)
-//	name, err := service.ReverseGeocode(48.8588, 2.3200)
+//	// Reverse geocoding (map click), cancelled by a newer click
+//	place, err := service.ReverseGeocode(ctx, 48.8588, 2.3200)
 type NominatimService struct {
 	// client is the HTTP client used for API requests.
 	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
 	client *http.Client
+
+	// mu guards userAgent and offline, which may change (SetUserAgent,
+	// SetOffline) while requests run in background goroutines.
+	mu sync.RWMutex
+
+	// userAgent is sent with every request (see UserAgent).
+	userAgent string
+
+	// offline answers from the cache only (privacy mode).
+	offline bool
+
+	// searchEndpoint and reverseEndpoint are the API URLs (replaced by
+	// tests).
+	searchEndpoint  string
+	reverseEndpoint string
+
+	// cache holds earlier answers; limiter spaces the requests.
+	cache   *cache
+	limiter *rateLimiter
 }
 
 // NewNominatimService creates a new geocoding service.
@@ -149,13 +249,119 @@ type NominatimService struct {
 // The service is configured with a timeout from config.DefaultHTTPTimeout
 // to prevent the application from hanging if the API is unreachable.
 //
+// Parameters:
+//   - userAgent: The User-Agent header to send (build it with UserAgent)
+//   - cachePath: The cache file (see DefaultCachePath); empty to cache in
+//     memory only
+//
 // Returns a ready-to-use NominatimService instance.
-func NewNominatimService() *NominatimService {
+func NewNominatimService(userAgent, cachePath string) *NominatimService {
 	return &NominatimService{
 		client: &http.Client{
 			Timeout: config.DefaultHTTPTimeout,
 		},
+		userAgent:       userAgent,
+		searchEndpoint:  nominatimSearchEndpoint,
+		reverseEndpoint: nominatimReverseEndpoint,
+		cache:           newCache(cachePath, cacheMaxEntries),
+		limiter:         &rateLimiter{interval: requestInterval},
+	}
+}
+
+// SetUserAgent changes the User-Agent header for later requests.
+//
+// Called when the user changes their contact email. Safe to call while
+// requests are running; those keep the header they were sent with.
+func (s *NominatimService) SetUserAgent(userAgent string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.userAgent = userAgent
+}
+
+// SetOffline turns privacy mode on or off: while offline, Search and
+// ReverseGeocode only answer from the cache.
+//
+// Safe to call while requests are running; those that were sent already
+// still finish.
+func (s *NominatimService) SetOffline(offline bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offline = offline
+}
+
+// isOffline reports whether privacy mode is on (see SetOffline).
+func (s *NominatimService) isOffline() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.offline
+}
+
+// SearchOptions steers a search toward locally relevant results (see
+// domain.SearchBias, which the user configures).
+type SearchOptions struct {
+	// Language lists the preferred languages of the names, as
+	// comma-separated language tags; empty for Nominatim's default (local
+	// names).
+	Language string
+
+	// Countries restricts results to these ISO 3166-1 alpha-2 codes;
+	// empty for worldwide.
+	Countries []string
+
+	// Viewbox prefers results inside the box (usually the map view); nil
+	// for none. Results outside it are still returned, ranked lower.
+	Viewbox *domain.Bounds
+}
+
+// apply adds the options to the parameters of a search request:
+// accept-language, countrycodes and viewbox (west, north, east, south).
+// The map reports longitudes beyond ±180° when scrolled across the
+// antimeridian; the box is clamped to the valid range.
+func (o SearchOptions) apply(q url.Values) {
+	if o.Language != "" {
+		q.Set("accept-language", o.Language)
+	}
+	if len(o.Countries) > 0 {
+		q.Set("countrycodes", strings.Join(o.Countries, ","))
+	}
+	if b := o.Viewbox; b != nil {
+		q.Set("viewbox", fmt.Sprintf("%.4f,%.4f,%.4f,%.4f", max(b.West, -180), min(b.North, 90),
+			min(b.East, 180), max(b.South, -90)))
+	}
+}
+
+// key returns the options' part of a cache key; empty for no options, so
+// plain searches keep the keys they had before options existed. The
+// viewbox is rounded to 0.1°, so small pans share an entry.
+func (o SearchOptions) key() string {
+	if o.Language == "" && len(o.Countries) == 0 && o.Viewbox == nil {
+		return ""
+	}
+	key := "|" + o.Language + "|" + strings.Join(o.Countries, ",")
+	if b := o.Viewbox; b != nil {
+		key += fmt.Sprintf("|%.1f,%.1f,%.1f,%.1f", b.West, b.North, b.East, b.South)
 	}
+	return key
+}
+
+// SystemLanguage returns the user's language from the locale environment
+// variables (LC_ALL, LC_MESSAGES, LANG) as a language tag, e.g., "de-AT"
+// for de_AT.UTF-8; empty if they aren't set or name the C locale.
+func SystemLanguage() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(name)
+		if value == "" {
+			continue
+		}
+		// Strip the encoding and modifier: de_AT.UTF-8@euro → de_AT
+		value, _, _ = strings.Cut(value, ".")
+		value, _, _ = strings.Cut(value, "@")
+		if value == "C" || value == "POSIX" {
+			return ""
+		}
+		return strings.ReplaceAll(value, "_", "-")
+	}
+	return ""
 }
 
 // =============================================================================
@@ -166,31 +372,47 @@ func NewNominatimService() *NominatimService {
 //
 // This helper method centralizes the HTTP request logic for both Search and
 // ReverseGeocode methods. It handles:
+//   - Waiting for the rate limiter (Nominatim policy compliance)
 //   - Setting the required User-Agent header (Nominatim policy compliance)
+//   - Refusing to send anything in privacy mode (see SetOffline)
 //   - Executing the request with the configured timeout
 //   - Checking for HTTP-level errors
 //
 // Parameters:
+//   - ctx: Cancels the wait for the rate limiter and the request itself
 //   - reqURL: The complete URL to request (with query parameters)
 //
 // Returns:
 //   - *http.Response: The response (caller must close Body)
-//   - error: Non-nil if request fails or returns non-200 status
+//   - error: Non-nil if request fails, is cancelled, returns non-200 status,
+//     or privacy mode is on (wrapping domain.ErrPrivacyMode)
 //
 // Note: The caller is responsible for closing resp.Body when done.
-func (s *NominatimService) doRequest(reqURL string) (*http.Response, error) {
+func (s *NominatimService) doRequest(ctx context.Context, reqURL string) (*http.Response, error) {
+	if s.isOffline() {
+		return nil, domain.ErrPrivacyMode
+	}
+
 	// Create request object so we can add custom headers
-	req, err := http.NewRequest("GET", reqURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	// Nominatim requires a valid User-Agent header that identifies the application.
 	// Requests without User-Agent may be blocked or rate-limited more aggressively.
-	req.Header.Set("User-Agent", userAgent)
+	s.mu.RLock()
+	req.Header.Set("User-Agent", s.userAgent)
+	s.mu.RUnlock()
 
-	// Execute the request with the configured timeout
+	// Execute the request with the configured timeout, at most one per
+	// requestInterval
+	if err := s.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	start := time.Now()
 	resp, err := s.client.Do(req)
+	stats.RecordRequest(stats.OpNominatim, time.Since(start), resp, err)
 	if err != nil {
 		return nil, err
 	}
@@ -216,25 +438,32 @@ func (s *NominatimService) doRequest(reqURL string) (*http.Response, error) {
 //
 // The method automatically:
 //   - Validates input parameters
+//   - Answers repeated searches from the cache
 //   - URL-encodes the query string
 //   - Determines timezones for each result using the timezone package
 //
 // Parameters:
+//   - ctx: Cancels the request, e.g., when the user stops the search
 //   - query: The search text (city name, address, etc.). Cannot be empty.
 //   - limit: Maximum number of results to return (1-10, default 5)
+//   - options: Language, countries and area to prefer (zero value: none)
 //
 // Returns:
-//   - []domain.Location: Matching locations with coordinates, names, and timezones
-//   - error: Non-nil if search fails
+//   - []domain.SearchResult: Matching locations with coordinates, names,
+//     timezones and the kind of place, most relevant first
+//   - error: Non-nil if search fails, or is cancelled (the error wraps
+//     ctx.Err(); a stale cached answer isn't returned then)
+//
+// Results with coordinates that can't be parsed are skipped.
 //
 // Example:
 //
-//	locations, err := service.Search("Paris, France", 5)
+//	results, err := service.Search(ctx, "Paris, France", 5, geocoding.SearchOptions{Language: "fr"})
 //	if err != nil {
 //	    // Handle error
 //	}
-//	// Use locations[0] as the primary result
-func (s *NominatimService) Search(query string, limit int) ([]domain.Location, error) {
+//	// results[0].Location is the most relevant match
+func (s *NominatimService) Search(ctx context.Context, query string, limit int, options SearchOptions) ([]domain.SearchResult, error) {
 	// Validate query - empty queries are not allowed
 	if query == "" {
 		return nil, fmt.Errorf("search query cannot be empty")
@@ -246,8 +475,36 @@ func (s *NominatimService) Search(query string, limit int) ([]domain.Location, e
 		limit = 5
 	}
 
+	// A fresh cached answer saves the request; a stale one is the fallback
+	// if Nominatim can't be reached
+	key := searchKey(query, limit, options)
+	cached, hit := s.cache.get(key)
+	fresh := hit && cached.fresh(time.Now())
+	stats.CacheLookup(stats.CacheGeocodingSearch, fresh)
+	if fresh {
+		return cached.searchResults(), nil
+	}
+	found, err := s.search(ctx, query, limit, options)
+	if err != nil {
+		// The stale answer is no use to a caller that gave up
+		if hit && ctx.Err() == nil {
+			return cached.searchResults(), nil
+		}
+		return nil, err
+	}
+
+	entry := cacheEntry{Key: key, Stored: time.Now()}
+	for _, r := range found {
+		entry.Results = append(entry.Results, cachedResult{Location: r.Location, Type: r.Type})
+	}
+	s.cache.put(entry)
+	return found, nil
+}
+
+// search asks Nominatim for the results of a query (see Search).
+func (s *NominatimService) search(ctx context.Context, query string, limit int, options SearchOptions) ([]domain.SearchResult, error) {
 	// Build the request URL with query parameters
-	reqURL, err := url.Parse(nominatimSearchEndpoint)
+	reqURL, err := url.Parse(s.searchEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
 	}
@@ -256,14 +513,17 @@ func (s *NominatimService) Search(query string, limit int) ([]domain.Location, e
 	// - q: the search query (URL-encoded by url.Values)
 	// - format: response format (json)
 	// - limit: maximum number of results
+	// - addressdetails: include the address parts (for short names)
 	q := reqURL.Query()
 	q.Set("q", query)
 	q.Set("format", "json")
 	q.Set("limit", strconv.Itoa(limit))
+	q.Set("addressdetails", "1")
+	options.apply(q)
 	reqURL.RawQuery = q.Encode()
 
 	// Execute the request
-	resp, err := s.doRequest(reqURL.String())
+	resp, err := s.doRequest(ctx, reqURL.String())
 	if err != nil {
 		return nil, fmt.Errorf("failed to search: %w", err)
 	}
@@ -275,15 +535,18 @@ func (s *NominatimService) Search(query string, limit int) ([]domain.Location, e
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	// Convert Nominatim results to domain.Location objects
-	locations := make([]domain.Location, 0, len(results))
+	// Convert Nominatim results to domain.SearchResult objects
+	found := make([]domain.SearchResult, 0, len(results))
 	for _, r := range results {
 		// Parse coordinates from strings to floats
 		// Nominatim returns coordinates as strings (API quirk)
-		lat, _ := strconv.ParseFloat(r.Lat, 64)
-		lon, _ := strconv.ParseFloat(r.Lon, 64)
+		lat, latErr := strconv.ParseFloat(r.Lat, 64)
+		lon, lonErr := strconv.ParseFloat(r.Lon, 64)
+		if latErr != nil || lonErr != nil {
+			continue
+		}
 
-		locations = append(locations, domain.Location{
+		loc := domain.Location{
 			Latitude:  lat,
 			Longitude: lon,
 			Elevation: 0, // Nominatim doesn't provide elevation data
@@ -291,10 +554,26 @@ func (s *NominatimService) Search(query string, limit int) ([]domain.Location, e
 			// Automatically determine timezone from coordinates
 			// This is crucial for accurate solar calculations
 			Timezone: timezone.FromCoordinates(lat, lon),
-		})
+		}
+		r.Address.apply(&loc)
+		found = append(found, domain.SearchResult{Location: loc, Type: placeType(r)})
 	}
 
-	return locations, nil
+	return found, nil
+}
+
+// placeType returns the kind of place of a result in plain words.
+//
+// OSM types use underscores ("nature_reserve"), and some say little about
+// the place: "administrative" covers everything from villages to countries,
+// and "yes" means "some kind of" in OSM tagging. For those, the address
+// type (e.g., "city", "state") is used when Nominatim provides it.
+func placeType(r nominatimResult) string {
+	kind := r.Type
+	if (kind == "administrative" || kind == "yes") && r.AddressType != "" {
+		kind = r.AddressType
+	}
+	return strings.ReplaceAll(kind, "_", " ")
 }
 
 // =============================================================================
@@ -305,62 +584,107 @@ func (s *NominatimService) Search(query string, limit int) ([]domain.Location, e
 //
 // This method is used when the user clicks on the map to determine the name
 // of the clicked location. It queries Nominatim's reverse geocoding endpoint
-// to get the address or place name at the specified coordinates.
+// to get the address or place name at the specified coordinates, along with
+// its city, region and country.
 //
 // Parameters:
+//   - ctx: Cancels the request, e.g., when the user clicks somewhere else
+//     before the name arrived
 //   - lat: Latitude of the point to reverse geocode
 //   - lon: Longitude of the point to reverse geocode
 //
 // Returns:
-//   - string: The display name for the location (address or place name)
+//   - domain.Location: The point with its display name (address or place
+//     name) and address parts; the timezone is left to the caller
 //   - error: Non-nil if reverse geocoding fails
 //
 // Error cases:
+//   - Cancellation (the error wraps ctx.Err())
 //   - Network errors or timeouts
 //   - Coordinates in the ocean or uninhabited areas (no data available)
 //   - API errors
 //
+// The name of a point within reverseCacheRadius of an earlier one is taken
+// from the cache.
+//
 // Example:
 //
-//	name, err := service.ReverseGeocode(48.8588, 2.3200)
-//	// name = "Eiffel Tower, Champ de Mars, 7th Arrondissement, Paris, France"
-func (s *NominatimService) ReverseGeocode(lat, lon float64) (string, error) {
+//	place, err := service.ReverseGeocode(ctx, 48.8588, 2.3200)
+//	// place.Name = "Eiffel Tower, Champ de Mars, 7th Arrondissement, Paris, France"
+//	// place.City = "Paris"
+func (s *NominatimService) ReverseGeocode(ctx context.Context, lat, lon float64) (domain.Location, error) {
+	cached, hit := s.cache.nearest(lat, lon, reverseCacheRadius)
+	fresh := hit && cached.fresh(time.Now())
+	stats.CacheLookup(stats.CacheGeocodingReverse, fresh)
+	if fresh {
+		return cached.place(lat, lon), nil
+	}
+	place, err := s.reverseGeocode(ctx, lat, lon)
+	if err != nil {
+		// The stale name is no use to a caller that gave up
+		if hit && ctx.Err() == nil {
+			return cached.place(lat, lon), nil
+		}
+		return domain.Location{}, err
+	}
+
+	s.cache.put(cacheEntry{
+		Key:       reverseKey(lat, lon),
+		Name:      place.Name,
+		City:      place.City,
+		Region:    place.Region,
+		Country:   place.Country,
+		Latitude:  lat,
+		Longitude: lon,
+		Stored:    time.Now(),
+	})
+	return place, nil
+}
+
+// reverseGeocode asks Nominatim for the place name at a point (see
+// ReverseGeocode).
+func (s *NominatimService) reverseGeocode(ctx context.Context, lat, lon float64) (domain.Location, error) {
 	// Build the request URL with coordinate parameters
-	reqURL, err := url.Parse(nominatimReverseEndpoint)
+	reqURL, err := url.Parse(s.reverseEndpoint)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse URL: %w", err)
+		return domain.Location{}, fmt.Errorf("failed to parse URL: %w", err)
 	}
 
 	// Set query parameters
 	// - lat, lon: coordinates (full precision, no rounding)
 	// - format: response format (json)
+	// - addressdetails: include the address parts (for short names)
 	q := reqURL.Query()
 	q.Set("lat", strconv.FormatFloat(lat, 'f', -1, 64))
 	q.Set("lon", strconv.FormatFloat(lon, 'f', -1, 64))
 	q.Set("format", "json")
+	q.Set("addressdetails", "1")
 	reqURL.RawQuery = q.Encode()
 
 	// Execute the request
-	resp, err := s.doRequest(reqURL.String())
+	resp, err := s.doRequest(ctx, reqURL.String())
 	if err != nil {
-		return "", fmt.Errorf("failed to reverse geocode: %w", err)
+		return domain.Location{}, fmt.Errorf("failed to reverse geocode: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Parse JSON response
 	// Reverse geocoding returns a single object (not an array like search)
 	var result struct {
-		DisplayName string `json:"display_name"`
-		Error       string `json:"error,omitempty"`
+		DisplayName string           `json:"display_name"`
+		Address     nominatimAddress `json:"address"`
+		Error       string           `json:"error,omitempty"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
+		return domain.Location{}, fmt.Errorf("failed to decode response: %w", err)
 	}
 
 	// Check for API-level errors (e.g., "Unable to geocode")
 	if result.Error != "" {
-		return "", fmt.Errorf("Nominatim error: %s", result.Error)
+		return domain.Location{}, fmt.Errorf("Nominatim error: %s", result.Error)
 	}
 
-	return result.DisplayName, nil
+	place := domain.Location{Latitude: lat, Longitude: lon, Name: result.DisplayName}
+	result.Address.apply(&place)
+	return place, nil
 }
@@ -0,0 +1,123 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		version, contact string
+		want             string
+	}{
+		{"0.1.3", "", "GoGoldenHour/0.1.3 (https://github.com/megatih/GoGoldenHour)"},
+		{"0.2.0", "me@example.com", "GoGoldenHour/0.2.0 (https://github.com/megatih/GoGoldenHour; me@example.com)"},
+	}
+
+	for _, tt := range tests {
+		if got := UserAgent(tt.version, tt.contact); got != tt.want {
+			t.Errorf("UserAgent(%q, %q) = %q, want %q", tt.version, tt.contact, got, tt.want)
+		}
+	}
+}
+
+func TestPlaceType(t *testing.T) {
+	tests := []struct {
+		result nominatimResult
+		want   string
+	}{
+		{nominatimResult{Type: "city", AddressType: "city"}, "city"},
+		{nominatimResult{Type: "nature_reserve", AddressType: "leisure"}, "nature reserve"},
+		{nominatimResult{Type: "administrative", AddressType: "state"}, "state"},
+		{nominatimResult{Type: "administrative"}, "administrative"},
+		{nominatimResult{Type: "yes", AddressType: "building"}, "building"},
+		{nominatimResult{}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := placeType(tt.result); got != tt.want {
+			t.Errorf("placeType(%+v) = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestSearchOptions(t *testing.T) {
+	q := url.Values{}
+	options := SearchOptions{
+		Language:  "de,en",
+		Countries: []string{"at", "de"},
+		Viewbox:   &domain.Bounds{South: 47.5, West: 13.1, North: 47.9, East: 13.6},
+	}
+	options.apply(q)
+	for param, want := range map[string]string{
+		"accept-language": "de,en",
+		"countrycodes":    "at,de",
+		"viewbox":         "13.1000,47.9000,13.6000,47.5000",
+	} {
+		if got := q.Get(param); got != want {
+			t.Errorf("%s = %q, want %q", param, got, want)
+		}
+	}
+
+	if searchKey("Paris", 5, SearchOptions{}) != "search:5:paris" {
+		t.Errorf("plain search key changed: %q", searchKey("Paris", 5, SearchOptions{}))
+	}
+	if searchKey("Paris", 5, options) == searchKey("Paris", 5, SearchOptions{Language: "de,en"}) {
+		t.Error("countries and viewbox don't change the search key")
+	}
+}
+
+func TestSystemLanguage(t *testing.T) {
+	for lang, want := range map[string]string{
+		"de_AT.UTF-8@euro": "de-AT",
+		"fr_FR":            "fr-FR",
+		"C.UTF-8":          "",
+		"":                 "",
+	} {
+		t.Setenv("LC_ALL", "")
+		t.Setenv("LC_MESSAGES", "")
+		t.Setenv("LANG", lang)
+		if got := SystemLanguage(); got != want {
+			t.Errorf("LANG=%q: SystemLanguage() = %q, want %q", lang, got, want)
+		}
+	}
+}
+
+func TestOffline(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	s := NewNominatimService("test", "")
+	s.searchEndpoint, s.reverseEndpoint = server.URL, server.URL
+	s.cache.put(cacheEntry{
+		Key:     searchKey("Lyon", 5, SearchOptions{}),
+		Results: []cachedResult{{Location: domain.Location{Name: "Lyon"}}},
+		Stored:  time.Now().Add(-2 * cacheTTL),
+	})
+	s.SetOffline(true)
+
+	results, err := s.Search(context.Background(), "Lyon", 5, SearchOptions{})
+	if err != nil || len(results) != 1 {
+		t.Errorf("stale cached search = %v, %v; want the cached result", results, err)
+	}
+	if _, err := s.Search(context.Background(), "Paris", 5, SearchOptions{}); !errors.Is(err, domain.ErrPrivacyMode) {
+		t.Errorf("uncached search error = %v, want ErrPrivacyMode", err)
+	}
+	if _, err := s.ReverseGeocode(context.Background(), 45.76, 4.84); !errors.Is(err, domain.ErrPrivacyMode) {
+		t.Errorf("uncached reverse error = %v, want ErrPrivacyMode", err)
+	}
+	if requests != 0 {
+		t.Errorf("%d requests sent while offline", requests)
+	}
+}
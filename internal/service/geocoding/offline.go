@@ -0,0 +1,225 @@
+package geocoding
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	_ "embed"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+)
+
+// =============================================================================
+// Offline City Database
+// =============================================================================
+
+// The offline database lets basic searches ("Lyon", "Springfield, Illinois")
+// work without a network connection. It holds cities from GeoNames
+// (https://www.geonames.org, CC BY 4.0), one per line, tab-separated and
+// gzip-compressed:
+//
+//	name  ascii name  latitude  longitude  region  country  population
+//
+// Lines are sorted by population, largest first. The file is built from the
+// GeoNames dumps by cmd/gencities (see the go:generate line below), which
+// downloads the dumps it doesn't find in this directory; the repository
+// carries a small seed database of major cities until it is regenerated.
+//
+//go:generate go run ../../../cmd/gencities -max 100000 -o cities.tsv.gz
+//go:embed cities.tsv.gz
+var citiesData []byte
+
+// city is one entry of the offline database.
+type city struct {
+	// location is the city center with its name and address parts (no
+	// timezone yet; see result).
+	location domain.Location
+
+	// key and asciiKey are the folded name and ASCII name matched against
+	// queries (see foldName).
+	key, asciiKey string
+
+	// regionKey and countryKey are the folded region and country, matched
+	// against the parts of a query after the first comma.
+	regionKey, countryKey string
+}
+
+// cityColumns is the number of columns in a line of the database.
+const cityColumns = 7
+
+var (
+	// cities is the decoded database, loaded on the first offline search.
+	cities     []city
+	citiesOnce sync.Once
+)
+
+// loadCities decodes the embedded database. Malformed lines are skipped; a
+// broken file leaves the database empty.
+func loadCities() []city {
+	zr, err := gzip.NewReader(bytes.NewReader(citiesData))
+	if err != nil {
+		return nil
+	}
+	defer zr.Close()
+
+	var list []city
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		if len(fields) != cityColumns {
+			continue
+		}
+		lat, latErr := strconv.ParseFloat(fields[2], 64)
+		lon, lonErr := strconv.ParseFloat(fields[3], 64)
+		if latErr != nil || lonErr != nil {
+			continue
+		}
+		name, region, country := fields[0], fields[4], fields[5]
+		list = append(list, city{
+			location: domain.Location{
+				Latitude:  lat,
+				Longitude: lon,
+				Name:      joinNonEmpty(name, region, country),
+				City:      name,
+				Region:    region,
+				Country:   country,
+			},
+			key:        foldName(name),
+			asciiKey:   foldName(fields[1]),
+			regionKey:  foldName(region),
+			countryKey: foldName(country),
+		})
+	}
+	return list
+}
+
+// SearchOffline finds cities in the offline database.
+//
+// The first part of the query (up to a comma) must start a city's name, in
+// any case and with or without accents ("zurich" finds Zürich). Further
+// parts must start its region or country, so "Paris, Texas" finds the
+// smaller Paris. Exact name matches come first, then larger cities.
+//
+// Parameters:
+//   - query: The search text as typed
+//   - limit: Maximum number of results
+//
+// Returns the matching cities with their timezones, best match first;
+// none for an empty query.
+func SearchOffline(query string, limit int) []domain.SearchResult {
+	citiesOnce.Do(func() { cities = loadCities() })
+
+	parts := strings.Split(query, ",")
+	name := foldName(parts[0])
+	if name == "" || limit <= 0 {
+		return nil
+	}
+	var qualifiers []string
+	for _, p := range parts[1:] {
+		if q := foldName(p); q != "" {
+			qualifiers = append(qualifiers, q)
+		}
+	}
+
+	// The database is sorted by population, so matches are found largest
+	// first; exact names are moved ahead afterwards
+	var exact, prefix []domain.SearchResult
+	for _, c := range cities {
+		isExact := c.key == name || c.asciiKey == name
+		if !isExact && !strings.HasPrefix(c.key, name) && !strings.HasPrefix(c.asciiKey, name) {
+			continue
+		}
+		if !c.matches(qualifiers) {
+			continue
+		}
+		if isExact {
+			exact = append(exact, c.result())
+		} else if len(prefix) < limit {
+			prefix = append(prefix, c.result())
+		}
+		if len(exact) >= limit {
+			break
+		}
+	}
+	results := append(exact, prefix...)
+	return results[:min(len(results), limit)]
+}
+
+// matches reports whether every qualifier starts the city's region or
+// country.
+func (c city) matches(qualifiers []string) bool {
+	for _, q := range qualifiers {
+		if !strings.HasPrefix(c.regionKey, q) && !strings.HasPrefix(c.countryKey, q) {
+			return false
+		}
+	}
+	return true
+}
+
+// result returns the city as a search result, with its timezone.
+func (c city) result() domain.SearchResult {
+	loc := c.location
+	loc.Timezone = timezone.FromCoordinates(loc.Latitude, loc.Longitude)
+	return domain.SearchResult{Location: loc, Type: "city"}
+}
+
+// MergeResults combines Nominatim's results with offline ones.
+//
+// Nominatim's come first, as it ranks better and knows far more places.
+// Offline cities are added after them unless Nominatim already found a
+// place within sameCityDistance, up to limit results in all.
+func MergeResults(online, offline []domain.SearchResult, limit int) []domain.SearchResult {
+	merged := slices.Clone(online)
+	for _, r := range offline {
+		if len(merged) >= limit {
+			break
+		}
+		duplicate := slices.ContainsFunc(online, func(o domain.SearchResult) bool {
+			return o.Location.DistanceTo(r.Location) < sameCityDistance
+		})
+		if !duplicate {
+			merged = append(merged, r)
+		}
+	}
+	return merged[:min(len(merged), limit)]
+}
+
+// sameCityDistance is how close (in meters) an offline city must be to a
+// Nominatim result to count as the same place. City centers from the two
+// sources can lie a few kilometers apart.
+const sameCityDistance = 10000.0
+
+// foldName prepares a name for matching: lower case, accents removed from
+// common Latin letters, and spaces collapsed.
+func foldName(s string) string {
+	return strings.Join(strings.Fields(accentFolder.Replace(strings.ToLower(s))), " ")
+}
+
+// accentFolder maps accented lower-case Latin letters to plain ones.
+var accentFolder = strings.NewReplacer(
+	"à", "a", "á", "a", "â", "a", "ã", "a", "ä", "a", "å", "a", "ā", "a", "ą", "a",
+	"æ", "ae", "ç", "c", "ć", "c", "č", "c", "ď", "d", "đ", "d",
+	"è", "e", "é", "e", "ê", "e", "ë", "e", "ē", "e", "ę", "e", "ě", "e",
+	"ğ", "g", "ì", "i", "í", "i", "î", "i", "ï", "i", "ī", "i", "ı", "i",
+	"ł", "l", "ñ", "n", "ń", "n", "ň", "n",
+	"ò", "o", "ó", "o", "ô", "o", "õ", "o", "ö", "o", "ø", "o", "ō", "o", "ő", "o", "œ", "oe",
+	"ř", "r", "ś", "s", "š", "s", "ş", "s", "ß", "ss", "ť", "t", "ţ", "t",
+	"ù", "u", "ú", "u", "û", "u", "ü", "u", "ū", "u", "ů", "u", "ű", "u",
+	"ý", "y", "ÿ", "y", "ź", "z", "ż", "z", "ž", "z",
+)
+
+// joinNonEmpty joins the non-empty parts with ", ".
+func joinNonEmpty(parts ...string) string {
+	var kept []string
+	for _, p := range parts {
+		if p != "" {
+			kept = append(kept, p)
+		}
+	}
+	return strings.Join(kept, ", ")
+}
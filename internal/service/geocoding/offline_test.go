@@ -0,0 +1,65 @@
+package geocoding
+
+import (
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSearchOffline(t *testing.T) {
+	tests := []struct {
+		query       string
+		wantCity    string
+		wantRegion  string
+		wantCountry string
+	}{
+		{"London", "London", "England", "United Kingdom"},
+		{"zurich", "Zürich", "Zurich", "Switzerland"},
+		{"  REYKJAV ", "Reykjavík", "Capital Region", "Iceland"},
+		{"Paris", "Paris", "Île-de-France", "France"},
+		{"Paris, Texas", "Paris", "Texas", "United States"},
+		{"springfield, missouri", "Springfield", "Missouri", "United States"},
+	}
+	for _, tt := range tests {
+		results := SearchOffline(tt.query, 5)
+		if len(results) == 0 {
+			t.Errorf("SearchOffline(%q) found nothing", tt.query)
+			continue
+		}
+		loc := results[0].Location
+		if loc.City != tt.wantCity || loc.Region != tt.wantRegion || loc.Country != tt.wantCountry {
+			t.Errorf("SearchOffline(%q) = %q, %q, %q; want %q, %q, %q", tt.query,
+				loc.City, loc.Region, loc.Country, tt.wantCity, tt.wantRegion, tt.wantCountry)
+		}
+		if loc.Timezone == "" {
+			t.Errorf("SearchOffline(%q) has no timezone", tt.query)
+		}
+	}
+
+	for _, query := range []string{"", " , ", "Atlantis"} {
+		if results := SearchOffline(query, 5); len(results) != 0 {
+			t.Errorf("SearchOffline(%q) = %v, want nothing", query, results)
+		}
+	}
+	if results := SearchOffline("Springfield", 2); len(results) != 2 {
+		t.Errorf("limit 2 returned %d results", len(results))
+	}
+}
+
+func TestMergeResults(t *testing.T) {
+	online := []domain.SearchResult{
+		{Location: domain.Location{Name: "Paris (Nominatim)", Latitude: 48.8589, Longitude: 2.3200}},
+	}
+	offline := SearchOffline("Paris", 5)
+
+	merged := MergeResults(online, offline, 5)
+	if len(merged) != 2 || merged[0].Location.Name != "Paris (Nominatim)" {
+		t.Fatalf("merged = %v; want Nominatim's Paris, then Paris, Texas", merged)
+	}
+	if merged[1].Location.Region != "Texas" {
+		t.Errorf("second result is %q, want Paris, Texas", merged[1].Location.Name)
+	}
+	if merged := MergeResults(online, offline, 1); len(merged) != 1 {
+		t.Errorf("limit 1 returned %d results", len(merged))
+	}
+}
@@ -1,9 +1,23 @@
-// Package geolocation provides IP-based location detection using the IP-API service.
+// Package geolocation provides location detection using IP geolocation
+// services or the operating system's location services.
 //
 // This package enables automatic location detection based on the user's public IP
 // address. It's used when the "Auto-detect location on startup" setting is enabled,
 // providing a convenient way to set an initial location without user input.
 //
+// # Providers
+//
+// Each backend implements Provider (provider.go):
+//
+//   - IPAPIService: ip-api.com (this file)
+//   - IPInfoService: ipinfo.io, over HTTPS (ipinfo.go)
+//   - SystemService: the OS location services, GeoClue2 on Linux and the
+//     Windows Location API on Windows (system.go); much more accurate
+//
+// A Chain tries several providers in the user's fallback order
+// (domain.Settings.LocationProviders), each with its own timeout, so
+// detection still works when one service is blocked or unreachable.
+//
 // # IP-API Service
 //
 // The package uses ip-api.com, a free geolocation API that requires no authentication.
@@ -33,12 +47,14 @@
 package geolocation
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"time"
 
-	"github.com/megatih/GoGoldenHour/internal/config"
 	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
 )
 
 // =============================================================================
@@ -57,6 +73,9 @@ const (
 	ipAPIEndpoint = "http://ip-api.com/json/"
 )
 
+// Compile-time check that IPAPIService is a Provider.
+var _ Provider = (*IPAPIService)(nil)
+
 // =============================================================================
 // API Response Types
 // =============================================================================
@@ -138,7 +157,7 @@ type ipAPIResponse struct {
 // Usage:
 //
 //	service := geolocation.NewIPAPIService()
-//	location, err := service.DetectLocation()
+//	location, err := service.DetectLocation(ctx)
 //	if err != nil {
 //	    // Handle error (network failure, API error, etc.)
 //	    // Fall back to default location or last saved location
@@ -146,25 +165,30 @@ type ipAPIResponse struct {
 //	// Use location for solar calculations
 type IPAPIService struct {
 	// client is the HTTP client used for API requests.
-	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	// Requests are bounded by the caller's context (the provider timeout
+	// of a Chain) rather than a client timeout.
 	client *http.Client
+
+	// endpoint is the URL of the lookup (ipAPIEndpoint; a test server in
+	// tests).
+	endpoint string
 }
 
 // NewIPAPIService creates a new IP geolocation service.
 //
-// The service is configured with a timeout from config.DefaultHTTPTimeout
-// to prevent the application from hanging if the API is unreachable.
-//
 // Returns a ready-to-use IPAPIService instance.
 func NewIPAPIService() *IPAPIService {
 	return &IPAPIService{
-		client: &http.Client{
-			// Use the shared timeout constant for consistent network behavior
-			Timeout: config.DefaultHTTPTimeout,
-		},
+		client:   &http.Client{},
+		endpoint: ipAPIEndpoint,
 	}
 }
 
+// ID returns domain.LocationProviderIPAPI.
+func (s *IPAPIService) ID() string {
+	return domain.LocationProviderIPAPI
+}
+
 // DetectLocation attempts to detect the user's geographic location based on their IP address.
 //
 // This method makes an HTTP request to the IP-API service, which returns
@@ -172,6 +196,10 @@ func NewIPAPIService() *IPAPIService {
 // is approximate (typically city-level) and may not be accurate for users
 // behind VPNs or on mobile networks.
 //
+// Parameters:
+//   - ctx: Bounds the request; without a deadline, config.DefaultHTTPTimeout
+//     applies
+//
 // Returns:
 //   - domain.Location: The detected location with coordinates, name, and timezone
 //   - error: Non-nil if detection fails (network error, API error, etc.)
@@ -184,10 +212,19 @@ func NewIPAPIService() *IPAPIService {
 //
 // On error, callers should fall back to the default location (London, UK)
 // or the user's last saved location.
-func (s *IPAPIService) DetectLocation() (domain.Location, error) {
+func (s *IPAPIService) DetectLocation(ctx context.Context) (domain.Location, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
 	// Make GET request to the IP-API endpoint.
 	// The API uses the source IP address of the request to determine location.
-	resp, err := s.client.Get(ipAPIEndpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint, nil)
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	stats.RecordRequest(stats.OpIPAPI, time.Since(start), resp, err)
 	if err != nil {
 		// Network error (timeout, DNS failure, connection refused, etc.)
 		return domain.Location{}, fmt.Errorf("failed to fetch location: %w", err)
@@ -235,5 +272,8 @@ func (s *IPAPIService) DetectLocation() (domain.Location, error) {
 		Elevation: 0, // IP-API doesn't provide elevation data
 		Name:      name,
 		Timezone:  apiResp.Timezone,
+		City:      apiResp.City,
+		Region:    apiResp.RegionName,
+		Country:   apiResp.Country,
 	}, nil
 }
@@ -0,0 +1,167 @@
+package geolocation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// ipinfo.io Service
+// =============================================================================
+
+// ipInfoEndpoint is the URL of the ipinfo.io lookup of the caller's address.
+//
+// Unlike ip-api.com's free tier, ipinfo.io answers over HTTPS without an
+// API key (limited to 50,000 requests per month, far beyond one request
+// per app launch), which also gets it through networks that block plain
+// HTTP lookups.
+//
+// Documentation: https://ipinfo.io/developers
+const ipInfoEndpoint = "https://ipinfo.io/json"
+
+// Compile-time check that IPInfoService is a Provider.
+var _ Provider = (*IPInfoService)(nil)
+
+// ipInfoResponse represents the JSON response from ipinfo.io.
+//
+// Example response:
+//
+//	{
+//	  "ip": "8.8.8.8",
+//	  "city": "Mountain View",
+//	  "region": "California",
+//	  "country": "US",
+//	  "loc": "37.4056,-122.0775",
+//	  "timezone": "America/Los_Angeles"
+//	}
+//
+// Errors come with a non-200 status and an "error" object.
+type ipInfoResponse struct {
+	// City and Region are the place names (e.g., "Munich", "Bavaria").
+	City   string `json:"city"`
+	Region string `json:"region"`
+
+	// Country is the ISO 3166-1 alpha-2 country code (e.g., "DE").
+	Country string `json:"country"`
+
+	// Loc holds the coordinates as "latitude,longitude".
+	Loc string `json:"loc"`
+
+	// Timezone is the IANA timezone identifier.
+	Timezone string `json:"timezone"`
+
+	// Bogon is true for private and reserved addresses, which have no
+	// location.
+	Bogon bool `json:"bogon"`
+}
+
+// location converts the response to a domain.Location.
+func (r ipInfoResponse) location() (domain.Location, error) {
+	if r.Bogon {
+		return domain.Location{}, fmt.Errorf("ipinfo.io error: private address")
+	}
+	latText, lonText, ok := strings.Cut(r.Loc, ",")
+	lat, errLat := strconv.ParseFloat(latText, 64)
+	lon, errLon := strconv.ParseFloat(lonText, 64)
+	if !ok || errLat != nil || errLon != nil {
+		return domain.Location{}, fmt.Errorf("ipinfo.io returned no coordinates")
+	}
+
+	name := r.City
+	for _, part := range []string{r.Region, r.Country} {
+		if part != "" && name != "" {
+			name += ", "
+		}
+		name += part
+	}
+	if name == "" {
+		name = "Unknown Location"
+	}
+	return domain.Location{
+		Latitude:  lat,
+		Longitude: lon,
+		Name:      name,
+		Timezone:  r.Timezone,
+		City:      r.City,
+		Region:    r.Region,
+		Country:   r.Country,
+	}, nil
+}
+
+// IPInfoService handles IP-based geolocation using the ipinfo.io service.
+//
+// It works like IPAPIService, with another provider behind it: the same
+// accuracy limits apply, but if one service is blocked or down the other
+// usually still answers.
+type IPInfoService struct {
+	// client is the HTTP client used for API requests; requests are
+	// bounded by the caller's context.
+	client *http.Client
+
+	// endpoint is the URL of the lookup (ipInfoEndpoint; a test server in
+	// tests).
+	endpoint string
+}
+
+// NewIPInfoService creates a new ipinfo.io geolocation service.
+//
+// Returns a ready-to-use IPInfoService instance.
+func NewIPInfoService() *IPInfoService {
+	return &IPInfoService{
+		client:   &http.Client{},
+		endpoint: ipInfoEndpoint,
+	}
+}
+
+// ID returns domain.LocationProviderIPInfo.
+func (s *IPInfoService) ID() string {
+	return domain.LocationProviderIPInfo
+}
+
+// DetectLocation looks up the location of the public IP address.
+//
+// Parameters:
+//   - ctx: Bounds the request; without a deadline, config.DefaultHTTPTimeout
+//     applies
+//
+// Returns:
+//   - domain.Location: The detected location with coordinates, name, and
+//     timezone
+//   - error: Non-nil if the request fails, the address has no location, or
+//     the response can't be read
+func (s *IPInfoService) DetectLocation(ctx context.Context) (domain.Location, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint, nil)
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	stats.RecordRequest(stats.OpIPInfo, time.Since(start), resp, err)
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to fetch location: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.Location{}, fmt.Errorf("ipinfo.io returned status %d", resp.StatusCode)
+	}
+
+	var apiResp ipInfoResponse
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return domain.Location{}, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return apiResp.location()
+}
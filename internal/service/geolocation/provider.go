@@ -0,0 +1,109 @@
+package geolocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Provider Interface
+// =============================================================================
+
+// Provider is a location detection backend.
+//
+// Implementations block until they have a location or ctx is done, so they
+// must be called from a background goroutine. Locations of IP services come
+// with a name and timezone; the system location services only report
+// coordinates (see SystemService).
+type Provider interface {
+	// ID returns the provider's identifier (domain.LocationProvider.ID).
+	ID() string
+
+	// DetectLocation finds the current location.
+	DetectLocation(ctx context.Context) (domain.Location, error)
+}
+
+// withDefaultTimeout bounds ctx by config.DefaultHTTPTimeout unless it has
+// a deadline already, so providers never wait forever.
+func withDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, config.DefaultHTTPTimeout)
+}
+
+// =============================================================================
+// Fallback Chain
+// =============================================================================
+
+// Step is a provider in a Chain, with how long it may take.
+type Step struct {
+	// Provider is the backend to ask.
+	Provider Provider
+
+	// Timeout is how long to wait for it before moving on.
+	Timeout time.Duration
+}
+
+// Chain tries providers in order until one finds the location.
+//
+// Usage:
+//
+//	chain := geolocation.Chain{
+//	    {Provider: geolocation.NewIPAPIService(), Timeout: 10 * time.Second},
+//	    {Provider: geolocation.NewIPInfoService(), Timeout: 10 * time.Second},
+//	}
+//	detection, err := chain.Detect(ctx)
+type Chain []Step
+
+// Detection is the result of Chain.Detect.
+type Detection struct {
+	// Location is the detected location.
+	Location domain.Location
+
+	// Provider is the ID of the provider that found it.
+	Provider string
+
+	// Failures are the errors of the providers tried before it, in order
+	// (each naming its provider); empty if the first one succeeded.
+	Failures []error
+}
+
+// Detect asks each provider in turn, each for at most its timeout.
+//
+// This blocks for up to the sum of the timeouts and must be called from a
+// background goroutine. Cancelling ctx stops the current provider and
+// skips the rest.
+//
+// Returns:
+//   - Detection: The location and which provider found it
+//   - error: Non-nil if the chain is empty or every provider failed; it
+//     joins the providers' errors
+func (c Chain) Detect(ctx context.Context) (Detection, error) {
+	if len(c) == 0 {
+		return Detection{}, errors.New("all location providers are turned off")
+	}
+
+	var failures []error
+	for _, step := range c {
+		if ctx.Err() != nil {
+			break
+		}
+		stepCtx, cancel := context.WithTimeout(ctx, step.Timeout)
+		loc, err := step.Provider.DetectLocation(stepCtx)
+		cancel()
+		if err == nil {
+			return Detection{Location: loc, Provider: step.Provider.ID(), Failures: failures}, nil
+		}
+		failures = append(failures, fmt.Errorf("%s: %w", step.Provider.ID(), err))
+	}
+	if ctx.Err() != nil {
+		failures = append(failures, ctx.Err())
+	}
+	return Detection{}, errors.Join(failures...)
+}
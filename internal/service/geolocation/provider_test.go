@@ -0,0 +1,113 @@
+package geolocation
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// fakeProvider returns a fixed result, or waits for its context to end if
+// hang is set.
+type fakeProvider struct {
+	id   string
+	loc  domain.Location
+	err  error
+	hang bool
+}
+
+func (p *fakeProvider) ID() string { return p.id }
+
+func (p *fakeProvider) DetectLocation(ctx context.Context) (domain.Location, error) {
+	if p.hang {
+		<-ctx.Done()
+		return domain.Location{}, ctx.Err()
+	}
+	return p.loc, p.err
+}
+
+func TestChainDetect(t *testing.T) {
+	paris := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris"}
+	failing := &fakeProvider{id: "failing", err: errors.New("blocked")}
+	slow := &fakeProvider{id: "slow", hang: true}
+	working := &fakeProvider{id: "working", loc: paris}
+
+	chain := Chain{
+		{Provider: failing, Timeout: time.Second},
+		{Provider: slow, Timeout: 10 * time.Millisecond},
+		{Provider: working, Timeout: time.Second},
+	}
+	detection, err := chain.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if detection.Provider != "working" || detection.Location != paris {
+		t.Errorf("Detect = %+v, want paris from working", detection)
+	}
+	if len(detection.Failures) != 2 ||
+		!strings.Contains(detection.Failures[0].Error(), "failing: blocked") ||
+		!errors.Is(detection.Failures[1], context.DeadlineExceeded) {
+		t.Errorf("Failures = %v", detection.Failures)
+	}
+
+	_, err = Chain{{Provider: failing, Timeout: time.Second}}.Detect(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "failing: blocked") {
+		t.Errorf("all failing: err = %v", err)
+	}
+	if _, err := (Chain{}).Detect(context.Background()); err == nil {
+		t.Error("empty chain: want an error")
+	}
+}
+
+func TestIPInfoService(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string // Location name
+		wantErr string
+	}{
+		{
+			name:   "location",
+			status: http.StatusOK,
+			body: `{"ip": "8.8.8.8", "city": "Mountain View", "region": "California",
+				"country": "US", "loc": "37.4056,-122.0775", "timezone": "America/Los_Angeles"}`,
+			want: "Mountain View, California, US",
+		},
+		{name: "private address", status: http.StatusOK, body: `{"ip": "10.0.0.1", "bogon": true}`, wantErr: "private address"},
+		{name: "no coordinates", status: http.StatusOK, body: `{"city": "Nowhere"}`, wantErr: "no coordinates"},
+		{name: "rate limited", status: http.StatusTooManyRequests, body: `{}`, wantErr: "status 429"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			service := NewIPInfoService()
+			service.endpoint = server.URL
+			loc, err := service.DetectLocation(context.Background())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("DetectLocation error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DetectLocation: %v", err)
+			}
+			if loc.Name != tt.want || loc.Latitude != 37.4056 || loc.Longitude != -122.0775 ||
+				loc.Timezone != "America/Los_Angeles" || loc.Country != "US" {
+				t.Errorf("DetectLocation = %+v", loc)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package geolocation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// System Location Service
+// =============================================================================
+
+// Compile-time check that SystemService is a Provider.
+var _ Provider = (*SystemService)(nil)
+
+// SystemService detects the location using the operating system's location
+// services instead of the public IP address.
+//
+// On laptops this is much more accurate than IP-API, because the OS combines
+// Wi-Fi access points, GPS hardware (if present) and other sources, typically
+// getting within tens of meters instead of city-level.
+//
+// # Platform Backends
+//
+//   - Linux: GeoClue2 over the D-Bus system bus (see locateSystem in
+//     system_linux.go). The desktop may ask the user for permission.
+//   - Windows: The Windows Location API through .NET's GeoCoordinateWatcher
+//     (see system_windows.go). Requires location access to be enabled in
+//     Settings → Privacy → Location.
+//   - Other platforms: Not supported; DetectLocation always returns an error.
+//
+// The OS only reports coordinates, so the returned location has no name or
+// timezone. Callers fill these in (e.g., by reverse geocoding), just like
+// for a map click.
+//
+// Usage:
+//
+//	service := geolocation.NewSystemService()
+//	location, err := service.DetectLocation(ctx)
+//	if err != nil {
+//	    // Fall back to IP-based detection
+//	}
+type SystemService struct{}
+
+// NewSystemService creates a new OS location service.
+//
+// Returns a ready-to-use SystemService instance. Creating the service does
+// not contact the OS; the platform backend is only used by DetectLocation.
+func NewSystemService() *SystemService {
+	return &SystemService{}
+}
+
+// ID returns domain.LocationProviderSystem.
+func (s *SystemService) ID() string {
+	return domain.LocationProviderSystem
+}
+
+// DetectLocation asks the operating system for the current position.
+//
+// This call blocks until a position fix is available or ctx is done, so it
+// must be run in a background goroutine. Wi-Fi positioning usually answers
+// within a few seconds, but the first fix after boot, or a permission
+// prompt shown by the desktop, can take longer (hence the 30 second
+// default timeout of domain.DefaultLocationProviders).
+//
+// Parameters:
+//   - ctx: Bounds the wait; without a deadline, config.DefaultHTTPTimeout
+//     applies
+//
+// Returns:
+//   - domain.Location: Coordinates only (Name and Timezone are empty)
+//   - error: Non-nil if the platform has no location service, the service
+//     is disabled or denied access, or no fix arrives in time
+func (s *SystemService) DetectLocation(ctx context.Context) (domain.Location, error) {
+	ctx, cancel := withDefaultTimeout(ctx)
+	defer cancel()
+
+	lat, lon, err := locateSystem(ctx)
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("system location unavailable: %w", err)
+	}
+
+	return domain.Location{
+		Latitude:  lat,
+		Longitude: lon,
+	}, nil
+}
@@ -0,0 +1,115 @@
+//go:build linux
+
+package geolocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// GeoClue2 D-Bus names.
+const (
+	geoclueService        = "org.freedesktop.GeoClue2"
+	geoclueManagerPath    = "/org/freedesktop/GeoClue2/Manager"
+	geoclueManagerIface   = "org.freedesktop.GeoClue2.Manager"
+	geoclueClientIface    = "org.freedesktop.GeoClue2.Client"
+	geoclueLocationIface  = "org.freedesktop.GeoClue2.Location"
+	geoclueDesktopID      = "gogoldenhour"
+	geoclueAccuracyExact  = uint32(8) // GCLUE_ACCURACY_LEVEL_EXACT
+	geocluePollInterval   = 250 * time.Millisecond
+	geoclueNoLocationPath = dbus.ObjectPath("/")
+)
+
+// locateSystem gets a position fix from GeoClue2.
+//
+// The GeoClue flow is:
+//  1. Manager.GetClient creates a client object tied to this connection
+//  2. DesktopId and RequestedAccuracyLevel are set (GeoClue requires the
+//     desktop ID to decide whether the app may use location)
+//  3. Client.Start begins positioning; the desktop may ask for permission
+//  4. The client's Location property changes from "/" to a Location object
+//     once a fix is available (polled instead of watching the signal)
+//  5. Latitude and Longitude are read from the Location object
+//
+// A private connection is used so that closing it releases the client,
+// which stops positioning. The context bounds the connection's lifetime:
+// once it expires, pending calls fail instead of blocking.
+func locateSystem(ctx context.Context) (float64, float64, error) {
+	conn, err := dbus.SystemBusPrivate(dbus.WithContext(ctx))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to connect to the system bus: %w", err)
+	}
+	defer conn.Close()
+	if err := conn.Auth(nil); err != nil {
+		return 0, 0, fmt.Errorf("failed to connect to the system bus: %w", err)
+	}
+	if err := conn.Hello(); err != nil {
+		return 0, 0, fmt.Errorf("failed to connect to the system bus: %w", err)
+	}
+
+	var clientPath dbus.ObjectPath
+	manager := conn.Object(geoclueService, geoclueManagerPath)
+	if err := manager.CallWithContext(ctx, geoclueManagerIface+".GetClient", 0).Store(&clientPath); err != nil {
+		return 0, 0, fmt.Errorf("GeoClue is not available: %w", err)
+	}
+	client := conn.Object(geoclueService, clientPath)
+
+	if err := setProperty(ctx, client, geoclueClientIface, "DesktopId", geoclueDesktopID); err != nil {
+		return 0, 0, fmt.Errorf("failed to configure GeoClue client: %w", err)
+	}
+	if err := setProperty(ctx, client, geoclueClientIface, "RequestedAccuracyLevel", geoclueAccuracyExact); err != nil {
+		return 0, 0, fmt.Errorf("failed to configure GeoClue client: %w", err)
+	}
+	if err := client.CallWithContext(ctx, geoclueClientIface+".Start", 0).Err; err != nil {
+		return 0, 0, fmt.Errorf("GeoClue denied location access: %w", err)
+	}
+
+	// Wait for the first fix
+	var location dbus.ObjectPath
+	for {
+		if err := getProperty(ctx, client, geoclueClientIface, "Location", &location); err != nil {
+			return 0, 0, err
+		}
+		if location.IsValid() && location != geoclueNoLocationPath {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return 0, 0, errors.New("no position fix")
+		case <-time.After(geocluePollInterval):
+		}
+	}
+
+	var latitude, longitude float64
+	fix := conn.Object(geoclueService, location)
+	if err := getProperty(ctx, fix, geoclueLocationIface, "Latitude", &latitude); err != nil {
+		return 0, 0, fmt.Errorf("GeoClue returned invalid coordinates: %w", err)
+	}
+	if err := getProperty(ctx, fix, geoclueLocationIface, "Longitude", &longitude); err != nil {
+		return 0, 0, fmt.Errorf("GeoClue returned invalid coordinates: %w", err)
+	}
+	return latitude, longitude, nil
+}
+
+// getProperty reads a D-Bus property into value (a pointer of the matching type).
+//
+// Unlike dbus.Object.StoreProperty, the call honors ctx.
+func getProperty(ctx context.Context, obj dbus.BusObject, iface, property string, value interface{}) error {
+	var variant dbus.Variant
+	err := obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Get", 0, iface, property).Store(&variant)
+	if err != nil {
+		return err
+	}
+	return dbus.Store([]interface{}{variant.Value()}, value)
+}
+
+// setProperty writes a D-Bus property, wrapping value in a variant.
+func setProperty(ctx context.Context, obj dbus.BusObject, iface, property string, value interface{}) error {
+	return obj.CallWithContext(ctx, "org.freedesktop.DBus.Properties.Set", 0, iface, property,
+		dbus.MakeVariant(value)).Err
+}
@@ -0,0 +1,16 @@
+//go:build !linux && !windows
+
+package geolocation
+
+import (
+	"context"
+	"errors"
+)
+
+// locateSystem reports that OS location services are not supported here.
+//
+// macOS Core Location requires an Objective-C bridge and an app bundle with
+// a usage description, so it is not available in this build.
+func locateSystem(_ context.Context) (float64, float64, error) {
+	return 0, 0, errors.New("not supported on this platform")
+}
@@ -0,0 +1,94 @@
+//go:build windows
+
+package geolocation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+)
+
+// createNoWindow is the CREATE_NO_WINDOW process creation flag. It stops
+// Windows from opening a console for PowerShell, which would otherwise
+// flash on screen because the app itself has no console.
+const createNoWindow = 0x08000000
+
+// powershellStartup is the part of the context's budget left for PowerShell
+// to start and load System.Device before the script's own timeouts begin.
+const powershellStartup = 5 * time.Second
+
+// windowsLocateScript queries the Windows Location API via PowerShell.
+//
+// System.Device.Location.GeoCoordinateWatcher is the .NET wrapper around the
+// Windows Location API. The script waits for a known position and prints
+// "latitude longitude" with invariant culture (so the decimal separator is
+// always "."), or exits with a non-zero status:
+//   - 2: The watcher could not start (location access disabled or denied)
+//   - 3: No position fix arrived in time
+//
+// Running the API through PowerShell avoids COM bindings, which Go can only
+// use through cgo or large third-party packages.
+const windowsLocateScript = `
+Add-Type -AssemblyName System.Device
+$w = New-Object System.Device.Location.GeoCoordinateWatcher([System.Device.Location.GeoPositionAccuracy]::High)
+if (-not $w.TryStart($false, [TimeSpan]::FromSeconds(%d))) { exit 2 }
+$deadline = (Get-Date).AddSeconds(%d)
+while ($w.Position.Location.IsUnknown -and (Get-Date) -lt $deadline) { Start-Sleep -Milliseconds 250 }
+$l = $w.Position.Location
+$w.Stop()
+if ($l.IsUnknown) { exit 3 }
+[string]::Format([Globalization.CultureInfo]::InvariantCulture, '{0} {1}', $l.Latitude, $l.Longitude)
+`
+
+// locateSystem gets a position fix from the Windows Location API.
+//
+// The script's timeouts are derived from the context deadline (falling back
+// to config.DefaultHTTPTimeout), so PowerShell gives up on its own before the
+// context kills it and the "no position fix" exit status is reported.
+func locateSystem(ctx context.Context) (float64, float64, error) {
+	budget := config.DefaultHTTPTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		budget = time.Until(deadline)
+	}
+	seconds := max(int((budget - powershellStartup).Seconds()), 1)
+	script := fmt.Sprintf(windowsLocateScript, seconds, seconds)
+
+	cmd := exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive",
+		"-Command", script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: createNoWindow}
+
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			switch exitErr.ExitCode() {
+			case 2:
+				return 0, 0, errors.New("location access is disabled or denied in Windows settings")
+			case 3:
+				return 0, 0, errors.New("no position fix")
+			}
+		}
+		return 0, 0, fmt.Errorf("failed to query Windows Location API: %w", err)
+	}
+
+	fields := strings.Fields(string(output))
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("unexpected Windows Location API output %q", output)
+	}
+	lat, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid latitude: %w", err)
+	}
+	lon, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid longitude: %w", err)
+	}
+	return lat, lon, nil
+}
@@ -0,0 +1,46 @@
+// Package linkscheme registers the app as the handler of gogoldenhour://
+// share links (see domain.Link), so clicking a link in a browser or chat
+// app starts GoGoldenHour on the linked place and date.
+//
+// Registration is per user and needs no administrator rights:
+//   - Linux: a hidden desktop entry in ~/.local/share/applications that
+//     handles x-scheme-handler/gogoldenhour, made the default with xdg-mime
+//   - Windows: the URL protocol keys under HKEY_CURRENT_USER\Software\Classes
+//
+// On macOS the scheme is declared in the app bundle's Info.plist
+// (CFBundleURLTypes), which can't be changed at run time; Register reports
+// ErrUnsupported there.
+//
+// The handler starts a new instance with the link as its argument; the
+// command line is read by cmd/gogoldenhour.
+package linkscheme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnsupported is returned by Register on platforms where the scheme
+// can't be registered at run time.
+var ErrUnsupported = errors.New("links can't be registered on this platform")
+
+// Register makes the running executable the handler of gogoldenhour://
+// links for the current user, replacing an earlier registration (e.g., of
+// an older copy of the app elsewhere).
+//
+// Returns ErrUnsupported on platforms without run-time registration, or an
+// error if the registration can't be written.
+func Register() error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to register links: %w", err)
+	}
+	if err := register(executable); err != nil {
+		if errors.Is(err, ErrUnsupported) {
+			return err
+		}
+		return fmt.Errorf("failed to register links: %w", err)
+	}
+	return nil
+}
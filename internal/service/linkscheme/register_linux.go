@@ -0,0 +1,61 @@
+//go:build linux
+
+package linkscheme
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// desktopFile is the name of the desktop entry that handles the links.
+const desktopFile = "gogoldenhour-link.desktop"
+
+// register writes a hidden desktop entry for the scheme and makes it the
+// default handler.
+//
+// The entry goes to $XDG_DATA_HOME/applications (~/.local/share/applications
+// by default). xdg-mime is part of xdg-utils, which desktops install; if it
+// is missing, the entry alone is usually picked up the next time the
+// desktop rebuilds its handler cache.
+func register(executable string) error {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return err
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	dir := filepath.Join(dataHome, "applications")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, desktopFile), []byte(desktopEntry(executable)), 0o644); err != nil {
+		return err
+	}
+
+	if _, err := exec.LookPath("xdg-mime"); err != nil {
+		return nil
+	}
+	return exec.Command("xdg-mime", "default", desktopFile, "x-scheme-handler/"+domain.LinkScheme).Run()
+}
+
+// desktopEntry returns the desktop entry that opens links with executable.
+// %u is replaced by the link; the path is quoted and escaped as the desktop
+// entry specification requires (backslashes twice: once for the quoted
+// argument, once for the string value).
+func desktopEntry(executable string) string {
+	quoted := `"` + strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", "$", `\\$`, "%", "%%").Replace(executable) + `"`
+	return "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=GoGoldenHour\n" +
+		"Comment=Open GoGoldenHour share links\n" +
+		"Exec=" + quoted + " %u\n" +
+		"MimeType=x-scheme-handler/" + domain.LinkScheme + ";\n" +
+		"NoDisplay=true\n" +
+		"Terminal=false\n"
+}
@@ -0,0 +1,11 @@
+//go:build !linux && !windows
+
+package linkscheme
+
+// register reports that links can't be registered at run time here.
+//
+// macOS reads URL schemes from the app bundle's Info.plist when the bundle
+// is installed, so the scheme has to be declared there when packaging.
+func register(_ string) error {
+	return ErrUnsupported
+}
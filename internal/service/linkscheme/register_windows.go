@@ -0,0 +1,37 @@
+//go:build windows
+
+package linkscheme
+
+import (
+	"os/exec"
+	"syscall"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// createNoWindow is the CREATE_NO_WINDOW process creation flag. It stops a
+// console from flashing on screen for reg.exe (see the geolocation
+// package).
+const createNoWindow = 0x08000000
+
+// register writes the URL protocol keys of the scheme under
+// HKEY_CURRENT_USER\Software\Classes with reg.exe:
+//
+//	gogoldenhour                     (default) = "URL:GoGoldenHour link"
+//	                                 URL Protocol = ""
+//	gogoldenhour\shell\open\command  (default) = "C:\...\gogoldenhour.exe" "%1"
+func register(executable string) error {
+	key := `HKCU\Software\Classes\` + domain.LinkScheme
+	for _, args := range [][]string{
+		{"add", key, "/ve", "/d", "URL:GoGoldenHour link", "/f"},
+		{"add", key, "/v", "URL Protocol", "/d", "", "/f"},
+		{"add", key + `\shell\open\command`, "/ve", "/d", `"` + executable + `" "%1"`, "/f"},
+	} {
+		cmd := exec.Command("reg", args...)
+		cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: createNoWindow}
+		if err := cmd.Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package photo
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rwcarlsen/goexif/exif"
+	"github.com/rwcarlsen/goexif/tiff"
+)
+
+// =============================================================================
+// EXIF
+// =============================================================================
+
+// exifTimeLayout is the format of EXIF dates and times, e.g.,
+// "2025:06:21 21:24:07".
+const exifTimeLayout = "2006:01:02 15:04:05"
+
+// ErrNoPosition is returned for photos without a GPS position, e.g., from
+// cameras without GPS or whose location tagging was off.
+var ErrNoPosition = errors.New("the photo has no GPS position")
+
+// metadata is what Analyze needs from a photo's EXIF data.
+type metadata struct {
+	// latitude, longitude and altitude are the GPS position; altitude is
+	// zero if the photo doesn't have it.
+	latitude  float64
+	longitude float64
+	altitude  float64
+
+	// utc is the GPS time stamp (zero if there is none); camera clocks
+	// drift and often aren't switched to the local time when traveling,
+	// the GPS clock doesn't.
+	utc time.Time
+
+	// local is the camera's "DateTimeOriginal" (or "DateTime") as written,
+	// in local time without a timezone; empty if there is none.
+	local string
+
+	// camera is the make and model, e.g., "Canon EOS R6".
+	camera string
+}
+
+// readMetadata reads the EXIF data of a JPEG, TIFF or raw file based on
+// TIFF (most raw formats, e.g., CR2, NEF, ARW and DNG).
+//
+// Returns ErrNoPosition if the photo has no GPS position, or an error if
+// it has no EXIF data or neither a GPS nor a camera time.
+func readMetadata(r io.Reader) (metadata, error) {
+	x, err := exif.Decode(r)
+	if err != nil {
+		return metadata{}, fmt.Errorf("failed to read EXIF data: %w", err)
+	}
+
+	var m metadata
+	m.latitude, m.longitude, err = x.LatLong()
+	if err != nil {
+		return metadata{}, ErrNoPosition
+	}
+	if alt, err := x.Get(exif.GPSAltitude); err == nil {
+		if num, den, err := alt.Rat2(0); err == nil && den != 0 {
+			m.altitude = float64(num) / float64(den)
+			// Reference 1 is below sea level
+			if ref, err := x.Get(exif.GPSAltitudeRef); err == nil {
+				if below, err := ref.Int(0); err == nil && below == 1 {
+					m.altitude = -m.altitude
+				}
+			}
+		}
+	}
+
+	m.utc = gpsTime(x)
+	for _, name := range []exif.FieldName{exif.DateTimeOriginal, exif.DateTime} {
+		if s := stringTag(x, name); s != "" {
+			m.local = s
+			break
+		}
+	}
+	if m.utc.IsZero() && m.local == "" {
+		return metadata{}, errors.New("the photo has no capture time")
+	}
+
+	maker, model := stringTag(x, exif.Make), stringTag(x, exif.Model)
+	// Many models already start with the make ("Canon EOS R6")
+	if maker != "" && !strings.HasPrefix(strings.ToLower(model), strings.ToLower(maker)) {
+		model = strings.TrimSpace(maker + " " + model)
+	}
+	m.camera = model
+	return m, nil
+}
+
+// gpsTime combines the GPS date and time stamps, or returns zero if the
+// photo doesn't have both.
+//
+// The date is "2025:06:21"; the time is three rationals for the hours,
+// minutes and seconds (UTC).
+func gpsTime(x *exif.Exif) time.Time {
+	date, err := time.Parse("2006:01:02", stringTag(x, exif.GPSDateStamp))
+	if err != nil {
+		return time.Time{}
+	}
+	tag, err := x.Get(exif.GPSTimeStamp)
+	if err != nil || tag.Format() != tiff.RatVal || tag.Count < 3 {
+		return time.Time{}
+	}
+	var seconds float64
+	for i, unit := range []float64{3600, 60, 1} {
+		num, den, err := tag.Rat2(i)
+		if err != nil || den == 0 {
+			return time.Time{}
+		}
+		seconds += float64(num) / float64(den) * unit
+	}
+	return date.Add(time.Duration(math.Round(seconds)) * time.Second)
+}
+
+// stringTag returns a text tag without its padding, or "" if the photo
+// doesn't have it.
+func stringTag(x *exif.Exif, name exif.FieldName) string {
+	tag, err := x.Get(name)
+	if err != nil {
+		return ""
+	}
+	s, err := tag.StringVal()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(s, "\x00"))
+}
@@ -0,0 +1,109 @@
+// Package photo works out the light a photo was taken in from its EXIF
+// data, for Tools → Analyze Photo.
+//
+// A photo with a GPS position and a capture time tells the sun's position
+// when it was shot and whether that was a golden or blue hour, as the
+// user's settings define them. Going through a catalog this way shows
+// which light a favorite shot had, and when to be back for it.
+//
+// # Capture Time
+//
+// The GPS time stamp (UTC) is used when the photo has one. Otherwise the
+// camera's "DateTimeOriginal" is taken as local time at the place the
+// photo was taken: EXIF only recently gained a timezone field, which few
+// cameras fill in, and a camera clock left at home time is off by the
+// time difference.
+//
+// Reading EXIF data is local and fast; nothing is sent over the network.
+package photo
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/service/solar"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+)
+
+// =============================================================================
+// Analysis
+// =============================================================================
+
+// FileFilter is the file dialog filter of the photos Analyze can read.
+const FileFilter = "Photos (*.jpg *.jpeg *.tif *.tiff *.dng *.cr2 *.nef *.arw *.orf *.pef);;All Files (*)"
+
+// Analyze reads a photo's GPS position and capture time and works out the
+// light it was taken in.
+//
+// Parameters:
+//   - path: The photo file (JPEG, TIFF, or a raw format based on TIFF)
+//   - settings: The golden and blue hour definitions (a favorite's
+//     overrides at the place are applied)
+//
+// Returns:
+//   - domain.PhotoLight: The sun's position and the light phase when the
+//     photo was taken
+//   - error: ErrNoPosition if the photo has no GPS position, or non-nil if
+//     it can't be read or has no capture time
+func Analyze(path string, settings domain.Settings) (domain.PhotoLight, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return domain.PhotoLight{}, fmt.Errorf("failed to open photo: %w", err)
+	}
+	defer f.Close()
+	m, err := readMetadata(f)
+	if err != nil {
+		return domain.PhotoLight{}, err
+	}
+
+	loc := domain.Location{
+		Latitude:  m.latitude,
+		Longitude: m.longitude,
+		Elevation: m.altitude,
+		Name:      domain.FormatCoordinatesIn(m.latitude, m.longitude, settings.CoordinateFormat),
+		Timezone:  timezone.FromCoordinates(m.latitude, m.longitude),
+	}
+	if favorite, ok := domain.FavoriteAt(settings.Favorites, loc); ok {
+		loc = favorite.CurrentLocation()
+	}
+	tz := timezone.LoadLocation(loc.Latitude, loc.Longitude)
+	taken := m.utc.In(tz)
+	if m.utc.IsZero() {
+		taken, err = time.ParseInLocation(exifTimeLayout, m.local, tz)
+		if err != nil {
+			return domain.PhotoLight{}, fmt.Errorf("invalid capture time %q: %w", m.local, err)
+		}
+	}
+
+	return lightAt(path, m.camera, loc, taken, settings)
+}
+
+// lightAt calculates the sun's position and the light phase at a place
+// and time, the part of Analyze after reading the photo.
+func lightAt(path, camera string, loc domain.Location, taken time.Time, settings domain.Settings) (domain.PhotoLight, error) {
+	pos, err := solar.SunPositionAt(loc, taken)
+	if err != nil {
+		return domain.PhotoLight{}, err
+	}
+	// The day before too, for a blue hour running past midnight
+	days, err := solar.New(settings.ForLocation(loc)).CalculateRange(loc,
+		domain.NewDateRange(taken.AddDate(0, 0, -1), taken))
+	if err != nil {
+		return domain.PhotoLight{}, err
+	}
+	phase, period := domain.LightPhaseAt(days, taken, pos.Elevation)
+
+	return domain.PhotoLight{
+		File:      filepath.Base(path),
+		Camera:    camera,
+		Location:  loc,
+		Taken:     taken,
+		Elevation: pos.Elevation,
+		Azimuth:   pos.Azimuth,
+		Phase:     phase,
+		Period:    period,
+	}, nil
+}
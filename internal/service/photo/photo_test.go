@@ -0,0 +1,198 @@
+package photo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// TIFF field types used by the test photos.
+const (
+	typeByte     = 1
+	typeASCII    = 2
+	typeLong     = 4
+	typeRational = 5
+)
+
+// tiffEntry is one EXIF tag of a test photo.
+type tiffEntry struct {
+	tag, typ uint16
+	count    uint32
+	data     []byte
+}
+
+func ascii(s string) tiffEntry {
+	return tiffEntry{typ: typeASCII, count: uint32(len(s) + 1), data: append([]byte(s), 0)}
+}
+
+func rationals(values ...[2]uint32) tiffEntry {
+	var data []byte
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, v[0])
+		data = binary.LittleEndian.AppendUint32(data, v[1])
+	}
+	return tiffEntry{typ: typeRational, count: uint32(len(values)), data: data}
+}
+
+func tagged(tag uint16, e tiffEntry) tiffEntry {
+	e.tag = tag
+	return e
+}
+
+// testJPEG builds a minimal JPEG with an EXIF segment holding IFD0 (the
+// camera) and the Exif and GPS sub-IFDs; a nil gps leaves the GPS IFD out.
+func testJPEG(ifd0, exifIFD, gps []tiffEntry) []byte {
+	ifds := [][]tiffEntry{ifd0, exifIFD}
+	if gps != nil {
+		ifds = append(ifds, gps)
+	}
+	// IFD0 points to the others
+	ifds[0] = append(ifds[0], tiffEntry{tag: 0x8769, typ: typeLong, count: 1})
+	if gps != nil {
+		ifds[0] = append(ifds[0], tiffEntry{tag: 0x8825, typ: typeLong, count: 1})
+	}
+
+	offsets := make([]uint32, len(ifds))
+	next := uint32(8)
+	for i, entries := range ifds {
+		offsets[i] = next
+		next += uint32(2 + 12*len(entries) + 4)
+	}
+	ifds[0][len(ifd0)].data = binary.LittleEndian.AppendUint32(nil, offsets[1])
+	if gps != nil {
+		ifds[0][len(ifd0)+1].data = binary.LittleEndian.AppendUint32(nil, offsets[2])
+	}
+
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	var extra []byte
+	for _, entries := range ifds {
+		tiff = binary.LittleEndian.AppendUint16(tiff, uint16(len(entries)))
+		for _, e := range entries {
+			tiff = binary.LittleEndian.AppendUint16(tiff, e.tag)
+			tiff = binary.LittleEndian.AppendUint16(tiff, e.typ)
+			tiff = binary.LittleEndian.AppendUint32(tiff, e.count)
+			if len(e.data) <= 4 {
+				tiff = append(tiff, append(e.data, make([]byte, 4-len(e.data))...)...)
+			} else {
+				tiff = binary.LittleEndian.AppendUint32(tiff, next+uint32(len(extra)))
+				extra = append(extra, e.data...)
+			}
+		}
+		tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	}
+	tiff = append(tiff, extra...)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	jpeg = binary.BigEndian.AppendUint16(jpeg, uint16(len(segment)+2))
+	jpeg = append(jpeg, segment...)
+	return append(jpeg, 0xFF, 0xD9)
+}
+
+// parisGPS is the GPS IFD of a photo at the Eiffel Tower, 35 m above sea
+// level, with a GPS time stamp if date isn't empty.
+func parisGPS(date string, hour, minute uint32) []tiffEntry {
+	gps := []tiffEntry{
+		tagged(0x1, ascii("N")),
+		tagged(0x2, rationals([2]uint32{48, 1}, [2]uint32{51, 1}, [2]uint32{3024, 100})),
+		tagged(0x3, ascii("E")),
+		tagged(0x4, rationals([2]uint32{2, 1}, [2]uint32{17, 1}, [2]uint32{4020, 100})),
+		{tag: 0x5, typ: typeByte, count: 1, data: []byte{0}},
+		tagged(0x6, rationals([2]uint32{35, 1})),
+	}
+	if date != "" {
+		gps = append(gps,
+			tagged(0x7, rationals([2]uint32{hour, 1}, [2]uint32{minute, 1}, [2]uint32{0, 1})),
+			tagged(0x1D, ascii(date)))
+	}
+	return gps
+}
+
+func TestReadMetadata(t *testing.T) {
+	camera := []tiffEntry{tagged(0x10F, ascii("Canon")), tagged(0x110, ascii("Canon EOS R6"))}
+	original := []tiffEntry{tagged(0x9003, ascii("2025:06:21 21:24:00"))}
+
+	m, err := readMetadata(bytes.NewReader(testJPEG(camera, original, parisGPS("2025:06:21", 19, 24))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.latitude < 48.85 || m.latitude > 48.86 || m.longitude < 2.29 || m.longitude > 2.30 || m.altitude != 35 {
+		t.Errorf("position %f, %f at %g m, want the Eiffel Tower at 35 m", m.latitude, m.longitude, m.altitude)
+	}
+	if want := time.Date(2025, 6, 21, 19, 24, 0, 0, time.UTC); !m.utc.Equal(want) {
+		t.Errorf("GPS time %v, want %v", m.utc, want)
+	}
+	if m.local != "2025:06:21 21:24:00" || m.camera != "Canon EOS R6" {
+		t.Errorf("camera time %q and camera %q", m.local, m.camera)
+	}
+
+	nikon := []tiffEntry{tagged(0x10F, ascii("NIKON CORPORATION")), tagged(0x110, ascii("Z 6"))}
+	if m, err := readMetadata(bytes.NewReader(testJPEG(nikon, original, parisGPS("", 0, 0)))); err != nil {
+		t.Fatal(err)
+	} else if !m.utc.IsZero() || m.camera != "NIKON CORPORATION Z 6" {
+		t.Errorf("without a GPS time: GPS time %v, camera %q", m.utc, m.camera)
+	}
+
+	if _, err := readMetadata(bytes.NewReader(testJPEG(camera, original, nil))); !errors.Is(err, ErrNoPosition) {
+		t.Errorf("without GPS: err = %v, want ErrNoPosition", err)
+	}
+	if _, err := readMetadata(bytes.NewReader(testJPEG(camera, nil, parisGPS("", 0, 0)))); err == nil {
+		t.Error("photo without a capture time was read")
+	}
+	if _, err := readMetadata(bytes.NewReader([]byte("not a photo"))); err == nil {
+		t.Error("file without EXIF data was read")
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	settings := domain.DefaultSettings()
+	dir := t.TempDir()
+	write := func(name string, content []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// 21:24 in Paris on midsummer, half an hour before sunset; the GPS time
+	// wins over a camera clock left an hour behind
+	path := write("sunset.jpg", testJPEG(nil, []tiffEntry{tagged(0x9003, ascii("2025:06:21 20:24:00"))},
+		parisGPS("2025:06:21", 19, 24)))
+	light, err := Analyze(path, settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if light.File != "sunset.jpg" || light.Location.Timezone != "Europe/Paris" {
+		t.Errorf("file %q in %q", light.File, light.Location.Timezone)
+	}
+	if got := light.Taken.Format("15:04 MST"); got != "21:24 CEST" {
+		t.Errorf("taken at %s, want the GPS time 21:24 CEST", got)
+	}
+	if light.Phase != "Evening golden hour" || !light.Period.IsValid() {
+		t.Errorf("phase %q, want the evening golden hour", light.Phase)
+	}
+	if light.Elevation < 0 || light.Elevation > 6 || light.Azimuth < 290 || light.Azimuth > 310 {
+		t.Errorf("sun at %.1f°, azimuth %.1f°; want low in the north-west", light.Elevation, light.Azimuth)
+	}
+
+	// Without a GPS time the camera time is local to Paris
+	path = write("noon.jpg", testJPEG(nil, []tiffEntry{tagged(0x9003, ascii("2025:06:21 14:00:00"))},
+		parisGPS("", 0, 0)))
+	if light, err := Analyze(path, settings); err != nil {
+		t.Fatal(err)
+	} else if got := light.Taken.Format("15:04 MST"); got != "14:00 CEST" || light.Phase != domain.PhaseDaylight {
+		t.Errorf("taken at %s in %q, want 14:00 CEST in daylight", got, light.Phase)
+	}
+
+	if _, err := Analyze(filepath.Join(dir, "missing.jpg"), settings); err == nil {
+		t.Error("missing file was analyzed")
+	}
+}
@@ -0,0 +1,152 @@
+package solar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Sun Alignment Search
+// =============================================================================
+
+// alignmentPrecision is the time resolution of the alignment search.
+//
+// The sun's azimuth near the horizon changes by roughly 0.1-0.2° per minute,
+// so a precision of a few seconds is far finer than any photographer needs.
+const alignmentPrecision = 5 * time.Second
+
+// FindAlignments searches for moments when the sun lines up with a landmark.
+//
+// The target bearing is the great-circle bearing from camera to subject.
+// For each day in the search range, the morning and evening golden hour
+// windows are checked: if the sun's azimuth crosses the target bearing
+// inside a window, the exact crossing time is located by bisection and
+// reported as a domain.SunAlignment.
+//
+// Restricting the search to golden hour keeps results to "low sun" events,
+// where the sun sits on or just above the horizon behind the subject. The
+// window boundaries follow the calculator's current golden hour elevation.
+//
+// Parameters:
+//   - camera: Where the photographer stands (timezone is used for results)
+//   - subject: The landmark the sun should line up with
+//   - start: First day to search (time portion is ignored)
+//   - days: Number of consecutive days to search (e.g., 365 for a year)
+//
+// Returns:
+//   - []domain.SunAlignment: Alignments in chronological order (may be empty)
+//   - error: Non-nil if a daily calculation fails
+//
+// Alignments usually appear in short runs of consecutive days, twice a year,
+// because the sunrise/sunset azimuth sweeps back and forth between the
+// solstices. Bearings outside that sweep (e.g., due north at mid-latitudes)
+// never align and return an empty slice.
+//
+// Example:
+//
+//	calc := solar.New(settings)
+//	alignments, err := calc.FindAlignments(camera, subject, time.Now(), 365)
+func (c *Calculator) FindAlignments(camera, subject domain.Location, start time.Time, days int) ([]domain.SunAlignment, error) {
+	bearing := camera.BearingTo(subject)
+	sampaLoc := toSampaLocation(camera)
+
+	var alignments []domain.SunAlignment
+	for i := 0; i < days; i++ {
+		sunTimes, err := c.Calculate(camera, start.AddDate(0, 0, i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate sun times: %w", err)
+		}
+
+		// Morning window first so results stay in chronological order
+		windows := []struct {
+			tr     domain.TimeRange
+			rising bool
+		}{
+			{sunTimes.GoldenMorning, true},
+			{sunTimes.GoldenEvening, false},
+		}
+
+		for _, w := range windows {
+			if !w.tr.IsValid() {
+				continue
+			}
+			alignment, found, err := findCrossing(sampaLoc, w.tr, bearing)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				alignment.Rising = w.rising
+				alignments = append(alignments, alignment)
+			}
+		}
+	}
+
+	return alignments, nil
+}
+
+// findCrossing locates the time within a window when the sun's azimuth equals bearing.
+//
+// The sun's azimuth changes monotonically within a golden hour window, so a
+// crossing exists exactly when the azimuth offset from the bearing changes
+// sign between the window's start and end. The crossing is then narrowed
+// down by bisection to alignmentPrecision.
+//
+// Offsets larger than 90° at either end are rejected: a sign change there
+// means the azimuth wrapped around the opposite direction, not that it
+// passed through the bearing.
+//
+// Returns the alignment (without Rising set), whether one was found, and
+// any error from the position calculation.
+func findCrossing(loc sampa.Location, window domain.TimeRange, bearing float64) (domain.SunAlignment, bool, error) {
+	offsetAt := func(t time.Time) (float64, sampa.SunPosition, error) {
+		pos, err := sampa.GetSunPosition(t, loc, nil)
+		if err != nil {
+			return 0, pos, fmt.Errorf("failed to get sun position: %w", err)
+		}
+		return domain.AngleDifference(pos.TopocentricAzimuthAngle, bearing), pos, nil
+	}
+
+	lo, hi := window.Start, window.End
+	loOffset, _, err := offsetAt(lo)
+	if err != nil {
+		return domain.SunAlignment{}, false, err
+	}
+	hiOffset, _, err := offsetAt(hi)
+	if err != nil {
+		return domain.SunAlignment{}, false, err
+	}
+
+	// No sign change means the sun never passes the bearing in this window
+	if (loOffset > 0) == (hiOffset > 0) || math.Abs(loOffset) > 90 || math.Abs(hiOffset) > 90 {
+		return domain.SunAlignment{}, false, nil
+	}
+
+	// Bisect until the window is narrower than the required precision
+	for hi.Sub(lo) > alignmentPrecision {
+		mid := lo.Add(hi.Sub(lo) / 2)
+		midOffset, _, err := offsetAt(mid)
+		if err != nil {
+			return domain.SunAlignment{}, false, err
+		}
+		if (midOffset > 0) == (loOffset > 0) {
+			lo, loOffset = mid, midOffset
+		} else {
+			hi = mid
+		}
+	}
+
+	_, pos, err := offsetAt(lo)
+	if err != nil {
+		return domain.SunAlignment{}, false, err
+	}
+
+	return domain.SunAlignment{
+		Time:      lo.Round(time.Second),
+		Azimuth:   pos.TopocentricAzimuthAngle,
+		Elevation: pos.TopocentricElevationAngle,
+	}, true, nil
+}
@@ -0,0 +1,79 @@
+package solar
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestFindAlignments(t *testing.T) {
+	settings := domain.DefaultSettings()
+	calc := New(settings)
+	camera := domain.Location{Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		bearing     float64
+		days        int
+		wantRising  int // minimum number of morning alignments
+		wantSetting int // minimum number of evening alignments
+		wantNone    bool
+	}{
+		{name: "due north never aligns", bearing: 0, days: 365, wantNone: true},
+		{name: "due south never aligns", bearing: 180, days: 365, wantNone: true},
+		{name: "due west near the equinoxes", bearing: 270, days: 365, wantSetting: 1},
+		{name: "due east near the equinoxes", bearing: 90, days: 365, wantRising: 1},
+		{name: "summer sunset in the north-west", bearing: 305, days: 365, wantSetting: 2},
+		{name: "winter sunrise in the south-east", bearing: 130, days: 365, wantRising: 2},
+		{name: "no days searched", bearing: 270, days: 0, wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject := camera.Destination(tt.bearing, 2000)
+			bearing := camera.BearingTo(subject)
+
+			alignments, err := calc.FindAlignments(camera, subject, start, tt.days)
+			if err != nil {
+				t.Fatalf("FindAlignments: %v", err)
+			}
+			if tt.wantNone {
+				if len(alignments) != 0 {
+					t.Fatalf("got %d alignments, want none (first %+v)", len(alignments), alignments[0])
+				}
+				return
+			}
+
+			rising, setting := 0, 0
+			for i, a := range alignments {
+				if i > 0 && !a.Time.After(alignments[i-1].Time) {
+					t.Errorf("alignment %d at %v is not after %v", i, a.Time, alignments[i-1].Time)
+				}
+				if diff := math.Abs(domain.AngleDifference(a.Azimuth, bearing)); diff > 0.1 {
+					t.Errorf("alignment at %v: azimuth %.3f° is %.3f° off the bearing", a.Time, a.Azimuth, diff)
+				}
+				if a.Elevation > settings.GoldenHourElevation+0.1 {
+					t.Errorf("alignment at %v: elevation %.2f° above golden hour", a.Time, a.Elevation)
+				}
+				if a.Rising != (a.Time.Hour() < 12) {
+					t.Errorf("alignment at %v: Rising = %v", a.Time, a.Rising)
+				}
+				if a.Time.Location().String() != camera.Timezone {
+					t.Errorf("alignment at %v not in the camera's timezone", a.Time)
+				}
+				if a.Rising {
+					rising++
+				} else {
+					setting++
+				}
+			}
+			if rising < tt.wantRising || setting < tt.wantSetting {
+				t.Errorf("got %d rising / %d setting alignments, want at least %d / %d",
+					rising, setting, tt.wantRising, tt.wantSetting)
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package solar
+
+import (
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCalculateBatch(t *testing.T) {
+	calc := New(domain.DefaultSettings())
+	date := time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)
+	locations := []domain.Location{
+		{Name: "London", Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"},
+		{Name: "Sydney", Latitude: -33.8688, Longitude: 151.2093, Timezone: "Australia/Sydney"},
+	}
+
+	results, err := calc.CalculateBatch(locations, date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != len(locations) {
+		t.Fatalf("got %d results, want %d", len(results), len(locations))
+	}
+	for i, loc := range locations {
+		want, err := calc.Calculate(loc, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !results[i].GoldenEvening.Start.Equal(want.GoldenEvening.Start) {
+			t.Errorf("%s: golden hour starts %v, want %v",
+				loc.Name, results[i].GoldenEvening.Start, want.GoldenEvening.Start)
+		}
+	}
+
+	if results, err := calc.CalculateBatch(nil, date); err != nil || len(results) != 0 {
+		t.Errorf("empty batch = %v, %v; want no results", results, err)
+	}
+}
+
+func TestCalculateRange(t *testing.T) {
+	calc := New(domain.DefaultSettings())
+	london := domain.Location{Name: "London", Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	dates := domain.NewDateRange(time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 22, 0, 0, 0, 0, time.UTC))
+
+	days, err := calc.CalculateRange(london, dates)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 3 {
+		t.Fatalf("got %d days, want 3", len(days))
+	}
+	for i, date := range dates.Dates() {
+		want, err := calc.Calculate(london, date)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !days[i].Sunset.Equal(want.Sunset) {
+			t.Errorf("day %d: sunset %v, want %v", i, days[i].Sunset, want.Sunset)
+		}
+	}
+}
+
+func TestCalculateMany(t *testing.T) {
+	// Run a pool even on a machine with one CPU
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+	ClearSunTimesCache()
+
+	calc := New(domain.DefaultSettings())
+	tromso := domain.Location{Name: "Tromsø", Latitude: 69.6492, Longitude: 18.9553, Timezone: "Europe/Oslo"}
+	year := domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+
+	days, err := calc.CalculateMany(tromso, year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ClearSunTimesCache()
+	want, err := calc.CalculateRange(tromso, year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i := range want {
+		if !days[i].Date.Equal(want[i].Date) || !days[i].Sunrise.Equal(want[i].Sunrise) ||
+			!days[i].GoldenEvening.End.Equal(want[i].GoldenEvening.End) {
+			t.Errorf("day %d = %s sunrise %v, want %s sunrise %v", i,
+				days[i].Date.Format(time.DateOnly), days[i].Sunrise, want[i].Date.Format(time.DateOnly), want[i].Sunrise)
+		}
+	}
+
+	// Short ranges are calculated in turn, with the same result
+	week := domain.NewDateRange(year.Start, year.Start.AddDate(0, 0, 6))
+	if days, err := calc.CalculateMany(tromso, week); err != nil || len(days) != 7 {
+		t.Errorf("week = %d days, %v; want 7", len(days), err)
+	}
+}
+
+// benchmarkYear calculates a year of London's days with calculate, without
+// the cache.
+func benchmarkYear(b *testing.B, calculate func(*Calculator, domain.Location, domain.DateRange) ([]domain.SunTimes, error)) {
+	calc := New(domain.DefaultSettings())
+	london := domain.Location{Name: "London", Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	year := domain.NewDateRange(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 12, 31, 0, 0, 0, 0, time.UTC))
+	for b.Loop() {
+		ClearSunTimesCache()
+		if _, err := calculate(calc, london, year); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCalculateRangeYear(b *testing.B) {
+	benchmarkYear(b, (*Calculator).CalculateRange)
+}
+
+func BenchmarkCalculateManyYear(b *testing.B) {
+	benchmarkYear(b, (*Calculator).CalculateMany)
+}
@@ -0,0 +1,192 @@
+package solar
+
+import (
+	"container/list"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Sun Times Cache
+// =============================================================================
+
+// cacheMaxEntries is how many days of sun times the cache keeps: a year's
+// date range with room for the month calendar, the favorites and going
+// back and forth between places. An entry is a few hundred bytes.
+const cacheMaxEntries = 2048
+
+// cacheKey identifies a calculation: everything Calculate's result depends
+// on, except the location's name and address, which are copied in.
+type cacheKey struct {
+	// latitude, longitude, elevation and timezone place the observer.
+	latitude  float64
+	longitude float64
+	elevation float64
+	timezone  string
+
+	// day is the calendar date, "2006-01-02".
+	day string
+
+	// golden, blueStart and blueEnd are the elevation angles, and events
+	// the custom events (see customEventsKey).
+	golden    float64
+	blueStart float64
+	blueEnd   float64
+	events    string
+}
+
+// newCacheKey returns the key of a calculation with the settings.
+func newCacheKey(settings domain.Settings, loc domain.Location, date time.Time) cacheKey {
+	return cacheKey{
+		latitude:  loc.Latitude,
+		longitude: loc.Longitude,
+		elevation: loc.Elevation,
+		timezone:  loc.Timezone,
+		day:       date.Format(time.DateOnly),
+		golden:    settings.GoldenHourElevation,
+		blueStart: settings.BlueHourStart,
+		blueEnd:   settings.BlueHourEnd,
+		events:    customEventsKey(settings.CustomEvents),
+	}
+}
+
+// customEventsKey joins the custom events' names and elevations, in
+// order (the order of SunTimes.Custom).
+func customEventsKey(events []domain.CustomEvent) string {
+	var b strings.Builder
+	for _, e := range events {
+		b.WriteString(e.Name)
+		b.WriteByte(0)
+		b.WriteString(strconv.FormatFloat(e.Elevation, 'g', -1, 64))
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// cacheEntry is a cached result.
+type cacheEntry struct {
+	key      cacheKey
+	sunTimes domain.SunTimes
+}
+
+// CacheStats describes the sun times cache, for debugging.
+type CacheStats struct {
+	// Entries is how many days are cached, out of Capacity.
+	Entries  int
+	Capacity int
+
+	// Hits are calculations the cache answered, Misses those it couldn't,
+	// and Evictions the days dropped to make room.
+	Hits      int
+	Misses    int
+	Evictions int
+}
+
+// sunTimesCache is a least recently used cache of calculated days, shared
+// by all calculators of the process (including the private ones of
+// background goroutines), so going back to a date or place doesn't redo
+// the same calculation.
+//
+// Failed calculations aren't cached. Hits and misses are also counted in
+// the stats package (stats.CacheSunTimes).
+type sunTimesCache struct {
+	// mu guards all fields below; calculators run on several goroutines.
+	mu sync.Mutex
+
+	// maxEntries is the capacity; the least recently used entry is dropped
+	// when it is exceeded.
+	maxEntries int
+
+	// order holds the entries (*cacheEntry), most recently used first.
+	order *list.List
+
+	// index finds the element of a key in order.
+	index map[cacheKey]*list.Element
+
+	hits, misses, evictions int
+}
+
+// cache is the process's sun times cache.
+var cache = newSunTimesCache(cacheMaxEntries)
+
+// newSunTimesCache creates an empty cache.
+func newSunTimesCache(maxEntries int) *sunTimesCache {
+	return &sunTimesCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		index:      make(map[cacheKey]*list.Element),
+	}
+}
+
+// get returns the sun times of a key and marks them as recently used.
+func (c *sunTimesCache) get(key cacheKey) (domain.SunTimes, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.index[key]
+	if !ok {
+		c.misses++
+		return domain.SunTimes{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(elem)
+	sunTimes := elem.Value.(*cacheEntry).sunTimes
+	sunTimes.Custom = slices.Clone(sunTimes.Custom)
+	return sunTimes, true
+}
+
+// put adds or replaces the sun times of a key and drops the least recently
+// used entries beyond the capacity.
+func (c *sunTimesCache) put(key cacheKey, sunTimes domain.SunTimes) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	sunTimes.Custom = slices.Clone(sunTimes.Custom)
+	if elem, ok := c.index[key]; ok {
+		c.order.Remove(elem)
+	}
+	c.index[key] = c.order.PushFront(&cacheEntry{key: key, sunTimes: sunTimes})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.index, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// stats returns the cache's counts.
+func (c *sunTimesCache) stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Entries:   c.order.Len(),
+		Capacity:  c.maxEntries,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
+
+// clear empties the cache and resets its counts.
+func (c *sunTimesCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.order.Init()
+	clear(c.index)
+	c.hits, c.misses, c.evictions = 0, 0, 0
+}
+
+// SunTimesCacheStats returns the counts of the sun times cache since
+// launch (or ClearSunTimesCache), for debugging.
+func SunTimesCacheStats() CacheStats {
+	return cache.stats()
+}
+
+// ClearSunTimesCache empties the sun times cache, e.g., to time
+// calculations without it.
+func ClearSunTimesCache() {
+	cache.clear()
+}
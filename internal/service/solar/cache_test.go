@@ -0,0 +1,84 @@
+package solar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCalculateCaches(t *testing.T) {
+	ClearSunTimesCache()
+	settings := domain.DefaultSettings()
+	settings.CustomEvents = []domain.CustomEvent{{Name: "Low sun", Elevation: 10}}
+	calc := New(settings)
+	london := domain.Location{Name: "London", Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	date := time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC)
+
+	first, err := calc.Calculate(london, date)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	renamed := london
+	renamed.Name = "Home"
+	second, err := calc.Calculate(renamed, date)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if !second.Sunrise.Equal(first.Sunrise) || !second.GoldenEvening.End.Equal(first.GoldenEvening.End) {
+		t.Errorf("cached sun times differ: %v, want %v", second, first)
+	}
+	if second.Location.Name != "Home" {
+		t.Errorf("cached Location.Name = %q, want the caller's %q", second.Location.Name, "Home")
+	}
+	if got := SunTimesCacheStats(); got.Hits != 1 || got.Misses != 1 || got.Entries != 1 {
+		t.Errorf("stats = %+v, want 1 hit, 1 miss, 1 entry", got)
+	}
+
+	// The caller's copy can't change the cached custom times
+	second.Custom[0].Morning = time.Time{}
+	third, _ := calc.Calculate(london, date)
+	if third.Custom[0].Morning.IsZero() {
+		t.Error("changing a result changed the cache")
+	}
+
+	// Other angles are another calculation
+	settings.GoldenHourElevation += 2
+	calc.UpdateSettings(settings)
+	steeper, err := calc.Calculate(london, date)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if steeper.GoldenEvening.Start.Equal(first.GoldenEvening.Start) {
+		t.Error("new golden hour angle answered from the cache")
+	}
+	if got := SunTimesCacheStats(); got.Entries != 2 {
+		t.Errorf("entries = %d, want 2", got.Entries)
+	}
+}
+
+func TestSunTimesCacheEvicts(t *testing.T) {
+	c := newSunTimesCache(2)
+	settings := domain.DefaultSettings()
+	loc := domain.Location{Latitude: 1, Longitude: 2, Timezone: "UTC"}
+	day := func(d int) cacheKey {
+		return newCacheKey(settings, loc, time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC))
+	}
+
+	c.put(day(1), domain.SunTimes{})
+	c.put(day(2), domain.SunTimes{})
+	c.get(day(1)) // day 2 is now the least recently used
+	c.put(day(3), domain.SunTimes{})
+
+	if _, ok := c.get(day(2)); ok {
+		t.Error("least recently used day not evicted")
+	}
+	for _, d := range []int{1, 3} {
+		if _, ok := c.get(day(d)); !ok {
+			t.Errorf("day %d evicted", d)
+		}
+	}
+	if got := c.stats(); got.Entries != 2 || got.Evictions != 1 || got.Capacity != 2 {
+		t.Errorf("stats = %+v, want 2 entries, 1 eviction", got)
+	}
+}
@@ -36,8 +36,10 @@
 // # Thread Safety
 //
 // The Calculator is NOT thread-safe. If used from multiple goroutines, external
-// synchronization is required. In the current app architecture, all calculations
-// are performed on the main thread after location/date/settings changes.
+// synchronization is required. In the current app architecture, the App's
+// calculator is used on the main thread after location/date/settings changes,
+// and background goroutines create their own. The results cache all calculators
+// share (see sunTimesCache) is safe to use from any goroutine.
 //
 // # Dependencies
 //
@@ -53,6 +55,7 @@ import (
 
 	"github.com/hablullah/go-sampa"
 	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
 )
 
 // =============================================================================
@@ -168,7 +171,8 @@ func extractTimeRange(events map[string]sampa.SunPosition, startKey, endKey stri
 // The calculation process:
 //  1. Load the timezone for accurate local time conversion
 //  2. Normalize the date to midnight in the location's timezone
-//  3. Define 8 custom sun events for golden/blue hour boundaries
+//  3. Define 8 custom sun events for golden/blue hour boundaries, and two
+//     for each of the user's custom events (see domain.CustomEvent)
 //  4. Call go-sampa to calculate when the sun reaches each elevation
 //  5. Extract and combine results into domain.SunTimes
 //
@@ -183,7 +187,31 @@ func extractTimeRange(events map[string]sampa.SunPosition, startKey, endKey stri
 // Errors can occur if the timezone is invalid and can't be loaded, or if
 // the go-sampa library encounters an internal error. In practice, these
 // errors are rare with validated input.
+//
+// Results are cached (see sunTimesCache): a day already calculated with
+// the same place and angles is answered from the cache, with loc's name
+// and address. Every calculation and cache lookup is counted for the
+// statistics dialog (see stats).
 func (c *Calculator) Calculate(loc domain.Location, date time.Time) (domain.SunTimes, error) {
+	key := newCacheKey(c.settings, loc, date)
+	sunTimes, hit := cache.get(key)
+	stats.CacheLookup(stats.CacheSunTimes, hit)
+	if hit {
+		sunTimes.Location = loc
+		return sunTimes, nil
+	}
+
+	start := time.Now()
+	sunTimes, err := c.calculate(loc, date)
+	stats.Record(stats.OpSunTimes, time.Since(start), err)
+	if err == nil {
+		cache.put(key, sunTimes)
+	}
+	return sunTimes, err
+}
+
+// calculate does the work of Calculate.
+func (c *Calculator) calculate(loc domain.Location, date time.Time) (domain.SunTimes, error) {
 	// Load the timezone for the location to ensure all times are in local time.
 	// This is important because users expect to see times in their local timezone.
 	tz, err := time.LoadLocation(loc.Timezone)
@@ -203,7 +231,7 @@ func (c *Calculator) Calculate(loc domain.Location, date time.Time) (domain.SunT
 
 	// Create custom events for all golden/blue hour boundaries.
 	// These are defined based on the user's configured elevation angles.
-	customEvents := c.createCustomEvents()
+	customEvents := append(c.createCustomEvents(), c.createUserEvents()...)
 
 	// Calculate all sun events using the go-sampa library.
 	// This returns standard events (sunrise, sunset, transit) plus our custom events.
@@ -227,6 +255,18 @@ func (c *Calculator) Calculate(loc domain.Location, date time.Time) (domain.SunT
 		BlueEvening:   extractTimeRange(events.Others, "BlueEveningStart", "BlueEveningEnd"),
 	}
 
+	// The user's custom events, when the sun passes their elevations
+	for i, event := range c.settings.CustomEvents {
+		custom := domain.CustomTime{Event: event}
+		if pos, ok := events.Others[userEventName(i, true)]; ok {
+			custom.Morning = pos.DateTime
+		}
+		if pos, ok := events.Others[userEventName(i, false)]; ok {
+			custom.Evening = pos.DateTime
+		}
+		sunTimes.Custom = append(sunTimes.Custom, custom)
+	}
+
 	return sunTimes, nil
 }
 
@@ -351,6 +391,34 @@ func (c *Calculator) createCustomEvents() []sampa.CustomSunEvent {
 	}
 }
 
+// createUserEvents creates two sun events (morning and evening) for each of
+// the user's custom events (see domain.CustomEvent), named by userEventName.
+func (c *Calculator) createUserEvents() []sampa.CustomSunEvent {
+	var events []sampa.CustomSunEvent
+	for i, event := range c.settings.CustomEvents {
+		elevation := event.Elevation
+		for _, morning := range []bool{true, false} {
+			events = append(events, sampa.CustomSunEvent{
+				Name:          userEventName(i, morning),
+				BeforeTransit: morning,
+				Elevation: func(_ sampa.SunPosition) float64 {
+					return elevation
+				},
+			})
+		}
+	}
+	return events
+}
+
+// userEventName names the sampa event of the i-th custom event, e.g.,
+// "User0Morning". The index keeps events with the same name apart.
+func userEventName(i int, morning bool) string {
+	if morning {
+		return fmt.Sprintf("User%dMorning", i)
+	}
+	return fmt.Sprintf("User%dEvening", i)
+}
+
 // =============================================================================
 // Real-Time Sun Position
 // =============================================================================
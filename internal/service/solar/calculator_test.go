@@ -0,0 +1,47 @@
+package solar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestCalculateCustomEvents(t *testing.T) {
+	settings := domain.DefaultSettings()
+	settings.CustomEvents = []domain.CustomEvent{
+		{Name: "Astronomical dark", Elevation: -18},
+		{Name: "Hard light", Elevation: 15},
+	}
+	calc := New(settings)
+	loc := domain.Location{Name: "Cairo", Latitude: 30.0444, Longitude: 31.2357, Timezone: "Africa/Cairo"}
+	st, err := calc.Calculate(loc, time.Date(2025, 3, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(st.Custom) != 2 {
+		t.Fatalf("got %d custom times, want 2", len(st.Custom))
+	}
+	dark, hard := st.Custom[0], st.Custom[1]
+	if dark.Event.Name != "Astronomical dark" || hard.Event.Name != "Hard light" {
+		t.Errorf("custom times for %q and %q, want them in settings order", dark.Event.Name, hard.Event.Name)
+	}
+	// Dawn comes before sunrise, the sun reaches 15° after the golden hour
+	if !dark.Morning.Before(st.BlueMorning.Start) || !dark.Evening.After(st.BlueEvening.End) {
+		t.Errorf("astronomical dark at %v and %v, want outside the blue hours", dark.Morning, dark.Evening)
+	}
+	if !hard.Morning.After(st.GoldenMorning.End) || !hard.Evening.Before(st.GoldenEvening.Start) {
+		t.Errorf("hard light at %v and %v, want between the golden hours", hard.Morning, hard.Evening)
+	}
+
+	// Near the pole in June the sun never gets 18° below the horizon
+	tromso := domain.Location{Latitude: 69.6492, Longitude: 18.9553, Timezone: "Europe/Oslo"}
+	st, err = calc.Calculate(tromso, time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !st.Custom[0].Morning.IsZero() || !st.Custom[0].Evening.IsZero() {
+		t.Errorf("astronomical dark in Tromsø at %v and %v, want never", st.Custom[0].Morning, st.Custom[0].Evening)
+	}
+}
@@ -0,0 +1,87 @@
+package solar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Darkness
+// =============================================================================
+
+// Darkness computes the night of a date: from when the sun sinks below an
+// elevation in the evening to when it rises above it the next morning.
+//
+// Used for the aurora outlook (domain.AuroraDarkElevation) and other night
+// sky subjects. At polar latitudes the night may not come (the sun stays
+// above the elevation; the range is invalid) or may last all day (the sun
+// stays below it; the range runs from the date's solar noon to the next).
+//
+// Like HorizonEvents, this is a plain function without state, so it is
+// safe to call from any goroutine.
+//
+// Parameters:
+//   - loc: Observer location with timezone (an invalid timezone falls back to
+//     the system local timezone, as in Calculate)
+//   - date: The date whose evening starts the night (time portion is
+//     ignored)
+//   - elevation: The sun elevation in degrees, e.g.,
+//     domain.NauticalTwilightElevation
+//
+// Returns:
+//   - domain.TimeRange: The night; invalid if the sky doesn't get that dark
+//   - error: Non-nil if a position calculation fails
+func Darkness(loc domain.Location, date time.Time, elevation float64) (domain.TimeRange, error) {
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		tz = time.Local
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, tz)
+	sampaLoc := toSampaLocation(loc)
+	event := func(morning bool) sampa.CustomSunEvent {
+		return sampa.CustomSunEvent{
+			Name:          "Dark",
+			BeforeTransit: morning,
+			Elevation: func(_ sampa.SunPosition) float64 {
+				return elevation
+			},
+		}
+	}
+
+	evening, err := sampa.GetSunEvents(date, sampaLoc, nil, event(false))
+	if err != nil {
+		return domain.TimeRange{}, fmt.Errorf("failed to calculate dusk: %w", err)
+	}
+	morning, err := sampa.GetSunEvents(date.AddDate(0, 0, 1), sampaLoc, nil, event(true))
+	if err != nil {
+		return domain.TimeRange{}, fmt.Errorf("failed to calculate dawn: %w", err)
+	}
+
+	// Without a crossing the sun stays on one side of the elevation: the
+	// night runs on from (or up to) solar noon if even noon is dark
+	start, ok := nightEdge(evening, elevation)
+	if !ok {
+		return domain.TimeRange{}, nil
+	}
+	end, ok := nightEdge(morning, elevation)
+	if !ok {
+		return domain.TimeRange{}, nil
+	}
+	return domain.TimeRange{Start: start, End: end}, nil
+}
+
+// nightEdge returns when the sun crosses the elevation of Darkness's
+// event on a day, or the day's solar noon if the sun stays below it.
+// Returns false if the sun stays above it.
+func nightEdge(day sampa.SunEvents, elevation float64) (time.Time, bool) {
+	if t := day.Others["Dark"].DateTime; !t.IsZero() {
+		return t, true
+	}
+	if day.Transit.IsZero() || day.Transit.TopocentricElevationAngle >= elevation {
+		return time.Time{}, false
+	}
+	return day.Transit.DateTime, true
+}
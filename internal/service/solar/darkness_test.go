@@ -0,0 +1,40 @@
+package solar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestDarkness(t *testing.T) {
+	tromso := domain.Location{Latitude: 69.6492, Longitude: 18.9553, Timezone: "Europe/Oslo"}
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	// Equinox: dark from the evening to the early morning
+	night, err := Darkness(tromso, date(time.September, 21), domain.NauticalTwilightElevation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !night.IsValid() || night.Start.Day() != 21 || night.Start.Hour() < 20 ||
+		night.End.Day() != 22 || night.End.Hour() > 5 {
+		t.Errorf("equinox night %v to %v, want the evening to the next morning", night.Start, night.End)
+	}
+
+	// Midsummer: the midnight sun
+	if night, _ := Darkness(tromso, date(time.June, 21), domain.NauticalTwilightElevation); night.IsValid() {
+		t.Errorf("midsummer night %v to %v, want none", night.Start, night.End)
+	}
+
+	// Polar night in Svalbard: even noon is past civil twilight
+	svalbard := domain.Location{Latitude: 78.2232, Longitude: 15.6267, Timezone: "Arctic/Longyearbyen"}
+	night, err = Darkness(svalbard, date(time.December, 21), domain.CivilTwilightElevation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := night.Duration(); d < 23*time.Hour || d > 25*time.Hour {
+		t.Errorf("polar night %v to %v, want noon to noon", night.Start, night.End)
+	}
+}
@@ -0,0 +1,71 @@
+package solar
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Horizon Events
+// =============================================================================
+
+// HorizonEvents computes sunrise, sunset, moonrise and moonset for a date.
+//
+// Each event carries the azimuth where the body crosses the horizon, so the
+// camera view can tell whether it happens inside the field of view. Events
+// that don't occur on the date are omitted: the sun at polar latitudes, and
+// the moon on the one day or so each month when it skips rising or setting
+// (its daily cycle is about 50 minutes longer than a day). Moon events are
+// found around the moon's transit on the date, so a moonset may fall in the
+// early hours of the next day.
+//
+// Like Terminator, this is a plain function without state, so it is safe to
+// call from any goroutine.
+//
+// Parameters:
+//   - loc: Observer location with timezone (an invalid timezone falls back to
+//     the system local timezone, as in Calculate)
+//   - date: The date to compute events for (time portion is ignored)
+//
+// Returns:
+//   - []domain.HorizonEvent: Events in chronological order
+//   - error: Non-nil if a position calculation fails
+func HorizonEvents(loc domain.Location, date time.Time) ([]domain.HorizonEvent, error) {
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		tz = time.Local
+	}
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, tz)
+	sampaLoc := toSampaLocation(loc)
+
+	sun, err := sampa.GetSunEvents(date, sampaLoc, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate sun events: %w", err)
+	}
+	moon, err := sampa.GetMoonEvents(date, sampaLoc, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate moon events: %w", err)
+	}
+
+	candidates := []domain.HorizonEvent{
+		{Name: "Sunrise", Time: sun.Sunrise.DateTime, Azimuth: sun.Sunrise.TopocentricAzimuthAngle},
+		{Name: "Sunset", Time: sun.Sunset.DateTime, Azimuth: sun.Sunset.TopocentricAzimuthAngle},
+		{Name: "Moonrise", Moon: true, Time: moon.Moonrise.DateTime, Azimuth: moon.Moonrise.TopocentricAzimuthAngle},
+		{Name: "Moonset", Moon: true, Time: moon.Moonset.DateTime, Azimuth: moon.Moonset.TopocentricAzimuthAngle},
+	}
+
+	var events []domain.HorizonEvent
+	for _, e := range candidates {
+		if !e.Time.IsZero() {
+			events = append(events, e)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	return events, nil
+}
@@ -0,0 +1,94 @@
+package solar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Meteor Showers
+// =============================================================================
+
+// moonStep is how often the moon's position is sampled through the night.
+// Moonrise and moonset are found to within this step, plenty for planning
+// a night out.
+const moonStep = 10 * time.Minute
+
+// MeteorNights rates the peak nights of the major meteor showers (see
+// domain.MeteorShowers) that start on the dates of a range.
+//
+// For each peak night, the darkness (solar.Darkness with
+// domain.MeteorDarkElevation) is sampled every moonStep for the moon's
+// elevation and illumination, and the longest moonless stretch is kept.
+//
+// Like Darkness, this is a plain function without state, so it is safe to
+// call from any goroutine.
+//
+// Parameters:
+//   - loc: Observer location with timezone
+//   - dates: The evenings to look at, e.g., a month for the month calendar
+//
+// Returns the peak nights in date order (none for most ranges), and an
+// error if a position calculation fails.
+func MeteorNights(loc domain.Location, dates domain.DateRange) ([]domain.MeteorNight, error) {
+	var nights []domain.MeteorNight
+	for _, date := range dates.Dates() {
+		for _, shower := range domain.MeteorShowersOn(date) {
+			night, err := meteorNight(loc, date, shower)
+			if err != nil {
+				return nights, err
+			}
+			nights = append(nights, night)
+		}
+	}
+	return nights, nil
+}
+
+// meteorNight rates one shower's peak night.
+func meteorNight(loc domain.Location, date time.Time, shower domain.MeteorShower) (domain.MeteorNight, error) {
+	night := domain.MeteorNight{
+		Shower:          shower,
+		Date:            date,
+		RadiantAltitude: shower.RadiantAltitude(loc.Latitude),
+	}
+	dark, err := Darkness(loc, date, domain.MeteorDarkElevation)
+	if err != nil {
+		return night, err
+	}
+	night.Dark = dark
+	if !dark.IsValid() {
+		return night, nil
+	}
+
+	// Walk the night, growing a stretch while the moon is out of the way
+	sampaLoc := toSampaLocation(loc)
+	var run domain.TimeRange
+	for t := dark.Start; !t.After(dark.End); t = t.Add(moonStep) {
+		moon, err := sampa.GetMoonPosition(t, sampaLoc, nil)
+		if err != nil {
+			return night, fmt.Errorf("failed to calculate the moon's position: %w", err)
+		}
+		// Despite its name, go-sampa gives the illuminated fraction (0-1)
+		lit := moon.PercentIlluminated * 100
+		night.MoonIllumination = max(night.MoonIllumination, lit)
+
+		if moon.TopocentricElevationAngle < domain.HorizonElevation || lit < domain.MeteorMoonBright {
+			if run.Start.IsZero() {
+				run.Start = t
+			}
+			run.End = t.Add(moonStep)
+			if run.End.After(dark.End) {
+				run.End = dark.End
+			}
+			if run.Duration() > night.Moonless.Duration() {
+				night.Moonless = run
+			}
+		} else {
+			run = domain.TimeRange{}
+		}
+	}
+	return night, nil
+}
@@ -0,0 +1,44 @@
+package solar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestMeteorNights(t *testing.T) {
+	paris := domain.Location{Latitude: 48.8566, Longitude: 2.3522, Timezone: "Europe/Paris"}
+	august := func(year int) domain.DateRange {
+		first := time.Date(year, time.August, 1, 0, 0, 0, 0, time.UTC)
+		return domain.NewDateRange(first, first.AddDate(0, 1, -1))
+	}
+
+	// 2026: the Perseids peak at new moon
+	nights, err := MeteorNights(paris, august(2026))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nights) != 1 || nights[0].Shower.Name != "Perseids" || nights[0].Date.Day() != 12 {
+		t.Fatalf("nights = %+v, want the Perseids on August 12", nights)
+	}
+	if night := nights[0]; !night.Good() || night.Moonless != night.Dark || night.MoonIllumination > 5 {
+		t.Errorf("2026 Perseids: moonless %v to %v of %v to %v, moon %.0f%%, want the whole night",
+			night.Moonless.Start, night.Moonless.End, night.Dark.Start, night.Dark.End, night.MoonIllumination)
+	}
+
+	// 2025: a waning gibbous moon rises before it gets dark
+	nights, err = MeteorNights(paris, august(2025))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nights) != 1 || nights[0].Good() || nights[0].MoonIllumination < 75 {
+		t.Errorf("2025 Perseids = %+v, want a moonlit night", nights)
+	}
+
+	// No shower peaks in early September
+	first := time.Date(2026, time.September, 1, 0, 0, 0, 0, time.UTC)
+	if nights, _ := MeteorNights(paris, domain.NewDateRange(first, first.AddDate(0, 0, 9))); len(nights) != 0 {
+		t.Errorf("September nights = %+v, want none", nights)
+	}
+}
@@ -0,0 +1,113 @@
+package solar
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Sun Path
+// =============================================================================
+
+// SunPathSamples is the number of positions SunPath takes across a golden
+// hour. The sun's azimuth moves steadily over such a short time, so the
+// arc drawn through them looks smooth.
+const SunPathSamples = 24
+
+// SunPath samples the sun's position at evenly spaced moments of a time
+// range, including both ends.
+//
+// The map uses it for the evening golden hour, drawing the segment of the
+// horizon between the azimuths at its start and at sunset. Like
+// HorizonEvents, this is a plain function without state, so it is safe to
+// call from any goroutine.
+//
+// Parameters:
+//   - loc: Observer location
+//   - period: The time range to sample (e.g., SunTimes.GoldenEvening)
+//   - samples: Number of positions, at least 2
+//
+// Returns:
+//   - []domain.SunPathPoint: Positions in chronological order; nil if the
+//     range is not valid (e.g., no golden hour during polar summer)
+//   - error: Non-nil if a position calculation fails
+func SunPath(loc domain.Location, period domain.TimeRange, samples int) ([]domain.SunPathPoint, error) {
+	if !period.IsValid() {
+		return nil, nil
+	}
+	samples = max(samples, 2)
+	sampaLoc := toSampaLocation(loc)
+	step := period.Duration() / time.Duration(samples-1)
+
+	path := make([]domain.SunPathPoint, 0, samples)
+	for i := 0; i < samples; i++ {
+		t := period.Start.Add(step * time.Duration(i))
+		if i == samples-1 {
+			t = period.End
+		}
+		pos, err := sampa.GetSunPosition(t, sampaLoc, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sun position: %w", err)
+		}
+		path = append(path, domain.SunPathPoint{
+			Time:      t,
+			Azimuth:   pos.TopocentricAzimuthAngle,
+			Elevation: pos.TopocentricElevationAngle,
+		})
+	}
+	return path, nil
+}
+
+// PositionSeriesStep is the spacing of PositionSeries samples. The sun
+// moves only a few degrees in ten minutes, so positions in between are
+// interpolated closely enough for a readout (see
+// domain.InterpolateSunPosition).
+const PositionSeriesStep = 10 * time.Minute
+
+// PositionSeries samples the sun's position through a whole day, from
+// midnight to the next midnight in the location's timezone, every
+// PositionSeriesStep.
+//
+// The time slider interpolates between these samples while it is dragged
+// instead of calculating each position. Like SunPath, this is a plain
+// function without state, safe to call from any goroutine.
+//
+// Parameters:
+//   - loc: Observer location, with its timezone
+//   - date: The day to sample (time portion is ignored)
+//
+// Returns the positions in chronological order (including both
+// midnights), or an error if a position calculation fails.
+func PositionSeries(loc domain.Location, date time.Time) ([]domain.SunPathPoint, error) {
+	tz, err := time.LoadLocation(loc.Timezone)
+	if err != nil {
+		tz = time.Local // Same fallback as Calculate
+	}
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, tz)
+	day := domain.TimeRange{Start: start, End: start.AddDate(0, 0, 1)}
+	return SunPath(loc, day, int(day.Duration()/PositionSeriesStep)+1)
+}
+
+// SunPositionAt calculates the sun's position at one moment, such as when
+// a photo was taken (see photo.Analyze). Like SunPath, this is a plain
+// function without state, safe to call from any goroutine.
+//
+// Parameters:
+//   - loc: Observer location
+//   - t: The moment
+//
+// Returns the position, or an error if the calculation fails.
+func SunPositionAt(loc domain.Location, t time.Time) (domain.SunPathPoint, error) {
+	pos, err := sampa.GetSunPosition(t, toSampaLocation(loc), nil)
+	if err != nil {
+		return domain.SunPathPoint{}, fmt.Errorf("failed to get sun position: %w", err)
+	}
+	return domain.SunPathPoint{
+		Time:      t,
+		Azimuth:   pos.TopocentricAzimuthAngle,
+		Elevation: pos.TopocentricElevationAngle,
+	}, nil
+}
@@ -0,0 +1,77 @@
+package solar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSunPath(t *testing.T) {
+	settings := domain.DefaultSettings()
+	loc := domain.Location{Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	sunTimes, err := New(settings).Calculate(loc, time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := SunPath(loc, sunTimes.GoldenEvening, SunPathSamples)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(path) != SunPathSamples {
+		t.Fatalf("got %d points, want %d", len(path), SunPathSamples)
+	}
+	first, last := path[0], path[len(path)-1]
+	if !first.Time.Equal(sunTimes.GoldenEvening.Start) || !last.Time.Equal(sunTimes.GoldenEvening.End) {
+		t.Errorf("path runs %v to %v, want the golden hour %v to %v",
+			first.Time, last.Time, sunTimes.GoldenEvening.Start, sunTimes.GoldenEvening.End)
+	}
+
+	// Midsummer in London: the sun sets in the north-west, moving north
+	// and down as it goes
+	if first.Azimuth < 280 || last.Azimuth > 320 {
+		t.Errorf("azimuths %.1f° to %.1f°, want the north-west", first.Azimuth, last.Azimuth)
+	}
+	for i := 1; i < len(path); i++ {
+		if path[i].Azimuth <= path[i-1].Azimuth || path[i].Elevation >= path[i-1].Elevation {
+			t.Errorf("point %d (%+v) doesn't continue from %+v", i, path[i], path[i-1])
+		}
+	}
+	if first.Elevation < settings.GoldenHourElevation-0.5 || last.Elevation > 0.5 {
+		t.Errorf("elevations %.2f° to %.2f°, want golden hour elevation to the horizon", first.Elevation, last.Elevation)
+	}
+
+	if path, err := SunPath(loc, domain.TimeRange{}, SunPathSamples); path != nil || err != nil {
+		t.Errorf("SunPath of an invalid range = %v, %v; want nil", path, err)
+	}
+}
+
+func TestPositionSeries(t *testing.T) {
+	loc := domain.Location{Latitude: 51.5074, Longitude: -0.1278, Timezone: "Europe/London"}
+	series, err := PositionSeries(loc, time.Date(2025, 6, 21, 15, 30, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 24*6+1 {
+		t.Fatalf("got %d positions, want one every 10 minutes", len(series))
+	}
+	london, _ := time.LoadLocation("Europe/London")
+	if want := time.Date(2025, 6, 21, 0, 0, 0, 0, london); !series[0].Time.Equal(want) {
+		t.Errorf("series starts %v, want local midnight %v", series[0].Time, want)
+	}
+
+	// Interpolating between samples stays close to the exact position
+	at := time.Date(2025, 6, 21, 18, 25, 0, 0, london)
+	got, _ := domain.InterpolateSunPosition(series, at)
+	exact, err := SunPath(loc, domain.TimeRange{Start: at, End: at.Add(time.Second)}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := domain.AngleDifference(got.Azimuth, exact[0].Azimuth); d > 0.1 || d < -0.1 {
+		t.Errorf("interpolated azimuth %.2f°, exact %.2f°", got.Azimuth, exact[0].Azimuth)
+	}
+	if d := got.Elevation - exact[0].Elevation; d > 0.1 || d < -0.1 {
+		t.Errorf("interpolated elevation %.2f°, exact %.2f°", got.Elevation, exact[0].Elevation)
+	}
+}
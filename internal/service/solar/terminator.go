@@ -0,0 +1,169 @@
+package solar
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/hablullah/go-sampa"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Day/Night Terminator
+// =============================================================================
+
+// terminatorStep is the sampling step of band outlines, in degrees.
+//
+// A 2° step yields smooth curves at world zoom levels while keeping each
+// band under 200 points, small enough to send to the map frequently.
+const terminatorStep = 2.0
+
+// terminatorElevations lists the band thresholds, from the whole night side
+// down to full darkness.
+var terminatorElevations = []float64{
+	domain.HorizonElevation,
+	domain.CivilTwilightElevation,
+	domain.NauticalTwilightElevation,
+	domain.AstronomicalTwilightElevation,
+}
+
+// SubsolarPoint returns the location where the sun is directly overhead.
+//
+// The latitude equals the sun's declination; the longitude is where the
+// local hour angle is zero. Both come from go-sampa's geocentric position
+// computed for an observer at (0°, 0°), whose hour angle is the subsolar
+// point's offset from Greenwich.
+//
+// Returns the subsolar point (only coordinates are set), or an error if the
+// sun position can't be calculated.
+func SubsolarPoint(t time.Time) (domain.Location, error) {
+	pos, err := sampa.GetSunPosition(t.UTC(), sampa.Location{}, nil)
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to get sun position: %w", err)
+	}
+
+	return domain.Location{
+		Latitude:  pos.GeocentricDeclination,
+		Longitude: domain.AngleDifference(0, pos.ObserverLocalHourAngle),
+	}, nil
+}
+
+// Terminator computes the day/night terminator and twilight bands at a moment.
+//
+// One band is returned for each threshold (horizon, civil, nautical and
+// astronomical twilight), ordered from the largest (whole night side) to
+// the smallest (full night). See domain.TwilightBand for the polygon format.
+//
+// Unlike Calculator methods, this is a plain function without state, so it
+// is safe to call from any goroutine.
+//
+// # Geometry
+//
+// The points where the sun is below elevation h form a spherical cap around
+// the antisolar point with an angular radius of 90° + h. If the cap contains
+// a pole, its outline crosses every meridian exactly once and is traced by
+// longitude, then closed along the pole. Otherwise the outline is a closed
+// ring that is traced by bearing around the antisolar point.
+//
+// Parameters:
+//   - t: The moment to compute the terminator for (typically time.Now())
+//
+// Returns the bands, or an error if the sun position can't be calculated.
+func Terminator(t time.Time) ([]domain.TwilightBand, error) {
+	subsolar, err := SubsolarPoint(t)
+	if err != nil {
+		return nil, err
+	}
+
+	bands := make([]domain.TwilightBand, 0, len(terminatorElevations))
+	for _, elevation := range terminatorElevations {
+		bands = append(bands, domain.TwilightBand{
+			Elevation: elevation,
+			Boundary:  capBoundary(subsolar, elevation),
+		})
+	}
+	return bands, nil
+}
+
+// capBoundary traces the outline of the region where the sun is below elevation.
+func capBoundary(subsolar domain.Location, elevation float64) []domain.Location {
+	centerLat := -subsolar.Latitude
+	radius := 90 + elevation
+
+	switch {
+	case 90-centerLat < radius:
+		return poleCapBoundary(subsolar, elevation, 90)
+	case 90+centerLat < radius:
+		return poleCapBoundary(subsolar, elevation, -90)
+	default:
+		return ringBoundary(subsolar, radius)
+	}
+}
+
+// poleCapBoundary traces a cap containing a pole, one point per meridian.
+//
+// On a meridian with hour angle H, the sun's elevation h at latitude φ
+// satisfies sin h = sin δ·sin φ + cos δ·cos H·cos φ, which is written as
+// R·sin(φ + α) and solved for φ. Of the two solutions, the one in
+// [-90°, 90°] is the crossing point.
+func poleCapBoundary(subsolar domain.Location, elevation, poleLat float64) []domain.Location {
+	decl := toRadians(subsolar.Latitude)
+	sinH := math.Sin(toRadians(elevation))
+
+	var points []domain.Location
+	for lon := -180.0; lon <= 180; lon += terminatorStep {
+		hourAngle := toRadians(lon - subsolar.Longitude)
+		a := math.Sin(decl)
+		b := math.Cos(decl) * math.Cos(hourAngle)
+		r := math.Hypot(a, b)
+		alpha := math.Atan2(b, a)
+
+		ratio := math.Max(-1, math.Min(1, sinH/r))
+		lat := math.Asin(ratio) - alpha
+		if l := math.Remainder(lat, 2*math.Pi); math.Abs(l) > math.Pi/2 {
+			lat = math.Pi - math.Asin(ratio) - alpha
+		}
+		lat = math.Remainder(lat, 2*math.Pi)
+
+		points = append(points, domain.Location{Latitude: toDegrees(lat), Longitude: lon})
+	}
+
+	// Close the polygon along the pole
+	return append(points,
+		domain.Location{Latitude: poleLat, Longitude: 180},
+		domain.Location{Latitude: poleLat, Longitude: -180})
+}
+
+// ringBoundary traces a cap that doesn't contain a pole, by bearing.
+//
+// Longitudes are unwrapped so the ring stays continuous where it crosses
+// the antimeridian.
+func ringBoundary(subsolar domain.Location, radius float64) []domain.Location {
+	antisolar := domain.Location{
+		Latitude:  -subsolar.Latitude,
+		Longitude: domain.AngleDifference(subsolar.Longitude+180, 0),
+	}
+	meters := toRadians(radius) * domain.EarthRadiusMeters
+
+	var points []domain.Location
+	for bearing := 0.0; bearing < 360; bearing += terminatorStep {
+		p := antisolar.Destination(bearing, meters)
+		if n := len(points); n > 0 {
+			prev := points[n-1].Longitude
+			p.Longitude = prev + domain.AngleDifference(p.Longitude, prev)
+		}
+		points = append(points, p)
+	}
+	return points
+}
+
+// toRadians converts degrees to radians.
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// toDegrees converts radians to degrees.
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
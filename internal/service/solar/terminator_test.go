@@ -0,0 +1,109 @@
+package solar
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// sunElevation returns the geocentric sun elevation at p for a subsolar point.
+func sunElevation(subsolar, p domain.Location) float64 {
+	decl := toRadians(subsolar.Latitude)
+	lat := toRadians(p.Latitude)
+	hourAngle := toRadians(p.Longitude - subsolar.Longitude)
+	sinH := math.Sin(decl)*math.Sin(lat) + math.Cos(decl)*math.Cos(lat)*math.Cos(hourAngle)
+	return toDegrees(math.Asin(sinH))
+}
+
+func TestSubsolarPoint(t *testing.T) {
+	tests := []struct {
+		name           string
+		t              time.Time
+		wantLat        float64
+		wantLon        float64
+		latTol, lonTol float64
+	}{
+		// Declination is 0 at the equinox and ±23.44° at the solstices. At
+		// 12:00 UTC the sun is over Greenwich to within the equation of
+		// time (at most about 4.1° of longitude).
+		{"March equinox", time.Date(2025, 3, 20, 9, 1, 0, 0, time.UTC), 0, 45, 0.05, 5},
+		{"June solstice", time.Date(2025, 6, 21, 12, 0, 0, 0, time.UTC), 23.44, 0, 0.05, 5},
+		{"December solstice", time.Date(2025, 12, 21, 12, 0, 0, 0, time.UTC), -23.44, 0, 0.05, 5},
+		{"midnight UTC", time.Date(2025, 6, 21, 0, 0, 0, 0, time.UTC), 23.44, 180, 0.05, 5},
+		{"local time zones are ignored", time.Date(2025, 6, 21, 14, 0, 0, 0, time.FixedZone("CEST", 2*3600)), 23.44, 0, 0.05, 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := SubsolarPoint(tt.t)
+			if err != nil {
+				t.Fatalf("SubsolarPoint: %v", err)
+			}
+			if math.Abs(p.Latitude-tt.wantLat) > tt.latTol {
+				t.Errorf("latitude = %.3f, want %.2f", p.Latitude, tt.wantLat)
+			}
+			if p.Longitude < -180 || p.Longitude > 180 {
+				t.Errorf("longitude %.3f not in [-180, 180]", p.Longitude)
+			}
+			if math.Abs(domain.AngleDifference(p.Longitude, tt.wantLon)) > tt.lonTol {
+				t.Errorf("longitude = %.3f, want %.1f ± %.1f", p.Longitude, tt.wantLon, tt.lonTol)
+			}
+		})
+	}
+}
+
+func TestTerminator(t *testing.T) {
+	times := []struct {
+		name string
+		t    time.Time
+	}{
+		// The horizon band is a ring around the equinoxes and contains a
+		// pole otherwise; the deeper bands contain a pole near the solstices.
+		{"March equinox", time.Date(2025, 3, 20, 9, 1, 0, 0, time.UTC)},
+		{"June solstice", time.Date(2025, 6, 21, 2, 42, 0, 0, time.UTC)},
+		{"December solstice", time.Date(2025, 12, 21, 15, 3, 0, 0, time.UTC)},
+		{"autumn evening", time.Date(2025, 10, 14, 18, 30, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range times {
+		t.Run(tt.name, func(t *testing.T) {
+			bands, err := Terminator(tt.t)
+			if err != nil {
+				t.Fatalf("Terminator: %v", err)
+			}
+			if len(bands) != len(terminatorElevations) {
+				t.Fatalf("got %d bands, want %d", len(bands), len(terminatorElevations))
+			}
+			subsolar, _ := SubsolarPoint(tt.t)
+
+			for i, band := range bands {
+				if band.Elevation != terminatorElevations[i] {
+					t.Errorf("band %d elevation = %v, want %v", i, band.Elevation, terminatorElevations[i])
+				}
+				if len(band.Boundary) < 3 {
+					t.Fatalf("band %v has %d points", band.Elevation, len(band.Boundary))
+				}
+
+				for j, p := range band.Boundary {
+					// Points closing a pole cap lie on the pole, not the outline
+					if math.Abs(p.Latitude) == 90 {
+						continue
+					}
+					if h := sunElevation(subsolar, p); math.Abs(h-band.Elevation) > 0.05 {
+						t.Errorf("band %v point %d (%.2f, %.2f): sun elevation %.3f",
+							band.Elevation, j, p.Latitude, p.Longitude, h)
+						break
+					}
+					if j > 0 {
+						if jump := math.Abs(p.Longitude - band.Boundary[j-1].Longitude); jump > 180 {
+							t.Errorf("band %v jumps %.1f° of longitude at point %d", band.Elevation, jump, j)
+							break
+						}
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,400 @@
+// Package tilecache keeps the map's base layer tiles on disk and serves them
+// to the map from a local HTTP server.
+//
+// The map page loads its tiles from http://127.0.0.1:<port>/tiles/..., and
+// the cache forwards each request to the tile server the user selected (see
+// domain.TileServer), storing the answer. Stored tiles are reused for
+// tileTTL without asking the server again, and older ones whenever the
+// server can't be reached, so areas looked at before keep their map
+// offline.
+//
+// # Privacy Mode
+//
+// With SetOffline no tile request leaves the machine: only stored tiles are
+// served (tiles that were never stored stay blank), except from tile
+// servers on the local machine or network (see domain.TileServer.IsLocal),
+// such as a tileserver-gl set up for offline use.
+//
+// # Tile Usage Policies
+//
+// Only the tiles the user looks at are requested (there is no prefetching;
+// see domain.TileProvider.CheckPrefetch). Requests carry the app's
+// User-Agent, and tiles are kept at least 7 days, as the OpenStreetMap tile
+// usage policy asks.
+//
+// Policy: https://operations.osmfoundation.org/policies/tiles/
+package tilecache
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// tileTTL is how long a stored tile is used without asking the tile
+	// server again (the minimum the OpenStreetMap policy asks for).
+	tileTTL = 7 * 24 * time.Hour
+
+	// maxCacheBytes is the size the tile directory is pruned to on
+	// startup, oldest tiles first. A 256 px tile is about 10-30 KB, so
+	// this holds several thousand screens of map.
+	maxCacheBytes = 500 << 20
+
+	// maxTileBytes is the largest tile accepted from a server.
+	maxTileBytes = 2 << 20
+
+	// maxTileZoom is the highest zoom level the cache serves.
+	maxTileZoom = 22
+
+	// cacheDirName is the app's directory below the platform cache
+	// directory; tileDirName the tile cache's directory in it.
+	cacheDirName = "GoGoldenHour"
+	tileDirName  = "tiles"
+
+	// tilePrefix starts the path of a tile request:
+	// /tiles/<server key>/<z>/<x>/<y>[@2x].
+	tilePrefix = "/tiles/"
+)
+
+// DefaultDir returns the on-disk location of the tile cache, in the
+// platform's cache directory (e.g., ~/.cache/GoGoldenHour/tiles on Linux).
+//
+// The tiles are public map images, so all profiles share them. Returns an
+// empty string if the platform has no cache directory.
+func DefaultDir() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, cacheDirName, tileDirName)
+}
+
+// =============================================================================
+// Cache
+// =============================================================================
+
+// Cache stores map tiles on disk and serves them to the map (see the
+// package docs).
+//
+// Usage:
+//
+//	tiles := tilecache.New(tilecache.DefaultDir(), geocoding.UserAgent(version, ""))
+//	tiles.SetServer(settings.TileServer)
+//	baseURL, err := tiles.Start()
+//	// The map loads baseURL + Path(settings.TileServer)
+//
+// All methods are safe to call from any goroutine.
+type Cache struct {
+	// client is the HTTP client used for tile requests.
+	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	client *http.Client
+
+	// dir is the tile directory; one subdirectory per server (by
+	// domain.TileServer.CacheKey) holds z/x/y files.
+	dir string
+
+	// mu guards the fields below, which change while tiles are served.
+	mu sync.RWMutex
+
+	// servers are the tile servers the map may ask for, by cache key.
+	// Only these are contacted, so the cache can't be used as a proxy to
+	// anywhere else.
+	servers map[string]domain.TileServer
+
+	// userAgent is sent with every tile request.
+	userAgent string
+
+	// offline serves stored tiles only (privacy mode).
+	offline bool
+}
+
+// New creates a tile cache.
+//
+// Parameters:
+//   - dir: The tile directory (see DefaultDir)
+//   - userAgent: The User-Agent header to send to tile servers
+//
+// Returns a cache that doesn't serve anything until Start is called.
+func New(dir, userAgent string) *Cache {
+	return &Cache{
+		client: &http.Client{
+			Timeout: config.DefaultHTTPTimeout,
+		},
+		dir:       dir,
+		servers:   make(map[string]domain.TileServer),
+		userAgent: userAgent,
+	}
+}
+
+// Start serves the cache on a free port of the loopback interface, and
+// prunes the tile directory in the background.
+//
+// Returns the base URL of the cache (e.g., "http://127.0.0.1:41234"), or an
+// error if no port could be opened; the map should then load tiles from
+// the servers directly.
+func (c *Cache) Start() (string, error) {
+	if c.dir == "" {
+		return "", errors.New("no cache directory")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to open tile cache port: %w", err)
+	}
+	server := &http.Server{Handler: c, ReadHeaderTimeout: config.DefaultHTTPTimeout}
+	crash.Go(func() { server.Serve(listener) })
+	crash.Go(func() { c.prune(maxCacheBytes) })
+	return "http://" + listener.Addr().String(), nil
+}
+
+// SetServer allows the map to load the tiles of a server (the one the user
+// selected). Servers set earlier stay allowed, so tiles the map requested
+// just before a switch still arrive.
+func (c *Cache) SetServer(server domain.TileServer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.servers[server.CacheKey()] = server
+}
+
+// SetUserAgent changes the User-Agent header for later tile requests.
+func (c *Cache) SetUserAgent(userAgent string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.userAgent = userAgent
+}
+
+// SetOffline turns privacy mode on or off: while offline, only stored tiles
+// (or those of local servers) are served.
+func (c *Cache) SetOffline(offline bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.offline = offline
+}
+
+// Path returns the URL path template (Leaflet syntax) of a server's tiles,
+// to be appended to the base URL from Start. {r} is only included for
+// servers with retina tiles, so other servers' tiles aren't stored twice.
+func Path(server domain.TileServer) string {
+	path := tilePrefix + server.CacheKey() + "/{z}/{x}/{y}"
+	if strings.Contains(server.Template(), "{r}") {
+		path += "{r}"
+	}
+	return path
+}
+
+// =============================================================================
+// Serving Tiles
+// =============================================================================
+
+// tileRequest is a parsed tile path.
+type tileRequest struct {
+	key     string
+	z, x, y int
+	retina  bool
+}
+
+// parseTilePath parses /tiles/<key>/<z>/<x>/<y>[@2x] (see Path).
+func parseTilePath(path string) (tileRequest, bool) {
+	rest, ok := strings.CutPrefix(path, tilePrefix)
+	if !ok {
+		return tileRequest{}, false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 4 || parts[0] == "" || strings.ContainsAny(parts[0], ".\\") {
+		return tileRequest{}, false
+	}
+	var t tileRequest
+	t.key = parts[0]
+	row, retina := strings.CutSuffix(parts[3], "@2x")
+	t.retina = retina
+	var errZ, errX, errY error
+	t.z, errZ = strconv.Atoi(parts[1])
+	t.x, errX = strconv.Atoi(parts[2])
+	t.y, errY = strconv.Atoi(row)
+	if errZ != nil || errX != nil || errY != nil || t.z < 0 || t.z > maxTileZoom {
+		return tileRequest{}, false
+	}
+	if n := 1 << t.z; t.x < 0 || t.x >= n || t.y < 0 || t.y >= n {
+		return tileRequest{}, false
+	}
+	return t, true
+}
+
+// file returns the path of the tile's file in the cache directory.
+func (c *Cache) file(t tileRequest) string {
+	name := strconv.Itoa(t.y)
+	if t.retina {
+		name += "@2x"
+	}
+	return filepath.Join(c.dir, t.key, strconv.Itoa(t.z), strconv.Itoa(t.x), name)
+}
+
+// ServeHTTP answers a tile request of the map.
+//
+// A tile stored less than tileTTL ago is served from disk. Otherwise it is
+// fetched from the tile server and stored; if that fails (or privacy mode
+// is on), an older stored tile is served instead. Tiles that can't be
+// found anywhere get 404 Not Found (offline) or 502 Bad Gateway, which
+// leaves the map's square blank.
+func (c *Cache) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	t, ok := parseTilePath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	c.mu.RLock()
+	server, known := c.servers[t.key]
+	offline := c.offline && !server.IsLocal()
+	userAgent := c.userAgent
+	c.mu.RUnlock()
+	if !known {
+		http.NotFound(w, r)
+		return
+	}
+
+	path := c.file(t)
+	stored, storedErr := os.ReadFile(path)
+	info, statErr := os.Stat(path)
+	fresh := storedErr == nil && statErr == nil && time.Since(info.ModTime()) < tileTTL
+	stats.CacheLookup(stats.CacheMapTiles, fresh)
+	if fresh || (offline && storedErr == nil) {
+		writeTile(w, stored)
+		return
+	}
+	if offline {
+		http.Error(w, "tile not cached (privacy mode)", http.StatusNotFound)
+		return
+	}
+
+	tile, err := c.fetch(r, server.TileURL(t.z, t.x, t.y, t.retina), userAgent)
+	if err != nil {
+		if storedErr == nil {
+			writeTile(w, stored)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	c.store(path, tile)
+	writeTile(w, tile)
+}
+
+// fetch downloads a tile from its server. The request is cancelled if the
+// map no longer needs the tile (e.g., it was panned away).
+func (c *Cache) fetch(r *http.Request, tileURL, userAgent string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(r.Context(), "GET", tileURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	start := time.Now()
+	resp, err := c.client.Do(req)
+	stats.RecordRequest(stats.OpMapTile, time.Since(start), resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("tile request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("tile server returned status %d", resp.StatusCode)
+	}
+	tile, err := io.ReadAll(io.LimitReader(resp.Body, maxTileBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tile: %w", err)
+	}
+	if len(tile) > maxTileBytes {
+		return nil, errors.New("tile too large")
+	}
+	return tile, nil
+}
+
+// store writes a tile to the cache directory. The file is replaced
+// atomically, so a concurrent request never reads half a tile. Failures
+// are ignored (the tile is still served).
+func (c *Cache) store(path string, tile []byte) {
+	if os.MkdirAll(filepath.Dir(path), 0o755) != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tile-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(tile)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || os.Rename(tmp.Name(), path) != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+// writeTile sends a tile, with its image type detected from the content
+// (tile servers send PNG, JPEG or WebP).
+func writeTile(w http.ResponseWriter, tile []byte) {
+	w.Header().Set("Content-Type", http.DetectContentType(tile))
+	w.Header().Set("Cache-Control", "max-age=3600")
+	w.Write(tile)
+}
+
+// =============================================================================
+// Pruning
+// =============================================================================
+
+// prune deletes the oldest stored tiles until the directory holds at most
+// maxBytes. Errors are ignored; the next start tries again.
+func (c *Cache) prune(maxBytes int64) {
+	type tileFile struct {
+		path     string
+		size     int64
+		modified time.Time
+	}
+	var files []tileFile
+	var total int64
+	filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		files = append(files, tileFile{path: path, size: info.Size(), modified: info.ModTime()})
+		total += info.Size()
+		return nil
+	})
+	if total <= maxBytes {
+		return
+	}
+
+	slices.SortFunc(files, func(a, b tileFile) int {
+		return cmp.Compare(a.modified.UnixNano(), b.modified.UnixNano())
+	})
+	for _, f := range files {
+		if total <= maxBytes {
+			break
+		}
+		if os.Remove(f.path) == nil {
+			total -= f.size
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package tilecache
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// get requests a tile path from the cache and returns the status and body.
+func get(c *Cache, path string) (int, string) {
+	rec := httptest.NewRecorder()
+	c.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	return rec.Code, rec.Body.String()
+}
+
+func TestParseTilePath(t *testing.T) {
+	tests := []struct {
+		path string
+		want tileRequest
+		ok   bool
+	}{
+		{"/tiles/abc/5/17/11", tileRequest{key: "abc", z: 5, x: 17, y: 11}, true},
+		{"/tiles/abc/5/17/11@2x", tileRequest{key: "abc", z: 5, x: 17, y: 11, retina: true}, true},
+		{"/tiles/abc/5/32/11", tileRequest{}, false},
+		{"/tiles/abc/23/0/0", tileRequest{}, false},
+		{"/tiles/abc/5/17", tileRequest{}, false},
+		{"/tiles/../5/17/11", tileRequest{}, false},
+		{"/other/abc/5/17/11", tileRequest{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseTilePath(tt.path)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("parseTilePath(%q) = %+v, %v; want %+v, %v", tt.path, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPath(t *testing.T) {
+	osm := domain.TileServer{}
+	if got, want := Path(osm), "/tiles/"+osm.CacheKey()+"/{z}/{x}/{y}"; got != want {
+		t.Errorf("Path(OpenStreetMap) = %q, want %q", got, want)
+	}
+	retina := domain.TileServer{URL: "https://tiles.example.com/{z}/{x}/{y}{r}.png"}
+	if got, want := Path(retina), "/tiles/"+retina.CacheKey()+"/{z}/{x}/{y}{r}"; got != want {
+		t.Errorf("Path(retina server) = %q, want %q", got, want)
+	}
+}
+
+func TestCacheServesAndStoresTiles(t *testing.T) {
+	requests := 0
+	failing := false
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if failing {
+			http.Error(w, "down", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/5/17/11.png" || r.Header.Get("User-Agent") != "test-agent" {
+			t.Errorf("upstream got %s with User-Agent %q", r.URL.Path, r.Header.Get("User-Agent"))
+		}
+		w.Write([]byte("tile"))
+	}))
+	defer upstream.Close()
+
+	c := New(t.TempDir(), "test-agent")
+	server := domain.TileServer{URL: upstream.URL + "/{z}/{x}/{y}.png"}
+	c.SetServer(server)
+	path := tilePrefix + server.CacheKey() + "/5/17/11"
+
+	for i := range 2 {
+		if code, body := get(c, path); code != http.StatusOK || body != "tile" {
+			t.Fatalf("request %d = %d %q, want the tile", i, code, body)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("tile server got %d requests, want 1 (then served from the cache)", requests)
+	}
+
+	// A stale tile is refreshed, and still served while the server is down
+	old := time.Now().Add(-2 * tileTTL)
+	os.Chtimes(c.file(tileRequest{key: server.CacheKey(), z: 5, x: 17, y: 11}), old, old)
+	failing = true
+	if code, body := get(c, path); code != http.StatusOK || body != "tile" {
+		t.Errorf("stale tile with server down = %d %q, want the stored tile", code, body)
+	}
+	if requests != 2 {
+		t.Errorf("stale tile not refreshed: %d requests", requests)
+	}
+
+	// Only servers set with SetServer are contacted
+	if code, _ := get(c, tilePrefix+"0123456789abcdef/5/17/11"); code != http.StatusNotFound {
+		t.Errorf("unknown server = %d, want 404", code)
+	}
+}
+
+func TestCacheOffline(t *testing.T) {
+	c := New(t.TempDir(), "test-agent")
+	server := domain.TileServer{URL: "https://tiles.invalid/{z}/{x}/{y}.png"}
+	c.SetServer(server)
+	c.SetOffline(true)
+
+	stored := tileRequest{key: server.CacheKey(), z: 3, x: 1, y: 2}
+	c.store(c.file(stored), []byte("stored"))
+	old := time.Now().Add(-2 * tileTTL)
+	os.Chtimes(c.file(stored), old, old)
+
+	if code, body := get(c, tilePrefix+server.CacheKey()+"/3/1/2"); code != http.StatusOK || body != "stored" {
+		t.Errorf("stored tile offline = %d %q, want the stored tile", code, body)
+	}
+	if code, _ := get(c, tilePrefix+server.CacheKey()+"/3/1/3"); code != http.StatusNotFound {
+		t.Errorf("missing tile offline = %d, want 404 without a request", code)
+	}
+}
+
+func TestPrune(t *testing.T) {
+	c := New(t.TempDir(), "")
+	for i, age := range []time.Duration{3 * time.Hour, time.Hour, 2 * time.Hour} {
+		path := c.file(tileRequest{key: "k", z: 1, x: 0, y: i})
+		c.store(path, make([]byte, 100))
+		modified := time.Now().Add(-age)
+		os.Chtimes(path, modified, modified)
+	}
+
+	c.prune(150)
+	for i, want := range []bool{false, true, false} {
+		_, err := os.Stat(c.file(tileRequest{key: "k", z: 1, x: 0, y: i}))
+		if got := err == nil; got != want {
+			t.Errorf("tile %d kept = %v, want %v", i, got, want)
+		}
+	}
+}
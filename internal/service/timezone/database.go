@@ -0,0 +1,93 @@
+package timezone
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// =============================================================================
+// Time Zone Database Diagnostics
+// =============================================================================
+
+// systemSources lists where time.LoadLocation looks for zoneinfo files on
+// Unix-like systems, in the order the standard library tries them.
+var systemSources = []string{
+	"/usr/share/zoneinfo/",
+	"/usr/share/lib/zoneinfo/",
+	"/usr/lib/locale/TZ/",
+	"/etc/zoneinfo/",
+}
+
+// probeZone is loaded to check that the database actually works. Any zone
+// other than UTC will do, since UTC is built into Go and always loads.
+const probeZone = "Europe/Paris"
+
+// DatabaseInfo describes the time zone database used by time.LoadLocation.
+type DatabaseInfo struct {
+	// Source is where zone data comes from: "ZONEINFO", "system", "embedded"
+	// or "none".
+	Source string
+
+	// Path is the directory or file of ZONEINFO and system sources.
+	Path string
+
+	// Available is false when zones can't be loaded at all. Solar times then
+	// fall back to the system local time zone instead of the location's.
+	Available bool
+}
+
+// String returns a one-line description, e.g.
+// "system (/usr/share/zoneinfo/)" or "embedded (time/tzdata)".
+func (d DatabaseInfo) String() string {
+	switch {
+	case !d.Available:
+		return "none (time zones fall back to local time)"
+	case d.Path != "":
+		return d.Source + " (" + d.Path + ")"
+	case d.Source == "embedded":
+		return d.Source + " (time/tzdata)"
+	default:
+		return d.Source
+	}
+}
+
+// Database reports which time zone database time.LoadLocation uses.
+//
+// The sources are checked in the standard library's order of precedence:
+//  1. The ZONEINFO environment variable (a directory or zip file)
+//  2. The system zoneinfo directories (Unix-like systems)
+//  3. The database embedded in the binary (see tzdata.go)
+//
+// On Windows, the standard library also tries the zoneinfo.zip of a local
+// Go installation before the embedded copy. That case is reported as
+// "embedded" as the data is the same.
+//
+// Example:
+//
+//	if db := timezone.Database(); !db.Available {
+//		// Warn that times will be shown in local time
+//	}
+func Database() DatabaseInfo {
+	_, err := time.LoadLocation(probeZone)
+	info := DatabaseInfo{Available: err == nil}
+
+	if path := os.Getenv("ZONEINFO"); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			info.Source, info.Path = "ZONEINFO", path
+			return info
+		}
+	}
+	for _, dir := range systemSources {
+		if _, err := os.Stat(filepath.Join(dir, probeZone)); err == nil {
+			info.Source, info.Path = "system", dir
+			return info
+		}
+	}
+	if embeddedDatabase {
+		info.Source = "embedded"
+	} else {
+		info.Source = "none"
+	}
+	return info
+}
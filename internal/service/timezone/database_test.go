@@ -0,0 +1,48 @@
+package timezone
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDatabaseInfoString(t *testing.T) {
+	tests := []struct {
+		info DatabaseInfo
+		want string
+	}{
+		{DatabaseInfo{Source: "system", Path: "/usr/share/zoneinfo/", Available: true}, "system (/usr/share/zoneinfo/)"},
+		{DatabaseInfo{Source: "ZONEINFO", Path: "/opt/zoneinfo.zip", Available: true}, "ZONEINFO (/opt/zoneinfo.zip)"},
+		{DatabaseInfo{Source: "embedded", Available: true}, "embedded (time/tzdata)"},
+		{DatabaseInfo{Source: "system", Path: "/usr/share/zoneinfo/"}, "none (time zones fall back to local time)"},
+		{DatabaseInfo{Source: "none"}, "none (time zones fall back to local time)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.info, got, tt.want)
+		}
+	}
+}
+
+func TestDatabase(t *testing.T) {
+	t.Run("ZONEINFO takes precedence", func(t *testing.T) {
+		dir := t.TempDir()
+		t.Setenv("ZONEINFO", dir)
+		info := Database()
+		if info.Source != "ZONEINFO" || info.Path != dir {
+			t.Errorf("Database() = %+v, want ZONEINFO source at %s", info, dir)
+		}
+	})
+
+	t.Run("missing ZONEINFO is ignored", func(t *testing.T) {
+		t.Setenv("ZONEINFO", filepath.Join(t.TempDir(), "missing.zip"))
+		info := Database()
+		if info.Source == "ZONEINFO" {
+			t.Errorf("Database() = %+v, want a source other than ZONEINFO", info)
+		}
+		// The default build embeds tzdata, so zones always load
+		if embeddedDatabase && (!info.Available || info.Source == "none") {
+			t.Errorf("Database() = %+v, want an available database", info)
+		}
+	})
+}
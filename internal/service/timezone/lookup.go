@@ -42,6 +42,7 @@
 package timezone
 
 import (
+	"slices"
 	"time"
 
 	"github.com/ringsaturn/tzf"
@@ -152,9 +153,23 @@ func LoadLocation(lat, lon float64) *time.Location {
 		// This should rarely happen since FromCoordinates returns valid
 		// IANA identifiers. Could occur if:
 		// - tzf returns a timezone not in Go's database (very rare)
-		// - System timezone files are missing and no embedded tzdata
+		// - System timezone files are missing in a system_tzdata build
+		//   (see Database)
 		return time.UTC
 	}
 
 	return loc
 }
+
+// Names returns the IANA identifiers of all timezones in the boundary
+// data, sorted (e.g., for a timezone picker).
+//
+// Example:
+//
+//	names := timezone.Names()
+//	// names = ["Africa/Abidjan", "Africa/Accra", ...]
+func Names() []string {
+	names := slices.Clone(finder.TimezoneNames())
+	slices.Sort(names)
+	return slices.Compact(names)
+}
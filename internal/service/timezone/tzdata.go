@@ -0,0 +1,15 @@
+//go:build !system_tzdata
+
+package timezone
+
+// Embed Go's copy of the IANA time zone database (about 450 KB) so that
+// time.LoadLocation works on systems without zoneinfo files, such as
+// Windows machines without a Go installation and minimal containers.
+// time.LoadLocation still prefers the system database when one exists and
+// only falls back to the embedded copy.
+//
+// Build with -tags system_tzdata to leave it out (see tzdata_system.go).
+import _ "time/tzdata"
+
+// embeddedDatabase reports whether the binary embeds the time zone database.
+const embeddedDatabase = true
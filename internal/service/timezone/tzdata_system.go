@@ -0,0 +1,10 @@
+//go:build system_tzdata
+
+package timezone
+
+// embeddedDatabase reports whether the binary embeds the time zone database.
+//
+// Builds tagged system_tzdata rely entirely on the system's zoneinfo files,
+// which suits distribution packages that keep tzdata updated separately.
+// Database reports when none are found.
+const embeddedDatabase = false
@@ -0,0 +1,155 @@
+// Package updates checks whether a newer release of the application is
+// available.
+//
+// # GitHub Releases API
+//
+// Releases are published on GitHub. The latest one is looked up with the
+// public REST API, which needs no account or token for this:
+//
+//   - GET https://api.github.com/repos/megatih/GoGoldenHour/releases/latest
+//   - Unauthenticated requests are limited to 60 per hour per IP address,
+//     plenty for a check the user starts from the Help menu
+//   - A User-Agent header is required
+//
+// Drafts and pre-releases aren't returned as the latest release.
+//
+// Documentation: https://docs.github.com/en/rest/releases/releases
+//
+// Nothing is downloaded or installed: the user is pointed to the release
+// page.
+package updates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// latestReleaseEndpoint is the URL of the latest release.
+	latestReleaseEndpoint = "https://api.github.com/repos/megatih/GoGoldenHour/releases/latest"
+
+	// ReleasesURL is the page listing all releases, for when the latest
+	// release can't be looked up.
+	ReleasesURL = "https://github.com/megatih/GoGoldenHour/releases"
+)
+
+// =============================================================================
+// Types
+// =============================================================================
+
+// Release is a published release of the application.
+type Release struct {
+	// Version is the release's version without the tag's "v" prefix,
+	// e.g., "0.1.4" (comparable with changelog.CompareVersions).
+	Version string
+
+	// URL is the release's web page, with its notes and downloads.
+	URL string
+}
+
+// githubRelease is the part of the API's release object used here.
+//
+// Example response (abridged):
+//
+//	{"tag_name": "v0.1.4",
+//	 "html_url": "https://github.com/megatih/GoGoldenHour/releases/tag/v0.1.4",
+//	 "published_at": "2026-10-01T12:00:00Z"}
+type githubRelease struct {
+	TagName string `json:"tag_name"`
+	HTMLURL string `json:"html_url"`
+}
+
+// =============================================================================
+// Service
+// =============================================================================
+
+// GitHubService looks up the latest release on GitHub.
+//
+// Usage:
+//
+//	service := updates.NewGitHubService(userAgent)
+//	latest, err := service.Latest()
+//	if err == nil && changelog.CompareVersions(latest.Version, version) > 0 {
+//	    // Offer latest.URL
+//	}
+type GitHubService struct {
+	// client is the HTTP client used for API requests.
+	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	client *http.Client
+
+	// endpoint is the URL of the latest release (latestReleaseEndpoint; a
+	// test server in tests).
+	endpoint string
+
+	// userAgent identifies the application, as the API requires.
+	userAgent string
+}
+
+// NewGitHubService creates a new update check service.
+//
+// Parameters:
+//   - userAgent: The User-Agent header (see geocoding.UserAgent)
+//
+// Returns a ready-to-use GitHubService instance.
+func NewGitHubService(userAgent string) *GitHubService {
+	return &GitHubService{
+		client: &http.Client{
+			Timeout: config.DefaultHTTPTimeout,
+		},
+		endpoint:  latestReleaseEndpoint,
+		userAgent: userAgent,
+	}
+}
+
+// Latest fetches the latest published release.
+//
+// This makes a network request and must be run in a background goroutine.
+//
+// Returns:
+//   - Release: The latest release
+//   - error: Non-nil if the request fails or the response has no version
+func (s *GitHubService) Latest() (Release, error) {
+	req, err := http.NewRequest(http.MethodGet, s.endpoint, nil)
+	if err != nil {
+		return Release{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", s.userAgent)
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	stats.RecordRequest(stats.OpGitHub, time.Since(start), resp, err)
+	if err != nil {
+		return Release{}, fmt.Errorf("update check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Without any published release, the API answers 404
+	if resp.StatusCode != http.StatusOK {
+		return Release{}, fmt.Errorf("update check returned status %d", resp.StatusCode)
+	}
+
+	var result githubRelease
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Release{}, fmt.Errorf("failed to decode release: %w", err)
+	}
+	version := strings.TrimPrefix(strings.TrimSpace(result.TagName), "v")
+	if version == "" {
+		return Release{}, fmt.Errorf("the latest release has no version")
+	}
+	url := result.HTMLURL
+	if !strings.HasPrefix(url, "https://") {
+		url = ReleasesURL
+	}
+	return Release{Version: version, URL: url}, nil
+}
@@ -0,0 +1,50 @@
+package updates
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLatest(t *testing.T) {
+	var body string
+	status := http.StatusOK
+	var userAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	s := NewGitHubService("GoGoldenHour/0.1.3")
+	s.endpoint = server.URL
+
+	body = `{"tag_name": "v0.1.4", "html_url": "https://github.com/megatih/GoGoldenHour/releases/tag/v0.1.4"}`
+	latest, err := s.Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Version != "0.1.4" || latest.URL != "https://github.com/megatih/GoGoldenHour/releases/tag/v0.1.4" {
+		t.Errorf("Latest() = %+v, want version 0.1.4 and its page", latest)
+	}
+	if userAgent != "GoGoldenHour/0.1.3" {
+		t.Errorf("User-Agent = %q, want the app's", userAgent)
+	}
+
+	// Links other than HTTPS aren't offered
+	body = `{"tag_name": "0.2.0", "html_url": "javascript:alert(1)"}`
+	if latest, err = s.Latest(); err != nil || latest.URL != ReleasesURL {
+		t.Errorf("Latest() = %+v, %v; want the releases page", latest, err)
+	}
+
+	body = `{"tag_name": ""}`
+	if _, err := s.Latest(); err == nil {
+		t.Error("release without a version: no error")
+	}
+
+	status = http.StatusNotFound
+	body = `{"message": "Not Found"}`
+	if _, err := s.Latest(); err == nil {
+		t.Error("no release: no error")
+	}
+}
@@ -0,0 +1,271 @@
+package weather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Forecast Constants
+// =============================================================================
+
+const (
+	// forecastEndpoint is the URL of the forecast API.
+	// Accepts query parameters: latitude, longitude, hourly (the variables),
+	// start_date, end_date, timezone and timeformat
+	forecastEndpoint = "https://api.open-meteo.com/v1/forecast"
+
+	// ForecastDays is how many days, today included, the forecast covers.
+	ForecastDays = 16
+
+	// forecastTTL is how long a forecast is reused for the same place and
+	// date before it's fetched again. Forecasts are updated hourly.
+	forecastTTL = 30 * time.Minute
+
+	// maxCachedForecasts is how many forecasts are kept before the cache
+	// is cleared.
+	maxCachedForecasts = 64
+)
+
+// ErrNoForecast is returned for dates the forecast doesn't cover: before
+// today or ForecastDays or more ahead. No request is made for them.
+var ErrNoForecast = errors.New("no forecast for this date")
+
+// forecastResponse is the forecast API response.
+//
+// Example response (abridged, timeformat=unixtime):
+//
+//	{"hourly": {"time": [1750525200, 1750528800],
+//	            "cloud_cover": [35, 60],
+//	            "precipitation": [0.0, 0.2],
+//	            "visibility": [24140.0, 18000.0]}}
+//
+// Values the model doesn't provide for an hour are null.
+type forecastResponse struct {
+	Hourly struct {
+		Time          []int64    `json:"time"`
+		CloudCover    []*float64 `json:"cloud_cover"`
+		Precipitation []*float64 `json:"precipitation"`
+		Visibility    []*float64 `json:"visibility"`
+	} `json:"hourly"`
+}
+
+// hours returns the response as forecast hours, leaving out those without
+// a cloud cover.
+func (r forecastResponse) hours() []domain.WeatherHour {
+	value := func(list []*float64, i int) float64 {
+		if i < len(list) && list[i] != nil {
+			return *list[i]
+		}
+		return 0
+	}
+	var hours []domain.WeatherHour
+	for i, t := range r.Hourly.Time {
+		if i >= len(r.Hourly.CloudCover) || r.Hourly.CloudCover[i] == nil {
+			continue
+		}
+		hours = append(hours, domain.WeatherHour{
+			Time:          time.Unix(t, 0),
+			CloudCover:    int(math.Round(*r.Hourly.CloudCover[i])),
+			Precipitation: value(r.Hourly.Precipitation, i),
+			Visibility:    value(r.Hourly.Visibility, i),
+		})
+	}
+	return hours
+}
+
+// =============================================================================
+// Forecast Service
+// =============================================================================
+
+// OpenMeteoService looks up hourly weather forecasts using the Open-Meteo
+// forecast API.
+//
+// Forecasts are kept for forecastTTL by place and date, since the sun
+// times are recalculated (and the forecast asked for again) whenever a
+// setting changes.
+//
+// Usage:
+//
+//	service := weather.NewOpenMeteoService()
+//	forecast, err := service.Forecast(ctx, location, date)
+//	if err != nil {
+//	    // Show no weather
+//	}
+//	if w, ok := forecast.During(sunTimes.GoldenEvening); ok {
+//	    fmt.Println(w.Icon(), w.Summary())
+//	}
+type OpenMeteoService struct {
+	// client is the HTTP client used for API requests.
+	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	client *http.Client
+
+	// endpoint is the URL of the forecast API (forecastEndpoint; a test
+	// server in tests).
+	endpoint string
+
+	// now returns the current time (time.Now; a fake clock in tests).
+	now func() time.Time
+
+	// mu guards cache.
+	mu sync.Mutex
+
+	// cache holds the recent forecasts, keyed by the rounded coordinates,
+	// the timezone and the first and last date.
+	cache map[string]cachedForecast
+}
+
+// cachedForecast is a forecast in the cache.
+type cachedForecast struct {
+	forecast domain.WeatherForecast
+	fetched  time.Time
+}
+
+// NewOpenMeteoService creates a new forecast service.
+//
+// Returns a ready-to-use OpenMeteoService instance.
+func NewOpenMeteoService() *OpenMeteoService {
+	return &OpenMeteoService{
+		client: &http.Client{
+			Timeout: config.DefaultHTTPTimeout,
+		},
+		endpoint: forecastEndpoint,
+		now:      time.Now,
+		cache:    make(map[string]cachedForecast),
+	}
+}
+
+// Forecast fetches the hourly forecast for a date at a place, from its
+// midnight to the following midnight (so evening blue hours running past
+// midnight are covered).
+//
+// This makes a network request (unless the forecast is cached) and must be
+// run in a background goroutine.
+//
+// Parameters:
+//   - ctx: Cancels the request
+//   - loc: The place; its timezone decides where the date starts
+//   - date: The date; only the day matters
+//
+// Returns:
+//   - domain.WeatherForecast: The forecast hours
+//   - error: ErrNoForecast for dates outside the forecast; non-nil too if
+//     the request fails or returns no hours
+func (s *OpenMeteoService) Forecast(ctx context.Context, loc domain.Location, date time.Time) (domain.WeatherForecast, error) {
+	return s.fetch(ctx, loc, date, 1)
+}
+
+// Outlook fetches the hourly forecast for the coming days at a place, from
+// today's midnight to the midnight after the last day.
+//
+// Like Forecast, this makes a network request (unless the forecast is
+// cached) and must be run in a background goroutine.
+//
+// Parameters:
+//   - ctx: Cancels the request
+//   - loc: The place; its timezone decides when today starts
+//   - days: The number of days, today included (up to ForecastDays)
+//
+// Returns the forecast hours, or an error if the request fails or returns
+// no hours.
+func (s *OpenMeteoService) Outlook(ctx context.Context, loc domain.Location, days int) (domain.WeatherForecast, error) {
+	return s.fetch(ctx, loc, time.Time{}, max(days, 1))
+}
+
+// fetch fetches the hourly forecast for days days from a date (zero for
+// today at the place), and the night after them as far as the forecast
+// goes.
+func (s *OpenMeteoService) fetch(ctx context.Context, loc domain.Location, date time.Time, days int) (domain.WeatherForecast, error) {
+	zone := time.UTC
+	if z, err := time.LoadLocation(loc.Timezone); err == nil {
+		zone = z
+	}
+	now := s.now().In(zone)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, zone)
+	if date.IsZero() {
+		date = today
+	}
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, zone)
+	last := today.AddDate(0, 0, ForecastDays-1)
+	if day.Before(today) || day.After(last) {
+		return domain.WeatherForecast{}, ErrNoForecast
+	}
+	end := day.AddDate(0, 0, days)
+	if end.After(last) {
+		end = last
+	}
+
+	key := fmt.Sprintf("%.3f,%.3f,%s,%s,%s", loc.Latitude, loc.Longitude, zone,
+		day.Format(time.DateOnly), end.Format(time.DateOnly))
+	if forecast, ok := s.cached(key, now); ok {
+		return forecast, nil
+	}
+
+	params := url.Values{}
+	params.Set("latitude", strconv.FormatFloat(loc.Latitude, 'f', 4, 64))
+	params.Set("longitude", strconv.FormatFloat(loc.Longitude, 'f', 4, 64))
+	params.Set("hourly", "cloud_cover,precipitation,visibility")
+	params.Set("start_date", day.Format(time.DateOnly))
+	params.Set("end_date", end.Format(time.DateOnly))
+	params.Set("timezone", zone.String())
+	params.Set("timeformat", "unixtime")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.endpoint+"?"+params.Encode(), nil)
+	if err != nil {
+		return domain.WeatherForecast{}, fmt.Errorf("failed to create forecast request: %w", err)
+	}
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	stats.RecordRequest(stats.OpForecast, time.Since(start), resp, err)
+	if err != nil {
+		return domain.WeatherForecast{}, fmt.Errorf("forecast request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return domain.WeatherForecast{}, fmt.Errorf("forecast request returned status %d", resp.StatusCode)
+	}
+
+	var result forecastResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return domain.WeatherForecast{}, fmt.Errorf("failed to decode forecast response: %w", err)
+	}
+	forecast := domain.WeatherForecast{Hours: result.hours()}
+	if len(forecast.Hours) == 0 {
+		return domain.WeatherForecast{}, fmt.Errorf("forecast response has no hours")
+	}
+	s.store(key, forecast, now)
+	return forecast, nil
+}
+
+// cached returns the forecast stored under key if it's recent enough.
+func (s *OpenMeteoService) cached(key string, now time.Time) (domain.WeatherForecast, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.cache[key]
+	hit := ok && now.Sub(entry.fetched) < forecastTTL
+	stats.CacheLookup(stats.CacheForecasts, hit)
+	return entry.forecast, hit
+}
+
+// store keeps a forecast under key, clearing the cache when it's full.
+func (s *OpenMeteoService) store(key string, forecast domain.WeatherForecast, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.cache) >= maxCachedForecasts {
+		clear(s.cache)
+	}
+	s.cache[key] = cachedForecast{forecast: forecast, fetched: now}
+}
@@ -0,0 +1,95 @@
+package weather
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestForecast(t *testing.T) {
+	requests := 0
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		query = r.URL.Query()
+		w.Write([]byte(`{"hourly": {"time": [1750525200, 1750528800, 1750532400],
+			"cloud_cover": [35, null, 80],
+			"precipitation": [0.0, 0.0, 0.4],
+			"visibility": [24140.0, null, null]}}`))
+	}))
+	defer server.Close()
+	now := time.Date(2025, 6, 20, 12, 0, 0, 0, time.UTC)
+	s := NewOpenMeteoService()
+	s.endpoint = server.URL
+	s.now = func() time.Time { return now }
+
+	loc := domain.Location{Latitude: 48.85, Longitude: 2.35, Timezone: "UTC"}
+	forecast, err := s.Forecast(context.Background(), loc, now.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("start_date") != "2025-06-21" || query.Get("end_date") != "2025-06-22" ||
+		query.Get("hourly") != "cloud_cover,precipitation,visibility" {
+		t.Errorf("query %v, want the 21st and the following night", query)
+	}
+	// The hour without a cloud cover is left out
+	if len(forecast.Hours) != 2 {
+		t.Fatalf("hours %+v, want 2", forecast.Hours)
+	}
+	if h := forecast.Hours[1]; !h.Time.Equal(time.Unix(1750532400, 0)) || h.CloudCover != 80 ||
+		h.Precipitation != 0.4 || h.Visibility != 0 {
+		t.Errorf("second hour %+v, want 80%% clouds, 0.4 mm, unknown visibility", h)
+	}
+
+	// Asked again, the forecast comes from the cache until it's old
+	if _, err := s.Forecast(context.Background(), loc, now.AddDate(0, 0, 1)); err != nil || requests != 1 {
+		t.Errorf("second forecast: %v after %d requests, want the cached one", err, requests)
+	}
+	now = now.Add(forecastTTL)
+	if _, err := s.Forecast(context.Background(), loc, now.AddDate(0, 0, 1)); err != nil || requests != 2 {
+		t.Errorf("expired forecast: %v after %d requests, want a new request", err, requests)
+	}
+
+	for _, date := range []time.Time{now.AddDate(0, 0, -1), now.AddDate(0, 0, ForecastDays)} {
+		if _, err := s.Forecast(context.Background(), loc, date); !errors.Is(err, ErrNoForecast) {
+			t.Errorf("forecast for %v: %v, want ErrNoForecast", date, err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("%d requests, want none for dates outside the forecast", requests-2)
+	}
+
+	// The last day has no following night in the forecast
+	s.Forecast(context.Background(), loc, now.AddDate(0, 0, ForecastDays-1))
+	if query.Get("start_date") != query.Get("end_date") {
+		t.Errorf("last day: %s to %s, want one day", query.Get("start_date"), query.Get("end_date"))
+	}
+}
+
+func TestOutlook(t *testing.T) {
+	var query url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"hourly": {"time": [1750525200], "cloud_cover": [35]}}`))
+	}))
+	defer server.Close()
+	s := NewOpenMeteoService()
+	s.endpoint = server.URL
+	s.now = func() time.Time { return time.Date(2025, 6, 20, 23, 30, 0, 0, time.UTC) }
+
+	// Today at the place, which is already the 21st in Tokyo
+	loc := domain.Location{Latitude: 35.68, Longitude: 139.77, Timezone: "Asia/Tokyo"}
+	if _, err := s.Outlook(context.Background(), loc, 7); err != nil {
+		t.Fatal(err)
+	}
+	if query.Get("start_date") != "2025-06-21" || query.Get("end_date") != "2025-06-28" ||
+		query.Get("timezone") != "Asia/Tokyo" {
+		t.Errorf("query %v, want a week from the 21st in Tokyo", query)
+	}
+}
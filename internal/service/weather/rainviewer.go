@@ -0,0 +1,191 @@
+// Package weather provides animated cloud map frames from RainViewer and
+// hourly forecasts from Open-Meteo.
+//
+// The cloud layer of the map plays recent satellite (or, where that isn't
+// available, precipitation radar) images around the golden hour, to help
+// judge whether a cloud bank will clear before sunset. The forecast rates
+// the golden and blue hours of the selected date by their cloud cover,
+// precipitation and visibility (see OpenMeteoService).
+//
+// # RainViewer Weather Maps API
+//
+// RainViewer publishes an index of its current map frames for free, without
+// an API key, for personal and non-commercial use:
+//
+//   - Frames every 10 minutes for about the past two hours, plus a short
+//     nowcast (extrapolated) for radar
+//   - Tiles up to zoom level 7; closer views scale them up
+//   - Attribution to RainViewer on the map is required
+//
+// Documentation: https://www.rainviewer.com/api.html
+//
+// # Open-Meteo Forecast API
+//
+// Open-Meteo serves hourly forecasts from national weather services for
+// free, without an API key, for non-commercial use:
+//
+//   - Up to 16 days ahead (ForecastDays)
+//   - Fair use limit of 10,000 requests per day
+//
+// Documentation: https://open-meteo.com/en/docs
+package weather
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// rainViewerEndpoint is the URL of the frame index.
+	rainViewerEndpoint = "https://api.rainviewer.com/public/weather-maps.json"
+
+	// MaxZoom is the highest zoom level RainViewer serves tiles for.
+	MaxZoom = 7
+
+	// Attribution is the credit the map must show with the layer, as HTML.
+	Attribution = `Clouds: <a href="https://www.rainviewer.com">RainViewer</a>`
+
+	// satelliteTiles and radarTiles complete a frame path to a tile URL
+	// template: 256 px tiles, then the color scheme and options. Infrared
+	// satellite images have a single scheme; radar uses scheme 2
+	// (universal blue) with smoothing and snow colors.
+	satelliteTiles = "/256/{z}/{x}/{y}/0/0_0.png"
+	radarTiles     = "/256/{z}/{x}/{y}/2/1_1.png"
+)
+
+// =============================================================================
+// API Response Types
+// =============================================================================
+
+// rainViewerFrame is one frame of the index.
+type rainViewerFrame struct {
+	// Time is the frame's moment in Unix seconds.
+	Time int64 `json:"time"`
+
+	// Path is the frame's tile path below the host, e.g.,
+	// "/v2/radar/1725000000".
+	Path string `json:"path"`
+}
+
+// rainViewerResponse is the frame index.
+//
+// Example response (abridged):
+//
+//	{"host": "https://tilecache.rainviewer.com",
+//	 "radar": {"past": [{"time": 1725000000, "path": "/v2/radar/1725000000"}],
+//	           "nowcast": [...]},
+//	 "satellite": {"infrared": [...]}}
+type rainViewerResponse struct {
+	// Host is the tile server the paths are relative to.
+	Host string `json:"host"`
+
+	Radar struct {
+		Past    []rainViewerFrame `json:"past"`
+		Nowcast []rainViewerFrame `json:"nowcast"`
+	} `json:"radar"`
+
+	Satellite struct {
+		Infrared []rainViewerFrame `json:"infrared"`
+	} `json:"satellite"`
+}
+
+// frames returns the index as cloud frames: the infrared satellite frames
+// if there are any (they show all clouds, not only those bringing rain),
+// else the past and nowcast radar frames.
+func (r rainViewerResponse) frames() []domain.CloudFrame {
+	var frames []domain.CloudFrame
+	add := func(list []rainViewerFrame, tiles string, forecast bool) {
+		for _, f := range list {
+			frames = append(frames, domain.CloudFrame{
+				Time:     time.Unix(f.Time, 0),
+				TileURL:  r.Host + f.Path + tiles,
+				Forecast: forecast,
+			})
+		}
+	}
+	if len(r.Satellite.Infrared) > 0 {
+		add(r.Satellite.Infrared, satelliteTiles, false)
+		return frames
+	}
+	add(r.Radar.Past, radarTiles, false)
+	add(r.Radar.Nowcast, radarTiles, true)
+	return frames
+}
+
+// =============================================================================
+// Service
+// =============================================================================
+
+// RainViewerService looks up the current cloud map frames from RainViewer.
+//
+// Usage:
+//
+//	service := weather.NewRainViewerService()
+//	frames, err := service.Frames()
+//	if err != nil {
+//	    // Show error
+//	}
+//	around := domain.CloudFramesAround(frames, sunTimes.GoldenEvening, time.Hour)
+type RainViewerService struct {
+	// client is the HTTP client used for API requests.
+	// Configured with a timeout from config.DefaultHTTPTimeout (10 seconds).
+	client *http.Client
+
+	// endpoint is the URL of the frame index (rainViewerEndpoint; a test
+	// server in tests).
+	endpoint string
+}
+
+// NewRainViewerService creates a new cloud frame service.
+//
+// Returns a ready-to-use RainViewerService instance.
+func NewRainViewerService() *RainViewerService {
+	return &RainViewerService{
+		client: &http.Client{
+			Timeout: config.DefaultHTTPTimeout,
+		},
+		endpoint: rainViewerEndpoint,
+	}
+}
+
+// Frames fetches the available cloud frames.
+//
+// This makes a network request and must be run in a background goroutine.
+// The tiles themselves are loaded by the map.
+//
+// Returns:
+//   - []domain.CloudFrame: The frames in chronological order
+//   - error: Non-nil if the request fails or lists no frames
+func (s *RainViewerService) Frames() ([]domain.CloudFrame, error) {
+	start := time.Now()
+	resp, err := s.client.Get(s.endpoint)
+	stats.RecordRequest(stats.OpRainViewer, time.Since(start), resp, err)
+	if err != nil {
+		return nil, fmt.Errorf("cloud map request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("cloud map request returned status %d", resp.StatusCode)
+	}
+
+	var result rainViewerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode cloud map response: %w", err)
+	}
+	frames := result.frames()
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("cloud map response lists no frames")
+	}
+	return frames, nil
+}
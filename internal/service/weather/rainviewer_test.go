@@ -0,0 +1,54 @@
+package weather
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFrames(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+	s := NewRainViewerService()
+	s.endpoint = server.URL
+
+	// Radar only: past frames, then the nowcast
+	body = `{"host": "https://tiles.example.com",
+		"radar": {"past": [{"time": 1000, "path": "/v2/radar/1000"}],
+		          "nowcast": [{"time": 1600, "path": "/v2/radar/nowcast_1600"}]},
+		"satellite": {"infrared": []}}`
+	frames, err := s.Frames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 2 || frames[0].Forecast || !frames[1].Forecast {
+		t.Fatalf("frames = %+v; want one past and one forecast frame", frames)
+	}
+	if want := "https://tiles.example.com/v2/radar/1000" + radarTiles; frames[0].TileURL != want {
+		t.Errorf("TileURL = %q, want %q", frames[0].TileURL, want)
+	}
+	if !frames[0].Time.Equal(time.Unix(1000, 0)) {
+		t.Errorf("Time = %v, want Unix 1000", frames[0].Time)
+	}
+
+	// Satellite frames are preferred
+	body = `{"host": "https://tiles.example.com",
+		"radar": {"past": [{"time": 1000, "path": "/v2/radar/1000"}]},
+		"satellite": {"infrared": [{"time": 1000, "path": "/v2/satellite/abc"}]}}`
+	frames, err = s.Frames()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 1 || frames[0].TileURL != "https://tiles.example.com/v2/satellite/abc"+satelliteTiles {
+		t.Errorf("frames = %+v; want the satellite frame", frames)
+	}
+
+	body = `{"host": "https://tiles.example.com"}`
+	if _, err := s.Frames(); err == nil {
+		t.Error("empty index: no error")
+	}
+}
@@ -0,0 +1,188 @@
+// Package state holds the application state shared between the UI and
+// background work.
+//
+// The App controller keeps the selected location, the date for
+// calculations and the user settings in a State. Background work (network
+// requests, the automation scheduler, searches) runs in goroutines and may
+// need to read that state while the user keeps changing it on the main
+// thread.
+//
+// # Thread Safety
+//
+// Reads are safe from any goroutine. Each getter returns a copy, and
+// Snapshot returns all values from the same moment, so a goroutine never
+// sees a location from one change and settings from another.
+//
+// Writes are made only by the App on the Qt main thread, so every change is
+// ordered with the UI update that shows it. A goroutine that wants to change
+// state hands the change to the main thread (mainthread.Wait) instead of
+// writing directly. The mutex makes the writes visible to readers; it does
+// not make concurrent writers safe to use, since their order would be
+// arbitrary.
+package state
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// State
+// =============================================================================
+
+// State is the App's location, date and settings, guarded for concurrent reads.
+//
+// Usage:
+//
+//	st := state.New(location, time.Now(), settings)
+//	go func() {
+//	    snap := st.Snapshot() // safe from any goroutine
+//	    // ... use snap.Location, snap.Date, snap.Settings
+//	}()
+//	st.SetDate(date) // main thread only
+type State struct {
+	// mu guards all fields below.
+	mu sync.RWMutex
+
+	// location is the currently selected geographic location.
+	location domain.Location
+
+	// date is the date for which solar times are calculated.
+	date time.Time
+
+	// rangeEnd is the last day of the planned date range starting at
+	// date; zero when a single day is planned.
+	rangeEnd time.Time
+
+	// settings are the user preferences, including the persisted last
+	// location, map zoom and automation hooks.
+	settings domain.Settings
+}
+
+// Snapshot is a consistent copy of the whole state.
+type Snapshot struct {
+	// Location is the currently selected geographic location.
+	Location domain.Location
+
+	// Date is the date for which solar times are calculated.
+	Date time.Time
+
+	// RangeEnd is the last day of the planned date range; zero when a
+	// single day is planned.
+	RangeEnd time.Time
+
+	// Settings are the user preferences.
+	Settings domain.Settings
+}
+
+// DateRange returns the planned days from Date to RangeEnd, and false if
+// a single day is planned.
+func (s Snapshot) DateRange() (domain.DateRange, bool) {
+	if s.RangeEnd.IsZero() {
+		return domain.NewDateRange(s.Date, s.Date), false
+	}
+	return domain.NewDateRange(s.Date, s.RangeEnd), true
+}
+
+// New creates a State with the given initial values.
+func New(location domain.Location, date time.Time, settings domain.Settings) *State {
+	return &State{location: location, date: date, settings: cloneSettings(settings)}
+}
+
+// Snapshot returns a copy of the location, date and settings taken at the
+// same moment.
+func (s *State) Snapshot() Snapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return Snapshot{Location: s.location, Date: s.date, RangeEnd: s.rangeEnd, Settings: cloneSettings(s.settings)}
+}
+
+// Location returns the currently selected location.
+func (s *State) Location() domain.Location {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.location
+}
+
+// Date returns the date for calculations.
+func (s *State) Date() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.date
+}
+
+// Settings returns a copy of the user settings.
+//
+// The copy doesn't share the hooks, favorites, recent locations or last
+// location with the state, so callers may modify it freely.
+func (s *State) Settings() domain.Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return cloneSettings(s.settings)
+}
+
+// SetLocation changes the selected location. Main thread only.
+func (s *State) SetLocation(location domain.Location) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.location = location
+}
+
+// SetDate changes the date for calculations. Main thread only.
+func (s *State) SetDate(date time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.date = date
+}
+
+// SetRangeEnd changes the last day of the planned date range; zero plans
+// a single day. Main thread only.
+func (s *State) SetRangeEnd(end time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rangeEnd = end
+}
+
+// UpdateSettings changes the settings in one step and returns the result.
+// Main thread only.
+//
+// change receives the current settings and modifies them in place. Readers
+// see either the old or the new settings, never a mix of both.
+//
+// Example:
+//
+//	st.UpdateSettings(func(s *domain.Settings) {
+//	    s.MapZoom = zoom
+//	})
+func (s *State) UpdateSettings(change func(settings *domain.Settings)) domain.Settings {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	settings := cloneSettings(s.settings)
+	change(&settings)
+	s.settings = cloneSettings(settings)
+	return settings
+}
+
+// cloneSettings copies the parts of settings that are shared by reference.
+func cloneSettings(settings domain.Settings) domain.Settings {
+	if settings.LastLocation != nil {
+		loc := *settings.LastLocation
+		settings.LastLocation = &loc
+	}
+	if settings.HomeLocation != nil {
+		loc := *settings.HomeLocation
+		settings.HomeLocation = &loc
+	}
+	settings.RecentLocations = slices.Clone(settings.RecentLocations)
+	settings.Hooks = slices.Clone(settings.Hooks)
+	settings.Favorites = slices.Clone(settings.Favorites)
+	settings.DailySummary.Favorites = slices.Clone(settings.DailySummary.Favorites)
+	settings.Push.Events = slices.Clone(settings.Push.Events)
+	settings.SearchBias.Countries = slices.Clone(settings.SearchBias.Countries)
+	settings.LocationProviders = slices.Clone(settings.LocationProviders)
+	settings.PanelLayout = slices.Clone(settings.PanelLayout)
+	return settings
+}
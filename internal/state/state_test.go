@@ -0,0 +1,105 @@
+package state
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSettingsAreCopies(t *testing.T) {
+	settings := domain.DefaultSettings()
+	settings.LastLocation = &domain.Location{Name: "Paris"}
+	settings.Hooks = []domain.Hook{{Command: "first"}}
+	st := New(domain.DefaultLocation(), time.Now(), settings)
+
+	// Changing the caller's values must not reach the state
+	settings.LastLocation.Name = "changed"
+	settings.Hooks[0].Command = "changed"
+
+	got := st.Settings()
+	if got.LastLocation.Name != "Paris" || got.Hooks[0].Command != "first" {
+		t.Fatalf("state shares values with New's argument: %+v", got)
+	}
+
+	// Nor must changing a returned copy
+	got.LastLocation.Name = "changed"
+	got.Hooks[0].Command = "changed"
+	if again := st.Snapshot().Settings; again.LastLocation.Name != "Paris" || again.Hooks[0].Command != "first" {
+		t.Fatalf("state shares values with a returned copy: %+v", again)
+	}
+}
+
+func TestUpdateSettings(t *testing.T) {
+	st := New(domain.DefaultLocation(), time.Now(), domain.DefaultSettings())
+
+	var kept []domain.Hook
+	result := st.UpdateSettings(func(s *domain.Settings) {
+		s.MapZoom = 7
+		s.Hooks = append(s.Hooks, domain.Hook{Command: "echo"})
+		kept = s.Hooks
+	})
+	if result.MapZoom != 7 || len(result.Hooks) != 1 {
+		t.Fatalf("UpdateSettings returned %+v", result)
+	}
+
+	// Holding on to the slice seen by change must not alias the state
+	kept[0].Command = "changed"
+	if got := st.Settings(); got.MapZoom != 7 || got.Hooks[0].Command != "echo" {
+		t.Errorf("Settings after update = %+v", got)
+	}
+}
+
+// TestConcurrentAccess is meant for the race detector (go test -race):
+// readers in goroutines while the "main thread" keeps writing.
+func TestConcurrentAccess(t *testing.T) {
+	st := New(domain.DefaultLocation(), time.Now(), domain.DefaultSettings())
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				snap := st.Snapshot()
+				if zoom := snap.Settings.MapZoom; zoom < 1 || zoom > 19 {
+					t.Errorf("MapZoom = %d", zoom)
+					return
+				}
+				_ = st.Location()
+				_ = st.Date()
+			}
+		}()
+	}
+
+	for i := 0; i < 200; i++ {
+		st.SetLocation(domain.Location{Latitude: float64(i%180 - 90)})
+		st.SetDate(time.Now().AddDate(0, 0, i))
+		st.UpdateSettings(func(s *domain.Settings) {
+			s.MapZoom = i%19 + 1
+			s.Hooks = append(s.Hooks, domain.Hook{Command: "echo"})
+		})
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestSnapshotDateRange(t *testing.T) {
+	start := time.Date(2025, 6, 20, 0, 0, 0, 0, time.UTC)
+	st := New(domain.DefaultLocation(), start, domain.DefaultSettings())
+	if r, ok := st.Snapshot().DateRange(); ok || r.Days() != 1 {
+		t.Errorf("without a range end = %d days, %v; want a single day", r.Days(), ok)
+	}
+
+	st.SetRangeEnd(start.AddDate(0, 0, 6))
+	if r, ok := st.Snapshot().DateRange(); !ok || r.Days() != 7 || !r.Start.Equal(start) {
+		t.Errorf("with a range end = %v, %d days, %v; want a week from the date", r.Start, r.Days(), ok)
+	}
+}
@@ -0,0 +1,229 @@
+// Package stats counts calculations, cache lookups and web service calls.
+//
+// The counts back the statistics dialog (Debug → Statistics), which shows
+// how much work the app does behind the scenes: how many sun times were
+// calculated, how often the caches answered, and how many requests went
+// to the internet and how long they took. Users on metered connections can
+// see what they pay for, and developers can tell whether a cache helps.
+//
+// # Recording
+//
+// Services record into one process-wide registry, so every instance counts,
+// including the private calculators that background goroutines create:
+//
+//	start := time.Now()
+//	resp, err := client.Do(req)
+//	stats.RecordRequest(stats.OpNominatim, time.Since(start), resp, err)
+//
+//	stats.CacheLookup(stats.CacheGeocodingSearch, hit)
+//
+// Recording is cheap (a mutex and a map lookup) and safe from any
+// goroutine. Nothing is persisted; the counts start at zero on every launch
+// and when the user resets them.
+package stats
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// =============================================================================
+// Names
+// =============================================================================
+
+// Operations, as shown in the statistics dialog. Web service calls name
+// the service, so they can be told apart from local work.
+const (
+	OpSunTimes    = "Sun times calculation"
+	OpNominatim   = "Nominatim request (place search)"
+	OpOpenMeteo   = "Open-Meteo request (elevation)"
+	OpRainViewer  = "RainViewer request (cloud layer)"
+	OpForecast    = "Open-Meteo request (weather forecast)"
+	OpSWPC        = "NOAA SWPC request (aurora forecast)"
+	OpIPAPI       = "IP-API request (location)"
+	OpIPInfo      = "ipinfo.io request (location)"
+	OpPushMessage = "Push notification (ntfy/Pushover)"
+	OpMapTile     = "Tile server request (map)"
+	OpCalDAV      = "CalDAV request (calendar sync)"
+	OpWebhook     = "Webhook request (automation)"
+	OpGitHub      = "GitHub request (update check)"
+)
+
+// Caches, as shown in the statistics dialog.
+const (
+	CacheGeocodingSearch  = "Place searches"
+	CacheGeocodingReverse = "Map click names"
+	CacheMapTiles         = "Map tiles"
+	CacheForecasts        = "Weather forecasts"
+	CacheKpForecast       = "Aurora forecast"
+	CacheSunTimes         = "Sun times"
+)
+
+// =============================================================================
+// Report
+// =============================================================================
+
+// Operation is the record of one kind of operation.
+type Operation struct {
+	// Name identifies the operation (one of the Op constants).
+	Name string
+
+	// Count is how often it ran; Errors how many of those failed.
+	Count  int
+	Errors int
+
+	// Total and Slowest are the summed and longest durations.
+	Total   time.Duration
+	Slowest time.Duration
+}
+
+// Average returns the mean duration, or 0 if the operation never ran.
+func (o Operation) Average() time.Duration {
+	if o.Count == 0 {
+		return 0
+	}
+	return o.Total / time.Duration(o.Count)
+}
+
+// Cache is the record of lookups in one cache.
+type Cache struct {
+	// Name identifies the cache (one of the Cache constants).
+	Name string
+
+	// Hits are lookups the cache answered; Misses those it couldn't.
+	Hits   int
+	Misses int
+}
+
+// HitRate returns the share of lookups answered (0 to 1), or 0 without
+// lookups.
+func (c Cache) HitRate() float64 {
+	if c.Hits+c.Misses == 0 {
+		return 0
+	}
+	return float64(c.Hits) / float64(c.Hits+c.Misses)
+}
+
+// Report is a snapshot of all records.
+type Report struct {
+	// Since is when counting started (launch or the last Reset).
+	Since time.Time
+
+	// Operations and Caches are sorted by name.
+	Operations []Operation
+	Caches     []Cache
+}
+
+// =============================================================================
+// Registry
+// =============================================================================
+
+// registry holds the records of the process.
+var registry = struct {
+	mu         sync.Mutex
+	since      time.Time
+	operations map[string]*Operation
+	caches     map[string]*Cache
+}{
+	since:      time.Now(),
+	operations: make(map[string]*Operation),
+	caches:     make(map[string]*Cache),
+}
+
+// Record adds one run of an operation that took elapsed and failed if err
+// is non-nil.
+func Record(name string, elapsed time.Duration, err error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	op := registry.operations[name]
+	if op == nil {
+		op = &Operation{Name: name}
+		registry.operations[name] = op
+	}
+	op.Count++
+	if err != nil {
+		op.Errors++
+	}
+	op.Total += elapsed
+	op.Slowest = max(op.Slowest, elapsed)
+}
+
+// RecordRequest adds one web service request that took elapsed, as
+// returned by http.Client.Do. It failed if err is non-nil or the status
+// is not 200 OK.
+func RecordRequest(name string, elapsed time.Duration, resp *http.Response, err error) {
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("status %d", resp.StatusCode)
+	}
+	Record(name, elapsed, err)
+}
+
+// CacheLookup adds one lookup in a cache, a hit if the cache answered it.
+func CacheLookup(name string, hit bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	c := registry.caches[name]
+	if c == nil {
+		c = &Cache{Name: name}
+		registry.caches[name] = c
+	}
+	if hit {
+		c.Hits++
+	} else {
+		c.Misses++
+	}
+}
+
+// Snapshot returns the current records.
+func Snapshot() Report {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	report := Report{Since: registry.since}
+	for _, op := range registry.operations {
+		report.Operations = append(report.Operations, *op)
+	}
+	for _, c := range registry.caches {
+		report.Caches = append(report.Caches, *c)
+	}
+	sort.Slice(report.Operations, func(i, j int) bool {
+		return report.Operations[i].Name < report.Operations[j].Name
+	})
+	sort.Slice(report.Caches, func(i, j int) bool {
+		return report.Caches[i].Name < report.Caches[j].Name
+	})
+	return report
+}
+
+// Reset clears all records and restarts counting now.
+func Reset() {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.since = time.Now()
+	registry.operations = make(map[string]*Operation)
+	registry.caches = make(map[string]*Cache)
+}
+
+// =============================================================================
+// Formatting
+// =============================================================================
+
+// FormatLatency formats a duration for the statistics dialog with a
+// precision that suits its size: "0.04 ms", "3.2 ms", "850 ms", "1.4 s".
+func FormatLatency(d time.Duration) string {
+	ms := float64(d) / float64(time.Millisecond)
+	switch {
+	case d == 0:
+		return "-"
+	case ms < 1:
+		return fmt.Sprintf("%.2f ms", ms)
+	case ms < 100:
+		return fmt.Sprintf("%.1f ms", ms)
+	case ms < 1000:
+		return fmt.Sprintf("%.0f ms", ms)
+	default:
+		return fmt.Sprintf("%.1f s", ms/1000)
+	}
+}
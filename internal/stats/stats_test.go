@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	Reset()
+	Record(OpNominatim, 100*time.Millisecond, nil)
+	Record(OpNominatim, 300*time.Millisecond, errors.New("timeout"))
+	Record(OpSunTimes, time.Millisecond, nil)
+	CacheLookup(CacheGeocodingSearch, true)
+	CacheLookup(CacheGeocodingSearch, true)
+	CacheLookup(CacheGeocodingSearch, true)
+	CacheLookup(CacheGeocodingSearch, false)
+
+	report := Snapshot()
+	if len(report.Operations) != 2 || report.Operations[0].Name != OpNominatim {
+		t.Fatalf("operations = %+v, want Nominatim and sun times in name order", report.Operations)
+	}
+	op := report.Operations[0]
+	if op.Count != 2 || op.Errors != 1 || op.Average() != 200*time.Millisecond || op.Slowest != 300*time.Millisecond {
+		t.Errorf("Nominatim = %+v (average %v)", op, op.Average())
+	}
+	if len(report.Caches) != 1 || report.Caches[0].HitRate() != 0.75 {
+		t.Errorf("caches = %+v, want a 75%% hit rate", report.Caches)
+	}
+
+	Reset()
+	if report := Snapshot(); len(report.Operations) != 0 || len(report.Caches) != 0 {
+		t.Errorf("after Reset: %+v", report)
+	}
+	if (Operation{}).Average() != 0 || (Cache{}).HitRate() != 0 {
+		t.Error("empty records should average to zero")
+	}
+}
+
+func TestFormatLatency(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "-"},
+		{40 * time.Microsecond, "0.04 ms"},
+		{3200 * time.Microsecond, "3.2 ms"},
+		{850 * time.Millisecond, "850 ms"},
+		{1400 * time.Millisecond, "1.4 s"},
+	}
+	for _, tt := range tests {
+		if got := FormatLatency(tt.d); got != tt.want {
+			t.Errorf("FormatLatency(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
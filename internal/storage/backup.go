@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Backup and Recovery
+// =============================================================================
+
+// Corrupt reports whether the last Load found the settings file damaged
+// (not valid JSON) and returned the defaults instead.
+//
+// The App then offers to restore the backup or to start over with the
+// defaults (see Recover). The damaged file itself is moved aside to
+// settings.json.corrupt by the next save.
+func (s *PreferencesStore) Corrupt() bool {
+	return s.corrupt
+}
+
+// HasBackup reports whether there is a backup of the settings
+// (settings.json.bak) to restore.
+func (s *PreferencesStore) HasBackup() bool {
+	_, err := os.Stat(s.backupPath())
+	return err == nil
+}
+
+// Recover ends the recovery after a damaged settings file was loaded (see
+// Corrupt), with the user's choice:
+//   - fromBackup true: The settings of the backup are returned (migrated
+//     and validated, like Load's)
+//   - fromBackup false: The defaults are returned
+//
+// Either way, later saves rotate the backup again. The settings aren't
+// written; the caller saves them once they are applied.
+//
+// Returns an error if the backup is missing or damaged as well; nothing
+// changes then, so the defaults can still be chosen.
+func (s *PreferencesStore) Recover(fromBackup bool) (domain.Settings, error) {
+	settings := domain.DefaultSettings()
+	if fromBackup {
+		data, err := os.ReadFile(s.backupPath())
+		if err != nil {
+			if os.IsNotExist(err) {
+				return domain.Settings{}, errors.New("there is no backup of the settings")
+			}
+			return domain.Settings{}, fmt.Errorf("failed to read the backup: %w", err)
+		}
+		if settings, err = parseSettings(data); err != nil {
+			return domain.Settings{}, fmt.Errorf("the backup is damaged too: %w", err)
+		}
+	}
+	s.corrupt = false
+	return settings, nil
+}
+
+// backupPath returns the path of the backup, settings.json.bak.
+func (s *PreferencesStore) backupPath() string {
+	return s.configPath + backupSuffix
+}
+
+// rotateBackup keeps the current settings file as the backup before it is
+// replaced (see Save).
+//
+// A valid file is copied to the backup, itself written atomically, so the
+// settings file stays in place should the save fail. A damaged file is
+// renamed to settings.json.corrupt, and while the store is Corrupt the
+// backup isn't replaced at all. Nothing happens before the first save.
+func (s *PreferencesStore) rotateBackup() error {
+	data, err := os.ReadFile(s.configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !json.Valid(data) {
+		// The damaged file may still hold passwords; an older version
+		// wrote it readable by all
+		if err := os.Rename(s.configPath, s.configPath+corruptSuffix); err != nil {
+			return err
+		}
+		return os.Chmod(s.configPath+corruptSuffix, settingsFileMode)
+	}
+	if s.corrupt {
+		return nil
+	}
+	// The backup holds the same secrets as the settings file
+	return writeFileAtomic(s.backupPath(), data, settingsFileMode)
+}
+
+// writeFileAtomic writes data to a file so that the file is either
+// unchanged or completely replaced, even after a crash: the data goes to a
+// temporary file in the same directory (a rename can't cross filesystems),
+// which is flushed to disk and then renamed over the file.
+//
+// Parameters:
+//   - path: The file to write
+//   - data: The new contents
+//   - perm: Permissions of the file
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	// Removing fails harmlessly once the file has been renamed
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	// Without Sync, a power cut soon after the rename may leave an empty
+	// file on some filesystems
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	// CreateTemp makes the file readable by the owner only
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSaveRotatesBackup(t *testing.T) {
+	dir := t.TempDir()
+	store := &PreferencesStore{configPath: filepath.Join(dir, configFileName)}
+
+	first := domain.DefaultSettings()
+	first.GoldenHourElevation = 7
+	if err := store.Save(first); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if store.HasBackup() {
+		t.Error("HasBackup() = true after the first save")
+	}
+
+	second := first
+	second.GoldenHourElevation = 9
+	if err := store.Save(second); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	restored, err := store.Recover(true)
+	if err != nil {
+		t.Fatalf("Recover(true) error: %v", err)
+	}
+	if restored.GoldenHourElevation != 7 {
+		t.Errorf("backup golden hour elevation = %v, want 7 (the previous save)", restored.GoldenHourElevation)
+	}
+
+	// No temporary files are left behind
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Errorf("directory has %d files, want settings.json and its backup", len(entries))
+	}
+}
+
+func TestLoadCorruptKeepsBackup(t *testing.T) {
+	store := &PreferencesStore{configPath: filepath.Join(t.TempDir(), configFileName)}
+
+	good := domain.DefaultSettings()
+	good.GoldenHourElevation = 8
+	for range 2 {
+		if err := store.Save(good); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+	if err := os.WriteFile(store.configPath, []byte(`{"golden_hour_elevation": 8,`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	settings, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !store.Corrupt() || settings.GoldenHourElevation != domain.DefaultSettings().GoldenHourElevation {
+		t.Fatalf("Load() of a damaged file: Corrupt() = %v, elevation %v; want true, defaults",
+			store.Corrupt(), settings.GoldenHourElevation)
+	}
+
+	// Saving the defaults meanwhile must not replace the good backup
+	for range 2 {
+		if err := store.Save(settings); err != nil {
+			t.Fatalf("Save() error: %v", err)
+		}
+	}
+	if _, err := os.Stat(store.configPath + corruptSuffix); err != nil {
+		t.Errorf("damaged file not set aside: %v", err)
+	}
+
+	restored, err := store.Recover(true)
+	if err != nil {
+		t.Fatalf("Recover(true) error: %v", err)
+	}
+	if restored.GoldenHourElevation != 8 || store.Corrupt() {
+		t.Errorf("Recover(true): elevation %v, Corrupt() = %v; want 8, false",
+			restored.GoldenHourElevation, store.Corrupt())
+	}
+}
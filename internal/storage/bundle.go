@@ -0,0 +1,247 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Configuration Bundles
+// =============================================================================
+
+// Configuration bundle file format.
+const (
+	// bundleFormat identifies a configuration bundle, so that other JSON
+	// files (e.g., a single settings.json) are rejected on import.
+	bundleFormat = "gogoldenhour-configuration"
+
+	// bundleVersion is the version of the bundle layout. Bundles of a newer
+	// version are rejected rather than imported incompletely.
+	bundleVersion = 1
+
+	// maxBundleSize limits the file read on import; a bundle of many
+	// profiles with hundreds of favorites each stays well below it.
+	maxBundleSize = 16 << 20
+)
+
+// ConfigBundle is the whole configuration of the app on one machine, for a
+// backup or for moving to another machine: the settings of every profile,
+// each with its favorites (the map's markers), presets, automation and
+// logins.
+//
+// A bundle is a JSON file (see ExportConfigBundle):
+//
+//	{
+//	  "format": "gogoldenhour-configuration",
+//	  "version": 1,
+//	  "exported": "2026-10-14T18:30:00Z",
+//	  "profiles": [
+//	    {"name": "", "settings": {"golden_hour_elevation": 6, ...}},
+//	    {"name": "work", "settings": {...}}
+//	  ]
+//	}
+//
+// The settings are those of settings.json, passwords and API keys
+// included, so the file is only readable by its owner.
+type ConfigBundle struct {
+	// Exported is when the bundle was written.
+	Exported time.Time
+
+	// Profiles holds the settings of each profile, the default profile
+	// (DefaultProfile) first.
+	Profiles []BundledProfile
+}
+
+// BundledProfile is one profile of a ConfigBundle.
+type BundledProfile struct {
+	// Name is the profile name, DefaultProfile ("") for the default profile.
+	Name string
+
+	// Settings are the profile's settings, migrated and validated like
+	// loaded ones (see ReadConfigBundle for what is left out on import).
+	Settings domain.Settings
+}
+
+// bundleFile is the JSON layout of a ConfigBundle.
+type bundleFile struct {
+	Format   string          `json:"format"`
+	Version  int             `json:"version"`
+	Exported time.Time       `json:"exported"`
+	Profiles []bundleProfile `json:"profiles"`
+}
+
+// bundleProfile is the JSON layout of a BundledProfile. The settings are
+// kept raw on import, so parseSettings can migrate them like a file.
+type bundleProfile struct {
+	Name     string          `json:"name"`
+	Settings json.RawMessage `json:"settings"`
+}
+
+// ExportConfigBundle writes the settings of every profile on this machine
+// to a configuration bundle (see ConfigBundle).
+//
+// Profiles without a settings file yet are left out. Damaged settings files
+// fail the export, so a backup never silently lacks a profile.
+//
+// Parameters:
+//   - path: Destination file (overwritten if it exists; written atomically
+//     with 0600 permissions)
+//   - now: The export time recorded in the bundle
+//
+// Returns the number of profiles written, or an error if a settings file
+// can't be read or the bundle can't be written.
+func ExportConfigBundle(path string, now time.Time) (int, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return exportConfigBundle(filepath.Join(configDir, configDirName), path, now)
+}
+
+// exportConfigBundle exports the profiles within appDir (see
+// ExportConfigBundle).
+func exportConfigBundle(appDir, path string, now time.Time) (int, error) {
+	names, err := listProfiles(appDir)
+	if err != nil {
+		return 0, err
+	}
+
+	file := bundleFile{Format: bundleFormat, Version: bundleVersion, Exported: now.UTC()}
+	for _, name := range append([]string{DefaultProfile}, names...) {
+		data, err := os.ReadFile(filepath.Join(profileDir(appDir, name), configFileName))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return 0, fmt.Errorf("failed to read the settings of %s: %w", profileLabel(name), err)
+		}
+		settings, err := parseSettings(data)
+		if err != nil {
+			return 0, fmt.Errorf("the settings of %s are damaged: %w", profileLabel(name), err)
+		}
+		settings.SchemaVersion = max(settings.SchemaVersion, domain.SettingsSchemaVersion)
+		raw, err := json.Marshal(settings)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal settings: %w", err)
+		}
+		file.Profiles = append(file.Profiles, bundleProfile{Name: name, Settings: raw})
+	}
+	if len(file.Profiles) == 0 {
+		return 0, errors.New("there are no settings to export yet")
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	// Owner only: the settings hold passwords and API keys
+	if err := writeFileAtomic(path, data, 0600); err != nil {
+		return 0, fmt.Errorf("failed to write configuration: %w", err)
+	}
+	return len(file.Profiles), nil
+}
+
+// ReadConfigBundle reads and validates a configuration bundle written by
+// ExportConfigBundle, without changing any settings.
+//
+// The checks are:
+//   - The file is a bundle ("format") of a version this app can read
+//   - It holds at least one profile; names pass ValidateProfileName and
+//     are unique, ignoring case (profile directories may be on a
+//     case-insensitive file system)
+//   - Each profile's settings are valid settings JSON; they are migrated
+//     and validated like a loaded settings file
+//
+// Approvals of automation commands (domain.Hook.Confirmed) aren't taken
+// over: a file from elsewhere could approve any command, so imported hooks
+// only run once the user has confirmed them in the preferences dialog.
+//
+// Returns the bundle, or a descriptive error (suitable for display) for the
+// first problem found.
+func ReadConfigBundle(path string) (ConfigBundle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ConfigBundle{}, fmt.Errorf("failed to open configuration: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, maxBundleSize+1))
+	if err != nil {
+		return ConfigBundle{}, fmt.Errorf("failed to read configuration: %w", err)
+	}
+	if len(data) > maxBundleSize {
+		return ConfigBundle{}, errors.New("the file is too large for a configuration")
+	}
+	return parseConfigBundle(data)
+}
+
+// parseConfigBundle decodes and validates a bundle (see ReadConfigBundle).
+func parseConfigBundle(data []byte) (ConfigBundle, error) {
+	var file bundleFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return ConfigBundle{}, fmt.Errorf("not a configuration file: %w", err)
+	}
+	if file.Format != bundleFormat {
+		return ConfigBundle{}, errors.New("not a GoGoldenHour configuration file (use Export Settings to create one)")
+	}
+	if file.Version > bundleVersion {
+		return ConfigBundle{}, fmt.Errorf("the configuration was exported by a newer version of the app (format %d)", file.Version)
+	}
+	if len(file.Profiles) == 0 {
+		return ConfigBundle{}, errors.New("the configuration holds no profiles")
+	}
+
+	bundle := ConfigBundle{Exported: file.Exported}
+	for _, p := range file.Profiles {
+		if err := ValidateProfileName(p.Name); err != nil {
+			return ConfigBundle{}, err
+		}
+		for _, seen := range bundle.Profiles {
+			if strings.EqualFold(seen.Name, p.Name) {
+				return ConfigBundle{}, fmt.Errorf("%s is in the configuration twice", profileLabel(p.Name))
+			}
+		}
+		if len(p.Settings) == 0 {
+			return ConfigBundle{}, fmt.Errorf("%s has no settings", profileLabel(p.Name))
+		}
+		settings, err := parseSettings(p.Settings)
+		if err != nil {
+			return ConfigBundle{}, fmt.Errorf("the settings of %s are invalid: %w", profileLabel(p.Name), err)
+		}
+		for i := range settings.Hooks {
+			settings.Hooks[i].Confirmed = ""
+		}
+		bundle.Profiles = append(bundle.Profiles, BundledProfile{Name: p.Name, Settings: settings})
+	}
+	return bundle, nil
+}
+
+// SaveProfile replaces the settings of a profile with the given ones, e.g.,
+// from an imported ConfigBundle. The profile is created if it doesn't exist,
+// and its previous settings file becomes the backup, as with Save.
+//
+// Don't use it for the profile the app is running: its store would
+// overwrite the file with the app's settings on the next save.
+func SaveProfile(profile string, settings domain.Settings) error {
+	store, err := NewPreferencesStore(profile)
+	if err != nil {
+		return err
+	}
+	return store.Save(settings)
+}
+
+// profileLabel names a profile in messages: "the default profile" or
+// "profile \"work\"".
+func profileLabel(name string) string {
+	if name == DefaultProfile {
+		return "the default profile"
+	}
+	return fmt.Sprintf("profile %q", name)
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestConfigBundleRoundTrip(t *testing.T) {
+	appDir := t.TempDir()
+	for name, elevation := range map[string]float64{DefaultProfile: 7, "work": 9} {
+		dir := profileDir(appDir, name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		settings := domain.DefaultSettings()
+		settings.GoldenHourElevation = elevation
+		settings.Favorites = []domain.Favorite{{ID: "f1", Location: domain.Location{Name: "Bridge", Latitude: 48, Longitude: 2}}}
+		store := &PreferencesStore{configPath: filepath.Join(dir, configFileName)}
+		if err := store.Save(settings); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A profile that was never saved is left out
+	if err := os.MkdirAll(profileDir(appDir, "empty"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	now := time.Date(2026, 10, 14, 18, 30, 0, 0, time.UTC)
+	n, err := exportConfigBundle(appDir, path, now)
+	if err != nil || n != 2 {
+		t.Fatalf("exportConfigBundle() = %d, %v; want 2 profiles", n, err)
+	}
+	if info, err := os.Stat(path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("bundle permissions = %v, want 0600", info.Mode().Perm())
+	}
+
+	bundle, err := ReadConfigBundle(path)
+	if err != nil {
+		t.Fatalf("ReadConfigBundle() error: %v", err)
+	}
+	if !bundle.Exported.Equal(now) || len(bundle.Profiles) != 2 {
+		t.Fatalf("bundle exported %v with %d profiles, want %v and 2", bundle.Exported, len(bundle.Profiles), now)
+	}
+	first, second := bundle.Profiles[0], bundle.Profiles[1]
+	if first.Name != DefaultProfile || first.Settings.GoldenHourElevation != 7 {
+		t.Errorf("first profile %q at %v°, want the default profile at 7°", first.Name, first.Settings.GoldenHourElevation)
+	}
+	if second.Name != "work" || second.Settings.GoldenHourElevation != 9 || len(second.Settings.Favorites) != 1 {
+		t.Errorf("second profile %q at %v° with %d favorites, want work at 9° with 1",
+			second.Name, second.Settings.GoldenHourElevation, len(second.Settings.Favorites))
+	}
+}
+
+func TestParseConfigBundleRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"not JSON", `{"format":`, "not a configuration file"},
+		{"settings file", `{"golden_hour_elevation": 6}`, "not a GoGoldenHour configuration"},
+		{"newer version", `{"format":"gogoldenhour-configuration","version":2,"profiles":[{"name":"","settings":{}}]}`, "newer version"},
+		{"no profiles", `{"format":"gogoldenhour-configuration","version":1,"profiles":[]}`, "no profiles"},
+		{"bad name", `{"format":"gogoldenhour-configuration","version":1,"profiles":[{"name":"a/b","settings":{}}]}`, "contains"},
+		{"duplicate", `{"format":"gogoldenhour-configuration","version":1,"profiles":[` +
+			`{"name":"Work","settings":{}},{"name":"work","settings":{}}]}`, "twice"},
+		{"no settings", `{"format":"gogoldenhour-configuration","version":1,"profiles":[{"name":""}]}`, "no settings"},
+		{"bad settings", `{"format":"gogoldenhour-configuration","version":1,"profiles":[` +
+			`{"name":"","settings":{"golden_hour_elevation":"high"}}]}`, "invalid"},
+	}
+	for _, tt := range tests {
+		_, err := parseConfigBundle([]byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: parseConfigBundle() error = %v, want it to mention %q", tt.name, err, tt.want)
+		}
+	}
+
+	// Values are validated like a loaded settings file, and commands need
+	// to be approved again
+	hook := domain.Hook{Enabled: true, Command: "rm -rf ~", Confirmed: domain.CommandFingerprint("rm -rf ~")}
+	settings := domain.DefaultSettings()
+	settings.GoldenHourElevation = 99
+	settings.Hooks = []domain.Hook{hook}
+	raw, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle, err := parseConfigBundle([]byte(`{"format":"gogoldenhour-configuration","version":1,` +
+		`"profiles":[{"name":"","settings":` + string(raw) + `}]}`))
+	if err != nil {
+		t.Fatalf("parseConfigBundle() error: %v", err)
+	}
+	imported := bundle.Profiles[0].Settings
+	if imported.GoldenHourElevation > 15 {
+		t.Errorf("golden hour elevation = %v, want it clamped", imported.GoldenHourElevation)
+	}
+	if len(imported.Hooks) != 1 || imported.Hooks[0].IsConfirmed() {
+		t.Errorf("imported hooks = %+v, want one unconfirmed hook", imported.Hooks)
+	}
+}
@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Config Codes
+// =============================================================================
+
+// configCodePrefix identifies a GoGoldenHour config code and its format version.
+//
+// The version number lets future releases change the payload layout while
+// still recognizing (and rejecting or migrating) codes from older versions.
+const configCodePrefix = "GGH1-"
+
+// ErrInvalidConfigCode is returned when a pasted string is not a valid config code.
+var ErrInvalidConfigCode = errors.New("invalid config code")
+
+// configCodePayload is the compact JSON structure embedded in a config code.
+//
+// Only settings that describe how times are calculated and displayed are
+// included. Personal data (last location, auto-detect preference) is left
+// out so that sharing a code never leaks where the sender is.
+//
+// Short JSON keys keep the resulting code compact. Pointer fields allow a
+// code to carry only a subset of values; missing fields leave the receiver's
+// current value untouched.
+type configCodePayload struct {
+	GoldenHourElevation *float64 `json:"g,omitempty"`
+	BlueHourStart       *float64 `json:"bs,omitempty"`
+	BlueHourEnd         *float64 `json:"be,omitempty"`
+	TimeFormat24Hour    *bool    `json:"h24,omitempty"`
+	TeachingMode        *bool    `json:"tm,omitempty"`
+}
+
+// EncodeConfigCode converts the shareable parts of the settings into a config code.
+//
+// A config code is a short, URL-safe string that can be pasted into a chat,
+// email, or slide so that another user can replicate the same elevation
+// presets and display options. This is intended for workshop instructors
+// who want every participant to use identical golden/blue hour definitions
+// (and, optionally, have teaching mode switched on).
+//
+// Format: "GGH1-" followed by unpadded base64url-encoded compact JSON.
+//
+// Example:
+//
+//	code := storage.EncodeConfigCode(settings)
+//	// code = "GGH1-eyJnIjo2LCJicyI6LTQsImJlIjotOCwiaDI0Ijp0cnVlLCJ0bSI6ZmFsc2V9"
+func EncodeConfigCode(settings domain.Settings) string {
+	payload := configCodePayload{
+		GoldenHourElevation: &settings.GoldenHourElevation,
+		BlueHourStart:       &settings.BlueHourStart,
+		BlueHourEnd:         &settings.BlueHourEnd,
+		TimeFormat24Hour:    &settings.TimeFormat24Hour,
+		TeachingMode:        &settings.TeachingMode,
+	}
+
+	// Marshalling a struct of plain numbers and booleans cannot fail
+	data, _ := json.Marshal(payload)
+	return configCodePrefix + base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeConfigCode applies a config code on top of the given base settings.
+//
+// Values present in the code replace the corresponding values in base; all
+// other settings (last location, auto-detect, etc.) are preserved. The
+// result is validated so that a hand-crafted code cannot push elevation
+// angles outside their supported ranges.
+//
+// Surrounding whitespace is ignored, which makes pasting from chat clients
+// that add trailing newlines forgiving.
+//
+// Parameters:
+//   - code: The config code string (e.g., "GGH1-eyJnIjo2...")
+//   - base: The settings to start from (normally the current settings)
+//
+// Returns:
+//   - domain.Settings: base with the code's values applied and validated
+//   - error: ErrInvalidConfigCode (wrapped) if the code cannot be parsed
+func DecodeConfigCode(code string, base domain.Settings) (domain.Settings, error) {
+	code = strings.TrimSpace(code)
+	if !strings.HasPrefix(code, configCodePrefix) {
+		return base, fmt.Errorf("%w: missing %q prefix", ErrInvalidConfigCode, configCodePrefix)
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(code, configCodePrefix))
+	if err != nil {
+		return base, fmt.Errorf("%w: %v", ErrInvalidConfigCode, err)
+	}
+
+	var payload configCodePayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return base, fmt.Errorf("%w: %v", ErrInvalidConfigCode, err)
+	}
+
+	// Apply only the values that were included in the code
+	settings := base
+	if payload.GoldenHourElevation != nil {
+		settings.GoldenHourElevation = *payload.GoldenHourElevation
+	}
+	if payload.BlueHourStart != nil {
+		settings.BlueHourStart = *payload.BlueHourStart
+	}
+	if payload.BlueHourEnd != nil {
+		settings.BlueHourEnd = *payload.BlueHourEnd
+	}
+	if payload.TimeFormat24Hour != nil {
+		settings.TimeFormat24Hour = *payload.TimeFormat24Hour
+	}
+	if payload.TeachingMode != nil {
+		settings.TeachingMode = *payload.TeachingMode
+	}
+
+	// Clamp values the same way settings loaded from disk are clamped
+	settings.Validate()
+
+	return settings, nil
+}
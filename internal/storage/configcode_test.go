@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// codeFor builds a config code from a raw JSON payload.
+func codeFor(payload string) string {
+	return configCodePrefix + base64.RawURLEncoding.EncodeToString([]byte(payload))
+}
+
+func TestConfigCodeRoundTrip(t *testing.T) {
+	sender := domain.DefaultSettings()
+	sender.GoldenHourElevation = 8
+	sender.BlueHourStart = -3
+	sender.BlueHourEnd = -9
+	sender.TimeFormat24Hour = false
+	sender.TeachingMode = true
+	sender.LastLocation = &domain.Location{Latitude: 48.8566, Longitude: 2.3522, Name: "Paris"}
+
+	code := EncodeConfigCode(sender)
+	if !strings.HasPrefix(code, configCodePrefix) {
+		t.Fatalf("code %q lacks prefix %q", code, configCodePrefix)
+	}
+	if strings.ContainsAny(code, "+/=") {
+		t.Errorf("code %q is not unpadded base64url", code)
+	}
+
+	receiver := domain.DefaultSettings()
+	receiver.MapZoom = 7
+	got, err := DecodeConfigCode(code, receiver)
+	if err != nil {
+		t.Fatalf("DecodeConfigCode: %v", err)
+	}
+
+	if got.GoldenHourElevation != 8 || got.BlueHourStart != -3 || got.BlueHourEnd != -9 ||
+		got.TimeFormat24Hour || !got.TeachingMode {
+		t.Errorf("shared values not applied: %+v", got)
+	}
+	if got.LastLocation != nil {
+		t.Errorf("code leaked the sender's location: %+v", got.LastLocation)
+	}
+	if got.MapZoom != 7 {
+		t.Errorf("MapZoom = %d, want receiver's 7", got.MapZoom)
+	}
+}
+
+func TestDecodeConfigCode(t *testing.T) {
+	base := domain.DefaultSettings()
+
+	tests := []struct {
+		name    string
+		code    string
+		want    func(domain.Settings) bool
+		wantErr bool
+	}{
+		{
+			name: "partial code keeps other values",
+			code: codeFor(`{"g":4}`),
+			want: func(s domain.Settings) bool {
+				return s.GoldenHourElevation == 4 && s.BlueHourStart == base.BlueHourStart &&
+					s.TimeFormat24Hour == base.TimeFormat24Hour
+			},
+		},
+		{
+			name: "surrounding whitespace is ignored",
+			code: "  \n" + codeFor(`{"tm":true}`) + "\n",
+			want: func(s domain.Settings) bool { return s.TeachingMode },
+		},
+		{
+			name: "golden hour elevation is clamped",
+			code: codeFor(`{"g":40}`),
+			want: func(s domain.Settings) bool { return s.GoldenHourElevation == 15 },
+		},
+		{
+			name: "blue hour angles are clamped",
+			code: codeFor(`{"bs":3,"be":-30}`),
+			want: func(s domain.Settings) bool { return s.BlueHourStart == 0 && s.BlueHourEnd == -18 },
+		},
+		{
+			name: "blue hour end never above start",
+			code: codeFor(`{"bs":-8,"be":-2}`),
+			want: func(s domain.Settings) bool { return s.BlueHourStart == -6 && s.BlueHourEnd == -6 },
+		},
+		{
+			name:    "missing prefix",
+			code:    strings.TrimPrefix(codeFor(`{"g":4}`), configCodePrefix),
+			wantErr: true,
+		},
+		{
+			name:    "future version",
+			code:    "GGH2-" + strings.TrimPrefix(codeFor(`{"g":4}`), configCodePrefix),
+			wantErr: true,
+		},
+		{
+			name:    "invalid base64",
+			code:    configCodePrefix + "not*base64",
+			wantErr: true,
+		},
+		{
+			name:    "invalid JSON",
+			code:    codeFor(`{"g":`),
+			wantErr: true,
+		},
+		{
+			name:    "wrong value type",
+			code:    codeFor(`{"g":"six"}`),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeConfigCode(tt.code, base)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidConfigCode) {
+					t.Fatalf("err = %v, want ErrInvalidConfigCode", err)
+				}
+				if got.GoldenHourElevation != base.GoldenHourElevation {
+					t.Errorf("failed decode changed settings: %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeConfigCode: %v", err)
+			}
+			if !tt.want(got) {
+				t.Errorf("unexpected settings: %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"encoding/json"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Schema Migration
+// =============================================================================
+
+// migratedPresetName names the preset that keeps the angles of a settings
+// file from before presets, unless they match a built-in preset.
+const migratedPresetName = "My Settings"
+
+// schemaVersion reads the layout version of a settings file (see
+// domain.SettingsSchemaVersion). Files from before it was tracked report 1.
+func schemaVersion(data []byte) int {
+	var header struct {
+		SchemaVersion int `json:"schema_version"`
+	}
+	if err := json.Unmarshal(data, &header); err != nil || header.SchemaVersion < 1 {
+		return 1
+	}
+	return header.SchemaVersion
+}
+
+// migrate brings settings loaded from a file of an older layout up to the
+// current one. Files of the current or a newer layout are left as they are.
+//
+// Parameters:
+//   - settings: The settings decoded on top of the defaults
+//   - version: The file's layout version (see schemaVersion)
+func migrate(settings *domain.Settings, version int) {
+	if version < 2 {
+		// Version 1 had a single set of angles. They become the active
+		// preset: the built-in one with the same angles, or a preset of
+		// their own, so switching presets never loses them.
+		settings.Presets = domain.DefaultPresets()
+		settings.ActivePreset = ""
+		settings.CustomEvents = nil
+		for _, p := range settings.Presets {
+			if p.GoldenHourElevation == settings.GoldenHourElevation && p.BlueHourStart == settings.BlueHourStart &&
+				p.BlueHourEnd == settings.BlueHourEnd && len(p.CustomEvents) == 0 {
+				settings.ApplyPreset(p.Name)
+				break
+			}
+		}
+		if settings.ActivePreset == "" {
+			// Cannot fail: the name is valid and there are only the built-in presets
+			_ = settings.SavePreset(migratedPresetName)
+		}
+	}
+	settings.SchemaVersion = max(version, domain.SettingsSchemaVersion)
+}
@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestLoadMigratesSchemaVersion1(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		active string
+	}{
+		{
+			name:   "angles of a built-in preset",
+			file:   `{"golden_hour_elevation": 6, "blue_hour_start": -4, "blue_hour_end": -8}`,
+			active: "Landscape",
+		},
+		{
+			name:   "angles of their own",
+			file:   `{"golden_hour_elevation": 8, "blue_hour_start": -3, "blue_hour_end": -9}`,
+			active: migratedPresetName,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &PreferencesStore{configPath: filepath.Join(t.TempDir(), configFileName)}
+			if err := os.WriteFile(store.configPath, []byte(tt.file), 0644); err != nil {
+				t.Fatal(err)
+			}
+			settings, err := store.Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if settings.ActivePreset != tt.active || settings.SchemaVersion != domain.SettingsSchemaVersion {
+				t.Fatalf("active preset %q, schema %d; want %q, %d",
+					settings.ActivePreset, settings.SchemaVersion, tt.active, domain.SettingsSchemaVersion)
+			}
+
+			// The file's angles are kept, and stay with the active preset
+			i := domain.FindPreset(settings.Presets, settings.ActivePreset)
+			p := settings.Presets[i]
+			if p.GoldenHourElevation != settings.GoldenHourElevation || p.BlueHourEnd != settings.BlueHourEnd {
+				t.Errorf("preset %+v doesn't hold the angles %v, %v",
+					p, settings.GoldenHourElevation, settings.BlueHourEnd)
+			}
+			if len(settings.CustomEvents) != 0 {
+				t.Errorf("migrated custom events %v, want none", settings.CustomEvents)
+			}
+		})
+	}
+}
+
+func TestLoadKeepsPresets(t *testing.T) {
+	store := &PreferencesStore{configPath: filepath.Join(t.TempDir(), configFileName)}
+	settings := domain.DefaultSettings()
+	settings.ApplyPreset("Astro")
+	if err := settings.SavePreset("Night"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save(settings); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.ActivePreset != "Night" || len(loaded.Presets) != 4 || len(loaded.CustomEvents) != 2 {
+		t.Errorf("loaded active preset %q, %d presets, %d custom events; want Night, 4, 2",
+			loaded.ActivePreset, len(loaded.Presets), len(loaded.CustomEvents))
+	}
+}
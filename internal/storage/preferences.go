@@ -15,6 +15,20 @@
 //
 // The directory is created automatically if it doesn't exist.
 //
+// # Profiles
+//
+// Named profiles (started with --profile NAME) keep a separate settings file
+// each, so that e.g. work and personal locations don't mix:
+//
+//   - Linux: ~/.config/GoGoldenHour/profiles/work/settings.json
+//
+// The default profile uses the settings file above, so settings from
+// versions without profiles keep working. See ListProfiles and
+// ValidateProfileName.
+//
+// Profiles are not presets (domain.Preset): a profile is a settings file of
+// its own, while the presets are sets of elevation angles within one.
+//
 // # Data Format
 //
 // Settings are stored as pretty-printed JSON (2-space indentation) for easy
@@ -26,20 +40,60 @@
 //	  "blue_hour_end": -8,
 //	  "time_format_24_hour": true,
 //	  "auto_detect_location": true,
+//	  "location_source": "ip",
+//	  "map_zoom": 13,
+//	  "teaching_mode": false,
 //	  "last_location": {
 //	    "latitude": 48.8566,
 //	    "longitude": 2.3522,
 //	    "name": "Paris, France",
 //	    "timezone": "Europe/Paris"
-//	  }
+//	  },
+//	  "presets": [
+//	    {"name": "Landscape", "golden_hour_elevation": 6, "blue_hour_start": -4, "blue_hour_end": -8},
+//	    {"name": "Astro", "golden_hour_elevation": 6, "blue_hour_start": -4, "blue_hour_end": -8,
+//	     "custom_events": [{"name": "Astronomical dark", "elevation": -18}]}
+//	  ],
+//	  "active_preset": "Landscape",
+//	  "schema_version": 2
 //	}
 //
+// # Schema Versions
+//
+// The file's "schema_version" (domain.SettingsSchemaVersion) is raised when
+// the layout changes in a way that needs more than new fields with
+// defaults. Older files are migrated when loaded and written in the new
+// layout on the next save:
+//
+//   - 1 (no "schema_version"): one set of elevation angles. They become
+//     the active preset, either the built-in preset with the same angles or
+//     a new "My Settings" preset
+//   - 2: presets and custom events
+//
+// # Crash Safety
+//
+// Settings are written to a temporary file next to settings.json, which
+// then replaces it in one rename, so a crash or power cut while saving
+// leaves either the old or the new file, never half of one. Before each
+// save the previous file is kept as settings.json.bak (one generation,
+// rotated on every save). See Save and Recover.
+//
+// # Configuration Bundles
+//
+// The settings of all profiles can be exported to one file and imported on
+// another machine (ConfigBundle): a JSON file naming the format, with each
+// profile's settings as in its settings.json. Imports are validated as a
+// whole before anything is written. See ExportConfigBundle and
+// ReadConfigBundle.
+//
 // # Error Handling
 //
 // The package is designed for graceful degradation:
 //   - Missing file: Returns default settings (no error)
-//   - Corrupted JSON: Returns default settings (no error)
+//   - Corrupted JSON: Returns default settings (no error); Corrupt reports
+//     it, so the user can be offered the backup (see Recover)
 //   - Invalid values: Validated and clamped to acceptable ranges
+//   - Missing fields: Keep their default values (files from older versions)
 //
 // This ensures the application always starts successfully, even if the
 // configuration file is damaged or manually edited incorrectly.
@@ -67,6 +121,28 @@ const (
 	// Using .json extension makes the format obvious and enables syntax highlighting
 	// when users manually edit the file.
 	configFileName = "settings.json"
+
+	// profilesDirName is the directory within the config directory that
+	// holds one subdirectory per named profile.
+	profilesDirName = "profiles"
+
+	// backupSuffix is appended to the settings file's name for the backup
+	// of the previous save (settings.json.bak).
+	backupSuffix = ".bak"
+
+	// corruptSuffix is appended to the settings file's name for a damaged
+	// file set aside by the first save after loading it
+	// (settings.json.corrupt), so it can still be inspected.
+	corruptSuffix = ".corrupt"
+
+	// settingsFileMode is the permissions of the settings file and its
+	// backup: owner read/write only, as the settings hold passwords and
+	// API keys (the CalDAV password, the Pushover token, webhook secrets).
+	settingsFileMode = 0600
+
+	// configDirMode is the permissions of the config directory: owner
+	// only, for the same reason.
+	configDirMode = 0700
 )
 
 // =============================================================================
@@ -75,13 +151,13 @@ const (
 
 // PreferencesStore handles persistent storage of user preferences.
 //
-// The store manages a single JSON file containing all user settings. It provides
-// thread-safe read/write operations (file operations are atomic) and handles
-// all error cases gracefully.
+// The store manages a single JSON file containing all user settings, and a
+// backup of its previous version. It provides crash-safe writes (see Save)
+// and handles all error cases gracefully.
 //
 // Usage:
 //
-//	store, err := storage.NewPreferencesStore()
+//	store, err := storage.NewPreferencesStore(storage.DefaultProfile)
 //	if err != nil {
 //	    // Handle error (rare, indicates filesystem issues)
 //	}
@@ -96,22 +172,36 @@ type PreferencesStore struct {
 	// configPath is the full path to the settings.json file.
 	// Determined at construction time based on the platform's config directory.
 	configPath string
+
+	// corrupt is true if Load found the settings file damaged. Until the
+	// user decides with Recover, saves leave the backup alone, so the
+	// defaults used meanwhile don't replace the last good settings.
+	corrupt bool
 }
 
-// NewPreferencesStore creates a new preferences store.
+// NewPreferencesStore creates a new preferences store for a profile.
 //
 // This constructor:
 //  1. Determines the platform-appropriate config directory
-//  2. Creates the GoGoldenHour config directory if it doesn't exist
+//  2. Creates the GoGoldenHour config directory (or the profile's
+//     subdirectory) if it doesn't exist
 //  3. Returns a store configured to use settings.json in that directory
 //
+// Parameters:
+//   - profile: The profile name, or DefaultProfile for the settings used
+//     without --profile (must pass ValidateProfileName)
+//
 // Returns:
 //   - *PreferencesStore: Ready-to-use store instance
 //   - error: Non-nil if the config directory cannot be determined or created
 //
 // Errors are rare and indicate system-level issues (no home directory,
 // permissions problems, etc.).
-func NewPreferencesStore() (*PreferencesStore, error) {
+func NewPreferencesStore(profile string) (*PreferencesStore, error) {
+	if err := ValidateProfileName(profile); err != nil {
+		return nil, err
+	}
+
 	// Get the platform's user configuration directory.
 	// This follows XDG on Linux, uses Application Support on macOS, etc.
 	configDir, err := os.UserConfigDir()
@@ -121,11 +211,15 @@ func NewPreferencesStore() (*PreferencesStore, error) {
 
 	// Create application-specific subdirectory.
 	// MkdirAll is idempotent - it succeeds if the directory already exists.
-	// Permissions 0755 allow owner full access, others read/execute.
-	appConfigDir := filepath.Join(configDir, configDirName)
-	if err := os.MkdirAll(appConfigDir, 0755); err != nil {
+	// Only the owner may enter it (configDirMode); a directory created
+	// by an older version with 0755 is tightened too.
+	appConfigDir := profileDir(filepath.Join(configDir, configDirName), profile)
+	if err := os.MkdirAll(appConfigDir, configDirMode); err != nil {
 		return nil, fmt.Errorf("failed to create config directory: %w", err)
 	}
+	if err := os.Chmod(appConfigDir, configDirMode); err != nil {
+		return nil, fmt.Errorf("failed to restrict config directory: %w", err)
+	}
 
 	return &PreferencesStore{
 		configPath: filepath.Join(appConfigDir, configFileName),
@@ -140,8 +234,10 @@ func NewPreferencesStore() (*PreferencesStore, error) {
 //
 // This method handles all error cases gracefully:
 //   - File doesn't exist: Returns default settings (first run)
-//   - File is corrupted/invalid JSON: Returns default settings
+//   - File is corrupted/invalid JSON: Returns default settings, and
+//     Corrupt reports true
 //   - File contains invalid values: Values are validated and clamped
+//   - File has an older layout: Settings are migrated (see Schema Versions)
 //
 // The only error case that propagates is when the file exists but cannot
 // be read (permissions, filesystem errors).
@@ -169,14 +265,29 @@ func (s *PreferencesStore) Load() (domain.Settings, error) {
 		return domain.Settings{}, fmt.Errorf("failed to read settings: %w", err)
 	}
 
-	// Parse JSON into settings struct
-	var settings domain.Settings
-	if err := json.Unmarshal(data, &settings); err != nil {
+	settings, err := parseSettings(data)
+	if err != nil {
 		// JSON is corrupted or invalid - return defaults rather than failing.
 		// This provides a recovery path for users who accidentally break
 		// their config file by manual editing.
+		s.corrupt = true
 		return domain.DefaultSettings(), nil
 	}
+	return settings, nil
+}
+
+// parseSettings decodes a settings file: the JSON is parsed on top of the
+// defaults, so that fields added in newer versions (absent from older
+// settings files) keep their default values, older layouts are migrated,
+// and the values are validated.
+//
+// Returns an error if the data isn't valid JSON for the settings.
+func parseSettings(data []byte) (domain.Settings, error) {
+	settings := domain.DefaultSettings()
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return domain.Settings{}, err
+	}
+	migrate(&settings, schemaVersion(data))
 
 	// Validate and clamp settings to acceptable ranges.
 	// This handles cases where the file was manually edited with invalid values.
@@ -188,18 +299,31 @@ func (s *PreferencesStore) Load() (domain.Settings, error) {
 // Save writes the given settings to disk.
 //
 // The settings are serialized to pretty-printed JSON (2-space indentation)
-// for human readability. The file is written with 0644 permissions (owner
-// read/write, others read-only).
+// for human readability, in the current layout (domain.SettingsSchemaVersion,
+// unless they were loaded from a newer one). The file is written with 0600
+// permissions (owner read/write only), as it holds passwords and API keys.
+//
+// The save goes through these steps:
+//  1. The current file becomes the backup (settings.json.bak), if it is
+//     valid; a damaged file is moved aside to settings.json.corrupt
+//     instead, and while the user hasn't decided what to do about it (see
+//     Corrupt), the backup is left alone
+//  2. The settings are written to a temporary file in the same directory
+//     and flushed to disk
+//  3. The temporary file is renamed over settings.json
 //
 // Parameters:
 //   - settings: The settings to save
 //
 // Returns:
-//   - error: Non-nil if the write fails (permissions, disk full, etc.)
+//   - error: Non-nil if the write fails (permissions, disk full, etc.);
+//     settings.json is then unchanged
 //
 // The write is atomic at the filesystem level - either the entire file
 // is written or the operation fails, preventing partial/corrupted files.
 func (s *PreferencesStore) Save(settings domain.Settings) error {
+	settings.SchemaVersion = max(settings.SchemaVersion, domain.SettingsSchemaVersion)
+
 	// Serialize to JSON with indentation for readability.
 	// This makes manual inspection and debugging easier.
 	data, err := json.MarshalIndent(settings, "", "  ")
@@ -208,9 +332,13 @@ func (s *PreferencesStore) Save(settings domain.Settings) error {
 		return fmt.Errorf("failed to marshal settings: %w", err)
 	}
 
+	if err := s.rotateBackup(); err != nil {
+		return fmt.Errorf("failed to back up settings: %w", err)
+	}
+
 	// Write the file atomically.
-	// Permissions 0644: owner read/write, group/others read-only.
-	if err := os.WriteFile(s.configPath, data, 0644); err != nil {
+	// Permissions 0600: owner read/write only (settingsFileMode).
+	if err := writeFileAtomic(s.configPath, data, settingsFileMode); err != nil {
 		return fmt.Errorf("failed to write settings: %w", err)
 	}
 
@@ -221,6 +349,16 @@ func (s *PreferencesStore) Save(settings domain.Settings) error {
 // Utility Methods
 // =============================================================================
 
+// Exists reports whether a settings file has been saved before.
+//
+// Load can't tell a first run from an existing file, as both may return
+// the defaults. The App uses this to greet new users differently from
+// users updating from an earlier version (see changelog).
+func (s *PreferencesStore) Exists() bool {
+	_, err := os.Stat(s.configPath)
+	return err == nil
+}
+
 // GetConfigPath returns the full path to the configuration file.
 //
 // This is useful for debugging, error messages, or informing users where
@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestPreferencesStoreExists(t *testing.T) {
+	store := &PreferencesStore{configPath: filepath.Join(t.TempDir(), configFileName)}
+	if store.Exists() {
+		t.Fatal("Exists() = true before the first save")
+	}
+
+	// A first run loads the defaults without creating the file
+	settings, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if store.Exists() {
+		t.Fatal("Exists() = true after loading defaults")
+	}
+
+	settings.LastSeenVersion = "0.1.3"
+	settings.ShowWhatsNew = false
+	if err := store.Save(settings); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if !store.Exists() {
+		t.Fatal("Exists() = false after saving")
+	}
+
+	loaded, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if loaded.LastSeenVersion != "0.1.3" || loaded.ShowWhatsNew {
+		t.Errorf("loaded LastSeenVersion %q, ShowWhatsNew %v; want \"0.1.3\", false",
+			loaded.LastSeenVersion, loaded.ShowWhatsNew)
+	}
+	if !domain.DefaultSettings().ShowWhatsNew {
+		t.Error("ShowWhatsNew should default to true")
+	}
+}
+
+func TestPreferencesStoreSaveIsPrivate(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("Unix permissions")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	// A directory left by an older version, readable by all
+	dir := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), configDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewPreferencesStore(DefaultProfile)
+	if err != nil {
+		t.Fatalf("NewPreferencesStore() error = %v", err)
+	}
+	settings := domain.DefaultSettings()
+	settings.CalDAV.Password = "secret"
+	// Twice, so the first save becomes the backup
+	for range 2 {
+		if err := store.Save(settings); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	for path, want := range map[string]os.FileMode{
+		dir:                configDirMode,
+		store.configPath:   settingsFileMode,
+		store.backupPath(): settingsFileMode,
+	} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := info.Mode().Perm(); got != want {
+			t.Errorf("%s has mode %o, want %o", filepath.Base(path), got, want)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// =============================================================================
+// Profiles
+// =============================================================================
+
+// DefaultProfile is the profile used when no --profile is given. Its
+// settings file is the one used by versions without profiles.
+const DefaultProfile = ""
+
+// maxProfileNameLength limits profile names to something that fits in a
+// window title and any file system's directory names.
+const maxProfileNameLength = 40
+
+// ValidateProfileName checks that a profile name can be used as a directory
+// name on every platform.
+//
+// Names may contain letters, digits, spaces, '-', '_' and '.', must not
+// start with '.' or a space, and are at most 40 characters long.
+// DefaultProfile ("") is valid.
+//
+// Returns a descriptive error for invalid names, suitable for display.
+func ValidateProfileName(name string) error {
+	if name == DefaultProfile {
+		return nil
+	}
+	if len([]rune(name)) > maxProfileNameLength {
+		return fmt.Errorf("profile name %q is longer than %d characters", name, maxProfileNameLength)
+	}
+	if strings.HasPrefix(name, ".") || strings.HasPrefix(name, " ") || strings.HasSuffix(name, " ") {
+		return fmt.Errorf("profile name %q must not start with a dot or start or end with a space", name)
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(" -_.", r) {
+			return fmt.Errorf("profile name %q contains %q (use letters, digits, spaces, '-', '_' and '.')", name, r)
+		}
+	}
+	return nil
+}
+
+// ListProfiles returns the names of the named profiles that have been used
+// on this machine, sorted case-insensitively. DefaultProfile is not
+// included.
+//
+// Returns an empty list (and no error) if no named profile exists yet.
+func ListProfiles() ([]string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return listProfiles(filepath.Join(configDir, configDirName))
+}
+
+// profileDir returns the directory holding a profile's settings file,
+// within the application's config directory appDir.
+func profileDir(appDir, profile string) string {
+	if profile == DefaultProfile {
+		return appDir
+	}
+	return filepath.Join(appDir, profilesDirName, profile)
+}
+
+// listProfiles lists the profile directories within appDir (see
+// ListProfiles). Directories with invalid names, e.g. created by hand,
+// are skipped.
+func listProfiles(appDir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(appDir, profilesDirName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list profiles: %w", err)
+	}
+
+	var profiles []string
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() != DefaultProfile && ValidateProfileName(entry.Name()) == nil {
+			profiles = append(profiles, entry.Name())
+		}
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return strings.ToLower(profiles[i]) < strings.ToLower(profiles[j])
+	})
+	return profiles, nil
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestValidateProfileName(t *testing.T) {
+	valid := []string{DefaultProfile, "work", "Personal 2", "client-a_b.v2", "Café"}
+	for _, name := range valid {
+		if err := ValidateProfileName(name); err != nil {
+			t.Errorf("ValidateProfileName(%q) error: %v", name, err)
+		}
+	}
+
+	invalid := []string{
+		".hidden", " work", "work ", "../work", "a/b", `a\b`, "a:b",
+		"a very long profile name that goes on and on",
+	}
+	for _, name := range invalid {
+		if err := ValidateProfileName(name); err == nil {
+			t.Errorf("ValidateProfileName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestListProfiles(t *testing.T) {
+	appDir := t.TempDir()
+
+	profiles, err := listProfiles(appDir)
+	if err != nil || len(profiles) != 0 {
+		t.Fatalf("listProfiles() = %v, %v before any profile", profiles, err)
+	}
+
+	for _, name := range []string{"work", "Personal", ".trash"} {
+		if err := os.MkdirAll(profileDir(appDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Files next to the profile directories are ignored
+	if err := os.WriteFile(filepath.Join(appDir, profilesDirName, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err = listProfiles(appDir)
+	if err != nil {
+		t.Fatalf("listProfiles() error: %v", err)
+	}
+	if want := []string{"Personal", "work"}; !reflect.DeepEqual(profiles, want) {
+		t.Errorf("listProfiles() = %v, want %v", profiles, want)
+	}
+}
+
+func TestProfileDir(t *testing.T) {
+	appDir := filepath.Join("config", configDirName)
+	if got := profileDir(appDir, DefaultProfile); got != appDir {
+		t.Errorf("profileDir(default) = %q, want %q", got, appDir)
+	}
+	want := filepath.Join(appDir, profilesDirName, "work")
+	if got := profileDir(appDir, "work"); got != want {
+		t.Errorf("profileDir(work) = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// SessionStore
+// =============================================================================
+
+// sessionFileName is the session file in the config directory, next to
+// the default profile's settings.json.
+const sessionFileName = "session.json"
+
+// SessionStore keeps the session the app last quit with (domain.Session)
+// in session.json, shared by all profiles:
+//
+//	~/.config/GoGoldenHour/
+//	    settings.json        # default profile
+//	    session.json         # the last session, of whichever profile
+//	    profiles/work/settings.json
+//
+// The session is a convenience: a missing or damaged file just means the
+// next launch starts as without one, so Load doesn't keep backups like
+// PreferencesStore.
+type SessionStore struct {
+	// path is the full path to session.json.
+	path string
+}
+
+// NewSessionStore creates the session store in the platform's config
+// directory, creating the directory if needed.
+//
+// Returns an error if the config directory can't be determined or
+// created.
+func NewSessionStore() (*SessionStore, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config directory: %w", err)
+	}
+	return NewSessionStoreAt(filepath.Join(configDir, configDirName))
+}
+
+// NewSessionStoreAt creates a session store keeping session.json in dir,
+// creating dir if needed (NewSessionStore, or a test's directory). Like
+// the settings, the session is private to the user (configDirMode,
+// settingsFileMode): it tells where they are.
+func NewSessionStoreAt(dir string) (*SessionStore, error) {
+	if err := os.MkdirAll(dir, configDirMode); err != nil {
+		return nil, fmt.Errorf("failed to create config directory: %w", err)
+	}
+	return &SessionStore{path: filepath.Join(dir, sessionFileName)}, nil
+}
+
+// Load reads the last session.
+//
+// The session is repaired (domain.Session.Validate); a profile that no
+// longer passes ValidateProfileName is forgotten.
+//
+// Returns an empty session without error if none was saved yet or the
+// file is damaged, and an error only if the file can't be read.
+func (s *SessionStore) Load() (domain.Session, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return domain.Session{}, nil
+		}
+		return domain.Session{}, fmt.Errorf("failed to read session: %w", err)
+	}
+	var session domain.Session
+	if err := json.Unmarshal(data, &session); err != nil {
+		return domain.Session{}, nil
+	}
+	session.Validate()
+	if ValidateProfileName(session.Profile) != nil {
+		session.Profile = DefaultProfile
+	}
+	return session, nil
+}
+
+// Save writes the session, replacing the last one. The write is atomic
+// (see PreferencesStore.Save), so a crash while quitting leaves the
+// previous session.
+func (s *SessionStore) Save(session domain.Session) error {
+	data, err := json.MarshalIndent(session, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data, settingsFileMode); err != nil {
+		return fmt.Errorf("failed to write session: %w", err)
+	}
+	return nil
+}
+
+// Path returns the full path to session.json.
+func (s *SessionStore) Path() string {
+	return s.path
+}
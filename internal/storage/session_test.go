@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+func TestSessionStoreRoundTrip(t *testing.T) {
+	store, err := NewSessionStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSessionStoreAt() error = %v", err)
+	}
+
+	// Nothing saved yet
+	session, err := store.Load()
+	if err != nil || session.Location != nil || session.Profile != "" {
+		t.Fatalf("Load() before Save = %+v, %v; want an empty session", session, err)
+	}
+
+	want := domain.Session{
+		Profile:  "work",
+		Location: &domain.Location{Latitude: 48.85, Longitude: 2.35, Name: "Paris", Timezone: "Europe/Paris"},
+		Date:     "2026-06-21",
+		MapZoom:  11,
+		Panels:   []string{domain.SessionPanelMonthCalendar},
+		SavedAt:  time.Date(2026, 6, 10, 22, 0, 0, 0, time.UTC),
+	}
+	if err := store.Save(want); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	got, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got.Profile != want.Profile || got.Location == nil || got.Location.Name != "Paris" ||
+		got.Date != want.Date || got.MapZoom != want.MapZoom ||
+		!got.HasPanel(domain.SessionPanelMonthCalendar) || !got.SavedAt.Equal(want.SavedAt) {
+		t.Errorf("Load() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSessionStoreLoadRepairs(t *testing.T) {
+	store, err := NewSessionStoreAt(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSessionStoreAt() error = %v", err)
+	}
+
+	// A damaged file is no session
+	if err := os.WriteFile(store.Path(), []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if session, err := store.Load(); err != nil || session.Date != "" {
+		t.Errorf("Load() of a damaged file = %+v, %v; want an empty session", session, err)
+	}
+
+	// An invalid profile name and location are dropped
+	data := `{"profile": "../other", "location": {"latitude": 200, "longitude": 0}, "date": "2026-06-21"}`
+	if err := os.WriteFile(store.Path(), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	session, err := store.Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if session.Profile != DefaultProfile || session.Location != nil || session.Date != "2026-06-21" {
+		t.Errorf("Load() = %+v, want the default profile, no location and the date", session)
+	}
+}
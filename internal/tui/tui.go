@@ -0,0 +1,941 @@
+// Package tui provides the terminal user interface of GoGoldenHour
+// (gogoldenhour --tui), for headless servers and SSH sessions.
+//
+// The terminal UI is a second frontend for the same App controller and
+// services as the Qt window: it reports user actions through
+// frontend.Controller and implements frontend.View, so searches, location
+// detection, favorites, presets and automation behave exactly as in the
+// window. It is built with tview (https://github.com/rivo/tview) and
+// doesn't start Qt, so no display is needed.
+//
+// # Layout
+//
+//	┌ GoGoldenHour ───────────────────────────────────────────────────┐
+//	│ Paris, France ★  48.8534, 2.3488  Europe/Paris                  │
+//	│ Saturday, June 21, 2025 · Preset: Landscape                     │
+//	│ Search: Eiffel Tower_                                           │
+//	├─ Sun Times ────────────────────────┬─ Favorites ────────────────┤
+//	│ Morning blue hour    04:52 – 05:14 │ Étretat      20:58 – 21:43 │
+//	│ Sunrise              05:47         │ Mont Blanc   20:41 – 21:25 │
+//	│ Morning golden hour  05:47 – 06:31 │                            │
+//	│ ...                                │                            │
+//	│ Evening golden hour at 21:07,      │                            │
+//	│ in 2h 5m                           │                            │
+//	├────────────────────────────────────┴────────────────────────────┤
+//	│ Searching… · / search  d detect  ←→ day  t today  f favorite    │
+//	└─────────────────────────────────────────────────────────────────┘
+//
+// The map, the sun path, the charts and the preference dialogs have no
+// place in a terminal; their View updates are ignored (see the View
+// methods). Settings changed in the window apply here too, as both use the
+// same settings file.
+//
+// # Thread Safety
+//
+// tview runs its own event loop. The App's goroutines hand their results
+// over with RunOnMainThread (tview's QueueUpdateDraw), so the controller
+// and the widgets are only touched by the event loop.
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/megatih/GoGoldenHour/internal/changelog"
+	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/events"
+	"github.com/megatih/GoGoldenHour/internal/frontend"
+	"github.com/megatih/GoGoldenHour/internal/service/automation"
+	"github.com/megatih/GoGoldenHour/internal/service/caldav"
+	"github.com/megatih/GoGoldenHour/internal/service/updates"
+	"github.com/rivo/tview"
+)
+
+// =============================================================================
+// Constants
+// =============================================================================
+
+const (
+	// countdownInterval is how often the countdown to the next golden or
+	// blue hour is redrawn.
+	countdownInterval = 30 * time.Second
+
+	// keyHelp lists the key bindings in the status line.
+	keyHelp = "/ search  d detect  ←→ day  t today  f favorite  r retry  ? help  q quit"
+
+	// helpText is the help page's text (the ? key).
+	helpText = `Keys
+
+  /        Search for a place (Enter searches, Esc goes back)
+  d        Detect the location (again to stop)
+  Esc      Stop the search or the detection
+  ← →      Previous and next day (also h and l)
+  t        Today
+  f        Add the location to the favorites, or remove it
+  w        Week outlook: the chance of usable light in the coming days
+  Tab      Move between the search field and the favorites
+  Enter    Open the selected favorite
+  r        Retry what failed last
+  q        Quit
+
+Settings changed in the window (gogoldenhour without --tui) apply here
+too; the terminal UI uses the same settings and favorites.`
+)
+
+// Page names of the Terminal's pages.
+const (
+	pageMain    = "main"
+	pageResults = "results"
+	pageDialog  = "dialog"
+)
+
+// errNoMap is the map location result of the terminal UI, so detection
+// falls back to the IP address and system providers.
+var errNoMap = errors.New("the terminal UI has no map to ask for the browser location")
+
+// Compile-time check that Terminal is a frontend.View.
+var _ frontend.View = (*Terminal)(nil)
+
+// =============================================================================
+// Terminal
+// =============================================================================
+
+// Terminal is the terminal user interface. It implements frontend.View.
+//
+// Usage:
+//
+//	var terminal *tui.Terminal
+//	application, err := app.New(profile, func(cfg config.AppConfig, c frontend.Controller) frontend.View {
+//		terminal = tui.New(cfg, c)
+//		return terminal
+//	})
+//	application.Run(link)
+//	err = terminal.Run() // blocks until the user quits
+type Terminal struct {
+	// app is the tview application running the event loop.
+	app *tview.Application
+
+	// controller receives the user's actions (the App).
+	controller frontend.Controller
+
+	// config holds the application version for messages.
+	config config.AppConfig
+
+	// pages stacks the main page and the search results or a dialog.
+	pages *tview.Pages
+
+	// header shows the location, date and preset.
+	header *tview.TextView
+
+	// search is the place search field.
+	search *tview.InputField
+
+	// times lists the day's sun times and the countdown.
+	times *tview.TextView
+
+	// favorites lists the favorites with tonight's golden hour.
+	favorites *tview.List
+
+	// days lists each day of a planned date range; hidden without one.
+	days *tview.TextView
+
+	// body holds the times, favorites and days, so days can be hidden.
+	body *tview.Flex
+
+	// status shows the last message, the running tasks and the keys.
+	status *tview.TextView
+
+	// location is the current location, shown in the header.
+	location domain.Location
+
+	// sunTimes is the selected day's sun times.
+	sunTimes domain.SunTimes
+
+	// weather is the forecast for the selected day; empty without one.
+	weather domain.WeatherForecast
+
+	// aurora is the darkness and aurora outlook of the selected date's
+	// night.
+	aurora domain.AuroraOutlook
+
+	// meteors are the meteor showers peaking on the selected date's night.
+	meteors []domain.MeteorNight
+
+	// countdownDays are the days around today for the countdown; nil
+	// hides it.
+	countdownDays []domain.SunTimes
+
+	// favoriteList is the favorites in the order of the favorites list.
+	favoriteList []domain.Favorite
+
+	// message is the status line's message; isError shows it in red.
+	message string
+	isError bool
+
+	// retry reruns the operation of the last retryable error (r key); nil
+	// if there is none.
+	retry func()
+
+	// busy are the network operations running now.
+	busy map[frontend.Task]bool
+
+	// stop ends the countdown updates when the event loop ends.
+	stop chan struct{}
+}
+
+// =============================================================================
+// Constructor
+// =============================================================================
+
+// New creates the terminal UI with all its widgets, for app.New's
+// frontend.Factory (see Terminal); call Run to start it.
+//
+// Parameters:
+//   - cfg: Application configuration (version for messages)
+//   - controller: The App controller for handling user actions
+func New(cfg config.AppConfig, controller frontend.Controller) *Terminal {
+	t := &Terminal{
+		app:        tview.NewApplication(),
+		controller: controller,
+		config:     cfg,
+		busy:       make(map[frontend.Task]bool),
+		stop:       make(chan struct{}),
+	}
+	t.setupUI()
+	t.subscribeEvents()
+	return t
+}
+
+// setupUI creates and arranges the widgets and binds the keys (see
+// helpText).
+func (t *Terminal) setupUI() {
+	t.header = tview.NewTextView().SetDynamicColors(true)
+
+	t.search = tview.NewInputField().
+		SetLabel("Search: ").
+		SetPlaceholder("place, address or coordinates (press /)")
+	t.search.SetDoneFunc(t.onSearchDone)
+
+	t.times = tview.NewTextView().SetDynamicColors(true)
+	t.times.SetBorder(true).SetTitle(" Sun Times ")
+
+	t.favorites = tview.NewList().ShowSecondaryText(false)
+	t.favorites.SetBorder(true).SetTitle(" Favorites ")
+	t.favorites.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		if index < len(t.favoriteList) {
+			t.controller.UpdateLocation(t.favoriteList[index].CurrentLocation())
+		}
+	})
+
+	t.days = tview.NewTextView().SetDynamicColors(true)
+	t.days.SetBorder(true).SetTitle(" Date Range ")
+
+	t.body = tview.NewFlex().
+		AddItem(t.times, 0, 3, false).
+		AddItem(t.favorites, 0, 2, true)
+
+	t.status = tview.NewTextView().SetDynamicColors(true)
+
+	top := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(t.header, 2, 0, false).
+		AddItem(t.search, 1, 0, false)
+	top.SetBorder(true).SetTitle(" GoGoldenHour ")
+
+	main := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(top, 5, 0, false).
+		AddItem(t.body, 0, 1, true).
+		AddItem(t.status, 1, 0, false)
+
+	t.pages = tview.NewPages().AddPage(pageMain, main, true, true)
+	t.app.SetRoot(t.pages, true).SetFocus(t.favorites)
+	t.app.SetInputCapture(t.onKey)
+	t.drawStatus()
+}
+
+// =============================================================================
+// Event Loop
+// =============================================================================
+
+// Run starts the event loop and blocks until the user quits (q or Ctrl+C),
+// then saves the session (Controller.EndSession).
+//
+// Returns an error if the terminal can't be used (e.g., no terminal on
+// standard input and output).
+func (t *Terminal) Run() error {
+	go t.runCountdown()
+	defer close(t.stop)
+	if err := t.app.Run(); err != nil {
+		return err
+	}
+	// The terminal has no planning windows to reopen
+	t.controller.EndSession(nil)
+	return nil
+}
+
+// runCountdown redraws the countdown every countdownInterval until the
+// event loop ends.
+func (t *Terminal) runCountdown() {
+	ticker := time.NewTicker(countdownInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			t.app.QueueUpdateDraw(t.drawTimes)
+		case <-t.stop:
+			return
+		}
+	}
+}
+
+// RunOnMainThread runs fn in the event loop and waits until it has run
+// (see frontend.View). Called from the App's goroutines.
+func (t *Terminal) RunOnMainThread(fn func()) {
+	t.app.QueueUpdateDraw(crash.Guard(fn))
+}
+
+// QueueOnMainThread runs fn in the event loop once it runs, and returns
+// immediately (QueueUpdateDraw waits, so it is called from a goroutine).
+func (t *Terminal) QueueOnMainThread(fn func()) {
+	go t.app.QueueUpdateDraw(crash.Guard(fn))
+}
+
+// =============================================================================
+// Key Handling
+// =============================================================================
+
+// onKey handles the key bindings, unless the search field or a dialog
+// has the focus (they take the keys themselves).
+func (t *Terminal) onKey(event *tcell.EventKey) *tcell.EventKey {
+	if front, _ := t.pages.GetFrontPage(); front != pageMain || t.search.HasFocus() {
+		return event
+	}
+	if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyBacktab {
+		t.app.SetFocus(t.search)
+		return nil
+	}
+	switch event.Key() {
+	case tcell.KeyEscape:
+		t.cancelTasks()
+		return nil
+	case tcell.KeyLeft:
+		t.moveDate(-1)
+		return nil
+	case tcell.KeyRight:
+		t.moveDate(1)
+		return nil
+	}
+	switch event.Rune() {
+	case '/':
+		t.app.SetFocus(t.search)
+	case 'd':
+		if t.busy[frontend.TaskDetect] {
+			t.controller.CancelDetection()
+			t.setStatus("Location detection stopped", false)
+		} else {
+			t.controller.DetectLocation()
+		}
+	case 'h':
+		t.moveDate(-1)
+	case 'l':
+		t.moveDate(1)
+	case 't':
+		t.controller.UpdateDate(time.Now())
+	case 'f':
+		t.controller.ToggleFavorite()
+	case 'w':
+		t.controller.FetchWeekOutlook()
+	case 'r':
+		if retry := t.retry; retry != nil {
+			t.retry = nil
+			t.setStatus("", false)
+			retry()
+		}
+	case '?':
+		t.showDialog(helpText, []string{"Close"}, nil)
+	case 'q':
+		t.app.Stop()
+	default:
+		return event
+	}
+	return nil
+}
+
+// onSearchDone searches for the typed place (Enter), or stops the search
+// (Esc); either way, and with Tab, the focus goes back to the favorites, so
+// the keys work while the search runs.
+func (t *Terminal) onSearchDone(key tcell.Key) {
+	switch key {
+	case tcell.KeyEnter:
+		if query := strings.TrimSpace(t.search.GetText()); query != "" {
+			t.controller.SearchLocation(query)
+		}
+	case tcell.KeyEscape:
+		t.cancelTasks()
+	}
+	t.app.SetFocus(t.favorites)
+}
+
+// cancelTasks stops the running search and location detection (Esc).
+func (t *Terminal) cancelTasks() {
+	if t.busy[frontend.TaskSearch] {
+		t.controller.CancelSearch()
+		t.setStatus("Search stopped", false)
+	}
+	if t.busy[frontend.TaskDetect] {
+		t.controller.CancelDetection()
+		t.setStatus("Location detection stopped", false)
+	}
+}
+
+// moveDate selects the day days after the selected one.
+func (t *Terminal) moveDate(days int) {
+	t.controller.UpdateDate(t.controller.GetDate().AddDate(0, 0, days))
+}
+
+// =============================================================================
+// Window Methods (called by App controller)
+// =============================================================================
+
+// Show draws the initial state; the terminal is taken over by Run.
+func (t *Terminal) Show() {
+	t.drawHeader()
+	t.drawTimes()
+}
+
+// ShowWelcome points a first-time user to the search, which sets the
+// location; the home location and formats keep their defaults (the
+// window's welcome wizard sets them).
+func (t *Terminal) ShowWelcome() {
+	t.setStatus("Welcome! Press / to search for your location, or d to detect it", false)
+}
+
+// ShowWhatsNew lists the release notes of the given versions in a dialog.
+func (t *Terminal) ShowWhatsNew(releases []changelog.Release) {
+	var b strings.Builder
+	for _, release := range releases {
+		b.WriteString(release.Title() + "\n\n")
+		for _, change := range release.Changes {
+			b.WriteString("• " + change + "\n")
+		}
+		b.WriteString("\n")
+	}
+	t.showDialog(strings.TrimSpace(b.String()), []string{"Close"}, nil)
+}
+
+// ShowSettingsRecovery offers the backup of a damaged settings file, or
+// the defaults that are in use.
+func (t *Terminal) ShowSettingsRecovery(hasBackup bool) {
+	if !hasBackup {
+		t.ShowError("The settings file was damaged and has no backup, so the defaults are in use")
+		return
+	}
+	t.showDialog("The settings file was damaged, so the defaults are in use.\n\nRestore the backup?",
+		[]string{"Restore Backup", "Keep Defaults"}, func(button int) {
+			if err := t.controller.RestoreSettings(button == 0); err != nil {
+				t.ShowError(fmt.Sprintf("Settings not restored: %v", err))
+			}
+		})
+}
+
+// =============================================================================
+// Event Subscriptions (published by App controller)
+// =============================================================================
+
+// subscribeEvents follows the App's changes (see the events package): the
+// location and date in the header, the sun times in their box. The App
+// publishes in the event loop, like its View calls.
+func (t *Terminal) subscribeEvents() {
+	bus := t.controller.Events()
+	events.Subscribe(bus, func(e events.LocationChanged) {
+		t.location = e.Location
+		t.drawHeader()
+	})
+	events.Subscribe(bus, func(events.DateChanged) {
+		t.drawHeader()
+	})
+	events.Subscribe(bus, func(e events.SunTimesUpdated) {
+		t.sunTimes = e.SunTimes
+		t.drawTimes()
+	})
+}
+
+// =============================================================================
+// Update Methods (called by App controller)
+// =============================================================================
+
+// UpdateWeather shows the forecast condition after each period.
+func (t *Terminal) UpdateWeather(forecast domain.WeatherForecast) {
+	t.weather = forecast
+	t.drawTimes()
+}
+
+// UpdateAurora shows the night's darkness and, with the aurora forecast
+// on, its aurora outlook below the sun times.
+func (t *Terminal) UpdateAurora(outlook domain.AuroraOutlook) {
+	t.aurora = outlook
+	t.drawTimes()
+}
+
+// UpdateMeteors shows the meteor showers peaking on the night below the
+// darkness.
+func (t *Terminal) UpdateMeteors(nights []domain.MeteorNight) {
+	t.meteors = nights
+	t.drawTimes()
+}
+
+// UpdateTimeline is ignored: the sun times list the same events.
+func (t *Terminal) UpdateTimeline([]domain.SunTimes) {}
+
+// UpdateSunPath is ignored: there is no map.
+func (t *Terminal) UpdateSunPath(domain.Location, []domain.SunPathPoint) {}
+
+// UpdateSunPositions is ignored: there is no time slider.
+func (t *Terminal) UpdateSunPositions(domain.Location, []domain.SunPathPoint) {}
+
+// UpdateTerminator is ignored: there is no map.
+func (t *Terminal) UpdateTerminator([]domain.TwilightBand) {}
+
+// UpdateCountdown restarts the countdown under the sun times.
+func (t *Terminal) UpdateCountdown(days []domain.SunTimes) {
+	t.countdownDays = days
+	t.drawTimes()
+}
+
+// UpdateDateRange lists each day's golden and blue hours next to the sun
+// times, or hides the list (nil: a single day is planned).
+func (t *Terminal) UpdateDateRange(days []domain.SunTimes) {
+	t.body.RemoveItem(t.days)
+	if len(days) == 0 {
+		return
+	}
+	use24Hour := t.controller.GetSettings().TimeFormat24Hour
+	var b strings.Builder
+	for _, day := range days {
+		fmt.Fprintf(&b, "%s  [yellow]%s[-]  [blue]%s[-]\n", day.Date.Format("Mon Jan 2"),
+			formatRange(day.GoldenEvening, use24Hour), formatRange(day.BlueEvening, use24Hour))
+	}
+	t.days.SetText(b.String())
+	t.body.AddItem(t.days, 0, 2, false)
+}
+
+// UpdateFavorites lists the favorites with tonight's golden hour.
+func (t *Terminal) UpdateFavorites(favorites []domain.Favorite, tonight []domain.TimeRange) {
+	style := t.controller.GetSettings().PlaceNameStyle
+	use24Hour := t.controller.GetSettings().TimeFormat24Hour
+	current := t.favorites.GetCurrentItem()
+	t.favoriteList = favorites
+	t.favorites.Clear()
+	for i, favorite := range favorites {
+		golden := "—"
+		if i < len(tonight) {
+			golden = formatRange(tonight[i], use24Hour)
+		}
+		name := tview.Escape(favorite.Location.DisplayName(style))
+		t.favorites.AddItem(fmt.Sprintf("%-24s %s", name, golden), "", 0, nil)
+	}
+	if len(favorites) == 0 {
+		t.favorites.AddItem("No favorites yet: press f to add the location", "", 0, nil)
+	}
+	t.favorites.SetCurrentItem(current)
+	t.drawHeader()
+}
+
+// UpdateRecentLocations is ignored: the favorites are the saved places of
+// the terminal UI.
+func (t *Terminal) UpdateRecentLocations([]domain.RecentLocation) {}
+
+// UpdatePresets shows the active preset in the header.
+func (t *Terminal) UpdatePresets(domain.Settings) {
+	t.drawHeader()
+}
+
+// ApplySettings redraws the times in the new formats.
+func (t *Terminal) ApplySettings(domain.Settings) {
+	t.drawHeader()
+	t.drawTimes()
+}
+
+// ReloadSettings redraws the times in the restored formats.
+func (t *Terminal) ReloadSettings(settings domain.Settings) {
+	t.ApplySettings(settings)
+}
+
+// SetMinimizeToTray is ignored: there is no tray.
+func (t *Terminal) SetMinimizeToTray(bool) {}
+
+// =============================================================================
+// Result Methods (called by App controller)
+// =============================================================================
+
+// ShowSearchResults offers the results of a search in a list; the
+// selected one becomes the location, Esc closes the list.
+func (t *Terminal) ShowSearchResults(results []domain.SearchResult) {
+	if len(results) == 0 {
+		t.setStatus("No places found", false)
+		return
+	}
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true).SetTitle(fmt.Sprintf(" %d places found ", len(results)))
+	for _, result := range results {
+		label := result.Location.Name
+		if result.Type != "" {
+			label += " (" + result.Type + ")"
+		}
+		list.AddItem(tview.Escape(label), "", 0, nil)
+	}
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		t.closePage(pageResults)
+		t.search.SetText("")
+		t.controller.UpdateLocation(results[index].Location)
+	})
+	list.SetDoneFunc(func() {
+		t.closePage(pageResults)
+	})
+	t.pages.AddPage(pageResults, centered(list, 70, len(results)+2), true, true)
+	t.app.SetFocus(list)
+}
+
+// ShowSunAlignments is ignored: alignment searches start from the map.
+func (t *Terminal) ShowSunAlignments(domain.Location, domain.Location, []domain.SunAlignment) {}
+
+// ShowElevationProfile is ignored: profiles are measured on the map.
+func (t *Terminal) ShowElevationProfile(domain.Location, domain.Location, domain.ElevationProfile) {}
+
+// ShowCloudFrames is ignored: the cloud layer is part of the map.
+func (t *Terminal) ShowCloudFrames([]domain.CloudFrame, domain.TimeRange, bool) {}
+
+// ShowWeekOutlook lists the coming week's golden and blue hours by start
+// time, with the chance of usable light colored like the window's:
+//
+//	             Golden AM    Golden PM    Blue AM      Blue PM
+//	Sat Jun 21   05:47    95% 21:07    55% 04:52    95% 21:58    80%
+func (t *Terminal) ShowWeekOutlook(days []domain.SunTimes, forecast domain.WeatherForecast, err error) {
+	use24Hour := t.controller.GetSettings().TimeFormat24Hour
+	// Each period is 12 columns: the start time (up to "12:47 PM") and
+	// the chance
+	period := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return fmt.Sprintf("%-12s", "--")
+		}
+		start := fmt.Sprintf("%-8s", domain.FormatTime(tr.Start, use24Hour))
+		w, ok := forecast.During(tr)
+		if !ok {
+			return start + "    "
+		}
+		chance := w.ShootProbability()
+		color := "red"
+		switch {
+		case chance >= domain.ShootLikely:
+			color = "green"
+		case chance >= domain.ShootPossible:
+			color = "yellow"
+		}
+		return fmt.Sprintf("%s[%s]%3d%%[-]", start, color, chance)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[::b]%-12s %-12s %-12s %-12s %s[::-]\n", "", "Golden AM", "Golden PM", "Blue AM", "Blue PM")
+	for _, day := range days {
+		fmt.Fprintf(&b, "%-12s %s %s %s %s\n", day.Date.Format("Mon Jan 2"), period(day.GoldenMorning),
+			period(day.GoldenEvening), period(day.BlueMorning), period(day.BlueEvening))
+	}
+	if err != nil {
+		fmt.Fprintf(&b, "\n[gray]No forecast: %s[-]", tview.Escape(err.Error()))
+	} else {
+		b.WriteString("\n[gray]Chance of usable light from the Open-Meteo forecast[-]")
+	}
+
+	text := tview.NewTextView().SetDynamicColors(true).SetText(b.String())
+	text.SetBorder(true).SetTitle(" Week outlook ")
+	text.SetDoneFunc(func(tcell.Key) {
+		t.closePage(pageResults)
+	})
+	t.pages.AddPage(pageResults, centered(text, 68, len(days)+6), true, true)
+	t.app.SetFocus(text)
+}
+
+// ShowUpdateCheck shows the result of an update check in the status line.
+func (t *Terminal) ShowUpdateCheck(latest updates.Release, newer bool) {
+	if !newer {
+		t.setStatus(fmt.Sprintf("GoGoldenHour %s is up to date", t.config.AppVersion), false)
+		return
+	}
+	t.setStatus(fmt.Sprintf("GoGoldenHour %s is available: %s", latest.Version, latest.URL), false)
+}
+
+// ShowHookResult reports an automation hook, push reminder, webhook call
+// or due desktop notification in the status line.
+func (t *Terminal) ShowHookResult(result automation.Result) {
+	label := result.Event.Kind.Label()
+	switch {
+	case result.Err != nil && result.Output != "" && !result.Notification && !result.Webhook && !result.Reminder:
+		t.ShowError(fmt.Sprintf("%s hook failed: %v (%s)", label, result.Err, result.Output))
+	case result.Err != nil:
+		t.ShowError(fmt.Sprintf("%s failed: %v", label, result.Err))
+	case result.Notification:
+		t.setStatus(result.Message, false)
+	case result.Webhook:
+		t.setStatus(fmt.Sprintf("Webhook called: %s at %s", label, result.Output), false)
+	case result.Reminder:
+		t.setStatus(fmt.Sprintf("Pushed reminder: %s", result.Output), false)
+	default:
+		t.setStatus(fmt.Sprintf("%s hook ran at %s", label, time.Now().Format("15:04")), false)
+	}
+}
+
+// ShowSummaryResult reports a daily summary in the status line.
+func (t *Terminal) ShowSummaryResult(result automation.SummaryResult) {
+	if result.Err != nil {
+		t.ShowError(fmt.Sprintf("Daily summary not sent: %v", result.Err))
+		return
+	}
+	t.setStatus(fmt.Sprintf("Daily summary sent to %s at %s", result.Target, time.Now().Format("15:04")), false)
+}
+
+// ShowFeedResult reports a widget feed write in the status line.
+func (t *Terminal) ShowFeedResult(result automation.FeedResult) {
+	if result.Err != nil {
+		t.ShowError(fmt.Sprintf("Widget feed not written: %v", result.Err))
+		return
+	}
+	t.setStatus(fmt.Sprintf("Widget feed written to %s at %s", result.Path, time.Now().Format("15:04")), false)
+}
+
+// ShowPushResult reports a pushed schedule in the status line.
+func (t *Terminal) ShowPushResult(err error) {
+	if err != nil {
+		t.ShowError(fmt.Sprintf("Schedule not pushed: %v", err))
+		return
+	}
+	t.setStatus(fmt.Sprintf("%d-day schedule pushed to the phone", domain.PushScheduleDays), false)
+}
+
+// ShowCalendarSyncResult reports a calendar sync in the status line.
+func (t *Terminal) ShowCalendarSyncResult(result caldav.Result, err error) {
+	if err != nil {
+		t.ShowError(fmt.Sprintf("Calendar not synced: %v", err))
+		return
+	}
+	t.setStatus(fmt.Sprintf("Calendar synced: %d added, %d updated", result.Created, result.Updated), false)
+}
+
+// =============================================================================
+// Status Methods (called by App controller)
+// =============================================================================
+
+// ShowError shows an error in the status line.
+func (t *Terminal) ShowError(message string) {
+	t.ShowRetryableError(message, nil)
+}
+
+// ShowRetryableError shows an error in the status line; with retry, the r
+// key runs the operation again.
+func (t *Terminal) ShowRetryableError(message string, retry func()) {
+	t.retry = retry
+	if retry != nil {
+		message += " (r to retry)"
+	}
+	t.setStatus(message, true)
+}
+
+// SetBusy shows the running network operations in the status line.
+func (t *Terminal) SetBusy(task frontend.Task, busy bool) {
+	if busy {
+		t.busy[task] = true
+	} else {
+		delete(t.busy, task)
+	}
+	t.drawStatus()
+}
+
+// =============================================================================
+// Map Methods (called by App controller)
+// =============================================================================
+
+// MapBounds reports that there is no map, so searches aren't biased to it.
+func (t *Terminal) MapBounds() (domain.Bounds, bool) {
+	return domain.Bounds{}, false
+}
+
+// LocateWithMap answers the map location request with errNoMap, so the
+// detection falls back to the other providers. The answer is queued, as
+// the App expects it after LocateWithMap returned.
+func (t *Terminal) LocateWithMap() {
+	t.QueueOnMainThread(func() {
+		t.controller.OnMapLocate(0, 0, errNoMap)
+	})
+}
+
+// =============================================================================
+// Drawing
+// =============================================================================
+
+// drawHeader shows the location, the selected date and the preset.
+func (t *Terminal) drawHeader() {
+	settings := t.controller.GetSettings()
+	name := tview.Escape(t.location.DisplayName(settings.PlaceNameStyle))
+	if name == "" {
+		name = "Unnamed place"
+	}
+	if _, ok := domain.FavoriteAt(settings.Favorites, t.location); ok {
+		name += " [yellow]★[-]"
+	}
+	fmt.Fprintf(t.header.Clear(), "[::b]%s[::-]  %s  %s\n%s",
+		name, domain.FormatCoordinatesIn(t.location.Latitude, t.location.Longitude,
+			settings.CoordinateFormat), t.location.Timezone,
+		t.controller.GetDate().Format("Monday, January 2, 2006"))
+	if settings.ActivePreset != "" {
+		fmt.Fprintf(t.header, " · Preset: %s", tview.Escape(settings.ActivePreset))
+	}
+}
+
+// drawTimes lists the selected day's events in order, with the forecast
+// weather of the periods, the night's darkness, meteor showers and aurora
+// outlook, and the countdown to the next golden or blue hour.
+func (t *Terminal) drawTimes() {
+	settings := t.controller.GetSettings()
+	use24Hour := settings.TimeFormat24Hour
+	st := t.sunTimes
+	period := func(tr domain.TimeRange) string {
+		text := formatRange(tr, use24Hour)
+		if w, ok := t.weather.During(tr); ok {
+			text += fmt.Sprintf("  [gray]%s, %d%% clouds[-]", w.Condition(), w.CloudCover)
+		}
+		return text
+	}
+	rows := []struct {
+		label, color string
+		text         string
+	}{
+		{"Morning blue hour", "blue", period(st.BlueMorning)},
+		{"Sunrise", "white", formatTime(st.Sunrise, use24Hour)},
+		{"Morning golden hour", "yellow", period(st.GoldenMorning)},
+		{"Solar noon", "white", formatTime(st.SolarNoon, use24Hour)},
+		{"Evening golden hour", "yellow", period(st.GoldenEvening)},
+		{"Sunset", "white", formatTime(st.Sunset, use24Hour)},
+		{"Evening blue hour", "blue", period(st.BlueEvening)},
+	}
+	t.times.Clear()
+	for _, row := range rows {
+		fmt.Fprintf(t.times, "[%s]%-20s[-] %s\n", row.color, row.label, row.text)
+	}
+	for _, custom := range st.Custom {
+		fmt.Fprintf(t.times, "%-20s %s, %s\n", tview.Escape(custom.Event.Name),
+			formatTime(custom.Morning, use24Hour), formatTime(custom.Evening, use24Hour))
+	}
+	fmt.Fprintf(t.times, "\nShooting light %s\n", st.FormatShootingWindow())
+	if t.aurora.Dark.IsValid() {
+		fmt.Fprintf(t.times, "%-20s %s\n", "Dark sky", formatRange(t.aurora.Dark, use24Hour))
+	} else {
+		fmt.Fprintf(t.times, "%-20s %s\n", "Dark sky", "none (bright night)")
+	}
+	for _, night := range t.meteors {
+		color := "white"
+		if night.Good() {
+			color = "green"
+		}
+		fmt.Fprintf(t.times, "[%s]%-20s[-] %s\n", color, "Meteors", night.Summary(use24Hour))
+	}
+	if settings.AuroraForecast {
+		text := t.aurora.Summary()
+		if !t.aurora.Kp.Time.IsZero() {
+			text += fmt.Sprintf("  [gray]Kp %s[-]", domain.FormatKp(t.aurora.Kp.Kp))
+		}
+		color := "white"
+		switch t.aurora.Visibility {
+		case domain.AuroraOverhead:
+			color = "green"
+		case domain.AuroraHorizon:
+			color = "orange"
+		}
+		fmt.Fprintf(t.times, "[%s]%-20s[-] %s\n", color, "Aurora", text)
+	}
+	if t.countdownDays != nil {
+		zone := time.Local
+		if loc, err := time.LoadLocation(t.location.Timezone); err == nil {
+			zone = loc
+		}
+		fmt.Fprintf(t.times, "\n[::b]%s[::-]", domain.LightStatus(t.countdownDays, time.Now().In(zone), use24Hour))
+	}
+}
+
+// drawStatus shows the message, the running operations and the keys.
+func (t *Terminal) drawStatus() {
+	var parts []string
+	for _, task := range []frontend.Task{frontend.TaskSearch, frontend.TaskDetect, frontend.TaskLookup} {
+		if t.busy[task] {
+			parts = append(parts, "[green]"+string(task)+"…[-]")
+		}
+	}
+	if t.message != "" {
+		color := "white"
+		if t.isError {
+			color = "red"
+		}
+		parts = append(parts, fmt.Sprintf("[%s]%s[-]", color, tview.Escape(t.message)))
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "[gray]"+keyHelp+"[-]")
+	}
+	t.status.SetText(strings.Join(parts, " · "))
+}
+
+// setStatus shows a message in the status line until the next one.
+func (t *Terminal) setStatus(message string, isError bool) {
+	t.message, t.isError = message, isError
+	t.drawStatus()
+}
+
+// showDialog shows text with buttons over the main page. done receives
+// the index of the chosen button (nil: the dialog just closes).
+func (t *Terminal) showDialog(text string, buttons []string, done func(button int)) {
+	modal := tview.NewModal().SetText(text).AddButtons(buttons)
+	modal.SetDoneFunc(func(index int, _ string) {
+		t.closePage(pageDialog)
+		if done != nil && index >= 0 {
+			done(index)
+		}
+	})
+	t.pages.AddPage(pageDialog, modal, true, true)
+	t.app.SetFocus(modal)
+}
+
+// closePage removes a results list or dialog and gives the focus back to
+// the favorites.
+func (t *Terminal) closePage(name string) {
+	t.pages.RemovePage(name)
+	t.app.SetFocus(t.favorites)
+}
+
+// centered places p in the middle of the screen at the given size.
+func centered(p tview.Primitive, width, height int) tview.Primitive {
+	return tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(p, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+}
+
+// formatTime formats a time of the day, or "—" if the event doesn't occur.
+func formatTime(t time.Time, use24Hour bool) string {
+	if t.IsZero() {
+		return "—"
+	}
+	return domain.FormatTime(t, use24Hour)
+}
+
+// formatRange formats a golden or blue hour, e.g., "20:15 – 21:00", or "—"
+// if it doesn't occur.
+func formatRange(tr domain.TimeRange, use24Hour bool) string {
+	if !tr.IsValid() {
+		return "—"
+	}
+	return domain.FormatTime(tr.Start, use24Hour) + " – " + domain.FormatTime(tr.End, use24Hour)
+}
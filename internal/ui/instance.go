@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"fmt"
+	"log"
+	"os/user"
+	"strings"
+
+	"github.com/mappu/miqt/qt6/network"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/storage"
+)
+
+// =============================================================================
+// Single Instance
+// =============================================================================
+//
+// Only one window runs per profile. Two windows would each keep their own
+// copy of the settings and overwrite each other's saves, so a second start
+// (e.g., a clicked share link) hands its link to the running window and
+// exits:
+//
+//	second start                          running window
+//	────────────                          ──────────────
+//	ForwardToRunningInstance ──socket──►  ListenForInstances
+//	  "gogoldenhour://?lat=…\n"             raise the window, open the link
+//	  exit                                  (an empty line only raises it)
+//
+// The local socket (a Unix domain socket, a named pipe on Windows) is named
+// per user and profile (see instanceServerName), so other users and other
+// profiles run their own windows.
+
+// instanceTimeout is how long a second start waits for the running
+// window, in milliseconds. A window that doesn't answer in time is taken
+// to be gone (e.g., a socket left behind by a crash).
+const instanceTimeout = 1000
+
+// instanceServerName returns the local socket name of a profile's window,
+// e.g., "gogoldenhour-alice-work".
+func instanceServerName(profile string) string {
+	name := "gogoldenhour"
+	if u, err := user.Current(); err == nil {
+		name += "-" + u.Username
+	}
+	if profile != storage.DefaultProfile {
+		name += "-" + profile
+	}
+	// Windows user names may hold a domain ("WORKGROUP\alice"), which a
+	// pipe name can't; keep to portable characters
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == '_' || r == '.' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// ForwardToRunningInstance hands the command line's link to the profile's
+// running window, which comes to the front and opens it.
+//
+// Called from main after the QApplication is created and the profile is
+// known, before anything reads or writes the profile's settings.
+//
+// Parameters:
+//   - profile: The settings profile
+//   - link: The share link given on the command line, or nil to only raise
+//     the window
+//
+// Returns true if a window of the profile is running; the caller exits
+// then.
+//
+// miqt API notes:
+//   - WaitForConnectedWithMsecs, WaitForBytesWritten: Block without an
+//     event loop, which this start never runs
+func ForwardToRunningInstance(profile string, link *domain.Link) bool {
+	socket := network.NewQLocalSocket()
+	defer socket.Delete()
+	socket.ConnectToServerWithName(instanceServerName(profile))
+	if !socket.WaitForConnectedWithMsecs(instanceTimeout) {
+		return false
+	}
+	message := ""
+	if link != nil {
+		message = link.URL()
+	}
+	socket.Write2([]byte(message + "\n"))
+	if !socket.WaitForBytesWritten(instanceTimeout) {
+		log.Printf("Warning: the running window didn't take the link: %s", socket.ErrorString())
+	}
+	socket.DisconnectFromServer()
+	return true
+}
+
+// ListenForInstances makes the window the profile's running instance:
+// later starts with the same profile forward their link to it (see
+// ForwardToRunningInstance) instead of opening a second window.
+//
+// Called from main after ForwardToRunningInstance found no running window,
+// so a socket left behind by a crashed one is removed first.
+//
+// Parameters:
+//   - profile: The settings profile
+//
+// Returns an error if the socket can't be created; the window works
+// without it, but later starts open windows of their own.
+//
+// miqt API notes:
+//   - NewQLocalServer2(parent): The server lives as long as the window
+//   - UserAccessOption: Only the user's own programs may connect
+//   - ReadLine2(): The next line, with its "\n"
+func (mw *MainWindow) ListenForInstances(profile string) error {
+	name := instanceServerName(profile)
+	server := network.NewQLocalServer2(mw.window.QObject)
+	server.SetSocketOptions(network.QLocalServer__UserAccessOption)
+	network.QLocalServer_RemoveServer(name)
+	if !server.Listen(name) {
+		err := fmt.Errorf("failed to listen on %s: %s", name, server.ErrorString())
+		server.Delete()
+		return err
+	}
+
+	server.OnNewConnection(crash.Guard(func() {
+		for server.HasPendingConnections() {
+			socket := server.NextPendingConnection()
+			socket.OnReadyRead(crash.Guard(func() {
+				if !socket.CanReadLine() {
+					return // the rest of the line is on its way
+				}
+				text := strings.TrimSpace(string(socket.ReadLine2()))
+				socket.DisconnectFromServer()
+				mw.onInstanceStarted(text)
+			}))
+			socket.OnDisconnected(func() {
+				socket.DeleteLater()
+			})
+		}
+	}))
+	mw.instanceServer = server
+	return nil
+}
+
+// onInstanceStarted handles a second start of the profile: the window
+// comes to the front (also out of the tray) and opens the start's link.
+//
+// Parameters:
+//   - text: The share link, or empty if the start had none
+func (mw *MainWindow) onInstanceStarted(text string) {
+	mw.showWindow()
+	if text == "" {
+		return
+	}
+	if err := mw.controller.OpenLinkText(text); err != nil {
+		mw.ShowError(fmt.Sprintf("Couldn't open the link: %v", err))
+	}
+}
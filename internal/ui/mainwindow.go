@@ -11,10 +11,14 @@
 //	MainWindow
 //	├── MapView (Qt WebEngine + Leaflet.js)
 //	├── LocationPanel (search, detect, display)
-//	├── DatePanel (navigation, calendar)
+//	├── DatePanel (navigation, calendar, date range)
 //	├── TimePanel (golden/blue hour display)
+//	├── TimeScrubber (sun position at any time of the day)
+//	├── FavoritesPanel (tonight's golden hour at each favorite)
+//	├── DaysPanel (each day of a date range)
 //	├── SettingsPanel (elevation angles, preferences)
-//	└── StatusBar (messages, errors)
+//	├── MenuBar (File, Edit → PreferencesDialog, View, Help → What's New)
+//	└── StatusBar (messages, errors, privacy mode, cursor coordinates)
 //
 // # Communication Pattern
 //
@@ -42,17 +46,37 @@
 // # Thread Safety
 //
 // All UI operations must happen on the main Qt thread. The App controller
-// ensures this by using mainthread.Wait() for any operations that update
-// the UI from background goroutines.
+// ensures this by handing updates from background goroutines over with
+// RunOnMainThread (mainthread.Wait; see frontend.View).
 package ui
 
 import (
+	"cmp"
 	"fmt"
+	"path/filepath"
+	"strings"
 	"time"
 
 	qt "github.com/mappu/miqt/qt6"
+	"github.com/mappu/miqt/qt6/mainthread"
+	"github.com/mappu/miqt/qt6/network"
+	"github.com/megatih/GoGoldenHour/internal/changelog"
 	"github.com/megatih/GoGoldenHour/internal/config"
+	"github.com/megatih/GoGoldenHour/internal/crash"
 	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/events"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/frontend"
+	"github.com/megatih/GoGoldenHour/internal/service/automation"
+	"github.com/megatih/GoGoldenHour/internal/service/caldav"
+	"github.com/megatih/GoGoldenHour/internal/service/geocoding"
+	"github.com/megatih/GoGoldenHour/internal/service/photo"
+	"github.com/megatih/GoGoldenHour/internal/service/tilecache"
+	"github.com/megatih/GoGoldenHour/internal/service/timezone"
+	"github.com/megatih/GoGoldenHour/internal/service/updates"
+	"github.com/megatih/GoGoldenHour/internal/service/weather"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+	"github.com/megatih/GoGoldenHour/internal/storage"
 	"github.com/megatih/GoGoldenHour/internal/ui/widgets"
 )
 
@@ -60,58 +84,12 @@ import (
 // AppController Interface
 // =============================================================================
 
-// AppController defines the interface for application control.
-//
-// This interface allows the MainWindow to communicate user actions to the
-// application controller without knowing the concrete implementation.
-// It's implemented by app.App.
-//
-// The interface includes:
-//   - Action methods: DetectLocation, SearchLocation, OnMapClick
-//   - Update methods: UpdateLocation, UpdateDate, UpdateSettings
-//   - Query methods: GetSettings, GetLocation, GetDate
-//
-// This interface enables:
-//   - Loose coupling between UI and application logic
-//   - Easier testing (can mock the controller)
-//   - Clear contract for UI-to-app communication
-type AppController interface {
-	// DetectLocation initiates IP-based location detection.
-	// Called when user clicks "Detect My Location" button.
-	DetectLocation()
-
-	// UpdateLocation changes the current location.
-	// Called after search results or map clicks.
-	UpdateLocation(loc domain.Location)
-
-	// UpdateDate changes the date for calculations.
-	// Called when user navigates dates or uses calendar.
-	UpdateDate(date time.Time)
-
-	// UpdateSettings applies new user preferences.
-	// Called when settings panel values change.
-	UpdateSettings(settings domain.Settings)
-
-	// SearchLocation performs geocoding search.
-	// Called when user submits a location query.
-	SearchLocation(query string)
-
-	// OnMapClick handles map click events.
-	// Called when user clicks on the map.
-	OnMapClick(lat, lon float64)
+// AppController is the application controller the MainWindow reports user
+// actions to (see frontend.Controller, where its methods are documented).
+type AppController = frontend.Controller
 
-	// GetSettings returns current settings.
-	// Used for initializing UI components.
-	GetSettings() domain.Settings
-
-	// GetLocation returns current location.
-	// Used for initializing UI components.
-	GetLocation() domain.Location
-
-	// GetDate returns current calculation date.
-	// Used for initializing UI components.
-	GetDate() time.Time
-}
+// Compile-time check that MainWindow is a frontend.View.
+var _ frontend.View = (*MainWindow)(nil)
 
 // =============================================================================
 // MainWindow
@@ -129,6 +107,10 @@ type AppController interface {
 //
 //	┌────────────────────────────────────────────────────────────────────┐
 //	│                    GoGoldenHour - Golden & Blue Hour Calculator    │
+//	├────────────────────────────────────────────────────────────────────┤
+//	│  File  Edit  View                                                  │
+//	├────────────────────────────────────────────────────────────────────┤
+//	│  Preset: [ Landscape ▾ ] [Save As...] [Delete]                     │
 //	├────────────────────────────────┬───────────────────────────────────┤
 //	│                                │  ┌─────────────────────────────┐  │
 //	│                                │  │  Location Panel             │  │
@@ -142,13 +124,22 @@ type AppController interface {
 //	│                                │  │  Time Panel                 │  │
 //	│                                │  │  Golden Hour | Blue Hour    │  │
 //	│                                │  └─────────────────────────────┘  │
-//	│                                │                                   │
+//	│                                │  ┌─────────────────────────────┐  │
+//	│                                │  │  Astro: Dark, Aurora        │  │
+//	│                                │  └─────────────────────────────┘  │
 //	│                                │  ┌─────────────────────────────┐  │
 //	│                                │  │  Settings (collapsible)     │  │
 //	│                                │  └─────────────────────────────┘  │
 //	├────────────────────────────────┴───────────────────────────────────┤
-//	│  Status: Location name or error message                            │
+//	│  Status: Location name or error message       Cursor coordinates   │
 //	└────────────────────────────────────────────────────────────────────┘
+//
+// The panels on the right sit in five dock widgets (Location, Date, Times,
+// Astro, Settings; see addPanelDock), which the user can drag to either side of
+// the map, stack as tabs, float as windows of their own or close (View →
+// Panels brings them back). Their arrangement is saved when the app quits
+// (Settings.PanelLayout). In the compact layout (View → Layout; see
+// updateCompactLayout) the docks are tabbed below the map instead.
 type MainWindow struct {
 	// window is the top-level Qt main window.
 	// Provides title bar, status bar, and central widget area.
@@ -174,17 +165,137 @@ type MainWindow struct {
 	// Shows golden hour and blue hour in side-by-side columns.
 	timePanel *widgets.TimePanel
 
+	// timeScrubber is the time slider showing the sun's position at any
+	// time of the selected day.
+	timeScrubber *widgets.TimeScrubber
+
+	// scrubberLocation is where the time slider's sun ray starts on the
+	// map: the location of the positions it was given.
+	scrubberLocation domain.Location
+
+	// countdownPanel counts down to the next golden or blue hour at the
+	// location, today.
+	countdownPanel *widgets.CountdownPanel
+
+	// timelinePanel lists the selected day's events, optionally across
+	// midnight to the next noon.
+	timelinePanel *widgets.TimelinePanel
+
+	// astroPanel shows the selected date's dark hours and, optionally, its
+	// aurora outlook.
+	astroPanel *widgets.AstroPanel
+
+	// favoritesPanel lists the favorites with tonight's golden hour start.
+	// Hidden while there are no favorites.
+	favoritesPanel *widgets.FavoritesPanel
+
 	// datePanel provides date navigation.
-	// Contains prev/next buttons, date picker, and today button.
+	// Contains prev/next buttons, date picker, today button and the end of
+	// a date range.
 	datePanel *widgets.DatePanel
 
+	// daysPanel lists each day of the date range. Hidden while a single
+	// day is planned.
+	daysPanel *widgets.DaysPanel
+
 	// settingsPanel allows adjusting elevation angles and preferences.
 	// Starts collapsed to save space; can be expanded by user.
 	settingsPanel *widgets.SettingsPanel
 
-	// statusLabel displays status messages and errors.
-	// Located in the status bar at the bottom of the window.
-	statusLabel *qt.QLabel
+	// statusArea shows status messages, errors, the progress of network
+	// operations and the current location in the status bar at the bottom
+	// of the window.
+	statusArea *widgets.StatusArea
+
+	// toast shows errors over the bottom of the window, with a Retry
+	// button for failed operations that can be repeated.
+	toast *widgets.Toast
+
+	// cursorLabel shows the coordinates under the pointer while it is over
+	// the map, at the right end of the status bar.
+	cursorLabel *qt.QLabel
+
+	// privacyLabel marks privacy mode in the status bar; hidden while it
+	// is off.
+	privacyLabel *qt.QLabel
+
+	// privacyAction is the Edit menu's checkable privacy mode toggle.
+	privacyAction *qt.QAction
+
+	// presetBar is the toolbar that switches between presets of elevation
+	// angles and custom events.
+	presetBar *widgets.PresetBar
+
+	// trayIcon is the system tray icon with the next light in its tooltip;
+	// nil on desktops without a system tray.
+	trayIcon *widgets.TrayIcon
+
+	// minimizeToTray hides the window in the tray when it is minimized
+	// (Settings.MinimizeToTray).
+	minimizeToTray bool
+
+	// instanceServer receives the links of later starts of the profile
+	// (see ListenForInstances); nil until it listens.
+	instanceServer *network.QLocalServer
+
+	// docks are the dock widgets holding the info panels, in View → Panels
+	// order.
+	docks []*qt.QDockWidget
+
+	// fullscreenDocks are the docks hidden by full-screen map mode, shown
+	// again when it ends.
+	fullscreenDocks []*qt.QDockWidget
+
+	// defaultPanelLayout is the panels' arrangement before the saved one was
+	// restored, for View → Reset Panel Layout.
+	defaultPanelLayout []byte
+
+	// windowLayout is the layout picked in View → Layout
+	// (Settings.WindowLayout).
+	windowLayout string
+
+	// compact is true while the docks are tabbed below the map.
+	compact bool
+
+	// wideLayout is the docks' arrangement before the compact layout moved
+	// them, restored when it ends and saved in its place on quit.
+	wideLayout []byte
+
+	// fullscreenAction is the View menu's checkable full-screen map toggle.
+	fullscreenAction *qt.QAction
+
+	// favoriteAction is the Tools menu's checkable favorite toggle, checked
+	// while the current location is a favorite (like the location panel's
+	// star).
+	favoriteAction *qt.QAction
+
+	// mapFullscreen is true while the map fills the window.
+	mapFullscreen bool
+
+	// sunTimes holds the last displayed sun times, for the full-screen overlay.
+	sunTimes domain.SunTimes
+
+	// cameraDialog adjusts the camera placed in the map's camera mode.
+	// Created on first placement and reused afterwards.
+	cameraDialog *widgets.CameraDialog
+
+	// monthDialog is the month calendar (View → Month Calendar), created
+	// when first opened.
+	monthDialog *widgets.MonthDialog
+
+	// weekDialog is the week outlook (View → Week Outlook), created when
+	// first opened.
+	weekDialog *widgets.WeekDialog
+
+	// dayExportOptions are the columns and time format of the last date
+	// range export, offered again by the next.
+	dayExportOptions export.DaysOptions
+
+	// camera is the camera shown in the map's field of view overlay.
+	camera domain.Camera
+
+	// cameraRange is the length of the field of view cone in meters.
+	cameraRange float64
 }
 
 // =============================================================================
@@ -196,25 +307,26 @@ type MainWindow struct {
 // This constructor:
 //  1. Creates the MainWindow struct
 //  2. Calls setupUI() to create and arrange all widgets
-//  3. Returns the fully initialized window (but not yet shown)
+//  3. Subscribes the widgets to the App's events (subscribeEvents)
+//  4. Returns the fully initialized window (but not yet shown)
 //
 // Parameters:
 //   - cfg: Application configuration with window size and initial settings
 //   - controller: The AppController for handling user actions
 //
 // Returns the created MainWindow. Call Show() to make it visible.
-//
-// Note: The SettingsPanel may trigger OnValueChanged callbacks during
-// construction when applySettings() is called. The App controller handles
-// this by checking if mainWindow is nil before using it.
 func NewMainWindow(cfg config.AppConfig, controller AppController) *MainWindow {
 	mw := &MainWindow{
 		config:     cfg,
 		controller: controller,
+		// 24-hour times read best in spreadsheets
+		dayExportOptions: export.DaysOptions{Use24Hour: true},
 	}
 
-	// Create and arrange all UI components
+	// Create and arrange all UI components, which then follow the App's
+	// changes
 	mw.setupUI()
+	mw.subscribeEvents()
 	return mw
 }
 
@@ -227,13 +339,17 @@ func NewMainWindow(cfg config.AppConfig, controller AppController) *MainWindow {
 // This method builds the complete UI hierarchy:
 //  1. Creates main window with title and size
 //  2. Creates central widget with main layout
-//  3. Creates horizontal splitter (map | info panels)
-//  4. Creates all widgets with their callbacks
+//  3. Creates the map as the central content
+//  4. Creates all widgets with their callbacks, the info panels in dock
+//     widgets right of the map
 //  5. Sets up status bar
+//  6. Sets up the menu bar and the preset toolbar
+//  7. Restores the panels' saved arrangement
+//  8. Sets up the system tray icon
 //
 // Layout uses Qt's layout system:
-//   - QSplitter: Divides window between map and info panels
-//   - QVBoxLayout: Stacks info panels vertically
+//   - QMainWindow dock areas: Hold the info panels around the map
+//   - QVBoxLayout: Stacks the panels within a dock
 //   - Individual widgets handle their internal layout
 //
 // miqt API notes:
@@ -247,7 +363,12 @@ func (mw *MainWindow) setupUI() {
 	// =========================================================================
 	// Create top-level window with title and size constraints
 	mw.window = qt.NewQMainWindow(nil)
-	mw.window.SetWindowTitle("GoGoldenHour - Golden & Blue Hour Calculator")
+	title := "GoGoldenHour - Golden & Blue Hour Calculator"
+	if mw.config.Profile != "" {
+		// Tell windows of different profiles apart
+		title = "GoGoldenHour [" + mw.config.Profile + "] - Golden & Blue Hour Calculator"
+	}
+	mw.window.SetWindowTitle(title)
 	mw.window.Resize(mw.config.WindowWidth, mw.config.WindowHeight)
 	// SetMinimumSize2 uses integer overload (suffix "2" in miqt)
 	mw.window.SetMinimumSize2(800, 600)
@@ -262,245 +383,2358 @@ func (mw *MainWindow) setupUI() {
 	mainLayout.SetSpacing(10)
 
 	// =========================================================================
-	// Splitter (Map | Info Panels)
-	// =========================================================================
-	// Horizontal splitter allows user to resize between map and info panels
-	splitter := qt.NewQSplitter(nil)
-	splitter.SetOrientation(qt.Horizontal)
-
-	// =========================================================================
-	// Left Side: Interactive Map
+	// Central Content: Interactive Map
 	// =========================================================================
-	// Create map view with click handler callback
-	mw.mapView = widgets.NewMapView(mw.onMapClick)
-	splitter.AddWidget(mw.mapView.Widget())
+	// Create map view with click, measurement, alignment, and zoom handler callbacks
+	mw.mapView = widgets.NewMapView(mw.onMapClick, mw.onMarkerDrag, mw.onPointClick, mw.onMeasure, mw.onAlign, mw.onPlaceCamera, mw.onMapZoom,
+		mw.toggleMapFullscreen, mw.onMapLocate, mw.onMapCursor, mw.controller.FetchCloudFrames)
+	// Restore the zoom level and tile server from the last session (also
+	// for the default server, whose attribution comes from its provider)
+	mw.mapView.SetZoom(mw.config.Settings.MapZoom)
+	mw.setTileServer(mw.config.Settings.TileServer)
+	mw.mapView.SetPrivacyMode(mw.config.Settings.PrivacyMode)
+	// Favorites are a point layer of their own
+	mw.mapView.SetPoints(favoritesLayer, favoritePoints(mw.config.Settings.Favorites))
+	mainLayout.AddWidget(mw.mapView.Widget())
 
 	// =========================================================================
-	// Right Side: Info Panels
+	// Info Panels (dock widgets right of the map)
 	// =========================================================================
-	rightPanel := qt.NewQWidget(nil)
-	rightLayout := qt.NewQVBoxLayout(rightPanel)
-	rightLayout.SetContentsMargins(0, 0, 0, 0)
-	rightLayout.SetSpacing(8)
 
 	// Location panel: Search and location display
-	// Callbacks: onLocationSearch (search button/enter), onDetectLocation (detect button)
-	mw.locationPanel = widgets.NewLocationPanel(mw.onLocationSearch, mw.onDetectLocation)
-	rightLayout.AddWidget(mw.locationPanel.Widget().QWidget)
+	// Callbacks: onLocationSearch (search button/enter), onSearchResultSelected
+	// (choice from the results dropdown), onDetectLocation (detect button),
+	// ToggleFavorite (star next to the name), onCancelSearch and
+	// onCancelDetect (the busy Go and Detect buttons)
+	mw.locationPanel = widgets.NewLocationPanel(mw.onLocationSearch, mw.onSearchResultSelected, mw.onDetectLocation,
+		mw.controller.ToggleFavorite, mw.controller.ClearRecentLocations, mw.onCancelSearch, mw.onCancelDetect)
+	mw.locationPanel.SetCoordinateFormat(mw.config.Settings.CoordinateFormat)
+	mw.locationPanel.SetPlaceNameStyle(mw.config.Settings.PlaceNameStyle)
+	mw.locationPanel.SetRecentLocations(mw.config.Settings.RecentLocations)
+
+	// Countdown panel: live countdown to the next golden or blue hour,
+	// with the days filled in by the App (UpdateCountdown)
+	// Callback: RefreshCountdown (midnight passed)
+	mw.countdownPanel = widgets.NewCountdownPanel(mw.config.Settings.TimeFormat24Hour, mw.controller.RefreshCountdown)
 
 	// Date panel: Date navigation with calendar
-	// Callback: onDateChanged (any date change)
-	mw.datePanel = widgets.NewDatePanel(mw.onDateChanged)
-	rightLayout.AddWidget(mw.datePanel.Widget().QWidget)
+	// Callbacks: onDateChanged (any date change), UpdateDateRange ("Until")
+	mw.datePanel = widgets.NewDatePanel(mw.onDateChanged, mw.controller.UpdateDateRange)
 
 	// Time panel: Golden and blue hour display in side-by-side columns
 	// No callback - this is a display-only widget
-	mw.timePanel = widgets.NewTimePanel(mw.config.Settings.TimeFormat24Hour)
-	rightLayout.AddWidget(mw.timePanel.Widget().QWidget)
+	mw.timePanel = widgets.NewTimePanel(mw.config.Settings.TimeFormat24Hour, mw.onAddToGoogleCalendar,
+		mw.onAddToCalendar)
+	mw.timePanel.SetHomeTimezone(mw.config.Settings.HomeTimezone)
+	mw.timePanel.SetTeachingMode(mw.config.Settings)
+	mw.timePanel.SetLayout(mw.config.Settings.TimePanelLayout)
 
-	// Add stretch to push settings panel to the bottom
-	// This keeps the settings collapsed at the bottom of the panel
-	rightLayout.AddStretch()
+	// Time scrubber: sun position at the slider's time, filled in by the
+	// App (UpdateSunPositions)
+	// Callback: onScrub (slider moved)
+	mw.timeScrubber = widgets.NewTimeScrubber(mw.config.Settings.TimeFormat24Hour, mw.onScrub)
 
-	// Settings panel: Elevation angles and preferences
-	// Callback: onSettingsChanged (any setting change)
-	// Note: This may trigger callback during construction (applySettings)
-	mw.settingsPanel = widgets.NewSettingsPanel(mw.config.Settings, mw.onSettingsChanged)
-	rightLayout.AddWidget(mw.settingsPanel.Widget().QWidget)
+	// Timeline panel: the day's events in order, filled in by the App
+	// (UpdateTimeline). No callback - the mode checkbox is handled inside
+	mw.timelinePanel = widgets.NewTimelinePanel()
 
-	splitter.AddWidget(rightPanel)
+	// Astro panel: the night's darkness and aurora outlook, filled in by
+	// the App (UpdateAurora). No callback - this is a display-only widget
+	mw.astroPanel = widgets.NewAstroPanel(mw.config.Settings.TimeFormat24Hour)
+	mw.astroPanel.SetAuroraEnabled(mw.config.Settings.AuroraForecast)
 
-	// =========================================================================
-	// Splitter Proportions
-	// =========================================================================
-	// Set initial sizes: 60% for map (480px), 40% for info (320px)
-	// User can drag the splitter to adjust these proportions
-	splitter.SetSizes([]int{480, 320})
+	// Favorites panel: favorites with tonight's golden hour start
+	// Callback: UpdateLocation (click on a favorite). The times are filled
+	// in by the App's first calculation (UpdateFavorites).
+	mw.favoritesPanel = widgets.NewFavoritesPanel(mw.controller.UpdateLocation, mw.onEditFavorite)
+	mw.favoritesPanel.SetFavorites(mw.config.Settings.Favorites, nil, mw.config.Settings.TimeFormat24Hour,
+		mw.config.Settings.PlaceNameStyle)
+
+	// Days panel: each day of a date range, filled in by the App
+	// (UpdateDateRange). No callback - this is a display-only widget
+	mw.daysPanel = widgets.NewDaysPanel()
+
+	// Settings panel: Elevation angles and preferences
+	// Callbacks: onSettingsChanged (any setting change),
+	// onCopyConfigCode / onPasteConfigCode (config code sharing),
+	// onShowPreferences (all other settings),
+	// onRestoreSettings (damaged settings file recovery)
+	mw.settingsPanel = widgets.NewSettingsPanel(mw.config.Settings, mw.onSettingsChanged,
+		mw.onCopyConfigCode, mw.onPasteConfigCode, mw.onShowPreferences,
+		func() { mw.onRestoreSettings(true) }, func() { mw.onRestoreSettings(false) })
 
-	// Add splitter to main layout (use .QWidget for layout compatibility)
-	mainLayout.AddWidget(splitter.QWidget)
+	// Docks, stacked right of the map in this order. The favorites and the
+	// days of a date range hide themselves while empty, so each shares a
+	// dock with the panel it belongs to.
+	mw.addPanelDock("locationDock", "Location", mw.locationPanel.Widget().QWidget,
+		mw.favoritesPanel.Widget().QWidget)
+	mw.addPanelDock("dateDock", "Date", mw.datePanel.Widget().QWidget, mw.daysPanel.Widget().QWidget)
+	mw.addPanelDock("timesDock", "Times", mw.countdownPanel.Widget().QWidget, mw.timePanel.Widget().QWidget,
+		mw.timeScrubber.Widget().QWidget, mw.timelinePanel.Widget().QWidget)
+	mw.addPanelDock("astroDock", "Astro", mw.astroPanel.Widget().QWidget)
+	mw.addPanelDock("settingsDock", "Settings", mw.settingsPanel.Widget().QWidget)
 
 	// =========================================================================
 	// Status Bar
 	// =========================================================================
-	// Create status label for messages and errors
-	// NewQLabel3("") creates label with empty initial text (suffix "3" = text param)
-	mw.statusLabel = qt.NewQLabel3("")
+	// Transient messages and errors, the progress indicator and the
+	// location label (see widgets.StatusArea)
 	statusBar := mw.window.StatusBar()
-	// AddPermanentWidget keeps the label visible (not replaced by temporary messages)
-	statusBar.AddPermanentWidget(mw.statusLabel.QWidget)
+	mw.statusArea = widgets.NewStatusArea(statusBar)
+	// Privacy mode indicator, between the location and the cursor
+	// coordinates so it stays in view however long the status is
+	mw.privacyLabel = qt.NewQLabel3("Privacy mode")
+	mw.privacyLabel.SetStyleSheet("background: #37474f; color: white; border-radius: 4px; padding: 1px 6px;")
+	mw.privacyLabel.SetToolTip("No requests to online services: searches use the offline city database, " +
+		"the map shows cached tiles, and location detection only uses the system location services. " +
+		"Turn it off in Edit → Privacy Mode.")
+	mw.privacyLabel.SetVisible(mw.config.Settings.PrivacyMode)
+	statusBar.AddPermanentWidget(mw.privacyLabel.QWidget)
+	// Cursor coordinates at the right end: the fixed width keeps the other
+	// labels from jumping while the numbers change
+	mw.cursorLabel = qt.NewQLabel3("")
+	mw.cursorLabel.SetAlignment(qt.AlignRight | qt.AlignVCenter)
+	mw.cursorLabel.SetMinimumWidth(mw.cursorLabel.FontMetrics().HorizontalAdvance("000.0000° N, 000.0000° W") + 8)
+	statusBar.AddPermanentWidget(mw.cursorLabel.QWidget)
+
+	// Error toasts float over the content; the central widget keeps them
+	// at its bottom when resized
+	mw.toast = widgets.NewToast(centralWidget)
+	centralWidget.OnResizeEvent(func(super func(event *qt.QResizeEvent), event *qt.QResizeEvent) {
+		defer crash.Recover()
+		super(event)
+		mw.toast.Reposition()
+	})
 
 	// Set central widget to complete window setup
 	mw.window.SetCentralWidget(centralWidget)
+
+	mw.setupMenus()
+	mw.setupPresetBar()
+	mw.setupShortcuts()
+	mw.restorePanelLayout()
+	mw.setupTray()
 }
 
-// =============================================================================
-// Window Control
-// =============================================================================
+// addPanelDock puts info panels into a dock widget right of the map and
+// lists it in View → Panels.
+//
+// Parameters:
+//   - name: The dock's object name, which identifies it in the saved
+//     layout; must stay the same across versions
+//   - title: The dock's title bar text
+//   - panels: The panels' widgets, stacked top to bottom
+//
+// miqt API notes:
+//   - SetObjectName takes a QAnyStringView (value, not pointer)
+//   - AddDockWidget(area, dock): Docks below those already in the area
+//   - All features (closable, movable, floatable) are on by default
+func (mw *MainWindow) addPanelDock(name, title string, panels ...*qt.QWidget) {
+	content := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(content)
+	layout.SetContentsMargins(0, 0, 0, 0)
+	layout.SetSpacing(8)
+	for _, panel := range panels {
+		layout.AddWidget(panel)
+	}
+	// Keeps the panels at the top of a dock taller than they are
+	layout.AddStretch()
+
+	// The panels scroll in a dock lower than they are, so a tall dock
+	// never makes the window grow beyond a small screen
+	scroll := qt.NewQScrollArea2()
+	scroll.SetWidgetResizable(true)
+	scroll.SetFrameShape(qt.QFrame__NoFrame)
+	scroll.SetHorizontalScrollBarPolicy(qt.ScrollBarAlwaysOff)
+	scroll.SetWidget(content)
 
-// Show makes the main window visible on screen.
+	dock := qt.NewQDockWidget2(title)
+	dock.SetObjectName(*qt.NewQAnyStringView3(name))
+	dock.SetAllowedAreas(qt.LeftDockWidgetArea | qt.RightDockWidgetArea | qt.BottomDockWidgetArea)
+	dock.SetWidget(scroll.QWidget)
+	mw.window.AddDockWidget(qt.RightDockWidgetArea, dock)
+	mw.docks = append(mw.docks, dock)
+}
+
+// restorePanelLayout arranges the docks and toolbars as they were when the
+// app last quit (Settings.PanelLayout), applies the window layout
+// (Settings.WindowLayout, again whenever the window is resized), and saves
+// the docks' arrangement and the session (see openPanels) when the app
+// quits.
 //
-// This should be called after the window is fully constructed and
-// the application is ready to display. Typically called from App.Run().
-func (mw *MainWindow) Show() {
-	mw.window.Show()
+// The layout built by setupUI is kept first for View → Reset Panel Layout.
+// A saved layout that doesn't match (e.g., from a version with other docks)
+// is ignored by Qt for the docks it doesn't know.
+//
+// miqt API notes:
+//   - SaveState()/RestoreState(): Qt's opaque layout format; docks and
+//     toolbars are recognized by their object names
+//   - OnAboutToQuit runs while the window still exists
+func (mw *MainWindow) restorePanelLayout() {
+	mw.defaultPanelLayout = mw.window.SaveState()
+	if layout := mw.config.Settings.PanelLayout; len(layout) > 0 {
+		mw.window.RestoreState(layout)
+	}
+	mw.windowLayout = mw.config.Settings.WindowLayout
+	mw.updateCompactLayout()
+	mw.window.OnResizeEvent(func(super func(event *qt.QResizeEvent), event *qt.QResizeEvent) {
+		defer crash.Recover()
+		super(event)
+		// Docks aren't moved while Qt lays out the window
+		mainthread.Start(mw.updateCompactLayout)
+	})
+
+	qt.QCoreApplication_Instance().OnAboutToQuit(crash.Guard(func() {
+		if mw.mapFullscreen {
+			// Save the panels shown before, not the full-screen map
+			mw.toggleMapFullscreen()
+		}
+		layout := mw.window.SaveState()
+		if mw.compact {
+			// The compact layout is made up again on the next start
+			layout = mw.wideLayout
+		}
+		mw.controller.UpdatePanelLayout(layout)
+		mw.controller.EndSession(mw.openPanels())
+	}))
 }
 
-// =============================================================================
-// Update Methods (called by App controller)
-// =============================================================================
+// The automatic layout (domain.WindowLayoutAutomatic) is compact while the
+// window is narrower than compactMaxWidth or lower than compactMaxHeight,
+// in pixels. It turns wide again only compactHysteresis pixels beyond, so
+// a window resized around a threshold doesn't switch back and forth.
+const (
+	compactMaxWidth   = 1100
+	compactMaxHeight  = 720
+	compactHysteresis = 40
+)
+
+// wantCompact reports whether the window layout calls for the compact
+// layout at the window's size.
+func (mw *MainWindow) wantCompact() bool {
+	switch mw.windowLayout {
+	case domain.WindowLayoutWide:
+		return false
+	case domain.WindowLayoutCompact:
+		return true
+	}
+	margin := 0
+	if mw.compact {
+		margin = compactHysteresis
+	}
+	return mw.window.Width() < compactMaxWidth+margin || mw.window.Height() < compactMaxHeight+margin
+}
 
-// UpdateLocation updates the location display in all relevant widgets.
+// updateCompactLayout switches between the wide and the compact layout
+// when the window layout or the window's size calls for the other one.
 //
-// This is called by the App controller after a location change from:
-//   - IP-based detection
-//   - Search results
-//   - Map clicks
+// The compact layout stacks the map above the docks, tabbed at the bottom
+// of the window:
 //
-// The method updates:
-//   - LocationPanel: Shows coordinates and location name
-//   - MapView: Centers and marks the new location
-//   - StatusBar: Shows location name
+//	┌──────────────────────────────────────────┐
+//	│                Map                       │
+//	├──────────────────────────────────────────┤
+//	│ Location │ Date │ Times │ Settings       │
+//	│  (the selected tab's panels, scrolling)  │
+//	└──────────────────────────────────────────┘
 //
-// Nil checks protect against calls during initialization.
-func (mw *MainWindow) UpdateLocation(loc domain.Location) {
-	// Update location panel (coordinates and name display)
-	if mw.locationPanel != nil {
-		mw.locationPanel.SetLocation(loc)
+// Closed docks stay closed. The docks' arrangement from before is put back
+// when the wide layout returns. Nothing moves in full-screen map mode,
+// which updates the layout when it ends.
+//
+// miqt API notes:
+//   - AddDockWidget moves a dock that is in another area already
+//   - TabifyDockWidget(first, second): second becomes a tab of first
+func (mw *MainWindow) updateCompactLayout() {
+	if mw.mapFullscreen {
+		return
+	}
+	compact := mw.wantCompact()
+	if compact == mw.compact {
+		return
 	}
+	mw.compact = compact
 
-	// Update map view (center and marker)
-	if mw.mapView != nil {
-		mw.mapView.SetLocation(loc.Latitude, loc.Longitude)
+	if !compact {
+		mw.window.RestoreState(mw.wideLayout)
+		return
+	}
+	mw.wideLayout = mw.window.SaveState()
+	for i, dock := range mw.docks {
+		shown := dock.ToggleViewAction().IsChecked()
+		dock.SetFloating(false)
+		mw.window.AddDockWidget(qt.BottomDockWidgetArea, dock)
+		if i > 0 {
+			mw.window.TabifyDockWidget(mw.docks[0], dock)
+		}
+		dock.SetVisible(shown)
 	}
+	mw.docks[0].Raise()
+}
 
-	// Update status bar with location name
-	mw.setStatus(fmt.Sprintf("Location: %s", loc.Name))
+// onWindowLayout applies and records the layout picked in View → Layout.
+func (mw *MainWindow) onWindowLayout(layout string) {
+	mw.windowLayout = layout
+	mw.controller.UpdateWindowLayout(layout)
+	mw.updateCompactLayout()
 }
 
-// UpdateDate updates the date display in the date panel.
-//
-// This is called by the App controller after a date change from:
-//   - Previous/next buttons
-//   - Calendar selection
-//   - Today button
-//
-// Nil check protects against calls during initialization.
-func (mw *MainWindow) UpdateDate(date time.Time) {
-	if mw.datePanel != nil {
-		mw.datePanel.SetDate(date)
+// onResetPanelLayout puts the docks back right of the map, all shown
+// (View → Reset Panel Layout).
+func (mw *MainWindow) onResetPanelLayout() {
+	if mw.mapFullscreen {
+		mw.toggleMapFullscreen()
 	}
+	// The wide arrangement is the one reset; the compact layout is made
+	// up again from it
+	mw.compact = false
+	mw.window.RestoreState(mw.defaultPanelLayout)
+	for _, dock := range mw.docks {
+		dock.SetFloating(false)
+		dock.Show()
+	}
+	mw.updateCompactLayout()
 }
 
-// UpdateSunTimes updates the time panel with calculated sun times.
+// setupPresetBar adds the toolbar that switches between presets (see
+// widgets.PresetBar), below the menu bar.
 //
-// This is called by the App controller after recalculation due to:
-//   - Location change
-//   - Date change
-//   - Settings change (elevation angles)
+// miqt API notes:
+//   - AddToolBarWithToolbar(toolbar): Adds to the top toolbar area
+func (mw *MainWindow) setupPresetBar() {
+	mw.presetBar = widgets.NewPresetBar(mw.controller.SelectPreset, mw.onSavePreset, mw.onDeletePreset)
+	mw.presetBar.SetPresets(mw.config.Settings.Presets, mw.config.Settings.ActivePreset)
+	mw.presetBar.Widget().SetObjectName(*qt.NewQAnyStringView3("presetToolbar"))
+	mw.window.AddToolBarWithToolbar(mw.presetBar.Widget())
+}
+
+// UpdatePresets refreshes the preset toolbar after a preset was selected,
+// saved or deleted. Called by the App controller.
+func (mw *MainWindow) UpdatePresets(settings domain.Settings) {
+	mw.config.Settings = settings
+	mw.presetBar.SetPresets(settings.Presets, settings.ActivePreset)
+}
+
+// onSavePreset handles "Save As..." in the preset toolbar.
 //
-// The time format (12/24 hour) is passed from current settings.
-// Nil check protects against calls during initialization.
-func (mw *MainWindow) UpdateSunTimes(sunTimes domain.SunTimes) {
-	if mw.timePanel != nil {
-		mw.timePanel.SetSunTimes(sunTimes, mw.config.Settings.TimeFormat24Hour)
+// The user is asked for a name, pre-filled with the active preset's so
+// that it can be updated in place. Invalid names (and a full list) are
+// reported and nothing is saved.
+func (mw *MainWindow) onSavePreset() {
+	ok := false
+	name := qt.QInputDialog_GetText4(mw.window.QWidget, "Save Preset",
+		"Name for the current angles and custom events:", qt.QLineEdit__Normal, mw.presetBar.Active(), &ok)
+	if !ok {
+		return
+	}
+
+	if err := mw.controller.SavePresetAs(name); err != nil {
+		mw.ShowError(fmt.Sprintf("Preset not saved: %v", err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Preset %q saved", strings.TrimSpace(name)))
+}
+
+// onDeletePreset handles "Delete" in the preset toolbar, after the user
+// confirms.
+func (mw *MainWindow) onDeletePreset(name string) {
+	answer := qt.QMessageBox_Question5(mw.window.QWidget, "Delete Preset",
+		fmt.Sprintf("Delete the preset %q?\n\nThe current angles and custom events stay.", name),
+		qt.QMessageBox__Yes|qt.QMessageBox__No)
+	if answer != qt.QMessageBox__Yes {
+		return
 	}
+	mw.controller.DeletePreset(name)
+	mw.setStatus(fmt.Sprintf("Preset %q deleted", name))
 }
 
-// ShowError displays an error message in the status bar.
+// setupTray gives the window the app's icon and, if the desktop has a
+// system tray, adds the tray icon (see widgets.TrayIcon).
 //
-// This is called by the App controller when operations fail:
-//   - Location detection failure
-//   - Search failure
-//   - Calculation errors
-//   - Settings save failures
+// With Settings.MinimizeToTray, minimizing the window hides it: only the
+// tray icon is left. Qt would quit once no window is visible and a dialog
+// closes, so quitting on the last closed window is turned off while the
+// window is hidden.
 //
-// Errors are prefixed with "Error: " for visibility.
-func (mw *MainWindow) ShowError(message string) {
-	mw.setStatus(fmt.Sprintf("Error: %s", message))
+// miqt API notes:
+//   - OnChangeEvent sees WindowStateChange after the window was minimized;
+//     hiding is queued with mainthread.Start, since hiding a window from
+//     inside its own state change confuses some window managers
+func (mw *MainWindow) setupTray() {
+	icon := widgets.TrayImage()
+	mw.window.SetWindowIcon(icon)
+	qt.QGuiApplication_SetWindowIcon(icon)
+	if !widgets.TrayAvailable() {
+		return
+	}
+
+	mw.minimizeToTray = mw.config.Settings.MinimizeToTray
+	mw.trayIcon = widgets.NewTrayIcon(mw.window.QObject, mw.toggleWindow, mw.onPauseNotifications, mw.quitFromTray)
+	mw.window.OnChangeEvent(func(super func(event *qt.QEvent), event *qt.QEvent) {
+		defer crash.Recover()
+		super(event)
+		if event.Type() == qt.QEvent__WindowStateChange && mw.minimizeToTray && mw.window.IsMinimized() {
+			mainthread.Start(mw.hideToTray)
+		}
+	})
 }
 
-// =============================================================================
-// Internal Helpers
-// =============================================================================
+// SetMinimizeToTray turns hiding the minimized window in the tray on or
+// off. Called by the App when the preference changes.
+func (mw *MainWindow) SetMinimizeToTray(on bool) {
+	mw.minimizeToTray = on
+}
 
-// setStatus updates the status bar text.
-//
-// This is an internal helper used by UpdateLocation and ShowError.
-// Nil check protects against calls during initialization.
-func (mw *MainWindow) setStatus(message string) {
-	if mw.statusLabel != nil {
-		mw.statusLabel.SetText(message)
+// hideToTray hides the window, leaving the tray icon.
+func (mw *MainWindow) hideToTray() {
+	qt.QGuiApplication_SetQuitOnLastWindowClosed(false)
+	mw.window.Hide()
+	mw.trayIcon.SetWindowVisible(false)
+}
+
+// toggleWindow shows the window if it is hidden or minimized, and hides it
+// otherwise (tray icon click or its first menu entry).
+func (mw *MainWindow) toggleWindow() {
+	if mw.window.IsVisible() && !mw.window.IsMinimized() {
+		mw.hideToTray()
+		return
 	}
+	mw.showWindow()
 }
 
-// =============================================================================
-// Event Handlers (callbacks from widgets)
-// =============================================================================
+// showWindow brings the window to the front, out of the tray or the
+// minimized state.
+func (mw *MainWindow) showWindow() {
+	mw.window.ShowNormal()
+	mw.window.Raise()
+	mw.window.ActivateWindow()
+	qt.QGuiApplication_SetQuitOnLastWindowClosed(true)
+	if mw.trayIcon != nil {
+		mw.trayIcon.SetWindowVisible(true)
+	}
+}
 
-// onMapClick handles map click events from the MapView widget.
+// onPauseNotifications handles the tray icon's "Pause Notifications".
+func (mw *MainWindow) onPauseNotifications(paused bool) {
+	mw.controller.PauseNotifications(paused)
+	if paused {
+		mw.setStatus("Notifications, phone reminders and webhook calls paused")
+	} else {
+		mw.setStatus("Notifications, phone reminders and webhook calls resumed")
+	}
+}
+
+// showNotification shows a desktop notification from the tray icon. On
+// desktops without a system tray it goes to the status bar instead, and the
+// window flashes in the taskbar to draw attention to it.
+func (mw *MainWindow) showNotification(title, message string) {
+	if mw.trayIcon != nil {
+		mw.trayIcon.ShowMessage(title, message)
+		return
+	}
+	mw.setStatus(title + ": " + message)
+	qt.QApplication_Alert(mw.window.QWidget)
+}
+
+// quitFromTray quits the app from the tray icon's menu, which works while
+// the window is hidden too.
+func (mw *MainWindow) quitFromTray() {
+	mw.trayIcon.Hide()
+	qt.QCoreApplication_Quit()
+}
+
+// setupMenus creates the window's menu bar and main toolbar.
 //
-// This is passed to MapView as a callback during construction.
-// When the user clicks on the map, MapView parses the coordinates
-// from the JavaScript console message and calls this handler.
+// Menus:
+//   - File: Import Map Data, Export Watch Calendar, Sync to Calendar,
+//     Export Date Range, Shoot Plan (PDF), Import/Export My Places,
+//     Export/Import Settings (all profiles, for a backup or another
+//     machine), Open Link, Register Link Handler, Quit
+//   - Edit: Copy Times (the day's summary for chats and notes), Copy Link (a
+//     gogoldenhour:// link to the place and date), Find Location (focuses
+//     the location search), Preferences (opens the tabbed
+//     PreferencesDialog), Privacy Mode (checkable)
+//   - View: Full-Screen Map (checkable), Month Calendar, Week Outlook (the
+//     coming week's chance of usable light), Panels (shows or hides each
+//     dock), Layout (automatic, wide or compact), Reset Panel
+//     Layout, Toolbar (shows or hides the main toolbar)
+//   - Tools: Detect Location, Favorite (checkable; adds or removes the
+//     current location), Open Profile (another window with a settings
+//     profile), Analyze Photo (the light of a photo's EXIF place and time)
+//   - Debug: Statistics (calculation, cache and web service counts)
+//   - Help: Keyboard Shortcuts (cheat sheet), What's New (release notes of
+//     all versions), Check for Updates, About GoGoldenHour
 //
-// The handler simply delegates to the AppController for processing.
-func (mw *MainWindow) onMapClick(lat, lon float64) {
-	mw.controller.OnMapClick(lat, lon)
+// The main toolbar shares the menus' actions for the most used ones:
+//
+//	[Detect Location] [Favorite] [Copy Times] [Shoot Plan] [Full-Screen Map]
+//
+// Standard key sequences are used so shortcuts follow platform conventions
+// (e.g., Ctrl+Q on Linux, Cmd+, for Preferences on macOS). The actions'
+// callbacks run in crash.Guard, so a panic in one shows the crash dialog
+// (see ShowCrash) instead of ending the app.
+//
+// miqt API notes:
+//   - MenuBar() returns the window's menu bar (created on first call)
+//   - AddMenuWithTitle("&File"): "&" marks the keyboard mnemonic
+//   - AddActionWithText() is inherited by QMenu from QWidget
+//   - SetShortcutsWithShortcuts(StandardKey) binds a platform shortcut
+//   - An action added to both a menu and a toolbar stays one action: its
+//     checked state and shortcut are shared
+//   - ToggleViewAction(): Checkable action that shows or hides the toolbar
+//     (or a dock)
+//   - QActionGroup: Checkable actions added to it are exclusive, like radio
+//     buttons
+func (mw *MainWindow) setupMenus() {
+	menuBar := mw.window.MenuBar()
+
+	// File menu
+	fileMenu := menuBar.AddMenuWithTitle("&File")
+	importAction := fileMenu.AddActionWithText("&Import Map Data...")
+	importAction.SetShortcutsWithShortcuts(qt.QKeySequence__Open)
+	importAction.OnTriggered(crash.Guard(mw.onImportMapData))
+	exportAction := fileMenu.AddActionWithText("Export &Watch Calendar...")
+	exportAction.OnTriggered(crash.Guard(mw.onExportWatchCalendar))
+	syncAction := fileMenu.AddActionWithText("S&ync to Calendar")
+	syncAction.OnTriggered(crash.Guard(mw.onSyncCalendar))
+	exportRangeAction := fileMenu.AddActionWithText("Export Date &Range...")
+	exportRangeAction.OnTriggered(crash.Guard(mw.onExportDateRange))
+	shootPlanAction := fileMenu.AddActionWithText("Shoot Plan (P&DF)...")
+	shootPlanAction.SetShortcutsWithShortcuts(qt.QKeySequence__Print)
+	shootPlanAction.OnTriggered(crash.Guard(mw.onGenerateShootPlan))
+	waypointsAction := fileMenu.AddActionWithText("Export Shoot W&aypoints (GPX)...")
+	waypointsAction.SetToolTip("The location and the favorites with their sun times, for GPS units and phone apps")
+	waypointsAction.OnTriggered(crash.Guard(mw.onExportWaypoints))
+	fileMenu.AddSeparator()
+	importPlacesAction := fileMenu.AddActionWithText("Import My &Places...")
+	importPlacesAction.OnTriggered(crash.Guard(mw.onImportFavorites))
+	exportPlacesAction := fileMenu.AddActionWithText("&Export My Places...")
+	exportPlacesAction.OnTriggered(crash.Guard(mw.onExportFavorites))
+	exportSettingsAction := fileMenu.AddActionWithText("Export &Settings...")
+	exportSettingsAction.OnTriggered(crash.Guard(mw.onExportSettings))
+	importSettingsAction := fileMenu.AddActionWithText("Import S&ettings...")
+	importSettingsAction.OnTriggered(crash.Guard(mw.onImportSettings))
+	fileMenu.AddSeparator()
+	openLinkAction := fileMenu.AddActionWithText("Open &Link...")
+	openLinkAction.OnTriggered(crash.Guard(mw.onOpenLink))
+	registerLinksAction := fileMenu.AddActionWithText("Register Link &Handler")
+	registerLinksAction.SetToolTip("Open gogoldenhour:// links from browsers and chat apps with this app")
+	registerLinksAction.OnTriggered(crash.Guard(mw.onRegisterLinks))
+	fileMenu.AddSeparator()
+	quitAction := fileMenu.AddActionWithText("&Quit")
+	quitAction.SetShortcutsWithShortcuts(qt.QKeySequence__Quit)
+	quitAction.OnTriggered(crash.Guard(func() {
+		mw.window.Close()
+	}))
+
+	// Edit menu
+	editMenu := menuBar.AddMenuWithTitle("&Edit")
+	copyTimesAction := editMenu.AddActionWithText("&Copy Times")
+	copyTimesAction.SetShortcut(qt.NewQKeySequence2(copyTimesKeys))
+	copyTimesAction.OnTriggered(crash.Guard(mw.onCopyTimes))
+	copyLinkAction := editMenu.AddActionWithText("Copy &Link")
+	copyLinkAction.OnTriggered(crash.Guard(mw.onCopyLink))
+	findAction := editMenu.AddActionWithText("&Find Location")
+	findAction.SetShortcut(qt.NewQKeySequence2(findKeys))
+	findAction.OnTriggered(crash.Guard(mw.locationPanel.FocusSearch))
+	prefsAction := editMenu.AddActionWithText("&Preferences...")
+	prefsAction.SetShortcutsWithShortcuts(qt.QKeySequence__Preferences)
+	prefsAction.OnTriggered(crash.Guard(mw.onShowPreferences))
+	editMenu.AddSeparator()
+	mw.privacyAction = editMenu.AddActionWithText("Privacy &Mode")
+	mw.privacyAction.SetCheckable(true)
+	mw.privacyAction.SetChecked(mw.config.Settings.PrivacyMode)
+	mw.privacyAction.OnToggled(func(checked bool) {
+		defer crash.Recover()
+		mw.onTogglePrivacyMode(checked)
+	})
+
+	// View menu
+	viewMenu := menuBar.AddMenuWithTitle("&View")
+	mw.fullscreenAction = viewMenu.AddActionWithText("Full-Screen &Map")
+	mw.fullscreenAction.SetCheckable(true)
+	mw.fullscreenAction.SetShortcutsWithShortcuts(qt.QKeySequence__FullScreen)
+	mw.fullscreenAction.OnTriggered(crash.Guard(mw.toggleMapFullscreen))
+	monthAction := viewMenu.AddActionWithText("Month &Calendar...")
+	monthAction.OnTriggered(crash.Guard(mw.onShowMonthCalendar))
+	weekAction := viewMenu.AddActionWithText("&Week Outlook...")
+	weekAction.OnTriggered(crash.Guard(mw.onShowWeekOutlook))
+	viewMenu.AddSeparator()
+	panelsMenu := viewMenu.AddMenuWithTitle("&Panels")
+	for _, dock := range mw.docks {
+		panelsMenu.AddAction(dock.ToggleViewAction())
+	}
+	layoutMenu := viewMenu.AddMenuWithTitle("&Layout")
+	layoutGroup := qt.NewQActionGroup(mw.window.QObject)
+	for _, choice := range []struct{ text, layout string }{
+		{"&Automatic", domain.WindowLayoutAutomatic},
+		{"&Wide (Panels Beside the Map)", domain.WindowLayoutWide},
+		{"&Compact (Panels Below the Map)", domain.WindowLayoutCompact},
+	} {
+		action := layoutMenu.AddActionWithText(choice.text)
+		action.SetCheckable(true)
+		action.SetChecked(choice.layout == mw.config.Settings.WindowLayout)
+		layoutGroup.AddAction(action)
+		action.OnTriggered(crash.Guard(func() { mw.onWindowLayout(choice.layout) }))
+	}
+	resetLayoutAction := viewMenu.AddActionWithText("&Reset Panel Layout")
+	resetLayoutAction.OnTriggered(crash.Guard(mw.onResetPanelLayout))
+
+	// Tools menu
+	toolsMenu := menuBar.AddMenuWithTitle("&Tools")
+	detectAction := toolsMenu.AddActionWithText("&Detect Location")
+	detectAction.SetShortcut(qt.NewQKeySequence2(detectKeys))
+	detectAction.OnTriggered(crash.Guard(mw.onDetectLocation))
+	mw.favoriteAction = toolsMenu.AddActionWithText("&Favorite")
+	mw.favoriteAction.SetCheckable(true)
+	mw.favoriteAction.SetToolTip("Add the current location to the favorites, or remove it")
+	mw.favoriteAction.OnTriggered(crash.Guard(mw.controller.ToggleFavorite))
+	toolsMenu.AddSeparator()
+	openProfileAction := toolsMenu.AddActionWithText("Open &Profile...")
+	openProfileAction.SetToolTip("Open another window with a settings profile")
+	openProfileAction.OnTriggered(crash.Guard(mw.onOpenProfile))
+	analyzePhotoAction := toolsMenu.AddActionWithText("&Analyze Photo...")
+	analyzePhotoAction.SetToolTip("Show the light a photo was taken in, from its GPS position and capture time")
+	analyzePhotoAction.OnTriggered(crash.Guard(mw.onAnalyzePhoto))
+
+	// Debug menu
+	debugMenu := menuBar.AddMenuWithTitle("&Debug")
+	statsAction := debugMenu.AddActionWithText("&Statistics...")
+	statsAction.OnTriggered(crash.Guard(mw.onShowStatistics))
+
+	// Help menu
+	helpMenu := menuBar.AddMenuWithTitle("&Help")
+	shortcutsAction := helpMenu.AddActionWithText("&Keyboard Shortcuts")
+	shortcutsAction.OnTriggered(crash.Guard(mw.onShowShortcuts))
+	whatsNewAction := helpMenu.AddActionWithText("&What's New")
+	whatsNewAction.OnTriggered(crash.Guard(mw.onShowWhatsNew))
+	helpMenu.AddSeparator()
+	updatesAction := helpMenu.AddActionWithText("Check for &Updates...")
+	updatesAction.OnTriggered(crash.Guard(mw.onCheckForUpdates))
+	aboutAction := helpMenu.AddActionWithText("&About GoGoldenHour")
+	aboutAction.OnTriggered(crash.Guard(mw.onShowAbout))
+
+	// Main toolbar, above the preset toolbar
+	mainBar := mw.window.AddToolBarWithTitle("Main")
+	mainBar.SetObjectName(*qt.NewQAnyStringView3("mainToolbar"))
+	mainBar.SetMovable(false)
+	for _, action := range []*qt.QAction{detectAction, mw.favoriteAction, copyTimesAction,
+		shootPlanAction, mw.fullscreenAction} {
+		mainBar.AddAction(action)
+	}
+	toolbarAction := mainBar.ToggleViewAction()
+	toolbarAction.SetText("&Toolbar")
+	viewMenu.AddSeparator()
+	viewMenu.AddAction(toolbarAction)
 }
 
-// onLocationSearch handles search submissions from the LocationPanel widget.
+// copyTimesKeys copies the day's summary (Edit → Copy Times). Ctrl+C itself
+// stays with text fields and tables.
+const copyTimesKeys = "Ctrl+Shift+C"
+
+// findKeys focus the location search (Edit → Find Location), as in a
+// browser's address bar; detectKeys detect the location (Tools → Detect
+// Location).
+const (
+	findKeys   = "Ctrl+L"
+	detectKeys = "Ctrl+D"
+)
+
+// dateShortcut is a keyboard shortcut for date navigation.
+type dateShortcut struct {
+	// keys is the key sequence in QKeySequence's portable text form.
+	keys string
+
+	// description is shown in the cheat sheet.
+	description string
+
+	// action moves the date.
+	action func(dp *widgets.DatePanel)
+}
+
+// dateShortcuts are the date navigation keys, in cheat sheet order.
+var dateShortcuts = []dateShortcut{
+	{"Left", "Previous day", func(dp *widgets.DatePanel) { dp.ShiftDays(-1) }},
+	{"Right", "Next day", func(dp *widgets.DatePanel) { dp.ShiftDays(1) }},
+	{"Shift+Left", "Previous week", func(dp *widgets.DatePanel) { dp.ShiftDays(-7) }},
+	{"Shift+Right", "Next week", func(dp *widgets.DatePanel) { dp.ShiftDays(7) }},
+	{"PgUp", "Previous month", func(dp *widgets.DatePanel) { dp.ShiftMonths(-1) }},
+	{"PgDown", "Next month", func(dp *widgets.DatePanel) { dp.ShiftMonths(1) }},
+	{"T", "Today", func(dp *widgets.DatePanel) { dp.GoToToday() }},
+	{"Ctrl+T", "Today (also while typing)", func(dp *widgets.DatePanel) { dp.GoToToday() }},
+}
+
+// setupShortcuts binds the date navigation keys (see dateShortcuts) and
+// Escape (see onEscape). The other keys belong to menu actions (Ctrl+L,
+// Ctrl+D) and the location panel's Go button (Alt+S).
 //
-// This is passed to LocationPanel as a callback during construction.
-// When the user presses Enter in the search box or clicks the Go button,
-// LocationPanel calls this handler with the query text.
+// The shortcuts go through the date panel, so they change the date exactly
+// like its buttons. They are application-wide, so they also work while the
+// month calendar has the focus. Qt gives the keys to a focused text field
+// first (its ShortcutOverride), so typing "t" or moving the cursor in the
+// search box still works, and a modal dialog blocks them.
 //
-// The handler delegates to the AppController for geocoding.
-func (mw *MainWindow) onLocationSearch(query string) {
-	mw.controller.SearchLocation(query)
+// miqt API notes:
+//   - NewQShortcut2(key, parent): Shortcut owned by the window
+//   - NewQKeySequence2("Shift+Left"): Key sequence from portable text
+//   - SetContext(qt.ApplicationShortcut): Active in all windows of the app
+func (mw *MainWindow) setupShortcuts() {
+	for _, s := range dateShortcuts {
+		shortcut := qt.NewQShortcut2(qt.NewQKeySequence2(s.keys), mw.window.QObject)
+		shortcut.SetContext(qt.ApplicationShortcut)
+		shortcut.OnActivated(crash.Guard(func() { s.action(mw.datePanel) }))
+	}
+
+	// Escape only acts in the main window; dialogs and the search results
+	// dropdown handle it themselves
+	escape := qt.NewQShortcut2(qt.NewQKeySequence2("Escape"), mw.window.QObject)
+	escape.OnActivated(crash.Guard(mw.onEscape))
 }
 
-// onDetectLocation handles the "Detect My Location" button from LocationPanel.
+// onEscape cancels the first of these that applies:
+//  1. A running search or location detection is stopped
+//  2. The full-screen map returns to the normal layout
+//  3. An error toast is dismissed
+//  4. The search field gives up the focus, so the single keys (T for
+//     today, arrows for the date) work again
+func (mw *MainWindow) onEscape() {
+	switch {
+	case mw.locationPanel.Searching():
+		mw.onCancelSearch()
+	case mw.locationPanel.Detecting():
+		mw.onCancelDetect()
+	case mw.mapFullscreen:
+		mw.toggleMapFullscreen()
+	case mw.toast.Visible():
+		mw.toast.Hide()
+	default:
+		mw.locationPanel.LeaveSearch()
+	}
+}
+
+// onShowShortcuts shows the keyboard shortcuts cheat sheet
+// (Help → Keyboard Shortcuts): the date keys and the menus' standard keys,
+// spelled the way the platform shows them.
+func (mw *MainWindow) onShowShortcuts() {
+	var shortcuts []widgets.Shortcut
+	for _, s := range dateShortcuts {
+		shortcuts = append(shortcuts, widgets.Shortcut{
+			Keys:        qt.NewQKeySequence2(s.keys).ToStringWithFormat(qt.QKeySequence__NativeText),
+			Description: s.description,
+		})
+	}
+	for _, s := range []struct {
+		key         qt.QKeySequence__StandardKey
+		description string
+	}{
+		{qt.QKeySequence__Open, "Import map data"},
+		{qt.QKeySequence__Preferences, "Preferences"},
+		{qt.QKeySequence__FullScreen, "Full-screen map"},
+		{qt.QKeySequence__Quit, "Quit"},
+	} {
+		// Not every platform has a key for each (e.g., Preferences on Windows)
+		if keys := qt.NewQKeySequence6(s.key).ToStringWithFormat(qt.QKeySequence__NativeText); keys != "" {
+			shortcuts = append(shortcuts, widgets.Shortcut{Keys: keys, Description: s.description})
+		}
+	}
+	for _, s := range []struct {
+		keys        string
+		description string
+	}{
+		{copyTimesKeys, "Copy the day's times"},
+		{findKeys, "Search for a location"},
+		{widgets.SearchKeys, "Search (or stop the search)"},
+		{detectKeys, "Detect the location"},
+		{"Escape", "Stop a search or detection, leave the full-screen map, or leave the search field"},
+	} {
+		shortcuts = append(shortcuts, widgets.Shortcut{
+			Keys:        qt.NewQKeySequence2(s.keys).ToStringWithFormat(qt.QKeySequence__NativeText),
+			Description: s.description,
+		})
+	}
+	widgets.ShowShortcutsDialog(mw.window.QWidget, shortcuts)
+}
+
+// onTogglePrivacyMode handles Edit → Privacy Mode.
 //
-// This is passed to LocationPanel as a callback during construction.
-// When the user clicks the detect button, LocationPanel calls this handler.
+// The AppController switches the services; the map withdraws (or offers
+// again) its online overlays, and the status bar indicator follows.
+func (mw *MainWindow) onTogglePrivacyMode(on bool) {
+	mw.controller.UpdatePrivacyMode(on)
+	mw.mapView.SetPrivacyMode(on)
+	mw.privacyLabel.SetVisible(on)
+	if on {
+		mw.setStatus("Privacy mode on: online services are not contacted")
+	} else {
+		mw.setStatus("Privacy mode off")
+	}
+}
+
+// setTileServer shows a tile server's tiles on the map, loaded through the
+// tile cache if it is running.
+func (mw *MainWindow) setTileServer(server domain.TileServer) {
+	cacheURL := ""
+	if mw.config.TileCacheURL != "" {
+		cacheURL = mw.config.TileCacheURL + tilecache.Path(server)
+	}
+	mw.mapView.SetTileServer(server, cacheURL)
+}
+
+// toggleMapFullscreen switches full-screen map mode on or off.
 //
-// The handler delegates to the AppController for IP-based detection.
-func (mw *MainWindow) onDetectLocation() {
-	mw.controller.DetectLocation()
+// In full-screen mode the info panels' docks are hidden so the map fills
+// the window (floating ones too), and a compact overlay on the map shows
+// the key times instead. Leaving the mode shows the docks that were shown.
+// The mode can be toggled from View → Full-Screen Map, its shortcut
+// (F11 on most platforms), or the button on the map.
+func (mw *MainWindow) toggleMapFullscreen() {
+	mw.mapFullscreen = !mw.mapFullscreen
+
+	if mw.mapFullscreen {
+		mw.fullscreenDocks = nil
+		for _, dock := range mw.docks {
+			// Checked also while behind another dock's tab
+			if dock.ToggleViewAction().IsChecked() {
+				mw.fullscreenDocks = append(mw.fullscreenDocks, dock)
+				dock.Hide()
+			}
+		}
+	} else {
+		for _, dock := range mw.fullscreenDocks {
+			dock.Show()
+		}
+		mw.fullscreenDocks = nil
+		// The window may have been resized meanwhile
+		mw.updateCompactLayout()
+	}
+	mw.fullscreenAction.SetChecked(mw.mapFullscreen)
+	mw.mapView.SetFullscreen(mw.mapFullscreen)
+	mw.updateMapOverlay()
 }
 
-// onDateChanged handles date changes from the DatePanel widget.
+// updateMapOverlay refreshes the key times overlay shown in full-screen mode.
 //
-// This is passed to DatePanel as a callback during construction.
-// When the user changes the date (via buttons, calendar, or today button),
-// DatePanel calls this handler with the new date.
+// Overlay format (one line per item, hours that don't occur are omitted):
 //
-// The handler delegates to the AppController for recalculation.
-func (mw *MainWindow) onDateChanged(date time.Time) {
-	mw.controller.UpdateDate(date)
+//	Paris, France · Tue, Jun 21
+//	Golden  06:45-07:30 / 21:00-21:45
+//	Blue    05:10-05:35 / 22:20-22:45
+//	Sunrise 05:47 · Sunset 21:58
+func (mw *MainWindow) updateMapOverlay() {
+	if !mw.mapFullscreen || mw.sunTimes.Date.IsZero() {
+		mw.mapView.SetOverlay("")
+		return
+	}
+
+	st := mw.sunTimes
+	use24Hour := mw.config.Settings.TimeFormat24Hour
+	formatRange := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return "N/A"
+		}
+		return domain.FormatTime(tr.Start, use24Hour) + "-" + domain.FormatTime(tr.End, use24Hour)
+	}
+
+	lines := []string{
+		st.Location.DisplayName(mw.config.Settings.PlaceNameStyle) + " · " + st.Date.Format("Mon, Jan 2"),
+		"Golden  " + formatRange(st.GoldenMorning) + " / " + formatRange(st.GoldenEvening),
+		"Blue    " + formatRange(st.BlueMorning) + " / " + formatRange(st.BlueEvening),
+		"Sunrise " + domain.FormatTime(st.Sunrise, use24Hour) + " · Sunset " + domain.FormatTime(st.Sunset, use24Hour),
+	}
+	mw.mapView.SetOverlay(strings.Join(lines, "\n"))
 }
 
-// onSettingsChanged handles settings changes from the SettingsPanel widget.
+// =============================================================================
+// Window Control
+// =============================================================================
+
+// Show makes the main window visible on screen, and opens the planning
+// windows the last session left open (config.AppConfig.OpenPanels) once
+// it is up.
 //
-// This is passed to SettingsPanel as a callback during construction.
-// When the user changes any setting (elevation angles, checkboxes),
-// SettingsPanel calls this handler with the complete new settings.
+// This should be called after the window is fully constructed and
+// the application is ready to display. Typically called from App.Run().
+func (mw *MainWindow) Show() {
+	mw.window.Show()
+	if len(mw.config.OpenPanels) > 0 {
+		mainthread.Start(mw.reopenPanels)
+	}
+}
+
+// reopenPanels opens the planning windows of the last session.
+func (mw *MainWindow) reopenPanels() {
+	session := domain.Session{Panels: mw.config.OpenPanels}
+	if session.HasPanel(domain.SessionPanelMonthCalendar) {
+		mw.onShowMonthCalendar()
+	}
+	if session.HasPanel(domain.SessionPanelWeekOutlook) {
+		mw.onShowWeekOutlook()
+	}
+}
+
+// openPanels returns the planning windows open now, saved with the
+// session (see reopenPanels).
+func (mw *MainWindow) openPanels() []string {
+	var panels []string
+	if mw.monthDialog != nil && mw.monthDialog.IsVisible() {
+		panels = append(panels, domain.SessionPanelMonthCalendar)
+	}
+	if mw.weekDialog != nil && mw.weekDialog.IsVisible() {
+		panels = append(panels, domain.SessionPanelWeekOutlook)
+	}
+	return panels
+}
+
+// RunOnMainThread runs fn on the Qt main thread and waits until it has run
+// (see frontend.View). Called from the App's goroutines. Like the menu
+// actions, fn runs in crash.Guard.
+func (mw *MainWindow) RunOnMainThread(fn func()) {
+	mainthread.Wait(crash.Guard(fn))
+}
+
+// QueueOnMainThread runs fn once the Qt event loop is started, or after the
+// events waiting now if it runs already.
+func (mw *MainWindow) QueueOnMainThread(fn func()) {
+	mainthread.Start(crash.Guard(fn))
+}
+
+// ShowCrash shows the crash dialog for a panic (see crash.SetHandler) or
+// for the crash that ended the last run (fatal). Safe to call from any
+// goroutine: the dialog is queued on the main thread, after the callback
+// that panicked has returned.
 //
-// The handler:
-//  1. Updates local config with new settings
-//  2. Updates time panel format (in case 12/24 hour changed)
-//  3. Delegates to AppController for persistence and recalculation
+// Parameters:
+//   - report: The crash
+//   - path: The saved report, or empty if it couldn't be saved
+//   - fatal: The crash ended the last run
+func (mw *MainWindow) ShowCrash(report crash.Report, path string, fatal bool) {
+	mainthread.Start(func() {
+		widgets.ShowCrashDialog(mw.window.QWidget, report, path, fatal)
+	})
+}
+
+// =============================================================================
+// Event Subscriptions (published by App controller)
+// =============================================================================
+
+// subscribeEvents subscribes the widgets to the App's changes of location,
+// date and sun times (see the events package). Each widget follows what it
+// shows, so a new panel subscribes here without touching the App:
 //
-// Note: This may be called during SettingsPanel construction (applySettings).
-// The App controller handles this by checking if mainWindow is nil.
-func (mw *MainWindow) onSettingsChanged(settings domain.Settings) {
-	// Update local config
-	mw.config.Settings = settings
+//	LocationChanged ──► location panel, map marker, status bar
+//	DateChanged     ──► date panel
+//	SunTimesUpdated ──► time panel, map overlay, open camera view, month
+//	                    calendar and week outlook
+//
+// The App publishes on the Qt main thread, so the handlers update the
+// widgets directly. The subscriptions last as long as the window.
+func (mw *MainWindow) subscribeEvents() {
+	bus := mw.controller.Events()
 
-	// Update time format immediately (before waiting for recalculation)
-	mw.timePanel.SetTimeFormat(settings.TimeFormat24Hour)
+	// Location panel: coordinates, name and favorite star
+	events.Subscribe(bus, func(e events.LocationChanged) {
+		mw.locationPanel.SetLocation(e.Location)
+		_, isFavorite := domain.FavoriteAt(mw.controller.GetSettings().Favorites, e.Location)
+		mw.showFavorite(isFavorite)
+	})
 
-	// Delegate to controller for persistence and recalculation
-	mw.controller.UpdateSettings(settings)
+	// Map view: center and marker
+	events.Subscribe(bus, func(e events.LocationChanged) {
+		mw.mapView.SetLocation(e.Location.Latitude, e.Location.Longitude)
+		mw.mapView.SetMarkerName(e.Location.DisplayName(mw.config.Settings.PlaceNameStyle))
+	})
+
+	// Status bar: the location label
+	events.Subscribe(bus, func(e events.LocationChanged) {
+		mw.statusArea.SetLocation(e.Location.DisplayName(mw.config.Settings.PlaceNameStyle))
+	})
+
+	// Date panel
+	events.Subscribe(bus, func(e events.DateChanged) {
+		mw.datePanel.SetDate(e.Date)
+	})
+
+	// Time panel and map overlay, in the chosen time format (the overlay
+	// and the fullscreen legend read mw.sunTimes)
+	events.Subscribe(bus, func(e events.SunTimesUpdated) {
+		mw.sunTimes = e.SunTimes
+		mw.timePanel.SetSunTimes(e.SunTimes, mw.config.Settings.TimeFormat24Hour)
+		mw.updateMapOverlay()
+	})
+
+	// The open planning dialogs follow the selected day and place
+	events.Subscribe(bus, func(events.SunTimesUpdated) {
+		// The camera view shows the selected date's events
+		if mw.cameraDialog != nil && mw.cameraDialog.IsVisible() {
+			mw.refreshCameraView()
+		}
+
+		// So does the month calendar, at the new location and for the new date
+		if mw.monthDialog != nil && mw.monthDialog.IsVisible() {
+			mw.showMonth(mw.monthDialog.MonthFor(mw.controller.GetDate()))
+		}
+
+		// And the week outlook, at the new location with the new angles
+		if mw.weekDialog != nil && mw.weekDialog.IsVisible() {
+			mw.controller.FetchWeekOutlook()
+		}
+	})
+}
+
+// =============================================================================
+// Update Methods (called by App controller)
+// =============================================================================
+
+// UpdateWeather shows the forecast weather next to the periods of the time
+// panel.
+func (mw *MainWindow) UpdateWeather(forecast domain.WeatherForecast) {
+	if mw.timePanel != nil {
+		mw.timePanel.SetWeather(forecast)
+	}
+}
+
+// UpdateAurora shows the night's darkness and aurora outlook in the Astro
+// panel.
+func (mw *MainWindow) UpdateAurora(outlook domain.AuroraOutlook) {
+	if mw.astroPanel != nil {
+		mw.astroPanel.SetOutlook(outlook)
+	}
+}
+
+// UpdateMeteors shows the meteor showers peaking on the night in the Astro
+// panel.
+func (mw *MainWindow) UpdateMeteors(nights []domain.MeteorNight) {
+	if mw.astroPanel != nil {
+		mw.astroPanel.SetMeteors(nights)
+	}
+}
+
+// UpdateDateRange shows the days of the date range in the days panel.
+//
+// Parameters:
+//   - days: Sun times of each day, in order; nil hides the panel (a single
+//     day is planned)
+func (mw *MainWindow) UpdateDateRange(days []domain.SunTimes) {
+	if mw.daysPanel != nil {
+		mw.daysPanel.SetDays(days, mw.config.Settings.TimeFormat24Hour)
+	}
+}
+
+// UpdateTimeline shows the selected day's events in the timeline panel.
+//
+// Parameters:
+//   - days: The selected day's sun times and the next day's, for the
+//     timeline across midnight
+func (mw *MainWindow) UpdateTimeline(days []domain.SunTimes) {
+	if mw.timelinePanel != nil {
+		mw.timelinePanel.SetDays(days, mw.config.Settings.TimeFormat24Hour)
+	}
+}
+
+// UpdateCountdown restarts the countdown to the next golden or blue hour.
+//
+// Parameters:
+//   - days: Sun times of the days around today at the location, in order
+//     (see CountdownPanel.SetDays); nil stops the countdown
+func (mw *MainWindow) UpdateCountdown(days []domain.SunTimes) {
+	if mw.countdownPanel != nil {
+		mw.countdownPanel.SetDays(days, mw.config.Settings.TimeFormat24Hour)
+	}
+	if mw.trayIcon != nil {
+		mw.trayIcon.SetDays(days, mw.config.Settings.TimeFormat24Hour)
+	}
+}
+
+// ApplySettings refreshes the settings panel controls with new values.
+//
+// This is called by the App controller when settings change from outside
+// the settings panel (e.g., importing a config code), so the controls stay
+// in sync with the active settings.
+//
+// The panel doesn't report values set this way (see
+// widgets.SettingsPanel.SetSettings), so this also does what
+// onSettingsChanged would for the time panel; the App applies the settings
+// itself.
+func (mw *MainWindow) ApplySettings(settings domain.Settings) {
+	mw.config.Settings = settings
+	if mw.settingsPanel != nil {
+		mw.settingsPanel.SetSettings(settings)
+	}
+	mw.timePanel.SetTimeFormat(settings.TimeFormat24Hour)
+	mw.timePanel.SetTeachingMode(settings)
+	mw.astroPanel.SetTimeFormat(settings.TimeFormat24Hour)
+	mw.astroPanel.SetAuroraEnabled(settings.AuroraForecast)
+}
+
+// ShowSettingsRecovery tells the user that the settings file was damaged
+// and offers its backup (if there is one) or the defaults in the settings
+// panel. Called by the App at startup.
+func (mw *MainWindow) ShowSettingsRecovery(hasBackup bool) {
+	mw.settingsPanel.ShowRecovery(hasBackup)
+	if hasBackup {
+		mw.ShowError("The settings file was damaged, so the defaults are in use: restore the backup in the Settings panel")
+	} else {
+		mw.ShowError("The settings file was damaged and has no backup, so the defaults are in use")
+	}
+}
+
+// ReloadSettings shows settings that replaced the current ones as a whole
+// (see AppController.RestoreSettings): the settings panel, the presets,
+// the map's tile server and privacy mode, the display formats, the home
+// clock, the tray option and the recent locations. The favorites and sun times follow from the
+// App.
+//
+// miqt API notes:
+//   - BlockSignals(true) keeps the privacy mode action from reporting the
+//     change back, as the App has applied it already
+func (mw *MainWindow) ReloadSettings(settings domain.Settings) {
+	mw.ApplySettings(settings)
+	mw.settingsPanel.HideRecovery()
+	mw.presetBar.SetPresets(settings.Presets, settings.ActivePreset)
+	mw.setTileServer(settings.TileServer)
+	mw.privacyAction.BlockSignals(true)
+	mw.privacyAction.SetChecked(settings.PrivacyMode)
+	mw.privacyAction.BlockSignals(false)
+	mw.mapView.SetPrivacyMode(settings.PrivacyMode)
+	mw.privacyLabel.SetVisible(settings.PrivacyMode)
+	mw.applyDisplayFormats(settings)
+	mw.timePanel.SetHomeTimezone(settings.HomeTimezone)
+	mw.SetMinimizeToTray(settings.MinimizeToTray)
+	mw.UpdateRecentLocations(settings.RecentLocations)
+}
+
+// applyDisplayFormats shows the coordinates, place names and sun times
+// in the formats of the given settings (see
+// AppController.UpdateDisplayFormats).
+func (mw *MainWindow) applyDisplayFormats(settings domain.Settings) {
+	mw.timePanel.SetLayout(settings.TimePanelLayout)
+	mw.locationPanel.SetCoordinateFormat(settings.CoordinateFormat)
+	mw.locationPanel.SetPlaceNameStyle(settings.PlaceNameStyle)
+	mw.mapView.SetMarkerName(mw.controller.GetLocation().DisplayName(settings.PlaceNameStyle))
+}
+
+// ShowSunAlignments displays the results of a sun alignment search.
+//
+// This is called by the App controller when a search started via
+// FindSunAlignments completes. Results are listed in a modal dialog and
+// summarized in the status bar.
+func (mw *MainWindow) ShowSunAlignments(camera, subject domain.Location, alignments []domain.SunAlignment) {
+	mw.setStatus(fmt.Sprintf("Found %d sun alignments", len(alignments)))
+	widgets.ShowAlignmentDialog(mw.window.QWidget, camera, subject, alignments,
+		mw.config.Settings.TimeFormat24Hour)
+}
+
+// ShowElevationProfile shows the terrain profile of a measured line on the map.
+//
+// This is called by the App controller when a profile requested via
+// FetchElevationProfile arrives. The chart is summarized by the terrain
+// horizon seen from the start of the line, compared with the golden hour
+// elevation, e.g.:
+//
+//	Horizon 2.3° (ridge at 4.1 km)
+//	Sun hidden below 2.3° toward WSW
+func (mw *MainWindow) ShowElevationProfile(from, to domain.Location, profile domain.ElevationProfile) {
+	angle, index, ok := profile.HorizonAngle()
+	if !ok {
+		return
+	}
+
+	bearing := from.BearingTo(to)
+	direction := domain.CompassPoint(bearing)
+	summary := fmt.Sprintf("Horizon %.1f° (ridge at %s)\n", angle,
+		domain.FormatDistance(profile.Samples[index].Distance))
+	switch golden := mw.config.Settings.GoldenHourElevation; {
+	case angle >= golden:
+		summary += fmt.Sprintf("Terrain hides all of golden hour (%.1f°) toward %s", golden, direction)
+	case angle > 0:
+		summary += fmt.Sprintf("Sun hidden below %.1f° toward %s", angle, direction)
+	default:
+		summary += fmt.Sprintf("Open view to the horizon toward %s", direction)
+	}
+
+	mw.mapView.SetProfile(profile, index, summary)
+	mw.setStatus(strings.ReplaceAll(summary, "\n", " - "))
+}
+
+// ShowCloudFrames plays cloud frames in the map's cloud layer.
+//
+// This is called by the App controller when frames requested via
+// FetchCloudFrames arrive. aroundGolden tells whether the frames cover
+// today's evening golden hour (golden) or are just the latest ones, which
+// the status bar explains.
+func (mw *MainWindow) ShowCloudFrames(frames []domain.CloudFrame, golden domain.TimeRange, aroundGolden bool) {
+	use24Hour := mw.config.Settings.TimeFormat24Hour
+	mw.mapView.SetCloudFrames(frames, weather.MaxZoom, weather.Attribution, golden, use24Hour)
+	switch {
+	case aroundGolden:
+		mw.setStatus(fmt.Sprintf("Clouds around golden hour (%s - %s)",
+			domain.FormatTime(golden.Start, use24Hour), domain.FormatTime(golden.End, use24Hour)))
+	case golden.IsValid():
+		mw.setStatus(fmt.Sprintf("Clouds of the last %d frames; golden hour (%s) is too far off for frames around it",
+			len(frames), domain.FormatTime(golden.Start, use24Hour)))
+	default:
+		mw.setStatus(fmt.Sprintf("Clouds of the last %d frames", len(frames)))
+	}
+}
+
+// MapBounds returns the area the map shows, for searches near the map
+// view; ok is false before the map has loaded.
+func (mw *MainWindow) MapBounds() (bounds domain.Bounds, ok bool) {
+	if mw.mapView == nil {
+		return domain.Bounds{}, false
+	}
+	return mw.mapView.Bounds()
+}
+
+// UpdateSunPath redraws the evening golden hour sun path on the map.
+//
+// This is called by the App controller after every recalculation, with the
+// sun's positions through the selected date's evening golden hour at loc
+// (nil if there is none).
+func (mw *MainWindow) UpdateSunPath(loc domain.Location, path []domain.SunPathPoint) {
+	if mw.mapView != nil {
+		mw.mapView.SetSunPath(loc, path, mw.config.Settings.TimeFormat24Hour)
+	}
+}
+
+// UpdateSunPositions gives the time slider the sun's positions through the
+// selected day.
+//
+// This is called by the App controller after every recalculation, with
+// positions sampled at loc (nil if they couldn't be calculated, which
+// removes the sun ray from the map).
+func (mw *MainWindow) UpdateSunPositions(loc domain.Location, positions []domain.SunPathPoint) {
+	if mw.timeScrubber == nil {
+		return
+	}
+	mw.scrubberLocation = loc
+	mw.timeScrubber.SetPositions(positions, mw.config.Settings.TimeFormat24Hour)
+	if mw.timePanel != nil {
+		// For the azimuths in the period tooltips
+		mw.timePanel.SetPositions(positions)
+	}
+	if len(positions) == 0 && mw.mapView != nil {
+		mw.mapView.ClearSunPosition()
+	}
+}
+
+// onScrub draws the sun's direction at the time slider's time on the map.
+//
+// This is passed to TimeScrubber as a callback during construction.
+func (mw *MainWindow) onScrub(pos domain.SunPathPoint) {
+	if mw.mapView != nil {
+		mw.mapView.SetSunPosition(mw.scrubberLocation, pos, mw.config.Settings.TimeFormat24Hour)
+	}
+}
+
+// UpdateTerminator redraws the map's day/night overlay.
+//
+// This is called by the App controller periodically with bands computed
+// for the current time.
+func (mw *MainWindow) UpdateTerminator(bands []domain.TwilightBand) {
+	if mw.mapView != nil {
+		mw.mapView.SetTerminator(bands)
+	}
+}
+
+// ShowHookResult reports the outcome of an automation hook in the status bar.
+//
+// This is called by the App controller after a hook ran, either at its
+// scheduled event or from the preferences dialog's "Test" button. On
+// failure the tail of the command's output is included, since that is
+// usually where the reason is printed.
+//
+// Push reminders and webhook calls, which the scheduler queues alongside
+// the hooks, are reported here as well, and due desktop notifications are
+// shown (see showNotification).
+func (mw *MainWindow) ShowHookResult(result automation.Result) {
+	label := result.Event.Kind.Label()
+	if result.Notification {
+		if result.Err != nil {
+			mw.ShowError(fmt.Sprintf("%s notification not shown: %v", label, result.Err))
+			return
+		}
+		mw.showNotification(result.Output, result.Message)
+		return
+	}
+	if result.Webhook {
+		if result.Err != nil {
+			mw.ShowError(fmt.Sprintf("%s webhook failed: %v", label, result.Err))
+			return
+		}
+		mw.setStatus(fmt.Sprintf("Webhook called: %s at %s", label, result.Output))
+		return
+	}
+	if result.Reminder {
+		if result.Err != nil {
+			mw.ShowError(fmt.Sprintf("%s reminder not pushed: %v", label, result.Err))
+			return
+		}
+		mw.setStatus(fmt.Sprintf("Pushed reminder: %s", result.Output))
+		return
+	}
+	if result.Err != nil {
+		message := fmt.Sprintf("%s hook failed: %v", label, result.Err)
+		if result.Output != "" {
+			message += " (" + result.Output + ")"
+		}
+		mw.ShowError(message)
+		return
+	}
+	mw.setStatus(fmt.Sprintf("%s hook ran at %s", label, time.Now().Format("15:04")))
+}
+
+// ShowSummaryResult reports the outcome of a daily summary in the status bar.
+//
+// This is called by the App controller after the summary was sent at its
+// scheduled time or with "Send Now" in the preferences dialog, or when it
+// was skipped because the computer was asleep.
+func (mw *MainWindow) ShowSummaryResult(result automation.SummaryResult) {
+	if result.Err != nil {
+		mw.ShowError(fmt.Sprintf("Daily summary not sent: %v", result.Err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Daily summary sent to %s at %s", result.Target, time.Now().Format("15:04")))
+}
+
+// ShowFeedResult reports a widget feed write in the status bar.
+//
+// This is called by the App controller when a scheduled write fails (once
+// until one succeeds again), and after "Write Now" in the preferences
+// dialog.
+func (mw *MainWindow) ShowFeedResult(result automation.FeedResult) {
+	if result.Err != nil {
+		mw.ShowError(fmt.Sprintf("Widget feed not written: %v", result.Err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Widget feed written to %s at %s", result.Path, time.Now().Format("15:04")))
+}
+
+// ShowPushResult reports the outcome of "Send 14-Day Schedule" in the
+// status bar.
+//
+// This is called by the App controller once the schedule was pushed, with
+// the service's error if it failed.
+func (mw *MainWindow) ShowPushResult(err error) {
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Schedule not pushed: %v", err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("%d-day schedule pushed to the phone", domain.PushScheduleDays))
+}
+
+// ShowCalendarSyncResult reports the outcome of "Sync to Calendar" in the
+// status bar.
+//
+// This is called by the App controller once the events were sent, with what
+// was done and the error that stopped the sync, if any.
+func (mw *MainWindow) ShowCalendarSyncResult(result caldav.Result, err error) {
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Calendar not synced: %v", err))
+		return
+	}
+	status := fmt.Sprintf("Calendar synced: %d added, %d updated", result.Created, result.Updated)
+	if len(result.Conflicts) > 0 {
+		status += "; changed in the calendar, left alone: " + strings.Join(result.Conflicts, ", ")
+	}
+	mw.setStatus(status)
+}
+
+// UpdateRecentLocations refreshes the location panel's "Recent" menu.
+//
+// Called by the App after every location change (and when scratch
+// locations expire or are cleared), with the new history (most recent
+// first).
+func (mw *MainWindow) UpdateRecentLocations(recent []domain.RecentLocation) {
+	if mw.locationPanel != nil {
+		mw.locationPanel.SetRecentLocations(recent)
+	}
+}
+
+// favoritesLayer is the title of the map point layer showing the favorites.
+const favoritesLayer = "Favorites"
+
+// UpdateFavorites shows the favorites after one was added or removed, or
+// after their times changed.
+//
+// The map's Favorites layer and the favorites panel are updated, and the
+// location panel's star is set for the current location.
+//
+// Parameters:
+//   - favorites: All favorites in settings order
+//   - tonight: Each favorite's evening golden hour today, in the same order
+func (mw *MainWindow) UpdateFavorites(favorites []domain.Favorite, tonight []domain.TimeRange) {
+	mw.mapView.SetPoints(favoritesLayer, favoritePoints(favorites))
+	mw.favoritesPanel.SetFavorites(favorites, tonight, mw.config.Settings.TimeFormat24Hour,
+		mw.config.Settings.PlaceNameStyle)
+	_, isFavorite := domain.FavoriteAt(favorites, mw.controller.GetLocation())
+	mw.showFavorite(isFavorite)
+}
+
+// showFavorite marks whether the current location is a favorite, in the
+// location panel's star and in the Tools menu.
+func (mw *MainWindow) showFavorite(isFavorite bool) {
+	mw.locationPanel.SetFavorite(isFavorite)
+	if mw.favoriteAction != nil {
+		mw.favoriteAction.SetChecked(isFavorite)
+	}
+}
+
+// favoritePoints converts favorites to map points, using the favorite IDs
+// as point IDs so the layer updates incrementally.
+func favoritePoints(favorites []domain.Favorite) []domain.MapPoint {
+	points := make([]domain.MapPoint, len(favorites))
+	for i, f := range favorites {
+		points[i] = domain.MapPoint{ID: f.ID, Location: f.Location}
+	}
+	return points
+}
+
+// ShowWhatsNew displays the release notes of the given versions.
+//
+// Called by the App on the first start after an update, and from
+// Help → What's New with all releases. The "Show what's new after updates"
+// checkbox in the dialog is passed to AppController.UpdateShowWhatsNew.
+func (mw *MainWindow) ShowWhatsNew(releases []changelog.Release) {
+	show := widgets.ShowWhatsNewDialog(mw.window.QWidget, releases,
+		mw.controller.GetSettings().ShowWhatsNew)
+	mw.controller.UpdateShowWhatsNew(show)
+}
+
+// ShowWelcome shows the welcome wizard of a first start (see
+// widgets.ShowWelcomeWizard) and passes the answers to the AppController.
+// The home search uses the offline city database. Nothing changes if the
+// user cancels.
+func (mw *MainWindow) ShowWelcome() {
+	choices, ok := widgets.ShowWelcomeWizard(mw.window.QWidget, mw.controller.GetSettings(),
+		geocoding.SearchOffline)
+	if !ok {
+		return
+	}
+	mw.controller.CompleteWelcome(choices.Home, choices.AutoDetect, choices.TimeFormat24Hour,
+		choices.CoordinateFormat)
+}
+
+// LocateWithMap starts a browser geolocation request in the map.
+//
+// Used by the App's location detection when the map is the selected
+// location source. The result is reported to AppController.OnMapLocate.
+func (mw *MainWindow) LocateWithMap() {
+	mw.setStatus("Locating with map geolocation...")
+	mw.mapView.Locate()
+}
+
+// ShowSearchResults offers the results of a location search to choose from.
+//
+// The results open in a dropdown under the location panel's search field;
+// the one the user selects is passed to AppController.UpdateLocation (see
+// onSearchResultSelected).
+func (mw *MainWindow) ShowSearchResults(results []domain.SearchResult) {
+	mw.setStatus(fmt.Sprintf("%d places found: choose one", len(results)))
+	mw.locationPanel.ShowSearchResults(results)
+}
+
+// ShowError displays an error message.
+//
+// This is called by the App controller when operations fail:
+//   - Location detection and search failures (with a Retry button, see
+//     ShowRetryableError)
+//   - Calculation errors
+//   - Settings save failures
+//
+// Errors are shown in a toast over the bottom of the window, which hides
+// itself after a few seconds (see widgets.Toast) and doesn't block the
+// window; the last ones are listed in the status bar's tooltip.
+func (mw *MainWindow) ShowError(message string) {
+	mw.ShowRetryableError(message, nil)
+}
+
+// ShowRetryableError displays an error with a Retry button, for an
+// operation that may work when repeated (e.g., a search that timed out).
+//
+// Called by the App controller when location detection or a search fails.
+//
+// Parameters:
+//   - message: The error message
+//   - retry: Runs the operation again when the user clicks Retry; nil
+//     shows the error without the button
+func (mw *MainWindow) ShowRetryableError(message string, retry func()) {
+	if mw.toast == nil {
+		return
+	}
+	mw.toast.Show(message, retry)
+	mw.statusArea.LogError(message)
+}
+
+// SetBusy shows the progress indicator while network operations run, and
+// turns the location panel's Go and Detect buttons into spinning stop
+// buttons while a search or a detection runs.
+// Called by the App controller when an operation starts (busy=true) and
+// when its result arrives, it fails or it is stopped (busy=false).
+func (mw *MainWindow) SetBusy(task frontend.Task, busy bool) {
+	mw.statusArea.SetBusy(string(task), busy)
+	switch task {
+	case frontend.TaskSearch:
+		mw.locationPanel.SetSearching(busy)
+	case frontend.TaskDetect:
+		mw.locationPanel.SetDetecting(busy)
+	}
+}
+
+// =============================================================================
+// Internal Helpers
+// =============================================================================
+
+// setStatus shows a status message, cleared after a few seconds (see
+// widgets.StatusArea).
+//
+// Nil check protects against calls during initialization.
+func (mw *MainWindow) setStatus(message string) {
+	if mw.statusArea != nil {
+		mw.statusArea.ShowMessage(message)
+	}
+}
+
+// =============================================================================
+// Event Handlers (callbacks from widgets)
+// =============================================================================
+
+// onMapClick handles map click events from the MapView widget.
+//
+// This is passed to MapView as a callback during construction.
+// When the user clicks on the map, MapView parses the coordinates
+// from the JavaScript console message and calls this handler.
+//
+// The handler simply delegates to the AppController for processing.
+func (mw *MainWindow) onMapClick(lat, lon float64) {
+	mw.controller.OnMapClick(lat, lon)
+}
+
+// onMarkerDrag handles the location marker being dropped after a drag.
+//
+// The drop point is selected like a map click: the AppController reverse
+// geocodes it in the background, and the status bar shows the lookup is
+// in progress until the new location arrives.
+func (mw *MainWindow) onMarkerDrag(lat, lon float64) {
+	mw.setStatus(fmt.Sprintf("Looking up %s…",
+		domain.FormatCoordinatesIn(lat, lon, mw.config.Settings.CoordinateFormat)))
+	mw.controller.OnMapClick(lat, lon)
+}
+
+// onPointClick handles clicks on points of the MapView's point layers.
+//
+// The handler delegates to the AppController, which selects the point as
+// the location, keeping its name from the imported file.
+func (mw *MainWindow) onPointClick(point domain.MapPoint) {
+	mw.controller.SelectMapPoint(point.Location)
+}
+
+// onMapLocate handles browser geolocation results from the MapView widget.
+//
+// The handler simply delegates to the AppController, which selects the
+// position (or falls back to IP detection on failure).
+func (mw *MainWindow) onMapLocate(lat, lon float64, err error) {
+	mw.controller.OnMapLocate(lat, lon, err)
+}
+
+// onMapCursor shows the coordinates under the pointer in the status bar.
+//
+// ok is false when the pointer has left the map, which clears the label.
+func (mw *MainWindow) onMapCursor(lat, lon float64, ok bool) {
+	if !ok {
+		mw.cursorLabel.SetText("")
+		return
+	}
+	mw.cursorLabel.SetText(domain.FormatCoordinatesIn(lat, lon, mw.config.Settings.CoordinateFormat))
+}
+
+// onMeasure handles completed measurements from the MapView widget.
+//
+// When the user measures between two points in the map's measure mode,
+// the distance and initial bearing are computed and echoed to the status
+// bar, e.g. "Distance: 12.3 km, Bearing: 247.5° (WSW)". An elevation profile
+// along the line is requested as well (see ShowElevationProfile).
+//
+// The bearing uses the same convention as sun azimuth (clockwise from true
+// north), so it can be compared directly with sunrise/sunset directions.
+func (mw *MainWindow) onMeasure(fromLat, fromLon, toLat, toLon float64) {
+	from := domain.Location{Latitude: fromLat, Longitude: fromLon}
+	to := domain.Location{Latitude: toLat, Longitude: toLon}
+	bearing := from.BearingTo(to)
+
+	mw.setStatus(fmt.Sprintf("Distance: %s, Bearing: %.1f° (%s)",
+		domain.FormatDistance(from.DistanceTo(to)), bearing, domain.CompassPoint(bearing)))
+	mw.controller.FetchElevationProfile(from, to)
+}
+
+// onMapZoom handles zoom level changes from the MapView widget.
+//
+// The handler delegates to the AppController, which persists the level.
+func (mw *MainWindow) onMapZoom(zoom int) {
+	mw.controller.UpdateMapZoom(zoom)
+}
+
+// onShowPreferences opens the preferences dialog from Edit → Preferences.
+//
+// The dialog works on a snapshot of the current settings. If the user
+// confirms, the automation switch and hooks are handed to the AppController,
+// which saves them and re-arms the scheduler, followed by the daily summary,
+// the push notifications, the webhook, the desktop notifications, the
+// calendar login, the location source and providers, the display formats,
+// the home clock, the copy template, the tray option, the aurora outlook,
+// the contact email, the tile server, the search bias and the custom
+// events. The settings panel and the formats of the panels are refreshed,
+// and the time panel is redrawn with the new home clock.
+//
+// The dialog is opened from Edit → Preferences and from "More Settings..."
+// in the settings panel.
+func (mw *MainWindow) onShowPreferences() {
+	dialog := widgets.NewPreferencesDialog(mw.window.QWidget, mw.controller.GetSettings(),
+		mw.controller.GetLocation(), timezone.Names(),
+		mw.controller.TestHook, mw.onSendSummaryNow, mw.onWriteFeedNow, mw.onSendPushSchedule,
+		mw.onTestWebhook, mw.controller.TestNotification)
+	if !dialog.Exec() {
+		return
+	}
+
+	mw.controller.UpdateAutomation(dialog.AutomationEnabled(), dialog.Hooks())
+	mw.controller.UpdateDailySummary(dialog.DailySummary())
+	mw.controller.UpdateWidgetFeed(dialog.WidgetFeed())
+	mw.controller.UpdatePush(dialog.PushNotifications())
+	mw.controller.UpdateWebhook(dialog.Webhook())
+	mw.controller.UpdateNotifications(dialog.Notifications())
+	mw.controller.UpdateCalDAV(dialog.CalDAV())
+	mw.controller.UpdateLocationProviders(dialog.LocationSource(), dialog.LocationProviders(),
+		dialog.SecureLocationOnly())
+	mw.controller.UpdateDisplayFormats(dialog.CoordinateFormat(), dialog.PlaceNameStyle(),
+		dialog.TimePanelLayout(), dialog.ScratchRetentionDays())
+	mw.controller.UpdateHomeLocation(dialog.HomeLocation())
+	mw.controller.UpdateHomeTimezone(dialog.HomeTimezone())
+	mw.controller.UpdateCopyTemplate(dialog.CopyTemplate())
+	mw.controller.UpdateMinimizeToTray(dialog.MinimizeToTray())
+	mw.controller.UpdateAuroraForecast(dialog.AuroraForecast())
+	mw.controller.UpdateContactEmail(dialog.ContactEmail())
+	mw.controller.UpdateTileServer(dialog.TileServer())
+	mw.controller.UpdateSearchBias(dialog.SearchBias())
+	mw.controller.UpdateCustomEvents(dialog.CustomEvents())
+
+	settings := mw.controller.GetSettings()
+	mw.ApplySettings(settings)
+	mw.applyDisplayFormats(settings)
+	mw.setTileServer(settings.TileServer)
+	mw.timePanel.SetHomeTimezone(settings.HomeTimezone)
+	mw.timePanel.SetSunTimes(mw.sunTimes, mw.config.Settings.TimeFormat24Hour)
+	mw.setStatus("Preferences saved")
+}
+
+// onSendSummaryNow handles "Send Now" in the preferences dialog.
+//
+// Sending may take a moment (an SMTP server can be slow to answer), so the
+// status bar is updated immediately and the AppController sends the
+// summary in the background.
+func (mw *MainWindow) onSendSummaryNow(summary domain.DailySummary) {
+	mw.setStatus("Sending daily summary...")
+	mw.controller.SendSummaryNow(summary)
+}
+
+// onWriteFeedNow handles "Write Now" in the preferences dialog's Widget
+// Feed tab. The result arrives in ShowFeedResult.
+func (mw *MainWindow) onWriteFeedNow(feed domain.WidgetFeed) {
+	mw.setStatus("Writing widget feed...")
+	mw.controller.WriteFeedNow(feed)
+}
+
+// onSendPushSchedule handles "Send 14-Day Schedule" in the preferences
+// dialog. The schedule is calculated and pushed in the background.
+func (mw *MainWindow) onSendPushSchedule(push domain.PushNotifications) {
+	mw.setStatus("Pushing schedule...")
+	mw.controller.SendPushSchedule(push)
+}
+
+// onTestWebhook handles "Send Test" in the preferences dialog's Webhook
+// tab. The result arrives in ShowHookResult.
+func (mw *MainWindow) onTestWebhook(webhook domain.Webhook) {
+	mw.setStatus("Calling webhook...")
+	mw.controller.TestWebhook(webhook)
+}
+
+// onShowStatistics shows the operation and cache counts (Debug → Statistics).
+func (mw *MainWindow) onShowStatistics() {
+	widgets.ShowStatisticsDialog(mw.window.QWidget, stats.Snapshot, stats.Reset)
+}
+
+// onOpenProfile asks for a settings profile and opens it in another window
+// (Tools → Open Profile).
+func (mw *MainWindow) onOpenProfile() {
+	profiles, err := storage.ListProfiles()
+	if err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	profile, ok := widgets.ChooseProfile(profiles, storage.ValidateProfileName)
+	if !ok {
+		return
+	}
+	if err := mw.controller.OpenProfile(profile); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Opening the %s profile in a new window...", cmp.Or(profile, "default")))
+}
+
+// onCheckForUpdates looks up the latest release (Help → Check for
+// Updates); the result is shown by ShowUpdateCheck.
+func (mw *MainWindow) onCheckForUpdates() {
+	mw.setStatus("Checking for updates...")
+	mw.controller.CheckForUpdates()
+}
+
+// ShowUpdateCheck shows the result of an update check. Called by the App
+// controller.
+//
+// A newer release is offered in a dialog that opens its page in the
+// browser; otherwise the status bar says the app is up to date.
+//
+// Parameters:
+//   - latest: The latest release
+//   - newer: True if latest is newer than the running version
+//
+// miqt API notes:
+//   - QMessageBox_Question5(parent, title, text, buttons) returns the
+//     clicked button
+//   - QDesktopServices_OpenUrl opens the URL in the default browser
+func (mw *MainWindow) ShowUpdateCheck(latest updates.Release, newer bool) {
+	if !newer {
+		mw.setStatus(fmt.Sprintf("GoGoldenHour %s is up to date", mw.config.AppVersion))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("GoGoldenHour %s is available", latest.Version))
+	answer := qt.QMessageBox_Question5(mw.window.QWidget, "Check for Updates",
+		fmt.Sprintf("GoGoldenHour %s is available (this is %s).\n\nOpen the release page to download it?",
+			latest.Version, mw.config.AppVersion),
+		qt.QMessageBox__Yes|qt.QMessageBox__No)
+	if answer == qt.QMessageBox__Yes {
+		qt.QDesktopServices_OpenUrl(qt.NewQUrl3(latest.URL))
+	}
+}
+
+// onShowAbout shows the version, build and credits (Help → About
+// GoGoldenHour; see widgets.ShowAboutDialog).
+func (mw *MainWindow) onShowAbout() {
+	widgets.ShowAboutDialog(mw.window.QWidget, mw.config.AppVersion, updates.ReleasesURL, mw.config.ConfigDir)
+}
+
+// onShowWhatsNew shows the release notes of all versions (Help → What's New).
+func (mw *MainWindow) onShowWhatsNew() {
+	releases, err := changelog.Releases()
+	if err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.ShowWhatsNew(releases)
+}
+
+// onImportMapData asks for a GPX/KML/GeoJSON file and shows it on the map.
+//
+// Each file gets its own point layer, named after the file, so several
+// files can be shown and toggled separately. Importing the same file again
+// replaces its layer. The map zooms to fit the imported data.
+//
+// QFileDialog_GetOpenFileName4(parent, caption, dir, filter) returns an
+// empty string if the user cancels.
+func (mw *MainWindow) onImportMapData() {
+	path := qt.QFileDialog_GetOpenFileName4(mw.window.QWidget, "Import Map Data", "",
+		"Map data (*.gpx *.kml *.kmz *.geojson *.json);;All files (*)")
+	if path == "" {
+		return
+	}
+
+	data, err := mw.controller.ImportMapData(path)
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Failed to import %s: %v", filepath.Base(path), err))
+		return
+	}
+
+	title := filepath.Base(path)
+	mw.mapView.SetPoints(title, data.Points)
+	mw.mapView.SetTracks(title, data.Tracks)
+	if south, west, north, east, ok := data.Bounds(); ok {
+		mw.mapView.FitBounds(south, west, north, east)
+	}
+	mw.setStatus(fmt.Sprintf("Imported %d points and %d tracks from %s",
+		len(data.Points), len(data.Tracks), title))
+}
+
+// onExportWatchCalendar asks for a file name and exports the watch calendar.
+//
+// QFileDialog_GetSaveFileName4(parent, caption, dir, filter) returns an
+// empty string if the user cancels.
+func (mw *MainWindow) onExportWatchCalendar() {
+	path := qt.QFileDialog_GetSaveFileName4(mw.window.QWidget, "Export Watch Calendar",
+		"golden-hour.ics", "iCalendar files (*.ics)")
+	if path == "" {
+		return
+	}
+
+	if err := mw.controller.ExportWatchCalendar(path); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus("Watch calendar exported to " + path)
+}
+
+// onSyncCalendar sends the watch calendar's events to the CalDAV calendar.
+// The result arrives in ShowCalendarSyncResult.
+func (mw *MainWindow) onSyncCalendar() {
+	mw.setStatus("Syncing calendar...")
+	mw.controller.SyncCalendar()
+}
+
+// onAddToGoogleCalendar opens Google Calendar's event form for a period,
+// picked from the Sun Times panel's context menu.
+//
+// miqt API notes:
+//   - QDesktopServices_OpenUrl opens the URL in the default browser
+func (mw *MainWindow) onAddToGoogleCalendar(period string) {
+	link, err := mw.controller.GoogleCalendarURL(period)
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Can't add to Google Calendar: %v", err))
+		return
+	}
+	qt.QDesktopServices_OpenUrl(qt.NewQUrl3(link))
+	mw.setStatus("Opened Google Calendar in the browser")
+}
+
+// onAddToCalendar sends a period, picked from the Sun Times panel's
+// context menu, to the CalDAV calendar. The result arrives in
+// ShowCalendarSyncResult.
+func (mw *MainWindow) onAddToCalendar(period string) {
+	mw.setStatus("Adding to calendar...")
+	mw.controller.AddToCalendar(period)
+}
+
+// onExportDateRange asks for a file name and exports the days of the date
+// range as CSV.
+func (mw *MainWindow) onExportDateRange() {
+	opts, target := widgets.ShowDayExportDialog(mw.window.QWidget, mw.dayExportOptions)
+	if target == widgets.DayExportCancel {
+		return
+	}
+	mw.dayExportOptions = opts
+
+	if target == widgets.DayExportClipboard {
+		text, err := mw.controller.DateRangeText(opts)
+		if err != nil {
+			mw.ShowError(err.Error())
+			return
+		}
+		qt.QGuiApplication_Clipboard().SetText(text)
+		mw.setStatus("Days copied to the clipboard")
+		return
+	}
+
+	path := qt.QFileDialog_GetSaveFileName4(mw.window.QWidget, "Export Date Range",
+		"golden-hour-days.csv", "CSV files (*.csv);;JSON files (*.json)")
+	if path == "" {
+		return
+	}
+	if err := mw.controller.ExportDateRange(path, opts); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus("Days exported to " + path)
+}
+
+// onCopyTimes handles Edit → Copy Times: the selected day's times, filled
+// into the copy template, go on the clipboard for pasting into a chat or a
+// note.
+func (mw *MainWindow) onCopyTimes() {
+	text, err := mw.controller.CopyTimesText()
+	if err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	qt.QGuiApplication_Clipboard().SetText(text)
+	mw.setStatus("Times copied to the clipboard")
+}
+
+// onGenerateShootPlan asks for a file name and prints a one-page PDF shoot
+// plan there: a snapshot of the map above the location's details and the
+// times of the selected date or date range.
+func (mw *MainWindow) onGenerateShootPlan() {
+	html, err := mw.controller.ShootPlanHTML()
+	if err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	path := qt.QFileDialog_GetSaveFileName4(mw.window.QWidget, "Shoot Plan",
+		"shoot-plan.pdf", "PDF files (*.pdf)")
+	if path == "" {
+		return
+	}
+
+	title := "Shoot Plan: " + mw.controller.GetLocation().DisplayName(mw.config.Settings.PlaceNameStyle)
+	if err := widgets.WriteShootPlanPDF(path, title, mw.mapView.Snapshot(), html); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus("Shoot plan saved to " + path)
+}
+
+// onExportWaypoints asks for a file name and exports the current location
+// and the favorites as GPX waypoints with their sun times on the selected
+// date or date range.
+func (mw *MainWindow) onExportWaypoints() {
+	path := qt.QFileDialog_GetSaveFileName4(mw.window.QWidget, "Export Shoot Waypoints",
+		"shoot-waypoints.gpx", "GPX files (*.gpx)")
+	if path == "" {
+		return
+	}
+
+	if err := mw.controller.ExportWaypoints(path); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus("Shoot waypoints exported to " + path)
+}
+
+// onAnalyzePhoto asks for a photo and reports the light it was taken in,
+// offering to show its place and date.
+func (mw *MainWindow) onAnalyzePhoto() {
+	path := qt.QFileDialog_GetOpenFileName4(mw.window.QWidget, "Analyze Photo", "", photo.FileFilter)
+	if path == "" {
+		return
+	}
+
+	light, err := mw.controller.AnalyzePhoto(path)
+	if err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	settings := mw.controller.GetSettings()
+	answer := qt.QMessageBox_Question5(mw.window.QWidget, "Analyze Photo: "+light.File,
+		light.Report(settings.TimeFormat24Hour, settings.CoordinateFormat)+"\n\nShow this place and date?",
+		qt.QMessageBox__Yes|qt.QMessageBox__No)
+	if answer != qt.QMessageBox__Yes {
+		return
+	}
+	mw.controller.UpdateLocation(light.Location)
+	mw.controller.UpdateDate(light.Taken)
+}
+
+// onImportFavorites asks for a file and adds its points to the favorites.
+//
+// Files exported with "Export My Places..." and the map data formats are
+// accepted; points that are favorites already are skipped.
+func (mw *MainWindow) onImportFavorites() {
+	path := qt.QFileDialog_GetOpenFileName4(mw.window.QWidget, "Import My Places", "",
+		"Places (*.geojson *.json *.csv *.gpx *.kml *.kmz);;All files (*)")
+	if path == "" {
+		return
+	}
+
+	added, found, err := mw.controller.ImportFavorites(path)
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Failed to import %s: %v", filepath.Base(path), err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Added %d of %d places from %s to the favorites",
+		added, found, filepath.Base(path)))
+}
+
+// onEditFavorite shows a favorite's own settings from the favorites list
+// (see widgets.ShowFavoriteSettingsDialog) and saves them.
+func (mw *MainWindow) onEditFavorite(favorite domain.Favorite) {
+	overrides, ok := widgets.ShowFavoriteSettingsDialog(mw.window.QWidget, favorite,
+		mw.controller.GetSettings().GoldenHourElevation, timezone.Names())
+	if !ok {
+		return
+	}
+	mw.controller.UpdateFavoriteOverrides(favorite.ID, overrides)
+}
+
+// onExportFavorites asks for a file name and exports the favorites.
+//
+// The filter chosen in the dialog only suggests the format; the App picks
+// it from the extension of the file name.
+func (mw *MainWindow) onExportFavorites() {
+	path := qt.QFileDialog_GetSaveFileName4(mw.window.QWidget, "Export My Places",
+		"my-places.geojson", "GeoJSON files (*.geojson);;CSV files (*.csv)")
+	if path == "" {
+		return
+	}
+
+	if err := mw.controller.ExportFavorites(path); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus("Favorites exported to " + path)
+}
+
+// onExportSettings asks for a file name and exports the settings of all
+// profiles (see AppController.ExportConfiguration).
+//
+// A change still waiting in the settings panel is reported first, so the
+// file has the settings as shown.
+func (mw *MainWindow) onExportSettings() {
+	mw.settingsPanel.Flush()
+	path := qt.QFileDialog_GetSaveFileName4(mw.window.QWidget, "Export Settings",
+		"gogoldenhour-settings.json", "Settings files (*.json)")
+	if path == "" {
+		return
+	}
+
+	count, err := mw.controller.ExportConfiguration(path)
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Settings not exported: %v", err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Settings of %d profile(s) exported to %s (the file includes passwords; keep it private)",
+		count, path))
+}
+
+// onImportSettings asks for a file exported with "Export Settings...",
+// validates it and, once the user confirms which profiles it replaces,
+// imports it.
+//
+// Nothing changes if the file is invalid or the user declines. The current
+// profile is marked in the confirmation, as its settings change at once.
+func (mw *MainWindow) onImportSettings() {
+	path := qt.QFileDialog_GetOpenFileName4(mw.window.QWidget, "Import Settings", "",
+		"Settings files (*.json);;All files (*)")
+	if path == "" {
+		return
+	}
+
+	bundle, err := mw.controller.ReadConfiguration(path)
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Failed to import %s: %v", filepath.Base(path), err))
+		return
+	}
+
+	var names []string
+	for _, p := range bundle.Profiles {
+		name := p.Name
+		if name == "" {
+			name = "default"
+		}
+		if p.Name == mw.config.Profile {
+			name += " (current)"
+		}
+		names = append(names, "  • "+name)
+	}
+	answer := qt.QMessageBox_Question5(mw.window.QWidget, "Import Settings",
+		fmt.Sprintf("Replace the settings of these profiles with those exported on %s?\n\n%s\n\n"+
+			"Favorites, presets and automation are replaced as well; other profiles are kept.",
+			bundle.Exported.Local().Format("2006-01-02 15:04"), strings.Join(names, "\n")),
+		qt.QMessageBox__Yes|qt.QMessageBox__No)
+	if answer != qt.QMessageBox__Yes {
+		return
+	}
+
+	if err := mw.controller.ImportConfiguration(bundle); err != nil {
+		mw.ShowError(fmt.Sprintf("Settings not fully imported: %v", err))
+		return
+	}
+	mw.setStatus(fmt.Sprintf("Settings of %d profile(s) imported from %s", len(bundle.Profiles), filepath.Base(path)))
+}
+
+// onAlign handles camera/subject selections from the MapView's alignment mode.
+//
+// The search over a full year takes a moment, so the status bar is updated
+// immediately and the AppController runs the search in the background.
+func (mw *MainWindow) onAlign(camLat, camLon, subLat, subLon float64) {
+	camera := domain.Location{Latitude: camLat, Longitude: camLon}
+	subject := domain.Location{Latitude: subLat, Longitude: subLon}
+
+	mw.setStatus("Searching for sun alignments...")
+	mw.controller.FindSunAlignments(camera, subject)
+}
+
+// minCameraRange is the shortest field of view cone drawn on the map, in
+// meters, so the cone stays visible when both clicks are close together.
+const minCameraRange = 500
+
+// onPlaceCamera handles camera placements from the MapView's camera mode.
+//
+// The camera faces the second click, and the cone reaches that far. The lens
+// settings carry over from the previous camera. The CameraDialog opens (or
+// updates) so the placement can be fine-tuned.
+func (mw *MainWindow) onPlaceCamera(camLat, camLon, dirLat, dirLon float64) {
+	location := domain.Location{Latitude: camLat, Longitude: camLon}
+	target := domain.Location{Latitude: dirLat, Longitude: dirLon}
+
+	if mw.cameraDialog == nil {
+		mw.cameraDialog = widgets.NewCameraDialog(mw.window.QWidget, mw.onCameraChange, mw.mapView.ClearCameraView)
+	}
+
+	mw.cameraRange = max(location.DistanceTo(target), minCameraRange)
+	mw.camera = mw.cameraDialog.SetCamera(domain.Camera{
+		Location:    location,
+		Heading:     location.BearingTo(target),
+		FocalLength: mw.camera.FocalLength,
+		Portrait:    mw.camera.Portrait,
+	})
+	mw.refreshCameraView()
+	mw.cameraDialog.Show()
+}
+
+// onCameraChange handles edits in the CameraDialog.
+func (mw *MainWindow) onCameraChange(heading, focalLength float64, portrait bool) {
+	mw.camera.Heading = heading
+	mw.camera.FocalLength = focalLength
+	mw.camera.Portrait = portrait
+	mw.refreshCameraView()
+}
+
+// refreshCameraView recomputes the camera's horizon events and redraws the
+// field of view on the map and in the CameraDialog.
+func (mw *MainWindow) refreshCameraView() {
+	events, err := mw.controller.HorizonEvents(mw.camera.Location)
+	if err != nil {
+		mw.ShowError(fmt.Sprintf("Failed to calculate horizon events: %v", err))
+		return
+	}
+	mw.mapView.SetCameraView(mw.camera, mw.cameraRange, events)
+	mw.cameraDialog.SetEvents(mw.camera, events, mw.config.Settings.TimeFormat24Hour)
+}
+
+// onShowMonthCalendar opens the month calendar at the selected date's
+// month (or brings it to the front if already open).
+func (mw *MainWindow) onShowMonthCalendar() {
+	if mw.monthDialog == nil {
+		mw.monthDialog = widgets.NewMonthDialog(mw.window.QWidget, mw.showMonth, mw.controller.UpdateDate)
+	}
+	date := mw.controller.GetDate()
+	mw.showMonth(time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.Local))
+	mw.monthDialog.Show()
+}
+
+// showMonth fills the month calendar with a month's days at the current
+// location.
+func (mw *MainWindow) showMonth(month time.Time) {
+	days, err := mw.controller.MonthSunTimes(month)
+	if err != nil {
+		mw.ShowError(err.Error())
+	}
+	meteors, err := mw.controller.MonthMeteorNights(month)
+	if err != nil {
+		mw.ShowError(err.Error())
+	}
+	mw.monthDialog.SetMonth(month, days, meteors, mw.controller.GetDate(), mw.config.Settings.TimeFormat24Hour)
+}
+
+// onShowWeekOutlook opens the week outlook (or brings it to the front if
+// already open); the App fills it in when the forecast arrives (see
+// ShowWeekOutlook).
+func (mw *MainWindow) onShowWeekOutlook() {
+	if mw.weekDialog == nil {
+		mw.weekDialog = widgets.NewWeekDialog(mw.window.QWidget, mw.controller.UpdateDate)
+	}
+	mw.controller.FetchWeekOutlook()
+	mw.weekDialog.Show()
+}
+
+// ShowWeekOutlook fills the week outlook with the coming week's days and
+// their forecast.
+//
+// This is called by the App controller when the forecast requested via
+// FetchWeekOutlook arrives (or right away without one, e.g., in privacy
+// mode); err explains a missing forecast in the dialog.
+func (mw *MainWindow) ShowWeekOutlook(days []domain.SunTimes, forecast domain.WeatherForecast, err error) {
+	if mw.weekDialog == nil {
+		return
+	}
+	mw.weekDialog.SetOutlook(days, forecast, err, mw.config.Settings.TimeFormat24Hour)
+}
+
+// onLocationSearch handles search submissions from the LocationPanel widget.
+//
+// This is passed to LocationPanel as a callback during construction.
+// When the user presses Enter in the search box or clicks the Go button,
+// LocationPanel calls this handler with the query text.
+//
+// The handler delegates to the AppController for geocoding.
+func (mw *MainWindow) onLocationSearch(query string) {
+	mw.controller.SearchLocation(query)
+}
+
+// onSearchResultSelected handles a choice from the LocationPanel's search
+// results dropdown (see ShowSearchResults) or its "Recent" menu.
+//
+// The handler delegates to the AppController, which updates the whole
+// application to the chosen place.
+func (mw *MainWindow) onSearchResultSelected(loc domain.Location) {
+	mw.controller.UpdateLocation(loc)
+}
+
+// onDetectLocation handles the "Detect My Location" button from LocationPanel.
+//
+// This is passed to LocationPanel as a callback during construction.
+// When the user clicks the detect button, LocationPanel calls this handler.
+//
+// The handler delegates to the AppController for IP-based detection.
+func (mw *MainWindow) onDetectLocation() {
+	mw.controller.DetectLocation()
+}
+
+// onCancelDetect stops the running location detection (the Detect button
+// while detecting).
+func (mw *MainWindow) onCancelDetect() {
+	mw.controller.CancelDetection()
+	mw.setStatus("Location detection stopped")
+}
+
+// onCancelSearch stops the running search (the Go button while searching).
+func (mw *MainWindow) onCancelSearch() {
+	mw.controller.CancelSearch()
+	mw.setStatus("Search stopped")
+}
+
+// onDateChanged handles date changes from the DatePanel widget.
+//
+// This is passed to DatePanel as a callback during construction.
+// When the user changes the date (via buttons, calendar, or today button),
+// DatePanel calls this handler with the new date.
+//
+// The handler delegates to the AppController for recalculation.
+func (mw *MainWindow) onDateChanged(date time.Time) {
+	mw.controller.UpdateDate(date)
+}
+
+// onSettingsChanged handles settings changes from the SettingsPanel widget.
+//
+// This is passed to SettingsPanel as a callback during construction.
+// When the user changes any setting (elevation angles, checkboxes),
+// SettingsPanel calls this handler with the complete new settings, once
+// the user pauses (changes in quick succession are reported together).
+//
+// The handler:
+//  1. Updates local config with new settings
+//  2. Updates time panel format and teaching mode annotations
+//  3. Delegates to AppController for persistence and recalculation
+func (mw *MainWindow) onSettingsChanged(settings domain.Settings) {
+	// Update local config
+	mw.config.Settings = settings
+
+	// Update time format and teaching annotations immediately
+	// (before waiting for recalculation)
+	mw.timePanel.SetTimeFormat(settings.TimeFormat24Hour)
+	mw.timePanel.SetTeachingMode(settings)
+
+	// Delegate to controller for persistence and recalculation
+	mw.controller.UpdateSettings(settings)
+}
+
+// onCopyConfigCode handles the "Copy Code" button from the SettingsPanel widget.
+//
+// The config code for the current settings is placed on the system clipboard
+// so it can be pasted into a chat or document and shared with other users.
+//
+// miqt API notes:
+//   - QGuiApplication_Clipboard() returns the application-wide *QClipboard
+func (mw *MainWindow) onCopyConfigCode() {
+	code := mw.controller.ExportConfigCode()
+	qt.QGuiApplication_Clipboard().SetText(code)
+	mw.setStatus(fmt.Sprintf("Config code copied to clipboard: %s", code))
+}
+
+// onCopyLink handles Edit → Copy Link: a gogoldenhour:// link to the
+// location and date goes on the clipboard, for sharing the exact spot and
+// day with someone else.
+func (mw *MainWindow) onCopyLink() {
+	link := mw.controller.ShareLink()
+	qt.QGuiApplication_Clipboard().SetText(link)
+	mw.setStatus("Link copied to clipboard: " + link)
+}
+
+// onOpenLink handles File → Open Link.
+//
+// The user is prompted for a share link, pre-filled with the clipboard
+// contents when they look like one (as with config codes). The
+// AppController parses it and shows its place and date.
+func (mw *MainWindow) onOpenLink() {
+	text := strings.TrimSpace(qt.QGuiApplication_Clipboard().Text())
+	if !domain.IsLink(text) {
+		text = ""
+	}
+
+	ok := false
+	link := qt.QInputDialog_GetText4(mw.window.QWidget, "Open Link",
+		"Link (gogoldenhour://?lat=...&lon=...&date=...):", qt.QLineEdit__Normal, text, &ok)
+	if !ok || strings.TrimSpace(link) == "" {
+		return
+	}
+
+	if err := mw.controller.OpenLinkText(link); err != nil {
+		mw.ShowError(fmt.Sprintf("Could not open link: %v", err))
+		return
+	}
+	mw.setStatus("Link opened")
+}
+
+// onRegisterLinks handles File → Register Link Handler: clicking a
+// gogoldenhour:// link elsewhere then starts this copy of the app.
+func (mw *MainWindow) onRegisterLinks() {
+	if err := mw.controller.RegisterLinks(); err != nil {
+		mw.ShowError(err.Error())
+		return
+	}
+	mw.setStatus("gogoldenhour:// links now open in GoGoldenHour")
+}
+
+// onRestoreSettings handles "Restore Backup" and "Restore Defaults" in the
+// settings panel, shown after the settings file was found damaged.
+//
+// Restoring the defaults loses the favorites, presets and automation, so
+// the user confirms it first.
+func (mw *MainWindow) onRestoreSettings(fromBackup bool) {
+	if !fromBackup {
+		answer := qt.QMessageBox_Question5(mw.window.QWidget, "Restore Defaults",
+			"Start over with the default settings?\n\nFavorites, presets and automation are not kept.",
+			qt.QMessageBox__Yes|qt.QMessageBox__No)
+		if answer != qt.QMessageBox__Yes {
+			return
+		}
+	}
+
+	if err := mw.controller.RestoreSettings(fromBackup); err != nil {
+		mw.ShowError(fmt.Sprintf("Settings not restored: %v", err))
+		return
+	}
+	if fromBackup {
+		mw.setStatus("Settings restored from the backup")
+	} else {
+		mw.setStatus("Default settings restored")
+	}
+}
+
+// onPasteConfigCode handles the "Paste Code" button from the SettingsPanel widget.
+//
+// The user is prompted for a config code, pre-filled with the clipboard
+// contents when they look like a code. The code is then handed to the
+// AppController, which validates and applies it.
+//
+// miqt API notes:
+//   - QInputDialog_GetText4 is the overload with echo mode, default text,
+//     and an ok flag (suffix "4" = fourth overload)
+func (mw *MainWindow) onPasteConfigCode() {
+	// Pre-fill from the clipboard to save the user a paste step
+	text := strings.TrimSpace(qt.QGuiApplication_Clipboard().Text())
+	if !strings.HasPrefix(text, "GGH") {
+		text = ""
+	}
+
+	ok := false
+	code := qt.QInputDialog_GetText4(mw.window.QWidget, "Paste Config Code",
+		"Config code:", qt.QLineEdit__Normal, text, &ok)
+	if !ok || strings.TrimSpace(code) == "" {
+		return
+	}
+
+	if err := mw.controller.ImportConfigCode(code); err != nil {
+		mw.ShowError(fmt.Sprintf("Could not apply config code: %v", err))
+		return
+	}
+	mw.setStatus("Config code applied")
 }
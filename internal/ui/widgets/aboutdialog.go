@@ -0,0 +1,178 @@
+package widgets
+
+import (
+	"fmt"
+	"html"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+)
+
+// =============================================================================
+// About Dialog
+// =============================================================================
+
+// attribution credits a data provider or library the application uses.
+type attribution struct {
+	// Name is the provider or project, e.g., "OpenStreetMap".
+	Name string
+
+	// URL is its web page, linked from the name.
+	URL string
+
+	// Use says what the application uses it for.
+	Use string
+
+	// Notice is the credit or license the provider asks to be shown, e.g.,
+	// "© OpenStreetMap contributors, ODbL"; empty if none.
+	Notice string
+}
+
+// attributions are the data providers and libraries credited in the About
+// dialog. Map tiles, Nominatim, GeoNames, Open-Meteo and the timezone
+// boundaries are under licenses that require the attribution to be shown.
+var attributions = []attribution{
+	{"OpenStreetMap", "https://www.openstreetmap.org/copyright", "Map tiles and map data",
+		"© OpenStreetMap contributors, Open Database License (ODbL)"},
+	{"Nominatim", "https://nominatim.org/", "Place search and place names",
+		"Data © OpenStreetMap contributors, ODbL"},
+	{"ip-api.com", "https://ip-api.com/", "IP-based location detection", ""},
+	{"IPinfo", "https://ipinfo.io/", "IP-based location detection", "IP address data powered by IPinfo"},
+	{"GeoNames", "https://www.geonames.org/", "Offline city database", "CC BY 4.0"},
+	{"tzf", "https://github.com/ringsaturn/tzf", "Timezone lookup from coordinates (MIT License)",
+		"Timezone boundaries from timezone-boundary-builder, ODbL"},
+	{"Open-Meteo", "https://open-meteo.com/", "Elevation data and weather forecasts", "CC BY 4.0"},
+	{"RainViewer", "https://www.rainviewer.com/", "Cloud and precipitation imagery", ""},
+	{"NOAA SWPC", "https://www.swpc.noaa.gov/", "Kp index forecasts for the aurora outlook", ""},
+	{"NASA GIBS", "https://earthdata.nasa.gov/gibs", "Black Marble night lights imagery", ""},
+	{"Leaflet", "https://leafletjs.com/", "Interactive map (BSD 2-Clause License)", ""},
+	{"go-sampa", "https://github.com/hablullah/go-sampa", "Solar position algorithm (MIT License)", ""},
+	{"miqt", "https://github.com/mappu/miqt", "Qt 6 bindings for Go (MIT License)", ""},
+	{"tview", "https://github.com/rivo/tview", "Terminal user interface, --tui (MIT License)", ""},
+	{"Qt", "https://www.qt.io/", "User interface toolkit (LGPL v3)", ""},
+}
+
+// ShowAboutDialog displays the version, build, license and the credits of
+// the data providers and libraries (Help → About GoGoldenHour).
+//
+//	┌─ About GoGoldenHour ──────────────────────────────────┐
+//	│ GoGoldenHour 0.1.3                                    │
+//	│ Golden hour, blue hour and sun times for photographers│
+//	│ Built with go1.24.2, revision 3f2a9c1 · Qt 6.8.2      │
+//	│ ┌───────────────────────────────────────────────────┐ │
+//	│ │ OpenStreetMap — Map tiles and map data            │ │
+//	│ │   © OpenStreetMap contributors, ODbL              │ │
+//	│ │ Nominatim — Place search and place names          │ │
+//	│ └───────────────────────────────────────────────────┘ │
+//	│ Licensed under the AGPL v3 · Releases                 │
+//	│ [Open Settings Folder]                    [ Close ]   │
+//	└───────────────────────────────────────────────────────┘
+//
+// Several providers require their attribution to be visible in the
+// application, so all are listed rather than only linked.
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - version: The application version (config.AppConfig.AppVersion)
+//   - releasesURL: The page listing all releases (updates.ReleasesURL)
+//   - configDir: The folder of the profile's settings file, opened by
+//     "Open Settings Folder"; empty hides the button
+//
+// The dialog is modal and blocks until the user closes it.
+//
+// miqt API notes:
+//   - SetOpenExternalLinks(true): Links open in the browser (QLabel and
+//     QTextBrowser)
+//   - AddButton2(text, role): Custom button in a QDialogButtonBox;
+//     ActionRole buttons don't close the dialog
+//   - QUrl_FromLocalFile: file:// URL, opened in the file manager by
+//     QDesktopServices_OpenUrl
+func ShowAboutDialog(parent *qt.QWidget, version, releasesURL, configDir string) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("About GoGoldenHour")
+	dialog.Resize(500, 460)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+
+	header := qt.NewQLabel3(fmt.Sprintf("<h3>GoGoldenHour %s</h3>"+
+		"<p>Golden hour, blue hour and sun times for photographers.</p>", html.EscapeString(version)))
+	layout.AddWidget(header.QWidget)
+
+	build := qt.NewQLabel3(buildDescription())
+	build.SetStyleSheet("color: gray; font-size: 11px;")
+	build.SetTextInteractionFlags(qt.TextSelectableByMouse)
+	layout.AddWidget(build.QWidget)
+
+	credits := qt.NewQTextBrowser2()
+	credits.SetOpenExternalLinks(true)
+	credits.SetHtml(attributionsHTML(attributions))
+	layout.AddWidget(credits.QWidget)
+
+	license := qt.NewQLabel3(fmt.Sprintf("Licensed under the AGPL v3 · <a href=\"%s\">Releases</a>",
+		html.EscapeString(releasesURL)))
+	license.SetOpenExternalLinks(true)
+	layout.AddWidget(license.QWidget)
+
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	if configDir != "" {
+		folderButton := buttons.AddButton2("Open Settings Folder", qt.QDialogButtonBox__ActionRole)
+		folderButton.SetToolTip(configDir)
+		folderButton.OnClicked(func() {
+			qt.QDesktopServices_OpenUrl(qt.QUrl_FromLocalFile(configDir))
+		})
+	}
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+
+	dialog.Exec()
+}
+
+// buildDescription describes the build for bug reports, e.g., "Built with
+// go1.24.2, revision 3f2a9c1 (modified) on 2026-10-01 · Qt 6.8.2". The
+// revision is only known for binaries built from a git checkout.
+func buildDescription() string {
+	parts := []string{"Built with " + runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		var revision, date, modified string
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				revision = s.Value[:min(len(s.Value), 7)]
+			case "vcs.time":
+				date = s.Value[:min(len(s.Value), len("2006-01-02"))]
+			case "vcs.modified":
+				if s.Value == "true" {
+					modified = " (modified)"
+				}
+			}
+		}
+		if revision != "" {
+			vcs := "revision " + revision + modified
+			if date != "" {
+				vcs += " on " + date
+			}
+			parts = append(parts, vcs)
+		}
+	}
+	return strings.Join(parts, ", ") + " · Qt " + qt.QLibraryInfo_Version().ToString()
+}
+
+// attributionsHTML lists attributions with linked names and their notices.
+func attributionsHTML(list []attribution) string {
+	var b strings.Builder
+	b.WriteString("<p><b>Data and software</b></p><ul>")
+	for _, a := range list {
+		fmt.Fprintf(&b, "<li><a href=\"%s\">%s</a> — %s", html.EscapeString(a.URL),
+			html.EscapeString(a.Name), html.EscapeString(a.Use))
+		if a.Notice != "" {
+			b.WriteString("<br><small>" + html.EscapeString(a.Notice) + "</small>")
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+	return b.String()
+}
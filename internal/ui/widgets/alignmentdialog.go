@@ -0,0 +1,96 @@
+package widgets
+
+import (
+	"fmt"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Alignment Dialog
+// =============================================================================
+
+// ShowAlignmentDialog displays the results of a sun alignment search.
+//
+// The dialog shows a summary of the camera → subject line (distance and
+// bearing) above a read-only table with one row per alignment:
+//
+//	┌───────────────────────────────────────────────────────────┐
+//	│ Bearing 298.9° (WNW), distance 3.0 km                     │
+//	│ ┌──────────────┬───────┬─────────┬─────────┬───────────┐  │
+//	│ │ Date         │ Time  │ Sun     │ Azimuth │ Elevation │  │
+//	│ ├──────────────┼───────┼─────────┼─────────┼───────────┤  │
+//	│ │ Thu, May 28  │ 20:12 │ Setting │ 299.0°  │ 0.5°      │  │
+//	│ └──────────────┴───────┴─────────┴─────────┴───────────┘  │
+//	│                                               [ Close ]   │
+//	└───────────────────────────────────────────────────────────┘
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - camera: Where the photographer stands
+//   - subject: The landmark the sun should line up with
+//   - alignments: Search results in chronological order (may be empty)
+//   - use24Hour: Time display format
+//
+// The dialog is modal and blocks until the user closes it.
+//
+// miqt API notes:
+//   - NewQTableWidget3(rows, cols): Table with fixed size (suffix "3")
+//   - NewQTableWidgetItem2("text"): Item with text (suffix "2")
+//   - NewQDialogButtonBox4(buttons): Box with standard buttons (suffix "4")
+func ShowAlignmentDialog(parent *qt.QWidget, camera, subject domain.Location, alignments []domain.SunAlignment, use24Hour bool) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("Sun Alignments")
+	dialog.Resize(520, 420)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+
+	// Summary of the line being matched
+	bearing := camera.BearingTo(subject)
+	summary := qt.NewQLabel3(fmt.Sprintf("Bearing %.1f° (%s), distance %s",
+		bearing, domain.CompassPoint(bearing), domain.FormatDistance(camera.DistanceTo(subject))))
+	layout.AddWidget(summary.QWidget)
+
+	if len(alignments) == 0 {
+		// Bearings outside the sunrise/sunset sweep never align
+		empty := qt.NewQLabel3("The sun does not rise or set along this line during the next year.")
+		empty.SetWordWrap(true)
+		layout.AddWidget(empty.QWidget)
+	} else {
+		table := qt.NewQTableWidget3(len(alignments), 5)
+		table.SetHorizontalHeaderLabels([]string{"Date", "Time", "Sun", "Azimuth", "Elevation"})
+		table.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+		table.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+		table.VerticalHeader().SetVisible(false)
+		table.HorizontalHeader().SetStretchLastSection(true)
+
+		for row, a := range alignments {
+			direction := "Setting"
+			if a.Rising {
+				direction = "Rising"
+			}
+			cells := []string{
+				a.Time.Format("Mon, Jan 2 2006"),
+				domain.FormatTime(a.Time, use24Hour),
+				direction,
+				fmt.Sprintf("%.1f°", a.Azimuth),
+				fmt.Sprintf("%.1f°", a.Elevation),
+			}
+			for col, text := range cells {
+				table.SetItem(row, col, qt.NewQTableWidgetItem2(text))
+			}
+		}
+		table.ResizeColumnsToContents()
+		layout.AddWidget(table.QWidget)
+	}
+
+	// Close button
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+
+	dialog.Exec()
+}
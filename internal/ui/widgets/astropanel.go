@@ -0,0 +1,223 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// AstroPanel
+// =============================================================================
+
+// AstroPanel shows the night sky of the selected date: how long it is
+// dark, the meteor shower peaking that night, and the aurora outlook for
+// high-latitude photographers.
+//
+// # UI Layout
+//
+//	┌─ Astro ────────────────────────────────────────────┐
+//	│ Dark:    21:09 - 04:06 (6h 57m)                    │
+//	│ Meteors: ☄ Perseids peak: moonless dark 22:41 -    │
+//	│          03:52 (5h 11m), up to 100/h               │
+//	│ Aurora:  Possible low on the northern horizon      │
+//	│          Kp 4+ at 00:00 (predicted)                │
+//	└────────────────────────────────────────────────────┘
+//
+// "Dark" is the night from the evening of the date to the next morning,
+// with the sun below domain.AuroraDarkElevation (nautical twilight); at
+// high latitudes there may be none, or it may last all day.
+//
+// The meteor row is shown only on the peak night of a major shower (see
+// SetMeteors). It is green when the peak falls in at least
+// domain.MinMeteorDarkness of moonless astronomical darkness with the
+// radiant high enough; the tooltip gives the shower's rate and radiant.
+//
+// The aurora rows are shown while Settings.AuroraForecast is on (see
+// SetAuroraEnabled). The rating comes from the highest Kp index forecast
+// during the dark hours and the place's geomagnetic latitude (see
+// domain.AuroraChance): green when the aurora is likely overhead, amber
+// when it may show on the horizon. The tooltip gives the geomagnetic
+// latitude and the Kp the aurora needs there.
+//
+// # Data Flow
+//
+// The App computes the outlook after every recalculation and again when
+// the Kp forecast arrives (see SetOutlook); the panel only displays it.
+type AstroPanel struct {
+	// groupBox is the container widget with "Astro" title border.
+	groupBox *qt.QGroupBox
+
+	// darkLabel shows the dark hours of the night.
+	darkLabel *qt.QLabel
+
+	// meteorTitle and meteorLabel are the meteor shower row, hidden on
+	// nights without a peak.
+	meteorTitle *qt.QLabel
+	meteorLabel *qt.QLabel
+
+	// meteors are the last peak nights shown, redrawn when the time format
+	// changes.
+	meteors []domain.MeteorNight
+
+	// auroraTitle, auroraLabel and kpLabel are the aurora rows: the
+	// rating and the Kp index it's based on.
+	auroraTitle *qt.QLabel
+	auroraLabel *qt.QLabel
+	kpLabel     *qt.QLabel
+
+	// outlook is the last outlook shown, redrawn when the time format
+	// changes.
+	outlook domain.AuroraOutlook
+
+	// use24Hour is the time display format.
+	use24Hour bool
+}
+
+// NewAstroPanel creates the panel with the aurora rows hidden.
+//
+// Parameters:
+//   - use24Hour: Time display format
+func NewAstroPanel(use24Hour bool) *AstroPanel {
+	ap := &AstroPanel{use24Hour: use24Hour}
+	ap.setupUI()
+	return ap
+}
+
+// setupUI creates the grid of labels.
+//
+// Layout (QGridLayout):
+//
+//	Row 0: [Dark:   ] [darkLabel  ]
+//	Row 1: [Meteors:] [meteorLabel]
+//	Row 2: [Aurora: ] [auroraLabel]
+//	Row 3: [        ] [kpLabel    ]
+func (ap *AstroPanel) setupUI() {
+	ap.groupBox = qt.NewQGroupBox3("Astro")
+	layout := qt.NewQGridLayout(ap.groupBox.QWidget)
+	layout.SetContentsMargins(6, 6, 6, 6)
+	layout.SetColumnStretch(1, 1)
+
+	darkTitle := qt.NewQLabel3("Dark:")
+	darkTitle.SetToolTip("Sun below the end of nautical twilight (-12°), dark enough for the aurora and the Milky Way")
+	layout.AddWidget2(darkTitle.QWidget, 0, 0)
+	ap.darkLabel = qt.NewQLabel3("--")
+	layout.AddWidget2(ap.darkLabel.QWidget, 0, 1)
+
+	ap.meteorTitle = qt.NewQLabel3("Meteors:")
+	layout.AddWidget2(ap.meteorTitle.QWidget, 1, 0)
+	ap.meteorLabel = qt.NewQLabel2()
+	ap.meteorLabel.SetWordWrap(true)
+	layout.AddWidget2(ap.meteorLabel.QWidget, 1, 1)
+
+	ap.auroraTitle = qt.NewQLabel3("Aurora:")
+	layout.AddWidget2(ap.auroraTitle.QWidget, 2, 0)
+	ap.auroraLabel = qt.NewQLabel3("--")
+	ap.auroraLabel.SetWordWrap(true)
+	layout.AddWidget2(ap.auroraLabel.QWidget, 2, 1)
+	ap.kpLabel = qt.NewQLabel2()
+	ap.kpLabel.SetStyleSheet("color: palette(mid); font-size: 11px;")
+	layout.AddWidget2(ap.kpLabel.QWidget, 3, 1)
+
+	ap.SetMeteors(nil)
+	ap.SetAuroraEnabled(false)
+}
+
+// Widget returns the group box container for adding to parent layouts.
+func (ap *AstroPanel) Widget() *qt.QGroupBox {
+	return ap.groupBox
+}
+
+// SetAuroraEnabled shows or hides the aurora rows (Settings.AuroraForecast).
+func (ap *AstroPanel) SetAuroraEnabled(on bool) {
+	ap.auroraTitle.SetVisible(on)
+	ap.auroraLabel.SetVisible(on)
+	ap.kpLabel.SetVisible(on)
+}
+
+// SetTimeFormat changes the time display format and redraws the times.
+func (ap *AstroPanel) SetTimeFormat(use24Hour bool) {
+	ap.use24Hour = use24Hour
+	ap.SetOutlook(ap.outlook)
+	ap.SetMeteors(ap.meteors)
+}
+
+// SetMeteors shows the meteor showers peaking on the night.
+//
+// Parameters:
+//   - nights: The peak nights (see solar.MeteorNights); empty hides the
+//     row
+func (ap *AstroPanel) SetMeteors(nights []domain.MeteorNight) {
+	ap.meteors = nights
+	ap.meteorTitle.SetVisible(len(nights) > 0)
+	ap.meteorLabel.SetVisible(len(nights) > 0)
+
+	var lines, tips []string
+	good := false
+	for _, night := range nights {
+		lines = append(lines, "☄ "+night.Summary(ap.use24Hour))
+		tips = append(tips, fmt.Sprintf("%s: up to %d meteors per hour under a perfect sky; "+
+			"the radiant climbs to %.0f° here", night.Shower.Name, night.Shower.ZHR, night.RadiantAltitude))
+		good = good || night.Good()
+	}
+	ap.meteorLabel.SetText(strings.Join(lines, "\n"))
+	ap.meteorLabel.SetToolTip(strings.Join(tips, "\n"))
+	if good {
+		ap.meteorLabel.SetStyleSheet("color: #2e7d32; font-weight: bold;")
+	} else {
+		ap.meteorLabel.SetStyleSheet("")
+	}
+}
+
+// SetOutlook shows a night's darkness and aurora outlook.
+//
+// Parameters:
+//   - outlook: The night (see domain.NewAuroraOutlook); without a Kp
+//     forecast only the darkness is known
+func (ap *AstroPanel) SetOutlook(outlook domain.AuroraOutlook) {
+	ap.outlook = outlook
+	switch dark := outlook.Dark; {
+	case !dark.IsValid():
+		ap.darkLabel.SetText("Not tonight (bright night)")
+	case dark.Duration() >= 23*time.Hour:
+		ap.darkLabel.SetText("All day (polar night)")
+	default:
+		ap.darkLabel.SetText(fmt.Sprintf("%s - %s (%s)", domain.FormatTime(dark.Start, ap.use24Hour),
+			domain.FormatTime(dark.End, ap.use24Hour), dark.FormatDuration()))
+	}
+
+	ap.auroraLabel.SetText(outlook.Summary())
+	switch outlook.Visibility {
+	case domain.AuroraOverhead:
+		ap.auroraLabel.SetStyleSheet("color: #2e7d32; font-weight: bold;")
+	case domain.AuroraHorizon:
+		ap.auroraLabel.SetStyleSheet("color: #e65100; font-weight: bold;")
+	default:
+		ap.auroraLabel.SetStyleSheet("")
+	}
+
+	if outlook.Kp.Time.IsZero() {
+		ap.kpLabel.SetText("")
+	} else {
+		kind := "observed"
+		if outlook.Kp.Predicted {
+			kind = "predicted"
+		}
+		ap.kpLabel.SetText(fmt.Sprintf("Kp %s at %s (%s)", domain.FormatKp(outlook.Kp.Kp),
+			domain.FormatTime(outlook.Kp.Time.In(outlook.Dark.Start.Location()), ap.use24Hour), kind))
+	}
+
+	needs := func(v domain.AuroraVisibility) string {
+		kp := domain.AuroraKpNeeded(outlook.Location, v)
+		if kp > 9 {
+			return "not even at Kp 9"
+		}
+		return "from Kp " + domain.FormatKp(kp)
+	}
+	ap.auroraLabel.SetToolTip(fmt.Sprintf("Geomagnetic latitude %.1f°: the aurora shows on the horizon %s "+
+		"and overhead %s.\nKp forecast by NOAA SWPC",
+		domain.GeomagneticLatitude(outlook.Location), needs(domain.AuroraHorizon), needs(domain.AuroraOverhead)))
+}
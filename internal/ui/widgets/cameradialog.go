@@ -0,0 +1,235 @@
+package widgets
+
+import (
+	"fmt"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// CameraDialog
+// =============================================================================
+
+// CameraDialog adjusts a camera placed on the map and lists what it will see.
+//
+// The dialog opens when the user places a camera in the map's camera mode.
+// The heading comes from the second click and can be fine-tuned here, along
+// with the focal length and orientation. Every change redraws the field of
+// view cone on the map. The table lists the selected date's sunrise, sunset,
+// moonrise and moonset, and whether each falls inside the field of view
+// (or how far outside the edge of the frame it is):
+//
+//	┌─ Camera View ──────────────────────────────────────────┐
+//	│ Heading:      [ 298.5° ]   Focal length: [  50 mm ]    │
+//	│ [ ] Portrait orientation   Field of view: 39.6°        │
+//	│ ┌──────────┬───────┬─────────┬─────────────────────┐   │
+//	│ │ Event    │ Time  │ Azimuth │ In view             │   │
+//	│ ├──────────┼───────┼─────────┼─────────────────────┤   │
+//	│ │ Sunset   │ 21:58 │ 308.4°  │ Yes                 │   │
+//	│ │ Moonset  │ 01:37 │ 265.5°  │ No (13.2° left)     │   │
+//	│ └──────────┴───────┴─────────┴─────────────────────┘   │
+//	│                                            [ Close ]   │
+//	└────────────────────────────────────────────────────────┘
+//
+// Unlike the alignment results, the dialog is modeless: it stays open next
+// to the map so the cone can be compared with the terrain while adjusting.
+// Closing it removes the cone from the map.
+//
+// # miqt API Notes
+//
+//   - Show() instead of Exec() opens the dialog without blocking
+//   - OnFinished(func(result int)): Emitted when the dialog is closed
+type CameraDialog struct {
+	// dialog is the top-level modeless dialog.
+	dialog *qt.QDialog
+
+	// headingSpin sets the direction the camera faces (degrees from north).
+	headingSpin *qt.QDoubleSpinBox
+
+	// focalSpin sets the full-frame equivalent focal length in millimeters.
+	focalSpin *qt.QDoubleSpinBox
+
+	// portraitCheck switches between landscape and portrait orientation.
+	portraitCheck *qt.QCheckBox
+
+	// fovLabel shows the resulting horizontal field of view.
+	fovLabel *qt.QLabel
+
+	// eventTable lists the date's horizon events, one per row.
+	eventTable *qt.QTableWidget
+
+	// updating is true while SetCamera fills the controls, so the value
+	// changes it causes aren't reported as user edits.
+	updating bool
+
+	// onChange is invoked when the user edits the heading, focal length or
+	// orientation.
+	onChange func(heading, focalLength float64, portrait bool)
+
+	// onClose is invoked when the dialog is closed.
+	onClose func()
+}
+
+// Focal length limits of the camera dialog, in millimeters.
+const (
+	minFocalLength     = 8
+	maxFocalLength     = 1200
+	defaultFocalLength = 24
+)
+
+// NewCameraDialog creates the camera dialog (initially hidden).
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - onChange: Callback invoked when the user edits the camera settings
+//   - onClose: Callback invoked when the dialog is closed
+//
+// Call SetCamera and SetEvents to fill it, then Show.
+func NewCameraDialog(parent *qt.QWidget, onChange func(heading, focalLength float64, portrait bool), onClose func()) *CameraDialog {
+	cd := &CameraDialog{
+		onChange: onChange,
+		onClose:  onClose,
+	}
+	cd.setupUI(parent)
+	return cd
+}
+
+// setupUI creates the dialog with its controls, table and Close button.
+func (cd *CameraDialog) setupUI(parent *qt.QWidget) {
+	cd.dialog = qt.NewQDialog(parent)
+	cd.dialog.SetWindowTitle("Camera View")
+	cd.dialog.Resize(480, 320)
+
+	layout := qt.NewQVBoxLayout(cd.dialog.QWidget)
+
+	// Camera controls
+	grid := qt.NewQGridLayout2()
+
+	cd.headingSpin = qt.NewQDoubleSpinBox2()
+	cd.headingSpin.SetRange(0, 359.9)
+	cd.headingSpin.SetSingleStep(1)
+	cd.headingSpin.SetDecimals(1)
+	cd.headingSpin.SetSuffix("°")
+	cd.headingSpin.SetWrapping(true) // 359.9° steps up to 0°
+	cd.headingSpin.OnValueChanged(func(float64) { cd.notifyChange() })
+	grid.AddWidget2(qt.NewQLabel3("Heading:").QWidget, 0, 0)
+	grid.AddWidget2(cd.headingSpin.QWidget, 0, 1)
+
+	cd.focalSpin = qt.NewQDoubleSpinBox2()
+	cd.focalSpin.SetRange(minFocalLength, maxFocalLength)
+	cd.focalSpin.SetSingleStep(5)
+	cd.focalSpin.SetDecimals(0)
+	cd.focalSpin.SetSuffix(" mm")
+	cd.focalSpin.SetValue(defaultFocalLength)
+	cd.focalSpin.SetToolTip("Full-frame equivalent focal length")
+	cd.focalSpin.OnValueChanged(func(float64) { cd.notifyChange() })
+	grid.AddWidget2(qt.NewQLabel3("Focal length:").QWidget, 0, 2)
+	grid.AddWidget2(cd.focalSpin.QWidget, 0, 3)
+
+	cd.portraitCheck = qt.NewQCheckBox3("Portrait orientation")
+	cd.portraitCheck.OnToggled(func(bool) { cd.notifyChange() })
+	grid.AddWidget3(cd.portraitCheck.QWidget, 1, 0, 1, 2)
+
+	cd.fovLabel = qt.NewQLabel2()
+	grid.AddWidget3(cd.fovLabel.QWidget, 1, 2, 1, 2)
+	layout.AddLayout(grid.QLayout)
+
+	// Horizon events table
+	cd.eventTable = qt.NewQTableWidget3(0, 4)
+	cd.eventTable.SetHorizontalHeaderLabels([]string{"Event", "Time", "Azimuth", "In view"})
+	cd.eventTable.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+	cd.eventTable.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	cd.eventTable.VerticalHeader().SetVisible(false)
+	cd.eventTable.HorizontalHeader().SetStretchLastSection(true)
+	layout.AddWidget(cd.eventTable.QWidget)
+
+	// Close button
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		cd.dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+
+	cd.dialog.OnFinished(func(int) {
+		if cd.onClose != nil {
+			cd.onClose()
+		}
+	})
+}
+
+// notifyChange reports a user edit of the camera controls.
+func (cd *CameraDialog) notifyChange() {
+	if cd.updating || cd.onChange == nil {
+		return
+	}
+	cd.onChange(cd.headingSpin.Value(), cd.focalSpin.Value(), cd.portraitCheck.IsChecked())
+}
+
+// SetCamera fills the controls from a camera without reporting a change.
+//
+// A zero focal length (a newly placed camera) is replaced by the value in
+// the dialog, so the lens carries over between placements (starting at
+// defaultFocalLength).
+//
+// Returns the camera with the focal length actually used.
+func (cd *CameraDialog) SetCamera(camera domain.Camera) domain.Camera {
+	cd.updating = true
+	defer func() { cd.updating = false }()
+
+	if camera.FocalLength == 0 {
+		camera.FocalLength = cd.focalSpin.Value()
+	}
+
+	cd.headingSpin.SetValue(camera.Heading)
+	cd.focalSpin.SetValue(camera.FocalLength)
+	cd.portraitCheck.SetChecked(camera.Portrait)
+	return camera
+}
+
+// SetEvents shows the horizon events and whether the camera sees them.
+//
+// Parameters:
+//   - camera: The camera the events are checked against
+//   - events: Horizon events for the selected date (see solar.HorizonEvents)
+//   - use24Hour: Time display format
+func (cd *CameraDialog) SetEvents(camera domain.Camera, events []domain.HorizonEvent, use24Hour bool) {
+	cd.fovLabel.SetText(fmt.Sprintf("Field of view: %.1f°", camera.HorizontalFOV()))
+
+	cd.eventTable.SetRowCount(len(events))
+	for row, e := range events {
+		inView := "Yes"
+		if !camera.InView(e.Azimuth) {
+			// Positive offsets are clockwise of the heading (to the right)
+			offset := domain.AngleDifference(e.Azimuth, camera.Heading)
+			side := "right"
+			if offset < 0 {
+				side, offset = "left", -offset
+			}
+			inView = fmt.Sprintf("No (%.1f° %s)", offset-camera.HorizontalFOV()/2, side)
+		}
+
+		cells := []string{
+			e.Name,
+			domain.FormatTime(e.Time, use24Hour),
+			fmt.Sprintf("%.1f° (%s)", e.Azimuth, domain.CompassPoint(e.Azimuth)),
+			inView,
+		}
+		for col, text := range cells {
+			cd.eventTable.SetItem(row, col, qt.NewQTableWidgetItem2(text))
+		}
+	}
+	cd.eventTable.ResizeColumnsToContents()
+}
+
+// Show opens the dialog (or brings it to the front if already open).
+func (cd *CameraDialog) Show() {
+	cd.dialog.Show()
+	cd.dialog.Raise()
+	cd.dialog.ActivateWindow()
+}
+
+// IsVisible returns true while the dialog is open.
+func (cd *CameraDialog) IsVisible() bool {
+	return cd.dialog.IsVisible()
+}
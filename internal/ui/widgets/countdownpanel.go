@@ -0,0 +1,183 @@
+package widgets
+
+import (
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// CountdownPanel
+// =============================================================================
+
+// CountdownPanel counts down, live, to the next golden or blue hour at the
+// location, and shows the progress of one that is in progress.
+//
+// # UI Layout
+//
+//	┌─ Next Light ───────────────────────────────────────┐
+//	│ Evening golden hour starts at 20:15                │
+//	│ in 02:05:09                                        │
+//	└────────────────────────────────────────────────────┘
+//
+// During a period the countdown shows the time until it ends, with a
+// progress bar:
+//
+//	┌─ Next Light ───────────────────────────────────────┐
+//	│ Evening golden hour until 21:00                    │
+//	│ 00:29:51 left                                      │
+//	│ [█████████████░░░░░░░░░░░░░░░░░░░░░░░░░░░░] 34%    │
+//	└────────────────────────────────────────────────────┘
+//
+// The countdown is always for today at the location, whichever date is
+// selected. The App hands over the sun times of the days around today
+// (see SetDays); a one-second timer then updates the display from them
+// without any calculation.
+//
+// # Communication
+//
+// When the clock passes into the last of the given days (after midnight),
+// onStale asks the App for the days around the new today.
+type CountdownPanel struct {
+	// groupBox is the container widget with "Next Light" title border.
+	groupBox *qt.QGroupBox
+
+	// eventLabel names the period and when it starts or ends.
+	eventLabel *qt.QLabel
+
+	// countdownLabel shows the time left (HH:MM:SS).
+	countdownLabel *qt.QLabel
+
+	// progressBar shows how much of a period in progress has passed;
+	// hidden between periods.
+	progressBar *qt.QProgressBar
+
+	// timer ticks once a second while the panel has days to count.
+	timer *qt.QTimer
+
+	// days are the sun times of consecutive days around today.
+	days []domain.SunTimes
+
+	// use24Hour is the time display format.
+	use24Hour bool
+
+	// onStale is the callback invoked when the days no longer cover today
+	// and tomorrow.
+	onStale func()
+}
+
+// NewCountdownPanel creates an idle countdown; call SetDays to start it.
+//
+// Parameters:
+//   - use24Hour: Time display format
+//   - onStale: Callback invoked after midnight, when the panel needs the
+//     days around the new today (it should call SetDays)
+func NewCountdownPanel(use24Hour bool, onStale func()) *CountdownPanel {
+	cp := &CountdownPanel{use24Hour: use24Hour, onStale: onStale}
+	cp.setupUI()
+	return cp
+}
+
+// setupUI creates the labels, the progress bar and the timer.
+//
+// miqt API notes:
+//   - NewQProgressBar2(): Progress bar without parent (suffix "2")
+//   - NewQTimer2(parent): Timer owned by the group box (suffix "2")
+//   - "font-family: monospace" keeps the digits from jumping each second
+func (cp *CountdownPanel) setupUI() {
+	cp.groupBox = qt.NewQGroupBox3("Next Light")
+	layout := qt.NewQVBoxLayout(cp.groupBox.QWidget)
+	layout.SetContentsMargins(6, 6, 6, 6)
+
+	cp.eventLabel = qt.NewQLabel2()
+	layout.AddWidget(cp.eventLabel.QWidget)
+
+	cp.countdownLabel = qt.NewQLabel2()
+	cp.countdownLabel.SetStyleSheet("font-family: monospace; font-size: 18px; font-weight: bold;")
+	layout.AddWidget(cp.countdownLabel.QWidget)
+
+	cp.progressBar = qt.NewQProgressBar2()
+	cp.progressBar.SetRange(0, 100)
+	cp.progressBar.SetVisible(false)
+	layout.AddWidget(cp.progressBar.QWidget)
+
+	cp.timer = qt.NewQTimer2(cp.groupBox.QObject)
+	cp.timer.SetInterval(1000)
+	cp.timer.OnTimeout(cp.update)
+
+	cp.update()
+}
+
+// Widget returns the group box container for adding to parent layouts.
+func (cp *CountdownPanel) Widget() *qt.QGroupBox {
+	return cp.groupBox
+}
+
+// SetDays replaces the days to count down through and starts the timer.
+//
+// Parameters:
+//   - days: Sun times of consecutive days at the location, in order,
+//     covering today and tomorrow (e.g., yesterday to tomorrow); nil
+//     stops the countdown
+//   - use24Hour: Time display format
+func (cp *CountdownPanel) SetDays(days []domain.SunTimes, use24Hour bool) {
+	cp.days = days
+	cp.use24Hour = use24Hour
+	if len(days) > 0 {
+		cp.timer.Start2()
+	} else {
+		cp.timer.Stop()
+	}
+	cp.update()
+}
+
+// update shows the countdown at the current time.
+func (cp *CountdownPanel) update() {
+	if len(cp.days) == 0 {
+		cp.eventLabel.SetText("No sun times for the location")
+		cp.countdownLabel.SetText("--:--:--")
+		cp.progressBar.SetVisible(false)
+		return
+	}
+
+	// Count in the location's timezone
+	last := cp.days[len(cp.days)-1].Date
+	now := time.Now().In(last.Location())
+	if !now.Before(last) && cp.onStale != nil {
+		// Midnight passed: ask for the new today once (SetDays
+		// replaces the days, so this runs only when the day changes)
+		cp.timer.Stop()
+		cp.onStale()
+		return
+	}
+
+	period, ok := domain.NextLightPeriod(cp.days, now)
+	if !ok {
+		cp.eventLabel.SetText("No golden or blue hour until tomorrow night")
+		cp.countdownLabel.SetText("--:--:--")
+		cp.progressBar.SetVisible(false)
+		return
+	}
+
+	if period.InProgress(now) {
+		cp.eventLabel.SetText(period.Name + " until " + domain.FormatTime(period.Period.End, cp.use24Hour))
+		cp.countdownLabel.SetText(domain.FormatCountdown(period.Period.End.Sub(now)) + " left")
+		cp.progressBar.SetValue(int(period.Progress(now) * 100))
+		cp.progressBar.SetVisible(true)
+		return
+	}
+	cp.eventLabel.SetText(period.Name + " starts at " + startLabel(period.Period.Start, now, cp.use24Hour))
+	cp.countdownLabel.SetText("in " + domain.FormatCountdown(period.Period.Start.Sub(now)))
+	cp.progressBar.SetVisible(false)
+}
+
+// startLabel formats the start of the next period, adding "tomorrow" when
+// it isn't today.
+func startLabel(start, now time.Time, use24Hour bool) string {
+	text := domain.FormatTime(start, use24Hour)
+	if start.YearDay() != now.YearDay() || start.Year() != now.Year() {
+		text += " tomorrow"
+	}
+	return text
+}
@@ -0,0 +1,94 @@
+package widgets
+
+import (
+	"fmt"
+	"html"
+	"path/filepath"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+)
+
+// =============================================================================
+// Crash Dialog
+// =============================================================================
+
+// ShowCrashDialog tells the user about a crash and offers to open its
+// report or to file it as a GitHub issue.
+//
+//	┌─ GoGoldenHour ran into a problem ─────────────────────────┐
+//	│ Something went wrong: index out of range [3] with length 2│
+//	│ GoGoldenHour kept running, but the last action may not    │
+//	│ have finished. A report was saved to crash-…-153000.txt.  │
+//	│ ┌────────────────────────────────────────────────────────┐│
+//	│ │ GoGoldenHour 0.1.3 (linux/amd64, go1.24.2)             ││
+//	│ │ panic: runtime error: index out of range …             ││
+//	│ └────────────────────────────────────────────────────────┘│
+//	│ [Open Report] [Report Issue…]                 [ Close ]   │
+//	└───────────────────────────────────────────────────────────┘
+//
+// Report Issue opens a new issue on GitHub in the browser, filled in with
+// the report (see crash.Report.IssueURL); nothing is sent until the user
+// submits it there.
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - report: The crash
+//   - path: The report's file, opened by "Open Report"; empty if it
+//     couldn't be saved (the button is hidden)
+//   - fatal: The crash ended the last run, rather than being recovered
+//
+// The dialog is modal and blocks until the user closes it.
+//
+// miqt API notes:
+//   - AddButton2(text, role): Custom button in a QDialogButtonBox;
+//     ActionRole buttons don't close the dialog
+//   - QUrl_FromLocalFile: file:// URL, opened in the text editor by
+//     QDesktopServices_OpenUrl
+//   - QUrl_FromEncoded: The issue URL is percent-encoded already
+func ShowCrashDialog(parent *qt.QWidget, report crash.Report, path string, fatal bool) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("GoGoldenHour ran into a problem")
+	dialog.Resize(560, 380)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+
+	what := "GoGoldenHour kept running, but the last action may not have finished."
+	if fatal {
+		what = "GoGoldenHour closed unexpectedly the last time it ran."
+	}
+	saved := "The report couldn't be saved; it is shown below."
+	if path != "" {
+		saved = fmt.Sprintf("A report was saved to %s.", html.EscapeString(filepath.Base(path)))
+	}
+	message := qt.NewQLabel3(fmt.Sprintf("<p><b>Something went wrong:</b> %s</p><p>%s %s</p>"+
+		"<p>Reporting the problem helps to fix it.</p>",
+		html.EscapeString(report.Panic), what, saved))
+	message.SetWordWrap(true)
+	layout.AddWidget(message.QWidget)
+
+	details := qt.NewQPlainTextEdit2()
+	details.SetReadOnly(true)
+	details.SetPlainText(report.Text())
+	layout.AddWidget(details.QWidget)
+
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	if path != "" {
+		openButton := buttons.AddButton2("Open Report", qt.QDialogButtonBox__ActionRole)
+		openButton.SetToolTip(path)
+		openButton.OnClicked(func() {
+			qt.QDesktopServices_OpenUrl(qt.QUrl_FromLocalFile(path))
+		})
+	}
+	issueButton := buttons.AddButton2("Report Issue…", qt.QDialogButtonBox__ActionRole)
+	issueButton.SetToolTip("Open a new GitHub issue with the report in the browser")
+	issueButton.OnClicked(func() {
+		qt.QDesktopServices_OpenUrl(qt.QUrl_FromEncoded([]byte(report.IssueURL())))
+	})
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+
+	dialog.Exec()
+}
@@ -1,9 +1,12 @@
 package widgets
 
 import (
+	"fmt"
 	"time"
 
 	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/dates"
+	"github.com/megatih/GoGoldenHour/internal/domain"
 )
 
 // =============================================================================
@@ -18,17 +21,31 @@ import (
 //   - Calendar popup for selecting any date
 //   - Today button to quickly return to current date
 //
+// Below the picker, the weekday and the distance from today ("in 3 days")
+// are shown, and a field takes dates typed in words, such as "next
+// saturday" or "in 2 weeks" (see the dates package).
+//
+// For planning several days, e.g., a trip, "Until" selects the last day of
+// a date range starting at the date; the App then lists each day's times
+// in the days panel.
+//
 // # UI Layout
 //
 //	┌─ Date ─────────────────────────────────────────────┐
 //	│ [<] [    January 2, 2026    ▼] [>] [  Today  ]     │
+//	│ Friday · in 3 days         [Go to: next saturday ] │
+//	│ [✓] Until [    January 9, 2026    ▼]               │
 //	└────────────────────────────────────────────────────┘
 //	 ▲        ▲                    ▲        ▲
 //	 │        │                    │        └── Reset to today
 //	 │        │                    └── Next day
-//	 │        └── Date with calendar popup
+//	 │        └── Date with calendar popup (first day of a range)
 //	 └── Previous day
 //
+// The range end can't be set before the date or more than
+// domain.MaxDateRangeDays days after it; moving the date past the end
+// moves the end along.
+//
 // # Date Handling
 //
 // The panel converts between Go's time.Time and Qt's QDate:
@@ -40,7 +57,8 @@ import (
 //
 // Date changes are communicated via the onDateChange callback. This callback
 // is invoked whenever the date changes (button click, calendar selection, etc.).
-// The App uses this to recalculate sun times for the new date.
+// The App uses this to recalculate sun times for the new date. Changes of
+// the range end (or turning the range on or off) go to onRangeChange.
 type DatePanel struct {
 	// groupBox is the container widget with "Date" title border.
 	groupBox *qt.QGroupBox
@@ -58,21 +76,42 @@ type DatePanel struct {
 	// todayBtn resets the date to today's date.
 	todayBtn *qt.QPushButton
 
+	// relativeLabel shows the date's weekday and distance from today.
+	relativeLabel *qt.QLabel
+
+	// goToEdit takes a date typed in words; Enter selects it.
+	goToEdit *qt.QLineEdit
+
+	// rangeCheck turns the date range on ("Until").
+	rangeCheck *qt.QCheckBox
+
+	// rangeEndEdit is the last day of the date range, enabled while
+	// rangeCheck is checked.
+	rangeEndEdit *qt.QDateEdit
+
 	// onDateChange is the callback invoked when the date changes.
 	// Receives the new date as time.Time.
 	onDateChange func(date time.Time)
+
+	// onRangeChange is the callback invoked when the range end changes.
+	// Receives the last day, or the zero time when the range is off.
+	onRangeChange func(end time.Time)
 }
 
-// NewDatePanel creates a new date panel with the given callback.
+// NewDatePanel creates a new date panel with the given callbacks.
 //
 // Parameters:
 //   - onDateChange: Callback invoked when the selected date changes.
 //     The App uses this to recalculate sun times for the new date.
+//   - onRangeChange: Callback invoked with the last day of the date range
+//     when it changes, or the zero time when the range is turned off
 //
-// Returns a fully initialized DatePanel with today's date selected.
-func NewDatePanel(onDateChange func(date time.Time)) *DatePanel {
+// Returns a fully initialized DatePanel with today's date selected and no
+// date range.
+func NewDatePanel(onDateChange func(date time.Time), onRangeChange func(end time.Time)) *DatePanel {
 	dp := &DatePanel{
-		onDateChange: onDateChange,
+		onDateChange:  onDateChange,
+		onRangeChange: onRangeChange,
 	}
 
 	dp.setupUI()
@@ -81,7 +120,9 @@ func NewDatePanel(onDateChange func(date time.Time)) *DatePanel {
 
 // setupUI creates and arranges all widgets in the date panel.
 //
-// The layout is horizontal: [<] [Date Picker] [>] [Today]
+// The layout has three rows: [<] [Date Picker] [>] [Today] for the date,
+// the relative date with the "Go to" field, and [ ] Until [Date Picker]
+// for the end of a date range.
 //
 // # miqt API Notes
 //
@@ -89,6 +130,8 @@ func NewDatePanel(onDateChange func(date time.Time)) *DatePanel {
 //   - QDate_CurrentDate() returns *QDate (pointer)
 //   - SetDate() requires dereferenced value: SetDate(*qdate)
 //   - AddDays() also returns *QDate, must dereference
+//   - SetDateRange(min, max): Limits a date edit; a date outside is
+//     moved to the nearest limit, which emits OnDateChanged
 //
 // Constructor patterns:
 //   - NewQGroupBox3("Date"): Creates group box with title (suffix "3")
@@ -97,8 +140,10 @@ func NewDatePanel(onDateChange func(date time.Time)) *DatePanel {
 func (dp *DatePanel) setupUI() {
 	// Create group box container with horizontal layout
 	dp.groupBox = qt.NewQGroupBox3("Date")
-	layout := qt.NewQHBoxLayout(dp.groupBox.QWidget)
+	outer := qt.NewQVBoxLayout(dp.groupBox.QWidget)
+	layout := qt.NewQHBoxLayout2()
 	layout.SetSpacing(6)
+	outer.AddLayout(layout.QLayout)
 
 	// =========================================================================
 	// Previous Day Button
@@ -124,8 +169,11 @@ func (dp *DatePanel) setupUI() {
 	currentDate := qt.QDate_CurrentDate()
 	dp.dateEdit.SetDate(*currentDate)
 
-	// Connect date change signal to our callback handler
+	// Connect date change signal to our callback handler. The range end
+	// follows first, so a range never ends before it starts.
 	dp.dateEdit.OnDateChanged(func(date qt.QDate) {
+		dp.limitRangeEnd()
+		dp.updateRelativeLabel()
 		dp.notifyDateChange()
 	})
 	layout.AddWidget(dp.dateEdit.QWidget)
@@ -152,6 +200,96 @@ func (dp *DatePanel) setupUI() {
 		dp.dateEdit.SetDate(*currentDate)
 	})
 	layout.AddWidget(dp.todayBtn.QWidget)
+
+	// =========================================================================
+	// Relative Date and "Go to" Field
+	// =========================================================================
+	relativeLayout := qt.NewQHBoxLayout2()
+	relativeLayout.SetSpacing(6)
+	dp.relativeLabel = qt.NewQLabel2()
+	dp.relativeLabel.SetStyleSheet("color: gray;")
+	relativeLayout.AddWidget(dp.relativeLabel.QWidget)
+	relativeLayout.AddStretch()
+	dp.goToEdit = qt.NewQLineEdit2()
+	dp.goToEdit.SetPlaceholderText("Go to: next saturday")
+	dp.goToEdit.SetToolTip(goToHelp)
+	dp.goToEdit.SetClearButtonEnabled(true)
+	dp.goToEdit.OnReturnPressed(dp.goToTypedDate)
+	dp.goToEdit.OnTextChanged(func(string) {
+		// Editing clears an earlier error
+		dp.goToEdit.SetStyleSheet("")
+		dp.goToEdit.SetToolTip(goToHelp)
+	})
+	relativeLayout.AddWidget(dp.goToEdit.QWidget)
+	outer.AddLayout(relativeLayout.QLayout)
+	dp.updateRelativeLabel()
+
+	// =========================================================================
+	// Date Range End ("Until")
+	// =========================================================================
+	rangeLayout := qt.NewQHBoxLayout2()
+	rangeLayout.SetSpacing(6)
+	dp.rangeCheck = qt.NewQCheckBox3("Until")
+	dp.rangeCheck.SetToolTip("Plan several days: list each day's golden and blue hours up to this date")
+	dp.rangeCheck.OnToggled(func(checked bool) {
+		dp.rangeEndEdit.SetEnabled(checked)
+		dp.notifyRangeChange()
+	})
+	rangeLayout.AddWidget(dp.rangeCheck.QWidget)
+
+	dp.rangeEndEdit = qt.NewQDateEdit2()
+	dp.rangeEndEdit.SetCalendarPopup(true)
+	dp.rangeEndEdit.SetDisplayFormat("MMMM d, yyyy")
+	dp.rangeEndEdit.SetEnabled(false)
+	dp.limitRangeEnd()
+	dp.rangeEndEdit.SetDate(*currentDate.AddDays(6)) // A week by default
+	dp.rangeEndEdit.OnDateChanged(func(date qt.QDate) {
+		if dp.rangeCheck.IsChecked() {
+			dp.notifyRangeChange()
+		}
+	})
+	rangeLayout.AddWidget(dp.rangeEndEdit.QWidget)
+	rangeLayout.AddStretch()
+	outer.AddLayout(rangeLayout.QLayout)
+}
+
+// goToHelp is the tooltip of the "Go to" field.
+const goToHelp = "Type a date and press Enter: today, tomorrow, saturday, next friday,\n" +
+	"in 3 days, 2 weeks ago, next month, +5, jul 4, 2026-07-04"
+
+// updateRelativeLabel shows the date's weekday and how far it is from
+// today, e.g., "Friday · in 3 days".
+func (dp *DatePanel) updateRelativeLabel() {
+	// Called while the controls are still being created
+	if dp.relativeLabel == nil {
+		return
+	}
+	date := dp.GetDate()
+	dp.relativeLabel.SetText(date.Weekday().String() + " · " + dates.Describe(date, time.Now()))
+}
+
+// goToTypedDate selects the date typed into the "Go to" field. Text that
+// isn't a date marks the field red and stays, so it can be corrected.
+func (dp *DatePanel) goToTypedDate() {
+	date, err := dates.Parse(dp.goToEdit.Text(), time.Now())
+	if err != nil {
+		dp.goToEdit.SetStyleSheet("border: 1px solid #e53935;")
+		dp.goToEdit.SetToolTip(fmt.Sprintf("Not a date: %v\n\n%s", err, goToHelp))
+		return
+	}
+	dp.goToEdit.Clear()
+	dp.SetDate(date)
+}
+
+// limitRangeEnd keeps the range end between the date and
+// domain.MaxDateRangeDays days after it.
+func (dp *DatePanel) limitRangeEnd() {
+	// Called while the controls are still being created
+	if dp.rangeEndEdit == nil {
+		return
+	}
+	start := dp.dateEdit.Date()
+	dp.rangeEndEdit.SetDateRange(*start, *start.AddDays(domain.MaxDateRangeDays-1))
 }
 
 // Widget returns the group box container for adding to parent layouts.
@@ -209,6 +347,28 @@ func (dp *DatePanel) changeDate(days int) {
 	dp.dateEdit.SetDate(*newDate)
 }
 
+// ShiftDays moves the date by the given number of days (negative: back),
+// like the previous/next buttons. Used by the keyboard shortcuts.
+func (dp *DatePanel) ShiftDays(days int) {
+	dp.changeDate(days)
+}
+
+// ShiftMonths moves the date by the given number of months (negative:
+// back). The day is kept where the month has it, else the month's last
+// day is taken (January 31 + 1 month = February 28).
+//
+// # miqt Note
+//
+// AddMonths() returns *QDate (pointer), must dereference when setting.
+func (dp *DatePanel) ShiftMonths(months int) {
+	dp.dateEdit.SetDate(*dp.dateEdit.Date().AddMonths(months))
+}
+
+// GoToToday selects today's date, like the Today button.
+func (dp *DatePanel) GoToToday() {
+	dp.dateEdit.SetDate(*qt.QDate_CurrentDate())
+}
+
 // notifyDateChange invokes the date change callback if set.
 //
 // This is called whenever the date changes, whether from:
@@ -223,3 +383,21 @@ func (dp *DatePanel) notifyDateChange() {
 		dp.onDateChange(dp.GetDate())
 	}
 }
+
+// RangeEnd returns the last day of the date range at midnight local time,
+// or the zero time if the range is off.
+func (dp *DatePanel) RangeEnd() time.Time {
+	if !dp.rangeCheck.IsChecked() {
+		return time.Time{}
+	}
+	qdate := dp.rangeEndEdit.Date()
+	return time.Date(qdate.Year(), time.Month(qdate.Month()), qdate.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// notifyRangeChange invokes the range change callback if set, with the
+// range end (zero if the range is off).
+func (dp *DatePanel) notifyRangeChange() {
+	if dp.onRangeChange != nil {
+		dp.onRangeChange(dp.RangeEnd())
+	}
+}
@@ -0,0 +1,120 @@
+package widgets
+
+import (
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/export"
+)
+
+// =============================================================================
+// Day Export Dialog
+// =============================================================================
+
+// DayExportTarget says where the day table chosen in ShowDayExportDialog
+// goes.
+type DayExportTarget int
+
+const (
+	// DayExportCancel means the dialog was closed without exporting.
+	DayExportCancel DayExportTarget = iota
+
+	// DayExportFile saves the table as a CSV or JSON file.
+	DayExportFile
+
+	// DayExportClipboard copies the table, tab-separated, to the clipboard.
+	DayExportClipboard
+)
+
+// ShowDayExportDialog asks which columns and time format to export the days
+// of the date range with, and where to.
+//
+//	┌─ Export Date Range ──────────────────────────────────────┐
+//	│ Columns (after the date):                                │
+//	│ [✓] Sunrise                    [✓] Sunset                │
+//	│ [ ] Solar noon                 [✓] Morning golden hour…  │
+//	│ ...                                                      │
+//	│ [✓] 24-hour times                                        │
+//	│       [ Copy to Clipboard ] [ Save File... ] [ Cancel ]  │
+//	└──────────────────────────────────────────────────────────┘
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - opts: The options to start from (e.g., the last export's)
+//
+// Returns the chosen options and target; the options are unchanged if the
+// dialog is cancelled. The dialog is modal.
+//
+// miqt API notes:
+//   - AddButton2("text", role) adds a custom button to a QDialogButtonBox
+//   - Done(code) closes the dialog with Exec returning code
+func ShowDayExportDialog(parent *qt.QWidget, opts export.DaysOptions) (export.DaysOptions, DayExportTarget) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("Export Date Range")
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+	layout.AddWidget(qt.NewQLabel3("Columns (after the date):").QWidget)
+
+	// One checkbox per column, two to a row
+	selected := make(map[export.DayColumn]bool)
+	for _, c := range opts.Columns {
+		selected[c] = true
+	}
+	if len(opts.Columns) == 0 {
+		for _, c := range export.DefaultDayColumns() {
+			selected[c] = true
+		}
+	}
+	grid := qt.NewQGridLayout2()
+	columns := export.AllDayColumns()
+	checks := make([]*qt.QCheckBox, len(columns))
+	for i, c := range columns {
+		checks[i] = qt.NewQCheckBox3(c.Label())
+		checks[i].SetChecked(selected[c])
+		grid.AddWidget2(checks[i].QWidget, i/2, i%2)
+	}
+	layout.AddLayout(grid.QLayout)
+
+	use24Hour := qt.NewQCheckBox3("24-hour times")
+	use24Hour.SetToolTip("Spreadsheets read 24-hour times as times of day most reliably")
+	use24Hour.SetChecked(opts.Use24Hour)
+	layout.AddWidget(use24Hour.QWidget)
+
+	// Buttons: the dialog's result code is the target
+	buttons := qt.NewQDialogButtonBox2()
+	copyBtn := buttons.AddButton2("Copy to Clipboard", qt.QDialogButtonBox__ActionRole)
+	copyBtn.SetToolTip("Copy as tab-separated text, which pastes into a spreadsheet as a table")
+	copyBtn.OnClicked(func() { dialog.Done(int(DayExportClipboard)) })
+	saveBtn := buttons.AddButton2("Save File...", qt.QDialogButtonBox__AcceptRole)
+	saveBtn.SetToolTip("Save as CSV or JSON (by file extension)")
+	saveBtn.SetDefault(true)
+	buttons.AddButtonWithButton(qt.QDialogButtonBox__Cancel)
+	buttons.OnAccepted(func() { dialog.Done(int(DayExportFile)) })
+	buttons.OnRejected(func() { dialog.Reject() })
+	layout.AddWidget(buttons.QWidget)
+
+	// Without any column there is nothing to export but the dates
+	updateButtons := func() {
+		checked := false
+		for _, check := range checks {
+			checked = checked || check.IsChecked()
+		}
+		copyBtn.SetEnabled(checked)
+		saveBtn.SetEnabled(checked)
+	}
+	for _, check := range checks {
+		check.OnToggled(func(bool) { updateButtons() })
+	}
+	updateButtons()
+
+	target := DayExportTarget(dialog.Exec())
+	if target != DayExportFile && target != DayExportClipboard {
+		return opts, DayExportCancel
+	}
+
+	chosen := export.DaysOptions{Use24Hour: use24Hour.IsChecked()}
+	for i, c := range columns {
+		if checks[i].IsChecked() {
+			chosen.Columns = append(chosen.Columns, c)
+		}
+	}
+	return chosen, target
+}
@@ -0,0 +1,113 @@
+package widgets
+
+import (
+	"fmt"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// DaysPanel
+// =============================================================================
+
+// DaysPanel lists the golden and blue hours of each day of a planned date
+// range, for comparing the days of a trip.
+//
+// # UI Layout
+//
+//	┌─ Days ─────────────────────────────────────────────────────────┐
+//	│ Date         Golden AM      Golden PM      Blue PM        Light │
+//	│ Mon, Jun 22  05:47 - 06:30  21:00 - 21:58  21:58 - 22:30  2h 38m│
+//	│ Tue, Jun 23  05:47 - 06:30  21:00 - 21:58  21:58 - 22:31  2h 39m│
+//	└────────────────────────────────────────────────────────────────┘
+//
+// "Light" is the day's total golden and blue hour (the shooting window).
+//
+// The panel is hidden unless a date range is selected in the date panel.
+// The times are calculated by the App (see SetDays); the panel only
+// displays them.
+type DaysPanel struct {
+	// groupBox is the container widget with "Days" title border.
+	groupBox *qt.QGroupBox
+
+	// table has one row per day of the range.
+	table *qt.QTableWidget
+}
+
+// daysMaxVisibleRows is how many days the table shows before it scrolls,
+// so a long trip doesn't push the other panels away.
+const daysMaxVisibleRows = 7
+
+// NewDaysPanel creates an empty, hidden days panel; call SetDays to fill it.
+func NewDaysPanel() *DaysPanel {
+	dp := &DaysPanel{}
+	dp.setupUI()
+	return dp
+}
+
+// setupUI creates the group box and its table.
+//
+// miqt API notes:
+//   - NewQTableWidget3(rows, cols): Table with fixed size (suffix "3")
+//   - SetRowCount(n): Grows or shrinks the table, dropping extra rows
+func (dp *DaysPanel) setupUI() {
+	dp.groupBox = qt.NewQGroupBox3("Days")
+	layout := qt.NewQVBoxLayout(dp.groupBox.QWidget)
+	layout.SetContentsMargins(6, 6, 6, 6)
+
+	dp.table = qt.NewQTableWidget3(0, 5)
+	dp.table.SetHorizontalHeaderLabels([]string{"Date", "Golden AM", "Golden PM", "Blue PM", "Light"})
+	dp.table.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+	dp.table.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	dp.table.VerticalHeader().SetVisible(false)
+	dp.table.HorizontalHeader().SetStretchLastSection(true)
+	dp.table.SetToolTip("Golden and blue hours of each day in the range. Export with File → Export Date Range.")
+	layout.AddWidget(dp.table.QWidget)
+
+	dp.groupBox.SetVisible(false)
+}
+
+// Widget returns the group box container for adding to parent layouts.
+func (dp *DaysPanel) Widget() *qt.QGroupBox {
+	return dp.groupBox
+}
+
+// SetDays replaces the listed days.
+//
+// Parameters:
+//   - days: Sun times of each day, in order; nil or empty hides the panel
+//   - use24Hour: Time display format
+func (dp *DaysPanel) SetDays(days []domain.SunTimes, use24Hour bool) {
+	dp.table.SetRowCount(len(days))
+	period := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return "N/A"
+		}
+		return fmt.Sprintf("%s - %s", domain.FormatTime(tr.Start, use24Hour), domain.FormatTime(tr.End, use24Hour))
+	}
+	for row, day := range days {
+		light := "none"
+		if window := day.ShootingWindow(); window > 0 {
+			light = domain.FormatDuration(window)
+		}
+		cells := []string{
+			day.Date.Format("Mon, Jan 2"),
+			period(day.GoldenMorning),
+			period(day.GoldenEvening),
+			period(day.BlueEvening),
+			light,
+		}
+		for col, text := range cells {
+			dp.table.SetItem(row, col, qt.NewQTableWidgetItem2(text))
+		}
+	}
+	dp.table.ResizeColumnsToContents()
+	dp.groupBox.SetVisible(len(days) > 0)
+
+	// Fit the rows without scrolling, up to daysMaxVisibleRows
+	if rows := min(len(days), daysMaxVisibleRows); rows > 0 {
+		frame := 2 * dp.table.FrameWidth()
+		dp.table.SetFixedHeight(dp.table.HorizontalHeader().Height() + rows*dp.table.RowHeight(0) + frame)
+	}
+}
@@ -0,0 +1,163 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Favorite Settings Dialog
+// =============================================================================
+
+// ShowFavoriteSettingsDialog edits a favorite's own settings (see
+// domain.FavoriteOverrides), used instead of the general ones while the
+// favorite is the current location.
+//
+// Each override is turned on with its checkbox; unchecked rows show the
+// value used without it:
+//
+//	┌─ Settings of Valley ─────────────────────────────────────┐
+//	│ [✓] Golden hour angle:  [ 11.0° ]                        │
+//	│ [ ] Timezone:           [Europe/Zurich                 ▼]│
+//	│ [✓] Elevation:          [ 1800 m ]                       │
+//	│ Applied whenever this favorite is selected, e.g., for a  │
+//	│ spot where a ridge hides the low sun.                    │
+//	│                                      [ OK ] [ Cancel ]   │
+//	└──────────────────────────────────────────────────────────┘
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - favorite: The favorite to edit
+//   - goldenHourElevation: The general golden hour angle
+//     (domain.Settings.GoldenHourElevation), shown while unchecked
+//   - timezones: The timezones offered (see timezone.Names); a typed name
+//     must be one of the valid timezones
+//
+// Returns the new overrides, and ok=false if the dialog was canceled. The
+// dialog is modal.
+//
+// miqt API notes:
+//   - OnToggled(func(bool)) is inherited by QCheckBox from QAbstractButton
+//   - QComboBox.SetEditable(true): The combo box accepts typed text
+func ShowFavoriteSettingsDialog(parent *qt.QWidget, favorite domain.Favorite, goldenHourElevation float64,
+	timezones []string) (overrides domain.FavoriteOverrides, ok bool) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("Settings of " + favorite.Location.Name)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+	form := qt.NewQFormLayout2()
+	current := favorite.Overrides
+
+	goldenCheck := qt.NewQCheckBox3("Golden hour angle:")
+	goldenSpin := qt.NewQDoubleSpinBox2()
+	goldenSpin.SetRange(0, 15)
+	goldenSpin.SetSingleStep(0.5)
+	goldenSpin.SetDecimals(1)
+	goldenSpin.SetSuffix("°")
+	goldenSpin.SetToolTip("Sun elevation where golden hour starts or ends at this spot, " +
+		"e.g., higher where hills hide the low sun")
+	goldenSpin.SetValue(goldenHourElevation)
+	if current.GoldenHourElevation != nil {
+		goldenSpin.SetValue(*current.GoldenHourElevation)
+	}
+	form.AddRow(goldenCheck.QWidget, goldenSpin.QWidget)
+
+	timezoneCheck := qt.NewQCheckBox3("Timezone:")
+	timezoneCombo := qt.NewQComboBox2()
+	timezoneCombo.SetEditable(true)
+	timezoneCombo.AddItems(timezones)
+	timezoneCombo.SetToolTip("Times at this spot are shown in this timezone instead of the one " +
+		"looked up from the coordinates")
+	timezoneCombo.SetCurrentText(favorite.Location.Timezone)
+	if current.Timezone != "" {
+		timezoneCombo.SetCurrentText(current.Timezone)
+	}
+	form.AddRow(timezoneCheck.QWidget, timezoneCombo.QWidget)
+
+	elevationCheck := qt.NewQCheckBox3("Elevation:")
+	elevationSpin := qt.NewQSpinBox2()
+	elevationSpin.SetRange(-500, 9000)
+	elevationSpin.SetSingleStep(10)
+	elevationSpin.SetSuffix(" m")
+	elevationSpin.SetToolTip("Height of the spot above sea level; a higher viewpoint sees the sun " +
+		"rise earlier and set later")
+	elevationSpin.SetValue(int(favorite.Location.Elevation))
+	if current.Elevation != nil {
+		elevationSpin.SetValue(int(*current.Elevation))
+	}
+	form.AddRow(elevationCheck.QWidget, elevationSpin.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	// A field is only editable while its override is on
+	for _, row := range []struct {
+		check *qt.QCheckBox
+		field *qt.QWidget
+		on    bool
+	}{
+		{goldenCheck, goldenSpin.QWidget, current.GoldenHourElevation != nil},
+		{timezoneCheck, timezoneCombo.QWidget, current.Timezone != ""},
+		{elevationCheck, elevationSpin.QWidget, current.Elevation != nil},
+	} {
+		row.check.SetChecked(row.on)
+		row.field.SetEnabled(row.on)
+		row.check.OnToggled(row.field.SetEnabled)
+	}
+
+	help := qt.NewQLabel3("Applied whenever this favorite is selected, e.g., for a spot where " +
+		"a ridge hides the low sun. The settings panel keeps the general angles.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Ok | qt.QDialogButtonBox__Cancel)
+	buttons.OnAccepted(func() {
+		zone := strings.TrimSpace(timezoneCombo.CurrentText())
+		if timezoneCheck.IsChecked() && !domain.ValidTimezone(zone) {
+			qt.QMessageBox_Warning(dialog.QWidget, "Timezone",
+				fmt.Sprintf("%q is not a timezone. Pick one from the list (e.g., Europe/Zurich).", zone))
+			timezoneCombo.SetFocus()
+			return
+		}
+		dialog.Accept()
+	})
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+
+	if dialog.Exec() != int(qt.QDialog__Accepted) {
+		return domain.FavoriteOverrides{}, false
+	}
+	if goldenCheck.IsChecked() {
+		golden := goldenSpin.Value()
+		overrides.GoldenHourElevation = &golden
+	}
+	if timezoneCheck.IsChecked() {
+		overrides.Timezone = strings.TrimSpace(timezoneCombo.CurrentText())
+	}
+	if elevationCheck.IsChecked() {
+		elevation := float64(elevationSpin.Value())
+		overrides.Elevation = &elevation
+	}
+	return overrides, true
+}
+
+// favoriteOverridesText describes a favorite's overrides for the favorites
+// list's tooltips, e.g., "golden hour at 11.0°, Europe/Rome"; empty if there
+// are none.
+func favoriteOverridesText(o domain.FavoriteOverrides) string {
+	var parts []string
+	if o.GoldenHourElevation != nil {
+		parts = append(parts, fmt.Sprintf("golden hour at %.1f°", *o.GoldenHourElevation))
+	}
+	if o.Timezone != "" {
+		parts = append(parts, o.Timezone)
+	}
+	if o.Elevation != nil {
+		parts = append(parts, fmt.Sprintf("%.0f m", *o.Elevation))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,183 @@
+package widgets
+
+import (
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// FavoritesPanel
+// =============================================================================
+
+// FavoritesPanel lists the saved favorites with tonight's golden hour start.
+//
+// Each row shows the favorite's name and a golden "chip" with the time the
+// evening golden hour starts there today, so spots can be compared at a
+// glance. Clicking a row selects the favorite as the current location;
+// right-clicking it offers the favorite's own settings (see
+// ShowFavoriteSettingsDialog), and favorites that have some are marked ⚙.
+//
+// # UI Layout
+//
+//	┌─ Favorites ──────────────────────────────────────┐
+//	│ Bridge, sunset side                    ( 20:41 ) │
+//	│ Valley ⚙                               ( 19:52 ) │
+//	│ Harbour lighthouse                     ( 20:58 ) │
+//	│ North ridge                            ( N/A   ) │
+//	└──────────────────────────────────────────────────┘
+//
+// The panel is hidden while there are no favorites; places are added with
+// the star in the location panel.
+//
+// The times are calculated by the App (see SetFavorites); the panel only
+// displays them.
+type FavoritesPanel struct {
+	// groupBox is the container widget with "Favorites" title border.
+	groupBox *qt.QGroupBox
+
+	// list has one row per favorite, in settings order. Each row's
+	// content is an item widget with the name and chip labels.
+	list *qt.QListWidget
+
+	// favorites are the listed favorites, indexed by row.
+	favorites []domain.Favorite
+
+	// onSelect is the callback invoked when user clicks a favorite.
+	onSelect func(loc domain.Location)
+
+	// onEdit is the callback invoked when user picks "Settings..." in a
+	// favorite's context menu.
+	onEdit func(favorite domain.Favorite)
+}
+
+// favoritesMaxVisibleRows is how many rows the list shows before it
+// scrolls, so many favorites don't push the other panels away.
+const favoritesMaxVisibleRows = 5
+
+// NewFavoritesPanel creates an empty favorites panel.
+//
+// Parameters:
+//   - onSelect: Callback invoked with the favorite's location when user
+//     clicks a row
+//   - onEdit: Callback invoked with the favorite when user picks
+//     "Settings..." from a row's context menu
+//
+// Returns a hidden FavoritesPanel; call SetFavorites to fill it.
+func NewFavoritesPanel(onSelect func(loc domain.Location), onEdit func(favorite domain.Favorite)) *FavoritesPanel {
+	fp := &FavoritesPanel{onSelect: onSelect, onEdit: onEdit}
+	fp.setupUI()
+	return fp
+}
+
+// setupUI creates the group box and its list.
+//
+// miqt API notes:
+//   - SetContextMenuPolicy(qt.CustomContextMenu) makes right-clicks emit
+//     OnCustomContextMenuRequested; a scroll area reports the position in
+//     its viewport's coordinates
+//   - QMenu.ExecWithPos shows the menu at a global position and blocks
+func (fp *FavoritesPanel) setupUI() {
+	fp.groupBox = qt.NewQGroupBox3("Favorites")
+	layout := qt.NewQVBoxLayout(fp.groupBox.QWidget)
+	layout.SetContentsMargins(6, 6, 6, 6)
+
+	fp.list = qt.NewQListWidget(nil)
+	fp.list.SetToolTip("Tonight's golden hour start at each favorite. Click to select, " +
+		"right-click for its own settings.")
+	fp.list.OnItemClicked(func(item *qt.QListWidgetItem) {
+		row := fp.list.Row(item)
+		if row >= 0 && row < len(fp.favorites) && fp.onSelect != nil {
+			fp.onSelect(fp.favorites[row].Location)
+		}
+	})
+	fp.list.SetContextMenuPolicy(qt.CustomContextMenu)
+	fp.list.OnCustomContextMenuRequested(func(pos *qt.QPoint) {
+		item := fp.list.ItemAt(pos)
+		if item == nil {
+			return
+		}
+		row := fp.list.Row(item)
+		if row < 0 || row >= len(fp.favorites) || fp.onEdit == nil {
+			return
+		}
+		favorite := fp.favorites[row]
+		menu := qt.NewQMenu(fp.list.QWidget)
+		menu.AddActionWithText("Settings...").OnTriggered(func() { fp.onEdit(favorite) })
+		menu.ExecWithPos(fp.list.Viewport().MapToGlobalWithQPoint(pos))
+	})
+	layout.AddWidget(fp.list.QWidget)
+
+	fp.groupBox.SetVisible(false)
+}
+
+// Widget returns the group box container for adding to parent layouts.
+func (fp *FavoritesPanel) Widget() *qt.QGroupBox {
+	return fp.groupBox
+}
+
+// SetFavorites replaces the listed favorites.
+//
+// Parameters:
+//   - favorites: The favorites in settings order
+//   - tonight: Each favorite's evening golden hour today, in the order of
+//     favorites; an invalid range (or a missing entry) shows "N/A"
+//   - use24Hour: Time format of the chips
+//   - nameStyle: How much of the names to show (domain.Settings.PlaceNameStyle)
+func (fp *FavoritesPanel) SetFavorites(favorites []domain.Favorite, tonight []domain.TimeRange, use24Hour bool, nameStyle string) {
+	fp.favorites = favorites
+	fp.list.Clear()
+	for i, f := range favorites {
+		chip := "N/A"
+		if i < len(tonight) && tonight[i].IsValid() {
+			chip = domain.FormatTime(tonight[i].Start, use24Hour)
+		}
+		name, tooltip := f.Location.DisplayName(nameStyle), f.Location.Name
+		if overrides := favoriteOverridesText(f.Overrides); overrides != "" {
+			name += " ⚙"
+			tooltip += "\nOwn settings: " + overrides
+		}
+		fp.addRow(name, tooltip, chip)
+	}
+	fp.groupBox.SetVisible(len(favorites) > 0)
+
+	// Fit the rows without scrolling, up to favoritesMaxVisibleRows
+	if rows := min(len(favorites), favoritesMaxVisibleRows); rows > 0 {
+		frame := 2 * fp.list.FrameWidth()
+		fp.list.SetFixedHeight(rows*fp.list.SizeHintForRow(0) + frame)
+	}
+}
+
+// addRow appends a row with a name and a time chip; the tooltip shows the
+// full name (and the favorite's own settings).
+//
+// miqt API notes:
+//   - NewQListWidgetItem5(list) adds an item without text to the list,
+//     so nothing shows through the item widget
+//   - SetItemWidget draws the widget over the item, which takes the
+//     widget's size hint
+func (fp *FavoritesPanel) addRow(name, tooltip, chip string) {
+	item := qt.NewQListWidgetItem5(fp.list)
+
+	row := qt.NewQWidget(nil)
+	rowLayout := qt.NewQHBoxLayout(row)
+	rowLayout.SetContentsMargins(4, 2, 4, 2)
+
+	nameLabel := qt.NewQLabel3(name)
+	rowLayout.AddWidget(nameLabel.QWidget)
+	rowLayout.AddStretch()
+
+	// Orange like the golden hour group of the time panel
+	chipLabel := qt.NewQLabel3(chip)
+	chipLabel.SetStyleSheet(`
+		background-color: #ff9800;
+		color: white;
+		font-weight: bold;
+		border-radius: 8px;
+		padding: 1px 8px;
+	`)
+	rowLayout.AddWidget(chipLabel.QWidget)
+
+	item.SetToolTip(tooltip)
+	item.SetSizeHint(row.SizeHint())
+	fp.list.SetItemWidget(item, row)
+}
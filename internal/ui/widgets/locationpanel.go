@@ -2,6 +2,7 @@ package widgets
 
 import (
 	"fmt"
+	"strings"
 
 	qt "github.com/mappu/miqt/qt6"
 	"github.com/megatih/GoGoldenHour/internal/domain"
@@ -14,24 +15,40 @@ import (
 // LocationPanel provides location search and display functionality.
 //
 // This panel allows users to:
-//   - Search for locations by name using Nominatim geocoding
+//   - Search for locations by name using Nominatim geocoding, or enter
+//     coordinates directly (decoded locally, see the coordinates package)
 //   - Auto-detect their location via IP geolocation
+//   - Jump back to one of the last selected scratch locations ("Recent"
+//     menu): map clicks, searches and detections that weren't saved as
+//     favorites, kept for a while (Settings.ScratchRetentionDays)
 //   - View the current location's coordinates and name
 //
 // # UI Layout
 //
 //	┌─ Location ─────────────────────────┐
 //	│ [Search location...        ] [Go]  │  <- Search input + button
-//	│ [    Detect My Location        ]   │  <- Auto-detect button
-//	│ Lat: 48.8566        Lon: 2.3522    │  <- Coordinate display
-//	│ Paris, France                      │  <- Location name (orange, bold)
-//	└────────────────────────────────────┘
+//	│ ┌────────────────────────────┐     │
+//	│ │ Paris, France (city)       │     │  <- Results dropdown (popup,
+//	│ │ Paris, Texas, USA (city)   │     │     shown for several results)
+//	│ └────────────────────────────┘     │
+//	│ [ Detect My Location  ] [Recent ▾] │  <- Auto-detect + history menu
+//	│ Lat: 48.8566° N   Lon: 2.3522° E   │  <- Coordinate display (decimal
+//	│                                    │     or DMS, SetCoordinateFormat)
+//	│ Paris, France                  [☆] │  <- Location name (orange, bold)
+//	└────────────────────────────────────┘     and favorite toggle
 //
 // # Communication
 //
 // The panel communicates with the main application via callbacks:
 //   - onSearch: Called when user submits a search query (Enter or Go button)
+//   - onSelectResult: Called when user picks a place from the results
+//     dropdown or the "Recent" menu
 //   - onDetect: Called when user clicks "Detect My Location"
+//   - onToggleFavorite: Called when user clicks the star next to the name
+//   - onClearRecent: Called when user clears the "Recent" menu
+//   - onCancelSearch, onCancelDetect: Called when user clicks the Go or
+//     Detect button while its operation runs (see SetSearching,
+//     SetDetecting)
 //
 // These callbacks are invoked synchronously on the main Qt thread.
 // The actual geocoding/geolocation work is done asynchronously by the App.
@@ -43,44 +60,124 @@ type LocationPanel struct {
 	// Supports Enter key to trigger search.
 	searchInput *qt.QLineEdit
 
-	// searchBtn triggers the search when clicked ("Go" button).
+	// searchBtn triggers the search when clicked ("Go" button), or stops
+	// it while searching.
 	searchBtn *qt.QPushButton
 
-	// detectBtn triggers IP-based location detection.
+	// resultsPopup lists search results under the search input.
+	// It is a top-level popup window, so it grabs the keyboard while open:
+	// Up/Down move the selection, Enter picks, Escape closes it.
+	resultsPopup *qt.QListWidget
+
+	// results are the search results shown in resultsPopup, by row.
+	results []domain.SearchResult
+
+	// detectBtn triggers IP-based location detection, or stops it while
+	// detecting.
 	detectBtn *qt.QPushButton
 
-	// latLabel displays the current latitude (e.g., "Lat: 48.8566").
+	// searching and detecting are true while a search or a detection runs
+	// (see SetSearching, SetDetecting).
+	searching bool
+	detecting bool
+
+	// spinner animates the buttons of running operations; spinnerFrame is
+	// the index into spinnerFrames shown.
+	spinner      *qt.QTimer
+	spinnerFrame int
+
+	// recentBtn opens recentMenu, which lists the recent locations (most
+	// recent first). Disabled while there are none.
+	recentBtn  *qt.QToolButton
+	recentMenu *qt.QMenu
+
+	// latLabel displays the current latitude (e.g., "Lat: 48.8566° N").
 	latLabel *qt.QLabel
 
-	// lonLabel displays the current longitude (e.g., "Lon: 2.3522").
+	// lonLabel displays the current longitude (e.g., "Lon: 2.3522° E").
 	lonLabel *qt.QLabel
 
+	// location and recent are the displayed location (if hasLocation) and
+	// recent locations, kept to redisplay their coordinates when the format
+	// changes.
+	location    domain.Location
+	hasLocation bool
+	recent      []domain.RecentLocation
+
+	// coordinateFormat is the coordinate display format
+	// (domain.Settings.CoordinateFormat).
+	coordinateFormat string
+
+	// placeNameStyle is how much of the place names to show
+	// (domain.Settings.PlaceNameStyle).
+	placeNameStyle string
+
 	// nameLabel displays the human-readable location name.
 	// Styled with orange color and bold font for visibility.
 	nameLabel *qt.QLabel
 
+	// favoriteBtn shows whether the location is a favorite (★) or not (☆),
+	// and adds or removes it when clicked.
+	favoriteBtn *qt.QToolButton
+
 	// onSearch is the callback invoked when user searches for a location.
 	// Receives the search query string.
 	onSearch func(query string)
 
+	// onSelectResult is the callback invoked when user picks a search result
+	// or a recent location. Receives the chosen location.
+	onSelectResult func(loc domain.Location)
+
 	// onDetect is the callback invoked when user clicks auto-detect.
 	onDetect func()
+
+	// onToggleFavorite is the callback invoked when user clicks the star.
+	onToggleFavorite func()
+
+	// onClearRecent is the callback invoked when user chooses "Clear
+	// Scratch Locations" in the "Recent" menu.
+	onClearRecent func()
+
+	// onCancelSearch and onCancelDetect are the callbacks invoked when user
+	// stops a running search or detection.
+	onCancelSearch func()
+	onCancelDetect func()
 }
 
+// spinnerFrames are the frames of the busy buttons' animation.
+var spinnerFrames = []string{"◐", "◓", "◑", "◒"}
+
 // NewLocationPanel creates a new location panel with the given callbacks.
 //
 // Parameters:
 //   - onSearch: Callback invoked when user submits a search query.
 //     The App uses this to trigger Nominatim geocoding.
+//   - onSelectResult: Callback invoked when user picks one of the results
+//     passed to ShowSearchResults, or one of the locations passed to
+//     SetRecentLocations. The App makes it the current location.
 //   - onDetect: Callback invoked when user clicks "Detect My Location".
 //     The App uses this to trigger IP-based geolocation.
+//   - onToggleFavorite: Callback invoked when user clicks the favorite star.
+//     The App adds the current location to the favorites or removes it.
+//   - onClearRecent: Callback invoked when user clears the scratch
+//     locations. The App forgets them and calls SetRecentLocations.
+//   - onCancelSearch: Callback invoked when user clicks Go while searching.
+//     The App stops the search.
+//   - onCancelDetect: Callback invoked when user clicks Detect while
+//     detecting. The App stops the detection.
 //
 // Returns a fully initialized LocationPanel ready to be added to a layout.
 // The panel initially shows placeholder text ("--") until SetLocation is called.
-func NewLocationPanel(onSearch func(query string), onDetect func()) *LocationPanel {
+func NewLocationPanel(onSearch func(query string), onSelectResult func(loc domain.Location), onDetect func(),
+	onToggleFavorite, onClearRecent, onCancelSearch, onCancelDetect func()) *LocationPanel {
 	lp := &LocationPanel{
-		onSearch: onSearch,
-		onDetect: onDetect,
+		onSearch:         onSearch,
+		onSelectResult:   onSelectResult,
+		onDetect:         onDetect,
+		onToggleFavorite: onToggleFavorite,
+		onClearRecent:    onClearRecent,
+		onCancelSearch:   onCancelSearch,
+		onCancelDetect:   onCancelDetect,
 	}
 
 	lp.setupUI()
@@ -91,11 +188,15 @@ func NewLocationPanel(onSearch func(query string), onDetect func()) *LocationPan
 //
 // This is a consolidated helper that handles both Enter key and button click.
 // It checks that:
-//  1. The search input is not empty
-//  2. The onSearch callback is set
+//  1. No search runs already (Enter is ignored then)
+//  2. The search input is not empty
+//  3. The onSearch callback is set
 //
-// If both conditions are met, it invokes the callback with the search query.
+// If all conditions are met, it invokes the callback with the search query.
 func (lp *LocationPanel) performSearch() {
+	if lp.searching {
+		return
+	}
 	query := lp.searchInput.Text()
 	if query != "" && lp.onSearch != nil {
 		lp.onSearch(query)
@@ -106,9 +207,9 @@ func (lp *LocationPanel) performSearch() {
 //
 // The layout is a vertical stack:
 //  1. Search row: text input + "Go" button (horizontal)
-//  2. Detect button: full-width "Detect My Location" button
+//  2. Detect row: "Detect My Location" button + "Recent" menu button
 //  3. Coordinates row: latitude and longitude labels (horizontal)
-//  4. Name label: location name with special styling
+//  4. Name row: location name with special styling + favorite star
 //
 // # miqt API Notes
 //
@@ -118,6 +219,7 @@ func (lp *LocationPanel) performSearch() {
 //   - NewQPushButton3("text"): Creates button with text (suffix "3")
 //   - NewQLabel3("text"): Creates label with text (suffix "3")
 //   - NewQHBoxLayout2(): Creates horizontal layout (suffix "2" = no parent)
+//   - NewQMenu2(): Creates a parentless menu, shown by a tool button
 //
 // Layout methods take single QWidget/QLayout argument (no stretch parameter).
 func (lp *LocationPanel) setupUI() {
@@ -135,7 +237,10 @@ func (lp *LocationPanel) setupUI() {
 
 	// NewQLineEdit2: suffix "2" = no-parameter constructor (empty input)
 	lp.searchInput = qt.NewQLineEdit2()
-	lp.searchInput.SetPlaceholderText("Search location...")
+	lp.searchInput.SetPlaceholderText("Search location or enter coordinates...")
+	lp.searchInput.SetToolTip("A place name, or coordinates such as 48.8566, 2.3522, " +
+		"48°51'24\"N 2°21'03\"E, an MGRS reference (31U DQ 48251 11932) " +
+		"or a plus code (8FW4V75V+8Q)")
 
 	// NewQPushButton3: suffix "3" = constructor with text parameter
 	lp.searchBtn = qt.NewQPushButton3("Go")
@@ -143,7 +248,18 @@ func (lp *LocationPanel) setupUI() {
 
 	// Connect both button click and Enter key to the same search handler.
 	// This provides a consistent UX - users can click or press Enter.
-	lp.searchBtn.OnClicked(func() { lp.performSearch() })
+	// While searching, the button stops the search instead. Alt+S clicks it
+	// from anywhere in the window.
+	lp.searchBtn.SetShortcut(qt.NewQKeySequence2(SearchKeys))
+	lp.searchBtn.OnClicked(func() {
+		if lp.searching {
+			if lp.onCancelSearch != nil {
+				lp.onCancelSearch()
+			}
+			return
+		}
+		lp.performSearch()
+	})
 	lp.searchInput.OnReturnPressed(func() { lp.performSearch() })
 
 	// Add widgets to horizontal layout (miqt takes single argument, no stretch)
@@ -152,16 +268,61 @@ func (lp *LocationPanel) setupUI() {
 	layout.AddLayout(searchRow.QLayout)
 
 	// =========================================================================
-	// Detect Location Button
+	// Search Results Dropdown
+	// =========================================================================
+	// A parentless list shown as a popup window (qt.Popup), like a combo box
+	// dropdown. Qt closes popups on Escape or a click outside them.
+	lp.resultsPopup = qt.NewQListWidget(nil)
+	lp.resultsPopup.SetWindowFlags(qt.Popup)
+
+	// Enter (and double click) activates an item; a single click should
+	// pick too. selectResult ignores the second call when both fire.
+	lp.resultsPopup.OnItemActivated(func(item *qt.QListWidgetItem) {
+		lp.selectResult(lp.resultsPopup.Row(item))
+	})
+	lp.resultsPopup.OnItemClicked(func(item *qt.QListWidgetItem) {
+		lp.selectResult(lp.resultsPopup.Row(item))
+	})
+
+	// =========================================================================
+	// Detect Location Button and Recent Menu
 	// =========================================================================
-	// Full-width button for IP-based location detection
+	// Wide button for IP-based location detection
+	detectRow := qt.NewQHBoxLayout2()
 	lp.detectBtn = qt.NewQPushButton3("Detect My Location")
 	lp.detectBtn.OnClicked(func() {
+		if lp.detecting {
+			if lp.onCancelDetect != nil {
+				lp.onCancelDetect()
+			}
+			return
+		}
 		if lp.onDetect != nil {
 			lp.onDetect()
 		}
 	})
-	layout.AddWidget(lp.detectBtn.QWidget)
+
+	// Busy buttons spin while their operation runs
+	lp.spinner = qt.NewQTimer2(lp.groupBox.QObject)
+	lp.spinner.SetInterval(150)
+	lp.spinner.OnTimeout(func() {
+		lp.spinnerFrame = (lp.spinnerFrame + 1) % len(spinnerFrames)
+		lp.updateBusyButtons()
+	})
+	detectRow.AddWidget(lp.detectBtn.QWidget)
+
+	// History menu: InstantPopup opens the menu on click (no separate
+	// arrow area), like a menu bar entry
+	lp.recentMenu = qt.NewQMenu2()
+	lp.recentMenu.SetToolTipsVisible(true)
+	lp.recentBtn = qt.NewQToolButton2()
+	lp.recentBtn.SetText("Recent")
+	lp.recentBtn.SetToolTip("Go back to a recently selected scratch location (not a favorite)")
+	lp.recentBtn.SetPopupMode(qt.QToolButton__InstantPopup)
+	lp.recentBtn.SetMenu(lp.recentMenu)
+	lp.recentBtn.SetEnabled(false)
+	detectRow.AddWidget(lp.recentBtn.QWidget)
+	layout.AddLayout(detectRow.QLayout)
 
 	// =========================================================================
 	// Coordinates Display Row
@@ -178,10 +339,62 @@ func (lp *LocationPanel) setupUI() {
 	// Location Name Display
 	// =========================================================================
 	// Display location name with golden hour theme styling (orange color)
+	nameRow := qt.NewQHBoxLayout2()
 	lp.nameLabel = qt.NewQLabel3("--")
 	lp.nameLabel.SetWordWrap(true) // Handle long location names
 	lp.nameLabel.SetStyleSheet("font-weight: bold; color: #ff9800;")
-	layout.AddWidget(lp.nameLabel.QWidget)
+	nameRow.AddWidget(lp.nameLabel.QWidget)
+
+	// Favorite star: a flat tool button, so it sits quietly next to the name
+	lp.favoriteBtn = qt.NewQToolButton2()
+	lp.favoriteBtn.SetAutoRaise(true)
+	lp.SetFavorite(false)
+	lp.favoriteBtn.OnClicked(func() {
+		if lp.onToggleFavorite != nil {
+			lp.onToggleFavorite()
+		}
+	})
+	nameRow.AddWidget(lp.favoriteBtn.QWidget)
+	layout.AddLayout(nameRow.QLayout)
+
+	// Tab goes through the panel in reading order: search, Go, Detect,
+	// Recent, star (the tool buttons only take the focus by Tab)
+	qt.QWidget_SetTabOrder(lp.searchInput.QWidget, lp.searchBtn.QWidget)
+	qt.QWidget_SetTabOrder(lp.searchBtn.QWidget, lp.detectBtn.QWidget)
+	qt.QWidget_SetTabOrder(lp.detectBtn.QWidget, lp.recentBtn.QWidget)
+	qt.QWidget_SetTabOrder(lp.recentBtn.QWidget, lp.favoriteBtn.QWidget)
+}
+
+// SearchKeys click the Go button (searching, or stopping a search).
+const SearchKeys = "Alt+S"
+
+// FocusSearch puts the keyboard focus into the search field, with its text
+// selected so typing replaces it (Ctrl+L).
+func (lp *LocationPanel) FocusSearch() {
+	lp.searchInput.SetFocus()
+	lp.searchInput.SelectAll()
+}
+
+// LeaveSearch takes the keyboard focus out of the search field, so the
+// window's single keys (e.g., T for today) work again.
+//
+// Returns false if the search field didn't have the focus.
+func (lp *LocationPanel) LeaveSearch() bool {
+	if !lp.searchInput.HasFocus() {
+		return false
+	}
+	lp.searchInput.ClearFocus()
+	return true
+}
+
+// Searching reports whether a search runs (see SetSearching).
+func (lp *LocationPanel) Searching() bool {
+	return lp.searching
+}
+
+// Detecting reports whether a detection runs (see SetDetecting).
+func (lp *LocationPanel) Detecting() bool {
+	return lp.detecting
 }
 
 // Widget returns the group box container for adding to parent layouts.
@@ -192,6 +405,89 @@ func (lp *LocationPanel) Widget() *qt.QGroupBox {
 	return lp.groupBox
 }
 
+// ShowSearchResults opens the results dropdown under the search input.
+//
+// Each result is listed as "display name (type)", with the full name as a
+// tooltip in case it is cut off. The first (most relevant) result is
+// selected, so pressing Enter right away picks it.
+//
+// Parameters:
+//   - results: The search results to choose from, most relevant first
+func (lp *LocationPanel) ShowSearchResults(results []domain.SearchResult) {
+	lp.results = results
+	lp.resultsPopup.Clear()
+	for _, r := range results {
+		label := r.Location.Name
+		if r.Type != "" {
+			label = fmt.Sprintf("%s (%s)", r.Location.Name, r.Type)
+		}
+		lp.resultsPopup.AddItem(label)
+		lp.resultsPopup.Item(lp.resultsPopup.Count() - 1).SetToolTip(label)
+	}
+	lp.resultsPopup.SetCurrentRow(0)
+
+	// Place the popup right below the input, at least as wide as the input
+	// MapToGlobalWithQPoint converts widget coordinates to screen coordinates
+	input := lp.searchInput.QWidget
+	pos := input.MapToGlobalWithQPoint(qt.NewQPoint2(0, input.Height()))
+	lp.resultsPopup.Move(pos.X(), pos.Y())
+	lp.resultsPopup.SetMinimumWidth(input.Width())
+	lp.resultsPopup.Show()
+	lp.resultsPopup.SetFocus()
+}
+
+// selectResult closes the results dropdown and reports the result in row.
+//
+// Does nothing if the dropdown is already closed, so a click that is
+// reported both as clicked and as activated selects only once.
+func (lp *LocationPanel) selectResult(row int) {
+	if !lp.resultsPopup.IsVisible() || row < 0 || row >= len(lp.results) {
+		return
+	}
+	lp.resultsPopup.Hide()
+	if lp.onSelectResult != nil {
+		lp.onSelectResult(lp.results[row].Location)
+	}
+}
+
+// SetRecentLocations fills the "Recent" menu.
+//
+// The menu is headed "Scratch Locations", to set them apart from the
+// saved favorites. Each location is listed by name, with its coordinates
+// and when it was last used as a tooltip; choosing one reports it through
+// onSelectResult. A last entry clears the list.
+//
+// Parameters:
+//   - recent: The scratch locations, most recent first (may be empty)
+func (lp *LocationPanel) SetRecentLocations(recent []domain.RecentLocation) {
+	lp.recent = recent
+	lp.recentMenu.Clear()
+	lp.recentMenu.AddSection("Scratch Locations")
+	for _, r := range recent {
+		loc := r.Location
+		// "&" would mark a mnemonic in menu text, so it is doubled
+		action := lp.recentMenu.AddActionWithText(strings.ReplaceAll(loc.DisplayName(lp.placeNameStyle), "&", "&&"))
+		tip := domain.FormatCoordinatesIn(loc.Latitude, loc.Longitude, lp.coordinateFormat)
+		if !r.Used.IsZero() {
+			tip += ", last used " + r.Used.Format("Jan 2 15:04")
+		}
+		action.SetToolTip(tip)
+		action.OnTriggered(func() {
+			if lp.onSelectResult != nil {
+				lp.onSelectResult(loc)
+			}
+		})
+	}
+	lp.recentMenu.AddSeparator()
+	clearAction := lp.recentMenu.AddActionWithText("Clear Scratch Locations")
+	clearAction.OnTriggered(func() {
+		if lp.onClearRecent != nil {
+			lp.onClearRecent()
+		}
+	})
+	lp.recentBtn.SetEnabled(len(recent) > 0)
+}
+
 // SetLocation updates the displayed location information.
 //
 // This method is called by MainWindow when the location changes, either from:
@@ -200,11 +496,99 @@ func (lp *LocationPanel) Widget() *qt.QGroupBox {
 //   - Map click (reverse geocoding result)
 //
 // The display is updated with:
-//   - Latitude formatted to 4 decimal places (≈11m precision)
-//   - Longitude formatted to 4 decimal places
-//   - Location name (city, country, or coordinates if unavailable)
+//   - Latitude with hemisphere, to 4 decimal places (≈11m precision) or
+//     tenths of a second, depending on the coordinate format
+//   - Longitude in the same format
+//   - Location name (city, country, or coordinates if unavailable) in the
+//     place name style, with the full name as a tooltip
 func (lp *LocationPanel) SetLocation(loc domain.Location) {
-	lp.latLabel.SetText(fmt.Sprintf("Lat: %.4f", loc.Latitude))
-	lp.lonLabel.SetText(fmt.Sprintf("Lon: %.4f", loc.Longitude))
-	lp.nameLabel.SetText(loc.Name)
+	lp.location, lp.hasLocation = loc, true
+	lp.latLabel.SetText("Lat: " + domain.FormatLatitude(loc.Latitude, lp.coordinateFormat))
+	lp.lonLabel.SetText("Lon: " + domain.FormatLongitude(loc.Longitude, lp.coordinateFormat))
+	lp.nameLabel.SetText(loc.DisplayName(lp.placeNameStyle))
+	lp.nameLabel.SetToolTip(loc.Name)
+}
+
+// SetPlaceNameStyle switches between short, medium and full place names,
+// updating the shown location and the "Recent" menu.
+//
+// Parameters:
+//   - style: domain.PlaceNameShort, domain.PlaceNameMedium or
+//     domain.PlaceNameFull
+func (lp *LocationPanel) SetPlaceNameStyle(style string) {
+	lp.placeNameStyle = style
+	if lp.hasLocation {
+		lp.SetLocation(lp.location)
+	}
+	lp.SetRecentLocations(lp.recent)
+}
+
+// SetCoordinateFormat switches the coordinate display between decimal
+// degrees and degrees/minutes/seconds, updating the shown location and the
+// "Recent" menu tooltips.
+//
+// Parameters:
+//   - format: domain.CoordinateFormatDecimal or domain.CoordinateFormatDMS
+func (lp *LocationPanel) SetCoordinateFormat(format string) {
+	lp.coordinateFormat = format
+	if lp.hasLocation {
+		lp.SetLocation(lp.location)
+	}
+	lp.SetRecentLocations(lp.recent)
+}
+
+// SetFavorite shows whether the displayed location is one of the favorites.
+//
+// The star is filled (★) for a favorite and hollow (☆) otherwise; its
+// tooltip says what clicking it does.
+func (lp *LocationPanel) SetFavorite(isFavorite bool) {
+	if isFavorite {
+		lp.favoriteBtn.SetText("★")
+		lp.favoriteBtn.SetToolTip("Remove from favorites")
+		return
+	}
+	lp.favoriteBtn.SetText("☆")
+	lp.favoriteBtn.SetToolTip("Add to favorites")
+}
+
+// SetSearching shows whether a search runs: the Go button spins and stops
+// the search when clicked, and Enter doesn't start another one.
+func (lp *LocationPanel) SetSearching(busy bool) {
+	lp.searching = busy
+	lp.updateBusyButtons()
+}
+
+// SetDetecting shows whether location detection runs: the Detect button
+// spins and stops the detection when clicked.
+func (lp *LocationPanel) SetDetecting(busy bool) {
+	lp.detecting = busy
+	lp.updateBusyButtons()
+}
+
+// updateBusyButtons shows the Go and Detect buttons as idle or busy, and
+// runs the spinner while either is busy.
+func (lp *LocationPanel) updateBusyButtons() {
+	frame := spinnerFrames[lp.spinnerFrame]
+	if lp.searching {
+		lp.searchBtn.SetText(frame)
+		lp.searchBtn.SetToolTip("Searching... Click to stop")
+	} else {
+		lp.searchBtn.SetText("Go")
+		lp.searchBtn.SetToolTip("")
+	}
+	if lp.detecting {
+		lp.detectBtn.SetText(frame + " Detecting... (Stop)")
+		lp.detectBtn.SetToolTip("Click to stop the detection")
+	} else {
+		lp.detectBtn.SetText("Detect My Location")
+		lp.detectBtn.SetToolTip("")
+	}
+
+	switch busy := lp.searching || lp.detecting; {
+	case busy && !lp.spinner.IsActive():
+		lp.spinner.Start2()
+	case !busy:
+		lp.spinner.Stop()
+		lp.spinnerFrame = 0
+	}
 }
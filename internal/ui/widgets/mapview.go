@@ -11,6 +11,9 @@
 //   - DatePanel: Date navigation with calendar
 //   - TimePanel: Golden/blue hour time display
 //   - SettingsPanel: User preferences configuration
+//   - ShowAlignmentDialog: Sun alignment search results
+//   - CameraDialog: Camera field of view and horizon events
+//   - ShowWhatsNewDialog: Release notes after an update
 //
 // # miqt Qt6 API Patterns
 //
@@ -36,12 +39,16 @@ package widgets
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 
 	qt "github.com/mappu/miqt/qt6"
 	we "github.com/mappu/miqt/qt6/webengine"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
 )
 
 // =============================================================================
@@ -61,16 +68,198 @@ import (
 // Since miqt doesn't expose QWebEnginePage.RunJavaScript(), the widget uses
 // alternative communication methods:
 //
-// Go → JavaScript (location updates):
-//   - URL hash fragments: data:text/html;base64,...#lat,lon,zoom
-//   - JavaScript listens for 'hashchange' events
+// Go → JavaScript (location updates, zoom control):
+//   - URL hash fragments: data:text/html;base64,...#!seq;cmd1;cmd2
+//   - JavaScript listens for 'hashchange' events and runs each command
 //   - Smooth panning without page reload
 //
-// JavaScript → Go (map clicks):
+// Commands are queued by sendCommand and flushed together on the next event
+// loop iteration, so several calls in a row (e.g., SetLocation followed by
+// FitBounds) arrive as one hash change instead of overwriting each other.
+// The increasing sequence number makes every batch a distinct hash, so
+// repeating a command (such as ZoomIn twice) still fires 'hashchange'.
+//
+// Command protocol:
+//
+//	view:lat,lon,zoom        Center the map and move the location marker
+//	markername:text          Set the location marker's tooltip
+//	tiles:url,subdomains,key Load base layer tiles from url (empty: OpenStreetMap)
+//	zoom:z                   Set the zoom level
+//	zoomin / zoomout         Change the zoom level by one step
+//	fitbounds:s,w,n,e        Fit the view to a bounding box
+//	terminator:i,lat,lon,... Replace twilight band i with a polygon
+//	fullscreen:0|1           Show the full-screen button as inactive/active
+//	pointlayer:id,title      Create point layer id, listed in the layer control
+//	points:id,h,lat,lon,name,...  Add points (handle h) to point layer id
+//	pointremove:id,h,...     Remove points from point layer id
+//	track:id,name,lat,lon,...  Add a line to point layer id
+//	trackclear:id            Remove all lines from point layer id
+//	locate                   Query the browser Geolocation API
+//	profile:total,horizon,eye,text,h0,h1,...  Show an elevation profile chart
+//	profileclear             Hide the elevation profile chart
+//	fovcone:lat,lon,...      Draw the camera's field of view cone (polygon)
+//	fovray:moon,inview,lat1,lon1,lat2,lon2,name  Draw a horizon event direction
+//	fovclear                 Remove the cone and rays
+//	sunpath:lat,lon,start,end,az,...  Draw the golden hour sun path sector
+//	sunpathclear             Remove the sun path sector
+//	sunpos:lat,lon,az,elev,text  Draw the sun's direction at the slider's time
+//	sunposclear              Remove the sun direction
+//	clouds:maxzoom,attribution,url,label,golden,...  Replace the cloud layer's
+//	                         animation frames (golden: 1 during golden hour)
+//	privacy:0|1              Offer or withdraw the online overlays
+//
+// Titles and names are sent as unpadded URL-safe base64, so they can contain
+// any character without breaking the command syntax.
+//
+// The initial page load uses the plain form #lat,lon,zoom. Only one batch is
+// in flight at a time: commands queued while the page runs a batch (and at
+// most maxBatchLength bytes per batch, e.g., for a large point layer) are
+// sent after the page acknowledges it with MAPACK. Setting a new hash before
+// the page handled the previous one could otherwise skip a batch and run
+// the next one twice.
+//
+// JavaScript → Go (map clicks, measurements, alignments):
 //   - JavaScript calls console.log("MAPCLICK:lat,lon")
 //   - Qt OnJavaScriptConsoleMessage intercepts the message
 //   - Go parses coordinates and invokes the callback
 //
+// Console message protocol:
+//
+//	MAPCLICK:lat,lon                       User clicked the map (selects location)
+//	MAPDRAG:lat,lon                        User dropped the location marker
+//	MAPPOINT:h                             User clicked point h of a point layer
+//	MAPMEASURE:lat1,lon1,lat2,lon2         User measured between two points
+//	MAPALIGN:camLat,camLon,subLat,subLon   User picked camera and subject points
+//	MAPCAMERA:camLat,camLon,dirLat,dirLon  User placed a camera and its direction
+//	MAPZOOM:z                              Zoom level changed (any cause)
+//	MAPBOUNDS:s,w,n,e                      The visible area changed (any cause)
+//	MAPCURSOR:lat,lon                      Pointer moved over the map or mini-map
+//	MAPCURSOROUT                           Pointer left the map
+//	MAPFULLSCREEN                          User clicked the full-screen button
+//	MAPCLOUDS                              User turned on the cloud layer
+//	MAPACK:seq                             Page finished running batch seq
+//	MAPLOCATE                              User clicked the locate button
+//	MAPLOCATED:lat,lon,accuracy            Geolocation succeeded (accuracy in m)
+//	MAPLOCATEERROR:message                 Geolocation failed or was denied
+//
+// # Moving the Marker
+//
+// Besides clicking the map, the location marker can be dragged. The drop
+// point is reported through onMarkerDrag, and the name of the location
+// (once reverse geocoded) is shown in the marker's tooltip with
+// SetMarkerName.
+//
+// # Measure Mode
+//
+// A ruler control in the top-left corner toggles measure mode. While it is
+// active, clicks place the two endpoints of a measurement line instead of
+// moving the location marker. Distance and bearing are computed in Go (see
+// domain.Location.DistanceTo/BearingTo) so that the same numbers can be
+// reused by other features, such as sun alignment checks. SetProfile shows
+// the terrain along the line in a small chart in the bottom-left corner,
+// with the line of sight to the terrain horizon.
+//
+// # Alignment Mode
+//
+// A sun control below the ruler toggles alignment mode. The first click
+// places the camera, the second the subject; the pair is reported to Go,
+// which searches for dates when the sun rises or sets along that line.
+//
+// # Camera Mode
+//
+// A camera control below the alignment control toggles camera mode. The
+// first click places the camera, the second sets the direction it faces.
+// Go works out the field of view (see domain.Camera) and draws it with
+// SetCameraView: a cone, plus one ray per sunrise/sunset/moonrise/moonset,
+// solid when inside the cone and dashed outside it.
+//
+// # Full-Screen Mode
+//
+// A button below the map tool controls asks the main window to toggle
+// full-screen map mode (the window hides its side panels). While active,
+// SetOverlay shows a compact summary of the key times in a Qt label on top
+// of the map, so the times remain visible without the panels.
+//
+// # Locate
+//
+// A locate button (and Locate, used by the "Detect" action when the map is
+// the selected location source) asks the web engine for the device position
+// through the browser Geolocation API. Qt WebEngine asks the page owner for
+// permission; MapView grants it only while a locate request is in progress,
+// so the page can't track the position in the background. The result,
+// shown with an accuracy circle, is reported through onLocate. Whether a
+// position is available depends on the Qt WebEngine build and the platform's
+// location provider, so callers should treat errors as routine.
+//
+// # Point Layers
+//
+// Collections of pins (favorites, photo spots, imported waypoints) are shown
+// in named point layers, each with its own entry in the layer control. A
+// layer can also hold lines (SetTracks), such as imported GPS tracks. To
+// keep panning smooth with hundreds of points:
+//   - SetPoints only sends the difference to what the page already has
+//   - The page keeps all points in memory but only draws those near the
+//     visible area, re-rendering when the view changes
+//   - Nearby points are merged into a numbered cluster pin on a pixel grid;
+//     clicking a cluster zooms to its points. Above clusterMaxZoom (in the
+//     page script) every point is drawn individually
+//
+// Clicking a single point reports it through onPointClick (MAPPOINT), so the
+// point's own name and data can be used instead of a reverse geocoded one.
+// When a measure, alignment or camera mode is active, the click places a
+// point of the pair instead.
+//
+// # Mini-Map and Cursor Position
+//
+// A small inset in the bottom-right corner shows a wide overview of the
+// area around the view, with the visible area outlined, so panning a
+// detailed map doesn't lose context. Clicking the inset pans the map there.
+// The pointer's position over either map is reported (at most every
+// cursorInterval in the page script) through onCursorMove, for display in
+// the status bar.
+//
+// # Day/Night Terminator
+//
+// An overlay (toggled in the layer control, top-right) shades the night
+// side of the Earth with stacked twilight bands. The band polygons are
+// computed in Go by solar.Terminator and sent with SetTerminator; the
+// page only draws them and repeats them across world copies.
+//
+// # Light Pollution
+//
+// An optional overlay (off by default, toggled in the layer control) shows
+// NASA's Black Marble night lights composite from the VIIRS instrument, a
+// proxy for artificial sky brightness when looking for dark sites for
+// astrophotography. The tiles come straight from NASA GIBS and exist
+// down to zoom level 8; closer views scale them up.
+//
+// # Clouds
+//
+// An optional animated overlay (toggled in the layer control) plays recent
+// cloud images, with the time of the frame shown in the bottom-left corner
+// and highlighted during golden hour. Each time it is turned on, the map
+// asks for frames through onCloudsShown; they arrive with SetCloudFrames.
+//
+// # Tile Server
+//
+// The base layer uses the public OpenStreetMap tiles unless SetTileServer
+// selects another server (a local tileserver-gl, a company map server or a
+// commercial provider). The attribution and zoom limit come from the
+// server's domain.TileProvider; servers of unknown providers are credited
+// with their host, as the app can't know whose data their tiles show.
+//
+// The page doesn't load any base layer tiles until SetTileServer selects
+// the server. With a tile cache (see SetTileServer), the tiles are loaded
+// through it instead of from the server.
+//
+// # Privacy Mode
+//
+// SetPrivacyMode takes the overlays whose tiles come straight from online
+// services (light pollution and clouds) off the map and out of the layer
+// control, and turns down locate requests, as the web engine's geolocation
+// asks a network location service. The base layer keeps working from the
+// tile cache.
+//
 // # Embedded HTML
 //
 // The complete map HTML (including Leaflet library references) is embedded
@@ -88,6 +277,61 @@ type MapView struct {
 	// The callback receives the latitude and longitude of the clicked point.
 	onMapClick func(lat, lon float64)
 
+	// onMarkerDrag is the callback invoked when the user drops the location
+	// marker after dragging it. The callback receives the drop point.
+	onMarkerDrag func(lat, lon float64)
+
+	// onPointClick is the callback invoked when the user clicks a point of a
+	// point layer (see SetPoints).
+	onPointClick func(point domain.MapPoint)
+
+	// onMeasure is the callback invoked when the user completes a measurement.
+	// The callback receives the start and end points of the measured line.
+	onMeasure func(fromLat, fromLon, toLat, toLon float64)
+
+	// onAlign is the callback invoked when the user picks an alignment pair.
+	// The callback receives the camera point followed by the subject point.
+	onAlign func(camLat, camLon, subLat, subLon float64)
+
+	// onPlaceCamera is the callback invoked when the user places a camera.
+	// The callback receives the camera point and a point it faces.
+	onPlaceCamera func(camLat, camLon, dirLat, dirLon float64)
+
+	// onZoomChange is the callback invoked when the map's zoom level changes,
+	// whether from the user (scroll, +/- buttons) or from a Go command.
+	onZoomChange func(zoom int)
+
+	// onFullscreenToggle is the callback invoked when the user clicks the
+	// map's full-screen button.
+	onFullscreenToggle func()
+
+	// onLocate is the callback invoked when a locate request finishes, with
+	// the position or the reason it failed.
+	onLocate func(lat, lon float64, err error)
+
+	// onCursorMove is the callback invoked when the pointer moves over the
+	// map; ok is false once it has left the map.
+	onCursorMove func(lat, lon float64, ok bool)
+
+	// onCloudsShown is the callback invoked when the user turns on the
+	// cloud layer, which then waits for SetCloudFrames.
+	onCloudsShown func()
+
+	// locating is true while a locate request waits for the page.
+	// Geolocation permission is only granted during this time.
+	locating bool
+
+	// locateTimer ends a locate request the page never answers (e.g., when
+	// the page script failed to load offline).
+	locateTimer *qt.QTimer
+
+	// privacy is true in privacy mode (see SetPrivacyMode).
+	privacy bool
+
+	// overlay is a label drawn on top of the map (hidden when empty).
+	// Used for the key times summary in full-screen mode.
+	overlay *qt.QLabel
+
 	// ready indicates whether the map has finished loading.
 	// Set to true when the OnLoadFinished signal fires with ok=true.
 	ready bool
@@ -97,29 +341,144 @@ type MapView struct {
 	currentLat float64
 	currentLon float64
 
+	// bounds is the visible area as last reported by JavaScript; boundsKnown
+	// is false until the first report.
+	bounds      domain.Bounds
+	boundsKnown bool
+
+	// currentZoom tracks the map's zoom level as last reported by JavaScript.
+	// SetLocation keeps this zoom instead of resetting to the default.
+	currentZoom int
+
 	// baseURL is the data URL containing the map HTML.
-	// Location updates append a hash fragment: baseURL#lat,lon,zoom
+	// Commands are sent by appending a hash fragment: baseURL#!seq;cmd
 	baseURL string
+
+	// pendingCommands holds commands queued since the last flush.
+	pendingCommands []string
+
+	// commandSeq numbers command batches so each produces a unique hash.
+	commandSeq int
+
+	// flushTimer is a single-shot timer that sends pendingCommands to the page.
+	// While awaitingAck is set it doubles as a watchdog, so a lost MAPACK
+	// cannot stall the queue.
+	flushTimer *qt.QTimer
+
+	// awaitingAck is true while the last batch sent waits for the page's
+	// MAPACK; new commands are queued until then.
+	awaitingAck bool
+
+	// pointLayers tracks what the page holds for each point layer, keyed by
+	// layer title (see SetPoints).
+	pointLayers map[string]*pointLayer
+
+	// nextPointHandle is the next handle to assign to a point sent to the page.
+	nextPointHandle int
+}
+
+// pointLayer is the Go-side record of a point layer in the map page.
+type pointLayer struct {
+	// id identifies the layer in page commands.
+	id int
+
+	// points maps each point's ID to the handle and data sent to the page.
+	points map[string]sentPoint
+}
+
+// sentPoint is a point that has been sent to the map page.
+type sentPoint struct {
+	// handle identifies the point in page commands (unique across layers).
+	handle int
+
+	// point is the data that was sent, used to detect changes.
+	point domain.MapPoint
 }
 
 // defaultZoom is the initial and default zoom level for the map.
 // Zoom level 13 shows approximately city-level detail (a few kilometers).
 const defaultZoom = 13
 
-// NewMapView creates a new map view widget with the given click handler.
+// overlayLeft and overlayTop position the overlay label, in pixels from the
+// top-left corner of the map. This clears Leaflet's zoom and tool controls.
+const (
+	overlayLeft = 56
+	overlayTop  = 10
+)
+
+// maxBatchLength limits the size of one command batch, in bytes.
+//
+// Large hashes are slow to set and parse, and the page can't render while it
+// runs a batch, so big transfers are split into several acknowledged batches.
+const maxBatchLength = 64 * 1024
+
+// ackTimeout is how long to wait for MAPACK before sending the next batch anyway.
+const ackTimeout = 1000 // milliseconds
+
+// locateTimeout is how long a locate request may wait for the page. It is a
+// little longer than the page's own Geolocation API timeout (30 s), so the
+// page normally reports the failure itself.
+const locateTimeout = 35000 // milliseconds
+
+// fovArcStep is the maximum angle between points on the cone's arc, in degrees.
+const fovArcStep = 2.0
+
+// fovRayScale is the length of horizon event rays relative to the cone.
+const fovRayScale = 1.2
+
+// pointChunkSize is the maximum number of points in one points/pointremove command.
+const pointChunkSize = 250
+
+// trackChunkSize is the maximum number of coordinates in one track command.
+const trackChunkSize = 1000
+
+// minZoom and maxZoom are the zoom levels supported by the OpenStreetMap tiles.
+const (
+	minZoom = 1
+	maxZoom = 19
+)
+
+// NewMapView creates a new map view widget with the given handlers.
 //
 // Parameters:
 //   - onMapClick: Callback invoked when user clicks on the map (lat, lon)
+//   - onMarkerDrag: Callback invoked when user drops the dragged location marker
+//   - onPointClick: Callback invoked when user clicks a point of a point layer
+//   - onMeasure: Callback invoked when user measures between two points
+//   - onAlign: Callback invoked when user picks camera and subject points
+//   - onPlaceCamera: Callback invoked when user places a camera and its direction
+//   - onZoomChange: Callback invoked when the zoom level changes
+//   - onFullscreenToggle: Callback invoked when user clicks the full-screen button
+//   - onLocate: Callback invoked when a locate request succeeds or fails
+//   - onCursorMove: Callback invoked when the pointer moves over or leaves the map
+//   - onCloudsShown: Callback invoked when user turns on the cloud layer
 //
 // Returns a fully initialized MapView ready to be added to a layout.
 // The map initially shows London (51.5074, -0.1278) until SetLocation is called.
-func NewMapView(onMapClick func(lat, lon float64)) *MapView {
+func NewMapView(onMapClick, onMarkerDrag func(lat, lon float64), onPointClick func(point domain.MapPoint),
+	onMeasure func(fromLat, fromLon, toLat, toLon float64),
+	onAlign func(camLat, camLon, subLat, subLon float64),
+	onPlaceCamera func(camLat, camLon, dirLat, dirLon float64), onZoomChange func(zoom int),
+	onFullscreenToggle func(), onLocate func(lat, lon float64, err error),
+	onCursorMove func(lat, lon float64, ok bool), onCloudsShown func()) *MapView {
 	mv := &MapView{
 		// NewQWebEngineView2(): No-param constructor (suffix "2")
-		view:       we.NewQWebEngineView2(),
-		onMapClick: onMapClick,
-		currentLat: 51.5074, // Default: London
-		currentLon: -0.1278,
+		view:               we.NewQWebEngineView2(),
+		onMapClick:         onMapClick,
+		onMarkerDrag:       onMarkerDrag,
+		onPointClick:       onPointClick,
+		onMeasure:          onMeasure,
+		onAlign:            onAlign,
+		onPlaceCamera:      onPlaceCamera,
+		onZoomChange:       onZoomChange,
+		onFullscreenToggle: onFullscreenToggle,
+		onLocate:           onLocate,
+		onCursorMove:       onCursorMove,
+		onCloudsShown:      onCloudsShown,
+		currentLat:         51.5074, // Default: London
+		currentLon:         -0.1278,
+		currentZoom:        defaultZoom,
+		pointLayers:        make(map[string]*pointLayer),
 	}
 
 	mv.setupView()
@@ -128,9 +487,8 @@ func NewMapView(onMapClick func(lat, lon float64)) *MapView {
 
 // buildLocationURL constructs a URL with location coordinates in the hash fragment.
 //
-// This is the key mechanism for updating the map location without a full page
-// reload. The JavaScript in the map HTML listens for 'hashchange' events and
-// updates the map view accordingly.
+// This is used for the initial page load. Later updates go through
+// sendCommand, which uses the command batch form of the hash.
 //
 // URL format: data:text/html;base64,...#latitude,longitude,zoom
 //
@@ -149,25 +507,33 @@ func (mv *MapView) setupView() {
 	// Set minimum size for the map
 	mv.view.SetMinimumSize2(400, 400)
 
+	// The page would keep Tab for its own controls, trapping keyboard users
+	// in the map; it gets the focus when clicked (then arrows pan, +/- zoom)
+	mv.view.SetFocusPolicy(qt.ClickFocus)
+
 	// Create a custom page directly (required for overriding virtual methods)
 	mv.page = we.NewQWebEnginePage()
 	mv.view.SetPage(mv.page)
 
-	// Intercept console messages for map click events
+	// Intercept console messages for map events (see protocol in type docs)
 	mv.page.OnJavaScriptConsoleMessage(func(super func(level we.QWebEnginePage__JavaScriptConsoleMessageLevel, message string, lineNumber int, sourceID string), level we.QWebEnginePage__JavaScriptConsoleMessageLevel, message string, lineNumber int, sourceID string) {
-		// Check for map click message
-		if strings.HasPrefix(message, "MAPCLICK:") {
-			parts := strings.Split(strings.TrimPrefix(message, "MAPCLICK:"), ",")
-			if len(parts) == 2 {
-				lat, err1 := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
-				lon, err2 := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
-				if err1 == nil && err2 == nil && mv.onMapClick != nil {
-					mv.onMapClick(lat, lon)
-				}
-			}
+		defer crash.Recover()
+		mv.handleConsoleMessage(message)
+		// Call parent handler for other messages. Cursor updates are skipped,
+		// as they would flood the log while the pointer moves.
+		if !strings.HasPrefix(message, "MAPCURSOR") {
+			super(level, message, lineNumber, sourceID)
+		}
+	})
+
+	// Geolocation is only allowed for locate requests (see Locate)
+	mv.page.OnFeaturePermissionRequested(func(securityOrigin *qt.QUrl, feature we.QWebEnginePage__Feature) {
+		defer crash.Recover()
+		policy := we.QWebEnginePage__PermissionDeniedByUser
+		if feature == we.QWebEnginePage__Geolocation && mv.locating {
+			policy = we.QWebEnginePage__PermissionGrantedByUser
 		}
-		// Call parent handler for other messages
-		super(level, message, lineNumber, sourceID)
+		mv.page.SetFeaturePermission(securityOrigin, feature, policy)
 	})
 
 	// Connect to load finished signal
@@ -175,10 +541,210 @@ func (mv *MapView) setupView() {
 		mv.ready = ok
 	})
 
+	// Single-shot timer batches commands issued in the same event loop pass.
+	// NewQTimer2(parent): Timer owned by the view (suffix "2")
+	mv.flushTimer = qt.NewQTimer2(mv.view.QObject)
+	mv.flushTimer.SetSingleShot(true)
+	mv.flushTimer.OnTimeout(crash.Guard(mv.flushCommands))
+
+	mv.locateTimer = qt.NewQTimer2(mv.view.QObject)
+	mv.locateTimer.SetSingleShot(true)
+	mv.locateTimer.OnTimeout(crash.Guard(func() {
+		mv.finishLocate(0, 0, errors.New("the map did not respond"))
+	}))
+
+	// Overlay label on top of the web view, right of Leaflet's zoom control.
+	// NewQLabel(parent): Child of the view so it moves with the map.
+	// Mouse events pass through to the map underneath.
+	mv.overlay = qt.NewQLabel(mv.view.QWidget)
+	mv.overlay.SetStyleSheet("background: rgba(0, 0, 0, 0.65); color: white; " +
+		"border-radius: 6px; padding: 6px 10px; font-size: 12px;")
+	mv.overlay.SetAttribute(qt.WA_TransparentForMouseEvents)
+	mv.overlay.Move(overlayLeft, overlayTop)
+	mv.overlay.Hide()
+
 	// Load the map HTML
 	mv.loadMapHTML()
 }
 
+// handleConsoleMessage dispatches a console message from the map JavaScript.
+//
+// Messages that don't match a known prefix are ignored, so regular
+// console.log output from Leaflet or the page is harmless.
+func (mv *MapView) handleConsoleMessage(message string) {
+	switch {
+	case strings.HasPrefix(message, "MAPCLICK:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPCLICK:"), 2)
+		if ok && mv.onMapClick != nil {
+			mv.onMapClick(coords[0], coords[1])
+		}
+
+	case strings.HasPrefix(message, "MAPDRAG:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPDRAG:"), 2)
+		if ok && mv.onMarkerDrag != nil {
+			mv.onMarkerDrag(coords[0], coords[1])
+		}
+
+	case strings.HasPrefix(message, "MAPPOINT:"):
+		handle, err := strconv.Atoi(strings.TrimPrefix(message, "MAPPOINT:"))
+		if err != nil {
+			return
+		}
+		if point, ok := mv.pointByHandle(handle); ok && mv.onPointClick != nil {
+			mv.onPointClick(point)
+		}
+
+	case strings.HasPrefix(message, "MAPMEASURE:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPMEASURE:"), 4)
+		if ok && mv.onMeasure != nil {
+			mv.onMeasure(coords[0], coords[1], coords[2], coords[3])
+		}
+
+	case strings.HasPrefix(message, "MAPALIGN:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPALIGN:"), 4)
+		if ok && mv.onAlign != nil {
+			mv.onAlign(coords[0], coords[1], coords[2], coords[3])
+		}
+
+	case strings.HasPrefix(message, "MAPACK:"):
+		seq, err := strconv.Atoi(strings.TrimPrefix(message, "MAPACK:"))
+		if err == nil && mv.awaitingAck && seq == mv.commandSeq {
+			mv.flushTimer.Stop()
+			mv.flushCommands()
+		}
+
+	case strings.HasPrefix(message, "MAPCAMERA:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPCAMERA:"), 4)
+		if ok && mv.onPlaceCamera != nil {
+			mv.onPlaceCamera(coords[0], coords[1], coords[2], coords[3])
+		}
+
+	case message == "MAPLOCATE":
+		mv.Locate()
+
+	case strings.HasPrefix(message, "MAPLOCATED:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPLOCATED:"), 3)
+		if ok {
+			mv.finishLocate(coords[0], coords[1], nil)
+		}
+
+	case strings.HasPrefix(message, "MAPLOCATEERROR:"):
+		mv.finishLocate(0, 0, errors.New(strings.TrimPrefix(message, "MAPLOCATEERROR:")))
+
+	case message == "MAPFULLSCREEN":
+		if mv.onFullscreenToggle != nil {
+			mv.onFullscreenToggle()
+		}
+
+	case strings.HasPrefix(message, "MAPCURSOR:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPCURSOR:"), 2)
+		if ok && mv.onCursorMove != nil {
+			mv.onCursorMove(coords[0], coords[1], true)
+		}
+
+	case message == "MAPCLOUDS":
+		if mv.onCloudsShown != nil {
+			mv.onCloudsShown()
+		}
+
+	case message == "MAPCURSOROUT":
+		if mv.onCursorMove != nil {
+			mv.onCursorMove(0, 0, false)
+		}
+
+	case strings.HasPrefix(message, "MAPBOUNDS:"):
+		coords, ok := parseCoordinateList(strings.TrimPrefix(message, "MAPBOUNDS:"), 4)
+		if ok {
+			mv.bounds = domain.Bounds{South: coords[0], West: coords[1], North: coords[2], East: coords[3]}
+			mv.boundsKnown = true
+		}
+
+	case strings.HasPrefix(message, "MAPZOOM:"):
+		zoom, err := strconv.Atoi(strings.TrimPrefix(message, "MAPZOOM:"))
+		if err == nil && zoom != mv.currentZoom {
+			mv.currentZoom = zoom
+			if mv.onZoomChange != nil {
+				mv.onZoomChange(zoom)
+			}
+		}
+	}
+}
+
+// sendCommand queues a command for the map page.
+//
+// Commands are delivered in order on the next event loop iteration (see
+// flushCommands). Queuing never blocks, and commands sent before the page
+// has finished loading are applied once its script runs.
+//
+// Parameters:
+//   - command: A command from the protocol in the type docs (e.g., "zoomin")
+func (mv *MapView) sendCommand(command string) {
+	mv.pendingCommands = append(mv.pendingCommands, command)
+	if !mv.awaitingAck && !mv.flushTimer.IsActive() {
+		mv.flushTimer.Start(0)
+	}
+}
+
+// flushCommands sends queued commands to the page as one hash change.
+//
+// Hash format: #!seq;cmd1;cmd2;...
+//
+// At most maxBatchLength bytes of commands are sent at once (but always at
+// least one command). Commands that remain or are queued later are held
+// until the page reports MAPACK for this batch, or until ackTimeout passes
+// (e.g., if the page script failed to load). Waiting for the page avoids a
+// second URL change replacing a batch it hasn't run yet.
+func (mv *MapView) flushCommands() {
+	mv.awaitingAck = false
+	if len(mv.pendingCommands) == 0 {
+		return
+	}
+
+	n, size := 0, 0
+	for n < len(mv.pendingCommands) {
+		size += len(mv.pendingCommands[n]) + 1
+		if n > 0 && size > maxBatchLength {
+			break
+		}
+		n++
+	}
+
+	mv.commandSeq++
+	hash := fmt.Sprintf("!%d;%s", mv.commandSeq, strings.Join(mv.pendingCommands[:n], ";"))
+	mv.pendingCommands = mv.pendingCommands[n:]
+
+	mv.page.SetUrl(qt.NewQUrl3(mv.baseURL + "#" + hash))
+
+	mv.awaitingAck = true
+	mv.flushTimer.Start(ackTimeout)
+}
+
+// encodeText encodes free text for a page command (unpadded URL-safe base64).
+func encodeText(text string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(text))
+}
+
+// parseCoordinateList parses a comma-separated list of exactly n float values.
+//
+// Returns the parsed values and true on success, or nil and false if the
+// count doesn't match or any value fails to parse.
+func parseCoordinateList(payload string, n int) ([]float64, bool) {
+	parts := strings.Split(payload, ",")
+	if len(parts) != n {
+		return nil, false
+	}
+
+	values := make([]float64, n)
+	for i, part := range parts {
+		v, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, false
+		}
+		values[i] = v
+	}
+	return values, true
+}
+
 // loadMapHTML loads the map HTML content using data URL
 func (mv *MapView) loadMapHTML() {
 	html := mv.createMapHTML()
@@ -210,15 +776,72 @@ func (mv *MapView) createMapHTML() string {
             width: 20px;
             height: 20px;
         }
+        .measure-control a {
+            font-size: 16px;
+            line-height: 30px;
+            text-align: center;
+            text-decoration: none;
+            cursor: pointer;
+        }
+        .measure-control a.active { background: #ff9800; color: #fff; }
+        .point-marker {
+            background: #4caf50;
+            border: 2px solid #fff;
+            border-radius: 50%;
+            box-shadow: 0 1px 6px rgba(0, 0, 0, 0.3);
+        }
+        .point-cluster {
+            background: rgba(76, 175, 80, 0.85);
+            border: 2px solid #fff;
+            border-radius: 50%;
+            box-shadow: 0 1px 6px rgba(0, 0, 0, 0.3);
+            color: #fff;
+            font: bold 12px sans-serif;
+            text-align: center;
+        }
+        .profile-panel {
+            display: none;
+            background: rgba(255, 255, 255, 0.92);
+            border-radius: 4px;
+            box-shadow: 0 1px 5px rgba(0, 0, 0, 0.4);
+            padding: 6px 8px;
+            font: 12px sans-serif;
+        }
+        .profile-panel .profile-text { white-space: pre-line; margin-right: 16px; }
+        .profile-panel .profile-close { float: right; cursor: pointer; font-weight: bold; }
+        .minimap {
+            width: 160px;
+            height: 110px;
+            border: 2px solid #fff;
+            border-radius: 4px;
+            box-shadow: 0 1px 5px rgba(0, 0, 0, 0.4);
+            cursor: pointer;
+        }
+        .cloud-panel {
+            display: none;
+            background: rgba(255, 255, 255, 0.92);
+            border-radius: 4px;
+            box-shadow: 0 1px 5px rgba(0, 0, 0, 0.4);
+            padding: 4px 8px;
+            font: bold 12px sans-serif;
+        }
+        .cloud-panel.golden { background: #ffcc80; }
+        .measure-point {
+            background: #2196f3;
+            border: 2px solid #fff;
+            border-radius: 50%;
+            width: 10px;
+            height: 10px;
+        }
     </style>
 </head>
 <body>
     <div id="map"></div>
     <script>
-        // Parse initial coordinates from URL hash
+        // Parse initial coordinates from URL hash (plain #lat,lon,zoom form)
         function parseHash() {
             var hash = window.location.hash.substring(1);
-            if (hash) {
+            if (hash && hash.charAt(0) !== '!') {
                 var parts = hash.split(',');
                 if (parts.length >= 2) {
                     var lat = parseFloat(parts[0]);
@@ -238,11 +861,13 @@ func (mv *MapView) createMapHTML() string {
         // Initialize map
         var map = L.map('map').setView([initial.lat, initial.lon], initial.zoom);
 
-        // Add OpenStreetMap tiles
-        L.tileLayer('https://tile.openstreetmap.org/{z}/{x}/{y}.png', {
-            maxZoom: 19,
-            attribution: '© <a href="https://www.openstreetmap.org/copyright">OpenStreetMap</a>'
-        }).addTo(map);
+        // Base layer: not shown until Go selects the server (or the tile
+        // cache) and sends its provider's attribution, so no tile is loaded
+        // from a server that isn't used
+        var osmTileURL = 'https://tile.openstreetmap.org/{z}/{x}/{y}.png';
+        var tileURL = osmTileURL;
+        var tileOptions = { maxZoom: 19 };
+        var baseLayer = L.tileLayer(tileURL, tileOptions);
 
         // Custom icon for the marker
         var goldenIcon = L.divIcon({
@@ -251,8 +876,45 @@ func (mv *MapView) createMapHTML() string {
             iconAnchor: [10, 10]
         });
 
-        // Add initial marker
-        var currentMarker = L.marker([initial.lat, initial.lon], {icon: goldenIcon}).addTo(map);
+        // Add initial marker; dropping it after a drag selects the drop point
+        var currentMarker = L.marker([initial.lat, initial.lon], {icon: goldenIcon, draggable: true}).addTo(map);
+        currentMarker.on('dragend', function() {
+            locateLayer.clearLayers();
+            // Longitudes are wrapped, as the marker may be dropped on a world copy
+            var p = currentMarker.getLatLng().wrap();
+            currentMarker.setLatLng(p);
+            console.log('MAPDRAG:' + p.lat + ',' + p.lng);
+        });
+
+        // Switch the base layer (and the mini-map's) to a tile server; an
+        // empty url restores OpenStreetMap. Leaflet fills {s} from the
+        // subdomains option and {apikey} from the option of that name.
+        // Tiles beyond the provider's maxNativeZoom are scaled up.
+        function setTileServer(url, subdomains, apikey, attribution, maxNativeZoom) {
+            tileURL = url || osmTileURL;
+            tileOptions = {
+                maxZoom: 19, maxNativeZoom: Math.min(maxNativeZoom || 19, 19),
+                attribution: attribution
+            };
+            if (url) {
+                tileOptions.subdomains = subdomains;
+                tileOptions.apikey = apikey;
+            }
+            map.removeLayer(baseLayer);
+            baseLayer = L.tileLayer(tileURL, tileOptions).addTo(map);
+            baseLayer.bringToBack();
+            miniMap.removeLayer(miniBaseLayer);
+            miniBaseLayer = L.tileLayer(tileURL, miniTileOptions()).addTo(miniMap);
+            miniBaseLayer.bringToBack();
+        }
+
+        function setMarkerName(name) {
+            if (name) {
+                currentMarker.bindTooltip(name);
+            } else {
+                currentMarker.unbindTooltip();
+            }
+        }
 
         // Update marker and center map
         function setLocation(lat, lon, zoom) {
@@ -260,20 +922,729 @@ func (mv *MapView) createMapHTML() string {
             map.setView([lat, lon], zoom || map.getZoom());
         }
 
-        // Handle hash changes (location updates from Go)
-        window.addEventListener('hashchange', function() {
-            var pos = parseHash();
-            setLocation(pos.lat, pos.lon, pos.zoom);
+        // Day/night terminator: stacked translucent twilight bands from Go.
+        // Each band is drawn three times so it covers neighboring world copies.
+        var terminatorLayer = L.layerGroup().addTo(map);
+        var terminatorBands = [];
+        var layerControl = L.control.layers(null, { 'Day/night': terminatorLayer }).addTo(map);
+
+        // Light pollution: NASA Black Marble (VIIRS night lights) from GIBS,
+        // not shown until enabled in the layer control
+        var lightPollutionLayer = L.tileLayer(
+            'https://gibs.earthdata.nasa.gov/wmts/epsg3857/best/VIIRS_Black_Marble/default/2016-01-01/GoogleMapsCompatible_Level8/{z}/{y}/{x}.png', {
+            maxNativeZoom: 8,
+            maxZoom: 19,
+            opacity: 0.7,
+            attribution: 'Night lights: <a href="https://earthdata.nasa.gov/gibs">NASA GIBS</a> Black Marble (VIIRS)'
+        });
+        layerControl.addOverlay(lightPollutionLayer, 'Light pollution');
+
+        function setTerminatorBand(index, coords) {
+            if (terminatorBands[index]) {
+                terminatorLayer.removeLayer(terminatorBands[index]);
+            }
+            var rings = [];
+            [-360, 0, 360].forEach(function(offset) {
+                var ring = [];
+                for (var i = 0; i + 1 < coords.length; i += 2) {
+                    ring.push([coords[i], coords[i + 1] + offset]);
+                }
+                rings.push([ring]);
+            });
+            terminatorBands[index] = L.polygon(rings, {
+                stroke: false, fillColor: '#001030', fillOpacity: 0.15, interactive: false
+            }).addTo(terminatorLayer);
+        }
+
+        // Locate: browser Geolocation API, shown with an accuracy circle.
+        // Go grants the permission while a request is in progress.
+        var locateLayer = L.layerGroup().addTo(map);
+
+        function locate() {
+            if (!navigator.geolocation) {
+                console.log('MAPLOCATEERROR:geolocation is not supported by the web engine');
+                return;
+            }
+            navigator.geolocation.getCurrentPosition(function(pos) {
+                var c = pos.coords;
+                locateLayer.clearLayers();
+                L.circle([c.latitude, c.longitude], {
+                    radius: c.accuracy, color: '#2196f3', weight: 1, fillOpacity: 0.1, interactive: false
+                }).addTo(locateLayer);
+                console.log('MAPLOCATED:' + c.latitude + ',' + c.longitude + ',' + c.accuracy);
+            }, function(err) {
+                console.log('MAPLOCATEERROR:' + (err.message || 'position unavailable'));
+            }, { enableHighAccuracy: true, timeout: 30000, maximumAge: 60000 }); // see locateTimeout
+        }
+
+        // Elevation profile of a measured line: terrain heights from Go,
+        // drawn as an SVG chart with the line of sight to the horizon
+        var profilePanel = null;
+        var ProfileControl = L.Control.extend({
+            options: { position: 'bottomleft' },
+            onAdd: function() {
+                profilePanel = L.DomUtil.create('div', 'profile-panel');
+                var close = L.DomUtil.create('span', 'profile-close', profilePanel);
+                close.innerHTML = '&times;';
+                L.DomEvent.on(close, 'click', hideProfile);
+                L.DomUtil.create('div', 'profile-text', profilePanel);
+                L.DomEvent.disableClickPropagation(profilePanel);
+                L.DomEvent.disableScrollPropagation(profilePanel);
+                return profilePanel;
+            }
+        });
+        map.addControl(new ProfileControl());
+
+        function showProfile(total, horizon, eye, text, heights) {
+            var w = 260, h = 90, pad = 4;
+            var lo = Math.min(eye, Math.min.apply(null, heights));
+            var hi = Math.max(eye, Math.max.apply(null, heights));
+            if (hi - lo < 10) {
+                hi = lo + 10;
+            }
+            function x(i) { return pad + (w - 2 * pad) * i / (heights.length - 1); }
+            function y(e) { return h - pad - (h - 2 * pad) * (e - lo) / (hi - lo); }
+
+            var terrain = 'M' + x(0) + ',' + h;
+            heights.forEach(function(e, i) { terrain += ' L' + x(i) + ',' + y(e); });
+            terrain += ' L' + x(heights.length - 1) + ',' + h + ' Z';
+
+            var svg = '<svg width="' + w + '" height="' + h + '">' +
+                '<path d="' + terrain + '" fill="#8d6e63" fill-opacity="0.6" stroke="#5d4037"/>' +
+                '<line x1="' + x(0) + '" y1="' + y(eye) + '" x2="' + x(horizon) + '" y2="' + y(heights[horizon]) +
+                '" stroke="#ff9800" stroke-width="2" stroke-dasharray="4 3"/>' +
+                '<text x="' + (w - pad) + '" y="12" text-anchor="end" font-size="10">' +
+                (total / 1000).toFixed(1) + ' km</text></svg>';
+
+            var old = profilePanel.querySelector('svg');
+            if (old) {
+                old.remove();
+            }
+            profilePanel.querySelector('.profile-text').textContent = text;
+            profilePanel.insertAdjacentHTML('beforeend', svg);
+            profilePanel.style.display = 'block';
+        }
+
+        function hideProfile() {
+            if (profilePanel) {
+                profilePanel.style.display = 'none';
+            }
+        }
+
+        // Camera field of view: cone and horizon event rays computed in Go
+        var cameraLayer = L.layerGroup().addTo(map);
+
+        function setCameraCone(coords) {
+            var ring = [];
+            for (var i = 0; i + 1 < coords.length; i += 2) {
+                ring.push([coords[i], coords[i + 1]]);
+            }
+            L.polygon(ring, {
+                color: '#9c27b0', weight: 1, fillOpacity: 0.15, interactive: false
+            }).addTo(cameraLayer);
+            L.circleMarker(ring[0], {
+                radius: 6, color: '#fff', weight: 2, fillColor: '#9c27b0', fillOpacity: 1
+            }).bindTooltip('Camera').addTo(cameraLayer);
+        }
+
+        function addCameraRay(moon, inView, c, name) {
+            L.polyline([[c[0], c[1]], [c[2], c[3]]], {
+                color: moon ? '#607d8b' : '#ff9800',
+                weight: inView ? 3 : 2,
+                dashArray: inView ? null : '4 6'
+            }).bindTooltip(name + (inView ? ' (in view)' : ' (outside view)')).addTo(cameraLayer);
+        }
+
+        // Evening golden hour sun path: a sector from the location between
+        // the sun's azimuths from Go. Its radius follows the view (a third
+        // of the smaller map side), so it is redrawn after zooming.
+        var sunPathLayer = L.layerGroup().addTo(map);
+        layerControl.addOverlay(sunPathLayer, 'Golden hour sun path');
+        var sunPath = null;
+
+        function setSunPath(lat, lon, startText, endText, azimuths) {
+            sunPath = azimuths ? {
+                center: L.latLng(lat, lon), startText: startText, endText: endText, azimuths: azimuths
+            } : null;
+            drawSunPath();
+        }
+
+        function destinationPoint(from, bearing, meters) {
+            var r = 6371008.8;
+            var d = meters / r;
+            var b = bearing * Math.PI / 180;
+            var lat1 = from.lat * Math.PI / 180;
+            var lon1 = from.lng * Math.PI / 180;
+            var lat2 = Math.asin(Math.sin(lat1) * Math.cos(d) + Math.cos(lat1) * Math.sin(d) * Math.cos(b));
+            var lon2 = lon1 + Math.atan2(Math.sin(b) * Math.sin(d) * Math.cos(lat1),
+                Math.cos(d) - Math.sin(lat1) * Math.sin(lat2));
+            return L.latLng(lat2 * 180 / Math.PI, lon2 * 180 / Math.PI);
+        }
+
+        function drawSunPath() {
+            sunPathLayer.clearLayers();
+            if (!sunPath) {
+                return;
+            }
+            var size = map.getSize();
+            var tip = map.latLngToContainerPoint(sunPath.center);
+            var edge = map.containerPointToLatLng([tip.x + Math.min(size.x, size.y) / 3, tip.y]);
+            var radius = sunPath.center.distanceTo(edge);
+
+            var ring = [sunPath.center];
+            sunPath.azimuths.forEach(function(az) {
+                ring.push(destinationPoint(sunPath.center, az, radius));
+            });
+            L.polygon(ring, {
+                color: '#ff9800', weight: 1, fillColor: '#ffc107', fillOpacity: 0.25, interactive: false
+            }).addTo(sunPathLayer);
+            L.polyline([sunPath.center, ring[1]], {color: '#ffb300', weight: 3})
+                .bindTooltip(sunPath.startText).addTo(sunPathLayer);
+            L.polyline([sunPath.center, ring[ring.length - 1]], {color: '#e65100', weight: 3})
+                .bindTooltip(sunPath.endText).addTo(sunPathLayer);
+        }
+        map.on('zoomend resize', drawSunPath);
+
+        // Sun position at the time slider's time: a ray from the location
+        // toward the sun's azimuth, ending in a sun that is yellow while up
+        // and grey below the horizon. Sized and redrawn like the sun path.
+        var sunPosLayer = L.layerGroup().addTo(map);
+        layerControl.addOverlay(sunPosLayer, 'Sun position (time slider)');
+        var sunPos = null;
+
+        function setSunPosition(lat, lon, azimuth, elevation, text) {
+            sunPos = text !== undefined ? {
+                center: L.latLng(lat, lon), azimuth: azimuth, elevation: elevation, text: text
+            } : null;
+            drawSunPosition();
+        }
+
+        function drawSunPosition() {
+            sunPosLayer.clearLayers();
+            if (!sunPos) {
+                return;
+            }
+            var size = map.getSize();
+            var tip = map.latLngToContainerPoint(sunPos.center);
+            var edge = map.containerPointToLatLng([tip.x + Math.min(size.x, size.y) / 3, tip.y]);
+            var end = destinationPoint(sunPos.center, sunPos.azimuth, sunPos.center.distanceTo(edge));
+            var color = sunPos.elevation > 0 ? '#fbc02d' : '#78909c';
+            L.polyline([sunPos.center, end], {color: color, weight: 2, dashArray: '6 4', interactive: false})
+                .addTo(sunPosLayer);
+            L.circleMarker(end, {radius: 9, color: color, weight: 2, fillColor: color, fillOpacity: 0.8})
+                .bindTooltip(sunPos.text).addTo(sunPosLayer);
+        }
+        map.on('zoomend resize', drawSunPosition);
+
+        // Clouds: animated frames from Go, requested each time the overlay
+        // is enabled in the layer control. Every frame has its own tile
+        // layer and all but the current one are transparent, so the
+        // animation doesn't wait for tiles once they have loaded.
+        var cloudLayer = L.layerGroup();
+        layerControl.addOverlay(cloudLayer, 'Clouds (animated)');
+        var cloudFrames = [];
+        var cloudIndex = 0;
+        var cloudTimer = null;
+        var cloudInterval = 700;    // Time per frame in milliseconds
+        var cloudPanel = null;
+        var CloudControl = L.Control.extend({
+            options: { position: 'bottomleft' },
+            onAdd: function() {
+                cloudPanel = L.DomUtil.create('div', 'cloud-panel');
+                return cloudPanel;
+            }
+        });
+        map.addControl(new CloudControl());
+
+        function setCloudFrames(maxNativeZoom, attribution, fields) {
+            cloudLayer.clearLayers();
+            cloudFrames = [];
+            for (var i = 0; i + 2 < fields.length; i += 3) {
+                var layer = L.tileLayer(decodeText(fields[i]), {
+                    maxNativeZoom: maxNativeZoom, maxZoom: 19, opacity: 0, attribution: attribution
+                }).addTo(cloudLayer);
+                cloudFrames.push({ layer: layer, label: decodeText(fields[i + 1]), golden: fields[i + 2] === '1' });
+            }
+            cloudIndex = 0;
+            showCloudFrame();
+            if (cloudTimer === null && cloudFrames.length > 1 && map.hasLayer(cloudLayer)) {
+                cloudTimer = setInterval(nextCloudFrame, cloudInterval);
+            }
+        }
+
+        function showCloudFrame() {
+            cloudFrames.forEach(function(f, i) {
+                f.layer.setOpacity(i === cloudIndex ? 0.65 : 0);
+            });
+            var frame = cloudFrames[cloudIndex];
+            cloudPanel.style.display = frame && map.hasLayer(cloudLayer) ? 'block' : 'none';
+            if (frame) {
+                cloudPanel.textContent = frame.label;
+                cloudPanel.classList.toggle('golden', frame.golden);
+            }
+        }
+
+        function nextCloudFrame() {
+            cloudIndex = (cloudIndex + 1) % cloudFrames.length;
+            showCloudFrame();
+        }
+
+        map.on('overlayadd', function(e) {
+            if (e.layer === cloudLayer) {
+                console.log('MAPCLOUDS');
+            }
+        });
+        map.on('overlayremove', function(e) {
+            if (e.layer === cloudLayer) {
+                clearInterval(cloudTimer);
+                cloudTimer = null;
+                cloudPanel.style.display = 'none';
+            }
+        });
+
+        // Privacy mode: the overlays loaded straight from online services
+        // are taken off the map (which stops the cloud animation) and out
+        // of the layer control, and put back when it ends
+        var onlineOverlays = [[lightPollutionLayer, 'Light pollution'], [cloudLayer, 'Clouds (animated)']];
+
+        function setPrivacy(on) {
+            onlineOverlays.forEach(function(overlay) {
+                map.removeLayer(overlay[0]);
+                layerControl.removeLayer(overlay[0]);
+                if (!on) {
+                    layerControl.addOverlay(overlay[0], overlay[1]);
+                }
+            });
+        }
+
+        // Point layers (favorites, photo spots, imports). Go sends each point
+        // once; the page keeps them all but only draws those near the view,
+        // merging neighbors into clusters on a pixel grid.
+        var pointLayers = {};
+        var clusterCellSize = 60;   // Grid cell size in pixels
+        var clusterMaxZoom = 16;    // Above this zoom every point is drawn
+        var renderPending = false;
+        var pointIcon = L.divIcon({
+            className: 'point-marker',
+            iconSize: [12, 12],
+            iconAnchor: [6, 6]
+        });
+
+        // Decode unpadded URL-safe base64 UTF-8 text from Go
+        function decodeText(text) {
+            var b64 = text.replace(/-/g, '+').replace(/_/g, '/');
+            while (b64.length % 4) {
+                b64 += '=';
+            }
+            try {
+                return decodeURIComponent(escape(atob(b64)));
+            } catch (e) {
+                return '';
+            }
+        }
+
+        // Each layer has one entry in the layer control; markers are
+        // re-rendered on every view change, while tracks stay as they are.
+        function addPointLayer(id, title) {
+            var group = L.layerGroup().addTo(map);
+            pointLayers[id] = {
+                group: group,
+                markers: L.layerGroup().addTo(group),
+                tracks: L.layerGroup().addTo(group),
+                points: {}
+            };
+            layerControl.addOverlay(group, title);
+        }
+
+        function addTrack(id, name, coords) {
+            var line = [];
+            for (var i = 0; i + 1 < coords.length; i += 2) {
+                line.push([coords[i], coords[i + 1]]);
+            }
+            var track = L.polyline(line, {color: '#e91e63', weight: 3, opacity: 0.8});
+            if (name) {
+                track.bindTooltip(name, {sticky: true});
+            }
+            track.addTo(pointLayers[id].tracks);
+        }
+
+        // fields: handle, lat, lon, name repeated
+        function addPoints(id, fields) {
+            var layer = pointLayers[id];
+            for (var i = 0; i + 3 < fields.length; i += 4) {
+                layer.points[fields[i]] = {
+                    handle: fields[i],
+                    lat: parseFloat(fields[i + 1]),
+                    lng: parseFloat(fields[i + 2]),
+                    name: decodeText(fields[i + 3])
+                };
+            }
+            scheduleRender();
+        }
+
+        function removePoints(id, handles) {
+            var layer = pointLayers[id];
+            handles.forEach(function(h) { delete layer.points[h]; });
+            scheduleRender();
+        }
+
+        // Render once after a burst of changes (e.g., several point chunks)
+        function scheduleRender() {
+            if (!renderPending) {
+                renderPending = true;
+                setTimeout(function() {
+                    renderPending = false;
+                    renderPoints();
+                }, 0);
+            }
+        }
+
+        function renderPoints() {
+            var zoom = map.getZoom();
+            var bounds = map.getBounds().pad(0.25);
+            for (var id in pointLayers) {
+                var layer = pointLayers[id];
+                layer.markers.clearLayers();
+                if (!map.hasLayer(layer.group)) {
+                    continue;
+                }
+                var cells = {};
+                for (var h in layer.points) {
+                    var p = layer.points[h];
+                    if (!bounds.contains([p.lat, p.lng])) {
+                        continue;
+                    }
+                    var key = h;
+                    if (zoom <= clusterMaxZoom) {
+                        var px = map.project([p.lat, p.lng], zoom);
+                        key = Math.floor(px.x / clusterCellSize) + ':' + Math.floor(px.y / clusterCellSize);
+                    }
+                    (cells[key] = cells[key] || []).push(p);
+                }
+                for (var cell in cells) {
+                    if (cells[cell].length === 1) {
+                        addPointMarker(layer.markers, cells[cell][0]);
+                    } else {
+                        addClusterMarker(layer.markers, cells[cell]);
+                    }
+                }
+            }
+        }
+
+        function addPointMarker(group, p) {
+            var marker = L.marker([p.lat, p.lng], {icon: pointIcon}).addTo(group);
+            if (p.name) {
+                marker.bindTooltip(p.name);
+            }
+            marker.on('click', function() {
+                if (pairMode) {
+                    addPairPoint(L.latLng(p.lat, p.lng));
+                    return;
+                }
+                currentMarker.setLatLng([p.lat, p.lng]);
+                console.log('MAPPOINT:' + p.handle);
+            });
+        }
+
+        function addClusterMarker(group, members) {
+            var lat = 0, lng = 0;
+            var bounds = L.latLngBounds([]);
+            members.forEach(function(m) {
+                lat += m.lat;
+                lng += m.lng;
+                bounds.extend([m.lat, m.lng]);
+            });
+            var size = members.length < 10 ? 28 : (members.length < 100 ? 34 : 40);
+            var icon = L.divIcon({
+                className: 'point-cluster',
+                html: '<div style="line-height: ' + size + 'px">' + members.length + '</div>',
+                iconSize: [size, size],
+                iconAnchor: [size / 2, size / 2]
+            });
+            var marker = L.marker([lat / members.length, lng / members.length], {icon: icon}).addTo(group);
+            marker.on('click', function() {
+                map.fitBounds(bounds, {padding: [40, 40]});
+            });
+        }
+
+        map.on('moveend overlayadd', scheduleRender);
+
+        // Run a single command from Go (see protocol in MapView docs)
+        function runCommand(command) {
+            try {
+                execCommand(command);
+            } catch (e) {
+                // Keep running the batch, so MAPACK is still sent
+                console.error('map command failed: ' + command.substring(0, 40) + ': ' + e);
+            }
+        }
+
+        function execCommand(command) {
+            var sep = command.indexOf(':');
+            var name = sep < 0 ? command : command.substring(0, sep);
+            var fields = sep < 0 ? [] : command.substring(sep + 1).split(',');
+            var args = fields.map(parseFloat);
+            switch (name) {
+                case 'view': setLocation(args[0], args[1], args[2]); break;
+                case 'markername': setMarkerName(decodeText(fields[0] || '')); break;
+                case 'tiles':
+                    setTileServer(decodeText(fields[0] || ''), decodeText(fields[1] || ''), decodeText(fields[2] || ''),
+                        decodeText(fields[3] || ''), args[4]);
+                    break;
+                case 'zoom': map.setZoom(args[0]); break;
+                case 'zoomin': map.zoomIn(); break;
+                case 'zoomout': map.zoomOut(); break;
+                case 'fitbounds':
+                    map.fitBounds([[args[0], args[1]], [args[2], args[3]]], {padding: [20, 20]});
+                    break;
+                case 'terminator': setTerminatorBand(args[0], args.slice(1)); break;
+                case 'fullscreen':
+                    // The button may not exist yet; its control reads fullscreenActive
+                    fullscreenActive = args[0] === 1;
+                    if (fullscreenButton) {
+                        fullscreenButton.classList.toggle('active', fullscreenActive);
+                    }
+                    // The map container changed size with the window layout
+                    map.invalidateSize();
+                    break;
+                case 'pointlayer': addPointLayer(args[0], decodeText(fields[1])); break;
+                case 'points': addPoints(args[0], fields.slice(1)); break;
+                case 'pointremove': removePoints(args[0], fields.slice(1)); break;
+                case 'track': addTrack(args[0], decodeText(fields[1]), args.slice(2)); break;
+                case 'trackclear': pointLayers[args[0]].tracks.clearLayers(); break;
+                case 'locate': locate(); break;
+                case 'profile':
+                    showProfile(args[0], args[1], args[2], decodeText(fields[3]), args.slice(4));
+                    break;
+                case 'profileclear': hideProfile(); break;
+                case 'fovcone': setCameraCone(args); break;
+                case 'fovray':
+                    addCameraRay(args[0] === 1, args[1] === 1, args.slice(2, 6), decodeText(fields[6] || ''));
+                    break;
+                case 'fovclear': cameraLayer.clearLayers(); break;
+                case 'sunpath':
+                    setSunPath(args[0], args[1], decodeText(fields[2]), decodeText(fields[3]), args.slice(4));
+                    break;
+                case 'sunpathclear': setSunPath(); break;
+                case 'sunpos': setSunPosition(args[0], args[1], args[2], args[3], decodeText(fields[4])); break;
+                case 'sunposclear': setSunPosition(); break;
+                case 'privacy': setPrivacy(args[0] === 1); break;
+                case 'clouds': setCloudFrames(args[0], decodeText(fields[1]), fields.slice(2)); break;
+            }
+        }
+
+        // Handle hash changes (command batches or plain location updates from Go).
+        // The hash is taken from the event's URL rather than the current
+        // location, and batches are run at most once, in sequence order.
+        var lastBatch = 0;
+        function applyHash(url) {
+            var at = url.indexOf('#');
+            var hash = at < 0 ? '' : decodeURIComponent(url.substring(at + 1));
+            if (hash.charAt(0) === '!') {
+                // First element is the batch sequence number
+                var commands = hash.split(';');
+                var seq = parseInt(commands[0].substring(1));
+                if (seq > lastBatch) {
+                    lastBatch = seq;
+                    commands.slice(1).forEach(runCommand);
+                }
+                console.log('MAPACK:' + seq);
+            } else if (hash) {
+                var pos = parseHash();
+                setLocation(pos.lat, pos.lon, pos.zoom);
+            }
+        }
+        window.addEventListener('hashchange', function(e) {
+            applyHash(e.newURL);
+        });
+
+        // Report zoom changes to Go so the level can be kept and persisted
+        map.on('zoomend', function() {
+            console.log('MAPZOOM:' + map.getZoom());
+        });
+
+        // Report the visible area to Go, for searches near the map view
+        map.on('moveend', function() {
+            var b = map.getBounds();
+            console.log('MAPBOUNDS:' + b.getSouth() + ',' + b.getWest() + ',' + b.getNorth() + ',' + b.getEast());
+        });
+
+        // Point-pair modes: two clicks define a line that is sent to Go.
+        //   'measure': Go computes distance/bearing (MAPMEASURE)
+        //   'align':   first point is the camera, second the subject (MAPALIGN)
+        //   'camera':  first point is the camera, second its direction (MAPCAMERA)
+        var pairModes = {
+            measure: { prefix: 'MAPMEASURE:', color: '#2196f3' },
+            align: { prefix: 'MAPALIGN:', color: '#ff9800' },
+            camera: { prefix: 'MAPCAMERA:', color: '#9c27b0' }
+        };
+        var pairMode = null;
+        var pairButtons = {};
+        var measurePoints = [];
+        var measureLayer = L.layerGroup().addTo(map);
+        var measureIcon = L.divIcon({
+            className: 'measure-point',
+            iconSize: [10, 10],
+            iconAnchor: [5, 5]
         });
 
+        function setPairMode(mode) {
+            pairMode = (pairMode === mode) ? null : mode;
+            measurePoints = [];
+            measureLayer.clearLayers();
+            hideProfile();
+            for (var key in pairButtons) {
+                pairButtons[key].classList.toggle('active', key === pairMode);
+            }
+            map.getContainer().style.cursor = pairMode ? 'crosshair' : '';
+        }
+
+        var PairControl = L.Control.extend({
+            options: { position: 'topleft' },
+            onAdd: function() {
+                var container = L.DomUtil.create('div', 'leaflet-bar measure-control');
+                var buttons = [
+                    { mode: 'measure', icon: '&#x1F4CF;', title: 'Measure distance and bearing' },
+                    { mode: 'align', icon: '&#x2600;', title: 'Find sun alignments (click camera, then subject)' },
+                    { mode: 'camera', icon: '&#x1F4F7;', title: 'Place camera (click camera, then the direction it faces)' }
+                ];
+                buttons.forEach(function(b) {
+                    var button = L.DomUtil.create('a', '', container);
+                    button.innerHTML = b.icon;
+                    button.title = b.title;
+                    pairButtons[b.mode] = button;
+                    L.DomEvent.on(button, 'click', function() { setPairMode(b.mode); });
+                });
+                L.DomEvent.disableClickPropagation(container);
+                return container;
+            }
+        });
+        map.addControl(new PairControl());
+
+        // Full-screen toggle: Go hides the side panels and reports back the state
+        var fullscreenButton = null;
+        var fullscreenActive = false;
+        var FullscreenControl = L.Control.extend({
+            options: { position: 'topleft' },
+            onAdd: function() {
+                var container = L.DomUtil.create('div', 'leaflet-bar measure-control');
+                fullscreenButton = L.DomUtil.create('a', '', container);
+                fullscreenButton.innerHTML = '&#x26F6;';
+                fullscreenButton.title = 'Full-screen map';
+                fullscreenButton.classList.toggle('active', fullscreenActive);
+                L.DomEvent.on(fullscreenButton, 'click', function() {
+                    console.log('MAPFULLSCREEN');
+                });
+                var locateButton = L.DomUtil.create('a', '', container);
+                locateButton.innerHTML = '&#x2316;';
+                locateButton.title = 'Show my location';
+                L.DomEvent.on(locateButton, 'click', function() {
+                    console.log('MAPLOCATE');
+                });
+                L.DomEvent.disableClickPropagation(container);
+                return container;
+            }
+        });
+        map.addControl(new FullscreenControl());
+
+        function addPairPoint(latlng) {
+            // A third click starts a new pair
+            if (measurePoints.length === 2) {
+                measurePoints = [];
+                measureLayer.clearLayers();
+                hideProfile();
+            }
+            measurePoints.push(latlng);
+            L.marker(latlng, {icon: measureIcon}).addTo(measureLayer);
+
+            if (measurePoints.length === 2) {
+                var a = measurePoints[0], b = measurePoints[1];
+                var mode = pairModes[pairMode];
+                L.polyline([a, b], {color: mode.color, weight: 3, dashArray: '6 6'}).addTo(measureLayer);
+                // Send the point pair to Go via console message
+                console.log(mode.prefix + a.lat + ',' + a.lng + ',' + b.lat + ',' + b.lng);
+            }
+        }
+
+        // Mini-map inset: a wide overview with the visible area outlined.
+        // It follows the main map; clicking it pans the main map there.
+        var miniMapZoomOffset = 5;
+        var MiniMapControl = L.Control.extend({
+            options: { position: 'bottomright' },
+            onAdd: function() {
+                var container = L.DomUtil.create('div', 'minimap');
+                L.DomEvent.disableClickPropagation(container);
+                L.DomEvent.disableScrollPropagation(container);
+                // The mini-map reports its own cursor position (see below)
+                L.DomEvent.on(container, 'mousemove', L.DomEvent.stopPropagation);
+                return container;
+            }
+        });
+        var miniMapControl = new MiniMapControl();
+        map.addControl(miniMapControl);
+        var miniMap = L.map(miniMapControl.getContainer(), {
+            attributionControl: false, zoomControl: false, dragging: false, keyboard: false,
+            scrollWheelZoom: false, doubleClickZoom: false, boxZoom: false, touchZoom: false
+        });
+        // Same tiles as the main map, without the attribution shown there
+        function miniTileOptions() {
+            var options = L.extend({}, tileOptions);
+            delete options.attribution;
+            return options;
+        }
+        var miniBaseLayer = L.tileLayer(tileURL, miniTileOptions());
+        var miniMapView = L.rectangle(map.getBounds(), {
+            color: '#ff5722', weight: 2, fillOpacity: 0.1, interactive: false
+        }).addTo(miniMap);
+
+        function updateMiniMap() {
+            miniMap.setView(map.getCenter(), Math.max(map.getZoom() - miniMapZoomOffset, 0), {animate: false});
+            miniMapView.setBounds(map.getBounds());
+        }
+        map.on('move zoomend resize', updateMiniMap);
+        miniMap.on('click', function(e) {
+            map.panTo(e.latlng);
+        });
+        updateMiniMap();
+
+        // Cursor position for the status bar, sent at most every cursorInterval
+        // ms. Longitudes are wrapped, as the map repeats across world copies.
+        var cursorInterval = 100;
+        var cursorLatLng = null;
+        var cursorTimer = null;
+        function reportCursor(latlng) {
+            cursorLatLng = latlng ? latlng.wrap() : null;
+            if (cursorTimer) {
+                return;
+            }
+            cursorTimer = setTimeout(function() {
+                cursorTimer = null;
+                console.log(cursorLatLng ? 'MAPCURSOR:' + cursorLatLng.lat + ',' + cursorLatLng.lng : 'MAPCURSOROUT');
+            }, cursorInterval);
+        }
+        map.on('mousemove', function(e) { reportCursor(e.latlng); });
+        miniMap.on('mousemove', function(e) { reportCursor(e.latlng); });
+        map.getContainer().addEventListener('mouseleave', function() { reportCursor(null); });
+
         // Handle map clicks - notify Go via console message
         map.on('click', function(e) {
+            if (pairMode) {
+                addPairPoint(e.latlng);
+                return;
+            }
+            locateLayer.clearLayers();
             var lat = e.latlng.lat;
             var lon = e.latlng.lng;
             currentMarker.setLatLng([lat, lon]);
             // Send click event to Go via console message
             console.log('MAPCLICK:' + lat + ',' + lon);
         });
+
+        // Commands sent before the page finished loading arrive in the initial
+        // hash. They run last, once every layer and control above exists.
+        if (window.location.hash.charAt(1) === '!') {
+            applyHash(window.location.href);
+        }
     </script>
 </body>
 </html>`
@@ -284,21 +1655,494 @@ func (mv *MapView) Widget() *qt.QWidget {
 	return mv.view.QWidget
 }
 
-// SetLocation updates the map location using hash fragment (no page reload)
+// Snapshot returns an image of the map as currently shown, with its
+// markers and overlays, e.g., for printing in a shoot plan.
+//
+// miqt API notes:
+//   - Grab() renders the widget (including the web engine's content)
+//     into a QPixmap
+func (mv *MapView) Snapshot() *qt.QPixmap {
+	return mv.view.Grab()
+}
+
+// SetLocation moves the marker and centers the map, keeping the current zoom.
+//
+// The zoom level the user last chose (or the one restored via SetZoom) is
+// preserved, so selecting a new location doesn't undo a zoom-out.
 func (mv *MapView) SetLocation(lat, lon float64) {
-	mv.currentLat = lat
-	mv.currentLon = lon
+	mv.CenterMap(lat, lon, mv.currentZoom)
+}
 
-	// Update via hash change to avoid full page reload
-	mv.page.SetUrl(qt.NewQUrl3(mv.buildLocationURL(lat, lon, defaultZoom)))
+// SetMarkerName sets the tooltip shown when hovering the location marker.
+//
+// An empty name removes the tooltip.
+func (mv *MapView) SetMarkerName(name string) {
+	mv.sendCommand("markername:" + encodeText(name))
+}
+
+// SetTileServer selects the server the base layer tiles are loaded from.
+//
+// The mini-map follows the main map. The zero TileServer restores the
+// OpenStreetMap tiles. The map credits the server's provider (see
+// TileServer.Provider) and scales up tiles beyond the provider's zoom
+// limit. The server should have been checked with TileServer.Validate; a
+// bad URL leaves the map without a base layer.
+//
+// Parameters:
+//   - server: The tile server (zero value: OpenStreetMap)
+//   - cacheURL: The URL template of the server's tiles in the tile cache
+//     (see tilecache.Path), which then loads them; empty to load the tiles
+//     from the server directly
+func (mv *MapView) SetTileServer(server domain.TileServer, cacheURL string) {
+	provider := server.Provider()
+	url, subdomains, apiKey := "", "", server.APIKey
+	switch {
+	case cacheURL != "":
+		// The cache fills in the subdomains and API key itself
+		url, apiKey = cacheURL, ""
+	case !server.IsDefault():
+		url, subdomains = server.URL, server.SubdomainList()
+	}
+	mv.sendCommand(fmt.Sprintf("tiles:%s,%s,%s,%s,%d", encodeText(url), encodeText(subdomains),
+		encodeText(apiKey), encodeText(provider.Attribution), provider.MaxZoom))
 }
 
-// CenterMap centers the map on the given coordinates
+// SetPrivacyMode turns privacy mode on or off (see the type docs): the
+// online overlays are withdrawn or offered again, and locate requests are
+// turned down while it is on.
+func (mv *MapView) SetPrivacyMode(on bool) {
+	if on == mv.privacy {
+		return
+	}
+	mv.privacy = on
+	mv.sendCommand(fmt.Sprintf("privacy:%d", boolToInt(on)))
+}
+
+// CenterMap centers the map on the given coordinates at the given zoom level.
+//
+// The location marker is moved to the new center as well.
 func (mv *MapView) CenterMap(lat, lon float64, zoom int) {
 	mv.currentLat = lat
 	mv.currentLon = lon
+	mv.currentZoom = clampZoom(zoom)
+
+	// Update via hash change to avoid full page reload
+	mv.sendCommand(fmt.Sprintf("view:%f,%f,%d", lat, lon, mv.currentZoom))
+}
+
+// Bounds returns the area the map shows; ok is false until the page has
+// reported it (shortly after loading).
+func (mv *MapView) Bounds() (bounds domain.Bounds, ok bool) {
+	return mv.bounds, mv.boundsKnown
+}
+
+// Zoom returns the map's current zoom level.
+func (mv *MapView) Zoom() int {
+	return mv.currentZoom
+}
+
+// SetZoom sets the zoom level without changing the map center.
+//
+// Values outside the supported range (1-19) are clamped.
+func (mv *MapView) SetZoom(zoom int) {
+	mv.currentZoom = clampZoom(zoom)
+	mv.sendCommand(fmt.Sprintf("zoom:%d", mv.currentZoom))
+}
+
+// ZoomIn increases the zoom level by one step.
+//
+// The new level is reported back through onZoomChange once the map has
+// finished zooming.
+func (mv *MapView) ZoomIn() {
+	mv.sendCommand("zoomin")
+}
+
+// ZoomOut decreases the zoom level by one step.
+//
+// The new level is reported back through onZoomChange once the map has
+// finished zooming.
+func (mv *MapView) ZoomOut() {
+	mv.sendCommand("zoomout")
+}
+
+// FitBounds adjusts the view so the given bounding box is fully visible.
+//
+// This is intended for features that show several points at once (such as
+// a measurement or an imported track). The resulting zoom level is reported
+// back through onZoomChange.
+//
+// Parameters:
+//   - south, west: Latitude and longitude of the south-west corner
+//   - north, east: Latitude and longitude of the north-east corner
+func (mv *MapView) FitBounds(south, west, north, east float64) {
+	mv.sendCommand(fmt.Sprintf("fitbounds:%f,%f,%f,%f", south, west, north, east))
+}
+
+// SetTerminator replaces the day/night overlay with the given bands.
+//
+// Bands are drawn in order with equal translucent shading, so passing them
+// from the night side down to full darkness (as solar.Terminator returns
+// them) gives progressively darker twilight. Coordinates are rounded to
+// 0.01°, far below what is visible at world zoom levels, to keep the
+// command short.
+//
+// Parameters:
+//   - bands: Twilight band polygons (see domain.TwilightBand)
+func (mv *MapView) SetTerminator(bands []domain.TwilightBand) {
+	for i, band := range bands {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "terminator:%d", i)
+		for _, p := range band.Boundary {
+			fmt.Fprintf(&sb, ",%.2f,%.2f", p.Latitude, p.Longitude)
+		}
+		mv.sendCommand(sb.String())
+	}
+}
+
+// Locate asks the web engine for the device position.
+//
+// The result arrives asynchronously through onLocate, at the latest after
+// locateTimeout. Calls made while a request is already in progress are
+// ignored; in privacy mode onLocate reports domain.ErrPrivacyMode at once.
+func (mv *MapView) Locate() {
+	if mv.locating {
+		return
+	}
+	if mv.privacy {
+		if mv.onLocate != nil {
+			mv.onLocate(0, 0, domain.ErrPrivacyMode)
+		}
+		return
+	}
+	mv.locating = true
+	mv.locateTimer.Start(locateTimeout)
+	mv.sendCommand("locate")
+}
+
+// finishLocate ends the locate request in progress (if any) and reports its
+// result through onLocate.
+func (mv *MapView) finishLocate(lat, lon float64, err error) {
+	if !mv.locating {
+		return
+	}
+	mv.locating = false
+	mv.locateTimer.Stop()
+	if mv.onLocate != nil {
+		mv.onLocate(lat, lon, err)
+	}
+}
+
+// SetProfile shows an elevation profile chart below the map controls.
+//
+// The chart draws the terrain heights (vertically exaggerated to fit) and
+// a dashed line of sight from the observer's eye to the horizon sample.
+// It stays until ClearProfile, the user closes it, or a new measurement
+// starts.
+//
+// Parameters:
+//   - profile: Terrain heights along the line (see domain.ElevationProfile)
+//   - horizon: Index of the sample forming the horizon (see HorizonAngle)
+//   - text: Summary shown above the chart (may contain newlines)
+func (mv *MapView) SetProfile(profile domain.ElevationProfile, horizon int, text string) {
+	if len(profile.Samples) < 2 {
+		return
+	}
+	total := profile.Samples[len(profile.Samples)-1].Distance
+	eye := profile.Samples[0].Elevation + domain.ObserverEyeHeight
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "profile:%.0f,%d,%.1f,%s", total, horizon, eye, encodeText(text))
+	for _, s := range profile.Samples {
+		fmt.Fprintf(&sb, ",%.1f", s.Elevation)
+	}
+	mv.sendCommand(sb.String())
+}
 
-	mv.page.SetUrl(qt.NewQUrl3(mv.buildLocationURL(lat, lon, zoom)))
+// ClearProfile hides the elevation profile chart.
+func (mv *MapView) ClearProfile() {
+	mv.sendCommand("profileclear")
+}
+
+// SetCameraView draws a camera's field of view and the day's horizon events.
+//
+// The cone spans the camera's horizontal field of view around its heading
+// out to radius. Each event ray points from the camera along the event's
+// azimuth, slightly past the cone, so it is visible whether or not it falls
+// inside; rays that do are drawn solid, the others dashed.
+//
+// Parameters:
+//   - camera: Camera position, heading and lens (see domain.Camera)
+//   - radius: Length of the cone in meters (e.g., the distance to the subject)
+//   - events: Horizon events to show as rays (see solar.HorizonEvents)
+func (mv *MapView) SetCameraView(camera domain.Camera, radius float64, events []domain.HorizonEvent) {
+	mv.sendCommand("fovclear")
+
+	// Cone: camera position, then the arc from the left to the right edge
+	fov := camera.HorizontalFOV()
+	steps := int(math.Ceil(fov/fovArcStep)) + 1
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "fovcone:%f,%f", camera.Location.Latitude, camera.Location.Longitude)
+	for i := 0; i < steps; i++ {
+		bearing := camera.Heading - fov/2 + fov*float64(i)/float64(steps-1)
+		p := camera.Location.Destination(bearing, radius)
+		fmt.Fprintf(&sb, ",%f,%f", p.Latitude, p.Longitude)
+	}
+	mv.sendCommand(sb.String())
+
+	for _, e := range events {
+		end := camera.Location.Destination(e.Azimuth, radius*fovRayScale)
+		mv.sendCommand(fmt.Sprintf("fovray:%d,%d,%f,%f,%f,%f,%s",
+			boolToInt(e.Moon), boolToInt(camera.InView(e.Azimuth)),
+			camera.Location.Latitude, camera.Location.Longitude, end.Latitude, end.Longitude,
+			encodeText(e.Name)))
+	}
+}
+
+// SetSunPath draws the sun's path through the evening golden hour as a
+// sector from the location, between the azimuths at its start and at
+// sunset, so the user sees which segment of the horizon the light sweeps.
+//
+// The page sizes the sector to the view and redraws it after zooming, so it
+// always reaches well into the visible map. Its edges have tooltips with
+// the time and direction of either end. A path with fewer than two points
+// (no golden hour on the date) removes the sector.
+//
+// Parameters:
+//   - loc: The observer location (the sector's tip)
+//   - path: Sun positions in chronological order (see solar.SunPath)
+//   - use24Hour: Time format of the tooltips
+func (mv *MapView) SetSunPath(loc domain.Location, path []domain.SunPathPoint, use24Hour bool) {
+	if len(path) < 2 {
+		mv.sendCommand("sunpathclear")
+		return
+	}
+	label := func(name string, p domain.SunPathPoint) string {
+		return fmt.Sprintf("%s %s · %.0f° %s", name, domain.FormatTime(p.Time, use24Hour),
+			p.Azimuth, domain.CompassPoint(p.Azimuth))
+	}
+	first, last := path[0], path[len(path)-1]
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "sunpath:%f,%f,%s,%s", loc.Latitude, loc.Longitude,
+		encodeText(label("Golden hour starts", first)), encodeText(label("Sunset", last)))
+	for _, p := range path {
+		fmt.Fprintf(&sb, ",%.2f", p.Azimuth)
+	}
+	mv.sendCommand(sb.String())
+}
+
+// SetSunPosition draws the sun's direction at one moment as a ray from the
+// location, for the time slider. The ray ends in a sun symbol whose tooltip
+// has the time and position; it turns grey while the sun is below the
+// horizon.
+//
+// Parameters:
+//   - loc: The observer location (the ray's start)
+//   - pos: The sun's position (see domain.InterpolateSunPosition)
+//   - use24Hour: Time format of the tooltip
+func (mv *MapView) SetSunPosition(loc domain.Location, pos domain.SunPathPoint, use24Hour bool) {
+	text := fmt.Sprintf("Sun at %s · %.0f° %s, %.1f° high", domain.FormatTime(pos.Time, use24Hour),
+		pos.Azimuth, domain.CompassPoint(pos.Azimuth), pos.Elevation)
+	mv.sendCommand(fmt.Sprintf("sunpos:%f,%f,%.2f,%.2f,%s", loc.Latitude, loc.Longitude,
+		pos.Azimuth, pos.Elevation, encodeText(text)))
+}
+
+// ClearSunPosition removes the sun direction drawn by SetSunPosition.
+func (mv *MapView) ClearSunPosition() {
+	mv.sendCommand("sunposclear")
+}
+
+// SetCloudFrames replaces the frames of the cloud layer's animation.
+//
+// The frames play in order, in a loop, while the layer is on. Each frame
+// is labeled with its time ("18:40", "18:50 forecast"); frames during the
+// golden hour are highlighted.
+//
+// Parameters:
+//   - frames: The frames in chronological order
+//   - maxZoom: Highest zoom level the frames' tiles exist for
+//   - attribution: Credit for the frames' source, as HTML
+//   - golden: The golden hour to highlight (an invalid range highlights none)
+//   - use24Hour: Time format of the labels
+func (mv *MapView) SetCloudFrames(frames []domain.CloudFrame, maxZoom int, attribution string,
+	golden domain.TimeRange, use24Hour bool) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "clouds:%d,%s", maxZoom, encodeText(attribution))
+	for _, f := range frames {
+		t := f.Time
+		if golden.IsValid() {
+			t = t.In(golden.Start.Location())
+		}
+		label := domain.FormatTime(t, use24Hour)
+		if f.Forecast {
+			label += " forecast"
+		}
+		inGolden := golden.IsValid() && !t.Before(golden.Start) && !t.After(golden.End)
+		fmt.Fprintf(&sb, ",%s,%s,%d", encodeText(f.TileURL), encodeText(label), boolToInt(inGolden))
+	}
+	mv.sendCommand(sb.String())
+}
+
+// ClearCameraView removes the field of view cone and event rays.
+func (mv *MapView) ClearCameraView() {
+	mv.sendCommand("fovclear")
+}
+
+// SetFullscreen updates the map's full-screen button to match the window.
+//
+// The window decides what full-screen mode means (hiding panels); this only
+// highlights the button and lets Leaflet adapt to the new map size.
+func (mv *MapView) SetFullscreen(active bool) {
+	mv.sendCommand(fmt.Sprintf("fullscreen:%d", boolToInt(active)))
+}
+
+// boolToInt converts a flag to the 0/1 form used in page commands.
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// SetOverlay shows text in a label on top of the map.
+//
+// The label sits in the top-left corner, next to the zoom control, and
+// sizes itself to the text. Mouse events pass through to the map.
+//
+// Parameters:
+//   - text: Plain text to show (may contain newlines); empty hides the label
+func (mv *MapView) SetOverlay(text string) {
+	if text == "" {
+		mv.overlay.Hide()
+		return
+	}
+	mv.overlay.SetText(text)
+	mv.overlay.AdjustSize()
+	mv.overlay.Show()
+	mv.overlay.Raise()
+}
+
+// SetPoints replaces the contents of a point layer.
+//
+// The layer is created on first use and added to the map's layer control
+// under the given title. Points are matched to the previous contents by ID:
+// unchanged points are not sent again, so calling SetPoints after a small
+// edit (such as adding one favorite) costs little even for large layers.
+// Large changes are split into chunks of pointChunkSize points.
+//
+// Parameters:
+//   - title: Layer name shown in the layer control (also identifies the layer)
+//   - points: The complete new contents; nil or empty clears the layer
+func (mv *MapView) SetPoints(title string, points []domain.MapPoint) {
+	layer := mv.pointLayer(title)
+
+	// Diff against the page's contents: changed points are removed and re-added
+	wanted := make(map[string]domain.MapPoint, len(points))
+	for _, p := range points {
+		wanted[p.ID] = p
+	}
+
+	var removed []int
+	for id, sent := range layer.points {
+		if p, ok := wanted[id]; !ok || p != sent.point {
+			removed = append(removed, sent.handle)
+			delete(layer.points, id)
+		}
+	}
+
+	var added []sentPoint
+	for _, p := range points {
+		if _, ok := layer.points[p.ID]; ok {
+			continue
+		}
+		sent := sentPoint{handle: mv.nextPointHandle, point: p}
+		mv.nextPointHandle++
+		layer.points[p.ID] = sent
+		added = append(added, sent)
+	}
+
+	for start := 0; start < len(removed); start += pointChunkSize {
+		end := min(start+pointChunkSize, len(removed))
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "pointremove:%d", layer.id)
+		for _, handle := range removed[start:end] {
+			fmt.Fprintf(&sb, ",%d", handle)
+		}
+		mv.sendCommand(sb.String())
+	}
+
+	for start := 0; start < len(added); start += pointChunkSize {
+		end := min(start+pointChunkSize, len(added))
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "points:%d", layer.id)
+		for _, sent := range added[start:end] {
+			loc := sent.point.Location
+			fmt.Fprintf(&sb, ",%d,%.6f,%.6f,%s", sent.handle, loc.Latitude, loc.Longitude, encodeText(loc.Name))
+		}
+		mv.sendCommand(sb.String())
+	}
+}
+
+// SetTracks replaces the lines of a point layer.
+//
+// The layer is created on first use, as in SetPoints; its points are not
+// affected. Lines are always sent in full (they are drawn as single
+// polylines, so there is nothing to diff), split into pieces of at most
+// trackChunkSize points that share their end points.
+//
+// Parameters:
+//   - title: Layer name shown in the layer control (also identifies the layer)
+//   - tracks: The complete new set of lines; nil or empty removes all lines
+func (mv *MapView) SetTracks(title string, tracks []domain.Track) {
+	layer := mv.pointLayer(title)
+	mv.sendCommand(fmt.Sprintf("trackclear:%d", layer.id))
+
+	for _, track := range tracks {
+		for start := 0; start < len(track.Points)-1; start += trackChunkSize - 1 {
+			end := min(start+trackChunkSize, len(track.Points))
+			var sb strings.Builder
+			fmt.Fprintf(&sb, "track:%d,%s", layer.id, encodeText(track.Name))
+			for _, p := range track.Points[start:end] {
+				fmt.Fprintf(&sb, ",%.6f,%.6f", p.Latitude, p.Longitude)
+			}
+			mv.sendCommand(sb.String())
+		}
+	}
+}
+
+// pointLayer returns the record of a point layer, creating the layer in the
+// page on first use.
+func (mv *MapView) pointLayer(title string) *pointLayer {
+	layer, ok := mv.pointLayers[title]
+	if !ok {
+		layer = &pointLayer{id: len(mv.pointLayers), points: make(map[string]sentPoint)}
+		mv.pointLayers[title] = layer
+		mv.sendCommand(fmt.Sprintf("pointlayer:%d,%s", layer.id, encodeText(title)))
+	}
+	return layer
+}
+
+// pointByHandle finds the point the page knows by handle.
+func (mv *MapView) pointByHandle(handle int) (domain.MapPoint, bool) {
+	for _, layer := range mv.pointLayers {
+		for _, sent := range layer.points {
+			if sent.handle == handle {
+				return sent.point, true
+			}
+		}
+	}
+	return domain.MapPoint{}, false
+}
+
+// clampZoom limits a zoom level to the range supported by the map tiles.
+func clampZoom(zoom int) int {
+	if zoom < minZoom {
+		return minZoom
+	}
+	if zoom > maxZoom {
+		return maxZoom
+	}
+	return zoom
 }
 
 // IsReady returns true if the map is loaded and ready
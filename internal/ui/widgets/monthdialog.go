@@ -0,0 +1,287 @@
+package widgets
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// MonthDialog
+// =============================================================================
+
+// MonthDialog shows a month at the selected location, one cell per day with
+// its sunrise, sunset and total golden hour, to scan a whole month for the
+// best days at a glance.
+//
+//	┌─ Month Calendar ───────────────────────────────────────────┐
+//	│ [<]                    June 2026                      [>]  │
+//	│   Mon          Tue          Wed          ...   Sun         │
+//	│ ┌──────────┐ ┌──────────┐ ┌──────────┐       ┌──────────┐  │
+//	│ │ 1        │ │ 2        │ │ 3        │       │ 7        │  │
+//	│ │ ↑ 05:49  │ │ ↑ 05:48  │ │ ↑ 05:48  │  ...  │ ↑ 05:46  │  │
+//	│ │ ↓ 21:48  │ │ ↓ 21:49  │ │ ↓ 21:50  │       │ ↓ 21:53  │  │
+//	│ │ ☀ 1h 41m │ │ ☀ 1h 41m │ │ ☀ 1h 42m │       │ ☀ 1h 43m │  │
+//	│ └──────────┘ └──────────┘ └──────────┘       └──────────┘  │
+//	│ ...                                                        │
+//	│                                                  [ Close ] │
+//	└────────────────────────────────────────────────────────────┘
+//
+// The cells are shaded gold by their golden hour, relative to the longest
+// of the month, and the selected date has an orange border. Clicking a day
+// selects it as the date in the main window; the tooltip lists the day's
+// golden and blue hours.
+//
+// The peak night of a meteor shower is marked "☄", with the shower's
+// outlook in the tooltip, and gets a green border when the peak falls in
+// moonless astronomical darkness (domain.MeteorNight.Good):
+//
+//	┌──────────┐
+//	│ 12    ☄  │
+//	│ ↑ 06:35  │
+//	│ ↓ 20:58  │
+//	│ ☀ 1h 52m │
+//	└──────────┘
+//
+// Like the camera view, the dialog is modeless and is refreshed while open
+// when the location, date or settings change. The days are calculated by
+// the App (see SetMonth); the dialog only displays them.
+//
+// # miqt API Notes
+//
+//   - Show() instead of Exec() opens the dialog without blocking
+//   - QPushButton text may span several lines ("\n")
+type MonthDialog struct {
+	// dialog is the top-level modeless dialog.
+	dialog *qt.QDialog
+
+	// titleLabel shows the month and year.
+	titleLabel *qt.QLabel
+
+	// cells are the day buttons of the grid, six weeks of seven days, and
+	// cellDates the date each shows (zero for cells outside the month).
+	cells     [monthGridCells]*qt.QPushButton
+	cellDates [monthGridCells]time.Time
+
+	// month is the first day of the shown month.
+	month time.Time
+
+	// selected is the date selected in the main window.
+	selected time.Time
+
+	// onMonthChange is invoked with the first day of the month to show
+	// when the user moves to the previous or next month.
+	onMonthChange func(month time.Time)
+
+	// onSelect is invoked with the date of a clicked day.
+	onSelect func(date time.Time)
+}
+
+// monthGridCells is the number of day cells: six weeks cover every month,
+// whichever weekday it starts on.
+const monthGridCells = 6 * 7
+
+// NewMonthDialog creates the month calendar (initially hidden).
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - onMonthChange: Callback invoked with the first day of the month the
+//     user moves to; it should call SetMonth with that month's days
+//   - onSelect: Callback invoked with the date of a clicked day
+//
+// Call SetMonth to fill it, then Show.
+func NewMonthDialog(parent *qt.QWidget, onMonthChange func(month time.Time), onSelect func(date time.Time)) *MonthDialog {
+	md := &MonthDialog{
+		onMonthChange: onMonthChange,
+		onSelect:      onSelect,
+	}
+	md.setupUI(parent)
+	return md
+}
+
+// setupUI creates the month navigation, the weekday header, the day grid
+// and the Close button.
+//
+// miqt API notes:
+//   - AddWidget2(widget, row, column) places a widget in a grid cell
+//   - SetSizePolicy2(h, v) lets the day buttons grow with the dialog
+func (md *MonthDialog) setupUI(parent *qt.QWidget) {
+	md.dialog = qt.NewQDialog(parent)
+	md.dialog.SetWindowTitle("Month Calendar")
+	md.dialog.Resize(720, 520)
+
+	layout := qt.NewQVBoxLayout(md.dialog.QWidget)
+
+	// Month navigation
+	nav := qt.NewQHBoxLayout2()
+	prevBtn := qt.NewQPushButton3("<")
+	prevBtn.SetFixedWidth(40)
+	prevBtn.OnClicked(func() { md.changeMonth(-1) })
+	nav.AddWidget(prevBtn.QWidget)
+	md.titleLabel = qt.NewQLabel2()
+	md.titleLabel.SetAlignment(qt.AlignCenter)
+	md.titleLabel.SetStyleSheet("font-size: 15px; font-weight: bold;")
+	nav.AddWidget(md.titleLabel.QWidget)
+	nextBtn := qt.NewQPushButton3(">")
+	nextBtn.SetFixedWidth(40)
+	nextBtn.OnClicked(func() { md.changeMonth(1) })
+	nav.AddWidget(nextBtn.QWidget)
+	layout.AddLayout(nav.QLayout)
+
+	// Weekdays, starting on Monday, then the day cells
+	grid := qt.NewQGridLayout2()
+	grid.SetSpacing(4)
+	for col := range 7 {
+		name := qt.NewQLabel3(time.Weekday((col + 1) % 7).String()[:3])
+		name.SetAlignment(qt.AlignCenter)
+		grid.AddWidget2(name.QWidget, 0, col)
+	}
+	for i := range md.cells {
+		cell := qt.NewQPushButton2()
+		cell.SetSizePolicy2(qt.QSizePolicy__Expanding, qt.QSizePolicy__Expanding)
+		cell.SetMinimumSize2(90, 64)
+		cell.OnClicked(func() {
+			if date := md.cellDates[i]; !date.IsZero() && md.onSelect != nil {
+				md.onSelect(date)
+			}
+		})
+		grid.AddWidget2(cell.QWidget, 1+i/7, i%7)
+		md.cells[i] = cell
+	}
+	layout.AddLayout(grid.QLayout)
+
+	// Close button
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		md.dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+}
+
+// changeMonth asks for the month delta months away from the shown one.
+func (md *MonthDialog) changeMonth(delta int) {
+	if md.onMonthChange != nil {
+		md.onMonthChange(md.month.AddDate(0, delta, 0))
+	}
+}
+
+// MonthFor returns the month the dialog should show for the date selected
+// in the main window: the shown month, unless the selected date has moved
+// to another month since the last SetMonth (then the selected date's).
+func (md *MonthDialog) MonthFor(selected time.Time) time.Time {
+	if md.month.IsZero() || !sameMonth(selected, md.selected) {
+		return time.Date(selected.Year(), selected.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	return md.month
+}
+
+// SetMonth shows a month's days.
+//
+// Parameters:
+//   - month: The first day of the month
+//   - days: Sun times of each day of the month, in order (see
+//     App.MonthSunTimes); days without times show "N/A"
+//   - meteors: The month's meteor shower peak nights (see
+//     App.MonthMeteorNights)
+//   - selected: The date selected in the main window, marked if it is in
+//     the month
+//   - use24Hour: Time display format
+func (md *MonthDialog) SetMonth(month time.Time, days []domain.SunTimes, meteors []domain.MeteorNight,
+	selected time.Time, use24Hour bool) {
+	md.month = month
+	md.selected = selected
+	md.titleLabel.SetText(month.Format("January 2006"))
+
+	// Shade relative to the month's longest golden hour
+	var longest time.Duration
+	for _, day := range days {
+		longest = max(longest, day.GoldenHourTotal())
+	}
+
+	offset := (int(month.Weekday()) + 6) % 7 // Monday first
+	for i, cell := range md.cells {
+		md.cellDates[i] = time.Time{}
+		index := i - offset
+		if index < 0 || index >= len(days) {
+			cell.SetText("")
+			cell.SetToolTip("")
+			cell.SetEnabled(false)
+			cell.SetStyleSheet("")
+			continue
+		}
+		day := days[index]
+		md.cellDates[i] = day.Date
+		cell.SetEnabled(true)
+		text, tip := dayCellText(day, use24Hour), dayCellToolTip(day, use24Hour)
+		good := false
+		for _, night := range meteors {
+			if night.Date.Day() == day.Date.Day() {
+				text = strings.Replace(text, "\n", "    ☄\n", 1)
+				tip += "\n" + night.Summary(use24Hour)
+				good = good || night.Good()
+			}
+		}
+		cell.SetText(text)
+		cell.SetToolTip(tip)
+
+		shade := 0.0
+		if longest > 0 {
+			shade = 0.1 + 0.5*float64(day.GoldenHourTotal())/float64(longest)
+		}
+		border := "1px solid palette(mid)"
+		if good {
+			border = "2px solid #2e7d32"
+		}
+		if sameMonth(day.Date, selected) && day.Date.Day() == selected.Day() {
+			border = "2px solid #ff9800"
+		}
+		cell.SetStyleSheet(fmt.Sprintf(
+			"QPushButton { background-color: rgba(255, 152, 0, %.2f); border: %s; "+
+				"border-radius: 4px; text-align: left; padding: 4px; }", shade, border))
+	}
+}
+
+// dayCellText formats a day cell: the day of the month, sunrise, sunset
+// and the total golden hour.
+func dayCellText(day domain.SunTimes, use24Hour bool) string {
+	text := fmt.Sprintf("%d\n↑ %s\n↓ %s", day.Date.Day(),
+		domain.FormatTime(day.Sunrise, use24Hour), domain.FormatTime(day.Sunset, use24Hour))
+	if golden := day.GoldenHourTotal(); golden > 0 {
+		return text + "\n☀ " + domain.FormatDuration(golden)
+	}
+	return text + "\n☀ none"
+}
+
+// dayCellToolTip lists a day's golden and blue hours.
+func dayCellToolTip(day domain.SunTimes, use24Hour bool) string {
+	period := func(tr domain.TimeRange) string {
+		if !tr.IsValid() {
+			return "N/A"
+		}
+		return domain.FormatTime(tr.Start, use24Hour) + " - " + domain.FormatTime(tr.End, use24Hour)
+	}
+	return fmt.Sprintf("%s\nGolden hour: %s, %s\nBlue hour: %s, %s\nClick to select this date",
+		day.Date.Format("Monday, January 2"),
+		period(day.GoldenMorning), period(day.GoldenEvening),
+		period(day.BlueMorning), period(day.BlueEvening))
+}
+
+// sameMonth reports whether a and b fall in the same month of the same year.
+func sameMonth(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.Month() == b.Month()
+}
+
+// Show opens the dialog (or brings it to the front if already open).
+func (md *MonthDialog) Show() {
+	md.dialog.Show()
+	md.dialog.Raise()
+	md.dialog.ActivateWindow()
+}
+
+// IsVisible reports whether the dialog is open.
+func (md *MonthDialog) IsVisible() bool {
+	return md.dialog.IsVisible()
+}
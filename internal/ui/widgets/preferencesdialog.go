@@ -0,0 +1,2302 @@
+package widgets
+
+import (
+	"cmp"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+)
+
+// =============================================================================
+// PreferencesDialog
+// =============================================================================
+
+// PreferencesDialog is the tabbed dialog for less frequently used preferences.
+//
+// The most-used settings (elevation angles, time format, auto-detect,
+// teaching mode) stay in the collapsible SettingsPanel; the dialog holds
+// everything else, grouped in tabs. It is opened from Edit → Preferences.
+//
+//   - Calculation: custom events of the active preset
+//   - Display: formats, home clock, Copy Times template, system tray,
+//     aurora forecast
+//   - Network: location detection (Location) and Nominatim searches
+//     (Search)
+//   - Notifications: desktop notifications (Desktop), phone reminders,
+//     webhook, daily summary, widget feed and calendar sync
+//   - Map: tile server
+//   - Automation: commands run at sun phase transitions
+//
+// # Calculation Tab
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [Calculation] [Display] [Network] [Notifications] [...]        │
+//	│ ┌──────────────────────────────────────────┬─────────────────┐ │
+//	│ │ Name                                     │ Sun elevation   │ │
+//	│ ├──────────────────────────────────────────┼─────────────────┤ │
+//	│ │ Nautical twilight                        │ [ -12.0° ]      │ │
+//	│ │ Astronomical dark                        │ [ -18.0° ]      │ │
+//	│ └──────────────────────────────────────────┴─────────────────┘ │
+//	│ [Add] [Remove]                                                 │
+//	│ Saved with the preset "Astro". The time panel shows when ...   │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// Rows without a name are dropped; at most domain.MaxCustomEvents rows can
+// be added.
+//
+// # Display Tab
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [Calculation] [Display] [Network] [Notifications] [...]        │
+//	│ ┌ Formats ───────────────────────────────────────────────────┐ │
+//	│ │ Coordinates:     [Decimal degrees (48.8566° N)          ▼] │ │
+//	│ │ Place names:     [Short (Paris, France)                 ▼] │ │
+//	│ │ Sun times:       [Compact (golden/blue hour grid)       ▼] │ │
+//	│ │ Scratch history: [30 days                               ▼] │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	│ ┌ Dual Clock ────────────────────────────────────────────────┐ │
+//	│ │ Home timezone: [America/New_York                        ▼] │ │
+//	│ │ Event times are also shown on your home clock ...          │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	│ ┌ Copy Times ────────────────────────────────────────────────┐ │
+//	│ │ ┌────────────────────────────────────────────────────────┐ │ │
+//	│ │ │ **{{.Location}}** – {{.Date}}                          │ │ │
+//	│ │ │ - Golden hour: {{.GoldenMorning}} · {{.GoldenEvening}} │ │ │
+//	│ │ └────────────────────────────────────────────────────────┘ │ │
+//	│ │ Placeholders: {{.Location}} {{.Sunrise}} ...  [Default]    │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	│ ┌ System Tray ───────────────────────────────────────────────┐ │
+//	│ │ [ ] Minimize to the system tray                            │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	│ ┌ Astro ─────────────────────────────────────────────────────┐ │
+//	│ │ [ ] Aurora forecast (Kp index from NOAA SWPC)              │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// The home timezone can be picked from the list or typed; a name that
+// isn't a timezone (domain.ValidTimezone) keeps the dialog open, as does a
+// copy template that export.CheckCopyTemplate rejects.
+//
+// # Network Tab: Location
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Location] [Search]                                            │
+//	│ Home:      Zürich, Switzerland  [Set Current as Home] [Reset]  │
+//	│ Location:  [IP address (approximate)              ▼]           │
+//	│ ┌────────────────────────────────────────────────┐ [Move Up]   │
+//	│ │ [✓] ip-api.com (IP address)                    │ [Move Down] │
+//	│ │ [✓] ipinfo.io (IP address, HTTPS)              │             │
+//	│ │ [ ] System location services                   │             │
+//	│ └────────────────────────────────────────────────┘             │
+//	│ Timeouts: ip-api.com [10 s]  ipinfo.io [10 s]  System [30 s]   │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// Detection tries the checked providers from top to bottom. Turning all of
+// them off is allowed (detection then reports an error, and the home
+// location is shown instead). The home location is also shown on a first
+// run; Reset goes back to London (domain.DefaultLocation).
+//
+// # Network Tab: Search
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Location] [Search]                                            │
+//	│ Contact email: [you@example.com (optional)              ]      │
+//	│ Sent to OpenStreetMap's Nominatim with location searches ...   │
+//	│ ┌ Search ────────────────────────────────────────────────────┐ │
+//	│ │ Languages: [de, en (default: system language)             ] │ │
+//	│ │ Countries: [at, de (default: worldwide)                   ] │ │
+//	│ │ [x] Prefer places in the map view                          │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// An address that isn't a plain email address, or malformed languages or
+// countries (domain.SearchBias.Validate), keep the dialog open.
+//
+// # Notifications Tab: Desktop
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Desktop] [Phone] [Webhook] [Daily Summary] [Widget Feed] [...]│
+//	│ [✓] Show a desktop notification before:                        │
+//	│ Lead times: [30 min] [10 min] [  off ] [  off ]                │
+//	│ ┌ Events ────────────────────────┐ ┌ At ─────────────────────┐ │
+//	│ │ [✓] Morning blue hour start    │ │ [ ] Riverside Bridge    │ │
+//	│ │ [✓] Evening golden hour start  │ │ [ ] Old Lighthouse      │ │
+//	│ └────────────────────────────────┘ └─────────────────────────┘ │
+//	│ [Show Test]                                                    │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// Every lead time set shows its own notification, so an event can be
+// announced twice (e.g., 30 and 10 minutes ahead). Without checked
+// favorites the notifications are for the current location.
+//
+// # Notifications Tab: Phone
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Desktop] [Phone] [Webhook] [Daily Summary] [Widget Feed] [...]│
+//	│ [✓] Push a reminder to my phone [30 min] before:               │
+//	│ ┌────────────────────────────────────────────────────────────┐ │
+//	│ │ [✓] Morning blue hour start                                │ │
+//	│ │ [ ] Sunrise                                                │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	│ Service:      [ntfy                           ▼]               │
+//	│ ntfy server:  [https://ntfy.sh                ]                │
+//	│ ntfy topic:   [my-secret-golden-hour          ]                │
+//	│ Pushover app: [••••••••                       ]                │
+//	│ Pushover key: [                               ]                │
+//	│ [Send 14-Day Schedule]                                         │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// Only the fields of the selected service are enabled. Enabled reminders
+// that fail domain.PushNotifications.Check keep the dialog open.
+//
+// # Notifications Tab: Webhook
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Desktop] [Phone] [Webhook] [Daily Summary] [Widget Feed] [...]│
+//	│ [✓] Call a webhook [15 min] before:                            │
+//	│ ┌ Events ────────────────────────┐ ┌ At ─────────────────────┐ │
+//	│ │ [✓] Evening golden hour start  │ │ [ ] Riverside Bridge    │ │
+//	│ │ [ ] Sunset                     │ │ [ ] Old Lighthouse      │ │
+//	│ └────────────────────────────────┘ └─────────────────────────┘ │
+//	│ URL: [https://maker.ifttt.com/trigger/golden/json/with/key/…]  │
+//	│ [Send Test]                                                    │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// Without checked favorites the webhook is called for the current
+// location. An enabled webhook that fails domain.Webhook.Check keeps the
+// dialog open.
+//
+// # Notifications Tab: Daily Summary
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Desktop] [Phone] [Webhook] [Daily Summary] [Widget Feed] [...]│
+//	│ [✓] Send tomorrow's times every day at [19:00]                 │
+//	│ ┌────────────────────────────────────────────────────────────┐ │
+//	│ │ [✓] Riverside Bridge                                       │ │
+//	│ │ [ ] Old Lighthouse                                         │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	│ Delivery:  [Write to a file                  ▼]                │
+//	│ File:      [~/Dropbox/golden-hour.txt       ] [Browse...]      │
+//	│ Email to:  [you@example.com                 ]                  │
+//	│ Sendmail:  [/usr/sbin/sendmail              ]                  │
+//	│ SMTP:      [localhost:25                    ]                  │
+//	│ [Send Now]                                                     │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// Only the fields of the selected delivery method are enabled. An enabled
+// summary that fails domain.DailySummary.Check keeps the dialog open.
+//
+// # Notifications Tab: Widget Feed
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [Desktop] [Phone] [Webhook] [Daily Summary] [Widget Feed] [...]│
+//	│ [✓] Write the home location's times every [15 min]             │
+//	│ File:    [~/.cache/golden-hour.json      ] [Browse...]         │
+//	│ Format:  [JSON                        ▼]                       │
+//	│ [Write Now]                                                    │
+//	│ For desktop widgets such as conky or Rainmeter ...             │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// An enabled feed that fails domain.WidgetFeed.Check keeps the dialog
+// open.
+//
+// # Notifications Tab: Calendar
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [...] [Webhook] [Daily Summary] [Widget Feed] [Calendar]       │
+//	│ Calendar URL: [https://cloud.example.com/.../shoots/   ]       │
+//	│ Username:     [me                                      ]       │
+//	│ Password:     [••••••••                                ]       │
+//	│ File → Sync to Calendar sends the golden and blue hours ...    │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// A configuration that fails domain.CalDAVSync.Check keeps the dialog
+// open; an empty URL turns calendar sync off.
+//
+// # Map Tab
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ ┌ Map Tiles ─────────────────────────────────────────────────┐ │
+//	│ │ Tile URL:   [https://tile.openstreetmap.org/... (default)] │ │
+//	│ │ Subdomains: [abc                                         ] │ │
+//	│ │ API key:    [••••••••                                    ] │ │
+//	│ └────────────────────────────────────────────────────────────┘ │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// A tile server that fails domain.TileServer.Validate keeps the dialog
+// open.
+//
+// # Automation Tab
+//
+//	┌─ Preferences ──────────────────────────────────────────────────┐
+//	│ [...] [Network] [Notifications] [Map] [Automation]             │
+//	│ [✓] Run commands at sun phase transitions                      │
+//	│ ┌────┬──────────────────────────┬─────────────────────────────┐│
+//	│ │ On │ Event                    │ Command                     ││
+//	│ ├────┼──────────────────────────┼─────────────────────────────┤│
+//	│ │ [✓]│ [Evening golden hour ▼]  │ gphoto2 --capture-image     ││
+//	│ └────┴──────────────────────────┴─────────────────────────────┘│
+//	│ [Add] [Remove] [Test]                                          │
+//	│ Placeholders: {{.Event}} {{.Time}} ...                         │
+//	│                                              [ OK ] [Cancel]   │
+//	└────────────────────────────────────────────────────────────────┘
+//
+// # Safety Confirmation
+//
+// Hooks run arbitrary programs, so when the user clicks OK with new or
+// changed commands, the dialog lists those commands and asks for explicit
+// confirmation. This happens whether or not automation or the hook is
+// switched on, since either can be turned on later without another prompt.
+// Declining keeps the dialog open so the commands can be edited or removed.
+// The "Test" button asks for the same confirmation before running a command,
+// which also counts as approving it.
+//
+// Approval is recorded per hook as a fingerprint of the command text (see
+// domain.Hook.Confirmed), so it never carries over to an edited command.
+//
+// # miqt API Notes
+//
+//   - NewQTabWidget2(): Tab container (suffix "2" = no params)
+//   - SetCellWidget(row, col, widget): Embeds a checkbox/combo in a table cell
+//   - QMessageBox_Question5: Question with explicit standard buttons
+type PreferencesDialog struct {
+	// dialog is the top-level modal dialog.
+	dialog *qt.QDialog
+
+	// automationCheck is the master switch for running hooks.
+	automationCheck *qt.QCheckBox
+
+	// hookTable lists the configured hooks, one per row.
+	// Column 2 (command) is a plain editable item.
+	hookTable *qt.QTableWidget
+
+	// hookRows holds the cell widgets of each table row, in row order.
+	// miqt returns cell widgets as plain *QWidget, so the typed pointers
+	// are kept here instead of being looked up from the table.
+	hookRows []hookRow
+
+	// locationSourceCombo selects the detection backend (Location tab).
+	// Item indexes map to locationSources.
+	locationSourceCombo *qt.QComboBox
+
+	// providerList shows the location providers in fallback order with a
+	// checkbox each (Location tab); providerIDs holds their IDs in list
+	// order, and providerTimeouts their timeout fields by ID.
+	providerList     *qt.QListWidget
+	providerIDs      []string
+	providerTimeouts map[string]*qt.QSpinBox
+
+	// secureLocationCheck restricts detection to providers reached over
+	// HTTPS (Location tab).
+	secureLocationCheck *qt.QCheckBox
+
+	// homeLabel names the home location (Location tab). home is the one
+	// chosen (nil for the default), current the location on screen that
+	// "Set Current as Home" takes.
+	homeLabel *qt.QLabel
+	home      *domain.Location
+	current   domain.Location
+
+	// customEventTable lists the custom events, one per row (Calculation
+	// tab). Column 0 (name) is a plain editable item; customEventSpins
+	// holds the elevation spin boxes of column 1, in row order.
+	customEventTable *qt.QTableWidget
+	customEventSpins []*qt.QDoubleSpinBox
+
+	// addCustomEventBtn adds a row; disabled at domain.MaxCustomEvents rows.
+	addCustomEventBtn *qt.QPushButton
+
+	// coordinateFormatCombo, placeNameCombo, timePanelLayoutCombo and
+	// scratchRetentionCombo hold the formats (Display tab); their item
+	// indexes map to coordinateFormats, placeNameStyles, timePanelLayouts
+	// and scratchRetentions.
+	coordinateFormatCombo *qt.QComboBox
+	placeNameCombo        *qt.QComboBox
+	timePanelLayoutCombo  *qt.QComboBox
+	scratchRetentionCombo *qt.QComboBox
+
+	// homeTimezoneCombo holds the home clock's timezone, empty for none
+	// (Display tab). Editable, so a zone can also be typed.
+	homeTimezoneCombo *qt.QComboBox
+
+	// copyTemplateEdit holds the Edit → Copy Times template (Display tab).
+	// It shows the built-in summary when no template is set.
+	copyTemplateEdit *qt.QPlainTextEdit
+
+	// minimizeToTrayCheck hides the minimized window in the system tray
+	// (Display tab).
+	minimizeToTrayCheck *qt.QCheckBox
+
+	// auroraCheck turns on the aurora outlook of the Astro panel (Display
+	// tab).
+	auroraCheck *qt.QCheckBox
+
+	// contactEmailEdit holds the optional contact email (Search tab).
+	contactEmailEdit *qt.QLineEdit
+
+	// searchLanguageEdit, searchCountriesEdit and searchNearMapCheck hold
+	// the search bias (Search tab).
+	searchLanguageEdit  *qt.QLineEdit
+	searchCountriesEdit *qt.QLineEdit
+	searchNearMapCheck  *qt.QCheckBox
+
+	// tileURLEdit, tileSubdomainsEdit and tileAPIKeyEdit hold the custom
+	// tile server (Map tab); an empty URL means OpenStreetMap.
+	tileURLEdit        *qt.QLineEdit
+	tileSubdomainsEdit *qt.QLineEdit
+	tileAPIKeyEdit     *qt.QLineEdit
+
+	// tileProviderLabel names the provider of the entered tile URL and
+	// its usage policy (see updateTileProvider).
+	tileProviderLabel *qt.QLabel
+
+	// summaryCheck turns the daily summary on (Daily Summary tab).
+	summaryCheck *qt.QCheckBox
+
+	// summaryTimeEdit holds the time of day the summary is sent.
+	summaryTimeEdit *qt.QTimeEdit
+
+	// summaryFavoritesList lists all favorites with a checkbox each.
+	// Rows are in settings order; summaryFavoriteIDs holds their IDs.
+	summaryFavoritesList *qt.QListWidget
+	summaryFavoriteIDs   []string
+
+	// deliveryCombo picks the delivery method; the index maps to
+	// summaryDeliveries.
+	deliveryCombo *qt.QComboBox
+
+	// summaryFileEdit, summaryFileBtn, summaryEmailEdit, sendmailEdit and
+	// smtpServerEdit hold the delivery fields; only those used by the
+	// selected method are enabled.
+	summaryFileEdit  *qt.QLineEdit
+	summaryFileBtn   *qt.QPushButton
+	summaryEmailEdit *qt.QLineEdit
+	sendmailEdit     *qt.QLineEdit
+	smtpServerEdit   *qt.QLineEdit
+
+	// feedCheck turns the widget feed on and feedIntervalSpin sets how
+	// often it is rewritten (Widget Feed tab).
+	feedCheck        *qt.QCheckBox
+	feedIntervalSpin *qt.QSpinBox
+
+	// feedPathEdit holds the feed file; feedFormatCombo picks the format,
+	// the index maps to feedFormats.
+	feedPathEdit    *qt.QLineEdit
+	feedFormatCombo *qt.QComboBox
+
+	// pushCheck turns the phone reminders on and pushLeadSpin sets how many
+	// minutes before each event they are pushed (Phone tab).
+	pushCheck    *qt.QCheckBox
+	pushLeadSpin *qt.QSpinBox
+
+	// pushEventsList lists all event kinds with a checkbox each, in
+	// domain.AllEventKinds order.
+	pushEventsList *qt.QListWidget
+
+	// pushServiceCombo picks the service; the index maps to pushServices.
+	pushServiceCombo *qt.QComboBox
+
+	// ntfyServerEdit, ntfyTopicEdit, pushoverTokenEdit and pushoverUserEdit
+	// hold the service fields; only those of the selected service are
+	// enabled.
+	ntfyServerEdit    *qt.QLineEdit
+	ntfyTopicEdit     *qt.QLineEdit
+	pushoverTokenEdit *qt.QLineEdit
+	pushoverUserEdit  *qt.QLineEdit
+
+	// webhookCheck turns the webhook on and webhookLeadSpin sets how many
+	// minutes before each event it is called (Webhook tab).
+	webhookCheck    *qt.QCheckBox
+	webhookLeadSpin *qt.QSpinBox
+
+	// webhookEventsList lists all event kinds with a checkbox each, in
+	// domain.AllEventKinds order.
+	webhookEventsList *qt.QListWidget
+
+	// webhookFavoritesList lists all favorites with a checkbox each.
+	// Rows are in settings order; webhookFavoriteIDs holds their IDs.
+	webhookFavoritesList *qt.QListWidget
+	webhookFavoriteIDs   []string
+
+	// webhookURLEdit holds the URL the requests are POSTed to.
+	webhookURLEdit *qt.QLineEdit
+
+	// notificationsCheck turns the desktop notifications on and
+	// notificationLeadSpins set how many minutes before each event they are
+	// shown; a spin box at 0 ("off") is unused (Desktop tab).
+	notificationsCheck    *qt.QCheckBox
+	notificationLeadSpins []*qt.QSpinBox
+
+	// notificationEventsList and notificationFavoritesList are checkable
+	// lists like those of the Webhook tab; notificationFavoriteIDs holds
+	// the IDs of the favorite rows.
+	notificationEventsList    *qt.QListWidget
+	notificationFavoritesList *qt.QListWidget
+	notificationFavoriteIDs   []string
+
+	// caldavURLEdit, caldavUserEdit and caldavPasswordEdit hold the
+	// calendar sync login (Calendar tab); an empty URL means no calendar.
+	caldavURLEdit      *qt.QLineEdit
+	caldavUserEdit     *qt.QLineEdit
+	caldavPasswordEdit *qt.QLineEdit
+
+	// onTestHook is invoked when the user tests a hook with "Test".
+	onTestHook func(hook domain.Hook)
+
+	// onSendSummary is invoked when the user clicks "Send Now".
+	onSendSummary func(summary domain.DailySummary)
+
+	// onWriteFeed is invoked when the user clicks "Write Now".
+	onWriteFeed func(feed domain.WidgetFeed)
+
+	// onSendSchedule is invoked when the user clicks "Send 14-Day Schedule".
+	onSendSchedule func(push domain.PushNotifications)
+
+	// onTestWebhook is invoked when the user clicks "Send Test".
+	onTestWebhook func(webhook domain.Webhook)
+
+	// onTestNotification is invoked when the user clicks "Show Test".
+	onTestNotification func(notifications domain.DesktopNotifications)
+}
+
+// pushServices lists the push services in pushServiceCombo order, with
+// their labels.
+var pushServices = []struct {
+	service string
+	label   string
+}{
+	{domain.PushServiceNtfy, "ntfy"},
+	{domain.PushServicePushover, "Pushover"},
+}
+
+// feedFormats lists the widget feed formats in feedFormatCombo order,
+// with their labels.
+var feedFormats = []struct {
+	format string
+	label  string
+}{
+	{domain.FeedFormatJSON, "JSON"},
+	{domain.FeedFormatRSS, "RSS"},
+}
+
+// summaryDeliveries lists the delivery methods in deliveryCombo order,
+// with their labels.
+var summaryDeliveries = []struct {
+	method string
+	label  string
+}{
+	{domain.SummaryDeliveryFile, "Write to a file"},
+	{domain.SummaryDeliverySendmail, "Email with sendmail"},
+	{domain.SummaryDeliverySMTP, "Email through an SMTP server"},
+}
+
+// locationSources lists the location source values in combo box order.
+var locationSources = []string{domain.LocationSourceIP, domain.LocationSourceSystem, domain.LocationSourceBrowser}
+
+// coordinateFormats lists the coordinate format values in combo box order.
+var coordinateFormats = []string{domain.CoordinateFormatDecimal, domain.CoordinateFormatDMS}
+
+// placeNameStyles lists the place name style values in combo box order.
+var placeNameStyles = []string{domain.PlaceNameShort, domain.PlaceNameMedium, domain.PlaceNameFull}
+
+// timePanelLayouts lists the time panel layout values in combo box order.
+var timePanelLayouts = []string{domain.TimePanelCompact, domain.TimePanelDetailed}
+
+// scratchRetentions lists the scratch retention values (days) in combo box
+// order.
+var scratchRetentions = []int{domain.ScratchKeepSession, 1, 7, domain.DefaultScratchRetentionDays, 90,
+	domain.ScratchKeepForever}
+
+// hookRow holds the widgets of one row in the hook table.
+type hookRow struct {
+	enabled *qt.QCheckBox
+	event   *qt.QComboBox
+
+	// confirmed is the fingerprint of the command the user last approved
+	// for this row (see domain.Hook.Confirmed).
+	confirmed string
+}
+
+// Column indexes of the hook table.
+const (
+	hookColumnEnabled = iota
+	hookColumnEvent
+	hookColumnCommand
+)
+
+// NewPreferencesDialog creates the preferences dialog for the given settings.
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - settings: Current settings used to fill the controls
+//   - current: The location on screen, used by "Set Current as Home"
+//   - timezones: The timezones offered for the home clock (see
+//     timezone.Names)
+//   - onTestHook: Callback that runs a hook once, used by the "Test" button
+//   - onSendSummary: Callback that sends the daily summary once, used by
+//     the "Send Now" button
+//   - onWriteFeed: Callback that writes the widget feed once, used by the
+//     "Write Now" button
+//   - onSendSchedule: Callback that pushes the two-week schedule once, used
+//     by the "Send 14-Day Schedule" button
+//   - onTestWebhook: Callback that calls the webhook once, used by the
+//     "Send Test" button
+//   - onTestNotification: Callback that shows a desktop notification once,
+//     used by the "Show Test" button
+//
+// Call Exec to show the dialog and read the results with
+// AutomationEnabled, Hooks, DailySummary, WidgetFeed, PushNotifications, Webhook, Notifications, CalDAV,
+// LocationSource, LocationProviders, HomeLocation, CustomEvents, CoordinateFormat, PlaceNameStyle,
+// TimePanelLayout, ScratchRetentionDays, HomeTimezone, CopyTemplate, MinimizeToTray,
+// AuroraForecast, ContactEmail, SearchBias and TileServer afterwards.
+func NewPreferencesDialog(parent *qt.QWidget, settings domain.Settings, current domain.Location, timezones []string,
+	onTestHook func(hook domain.Hook),
+	onSendSummary func(summary domain.DailySummary), onWriteFeed func(feed domain.WidgetFeed),
+	onSendSchedule func(push domain.PushNotifications), onTestWebhook func(webhook domain.Webhook), onTestNotification func(notifications domain.DesktopNotifications)) *PreferencesDialog {
+	pd := &PreferencesDialog{
+		onTestHook:         onTestHook,
+		onSendSummary:      onSendSummary,
+		onWriteFeed:        onWriteFeed,
+		onSendSchedule:     onSendSchedule,
+		onTestWebhook:      onTestWebhook,
+		onTestNotification: onTestNotification,
+		current:            current,
+	}
+	pd.setupUI(parent, timezones, settings.ActivePreset)
+
+	// Fill controls from the current settings
+	pd.automationCheck.SetChecked(settings.AutomationEnabled)
+	for _, h := range settings.Hooks {
+		pd.addHookRow(h)
+	}
+	pd.setDailySummary(settings.DailySummary, settings.Favorites)
+	pd.setWidgetFeed(settings.WidgetFeed)
+	pd.setPushNotifications(settings.Push)
+	pd.setWebhook(settings.Webhook, settings.Favorites)
+	pd.setNotifications(settings.Notifications, settings.Favorites)
+	pd.caldavURLEdit.SetText(settings.CalDAV.CalendarURL)
+	pd.caldavUserEdit.SetText(settings.CalDAV.Username)
+	pd.caldavPasswordEdit.SetText(settings.CalDAV.Password)
+	selectCombo(pd.locationSourceCombo, locationSources, settings.LocationSource)
+	pd.setLocationProviders(settings.LocationProviders)
+	pd.secureLocationCheck.SetChecked(settings.SecureLocationOnly)
+	pd.setHome(settings.HomeLocation)
+	for _, e := range settings.CustomEvents {
+		pd.addCustomEventRow(e)
+	}
+	selectCombo(pd.coordinateFormatCombo, coordinateFormats, settings.CoordinateFormat)
+	selectCombo(pd.placeNameCombo, placeNameStyles, settings.PlaceNameStyle)
+	selectCombo(pd.timePanelLayoutCombo, timePanelLayouts, settings.TimePanelLayout)
+	selectCombo(pd.scratchRetentionCombo, scratchRetentions, settings.ScratchRetentionDays)
+	pd.homeTimezoneCombo.SetCurrentText(settings.HomeTimezone)
+	pd.copyTemplateEdit.SetPlainText(cmp.Or(settings.CopyTemplate, export.DefaultCopyTemplate))
+	pd.minimizeToTrayCheck.SetChecked(settings.MinimizeToTray)
+	pd.auroraCheck.SetChecked(settings.AuroraForecast)
+	pd.contactEmailEdit.SetText(settings.ContactEmail)
+	pd.searchLanguageEdit.SetText(strings.ReplaceAll(settings.SearchBias.Language, ",", ", "))
+	pd.searchCountriesEdit.SetText(strings.Join(settings.SearchBias.Countries, ", "))
+	pd.searchNearMapCheck.SetChecked(settings.SearchBias.NearMapView)
+	pd.tileURLEdit.SetText(settings.TileServer.URL)
+	pd.tileSubdomainsEdit.SetText(settings.TileServer.Subdomains)
+	pd.tileAPIKeyEdit.SetText(settings.TileServer.APIKey)
+	return pd
+}
+
+// setupUI creates the dialog, its tabs, and the OK/Cancel buttons.
+//
+// The Network and Notifications tabs hold tabs of their own; a check that
+// fails on OK shows the tab with the offending field, e.g., Notifications
+// → Phone.
+func (pd *PreferencesDialog) setupUI(parent *qt.QWidget, timezones []string, activePreset string) {
+	pd.dialog = qt.NewQDialog(parent)
+	pd.dialog.SetWindowTitle("Preferences")
+	pd.dialog.Resize(640, 540)
+
+	layout := qt.NewQVBoxLayout(pd.dialog.QWidget)
+
+	network := qt.NewQTabWidget2()
+	network.AddTab(pd.createLocationTab(), "Location")
+	searchTab := network.AddTab(pd.createSearchTab(), "Search")
+
+	notifications := qt.NewQTabWidget2()
+	notifications.AddTab(pd.createDesktopTab(), "Desktop")
+	phoneTab := notifications.AddTab(pd.createPhoneTab(), "Phone")
+	webhookTab := notifications.AddTab(pd.createWebhookTab(), "Webhook")
+	summaryTab := notifications.AddTab(pd.createSummaryTab(), "Daily Summary")
+	feedTab := notifications.AddTab(pd.createFeedTab(), "Widget Feed")
+	calendarTab := notifications.AddTab(pd.createCalendarTab(), "Calendar")
+
+	tabs := qt.NewQTabWidget2()
+	tabs.AddTab(pd.createCalculationTab(activePreset), "Calculation")
+	displayTab := tabs.AddTab(pd.createDisplayTab(timezones), "Display")
+	networkTab := tabs.AddTab(network.QWidget, "Network")
+	notificationsTab := tabs.AddTab(notifications.QWidget, "Notifications")
+	mapTab := tabs.AddTab(pd.createMapTab(), "Map")
+	tabs.AddTab(pd.createAutomationTab(), "Automation")
+	layout.AddWidget(tabs.QWidget)
+
+	// showNotifications shows one of the Notifications tab's own tabs
+	showNotifications := func(index int) {
+		tabs.SetCurrentIndex(notificationsTab)
+		notifications.SetCurrentIndex(index)
+	}
+
+	// OK validates and asks for confirmation before closing
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Ok | qt.QDialogButtonBox__Cancel)
+	buttons.OnAccepted(func() {
+		if !pd.checkPush() {
+			showNotifications(phoneTab)
+			return
+		}
+		if !pd.checkWebhook() {
+			showNotifications(webhookTab)
+			return
+		}
+		if !pd.checkDailySummary() {
+			showNotifications(summaryTab)
+			return
+		}
+		if !pd.checkWidgetFeed() {
+			showNotifications(feedTab)
+			return
+		}
+		if !pd.checkCalDAV() {
+			showNotifications(calendarTab)
+			return
+		}
+		if !pd.checkHomeTimezone() || !pd.checkCopyTemplate() {
+			tabs.SetCurrentIndex(displayTab)
+			return
+		}
+		if !pd.checkContactEmail() || !pd.checkSearchBias() {
+			tabs.SetCurrentIndex(networkTab)
+			network.SetCurrentIndex(searchTab)
+			return
+		}
+		if !pd.checkTileServer() {
+			tabs.SetCurrentIndex(mapTab)
+			return
+		}
+		if pd.confirmNewCommands() {
+			pd.dialog.Accept()
+		}
+	})
+	buttons.OnRejected(func() {
+		pd.dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+}
+
+// createAutomationTab builds the Automation tab with the hook table.
+func (pd *PreferencesDialog) createAutomationTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	pd.automationCheck = qt.NewQCheckBox3("Run commands at sun phase transitions")
+	layout.AddWidget(pd.automationCheck.QWidget)
+
+	// Hook table: enabled checkbox | event picker | command line
+	pd.hookTable = qt.NewQTableWidget3(0, 3)
+	pd.hookTable.SetHorizontalHeaderLabels([]string{"On", "Event", "Command"})
+	pd.hookTable.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	pd.hookTable.VerticalHeader().SetVisible(false)
+	pd.hookTable.HorizontalHeader().SetStretchLastSection(true)
+	layout.AddWidget(pd.hookTable.QWidget)
+
+	// Row buttons
+	buttonLayout := qt.NewQHBoxLayout2()
+	addBtn := qt.NewQPushButton3("Add")
+	addBtn.OnClicked(func() {
+		pd.addHookRow(domain.Hook{Event: domain.EventGoldenEveningStart, Enabled: true})
+	})
+	removeBtn := qt.NewQPushButton3("Remove")
+	removeBtn.OnClicked(pd.removeSelectedRow)
+	testBtn := qt.NewQPushButton3("Test")
+	testBtn.SetToolTip("Run the selected command once now")
+	testBtn.OnClicked(pd.testSelectedRow)
+	buttonLayout.AddWidget(addBtn.QWidget)
+	buttonLayout.AddWidget(removeBtn.QWidget)
+	buttonLayout.AddWidget(testBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	// Placeholder reference
+	help := qt.NewQLabel3("Commands run without a shell (use sh -c '...' for pipes). " +
+		"Placeholders: {{.Event}}, {{.EventLabel}}, {{.Time}}, {{.Date}}, {{.Clock}}, " +
+		"{{.Unix}}, {{.Location}}, {{.Latitude}}, {{.Longitude}}")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	return tab
+}
+
+// createSummaryTab builds the Daily Summary tab.
+//
+// miqt API notes:
+//   - NewQTimeEdit2(): Time of day picker (suffix "2" = no params)
+//   - QListWidgetItem.SetFlags(ItemIsUserCheckable | ...) adds a checkbox
+//   - QFileDialog_GetSaveFileName4 picks a file that may not exist yet
+func (pd *PreferencesDialog) createSummaryTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	timeRow := qt.NewQHBoxLayout2()
+	pd.summaryCheck = qt.NewQCheckBox3("Send tomorrow's times every day at")
+	timeRow.AddWidget(pd.summaryCheck.QWidget)
+	pd.summaryTimeEdit = qt.NewQTimeEdit2()
+	pd.summaryTimeEdit.SetDisplayFormat("HH:mm")
+	timeRow.AddWidget(pd.summaryTimeEdit.QWidget)
+	timeRow.AddStretch()
+	layout.AddLayout(timeRow.QLayout)
+
+	layout.AddWidget(qt.NewQLabel3("Favorites to include:").QWidget)
+	pd.summaryFavoritesList = qt.NewQListWidget(nil)
+	layout.AddWidget(pd.summaryFavoritesList.QWidget)
+
+	form := qt.NewQFormLayout2()
+	pd.deliveryCombo = qt.NewQComboBox2()
+	for _, d := range summaryDeliveries {
+		pd.deliveryCombo.AddItem(d.label)
+	}
+	pd.deliveryCombo.OnCurrentIndexChanged(func(int) {
+		pd.updateDeliveryFields()
+	})
+	form.AddRow3("Delivery:", pd.deliveryCombo.QWidget)
+
+	fileRow := qt.NewQHBoxLayout2()
+	pd.summaryFileEdit = qt.NewQLineEdit2()
+	pd.summaryFileEdit.SetPlaceholderText("e.g., a file in a synced folder")
+	fileRow.AddWidget(pd.summaryFileEdit.QWidget)
+	pd.summaryFileBtn = qt.NewQPushButton3("Browse...")
+	pd.summaryFileBtn.OnClicked(func() {
+		path := qt.QFileDialog_GetSaveFileName4(pd.dialog.QWidget, "Daily Summary File",
+			"golden-hour.txt", "Text files (*.txt)")
+		if path != "" {
+			pd.summaryFileEdit.SetText(path)
+		}
+	})
+	fileRow.AddWidget(pd.summaryFileBtn.QWidget)
+	form.AddRow4("File:", fileRow.QLayout)
+
+	pd.summaryEmailEdit = qt.NewQLineEdit2()
+	pd.summaryEmailEdit.SetPlaceholderText("you@example.com")
+	form.AddRow3("Email to:", pd.summaryEmailEdit.QWidget)
+	pd.sendmailEdit = qt.NewQLineEdit2()
+	pd.sendmailEdit.SetPlaceholderText(domain.DefaultSendmailPath)
+	form.AddRow3("Sendmail:", pd.sendmailEdit.QWidget)
+	pd.smtpServerEdit = qt.NewQLineEdit2()
+	pd.smtpServerEdit.SetPlaceholderText(domain.DefaultSMTPServer)
+	form.AddRow3("SMTP server:", pd.smtpServerEdit.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	buttonLayout := qt.NewQHBoxLayout2()
+	sendBtn := qt.NewQPushButton3("Send Now")
+	sendBtn.SetToolTip("Send tomorrow's summary once now, to check the delivery")
+	sendBtn.OnClicked(pd.sendSummaryNow)
+	buttonLayout.AddWidget(sendBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	help := qt.NewQLabel3("The summary is sent while GoGoldenHour is running. Emails are " +
+		"sent without logging in, so use a local mail program (such as msmtp or Postfix) " +
+		"or a relay on your network.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	return tab
+}
+
+// setDailySummary fills the Daily Summary tab.
+func (pd *PreferencesDialog) setDailySummary(summary domain.DailySummary, favorites []domain.Favorite) {
+	pd.summaryCheck.SetChecked(summary.Enabled)
+	if hour, minute, err := domain.ParseSummaryTime(summary.Time); err == nil {
+		pd.summaryTimeEdit.SetTime(*qt.NewQTime2(hour, minute))
+	}
+
+	for _, f := range favorites {
+		pd.summaryFavoritesList.AddItem(f.Location.Name)
+		item := pd.summaryFavoritesList.Item(pd.summaryFavoritesList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+		if slices.Contains(summary.Favorites, f.ID) {
+			item.SetCheckState(qt.Checked)
+		}
+		pd.summaryFavoriteIDs = append(pd.summaryFavoriteIDs, f.ID)
+	}
+	if len(favorites) == 0 {
+		pd.summaryFavoritesList.AddItem("No favorites yet: mark places with ☆ in the Location panel")
+		pd.summaryFavoritesList.SetEnabled(false)
+	}
+
+	for i, d := range summaryDeliveries {
+		if d.method == summary.Delivery {
+			pd.deliveryCombo.SetCurrentIndex(i)
+		}
+	}
+	pd.summaryFileEdit.SetText(summary.FilePath)
+	pd.summaryEmailEdit.SetText(summary.EmailTo)
+	pd.sendmailEdit.SetText(summary.SendmailPath)
+	pd.smtpServerEdit.SetText(summary.SMTPServer)
+	pd.updateDeliveryFields()
+}
+
+// updateDeliveryFields enables the fields of the selected delivery method.
+func (pd *PreferencesDialog) updateDeliveryFields() {
+	method := pd.DailySummary().Delivery
+	isFile := method == domain.SummaryDeliveryFile
+	pd.summaryFileEdit.SetEnabled(isFile)
+	pd.summaryFileBtn.SetEnabled(isFile)
+	pd.summaryEmailEdit.SetEnabled(!isFile)
+	pd.sendmailEdit.SetEnabled(method == domain.SummaryDeliverySendmail)
+	pd.smtpServerEdit.SetEnabled(method == domain.SummaryDeliverySMTP)
+}
+
+// sendSummaryNow sends the summary as configured in the tab, whether or
+// not it is switched on.
+func (pd *PreferencesDialog) sendSummaryNow() {
+	summary := pd.DailySummary()
+	if err := summary.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Daily Summary",
+			fmt.Sprintf("The summary can't be sent: %v.", err))
+		return
+	}
+	if pd.onSendSummary != nil {
+		pd.onSendSummary(summary)
+	}
+}
+
+// checkDailySummary warns if the enabled summary couldn't be delivered.
+//
+// Returns true if the summary is off or its configuration is complete.
+func (pd *PreferencesDialog) checkDailySummary() bool {
+	summary := pd.DailySummary()
+	if !summary.Enabled {
+		return true
+	}
+	if err := summary.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Daily Summary",
+			fmt.Sprintf("The daily summary can't be sent: %v.", err))
+		return false
+	}
+	return true
+}
+
+// createFeedTab builds the Widget Feed tab.
+func (pd *PreferencesDialog) createFeedTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	intervalRow := qt.NewQHBoxLayout2()
+	pd.feedCheck = qt.NewQCheckBox3("Write the home location's times every")
+	intervalRow.AddWidget(pd.feedCheck.QWidget)
+	pd.feedIntervalSpin = qt.NewQSpinBox2()
+	pd.feedIntervalSpin.SetRange(domain.MinFeedIntervalMinutes, domain.MaxFeedIntervalMinutes)
+	pd.feedIntervalSpin.SetSuffix(" min")
+	intervalRow.AddWidget(pd.feedIntervalSpin.QWidget)
+	intervalRow.AddStretch()
+	layout.AddLayout(intervalRow.QLayout)
+
+	form := qt.NewQFormLayout2()
+	fileRow := qt.NewQHBoxLayout2()
+	pd.feedPathEdit = qt.NewQLineEdit2()
+	pd.feedPathEdit.SetPlaceholderText("e.g., ~/.cache/golden-hour.json")
+	fileRow.AddWidget(pd.feedPathEdit.QWidget)
+	browseBtn := qt.NewQPushButton3("Browse...")
+	browseBtn.OnClicked(func() {
+		name, filter := "golden-hour.json", "JSON files (*.json)"
+		if pd.WidgetFeed().Format == domain.FeedFormatRSS {
+			name, filter = "golden-hour.xml", "RSS feeds (*.xml *.rss)"
+		}
+		path := qt.QFileDialog_GetSaveFileName4(pd.dialog.QWidget, "Widget Feed File", name, filter)
+		if path != "" {
+			pd.feedPathEdit.SetText(path)
+		}
+	})
+	fileRow.AddWidget(browseBtn.QWidget)
+	form.AddRow4("File:", fileRow.QLayout)
+
+	pd.feedFormatCombo = qt.NewQComboBox2()
+	for _, f := range feedFormats {
+		pd.feedFormatCombo.AddItem(f.label)
+	}
+	form.AddRow3("Format:", pd.feedFormatCombo.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	buttonLayout := qt.NewQHBoxLayout2()
+	writeBtn := qt.NewQPushButton3("Write Now")
+	writeBtn.SetToolTip("Write the feed once now, to point a widget at it")
+	writeBtn.OnClicked(pd.writeFeedNow)
+	buttonLayout.AddWidget(writeBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	help := qt.NewQLabel3(fmt.Sprintf("For desktop widgets such as conky, Rainmeter or GeekTool: "+
+		"the file lists the golden and blue hours of the next %d hours at your home location, "+
+		"and is rewritten while GoGoldenHour is running.", domain.FeedHours))
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+	layout.AddStretch()
+
+	return tab
+}
+
+// setWidgetFeed fills the Widget Feed tab.
+func (pd *PreferencesDialog) setWidgetFeed(feed domain.WidgetFeed) {
+	pd.feedCheck.SetChecked(feed.Enabled)
+	pd.feedIntervalSpin.SetValue(feed.IntervalMinutes)
+	pd.feedPathEdit.SetText(feed.Path)
+	for i, f := range feedFormats {
+		if f.format == feed.Format {
+			pd.feedFormatCombo.SetCurrentIndex(i)
+		}
+	}
+}
+
+// writeFeedNow writes the feed as configured in the tab, whether or not
+// it is switched on.
+func (pd *PreferencesDialog) writeFeedNow() {
+	feed := pd.WidgetFeed()
+	if err := feed.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Widget Feed",
+			fmt.Sprintf("The feed can't be written: %v.", err))
+		return
+	}
+	if pd.onWriteFeed != nil {
+		pd.onWriteFeed(feed)
+	}
+}
+
+// checkWidgetFeed warns if the enabled feed couldn't be written.
+//
+// Returns true if the feed is off or its configuration is complete.
+func (pd *PreferencesDialog) checkWidgetFeed() bool {
+	feed := pd.WidgetFeed()
+	if !feed.Enabled {
+		return true
+	}
+	if err := feed.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Widget Feed",
+			fmt.Sprintf("The widget feed can't be written: %v.", err))
+		return false
+	}
+	return true
+}
+
+// createPhoneTab builds the Phone tab with the push notifications.
+//
+// miqt API notes:
+//   - NewQSpinBox2(): Integer field with arrows (suffix "2" = no params)
+func (pd *PreferencesDialog) createPhoneTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	leadRow := qt.NewQHBoxLayout2()
+	pd.pushCheck = qt.NewQCheckBox3("Push a reminder to my phone")
+	leadRow.AddWidget(pd.pushCheck.QWidget)
+	pd.pushLeadSpin = qt.NewQSpinBox2()
+	pd.pushLeadSpin.SetRange(0, domain.MaxPushLeadMinutes)
+	pd.pushLeadSpin.SetSuffix(" min")
+	leadRow.AddWidget(pd.pushLeadSpin.QWidget)
+	leadRow.AddWidget(qt.NewQLabel3("before:").QWidget)
+	leadRow.AddStretch()
+	layout.AddLayout(leadRow.QLayout)
+
+	pd.pushEventsList = qt.NewQListWidget(nil)
+	for _, kind := range domain.AllEventKinds() {
+		pd.pushEventsList.AddItem(kind.Label())
+		item := pd.pushEventsList.Item(pd.pushEventsList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+	}
+	layout.AddWidget(pd.pushEventsList.QWidget)
+
+	form := qt.NewQFormLayout2()
+	pd.pushServiceCombo = qt.NewQComboBox2()
+	for _, s := range pushServices {
+		pd.pushServiceCombo.AddItem(s.label)
+	}
+	pd.pushServiceCombo.OnCurrentIndexChanged(func(int) {
+		pd.updatePushFields()
+	})
+	form.AddRow3("Service:", pd.pushServiceCombo.QWidget)
+
+	pd.ntfyServerEdit = qt.NewQLineEdit2()
+	pd.ntfyServerEdit.SetPlaceholderText(domain.DefaultNtfyServer)
+	form.AddRow3("ntfy server:", pd.ntfyServerEdit.QWidget)
+	pd.ntfyTopicEdit = qt.NewQLineEdit2()
+	pd.ntfyTopicEdit.SetPlaceholderText("a hard-to-guess name, e.g., golden-hour-7f3k9q")
+	form.AddRow3("ntfy topic:", pd.ntfyTopicEdit.QWidget)
+	pd.pushoverTokenEdit = qt.NewQLineEdit2()
+	pd.pushoverTokenEdit.SetPlaceholderText("API token of your Pushover application")
+	pd.pushoverTokenEdit.SetEchoMode(qt.QLineEdit__PasswordEchoOnEdit)
+	form.AddRow3("Pushover app:", pd.pushoverTokenEdit.QWidget)
+	pd.pushoverUserEdit = qt.NewQLineEdit2()
+	pd.pushoverUserEdit.SetPlaceholderText("your user key")
+	form.AddRow3("Pushover key:", pd.pushoverUserEdit.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	buttonLayout := qt.NewQHBoxLayout2()
+	scheduleBtn := qt.NewQPushButton3(fmt.Sprintf("Send %d-Day Schedule", domain.PushScheduleDays))
+	scheduleBtn.SetToolTip("Push the golden and blue hours of the coming days at the current location")
+	scheduleBtn.OnClicked(pd.sendSchedule)
+	buttonLayout.AddWidget(scheduleBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	help := qt.NewQLabel3("Reminders are pushed for the current location while GoGoldenHour " +
+		"is running. Install the ntfy app and subscribe to the topic, or use the Pushover " +
+		"app with a token from pushover.net. Anyone who knows an ntfy topic on a public " +
+		"server can read it.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	return tab
+}
+
+// setPushNotifications fills the Phone tab.
+func (pd *PreferencesDialog) setPushNotifications(push domain.PushNotifications) {
+	pd.pushCheck.SetChecked(push.Enabled)
+	pd.pushLeadSpin.SetValue(push.LeadMinutes)
+	for row, kind := range domain.AllEventKinds() {
+		if slices.Contains(push.Events, kind) {
+			pd.pushEventsList.Item(row).SetCheckState(qt.Checked)
+		}
+	}
+	for i, s := range pushServices {
+		if s.service == push.Service {
+			pd.pushServiceCombo.SetCurrentIndex(i)
+		}
+	}
+	pd.ntfyServerEdit.SetText(push.NtfyServer)
+	pd.ntfyTopicEdit.SetText(push.NtfyTopic)
+	pd.pushoverTokenEdit.SetText(push.PushoverToken)
+	pd.pushoverUserEdit.SetText(push.PushoverUser)
+	pd.updatePushFields()
+}
+
+// updatePushFields enables the fields of the selected push service.
+func (pd *PreferencesDialog) updatePushFields() {
+	isNtfy := pd.PushNotifications().Service == domain.PushServiceNtfy
+	pd.ntfyServerEdit.SetEnabled(isNtfy)
+	pd.ntfyTopicEdit.SetEnabled(isNtfy)
+	pd.pushoverTokenEdit.SetEnabled(!isNtfy)
+	pd.pushoverUserEdit.SetEnabled(!isNtfy)
+}
+
+// sendSchedule pushes the schedule as configured in the tab, whether or
+// not the reminders are switched on.
+func (pd *PreferencesDialog) sendSchedule() {
+	push := pd.PushNotifications()
+	if err := push.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Phone",
+			fmt.Sprintf("The schedule can't be pushed: %v.", err))
+		return
+	}
+	if pd.onSendSchedule != nil {
+		pd.onSendSchedule(push)
+	}
+}
+
+// checkPush warns if the enabled reminders couldn't be pushed.
+//
+// Returns true if the reminders are off or their configuration is complete.
+func (pd *PreferencesDialog) checkPush() bool {
+	push := pd.PushNotifications()
+	if !push.Enabled {
+		return true
+	}
+	if err := push.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Phone",
+			fmt.Sprintf("Reminders can't be pushed: %v.", err))
+		return false
+	}
+	return true
+}
+
+// createWebhookTab builds the Webhook tab.
+func (pd *PreferencesDialog) createWebhookTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	leadRow := qt.NewQHBoxLayout2()
+	pd.webhookCheck = qt.NewQCheckBox3("Call a webhook")
+	leadRow.AddWidget(pd.webhookCheck.QWidget)
+	pd.webhookLeadSpin = qt.NewQSpinBox2()
+	pd.webhookLeadSpin.SetRange(0, domain.MaxPushLeadMinutes)
+	pd.webhookLeadSpin.SetSuffix(" min")
+	leadRow.AddWidget(pd.webhookLeadSpin.QWidget)
+	leadRow.AddWidget(qt.NewQLabel3("before:").QWidget)
+	leadRow.AddStretch()
+	layout.AddLayout(leadRow.QLayout)
+
+	listsRow := qt.NewQHBoxLayout2()
+	eventsGroup := qt.NewQGroupBox3("Events")
+	eventsLayout := qt.NewQVBoxLayout(eventsGroup.QWidget)
+	pd.webhookEventsList = qt.NewQListWidget(nil)
+	for _, kind := range domain.AllEventKinds() {
+		pd.webhookEventsList.AddItem(kind.Label())
+		item := pd.webhookEventsList.Item(pd.webhookEventsList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+	}
+	eventsLayout.AddWidget(pd.webhookEventsList.QWidget)
+	listsRow.AddWidget(eventsGroup.QWidget)
+
+	placesGroup := qt.NewQGroupBox3("At (none checked: current location)")
+	placesLayout := qt.NewQVBoxLayout(placesGroup.QWidget)
+	pd.webhookFavoritesList = qt.NewQListWidget(nil)
+	placesLayout.AddWidget(pd.webhookFavoritesList.QWidget)
+	listsRow.AddWidget(placesGroup.QWidget)
+	layout.AddLayout(listsRow.QLayout)
+
+	form := qt.NewQFormLayout2()
+	pd.webhookURLEdit = qt.NewQLineEdit2()
+	pd.webhookURLEdit.SetPlaceholderText("https://maker.ifttt.com/trigger/golden_hour/json/with/key/...")
+	form.AddRow3("URL:", pd.webhookURLEdit.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	buttonLayout := qt.NewQHBoxLayout2()
+	testBtn := qt.NewQPushButton3("Send Test")
+	testBtn.SetToolTip("Call the webhook once now, for the first checked event at the current location")
+	testBtn.OnClicked(pd.testWebhook)
+	buttonLayout.AddWidget(testBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	help := qt.NewQLabel3("The webhook is called while GoGoldenHour is running, with a JSON " +
+		"body naming the event, its time and the place. IFTTT's \"Receive a web request\" " +
+		"trigger gets the event, the time and the place as value1 to value3; Home Assistant " +
+		"and Node-RED can use the whole body.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	return tab
+}
+
+// setWebhook fills the Webhook tab.
+func (pd *PreferencesDialog) setWebhook(webhook domain.Webhook, favorites []domain.Favorite) {
+	pd.webhookCheck.SetChecked(webhook.Enabled)
+	pd.webhookLeadSpin.SetValue(webhook.LeadMinutes)
+	for row, kind := range domain.AllEventKinds() {
+		if slices.Contains(webhook.Events, kind) {
+			pd.webhookEventsList.Item(row).SetCheckState(qt.Checked)
+		}
+	}
+
+	for _, f := range favorites {
+		pd.webhookFavoritesList.AddItem(f.Location.Name)
+		item := pd.webhookFavoritesList.Item(pd.webhookFavoritesList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+		if slices.Contains(webhook.Favorites, f.ID) {
+			item.SetCheckState(qt.Checked)
+		}
+		pd.webhookFavoriteIDs = append(pd.webhookFavoriteIDs, f.ID)
+	}
+	if len(favorites) == 0 {
+		pd.webhookFavoritesList.AddItem("No favorites yet: the current location is used")
+		pd.webhookFavoritesList.SetEnabled(false)
+	}
+
+	pd.webhookURLEdit.SetText(webhook.URL)
+}
+
+// testWebhook calls the webhook as configured in the tab, whether or not
+// it is switched on.
+func (pd *PreferencesDialog) testWebhook() {
+	webhook := pd.Webhook()
+	if err := webhook.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Webhook",
+			fmt.Sprintf("The webhook can't be called: %v.", err))
+		return
+	}
+	if pd.onTestWebhook != nil {
+		pd.onTestWebhook(webhook)
+	}
+}
+
+// checkWebhook warns if the enabled webhook couldn't be called.
+//
+// Returns true if the webhook is off or its URL is valid.
+func (pd *PreferencesDialog) checkWebhook() bool {
+	webhook := pd.Webhook()
+	if !webhook.Enabled {
+		return true
+	}
+	if err := webhook.Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Webhook",
+			fmt.Sprintf("The webhook can't be called: %v.", err))
+		pd.webhookURLEdit.SetFocus()
+		return false
+	}
+	return true
+}
+
+// createDesktopTab builds the Notifications tab's Desktop tab.
+//
+// miqt API notes:
+//   - SetSpecialValueText shows "off" instead of the minimum (0)
+func (pd *PreferencesDialog) createDesktopTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	pd.notificationsCheck = qt.NewQCheckBox3("Show a desktop notification before:")
+	layout.AddWidget(pd.notificationsCheck.QWidget)
+
+	leadRow := qt.NewQHBoxLayout2()
+	leadRow.AddWidget(qt.NewQLabel3("Lead times:").QWidget)
+	for range domain.MaxNotificationLeads {
+		spin := qt.NewQSpinBox2()
+		spin.SetRange(0, domain.MaxPushLeadMinutes)
+		spin.SetSuffix(" min")
+		spin.SetSpecialValueText("off")
+		leadRow.AddWidget(spin.QWidget)
+		pd.notificationLeadSpins = append(pd.notificationLeadSpins, spin)
+	}
+	leadRow.AddStretch()
+	layout.AddLayout(leadRow.QLayout)
+
+	listsRow := qt.NewQHBoxLayout2()
+	eventsGroup := qt.NewQGroupBox3("Events")
+	eventsLayout := qt.NewQVBoxLayout(eventsGroup.QWidget)
+	pd.notificationEventsList = qt.NewQListWidget(nil)
+	for _, kind := range domain.AllEventKinds() {
+		pd.notificationEventsList.AddItem(kind.Label())
+		item := pd.notificationEventsList.Item(pd.notificationEventsList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+	}
+	eventsLayout.AddWidget(pd.notificationEventsList.QWidget)
+	listsRow.AddWidget(eventsGroup.QWidget)
+
+	placesGroup := qt.NewQGroupBox3("At (none checked: current location)")
+	placesLayout := qt.NewQVBoxLayout(placesGroup.QWidget)
+	pd.notificationFavoritesList = qt.NewQListWidget(nil)
+	placesLayout.AddWidget(pd.notificationFavoritesList.QWidget)
+	listsRow.AddWidget(placesGroup.QWidget)
+	layout.AddLayout(listsRow.QLayout)
+
+	buttonLayout := qt.NewQHBoxLayout2()
+	testBtn := qt.NewQPushButton3("Show Test")
+	testBtn.SetToolTip("Show a notification now, for the first checked event at the current location")
+	testBtn.OnClicked(func() {
+		if pd.onTestNotification != nil {
+			pd.onTestNotification(pd.Notifications())
+		}
+	})
+	buttonLayout.AddWidget(testBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	text := "Notifications are shown while GoGoldenHour is running, also while its window " +
+		"is minimized or hidden in the system tray. They don't need an internet connection, " +
+		"so they keep working in privacy mode. The tray icon's menu pauses them."
+	if !TrayAvailable() {
+		text = "This desktop has no system tray, so notifications are shown in the status " +
+			"bar of the window, which flashes in the taskbar."
+	}
+	help := qt.NewQLabel3(text)
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	return tab
+}
+
+// setNotifications fills the Desktop tab.
+func (pd *PreferencesDialog) setNotifications(notifications domain.DesktopNotifications, favorites []domain.Favorite) {
+	pd.notificationsCheck.SetChecked(notifications.Enabled)
+	for i, spin := range pd.notificationLeadSpins {
+		if i < len(notifications.LeadMinutes) {
+			spin.SetValue(notifications.LeadMinutes[i])
+		}
+	}
+	for row, kind := range domain.AllEventKinds() {
+		if slices.Contains(notifications.Events, kind) {
+			pd.notificationEventsList.Item(row).SetCheckState(qt.Checked)
+		}
+	}
+
+	for _, f := range favorites {
+		pd.notificationFavoritesList.AddItem(f.Location.Name)
+		item := pd.notificationFavoritesList.Item(pd.notificationFavoritesList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+		if slices.Contains(notifications.Favorites, f.ID) {
+			item.SetCheckState(qt.Checked)
+		}
+		pd.notificationFavoriteIDs = append(pd.notificationFavoriteIDs, f.ID)
+	}
+	if len(favorites) == 0 {
+		pd.notificationFavoritesList.AddItem("No favorites yet: the current location is used")
+		pd.notificationFavoritesList.SetEnabled(false)
+	}
+}
+
+// createCalendarTab builds the Calendar tab with the CalDAV login.
+func (pd *PreferencesDialog) createCalendarTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	form := qt.NewQFormLayout2()
+	pd.caldavURLEdit = qt.NewQLineEdit2()
+	pd.caldavURLEdit.SetPlaceholderText("https://cloud.example.com/remote.php/dav/calendars/me/shoots/ (default: off)")
+	form.AddRow3("Calendar URL:", pd.caldavURLEdit.QWidget)
+	pd.caldavUserEdit = qt.NewQLineEdit2()
+	form.AddRow3("Username:", pd.caldavUserEdit.QWidget)
+	pd.caldavPasswordEdit = qt.NewQLineEdit2()
+	pd.caldavPasswordEdit.SetPlaceholderText("an app password, if your server has them")
+	pd.caldavPasswordEdit.SetEchoMode(qt.QLineEdit__PasswordEchoOnEdit)
+	form.AddRow3("Password:", pd.caldavPasswordEdit.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	help := qt.NewQLabel3("File → Sync to Calendar sends the golden and blue hours of the " +
+		"planned dates (or the coming week) at the current location to this CalDAV calendar " +
+		"(Nextcloud, Radicale, iCloud, Fastmail, ...). Syncing again updates the events; " +
+		"events you changed in your calendar are left alone. A separate calendar for the " +
+		"shoots keeps them apart from your own events. The password is stored in the " +
+		"settings file.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+	layout.AddStretch()
+
+	return tab
+}
+
+// checkCalDAV warns if the calendar sync configuration is unusable.
+//
+// Returns true if no calendar is set or the configuration is complete.
+func (pd *PreferencesDialog) checkCalDAV() bool {
+	if err := pd.CalDAV().Check(); err != nil {
+		qt.QMessageBox_Warning(pd.dialog.QWidget, "Calendar",
+			fmt.Sprintf("Events can't be sent to the calendar: %v.", err))
+		pd.caldavURLEdit.SetFocus()
+		return false
+	}
+	return true
+}
+
+// createCalculationTab builds the Calculation tab with the table of the
+// active preset's custom events.
+//
+// miqt API notes:
+//   - NewQDoubleSpinBox2(): Spin box for fractional degrees
+func (pd *PreferencesDialog) createCalculationTab(activePreset string) *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	// Event table: name | elevation
+	pd.customEventTable = qt.NewQTableWidget3(0, 2)
+	pd.customEventTable.SetHorizontalHeaderLabels([]string{"Name", "Sun elevation"})
+	pd.customEventTable.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	pd.customEventTable.VerticalHeader().SetVisible(false)
+	pd.customEventTable.HorizontalHeader().SetSectionResizeMode2(0, qt.QHeaderView__Stretch)
+	layout.AddWidget(pd.customEventTable.QWidget)
+
+	// Row buttons
+	buttonLayout := qt.NewQHBoxLayout2()
+	pd.addCustomEventBtn = qt.NewQPushButton3("Add")
+	pd.addCustomEventBtn.OnClicked(func() {
+		pd.addCustomEventRow(domain.CustomEvent{Name: "Sun at 15°", Elevation: 15})
+	})
+	removeBtn := qt.NewQPushButton3("Remove")
+	removeBtn.OnClicked(pd.removeSelectedCustomEvent)
+	buttonLayout.AddWidget(pd.addCustomEventBtn.QWidget)
+	buttonLayout.AddWidget(removeBtn.QWidget)
+	buttonLayout.AddStretch()
+	layout.AddLayout(buttonLayout.QLayout)
+
+	saved := "Not saved with a preset; use Save As... in the toolbar to keep them."
+	if activePreset != "" {
+		saved = fmt.Sprintf("Saved with the preset %q.", activePreset)
+	}
+	help := qt.NewQLabel3(saved + " The time panel shows when the rising and the setting " +
+		"sun pass each elevation, e.g., -12° for nautical twilight or -18° for a fully dark " +
+		"sky. Negative elevations are below the horizon. The golden and blue hour angles " +
+		"are set in the Settings panel of the main window.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	return tab
+}
+
+// addCustomEventRow appends a row for a custom event to the table.
+func (pd *PreferencesDialog) addCustomEventRow(event domain.CustomEvent) {
+	if len(pd.customEventSpins) >= domain.MaxCustomEvents {
+		return
+	}
+	row := pd.customEventTable.RowCount()
+	pd.customEventTable.InsertRow(row)
+	pd.customEventTable.SetItem(row, 0, qt.NewQTableWidgetItem2(event.Name))
+
+	spin := qt.NewQDoubleSpinBox2()
+	spin.SetRange(domain.MinCustomElevation, domain.MaxCustomElevation)
+	spin.SetDecimals(1)
+	spin.SetSingleStep(0.5)
+	spin.SetSuffix("°")
+	spin.SetValue(event.Elevation)
+	pd.customEventTable.SetCellWidget(row, 1, spin.QWidget)
+	pd.customEventSpins = append(pd.customEventSpins, spin)
+
+	pd.addCustomEventBtn.SetEnabled(len(pd.customEventSpins) < domain.MaxCustomEvents)
+}
+
+// removeSelectedCustomEvent deletes the currently selected custom event row.
+func (pd *PreferencesDialog) removeSelectedCustomEvent() {
+	row := pd.customEventTable.CurrentRow()
+	if row < 0 || row >= len(pd.customEventSpins) {
+		return
+	}
+	pd.customEventTable.RemoveRow(row)
+	pd.customEventSpins = append(pd.customEventSpins[:row], pd.customEventSpins[row+1:]...)
+	pd.addCustomEventBtn.SetEnabled(true)
+}
+
+// createLocationTab builds the Network tab's Location tab with the
+// location source, the provider fallback order, timeouts and the HTTPS-only
+// option.
+//
+// miqt API notes:
+//   - TakeItem(row)/InsertItem(row, item): Move a list entry
+//   - SetSuffix(" s"): Unit shown in a spin box
+func (pd *PreferencesDialog) createLocationTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	// Backend used by auto-detect and the location panel's "Detect" button
+	sourceForm := qt.NewQFormLayout2()
+	homeRow := qt.NewQHBoxLayout2()
+	pd.homeLabel = qt.NewQLabel2()
+	pd.homeLabel.SetToolTip("Shown on the first start and whenever the location can't be detected")
+	homeRow.AddWidget(pd.homeLabel.QWidget)
+	homeRow.AddStretch()
+	setHomeBtn := qt.NewQPushButton3("Set Current as Home")
+	setHomeBtn.SetToolTip("Use " + pd.current.Name + " as the home location")
+	setHomeBtn.OnClicked(func() {
+		loc := pd.current
+		pd.setHome(&loc)
+	})
+	homeRow.AddWidget(setHomeBtn.QWidget)
+	resetHomeBtn := qt.NewQPushButton3("Reset")
+	resetHomeBtn.SetToolTip("Use " + domain.DefaultLocation().Name + " as the home location")
+	resetHomeBtn.OnClicked(func() { pd.setHome(nil) })
+	homeRow.AddWidget(resetHomeBtn.QWidget)
+	sourceForm.AddRow4("Home:", homeRow.QLayout)
+	pd.locationSourceCombo = qt.NewQComboBox2()
+	pd.locationSourceCombo.AddItem("IP address (approximate)")
+	pd.locationSourceCombo.AddItem("System location services")
+	pd.locationSourceCombo.AddItem("Map (browser geolocation)")
+	pd.locationSourceCombo.SetToolTip("System location uses Wi-Fi/GPS positioning " +
+		"(GeoClue on Linux, Windows Location); map location uses the web engine's " +
+		"geolocation. Both fall back to the providers below")
+	sourceForm.AddRow3("Location:", pd.locationSourceCombo.QWidget)
+	layout.AddLayout(sourceForm.QLayout)
+
+	orderLabel := qt.NewQLabel3("Detect the location with the first of these that answers:")
+	layout.AddWidget(orderLabel.QWidget)
+
+	listRow := qt.NewQHBoxLayout2()
+	pd.providerList = qt.NewQListWidget(nil)
+	listRow.AddWidget(pd.providerList.QWidget)
+	moveButtons := qt.NewQVBoxLayout2()
+	upBtn := qt.NewQPushButton3("Move Up")
+	upBtn.OnClicked(func() { pd.moveSelectedProvider(-1) })
+	downBtn := qt.NewQPushButton3("Move Down")
+	downBtn.OnClicked(func() { pd.moveSelectedProvider(1) })
+	moveButtons.AddWidget(upBtn.QWidget)
+	moveButtons.AddWidget(downBtn.QWidget)
+	moveButtons.AddStretch()
+	listRow.AddLayout(moveButtons.QLayout)
+	layout.AddLayout(listRow.QLayout)
+
+	// One timeout per provider, in the default order
+	timeoutForm := qt.NewQFormLayout2()
+	pd.providerTimeouts = make(map[string]*qt.QSpinBox)
+	for _, p := range domain.DefaultLocationProviders() {
+		spin := qt.NewQSpinBox2()
+		spin.SetRange(1, 120)
+		spin.SetSuffix(" s")
+		spin.SetToolTip("How long to wait before trying the next provider")
+		timeoutForm.AddRow3(p.Label()+" timeout:", spin.QWidget)
+		pd.providerTimeouts[p.ID] = spin
+	}
+	layout.AddLayout(timeoutForm.QLayout)
+
+	pd.secureLocationCheck = qt.NewQCheckBox3("Only use providers with encrypted connections (HTTPS)")
+	pd.secureLocationCheck.SetToolTip("Skips ip-api.com, which is only reachable over plain HTTP,\n" +
+		"even where it is checked above")
+	layout.AddWidget(pd.secureLocationCheck.QWidget)
+
+	help := qt.NewQLabel3("If a service is blocked on your network (e.g., plain HTTP lookups " +
+		"by a company firewall), the next one is tried. The Location setting above still " +
+		"applies: \"System location services\" always goes first, and the map's browser " +
+		"geolocation falls back to this list.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+	layout.AddStretch()
+
+	return tab
+}
+
+// setLocationProviders fills the Location tab.
+func (pd *PreferencesDialog) setLocationProviders(providers []domain.LocationProvider) {
+	for _, p := range providers {
+		pd.providerList.AddItem(p.Label())
+		item := pd.providerList.Item(pd.providerList.Count() - 1)
+		item.SetFlags(item.Flags() | qt.ItemIsUserCheckable)
+		item.SetCheckState(qt.Unchecked)
+		if p.Enabled {
+			item.SetCheckState(qt.Checked)
+		}
+		pd.providerIDs = append(pd.providerIDs, p.ID)
+		if spin, ok := pd.providerTimeouts[p.ID]; ok {
+			spin.SetValue(p.TimeoutSeconds)
+		}
+	}
+}
+
+// setHome shows the home location in the Location tab (nil for the
+// default, London).
+func (pd *PreferencesDialog) setHome(home *domain.Location) {
+	pd.home = home
+	if home == nil {
+		pd.homeLabel.SetText(domain.DefaultLocation().Name + " (default)")
+		return
+	}
+	pd.homeLabel.SetText(home.Name)
+}
+
+// moveSelectedProvider moves the selected provider up (delta -1) or down
+// (delta 1) in the fallback order.
+func (pd *PreferencesDialog) moveSelectedProvider(delta int) {
+	row := pd.providerList.CurrentRow()
+	target := row + delta
+	if row < 0 || target < 0 || target >= len(pd.providerIDs) {
+		return
+	}
+	item := pd.providerList.TakeItem(row)
+	pd.providerList.InsertItem(target, item)
+	pd.providerList.SetCurrentRow(target)
+	pd.providerIDs[row], pd.providerIDs[target] = pd.providerIDs[target], pd.providerIDs[row]
+}
+
+// createDisplayTab builds the Display tab with the formats, the home
+// clock's timezone, the Copy Times template, the tray option and the
+// aurora forecast.
+//
+// miqt API notes:
+//   - SetEditable(true): The combo box accepts typed text (CurrentText)
+//   - AddItems(texts): Appends all list entries at once
+func (pd *PreferencesDialog) createDisplayTab(timezones []string) *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	formatsBox := qt.NewQGroupBox3("Formats")
+	formatsForm := qt.NewQFormLayout(formatsBox.QWidget)
+
+	// Used by the location panel, the map cursor readout and exports
+	pd.coordinateFormatCombo = qt.NewQComboBox2()
+	pd.coordinateFormatCombo.AddItem("Decimal degrees (48.8566° N)")
+	pd.coordinateFormatCombo.AddItem("Degrees, minutes, seconds (48°51'23.8\" N)")
+	formatsForm.AddRow3("Coordinates:", pd.coordinateFormatCombo.QWidget)
+
+	// Used by the location panel, favorites, status bar and exports
+	pd.placeNameCombo = qt.NewQComboBox2()
+	pd.placeNameCombo.AddItem("Short (Paris, France)")
+	pd.placeNameCombo.AddItem("Medium (Paris, Île-de-France, France)")
+	pd.placeNameCombo.AddItem("Full address")
+	formatsForm.AddRow3("Place names:", pd.placeNameCombo.QWidget)
+
+	pd.timePanelLayoutCombo = qt.NewQComboBox2()
+	pd.timePanelLayoutCombo.AddItem("Compact (golden/blue hour grid)")
+	pd.timePanelLayoutCombo.AddItem("Detailed (all events in order)")
+	pd.timePanelLayoutCombo.SetToolTip("How the Sun Times panel shows the day")
+	formatsForm.AddRow3("Sun times:", pd.timePanelLayoutCombo.QWidget)
+
+	// Map clicks and searches that weren't saved as favorites
+	pd.scratchRetentionCombo = qt.NewQComboBox2()
+	pd.scratchRetentionCombo.AddItem("This session only")
+	pd.scratchRetentionCombo.AddItem("1 day")
+	pd.scratchRetentionCombo.AddItem("7 days")
+	pd.scratchRetentionCombo.AddItem("30 days")
+	pd.scratchRetentionCombo.AddItem("90 days")
+	pd.scratchRetentionCombo.AddItem("Forever")
+	pd.scratchRetentionCombo.SetToolTip("How long locations that aren't favorites stay in the Recent menu")
+	formatsForm.AddRow3("Scratch history:", pd.scratchRetentionCombo.QWidget)
+	layout.AddWidget(formatsBox.QWidget)
+
+	clockBox := qt.NewQGroupBox3("Dual Clock")
+	clockLayout := qt.NewQVBoxLayout(clockBox.QWidget)
+	clockForm := qt.NewQFormLayout2()
+	pd.homeTimezoneCombo = qt.NewQComboBox2()
+	pd.homeTimezoneCombo.SetEditable(true)
+	pd.homeTimezoneCombo.AddItem("") // No home clock
+	pd.homeTimezoneCombo.AddItems(timezones)
+	pd.homeTimezoneCombo.LineEdit().SetPlaceholderText("e.g., America/New_York (default: off)")
+	clockForm.AddRow3("Home timezone:", pd.homeTimezoneCombo.QWidget)
+	clockLayout.AddLayout(clockForm.QLayout)
+
+	clockHelp := qt.NewQLabel3("Event times are also shown on your home clock, e.g., to plan " +
+		"calls at home around a shoot abroad. Nothing is added for places whose clocks " +
+		"read the same as yours. Leave empty to show local times only.")
+	clockHelp.SetWordWrap(true)
+	clockHelp.SetStyleSheet("color: gray; font-size: 11px;")
+	clockLayout.AddWidget(clockHelp.QWidget)
+	layout.AddWidget(clockBox.QWidget)
+
+	copyBox := qt.NewQGroupBox3("Copy Times")
+	copyLayout := qt.NewQVBoxLayout(copyBox.QWidget)
+	pd.copyTemplateEdit = qt.NewQPlainTextEdit2()
+	pd.copyTemplateEdit.SetToolTip("Text that Edit → Copy Times puts on the clipboard")
+	copyLayout.AddWidget(pd.copyTemplateEdit.QWidget)
+
+	copyHelpRow := qt.NewQHBoxLayout2()
+	copyHelp := qt.NewQLabel3("Placeholders: {{.Location}} {{.Date}} {{.Sunrise}} {{.Sunset}} " +
+		"{{.SolarNoon}} {{.GoldenMorning}} {{.GoldenEvening}} {{.BlueMorning}} {{.BlueEvening}} " +
+		"{{.ShootingLight}} {{.Coordinates}} {{.Timezone}}, and " +
+		"{{range .Events}}{{.Time}} {{.Label}}{{end}} for every event.")
+	copyHelp.SetWordWrap(true)
+	copyHelp.SetStyleSheet("color: gray; font-size: 11px;")
+	copyHelpRow.AddWidget(copyHelp.QWidget)
+	defaultBtn := qt.NewQPushButton3("Default")
+	defaultBtn.SetToolTip("Go back to the built-in Markdown summary")
+	defaultBtn.OnClicked(func() {
+		pd.copyTemplateEdit.SetPlainText(export.DefaultCopyTemplate)
+	})
+	copyHelpRow.AddWidget(defaultBtn.QWidget)
+	copyLayout.AddLayout(copyHelpRow.QLayout)
+	layout.AddWidget(copyBox.QWidget)
+
+	trayBox := qt.NewQGroupBox3("System Tray")
+	trayLayout := qt.NewQVBoxLayout(trayBox.QWidget)
+	pd.minimizeToTrayCheck = qt.NewQCheckBox3("Minimize to the system tray")
+	pd.minimizeToTrayCheck.SetToolTip("Hide the minimized window; click the tray icon to bring it back")
+	if !TrayAvailable() {
+		pd.minimizeToTrayCheck.SetEnabled(false)
+		pd.minimizeToTrayCheck.SetToolTip("This desktop has no system tray")
+	}
+	trayLayout.AddWidget(pd.minimizeToTrayCheck.QWidget)
+	layout.AddWidget(trayBox.QWidget)
+
+	astroBox := qt.NewQGroupBox3("Astro")
+	astroLayout := qt.NewQVBoxLayout(astroBox.QWidget)
+	pd.auroraCheck = qt.NewQCheckBox3("Aurora forecast (Kp index from NOAA SWPC)")
+	pd.auroraCheck.SetToolTip("Rate each night for the aurora in the Astro panel; worth it at high latitudes")
+	astroLayout.AddWidget(pd.auroraCheck.QWidget)
+	layout.AddWidget(astroBox.QWidget)
+
+	return tab
+}
+
+// checkCopyTemplate warns if the copy template doesn't parse or uses an
+// unknown placeholder.
+//
+// Returns true if the template is usable.
+func (pd *PreferencesDialog) checkCopyTemplate() bool {
+	err := export.CheckCopyTemplate(pd.CopyTemplate())
+	if err == nil {
+		return true
+	}
+	qt.QMessageBox_Warning(pd.dialog.QWidget, "Copy Times", err.Error())
+	pd.copyTemplateEdit.SetFocus()
+	return false
+}
+
+// checkHomeTimezone warns if the home timezone isn't a timezone.
+//
+// Returns true if the field is empty or valid.
+func (pd *PreferencesDialog) checkHomeTimezone() bool {
+	zone := pd.HomeTimezone()
+	if zone == "" || domain.ValidTimezone(zone) {
+		return true
+	}
+	qt.QMessageBox_Warning(pd.dialog.QWidget, "Home Timezone",
+		fmt.Sprintf("%q is not a timezone. Pick one from the list (e.g., America/New_York), "+
+			"or leave the field empty.", zone))
+	pd.homeTimezoneCombo.SetFocus()
+	return false
+}
+
+// createSearchTab builds the Network tab's Search tab with the contact
+// email and the search bias.
+//
+// miqt API notes:
+//   - NewQFormLayout(parent): Label/field rows
+//   - AddRow3(label, field): Row with a text label
+func (pd *PreferencesDialog) createSearchTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	form := qt.NewQFormLayout2()
+	pd.contactEmailEdit = qt.NewQLineEdit2()
+	pd.contactEmailEdit.SetPlaceholderText("you@example.com (optional)")
+	form.AddRow3("Contact email:", pd.contactEmailEdit.QWidget)
+	layout.AddLayout(form.QLayout)
+
+	help := qt.NewQLabel3("Sent to OpenStreetMap's Nominatim service with location searches " +
+		"and map clicks, as its usage policy recommends, so its operators can contact you " +
+		"about a problem with your requests instead of blocking them. Leave empty to " +
+		"identify requests by the GoGoldenHour project page only.")
+	help.SetWordWrap(true)
+	help.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(help.QWidget)
+
+	// Search bias: name languages, countries and the map area
+	searchBox := qt.NewQGroupBox3("Search")
+	searchLayout := qt.NewQVBoxLayout(searchBox.QWidget)
+	searchForm := qt.NewQFormLayout2()
+	pd.searchLanguageEdit = qt.NewQLineEdit2()
+	pd.searchLanguageEdit.SetPlaceholderText("e.g., de, en (default: system language)")
+	searchForm.AddRow3("Languages:", pd.searchLanguageEdit.QWidget)
+	pd.searchCountriesEdit = qt.NewQLineEdit2()
+	pd.searchCountriesEdit.SetPlaceholderText("e.g., at, de (default: worldwide)")
+	searchForm.AddRow3("Countries:", pd.searchCountriesEdit.QWidget)
+	searchLayout.AddLayout(searchForm.QLayout)
+	pd.searchNearMapCheck = qt.NewQCheckBox3("Prefer places in the map view")
+	searchLayout.AddWidget(pd.searchNearMapCheck.QWidget)
+
+	searchHelp := qt.NewQLabel3("Place names are shown in the first of the languages Nominatim " +
+		"knows. Countries are two-letter codes; searches then only find places there. " +
+		"Places in the map view are listed first, but others are still found.")
+	searchHelp.SetWordWrap(true)
+	searchHelp.SetStyleSheet("color: gray; font-size: 11px;")
+	searchLayout.AddWidget(searchHelp.QWidget)
+	layout.AddWidget(searchBox.QWidget)
+	layout.AddStretch()
+
+	return tab
+}
+
+// createMapTab builds the Map tab with the tile server.
+func (pd *PreferencesDialog) createMapTab() *qt.QWidget {
+	tab := qt.NewQWidget(nil)
+	layout := qt.NewQVBoxLayout(tab)
+
+	// Tile server: URL template with optional subdomains and API key
+	tilesBox := qt.NewQGroupBox3("Map Tiles")
+	tilesLayout := qt.NewQVBoxLayout(tilesBox.QWidget)
+	tilesForm := qt.NewQFormLayout2()
+	pd.tileURLEdit = qt.NewQLineEdit2()
+	pd.tileURLEdit.SetPlaceholderText("https://tile.openstreetmap.org/{z}/{x}/{y}.png (default)")
+	tilesForm.AddRow3("Tile URL:", pd.tileURLEdit.QWidget)
+	pd.tileSubdomainsEdit = qt.NewQLineEdit2()
+	pd.tileSubdomainsEdit.SetPlaceholderText(domain.DefaultTileSubdomains)
+	tilesForm.AddRow3("Subdomains:", pd.tileSubdomainsEdit.QWidget)
+	pd.tileAPIKeyEdit = qt.NewQLineEdit2()
+	pd.tileAPIKeyEdit.SetPlaceholderText("(optional)")
+	// Shown in clear text only while editing
+	pd.tileAPIKeyEdit.SetEchoMode(qt.QLineEdit__PasswordEchoOnEdit)
+	tilesForm.AddRow3("API key:", pd.tileAPIKeyEdit.QWidget)
+	tilesLayout.AddLayout(tilesForm.QLayout)
+
+	pd.tileProviderLabel = qt.NewQLabel3("")
+	pd.tileProviderLabel.SetWordWrap(true)
+	pd.tileProviderLabel.SetOpenExternalLinks(true)
+	pd.tileProviderLabel.SetStyleSheet("font-size: 11px;")
+	pd.tileURLEdit.OnTextChanged(func(string) { pd.updateTileProvider() })
+	pd.updateTileProvider()
+	tilesLayout.AddWidget(pd.tileProviderLabel.QWidget)
+
+	tilesHelp := qt.NewQLabel3("Load the map from your own tile server, e.g., tileserver-gl " +
+		"at http://localhost:8080/styles/basic/{z}/{x}/{y}.png. Placeholders: {z}, {x}, {y} " +
+		"(or {-y}), {s} (one of the subdomains), {r} (@2x on high-DPI screens), {apikey}. " +
+		"Leave the URL empty to use OpenStreetMap.")
+	tilesHelp.SetWordWrap(true)
+	tilesHelp.SetStyleSheet("color: gray; font-size: 11px;")
+	tilesLayout.AddWidget(tilesHelp.QWidget)
+	layout.AddWidget(tilesBox.QWidget)
+	layout.AddStretch()
+
+	return tab
+}
+
+// updateTileProvider shows which provider serves the entered tile URL,
+// with a link to its usage policy and whether it allows bulk downloads.
+func (pd *PreferencesDialog) updateTileProvider() {
+	provider := pd.TileServer().Provider()
+	if provider.ID == "" {
+		pd.tileProviderLabel.SetText("Provider: " + html.EscapeString(provider.Name) +
+			" (your own server: no usage restrictions known)")
+		return
+	}
+	text := fmt.Sprintf(`Provider: %s (<a href="%s">usage policy</a>)`,
+		html.EscapeString(provider.Name), provider.PolicyURL)
+	if !provider.AllowsPrefetch {
+		text += ". Bulk downloading is not allowed."
+	}
+	pd.tileProviderLabel.SetText(text)
+}
+
+// checkTileServer warns if the tile server can't be used by the map.
+//
+// Returns true if the URL is empty or the server is valid.
+func (pd *PreferencesDialog) checkTileServer() bool {
+	err := pd.TileServer().Validate()
+	if err == nil {
+		return true
+	}
+	qt.QMessageBox_Warning(pd.dialog.QWidget, "Map Tiles",
+		fmt.Sprintf("The tile server can't be used: %v.", err))
+	pd.tileURLEdit.SetFocus()
+	return false
+}
+
+// checkSearchBias warns if a language or country code is malformed.
+//
+// Returns true if both fields are empty or valid.
+func (pd *PreferencesDialog) checkSearchBias() bool {
+	err := pd.SearchBias().Validate()
+	if err == nil {
+		return true
+	}
+	qt.QMessageBox_Warning(pd.dialog.QWidget, "Search",
+		fmt.Sprintf("The search preferences can't be used: %v.", err))
+	pd.searchLanguageEdit.SetFocus()
+	return false
+}
+
+// checkContactEmail warns if the contact email isn't a plain address.
+//
+// Returns true if the field is empty or valid.
+func (pd *PreferencesDialog) checkContactEmail() bool {
+	email := pd.ContactEmail()
+	if email == "" || domain.IsValidContactEmail(email) {
+		return true
+	}
+	qt.QMessageBox_Warning(pd.dialog.QWidget, "Contact Email",
+		fmt.Sprintf("%q is not an email address. Enter an address such as "+
+			"you@example.com, or leave the field empty.", email))
+	pd.contactEmailEdit.SetFocus()
+	return false
+}
+
+// addHookRow appends a row for a hook to the table.
+func (pd *PreferencesDialog) addHookRow(hook domain.Hook) {
+	row := pd.hookTable.RowCount()
+	pd.hookTable.InsertRow(row)
+
+	enabled := qt.NewQCheckBox2()
+	enabled.SetChecked(hook.Enabled)
+	pd.hookTable.SetCellWidget(row, hookColumnEnabled, enabled.QWidget)
+
+	// Event picker, items in daily order; index maps to AllEventKinds
+	event := qt.NewQComboBox2()
+	for i, kind := range domain.AllEventKinds() {
+		event.AddItem(kind.Label())
+		if kind == hook.Event {
+			event.SetCurrentIndex(i)
+		}
+	}
+	pd.hookTable.SetCellWidget(row, hookColumnEvent, event.QWidget)
+
+	pd.hookTable.SetItem(row, hookColumnCommand, qt.NewQTableWidgetItem2(hook.Command))
+	pd.hookRows = append(pd.hookRows, hookRow{enabled: enabled, event: event, confirmed: hook.Confirmed})
+	pd.hookTable.ResizeColumnToContents(hookColumnEvent)
+}
+
+// removeSelectedRow deletes the currently selected hook row.
+func (pd *PreferencesDialog) removeSelectedRow() {
+	row := pd.hookTable.CurrentRow()
+	if row < 0 || row >= len(pd.hookRows) {
+		return
+	}
+	pd.hookTable.RemoveRow(row)
+	pd.hookRows = append(pd.hookRows[:row], pd.hookRows[row+1:]...)
+}
+
+// testSelectedRow runs the selected hook once after confirmation.
+func (pd *PreferencesDialog) testSelectedRow() {
+	row := pd.hookTable.CurrentRow()
+	if row < 0 || row >= len(pd.hookRows) || pd.onTestHook == nil {
+		return
+	}
+
+	hook := pd.hookAt(row)
+	if hook.Command == "" {
+		return
+	}
+
+	answer := qt.QMessageBox_Question5(pd.dialog.QWidget, "Run Command",
+		fmt.Sprintf("Run this command now?\n\n%s", hook.Command),
+		qt.QMessageBox__Yes|qt.QMessageBox__No)
+	if answer == qt.QMessageBox__Yes {
+		pd.hookRows[row].confirmed = domain.CommandFingerprint(hook.Command)
+		pd.onTestHook(hook)
+	}
+}
+
+// hookAt reads the hook shown in a table row.
+func (pd *PreferencesDialog) hookAt(row int) domain.Hook {
+	r := pd.hookRows[row]
+
+	command := ""
+	if item := pd.hookTable.Item(row, hookColumnCommand); item != nil {
+		command = strings.TrimSpace(item.Text())
+	}
+
+	kinds := domain.AllEventKinds()
+	kind := kinds[0]
+	if i := r.event.CurrentIndex(); i >= 0 && i < len(kinds) {
+		kind = kinds[i]
+	}
+
+	return domain.Hook{
+		Event:     kind,
+		Command:   command,
+		Enabled:   r.enabled.IsChecked(),
+		Confirmed: r.confirmed,
+	}
+}
+
+// confirmNewCommands asks the user to approve commands not approved before.
+//
+// Every hook whose command doesn't match its approval fingerprint is listed,
+// including disabled hooks and while the master switch is off. On approval
+// the rows' fingerprints are updated, so Hooks returns them as confirmed.
+//
+// Returns true if there is nothing to confirm or the user approved, false
+// if the user declined (the dialog then stays open).
+func (pd *PreferencesDialog) confirmNewCommands() bool {
+	var rows []int
+	var unconfirmed []string
+	for row := range pd.hookRows {
+		if h := pd.hookAt(row); h.Command != "" && !h.IsConfirmed() {
+			rows = append(rows, row)
+			unconfirmed = append(unconfirmed, "• "+h.Event.Label()+": "+h.Command)
+		}
+	}
+	if len(unconfirmed) == 0 {
+		return true
+	}
+
+	answer := qt.QMessageBox_Question5(pd.dialog.QWidget, "Confirm Automation",
+		"The following commands can run automatically on this computer, "+
+			"with your user's permissions:\n\n"+strings.Join(unconfirmed, "\n")+
+			"\n\nOnly continue if you trust these commands.",
+		qt.QMessageBox__Yes|qt.QMessageBox__No)
+	if answer != qt.QMessageBox__Yes {
+		return false
+	}
+
+	for _, row := range rows {
+		pd.hookRows[row].confirmed = domain.CommandFingerprint(pd.hookAt(row).Command)
+	}
+	return true
+}
+
+// Exec shows the dialog modally.
+//
+// Returns true if the user clicked OK (and confirmed any new commands).
+func (pd *PreferencesDialog) Exec() bool {
+	return pd.dialog.Exec() == int(qt.QDialog__Accepted)
+}
+
+// AutomationEnabled returns the state of the automation master switch.
+func (pd *PreferencesDialog) AutomationEnabled() bool {
+	return pd.automationCheck.IsChecked()
+}
+
+// Hooks returns the hooks from the table, skipping rows without a command.
+func (pd *PreferencesDialog) Hooks() []domain.Hook {
+	var hooks []domain.Hook
+	for row := range pd.hookRows {
+		if h := pd.hookAt(row); h.Command != "" {
+			hooks = append(hooks, h)
+		}
+	}
+	return hooks
+}
+
+// DailySummary returns the daily summary configuration (fields trimmed;
+// empty program and server fields get their defaults).
+func (pd *PreferencesDialog) DailySummary() domain.DailySummary {
+	summary := domain.DailySummary{
+		Enabled:      pd.summaryCheck.IsChecked(),
+		Time:         pd.summaryTimeEdit.Time().ToStringWithFormat("HH:mm"),
+		Delivery:     domain.SummaryDeliveryFile,
+		FilePath:     strings.TrimSpace(pd.summaryFileEdit.Text()),
+		EmailTo:      strings.TrimSpace(pd.summaryEmailEdit.Text()),
+		SendmailPath: strings.TrimSpace(pd.sendmailEdit.Text()),
+		SMTPServer:   strings.TrimSpace(pd.smtpServerEdit.Text()),
+	}
+	if i := pd.deliveryCombo.CurrentIndex(); i >= 0 && i < len(summaryDeliveries) {
+		summary.Delivery = summaryDeliveries[i].method
+	}
+	if summary.SendmailPath == "" {
+		summary.SendmailPath = domain.DefaultSendmailPath
+	}
+	if summary.SMTPServer == "" {
+		summary.SMTPServer = domain.DefaultSMTPServer
+	}
+	for row, id := range pd.summaryFavoriteIDs {
+		if pd.summaryFavoritesList.Item(row).CheckState() == qt.Checked {
+			summary.Favorites = append(summary.Favorites, id)
+		}
+	}
+	return summary
+}
+
+// WidgetFeed returns the widget feed configuration (path trimmed).
+func (pd *PreferencesDialog) WidgetFeed() domain.WidgetFeed {
+	feed := domain.WidgetFeed{
+		Enabled:         pd.feedCheck.IsChecked(),
+		Path:            strings.TrimSpace(pd.feedPathEdit.Text()),
+		Format:          domain.FeedFormatJSON,
+		IntervalMinutes: pd.feedIntervalSpin.Value(),
+	}
+	if i := pd.feedFormatCombo.CurrentIndex(); i >= 0 && i < len(feedFormats) {
+		feed.Format = feedFormats[i].format
+	}
+	return feed
+}
+
+// PushNotifications returns the push configuration (fields trimmed; an
+// empty ntfy server gets the default).
+func (pd *PreferencesDialog) PushNotifications() domain.PushNotifications {
+	push := domain.PushNotifications{
+		Enabled:       pd.pushCheck.IsChecked(),
+		Service:       domain.PushServiceNtfy,
+		NtfyServer:    strings.TrimRight(strings.TrimSpace(pd.ntfyServerEdit.Text()), "/"),
+		NtfyTopic:     strings.TrimSpace(pd.ntfyTopicEdit.Text()),
+		PushoverToken: strings.TrimSpace(pd.pushoverTokenEdit.Text()),
+		PushoverUser:  strings.TrimSpace(pd.pushoverUserEdit.Text()),
+		LeadMinutes:   pd.pushLeadSpin.Value(),
+	}
+	if i := pd.pushServiceCombo.CurrentIndex(); i >= 0 && i < len(pushServices) {
+		push.Service = pushServices[i].service
+	}
+	if push.NtfyServer == "" {
+		push.NtfyServer = domain.DefaultNtfyServer
+	}
+	for row, kind := range domain.AllEventKinds() {
+		if pd.pushEventsList.Item(row).CheckState() == qt.Checked {
+			push.Events = append(push.Events, kind)
+		}
+	}
+	return push
+}
+
+// Webhook returns the webhook configuration (URL trimmed).
+func (pd *PreferencesDialog) Webhook() domain.Webhook {
+	webhook := domain.Webhook{
+		Enabled:     pd.webhookCheck.IsChecked(),
+		URL:         strings.TrimSpace(pd.webhookURLEdit.Text()),
+		LeadMinutes: pd.webhookLeadSpin.Value(),
+	}
+	for row, kind := range domain.AllEventKinds() {
+		if pd.webhookEventsList.Item(row).CheckState() == qt.Checked {
+			webhook.Events = append(webhook.Events, kind)
+		}
+	}
+	for row, id := range pd.webhookFavoriteIDs {
+		if pd.webhookFavoritesList.Item(row).CheckState() == qt.Checked {
+			webhook.Favorites = append(webhook.Favorites, id)
+		}
+	}
+	return webhook
+}
+
+// Notifications returns the desktop notifications as configured. Lead
+// times left "off" are omitted; the AppController sorts the rest (see
+// domain.DesktopNotifications.LeadMinutes).
+func (pd *PreferencesDialog) Notifications() domain.DesktopNotifications {
+	notifications := domain.DesktopNotifications{Enabled: pd.notificationsCheck.IsChecked()}
+	for _, spin := range pd.notificationLeadSpins {
+		if spin.Value() > 0 {
+			notifications.LeadMinutes = append(notifications.LeadMinutes, spin.Value())
+		}
+	}
+	for row, kind := range domain.AllEventKinds() {
+		if pd.notificationEventsList.Item(row).CheckState() == qt.Checked {
+			notifications.Events = append(notifications.Events, kind)
+		}
+	}
+	for row, id := range pd.notificationFavoriteIDs {
+		if pd.notificationFavoritesList.Item(row).CheckState() == qt.Checked {
+			notifications.Favorites = append(notifications.Favorites, id)
+		}
+	}
+	return notifications
+}
+
+// CalDAV returns the calendar sync login (without ETags, which the
+// AppController keeps).
+func (pd *PreferencesDialog) CalDAV() domain.CalDAVSync {
+	return domain.CalDAVSync{
+		CalendarURL: strings.TrimSpace(pd.caldavURLEdit.Text()),
+		Username:    strings.TrimSpace(pd.caldavUserEdit.Text()),
+		Password:    pd.caldavPasswordEdit.Text(),
+	}
+}
+
+// LocationProviders returns the location providers in the listed order,
+// with their checkboxes and timeouts.
+func (pd *PreferencesDialog) LocationProviders() []domain.LocationProvider {
+	providers := make([]domain.LocationProvider, len(pd.providerIDs))
+	for row, id := range pd.providerIDs {
+		providers[row] = domain.LocationProvider{
+			ID:             id,
+			Enabled:        pd.providerList.Item(row).CheckState() == qt.Checked,
+			TimeoutSeconds: pd.providerTimeouts[id].Value(),
+		}
+	}
+	return providers
+}
+
+// HomeLocation returns the home location, or nil for the default (see
+// domain.Settings.Home).
+func (pd *PreferencesDialog) HomeLocation() *domain.Location {
+	return pd.home
+}
+
+// CustomEvents returns the custom events in table order. Rows without a
+// name are omitted.
+func (pd *PreferencesDialog) CustomEvents() []domain.CustomEvent {
+	var events []domain.CustomEvent
+	for row, spin := range pd.customEventSpins {
+		name := ""
+		if item := pd.customEventTable.Item(row, 0); item != nil {
+			name = strings.TrimSpace(item.Text())
+		}
+		if name != "" {
+			events = append(events, domain.CustomEvent{Name: name, Elevation: spin.Value()})
+		}
+	}
+	return events
+}
+
+// LocationSource returns the detection backend (domain.LocationSourceIP,
+// domain.LocationSourceSystem or domain.LocationSourceBrowser).
+func (pd *PreferencesDialog) LocationSource() string {
+	return comboValue(pd.locationSourceCombo, locationSources)
+}
+
+// SecureLocationOnly reports whether location detection is limited to
+// providers reached over HTTPS.
+func (pd *PreferencesDialog) SecureLocationOnly() bool {
+	return pd.secureLocationCheck.IsChecked()
+}
+
+// CoordinateFormat returns the coordinate display format
+// (domain.CoordinateFormatDecimal or domain.CoordinateFormatDMS).
+func (pd *PreferencesDialog) CoordinateFormat() string {
+	return comboValue(pd.coordinateFormatCombo, coordinateFormats)
+}
+
+// PlaceNameStyle returns how much of place names is shown
+// (domain.PlaceNameShort, domain.PlaceNameMedium or domain.PlaceNameFull).
+func (pd *PreferencesDialog) PlaceNameStyle() string {
+	return comboValue(pd.placeNameCombo, placeNameStyles)
+}
+
+// TimePanelLayout returns the time panel layout (domain.TimePanelCompact
+// or domain.TimePanelDetailed).
+func (pd *PreferencesDialog) TimePanelLayout() string {
+	return comboValue(pd.timePanelLayoutCombo, timePanelLayouts)
+}
+
+// ScratchRetentionDays returns how long scratch locations are kept (see
+// domain.Settings.ScratchRetentionDays).
+func (pd *PreferencesDialog) ScratchRetentionDays() int {
+	return comboValue(pd.scratchRetentionCombo, scratchRetentions)
+}
+
+// HomeTimezone returns the home clock's timezone (trimmed; empty if none).
+func (pd *PreferencesDialog) HomeTimezone() string {
+	return strings.TrimSpace(pd.homeTimezoneCombo.CurrentText())
+}
+
+// CopyTemplate returns the Copy Times template, empty if it is the built-in
+// summary (so later versions can improve it).
+func (pd *PreferencesDialog) CopyTemplate() string {
+	text := pd.copyTemplateEdit.ToPlainText()
+	if strings.TrimSpace(text) == strings.TrimSpace(export.DefaultCopyTemplate) {
+		return ""
+	}
+	return text
+}
+
+// MinimizeToTray reports whether the minimized window hides in the system
+// tray.
+func (pd *PreferencesDialog) MinimizeToTray() bool {
+	return pd.minimizeToTrayCheck.IsChecked()
+}
+
+// AuroraForecast reports whether the Astro panel rates the night for the
+// aurora.
+func (pd *PreferencesDialog) AuroraForecast() bool {
+	return pd.auroraCheck.IsChecked()
+}
+
+// ContactEmail returns the contact email (trimmed; empty if none).
+func (pd *PreferencesDialog) ContactEmail() string {
+	return strings.TrimSpace(pd.contactEmailEdit.Text())
+}
+
+// SearchBias returns the search bias (lists normalized; see
+// domain.ParseLanguages and domain.ParseCountryCodes).
+func (pd *PreferencesDialog) SearchBias() domain.SearchBias {
+	return domain.SearchBias{
+		Language:    domain.ParseLanguages(pd.searchLanguageEdit.Text()),
+		Countries:   domain.ParseCountryCodes(pd.searchCountriesEdit.Text()),
+		NearMapView: pd.searchNearMapCheck.IsChecked(),
+	}
+}
+
+// TileServer returns the tile server (fields trimmed; zero value for
+// OpenStreetMap).
+func (pd *PreferencesDialog) TileServer() domain.TileServer {
+	server := domain.TileServer{
+		URL:        strings.TrimSpace(pd.tileURLEdit.Text()),
+		Subdomains: strings.TrimSpace(pd.tileSubdomainsEdit.Text()),
+		APIKey:     strings.TrimSpace(pd.tileAPIKeyEdit.Text()),
+	}
+	if server.IsDefault() {
+		// Subdomains and key mean nothing without a URL
+		return domain.TileServer{}
+	}
+	return server
+}
+
+// selectCombo selects the item of a combo box whose value is value.
+//
+// Parameters:
+//   - combo: A combo box whose items are in values order
+//   - values: The setting's values, e.g., coordinateFormats
+//   - value: The current value; unknown values leave the selection alone
+func selectCombo[T comparable](combo *qt.QComboBox, values []T, value T) {
+	if i := slices.Index(values, value); i >= 0 {
+		combo.SetCurrentIndex(i)
+	}
+}
+
+// comboValue returns the value of a combo box's selected item, or the first
+// value if none is selected (see selectCombo).
+func comboValue[T comparable](combo *qt.QComboBox, values []T) T {
+	if i := combo.CurrentIndex(); i >= 0 && i < len(values) {
+		return values[i]
+	}
+	return values[0]
+}
@@ -0,0 +1,136 @@
+package widgets
+
+import (
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// PresetBar
+// =============================================================================
+
+// unsavedPresetItem is the dropdown entry shown while no preset is active,
+// e.g., after the active preset was deleted.
+const unsavedPresetItem = "(unsaved)"
+
+// PresetBar is the toolbar that switches between presets of elevation
+// angles and custom events (see domain.Preset):
+//
+//	┌──────────────────────────────────────────────────────┐
+//	│ Preset: [ Landscape      ▾ ] [Save As...] [Delete]   │
+//	└──────────────────────────────────────────────────────┘
+//
+// Picking a preset applies it at once. "Save As..." keeps the current
+// values under a new name (or replaces a preset of that name), "Delete"
+// removes the preset shown; both ask through the callbacks, which own the
+// dialogs. The list is refreshed with SetPresets whenever the settings
+// change.
+type PresetBar struct {
+	// toolbar is the toolbar added to the main window.
+	toolbar *qt.QToolBar
+
+	// combo lists the presets, plus unsavedPresetItem while none is active.
+	combo *qt.QComboBox
+
+	// deleteButton removes the preset shown; disabled while none is active.
+	deleteButton *qt.QPushButton
+
+	// active is the name of the active preset ("" for none).
+	active string
+
+	// onSelect, onSaveAs and onDelete are invoked by the dropdown and the
+	// buttons.
+	onSelect func(name string)
+	onSaveAs func()
+	onDelete func(name string)
+}
+
+// NewPresetBar creates the preset toolbar.
+//
+// Parameters:
+//   - onSelect: Callback invoked when the user picks a preset
+//   - onSaveAs: Callback invoked by "Save As..."; asks for the name
+//   - onDelete: Callback invoked by "Delete" with the active preset's name
+//
+// miqt API notes:
+//   - NewQToolBar2(title): Toolbar with a title (shown in its context menu)
+//   - OnActivated fires only for the user's choices, not for SetCurrentIndex,
+//     so refreshing the list doesn't switch presets
+func NewPresetBar(onSelect func(name string), onSaveAs func(), onDelete func(name string)) *PresetBar {
+	pb := &PresetBar{onSelect: onSelect, onSaveAs: onSaveAs, onDelete: onDelete}
+
+	pb.toolbar = qt.NewQToolBar2("Presets")
+	pb.toolbar.SetMovable(false)
+
+	label := qt.NewQLabel3("Preset: ")
+	pb.toolbar.AddWidget(label.QWidget)
+
+	pb.combo = qt.NewQComboBox2()
+	pb.combo.SetMinimumWidth(160)
+	pb.combo.SetToolTip("Elevation angles and custom events for a kind of photography")
+	pb.combo.OnActivated(func(index int) {
+		defer crash.Recover()
+		name := pb.combo.ItemText(index)
+		if name == unsavedPresetItem || strings.EqualFold(name, pb.active) {
+			return
+		}
+		if pb.onSelect != nil {
+			pb.onSelect(name)
+		}
+	})
+	pb.toolbar.AddWidget(pb.combo.QWidget)
+
+	saveButton := qt.NewQPushButton3("Save As...")
+	saveButton.SetToolTip("Save the current angles and custom events as a preset")
+	saveButton.OnClicked(crash.Guard(func() {
+		if pb.onSaveAs != nil {
+			pb.onSaveAs()
+		}
+	}))
+	pb.toolbar.AddWidget(saveButton.QWidget)
+
+	pb.deleteButton = qt.NewQPushButton3("Delete")
+	pb.deleteButton.SetToolTip("Delete the preset shown; the current values stay")
+	pb.deleteButton.OnClicked(crash.Guard(func() {
+		if pb.active != "" && pb.onDelete != nil {
+			pb.onDelete(pb.active)
+		}
+	}))
+	pb.toolbar.AddWidget(pb.deleteButton.QWidget)
+
+	return pb
+}
+
+// Widget returns the toolbar, for adding to the main window.
+func (pb *PresetBar) Widget() *qt.QToolBar {
+	return pb.toolbar
+}
+
+// Active returns the name of the active preset ("" for none).
+func (pb *PresetBar) Active() string {
+	return pb.active
+}
+
+// SetPresets lists the presets and shows the active one (or
+// unsavedPresetItem if none is active).
+func (pb *PresetBar) SetPresets(presets []domain.Preset, active string) {
+	pb.active = active
+	pb.combo.Clear()
+	current := -1
+	if domain.FindPreset(presets, active) < 0 {
+		pb.active = ""
+		pb.combo.AddItem(unsavedPresetItem)
+		current = 0
+	}
+	for i, p := range presets {
+		pb.combo.AddItem(p.Name)
+		if strings.EqualFold(p.Name, active) {
+			current = i
+		}
+	}
+	pb.combo.SetCurrentIndex(current)
+	pb.deleteButton.SetEnabled(pb.active != "")
+}
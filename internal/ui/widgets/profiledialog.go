@@ -0,0 +1,55 @@
+package widgets
+
+import (
+	qt "github.com/mappu/miqt/qt6"
+)
+
+// =============================================================================
+// Profile Chooser
+// =============================================================================
+
+// defaultProfileItem is the chooser entry for the default profile.
+const defaultProfileItem = "(default)"
+
+// ChooseProfile asks which settings profile to start with.
+//
+// The chooser lists the default profile and the existing named profiles; a
+// new profile is created by typing its name:
+//
+//	┌─ GoGoldenHour ─────────────────────┐
+//	│ Profile (type a name for a new one)│
+//	│ [ work                         ▾ ] │
+//	│                  [ OK ] [ Cancel ] │
+//	└────────────────────────────────────┘
+//
+// Parameters:
+//   - profiles: The existing named profiles (storage.ListProfiles)
+//   - validate: Checks a typed name (storage.ValidateProfileName); invalid
+//     names are reported and the chooser is shown again
+//
+// Returns the chosen profile ("" for the default profile), and ok=false if
+// the user canceled. The dialog is modal and has no parent, as it is shown
+// before the main window exists.
+//
+// miqt API notes:
+//   - QInputDialog_GetItem4(parent, title, label, items, current, editable, ok):
+//     Combo box dialog; ok reports whether OK was pressed
+func ChooseProfile(profiles []string, validate func(string) error) (profile string, ok bool) {
+	items := append([]string{defaultProfileItem}, profiles...)
+	current := 0
+	for {
+		choice := qt.QInputDialog_GetItem4(nil, "GoGoldenHour",
+			"Profile (type a name for a new one):", items, current, true, &ok)
+		if !ok {
+			return "", false
+		}
+		if choice == defaultProfileItem || choice == "" {
+			return "", true
+		}
+		if err := validate(choice); err != nil {
+			qt.QMessageBox_Warning(nil, "GoGoldenHour", err.Error())
+			continue
+		}
+		return choice, true
+	}
+}
@@ -1,6 +1,8 @@
 package widgets
 
 import (
+	"time"
+
 	qt "github.com/mappu/miqt/qt6"
 	"github.com/megatih/GoGoldenHour/internal/domain"
 )
@@ -15,6 +17,11 @@ import (
 //   - Sun elevation angles for golden and blue hour boundaries
 //   - Time display format (12-hour vs 24-hour)
 //   - Auto-detect location on startup behavior
+//   - Teaching mode (explanations next to the sun times)
+//
+// These are the settings changed most often, e.g., during a workshop. The
+// others (formats, location source, notifications, map tiles and more)
+// are in the PreferencesDialog, which the "More Settings..." button opens.
 //
 // # UI Layout
 //
@@ -25,9 +32,16 @@ import (
 //	├────────────────────────────────────────────────────────────┤
 //	│ Golden Hour: [6.0°]      Blue Start: [-4.0°]               │
 //	│ Blue End:    [-8.0°]     [✓] 24-hour format                │
-//	│ [✓] Auto-detect location on startup                        │
+//	│ [✓] Auto-detect location   [ ] Teaching mode               │
+//	│ Config code: [Copy Code] [Paste Code...]                   │
+//	│ [More Settings...]                                         │
+//	│ ⚠ The settings file was damaged; defaults are in use.      │
+//	│ [Restore Backup] [Restore Defaults]                        │
 //	└────────────────────────────────────────────────────────────┘
 //
+// The last two rows only appear after a damaged settings file was loaded
+// (see ShowRecovery), until the user picks one of the buttons.
+//
 // # Elevation Angles
 //
 // Sun elevation angle settings control when golden/blue hours occur:
@@ -41,21 +55,30 @@ import (
 //	                         │
 //	                    Nadir (-90°)
 //
-// # Initialization Warning
-//
-// IMPORTANT: This panel triggers onSettingsChange callbacks during construction
-// when applySettings() is called. This happens because setting widget values
-// fires their OnValueChanged signals. The App must handle this by checking
-// if mainWindow is nil in recalculate().
-//
 // # Communication
 //
-// Settings changes are communicated via the onSettingsChange callback.
-// Each widget change immediately invokes the callback, which:
+// Settings changes are communicated via the onSettingsChange callback,
+// which:
 //  1. Updates the App's internal settings
 //  2. Reconfigures the solar calculator
 //  3. Persists settings to disk
 //  4. Triggers sun time recalculation
+//
+// Only the user's changes are reported, and not one by one: every change
+// (re)starts a settingsChangeDelay timer, and the callback runs once the
+// user pauses, with all changes so far. Holding down a spin box arrow thus
+// saves and recalculates once rather than for every 0.5° step. A pending
+// change is reported at once before a config code is copied, before the
+// preferences dialog opens and when the app quits (see Flush).
+//
+// Values set from outside (the constructor, SetSettings) are shown with
+// the widgets' signals blocked, so they never come back through the
+// callback; the caller applies them itself.
+//
+// The config code buttons invoke onCopyCode and onPasteCode. The panel does
+// not encode or decode codes itself; that is the App's responsibility. The
+// recovery buttons invoke onRestoreBackup and onRestoreDefaults, and "More
+// Settings..." invokes onMoreSettings.
 type SettingsPanel struct {
 	// groupBox is the collapsible container with "Settings" title.
 	// The checkable property makes it expandable/collapsible.
@@ -77,39 +100,94 @@ type SettingsPanel struct {
 	// Checked = 24-hour (14:30), Unchecked = 12-hour (2:30 PM)
 	timeFormatCheck *qt.QCheckBox
 
-	// autoDetectCheck toggles IP-based location detection on app startup.
-	// When enabled, the app queries IP-API to determine initial location.
+	// autoDetectCheck toggles location detection on app startup.
+	// When enabled, the app detects the initial location with the chosen source.
 	autoDetectCheck *qt.QCheckBox
 
+	// teachingModeCheck toggles the explanatory annotations in the time panel.
+	// Intended for workshops; the text comes from the help data file.
+	teachingModeCheck *qt.QCheckBox
+
 	// settings holds the current settings values.
 	// Updated in real-time as widgets change.
 	settings domain.Settings
 
+	// changeTimer is the single-shot timer that reports the user's changes
+	// once they pause (see notifyChange); pending is set while a change
+	// waits for it.
+	changeTimer *qt.QTimer
+	pending     bool
+
 	// onSettingsChange is the callback invoked when any setting changes.
 	// Receives the complete updated Settings object.
 	onSettingsChange func(settings domain.Settings)
+
+	// onCopyCode is the callback invoked when the user clicks "Copy Code".
+	onCopyCode func()
+
+	// onPasteCode is the callback invoked when the user clicks "Paste Code...".
+	onPasteCode func()
+
+	// onMoreSettings is the callback invoked when the user clicks "More
+	// Settings...".
+	onMoreSettings func()
+
+	// recoveryWidget holds the damaged settings warning and its buttons;
+	// hidden unless ShowRecovery was called. restoreBackupBtn is disabled
+	// without a backup.
+	recoveryWidget   *qt.QWidget
+	restoreBackupBtn *qt.QPushButton
+
+	// onRestoreBackup and onRestoreDefaults are the callbacks invoked by
+	// "Restore Backup" and "Restore Defaults".
+	onRestoreBackup   func()
+	onRestoreDefaults func()
 }
 
+// settingsChangeDelay is how long the user must pause before changes are
+// reported (see notifyChange): long enough to span the auto-repeat of a
+// held spin box arrow, short enough that the sun times follow promptly.
+const settingsChangeDelay = 400 * time.Millisecond
+
 // NewSettingsPanel creates a new settings panel with initial values and callback.
 //
 // Parameters:
 //   - settings: Initial settings values to display in the controls
 //   - onSettingsChange: Callback invoked whenever any setting changes.
 //     The App uses this to update configuration, persist, and recalculate.
+//   - onCopyCode: Callback invoked when the user wants to share a config code.
+//   - onPasteCode: Callback invoked when the user wants to apply a config code.
+//   - onMoreSettings: Callback invoked when the user wants the other
+//     settings (the preferences dialog).
+//   - onRestoreBackup: Callback invoked when the user restores the backup of
+//     a damaged settings file.
+//   - onRestoreDefaults: Callback invoked when the user starts over with the
+//     defaults instead.
 //
 // Returns a fully initialized SettingsPanel with the given settings applied.
+// Showing them doesn't invoke onSettingsChange.
 //
-// WARNING: This constructor triggers onSettingsChange during initialization
-// because applySettings() sets widget values, which fires their change signals.
-// The App handles this by checking mainWindow == nil in recalculate().
-func NewSettingsPanel(settings domain.Settings, onSettingsChange func(settings domain.Settings)) *SettingsPanel {
+// miqt API notes:
+//   - QCoreApplication_Instance().OnAboutToQuit(slot): Runs slot when the
+//     event loop ends, while widgets still exist
+func NewSettingsPanel(settings domain.Settings, onSettingsChange func(settings domain.Settings),
+	onCopyCode func(), onPasteCode func(), onMoreSettings func(), onRestoreBackup func(),
+	onRestoreDefaults func()) *SettingsPanel {
 	sp := &SettingsPanel{
-		settings:         settings,
-		onSettingsChange: onSettingsChange,
+		settings:          settings,
+		onSettingsChange:  onSettingsChange,
+		onCopyCode:        onCopyCode,
+		onPasteCode:       onPasteCode,
+		onMoreSettings:    onMoreSettings,
+		onRestoreBackup:   onRestoreBackup,
+		onRestoreDefaults: onRestoreDefaults,
 	}
 
 	sp.setupUI()
 	sp.applySettings(settings)
+
+	// A change the user made just before quitting is still saved
+	qt.QCoreApplication_Instance().OnAboutToQuit(sp.Flush)
 	return sp
 }
 
@@ -119,7 +197,10 @@ func NewSettingsPanel(settings domain.Settings, onSettingsChange func(settings d
 //
 //	Row 0: [Label] [Spin] [Label] [Spin]   - Golden Hour & Blue Start
 //	Row 1: [Label] [Spin] [Checkbox----]   - Blue End & Time Format
-//	Row 2: [Checkbox------------------]    - Auto-detect (spans 4 cols)
+//	Row 2: [Checkbox----] [Checkbox----]   - Auto-detect & Teaching mode
+//	Row 3: [Label] [Button] [Button----]   - Config code sharing
+//	Row 4: [Button---------------------]   - More settings (preferences)
+//	Row 5: [Warning and buttons--------]   - Damaged file recovery (hidden)
 //
 // # miqt API Notes
 //
@@ -148,6 +229,11 @@ func (sp *SettingsPanel) setupUI() {
 	layout := qt.NewQGridLayout(sp.groupBox.QWidget)
 	layout.SetSpacing(8)
 
+	// Reports changes once the user pauses (see notifyChange)
+	sp.changeTimer = qt.NewQTimer2(sp.groupBox.QObject)
+	sp.changeTimer.SetSingleShot(true)
+	sp.changeTimer.OnTimeout(sp.Flush)
+
 	// =========================================================================
 	// Row 0: Golden Hour Elevation | Blue Hour Start Elevation
 	// =========================================================================
@@ -204,15 +290,115 @@ func (sp *SettingsPanel) setupUI() {
 	layout.AddWidget3(sp.timeFormatCheck.QWidget, 1, 2, 1, 2)
 
 	// =========================================================================
-	// Row 2: Auto-Detect Location (Full Width)
+	// Row 2: Auto-Detect Location | Teaching Mode
 	// =========================================================================
-	// Spans all 4 columns since the label is long
-	sp.autoDetectCheck = qt.NewQCheckBox3("Auto-detect location on startup")
+	// Each checkbox spans 2 columns since the labels are long
+	sp.autoDetectCheck = qt.NewQCheckBox3("Auto-detect location")
+	sp.autoDetectCheck.SetToolTip("Detect the location on startup")
 	sp.autoDetectCheck.OnStateChanged(func(state int) {
 		sp.settings.AutoDetectLocation = state == int(qt.Checked)
 		sp.notifyChange()
 	})
-	layout.AddWidget3(sp.autoDetectCheck.QWidget, 2, 0, 1, 4)
+	layout.AddWidget3(sp.autoDetectCheck.QWidget, 2, 0, 1, 2)
+
+	// Teaching Mode: Explain angles and periods next to the sun times
+	sp.teachingModeCheck = qt.NewQCheckBox3("Teaching mode")
+	sp.teachingModeCheck.SetToolTip("Show explanations of the elevation angles and hour order")
+	sp.teachingModeCheck.OnStateChanged(func(state int) {
+		sp.settings.TeachingMode = state == int(qt.Checked)
+		sp.notifyChange()
+	})
+	layout.AddWidget3(sp.teachingModeCheck.QWidget, 2, 2, 1, 2)
+
+	// =========================================================================
+	// Row 3: Config Code Sharing
+	// =========================================================================
+	// Lets instructors hand out a short code that replicates these settings
+	codeLabel := qt.NewQLabel3("Config code:")
+	copyCodeBtn := qt.NewQPushButton3("Copy Code")
+	copyCodeBtn.SetToolTip("Copy a shareable code for the elevation and display settings")
+	copyCodeBtn.OnClicked(func() {
+		// The code must include a change still waiting to be reported
+		sp.Flush()
+		if sp.onCopyCode != nil {
+			sp.onCopyCode()
+		}
+	})
+	pasteCodeBtn := qt.NewQPushButton3("Paste Code...")
+	pasteCodeBtn.SetToolTip("Apply a config code shared by another user")
+	pasteCodeBtn.OnClicked(func() {
+		if sp.onPasteCode != nil {
+			sp.onPasteCode()
+		}
+	})
+	layout.AddWidget2(codeLabel.QWidget, 3, 0)
+	layout.AddWidget2(copyCodeBtn.QWidget, 3, 1)
+	layout.AddWidget3(pasteCodeBtn.QWidget, 3, 2, 1, 2)
+
+	// =========================================================================
+	// Row 4: More Settings
+	// =========================================================================
+	// Everything else is in the tabbed preferences dialog
+	moreBtn := qt.NewQPushButton3("More Settings...")
+	moreBtn.SetToolTip("Formats, location detection, notifications, map tiles and more")
+	moreBtn.OnClicked(func() {
+		sp.Flush()
+		if sp.onMoreSettings != nil {
+			sp.onMoreSettings()
+		}
+	})
+	layout.AddWidget3(moreBtn.QWidget, 4, 0, 1, 2)
+
+	// =========================================================================
+	// Row 5: Damaged Settings Recovery
+	// =========================================================================
+	// Shown by ShowRecovery after the settings file couldn't be read
+	sp.recoveryWidget = qt.NewQWidget(nil)
+	recoveryLayout := qt.NewQVBoxLayout(sp.recoveryWidget)
+	recoveryLayout.SetContentsMargins(0, 0, 0, 0)
+	warning := qt.NewQLabel3("⚠ The settings file was damaged; defaults are in use.")
+	warning.SetWordWrap(true)
+	warning.SetStyleSheet("color: #c62828;")
+	recoveryLayout.AddWidget(warning.QWidget)
+	recoveryButtons := qt.NewQHBoxLayout2()
+	sp.restoreBackupBtn = qt.NewQPushButton3("Restore Backup")
+	sp.restoreBackupBtn.SetToolTip("Restore the settings as they were before the last save")
+	sp.restoreBackupBtn.OnClicked(func() {
+		if sp.onRestoreBackup != nil {
+			sp.onRestoreBackup()
+		}
+	})
+	restoreDefaultsBtn := qt.NewQPushButton3("Restore Defaults")
+	restoreDefaultsBtn.SetToolTip("Start over with the default settings")
+	restoreDefaultsBtn.OnClicked(func() {
+		if sp.onRestoreDefaults != nil {
+			sp.onRestoreDefaults()
+		}
+	})
+	recoveryButtons.AddWidget(sp.restoreBackupBtn.QWidget)
+	recoveryButtons.AddWidget(restoreDefaultsBtn.QWidget)
+	recoveryButtons.AddStretch()
+	recoveryLayout.AddLayout(recoveryButtons.QLayout)
+	sp.recoveryWidget.SetVisible(false)
+	layout.AddWidget3(sp.recoveryWidget, 5, 0, 1, 4)
+}
+
+// ShowRecovery shows the damaged settings warning with its restore buttons,
+// and expands the panel so it is seen.
+//
+// Parameters:
+//   - hasBackup: Whether there is a backup to restore; without one only
+//     "Restore Defaults" is enabled
+func (sp *SettingsPanel) ShowRecovery(hasBackup bool) {
+	sp.restoreBackupBtn.SetEnabled(hasBackup)
+	sp.recoveryWidget.SetVisible(true)
+	sp.groupBox.SetChecked(true)
+}
+
+// HideRecovery hides the damaged settings warning once the user restored
+// the backup or the defaults.
+func (sp *SettingsPanel) HideRecovery() {
+	sp.recoveryWidget.SetVisible(false)
 }
 
 // Widget returns the group box container for adding to parent layouts.
@@ -226,21 +412,28 @@ func (sp *SettingsPanel) Widget() *qt.QGroupBox {
 // applySettings updates all UI controls to reflect the given settings.
 //
 // This is called during construction to initialize the controls with
-// the user's saved settings.
+// the user's saved settings, and by SetSettings.
 //
-// WARNING: This method triggers OnValueChanged/OnStateChanged callbacks
-// because setting widget values fires their change signals. This means
-// onSettingsChange will be called during initialization.
+// The widgets' signals are blocked meanwhile, so their change handlers
+// don't run: applied values are neither copied into sp.settings one by one
+// nor reported through onSettingsChange.
 //
-// The App handles this edge case by checking if mainWindow is nil in
-// recalculate(), preventing crashes during initialization.
+// miqt API notes:
+//   - BlockSignals(true) returns the previous state, which is restored
+//     afterwards
 func (sp *SettingsPanel) applySettings(settings domain.Settings) {
-	// Set spin box values (triggers OnValueChanged for each)
+	for _, w := range []*qt.QWidget{
+		sp.goldenElevation.QWidget, sp.blueStartElevation.QWidget, sp.blueEndElevation.QWidget,
+		sp.timeFormatCheck.QWidget, sp.autoDetectCheck.QWidget, sp.teachingModeCheck.QWidget,
+	} {
+		blocked := w.BlockSignals(true)
+		defer w.BlockSignals(blocked)
+	}
+
 	sp.goldenElevation.SetValue(settings.GoldenHourElevation)
 	sp.blueStartElevation.SetValue(settings.BlueHourStart)
 	sp.blueEndElevation.SetValue(settings.BlueHourEnd)
 
-	// Set checkbox states (triggers OnStateChanged for each)
 	// Qt checkboxes use SetCheckState with qt.Checked/qt.Unchecked constants
 	if settings.TimeFormat24Hour {
 		sp.timeFormatCheck.SetCheckState(qt.Checked)
@@ -253,6 +446,25 @@ func (sp *SettingsPanel) applySettings(settings domain.Settings) {
 	} else {
 		sp.autoDetectCheck.SetCheckState(qt.Unchecked)
 	}
+
+	if settings.TeachingMode {
+		sp.teachingModeCheck.SetCheckState(qt.Checked)
+	} else {
+		sp.teachingModeCheck.SetCheckState(qt.Unchecked)
+	}
+}
+
+// SetSettings replaces the displayed settings with new values.
+//
+// This is used when settings change from outside the panel, such as when a
+// config code is imported. onSettingsChange isn't invoked; the caller
+// applies the settings itself. A change of the user's that is still
+// waiting to be reported is dropped, as the new settings replace it.
+func (sp *SettingsPanel) SetSettings(settings domain.Settings) {
+	sp.changeTimer.Stop()
+	sp.pending = false
+	sp.settings = settings
+	sp.applySettings(settings)
 }
 
 // GetSettings returns the current settings values.
@@ -263,13 +475,26 @@ func (sp *SettingsPanel) GetSettings() domain.Settings {
 	return sp.settings
 }
 
-// notifyChange invokes the settings change callback if set.
-//
-// This is called by all widget change handlers to propagate changes
-// to the App controller. Each change invokes the callback immediately,
-// providing real-time settings updates.
-func (sp *SettingsPanel) notifyChange() {
+// Flush reports a pending change through onSettingsChange now, instead of
+// when the user pauses. Does nothing if no change is pending.
+func (sp *SettingsPanel) Flush() {
+	if !sp.pending {
+		return
+	}
+	sp.changeTimer.Stop()
+	sp.pending = false
 	if sp.onSettingsChange != nil {
 		sp.onSettingsChange(sp.settings)
 	}
 }
+
+// notifyChange schedules the report of a change.
+//
+// This is called by all widget change handlers. The timer restarts with
+// every change, so a burst of changes (e.g., spin box steps while an
+// arrow is held down) is reported once, settingsChangeDelay after the
+// last one.
+func (sp *SettingsPanel) notifyChange() {
+	sp.pending = true
+	sp.changeTimer.Start(int(settingsChangeDelay / time.Millisecond))
+}
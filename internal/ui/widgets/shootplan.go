@@ -0,0 +1,97 @@
+package widgets
+
+import (
+	"errors"
+
+	qt "github.com/mappu/miqt/qt6"
+)
+
+// =============================================================================
+// Shoot Plan PDF
+// =============================================================================
+
+// shootPlanResolution is the PDF's resolution in dots per inch. It matches
+// the usual screen resolution, so the rich text lays out as it would on
+// screen and the map snapshot prints at its own size (text stays vector
+// graphics either way).
+const shootPlanResolution = 96
+
+// shootPlanMarginMM is the page margin in millimeters.
+const shootPlanMarginMM = 15
+
+// WriteShootPlanPDF writes a one-page A4 shoot plan: a snapshot of the map
+// on top and the plan's text below it.
+//
+//	┌─────────────────────────────┐
+//	│ ┌─────────────────────────┐ │
+//	│ │      map snapshot       │ │
+//	│ └─────────────────────────┘ │
+//	│ Shoot Plan: Riverside Bridge│
+//	│ 48.8566° N, ...             │
+//	│ ┌────┬────┬────┬────┬────┐  │
+//	│ │ ...                     │ │
+//	└─────────────────────────────┘
+//
+// Parameters:
+//   - path: Destination file (overwritten if it exists)
+//   - title: The PDF's document title
+//   - snapshot: The map image; nil or a null pixmap leaves it out
+//   - html: The plan's text (see export.ShootPlanHTML)
+//
+// The snapshot is scaled to the page width, and to at most half the page
+// height. Text that doesn't fit the rest of the page is cut off.
+//
+// Returns an error if the file can't be written.
+//
+// miqt API notes:
+//   - NewQPdfWriter(path) opens the file when painting begins; a painter
+//     that isn't active after NewQPainter2 means the file can't be written
+//   - The writer's page size and margins come from QPagedPaintDevice;
+//     Width()/Height() are the printable area in device pixels
+//   - QTextDocument.DrawContents(painter) draws the laid out rich text at
+//     the painter's origin
+func WriteShootPlanPDF(path, title string, snapshot *qt.QPixmap, html string) error {
+	pdf := qt.NewQPdfWriter(path)
+	pdf.SetTitle(title)
+	pdf.SetCreator("GoGoldenHour")
+	pdf.SetResolution(shootPlanResolution)
+	pdf.SetPageSize(qt.NewQPageSize2(qt.QPageSize__A4))
+	pdf.SetPageMargins(qt.NewQMarginsF2(shootPlanMarginMM, shootPlanMarginMM, shootPlanMarginMM, shootPlanMarginMM),
+		qt.QPageLayout__Millimeter)
+
+	painter := qt.NewQPainter2(pdf.QPaintDevice)
+	if !painter.IsActive() {
+		return errors.New("failed to write shoot plan: can't open " + path)
+	}
+	painter.SetRenderHint(qt.QPainter__SmoothPixmapTransform)
+
+	width := float64(pdf.Width())
+	height := float64(pdf.Height())
+	top := 0.0
+
+	// Map snapshot, keeping its aspect ratio
+	if snapshot != nil && !snapshot.IsNull() && snapshot.Width() > 0 {
+		w := width
+		h := w * float64(snapshot.Height()) / float64(snapshot.Width())
+		if h > height/2 {
+			h = height / 2
+			w = h * float64(snapshot.Width()) / float64(snapshot.Height())
+		}
+		source := qt.NewQRectF4(0, 0, float64(snapshot.Width()), float64(snapshot.Height()))
+		painter.DrawPixmap(qt.NewQRectF4((width-w)/2, 0, w, h), snapshot, source)
+		top = h + 12
+	}
+
+	// Plan text below, clipped to the page
+	doc := qt.NewQTextDocument()
+	doc.SetDocumentMargin(0)
+	doc.SetTextWidth(width)
+	doc.SetHtml(html)
+	painter.Translate2(0, top)
+	doc.DrawContents2(painter, qt.NewQRectF4(0, 0, width, height-top))
+
+	if !painter.End() {
+		return errors.New("failed to write shoot plan: " + path + " is incomplete")
+	}
+	return nil
+}
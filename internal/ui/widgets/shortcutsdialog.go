@@ -0,0 +1,77 @@
+package widgets
+
+import (
+	qt "github.com/mappu/miqt/qt6"
+)
+
+// =============================================================================
+// Keyboard Shortcuts Dialog
+// =============================================================================
+
+// Shortcut is one line of the keyboard shortcuts cheat sheet.
+type Shortcut struct {
+	// Keys is the key sequence as shown to the user, e.g., "Shift+Left"
+	// (QKeySequence.ToStringWithFormat(NativeText) gives the platform's
+	// spelling, e.g., "⇧←" on macOS).
+	Keys string
+
+	// Description says what the keys do.
+	Description string
+}
+
+// ShowShortcutsDialog displays the keyboard shortcuts cheat sheet.
+//
+//	┌─ Keyboard Shortcuts ─────────────────────────┐
+//	│ ┌──────────────┬───────────────────────────┐ │
+//	│ │ Keys         │ Action                    │ │
+//	│ ├──────────────┼───────────────────────────┤ │
+//	│ │ Left         │ Previous day              │ │
+//	│ │ Shift+Right  │ Next week                 │ │
+//	│ └──────────────┴───────────────────────────┘ │
+//	│                                   [ Close ]  │
+//	└──────────────────────────────────────────────┘
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - shortcuts: The shortcuts in display order
+//
+// The dialog is modal and blocks until the user closes it.
+//
+// miqt API notes:
+//   - NewQTableWidget3(rows, cols): Table with fixed size (suffix "3")
+//   - NewQTableWidgetItem2("text"): Item with text (suffix "2")
+func ShowShortcutsDialog(parent *qt.QWidget, shortcuts []Shortcut) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("Keyboard Shortcuts")
+	dialog.Resize(420, 400)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+
+	table := qt.NewQTableWidget3(len(shortcuts), 2)
+	table.SetHorizontalHeaderLabels([]string{"Keys", "Action"})
+	table.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+	table.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	table.VerticalHeader().SetVisible(false)
+	table.HorizontalHeader().SetStretchLastSection(true)
+	for row, s := range shortcuts {
+		table.SetItem(row, 0, qt.NewQTableWidgetItem2(s.Keys))
+		table.SetItem(row, 1, qt.NewQTableWidgetItem2(s.Description))
+	}
+	table.ResizeColumnsToContents()
+	layout.AddWidget(table.QWidget)
+
+	note := qt.NewQLabel3("Date keys work anywhere in the main window and the month calendar, " +
+		"except while typing in a text field.")
+	note.SetWordWrap(true)
+	note.SetStyleSheet("color: gray; font-size: 11px;")
+	layout.AddWidget(note.QWidget)
+
+	// Close button
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+
+	dialog.Exec()
+}
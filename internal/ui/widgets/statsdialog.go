@@ -0,0 +1,120 @@
+package widgets
+
+import (
+	"fmt"
+	"html"
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/stats"
+)
+
+// =============================================================================
+// Statistics Dialog
+// =============================================================================
+
+// ShowStatisticsDialog displays the calculation, cache and web service
+// counts recorded by the stats package (Debug → Statistics).
+//
+//	┌─ Statistics ──────────────────────────────────────────────┐
+//	│ ┌────────────────────────────────────────────────────────┐│
+//	│ │ Since 14:02                                            ││
+//	│ │ Operation                 Calls Errors Average Slowest ││
+//	│ │ Nominatim request            12      0  310 ms  820 ms ││
+//	│ │ Sun times calculation       480      0 0.08 ms  1.2 ms ││
+//	│ │ Cache              Hits Misses Hit rate                ││
+//	│ │ Place searches        9      3      75%                ││
+//	│ └────────────────────────────────────────────────────────┘│
+//	│ [Refresh] [Reset]                             [ Close ]   │
+//	└───────────────────────────────────────────────────────────┘
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - snapshot: Returns the current counts (stats.Snapshot)
+//   - reset: Clears the counts (stats.Reset)
+//
+// The dialog is modal and blocks until the user closes it. The counts keep
+// changing in the background, so Refresh reads them again.
+//
+// miqt API notes:
+//   - NewQTextBrowser2(): Read-only rich text view (suffix "2" = no params)
+//   - SetHtml is inherited from QTextEdit
+func ShowStatisticsDialog(parent *qt.QWidget, snapshot func() stats.Report, reset func()) {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("Statistics")
+	dialog.Resize(560, 400)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+
+	view := qt.NewQTextBrowser2()
+	refresh := func() {
+		view.SetHtml(statisticsHTML(snapshot()))
+	}
+	refresh()
+	layout.AddWidget(view.QWidget)
+
+	bottomRow := qt.NewQHBoxLayout2()
+	refreshBtn := qt.NewQPushButton3("Refresh")
+	refreshBtn.OnClicked(refresh)
+	bottomRow.AddWidget(refreshBtn.QWidget)
+	resetBtn := qt.NewQPushButton3("Reset")
+	resetBtn.SetToolTip("Start counting from zero")
+	resetBtn.OnClicked(func() {
+		reset()
+		refresh()
+	})
+	bottomRow.AddWidget(resetBtn.QWidget)
+	bottomRow.AddStretch()
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	bottomRow.AddWidget(buttons.QWidget)
+	layout.AddLayout(bottomRow.QLayout)
+
+	dialog.Exec()
+}
+
+// statisticsHTML formats a report as two tables, operations and caches.
+func statisticsHTML(report stats.Report) string {
+	var b strings.Builder
+	b.WriteString("<p>Counted since " + report.Since.Format("Jan 2, 15:04:05") + ".</p>")
+
+	cell := func(text string, align string) {
+		b.WriteString(`<td align="` + align + `">` + html.EscapeString(text) + "</td>")
+	}
+
+	b.WriteString(`<h3>Operations</h3><table cellpadding="3" width="100%">` +
+		`<tr><th align="left">Operation</th><th align="right">Calls</th><th align="right">Errors</th>` +
+		`<th align="right">Average</th><th align="right">Slowest</th></tr>`)
+	for _, op := range report.Operations {
+		b.WriteString("<tr>")
+		cell(op.Name, "left")
+		cell(fmt.Sprint(op.Count), "right")
+		cell(fmt.Sprint(op.Errors), "right")
+		cell(stats.FormatLatency(op.Average()), "right")
+		cell(stats.FormatLatency(op.Slowest), "right")
+		b.WriteString("</tr>")
+	}
+	if len(report.Operations) == 0 {
+		b.WriteString(`<tr><td colspan="5">Nothing yet</td></tr>`)
+	}
+	b.WriteString("</table>")
+
+	b.WriteString(`<h3>Caches</h3><table cellpadding="3" width="100%">` +
+		`<tr><th align="left">Cache</th><th align="right">Hits</th><th align="right">Misses</th>` +
+		`<th align="right">Hit rate</th></tr>`)
+	for _, c := range report.Caches {
+		b.WriteString("<tr>")
+		cell(c.Name, "left")
+		cell(fmt.Sprint(c.Hits), "right")
+		cell(fmt.Sprint(c.Misses), "right")
+		cell(fmt.Sprintf("%.0f%%", c.HitRate()*100), "right")
+		b.WriteString("</tr>")
+	}
+	if len(report.Caches) == 0 {
+		b.WriteString(`<tr><td colspan="4">Nothing yet</td></tr>`)
+	}
+	b.WriteString("</table>")
+	return b.String()
+}
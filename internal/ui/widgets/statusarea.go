@@ -0,0 +1,143 @@
+package widgets
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+)
+
+// =============================================================================
+// StatusArea
+// =============================================================================
+
+const (
+	// messageTimeout is how long a status message stays before the message
+	// area is cleared.
+	messageTimeout = 10 * time.Second
+
+	// maxRecentErrors is how many errors the message area's tooltip lists.
+	maxRecentErrors = 5
+)
+
+// StatusArea manages the contents of the main window's status bar:
+//
+//	┌───────────────────────────────────────────────────────────────────────┐
+//	│ Preferences saved              [▓▓░░░░]        📍 Paris, France   ... │
+//	└───────────────────────────────────────────────────────────────────────┘
+//	  message area (transient)      progress         location (permanent)
+//
+// The parts:
+//   - The message area shows status messages for a while (messageTimeout)
+//     and is then cleared. Errors are shown as toasts (see Toast); the
+//     last ones are listed in the area's tooltip (see LogError), for
+//     reading one after its toast is gone.
+//   - The progress indicator runs while network operations are in flight
+//     (see SetBusy); its tooltip names them.
+//   - The location label always shows the current location.
+//
+// Further permanent widgets (e.g., the cursor coordinates) can be added by
+// the window after creating the StatusArea; they go right of the location.
+type StatusArea struct {
+	// message is the transient message area.
+	message *qt.QLabel
+
+	// timer clears the message area.
+	timer *qt.QTimer
+
+	// progress is the busy indicator, visible while tasks are running.
+	progress *qt.QProgressBar
+
+	// location shows the current location's name.
+	location *qt.QLabel
+
+	// recentErrors are the last errors with their times, oldest first.
+	recentErrors []string
+
+	// tasks are the names of the running network operations.
+	tasks map[string]bool
+}
+
+// NewStatusArea creates the status bar's message area, progress indicator
+// and location label, and adds them to the status bar.
+//
+// Parameters:
+//   - statusBar: The window's status bar (QMainWindow.StatusBar)
+//
+// miqt API notes:
+//   - AddWidget2(widget, stretch): Normal widget on the left; stretch 1
+//     gives the message area the free space
+//   - AddPermanentWidget: Widget on the right, never hidden by messages
+//   - SetRange(0, 0) turns the progress bar into a busy indicator
+func NewStatusArea(statusBar *qt.QStatusBar) *StatusArea {
+	sa := &StatusArea{tasks: make(map[string]bool)}
+
+	sa.message = qt.NewQLabel3("")
+	statusBar.AddWidget2(sa.message.QWidget, 1)
+
+	sa.timer = qt.NewQTimer2(statusBar.QObject)
+	sa.timer.SetSingleShot(true)
+	sa.timer.OnTimeout(func() {
+		sa.message.SetText("")
+	})
+
+	sa.progress = qt.NewQProgressBar2()
+	sa.progress.SetRange(0, 0)
+	sa.progress.SetTextVisible(false)
+	sa.progress.SetMaximumWidth(100)
+	sa.progress.SetMaximumHeight(12)
+	sa.progress.Hide()
+	statusBar.AddWidget(sa.progress.QWidget)
+
+	sa.location = qt.NewQLabel3("")
+	sa.location.SetToolTip("Current location")
+	statusBar.AddPermanentWidget(sa.location.QWidget)
+
+	return sa
+}
+
+// ShowMessage shows a status message, cleared after messageTimeout.
+func (sa *StatusArea) ShowMessage(text string) {
+	sa.message.SetText(text)
+	sa.timer.Start(int(messageTimeout.Milliseconds()))
+}
+
+// LogError adds an error, with the time, to the recent errors listed in
+// the message area's tooltip (the last maxRecentErrors).
+func (sa *StatusArea) LogError(text string) {
+	sa.recentErrors = append(sa.recentErrors, time.Now().Format("15:04:05")+"  "+text)
+	if len(sa.recentErrors) > maxRecentErrors {
+		sa.recentErrors = slices.Delete(sa.recentErrors, 0, len(sa.recentErrors)-maxRecentErrors)
+	}
+	sa.message.SetToolTip("Recent errors:\n" + strings.Join(sa.recentErrors, "\n"))
+}
+
+// SetLocation shows the current location's name in the location label.
+func (sa *StatusArea) SetLocation(name string) {
+	sa.location.SetText("📍 " + name)
+}
+
+// SetBusy marks a network operation as started or finished. The progress
+// indicator runs while any is in flight.
+//
+// Operations are told apart by name only: starting one that runs already
+// changes nothing, and one end finishes it. This suits lookups where a
+// newer request replaces the running one (e.g., quick map clicks), which
+// then ends them all.
+//
+// Parameters:
+//   - task: What runs, e.g., "Searching"; shown in the indicator's tooltip
+//   - busy: True when the operation starts, false when it ends
+func (sa *StatusArea) SetBusy(task string, busy bool) {
+	if busy {
+		sa.tasks[task] = true
+	} else {
+		delete(sa.tasks, task)
+	}
+
+	names := slices.Sorted(maps.Keys(sa.tasks))
+	sa.progress.SetToolTip(strings.Join(names, ", ") + "…")
+	sa.progress.SetVisible(len(names) > 0)
+}
@@ -0,0 +1,121 @@
+package widgets
+
+import (
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// TimelinePanel
+// =============================================================================
+
+// TimelinePanel lists the selected day's events in order, or, across
+// midnight, the selected day's and the next morning's.
+//
+// # UI Layout
+//
+//	┌─ Timeline ─────────────────────────────────────────┐
+//	│ [x] Across midnight (36 h)                         │
+//	│ 20:15        Evening golden hour start             │
+//	│ 21:58        Sunset                                │
+//	│ 23:40        Evening blue hour start               │
+//	│ Tue 00:25    Evening blue hour end                 │
+//	│ Tue 04:32    Morning blue hour start               │
+//	└────────────────────────────────────────────────────┘
+//
+// Evening blue hour (and the night around it) often runs past midnight,
+// which splits it across two dates in the time panel. The 36-hour mode
+// runs from the selected day's midnight to noon of the next day, so a
+// night's shoot reads in one list without flipping dates. Events of the
+// next day are marked with its weekday.
+//
+// The events are calculated by the App (see SetDays); the panel only
+// selects and displays them.
+type TimelinePanel struct {
+	// groupBox is the container widget with "Timeline" title border.
+	groupBox *qt.QGroupBox
+
+	// acrossMidnight switches between the 24- and 36-hour timeline.
+	acrossMidnight *qt.QCheckBox
+
+	// table has one row per event.
+	table *qt.QTableWidget
+
+	// days are the selected day's and the next day's sun times.
+	days []domain.SunTimes
+
+	// use24Hour is the time display format.
+	use24Hour bool
+}
+
+// NewTimelinePanel creates an empty 24-hour timeline; call SetDays to
+// fill it.
+func NewTimelinePanel() *TimelinePanel {
+	tp := &TimelinePanel{}
+	tp.setupUI()
+	return tp
+}
+
+// setupUI creates the mode checkbox and the event table.
+//
+// miqt API notes:
+//   - NewQCheckBox3("text"): Checkbox with label (suffix "3")
+//   - NewQTableWidget3(rows, cols): Table with fixed size (suffix "3")
+func (tp *TimelinePanel) setupUI() {
+	tp.groupBox = qt.NewQGroupBox3("Timeline")
+	layout := qt.NewQVBoxLayout(tp.groupBox.QWidget)
+	layout.SetContentsMargins(6, 6, 6, 6)
+
+	tp.acrossMidnight = qt.NewQCheckBox3("Across midnight (36 h)")
+	tp.acrossMidnight.SetToolTip("Continue the timeline to noon of the next day, " +
+		"so a blue hour past midnight and the next morning are listed too")
+	tp.acrossMidnight.OnToggled(func(bool) { tp.update() })
+	layout.AddWidget(tp.acrossMidnight.QWidget)
+
+	tp.table = qt.NewQTableWidget3(0, 2)
+	tp.table.SetHorizontalHeaderLabels([]string{"Time", "Event"})
+	tp.table.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+	tp.table.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	tp.table.VerticalHeader().SetVisible(false)
+	tp.table.HorizontalHeader().SetStretchLastSection(true)
+	tp.table.SetMinimumHeight(120)
+	layout.AddWidget(tp.table.QWidget)
+}
+
+// Widget returns the group box container for adding to parent layouts.
+func (tp *TimelinePanel) Widget() *qt.QGroupBox {
+	return tp.groupBox
+}
+
+// SetDays replaces the days the timeline is drawn from.
+//
+// Parameters:
+//   - days: The selected day's sun times followed by the next day's (only
+//     the first is used in the 24-hour mode); nil empties the timeline
+//   - use24Hour: Time display format
+func (tp *TimelinePanel) SetDays(days []domain.SunTimes, use24Hour bool) {
+	tp.days = days
+	tp.use24Hour = use24Hour
+	tp.update()
+}
+
+// update lists the events of the chosen span.
+func (tp *TimelinePanel) update() {
+	hours := domain.DayTimelineHours
+	if tp.acrossMidnight.IsChecked() {
+		hours = domain.ExtendedTimelineHours
+	}
+	events := domain.Timeline(tp.days, hours)
+
+	tp.table.SetRowCount(len(events))
+	for row, event := range events {
+		when := domain.FormatTime(event.Time, tp.use24Hour)
+		if event.Time.Day() != tp.days[0].Date.Day() {
+			// Next day
+			when = event.Time.Format("Mon") + " " + when
+		}
+		tp.table.SetItem(row, 0, qt.NewQTableWidgetItem2(when))
+		tp.table.SetItem(row, 1, qt.NewQTableWidgetItem2(event.Kind.Label()))
+	}
+	tp.table.ResizeColumnsToContents()
+}
@@ -2,9 +2,14 @@ package widgets
 
 import (
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	qt "github.com/mappu/miqt/qt6"
 	"github.com/megatih/GoGoldenHour/internal/domain"
+	"github.com/megatih/GoGoldenHour/internal/export"
+	"github.com/megatih/GoGoldenHour/internal/help"
 )
 
 // =============================================================================
@@ -14,7 +19,8 @@ import (
 // TimePanel displays calculated golden hour and blue hour times.
 //
 // This is the primary output panel showing the results of solar calculations.
-// It displays sunrise/sunset times and the four key photography periods:
+// It displays the day's total shooting window, sunrise/sunset times and the
+// four key photography periods:
 //   - Morning golden hour (after sunrise)
 //   - Evening golden hour (before sunset)
 //   - Morning blue hour (before sunrise)
@@ -23,13 +29,65 @@ import (
 // # UI Layout
 //
 //	┌─ Sun Times ───────────────────────────────────────────────┐
+//	│ Shooting light: 2h (8% of the day)                        │
+//	│ ████░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░ │
 //	│ Sunrise: 07:15                  Sunset: 17:45             │
-//	│ ┌─ Golden Hour ──────────┐ ┌─ Blue Hour ───────────┐      │
-//	│ │ AM: 07:15 - 08:15      │ │ AM: 06:45 - 07:15     │      │
-//	│ │ PM: 16:45 - 17:45      │ │ PM: 17:45 - 18:15     │      │
-//	│ └────────────────────────┘ └───────────────────────┘      │
+//	│ ┌─ Golden Hour ──────────┐ ┌─ Blue Hour ────────────────┐ │
+//	│ │ AM: 07:15 - 08:15 [1h] │ │ AM: 06:45 - 07:15 [30 min] │ │
+//	│ │ PM: 16:45 - 17:45 [1h] │ │ PM: 17:45 - 18:15 [30 min] │ │
+//	│ └────────────────────────┘ └────────────────────────────┘ │
+//	│ Nautical twilight (-12°): 06:02 · 18:58                   │
 //	└───────────────────────────────────────────────────────────┘
 //
+// The custom events of the active preset (see domain.CustomEvent), if any,
+// are listed below the groups with their morning and evening times ("--"
+// where the sun doesn't reach the elevation).
+//
+// Each period's duration is shown as a badge next to it. The tooltip of a
+// period gives its start, end and duration to the second, with the sun's
+// azimuth at the start and end (from the day's sun positions, see
+// SetPositions).
+//
+// # Weather
+//
+// For dates within the weather forecast, an icon before the badge shows
+// the expected weather of each period (clear, partly or mostly cloudy,
+// overcast, rain or fog; see SetWeather), with the cloud cover,
+// precipitation and visibility in its tooltip:
+//
+//	│ │ PM: 16:45 - 17:45 🌤 [1h] │
+//
+// In the detailed layout, the condition follows the start of each period.
+//
+// # Detailed Layout
+//
+// In the detailed layout (see SetLayout), the sunrise/sunset row and the
+// two groups are replaced by one table of all the day's events, custom
+// events included, in chronological order:
+//
+//	┌─ Sun Times ───────────────────────────────────────────────┐
+//	│ Shooting light: 2h (8% of the day)                        │
+//	│ ████░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░░ │
+//	│ 06:45   Morning blue hour start                           │
+//	│ 07:15   Morning blue hour end                             │
+//	│ 07:15   Sunrise                                           │
+//	│ ...                                                       │
+//	│ 18:15   Evening blue hour end                             │
+//	└───────────────────────────────────────────────────────────┘
+//
+// # Add to Calendar
+//
+// Right-clicking a golden or blue hour (or one of its events in the
+// detailed layout) offers to add it to a calendar:
+//
+//	┌──────────────────────────┐
+//	│ Add to Google Calendar   │
+//	│ Add to My Calendar       │
+//	└──────────────────────────┘
+//
+// "My Calendar" is the CalDAV calendar set up in the preferences (e.g.,
+// Nextcloud); the panel only reports the picked period to the callbacks.
+//
 // # Styling
 //
 // Each group has distinctive styling matching the lighting conditions:
@@ -42,6 +100,21 @@ import (
 //   - Polar regions during midnight sun have no blue hour
 //   - Polar regions during polar night may have no sunrise/sunset
 //   - Invalid ranges display "N/A" instead of times
+//
+// # Teaching Mode
+//
+// When teaching mode is enabled (see SetTeachingMode), annotation labels
+// appear next to the times explaining what the elevation angles mean and
+// why the periods happen in the order they do. The annotation text comes
+// from the help package's data file and is filled with the user's current
+// elevation settings. Annotations are hidden when teaching mode is off.
+//
+// # Dual Clock
+//
+// With a home timezone set (see SetHomeTimezone), every time is followed
+// by the same moment on the home clock, e.g., "Sunset: 17:20 (11:20 EST)"
+// and a second line "01:15 - 02:15 EST" under each period. Nothing is
+// added while the location's clock reads the same as the home clock.
 type TimePanel struct {
 	// groupBox is the outer container with "Sun Times" title.
 	groupBox *qt.QGroupBox
@@ -70,15 +143,88 @@ type TimePanel struct {
 	// Shows "PM: HH:MM - HH:MM" or "PM: N/A" if invalid.
 	blueEvening *qt.QLabel
 
+	// goldenMorningBadge, goldenEveningBadge, blueMorningBadge and
+	// blueEveningBadge show the duration of each period ("38 min").
+	goldenMorningBadge *qt.QLabel
+	goldenEveningBadge *qt.QLabel
+	blueMorningBadge   *qt.QLabel
+	blueEveningBadge   *qt.QLabel
+
+	// goldenMorningWeather, goldenEveningWeather, blueMorningWeather and
+	// blueEveningWeather show the forecast weather icon of each period;
+	// hidden without a forecast for it.
+	goldenMorningWeather *qt.QLabel
+	goldenEveningWeather *qt.QLabel
+	blueMorningWeather   *qt.QLabel
+	blueEveningWeather   *qt.QLabel
+
+	// weather is the forecast for the displayed day; empty without one.
+	weather domain.WeatherForecast
+
+	// eventsTable lists every event of the day in the detailed layout;
+	// hidden in the compact layout.
+	eventsTable *qt.QTableWidget
+
+	// customLabel lists the custom events' times in the compact layout;
+	// hidden without custom events.
+	customLabel *qt.QLabel
+
+	// detailed is true for the detailed layout (domain.TimePanelDetailed).
+	detailed bool
+
+	// sunTimes are the displayed times, kept for the period tooltips.
+	sunTimes domain.SunTimes
+
+	// positions are the day's sampled sun positions, for the azimuths in
+	// the period tooltips.
+	positions []domain.SunPathPoint
+
 	// sunriseLabel displays the sunrise time.
 	sunriseLabel *qt.QLabel
 
 	// sunsetLabel displays the sunset time.
 	sunsetLabel *qt.QLabel
 
+	// shootingLabel displays the total of all four periods
+	// (domain.SunTimes.FormatShootingWindow).
+	shootingLabel *qt.QLabel
+
+	// shootingBar shows the shooting window as a fraction of the day, in
+	// tenths of a percent.
+	shootingBar *qt.QProgressBar
+
+	// annotations holds the teaching mode labels keyed by help topic ID.
+	// Each label is hidden unless teaching mode is enabled.
+	annotations map[string]*qt.QLabel
+
 	// use24Hour determines the time display format.
 	// true: 24-hour format (14:30), false: 12-hour format (2:30 PM)
 	use24Hour bool
+
+	// home is the timezone of the home clock; nil shows local times only.
+	home *time.Location
+
+	// eventPeriods holds the period (export.PeriodGoldenPM etc.) of each
+	// row of eventsTable, "" for rows that aren't part of one.
+	eventPeriods []string
+
+	// onAddToGoogle and onAddToCalendar are invoked with the period the
+	// user picked "Add to Google Calendar" or "Add to My Calendar" for.
+	onAddToGoogle   func(period string)
+	onAddToCalendar func(period string)
+}
+
+// calendarPeriods maps the start and end events of the golden and blue hours
+// to their calendar periods.
+var calendarPeriods = map[domain.EventKind]string{
+	domain.EventBlueMorningStart:   export.PeriodBlueAM,
+	domain.EventBlueMorningEnd:     export.PeriodBlueAM,
+	domain.EventGoldenMorningStart: export.PeriodGoldenAM,
+	domain.EventGoldenMorningEnd:   export.PeriodGoldenAM,
+	domain.EventGoldenEveningStart: export.PeriodGoldenPM,
+	domain.EventGoldenEveningEnd:   export.PeriodGoldenPM,
+	domain.EventBlueEveningStart:   export.PeriodBluePM,
+	domain.EventBlueEveningEnd:     export.PeriodBluePM,
 }
 
 // NewTimePanel creates a new time panel with the specified time format.
@@ -86,11 +232,19 @@ type TimePanel struct {
 // Parameters:
 //   - use24Hour: If true, display times in 24-hour format (14:30).
 //     If false, display in 12-hour format (2:30 PM).
+//   - onAddToGoogle: Callback invoked with a period's export.Period
+//     constant when user picks "Add to Google Calendar" on it
+//   - onAddToCalendar: Callback invoked likewise for "Add to My Calendar"
 //
 // Returns a fully initialized TimePanel showing placeholder times ("--:--").
 // Call SetSunTimes() to update with actual calculated values.
-func NewTimePanel(use24Hour bool) *TimePanel {
-	tp := &TimePanel{use24Hour: use24Hour}
+func NewTimePanel(use24Hour bool, onAddToGoogle, onAddToCalendar func(period string)) *TimePanel {
+	tp := &TimePanel{
+		use24Hour:       use24Hour,
+		annotations:     make(map[string]*qt.QLabel),
+		onAddToGoogle:   onAddToGoogle,
+		onAddToCalendar: onAddToCalendar,
+	}
 	tp.setupUI()
 	return tp
 }
@@ -98,10 +252,12 @@ func NewTimePanel(use24Hour bool) *TimePanel {
 // setupUI creates and arranges all widgets in the time panel.
 //
 // The layout structure:
-//  1. Sunrise/Sunset row at top (horizontal)
-//  2. Two side-by-side group boxes below (horizontal):
+//  1. Shooting window summary and fraction bar at top
+//  2. Sunrise/Sunset row (horizontal)
+//  3. Two side-by-side group boxes below (horizontal):
 //     - Golden Hour group (orange styled)
 //     - Blue Hour group (blue styled)
+//  4. Custom event times below the groups
 //
 // Each hour group contains AM and PM time ranges stacked vertically.
 //
@@ -117,6 +273,31 @@ func (tp *TimePanel) setupUI() {
 	mainLayout := qt.NewQVBoxLayout(tp.groupBox.QWidget)
 	mainLayout.SetSpacing(8)
 
+	// =========================================================================
+	// Shooting Window Summary
+	// =========================================================================
+	// All four periods added up, with a thin bar showing how much of the
+	// day they cover (warm to cool, like the groups below)
+	tp.shootingLabel = qt.NewQLabel3("Shooting light: --")
+	mainLayout.AddWidget(tp.shootingLabel.QWidget)
+	tp.shootingBar = qt.NewQProgressBar2()
+	tp.shootingBar.SetRange(0, 1000)
+	tp.shootingBar.SetValue(0)
+	tp.shootingBar.SetTextVisible(false)
+	tp.shootingBar.SetMaximumHeight(6)
+	tp.shootingBar.SetStyleSheet(`
+		QProgressBar {
+			border: none;
+			border-radius: 3px;
+			background: palette(mid);
+		}
+		QProgressBar::chunk {
+			border-radius: 3px;
+			background: qlineargradient(x1:0, y1:0, x2:1, y2:0, stop:0 #ff9800, stop:1 #2196f3);
+		}
+	`)
+	mainLayout.AddWidget(tp.shootingBar.QWidget)
+
 	// =========================================================================
 	// Sunrise/Sunset Row
 	// =========================================================================
@@ -130,6 +311,9 @@ func (tp *TimePanel) setupUI() {
 	sunLayout.AddWidget(tp.sunsetLabel.QWidget)
 	mainLayout.AddLayout(sunLayout.QLayout)
 
+	// Teaching mode: what the elevation angles mean
+	mainLayout.AddWidget(tp.newAnnotation(help.TopicSunElevation, "#9e9e9e").QWidget)
+
 	// =========================================================================
 	// Golden Hour and Blue Hour Groups (Side by Side)
 	// =========================================================================
@@ -160,10 +344,9 @@ func (tp *TimePanel) setupUI() {
 	goldenLayout.SetSpacing(4)
 
 	// Morning and evening time range labels
-	tp.goldenMorning = qt.NewQLabel3("AM: --:-- - --:--")
-	tp.goldenEvening = qt.NewQLabel3("PM: --:-- - --:--")
-	goldenLayout.AddWidget(tp.goldenMorning.QWidget)
-	goldenLayout.AddWidget(tp.goldenEvening.QWidget)
+	tp.goldenMorning, tp.goldenMorningWeather, tp.goldenMorningBadge = tp.addPeriodRow(goldenLayout, "AM", "#ff9800", export.PeriodGoldenAM)
+	tp.goldenEvening, tp.goldenEveningWeather, tp.goldenEveningBadge = tp.addPeriodRow(goldenLayout, "PM", "#ff9800", export.PeriodGoldenPM)
+	goldenLayout.AddWidget(tp.newAnnotation(help.TopicGoldenHour, "#ff9800").QWidget)
 
 	hoursLayout.AddWidget(tp.goldenGroup.QWidget)
 
@@ -191,14 +374,134 @@ func (tp *TimePanel) setupUI() {
 	blueLayout.SetSpacing(4)
 
 	// Morning and evening time range labels
-	tp.blueMorning = qt.NewQLabel3("AM: --:-- - --:--")
-	tp.blueEvening = qt.NewQLabel3("PM: --:-- - --:--")
-	blueLayout.AddWidget(tp.blueMorning.QWidget)
-	blueLayout.AddWidget(tp.blueEvening.QWidget)
+	tp.blueMorning, tp.blueMorningWeather, tp.blueMorningBadge = tp.addPeriodRow(blueLayout, "AM", "#2196f3", export.PeriodBlueAM)
+	tp.blueEvening, tp.blueEveningWeather, tp.blueEveningBadge = tp.addPeriodRow(blueLayout, "PM", "#2196f3", export.PeriodBluePM)
+	blueLayout.AddWidget(tp.newAnnotation(help.TopicBlueHour, "#2196f3").QWidget)
 
 	hoursLayout.AddWidget(tp.blueGroup.QWidget)
 
 	mainLayout.AddLayout(hoursLayout.QLayout)
+
+	// Custom events of the active preset, one per line
+	tp.customLabel = qt.NewQLabel3("")
+	tp.customLabel.SetVisible(false)
+	mainLayout.AddWidget(tp.customLabel.QWidget)
+
+	// Teaching mode: why morning blue hour comes before sunrise
+	mainLayout.AddWidget(tp.newAnnotation(help.TopicBlueBeforeSunrise, "#9e9e9e").QWidget)
+
+	// =========================================================================
+	// Events Table (Detailed Layout)
+	// =========================================================================
+	tp.eventsTable = qt.NewQTableWidget3(0, 2)
+	tp.eventsTable.SetHorizontalHeaderLabels([]string{"Time", "Event"})
+	tp.eventsTable.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+	tp.eventsTable.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	tp.eventsTable.VerticalHeader().SetVisible(false)
+	tp.eventsTable.HorizontalHeader().SetStretchLastSection(true)
+	tp.eventsTable.SetVisible(false)
+	tp.eventsTable.SetContextMenuPolicy(qt.CustomContextMenu)
+	tp.eventsTable.OnCustomContextMenuRequested(func(pos *qt.QPoint) {
+		row := tp.eventsTable.RowAt(pos.Y())
+		if row >= 0 && row < len(tp.eventPeriods) && tp.eventPeriods[row] != "" {
+			tp.showCalendarMenu(tp.eventPeriods[row], tp.eventsTable.Viewport().MapToGlobalWithQPoint(pos))
+		}
+	})
+	mainLayout.AddWidget(tp.eventsTable.QWidget)
+}
+
+// addPeriodRow adds a period's row to a group: its time range, and the
+// weather icon and a duration badge at the right end.
+//
+// Parameters:
+//   - layout: The group's layout
+//   - prefix: "AM" or "PM"
+//   - color: The group's accent color, for the badge (CSS color string)
+//   - period: The period's export.Period constant, for the label's
+//     context menu
+//
+// Returns the time range label, the weather icon and the badge.
+//
+// miqt API notes:
+//   - SetContextMenuPolicy(qt.CustomContextMenu) makes right-clicks emit
+//     OnCustomContextMenuRequested with the position in widget coordinates
+func (tp *TimePanel) addPeriodRow(layout *qt.QVBoxLayout, prefix, color, period string) (*qt.QLabel, *qt.QLabel, *qt.QLabel) {
+	row := qt.NewQHBoxLayout2()
+	label := qt.NewQLabel3(prefix + ": --:-- - --:--")
+	label.SetContextMenuPolicy(qt.CustomContextMenu)
+	label.OnCustomContextMenuRequested(func(pos *qt.QPoint) {
+		tp.showCalendarMenu(period, label.MapToGlobalWithQPoint(pos))
+	})
+	row.AddWidget(label.QWidget)
+	row.AddStretch()
+
+	weather := qt.NewQLabel3("")
+	weather.SetStyleSheet("font-weight: normal;")
+	weather.SetVisible(false)
+	row.AddWidget(weather.QWidget)
+
+	badge := qt.NewQLabel3("")
+	badge.SetStyleSheet(fmt.Sprintf(`
+		font-weight: normal;
+		font-size: 11px;
+		color: white;
+		background: %s;
+		border-radius: 7px;
+		padding: 0 6px;
+	`, color))
+	badge.SetVisible(false)
+	row.AddWidget(badge.QWidget)
+
+	layout.AddLayout(row.QLayout)
+	return label, weather, badge
+}
+
+// showCalendarMenu offers to add a period to a calendar, if it occurs on
+// the displayed day.
+//
+// Parameters:
+//   - period: The period's export.Period constant
+//   - pos: Where to show the menu, in global coordinates
+func (tp *TimePanel) showCalendarMenu(period string, pos *qt.QPoint) {
+	ranges := map[string]domain.TimeRange{
+		export.PeriodBlueAM:   tp.sunTimes.BlueMorning,
+		export.PeriodGoldenAM: tp.sunTimes.GoldenMorning,
+		export.PeriodGoldenPM: tp.sunTimes.GoldenEvening,
+		export.PeriodBluePM:   tp.sunTimes.BlueEvening,
+	}
+	if !ranges[period].IsValid() || tp.onAddToGoogle == nil || tp.onAddToCalendar == nil {
+		return
+	}
+	menu := qt.NewQMenu(tp.groupBox.QWidget)
+	menu.AddActionWithText("Add to Google Calendar").OnTriggered(func() { tp.onAddToGoogle(period) })
+	menu.AddActionWithText("Add to My Calendar").OnTriggered(func() { tp.onAddToCalendar(period) })
+	menu.ExecWithPos(pos)
+}
+
+// newAnnotation creates a hidden teaching mode label for a help topic.
+//
+// Annotations are styled as notes (small italic text with a colored left
+// bar matching the section they explain) so they read as commentary rather
+// than data. The label starts hidden; SetTeachingMode fills in the text and
+// shows it.
+//
+// Parameters:
+//   - topicID: Help topic whose text the label displays
+//   - color: Accent color for the left bar (CSS color string)
+func (tp *TimePanel) newAnnotation(topicID, color string) *qt.QLabel {
+	label := qt.NewQLabel3("")
+	label.SetWordWrap(true)
+	label.SetStyleSheet(fmt.Sprintf(`
+		font-weight: normal;
+		font-style: italic;
+		font-size: 11px;
+		border-left: 3px solid %s;
+		padding-left: 6px;
+	`, color))
+	label.SetVisible(false)
+
+	tp.annotations[topicID] = label
+	return label
 }
 
 // Widget returns the group box container for adding to parent layouts.
@@ -235,52 +538,267 @@ func (tp *TimePanel) Widget() *qt.QGroupBox {
 func (tp *TimePanel) SetSunTimes(st domain.SunTimes, use24Hour bool) {
 	tp.use24Hour = use24Hour
 
+	// -------------------------------------------------------------------------
+	// Shooting Window (all four periods combined)
+	// -------------------------------------------------------------------------
+	tp.shootingLabel.SetText("Shooting light: " + st.FormatShootingWindow())
+	tp.shootingBar.SetValue(int(st.ShootingFraction() * 1000))
+
 	// -------------------------------------------------------------------------
 	// Sunrise and Sunset (always valid for non-polar regions)
 	// -------------------------------------------------------------------------
-	tp.sunriseLabel.SetText(fmt.Sprintf("Sunrise: %s", domain.FormatTime(st.Sunrise, use24Hour)))
-	tp.sunsetLabel.SetText(fmt.Sprintf("Sunset: %s", domain.FormatTime(st.Sunset, use24Hour)))
+	tp.sunriseLabel.SetText("Sunrise: " + tp.timeText(st.Sunrise, use24Hour))
+	tp.sunsetLabel.SetText("Sunset: " + tp.timeText(st.Sunset, use24Hour))
 
 	// -------------------------------------------------------------------------
 	// Golden Hour Times
 	// -------------------------------------------------------------------------
 	// Morning golden hour occurs just after sunrise
-	if st.GoldenMorning.IsValid() {
-		tp.goldenMorning.SetText(fmt.Sprintf("AM: %s - %s",
-			domain.FormatTime(st.GoldenMorning.Start, use24Hour),
-			domain.FormatTime(st.GoldenMorning.End, use24Hour)))
-	} else {
-		tp.goldenMorning.SetText("AM: N/A")
-	}
+	tp.goldenMorning.SetText(tp.rangeText("AM", st.GoldenMorning, use24Hour))
 
 	// Evening golden hour occurs just before sunset
-	if st.GoldenEvening.IsValid() {
-		tp.goldenEvening.SetText(fmt.Sprintf("PM: %s - %s",
-			domain.FormatTime(st.GoldenEvening.Start, use24Hour),
-			domain.FormatTime(st.GoldenEvening.End, use24Hour)))
-	} else {
-		tp.goldenEvening.SetText("PM: N/A")
-	}
+	tp.goldenEvening.SetText(tp.rangeText("PM", st.GoldenEvening, use24Hour))
 
 	// -------------------------------------------------------------------------
 	// Blue Hour Times
 	// -------------------------------------------------------------------------
 	// Morning blue hour occurs just before sunrise
-	if st.BlueMorning.IsValid() {
-		tp.blueMorning.SetText(fmt.Sprintf("AM: %s - %s",
-			domain.FormatTime(st.BlueMorning.Start, use24Hour),
-			domain.FormatTime(st.BlueMorning.End, use24Hour)))
-	} else {
-		tp.blueMorning.SetText("AM: N/A")
-	}
+	tp.blueMorning.SetText(tp.rangeText("AM", st.BlueMorning, use24Hour))
 
 	// Evening blue hour occurs just after sunset
-	if st.BlueEvening.IsValid() {
-		tp.blueEvening.SetText(fmt.Sprintf("PM: %s - %s",
-			domain.FormatTime(st.BlueEvening.Start, use24Hour),
-			domain.FormatTime(st.BlueEvening.End, use24Hour)))
-	} else {
-		tp.blueEvening.SetText("PM: N/A")
+	tp.blueEvening.SetText(tp.rangeText("PM", st.BlueEvening, use24Hour))
+
+	tp.sunTimes = st
+	tp.updatePeriodDetails()
+	tp.updateCustomEvents()
+	tp.updateEventsTable()
+}
+
+// SetLayout switches between the compact and the detailed layout.
+//
+// Parameters:
+//   - layout: domain.TimePanelCompact or domain.TimePanelDetailed
+//     (domain.Settings.TimePanelLayout)
+func (tp *TimePanel) SetLayout(layout string) {
+	tp.detailed = layout == domain.TimePanelDetailed
+	tp.sunriseLabel.SetVisible(!tp.detailed)
+	tp.sunsetLabel.SetVisible(!tp.detailed)
+	tp.goldenGroup.SetVisible(!tp.detailed)
+	tp.blueGroup.SetVisible(!tp.detailed)
+	tp.eventsTable.SetVisible(tp.detailed)
+	tp.updateCustomEvents()
+	tp.updateEventsTable()
+}
+
+// updateCustomEvents lists the custom events' times in the compact
+// layout's label, e.g., "Nautical twilight (-12°): 06:02 · 18:58".
+func (tp *TimePanel) updateCustomEvents() {
+	lines := make([]string, len(tp.sunTimes.Custom))
+	for i, ct := range tp.sunTimes.Custom {
+		lines[i] = fmt.Sprintf("%s: %s · %s", ct.Event.Label(),
+			tp.customTimeText(ct.Morning), tp.customTimeText(ct.Evening))
+	}
+	tp.customLabel.SetText(strings.Join(lines, "\n"))
+	tp.customLabel.SetVisible(!tp.detailed && len(lines) > 0)
+}
+
+// customTimeText formats a custom event time, or "--" if the sun doesn't
+// pass the elevation.
+func (tp *TimePanel) customTimeText(t time.Time) string {
+	if t.IsZero() {
+		return "--"
+	}
+	return tp.timeText(t, tp.use24Hour)
+}
+
+// updateEventsTable lists the day's events in the detailed layout's table,
+// fitting the table's height to them. Custom events are merged in by time,
+// as "Nautical twilight, morning" and "Nautical twilight, evening".
+func (tp *TimePanel) updateEventsTable() {
+	if !tp.detailed {
+		return
+	}
+	type eventRow struct {
+		time   time.Time
+		label  string
+		period string
+	}
+	periods := map[domain.EventKind]domain.TimeRange{
+		domain.EventGoldenMorningStart: tp.sunTimes.GoldenMorning,
+		domain.EventGoldenEveningStart: tp.sunTimes.GoldenEvening,
+		domain.EventBlueMorningStart:   tp.sunTimes.BlueMorning,
+		domain.EventBlueEveningStart:   tp.sunTimes.BlueEvening,
+	}
+	var events []eventRow
+	for _, event := range tp.sunTimes.Events() {
+		label := event.Kind.Label()
+		if w, ok := tp.weather.During(periods[event.Kind]); ok {
+			label += fmt.Sprintf("  %s %s", w.Icon(), w.Condition())
+		}
+		events = append(events, eventRow{event.Time, label, calendarPeriods[event.Kind]})
+	}
+	for _, ct := range tp.sunTimes.Custom {
+		if !ct.Morning.IsZero() {
+			events = append(events, eventRow{ct.Morning, ct.Event.Name + ", morning", ""})
+		}
+		if !ct.Evening.IsZero() {
+			events = append(events, eventRow{ct.Evening, ct.Event.Name + ", evening", ""})
+		}
+	}
+	slices.SortStableFunc(events, func(a, b eventRow) int { return a.time.Compare(b.time) })
+
+	tp.eventsTable.SetRowCount(len(events))
+	tp.eventPeriods = make([]string, len(events))
+	for row, event := range events {
+		tp.eventPeriods[row] = event.period
+		tp.eventsTable.SetItem(row, 0, qt.NewQTableWidgetItem2(tp.timeText(event.time, tp.use24Hour)))
+		tp.eventsTable.SetItem(row, 1, qt.NewQTableWidgetItem2(event.label))
+	}
+	tp.eventsTable.ResizeColumnsToContents()
+	if len(events) > 0 {
+		frame := 2 * tp.eventsTable.FrameWidth()
+		tp.eventsTable.SetFixedHeight(tp.eventsTable.HorizontalHeader().Height() +
+			len(events)*tp.eventsTable.RowHeight(0) + frame)
+	}
+}
+
+// SetPositions sets the day's sun positions, from which the period
+// tooltips take the sun's azimuth at the start and end of each period.
+//
+// Parameters:
+//   - series: Sun positions through the displayed day in chronological
+//     order (see solar.PositionSeries); nil leaves the azimuths out
+func (tp *TimePanel) SetPositions(series []domain.SunPathPoint) {
+	tp.positions = series
+	tp.updatePeriodDetails()
+}
+
+// SetWeather sets the weather forecast shown next to the periods.
+//
+// Parameters:
+//   - forecast: The hourly forecast for the displayed day's place (see
+//     weather.OpenMeteoService); empty hides the weather icons
+func (tp *TimePanel) SetWeather(forecast domain.WeatherForecast) {
+	tp.weather = forecast
+	tp.updatePeriodDetails()
+	tp.updateEventsTable()
+}
+
+// updatePeriodDetails fills in the duration badges, the weather icons and
+// the tooltips of the four periods.
+func (tp *TimePanel) updatePeriodDetails() {
+	rows := []struct {
+		name                  string
+		tr                    domain.TimeRange
+		label, weather, badge *qt.QLabel
+	}{
+		{"Morning golden hour", tp.sunTimes.GoldenMorning, tp.goldenMorning, tp.goldenMorningWeather, tp.goldenMorningBadge},
+		{"Evening golden hour", tp.sunTimes.GoldenEvening, tp.goldenEvening, tp.goldenEveningWeather, tp.goldenEveningBadge},
+		{"Morning blue hour", tp.sunTimes.BlueMorning, tp.blueMorning, tp.blueMorningWeather, tp.blueMorningBadge},
+		{"Evening blue hour", tp.sunTimes.BlueEvening, tp.blueEvening, tp.blueEveningWeather, tp.blueEveningBadge},
+	}
+	for _, row := range rows {
+		w, ok := tp.weather.During(row.tr)
+		if ok {
+			row.weather.SetText(w.Icon())
+			row.weather.SetToolTip(w.Summary() + "\nForecast by Open-Meteo")
+		}
+		row.weather.SetVisible(ok)
+
+		tip := ""
+		if row.tr.IsValid() {
+			row.badge.SetText(row.tr.FormatDuration())
+			tip = tp.periodToolTip(row.name, row.tr)
+		}
+		row.badge.SetVisible(row.tr.IsValid())
+		row.label.SetToolTip(tip)
+		row.badge.SetToolTip(tip)
+	}
+}
+
+// periodToolTip details a period to the second, e.g.:
+//
+//	Evening golden hour
+//	Start: 20:14:37, sun at 296° (WNW)
+//	End: 20:58:02, sun at 302° (WNW)
+//	Duration: 43 min 25 s
+//
+// The azimuths are left out for moments the day's sun positions don't
+// cover (e.g., a blue hour ending after midnight).
+func (tp *TimePanel) periodToolTip(name string, tr domain.TimeRange) string {
+	layout := "3:04:05 PM"
+	if tp.use24Hour {
+		layout = "15:04:05"
+	}
+	moment := func(t time.Time) string {
+		text := t.Format(layout)
+		if n := len(tp.positions); n > 0 && !t.Before(tp.positions[0].Time) && !t.After(tp.positions[n-1].Time) {
+			pos, _ := domain.InterpolateSunPosition(tp.positions, t)
+			text += fmt.Sprintf(", sun at %.0f° (%s)", pos.Azimuth, domain.CompassPoint(pos.Azimuth))
+		}
+		return text
+	}
+	d := tr.Duration().Round(time.Second)
+	return fmt.Sprintf("%s\nStart: %s\nEnd: %s\nDuration: %d min %d s", name,
+		moment(tr.Start), moment(tr.End), int(d.Minutes()), int(d.Seconds())%60)
+}
+
+// timeText formats t, followed by the home clock in parentheses when it
+// reads differently (see domain.FormatHomeTime).
+func (tp *TimePanel) timeText(t time.Time, use24Hour bool) string {
+	text := domain.FormatTime(t, use24Hour)
+	if home := domain.FormatHomeTime(t, tp.home, use24Hour); home != "" {
+		text += " (" + home + ")"
+	}
+	return text
+}
+
+// rangeText formats a period as "AM: 07:15 - 08:15", or "AM: N/A" if it
+// doesn't occur. The home clock, when shown, follows on a second line.
+func (tp *TimePanel) rangeText(prefix string, tr domain.TimeRange, use24Hour bool) string {
+	if !tr.IsValid() {
+		return prefix + ": N/A"
+	}
+	text := fmt.Sprintf("%s: %s - %s", prefix,
+		domain.FormatTime(tr.Start, use24Hour), domain.FormatTime(tr.End, use24Hour))
+	if home := domain.FormatHomeRange(tr.Start, tr.End, tp.home, use24Hour); home != "" {
+		text += "\n      " + home
+	}
+	return text
+}
+
+// SetHomeTimezone sets the timezone of the home clock.
+//
+// Like SetTimeFormat, this doesn't update the display; the next call to
+// SetSunTimes shows the home clock.
+//
+// Parameters:
+//   - name: An IANA timezone (domain.Settings.HomeTimezone); empty or
+//     invalid names turn the home clock off
+func (tp *TimePanel) SetHomeTimezone(name string) {
+	tp.home = nil
+	if domain.ValidTimezone(name) {
+		tp.home, _ = time.LoadLocation(name)
+	}
+}
+
+// SetTeachingMode shows or hides the teaching mode annotations.
+//
+// The annotation text is re-rendered from the help data on every call, so
+// the explanations follow the current elevation settings (e.g., a golden
+// hour elevation changed from 6° to 8° is reflected immediately). Topics
+// missing from the data file stay hidden.
+//
+// Parameters:
+//   - settings: Current settings; TeachingMode controls visibility and the
+//     elevation angles fill the text placeholders
+func (tp *TimePanel) SetTeachingMode(settings domain.Settings) {
+	for topicID, label := range tp.annotations {
+		text := help.Render(topicID, settings)
+		if topic, ok := help.Lookup(topicID); ok && text != "" {
+			label.SetText(fmt.Sprintf("<b>%s:</b> %s", topic.Title, text))
+		}
+		label.SetVisible(settings.TeachingMode && text != "")
 	}
 }
 
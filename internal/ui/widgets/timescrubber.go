@@ -0,0 +1,157 @@
+package widgets
+
+import (
+	"fmt"
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// TimeScrubber
+// =============================================================================
+
+// TimeScrubber is a time slider for the selected day: dragging it shows
+// where the sun stands at that moment, in a readout and on the map.
+//
+// # UI Layout
+//
+//	┌─ Sun Position ─────────────────────────────────────┐
+//	│ [────────────────────●─────────────]        18:25  │
+//	│ Elevation 22.4°, azimuth 281° (W)                  │
+//	└────────────────────────────────────────────────────┘
+//
+// The slider covers the day from midnight to 23:59 in the location's
+// timezone, in minutes. The position comes from a series of samples
+// through the day (see solar.PositionSeries) that the App hands over after
+// every recalculation; while dragging, positions are interpolated from it
+// (domain.InterpolateSunPosition), so no calculation or timer runs during
+// the drag.
+//
+// # Communication
+//
+// Each slider move invokes onScrub with the interpolated position; the
+// MainWindow draws it on the map. The slider keeps its time of day when
+// the date or location changes.
+type TimeScrubber struct {
+	// groupBox is the container widget with "Sun Position" title border.
+	groupBox *qt.QGroupBox
+
+	// slider selects the minute of the day (0-1439).
+	slider *qt.QSlider
+
+	// timeLabel shows the slider's time.
+	timeLabel *qt.QLabel
+
+	// readoutLabel shows the sun's elevation and azimuth at that time.
+	readoutLabel *qt.QLabel
+
+	// series are the day's sampled sun positions; empty disables the slider.
+	series []domain.SunPathPoint
+
+	// use24Hour is the time display format.
+	use24Hour bool
+
+	// onScrub is the callback invoked with the sun's position when the
+	// slider moves or the series changes.
+	onScrub func(pos domain.SunPathPoint)
+}
+
+// minutesPerDay is the slider's range: one step per minute of the day.
+const minutesPerDay = 24 * 60
+
+// NewTimeScrubber creates a time slider set to the current time of day.
+//
+// Parameters:
+//   - use24Hour: Time display format
+//   - onScrub: Callback invoked with the sun's position at the slider's
+//     time
+//
+// The slider is disabled until SetPositions provides the day's positions.
+func NewTimeScrubber(use24Hour bool, onScrub func(pos domain.SunPathPoint)) *TimeScrubber {
+	ts := &TimeScrubber{use24Hour: use24Hour, onScrub: onScrub}
+	ts.setupUI()
+	return ts
+}
+
+// setupUI creates the slider and the readout labels.
+//
+// miqt API notes:
+//   - NewQSlider3(qt.Horizontal): Slider with an orientation (suffix "3")
+//   - SetSingleStep/SetPageStep: Arrow keys move 5 minutes, Page Up/Down
+//     an hour
+func (ts *TimeScrubber) setupUI() {
+	ts.groupBox = qt.NewQGroupBox3("Sun Position")
+	layout := qt.NewQVBoxLayout(ts.groupBox.QWidget)
+	layout.SetContentsMargins(6, 6, 6, 6)
+
+	row := qt.NewQHBoxLayout2()
+	ts.slider = qt.NewQSlider3(qt.Horizontal)
+	ts.slider.SetRange(0, minutesPerDay-1)
+	ts.slider.SetSingleStep(5)
+	ts.slider.SetPageStep(60)
+	ts.slider.SetToolTip("Drag to see where the sun stands at any time of the day")
+	now := time.Now()
+	ts.slider.SetValue(now.Hour()*60 + now.Minute())
+	ts.slider.OnValueChanged(func(int) { ts.update() })
+	row.AddWidget(ts.slider.QWidget)
+
+	ts.timeLabel = qt.NewQLabel2()
+	ts.timeLabel.SetMinimumWidth(64)
+	ts.timeLabel.SetAlignment(qt.AlignRight | qt.AlignVCenter)
+	ts.timeLabel.SetStyleSheet("font-weight: bold;")
+	row.AddWidget(ts.timeLabel.QWidget)
+	layout.AddLayout(row.QLayout)
+
+	ts.readoutLabel = qt.NewQLabel2()
+	layout.AddWidget(ts.readoutLabel.QWidget)
+
+	ts.slider.SetEnabled(false)
+}
+
+// Widget returns the group box container for adding to parent layouts.
+func (ts *TimeScrubber) Widget() *qt.QGroupBox {
+	return ts.groupBox
+}
+
+// SetPositions replaces the day's sun positions and reports the position
+// at the slider's time.
+//
+// Parameters:
+//   - series: Sun positions through the selected day in chronological
+//     order (see solar.PositionSeries); nil disables the slider
+//   - use24Hour: Time display format
+func (ts *TimeScrubber) SetPositions(series []domain.SunPathPoint, use24Hour bool) {
+	ts.series = series
+	ts.use24Hour = use24Hour
+	ts.slider.SetEnabled(len(series) > 0)
+	ts.update()
+}
+
+// update shows the position at the slider's time and reports it.
+func (ts *TimeScrubber) update() {
+	if len(ts.series) == 0 {
+		ts.timeLabel.SetText("")
+		ts.readoutLabel.SetText("No sun positions for this day")
+		return
+	}
+
+	// Wall-clock minutes of the day; time.Date resolves daylight saving
+	// gaps and repeats
+	day := ts.series[0].Time
+	t := time.Date(day.Year(), day.Month(), day.Day(), 0, ts.slider.Value(), 0, 0, day.Location())
+	pos, _ := domain.InterpolateSunPosition(ts.series, t)
+
+	ts.timeLabel.SetText(domain.FormatTime(t, ts.use24Hour))
+	state := ""
+	if pos.Elevation < 0 {
+		state = " (below the horizon)"
+	}
+	ts.readoutLabel.SetText(fmt.Sprintf("Elevation %.1f°%s, azimuth %.0f° (%s)",
+		pos.Elevation, state, pos.Azimuth, domain.CompassPoint(pos.Azimuth)))
+
+	if ts.onScrub != nil {
+		ts.onScrub(pos)
+	}
+}
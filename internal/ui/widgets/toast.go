@@ -0,0 +1,154 @@
+package widgets
+
+import (
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+)
+
+// =============================================================================
+// Toast
+// =============================================================================
+
+const (
+	// toastTimeout is how long a toast stays before it hides itself.
+	toastTimeout = 10 * time.Second
+
+	// retryToastTimeout is how long a toast with a Retry button stays,
+	// longer so there is time to decide.
+	retryToastTimeout = 20 * time.Second
+
+	// toastMargin is the toast's distance from the parent's bottom edge and
+	// the least distance from its sides, in pixels.
+	toastMargin = 16
+)
+
+// Toast is a notification that floats over the bottom of the window,
+// for errors that shouldn't go unnoticed but shouldn't block either:
+//
+//	┌──────────────────────────────────────────────────────────┐
+//	│ ⚠ Search failed: request timed out        [Retry]  [✕]   │
+//	└──────────────────────────────────────────────────────────┘
+//
+// A toast hides itself after a while (toastTimeout, longer with a Retry
+// button), or when closed with ✕. A new toast replaces the one shown.
+// "Retry" hides the toast and runs the failed operation again.
+//
+// The toast is a child widget of the window's central widget rather than a
+// window of its own, so it moves with the window and never takes the
+// focus. The parent calls Reposition when its size changes.
+type Toast struct {
+	// frame is the toast itself, on top of the parent's other children.
+	frame *qt.QWidget
+
+	// label shows the message.
+	label *qt.QLabel
+
+	// retryButton runs retry; hidden for toasts without one.
+	retryButton *qt.QPushButton
+
+	// timer hides the toast.
+	timer *qt.QTimer
+
+	// retry runs the failed operation again, or nil.
+	retry func()
+}
+
+// NewToast creates a hidden toast over a widget.
+//
+// Parameters:
+//   - parent: The widget the toast floats over (the window's central widget)
+//
+// miqt API notes:
+//   - WA_StyledBackground: A plain QWidget paints its style sheet background
+//     only with this attribute
+//   - A child widget created after its siblings is drawn above them; Raise
+//     keeps it there after other children are added
+func NewToast(parent *qt.QWidget) *Toast {
+	t := &Toast{}
+
+	t.frame = qt.NewQWidget(parent)
+	t.frame.SetAttribute(qt.WA_StyledBackground)
+	t.frame.SetStyleSheet("QWidget { background: #b71c1c; color: white; border-radius: 6px; }")
+
+	layout := qt.NewQHBoxLayout(t.frame)
+	layout.SetContentsMargins(12, 8, 8, 8)
+
+	t.label = qt.NewQLabel3("")
+	t.label.SetWordWrap(true)
+	layout.AddWidget(t.label.QWidget)
+
+	buttonStyle := "QPushButton { background: white; color: #b71c1c; border-radius: 4px; padding: 2px 10px; }"
+	t.retryButton = qt.NewQPushButton3("Retry")
+	t.retryButton.SetStyleSheet(buttonStyle)
+	t.retryButton.OnClicked(func() {
+		retry := t.retry
+		t.Hide()
+		if retry != nil {
+			retry()
+		}
+	})
+	layout.AddWidget(t.retryButton.QWidget)
+
+	closeButton := qt.NewQPushButton3("✕")
+	closeButton.SetStyleSheet(buttonStyle)
+	closeButton.SetToolTip("Dismiss")
+	closeButton.OnClicked(t.Hide)
+	layout.AddWidget(closeButton.QWidget)
+
+	t.timer = qt.NewQTimer2(t.frame.QObject)
+	t.timer.SetSingleShot(true)
+	t.timer.OnTimeout(t.Hide)
+
+	t.frame.Hide()
+	return t
+}
+
+// Show shows a message, replacing the toast shown.
+//
+// Parameters:
+//   - message: The error message
+//   - retry: Runs the failed operation again, offered with a Retry button;
+//     nil for none
+func (t *Toast) Show(message string, retry func()) {
+	t.retry = retry
+	t.label.SetText("⚠ " + message)
+	t.retryButton.SetVisible(retry != nil)
+	t.frame.Show()
+	t.frame.Raise()
+	t.Reposition()
+
+	timeout := toastTimeout
+	if retry != nil {
+		timeout = retryToastTimeout
+	}
+	t.timer.Start(int(timeout.Milliseconds()))
+}
+
+// Visible reports whether a toast is shown.
+func (t *Toast) Visible() bool {
+	return t.frame.IsVisible()
+}
+
+// Hide hides the toast and forgets its retry.
+func (t *Toast) Hide() {
+	t.timer.Stop()
+	t.retry = nil
+	t.frame.Hide()
+}
+
+// Reposition centers the toast at the bottom of its parent, as wide as its
+// message needs (at most the parent's width less the margins). Called
+// when the toast is shown and when the parent is resized.
+func (t *Toast) Reposition() {
+	if !t.frame.IsVisible() {
+		return
+	}
+	parent := t.frame.ParentWidget()
+	width := min(t.frame.SizeHint().Width(), parent.Width()-2*toastMargin)
+	height := t.frame.HeightForWidth(width)
+	if height <= 0 {
+		height = t.frame.SizeHint().Height()
+	}
+	t.frame.SetGeometry((parent.Width()-width)/2, parent.Height()-height-toastMargin, width, height)
+}
@@ -0,0 +1,222 @@
+package widgets
+
+import (
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/crash"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// TrayIcon
+// =============================================================================
+
+// trayInterval is how often the tooltip is refreshed. The tooltip shows
+// minutes, so twice a minute keeps it current without a busy timer.
+const trayInterval = 30 * time.Second
+
+// TrayIcon is the app's icon in the system tray (notification area).
+//
+// Its tooltip names the next golden or blue hour at the location and how
+// soon it is, so the time can be checked without opening the window:
+//
+//	GoGoldenHour
+//	Evening golden hour at 20:15, in 2h 5m
+//
+// # Context Menu
+//
+//	┌─────────────────────────┐
+//	│ Hide Window             │  (Show Window while hidden)
+//	│ [ ] Pause Notifications │
+//	├─────────────────────────┤
+//	│ Quit                    │
+//	└─────────────────────────┘
+//
+// Clicking the icon shows or hides the window, like the first menu entry.
+// Pausing notifications stops the desktop notifications, phone reminders
+// and webhook calls (for this session) until it is unchecked; the tooltip
+// says so while they are paused. Automation hooks keep running.
+//
+// The desktop notifications before golden and blue hours (see
+// domain.DesktopNotifications) are shown from the icon with ShowMessage.
+//
+// Like CountdownPanel, the icon is handed the sun times of the days around
+// today (see SetDays) and updates the tooltip from them without any
+// calculation.
+type TrayIcon struct {
+	// tray is the system tray icon, and icon its image (also shown in the
+	// notifications).
+	tray *qt.QSystemTrayIcon
+	icon *qt.QIcon
+
+	// menu is the context menu; toggleAction shows or hides the window,
+	// pauseAction pauses the notifications (checkable).
+	menu         *qt.QMenu
+	toggleAction *qt.QAction
+	pauseAction  *qt.QAction
+
+	// timer refreshes the tooltip while there are days to count.
+	timer *qt.QTimer
+
+	// days are the sun times of consecutive days around today.
+	days []domain.SunTimes
+
+	// use24Hour is the time display format.
+	use24Hour bool
+
+	// onToggleWindow, onPause and onQuit are invoked from the menu.
+	onToggleWindow func()
+	onPause        func(paused bool)
+	onQuit         func()
+}
+
+// NewTrayIcon creates the tray icon and shows it.
+//
+// Parameters:
+//   - parent: Owner of the icon and its timer
+//   - onToggleWindow: Callback that shows the window if hidden, or hides it
+//   - onPause: Callback invoked when notifications are paused or resumed
+//   - onQuit: Callback that quits the app
+//
+// Callers should check TrayAvailable first; without a system tray the icon
+// is never shown.
+//
+// miqt API notes:
+//   - NewQSystemTrayIcon4(icon, parent): Icon owned by parent (suffix "4")
+//   - OnActivated reports clicks with a reason; Trigger is a plain click
+//     (the context menu opens on its own)
+func NewTrayIcon(parent *qt.QObject, onToggleWindow func(), onPause func(paused bool), onQuit func()) *TrayIcon {
+	ti := &TrayIcon{onToggleWindow: onToggleWindow, onPause: onPause, onQuit: onQuit}
+
+	ti.icon = TrayImage()
+	ti.tray = qt.NewQSystemTrayIcon4(ti.icon, parent)
+	ti.menu = qt.NewQMenu2()
+	ti.toggleAction = ti.menu.AddActionWithText("Hide Window")
+	ti.toggleAction.OnTriggered(crash.Guard(func() {
+		if ti.onToggleWindow != nil {
+			ti.onToggleWindow()
+		}
+	}))
+	ti.pauseAction = ti.menu.AddActionWithText("Pause Notifications")
+	ti.pauseAction.SetCheckable(true)
+	ti.pauseAction.SetToolTip("Stop desktop notifications, phone reminders and webhook calls until unchecked")
+	ti.pauseAction.OnTriggered(crash.Guard(func() {
+		ti.update()
+		if ti.onPause != nil {
+			ti.onPause(ti.pauseAction.IsChecked())
+		}
+	}))
+	ti.menu.AddSeparator()
+	quitAction := ti.menu.AddActionWithText("Quit")
+	quitAction.OnTriggered(crash.Guard(func() {
+		if ti.onQuit != nil {
+			ti.onQuit()
+		}
+	}))
+	ti.tray.SetContextMenu(ti.menu)
+
+	ti.tray.OnActivated(func(reason qt.QSystemTrayIcon__ActivationReason) {
+		defer crash.Recover()
+		if reason == qt.QSystemTrayIcon__Trigger && ti.onToggleWindow != nil {
+			ti.onToggleWindow()
+		}
+	})
+
+	ti.timer = qt.NewQTimer2(parent)
+	ti.timer.SetInterval(int(trayInterval / time.Millisecond))
+	ti.timer.OnTimeout(crash.Guard(ti.update))
+
+	ti.update()
+	ti.tray.Show()
+	return ti
+}
+
+// TrayAvailable reports whether the desktop has a system tray to show the
+// icon in (some Wayland and GNOME setups don't).
+func TrayAvailable() bool {
+	return qt.QSystemTrayIcon_IsSystemTrayAvailable()
+}
+
+// TrayImage draws the app's icon: a golden sun setting on the horizon.
+// The app ships no image files, so it is painted at startup.
+//
+// miqt API notes:
+//   - FillWithFillColor(transparent) clears the new pixmap, whose pixels
+//     are otherwise undefined
+//   - SetPenWithStyle(NoPen) draws the shapes without outlines
+//   - DrawPie2 angles are in 1/16 degrees, counterclockwise from 3 o'clock
+func TrayImage() *qt.QIcon {
+	const size = 64
+	pixmap := qt.NewQPixmap2(size, size)
+	pixmap.FillWithFillColor(qt.NewQColor2(qt.Transparent))
+
+	painter := qt.NewQPainter2(pixmap.QPaintDevice)
+	painter.SetRenderHint(qt.QPainter__Antialiasing)
+	painter.SetPenWithStyle(qt.NoPen)
+	painter.SetBrush(qt.NewQBrush3(qt.NewQColor3(0xFF, 0xA5, 0x00)))
+	painter.DrawPie2(8, 14, 48, 48, 0, 180*16)
+	painter.SetBrush(qt.NewQBrush3(qt.NewQColor3(0x41, 0x69, 0xE1)))
+	painter.DrawRect2(4, 42, size-8, 6)
+	painter.End()
+
+	return qt.NewQIcon2(pixmap)
+}
+
+// SetDays replaces the days the tooltip counts down through.
+//
+// Parameters:
+//   - days: Sun times of consecutive days at the location, in order (see
+//     CountdownPanel.SetDays); nil shows no countdown
+//   - use24Hour: Time display format
+func (ti *TrayIcon) SetDays(days []domain.SunTimes, use24Hour bool) {
+	ti.days = days
+	ti.use24Hour = use24Hour
+	if len(days) > 0 {
+		ti.timer.Start2()
+	} else {
+		ti.timer.Stop()
+	}
+	ti.update()
+}
+
+// SetWindowVisible relabels the first menu entry after the window was
+// shown or hidden.
+func (ti *TrayIcon) SetWindowVisible(visible bool) {
+	if visible {
+		ti.toggleAction.SetText("Hide Window")
+	} else {
+		ti.toggleAction.SetText("Show Window")
+	}
+}
+
+// ShowMessage shows a desktop notification next to the icon. On Linux it
+// is handed to the desktop's notification service, like notify-send would;
+// the desktop decides how long it stays.
+//
+// miqt API notes:
+//   - ShowMessage(title, msg, icon) takes a QIcon; ShowMessage2 would show
+//     Qt's generic information icon instead
+func (ti *TrayIcon) ShowMessage(title, message string) {
+	ti.tray.ShowMessage(title, message, ti.icon)
+}
+
+// Hide removes the icon from the tray, e.g., before the app quits.
+func (ti *TrayIcon) Hide() {
+	ti.timer.Stop()
+	ti.tray.Hide()
+}
+
+// update refreshes the tooltip at the current time.
+func (ti *TrayIcon) update() {
+	status := "No sun times for the location"
+	if len(ti.days) > 0 {
+		// Count in the location's timezone
+		now := time.Now().In(ti.days[len(ti.days)-1].Date.Location())
+		status = domain.LightStatus(ti.days, now, ti.use24Hour)
+	}
+	if ti.pauseAction.IsChecked() {
+		status += "\nNotifications paused"
+	}
+	ti.tray.SetToolTip("GoGoldenHour\n" + status)
+}
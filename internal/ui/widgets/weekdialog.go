@@ -0,0 +1,183 @@
+package widgets
+
+import (
+	"fmt"
+	"time"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// WeekDialog
+// =============================================================================
+
+// WeekDialog rates the golden and blue hours of the coming week at the
+// selected location by the weather forecast, to pick the days worth
+// planning a shoot for.
+//
+//	┌─ Week Outlook ─────────────────────────────────────────────┐
+//	│ Date         Golden AM      Golden PM      Blue AM   ...   │
+//	│ Sat, Jun 21  05:47  🌤 95%  21:07  ⛅ 55%  04:52  🌤 95%   │
+//	│ Sun, Jun 22  05:47  ☁ 10%   21:07  🌧 10%  04:52  ☁ 10%    │
+//	│ ...                                                        │
+//	│ Chance of usable light from the Open-Meteo forecast        │
+//	│                                                  [ Close ] │
+//	└────────────────────────────────────────────────────────────┘
+//
+// Each period shows its start time, the forecast weather and the chance of
+// usable light (see domain.PeriodWeather.ShootProbability), on a green,
+// amber or red background (domain.ShootLikely, domain.ShootPossible). The
+// tooltip gives the period and the forecast in detail. Without a forecast
+// (privacy mode, no connection) only the times are shown, with the reason
+// below the table. Clicking a day selects it as the date in the main
+// window.
+//
+// Like the month calendar, the dialog is modeless and is refreshed while
+// open when the location or settings change. The days and the forecast
+// come from the App (see SetOutlook); the dialog only displays them.
+type WeekDialog struct {
+	// dialog is the top-level modeless dialog.
+	dialog *qt.QDialog
+
+	// table has one row per day.
+	table *qt.QTableWidget
+
+	// noteLabel tells where the chances come from, or why there are none.
+	noteLabel *qt.QLabel
+
+	// dates are the days of the rows.
+	dates []time.Time
+
+	// onSelect is invoked with the date of a clicked day.
+	onSelect func(date time.Time)
+}
+
+// weekColumns are the table's headers: the date, then the periods, named
+// in the tooltips as in weekPeriodNames.
+var (
+	weekColumns     = []string{"Date", "Golden AM", "Golden PM", "Blue AM", "Blue PM"}
+	weekPeriodNames = []string{"Morning golden hour", "Evening golden hour", "Morning blue hour", "Evening blue hour"}
+)
+
+// NewWeekDialog creates the week outlook (initially hidden).
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - onSelect: Callback invoked with the date of a clicked day
+//
+// Call SetOutlook to fill it, then Show.
+func NewWeekDialog(parent *qt.QWidget, onSelect func(date time.Time)) *WeekDialog {
+	wd := &WeekDialog{onSelect: onSelect}
+	wd.setupUI(parent)
+	return wd
+}
+
+// setupUI creates the table, the note and the Close button.
+//
+// miqt API notes:
+//   - OnCellClicked(row, column) reports clicks on any cell of a row
+//   - QTableWidgetItem.SetBackground takes a QBrush (NewQBrush3 from a
+//     QColor)
+func (wd *WeekDialog) setupUI(parent *qt.QWidget) {
+	wd.dialog = qt.NewQDialog(parent)
+	wd.dialog.SetWindowTitle("Week Outlook")
+	wd.dialog.Resize(640, 340)
+
+	layout := qt.NewQVBoxLayout(wd.dialog.QWidget)
+
+	wd.table = qt.NewQTableWidget3(0, len(weekColumns))
+	wd.table.SetHorizontalHeaderLabels(weekColumns)
+	wd.table.SetEditTriggers(qt.QAbstractItemView__NoEditTriggers)
+	wd.table.SetSelectionBehavior(qt.QAbstractItemView__SelectRows)
+	wd.table.VerticalHeader().SetVisible(false)
+	wd.table.HorizontalHeader().SetStretchLastSection(true)
+	wd.table.OnCellClicked(func(row, _ int) {
+		if row < len(wd.dates) && wd.onSelect != nil {
+			wd.onSelect(wd.dates[row])
+		}
+	})
+	layout.AddWidget(wd.table.QWidget)
+
+	wd.noteLabel = qt.NewQLabel3("Loading the forecast…")
+	wd.noteLabel.SetWordWrap(true)
+	wd.noteLabel.SetStyleSheet("color: palette(mid); font-size: 11px;")
+	layout.AddWidget(wd.noteLabel.QWidget)
+
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		wd.dialog.Reject()
+	})
+	layout.AddWidget(buttons.QWidget)
+}
+
+// SetOutlook shows the days and their chances.
+//
+// Parameters:
+//   - days: Sun times of each day, in order (see App.FetchWeekOutlook)
+//   - forecast: The hourly forecast covering the days; periods it doesn't
+//     cover show their times only
+//   - err: Why there is no forecast, shown below the table; nil if there
+//     is one
+//   - use24Hour: Time display format
+func (wd *WeekDialog) SetOutlook(days []domain.SunTimes, forecast domain.WeatherForecast, err error, use24Hour bool) {
+	wd.dates = make([]time.Time, len(days))
+	wd.table.SetRowCount(len(days))
+	for row, day := range days {
+		wd.dates[row] = day.Date
+		wd.table.SetItem(row, 0, qt.NewQTableWidgetItem2(day.Date.Format("Mon, Jan 2")))
+		periods := []domain.TimeRange{day.GoldenMorning, day.GoldenEvening, day.BlueMorning, day.BlueEvening}
+		for i, tr := range periods {
+			wd.table.SetItem(row, 1+i, weekPeriodItem(weekPeriodNames[i], tr, forecast, use24Hour))
+		}
+	}
+	wd.table.ResizeColumnsToContents()
+
+	if err != nil {
+		wd.noteLabel.SetText(fmt.Sprintf("No forecast: %v. Showing the times only.", err))
+	} else {
+		wd.noteLabel.SetText("Chance of usable light from the Open-Meteo forecast of cloud cover, " +
+			"rain and visibility. Click a day to select it.")
+	}
+}
+
+// weekPeriodItem creates a period's cell: its start time and, within the
+// forecast, the weather icon and the chance on a colored background.
+func weekPeriodItem(name string, tr domain.TimeRange, forecast domain.WeatherForecast, use24Hour bool) *qt.QTableWidgetItem {
+	if !tr.IsValid() {
+		return qt.NewQTableWidgetItem2("N/A")
+	}
+	start := domain.FormatTime(tr.Start, use24Hour)
+	tip := fmt.Sprintf("%s: %s - %s", name, start, domain.FormatTime(tr.End, use24Hour))
+	w, ok := forecast.During(tr)
+	if !ok {
+		item := qt.NewQTableWidgetItem2(start)
+		item.SetToolTip(tip)
+		return item
+	}
+
+	chance := w.ShootProbability()
+	item := qt.NewQTableWidgetItem2(fmt.Sprintf("%s  %s %d%%", start, w.Icon(), chance))
+	item.SetToolTip(fmt.Sprintf("%s\n%s\n%d%% chance of usable light", tip, w.Summary(), chance))
+	color := qt.NewQColor11(244, 67, 54, 80) // red
+	switch {
+	case chance >= domain.ShootLikely:
+		color = qt.NewQColor11(76, 175, 80, 90) // green
+	case chance >= domain.ShootPossible:
+		color = qt.NewQColor11(255, 193, 7, 90) // amber
+	}
+	item.SetBackground(qt.NewQBrush3(color))
+	return item
+}
+
+// Show opens the dialog (or brings it to the front if already open).
+func (wd *WeekDialog) Show() {
+	wd.dialog.Show()
+	wd.dialog.Raise()
+	wd.dialog.ActivateWindow()
+}
+
+// IsVisible reports whether the dialog is open.
+func (wd *WeekDialog) IsVisible() bool {
+	return wd.dialog.IsVisible()
+}
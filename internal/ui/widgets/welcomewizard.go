@@ -0,0 +1,186 @@
+package widgets
+
+import (
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/domain"
+)
+
+// =============================================================================
+// Welcome Wizard
+// =============================================================================
+
+// maxWelcomeResults is how many cities the wizard's home search lists.
+const maxWelcomeResults = 8
+
+// WelcomeChoices are the answers of the welcome wizard (see
+// ShowWelcomeWizard), the first settings of a new user.
+type WelcomeChoices struct {
+	// Home is the chosen home location (domain.Settings.HomeLocation), or
+	// nil to keep the default.
+	Home *domain.Location
+
+	// AutoDetect turns location detection on startup on
+	// (domain.Settings.AutoDetectLocation).
+	AutoDetect bool
+
+	// TimeFormat24Hour selects 24-hour times (domain.Settings.TimeFormat24Hour).
+	TimeFormat24Hour bool
+
+	// CoordinateFormat is the unit of the coordinates shown
+	// (domain.CoordinateFormatDecimal or domain.CoordinateFormatDMS).
+	CoordinateFormat string
+}
+
+// ShowWelcomeWizard asks a new user for the first settings, on the first
+// start (no settings file yet), instead of greeting them with London.
+//
+// Two pages:
+//
+//	┌─ Welcome to GoGoldenHour ──────────────────────────────────┐
+//	│ Where do you usually photograph?                           │
+//	│ Home: [Zurich                               ] [Find]       │
+//	│ ┌────────────────────────────────────────────────────────┐ │
+//	│ │ Zürich, Zurich, Switzerland                            │ │
+//	│ │ Zürichberg, Zurich, Switzerland                        │ │
+//	│ └────────────────────────────────────────────────────────┘ │
+//	│ [✓] Detect my location automatically on startup            │
+//	│                                    [ Next > ] [ Cancel ]   │
+//	└────────────────────────────────────────────────────────────┘
+//
+//	┌─ Welcome to GoGoldenHour ──────────────────────────────────┐
+//	│ How should times and coordinates be shown?                 │
+//	│ Times:        (•) 24-hour (18:30)   ( ) 12-hour (6:30 PM)  │
+//	│ Coordinates:  (•) Decimal degrees   ( ) Degrees, minutes…  │
+//	│                          [ < Back ] [ Finish ] [ Cancel ]  │
+//	└────────────────────────────────────────────────────────────┘
+//
+// The home location is shown when detection is off or fails; without one,
+// London is used. Cities come from the offline database, so the wizard
+// works before any online service is used.
+//
+// Parameters:
+//   - parent: Parent widget (the wizard is centered over it)
+//   - settings: The default settings, for the initial answers
+//   - search: Finds cities for the home search (geocoding.SearchOffline),
+//     best match first
+//
+// Returns the answers, and ok=false if the user canceled. The wizard is
+// modal and blocks until it is closed.
+//
+// miqt API notes:
+//   - QWizard is a QDialog; Exec is inherited and returns QDialog__Accepted
+//     when Finish was pressed
+//   - NewQWizardPage2(): Page without a parent (AddPage takes ownership)
+//   - NewQButtonGroup2(parent): Makes its buttons exclusive; AddButton
+//     takes the embedded QAbstractButton
+func ShowWelcomeWizard(parent *qt.QWidget, settings domain.Settings,
+	search func(query string, limit int) []domain.SearchResult) (choices WelcomeChoices, ok bool) {
+	wizard := qt.NewQWizard(parent)
+	wizard.SetWindowTitle("Welcome to GoGoldenHour")
+	wizard.SetWizardStyle(qt.QWizard__ModernStyle)
+	wizard.SetOption(qt.QWizard__NoBackButtonOnStartPage)
+
+	// =========================================================================
+	// Page 1: Location
+	// =========================================================================
+	locationPage := qt.NewQWizardPage2()
+	locationPage.SetTitle("Where do you usually photograph?")
+	locationPage.SetSubTitle("Your home location is shown when the location isn't detected. " +
+		"You can change it later in Edit → Preferences → Network.")
+	locationLayout := qt.NewQVBoxLayout(locationPage.QWidget)
+
+	searchRow := qt.NewQHBoxLayout2()
+	searchEdit := qt.NewQLineEdit2()
+	searchEdit.SetPlaceholderText("City, e.g., Zurich or Paris, Texas")
+	searchRow.AddWidget(searchEdit.QWidget)
+	findBtn := qt.NewQPushButton3("Find")
+	searchRow.AddWidget(findBtn.QWidget)
+	searchForm := qt.NewQFormLayout2()
+	searchForm.AddRow4("Home:", searchRow.QLayout)
+	locationLayout.AddLayout(searchForm.QLayout)
+
+	resultList := qt.NewQListWidget2()
+	locationLayout.AddWidget(resultList.QWidget)
+	noResults := qt.NewQLabel3("No city found. Try the name in English or without the country.")
+	noResults.SetStyleSheet("color: gray;")
+	noResults.Hide()
+	locationLayout.AddWidget(noResults.QWidget)
+
+	var results []domain.SearchResult
+	find := func() {
+		results = search(strings.TrimSpace(searchEdit.Text()), maxWelcomeResults)
+		resultList.Clear()
+		for _, r := range results {
+			resultList.AddItem(r.Location.Name)
+		}
+		if len(results) > 0 {
+			resultList.SetCurrentRow(0)
+		}
+		noResults.SetVisible(len(results) == 0 && strings.TrimSpace(searchEdit.Text()) != "")
+	}
+	findBtn.OnClicked(find)
+	searchEdit.OnReturnPressed(find)
+
+	autoDetectCheck := qt.NewQCheckBox3("Detect my location automatically on startup")
+	autoDetectCheck.SetChecked(settings.AutoDetectLocation)
+	autoDetectCheck.SetToolTip("Uses your IP address; other backends can be chosen in the preferences")
+	locationLayout.AddWidget(autoDetectCheck.QWidget)
+	wizard.AddPage(locationPage)
+
+	// =========================================================================
+	// Page 2: Formats
+	// =========================================================================
+	formatsPage := qt.NewQWizardPage2()
+	formatsPage.SetTitle("How should times and coordinates be shown?")
+	formatsPage.SetSubTitle("Both can be changed later in the Settings panel and the preferences.")
+	formatsForm := qt.NewQFormLayout(formatsPage.QWidget)
+
+	// Radio buttons of one parent exclude each other, so each pair gets a
+	// button group of its own
+	timeRow := qt.NewQHBoxLayout2()
+	time24Radio := qt.NewQRadioButton3("24-hour (18:30)")
+	time12Radio := qt.NewQRadioButton3("12-hour (6:30 PM)")
+	timeGroup := qt.NewQButtonGroup2(wizard.QObject)
+	timeGroup.AddButton(time24Radio.QAbstractButton)
+	timeGroup.AddButton(time12Radio.QAbstractButton)
+	time24Radio.SetChecked(settings.TimeFormat24Hour)
+	time12Radio.SetChecked(!settings.TimeFormat24Hour)
+	timeRow.AddWidget(time24Radio.QWidget)
+	timeRow.AddWidget(time12Radio.QWidget)
+	timeRow.AddStretch()
+	formatsForm.AddRow4("Times:", timeRow.QLayout)
+
+	coordinateRow := qt.NewQHBoxLayout2()
+	decimalRadio := qt.NewQRadioButton3("Decimal degrees (48.8566° N)")
+	dmsRadio := qt.NewQRadioButton3("Degrees, minutes, seconds (48°51'23.8\" N)")
+	coordinateGroup := qt.NewQButtonGroup2(wizard.QObject)
+	coordinateGroup.AddButton(decimalRadio.QAbstractButton)
+	coordinateGroup.AddButton(dmsRadio.QAbstractButton)
+	decimalRadio.SetChecked(settings.CoordinateFormat != domain.CoordinateFormatDMS)
+	dmsRadio.SetChecked(settings.CoordinateFormat == domain.CoordinateFormatDMS)
+	coordinateRow.AddWidget(decimalRadio.QWidget)
+	coordinateRow.AddWidget(dmsRadio.QWidget)
+	coordinateRow.AddStretch()
+	formatsForm.AddRow4("Coordinates:", coordinateRow.QLayout)
+	wizard.AddPage(formatsPage)
+
+	if wizard.Exec() != int(qt.QDialog__Accepted) {
+		return WelcomeChoices{}, false
+	}
+
+	choices = WelcomeChoices{
+		AutoDetect:       autoDetectCheck.IsChecked(),
+		TimeFormat24Hour: time24Radio.IsChecked(),
+		CoordinateFormat: domain.CoordinateFormatDecimal,
+	}
+	if row := resultList.CurrentRow(); row >= 0 && row < len(results) {
+		home := results[row].Location
+		choices.Home = &home
+	}
+	if dmsRadio.IsChecked() {
+		choices.CoordinateFormat = domain.CoordinateFormatDMS
+	}
+	return choices, true
+}
@@ -0,0 +1,79 @@
+package widgets
+
+import (
+	"html"
+	"strings"
+
+	qt "github.com/mappu/miqt/qt6"
+	"github.com/megatih/GoGoldenHour/internal/changelog"
+)
+
+// =============================================================================
+// What's New Dialog
+// =============================================================================
+
+// ShowWhatsNewDialog displays release notes from the embedded changelog.
+//
+// The releases are listed newest first, each with its version, date and
+// changes:
+//
+//	┌─ What's New ──────────────────────────────────────────────┐
+//	│ ┌────────────────────────────────────────────────────────┐│
+//	│ │ Version 0.1.3 (January 2, 2026)                        ││
+//	│ │  • Every source file is now documented, ...            ││
+//	│ └────────────────────────────────────────────────────────┘│
+//	│ [✓] Show what's new after updates             [ Close ]   │
+//	└───────────────────────────────────────────────────────────┘
+//
+// Parameters:
+//   - parent: Parent widget (the dialog is centered over it)
+//   - releases: The releases to list, newest first
+//   - showAfterUpdates: Initial state of the checkbox
+//     (domain.Settings.ShowWhatsNew)
+//
+// Returns the state of the checkbox when the dialog was closed. The dialog
+// is modal and blocks until the user closes it.
+//
+// miqt API notes:
+//   - NewQTextBrowser2(): Read-only rich text view (suffix "2" = no params)
+//   - SetHtml is inherited from QTextEdit
+func ShowWhatsNewDialog(parent *qt.QWidget, releases []changelog.Release, showAfterUpdates bool) bool {
+	dialog := qt.NewQDialog(parent)
+	dialog.SetWindowTitle("What's New")
+	dialog.Resize(480, 360)
+
+	layout := qt.NewQVBoxLayout(dialog.QWidget)
+
+	notes := qt.NewQTextBrowser2()
+	notes.SetHtml(releaseNotesHTML(releases))
+	layout.AddWidget(notes.QWidget)
+
+	// Checkbox and Close button on one row
+	bottomRow := qt.NewQHBoxLayout2()
+	showCheck := qt.NewQCheckBox3("Show what's new after updates")
+	showCheck.SetChecked(showAfterUpdates)
+	bottomRow.AddWidget(showCheck.QWidget)
+	buttons := qt.NewQDialogButtonBox4(qt.QDialogButtonBox__Close)
+	buttons.OnRejected(func() {
+		dialog.Reject()
+	})
+	bottomRow.AddWidget(buttons.QWidget)
+	layout.AddLayout(bottomRow.QLayout)
+
+	dialog.Exec()
+	return showCheck.IsChecked()
+}
+
+// releaseNotesHTML formats releases as a heading and bullet list each.
+// The notes are plain text, so they are escaped.
+func releaseNotesHTML(releases []changelog.Release) string {
+	var b strings.Builder
+	for _, r := range releases {
+		b.WriteString("<h3>" + html.EscapeString(r.Title()) + "</h3><ul>")
+		for _, change := range r.Changes {
+			b.WriteString("<li>" + html.EscapeString(change) + "</li>")
+		}
+		b.WriteString("</ul>")
+	}
+	return b.String()
+}